@@ -0,0 +1,458 @@
+package accounts
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	keyVersion = 3
+
+	// kdfIterations is the number of pbkdf2 rounds used for deriving the encryption key
+	kdfIterations = 262144
+	kdfKeyLen     = 32
+	kdfSaltLen    = 32
+)
+
+var (
+	ErrNoAccount          = errors.New("account not found in the keystore")
+	ErrLocked             = errors.New("account is locked")
+	ErrWrongPassphrase    = errors.New("could not decrypt the key with the given passphrase")
+	ErrEmptyPassphrase    = errors.New("passphrase cannot be empty")
+	errUnsupportedVersion = errors.New("unsupported key file version")
+	errUnsupportedCipher  = errors.New("unsupported key file cipher or kdf")
+)
+
+// encryptedKey is the key file format, following the web3 secret storage
+// definition (version 3) with the pbkdf2 key derivation function
+type encryptedKey struct {
+	Address string     `json:"address"`
+	Crypto  cryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams cipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    kdfParams    `json:"kdfparams"`
+	MAC          string       `json:"mac"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+type kdfParams struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+// Keystore holds the passphrase protected accounts of the node.
+// The keys are only kept in memory while the account is unlocked
+type Keystore struct {
+	dir        string
+	iterations int
+
+	lock     sync.Mutex
+	unlocked map[types.Address]*unlockedKey
+}
+
+type unlockedKey struct {
+	key   *ecdsa.PrivateKey
+	timer *time.Timer
+}
+
+// NewKeystore creates a keystore backed by the key files in the given directory
+func NewKeystore(dir string) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the keystore directory (%s): %v", dir, err)
+	}
+
+	return &Keystore{
+		dir:        dir,
+		iterations: kdfIterations,
+		unlocked:   map[types.Address]*unlockedKey{},
+	}, nil
+}
+
+// NewAccount generates a new key, stores it encrypted with the passphrase
+// and returns the address of the account
+func (k *Keystore) NewAccount(passphrase string) (types.Address, error) {
+	if passphrase == "" {
+		return types.ZeroAddress, ErrEmptyPassphrase
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+	addr := crypto.PubKeyToAddress(&key.PublicKey)
+
+	data, err := k.encryptKey(key, passphrase)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	path := filepath.Join(k.dir, keyFileName(addr))
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return types.ZeroAddress, fmt.Errorf("unable to write the key file (%s): %v", path, err)
+	}
+
+	return addr, nil
+}
+
+// Accounts returns the addresses of all the accounts in the keystore
+func (k *Keystore) Accounts() ([]types.Address, error) {
+	files, err := k.keyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]types.Address, 0, len(files))
+	for addr := range files {
+		addrs = append(addrs, addr)
+	}
+	sortAddresses(addrs)
+
+	return addrs, nil
+}
+
+// HasAccount checks if the account is stored in the keystore
+func (k *Keystore) HasAccount(addr types.Address) bool {
+	_, err := k.keyFile(addr)
+
+	return err == nil
+}
+
+// Unlock decrypts the key of the account and keeps it in memory for the given duration.
+// A zero timeout keeps the account unlocked until Lock is called
+func (k *Keystore) Unlock(addr types.Address, passphrase string, timeout time.Duration) error {
+	key, err := k.decryptAccount(addr, passphrase)
+	if err != nil {
+		return err
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if prev, ok := k.unlocked[addr]; ok && prev.timer != nil {
+		prev.timer.Stop()
+	}
+
+	u := &unlockedKey{key: key}
+	if timeout > 0 {
+		u.timer = time.AfterFunc(timeout, func() {
+			k.expire(addr, u)
+		})
+	}
+	k.unlocked[addr] = u
+
+	return nil
+}
+
+// expire locks the account, unless it was unlocked again in the meantime
+func (k *Keystore) expire(addr types.Address, u *unlockedKey) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if k.unlocked[addr] == u {
+		delete(k.unlocked, addr)
+	}
+}
+
+// Lock removes the decrypted key of the account from memory
+func (k *Keystore) Lock(addr types.Address) error {
+	if !k.HasAccount(addr) {
+		return ErrNoAccount
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if u, ok := k.unlocked[addr]; ok {
+		if u.timer != nil {
+			u.timer.Stop()
+		}
+		delete(k.unlocked, addr)
+	}
+
+	return nil
+}
+
+// IsUnlocked checks if the key of the account is held in memory
+func (k *Keystore) IsUnlocked(addr types.Address) bool {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	_, ok := k.unlocked[addr]
+
+	return ok
+}
+
+// SignTx signs the transaction with the key of the unlocked sender account
+func (k *Keystore) SignTx(tx *types.Transaction, signer crypto.TxSigner) (*types.Transaction, error) {
+	key, err := k.unlockedKey(tx.From)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.SignTx(tx, key)
+}
+
+// SignTxWithPassphrase signs the transaction with the key of the sender account,
+// decrypted with the passphrase for this signature only
+func (k *Keystore) SignTxWithPassphrase(tx *types.Transaction, signer crypto.TxSigner, passphrase string) (*types.Transaction, error) {
+	key, err := k.decryptAccount(tx.From, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.SignTx(tx, key)
+}
+
+// SignHashWithPassphrase signs the hash with the key of the account,
+// decrypted with the passphrase for this signature only
+func (k *Keystore) SignHashWithPassphrase(addr types.Address, passphrase string, hash []byte) ([]byte, error) {
+	key, err := k.decryptAccount(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Sign(key, hash)
+}
+
+// SignHash signs the hash with the key of the unlocked account
+func (k *Keystore) SignHash(addr types.Address, hash []byte) ([]byte, error) {
+	key, err := k.unlockedKey(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Sign(key, hash)
+}
+
+// unlockedKey returns the key of the account, if it is unlocked
+func (k *Keystore) unlockedKey(addr types.Address) (*ecdsa.PrivateKey, error) {
+	k.lock.Lock()
+	u, ok := k.unlocked[addr]
+	k.lock.Unlock()
+
+	if !ok {
+		if !k.HasAccount(addr) {
+			return nil, ErrNoAccount
+		}
+
+		return nil, ErrLocked
+	}
+
+	return u.key, nil
+}
+
+// decryptAccount reads the key file of the account and decrypts its key with the passphrase
+func (k *Keystore) decryptAccount(addr types.Address, passphrase string) (*ecdsa.PrivateKey, error) {
+	path, err := k.keyFile(addr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the key file (%s): %v", path, err)
+	}
+
+	key, err := decryptKey(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubKeyToAddress(&key.PublicKey) != addr {
+		return nil, fmt.Errorf("key file %s does not hold the key of %s", path, addr)
+	}
+
+	return key, nil
+}
+
+// keyFile returns the path of the key file of the account
+func (k *Keystore) keyFile(addr types.Address) (string, error) {
+	files, err := k.keyFiles()
+	if err != nil {
+		return "", err
+	}
+
+	path, ok := files[addr]
+	if !ok {
+		return "", ErrNoAccount
+	}
+
+	return path, nil
+}
+
+// keyFiles scans the keystore directory and maps the accounts to their key files.
+// Files that are not valid key files are skipped
+func (k *Keystore) keyFiles() (map[types.Address]string, error) {
+	entries, err := ioutil.ReadDir(k.dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the keystore directory (%s): %v", k.dir, err)
+	}
+
+	files := map[types.Address]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(k.dir, entry.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var key encryptedKey
+		if err := json.Unmarshal(data, &key); err != nil || key.Version != keyVersion {
+			continue
+		}
+
+		addr, err := hex.DecodeString(key.Address)
+		if err != nil || len(addr) != types.AddressLength {
+			continue
+		}
+		files[types.BytesToAddress(addr)] = path
+	}
+
+	return files, nil
+}
+
+func (k *Keystore) encryptKey(key *ecdsa.PrivateKey, passphrase string) ([]byte, error) {
+	keyBytes, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, kdfSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	derived := pbkdf2.Key([]byte(passphrase), salt, k.iterations, kdfKeyLen, sha256.New)
+
+	cipherText, err := aesCTR(derived[:16], iv, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(derived[16:32], cipherText)
+
+	return json.Marshal(&encryptedKey{
+		Address: hex.EncodeToString(crypto.PubKeyToAddress(&key.PublicKey).Bytes()),
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          "pbkdf2",
+			KDFParams: kdfParams{
+				DKLen: kdfKeyLen,
+				C:     k.iterations,
+				PRF:   "hmac-sha256",
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		ID:      uuid.New().String(),
+		Version: keyVersion,
+	})
+}
+
+func decryptKey(data []byte, passphrase string) (*ecdsa.PrivateKey, error) {
+	var key encryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, err
+	}
+	if key.Version != keyVersion {
+		return nil, errUnsupportedVersion
+	}
+
+	c := key.Crypto
+	if c.Cipher != "aes-128-ctr" || c.KDF != "pbkdf2" || c.KDFParams.PRF != "hmac-sha256" || c.KDFParams.DKLen != kdfKeyLen {
+		return nil, errUnsupportedCipher
+	}
+
+	salt, err := hex.DecodeString(c.KDFParams.Salt)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(c.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	derived := pbkdf2.Key([]byte(passphrase), salt, c.KDFParams.C, kdfKeyLen, sha256.New)
+	if !bytes.Equal(crypto.Keccak256(derived[16:32], cipherText), mac) {
+		return nil, ErrWrongPassphrase
+	}
+
+	keyBytes, err := aesCTR(derived[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.ToECDSA(keyBytes)
+}
+
+func aesCTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+
+	return out, nil
+}
+
+// keyFileName returns the name of the key file, in the UTC--<created at>--<address> format
+func keyFileName(addr types.Address) string {
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z")
+
+	return fmt.Sprintf("UTC--%s--%s", ts, hex.EncodeToString(addr.Bytes()))
+}
+
+func sortAddresses(addrs []types.Address) {
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+}
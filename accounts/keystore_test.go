@@ -0,0 +1,174 @@
+package accounts
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeystore(t *testing.T) *Keystore {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	k, err := NewKeystore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// keep the tests fast
+	k.iterations = 1024
+
+	return k
+}
+
+func TestKeystoreNewAccount(t *testing.T) {
+	k := newTestKeystore(t)
+
+	_, err := k.NewAccount("")
+	assert.Equal(t, ErrEmptyPassphrase, err)
+
+	addr1, err := k.NewAccount("pass1")
+	assert.NoError(t, err)
+	addr2, err := k.NewAccount("pass2")
+	assert.NoError(t, err)
+
+	// files that are not keys are ignored
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(k.dir, "README"), []byte("not a key"), 0600))
+
+	accounts, err := k.Accounts()
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Contains(t, accounts, addr1)
+	assert.Contains(t, accounts, addr2)
+
+	// the key is not stored in plain text
+	files, err := k.keyFiles()
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile(files[addr1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"kdf":"pbkdf2"`)
+}
+
+func TestKeystoreUnlock(t *testing.T) {
+	k := newTestKeystore(t)
+
+	addr, err := k.NewAccount("pass")
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrWrongPassphrase, k.Unlock(addr, "wrong", 0))
+	assert.Equal(t, ErrNoAccount, k.Unlock(types.StringToAddress("1"), "pass", 0))
+	assert.False(t, k.IsUnlocked(addr))
+
+	assert.NoError(t, k.Unlock(addr, "pass", 0))
+	assert.True(t, k.IsUnlocked(addr))
+
+	assert.NoError(t, k.Lock(addr))
+	assert.False(t, k.IsUnlocked(addr))
+}
+
+func TestKeystoreUnlockTimeout(t *testing.T) {
+	k := newTestKeystore(t)
+
+	addr, err := k.NewAccount("pass")
+	assert.NoError(t, err)
+
+	assert.NoError(t, k.Unlock(addr, "pass", 50*time.Millisecond))
+	assert.True(t, k.IsUnlocked(addr))
+
+	assert.Eventually(t, func() bool {
+		return !k.IsUnlocked(addr)
+	}, time.Second, 10*time.Millisecond)
+
+	// unlocking again without a timeout replaces the previous timer
+	assert.NoError(t, k.Unlock(addr, "pass", 50*time.Millisecond))
+	assert.NoError(t, k.Unlock(addr, "pass", 0))
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, k.IsUnlocked(addr))
+}
+
+func TestKeystoreSignTx(t *testing.T) {
+	k := newTestKeystore(t)
+	signer := crypto.NewEIP155Signer(100)
+
+	addr, err := k.NewAccount("pass")
+	assert.NoError(t, err)
+
+	to := types.StringToAddress("2")
+	tx := &types.Transaction{
+		From:     addr,
+		To:       &to,
+		Value:    big.NewInt(1),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+	}
+
+	_, err = k.SignTx(tx, signer)
+	assert.Equal(t, ErrLocked, err)
+
+	tx.From = types.StringToAddress("3")
+	_, err = k.SignTx(tx, signer)
+	assert.Equal(t, ErrNoAccount, err)
+	tx.From = addr
+
+	assert.NoError(t, k.Unlock(addr, "pass", 0))
+
+	signed, err := k.SignTx(tx, signer)
+	assert.NoError(t, err)
+
+	sender, err := signer.Sender(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, sender)
+}
+
+func TestKeystoreSignWithPassphrase(t *testing.T) {
+	k := newTestKeystore(t)
+	signer := crypto.NewEIP155Signer(100)
+
+	addr, err := k.NewAccount("pass")
+	assert.NoError(t, err)
+
+	to := types.StringToAddress("2")
+	tx := &types.Transaction{
+		From:     addr,
+		To:       &to,
+		Value:    big.NewInt(1),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+	}
+
+	_, err = k.SignTxWithPassphrase(tx, signer, "wrong")
+	assert.Equal(t, ErrWrongPassphrase, err)
+
+	signed, err := k.SignTxWithPassphrase(tx, signer, "pass")
+	assert.NoError(t, err)
+
+	sender, err := signer.Sender(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, sender)
+
+	// the account stays locked
+	assert.False(t, k.IsUnlocked(addr))
+	_, err = k.SignHash(addr, crypto.Keccak256([]byte("data")))
+	assert.Equal(t, ErrLocked, err)
+
+	hash := crypto.Keccak256([]byte("data"))
+	sig, err := k.SignHashWithPassphrase(addr, "pass", hash)
+	assert.NoError(t, err)
+
+	pub, err := crypto.SigToPub(hash, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubKeyToAddress(pub))
+}
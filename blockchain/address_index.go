@@ -0,0 +1,163 @@
+package blockchain
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+var (
+	ErrAddressIndexDisabled = errors.New("the index of the transactions by address is not enabled")
+)
+
+// AddressTxsPage is a page of the canonical transactions of an address, the newest first
+type AddressTxsPage struct {
+	Txs []*storage.AddressTx
+
+	// Next is the cursor of the next page, 0 on the last page
+	Next uint64
+
+	// IndexedFrom is the first block of the index, the transactions of the previous blocks are not indexed
+	IndexedFrom uint64
+}
+
+// EnableAddressIndex indexes the transactions of the blocks written from now on by their sender, their recipient
+// and the contract they create. The first block of the index is kept when the node restarts,
+// the blocks written while the index is disabled are not indexed
+func (b *Blockchain) EnableAddressIndex() error {
+	if _, ok := b.db.ReadAddressIndexStart(); !ok {
+		if err := b.db.WriteAddressIndexStart(b.Header().Number + 1); err != nil {
+			return err
+		}
+	}
+	b.addressIndex = true
+
+	return nil
+}
+
+// indexAddressTxs appends the transactions of the block to the index of their addresses.
+// All the blocks are indexed, the ones out of the canonical chain are skipped when read
+func (b *Blockchain) indexAddressTxs(block *types.Block, receipts []*types.Receipt) error {
+	if !b.addressIndex {
+		return nil
+	}
+
+	b.addressIndexLock.Lock()
+	defer b.addressIndexLock.Unlock()
+
+	signer := crypto.NewSigner(b.config.Params.Forks.At(block.Number()), uint64(b.config.Params.ChainID))
+
+	// the transactions of the block by address, in order
+	addrs := []types.Address{}
+	entries := map[types.Address][]*storage.AddressTx{}
+
+	add := func(addr types.Address, entry *storage.AddressTx) {
+		if _, ok := entries[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+		entries[addr] = append(entries[addr], entry)
+	}
+
+	for i, txn := range block.Transactions {
+		from := txn.From
+		if from == types.ZeroAddress {
+			// the bodies of the snapshot synced blocks have no sender
+			sender, err := signer.Sender(txn)
+			if err != nil {
+				return err
+			}
+			from = sender
+		}
+
+		entry := &storage.AddressTx{BlockHash: block.Hash(), TxHash: txn.Hash}
+
+		add(from, entry)
+		if txn.To != nil && *txn.To != from {
+			add(*txn.To, entry)
+		}
+		if i < len(receipts) && receipts[i].ContractAddress != types.ZeroAddress {
+			add(receipts[i].ContractAddress, entry)
+		}
+	}
+
+	for _, addr := range addrs {
+		if err := b.appendAddressTxs(addr, entries[addr]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendAddressTxs appends the transactions of a block to the index of the address
+func (b *Blockchain) appendAddressTxs(addr types.Address, entries []*storage.AddressTx) error {
+	count := b.db.ReadAddressTxCount(addr)
+	if count > 0 {
+		// a block written again is not indexed twice
+		if last, ok := b.db.ReadAddressTx(addr, count-1); ok && last.BlockHash == entries[0].BlockHash {
+			return nil
+		}
+	}
+
+	for _, entry := range entries {
+		if err := b.db.WriteAddressTx(addr, count, entry); err != nil {
+			return err
+		}
+		count++
+	}
+
+	return b.db.WriteAddressTxCount(addr, count)
+}
+
+// GetAddressTxs returns a page of at most limit canonical transactions of the address, the newest first.
+// The cursor is the one of the previous page, 0 starts from the newest transaction
+func (b *Blockchain) GetAddressTxs(addr types.Address, cursor uint64, limit int) (*AddressTxsPage, error) {
+	start, ok := b.db.ReadAddressIndexStart()
+	if !b.addressIndex || !ok {
+		return nil, ErrAddressIndexDisabled
+	}
+
+	count := b.db.ReadAddressTxCount(addr)
+	if cursor == 0 || cursor > count {
+		cursor = count
+	}
+
+	page := &AddressTxsPage{
+		Txs:         []*storage.AddressTx{},
+		IndexedFrom: start,
+	}
+
+	// the cursor is the number of the transactions left, from the oldest one
+	for pos := cursor; pos > 0; pos-- {
+		if len(page.Txs) == limit {
+			page.Next = pos
+
+			break
+		}
+
+		entry, ok := b.db.ReadAddressTx(addr, pos-1)
+		if !ok || !b.isCanonicalTx(entry) {
+			continue
+		}
+		page.Txs = append(page.Txs, entry)
+	}
+
+	return page, nil
+}
+
+// isCanonicalTx checks if the indexed transaction is in a block of the canonical chain
+func (b *Blockchain) isCanonicalTx(entry *storage.AddressTx) bool {
+	if blockHash, ok := b.db.ReadTxLookup(entry.TxHash); !ok || blockHash != entry.BlockHash {
+		return false
+	}
+
+	header, ok := b.readHeader(entry.BlockHash)
+	if !ok {
+		return false
+	}
+	canonical, ok := b.db.ReadCanonicalHash(header.Number)
+
+	return ok && canonical == entry.BlockHash
+}
@@ -0,0 +1,92 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressIndex(t *testing.T) {
+	headers := NewTestHeaderChain(5)
+	b := NewTestBlockchain(t, headers)
+
+	// the index is disabled by default
+	_, err := b.GetAddressTxs(types.StringToAddress("1"), 0, 10)
+	assert.Equal(t, ErrAddressIndexDisabled, err)
+
+	assert.NoError(t, b.EnableAddressIndex())
+
+	sender, receiver, contract := types.StringToAddress("1"), types.StringToAddress("2"), types.StringToAddress("3")
+
+	// writeBlock indexes a block with a transfer and a contract creation of the sender
+	writeBlock := func(header *types.Header) *types.Block {
+		transfer := &types.Transaction{To: &receiver, From: sender, Value: big.NewInt(1), GasPrice: big.NewInt(0), Input: header.Hash.Bytes()}
+		create := &types.Transaction{From: sender, Value: big.NewInt(0), GasPrice: big.NewInt(0), Input: header.Hash.Bytes()}
+		transfer.ComputeHash()
+		create.ComputeHash()
+
+		block := &types.Block{Header: header, Transactions: []*types.Transaction{transfer, create}}
+		receipts := []*types.Receipt{{}, {ContractAddress: contract}}
+
+		for _, txn := range block.Transactions {
+			assert.NoError(t, b.db.WriteTxLookup(txn.Hash, header.Hash))
+		}
+		assert.NoError(t, b.indexAddressTxs(block, receipts))
+
+		return block
+	}
+
+	blocks := []*types.Block{}
+	for _, header := range headers[1:] {
+		blocks = append(blocks, writeBlock(header))
+	}
+
+	// a block written again is not indexed twice
+	assert.NoError(t, b.indexAddressTxs(blocks[3], []*types.Receipt{{}, {ContractAddress: contract}}))
+
+	// the transactions of a side chain are skipped
+	side := NewTestHeaderFromChainWithSeed(headers[:3], 1, 10)[3]
+	assert.NoError(t, b.db.WriteHeader(side))
+	writeBlock(side)
+
+	// the pages go from the newest transaction to the oldest one
+	hashes := func(page *AddressTxsPage) []types.Hash {
+		res := []types.Hash{}
+		for _, entry := range page.Txs {
+			res = append(res, entry.TxHash)
+		}
+		return res
+	}
+
+	page, err := b.GetAddressTxs(sender, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), page.IndexedFrom)
+	assert.Equal(t, []types.Hash{
+		blocks[3].Transactions[1].Hash,
+		blocks[3].Transactions[0].Hash,
+		blocks[2].Transactions[1].Hash,
+	}, hashes(page))
+	assert.NotZero(t, page.Next)
+
+	for page.Next != 0 {
+		next, err := b.GetAddressTxs(sender, page.Next, 3)
+		assert.NoError(t, err)
+		page.Txs = append(page.Txs, next.Txs...)
+		page.Next = next.Next
+	}
+	assert.Len(t, page.Txs, 8)
+	assert.Equal(t, blocks[0].Transactions[0].Hash, page.Txs[7].TxHash)
+
+	// the receivers and the created contracts are indexed
+	page, err = b.GetAddressTxs(receiver, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, page.Txs, 4)
+	assert.Zero(t, page.Next)
+
+	page, err = b.GetAddressTxs(contract, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*storage.AddressTx{{BlockHash: blocks[3].Hash(), TxHash: blocks[3].Transactions[1].Hash}}, page.Txs)
+}
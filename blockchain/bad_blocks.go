@@ -0,0 +1,70 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// blockTracer is implemented by the executors which trace the transactions of a block
+type blockTracer interface {
+	TraceBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) ([]*state.CallTrace, error)
+}
+
+// reportBadBlock records the block which failed its verification or its execution, for the postmortems.
+// The blocks which were executed are recorded with the call trace of their transactions
+func (b *Blockchain) reportBadBlock(block *types.Block, reason error, executed bool) {
+	b.logger.Error("bad block", "number", block.Number(), "hash", block.Hash(), "err", reason)
+
+	bad := &storage.BadBlock{
+		Block:  block,
+		Reason: reason.Error(),
+		Time:   uint64(time.Now().Unix()),
+	}
+
+	if executed {
+		trace, err := b.traceBadBlock(block)
+		if err != nil {
+			b.logger.Warn("failed to trace the bad block", "hash", block.Hash(), "err", err)
+		}
+		bad.Trace = trace
+	}
+
+	if err := b.db.WriteBadBlock(bad); err != nil {
+		b.logger.Error("failed to record the bad block", "hash", block.Hash(), "err", err)
+	}
+}
+
+// traceBadBlock re-executes the transactions of the block on top of its parent, and returns their call traces
+func (b *Blockchain) traceBadBlock(block *types.Block) ([]byte, error) {
+	tracer, ok := b.executor.(blockTracer)
+	if !ok {
+		return nil, nil
+	}
+
+	parent, ok := b.readHeader(block.ParentHash())
+	if !ok {
+		return nil, fmt.Errorf("parent of block %d not found", block.Number())
+	}
+
+	blockCreator, err := b.consensus.GetBlockCreator(block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	traces, err := tracer.TraceBlock(parent.StateRoot, block, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(traces)
+}
+
+// GetBadBlocks returns the blocks which failed their verification or their execution, the most recent first
+func (b *Blockchain) GetBadBlocks() ([]*storage.BadBlock, error) {
+	return b.db.ReadBadBlocks()
+}
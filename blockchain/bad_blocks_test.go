@@ -0,0 +1,66 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// tracingExecutor traces every block with a single value transfer
+type tracingExecutor struct {
+	replayExecutor
+}
+
+func (e *tracingExecutor) TraceBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) ([]*state.CallTrace, error) {
+	return []*state.CallTrace{
+		{
+			Type:     state.CallTraceCall,
+			CallType: "call",
+			From:     types.StringToAddress("1"),
+			To:       types.StringToAddress("2"),
+			Value:    big.NewInt(10),
+			Gas:      21000,
+		},
+	}, nil
+}
+
+func TestBadBlocks(t *testing.T) {
+	b := TestBlockchain(t, nil)
+	b.executor = &tracingExecutor{replayExecutor{diverging: map[uint64]bool{2: true}}}
+
+	headers := NewTestHeaderChainWithSeed(b.Header(), 3, int(b.Header().GasLimit))
+	blocks := HeadersToBlocks(headers)
+
+	// the body of the block does not match its header
+	invalid := headers[1].Copy()
+	invalid.TxRoot = types.StringToHash("invalid")
+	invalid.ComputeHash()
+
+	assert.Error(t, b.WriteBlocks([]*types.Block{{Header: invalid}}))
+
+	// the state of the second block diverges once executed
+	assert.Error(t, b.WriteBlocks(blocks[1:]))
+	assert.Equal(t, headers[1].Hash, b.Header().Hash)
+
+	badBlocks, err := b.GetBadBlocks()
+	assert.NoError(t, err)
+	assert.Len(t, badBlocks, 2)
+
+	assert.Equal(t, headers[2].Hash, badBlocks[0].Block.Hash())
+	assert.Contains(t, badBlocks[0].Reason, "invalid merkle root")
+
+	var trace []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(badBlocks[0].Trace, &trace))
+	assert.Len(t, trace, 1)
+	assert.Equal(t, "0xa", trace[0]["value"])
+	assert.Equal(t, "0x5208", trace[0]["gas"])
+
+	// the blocks failing their verification are not executed
+	assert.Equal(t, invalid.Hash, badBlocks[1].Block.Hash())
+	assert.Contains(t, badBlocks[1].Reason, "transaction root hash mismatch")
+	assert.Empty(t, badBlocks[1].Trace)
+}
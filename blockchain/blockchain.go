@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage/leveldb"
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage/memory"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/eventbus"
 	"github.com/0xPolygon/polygon-sdk/helper/common"
 	"github.com/0xPolygon/polygon-sdk/state"
 	"github.com/0xPolygon/polygon-sdk/types"
@@ -22,6 +24,14 @@ import (
 
 const (
 	BlockGasTargetDivisor uint64 = 1024 // The bound divisor of the gas limit, used in update calculations
+	MinGasLimit           uint64 = 5000 // The lowest gas limit a block can decrease to
+)
+
+var (
+	// ErrInvalidReceiptsRoot and ErrInvalidLogsBloom are the receipts of a block not matching its header,
+	// the block was corrupted by the peer it was received from
+	ErrInvalidReceiptsRoot = errors.New("invalid receipts root")
+	ErrInvalidLogsBloom    = errors.New("invalid logs bloom")
 )
 
 // Blockchain is a blockchain reference
@@ -41,7 +51,26 @@ type Blockchain struct {
 	currentHeader     atomic.Value // The current header
 	currentDifficulty atomic.Value // The current difficulty of the chain (total difficulty)
 
-	stream *eventStream // Event subscriptions
+	stream *eventStream  // Event subscriptions
+	bus    *eventbus.Bus // Event bus of the node, nil if not set
+
+	finalized uint64 // The number of the latest finalized block published on the bus (atomic)
+
+	bloomIndexer *bloomIndexer // Background indexer of the logs blooms
+	freezer      *freezer      // Background mover of the old blocks to the freezer
+
+	maxReorgDepth uint64 // The maximum number of canonical blocks a reorg replaces, 0 for no limit
+
+	gasTarget atomic.Value // The block gas target set at runtime, overriding the one of the chain params
+
+	gasTargetSource   GasTargetSource    // Reader of the gas target contract, nil if not set
+	contractGasTarget *contractGasTarget // The gas target read from the contract for the latest epoch
+	gasTargetLock     sync.Mutex         // Lock of the gas target read from the contract
+
+	addressIndex     bool       // Whether the transactions are indexed by address
+	addressIndexLock sync.Mutex // Lock of the counters of the index by address
+
+	metrics *Metrics // The height and reorgs metrics
 
 	// Average gas price (rolling average)
 	averageGasPrice      *big.Int // The average gas price that gets queried
@@ -59,6 +88,17 @@ type Executor interface {
 	ProcessBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) (*state.BlockResult, error)
 }
 
+// GasTargetSource reads the block gas target set in the gas target contract, at the state of the header
+type GasTargetSource interface {
+	GasTarget(header *types.Header, contract types.Address) (uint64, error)
+}
+
+// contractGasTarget is the gas target read at the state of a header, 0 if the contract has not set one
+type contractGasTarget struct {
+	hash   types.Hash
+	target uint64
+}
+
 // UpdateGasPriceAvg Updates the rolling average value of the gas price
 func (b *Blockchain) UpdateGasPriceAvg(newValue *big.Int) {
 	b.agpMux.Lock()
@@ -87,14 +127,6 @@ func NewBlockchain(
 	executor Executor,
 ) (*Blockchain, error) {
 
-	b := &Blockchain{
-		logger:    logger.Named("blockchain"),
-		config:    config,
-		consensus: consensus,
-		executor:  executor,
-		stream:    &eventStream{},
-	}
-
 	var (
 		db  storage.Storage
 		err error
@@ -113,6 +145,26 @@ func NewBlockchain(
 		}
 	}
 
+	return NewBlockchainWithStorage(logger, db, config, consensus, executor)
+}
+
+// NewBlockchainWithStorage creates a new blockchain object on top of an opened storage
+func NewBlockchainWithStorage(
+	logger hclog.Logger,
+	db storage.Storage,
+	config *chain.Chain,
+	consensus Verifier,
+	executor Executor,
+) (*Blockchain, error) {
+	b := &Blockchain{
+		logger:    logger.Named("blockchain"),
+		config:    config,
+		consensus: consensus,
+		executor:  executor,
+		stream:    &eventStream{},
+		metrics:   NilMetrics(),
+	}
+
 	b.db = db
 
 	b.headersCache, _ = lru.New(100)
@@ -146,13 +198,16 @@ func (b *Blockchain) ComputeGenesis() error {
 		}
 
 		header, ok := b.GetHeaderByHash(head)
-		if !ok {
-			return fmt.Errorf("failed to get header with hash %s", head.String())
-		}
+		diff, tdOk := b.GetTD(head)
 
-		diff, ok := b.GetTD(head)
-		if !ok {
-			return fmt.Errorf("failed to read difficulty")
+		if !ok || !tdOk {
+			// the head was not completely written before an unclean shutdown
+			b.logger.Warn("the head block is incomplete", "hash", head.String())
+
+			var err error
+			if header, diff, err = b.recoverHead(); err != nil {
+				return err
+			}
 		}
 
 		b.logger.Info(
@@ -185,6 +240,18 @@ func (b *Blockchain) SetConsensus(c Verifier) {
 	b.consensus = c
 }
 
+// SetMaxReorgDepth sets the maximum number of canonical blocks a reorg replaces, 0 for no limit.
+// A heavier side chain forking deeper is recorded as a fork, without replacing the canonical chain
+func (b *Blockchain) SetMaxReorgDepth(depth uint64) {
+	b.maxReorgDepth = depth
+}
+
+// SetMetrics sets the metrics of the height and of the reorgs
+func (b *Blockchain) SetMetrics(metrics *Metrics) {
+	b.metrics = metrics
+	b.metrics.Height.Set(float64(b.Header().Number))
+}
+
 // setCurrentHeader sets the current header
 func (b *Blockchain) setCurrentHeader(h *types.Header, diff *big.Int) {
 	// Update the header (atomic)
@@ -194,6 +261,8 @@ func (b *Blockchain) setCurrentHeader(h *types.Header, diff *big.Int) {
 	// Update the difficulty (atomic)
 	difficulty := new(big.Int).Set(diff)
 	b.currentDifficulty.Store(difficulty)
+
+	b.metrics.Height.Set(float64(h.Number))
 }
 
 // Header returns the current header (atomic)
@@ -238,14 +307,83 @@ func (b *Blockchain) CalculateGasLimit(number uint64) (uint64, error) {
 		return 0, fmt.Errorf("parent of block %d not found", number)
 	}
 
-	return b.calculateGasLimit(parent.GasLimit), nil
+	return b.calculateGasLimit(parent.GasLimit, b.blockGasTargetAt(number)), nil
+}
+
+// CalculateBaseFee returns the base fee of the next block after parent,
+// or nil if the London fork is not active at that block
+func (b *Blockchain) CalculateBaseFee(parent *types.Header) *big.Int {
+	return b.Config().CalculateBaseFee(parent)
+}
+
+// SetBlockGasTarget sets the gas limit the proposed blocks move towards, 0 to keep the gas limit of the parent
+func (b *Blockchain) SetBlockGasTarget(target uint64) {
+	b.gasTarget.Store(target)
+}
+
+// BlockGasTarget returns the gas limit the proposed blocks move towards, 0 if not set
+func (b *Blockchain) BlockGasTarget() uint64 {
+	if target, ok := b.gasTarget.Load().(uint64); ok {
+		return target
+	}
+	return b.Config().BlockGasTarget
+}
+
+// SetGasTargetSource sets the reader of the gas target contract of the chain params
+func (b *Blockchain) SetGasTargetSource(source GasTargetSource) {
+	b.gasTargetLock.Lock()
+	defer b.gasTargetLock.Unlock()
+
+	b.gasTargetSource = source
+	b.contractGasTarget = nil
+}
+
+// blockGasTargetAt returns the gas target of the block, the one set in the gas target contract
+// at the start of its epoch, or the one of the node if the contract has not set one
+func (b *Blockchain) blockGasTargetAt(number uint64) uint64 {
+	contract := b.Config().GasTargetContract
+	if contract == nil {
+		return b.BlockGasTarget()
+	}
+
+	b.gasTargetLock.Lock()
+	defer b.gasTargetLock.Unlock()
+
+	if b.gasTargetSource == nil {
+		return b.BlockGasTarget()
+	}
+
+	// The target of the epoch is read at the state of the block before it
+	stateNumber := contract.Epoch(number)
+	if stateNumber != 0 {
+		stateNumber--
+	}
+	header, ok := b.GetHeaderByNumber(stateNumber)
+	if !ok {
+		return b.BlockGasTarget()
+	}
+
+	// The target is read once per epoch, and again after a reorg of the block it is read at
+	if b.contractGasTarget == nil || b.contractGasTarget.hash != header.Hash {
+		target, err := b.gasTargetSource.GasTarget(header, contract.Address)
+		if err != nil {
+			b.logger.Warn("failed to read the gas target contract, using the gas target of the node",
+				"number", header.Number, "contract", contract.Address, "err", err)
+		}
+		b.contractGasTarget = &contractGasTarget{hash: header.Hash, target: target}
+	}
+
+	if b.contractGasTarget.target == 0 {
+		return b.BlockGasTarget()
+	}
+
+	return b.contractGasTarget.target
 }
 
 // calculateGasLimit calculates gas limit in reference to the block gas target
-func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
+func (b *Blockchain) calculateGasLimit(parentGasLimit, blockGasTarget uint64) uint64 {
 	// The gas limit cannot move more than 1/1024 * parentGasLimit
 	// in either direction per block
-	blockGasTarget := b.Config().BlockGasTarget
 
 	// Check if the gas limit target has been set
 	if blockGasTarget == 0 {
@@ -254,6 +392,9 @@ func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
 		return parentGasLimit
 	}
 
+	// The gas limit can't decrease below the minimum
+	blockGasTarget = common.Max(blockGasTarget, MinGasLimit)
+
 	// Check if the gas limit is already at the target
 	if parentGasLimit == blockGasTarget {
 		// The gas limit is already at the target, no need to move it
@@ -302,6 +443,11 @@ func (b *Blockchain) writeGenesisImpl(header *types.Header) error {
 		return err
 	}
 
+	// The genesis state is computed by the executor
+	if err := b.db.WriteStateRoot(header.Number, header.Hash, header.StateRoot); err != nil {
+		return err
+	}
+
 	// Create an event and send it to the stream
 	event := &Event{}
 	event.AddNewHeader(header)
@@ -582,27 +728,41 @@ func (b *Blockchain) WriteBlocks(blocks []*types.Block) error {
 
 		// Verify the header
 		if err := b.consensus.VerifyHeader(parent, block.Header); err != nil {
-			return fmt.Errorf("failed to verify the header: %v", err)
+			err = fmt.Errorf("failed to verify the header: %v", err)
+			b.reportBadBlock(block, err, false)
+
+			return err
 		}
 
 		// Verify body data
 		if hash := buildroot.CalculateUncleRoot(block.Uncles); hash != block.Header.Sha3Uncles {
-
-			return fmt.Errorf(
+			err := fmt.Errorf(
 				"uncle root hash mismatch: have %s, want %s",
 				hash,
 				block.Header.Sha3Uncles,
 			)
+			b.reportBadBlock(block, err, false)
+
+			return err
 		}
 
 		// TODO, the wrapper around transactions
 		if hash := buildroot.CalculateTransactionsRoot(block.Transactions); hash != block.Header.TxRoot {
-
-			return fmt.Errorf(
+			err := fmt.Errorf(
 				"transaction root hash mismatch: have %s, want %s",
 				hash,
 				block.Header.TxRoot,
 			)
+			b.reportBadBlock(block, err, false)
+
+			return err
+		}
+
+		// the receipts of an empty block are known before its execution
+		if err := verifyEmptyReceipts(block); err != nil {
+			b.reportBadBlock(block, err, false)
+
+			return err
 		}
 
 		parent = block.Header
@@ -615,6 +775,8 @@ func (b *Blockchain) WriteBlocks(blocks []*types.Block) error {
 		// Process and validate the block
 		res, err := b.processBlock(blocks[indx])
 		if err != nil {
+			b.reportBadBlock(block, err, true)
+
 			return err
 		}
 
@@ -635,6 +797,14 @@ func (b *Blockchain) WriteBlocks(blocks []*types.Block) error {
 			return err
 		}
 
+		if err := b.indexAddressTxs(block, res.Receipts); err != nil {
+			return err
+		}
+
+		if err := b.db.WriteStateRoot(header.Number, header.Hash, res.Root); err != nil {
+			return err
+		}
+
 		b.dispatchEvent(evnt)
 
 		// Update the average gas price
@@ -677,6 +847,17 @@ func (b *Blockchain) writeBody(block *types.Block) error {
 	return nil
 }
 
+// GetStateRoot returns the state root of the canonical block, if the node stored its state.
+// The blocks whose headers were synced without executing them have no indexed state
+func (b *Blockchain) GetStateRoot(n uint64) (types.Hash, bool) {
+	hash, ok := b.db.ReadCanonicalHash(n)
+	if !ok {
+		return types.Hash{}, false
+	}
+
+	return b.db.ReadStateRoot(n, hash)
+}
+
 // ReadTxLookup returns the block hash using the transaction hash
 func (b *Blockchain) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
 	v, ok := b.db.ReadTxLookup(hash)
@@ -699,6 +880,10 @@ func (b *Blockchain) processBlock(block *types.Block) (*state.BlockResult, error
 		return nil, err
 	}
 
+	if err := verifySystemTxs(block.Transactions); err != nil {
+		return nil, err
+	}
+
 	result, err := b.executor.ProcessBlock(parent.StateRoot, block, blockCreator)
 	if err != nil {
 		return nil, err
@@ -709,7 +894,17 @@ func (b *Blockchain) processBlock(block *types.Block) (*state.BlockResult, error
 		return nil, fmt.Errorf("bad size of receipts and transactions")
 	}
 
-	// Validate the fields
+	// Validate the fields, the receipts first since they point at the faulty transaction
+	receiptSha := buildroot.CalculateReceiptsRoot(result.Receipts)
+	if receiptSha != header.ReceiptsRoot {
+		return nil, fmt.Errorf("%w: have %s, want %s", ErrInvalidReceiptsRoot, receiptSha, header.ReceiptsRoot)
+	}
+
+	// the blocks built before the bloom was set have an empty bloom, they are still accepted
+	if header.LogsBloom != (types.Bloom{}) && types.CreateBloom(result.Receipts) != header.LogsBloom {
+		return nil, ErrInvalidLogsBloom
+	}
+
 	if result.Root != header.StateRoot {
 		return nil, fmt.Errorf("invalid merkle root")
 	}
@@ -718,18 +913,68 @@ func (b *Blockchain) processBlock(block *types.Block) (*state.BlockResult, error
 		return nil, fmt.Errorf("gas used is different")
 	}
 
-	receiptSha := buildroot.CalculateReceiptsRoot(result.Receipts)
-	if receiptSha != header.ReceiptsRoot {
-		return nil, fmt.Errorf("invalid receipts root")
-	}
-
 	if gasLimitErr := b.verifyGasLimit(header); gasLimitErr != nil {
 		return nil, fmt.Errorf("invalid gas limit, %v", gasLimitErr)
 	}
 
+	if baseFeeErr := b.verifyBaseFee(header, parent); baseFeeErr != nil {
+		return nil, baseFeeErr
+	}
+
 	return result, nil
 }
 
+// verifyBaseFee checks that the base fee of the header is the one calculated from its parent (EIP-1559)
+func (b *Blockchain) verifyBaseFee(header, parent *types.Header) error {
+	expected := b.CalculateBaseFee(parent)
+
+	if expected == nil {
+		if header.BaseFee != nil {
+			return fmt.Errorf("unexpected base fee before the London fork")
+		}
+
+		return nil
+	}
+
+	if header.BaseFee == nil || header.BaseFee.Cmp(expected) != 0 {
+		return fmt.Errorf("invalid base fee, got %v, want %s", header.BaseFee, expected)
+	}
+
+	return nil
+}
+
+// verifyEmptyReceipts checks the receipts root and the logs bloom of a block without transactions
+func verifyEmptyReceipts(block *types.Block) error {
+	if len(block.Transactions) != 0 {
+		return nil
+	}
+
+	if block.Header.ReceiptsRoot != types.EmptyRootHash {
+		return fmt.Errorf("%w: have %s, want %s", ErrInvalidReceiptsRoot, types.EmptyRootHash, block.Header.ReceiptsRoot)
+	}
+
+	if block.Header.LogsBloom != (types.Bloom{}) {
+		return ErrInvalidLogsBloom
+	}
+
+	return nil
+}
+
+// verifySystemTxs checks the system transactions are at the top of the block, before the other transactions.
+// The consensus verifies they are the expected ones
+func verifySystemTxs(txs []*types.Transaction) error {
+	top := true
+	for i, tx := range txs {
+		if !tx.IsSystemTx() {
+			top = false
+		} else if !top {
+			return fmt.Errorf("system transaction %d after the other transactions", i)
+		}
+	}
+
+	return nil
+}
+
 // verifyGasLimit is a helper function for validating a gas limit in a header
 func (b *Blockchain) verifyGasLimit(header *types.Header) error {
 	if header.GasUsed > header.GasLimit {
@@ -767,6 +1012,15 @@ func (b *Blockchain) verifyGasLimit(header *types.Header) error {
 		)
 	}
 
+	// The chains started below the minimum keep their gas limit, but none decreases below it
+	if header.GasLimit < MinGasLimit && header.GasLimit < parent.GasLimit {
+		return fmt.Errorf(
+			"invalid gas limit, limit = %d decreases below the minimum %d",
+			header.GasLimit,
+			MinGasLimit,
+		)
+	}
+
 	return nil
 }
 
@@ -821,6 +1075,7 @@ func (b *Blockchain) WriteBlock(block *types.Block) error {
 // dispatchEvent pushes a new event to the stream
 func (b *Blockchain) dispatchEvent(evnt *Event) {
 	b.stream.push(evnt)
+	b.publishEvent(evnt)
 }
 
 // writeHeaderImpl writes a block and the data, assumes the genesis is already set
@@ -920,6 +1175,7 @@ func (b *Blockchain) handleReorg(
 	newChainHead := newHeader
 	oldChainHead := oldHeader
 
+	// the headers replaced in the canonical chain and the ones replacing them, from the heads to the common ancestor
 	oldChain := []*types.Header{}
 	newChain := []*types.Header{}
 
@@ -927,58 +1183,98 @@ func (b *Blockchain) handleReorg(
 
 	// Fill up the old headers array
 	for oldHeader.Number > newHeader.Number {
+		oldChain = append(oldChain, oldHeader)
+
 		oldHeader, ok = b.readHeader(oldHeader.ParentHash)
 		if !ok {
-			return fmt.Errorf("header '%s' not found", oldHeader.ParentHash.String())
+			return fmt.Errorf("header '%s' not found", oldChain[len(oldChain)-1].ParentHash.String())
 		}
-
-		oldChain = append(oldChain, oldHeader)
 	}
 
 	// Fill up the new headers array
 	for newHeader.Number > oldHeader.Number {
+		newChain = append(newChain, newHeader)
+
 		newHeader, ok = b.readHeader(newHeader.ParentHash)
 		if !ok {
-			return fmt.Errorf("header '%s' not found", newHeader.ParentHash.String())
+			return fmt.Errorf("header '%s' not found", newChain[len(newChain)-1].ParentHash.String())
 		}
-
-		newChain = append(newChain, newHeader)
 	}
 
 	for oldHeader.Hash != newHeader.Hash {
+		oldChain = append(oldChain, oldHeader)
+		newChain = append(newChain, newHeader)
+
 		oldHeader, ok = b.readHeader(oldHeader.ParentHash)
 		if !ok {
-			return fmt.Errorf("header '%s' not found", oldHeader.ParentHash.String())
+			return fmt.Errorf("header '%s' not found", oldChain[len(oldChain)-1].ParentHash.String())
 		}
 
 		newHeader, ok = b.readHeader(newHeader.ParentHash)
 		if !ok {
-			return fmt.Errorf("header '%s' not found", newHeader.ParentHash.String())
+			return fmt.Errorf("header '%s' not found", newChain[len(newChain)-1].ParentHash.String())
 		}
+	}
 
-		oldChain = append(oldChain, oldHeader)
+	ancestor := oldHeader
+
+	// the frozen blocks are final
+	if frozen := b.db.Frozen(); ancestor.Number+1 < frozen {
+		return fmt.Errorf("the reorg to %s replaces frozen blocks, the first %d blocks are final", newChainHead.Hash, frozen)
 	}
 
-	for _, b := range oldChain[:len(oldChain)-1] {
-		evnt.AddOldHeader(b)
+	// the side chain is valid, so the peers serving it are not at fault: it's kept as a fork
+	if b.maxReorgDepth != 0 && uint64(len(oldChain)) > b.maxReorgDepth {
+		b.logger.Warn(
+			"reorg deeper than the maximum reorg depth, the new chain is kept as a fork",
+			"depth", len(oldChain),
+			"ancestor", ancestor.Number,
+			"new", newChainHead.Hash,
+			"number", newChainHead.Number,
+			"max", b.maxReorgDepth,
+		)
+
+		evnt.AddOldHeader(newChainHead)
+		evnt.Type = EventFork
+
+		return b.writeFork(newChainHead)
 	}
 
-	evnt.AddOldHeader(oldChainHead)
-	evnt.AddNewHeader(newChainHead)
+	// the old head is not replaced when the new chain descends from it
+	if len(oldChain) != 0 {
+		b.metrics.Reorgs.Add(1)
+		b.metrics.ReorgedBlocks.Add(float64(len(oldChain)))
 
-	for _, b := range newChain {
-		evnt.AddNewHeader(b)
+		for _, b := range oldChain[1:] {
+			evnt.AddOldHeader(b)
+		}
+
+		evnt.AddOldHeader(oldChainHead)
+
+		if err := b.writeFork(oldChainHead); err != nil {
+			return fmt.Errorf("failed to write the old header as fork: %v", err)
+		}
 	}
 
-	if err := b.writeFork(oldChainHead); err != nil {
-		return fmt.Errorf("failed to write the old header as fork: %v", err)
+	for _, b := range newChain {
+		evnt.AddNewHeader(b)
 	}
 
-	// Update canonical chain numbers
+	// Update canonical chain numbers, and point the transactions to the blocks of the new chain
 	for _, h := range newChain {
 		if err := b.db.WriteCanonicalHash(h.Number, h.Hash); err != nil {
 			return err
 		}
+
+		body, ok := b.readBody(h.Hash)
+		if !ok {
+			continue
+		}
+		for _, txn := range body.Transactions {
+			if err := b.db.WriteTxLookup(txn.Hash, h.Hash); err != nil {
+				return err
+			}
+		}
 	}
 
 	diff, err := b.advanceHead(newChainHead)
@@ -986,6 +1282,15 @@ func (b *Blockchain) handleReorg(
 		return err
 	}
 
+	b.logger.Warn(
+		"chain reorg",
+		"depth", len(oldChain),
+		"ancestor", ancestor.Number,
+		"old", oldChainHead.Hash,
+		"new", newChainHead.Hash,
+		"number", newChainHead.Number,
+	)
+
 	// Set the event type and difficulty
 	evnt.Type = EventReorg
 	evnt.SetDifficulty(diff)
@@ -993,6 +1298,54 @@ func (b *Blockchain) handleReorg(
 	return nil
 }
 
+// Rewind moves the head of the chain back to the canonical block of number, and returns the headers
+// of the blocks after it, which are no longer canonical. It is meant for the dev chains:
+// unlike a reorg, the subscribers are not notified
+func (b *Blockchain) Rewind(number uint64) ([]*types.Header, error) {
+	head := b.Header()
+	if number >= head.Number {
+		return nil, nil
+	}
+
+	// the frozen blocks are final
+	if frozen := b.db.Frozen(); number+1 < frozen {
+		return nil, fmt.Errorf("the rewind to block %d replaces frozen blocks, the first %d blocks are final", number, frozen)
+	}
+
+	header, ok := b.GetHeaderByNumber(number)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+
+	removed := []*types.Header{}
+	for n := head.Number; n > number; n-- {
+		h, ok := b.GetHeaderByNumber(n)
+		if !ok {
+			return nil, fmt.Errorf("block %d not found", n)
+		}
+		removed = append(removed, h)
+	}
+
+	if err := b.writeFork(head); err != nil {
+		return nil, fmt.Errorf("failed to write the old header as fork: %v", err)
+	}
+
+	if _, err := b.advanceHead(header); err != nil {
+		return nil, err
+	}
+
+	// the numbers after the head point to no block
+	for _, h := range removed {
+		if err := b.db.WriteCanonicalHash(h.Number, types.ZeroHash); err != nil {
+			return nil, err
+		}
+	}
+
+	b.logger.Warn("chain rewound", "number", number, "removed", len(removed))
+
+	return removed, nil
+}
+
 // GetForks returns the forks
 func (b *Blockchain) GetForks() ([]types.Hash, error) {
 	return b.db.ReadForks()
@@ -1042,5 +1395,12 @@ func (b *Blockchain) GetBlockByNumber(blockNumber uint64, full bool) (*types.Blo
 
 // Close closes the DB connection
 func (b *Blockchain) Close() error {
+	if b.bloomIndexer != nil {
+		b.bloomIndexer.close()
+	}
+	if b.freezer != nil {
+		b.freezer.close()
+	}
+
 	return b.db.Close()
 }
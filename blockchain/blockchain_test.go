@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-sdk/state"
 	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/types/buildroot"
 )
 
 func TestGenesis(t *testing.T) {
@@ -600,6 +603,12 @@ func TestCalculateGasLimit(t *testing.T) {
 			parentGasLimit:   25000000,
 			expectedGasLimit: 25000000 - 25000000/1024 + 100,
 		},
+		{
+			name:             "should not decrease below the minimum gas limit",
+			blockGasTarget:   1000,
+			parentGasLimit:   MinGasLimit + 1,
+			expectedGasLimit: MinGasLimit,
+		},
 	}
 
 	for _, tt := range tests {
@@ -619,3 +628,267 @@ func TestCalculateGasLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestSetBlockGasTarget(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	assert.NoError(t, b.writeGenesis(&chain.Genesis{GasLimit: 20000000}))
+	b.config.Params = &chain.Params{BlockGasTarget: 25000000}
+
+	// the target set at runtime overrides the one of the chain params
+	b.SetBlockGasTarget(10000000)
+	nextGas, err := b.CalculateGasLimit(1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20000000-20000000/1024), nextGas)
+
+	// an unset target keeps the gas limit of the parent
+	b.SetBlockGasTarget(0)
+	nextGas, err = b.CalculateGasLimit(1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20000000), nextGas)
+}
+
+// mockGasTargetSource returns the gas target set at the blocks, and records the blocks it is read at
+type mockGasTargetSource struct {
+	targets map[uint64]uint64
+	reads   []uint64
+}
+
+func (m *mockGasTargetSource) GasTarget(header *types.Header, contract types.Address) (uint64, error) {
+	m.reads = append(m.reads, header.Number)
+
+	target, ok := m.targets[header.Number]
+	if !ok {
+		return 0, fmt.Errorf("no gas target at block %d", header.Number)
+	}
+	return target, nil
+}
+
+func TestContractGasTarget(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	assert.NoError(t, b.writeGenesis(&chain.Genesis{GasLimit: 20000000}))
+	assert.NoError(t, b.WriteHeaders(NewTestHeaderFromChainWithSeed([]*types.Header{b.Header()}, 29, 20000000)[1:]))
+	b.config.Params = &chain.Params{
+		BlockGasTarget:    25000000,
+		GasTargetContract: &chain.GasTargetContract{Interval: 10},
+	}
+
+	source := &mockGasTargetSource{targets: map[uint64]uint64{0: 10000000, 9: 0}}
+	b.SetGasTargetSource(source)
+
+	// the first epoch reads the target at the genesis
+	nextGas, err := b.CalculateGasLimit(5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20000000-20000000/1024), nextGas)
+
+	// the target is read once per epoch, and a zero target leaves the target of the node
+	for _, number := range []uint64{10, 15} {
+		nextGas, err = b.CalculateGasLimit(number)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(20000000+20000000/1024), nextGas)
+	}
+
+	// a failed read leaves the target of the node too
+	nextGas, err = b.CalculateGasLimit(25)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20000000+20000000/1024), nextGas)
+
+	assert.Equal(t, []uint64{0, 9, 19}, source.reads)
+}
+
+func TestVerifyGasLimit(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	assert.NoError(t, b.writeGenesis(&chain.Genesis{GasLimit: MinGasLimit + 1}))
+
+	// the gas limit moves by up to 1/1024 of the parent one, but not below the minimum
+	assert.NoError(t, b.verifyGasLimit(&types.Header{Number: 1, GasLimit: MinGasLimit + 1 + 4}))
+	assert.NoError(t, b.verifyGasLimit(&types.Header{Number: 1, GasLimit: MinGasLimit}))
+	assert.Error(t, b.verifyGasLimit(&types.Header{Number: 1, GasLimit: MinGasLimit + 1 + 5}))
+	assert.Error(t, b.verifyGasLimit(&types.Header{Number: 1, GasLimit: MinGasLimit - 1}))
+	assert.Error(t, b.verifyGasLimit(&types.Header{Number: 1, GasLimit: MinGasLimit, GasUsed: MinGasLimit + 1}))
+}
+
+func TestVerifyBaseFee(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	b.config.Params = &chain.Params{
+		Forks: &chain.Forks{
+			London: chain.NewFork(2),
+		},
+	}
+
+	// the blocks before the fork have no base fee
+	parent := &types.Header{Number: 0, GasLimit: 1000}
+	assert.NoError(t, b.verifyBaseFee(&types.Header{Number: 1}, parent))
+	assert.Error(t, b.verifyBaseFee(&types.Header{Number: 1, BaseFee: big.NewInt(1)}, parent))
+
+	// the first block of the fork has the initial base fee
+	parent = &types.Header{Number: 1, GasLimit: 1000}
+	assert.NoError(t, b.verifyBaseFee(&types.Header{Number: 2, BaseFee: chain.InitialBaseFee}, parent))
+	assert.Error(t, b.verifyBaseFee(&types.Header{Number: 2}, parent))
+
+	// the base fee follows the gas used by the parent
+	parent = &types.Header{Number: 2, GasLimit: 1000, GasUsed: 1000, BaseFee: big.NewInt(800)}
+	assert.NoError(t, b.verifyBaseFee(&types.Header{Number: 3, BaseFee: big.NewInt(900)}, parent))
+	assert.Error(t, b.verifyBaseFee(&types.Header{Number: 3, BaseFee: big.NewInt(800)}, parent))
+}
+
+func TestVerifySystemTxs(t *testing.T) {
+	system := &types.Transaction{Type: types.StateTx}
+	tx := &types.Transaction{}
+
+	assert.NoError(t, verifySystemTxs(nil))
+	assert.NoError(t, verifySystemTxs([]*types.Transaction{system, system, tx, tx}))
+	assert.NoError(t, verifySystemTxs([]*types.Transaction{tx}))
+
+	// the system transactions are at the top of the block
+	assert.Error(t, verifySystemTxs([]*types.Transaction{tx, system}))
+	assert.Error(t, verifySystemTxs([]*types.Transaction{system, tx, system}))
+}
+
+func TestHandleReorg(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+
+	// the side chain forks after block 2 and overtakes the canonical chain at block 6
+	fork := NewTestHeaderFromChainWithSeed(headers[:3], 4, 1)
+
+	writeFork := func(b *Blockchain) error {
+		for _, h := range fork[3:] {
+			if err := b.WriteHeaders([]*types.Header{h}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("reorg to the side chain", func(t *testing.T) {
+		b := NewTestBlockchain(t, headers)
+		sub := b.SubscribeEvents()
+
+		assert.NoError(t, writeFork(b))
+		assert.Equal(t, fork[6].Hash, b.Header().Hash)
+
+		// all the replaced numbers point to the side chain
+		for _, h := range fork[1:] {
+			canonical, ok := b.GetHeaderByNumber(h.Number)
+			assert.True(t, ok)
+			assert.Equal(t, h.Hash, canonical.Hash)
+		}
+
+		// the first side blocks don't overtake the chain
+		for i := 3; i < 6; i++ {
+			assert.Equal(t, EventFork, sub.GetEvent().Type)
+		}
+
+		evnt := sub.GetEvent()
+		assert.Equal(t, EventReorg, evnt.Type)
+		assert.Len(t, evnt.OldChain, 3)
+		assert.Len(t, evnt.NewChain, 4)
+		assert.Equal(t, headers[5].Hash, evnt.OldChain[len(evnt.OldChain)-1].Hash)
+		assert.Equal(t, fork[6].Hash, evnt.NewChain[0].Hash)
+	})
+
+	t.Run("reorg deeper than the maximum", func(t *testing.T) {
+		b := NewTestBlockchain(t, headers)
+		b.SetMaxReorgDepth(2)
+
+		// the heavier side chain is kept as a fork, the blocks are not rejected
+		assert.NoError(t, writeFork(b))
+		assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+		canonical, ok := b.GetHeaderByNumber(3)
+		assert.True(t, ok)
+		assert.Equal(t, headers[3].Hash, canonical.Hash)
+
+		forks, err := b.GetForks()
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Hash{fork[6].Hash}, forks)
+	})
+}
+
+func TestRewind(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+	b := NewTestBlockchain(t, headers)
+
+	removed, err := b.Rewind(3)
+	assert.NoError(t, err)
+	assert.Len(t, removed, 2)
+	assert.Equal(t, headers[5].Hash, removed[0].Hash)
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+
+	// the rewound numbers point to no block
+	_, ok := b.GetHeaderByNumber(4)
+	assert.False(t, ok)
+
+	// the chain is extended from the new head
+	fork := NewTestHeaderFromChainWithSeed(headers[:4], 2, 1)
+	assert.NoError(t, b.WriteHeaders(fork[4:]))
+	assert.Equal(t, fork[5].Hash, b.Header().Hash)
+
+	// there is nothing to rewind after the head
+	removed, err = b.Rewind(10)
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+type mockReceiptsExecutor struct {
+	receipts []*types.Receipt
+}
+
+func (m *mockReceiptsExecutor) ProcessBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) (*state.BlockResult, error) {
+	return &state.BlockResult{Receipts: m.receipts}, nil
+}
+
+func TestProcessBlockReceipts(t *testing.T) {
+	receipts := []*types.Receipt{
+		{Logs: []*types.Log{{Address: types.StringToAddress("1"), Topics: []types.Hash{types.StringToHash("2")}}}},
+	}
+
+	b, err := newBlockChain(&chain.Chain{
+		Genesis: &chain.Genesis{GasLimit: 1000000},
+		Params:  &chain.Params{BlockGasTarget: 1000000},
+	}, &mockReceiptsExecutor{receipts: receipts})
+	assert.NoError(t, err)
+
+	block := func(receiptsRoot types.Hash, bloom types.Bloom) *types.Block {
+		return &types.Block{
+			Header: &types.Header{
+				Number:       1,
+				ParentHash:   b.genesis,
+				GasLimit:     1000000,
+				ReceiptsRoot: receiptsRoot,
+				LogsBloom:    bloom,
+			},
+			Transactions: []*types.Transaction{{}},
+		}
+	}
+	root := buildroot.CalculateReceiptsRoot(receipts)
+
+	_, err = b.processBlock(block(root, types.CreateBloom(receipts)))
+	assert.NoError(t, err)
+
+	// the blocks built without a bloom are accepted
+	_, err = b.processBlock(block(root, types.Bloom{}))
+	assert.NoError(t, err)
+
+	_, err = b.processBlock(block(types.EmptyRootHash, types.CreateBloom(receipts)))
+	assert.True(t, errors.Is(err, ErrInvalidReceiptsRoot))
+
+	_, err = b.processBlock(block(root, types.Bloom{0x1}))
+	assert.True(t, errors.Is(err, ErrInvalidLogsBloom))
+}
+
+func TestVerifyEmptyReceipts(t *testing.T) {
+	empty := &types.Block{Header: &types.Header{ReceiptsRoot: types.EmptyRootHash}}
+	assert.NoError(t, verifyEmptyReceipts(empty))
+
+	// the receipts of the blocks with transactions are verified after their execution
+	assert.NoError(t, verifyEmptyReceipts(&types.Block{
+		Header:       &types.Header{ReceiptsRoot: types.StringToHash("1")},
+		Transactions: []*types.Transaction{{}},
+	}))
+
+	empty.Header.LogsBloom = types.Bloom{0x1}
+	assert.True(t, errors.Is(verifyEmptyReceipts(empty), ErrInvalidLogsBloom))
+
+	empty.Header.ReceiptsRoot = types.StringToHash("1")
+	assert.True(t, errors.Is(verifyEmptyReceipts(empty), ErrInvalidReceiptsRoot))
+}
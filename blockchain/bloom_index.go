@@ -0,0 +1,363 @@
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/helper/keccak"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// BloomSectionSize is the number of blocks whose logs blooms are indexed together.
+	// For every bit of the bloom, a section stores a vector with one bit per block
+	BloomSectionSize = 4096
+
+	// bloomBitLength is the number of bits of a logs bloom
+	bloomBitLength = types.BloomByteLength * 8
+
+	// bloomConfirmations is the number of blocks a section must be behind the head before it is indexed,
+	// so the indexed sections are not affected by the regular reorgs
+	bloomConfirmations = 256
+
+	// bloomIndexInterval is the time between the runs of the background indexer
+	bloomIndexInterval = 10 * time.Second
+)
+
+// bloomIndexer indexes the logs blooms of the canonical chain in the background, one section at a time
+type bloomIndexer struct {
+	logger hclog.Logger
+	b      *Blockchain
+
+	// sections is the number of sections indexed, starting from the first one
+	sections uint64
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// StartBloomIndexer starts indexing the logs blooms of the chain in the background.
+// The sections already indexed are resumed from the storage
+func (b *Blockchain) StartBloomIndexer() {
+	b.bloomIndexer = &bloomIndexer{
+		logger:  b.logger.Named("bloom-indexer"),
+		b:       b,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	go b.bloomIndexer.run()
+}
+
+func (i *bloomIndexer) close() {
+	close(i.closeCh)
+	<-i.doneCh
+}
+
+func (i *bloomIndexer) run() {
+	defer close(i.doneCh)
+
+	for {
+		if _, ok := i.b.bloomSectionHead(i.sections); !ok {
+			break
+		}
+		i.sections++
+	}
+
+	i.logger.Debug("resuming the bloom index", "sections", i.sections)
+
+	ticker := time.NewTicker(bloomIndexInterval)
+	defer ticker.Stop()
+
+	for {
+		i.index()
+
+		select {
+		case <-ticker.C:
+		case <-i.closeCh:
+			return
+		}
+	}
+}
+
+// index indexes the sections that are complete and deep enough in the chain
+func (i *bloomIndexer) index() {
+	for {
+		head := i.b.Header()
+		if head == nil || head.Number+1 < bloomConfirmations {
+			return
+		}
+		available := (head.Number + 1 - bloomConfirmations) / BloomSectionSize
+
+		// the sections of a reorganized chain are indexed again
+		for i.sections > 0 {
+			if _, ok := i.b.bloomSectionHead(i.sections - 1); ok {
+				break
+			}
+			i.sections--
+		}
+
+		if i.sections >= available {
+			return
+		}
+
+		select {
+		case <-i.closeCh:
+			return
+		default:
+		}
+
+		start := time.Now()
+		if err := i.b.indexBloomSection(i.sections); err != nil {
+			i.logger.Error("failed to index the bloom section", "section", i.sections, "err", err)
+			return
+		}
+
+		i.logger.Debug(
+			"indexed bloom section",
+			"section", i.sections,
+			"available", available,
+			"elapsed", time.Since(start),
+		)
+		i.sections++
+	}
+}
+
+// bloomSectionHead returns the last block of an indexed section,
+// if the section was indexed from the current canonical chain
+func (b *Blockchain) bloomSectionHead(section uint64) (types.Hash, bool) {
+	head, ok := b.db.ReadBloomSection(section)
+	if !ok {
+		return types.Hash{}, false
+	}
+
+	canonical, ok := b.db.ReadCanonicalHash((section+1)*BloomSectionSize - 1)
+	if !ok || canonical != head {
+		return types.Hash{}, false
+	}
+
+	return head, true
+}
+
+// indexBloomSection writes the bloom bit vectors of the blocks of the section
+func (b *Blockchain) indexBloomSection(section uint64) error {
+	last := (section+1)*BloomSectionSize - 1
+
+	head, ok := b.db.ReadCanonicalHash(last)
+	if !ok {
+		return fmt.Errorf("canonical block %d not found", last)
+	}
+
+	vectors := make([][]byte, bloomBitLength)
+
+	// the headers are read from the head of the section through the parent hashes,
+	// so the whole section belongs to the same chain even if a reorg happens meanwhile
+	hash := head
+
+	for index := uint64(BloomSectionSize); index > 0; index-- {
+		header, err := b.db.ReadHeader(hash)
+		if err != nil {
+			return fmt.Errorf("failed to read header %s: %v", hash, err)
+		}
+
+		for i, data := range header.LogsBloom {
+			if data == 0 {
+				continue
+			}
+			for j := uint(0); j < 8; j++ {
+				if data&(1<<j) == 0 {
+					continue
+				}
+
+				bit := uint(types.BloomByteLength-1-i)*8 + j
+				if vectors[bit] == nil {
+					vectors[bit] = make([]byte, BloomSectionSize/8)
+				}
+				setSectionBit(vectors[bit], index-1)
+			}
+		}
+
+		hash = header.ParentHash
+	}
+
+	// the vectors without any block are not written
+	for bit, vector := range vectors {
+		if vector == nil {
+			continue
+		}
+		if err := b.db.WriteBloomBits(uint(bit), section, head, vector); err != nil {
+			return err
+		}
+	}
+
+	return b.db.WriteBloomSection(section, head)
+}
+
+func setSectionBit(vector []byte, index uint64) {
+	vector[index/8] |= 1 << (7 - index%8)
+}
+
+func isSectionBitSet(vector []byte, index uint64) bool {
+	return vector[index/8]&(1<<(7-index%8)) != 0
+}
+
+// bloomBits returns the bits set in the logs bloom for the data
+func bloomBits(data []byte) [3]uint {
+	hasher := keccak.DefaultKeccakPool.Get()
+	defer keccak.DefaultKeccakPool.Put(hasher)
+
+	hasher.Write(data)
+	buf := hasher.Read()
+
+	var bits [3]uint
+	for i := 0; i < 6; i += 2 {
+		bits[i/2] = (uint(buf[i+1]) + (uint(buf[i]) << 8)) & (bloomBitLength - 1)
+	}
+
+	return bits
+}
+
+func isBloomBitSet(bloom types.Bloom, bit uint) bool {
+	return bloom[types.BloomByteLength-1-bit/8]&(1<<(bit%8)) != 0
+}
+
+// bloomFilter is a log filter in the form of bloom bits. A block matches if, for every group,
+// all the bits of one of the items of the group are set
+type bloomFilter [][][3]uint
+
+func newBloomFilter(addresses []types.Address, topics [][]types.Hash) bloomFilter {
+	filter := bloomFilter{}
+
+	if len(addresses) > 0 {
+		group := [][3]uint{}
+		for _, addr := range addresses {
+			group = append(group, bloomBits(addr.Bytes()))
+		}
+		filter = append(filter, group)
+	}
+
+	for _, set := range topics {
+		if len(set) == 0 {
+			// any topic
+			continue
+		}
+
+		group := [][3]uint{}
+		for _, topic := range set {
+			group = append(group, bloomBits(topic.Bytes()))
+		}
+		filter = append(filter, group)
+	}
+
+	return filter
+}
+
+func (f bloomFilter) matchBloom(bloom types.Bloom) bool {
+	for _, group := range f {
+		match := false
+		for _, bits := range group {
+			if isBloomBitSet(bloom, bits[0]) && isBloomBitSet(bloom, bits[1]) && isBloomBitSet(bloom, bits[2]) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchSection returns the vector of the blocks of an indexed section that match the filter
+func (f bloomFilter) matchSection(b *Blockchain, section uint64, head types.Hash) []byte {
+	vectors := map[uint][]byte{}
+	readVector := func(bit uint) ([]byte, bool) {
+		vector, ok := vectors[bit]
+		if !ok {
+			vector, ok = b.db.ReadBloomBits(bit, section, head)
+			if !ok {
+				// no block of the section sets the bit
+				vector = nil
+			}
+			vectors[bit] = vector
+		}
+
+		return vector, vector != nil
+	}
+
+	var result []byte
+
+	for _, group := range f {
+		groupMatch := make([]byte, BloomSectionSize/8)
+
+		for _, bits := range group {
+			v0, ok0 := readVector(bits[0])
+			v1, ok1 := readVector(bits[1])
+			v2, ok2 := readVector(bits[2])
+
+			if !ok0 || !ok1 || !ok2 {
+				continue
+			}
+			for i := range groupMatch {
+				groupMatch[i] |= v0[i] & v1[i] & v2[i]
+			}
+		}
+
+		if result == nil {
+			result = groupMatch
+		} else {
+			for i := range result {
+				result[i] &= groupMatch[i]
+			}
+		}
+	}
+
+	return result
+}
+
+// FilterLogBlocks returns the canonical blocks in the [from, to] range that may include logs
+// emitted by one of the addresses, with one of the topics of every set at the position of the set.
+// Empty address lists and topic sets match any log.
+// The indexed sections are filtered with the bloom bits, the rest of the blocks with their headers
+func (b *Blockchain) FilterLogBlocks(from, to uint64, addresses []types.Address, topics [][]types.Hash) []uint64 {
+	numbers := []uint64{}
+	filter := newBloomFilter(addresses, topics)
+
+	for section := from / BloomSectionSize; section <= to/BloomSectionSize; section++ {
+		start := section * BloomSectionSize
+		if start < from {
+			start = from
+		}
+
+		end := (section+1)*BloomSectionSize - 1
+		if end > to {
+			end = to
+		}
+
+		if head, ok := b.bloomSectionHead(section); ok && len(filter) > 0 {
+			match := filter.matchSection(b, section, head)
+			for n := start; n <= end; n++ {
+				if isSectionBitSet(match, n%BloomSectionSize) {
+					numbers = append(numbers, n)
+				}
+			}
+
+			continue
+		}
+
+		for n := start; n <= end; n++ {
+			header, ok := b.GetHeaderByNumber(n)
+			if !ok {
+				// the end of the chain
+				return numbers
+			}
+			if filter.matchBloom(header.LogsBloom) {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+
+	return numbers
+}
@@ -0,0 +1,136 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	bloomAddr  = types.StringToAddress("1")
+	bloomTopic = types.StringToHash("2")
+)
+
+// newBloomBlockchain creates a chain where the blocks multiple of 100 have logs from bloomAddr,
+// and the blocks multiple of 150 have logs with bloomTopic
+func newBloomBlockchain(t *testing.T, n int) *Blockchain {
+	t.Helper()
+
+	headers := NewTestHeaderChain(1)
+	for i := 1; i < n; i++ {
+		header := &types.Header{
+			Number:       uint64(i),
+			ParentHash:   headers[i-1].Hash,
+			TxRoot:       types.EmptyRootHash,
+			Sha3Uncles:   types.EmptyUncleHash,
+			ReceiptsRoot: types.EmptyRootHash,
+			Difficulty:   uint64(i),
+		}
+
+		log := &types.Log{}
+		if i%100 == 0 {
+			log.Address = bloomAddr
+		}
+		if i%150 == 0 {
+			log.Topics = []types.Hash{bloomTopic}
+		}
+		if i%100 == 0 || i%150 == 0 {
+			header.LogsBloom = types.CreateBloom([]*types.Receipt{{Logs: []*types.Log{log}}})
+		}
+
+		header.ComputeHash()
+		headers = append(headers, header)
+	}
+
+	b := NewTestBlockchain(t, headers)
+
+	// the test genesis is only written as the canonical head
+	assert.NoError(t, b.db.WriteHeader(headers[0]))
+
+	return b
+}
+
+func multiplesOf(from, to uint64, divisors ...uint64) []uint64 {
+	res := []uint64{}
+	for n := from; n <= to; n++ {
+		match := true
+		for _, d := range divisors {
+			if n%d != 0 {
+				match = false
+			}
+		}
+		if match {
+			res = append(res, n)
+		}
+	}
+
+	return res
+}
+
+func TestBloomIndexFilterLogBlocks(t *testing.T) {
+	b := newBloomBlockchain(t, 2*BloomSectionSize+300)
+
+	indexer := &bloomIndexer{logger: hclog.NewNullLogger(), b: b}
+	indexer.index()
+
+	// the last section is not indexed until it is deep enough in the chain
+	assert.Equal(t, uint64(2), indexer.sections)
+
+	check := func() {
+		to := b.Header().Number
+
+		assert.Equal(t, multiplesOf(1, to, 100), b.FilterLogBlocks(1, to, []types.Address{bloomAddr}, nil))
+		assert.Equal(
+			t,
+			multiplesOf(1, to, 150),
+			b.FilterLogBlocks(1, to, nil, [][]types.Hash{{bloomTopic}}),
+		)
+		assert.Equal(
+			t,
+			multiplesOf(1, to, 300),
+			b.FilterLogBlocks(1, to, []types.Address{bloomAddr}, [][]types.Hash{{bloomTopic}}),
+		)
+
+		// ranges within the sections
+		assert.Equal(
+			t,
+			multiplesOf(4000, 4200, 100),
+			b.FilterLogBlocks(4000, 4200, []types.Address{bloomAddr}, [][]types.Hash{{}}),
+		)
+
+		// unknown items and ranges beyond the head
+		assert.Empty(t, b.FilterLogBlocks(1, to, []types.Address{types.StringToAddress("3")}, nil))
+		assert.Equal(t, []uint64{to}, b.FilterLogBlocks(to, to+10, nil, nil))
+	}
+
+	check()
+
+	// the sections are not used once they are not part of the canonical chain anymore
+	assert.NoError(t, b.db.WriteBloomSection(1, types.StringToHash("reorg")))
+
+	_, ok := b.bloomSectionHead(1)
+	assert.False(t, ok)
+
+	check()
+
+	// and they are indexed again
+	indexer.index()
+	assert.Equal(t, uint64(2), indexer.sections)
+
+	_, ok = b.bloomSectionHead(1)
+	assert.True(t, ok)
+
+	check()
+}
+
+func TestBloomIndexerResume(t *testing.T) {
+	b := newBloomBlockchain(t, BloomSectionSize+300)
+	assert.NoError(t, b.indexBloomSection(0))
+
+	b.StartBloomIndexer()
+	assert.NoError(t, b.Close())
+
+	assert.Equal(t, uint64(1), b.bloomIndexer.sections)
+}
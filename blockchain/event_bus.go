@@ -0,0 +1,89 @@
+package blockchain
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// SetEventBus sets the bus the new heads, the new finalized blocks and the reorgs are published on
+func (b *Blockchain) SetEventBus(bus *eventbus.Bus) {
+	b.bus = bus
+}
+
+// FinalizedNumber returns the number of the latest block that can't be reorganized anymore:
+// the block deeper than the maximum reorg depth, or the latest frozen block
+func (b *Blockchain) FinalizedNumber() uint64 {
+	var finalized uint64
+
+	head := b.Header()
+	if b.maxReorgDepth != 0 && head.Number > b.maxReorgDepth {
+		finalized = head.Number - b.maxReorgDepth
+	}
+
+	if frozen := b.db.Frozen(); frozen > finalized+1 {
+		// the frozen count includes the genesis
+		finalized = frozen - 1
+	}
+
+	return finalized
+}
+
+// publishEvent publishes the blockchain event on the bus
+func (b *Blockchain) publishEvent(evnt *Event) {
+	if b.bus == nil || evnt.Type == EventFork || len(evnt.NewChain) == 0 {
+		return
+	}
+
+	if evnt.Type == EventReorg && len(evnt.OldChain) != 0 {
+		if reorg := b.newReorg(evnt); reorg != nil {
+			b.bus.Publish(reorg)
+		}
+	}
+
+	// the new chain of a reorg is not sorted
+	head := evnt.NewChain[0]
+	for _, header := range evnt.NewChain[1:] {
+		if header.Number > head.Number {
+			head = header
+		}
+	}
+	b.bus.Publish(&eventbus.NewHead{Header: head})
+
+	finalized := b.FinalizedNumber()
+	if finalized <= atomic.LoadUint64(&b.finalized) {
+		return
+	}
+	atomic.StoreUint64(&b.finalized, finalized)
+
+	if header, ok := b.GetHeaderByNumber(finalized); ok {
+		b.bus.Publish(&eventbus.NewFinalized{Header: header})
+	}
+}
+
+// newReorg returns the reorg of the blockchain event, nil if the common ancestor is not found
+func (b *Blockchain) newReorg(evnt *Event) *eventbus.Reorg {
+	sorted := func(headers []*types.Header) []*types.Header {
+		res := append([]*types.Header{}, headers...)
+		sort.Slice(res, func(i, j int) bool {
+			return res[i].Number < res[j].Number
+		})
+
+		return res
+	}
+
+	added := sorted(evnt.NewChain)
+
+	ancestor, ok := b.readHeader(added[0].ParentHash)
+	if !ok {
+		return nil
+	}
+
+	return &eventbus.Reorg{
+		Ancestor: ancestor,
+		Removed:  sorted(evnt.OldChain),
+		Added:    added,
+	}
+}
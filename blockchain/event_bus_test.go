@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishEvents(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+
+	// the side chain forks after block 2 and overtakes the canonical chain at block 6
+	fork := NewTestHeaderFromChainWithSeed(headers[:3], 4, 1)
+
+	b := NewTestBlockchain(t, headers)
+	b.SetMaxReorgDepth(3)
+
+	bus := eventbus.New(hclog.NewNullLogger())
+	b.SetEventBus(bus)
+
+	ch, unsubscribe := bus.Subscribe(eventbus.TopicNewHead, eventbus.TopicNewFinalized, eventbus.TopicReorg)
+	defer unsubscribe()
+
+	for _, h := range fork[3:] {
+		assert.NoError(t, b.WriteHeaders([]*types.Header{h}))
+	}
+
+	hashes := func(headers []*types.Header) []types.Hash {
+		res := []types.Hash{}
+		for _, h := range headers {
+			res = append(res, h.Hash)
+		}
+
+		return res
+	}
+
+	// the side blocks which don't overtake the chain are not published
+	reorg, ok := (<-ch).(*eventbus.Reorg)
+	assert.True(t, ok)
+	assert.Equal(t, headers[2].Hash, reorg.Ancestor.Hash)
+	assert.Equal(t, hashes(headers[3:]), hashes(reorg.Removed))
+	assert.Equal(t, hashes(fork[3:]), hashes(reorg.Added))
+
+	head, ok := (<-ch).(*eventbus.NewHead)
+	assert.True(t, ok)
+	assert.Equal(t, fork[6].Hash, head.Header.Hash)
+
+	// the blocks deeper than the maximum reorg depth are final
+	finalized, ok := (<-ch).(*eventbus.NewFinalized)
+	assert.True(t, ok)
+	assert.Equal(t, fork[3].Hash, finalized.Header.Hash)
+	assert.Equal(t, uint64(3), b.FinalizedNumber())
+}
@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// freezeBatchSize is the maximum number of blocks moved to the freezer at once
+	freezeBatchSize = 2048
+
+	// freezeInterval is the time between the runs of the background freezer
+	freezeInterval = 30 * time.Second
+)
+
+// freezer moves the blocks that are deep enough in the chain to the freezer of the storage, in the background
+type freezer struct {
+	logger hclog.Logger
+	b      *Blockchain
+
+	// threshold is the number of recent blocks kept in the key-value store
+	threshold uint64
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// StartFreezer starts moving the blocks older than the threshold to the freezer in the background.
+// The frozen blocks are still read transparently, but they can't be reorganized anymore
+func (b *Blockchain) StartFreezer(threshold uint64) {
+	b.freezer = &freezer{
+		logger:    b.logger.Named("freezer"),
+		b:         b,
+		threshold: threshold,
+		closeCh:   make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+
+	go b.freezer.run()
+}
+
+func (f *freezer) close() {
+	close(f.closeCh)
+	<-f.doneCh
+}
+
+func (f *freezer) run() {
+	defer close(f.doneCh)
+
+	f.logger.Debug("resuming the freezer", "frozen", f.b.db.Frozen())
+
+	ticker := time.NewTicker(freezeInterval)
+	defer ticker.Stop()
+
+	for {
+		f.freeze()
+
+		select {
+		case <-ticker.C:
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// freeze moves the blocks older than the threshold to the freezer, one batch at a time
+func (f *freezer) freeze() {
+	for {
+		head := f.b.Header()
+		if head == nil || head.Number < f.threshold {
+			return
+		}
+
+		frozen := f.b.db.Frozen()
+		limit := head.Number - f.threshold
+		if limit <= frozen {
+			return
+		}
+		if limit > frozen+freezeBatchSize {
+			limit = frozen + freezeBatchSize
+		}
+
+		select {
+		case <-f.closeCh:
+			return
+		default:
+		}
+
+		start := time.Now()
+		if err := f.b.db.Freeze(limit); err != nil {
+			f.logger.Error("failed to freeze the blocks", "from", frozen, "to", limit, "err", err)
+			return
+		}
+
+		f.logger.Debug("froze blocks", "from", frozen, "to", limit, "elapsed", time.Since(start))
+	}
+}
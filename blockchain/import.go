@@ -0,0 +1,47 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+var (
+	ErrImportGenesisMismatch = errors.New("the imported chain has another genesis block")
+)
+
+// ImportBlocks writes consecutive blocks of an exported chain. The blocks already in the canonical
+// chain are skipped, the others are verified and executed like the synced blocks.
+// It returns the number of written blocks
+func (b *Blockchain) ImportBlocks(blocks []*types.Block) (int, error) {
+	first := len(blocks)
+	for i, block := range blocks {
+		if block.Number() == 0 && block.Hash() != b.genesis {
+			return 0, ErrImportGenesisMismatch
+		}
+
+		hash, ok := b.db.ReadCanonicalHash(block.Number())
+		if !ok || hash != block.Hash() {
+			first = i
+			break
+		}
+	}
+
+	blocks = blocks[first:]
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].Number() != blocks[i-1].Number()+1 {
+			return 0, fmt.Errorf("the imported blocks are not consecutive at block %d", blocks[i].Number())
+		}
+	}
+
+	if err := b.WriteBlocks(blocks); err != nil {
+		return 0, err
+	}
+
+	return len(blocks), nil
+}
@@ -0,0 +1,41 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBlocks(t *testing.T) {
+	headers := NewTestHeaderChain(10)
+	blocks := HeadersToBlocks(headers)
+
+	// the node has the first blocks of the exported chain
+	b := NewTestBlockchain(t, headers[:4])
+	b.genesis = headers[0].Hash
+	b.executor = &replayExecutor{diverging: map[uint64]bool{}}
+
+	count, err := b.ImportBlocks(blocks[:6])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, uint64(5), b.Header().Number)
+
+	// the blocks must follow each other
+	_, err = b.ImportBlocks([]*types.Block{blocks[6], blocks[8]})
+	assert.Error(t, err)
+
+	count, err = b.ImportBlocks(blocks[6:])
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, headers[9].Hash, b.Header().Hash)
+
+	// importing the chain again is a no-op
+	count, err = b.ImportBlocks(blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	other := HeadersToBlocks(NewTestHeaderChainWithSeed(nil, 3, 1))
+	_, err = b.ImportBlocks(other)
+	assert.Equal(t, ErrImportGenesisMismatch, err)
+}
@@ -0,0 +1,149 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// recoverHead finds the highest canonical block whose header and total difficulty were written,
+// when the ones of the head were lost by an unclean shutdown, and makes it the head
+func (b *Blockchain) recoverHead() (*types.Header, *big.Int, error) {
+	number, ok := b.db.ReadHeadNumber()
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to read the head number")
+	}
+
+	for n := number; ; n-- {
+		if header, ok := b.GetHeaderByNumber(n); ok {
+			if diff, ok := b.GetTD(header.Hash); ok {
+				if err := b.resetHead(header, number); err != nil {
+					return nil, nil, err
+				}
+
+				b.logger.Warn("recovered the head from an incomplete block", "number", header.Number, "lost", number-n)
+
+				return header, diff, nil
+			}
+		}
+
+		if n == 0 {
+			return nil, nil, fmt.Errorf("no complete block found below the head %d", number)
+		}
+	}
+}
+
+// resetHead writes the header as the head, and clears the canonical numbers up to the previous head
+func (b *Blockchain) resetHead(header *types.Header, prevNumber uint64) error {
+	if err := b.db.WriteHeadHash(header.Hash); err != nil {
+		return err
+	}
+	if err := b.db.WriteHeadNumber(header.Number); err != nil {
+		return err
+	}
+
+	for n := header.Number + 1; n <= prevNumber; n++ {
+		if err := b.db.WriteCanonicalHash(n, types.ZeroHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckIntegrity checks the head block was completely written, along with its receipts and its state,
+// and rolls the chain back to the last consistent block otherwise. hasState tells whether the state
+// of a root is in the state storage. An unclean shutdown may lose the last writes of the databases
+func (b *Blockchain) CheckIntegrity(hasState func(root types.Hash) bool) error {
+	head := b.Header()
+
+	err := b.checkBlock(head, hasState)
+	if err == nil {
+		return nil
+	}
+
+	b.logger.Warn("the head block is inconsistent, rolling back", "number", head.Number, "hash", head.Hash, "err", err)
+
+	for n := head.Number; n > 0; n-- {
+		header, ok := b.GetHeaderByNumber(n - 1)
+		if !ok {
+			continue
+		}
+		if err := b.checkBlock(header, hasState); err != nil {
+			b.logger.Debug("inconsistent block", "number", header.Number, "err", err)
+
+			continue
+		}
+
+		if err := b.rollBack(header, head.Number); err != nil {
+			return fmt.Errorf("failed to roll back to the consistent block %d: %v", header.Number, err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no consistent block found below the head %d: %v", head.Number, err)
+}
+
+// rollBack makes the consistent block the head. Unlike a rewind, the blocks after it may have lost
+// their headers, so the canonical numbers up to the previous head are cleared without reading them
+func (b *Blockchain) rollBack(header *types.Header, prevNumber uint64) error {
+	// the frozen blocks are final
+	if frozen := b.db.Frozen(); header.Number+1 < frozen {
+		return fmt.Errorf("the first %d blocks are frozen", frozen)
+	}
+
+	diff, ok := b.GetTD(header.Hash)
+	if !ok {
+		return fmt.Errorf("the total difficulty is missing")
+	}
+
+	if err := b.resetHead(header, prevNumber); err != nil {
+		return err
+	}
+	b.setCurrentHeader(header, diff)
+
+	b.logger.Warn("rolled back to the last consistent block", "number", header.Number, "removed", prevNumber-header.Number)
+
+	return nil
+}
+
+// checkBlock checks the canonical block has its total difficulty, its body, its receipts and its state
+func (b *Blockchain) checkBlock(header *types.Header, hasState func(root types.Hash) bool) error {
+	if hash, ok := b.db.ReadCanonicalHash(header.Number); !ok || hash != header.Hash {
+		return fmt.Errorf("the block is not canonical")
+	}
+	if _, ok := b.GetTD(header.Hash); !ok {
+		return fmt.Errorf("the total difficulty is missing")
+	}
+
+	// the genesis has no body, and its state is written before the chain
+	if header.Number != 0 {
+		body, err := b.db.ReadBody(header.Hash)
+		if err != nil {
+			return fmt.Errorf("the body is missing: %v", err)
+		}
+
+		if len(body.Transactions) != 0 {
+			receipts, err := b.db.ReadReceipts(header.Hash)
+			if err != nil {
+				return fmt.Errorf("the receipts are missing: %v", err)
+			}
+			if len(receipts) != len(body.Transactions) {
+				return fmt.Errorf("%d receipts for %d transactions", len(receipts), len(body.Transactions))
+			}
+		}
+	}
+
+	// the databases written before the state roots were indexed only have the root of the header
+	root, ok := b.db.ReadStateRoot(header.Number, header.Hash)
+	if !ok {
+		root = header.StateRoot
+	}
+	if !hasState(root) {
+		return fmt.Errorf("the state %s is missing", root)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+	b := NewTestBlockchain(t, headers)
+
+	// every block has a body, and an indexed state
+	states := map[types.Hash]bool{}
+	for _, h := range headers {
+		root := types.BytesToHash([]byte{byte(h.Number + 1)})
+		assert.NoError(t, b.db.WriteBody(h.Hash, &types.Body{}))
+		assert.NoError(t, b.db.WriteStateRoot(h.Number, h.Hash, root))
+		states[root] = true
+	}
+	hasState := func(root types.Hash) bool {
+		return states[root]
+	}
+
+	assert.NoError(t, b.CheckIntegrity(hasState))
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+	// the state of the head and the receipts of its parent were lost
+	delete(states, types.BytesToHash([]byte{6}))
+	assert.NoError(t, b.db.WriteBody(headers[4].Hash, &types.Body{Transactions: []*types.Transaction{{}}}))
+
+	assert.NoError(t, b.CheckIntegrity(hasState))
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+
+	_, ok := b.GetHeaderByNumber(4)
+	assert.False(t, ok)
+
+	// no block has its state
+	assert.Error(t, b.CheckIntegrity(func(types.Hash) bool {
+		return false
+	}))
+}
+
+func TestRecoverHead(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+	b := NewTestBlockchain(t, headers)
+
+	// the header of the head was lost
+	assert.NoError(t, b.db.WriteCanonicalHash(5, types.StringToHash("1")))
+
+	header, diff, err := b.recoverHead()
+	assert.NoError(t, err)
+	assert.Equal(t, headers[4].Hash, header.Hash)
+
+	td, _ := b.GetTD(headers[4].Hash)
+	assert.Equal(t, td, diff)
+
+	hash, _ := b.db.ReadHeadHash()
+	assert.Equal(t, headers[4].Hash, hash)
+
+	number, _ := b.db.ReadHeadNumber()
+	assert.Equal(t, uint64(4), number)
+
+	_, ok := b.GetHeaderByNumber(5)
+	assert.False(t, ok)
+}
+
+func TestCheckIntegrity_MissingHeader(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+	b := NewTestBlockchain(t, headers)
+
+	for _, h := range headers {
+		assert.NoError(t, b.db.WriteBody(h.Hash, &types.Body{}))
+	}
+
+	// the state is only kept up to the block 3
+	root := types.StringToHash("2")
+	assert.NoError(t, b.db.WriteStateRoot(3, headers[3].Hash, root))
+
+	// the header of the parent of the head was lost
+	assert.NoError(t, b.db.WriteCanonicalHash(4, types.StringToHash("1")))
+
+	assert.NoError(t, b.CheckIntegrity(func(r types.Hash) bool {
+		return r == root
+	}))
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+
+	for n := uint64(4); n <= 5; n++ {
+		_, ok := b.GetHeaderByNumber(n)
+		assert.False(t, ok)
+	}
+
+	number, _ := b.db.ReadHeadNumber()
+	assert.Equal(t, uint64(3), number)
+}
+
+func TestCheckIntegrity_NoStateRootIndex(t *testing.T) {
+	headers := NewTestHeaderChain(6)
+	b := NewTestBlockchain(t, headers)
+
+	// the chain was written before the state roots were indexed, the roots of the headers are checked
+	states := map[types.Hash]bool{}
+	for _, h := range headers {
+		assert.NoError(t, b.db.WriteBody(h.Hash, &types.Body{}))
+		states[h.StateRoot] = true
+	}
+
+	assert.NoError(t, b.CheckIntegrity(func(root types.Hash) bool {
+		return states[root]
+	}))
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+}
@@ -0,0 +1,57 @@
+package blockchain
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the blockchain metrics
+type Metrics struct {
+	// Number of the head of the canonical chain
+	Height metrics.Gauge
+	// No.of reorgs replacing canonical blocks
+	Reorgs metrics.Counter
+	// No.of canonical blocks replaced by the reorgs
+	ReorgedBlocks metrics.Counter
+}
+
+// GetPrometheusMetrics return the blockchain metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		Height: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "height",
+			Help:      "Number of the head of the canonical chain.",
+		}, labels).With(labelsWithValues...),
+		Reorgs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "reorgs",
+			Help:      "Number of reorgs replacing canonical blocks.",
+		}, labels).With(labelsWithValues...),
+		ReorgedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "reorged_blocks",
+			Help:      "Number of canonical blocks replaced by the reorgs.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational blockchain metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		Height:        discard.NewGauge(),
+		Reorgs:        discard.NewCounter(),
+		ReorgedBlocks: discard.NewCounter(),
+	}
+}
@@ -0,0 +1,311 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/types/buildroot"
+)
+
+// ReplayTraceMode selects the replayed blocks whose transactions are traced
+type ReplayTraceMode int
+
+const (
+	// ReplayTraceNone traces no block
+	ReplayTraceNone ReplayTraceMode = iota
+
+	// ReplayTraceMismatches traces the blocks whose execution does not match the stored block
+	ReplayTraceMismatches
+
+	// ReplayTraceAll traces every replayed block
+	ReplayTraceAll
+)
+
+// BlockTracer is implemented by the executors which can trace the transactions of a block
+type BlockTracer interface {
+	TraceBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) ([]*state.CallTrace, error)
+}
+
+// ReplayResult is the outcome of the re-execution of a canonical block
+type ReplayResult struct {
+	Number   uint64
+	Hash     types.Hash
+	Txns     int
+	GasUsed  uint64
+	Duration time.Duration
+
+	// Err is set if the block could not be executed,
+	// or if the execution does not match the stored block
+	Err error
+
+	// Traces are the traces of the transactions of the block, if traced.
+	// TraceErr is set if the block could not be traced
+	Traces   []*ReplayTxTrace
+	TraceErr error
+}
+
+// ReplayTxTrace is the trace of a replayed transaction, with its receipt and the stored one
+type ReplayTxTrace struct {
+	TxHash        types.Hash       `json:"txHash"`
+	Status        *uint64          `json:"status"`
+	StoredStatus  *uint64          `json:"storedStatus"`
+	GasUsed       uint64           `json:"gasUsed"`
+	StoredGasUsed uint64           `json:"storedGasUsed"`
+	Logs          int              `json:"logs"`
+	StoredLogs    int              `json:"storedLogs"`
+	Trace         *state.CallTrace `json:"trace"`
+}
+
+// ReplayBlock re-executes the canonical block at the given height on top of the state of its parent,
+// and verifies the resulting state root, gas used and receipts against the stored ones.
+// The chain is not modified
+func (b *Blockchain) ReplayBlock(number uint64) *ReplayResult {
+	return b.replayBlock(number, ReplayTraceNone)
+}
+
+func (b *Blockchain) replayBlock(number uint64, trace ReplayTraceMode) *ReplayResult {
+	res := &ReplayResult{
+		Number: number,
+	}
+
+	if number == 0 {
+		res.Err = fmt.Errorf("the genesis block cannot be replayed")
+		return res
+	}
+
+	block, ok := b.GetBlockByNumber(number, true)
+	if !ok {
+		res.Err = fmt.Errorf("block %d not found", number)
+		return res
+	}
+	header := block.Header
+
+	res.Hash = header.Hash
+	res.Txns = len(block.Transactions)
+
+	parent, ok := b.readHeader(header.ParentHash)
+	if !ok {
+		res.Err = fmt.Errorf("parent of block %d not found", number)
+		return res
+	}
+
+	blockCreator, err := b.consensus.GetBlockCreator(header)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	start := time.Now()
+	result, err := b.executor.ProcessBlock(parent.StateRoot, block, blockCreator)
+	res.Duration = time.Since(start)
+
+	if err != nil {
+		res.Err = fmt.Errorf("failed to execute the block: %v", err)
+		return res
+	}
+	res.GasUsed = result.TotalGas
+
+	stored, err := b.db.ReadReceipts(header.Hash)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to read the stored receipts: %v", err)
+	} else {
+		res.Err = verifyReplay(header, result, stored)
+	}
+
+	if trace == ReplayTraceAll || (trace == ReplayTraceMismatches && res.Err != nil) {
+		res.Traces, res.TraceErr = b.traceReplay(parent, block, blockCreator, result.Receipts, stored)
+	}
+
+	return res
+}
+
+// verifyReplay checks the execution of a block against the stored block and receipts
+func verifyReplay(header *types.Header, result *state.BlockResult, stored []*types.Receipt) error {
+	if result.Root != header.StateRoot {
+		return fmt.Errorf("state root mismatch: have %s, want %s", result.Root, header.StateRoot)
+	}
+	if result.TotalGas != header.GasUsed {
+		return fmt.Errorf("gas used mismatch: have %d, want %d", result.TotalGas, header.GasUsed)
+	}
+	if root := buildroot.CalculateReceiptsRoot(result.Receipts); root != header.ReceiptsRoot {
+		return fmt.Errorf("receipts root mismatch: have %s, want %s", root, header.ReceiptsRoot)
+	}
+
+	return compareReceipts(result.Receipts, stored)
+}
+
+// traceReplay traces the transactions of the replayed block, along with their receipts
+func (b *Blockchain) traceReplay(
+	parent *types.Header,
+	block *types.Block,
+	blockCreator types.Address,
+	have, want []*types.Receipt,
+) ([]*ReplayTxTrace, error) {
+	tracer, ok := b.executor.(BlockTracer)
+	if !ok {
+		return nil, fmt.Errorf("the executor does not trace the blocks")
+	}
+
+	traces, err := tracer.TraceBlock(parent.StateRoot, block, blockCreator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace the block: %v", err)
+	}
+
+	status := func(receipt *types.Receipt) *uint64 {
+		if receipt.Status == nil {
+			return nil
+		}
+		v := uint64(*receipt.Status)
+		return &v
+	}
+
+	res := make([]*ReplayTxTrace, 0, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		tx := &ReplayTxTrace{TxHash: txn.Hash}
+		if i < len(traces) {
+			tx.Trace = traces[i]
+		}
+		if i < len(have) {
+			tx.Status, tx.GasUsed, tx.Logs = status(have[i]), have[i].GasUsed, len(have[i].Logs)
+		}
+		if i < len(want) {
+			tx.StoredStatus, tx.StoredGasUsed, tx.StoredLogs = status(want[i]), want[i].GasUsed, len(want[i].Logs)
+		}
+		res = append(res, tx)
+	}
+
+	return res, nil
+}
+
+// compareReceipts checks the receipts of a re-executed block against the stored ones,
+// including the fields that are not part of the receipts root
+func compareReceipts(have, want []*types.Receipt) error {
+	if len(have) != len(want) {
+		return fmt.Errorf("receipts count mismatch: have %d, want %d", len(have), len(want))
+	}
+
+	for i := range have {
+		h, w := have[i], want[i]
+
+		if h.TxHash != w.TxHash {
+			return fmt.Errorf("receipt %d: tx hash mismatch: have %s, want %s", i, h.TxHash, w.TxHash)
+		}
+		if h.GasUsed != w.GasUsed {
+			return fmt.Errorf("receipt %d: gas used mismatch: have %d, want %d", i, h.GasUsed, w.GasUsed)
+		}
+		if h.CumulativeGasUsed != w.CumulativeGasUsed {
+			return fmt.Errorf(
+				"receipt %d: cumulative gas used mismatch: have %d, want %d",
+				i,
+				h.CumulativeGasUsed,
+				w.CumulativeGasUsed,
+			)
+		}
+		if h.ContractAddress != w.ContractAddress {
+			return fmt.Errorf(
+				"receipt %d: contract address mismatch: have %s, want %s",
+				i,
+				h.ContractAddress,
+				w.ContractAddress,
+			)
+		}
+		if len(h.Logs) != len(w.Logs) {
+			return fmt.Errorf("receipt %d: logs count mismatch: have %d, want %d", i, len(h.Logs), len(w.Logs))
+		}
+	}
+
+	return nil
+}
+
+// ReplayBlocks replays the canonical blocks in the [from, to] range using the given number of workers.
+// The blocks only depend on the stored state of their parents, so they are executed concurrently,
+// while the results are handed to the callback in block order. The blocks selected by the trace mode
+// are traced too
+func (b *Blockchain) ReplayBlocks(
+	ctx context.Context,
+	from, to uint64,
+	workers int,
+	trace ReplayTraceMode,
+	fn func(res *ReplayResult) error,
+) error {
+	if from > to {
+		return fmt.Errorf("invalid range, from %d is larger than to %d", from, to)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if count := to - from + 1; count != 0 && count < uint64(workers) {
+		workers = int(count)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// every block is handed to the workers together with the channel of its result,
+	// and the channels are read in order
+	type job struct {
+		number uint64
+		resCh  chan *ReplayResult
+	}
+
+	jobCh := make(chan *job)
+	orderCh := make(chan *job, workers)
+
+	go func() {
+		defer close(orderCh)
+		defer close(jobCh)
+
+		for number := from; number <= to; number++ {
+			j := &job{number: number, resCh: make(chan *ReplayResult, 1)}
+
+			select {
+			case orderCh <- j:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobCh <- j:
+			case <-ctx.Done():
+				return
+			}
+
+			if number == to {
+				// avoid the overflow of the loop variable
+				break
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range jobCh {
+				j.resCh <- b.replayBlock(j.number, trace)
+			}
+		}()
+	}
+	defer func() {
+		// stop handing out blocks and wait for the running replays
+		cancel()
+		wg.Wait()
+	}()
+
+	for j := range orderCh {
+		select {
+		case res := <-j.resCh:
+			if err := fn(res); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,157 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// replayExecutor returns the stored results, except for the blocks marked as diverging
+type replayExecutor struct {
+	diverging map[uint64]bool
+}
+
+func (e *replayExecutor) ProcessBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) (*state.BlockResult, error) {
+	root := block.Header.StateRoot
+	if e.diverging[block.Number()] {
+		root = types.StringToHash("diverging")
+	}
+
+	return &state.BlockResult{Root: root}, nil
+}
+
+// tracingReplayExecutor traces the blocks with a frame per transaction
+type tracingReplayExecutor struct {
+	replayExecutor
+}
+
+func (e *tracingReplayExecutor) TraceBlock(
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+) ([]*state.CallTrace, error) {
+	traces := []*state.CallTrace{}
+	for _, txn := range block.Transactions {
+		traces = append(traces, &state.CallTrace{Type: state.CallTraceCall, Gas: txn.Gas})
+	}
+
+	return traces, nil
+}
+
+func newReplayBlockchain(t *testing.T, n int, diverging ...uint64) *Blockchain {
+	t.Helper()
+
+	headers := NewTestHeaderChain(n)
+	b := NewTestBlockchain(t, headers)
+
+	// the test genesis is only written as the canonical head
+	assert.NoError(t, b.db.WriteHeader(headers[0]))
+
+	for _, block := range HeadersToBlocks(headers[1:]) {
+		assert.NoError(t, b.writeBody(block))
+		assert.NoError(t, b.db.WriteReceipts(block.Hash(), []*types.Receipt{}))
+	}
+
+	executor := &replayExecutor{diverging: map[uint64]bool{}}
+	for _, number := range diverging {
+		executor.diverging[number] = true
+	}
+	b.executor = executor
+
+	return b
+}
+
+func TestReplayBlock(t *testing.T) {
+	b := newReplayBlockchain(t, 5, 3)
+
+	res := b.ReplayBlock(2)
+	assert.NoError(t, res.Err)
+	assert.Equal(t, uint64(2), res.Number)
+
+	res = b.ReplayBlock(3)
+	assert.Error(t, res.Err)
+	assert.Contains(t, res.Err.Error(), "state root mismatch")
+
+	assert.Error(t, b.ReplayBlock(0).Err)
+	assert.Error(t, b.ReplayBlock(10).Err)
+}
+
+func TestReplayBlocks(t *testing.T) {
+	b := newReplayBlockchain(t, 50, 7, 30)
+
+	for _, workers := range []int{1, 4, 100} {
+		numbers := []uint64{}
+		failed := []uint64{}
+
+		err := b.ReplayBlocks(context.Background(), 1, 49, workers, ReplayTraceNone, func(res *ReplayResult) error {
+			numbers = append(numbers, res.Number)
+			if res.Err != nil {
+				failed = append(failed, res.Number)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+
+		// the results are handed out in block order
+		assert.Len(t, numbers, 49)
+		for i, number := range numbers {
+			assert.Equal(t, uint64(i+1), number)
+		}
+		assert.Equal(t, []uint64{7, 30}, failed)
+	}
+
+	// the replay stops when the callback fails
+	stopErr := errors.New("stop")
+	count := 0
+
+	err := b.ReplayBlocks(context.Background(), 1, 49, 4, ReplayTraceNone, func(res *ReplayResult) error {
+		count++
+		if res.Err != nil {
+			return stopErr
+		}
+		return nil
+	})
+	assert.Equal(t, stopErr, err)
+	assert.Equal(t, 7, count)
+
+	assert.Error(t, b.ReplayBlocks(context.Background(), 5, 1, 1, ReplayTraceNone, nil))
+}
+
+func TestReplayBlocks_Trace(t *testing.T) {
+	b := newReplayBlockchain(t, 10, 4)
+
+	replay := func(trace ReplayTraceMode) map[uint64]*ReplayResult {
+		results := map[uint64]*ReplayResult{}
+		err := b.ReplayBlocks(context.Background(), 1, 9, 2, trace, func(res *ReplayResult) error {
+			results[res.Number] = res
+			return nil
+		})
+		assert.NoError(t, err)
+
+		return results
+	}
+
+	// the executor without tracing reports the failure of the traces
+	res := replay(ReplayTraceMismatches)[4]
+	assert.Nil(t, res.Traces)
+	assert.Error(t, res.TraceErr)
+
+	b.executor = &tracingReplayExecutor{*b.executor.(*replayExecutor)}
+
+	// only the mismatching blocks are traced
+	for number, res := range replay(ReplayTraceMismatches) {
+		assert.NoError(t, res.TraceErr)
+		assert.Equal(t, number == 4, res.Traces != nil, "block %d", number)
+	}
+
+	for _, res := range replay(ReplayTraceAll) {
+		assert.NotNil(t, res.Traces)
+	}
+	for _, res := range replay(ReplayTraceNone) {
+		assert.Nil(t, res.Traces)
+	}
+}
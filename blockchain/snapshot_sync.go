@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/types/buildroot"
+)
+
+// verifySyncedHeader checks that the header extends the parent, and verifies its seal
+func (b *Blockchain) verifySyncedHeader(parent, header *types.Header) error {
+	if header.Number != parent.Number+1 {
+		return fmt.Errorf("number sequence not correct at %d, %d", header.Number, parent.Number)
+	}
+	if header.ParentHash != parent.Hash {
+		return fmt.Errorf("parent hash of %d not correct", header.Number)
+	}
+
+	if err := b.consensus.VerifyHeader(parent, header); err != nil {
+		return fmt.Errorf("failed to verify the header %d: %v", header.Number, err)
+	}
+
+	return nil
+}
+
+// WriteSyncedHeaders verifies and writes a batch of headers extending the current head, without
+// their blocks. It is used by the snapshot sync, which does not execute the blocks below the pivot
+func (b *Blockchain) WriteSyncedHeaders(headers []*types.Header) error {
+	if len(headers) == 0 {
+		return fmt.Errorf("passed in headers array is empty")
+	}
+
+	parent := b.Header()
+	for _, header := range headers {
+		if err := b.verifySyncedHeader(parent, header); err != nil {
+			return err
+		}
+
+		evnt := &Event{}
+		if err := b.writeHeaderImpl(evnt, header); err != nil {
+			return err
+		}
+		b.dispatchEvent(evnt)
+
+		parent = header
+	}
+
+	return nil
+}
+
+// WriteSyncedBlock writes the pivot block of the snapshot sync, whose state was downloaded
+// instead of being computed. The block is verified against its header, and the receipts,
+// which can't be computed either, against the receipts root
+func (b *Blockchain) WriteSyncedBlock(block *types.Block, receipts []*types.Receipt) error {
+	header := block.Header
+
+	if err := b.verifySyncedHeader(b.Header(), header); err != nil {
+		return err
+	}
+
+	if hash := buildroot.CalculateUncleRoot(block.Uncles); hash != header.Sha3Uncles {
+		return fmt.Errorf("uncle root hash mismatch: have %s, want %s", hash, header.Sha3Uncles)
+	}
+	if hash := buildroot.CalculateTransactionsRoot(block.Transactions); hash != header.TxRoot {
+		return fmt.Errorf("transaction root hash mismatch: have %s, want %s", hash, header.TxRoot)
+	}
+	if len(receipts) != len(block.Transactions) {
+		return fmt.Errorf("bad size of receipts and transactions")
+	}
+	if hash := buildroot.CalculateReceiptsRoot(receipts); hash != header.ReceiptsRoot {
+		return fmt.Errorf("receipts root hash mismatch: have %s, want %s", hash, header.ReceiptsRoot)
+	}
+
+	if err := b.writeBody(block); err != nil {
+		return err
+	}
+
+	evnt := &Event{}
+	if err := b.writeHeaderImpl(evnt, header); err != nil {
+		return err
+	}
+
+	if err := b.db.WriteReceipts(block.Hash(), receipts); err != nil {
+		return err
+	}
+	if err := b.indexAddressTxs(block, receipts); err != nil {
+		return err
+	}
+	if err := b.db.WriteStateRoot(header.Number, header.Hash, header.StateRoot); err != nil {
+		return err
+	}
+
+	b.dispatchEvent(evnt)
+
+	b.logger.Info("snapshot sync pivot block", "number", header.Number, "hash", header.Hash)
+
+	return nil
+}
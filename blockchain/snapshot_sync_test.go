@@ -0,0 +1,45 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSynced(t *testing.T) {
+	headers, blocks, receipts := NewTestBodyChain(6)
+	b := NewTestBlockchain(t, nil)
+	_, err := b.advanceHead(headers[0])
+	assert.NoError(t, err)
+
+	// the headers must extend the head
+	assert.Error(t, b.WriteSyncedHeaders(headers[2:5]))
+	assert.NoError(t, b.WriteSyncedHeaders(headers[1:5]))
+	assert.Equal(t, uint64(4), b.Header().Number)
+
+	// the receipts are checked against the receipts root of the pivot
+	invalid := []*types.Receipt{{GasUsed: 1, CumulativeGasUsed: 1}}
+	assert.Error(t, b.WriteSyncedBlock(blocks[5], invalid))
+	assert.Equal(t, uint64(4), b.Header().Number)
+
+	assert.NoError(t, b.WriteSyncedBlock(blocks[5], receipts[5]))
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+	// only the state of the pivot is stored
+	root, ok := b.GetStateRoot(5)
+	assert.True(t, ok)
+	assert.Equal(t, headers[5].StateRoot, root)
+
+	_, ok = b.GetStateRoot(4)
+	assert.False(t, ok)
+
+	block, ok := b.GetBlockByNumber(5, true)
+	assert.True(t, ok)
+	assert.Len(t, block.Transactions, 1)
+
+	var stored []*types.Receipt
+	stored, err = b.GetReceiptsByHash(headers[5].Hash)
+	assert.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/hashicorp/go-hclog"
+)
+
+// NewDatabaseStorage creates the storage on top of the key-value database, and of the freezer
+// if it is not nil. The values are encrypted with the cipher if it is not nil
+func NewDatabaseStorage(db kvdb.Database, freezer *Freezer, cipher *encryption.Cipher, logger hclog.Logger) (Storage, error) {
+	if err := encryption.CheckDatabase(db, cipher); err != nil {
+		return nil, err
+	}
+
+	return NewFreezerKeyValueStorage(logger, &databaseKV{db, cipher}, freezer), nil
+}
+
+// databaseKV is the kv storage on top of a key-value database
+type databaseKV struct {
+	db     kvdb.Database
+	cipher *encryption.Cipher
+}
+
+// Set sets the key-value pair in the database
+func (d *databaseKV) Set(p []byte, v []byte) error {
+	if d.cipher != nil {
+		v = d.cipher.Seal(p, v)
+	}
+	return d.db.Put(p, v)
+}
+
+// Get retrieves the key-value pair from the database
+func (d *databaseKV) Get(p []byte) ([]byte, bool, error) {
+	data, ok, err := d.db.Get(p)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	if d.cipher != nil {
+		if data, err = d.cipher.Open(p, data); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return data, true, nil
+}
+
+// Delete removes the key from the database
+func (d *databaseKV) Delete(p []byte) error {
+	return d.db.Delete(p)
+}
+
+// Close closes the database
+func (d *databaseKV) Close() error {
+	return d.db.Close()
+}
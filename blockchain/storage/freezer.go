@@ -0,0 +1,415 @@
+package storage
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
+)
+
+// Tables of the freezer, one item per block
+const (
+	freezerHeaders  = "headers"
+	freezerBodies   = "bodies"
+	freezerReceipts = "receipts"
+)
+
+// freezerIndexSize is the size of an index entry, the end offset of the item in the data file
+const freezerIndexSize = 8
+
+// flateWriters are reused, a flate writer allocates large buffers
+var flateWriters = sync.Pool{
+	New: func() interface{} {
+		w, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return w
+	},
+}
+
+// compress deflates the item
+func compress(blob []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := flateWriters.Get().(*flate.Writer)
+	defer flateWriters.Put(w)
+
+	w.Reset(&buf)
+	if _, err := w.Write(blob); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress inflates the item
+func decompress(blob []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(blob))
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+var (
+	ErrNoFreezer         = errors.New("the storage has no freezer")
+	ErrFreezerOutOfOrder = errors.New("the freezer items must be appended in order")
+)
+
+// Freezer is an append-only store of the old blocks of the canonical chain, which are never
+// reorganized. Every block is an item of the headers, the bodies and the receipts tables, which are
+// flat files of compressed items, so they don't grow the key-value store nor its compactions
+type Freezer struct {
+	lock   sync.RWMutex
+	tables map[string]*freezerTable
+	items  uint64
+}
+
+// OpenFreezer opens the freezer in the directory, it is created if it doesn't exist.
+// The items are encrypted with the cipher, if it is not nil
+func OpenFreezer(path string, cipher *encryption.Cipher) (*Freezer, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+
+	f := &Freezer{tables: map[string]*freezerTable{}}
+	for _, name := range []string{freezerHeaders, freezerBodies, freezerReceipts} {
+		table, err := openFreezerTable(path, name, cipher)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+
+	// a block is frozen once it is in every table, the partial writes of an interrupted freeze are dropped
+	f.items = f.tables[freezerHeaders].items
+	for _, table := range f.tables {
+		if table.items < f.items {
+			f.items = table.items
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(f.items); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// Items returns the number of frozen blocks, they are the blocks from genesis up to it
+func (f *Freezer) Items() uint64 {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.items
+}
+
+// Append freezes the block with the given number, which must be the next one. The missing
+// body and receipts, of the blocks synced without them, are empty
+func (f *Freezer) Append(number uint64, header, body, receipts []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if number != f.items {
+		return ErrFreezerOutOfOrder
+	}
+
+	items := map[string][]byte{
+		freezerHeaders:  header,
+		freezerBodies:   body,
+		freezerReceipts: receipts,
+	}
+	for name, item := range items {
+		if err := f.tables[name].append(number, item); err != nil {
+			return err
+		}
+	}
+	f.items++
+
+	return nil
+}
+
+// Retrieve returns the item of the block in the table, if it is frozen and not empty
+func (f *Freezer) Retrieve(table string, number uint64) ([]byte, bool, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if number >= f.items {
+		return nil, false, nil
+	}
+
+	return f.tables[table].retrieve(number)
+}
+
+// Sync flushes the frozen items to the disk
+func (f *Freezer) Sync() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, table := range f.tables {
+		if err := table.sync(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportFiles passes the files of the tables holding the given number of first items to fn,
+// with the size of their content. The files are only appended to, so they are read while
+// the next blocks are frozen
+func (f *Freezer) ExportFiles(items uint64, fn func(name string, size int64, r io.Reader) error) error {
+	type file struct {
+		name string
+		f    *os.File
+		size uint64
+	}
+
+	f.lock.RLock()
+	if items > f.items {
+		f.lock.RUnlock()
+		return fmt.Errorf("the freezer has %d items, %d requested", f.items, items)
+	}
+
+	files := []file{}
+	for _, name := range []string{freezerHeaders, freezerBodies, freezerReceipts} {
+		table := f.tables[name]
+
+		var size uint64
+		if items > 0 {
+			end, err := table.offset(items - 1)
+			if err != nil {
+				f.lock.RUnlock()
+				return err
+			}
+			size = end
+		}
+
+		files = append(files,
+			file{name + ".dat", table.data, size},
+			file{name + ".idx", table.index, items * freezerIndexSize},
+		)
+	}
+	f.lock.RUnlock()
+
+	for _, file := range files {
+		if err := fn(file.name, int64(file.size), io.NewSectionReader(file.f, 0, int64(file.size))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the files of the tables
+func (f *Freezer) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var err error
+	for _, table := range f.tables {
+		if closeErr := table.close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// freezerTable is a data file with the compressed items one after the other,
+// and an index file with the end offset of every item in the data file
+type freezerTable struct {
+	name   string
+	data   *os.File
+	index  *os.File
+	cipher *encryption.Cipher
+
+	items uint64
+	size  uint64
+}
+
+func openFreezerTable(path, name string, cipher *encryption.Cipher) (*freezerTable, error) {
+	data, err := os.OpenFile(filepath.Join(path, name+".dat"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(path, name+".idx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+
+	t := &freezerTable{
+		name:   name,
+		data:   data,
+		index:  index,
+		cipher: cipher,
+	}
+	if err := t.repair(); err != nil {
+		t.close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// repair drops the items whose index entry or data were not completely written
+func (t *freezerTable) repair() error {
+	indexStat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	dataStat, err := t.data.Stat()
+	if err != nil {
+		return err
+	}
+
+	items := uint64(indexStat.Size()) / freezerIndexSize
+	for items > 0 {
+		end, err := t.offset(items - 1)
+		if err != nil {
+			return err
+		}
+		if end <= uint64(dataStat.Size()) {
+			break
+		}
+		items--
+	}
+
+	t.items = items
+
+	return t.truncate(items)
+}
+
+// offset returns the end offset of the item in the data file
+func (t *freezerTable) offset(item uint64) (uint64, error) {
+	buf := make([]byte, freezerIndexSize)
+	if _, err := t.index.ReadAt(buf, int64(item*freezerIndexSize)); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// truncate drops the items from the given one
+func (t *freezerTable) truncate(items uint64) error {
+	var size uint64
+	if items > 0 {
+		end, err := t.offset(items - 1)
+		if err != nil {
+			return err
+		}
+		size = end
+	}
+
+	if err := t.index.Truncate(int64(items * freezerIndexSize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.items = items
+	t.size = size
+
+	return nil
+}
+
+// itemKey binds the encrypted item to its table and its number
+func (t *freezerTable) itemKey(item uint64) []byte {
+	key := make([]byte, 8, 8+len(t.name))
+	binary.BigEndian.PutUint64(key, item)
+
+	return append(key, t.name...)
+}
+
+func (t *freezerTable) append(item uint64, blob []byte) error {
+	// the empty items are kept empty, so they are known to be missing
+	if len(blob) != 0 {
+		var err error
+		if blob, err = compress(blob); err != nil {
+			return err
+		}
+		if t.cipher != nil {
+			blob = t.cipher.Seal(t.itemKey(item), blob)
+		}
+	}
+
+	if _, err := t.data.WriteAt(blob, int64(t.size)); err != nil {
+		return err
+	}
+
+	end := make([]byte, freezerIndexSize)
+	binary.BigEndian.PutUint64(end, t.size+uint64(len(blob)))
+	if _, err := t.index.WriteAt(end, int64(t.items*freezerIndexSize)); err != nil {
+		return err
+	}
+
+	t.items++
+	t.size += uint64(len(blob))
+
+	return nil
+}
+
+func (t *freezerTable) retrieve(item uint64) ([]byte, bool, error) {
+	var start uint64
+	if item > 0 {
+		var err error
+		if start, err = t.offset(item - 1); err != nil {
+			return nil, false, err
+		}
+	}
+	end, err := t.offset(item)
+	if err != nil {
+		return nil, false, err
+	}
+	if end < start {
+		return nil, false, fmt.Errorf("freezer table %s is corrupted at item %d", t.name, item)
+	}
+	if end == start {
+		return nil, false, nil
+	}
+
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil && err != io.EOF {
+		return nil, false, err
+	}
+
+	if t.cipher != nil {
+		if blob, err = t.cipher.Open(t.itemKey(item), blob); err != nil {
+			return nil, false, err
+		}
+	}
+	if blob, err = decompress(blob); err != nil {
+		return nil, false, err
+	}
+
+	return blob, true, nil
+}
+
+func (t *freezerTable) sync() error {
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+
+	return t.index.Sync()
+}
+
+func (t *freezerTable) close() error {
+	dataErr := t.data.Close()
+	if err := t.index.Close(); err != nil {
+		return err
+	}
+
+	return dataErr
+}
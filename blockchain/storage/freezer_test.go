@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFreezerDir(t *testing.T) string {
+	path, err := ioutil.TempDir("", "freezer")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(path)
+	})
+
+	return path
+}
+
+func TestFreezer(t *testing.T) {
+	key := bytes.Repeat([]byte{0x1}, encryption.KeySize)
+	cipher, err := encryption.NewCipher(key)
+	assert.NoError(t, err)
+
+	for name, cipher := range map[string]*encryption.Cipher{"plain": nil, "encrypted": cipher} {
+		t.Run(name, func(t *testing.T) {
+			path := newTestFreezerDir(t)
+
+			f, err := OpenFreezer(path, cipher)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(0), f.Items())
+
+			assert.NoError(t, f.Append(0, []byte("h0"), nil, nil))
+			assert.NoError(t, f.Append(1, []byte("h1"), []byte("b1"), []byte("r1")))
+			assert.Equal(t, ErrFreezerOutOfOrder, f.Append(3, []byte("h3"), nil, nil))
+			assert.Equal(t, uint64(2), f.Items())
+
+			// the empty items are missing
+			_, ok, err := f.Retrieve(freezerBodies, 0)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			item, ok, err := f.Retrieve(freezerBodies, 1)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("b1"), item)
+
+			_, ok, err = f.Retrieve(freezerHeaders, 2)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.NoError(t, f.Sync())
+			assert.NoError(t, f.Close())
+
+			// the items are kept once reopened
+			f, err = OpenFreezer(path, cipher)
+			assert.NoError(t, err)
+			defer f.Close()
+
+			assert.Equal(t, uint64(2), f.Items())
+			item, ok, err = f.Retrieve(freezerReceipts, 1)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("r1"), item)
+		})
+	}
+}
+
+func TestFreezer_Repair(t *testing.T) {
+	path := newTestFreezerDir(t)
+
+	f, err := OpenFreezer(path, nil)
+	assert.NoError(t, err)
+	for i := uint64(0); i < 3; i++ {
+		assert.NoError(t, f.Append(i, []byte{byte(i), 0x1}, []byte{byte(i), 0x2}, []byte{byte(i), 0x3}))
+	}
+	assert.NoError(t, f.Close())
+
+	// an interrupted freeze wrote the last body partially
+	data := filepath.Join(path, freezerBodies+".dat")
+	stat, err := os.Stat(data)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(data, stat.Size()-1))
+
+	f, err = OpenFreezer(path, nil)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	// the last block is dropped from every table
+	assert.Equal(t, uint64(2), f.Items())
+	_, ok, err := f.Retrieve(freezerHeaders, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	item, ok, err := f.Retrieve(freezerHeaders, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x1, 0x1}, item)
+
+	assert.NoError(t, f.Append(2, []byte{0x2, 0x1}, nil, nil))
+}
+
+func TestKeyValueStorage_Freeze(t *testing.T) {
+	freezer, err := OpenFreezer(newTestFreezerDir(t), nil)
+	assert.NoError(t, err)
+
+	db := kvdb.NewMemoryDatabase()
+	s, err := NewDatabaseStorage(db, freezer, nil, hclog.NewNullLogger())
+	assert.NoError(t, err)
+	defer s.Close()
+
+	headers := []*types.Header{}
+	for i := uint64(0); i < 4; i++ {
+		h := &types.Header{
+			Number:    i,
+			ExtraData: []byte{},
+		}
+		h.ComputeHash()
+		headers = append(headers, h)
+
+		assert.NoError(t, s.WriteCanonicalHeader(h, big.NewInt(int64(i))))
+
+		// the genesis has no body nor receipts
+		if i == 0 {
+			continue
+		}
+		txn := &types.Transaction{
+			Nonce:    i,
+			GasPrice: big.NewInt(1),
+			V:        []byte{0x1},
+		}
+		txn.ComputeHash()
+		assert.NoError(t, s.WriteBody(h.Hash, &types.Body{Transactions: []*types.Transaction{txn}}))
+		assert.NoError(t, s.WriteReceipts(h.Hash, []*types.Receipt{{TxHash: txn.Hash, CumulativeGasUsed: i}}))
+	}
+
+	assert.NoError(t, s.Freeze(3))
+	assert.Equal(t, uint64(3), s.Frozen())
+
+	// freezing the frozen blocks again is a no-op
+	assert.NoError(t, s.Freeze(2))
+	assert.Equal(t, uint64(3), s.Frozen())
+
+	for i, h := range headers {
+		_, inDB, err := db.Get(append(append([]byte{}, HEADER...), h.Hash.Bytes()...))
+		assert.NoError(t, err)
+		assert.Equal(t, i == 3, inDB)
+
+		header, err := s.ReadHeader(h.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, h.Number, header.Number)
+
+		if i == 0 {
+			_, err := s.ReadBody(h.Hash)
+			assert.Error(t, err)
+			continue
+		}
+
+		body, err := s.ReadBody(h.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(i), body.Transactions[0].Nonce)
+
+		receipts, err := s.ReadReceipts(h.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(i), receipts[0].CumulativeGasUsed)
+	}
+
+	// the storage without freezer keeps everything
+	plain, err := NewDatabaseStorage(kvdb.NewMemoryDatabase(), nil, nil, hclog.NewNullLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, ErrNoFreezer, plain.Freeze(1))
+	assert.Equal(t, uint64(0), plain.Frozen())
+}
+
+func TestFreezer_ExportFiles(t *testing.T) {
+	f, err := OpenFreezer(newTestFreezerDir(t), nil)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	for n := uint64(0); n < 3; n++ {
+		assert.NoError(t, f.Append(n, []byte{byte(n)}, []byte("body"), nil))
+	}
+
+	// the files of the first items are exported, they open as a freezer with those items
+	exported := newTestFreezerDir(t)
+	err = f.ExportFiles(2, func(name string, size int64, r io.Reader) error {
+		blob, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Len(t, blob, int(size))
+
+		return ioutil.WriteFile(filepath.Join(exported, name), blob, 0644)
+	})
+	assert.NoError(t, err)
+
+	g, err := OpenFreezer(exported, nil)
+	assert.NoError(t, err)
+	defer g.Close()
+
+	assert.Equal(t, uint64(2), g.Items())
+
+	item, ok, err := g.Retrieve(freezerHeaders, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1}, item)
+
+	assert.Error(t, f.ExportFiles(4, func(string, int64, io.Reader) error {
+		return nil
+	}))
+}
@@ -38,6 +38,30 @@ var (
 
 	// TX_LOOKUP_PREFIX is the prefix for transaction lookups
 	TX_LOOKUP_PREFIX = []byte("l")
+
+	// BLOOM_BITS is the prefix for the bloom bits of the log index
+	BLOOM_BITS = []byte("i")
+
+	// BLOOM_SECTION is the prefix for the head hashes of the indexed bloom sections
+	BLOOM_SECTION = []byte("e")
+
+	// STATE_ROOT is the prefix for the index of the blocks whose state was stored
+	STATE_ROOT = []byte("t")
+
+	// FROZEN is the prefix for the numbers of the blocks moved to the freezer
+	FROZEN = []byte("z")
+
+	// BAD_BLOCK is the prefix for the blocks that failed their verification or their execution
+	BAD_BLOCK = []byte("x")
+
+	// ADDRESS_TX is the prefix for the transactions of the addresses, by position
+	ADDRESS_TX = []byte("a")
+
+	// ADDRESS_TX_COUNT is the prefix for the number of indexed transactions of the addresses
+	ADDRESS_TX_COUNT = []byte("n")
+
+	// ADDRESS_INDEX is the prefix for the first block of the index of the transactions by address
+	ADDRESS_INDEX = []byte("g")
 )
 
 // Sub-prefixes
@@ -54,6 +78,7 @@ type KV interface {
 	Close() error
 	Set(p []byte, v []byte) error
 	Get(p []byte) ([]byte, bool, error)
+	Delete(p []byte) error
 }
 
 // KeyValueStorage is a generic storage for kv databases
@@ -61,12 +86,20 @@ type KeyValueStorage struct {
 	logger hclog.Logger
 	db     KV
 	Db     KV
+
+	// freezer holds the old blocks, if it is enabled
+	freezer *Freezer
 }
 
 func NewKeyValueStorage(logger hclog.Logger, db KV) Storage {
 	return &KeyValueStorage{logger: logger, db: db}
 }
 
+// NewFreezerKeyValueStorage creates a storage whose old blocks can be moved to the freezer
+func NewFreezerKeyValueStorage(logger hclog.Logger, db KV, freezer *Freezer) Storage {
+	return &KeyValueStorage{logger: logger, db: db, freezer: freezer}
+}
+
 func (s *KeyValueStorage) encodeUint(n uint64) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b[:], n)
@@ -262,6 +295,264 @@ func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
 	return types.BytesToHash(blockHash), true
 }
 
+// BLOOM BITS //
+
+// bloomBitsKey returns the key of a bloom bit vector. The key includes the head of the section,
+// so the vectors of a section that was reorganized are never read back
+func (s *KeyValueStorage) bloomBitsKey(bit uint, section uint64, head types.Hash) []byte {
+	key := make([]byte, 2, 2+8+types.HashLength)
+	binary.BigEndian.PutUint16(key, uint16(bit))
+	key = append(key, s.encodeUint(section)...)
+
+	return append(key, head.Bytes()...)
+}
+
+// WriteBloomBits writes the bit vector of the bloom bit for the blocks of the section
+func (s *KeyValueStorage) WriteBloomBits(bit uint, section uint64, head types.Hash, bits []byte) error {
+	return s.set(BLOOM_BITS, s.bloomBitsKey(bit, section, head), bits)
+}
+
+// ReadBloomBits reads the bit vector of the bloom bit for the blocks of the section
+func (s *KeyValueStorage) ReadBloomBits(bit uint, section uint64, head types.Hash) ([]byte, bool) {
+	return s.get(BLOOM_BITS, s.bloomBitsKey(bit, section, head))
+}
+
+// WriteBloomSection writes the hash of the last block of an indexed bloom section
+func (s *KeyValueStorage) WriteBloomSection(section uint64, head types.Hash) error {
+	return s.set(BLOOM_SECTION, s.encodeUint(section), head.Bytes())
+}
+
+// ReadBloomSection reads the hash of the last block of an indexed bloom section
+func (s *KeyValueStorage) ReadBloomSection(section uint64) (types.Hash, bool) {
+	data, ok := s.get(BLOOM_SECTION, s.encodeUint(section))
+	if !ok {
+		return types.Hash{}, false
+	}
+	return types.BytesToHash(data), true
+}
+
+// STATE ROOTS //
+
+// stateRootKey returns the key of the state root of a block, ordered by the block number
+func (s *KeyValueStorage) stateRootKey(n uint64, hash types.Hash) []byte {
+	return append(s.encodeUint(n), hash.Bytes()...)
+}
+
+// WriteStateRoot indexes the state root of a block whose state was stored
+func (s *KeyValueStorage) WriteStateRoot(n uint64, hash types.Hash, root types.Hash) error {
+	return s.set(STATE_ROOT, s.stateRootKey(n, hash), root.Bytes())
+}
+
+// ReadStateRoot reads the state root of a block, if its state was stored
+func (s *KeyValueStorage) ReadStateRoot(n uint64, hash types.Hash) (types.Hash, bool) {
+	data, ok := s.get(STATE_ROOT, s.stateRootKey(n, hash))
+	if !ok {
+		return types.Hash{}, false
+	}
+	return types.BytesToHash(data), true
+}
+
+// ADDRESS TRANSACTIONS //
+
+// addressTxKey returns the key of the transaction of the address at the position
+func (s *KeyValueStorage) addressTxKey(addr types.Address, index uint64) []byte {
+	return append(addr.Bytes(), s.encodeUint(index)...)
+}
+
+// WriteAddressTx writes the transaction of the address at the position
+func (s *KeyValueStorage) WriteAddressTx(addr types.Address, index uint64, tx *AddressTx) error {
+	return s.set(ADDRESS_TX, s.addressTxKey(addr, index), append(tx.BlockHash.Bytes(), tx.TxHash.Bytes()...))
+}
+
+// ReadAddressTx reads the transaction of the address at the position
+func (s *KeyValueStorage) ReadAddressTx(addr types.Address, index uint64) (*AddressTx, bool) {
+	data, ok := s.get(ADDRESS_TX, s.addressTxKey(addr, index))
+	if !ok || len(data) != 2*types.HashLength {
+		return nil, false
+	}
+	return &AddressTx{
+		BlockHash: types.BytesToHash(data[:types.HashLength]),
+		TxHash:    types.BytesToHash(data[types.HashLength:]),
+	}, true
+}
+
+// WriteAddressTxCount writes the number of indexed transactions of the address
+func (s *KeyValueStorage) WriteAddressTxCount(addr types.Address, count uint64) error {
+	return s.set(ADDRESS_TX_COUNT, addr.Bytes(), s.encodeUint(count))
+}
+
+// ReadAddressTxCount reads the number of indexed transactions of the address
+func (s *KeyValueStorage) ReadAddressTxCount(addr types.Address) uint64 {
+	data, ok := s.get(ADDRESS_TX_COUNT, addr.Bytes())
+	if !ok || len(data) != 8 {
+		return 0
+	}
+	return s.decodeUint(data)
+}
+
+// WriteAddressIndexStart writes the number of the first block of the index of the transactions by address
+func (s *KeyValueStorage) WriteAddressIndexStart(n uint64) error {
+	return s.set(ADDRESS_INDEX, NUMBER, s.encodeUint(n))
+}
+
+// ReadAddressIndexStart reads the number of the first block of the index of the transactions by address
+func (s *KeyValueStorage) ReadAddressIndexStart() (uint64, bool) {
+	data, ok := s.get(ADDRESS_INDEX, NUMBER)
+	if !ok || len(data) != 8 {
+		return 0, false
+	}
+	return s.decodeUint(data), true
+}
+
+// BAD BLOCKS //
+
+// MaxBadBlocks is the number of bad blocks kept, the oldest ones are dropped first
+const MaxBadBlocks = 16
+
+// WriteBadBlock records the bad block, a block already recorded is moved to the front
+func (s *KeyValueStorage) WriteBadBlock(bad *BadBlock) error {
+	hashes := Forks{}
+	if err := s.readRLP(BAD_BLOCK, EMPTY, &hashes); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	hash := bad.Block.Hash()
+	if err := s.writeRLP(BAD_BLOCK, hash.Bytes(), bad); err != nil {
+		return err
+	}
+
+	// the index lists the bad blocks from the most recent one
+	index := Forks{hash}
+	for _, h := range hashes {
+		if h == hash {
+			continue
+		}
+		if len(index) == MaxBadBlocks {
+			if err := s.db.Delete(append(append([]byte{}, BAD_BLOCK...), h.Bytes()...)); err != nil {
+				return err
+			}
+			continue
+		}
+		index = append(index, h)
+	}
+
+	return s.writeRLP(BAD_BLOCK, EMPTY, &index)
+}
+
+// ReadBadBlocks returns the recorded bad blocks, the most recent first
+func (s *KeyValueStorage) ReadBadBlocks() ([]*BadBlock, error) {
+	hashes := Forks{}
+	if err := s.readRLP(BAD_BLOCK, EMPTY, &hashes); err != nil {
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	badBlocks := make([]*BadBlock, 0, len(hashes))
+	for _, hash := range hashes {
+		bad := &BadBlock{}
+		if err := s.readRLP(BAD_BLOCK, hash.Bytes(), bad); err != nil {
+			return nil, err
+		}
+
+		// the hash of the consensus is kept, the block may be decoded without it
+		bad.Block.Header.Hash = hash
+
+		badBlocks = append(badBlocks, bad)
+	}
+
+	return badBlocks, nil
+}
+
+// FREEZER //
+
+// freezerTables are the freezer tables of the prefixes of the block data
+var freezerTables = map[string]string{
+	string(HEADER):   freezerHeaders,
+	string(BODY):     freezerBodies,
+	string(RECEIPTS): freezerReceipts,
+}
+
+// readData reads the entry of the key, the block data falls back to the freezer once the block is frozen
+func (s *KeyValueStorage) readData(p, k []byte) ([]byte, bool, error) {
+	data, ok, err := s.db.Get(append(append([]byte{}, p...), k...))
+	if err != nil || ok || s.freezer == nil {
+		return data, ok, err
+	}
+
+	table, frozen := freezerTables[string(p)]
+	if !frozen {
+		return nil, false, nil
+	}
+	number, ok := s.get(FROZEN, k)
+	if !ok {
+		return nil, false, nil
+	}
+
+	return s.freezer.Retrieve(table, s.decodeUint(number))
+}
+
+// Frozen returns the number of blocks moved to the freezer, from genesis
+func (s *KeyValueStorage) Frozen() uint64 {
+	if s.freezer == nil {
+		return 0
+	}
+	return s.freezer.Items()
+}
+
+// Freeze moves the canonical blocks below the limit that are not frozen yet to the freezer.
+// The blocks must be final, the frozen blocks are never reorganized
+func (s *KeyValueStorage) Freeze(limit uint64) error {
+	if s.freezer == nil {
+		return ErrNoFreezer
+	}
+
+	first := s.freezer.Items()
+	if limit <= first {
+		return nil
+	}
+
+	hashes := make([]types.Hash, 0, limit-first)
+	for n := first; n < limit; n++ {
+		hash, ok := s.ReadCanonicalHash(n)
+		if !ok {
+			return fmt.Errorf("canonical hash of block %d not found", n)
+		}
+		header, ok := s.get(HEADER, hash.Bytes())
+		if !ok {
+			return fmt.Errorf("header of block %d not found", n)
+		}
+
+		// the blocks below the pivot of a snapshot sync have no body nor receipts
+		body, _ := s.get(BODY, hash.Bytes())
+		receipts, _ := s.get(RECEIPTS, hash.Bytes())
+
+		if err := s.freezer.Append(n, header, body, receipts); err != nil {
+			return err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	// the blocks are only deleted from the key-value store once they are on the disk
+	if err := s.freezer.Sync(); err != nil {
+		return err
+	}
+
+	for i, hash := range hashes {
+		if err := s.set(FROZEN, hash.Bytes(), s.encodeUint(first+uint64(i))); err != nil {
+			return err
+		}
+		for _, p := range [][]byte{HEADER, BODY, RECEIPTS} {
+			if err := s.db.Delete(append(append([]byte{}, p...), hash.Bytes()...)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // WRITE OPERATIONS //
 
 func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
@@ -277,8 +568,7 @@ func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
 var ErrNotFound = fmt.Errorf("not found")
 
 func (s *KeyValueStorage) readRLP(p, k []byte, raw types.RLPUnmarshaler) error {
-	p = append(p, k...)
-	data, ok, err := s.db.Get(p)
+	data, ok, err := s.readData(p, k)
 	if err != nil {
 		return err
 	}
@@ -332,7 +622,12 @@ func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
 	return data, ok
 }
 
-// Close closes the connection with the db
+// Close closes the connection with the db, and the freezer
 func (s *KeyValueStorage) Close() error {
+	if s.freezer != nil {
+		if err := s.freezer.Close(); err != nil {
+			s.logger.Error("failed to close the freezer", "err", err)
+		}
+	}
 	return s.db.Close()
 }
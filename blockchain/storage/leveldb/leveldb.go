@@ -4,8 +4,9 @@ import (
 	"fmt"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
 	"github.com/hashicorp/go-hclog"
-	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // Factory creates a leveldb storage
@@ -23,40 +24,22 @@ func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storag
 
 // NewLevelDBStorage creates the new storage reference with leveldb
 func NewLevelDBStorage(path string, logger hclog.Logger) (storage.Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	return NewEncryptedLevelDBStorage(path, nil, logger)
+}
+
+// NewEncryptedLevelDBStorage creates the new storage reference with leveldb,
+// the values are encrypted with the cipher if it is not nil
+func NewEncryptedLevelDBStorage(path string, cipher *encryption.Cipher, logger hclog.Logger) (storage.Storage, error) {
+	db, err := kvdb.OpenLevelDB(path)
 	if err != nil {
 		return nil, err
 	}
 
-	kv := &levelDBKV{db}
-	return storage.NewKeyValueStorage(logger.Named("leveldb"), kv), nil
-}
-
-// levelDBKV is the leveldb implementation of the kv storage
-type levelDBKV struct {
-	db *leveldb.DB
-}
-
-// Set sets the key-value pair in leveldb storage
-func (l *levelDBKV) Set(p []byte, v []byte) error {
-	return l.db.Put(p, v, nil)
-}
-
-// Get retrieves the key-value pair in leveldb storage
-func (l *levelDBKV) Get(p []byte) ([]byte, bool, error) {
-	data, err := l.db.Get(p, nil)
+	s, err := storage.NewDatabaseStorage(db, nil, cipher, logger.Named("leveldb"))
 	if err != nil {
-		if err.Error() == "leveldb: not found" {
-			return nil, false, nil
-		}
-
-		return nil, false, err
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
 	}
 
-	return data, true, nil
-}
-
-// Close closes the leveldb storage instance
-func (l *levelDBKV) Close() error {
-	return l.db.Close()
+	return s, nil
 }
@@ -6,15 +6,20 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
 	"github.com/hashicorp/go-hclog"
 )
 
 func newStorage(t *testing.T) (storage.Storage, func()) {
+	return newEncryptedStorage(t, nil)
+}
+
+func newEncryptedStorage(t *testing.T, cipher *encryption.Cipher) (storage.Storage, func()) {
 	path, err := ioutil.TempDir("/tmp", "minimal_storage")
 	if err != nil {
 		t.Fatal(err)
 	}
-	s, err := NewLevelDBStorage(path, hclog.NewNullLogger())
+	s, err := NewEncryptedLevelDBStorage(path, cipher, hclog.NewNullLogger())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -32,3 +37,14 @@ func newStorage(t *testing.T) (storage.Storage, func()) {
 func TestStorage(t *testing.T) {
 	storage.TestStorage(t, newStorage)
 }
+
+func TestEncryptedStorage(t *testing.T) {
+	cipher, err := encryption.NewCipher(make([]byte, encryption.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage.TestStorage(t, func(t *testing.T) (storage.Storage, func()) {
+		return newEncryptedStorage(t, cipher)
+	})
+}
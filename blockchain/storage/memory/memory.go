@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"sync"
+
 	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
 	"github.com/hashicorp/go-hclog"
@@ -8,21 +10,28 @@ import (
 
 // NewMemoryStorage creates the new storage reference with inmemory
 func NewMemoryStorage(logger hclog.Logger) (storage.Storage, error) {
-	db := &memoryKV{map[string][]byte{}}
+	db := &memoryKV{db: map[string][]byte{}}
 	return storage.NewKeyValueStorage(logger, db), nil
 }
 
 // memoryKV is an in memory implementation of the kv storage
 type memoryKV struct {
-	db map[string][]byte
+	lock sync.RWMutex
+	db   map[string][]byte
 }
 
 func (m *memoryKV) Set(p []byte, v []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.db[hex.EncodeToHex(p)] = v
 	return nil
 }
 
 func (m *memoryKV) Get(p []byte) ([]byte, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	v, ok := m.db[hex.EncodeToHex(p)]
 	if !ok {
 		return nil, false, nil
@@ -30,6 +39,14 @@ func (m *memoryKV) Get(p []byte) ([]byte, bool, error) {
 	return v, true, nil
 }
 
+func (m *memoryKV) Delete(p []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.db, hex.EncodeToHex(p))
+	return nil
+}
+
 func (m *memoryKV) Close() error {
 	return nil
 }
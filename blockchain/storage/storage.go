@@ -40,6 +40,29 @@ type Storage interface {
 	WriteTxLookup(hash types.Hash, blockHash types.Hash) error
 	ReadTxLookup(hash types.Hash) (types.Hash, bool)
 
+	WriteBloomBits(bit uint, section uint64, head types.Hash, bits []byte) error
+	ReadBloomBits(bit uint, section uint64, head types.Hash) ([]byte, bool)
+
+	WriteBloomSection(section uint64, head types.Hash) error
+	ReadBloomSection(section uint64) (types.Hash, bool)
+
+	WriteStateRoot(n uint64, hash types.Hash, root types.Hash) error
+	ReadStateRoot(n uint64, hash types.Hash) (types.Hash, bool)
+
+	WriteAddressTx(addr types.Address, index uint64, tx *AddressTx) error
+	ReadAddressTx(addr types.Address, index uint64) (*AddressTx, bool)
+	WriteAddressTxCount(addr types.Address, count uint64) error
+	ReadAddressTxCount(addr types.Address) uint64
+
+	WriteAddressIndexStart(n uint64) error
+	ReadAddressIndexStart() (uint64, bool)
+
+	WriteBadBlock(bad *BadBlock) error
+	ReadBadBlocks() ([]*BadBlock, error)
+
+	Frozen() uint64
+	Freeze(limit uint64) error
+
 	Close() error
 }
 
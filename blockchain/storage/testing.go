@@ -49,6 +49,18 @@ func TestStorage(t *testing.T, m MockStorage) {
 	t.Run("", func(t *testing.T) {
 		testReceipts(t, m)
 	})
+	t.Run("", func(t *testing.T) {
+		testBloomBits(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testStateRoot(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testAddressTxs(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testBadBlocks(t, m)
+	})
 }
 
 func testCanonicalChain(t *testing.T, m MockStorage) {
@@ -415,3 +427,126 @@ func testWriteCanonicalHeader(t *testing.T, m MockStorage) {
 		t.Fatal("canonical hash not correct")
 	}
 }
+
+func testBloomBits(t *testing.T, m MockStorage) {
+	s, close := m(t)
+	defer close()
+
+	bits := []byte{0x1, 0x2, 0x3}
+	assert.NoError(t, s.WriteBloomBits(2047, 3, hash1, bits))
+
+	data, ok := s.ReadBloomBits(2047, 3, hash1)
+	assert.True(t, ok)
+	assert.Equal(t, bits, data)
+
+	// the vectors are bound to the bit, the section and the section head
+	_, ok = s.ReadBloomBits(2046, 3, hash1)
+	assert.False(t, ok)
+	_, ok = s.ReadBloomBits(2047, 4, hash1)
+	assert.False(t, ok)
+	_, ok = s.ReadBloomBits(2047, 3, hash2)
+	assert.False(t, ok)
+
+	_, ok = s.ReadBloomSection(3)
+	assert.False(t, ok)
+
+	assert.NoError(t, s.WriteBloomSection(3, hash2))
+
+	head, ok := s.ReadBloomSection(3)
+	assert.True(t, ok)
+	assert.Equal(t, hash2, head)
+}
+
+func testStateRoot(t *testing.T, m MockStorage) {
+	s, close := m(t)
+	defer close()
+
+	root := types.StringToHash("3")
+	assert.NoError(t, s.WriteStateRoot(10, hash1, root))
+
+	data, ok := s.ReadStateRoot(10, hash1)
+	assert.True(t, ok)
+	assert.Equal(t, root, data)
+
+	// the roots are bound to the block, not only to its number
+	_, ok = s.ReadStateRoot(10, hash2)
+	assert.False(t, ok)
+	_, ok = s.ReadStateRoot(11, hash1)
+	assert.False(t, ok)
+}
+
+func testAddressTxs(t *testing.T, m MockStorage) {
+	s, close := m(t)
+	defer close()
+
+	assert.Equal(t, uint64(0), s.ReadAddressTxCount(addr1))
+	_, ok := s.ReadAddressIndexStart()
+	assert.False(t, ok)
+
+	tx := &AddressTx{BlockHash: hash1, TxHash: hash2}
+	assert.NoError(t, s.WriteAddressTx(addr1, 3, tx))
+	assert.NoError(t, s.WriteAddressTxCount(addr1, 4))
+	assert.NoError(t, s.WriteAddressIndexStart(10))
+
+	found, ok := s.ReadAddressTx(addr1, 3)
+	assert.True(t, ok)
+	assert.Equal(t, tx, found)
+	assert.Equal(t, uint64(4), s.ReadAddressTxCount(addr1))
+
+	start, ok := s.ReadAddressIndexStart()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), start)
+
+	// the transactions are bound to the address and the position
+	_, ok = s.ReadAddressTx(addr2, 3)
+	assert.False(t, ok)
+	_, ok = s.ReadAddressTx(addr1, 2)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), s.ReadAddressTxCount(addr2))
+}
+
+func testBadBlocks(t *testing.T, m MockStorage) {
+	s, close := m(t)
+	defer close()
+
+	badBlocks, err := s.ReadBadBlocks()
+	assert.NoError(t, err)
+	assert.Empty(t, badBlocks)
+
+	newBadBlock := func(number uint64) *BadBlock {
+		header := &types.Header{Number: number}
+		header.ComputeHash()
+
+		return &BadBlock{
+			Block:  &types.Block{Header: header, Transactions: []*types.Transaction{}, Uncles: []*types.Header{}},
+			Reason: fmt.Sprintf("bad block %d", number),
+			Trace:  []byte("[]"),
+			Time:   1000 + number,
+		}
+	}
+
+	for i := uint64(0); i < MaxBadBlocks+2; i++ {
+		assert.NoError(t, s.WriteBadBlock(newBadBlock(i)))
+	}
+
+	// the oldest bad blocks are dropped
+	badBlocks, err = s.ReadBadBlocks()
+	assert.NoError(t, err)
+	assert.Len(t, badBlocks, MaxBadBlocks)
+	assert.Equal(t, uint64(MaxBadBlocks+1), badBlocks[0].Block.Number())
+	assert.Equal(t, uint64(2), badBlocks[MaxBadBlocks-1].Block.Number())
+
+	expected := newBadBlock(MaxBadBlocks + 1)
+	assert.Equal(t, expected.Block.Hash(), badBlocks[0].Block.Hash())
+	assert.Equal(t, expected.Reason, badBlocks[0].Reason)
+	assert.Equal(t, expected.Trace, badBlocks[0].Trace)
+	assert.Equal(t, expected.Time, badBlocks[0].Time)
+
+	// a bad block recorded again moves to the front
+	assert.NoError(t, s.WriteBadBlock(newBadBlock(5)))
+
+	badBlocks, err = s.ReadBadBlocks()
+	assert.NoError(t, err)
+	assert.Len(t, badBlocks, MaxBadBlocks)
+	assert.Equal(t, uint64(5), badBlocks[0].Block.Number())
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/umbracle/fastrlp"
 )
@@ -52,3 +54,76 @@ func (f *Forks) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 
 	return nil
 }
+
+// AddressTx is a transaction in the index of the transactions by address
+type AddressTx struct {
+	BlockHash types.Hash
+	TxHash    types.Hash
+}
+
+// BadBlock is a block that failed its verification or its execution
+type BadBlock struct {
+	Block *types.Block
+
+	// Reason is the error the block failed with
+	Reason string
+
+	// Trace is the JSON encoded call trace of the transactions of the block, if it was executed
+	Trace []byte
+
+	// Time is the unix time the block was rejected at
+	Time uint64
+}
+
+// MarshalRLPTo is a wrapper function for calling the type marshal implementation
+func (b *BadBlock) MarshalRLPTo(dst []byte) []byte {
+	return types.MarshalRLPTo(b.MarshalRLPWith, dst)
+}
+
+// MarshalRLPWith is the actual RLP marshal implementation for the type
+func (b *BadBlock) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
+	vv := ar.NewArray()
+	vv.Set(b.Block.MarshalRLPWith(ar))
+	vv.Set(ar.NewString(b.Reason))
+	vv.Set(ar.NewCopyBytes(b.Trace))
+	vv.Set(ar.NewUint(b.Time))
+
+	return vv
+}
+
+// UnmarshalRLP is a wrapper function for calling the type unmarshal implementation
+func (b *BadBlock) UnmarshalRLP(input []byte) error {
+	return types.UnmarshalRlp(b.UnmarshalRLPFrom, input)
+}
+
+// UnmarshalRLPFrom is the actual RLP unmarshal implementation for the type
+func (b *BadBlock) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if num := len(elems); num != 4 {
+		return fmt.Errorf("not enough elements to decode bad block, expected 4 but found %d", num)
+	}
+
+	b.Block = &types.Block{}
+	if err := b.Block.UnmarshalRLPFrom(p, elems[0]); err != nil {
+		return err
+	}
+
+	reason, err := elems[1].GetString()
+	if err != nil {
+		return err
+	}
+	b.Reason = reason
+
+	if b.Trace, err = elems[2].GetBytes(b.Trace[:0]); err != nil {
+		return err
+	}
+
+	if b.Time, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,436 @@
+package bridge
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/go-web3"
+	"github.com/umbracle/go-web3/abi"
+	"github.com/umbracle/go-web3/jsonrpc"
+)
+
+const (
+	// DefaultConfirmations is the default number of root chain blocks a deposit waits for before it is relayed
+	DefaultConfirmations = 6
+
+	// StateSyncGas is the gas limit of the state sync transactions
+	StateSyncGas = 1000000
+
+	// pollInterval is the interval the root chain is checked for new deposits at
+	pollInterval = 5 * time.Second
+
+	// maxBlockRange is the maximum number of root chain blocks queried for deposits at once
+	maxBlockRange = 1000
+)
+
+var (
+	errNoExit  = errors.New("the log is not a message sent by the exit contract")
+	errPending = errors.New("the transaction is not included in a block")
+)
+
+// keys of the progress of the relayer in its database
+var (
+	cursorKey = []byte("cursor")
+	lastIDKey = []byte("last-id")
+)
+
+var (
+	stateSyncedEvent = abis.StateSenderABI.Events["StateSynced"]
+	onStateReceive   = abis.StateReceiverABI.Methods["onStateReceive"]
+	messageSentEvent = abis.ExitSenderABI.Events["MessageSent"]
+)
+
+// Config is the bridge between the root chain and this chain
+type Config struct {
+	// URL is the JSON-RPC endpoint of the root chain
+	URL string
+
+	// RootContract is the contract of the root chain emitting the StateSynced events of the deposits
+	RootContract types.Address
+
+	// StateReceiver is the contract of this chain the deposits are relayed to, with onStateReceive.
+	// Every validator relays every deposit, the contract executes it once
+	StateReceiver types.Address
+
+	// ExitContract is the contract of this chain emitting the MessageSent events of the withdrawals
+	ExitContract types.Address
+
+	// Confirmations is the number of root chain blocks a deposit waits for before it is relayed
+	Confirmations uint64
+
+	// StartBlock is the root chain block the deposits are watched from on the first run
+	StartBlock uint64
+}
+
+// Backend is the chain the deposits are relayed to, and the withdrawals are proven from
+type Backend interface {
+	Header() *types.Header
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
+	ReadTxLookup(hash types.Hash) (types.Hash, bool)
+
+	// GetNonce returns the next nonce of the account, the pending transactions included
+	GetNonce(addr types.Address) uint64
+
+	// AddTx adds the transaction to the pool
+	AddTx(tx *types.Transaction) error
+}
+
+// StateSync is a deposit of the root chain, relayed to the state receiver
+type StateSync struct {
+	ID       uint64
+	Contract types.Address
+	Data     []byte
+}
+
+// ExitProof proves a withdrawal to the root chain: the receipt with the MessageSent log is in the
+// receipts trie of the block, whose header the root chain verifies against the checkpoints
+type ExitProof struct {
+	BlockNumber  uint64
+	BlockHash    types.Hash
+	Header       []byte
+	Receipt      []byte
+	ReceiptIndex uint64
+	LogIndex     uint64
+	Proof        [][]byte
+}
+
+// Bridge relays the deposits of the root chain to this chain with state sync transactions signed by
+// the validator, and builds the proofs of the withdrawals of this chain to the root chain
+type Bridge struct {
+	logger  hclog.Logger
+	config  *Config
+	backend Backend
+	db      kvdb.Database
+	key     *ecdsa.PrivateKey
+	signer  crypto.TxSigner
+	client  *jsonrpc.Client
+
+	// cursor is the last root chain block processed, lastID the id of the last deposit relayed.
+	// The ids of the deposits start at 1
+	lock   sync.Mutex
+	cursor uint64
+	lastID uint64
+
+	started bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewBridge returns the bridge, the relaying of the deposits starts with Start.
+// The progress of the relayer is kept in the database
+func NewBridge(
+	logger hclog.Logger,
+	config *Config,
+	backend Backend,
+	db kvdb.Database,
+	key *ecdsa.PrivateKey,
+	signer crypto.TxSigner,
+) (*Bridge, error) {
+	client, err := jsonrpc.NewClient(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the root chain: %v", err)
+	}
+
+	b := &Bridge{
+		logger:  logger.Named("bridge"),
+		config:  config,
+		backend: backend,
+		db:      db,
+		key:     key,
+		signer:  signer,
+		client:  client,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	if config.StartBlock != 0 {
+		b.cursor = config.StartBlock - 1
+	}
+	if b.cursor, err = b.readUint64(cursorKey, b.cursor); err != nil {
+		return nil, err
+	}
+	if b.lastID, err = b.readUint64(lastIDKey, 0); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Address returns the account sending the state sync transactions
+func (b *Bridge) Address() types.Address {
+	return crypto.PubKeyToAddress(&b.key.PublicKey)
+}
+
+// Start starts relaying the deposits of the root chain
+func (b *Bridge) Start() {
+	b.logger.Info("relaying the deposits",
+		"root", b.config.RootContract,
+		"receiver", b.config.StateReceiver,
+		"sender", b.Address(),
+		"from", b.cursor+1,
+	)
+	b.started = true
+
+	go b.run()
+}
+
+// Close stops the relaying of the deposits, if started, and closes the database
+func (b *Bridge) Close() error {
+	close(b.closeCh)
+
+	if b.started {
+		<-b.doneCh
+	}
+
+	b.client.Close()
+
+	return b.db.Close()
+}
+
+func (b *Bridge) run() {
+	defer close(b.doneCh)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.relay(); err != nil {
+			b.logger.Error("failed to relay the deposits", "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-b.closeCh:
+			return
+		}
+	}
+}
+
+// relay relays the deposits of the confirmed root chain blocks after the cursor
+func (b *Bridge) relay() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var head string
+	if err := b.client.Call("eth_blockNumber", &head); err != nil {
+		return err
+	}
+	headNum, err := types.ParseUint64orHex(&head)
+	if err != nil {
+		return err
+	}
+	if headNum < b.config.Confirmations {
+		return nil
+	}
+
+	from, to := b.cursor+1, headNum-b.config.Confirmations
+	if from > to {
+		return nil
+	}
+	if to-from >= maxBlockRange {
+		to = from + maxBlockRange - 1
+	}
+
+	syncs, err := b.getStateSyncs(from, to)
+	if err != nil {
+		return err
+	}
+	for _, sync := range syncs {
+		// the deposits of a partially relayed range were relayed already
+		if sync.ID <= b.lastID {
+			continue
+		}
+		if err := b.relayStateSync(sync); err != nil {
+			return fmt.Errorf("failed to relay the deposit %d: %v", sync.ID, err)
+		}
+		if err := b.writeUint64(lastIDKey, sync.ID); err != nil {
+			return err
+		}
+		b.lastID = sync.ID
+	}
+
+	if err := b.writeUint64(cursorKey, to); err != nil {
+		return err
+	}
+	b.cursor = to
+
+	return nil
+}
+
+// getStateSyncs returns the deposits of the root chain blocks, sorted by id
+func (b *Bridge) getStateSyncs(from, to uint64) ([]*StateSync, error) {
+	filter := map[string]interface{}{
+		"fromBlock": fmt.Sprintf("0x%x", from),
+		"toBlock":   fmt.Sprintf("0x%x", to),
+		"address":   b.config.RootContract.String(),
+		"topics":    []string{stateSyncedEvent.ID().String()},
+	}
+
+	var logs []*web3.Log
+	if err := b.client.Call("eth_getLogs", &logs, filter); err != nil {
+		return nil, fmt.Errorf("failed to get the deposits of blocks %d-%d: %v", from, to, err)
+	}
+
+	syncs := make([]*StateSync, 0, len(logs))
+	for _, log := range logs {
+		sync, err := decodeStateSynced(log)
+		if err != nil {
+			return nil, err
+		}
+		syncs = append(syncs, sync)
+	}
+
+	return syncs, nil
+}
+
+// relayStateSync adds the signed state sync transaction of the deposit to the pool
+func (b *Bridge) relayStateSync(sync *StateSync) error {
+	args, err := abi.Encode([]interface{}{
+		new(big.Int).SetUint64(sync.ID),
+		web3.Address(sync.Contract),
+		sync.Data,
+	}, onStateReceive.Inputs)
+	if err != nil {
+		return err
+	}
+
+	from := b.Address()
+	tx := &types.Transaction{
+		Nonce:    b.backend.GetNonce(from),
+		To:       &b.config.StateReceiver,
+		Value:    big.NewInt(0),
+		Gas:      StateSyncGas,
+		GasPrice: big.NewInt(0),
+		Input:    append(onStateReceive.ID(), args...),
+	}
+	if tx, err = b.signer.SignTx(tx, b.key); err != nil {
+		return err
+	}
+	if err := b.backend.AddTx(tx); err != nil {
+		return err
+	}
+
+	b.logger.Info("deposit relayed", "id", sync.ID, "contract", sync.Contract, "tx", tx.Hash)
+
+	return nil
+}
+
+// ExitProof returns the proof of the withdrawal of the log of the transaction, the log index
+// is the index of the log in the receipt
+func (b *Bridge) ExitProof(txHash types.Hash, logIndex uint64) (*ExitProof, error) {
+	blockHash, ok := b.backend.ReadTxLookup(txHash)
+	if !ok {
+		return nil, errPending
+	}
+	block, ok := b.backend.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+	receipts, err := b.backend.GetReceiptsByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, tx := range block.Transactions {
+		if tx.Hash == txHash {
+			index = i
+			break
+		}
+	}
+	if index == -1 || index >= len(receipts) {
+		return nil, errPending
+	}
+
+	receipt := receipts[index]
+	if logIndex >= uint64(len(receipt.Logs)) {
+		return nil, fmt.Errorf("the receipt has no log %d", logIndex)
+	}
+	if !isExit(receipt.Logs[logIndex], b.config.ExitContract) {
+		return nil, errNoExit
+	}
+
+	proof, err := itrie.ProveIndex(len(receipts), func(i int) []byte {
+		return receipts[i].MarshalRLPTo(nil)
+	}, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExitProof{
+		BlockNumber:  block.Number(),
+		BlockHash:    blockHash,
+		Header:       block.Header.MarshalRLPTo(nil),
+		Receipt:      receipt.MarshalRLPTo(nil),
+		ReceiptIndex: uint64(index),
+		LogIndex:     logIndex,
+		Proof:        proof,
+	}, nil
+}
+
+// isExit checks the log is a MessageSent event of the exit contract
+func isExit(log *types.Log, exitContract types.Address) bool {
+	if log.Address != exitContract {
+		return false
+	}
+
+	return len(log.Topics) != 0 && log.Topics[0] == types.Hash(messageSentEvent.ID())
+}
+
+// decodeStateSynced decodes the StateSynced event of a deposit
+func decodeStateSynced(log *web3.Log) (*StateSync, error) {
+	values, err := stateSyncedEvent.ParseLog(log)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deposit: %v", err)
+	}
+
+	id, ok := values["id"].(*big.Int)
+	if !ok || !id.IsUint64() {
+		return nil, errors.New("invalid deposit id")
+	}
+	contract, ok := values["contractAddress"].(web3.Address)
+	if !ok {
+		return nil, errors.New("invalid deposit contract")
+	}
+	data, ok := values["data"].([]byte)
+	if !ok {
+		return nil, errors.New("invalid deposit data")
+	}
+
+	return &StateSync{ID: id.Uint64(), Contract: types.Address(contract), Data: data}, nil
+}
+
+func (b *Bridge) readUint64(key []byte, def uint64) (uint64, error) {
+	v, ok, err := b.db.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return def, nil
+	}
+	if len(v) != 8 {
+		return 0, fmt.Errorf("invalid %s of the bridge: %s", key, hex.EncodeToHex(v))
+	}
+
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (b *Bridge) writeUint64(key []byte, val uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, val)
+
+	return b.db.Put(key, buf)
+}
@@ -0,0 +1,272 @@
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/types/buildroot"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/go-web3"
+	"github.com/umbracle/go-web3/abi"
+)
+
+var (
+	rootContract  = types.StringToAddress("100")
+	stateReceiver = types.StringToAddress("200")
+	exitContract  = types.StringToAddress("300")
+)
+
+// deposit is a StateSynced event of the root chain
+type deposit struct {
+	block uint64
+	id    uint64
+}
+
+// mockRootChain is the JSON-RPC endpoint of the root chain, with the deposits of the root contract
+type mockRootChain struct {
+	lock     sync.Mutex
+	head     uint64
+	deposits []deposit
+	ranges   [][2]uint64
+}
+
+func (m *mockRootChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     interface{}       `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var result interface{}
+	switch req.Method {
+	case "eth_blockNumber":
+		result = fmt.Sprintf("0x%x", m.head)
+	case "eth_getLogs":
+		var filter struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		_ = json.Unmarshal(req.Params[0], &filter)
+		from, _ := types.ParseUint64orHex(&filter.FromBlock)
+		to, _ := types.ParseUint64orHex(&filter.ToBlock)
+		m.ranges = append(m.ranges, [2]uint64{from, to})
+
+		logs := []map[string]interface{}{}
+		for _, d := range m.deposits {
+			if d.block < from || d.block > to {
+				continue
+			}
+			data, _ := abi.Encode([]interface{}{[]byte{byte(d.id)}}, abi.MustNewType("tuple(bytes)"))
+			logs = append(logs, map[string]interface{}{
+				"removed":          false,
+				"logIndex":         "0x0",
+				"transactionIndex": "0x0",
+				"transactionHash":  types.ZeroHash.String(),
+				"blockHash":        types.ZeroHash.String(),
+				"blockNumber":      fmt.Sprintf("0x%x", d.block),
+				"address":          rootContract.String(),
+				"data":             hex.EncodeToHex(data),
+				"topics": []string{
+					stateSyncedEvent.ID().String(),
+					types.BytesToHash(new(big.Int).SetUint64(d.id).Bytes()).String(),
+					types.BytesToHash(types.StringToAddress("1").Bytes()).String(),
+				},
+			})
+		}
+		result = logs
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+		"result":  result,
+	})
+}
+
+type mockBackend struct {
+	nonces   map[types.Address]uint64
+	txs      []*types.Transaction
+	block    *types.Block
+	receipts []*types.Receipt
+}
+
+func (m *mockBackend) Header() *types.Header {
+	return m.block.Header
+}
+
+func (m *mockBackend) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	if hash != m.block.Hash() {
+		return nil, false
+	}
+	return m.block, true
+}
+
+func (m *mockBackend) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	if hash != m.block.Hash() {
+		return nil, errors.New("not found")
+	}
+	return m.receipts, nil
+}
+
+func (m *mockBackend) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	for _, tx := range m.block.Transactions {
+		if tx.Hash == hash {
+			return m.block.Hash(), true
+		}
+	}
+	return types.Hash{}, false
+}
+
+func (m *mockBackend) GetNonce(addr types.Address) uint64 {
+	return m.nonces[addr]
+}
+
+func (m *mockBackend) AddTx(tx *types.Transaction) error {
+	from, err := crypto.NewEIP155Signer(100).Sender(tx)
+	if err != nil {
+		return err
+	}
+	m.txs = append(m.txs, tx)
+	m.nonces[from]++
+	return nil
+}
+
+func newTestBridge(t *testing.T, url string, backend Backend, db kvdb.Database) *Bridge {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBridge(hclog.NewNullLogger(), &Config{
+		URL:           url,
+		RootContract:  rootContract,
+		StateReceiver: stateReceiver,
+		ExitContract:  exitContract,
+		Confirmations: 2,
+		StartBlock:    10,
+	}, backend, db, key, crypto.NewEIP155Signer(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestBridgeRelay(t *testing.T) {
+	root := &mockRootChain{
+		head:     20,
+		deposits: []deposit{{block: 5, id: 1}, {block: 12, id: 2}, {block: 17, id: 3}, {block: 19, id: 4}},
+	}
+	srv := httptest.NewServer(root)
+	defer srv.Close()
+
+	backend := &mockBackend{nonces: map[types.Address]uint64{}}
+	db := kvdb.NewMemoryDatabase()
+
+	b := newTestBridge(t, srv.URL, backend, db)
+	assert.NoError(t, b.relay())
+
+	// the confirmed deposits after the start block are relayed
+	assert.Equal(t, [][2]uint64{{10, 18}}, root.ranges)
+	assert.Len(t, backend.txs, 2)
+
+	signer := crypto.NewEIP155Signer(100)
+	for i, tx := range backend.txs {
+		from, err := signer.Sender(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, b.Address(), from)
+		assert.Equal(t, uint64(i), tx.Nonce)
+		assert.Equal(t, stateReceiver, *tx.To)
+		assert.Equal(t, onStateReceive.ID(), tx.Input[:4])
+
+		args, err := abi.Decode(onStateReceive.Inputs, tx.Input[4:])
+		assert.NoError(t, err)
+		values := args.(map[string]interface{})
+		assert.Equal(t, big.NewInt(int64(i+2)), values["id"])
+		assert.Equal(t, web3.Address(types.StringToAddress("1")), values["contractAddress"])
+		assert.Equal(t, []byte{byte(i + 2)}, values["data"])
+	}
+
+	// the progress is kept once restarted, the relayed deposits are skipped
+	root.head = 22
+	root.deposits = append(root.deposits, deposit{block: 18, id: 5})
+
+	b = newTestBridge(t, srv.URL, backend, db)
+	assert.NoError(t, b.relay())
+	assert.Equal(t, [2]uint64{19, 20}, root.ranges[1])
+	assert.Len(t, backend.txs, 3)
+}
+
+func TestBridgeExitProof(t *testing.T) {
+	exitLog := &types.Log{
+		Address: exitContract,
+		Topics:  []types.Hash{types.Hash(messageSentEvent.ID())},
+		Data:    []byte{0x1},
+	}
+	otherLog := &types.Log{
+		Address: types.StringToAddress("400"),
+		Topics:  []types.Hash{types.Hash(messageSentEvent.ID())},
+	}
+
+	backend := &mockBackend{block: &types.Block{}}
+	success := types.ReceiptSuccess
+	for i := 0; i < 3; i++ {
+		tx := &types.Transaction{Nonce: uint64(i), Value: big.NewInt(0), GasPrice: big.NewInt(0)}
+		tx.ComputeHash()
+		backend.block.Transactions = append(backend.block.Transactions, tx)
+		backend.receipts = append(backend.receipts, &types.Receipt{
+			CumulativeGasUsed: uint64(i + 1),
+			Status:            &success,
+			Logs:              []*types.Log{otherLog, exitLog},
+		})
+	}
+	backend.block.Header = &types.Header{
+		Number:       7,
+		ReceiptsRoot: buildroot.CalculateReceiptsRoot(backend.receipts),
+	}
+	backend.block.Header.ComputeHash()
+
+	b := newTestBridge(t, "http://127.0.0.1:0", backend, kvdb.NewMemoryDatabase())
+
+	txHash := backend.block.Transactions[1].Hash
+	proof, err := b.ExitProof(txHash, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), proof.BlockNumber)
+	assert.Equal(t, uint64(1), proof.ReceiptIndex)
+	assert.Equal(t, backend.block.Header.MarshalRLPTo(nil), proof.Header)
+
+	receipt, err := itrie.VerifyIndexProof(backend.block.Header.ReceiptsRoot, int(proof.ReceiptIndex), proof.Proof)
+	assert.NoError(t, err)
+	assert.Equal(t, proof.Receipt, receipt)
+
+	// the log must be a message of the exit contract
+	_, err = b.ExitProof(txHash, 0)
+	assert.Equal(t, errNoExit, err)
+
+	_, err = b.ExitProof(txHash, 2)
+	assert.Error(t, err)
+
+	_, err = b.ExitProof(types.StringToHash("1"), 1)
+	assert.Equal(t, errPending, err)
+}
@@ -0,0 +1,64 @@
+package chain
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+const (
+	// BaseFeeChangeDenom bounds the change of the base fee between two blocks to 1/8
+	BaseFeeChangeDenom = 8
+
+	// ElasticityMultiplier is the ratio between the gas limit and the gas target of a block
+	ElasticityMultiplier = 2
+)
+
+// InitialBaseFee is the base fee of the first London block
+var InitialBaseFee = big.NewInt(1000000000)
+
+// CalculateBaseFee returns the base fee of the block after parent, as defined by EIP-1559,
+// or nil if the London fork is not active at that block
+func (p *Params) CalculateBaseFee(parent *types.Header) *big.Int {
+	if p.Forks == nil || !p.Forks.IsLondon(parent.Number+1) {
+		return nil
+	}
+
+	if parent.BaseFee == nil {
+		// the parent is the last block before the fork
+		return new(big.Int).Set(InitialBaseFee)
+	}
+
+	parentGasTarget := parent.GasLimit / ElasticityMultiplier
+	if parentGasTarget == 0 || parent.GasUsed == parentGasTarget {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+
+	var gasUsedDelta uint64
+	if parent.GasUsed > parentGasTarget {
+		gasUsedDelta = parent.GasUsed - parentGasTarget
+	} else {
+		gasUsedDelta = parentGasTarget - parent.GasUsed
+	}
+
+	// baseFeeDelta = parentBaseFee * gasUsedDelta / parentGasTarget / BaseFeeChangeDenom
+	baseFeeDelta := new(big.Int).Mul(parent.BaseFee, new(big.Int).SetUint64(gasUsedDelta))
+	baseFeeDelta.Div(baseFeeDelta, new(big.Int).SetUint64(parentGasTarget))
+	baseFeeDelta.Div(baseFeeDelta, big.NewInt(BaseFeeChangeDenom))
+
+	if parent.GasUsed > parentGasTarget {
+		// the base fee increases by at least 1 above the target
+		if baseFeeDelta.Sign() == 0 {
+			baseFeeDelta.SetUint64(1)
+		}
+
+		return baseFeeDelta.Add(parent.BaseFee, baseFeeDelta)
+	}
+
+	baseFee := baseFeeDelta.Sub(parent.BaseFee, baseFeeDelta)
+	if baseFee.Sign() < 0 {
+		baseFee.SetUint64(0)
+	}
+
+	return baseFee
+}
@@ -0,0 +1,60 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateBaseFee(t *testing.T) {
+	p := &Params{
+		Forks: &Forks{
+			London: NewFork(5),
+		},
+	}
+
+	cases := []struct {
+		name    string
+		parent  *types.Header
+		baseFee *big.Int
+	}{
+		{
+			"before the fork",
+			&types.Header{Number: 3},
+			nil,
+		},
+		{
+			"first block of the fork",
+			&types.Header{Number: 4},
+			InitialBaseFee,
+		},
+		{
+			"parent at the gas target",
+			&types.Header{Number: 10, GasLimit: 20000000, GasUsed: 10000000, BaseFee: big.NewInt(1000000000)},
+			big.NewInt(1000000000),
+		},
+		{
+			"full parent",
+			&types.Header{Number: 10, GasLimit: 20000000, GasUsed: 20000000, BaseFee: big.NewInt(1000000000)},
+			big.NewInt(1125000000),
+		},
+		{
+			"empty parent",
+			&types.Header{Number: 10, GasLimit: 20000000, GasUsed: 0, BaseFee: big.NewInt(1000000000)},
+			big.NewInt(875000000),
+		},
+		{
+			"minimum increase",
+			&types.Header{Number: 10, GasLimit: 20000000, GasUsed: 10000001, BaseFee: big.NewInt(1)},
+			big.NewInt(2),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.baseFee, p.CalculateBaseFee(c.parent))
+		})
+	}
+}
@@ -75,6 +75,10 @@ func (g *Genesis) GenesisHeader() *types.Header {
 		TxRoot:       types.EmptyRootHash,
 	}
 
+	if g.Config != nil && g.Config.Forks != nil && g.Config.Forks.IsLondon(g.Number) {
+		head.BaseFee = new(big.Int).Set(InitialBaseFee)
+	}
+
 	// Set default values if none are passed in
 	if g.GasLimit == 0 {
 		head.GasLimit = GenesisGasLimit
@@ -231,14 +235,19 @@ type GenesisAccount struct {
 	Balance    *big.Int                  `json:"balance,omitempty"`
 	Nonce      uint64                    `json:"nonce,omitempty"`
 	PrivateKey []byte                    `json:"secretKey,omitempty"` // for tests
+
+	// Constructor is the creation code run at genesis, its output is the code of the account.
+	// It is exclusive with the code
+	Constructor []byte `json:"constructor,omitempty"`
 }
 
 type genesisAccountEncoder struct {
-	Code       *string                   `json:"code,omitempty"`
-	Storage    map[types.Hash]types.Hash `json:"storage,omitempty"`
-	Balance    *string                   `json:"balance"`
-	Nonce      *string                   `json:"nonce,omitempty"`
-	PrivateKey *string                   `json:"secretKey,omitempty"`
+	Code        *string                   `json:"code,omitempty"`
+	Constructor *string                   `json:"constructor,omitempty"`
+	Storage     map[types.Hash]types.Hash `json:"storage,omitempty"`
+	Balance     *string                   `json:"balance"`
+	Nonce       *string                   `json:"nonce,omitempty"`
+	PrivateKey  *string                   `json:"secretKey,omitempty"`
 }
 
 // ENCODING //
@@ -248,6 +257,9 @@ func (g *GenesisAccount) MarshalJSON() ([]byte, error) {
 	if g.Code != nil {
 		obj.Code = types.EncodeBytes(g.Code)
 	}
+	if g.Constructor != nil {
+		obj.Constructor = types.EncodeBytes(g.Constructor)
+	}
 	if len(g.Storage) != 0 {
 		obj.Storage = g.Storage
 	}
@@ -267,11 +279,12 @@ func (g *GenesisAccount) MarshalJSON() ([]byte, error) {
 
 func (g *GenesisAccount) UnmarshalJSON(data []byte) error {
 	type GenesisAccount struct {
-		Code       *string                   `json:"code,omitempty"`
-		Storage    map[types.Hash]types.Hash `json:"storage,omitempty"`
-		Balance    *string                   `json:"balance"`
-		Nonce      *string                   `json:"nonce,omitempty"`
-		PrivateKey *string                   `json:"secretKey,omitempty"`
+		Code        *string                   `json:"code,omitempty"`
+		Constructor *string                   `json:"constructor,omitempty"`
+		Storage     map[types.Hash]types.Hash `json:"storage,omitempty"`
+		Balance     *string                   `json:"balance"`
+		Nonce       *string                   `json:"nonce,omitempty"`
+		PrivateKey  *string                   `json:"secretKey,omitempty"`
 	}
 
 	var dec GenesisAccount
@@ -293,6 +306,13 @@ func (g *GenesisAccount) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if dec.Constructor != nil {
+		g.Constructor, subErr = types.ParseBytes(dec.Constructor)
+		if subErr != nil {
+			parseError("constructor", subErr)
+		}
+	}
+
 	if dec.Storage != nil {
 		g.Storage = dec.Storage
 	}
@@ -316,23 +336,23 @@ func (g *GenesisAccount) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// Import imports a chain from a preset, or from a filepath if there is no preset with the name
 func Import(chain string) (*Chain, error) {
-	c, err := ImportFromName(chain)
-	if err == nil {
-		return c, nil
+	if preset, ok := lookupPreset(chain); ok {
+		return preset()
 	}
 
 	return ImportFromFile(chain)
 }
 
-// ImportFromName imports a chain from the precompiled json chains (i.e. foundation)
+// ImportFromName imports a chain from the registered presets, like the precompiled json chains (i.e. foundation)
 func ImportFromName(chain string) (*Chain, error) {
-	data, err := Asset("chain/chains/" + chain + ".json")
-	if err != nil {
-		return nil, err
+	preset, ok := lookupPreset(chain)
+	if !ok {
+		return nil, fmt.Errorf("preset %s not found, presets: %v", chain, Presets())
 	}
 
-	return importChain(data)
+	return preset()
 }
 
 // ImportFromFile imports a chain from a filepath
@@ -78,6 +78,20 @@ func TestGenesisAlloc(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: `{
+				"0x0000000000000000000000000000000000000000": {
+					"balance": "0x11",
+					"constructor": "0x6000"
+				}
+			}`,
+			output: map[types.Address]GenesisAccount{
+				emptyAddr: GenesisAccount{
+					Balance:     big.NewInt(17),
+					Constructor: []byte{0x60, 0x00},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
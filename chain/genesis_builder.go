@@ -0,0 +1,188 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-multierror"
+)
+
+// GenesisBuilder builds a chain spec programmatically, for the test harnesses and the devnet tools.
+// The setters are chained, their errors are returned by Build:
+//
+//	c, err := chain.NewGenesisBuilder("devnet").
+//		ChainID(100).
+//		Consensus("ibft", nil).
+//		ExtraData(ibft.GenesisExtraData(validators)).
+//		Premine(addr, balance).
+//		Fork("london", 0).
+//		Build()
+type GenesisBuilder struct {
+	chain *Chain
+	err   error
+}
+
+// NewGenesisBuilder returns a builder of a chain with every fork before Berlin active from the genesis,
+// and the default gas limit
+func NewGenesisBuilder(name string) *GenesisBuilder {
+	forks := *AllForksEnabled
+
+	return &GenesisBuilder{
+		chain: &Chain{
+			Name: name,
+			Genesis: &Genesis{
+				GasLimit:   GenesisGasLimit,
+				Difficulty: 1,
+				Alloc:      map[types.Address]*GenesisAccount{},
+			},
+			Params: &Params{
+				Forks:  &forks,
+				Engine: map[string]interface{}{},
+			},
+			Bootnodes: []string{},
+		},
+	}
+}
+
+func (b *GenesisBuilder) fail(format string, args ...interface{}) *GenesisBuilder {
+	b.err = multierror.Append(b.err, fmt.Errorf(format, args...))
+	return b
+}
+
+// ChainID sets the id of the chain
+func (b *GenesisBuilder) ChainID(id int) *GenesisBuilder {
+	b.chain.Params.ChainID = id
+	return b
+}
+
+// Consensus sets the consensus engine and its params, replacing the previous engine
+func (b *GenesisBuilder) Consensus(engine string, params map[string]interface{}) *GenesisBuilder {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	b.chain.Params.Engine = map[string]interface{}{
+		engine: params,
+	}
+	return b
+}
+
+// ExtraData sets the extra data of the genesis header, like the initial validator set of IBFT
+func (b *GenesisBuilder) ExtraData(extra []byte) *GenesisBuilder {
+	b.chain.Genesis.ExtraData = extra
+	return b
+}
+
+// Forks replaces the forks of the chain
+func (b *GenesisBuilder) Forks(forks *Forks) *GenesisBuilder {
+	copied := *forks
+	b.chain.Params.Forks = &copied
+	return b
+}
+
+// Fork activates the fork at the block, the name is the one of the chain spec (i.e. london)
+func (b *GenesisBuilder) Fork(name string, block uint64) *GenesisBuilder {
+	for _, named := range b.chain.Params.Forks.named() {
+		if named.name == name {
+			*named.fork = NewFork(block)
+			return b
+		}
+	}
+
+	return b.fail("fork %s is unknown", name)
+}
+
+// Params applies the function to the params of the chain, for the settings without a setter
+// like the state rent or the economics
+func (b *GenesisBuilder) Params(fn func(params *Params)) *GenesisBuilder {
+	fn(b.chain.Params)
+	return b
+}
+
+// GasLimit sets the gas limit of the genesis block
+func (b *GenesisBuilder) GasLimit(gasLimit uint64) *GenesisBuilder {
+	b.chain.Genesis.GasLimit = gasLimit
+	return b
+}
+
+// BlockGasTarget sets the gas limit the block producers move the gas limit towards
+func (b *GenesisBuilder) BlockGasTarget(target uint64) *GenesisBuilder {
+	b.chain.Params.BlockGasTarget = target
+	return b
+}
+
+// Timestamp sets the timestamp of the genesis block
+func (b *GenesisBuilder) Timestamp(timestamp uint64) *GenesisBuilder {
+	b.chain.Genesis.Timestamp = timestamp
+	return b
+}
+
+// Premine credits the balance to the account, on top of its previous balance
+func (b *GenesisBuilder) Premine(addr types.Address, balance *big.Int) *GenesisBuilder {
+	account, ok := b.chain.Genesis.Alloc[addr]
+	if !ok {
+		account = &GenesisAccount{}
+		b.chain.Genesis.Alloc[addr] = account
+	}
+
+	if account.Balance == nil {
+		account.Balance = new(big.Int)
+	}
+	account.Balance = new(big.Int).Add(account.Balance, balance)
+
+	return b
+}
+
+// Account allocates the account, like a system contract. An address is allocated once
+func (b *GenesisBuilder) Account(addr types.Address, account *GenesisAccount) *GenesisBuilder {
+	if _, ok := b.chain.Genesis.Alloc[addr]; ok {
+		return b.fail("account %s is allocated twice", addr)
+	}
+	if account.Code != nil && account.Constructor != nil {
+		return b.fail("account %s has both code and a constructor", addr)
+	}
+
+	b.chain.Genesis.Alloc[addr] = account
+	return b
+}
+
+// Bootnodes adds the libp2p multiaddrs of the bootnodes
+func (b *GenesisBuilder) Bootnodes(bootnodes ...string) *GenesisBuilder {
+	b.chain.Bootnodes = append(b.chain.Bootnodes, bootnodes...)
+	return b
+}
+
+// Build returns the chain spec, or the errors of the setters and of the validation of the spec
+func (b *GenesisBuilder) Build() (*Chain, error) {
+	err := b.err
+	if validateErr := b.chain.Validate(); validateErr != nil {
+		err = multierror.Append(err, validateErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.chain, nil
+}
+
+// WriteFile builds the chain spec and writes it to the file, in the format of the genesis command
+func (b *GenesisBuilder) WriteFile(path string) error {
+	c, err := b.Build()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to generate genesis: %w", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write genesis: %w", err)
+	}
+
+	return nil
+}
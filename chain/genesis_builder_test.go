@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenesisBuilder(t *testing.T) {
+	addr1 := types.StringToAddress("1000")
+	addr2 := types.StringToAddress("2000")
+
+	c, err := NewGenesisBuilder("devnet").
+		ChainID(100).
+		Consensus("ibft", map[string]interface{}{"epochSize": 10}).
+		ExtraData([]byte{1, 2}).
+		Fork("berlin", 0).
+		Fork("london", 10).
+		Premine(addr1, big.NewInt(10)).
+		Premine(addr1, big.NewInt(5)).
+		Account(addr2, &GenesisAccount{Code: []byte{0x1}}).
+		Params(func(params *Params) {
+			params.StateRent = &StateRent{InactivityPeriod: 100}
+		}).
+		Bootnodes("/ip4/127.0.0.1/tcp/1478/p2p/node").
+		Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "devnet", c.Name)
+	assert.Equal(t, 100, c.Params.ChainID)
+	assert.Equal(t, map[string]interface{}{"ibft": map[string]interface{}{"epochSize": 10}}, c.Params.Engine)
+	assert.Equal(t, []byte{1, 2}, c.Genesis.ExtraData)
+	assert.Equal(t, GenesisGasLimit, c.Genesis.GasLimit)
+	assert.Equal(t, uint64(100), c.Params.StateRent.InactivityPeriod)
+	assert.Equal(t, []string{"/ip4/127.0.0.1/tcp/1478/p2p/node"}, c.Bootnodes)
+
+	// the forks before Berlin are active from the genesis
+	assert.True(t, c.Params.Forks.IsPetersburg(0))
+	assert.True(t, c.Params.Forks.IsBerlin(0))
+	assert.False(t, c.Params.Forks.IsLondon(9))
+	assert.True(t, c.Params.Forks.IsLondon(10))
+
+	// the premines add up
+	assert.Equal(t, big.NewInt(15), c.Genesis.Alloc[addr1].Balance)
+	assert.Equal(t, []byte{0x1}, c.Genesis.Alloc[addr2].Code)
+
+	// the spec is the one of a genesis file
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+
+	imported, err := importChain(data)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Genesis.Hash(), imported.Genesis.Hash())
+
+	// the defaults are not shared between the chains
+	assert.Nil(t, AllForksEnabled.London)
+}
+
+func TestGenesisBuilder_Invalid(t *testing.T) {
+	addr := types.StringToAddress("1")
+
+	cases := map[string]*GenesisBuilder{
+		"no consensus": NewGenesisBuilder("test"),
+		"unknown fork": NewGenesisBuilder("test").Consensus("dev", nil).Fork("merge", 0),
+		"london before berlin": NewGenesisBuilder("test").Consensus("dev", nil).
+			Fork("berlin", 10).Fork("london", 5),
+		"account allocated twice": NewGenesisBuilder("test").Consensus("dev", nil).
+			Premine(addr, big.NewInt(1)).Account(addr, &GenesisAccount{}),
+		"code and constructor": NewGenesisBuilder("test").Consensus("dev", nil).
+			Account(addr, &GenesisAccount{Code: []byte{1}, Constructor: []byte{1}}),
+	}
+	for name, builder := range cases {
+		_, err := builder.Build()
+		assert.Error(t, err, name)
+	}
+}
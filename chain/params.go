@@ -1,7 +1,12 @@
 package chain
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-multierror"
 )
 
 // Params are all the set of params for the chain
@@ -10,6 +15,298 @@ type Params struct {
 	ChainID        int                    `json:"chainID"`
 	Engine         map[string]interface{} `json:"engine"`
 	BlockGasTarget uint64                 `json:"blockGasTarget"`
+
+	// StateRent configures the archival of the inactive accounts, once the StateRent fork is active
+	StateRent *StateRent `json:"stateRent,omitempty"`
+
+	// Precompiles places the custom precompiled contracts registered in the node
+	Precompiles []*Precompile `json:"precompiles,omitempty"`
+
+	// Upgrades replaces the code of the system contracts at fork blocks
+	Upgrades []*ContractUpgrade `json:"upgrades,omitempty"`
+
+	// Economics configures the block rewards and the distribution of the fees
+	Economics *Economics `json:"economics,omitempty"`
+
+	// SystemCalls are the calls the validators make with system transactions at the top of the blocks
+	SystemCalls []*SystemCall `json:"systemCalls,omitempty"`
+
+	// GasTargetContract sets the block gas target on-chain, instead of the target of each node
+	GasTargetContract *GasTargetContract `json:"gasTargetContract,omitempty"`
+}
+
+// StateRent are the params of the archival of the inactive accounts
+type StateRent struct {
+	// InactivityPeriod is the number of blocks an account can stay unmodified before it is archived.
+	// The accounts are archived at the blocks multiple of the period
+	InactivityPeriod uint64 `json:"inactivityPeriod"`
+}
+
+// Precompile places a custom precompiled contract at an address, from a block on.
+// A call costs the base gas, plus the word gas for every 32 bytes of input
+type Precompile struct {
+	// Name is the name the contract is registered with in the node
+	Name    string        `json:"name"`
+	Address types.Address `json:"address"`
+	Block   uint64        `json:"block"`
+	BaseGas uint64        `json:"baseGas"`
+	WordGas uint64        `json:"wordGas"`
+}
+
+// ContractUpgrade replaces the code of a contract at the start of a block, before its transactions.
+// The storage of the contract is kept, and the slots of the upgrade are set on top of it
+type ContractUpgrade struct {
+	Block   uint64
+	Address types.Address
+	Code    []byte
+	Storage map[types.Hash]types.Hash
+}
+
+type contractUpgradeEncoder struct {
+	Block   *string                   `json:"block"`
+	Address types.Address             `json:"address"`
+	Code    *string                   `json:"code"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+func (c *ContractUpgrade) MarshalJSON() ([]byte, error) {
+	obj := &contractUpgradeEncoder{
+		Block:   types.EncodeUint64(c.Block),
+		Address: c.Address,
+		Code:    types.EncodeBytes(c.Code),
+	}
+	if len(c.Storage) != 0 {
+		obj.Storage = c.Storage
+	}
+	return json.Marshal(obj)
+}
+
+func (c *ContractUpgrade) UnmarshalJSON(data []byte) error {
+	var dec contractUpgradeEncoder
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	var err, subErr error
+	parseError := func(field string, subErr error) {
+		err = multierror.Append(err, fmt.Errorf("%s: %v", field, subErr))
+	}
+
+	c.Block, subErr = types.ParseUint64orHex(dec.Block)
+	if subErr != nil {
+		parseError("block", subErr)
+	}
+	c.Address = dec.Address
+
+	if dec.Code == nil {
+		return fmt.Errorf("field 'code' is required")
+	}
+	c.Code, subErr = types.ParseBytes(dec.Code)
+	if subErr != nil {
+		parseError("code", subErr)
+	}
+	c.Storage = dec.Storage
+
+	return err
+}
+
+// MaxShare is the denominator of the shares of the fees, in basis points
+const MaxShare = 10000
+
+// Economics are the block rewards and the distribution of the fees, from a block on.
+// Without them, the proposer receives the whole fees and nothing is minted
+type Economics struct {
+	Block uint64
+
+	// BlockReward is minted to the proposer of every block
+	BlockReward *big.Int
+
+	// TreasuryShare and BurnShare are the shares of the fees, in basis points, sent to the treasury and burnt.
+	// The proposer receives the rest
+	Treasury      types.Address
+	TreasuryShare uint64
+	BurnShare     uint64
+}
+
+type economicsEncoder struct {
+	Block         *string       `json:"block,omitempty"`
+	BlockReward   *string       `json:"blockReward,omitempty"`
+	Treasury      types.Address `json:"treasury"`
+	TreasuryShare uint64        `json:"treasuryShare"`
+	BurnShare     uint64        `json:"burnShare"`
+}
+
+func (e *Economics) MarshalJSON() ([]byte, error) {
+	obj := &economicsEncoder{
+		Block:         types.EncodeUint64(e.Block),
+		Treasury:      e.Treasury,
+		TreasuryShare: e.TreasuryShare,
+		BurnShare:     e.BurnShare,
+	}
+	if e.BlockReward != nil {
+		obj.BlockReward = types.EncodeBigInt(e.BlockReward)
+	}
+	return json.Marshal(obj)
+}
+
+func (e *Economics) UnmarshalJSON(data []byte) error {
+	var dec economicsEncoder
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	var err, subErr error
+	parseError := func(field string, subErr error) {
+		err = multierror.Append(err, fmt.Errorf("%s: %v", field, subErr))
+	}
+
+	e.Block, subErr = types.ParseUint64orHex(dec.Block)
+	if subErr != nil {
+		parseError("block", subErr)
+	}
+	e.BlockReward, subErr = types.ParseUint256orHex(dec.BlockReward)
+	if subErr != nil {
+		parseError("blockReward", subErr)
+	}
+	e.Treasury = dec.Treasury
+	e.TreasuryShare = dec.TreasuryShare
+	e.BurnShare = dec.BurnShare
+
+	if e.TreasuryShare+e.BurnShare > MaxShare {
+		parseError("shares", fmt.Errorf("the treasury and burn shares exceed %d basis points", MaxShare))
+	}
+	if e.TreasuryShare != 0 && e.Treasury == types.ZeroAddress {
+		parseError("treasury", fmt.Errorf("the treasury share has no treasury"))
+	}
+
+	return err
+}
+
+// SystemCall is a call to a system contract at the top of a block, like the update of the validator set
+// at the end of an epoch. It is made at the block, and at every interval of blocks after it if set
+type SystemCall struct {
+	Block    uint64
+	Interval uint64
+	To       types.Address
+	Input    []byte
+	Gas      uint64
+}
+
+type systemCallEncoder struct {
+	Block    *string       `json:"block"`
+	Interval *string       `json:"interval,omitempty"`
+	To       types.Address `json:"to"`
+	Input    *string       `json:"input"`
+	Gas      *string       `json:"gas"`
+}
+
+func (s *SystemCall) MarshalJSON() ([]byte, error) {
+	obj := &systemCallEncoder{
+		Block: types.EncodeUint64(s.Block),
+		To:    s.To,
+		Input: types.EncodeBytes(s.Input),
+		Gas:   types.EncodeUint64(s.Gas),
+	}
+	if s.Interval != 0 {
+		obj.Interval = types.EncodeUint64(s.Interval)
+	}
+	return json.Marshal(obj)
+}
+
+func (s *SystemCall) UnmarshalJSON(data []byte) error {
+	var dec systemCallEncoder
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	var err, subErr error
+	parseError := func(field string, subErr error) {
+		err = multierror.Append(err, fmt.Errorf("%s: %v", field, subErr))
+	}
+
+	s.Block, subErr = types.ParseUint64orHex(dec.Block)
+	if subErr != nil {
+		parseError("block", subErr)
+	}
+	s.Interval, subErr = types.ParseUint64orHex(dec.Interval)
+	if subErr != nil {
+		parseError("interval", subErr)
+	}
+	s.To = dec.To
+	s.Input, subErr = types.ParseBytes(dec.Input)
+	if subErr != nil {
+		parseError("input", subErr)
+	}
+	s.Gas, subErr = types.ParseUint64orHex(dec.Gas)
+	if subErr != nil {
+		parseError("gas", subErr)
+	}
+
+	if s.Gas == 0 {
+		parseError("gas", fmt.Errorf("the system call has no gas"))
+	}
+
+	return err
+}
+
+// Active checks if the call is made at the block
+func (s *SystemCall) Active(number uint64) bool {
+	if number < s.Block {
+		return false
+	}
+	if s.Interval == 0 {
+		return number == s.Block
+	}
+	return (number-s.Block)%s.Interval == 0
+}
+
+// GasTargetContract is a contract whose gasTarget() view sets the block gas target of the whole chain.
+// The target is read at the start of every epoch of Interval blocks, at the state of the block before it,
+// and a zero target leaves the target of the node
+type GasTargetContract struct {
+	Address  types.Address
+	Interval uint64
+}
+
+type gasTargetContractEncoder struct {
+	Address  types.Address `json:"address"`
+	Interval *string       `json:"interval"`
+}
+
+func (g *GasTargetContract) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&gasTargetContractEncoder{
+		Address:  g.Address,
+		Interval: types.EncodeUint64(g.Interval),
+	})
+}
+
+func (g *GasTargetContract) UnmarshalJSON(data []byte) error {
+	var dec gasTargetContractEncoder
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	var err, subErr error
+	parseError := func(field string, subErr error) {
+		err = multierror.Append(err, fmt.Errorf("%s: %v", field, subErr))
+	}
+
+	g.Address = dec.Address
+	g.Interval, subErr = types.ParseUint64orHex(dec.Interval)
+	if subErr != nil {
+		parseError("interval", subErr)
+	}
+
+	if g.Interval == 0 {
+		parseError("interval", fmt.Errorf("the gas target contract has no interval"))
+	}
+
+	return err
+}
+
+// Epoch returns the first block of the epoch of the block
+func (g *GasTargetContract) Epoch(number uint64) uint64 {
+	return number - number%g.Interval
 }
 
 func (p *Params) GetEngine() string {
@@ -30,6 +327,9 @@ type Forks struct {
 	EIP150         *Fork `json:"EIP150,omitempty"`
 	EIP158         *Fork `json:"EIP158,omitempty"`
 	EIP155         *Fork `json:"EIP155,omitempty"`
+	StateRent      *Fork `json:"stateRent,omitempty"`
+	Berlin         *Fork `json:"berlin,omitempty"`
+	London         *Fork `json:"london,omitempty"`
 }
 
 func (f *Forks) active(ff *Fork, block uint64) bool {
@@ -67,6 +367,18 @@ func (f *Forks) IsEIP155(block uint64) bool {
 	return f.active(f.EIP155, block)
 }
 
+func (f *Forks) IsStateRent(block uint64) bool {
+	return f.active(f.StateRent, block)
+}
+
+func (f *Forks) IsBerlin(block uint64) bool {
+	return f.active(f.Berlin, block)
+}
+
+func (f *Forks) IsLondon(block uint64) bool {
+	return f.active(f.London, block)
+}
+
 func (f *Forks) At(block uint64) ForksInTime {
 	return ForksInTime{
 		Homestead:      f.active(f.Homestead, block),
@@ -77,6 +389,9 @@ func (f *Forks) At(block uint64) ForksInTime {
 		EIP150:         f.active(f.EIP150, block),
 		EIP158:         f.active(f.EIP158, block),
 		EIP155:         f.active(f.EIP155, block),
+		StateRent:      f.active(f.StateRent, block),
+		Berlin:         f.active(f.Berlin, block),
+		London:         f.active(f.London, block),
 	}
 }
 
@@ -104,7 +419,10 @@ type ForksInTime struct {
 	Istanbul,
 	EIP150,
 	EIP158,
-	EIP155 bool
+	EIP155,
+	StateRent,
+	Berlin,
+	London bool
 }
 
 var AllForksEnabled = &Forks{
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
 )
 
 func TestValidateChainID(t *testing.T) {
@@ -31,6 +33,16 @@ func TestParamsForks(t *testing.T) {
 				Homestead: NewFork(1000),
 			},
 		},
+		{
+			input: `{
+				"berlin": 10,
+				"london": 20
+			}`,
+			output: &Forks{
+				Berlin: NewFork(10),
+				London: NewFork(20),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -45,6 +57,143 @@ func TestParamsForks(t *testing.T) {
 	}
 }
 
+func TestParamsUpgrades(t *testing.T) {
+	input := `{
+		"upgrades": [
+			{
+				"block": "0x10",
+				"address": "0x0000000000000000000000000000000000001001",
+				"code": "0x6000",
+				"storage": {
+					"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+				}
+			}
+		]
+	}`
+
+	var params *Params
+	if err := json.Unmarshal([]byte(input), &params); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*ContractUpgrade{
+		{
+			Block:   16,
+			Address: types.StringToAddress("1001"),
+			Code:    []byte{0x60, 0x00},
+			Storage: map[types.Hash]types.Hash{
+				types.StringToHash("1"): types.StringToHash("2"),
+			},
+		},
+	}
+	if !reflect.DeepEqual(params.Upgrades, expected) {
+		t.Fatal("bad")
+	}
+
+	// and survives the round trip
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec *Params
+	if err := json.Unmarshal(data, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec.Upgrades, expected) {
+		t.Fatal("bad")
+	}
+
+	// the code is required
+	if err := json.Unmarshal([]byte(`{"upgrades": [{"block": "0x10"}]}`), &dec); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestParamsEconomics(t *testing.T) {
+	var params *Params
+	if err := json.Unmarshal([]byte(`{
+		"economics": {
+			"blockReward": "0xde0b6b3a7640000",
+			"treasury": "0x0000000000000000000000000000000000000001",
+			"treasuryShare": 2000,
+			"burnShare": 3000
+		}
+	}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Economics.BlockReward.String() != "1000000000000000000" || params.Economics.TreasuryShare != 2000 {
+		t.Fatal("bad")
+	}
+
+	// the shares can't exceed the fees
+	if err := json.Unmarshal([]byte(`{"economics": {"treasury": "0x0000000000000000000000000000000000000001", "treasuryShare": 8000, "burnShare": 3000}}`), &params); err == nil {
+		t.Fatal("expected an error")
+	}
+	// and the treasury share needs a treasury
+	if err := json.Unmarshal([]byte(`{"economics": {"treasuryShare": 1000}}`), &params); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestParamsSystemCalls(t *testing.T) {
+	var params *Params
+	if err := json.Unmarshal([]byte(`{
+		"systemCalls": [
+			{
+				"block": "0x64",
+				"interval": "0xa",
+				"to": "0x0000000000000000000000000000000000001002",
+				"input": "0x01020304",
+				"gas": "0x186a0"
+			}
+		]
+	}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	call := params.SystemCalls[0]
+	if call.Block != 100 || call.Interval != 10 || call.Gas != 100000 || len(call.Input) != 4 {
+		t.Fatal("bad")
+	}
+
+	for number, active := range map[uint64]bool{90: false, 100: true, 105: false, 110: true, 200: true} {
+		if call.Active(number) != active {
+			t.Fatalf("block %d: expected active %v", number, active)
+		}
+	}
+
+	// the calls need gas
+	if err := json.Unmarshal([]byte(`{"systemCalls": [{"block": "0x1", "to": "0x0000000000000000000000000000000000001002"}]}`), &params); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestParamsGasTargetContract(t *testing.T) {
+	var params *Params
+	if err := json.Unmarshal([]byte(`{
+		"gasTargetContract": {
+			"address": "0x0000000000000000000000000000000000001003",
+			"interval": "0x64"
+		}
+	}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	contract := params.GasTargetContract
+	if contract.Address != types.StringToAddress("0x1003") || contract.Interval != 100 {
+		t.Fatal("bad")
+	}
+
+	for number, epoch := range map[uint64]uint64{0: 0, 99: 0, 100: 100, 250: 200} {
+		if contract.Epoch(number) != epoch {
+			t.Fatalf("block %d: expected epoch %d", number, epoch)
+		}
+	}
+
+	// the target is read at an interval
+	if err := json.Unmarshal([]byte(`{"gasTargetContract": {"address": "0x0000000000000000000000000000000000001003"}}`), &params); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
 func TestParamsForksInTime(t *testing.T) {
 	f := Forks{
 		Homestead:      NewFork(0),
@@ -65,4 +214,13 @@ func TestParamsForksInTime(t *testing.T) {
 	expect("byzantium", ff.Byzantium, true)
 	expect("constantinople", ff.Constantinople, false)
 	expect("eip150", ff.EIP150, false)
+	expect("berlin", ff.Berlin, false)
+	expect("london", ff.London, false)
+
+	f.Berlin = NewFork(1000)
+	f.London = NewFork(1001)
+
+	expect("berlin", f.At(1000).Berlin, true)
+	expect("london", f.At(1000).London, false)
+	expect("london", f.At(1001).London, true)
 }
@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Preset returns the chain spec of a named network: its genesis, bootnodes and fork schedule.
+// It is called every time the preset is imported, and returns a new chain every time
+type Preset func() (*Chain, error)
+
+var (
+	presetsLock sync.Mutex
+	presets     = map[string]Preset{}
+)
+
+// RegisterPreset makes the network selectable by its name with the chain flag, so the downstream distributions
+// can bundle their own networks. It is meant to be called from the init function of a package,
+// and panics if the name is already taken
+func RegisterPreset(name string, preset Preset) {
+	presetsLock.Lock()
+	defer presetsLock.Unlock()
+
+	if preset == nil {
+		panic("chain: registered preset is nil")
+	}
+	if _, ok := presets[name]; ok {
+		panic(fmt.Sprintf("chain: preset %s registered twice", name))
+	}
+
+	presets[name] = preset
+}
+
+// Presets returns the sorted names of the registered presets
+func Presets() []string {
+	presetsLock.Lock()
+	defer presetsLock.Unlock()
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupPreset(name string) (Preset, bool) {
+	presetsLock.Lock()
+	defer presetsLock.Unlock()
+
+	preset, ok := presets[name]
+
+	return preset, ok
+}
+
+// localPreset is a single node chain sealed by the dev consensus, with every fork active from the genesis
+func localPreset() (*Chain, error) {
+	return NewGenesisBuilder("local").
+		ChainID(100).
+		Consensus("dev", nil).
+		Fork("berlin", 0).
+		Fork("london", 0).
+		Build()
+}
+
+func init() {
+	// the chains bundled in the chains folder
+	for _, asset := range AssetNames() {
+		asset := asset
+		name := strings.TrimSuffix(strings.TrimPrefix(asset, "chain/chains/"), ".json")
+
+		RegisterPreset(name, func() (*Chain, error) {
+			data, err := Asset(asset)
+			if err != nil {
+				return nil, err
+			}
+
+			return importChain(data)
+		})
+	}
+
+	RegisterPreset("local", localPreset)
+}
@@ -0,0 +1,46 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	// the bundled chains and the local chain
+	for _, name := range []string{"foundation", "ibft", "test", "local"} {
+		assert.Contains(t, Presets(), name)
+	}
+
+	local, err := Import("local")
+	assert.NoError(t, err)
+	assert.Equal(t, "local", local.Name)
+	assert.Contains(t, local.Params.Engine, "dev")
+	assert.True(t, local.Params.Forks.IsLondon(0))
+
+	// every import is a new chain
+	local.Params.ChainID = 1
+
+	imported, err := Import("local")
+	assert.NoError(t, err)
+	assert.Equal(t, 100, imported.Params.ChainID)
+
+	// the names without a preset are files
+	_, err = Import("unknown.json")
+	assert.Error(t, err)
+}
+
+func TestRegisterPreset(t *testing.T) {
+	RegisterPreset("test-preset", func() (*Chain, error) {
+		return NewGenesisBuilder("test-preset").ChainID(1).Consensus("dummy", nil).Bootnodes("/ip4/127.0.0.1/tcp/1478").Build()
+	})
+
+	c, err := Import("test-preset")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/ip4/127.0.0.1/tcp/1478"}, c.Bootnodes)
+
+	// the names are unique
+	assert.Panics(t, func() {
+		RegisterPreset("test-preset", localPreset)
+	})
+}
@@ -0,0 +1,192 @@
+package chain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-multierror"
+)
+
+// builtinPrecompiles is the number of the precompiled contracts of the evm, at the addresses 0x1 and following
+const builtinPrecompiles = 9
+
+// EngineCheck checks the params and the genesis of a chain run by a consensus engine, like its validator set
+type EngineCheck func(c *Chain) error
+
+var (
+	engineChecksLock sync.Mutex
+	engineChecks     = map[string]EngineCheck{}
+)
+
+// RegisterEngineCheck sets the check run by Validate for the chains of the consensus engine.
+// It is meant to be called from the init function of the package of the engine
+func RegisterEngineCheck(engine string, check EngineCheck) {
+	engineChecksLock.Lock()
+	defer engineChecksLock.Unlock()
+
+	engineChecks[engine] = check
+}
+
+func lookupEngineCheck(engine string) (EngineCheck, bool) {
+	engineChecksLock.Lock()
+	defer engineChecksLock.Unlock()
+
+	check, ok := engineChecks[engine]
+
+	return check, ok
+}
+
+// namedFork is a fork and its name in the chain spec
+type namedFork struct {
+	name string
+	fork **Fork
+}
+
+// named returns the forks in the order they build on each other. The state rent is not ordered
+func (f *Forks) named() []namedFork {
+	return []namedFork{
+		{"homestead", &f.Homestead},
+		{"EIP150", &f.EIP150},
+		{"EIP155", &f.EIP155},
+		{"EIP158", &f.EIP158},
+		{"byzantium", &f.Byzantium},
+		{"constantinople", &f.Constantinople},
+		{"petersburg", &f.Petersburg},
+		{"istanbul", &f.Istanbul},
+		{"berlin", &f.Berlin},
+		{"london", &f.London},
+		{"stateRent", &f.StateRent},
+	}
+}
+
+// Heights returns the blocks the forks are activated at after the genesis
+func (f *Forks) Heights() []uint64 {
+	heights := []uint64{}
+	for _, named := range f.named() {
+		if fork := *named.fork; fork != nil && *fork != 0 {
+			heights = append(heights, uint64(*fork))
+		}
+	}
+
+	return heights
+}
+
+// Validate checks the chain spec for the inconsistencies that would only show once the network is launched:
+// the order of the forks, the accounts allocated at the addresses of the precompiled contracts,
+// the custom precompiled contracts and the contract upgrades. The checks of the consensus engine
+// of the chain are run too, if registered
+func (c *Chain) Validate() error {
+	var err error
+	fail := func(format string, args ...interface{}) {
+		err = multierror.Append(err, fmt.Errorf(format, args...))
+	}
+
+	if c.Genesis == nil || c.Params == nil {
+		return fmt.Errorf("the genesis and the params of the chain are required")
+	}
+
+	if c.Params.ChainID <= 0 {
+		fail("the chain id must be positive")
+	}
+
+	if len(c.Params.Engine) != 1 {
+		fail("expected one consensus engine but found %d", len(c.Params.Engine))
+	}
+
+	if c.Params.Forks != nil {
+		c.validateForks(fail)
+	}
+
+	// the accounts at the precompiled addresses can hold a balance, not code or storage
+	precompiles := map[types.Address]string{}
+	for i := 1; i <= builtinPrecompiles; i++ {
+		precompiles[types.BytesToAddress([]byte{byte(i)})] = "built-in"
+	}
+
+	names := map[string]struct{}{}
+	for _, precompile := range c.Params.Precompiles {
+		if name, ok := precompiles[precompile.Address]; ok {
+			fail("precompiled contract %s: address %s is taken by the %s contract", precompile.Name, precompile.Address, name)
+		}
+		if _, ok := names[precompile.Name]; ok {
+			fail("precompiled contract %s is placed twice", precompile.Name)
+		}
+		precompiles[precompile.Address] = precompile.Name
+		names[precompile.Name] = struct{}{}
+	}
+
+	for addr, account := range c.Genesis.Alloc {
+		if account.Code != nil && account.Constructor != nil {
+			fail("account %s has both code and a constructor", addr)
+		}
+		if name, ok := precompiles[addr]; ok && (account.Code != nil || account.Constructor != nil || len(account.Storage) != 0) {
+			fail("account %s has code or storage at the address of the %s precompiled contract", addr, name)
+		}
+	}
+
+	upgrades := map[string]struct{}{}
+	for _, upgrade := range c.Params.Upgrades {
+		if upgrade.Block == 0 {
+			fail("upgrade of %s: the genesis is not executed, the code must be set in the alloc", upgrade.Address)
+		}
+		if name, ok := precompiles[upgrade.Address]; ok {
+			fail("upgrade of %s: the address is taken by the %s precompiled contract", upgrade.Address, name)
+		}
+
+		key := fmt.Sprintf("%s:%d", upgrade.Address, upgrade.Block)
+		if _, ok := upgrades[key]; ok {
+			fail("upgrade of %s at block %d is set twice", upgrade.Address, upgrade.Block)
+		}
+		upgrades[key] = struct{}{}
+	}
+
+	for _, call := range c.Params.SystemCalls {
+		if call.Block == 0 {
+			fail("system call to %s: the genesis has no transactions, the call starts at block 1 or later", call.To)
+		}
+	}
+
+	for engine := range c.Params.Engine {
+		if check, ok := lookupEngineCheck(engine); ok {
+			engineErr := check(c)
+			if merr, ok := engineErr.(*multierror.Error); ok {
+				for _, issue := range merr.Errors {
+					fail("%s: %v", engine, issue)
+				}
+			} else if engineErr != nil {
+				fail("%s: %v", engine, engineErr)
+			}
+		}
+	}
+
+	return err
+}
+
+// validateForks checks that the forks are not activated before the forks they build on
+func (c *Chain) validateForks(fail func(format string, args ...interface{})) {
+	forks := c.Params.Forks
+
+	var last *namedFork
+	for _, named := range forks.named() {
+		named := named
+
+		fork := *named.fork
+		if fork == nil || named.fork == &forks.StateRent {
+			continue
+		}
+		if last != nil && *fork < **last.fork {
+			fail("the %s fork can't be activated before the %s fork", named.name, last.name)
+		}
+		last = &named
+	}
+
+	// the London fork builds on the access lists of the Berlin fork
+	if forks.London != nil && forks.Berlin == nil {
+		fail("the london fork can't be activated before the berlin fork")
+	}
+
+	if forks.StateRent != nil && c.Params.StateRent == nil {
+		fail("the state rent fork requires the state rent params")
+	}
+}
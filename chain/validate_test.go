@@ -0,0 +1,111 @@
+package chain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	// the bundled presets are valid
+	for _, name := range []string{"foundation", "ibft", "test", "local"} {
+		c, err := ImportFromName(name)
+		assert.NoError(t, err)
+		assert.NoError(t, c.Validate(), name)
+	}
+
+	valid := func() *Chain {
+		forks := *AllForksEnabled
+		return &Chain{
+			Genesis: &Genesis{Alloc: map[types.Address]*GenesisAccount{}},
+			Params: &Params{
+				ChainID: 100,
+				Forks:   &forks,
+				Engine:  map[string]interface{}{"dev": map[string]interface{}{}},
+			},
+		}
+	}
+
+	cases := map[string]func(c *Chain){
+		"chain id": func(c *Chain) {
+			c.Params.ChainID = 0
+		},
+		"two engines": func(c *Chain) {
+			c.Params.Engine["ibft"] = map[string]interface{}{}
+		},
+		"fork order": func(c *Chain) {
+			c.Params.Forks.Homestead = NewFork(10)
+		},
+		"london without berlin": func(c *Chain) {
+			c.Params.Forks.London = NewFork(0)
+		},
+		"state rent without params": func(c *Chain) {
+			c.Params.Forks.StateRent = NewFork(10)
+		},
+		"code at a precompile": func(c *Chain) {
+			c.Genesis.Alloc[types.StringToAddress("1")] = &GenesisAccount{Code: []byte{1}}
+		},
+		"storage at a custom precompile": func(c *Chain) {
+			c.Params.Precompiles = []*Precompile{{Name: "custom", Address: types.StringToAddress("100")}}
+			c.Genesis.Alloc[types.StringToAddress("100")] = &GenesisAccount{
+				Storage: map[types.Hash]types.Hash{{}: {1}},
+			}
+		},
+		"custom precompile at a built-in address": func(c *Chain) {
+			c.Params.Precompiles = []*Precompile{{Name: "custom", Address: types.StringToAddress("2")}}
+		},
+		"upgrade at the genesis": func(c *Chain) {
+			c.Params.Upgrades = []*ContractUpgrade{{Address: types.StringToAddress("100"), Code: []byte{1}}}
+		},
+		"upgrade set twice": func(c *Chain) {
+			c.Params.Upgrades = []*ContractUpgrade{
+				{Block: 10, Address: types.StringToAddress("100"), Code: []byte{1}},
+				{Block: 10, Address: types.StringToAddress("100"), Code: []byte{2}},
+			}
+		},
+		"system call at the genesis": func(c *Chain) {
+			c.Params.SystemCalls = []*SystemCall{{To: types.StringToAddress("100"), Gas: 100000}}
+		},
+	}
+
+	assert.NoError(t, valid().Validate())
+
+	for name, change := range cases {
+		c := valid()
+		change(c)
+		assert.Error(t, c.Validate(), name)
+	}
+
+	// the premines at the precompiled addresses are allowed
+	c := valid()
+	c.Genesis.Alloc[types.StringToAddress("1")] = &GenesisAccount{}
+	assert.NoError(t, c.Validate())
+}
+
+func TestValidate_EngineCheck(t *testing.T) {
+	RegisterEngineCheck("test-engine", func(c *Chain) error {
+		var err error
+		for i := 0; i < 2; i++ {
+			err = multierror.Append(err, fmt.Errorf("issue %d", i))
+		}
+		return err
+	})
+
+	forks := *AllForksEnabled
+	c := &Chain{
+		Genesis: &Genesis{},
+		Params: &Params{
+			ChainID: 100,
+			Forks:   &forks,
+			Engine:  map[string]interface{}{"test-engine": map[string]interface{}{}},
+		},
+	}
+
+	err := c.Validate()
+	assert.Error(t, err)
+	assert.Len(t, err.(*multierror.Error).Errors, 2)
+	assert.Contains(t, err.Error(), "test-engine: issue 1")
+}
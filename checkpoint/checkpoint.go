@@ -0,0 +1,419 @@
+package checkpoint
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/go-web3/abi"
+	"github.com/umbracle/go-web3/jsonrpc"
+)
+
+const (
+	// DefaultInterval is the default number of blocks between two checkpoints
+	DefaultInterval = 1000
+
+	// pollInterval is the interval the head of the chain and the pending checkpoint are checked at
+	pollInterval = 5 * time.Second
+
+	// pendingTimeout is the time a checkpoint transaction has to be included, before it is sent again
+	pendingTimeout = 5 * time.Minute
+)
+
+var (
+	errNotDeployed = errors.New("no checkpoint contract at the address")
+	errDiverged    = errors.New("the chain diverged from the checkpoint anchored on the external chain")
+)
+
+// Config is the contract on the external chain the checkpoints are submitted to
+type Config struct {
+	// URL is the JSON-RPC endpoint of the external chain
+	URL string
+
+	// Contract is the address of the checkpoint contract
+	Contract types.Address
+
+	// Interval is the number of blocks between two checkpoints
+	Interval uint64
+
+	// KeyPath is the file of the key of the account sending the checkpoints, generated if missing
+	KeyPath string
+}
+
+// Backend provides the finalized blocks of the chain
+type Backend interface {
+	Header() *types.Header
+	GetHeaderByNumber(number uint64) (*types.Header, bool)
+
+	// FinalitySignatures returns the signatures of the validators which finalized the block
+	FinalitySignatures(header *types.Header) ([][]byte, error)
+}
+
+// Checkpoint is a block anchored on the external chain
+type Checkpoint struct {
+	Number uint64
+	Hash   types.Hash
+}
+
+// pendingCheckpoint is a checkpoint sent to the external chain, not included yet
+type pendingCheckpoint struct {
+	Checkpoint
+	txHash types.Hash
+	sent   time.Time
+}
+
+// Service submits the finalized blocks at every interval to the checkpoint contract of an external chain,
+// along with the signatures of the validators. The contract anchors the chain, as in the L2 chains
+type Service struct {
+	logger  hclog.Logger
+	config  *Config
+	backend Backend
+	client  *jsonrpc.Client
+	key     *ecdsa.PrivateKey
+	signer  crypto.TxSigner
+
+	lock    sync.Mutex
+	latest  Checkpoint
+	pending *pendingCheckpoint
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewService returns the checkpointing service, it starts with Start
+func NewService(logger hclog.Logger, config *Config, backend Backend) (*Service, error) {
+	if config.Interval == 0 {
+		return nil, errors.New("the checkpoint interval must be positive")
+	}
+
+	key, err := crypto.GenerateOrReadPrivateKey(config.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the checkpoint key: %v", err)
+	}
+
+	client, err := jsonrpc.NewClient(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the checkpoint endpoint: %v", err)
+	}
+
+	return &Service{
+		logger:  logger.Named("checkpoint"),
+		config:  config,
+		backend: backend,
+		client:  client,
+		key:     key,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}, nil
+}
+
+// Address returns the account sending the checkpoints, it pays their fees on the external chain
+func (s *Service) Address() types.Address {
+	return crypto.PubKeyToAddress(&s.key.PublicKey)
+}
+
+// Latest returns the latest checkpoint included in the contract
+func (s *Service) Latest() Checkpoint {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.latest
+}
+
+// Start verifies the chain against the latest checkpoint of the contract, and starts the submissions.
+// It fails if the chain diverged from it
+func (s *Service) Start() error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	go s.run()
+
+	return nil
+}
+
+// setup reads the chain id of the external chain, and verifies the latest checkpoint
+func (s *Service) setup() error {
+	var chainID string
+	if err := s.client.Call("eth_chainId", &chainID); err != nil {
+		return fmt.Errorf("failed to get the chain id of the checkpoint endpoint: %v", err)
+	}
+	id, err := types.ParseUint64orHex(&chainID)
+	if err != nil {
+		return err
+	}
+	s.signer = crypto.NewEIP155Signer(id)
+
+	latest, err := s.latestCheckpoint()
+	if err != nil {
+		return err
+	}
+	if err := s.verify(latest); err != nil {
+		return err
+	}
+	s.latest = latest
+
+	s.logger.Info("checkpointing started",
+		"contract", s.config.Contract,
+		"sender", s.Address(),
+		"latest", latest.Number,
+	)
+
+	return nil
+}
+
+// Close stops the submissions
+func (s *Service) Close() {
+	close(s.closeCh)
+	<-s.doneCh
+
+	s.client.Close()
+}
+
+// verify checks the checkpoint is a block of the chain. The checkpoints ahead of the chain
+// are checked once synced
+func (s *Service) verify(checkpoint Checkpoint) error {
+	if checkpoint.Number == 0 {
+		return nil
+	}
+
+	header, ok := s.backend.GetHeaderByNumber(checkpoint.Number)
+	if !ok {
+		s.logger.Warn("the latest checkpoint is ahead of the chain", "number", checkpoint.Number)
+		return nil
+	}
+	if header.Hash != checkpoint.Hash {
+		return fmt.Errorf("%v: block %d is %s, anchored %s", errDiverged, checkpoint.Number, header.Hash, checkpoint.Hash)
+	}
+
+	s.logger.Info("chain verified against the latest checkpoint", "number", checkpoint.Number, "hash", checkpoint.Hash)
+
+	return nil
+}
+
+func (s *Service) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.update(); err != nil {
+			s.logger.Error("failed to update the checkpoint", "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// update follows the pending checkpoint, and submits the next one once its block is finalized
+func (s *Service) update() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.pending != nil {
+		done, err := s.checkPending()
+		if err != nil || !done {
+			return err
+		}
+	}
+
+	head := s.backend.Header().Number
+	next := head - head%s.config.Interval
+	if next == 0 || next <= s.latest.Number {
+		return nil
+	}
+
+	return s.submit(next)
+}
+
+// checkPending returns true once the pending checkpoint is included, or dropped
+func (s *Service) checkPending() (bool, error) {
+	var receipt *struct {
+		Status string `json:"status"`
+	}
+	if err := s.client.Call("eth_getTransactionReceipt", &receipt, s.pending.txHash.String()); err != nil {
+		return false, err
+	}
+
+	if receipt == nil {
+		if time.Since(s.pending.sent) < pendingTimeout {
+			return false, nil
+		}
+
+		s.logger.Warn("the checkpoint transaction was not included, sending it again", "tx", s.pending.txHash)
+		s.pending = nil
+
+		return true, nil
+	}
+
+	if receipt.Status == "0x1" {
+		s.latest = s.pending.Checkpoint
+		s.logger.Info("checkpoint included", "number", s.latest.Number, "hash", s.latest.Hash, "tx", s.pending.txHash)
+	} else {
+		s.logger.Error("the checkpoint transaction failed", "number", s.pending.Number, "tx", s.pending.txHash)
+	}
+	s.pending = nil
+
+	return true, nil
+}
+
+// submit sends the transaction of the checkpoint of the block
+func (s *Service) submit(number uint64) error {
+	header, ok := s.backend.GetHeaderByNumber(number)
+	if !ok {
+		return fmt.Errorf("block %d not found", number)
+	}
+
+	seals, err := s.backend.FinalitySignatures(header)
+	if err != nil {
+		return fmt.Errorf("failed to get the signatures of block %d: %v", number, err)
+	}
+	signatures := []byte{}
+	for _, seal := range seals {
+		signatures = append(signatures, seal...)
+	}
+
+	input, err := encodeSubmit(header, signatures)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.buildTx(input)
+	if err != nil {
+		return err
+	}
+	if tx, err = s.signer.SignTx(tx, s.key); err != nil {
+		return err
+	}
+
+	var txHash types.Hash
+	if err := s.client.Call("eth_sendRawTransaction", &txHash, hex.EncodeToHex(tx.MarshalRLP())); err != nil {
+		return fmt.Errorf("failed to send the checkpoint of block %d: %v", number, err)
+	}
+
+	s.pending = &pendingCheckpoint{
+		Checkpoint: Checkpoint{Number: number, Hash: header.Hash},
+		txHash:     txHash,
+		sent:       time.Now(),
+	}
+	s.logger.Info("checkpoint submitted", "number", number, "hash", header.Hash, "signatures", len(seals), "tx", txHash)
+
+	return nil
+}
+
+// buildTx fills in the nonce, the gas price and the gas of the call to the contract
+func (s *Service) buildTx(input []byte) (*types.Transaction, error) {
+	from := s.Address()
+
+	var nonce, gasPrice, gas string
+	if err := s.client.Call("eth_getTransactionCount", &nonce, from.String(), "pending"); err != nil {
+		return nil, err
+	}
+	if err := s.client.Call("eth_gasPrice", &gasPrice); err != nil {
+		return nil, err
+	}
+	msg := map[string]interface{}{
+		"from": from.String(),
+		"to":   s.config.Contract.String(),
+		"data": hex.EncodeToHex(input),
+	}
+	if err := s.client.Call("eth_estimateGas", &gas, msg); err != nil {
+		return nil, fmt.Errorf("the checkpoint would fail: %v", err)
+	}
+
+	tx := &types.Transaction{
+		From:     from,
+		To:       &s.config.Contract,
+		Input:    input,
+		Value:    big.NewInt(0),
+		GasPrice: new(big.Int),
+	}
+
+	var err error
+	if tx.Nonce, err = types.ParseUint64orHex(&nonce); err != nil {
+		return nil, err
+	}
+	if tx.Gas, err = types.ParseUint64orHex(&gas); err != nil {
+		return nil, err
+	}
+	if tx.GasPrice, err = types.ParseUint256orHex(&gasPrice); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// latestCheckpoint reads the latest checkpoint from the contract
+func (s *Service) latestCheckpoint() (Checkpoint, error) {
+	method := abis.CheckpointManagerABI.Methods["latestCheckpoint"]
+
+	msg := map[string]interface{}{
+		"to":   s.config.Contract.String(),
+		"data": hex.EncodeToHex(method.ID()),
+	}
+
+	var res string
+	if err := s.client.Call("eth_call", &res, msg, "latest"); err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to read the latest checkpoint: %v", err)
+	}
+	data, err := hex.DecodeHex(res)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if len(data) == 0 {
+		return Checkpoint{}, errNotDeployed
+	}
+
+	return decodeLatest(data)
+}
+
+// encodeSubmit encodes the call of submitCheckpoint(number, blockHash, signatures)
+func encodeSubmit(header *types.Header, signatures []byte) ([]byte, error) {
+	method := abis.CheckpointManagerABI.Methods["submitCheckpoint"]
+
+	args, err := abi.Encode([]interface{}{
+		new(big.Int).SetUint64(header.Number),
+		[32]byte(header.Hash),
+		signatures,
+	}, method.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(method.ID(), args...), nil
+}
+
+// decodeLatest decodes the checkpoint returned by latestCheckpoint()
+func decodeLatest(data []byte) (Checkpoint, error) {
+	method := abis.CheckpointManagerABI.Methods["latestCheckpoint"]
+
+	decoded, err := abi.Decode(method.Outputs, data)
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("invalid checkpoint: %v", err)
+	}
+	values := decoded.(map[string]interface{})
+
+	number, ok := values["number"].(*big.Int)
+	if !ok || !number.IsUint64() {
+		return Checkpoint{}, errors.New("invalid checkpoint number")
+	}
+	hash, ok := values["blockHash"].([32]byte)
+	if !ok {
+		return Checkpoint{}, errors.New("invalid checkpoint hash")
+	}
+
+	return Checkpoint{Number: number.Uint64(), Hash: types.Hash(hash)}, nil
+}
@@ -0,0 +1,200 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/go-web3/abi"
+)
+
+const externalChainID = 5
+
+type mockBackend struct {
+	headers []*types.Header
+}
+
+func newMockBackend(n uint64) *mockBackend {
+	b := &mockBackend{}
+	for i := uint64(0); i <= n; i++ {
+		b.headers = append(b.headers, &types.Header{Number: i, Hash: types.BytesToHash([]byte{byte(i + 1)})})
+	}
+	return b
+}
+
+func (b *mockBackend) Header() *types.Header {
+	return b.headers[len(b.headers)-1]
+}
+
+func (b *mockBackend) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(b.headers)) {
+		return nil, false
+	}
+	return b.headers[number], true
+}
+
+func (b *mockBackend) FinalitySignatures(header *types.Header) ([][]byte, error) {
+	return [][]byte{{0x1, 0x2}, {0x3, 0x4}}, nil
+}
+
+// mockExternalChain is the JSON-RPC endpoint of the external chain, with the checkpoint contract
+type mockExternalChain struct {
+	lock     sync.Mutex
+	latest   Checkpoint
+	sent     []*types.Transaction
+	included bool
+}
+
+func (m *mockExternalChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     interface{}       `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var result interface{}
+	switch req.Method {
+	case "eth_chainId":
+		result = "0x5"
+	case "eth_call":
+		method := abis.CheckpointManagerABI.Methods["latestCheckpoint"]
+		data, _ := abi.Encode(map[string]interface{}{
+			"number":    new(big.Int).SetUint64(m.latest.Number),
+			"blockHash": [32]byte(m.latest.Hash),
+		}, method.Outputs)
+		result = hex.EncodeToHex(data)
+	case "eth_getTransactionCount":
+		result = "0x3"
+	case "eth_gasPrice":
+		result = "0x10"
+	case "eth_estimateGas":
+		result = "0x30000"
+	case "eth_sendRawTransaction":
+		var raw string
+		_ = json.Unmarshal(req.Params[0], &raw)
+		tx := &types.Transaction{}
+		if err := tx.UnmarshalRLP(hex.MustDecodeHex(raw)); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.sent = append(m.sent, tx)
+		result = tx.Hash.String()
+	case "eth_getTransactionReceipt":
+		if m.included {
+			result = map[string]string{"status": "0x1"}
+		}
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+		"result":  result,
+	})
+}
+
+func newTestService(t *testing.T, chain *mockExternalChain, backend Backend) *Service {
+	t.Helper()
+
+	srv := httptest.NewServer(chain)
+	t.Cleanup(srv.Close)
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	service, err := NewService(hclog.NewNullLogger(), &Config{
+		URL:      srv.URL,
+		Contract: types.StringToAddress("100"),
+		Interval: 10,
+		KeyPath:  filepath.Join(dir, "checkpoint.key"),
+	}, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service
+}
+
+func TestCheckpointVerify(t *testing.T) {
+	backend := newMockBackend(25)
+
+	// the checkpoint of another chain
+	chain := &mockExternalChain{latest: Checkpoint{Number: 20, Hash: types.StringToHash("1")}}
+	assert.Error(t, newTestService(t, chain, backend).Start())
+
+	// the checkpoint ahead of the chain is verified once synced
+	chain = &mockExternalChain{latest: Checkpoint{Number: 30, Hash: types.StringToHash("1")}}
+	service := newTestService(t, chain, backend)
+	assert.NoError(t, service.Start())
+	service.Close()
+
+	chain = &mockExternalChain{latest: Checkpoint{Number: 20, Hash: backend.headers[20].Hash}}
+	service = newTestService(t, chain, backend)
+	assert.NoError(t, service.Start())
+	assert.Equal(t, uint64(20), service.Latest().Number)
+	service.Close()
+}
+
+func TestCheckpointSubmit(t *testing.T) {
+	backend := newMockBackend(25)
+	chain := &mockExternalChain{}
+
+	service := newTestService(t, chain, backend)
+	assert.NoError(t, service.setup())
+
+	// the last block of the interval is submitted
+	assert.NoError(t, service.update())
+	assert.Len(t, chain.sent, 1)
+
+	tx := chain.sent[0]
+	assert.Equal(t, uint64(3), tx.Nonce)
+	assert.Equal(t, uint64(0x30000), tx.Gas)
+	assert.Equal(t, big.NewInt(0x10), tx.GasPrice)
+
+	sender, err := crypto.NewEIP155Signer(externalChainID).Sender(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, service.Address(), sender)
+
+	method := abis.CheckpointManagerABI.Methods["submitCheckpoint"]
+	assert.Equal(t, method.ID(), tx.Input[:4])
+
+	args, err := abi.Decode(method.Inputs, tx.Input[4:])
+	assert.NoError(t, err)
+	values := args.(map[string]interface{})
+	assert.Equal(t, big.NewInt(20), values["number"])
+	assert.Equal(t, [32]byte(backend.headers[20].Hash), values["blockHash"])
+	assert.Equal(t, []byte{0x1, 0x2, 0x3, 0x4}, values["signatures"])
+
+	// the checkpoint isn't sent again while it is pending
+	assert.NoError(t, service.update())
+	assert.Len(t, chain.sent, 1)
+	assert.Equal(t, uint64(0), service.Latest().Number)
+
+	chain.included = true
+	assert.NoError(t, service.update())
+	assert.Len(t, chain.sent, 1)
+	assert.Equal(t, Checkpoint{Number: 20, Hash: backend.headers[20].Hash}, service.Latest())
+}
@@ -0,0 +1,26 @@
+package backup
+
+import "github.com/mitchellh/cli"
+
+// BackupCommand is the top level backup command
+type BackupCommand struct {
+}
+
+// Help implements the cli.Command interface
+func (c *BackupCommand) Help() string {
+	return c.Synopsis()
+}
+
+func (c *BackupCommand) GetBaseCommand() string {
+	return "backup"
+}
+
+// Synopsis implements the cli.Command interface
+func (c *BackupCommand) Synopsis() string {
+	return "Top level command for backing up and restoring the data stores of the node. Only accepts subcommands"
+}
+
+// Run implements the cli.Command interface
+func (c *BackupCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
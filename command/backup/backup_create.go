@@ -0,0 +1,179 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/helper/backup"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// BackupCreate is the command to back up the data stores of a running node into a file
+type BackupCreate struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *BackupCreate) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["file"] = helper.FlagDescriptor{
+		Description: "The file the backup is written to",
+		Arguments: []string{
+			"FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *BackupCreate) GetHelperText() string {
+	return "Writes a consistent backup of the blockchain, the state and the freezer of the running node into a file. " +
+		"The secrets are not in the backup, a node with encrypted data stores needs its storage key to read them"
+}
+
+func (c *BackupCreate) GetBaseCommand() string {
+	return "backup create"
+}
+
+// Help implements the cli.Command interface
+func (c *BackupCreate) Help() string {
+	c.Meta.DefineFlags()
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *BackupCreate) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *BackupCreate) Run(args []string) int {
+	flags := c.FlagSet(c.GetBaseCommand())
+
+	var file string
+
+	flags.StringVar(&file, "file", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if file == "" {
+		c.UI.Error("The file the backup is written to must be set")
+		return 1
+	}
+
+	conn, err := c.Conn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+
+	stream, err := clt.Backup(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	start := time.Now()
+
+	size, err := writeBackup(file, stream)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to back up the node: %v", err))
+		return 1
+	}
+
+	metadata, err := readMetadata(file)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to read the backup: %v", err))
+		return 1
+	}
+
+	c.UI.Output("\n[BACKUP CREATE]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", file),
+		fmt.Sprintf("Size|%d bytes", size),
+		fmt.Sprintf("Head Number|%d", metadata.HeadNumber),
+		fmt.Sprintf("Head Hash|%s", metadata.HeadHash),
+		fmt.Sprintf("Data Stores|%v", metadata.Stores),
+		fmt.Sprintf("Frozen Blocks|%d", metadata.Frozen),
+		fmt.Sprintf("Duration|%s", time.Since(start).Round(time.Millisecond)),
+	}))
+
+	return 0
+}
+
+// writeBackup writes the streamed backup into the file, which is removed if the stream fails
+func writeBackup(file string, stream proto.System_BackupClient) (int64, error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := func() (int64, error) {
+		buf := bufio.NewWriter(f)
+
+		var size int64
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return size, err
+			}
+
+			if _, err := buf.Write(chunk.Data); err != nil {
+				return size, err
+			}
+			size += int64(len(chunk.Data))
+		}
+
+		if err := buf.Flush(); err != nil {
+			return size, err
+		}
+
+		return size, f.Sync()
+	}()
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file)
+	}
+
+	return size, err
+}
+
+// readMetadata reads the metadata of the backup file
+func readMetadata(file string) (*backup.Metadata, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := backup.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Metadata, nil
+}
@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/helper/backup"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+)
+
+// BackupRestore is the command to restore a backup into the data directory of a stopped node
+type BackupRestore struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *BackupRestore) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["file"] = helper.FlagDescriptor{
+		Description: "The backup file",
+		Arguments: []string{
+			"FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["data-dir"] = helper.FlagDescriptor{
+		Description: "The data directory the data stores are restored to, it must not have them yet",
+		Arguments: []string{
+			"DATA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["db-backend"] = helper.FlagDescriptor{
+		Description: "The database backend of the restored data stores, leveldb or badger. Default: leveldb",
+		Arguments: []string{
+			"DB_BACKEND",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *BackupRestore) GetHelperText() string {
+	return "Restores the data stores and the freezer of a backup into the data directory of a stopped node, " +
+		"which then starts from the head of the backup. The secrets of the data directory are not changed"
+}
+
+func (c *BackupRestore) GetBaseCommand() string {
+	return "backup restore"
+}
+
+// Help implements the cli.Command interface
+func (c *BackupRestore) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *BackupRestore) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *BackupRestore) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var (
+		file    string
+		dataDir string
+		backend string
+	)
+
+	flags.StringVar(&file, "file", "", "")
+	flags.StringVar(&dataDir, "data-dir", "", "")
+	flags.StringVar(&backend, "db-backend", kvdb.BackendLevelDB, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if file == "" || dataDir == "" {
+		c.UI.Error("The backup file and the data directory must be set")
+		return 1
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer f.Close()
+
+	start := time.Now()
+
+	res, err := backup.Restore(bufio.NewReader(f), dataDir, backend)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to restore the backup: %v", err))
+		return 1
+	}
+
+	output := []string{
+		fmt.Sprintf("Data Directory|%s", dataDir),
+		fmt.Sprintf("Head Number|%d", res.Metadata.HeadNumber),
+		fmt.Sprintf("Head Hash|%s", res.Metadata.HeadHash),
+		fmt.Sprintf("Created|%s", res.Metadata.Created.Format(time.RFC3339)),
+	}
+	for _, store := range res.Metadata.Stores {
+		output = append(output, fmt.Sprintf("%s Entries|%d", store, res.Entries[store]))
+	}
+	output = append(output,
+		fmt.Sprintf("Files|%d", res.Files),
+		fmt.Sprintf("Duration|%s", time.Since(start).Round(time.Millisecond)),
+	)
+
+	c.UI.Output("\n[BACKUP RESTORE]\n")
+	c.UI.Output(helper.FormatKV(output))
+
+	return 0
+}
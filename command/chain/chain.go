@@ -0,0 +1,26 @@
+package chain
+
+import "github.com/mitchellh/cli"
+
+// ChainCommand is the top level chain command
+type ChainCommand struct {
+}
+
+// Help implements the cli.Command interface
+func (c *ChainCommand) Help() string {
+	return c.Synopsis()
+}
+
+func (c *ChainCommand) GetBaseCommand() string {
+	return "chain"
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainCommand) Synopsis() string {
+	return "Top level command for inspecting the local chain. Only accepts subcommands"
+}
+
+// Run implements the cli.Command interface
+func (c *ChainCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,181 @@
+package chain
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// ChainBadBlocks is the command to dump the bad blocks recorded by a stopped node
+type ChainBadBlocks struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ChainBadBlocks) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["data-dir"] = helper.FlagDescriptor{
+		Description: "The data directory of the node",
+		Arguments: []string{
+			"DATA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["out"] = helper.FlagDescriptor{
+		Description: "The JSON file the bad blocks are dumped to, with their RLP encoding and the call traces " +
+			"of their transactions. Default: only the summary is printed",
+		Arguments: []string{
+			"FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ChainBadBlocks) GetHelperText() string {
+	return "Lists the blocks a stopped node rejected because they failed their verification or their execution, " +
+		"and dumps them for the postmortems. The running nodes serve them with debug_getBadBlocks"
+}
+
+func (c *ChainBadBlocks) GetBaseCommand() string {
+	return "chain bad-blocks"
+}
+
+// Help implements the cli.Command interface
+func (c *ChainBadBlocks) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainBadBlocks) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// badBlockDump is the JSON dump of a bad block
+type badBlockDump struct {
+	Number uint64          `json:"number"`
+	Hash   types.Hash      `json:"hash"`
+	Time   uint64          `json:"time"`
+	Reason string          `json:"reason"`
+	RLP    string          `json:"rlp"`
+	Trace  json.RawMessage `json:"trace,omitempty"`
+}
+
+// Run implements the cli.Command interface
+func (c *ChainBadBlocks) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var dataDir, out string
+
+	flags.StringVar(&dataDir, "data-dir", "", "")
+	flags.StringVar(&out, "out", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if dataDir == "" {
+		c.UI.Error("The data directory of the node must be set")
+		return 1
+	}
+
+	badBlocks, err := readBadBlocks(dataDir)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to read the bad blocks: %v", err))
+		return 1
+	}
+
+	if out != "" {
+		if err := writeBadBlocks(out, badBlocks); err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to dump the bad blocks: %v", err))
+			return 1
+		}
+	}
+
+	rows := make([]string, len(badBlocks)+1)
+	if len(badBlocks) == 0 {
+		rows[0] = "No bad blocks found"
+	} else {
+		rows[0] = "NUMBER|HASH|TIME|REASON"
+		for i, bad := range badBlocks {
+			rows[i+1] = fmt.Sprintf(
+				"%d|%s|%s|%s",
+				bad.Block.Number(),
+				bad.Block.Hash(),
+				time.Unix(int64(bad.Time), 0).UTC().Format(time.RFC3339),
+				bad.Reason,
+			)
+		}
+	}
+
+	output := "\n[CHAIN BAD BLOCKS]\n"
+	output += helper.FormatList(rows)
+	output += "\n"
+
+	if out != "" {
+		output += fmt.Sprintf("\nDumped %d bad blocks to %s\n", len(badBlocks), out)
+	}
+
+	c.UI.Output(output)
+
+	return 0
+}
+
+// readBadBlocks reads the bad blocks recorded in the blockchain storage of the data directory
+func readBadBlocks(dataDir string) ([]*storage.BadBlock, error) {
+	path, err := common.StoreDir(dataDir, "blockchain")
+	if err != nil {
+		return nil, err
+	}
+
+	// the store can't be opened while the node runs, nor if it is encrypted
+	blockchainStorage, err := leveldb.NewLevelDBStorage(path, hclog.NewNullLogger())
+	if err != nil {
+		return nil, err
+	}
+	defer blockchainStorage.Close()
+
+	return blockchainStorage.ReadBadBlocks()
+}
+
+// writeBadBlocks writes the JSON dump of the bad blocks
+func writeBadBlocks(file string, badBlocks []*storage.BadBlock) error {
+	dump := make([]*badBlockDump, 0, len(badBlocks))
+	for _, bad := range badBlocks {
+		dump = append(dump, &badBlockDump{
+			Number: bad.Block.Number(),
+			Hash:   bad.Block.Hash(),
+			Time:   bad.Time,
+			Reason: bad.Reason,
+			RLP:    hex.EncodeToHex(bad.Block.MarshalRLP()),
+			Trace:  json.RawMessage(bad.Trace),
+		})
+	}
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0600)
+}
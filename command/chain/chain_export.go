@@ -0,0 +1,185 @@
+package chain
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+)
+
+// ChainExport is the command to export the canonical chain into an RLP file
+type ChainExport struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ChainExport) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["file"] = helper.FlagDescriptor{
+		Description: "The file the blocks are written to, it is gzipped if its name ends with .gz",
+		Arguments: []string{
+			"FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["from"] = helper.FlagDescriptor{
+		Description: "The first block exported. Default: 0",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["to"] = helper.FlagDescriptor{
+		Description: "The last block exported. Default: the head of the chain",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ChainExport) GetHelperText() string {
+	return "Exports the canonical blocks of the node into a file of RLP encoded blocks, the format of the geth exports"
+}
+
+func (c *ChainExport) GetBaseCommand() string {
+	return "chain export"
+}
+
+// Help implements the cli.Command interface
+func (c *ChainExport) Help() string {
+	c.Meta.DefineFlags()
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainExport) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ChainExport) Run(args []string) int {
+	flags := c.FlagSet(c.GetBaseCommand())
+
+	var (
+		file     string
+		from, to uint64
+	)
+
+	flags.StringVar(&file, "file", "", "")
+	flags.Uint64Var(&from, "from", 0, "")
+	flags.Uint64Var(&to, "to", 0, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if file == "" {
+		c.UI.Error("The file the blocks are written to must be set")
+		return 1
+	}
+	if to != 0 && to < from {
+		c.UI.Error("The last block must not be smaller than the first one")
+		return 1
+	}
+
+	conn, err := c.Conn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+
+	stream, err := clt.ExportBlocks(context.Background(), &proto.ExportBlocksRequest{
+		From: from,
+		To:   to,
+	})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	start := time.Now()
+
+	exported, err := writeExport(file, stream)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to export the blocks: %v", err))
+		return 1
+	}
+
+	c.UI.Output("\n[CHAIN EXPORT]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", file),
+		fmt.Sprintf("Blocks|%d", exported),
+		fmt.Sprintf("Duration|%s", time.Since(start).Round(time.Millisecond)),
+	}))
+
+	return 0
+}
+
+// writeExport writes the streamed blocks one after the other into the file
+func writeExport(file string, stream proto.System_ExportBlocksClient) (int, error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+
+	var w io.Writer = buf
+	if strings.HasSuffix(file, ".gz") {
+		w = gzip.NewWriter(buf)
+	}
+
+	exported := 0
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return exported, err
+		}
+
+		for _, blob := range res.Blocks {
+			if _, err := w.Write(blob); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+	}
+
+	// the gzip footer is only written once closed
+	if gz, ok := w.(*gzip.Writer); ok {
+		if err := gz.Close(); err != nil {
+			return exported, err
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return exported, err
+	}
+
+	return exported, f.Sync()
+}
@@ -0,0 +1,236 @@
+package chain
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+)
+
+const (
+	// importBatchSize is the size of the RLP encoded blocks sent at once to the node
+	importBatchSize = 1024 * 1024
+
+	// maxImportedBlockSize bounds the size of a block read from the file
+	maxImportedBlockSize = 32 * 1024 * 1024
+)
+
+// ChainImport is the command to import the blocks of an RLP file into the chain
+type ChainImport struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ChainImport) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["file"] = helper.FlagDescriptor{
+		Description: "The file of RLP encoded blocks, it is gunzipped if its name ends with .gz",
+		Arguments: []string{
+			"FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ChainImport) GetHelperText() string {
+	return "Imports a file of RLP encoded blocks, like the chain exports, into the node. The blocks are verified " +
+		"and executed, the blocks already in the chain are skipped"
+}
+
+func (c *ChainImport) GetBaseCommand() string {
+	return "chain import"
+}
+
+// Help implements the cli.Command interface
+func (c *ChainImport) Help() string {
+	c.Meta.DefineFlags()
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainImport) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ChainImport) Run(args []string) int {
+	flags := c.FlagSet(c.GetBaseCommand())
+
+	var file string
+
+	flags.StringVar(&file, "file", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if file == "" {
+		c.UI.Error("The file of the blocks must be set")
+		return 1
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(file, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to read the gzipped file: %v", err))
+			return 1
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	conn, err := c.Conn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+
+	stream, err := clt.ImportBlocks(context.Background())
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	start := time.Now()
+
+	if err := sendImport(bufio.NewReader(r), stream); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to read the blocks: %v", err))
+		return 1
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to import the blocks: %v", err))
+		return 1
+	}
+
+	c.UI.Output("\n[CHAIN IMPORT]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("Imported Blocks|%d", res.Imported),
+		fmt.Sprintf("Skipped Blocks|%d", res.Skipped),
+		fmt.Sprintf("Head Number|%d", res.HeadNumber),
+		fmt.Sprintf("Head Hash|%s", res.HeadHash),
+		fmt.Sprintf("Duration|%s", time.Since(start).Round(time.Millisecond)),
+	}))
+
+	return 0
+}
+
+// sendImport sends the blocks of the file to the node in batches
+func sendImport(r *bufio.Reader, stream proto.System_ImportBlocksClient) error {
+	batch := &proto.RLPBlocks{}
+	size := 0
+
+	for {
+		blob, err := readRLPList(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		batch.Blocks = append(batch.Blocks, blob)
+		size += len(blob)
+
+		if size >= importBatchSize {
+			if err := stream.Send(batch); err != nil {
+				return sendError(err)
+			}
+			batch = &proto.RLPBlocks{}
+			size = 0
+		}
+	}
+
+	if len(batch.Blocks) != 0 {
+		return sendError(stream.Send(batch))
+	}
+
+	return nil
+}
+
+// sendError drops the io.EOF of a stream stopped by the node, its error is received once the stream is closed
+func sendError(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+
+	return err
+}
+
+// readRLPList reads the next RLP list of the stream, with its prefix
+func readRLPList(r *bufio.Reader) ([]byte, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := []byte{kind}
+
+	var size uint64
+	switch {
+	case kind >= 0xc0 && kind <= 0xf7:
+		size = uint64(kind - 0xc0)
+
+	case kind > 0xf7:
+		lenOfSize := int(kind - 0xf7)
+		buf := make([]byte, 8)
+		if _, err := io.ReadFull(r, buf[8-lenOfSize:]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		prefix = append(prefix, buf[8-lenOfSize:]...)
+		size = binary.BigEndian.Uint64(buf)
+
+	default:
+		return nil, fmt.Errorf("expected an RLP list, found the prefix 0x%x", kind)
+	}
+
+	if size > maxImportedBlockSize {
+		return nil, fmt.Errorf("the RLP list of %d bytes is larger than the maximum block size", size)
+	}
+
+	blob := make([]byte, len(prefix)+int(size))
+	copy(blob, prefix)
+	if _, err := io.ReadFull(r, blob[len(prefix):]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	return blob, nil
+}
+
+// unexpectedEOF reports the end of the stream in the middle of a block as a truncated file
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,236 @@
+package chain
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+)
+
+// migratedStores are the data stores of a node, the missing ones are skipped
+var migratedStores = []string{"blockchain", "trie", "archive", "fork", "bridge"}
+
+// ChainMigrate is the command to copy the data stores of a stopped node into another database backend
+type ChainMigrate struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ChainMigrate) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["data-dir"] = helper.FlagDescriptor{
+		Description: "The data directory of the node",
+		Arguments: []string{
+			"DATA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["db-backend"] = helper.FlagDescriptor{
+		Description: "The database backend of the data directory, leveldb or badger. Default: leveldb",
+		Arguments: []string{
+			"DB_BACKEND",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["to-data-dir"] = helper.FlagDescriptor{
+		Description: "The new data directory the data stores are copied to",
+		Arguments: []string{
+			"DATA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["to-db-backend"] = helper.FlagDescriptor{
+		Description: "The database backend of the new data directory, leveldb or badger. Default: leveldb",
+		Arguments: []string{
+			"DB_BACKEND",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ChainMigrate) GetHelperText() string {
+	return "Copies the data stores and the freezer of a stopped node into a new data directory, using another " +
+		"database backend. The other files of the data directory, like the secrets, are not copied"
+}
+
+func (c *ChainMigrate) GetBaseCommand() string {
+	return "chain migrate"
+}
+
+// Help implements the cli.Command interface
+func (c *ChainMigrate) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainMigrate) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ChainMigrate) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var (
+		dataDir   string
+		backend   string
+		toDataDir string
+		toBackend string
+	)
+
+	flags.StringVar(&dataDir, "data-dir", "", "")
+	flags.StringVar(&backend, "db-backend", kvdb.BackendLevelDB, "")
+	flags.StringVar(&toDataDir, "to-data-dir", "", "")
+	flags.StringVar(&toBackend, "to-db-backend", kvdb.BackendLevelDB, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if dataDir == "" || toDataDir == "" {
+		c.UI.Error("The data directory and the new data directory must be set")
+		return 1
+	}
+	if filepath.Clean(dataDir) == filepath.Clean(toDataDir) {
+		c.UI.Error("The new data directory must be different from the data directory")
+		return 1
+	}
+
+	// the stores placed outside of the data directory are migrated into the new one
+	dirs, err := common.ReadStoreDirs(dataDir)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	output := []string{}
+	for _, store := range migratedStores {
+		count, err := migrateStore(
+			dirs.Dir(dataDir, store),
+			backend,
+			filepath.Join(toDataDir, store),
+			toBackend,
+		)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to migrate the %s store: %v", store, err))
+			return 1
+		}
+
+		output = append(output, fmt.Sprintf("%s Entries|%d", store, count))
+	}
+
+	// the freezer files don't depend on the database backend, they are copied as they are
+	files, err := copyFiles(dirs.Dir(dataDir, "ancient"), filepath.Join(toDataDir, "ancient"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.UI.Error(fmt.Sprintf("Failed to copy the freezer: %v", err))
+		return 1
+	}
+	if err == nil {
+		output = append(output, fmt.Sprintf("ancient Files|%d", files))
+	}
+
+	if len(output) == 0 {
+		c.UI.Error(fmt.Sprintf("No data store found in %s", dataDir))
+		return 1
+	}
+
+	c.UI.Output("\n[CHAIN MIGRATE]\n")
+	c.UI.Output(helper.FormatKV(output))
+
+	return 0
+}
+
+// migrateStore copies the entries of the store into the new store. The encrypted values are
+// copied as they are, they stay bound to their keys
+func migrateStore(path, backend, toPath, toBackend string) (uint64, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+
+	src, err := kvdb.Open(backend, path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	dst, err := kvdb.Open(toBackend, toPath)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	count, err := kvdb.Copy(dst, src)
+	if err != nil {
+		return count, err
+	}
+
+	return count, dst.Compact()
+}
+
+// copyFiles copies the files of the directory into the new directory
+func copyFiles(dir, toDir string) (int, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(toDir, 0755); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := copyFile(filepath.Join(dir, entry.Name()), filepath.Join(toDir, entry.Name())); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func copyFile(path, toPath string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(toPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
@@ -0,0 +1,194 @@
+package chain
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/server"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// ChainPrune is the command to prune the state of a stopped node
+type ChainPrune struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ChainPrune) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["data-dir"] = helper.FlagDescriptor{
+		Description: "The data directory of the node",
+		Arguments: []string{
+			"DATA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["pruning"] = helper.FlagDescriptor{
+		Description: "The pruning mode defining the states kept, full or light. Default: full",
+		Arguments: []string{
+			"PRUNING_MODE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["pruning-retention"] = helper.FlagDescriptor{
+		Description: "The number of recent blocks whose state is kept. Default: the retention of the pruning mode",
+		Arguments: []string{
+			"BLOCKS",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ChainPrune) GetHelperText() string {
+	return "Deletes the states that are not kept by the pruning mode from the data directory of a stopped node, " +
+		"and reclaims their disk space"
+}
+
+func (c *ChainPrune) GetBaseCommand() string {
+	return "chain prune"
+}
+
+// Help implements the cli.Command interface
+func (c *ChainPrune) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainPrune) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// canonicalHeaders reads the canonical headers from the blockchain storage
+type canonicalHeaders struct {
+	storage storage.Storage
+}
+
+func (c *canonicalHeaders) GetHeaderByNumber(n uint64) (*types.Header, bool) {
+	hash, ok := c.storage.ReadCanonicalHash(n)
+	if !ok {
+		return nil, false
+	}
+	header, err := c.storage.ReadHeader(hash)
+	if err != nil {
+		return nil, false
+	}
+
+	return header, true
+}
+
+// Run implements the cli.Command interface
+func (c *ChainPrune) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var (
+		dataDir   string
+		mode      string
+		retention uint64
+	)
+
+	flags.StringVar(&dataDir, "data-dir", "", "")
+	flags.StringVar(&mode, "pruning", itrie.PruningFull, "")
+	flags.Uint64Var(&retention, "pruning-retention", 0, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if dataDir == "" {
+		c.UI.Error("The data directory of the node must be set")
+		return 1
+	}
+
+	config, err := itrie.NewPruningConfig(mode)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if config == nil {
+		c.UI.Error("The archive mode does not prune any state")
+		return 1
+	}
+	if retention != 0 {
+		config.Retention = retention
+	}
+
+	res, err := pruneDataDir(dataDir, config)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to prune the state: %v", err))
+		return 1
+	}
+
+	c.UI.Output("\n[CHAIN PRUNE]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("Retained States|%d", res.Roots),
+		fmt.Sprintf("Kept Trie Nodes|%d", res.Kept),
+		fmt.Sprintf("Deleted Trie Nodes|%d", res.Deleted),
+		fmt.Sprintf("Duration|%s", res.Duration),
+	}))
+
+	return 0
+}
+
+// pruneDataDir prunes the state storage of the data directory, and compacts it
+func pruneDataDir(dataDir string, config *itrie.PruningConfig) (*itrie.PruneResult, error) {
+	logger := hclog.NewNullLogger()
+
+	dirs, err := common.ReadStoreDirs(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// the stores can't be opened while the node runs, nor if they are encrypted
+	blockchainStorage, err := leveldb.NewLevelDBStorage(dirs.Dir(dataDir, "blockchain"), logger)
+	if err != nil {
+		return nil, err
+	}
+	defer blockchainStorage.Close()
+
+	head, ok := blockchainStorage.ReadHeadNumber()
+	if !ok {
+		return nil, fmt.Errorf("the head of the chain was not found")
+	}
+
+	stateStorage, err := itrie.NewLevelDBStorage(dirs.Dir(dataDir, "trie"), logger)
+	if err != nil {
+		return nil, err
+	}
+	pruner, err := itrie.NewPruner(stateStorage, logger)
+	if err != nil {
+		stateStorage.Close()
+		return nil, err
+	}
+	defer pruner.Close()
+
+	roots := server.RetainedRoots(config, &canonicalHeaders{storage: blockchainStorage}, head)
+
+	res, err := pruner.Prune(roots)
+	if err != nil {
+		return nil, err
+	}
+	if err := pruner.Compact(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
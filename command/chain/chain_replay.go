@@ -0,0 +1,235 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+)
+
+// ChainReplay is the command to re-execute a range of blocks and verify the results
+type ChainReplay struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ChainReplay) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["from"] = helper.FlagDescriptor{
+		Description: "The first block of the range to replay",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["to"] = helper.FlagDescriptor{
+		Description: "The last block of the range to replay",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["parallelism"] = helper.FlagDescriptor{
+		Description: "The number of blocks executed concurrently. Default: number of CPUs of the node",
+		Arguments: []string{
+			"PARALLELISM",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["trace"] = helper.FlagDescriptor{
+		Description: "Traces the calls of the transactions of the blocks whose execution does not match (mismatches), " +
+			"or of all the replayed blocks (all), along with their receipts and the stored ones",
+		Arguments: []string{
+			"TRACE_MODE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["trace-dir"] = helper.FlagDescriptor{
+		Description: "The directory the traces are written to, one block-<number>.json file per block. " +
+			"Default: the traces are printed",
+		Arguments: []string{
+			"DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ChainReplay) GetHelperText() string {
+	return "Re-executes a range of blocks on top of the stored state and verifies the state roots and receipts"
+}
+
+func (c *ChainReplay) GetBaseCommand() string {
+	return "chain replay"
+}
+
+// Help implements the cli.Command interface
+func (c *ChainReplay) Help() string {
+	c.Meta.DefineFlags()
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ChainReplay) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ChainReplay) Run(args []string) int {
+	flags := c.FlagSet(c.GetBaseCommand())
+
+	var (
+		from, to, parallelism uint64
+		trace, traceDir       string
+	)
+
+	flags.Uint64Var(&from, "from", 0, "")
+	flags.Uint64Var(&to, "to", 0, "")
+	flags.Uint64Var(&parallelism, "parallelism", 0, "")
+	flags.StringVar(&trace, "trace", "", "")
+	flags.StringVar(&traceDir, "trace-dir", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if from == 0 {
+		c.UI.Error("The first block of the range must be larger than 0")
+		return 1
+	}
+	if to < from {
+		c.UI.Error("The last block of the range must not be smaller than the first one")
+		return 1
+	}
+
+	if trace != "" && trace != "mismatches" && trace != "all" {
+		c.UI.Error("The trace mode must be mismatches or all")
+		return 1
+	}
+	if traceDir != "" {
+		if trace == "" {
+			c.UI.Error("The trace directory requires a trace mode")
+			return 1
+		}
+		if err := os.MkdirAll(traceDir, 0755); err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to create the trace directory: %v", err))
+			return 1
+		}
+	}
+
+	conn, err := c.Conn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+
+	stream, err := clt.ReplayBlocks(context.Background(), &proto.ReplayBlocksRequest{
+		From:        from,
+		To:          to,
+		Parallelism: parallelism,
+		Trace:       trace,
+	})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	var (
+		replayed, mismatches, txns uint64
+		start                      = time.Now()
+	)
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to replay the blocks: %v", err))
+			return 1
+		}
+
+		replayed++
+		txns += res.Txns
+
+		if res.Error != "" {
+			mismatches++
+
+			c.UI.Error(helper.FormatKV([]string{
+				fmt.Sprintf("Block Number|%d", res.Number),
+				fmt.Sprintf("Block Hash|%s", res.Hash),
+				fmt.Sprintf("Error|%s", res.Error),
+			}))
+		}
+
+		if res.TraceError != "" {
+			c.UI.Error(fmt.Sprintf("Failed to trace the block %d: %s", res.Number, res.TraceError))
+		}
+		if len(res.Traces) != 0 {
+			if err := c.outputTraces(res, traceDir); err != nil {
+				c.UI.Error(err.Error())
+				return 1
+			}
+		}
+	}
+
+	c.UI.Output("\n[CHAIN REPLAY]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("Blocks|%d", replayed),
+		fmt.Sprintf("Transactions|%d", txns),
+		fmt.Sprintf("Mismatches|%d", mismatches),
+		fmt.Sprintf("Duration|%s", time.Since(start).Round(time.Millisecond)),
+	}))
+
+	if mismatches != 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// outputTraces writes the traces of the block to its file of the directory, or prints them
+func (c *ChainReplay) outputTraces(res *proto.ReplayBlockResult, dir string) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, res.Traces, "", "  "); err != nil {
+		return fmt.Errorf("failed to decode the traces of the block %d: %v", res.Number, err)
+	}
+
+	if dir == "" {
+		c.UI.Output(fmt.Sprintf("\n[TRACES %d]\n%s", res.Number, buf.String()))
+		return nil
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("block-%d.json", res.Number))
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write the traces of the block %d: %v", res.Number, err)
+	}
+
+	return nil
+}
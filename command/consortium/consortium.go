@@ -0,0 +1,26 @@
+package consortium
+
+import "github.com/mitchellh/cli"
+
+// ConsortiumCommand is the top level consortium command
+type ConsortiumCommand struct {
+}
+
+// Help implements the cli.Command interface
+func (c *ConsortiumCommand) Help() string {
+	return c.Synopsis()
+}
+
+func (c *ConsortiumCommand) GetBaseCommand() string {
+	return "consortium"
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ConsortiumCommand) Synopsis() string {
+	return "Top level command for managing the certificates of a permissioned network. Only accepts subcommands"
+}
+
+// Run implements the cli.Command interface
+func (c *ConsortiumCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
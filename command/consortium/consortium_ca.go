@@ -0,0 +1,169 @@
+package consortium
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/network"
+)
+
+const (
+	caCertFile = "consortium-ca.pem"
+	caKeyFile  = "consortium-ca.key"
+
+	defaultCAValidity   = 10 * 365 * 24 * time.Hour
+	defaultNodeValidity = 365 * 24 * time.Hour
+)
+
+// ConsortiumCA is the command to generate the CA of a permissioned network
+type ConsortiumCA struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ConsortiumCA) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["dir"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the directory the %s certificate and the %s key are written to", caCertFile, caKeyFile),
+		Arguments: []string{
+			"DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["name"] = helper.FlagDescriptor{
+		Description: "Sets the common name of the CA certificate. Default: consortium",
+		Arguments: []string{
+			"NAME",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["validity"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the validity of the CA certificate. Default: %s", defaultCAValidity),
+		Arguments: []string{
+			"DURATION",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ConsortiumCA) GetHelperText() string {
+	return "Generates the key and the certificate of the CA issuing the certificates of the consortium nodes"
+}
+
+func (c *ConsortiumCA) GetBaseCommand() string {
+	return "consortium ca"
+}
+
+// Help implements the cli.Command interface
+func (c *ConsortiumCA) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ConsortiumCA) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ConsortiumCA) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var dir, name string
+	var validity time.Duration
+
+	flags.StringVar(&dir, "dir", "", "")
+	flags.StringVar(&name, "name", "consortium", "")
+	flags.DurationVar(&validity, "validity", defaultCAValidity, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if dir == "" {
+		c.UI.Error("required argument (directory) not passed in")
+		return 1
+	}
+
+	certPath, keyPath := filepath.Join(dir, caCertFile), filepath.Join(dir, caKeyFile)
+	for _, path := range []string{certPath, keyPath} {
+		if _, err := os.Stat(path); err == nil {
+			c.UI.Error(fmt.Sprintf("%s already exists", path))
+			return 1
+		}
+	}
+
+	cert, key, err := network.GenerateConsortiumCA(name, validity)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	keyData, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := writePEM(keyPath, "EC PRIVATE KEY", keyData, 0600); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := writePEM(certPath, "CERTIFICATE", cert, 0644); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	output := "\n[CONSORTIUM CA]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Certificate|%s", certPath),
+		fmt.Sprintf("Key|%s", keyPath),
+		fmt.Sprintf("Valid until|%s", time.Now().Add(validity).Format(time.RFC3339)),
+	})
+	output += "\n"
+
+	c.UI.Output(output)
+
+	return 0
+}
+
+func writePEM(path, typ string, data []byte, perm os.FileMode) error {
+	return ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: data}), perm)
+}
+
+func readPEM(path, typ string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != typ {
+		return nil, fmt.Errorf("no %s found in %s", typ, path)
+	}
+
+	return block.Bytes, nil
+}
@@ -0,0 +1,157 @@
+package consortium
+
+import (
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// ConsortiumIssue is the command to issue the certificate of a node of a permissioned network
+type ConsortiumIssue struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ConsortiumIssue) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["ca-dir"] = helper.FlagDescriptor{
+		Description: "Sets the directory of the consortium CA generated by the consortium ca command",
+		Arguments: []string{
+			"CA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["node-id"] = helper.FlagDescriptor{
+		Description: "Sets the libp2p ID of the node the certificate is issued to",
+		Arguments: []string{
+			"NODE_ID",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["out"] = helper.FlagDescriptor{
+		Description: "Sets the path of the certificate file",
+		Arguments: []string{
+			"CERTIFICATE_FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["validity"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the validity of the certificate. Default: %s", defaultNodeValidity),
+		Arguments: []string{
+			"DURATION",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ConsortiumIssue) GetHelperText() string {
+	return "Issues the consortium certificate of a node, which admits the node to the permissioned network"
+}
+
+func (c *ConsortiumIssue) GetBaseCommand() string {
+	return "consortium issue"
+}
+
+// Help implements the cli.Command interface
+func (c *ConsortiumIssue) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ConsortiumIssue) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ConsortiumIssue) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var caDir, nodeID, out string
+	var validity time.Duration
+
+	flags.StringVar(&caDir, "ca-dir", "", "")
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&out, "out", "", "")
+	flags.DurationVar(&validity, "validity", defaultNodeValidity, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if caDir == "" || nodeID == "" || out == "" {
+		c.UI.Error("required arguments (CA directory, node ID and output file) not passed in")
+		return 1
+	}
+
+	id, err := peer.Decode(nodeID)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("invalid node ID: %v", err))
+		return 1
+	}
+
+	caData, err := readPEM(filepath.Join(caDir, caCertFile), "CERTIFICATE")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	ca, err := x509.ParseCertificate(caData)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	keyData, err := readPEM(filepath.Join(caDir, caKeyFile), "EC PRIVATE KEY")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	key, err := x509.ParseECPrivateKey(keyData)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	cert, err := network.IssueConsortiumCertificate(ca, key, id, validity)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := writePEM(out, "CERTIFICATE", cert, 0644); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	output := "\n[CONSORTIUM ISSUE]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Node ID|%s", id),
+		fmt.Sprintf("Certificate|%s", out),
+		fmt.Sprintf("Valid until|%s", time.Now().Add(validity).Format(time.RFC3339)),
+	})
+	output += "\n"
+
+	c.UI.Output(output)
+
+	return 0
+}
@@ -1,11 +1,14 @@
 package dev
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/consensus/dev"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/logging"
 	"github.com/0xPolygon/polygon-sdk/server"
-	"github.com/hashicorp/go-hclog"
 	"github.com/mitchellh/cli"
 )
 
@@ -24,13 +27,22 @@ func (d *DevCommand) DefineFlags() {
 	}
 
 	d.FlagMap["log-level"] = helper.FlagDescriptor{
-		Description: fmt.Sprintf("Sets the log level for console output. Default: %s", helper.DefaultConfig().LogLevel),
+		Description: fmt.Sprintf("Sets the log level for console output, optionally followed by the levels "+
+			"of the modules (e.g. info,ibft=debug,network=warn). Default: %s", helper.DefaultConfig().LogLevel),
 		Arguments: []string{
 			"LOG_LEVEL",
 		},
 		FlagOptional: true,
 	}
 
+	d.FlagMap["log-format"] = helper.FlagDescriptor{
+		Description: "Sets the format of the log output, text or json. Default: text",
+		Arguments: []string{
+			"LOG_FORMAT",
+		},
+		FlagOptional: true,
+	}
+
 	d.FlagMap["premine"] = helper.FlagDescriptor{
 		Description: fmt.Sprintf("Sets the premined accounts and balances. Default premined balance: %s", helper.DefaultPremineBalance),
 		Arguments: []string{
@@ -41,13 +53,50 @@ func (d *DevCommand) DefineFlags() {
 	}
 
 	d.FlagMap["dev-interval"] = helper.FlagDescriptor{
-		Description: "Sets the client's dev notification interval. Default: 0",
+		Description: "Sets the number of seconds the chain can be idle before an empty block is sealed. " +
+			"The transactions are sealed as they arrive regardless. Default: 0 (no empty blocks)",
 		Arguments: []string{
 			"DEV_INTERVAL",
 		},
 		FlagOptional: true,
 	}
 
+	d.FlagMap["dev-accounts"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the number of prefunded dev accounts, whose well-known keys are printed "+
+			"on start. Default: %d", helper.DefaultDevAccounts),
+		Arguments: []string{
+			"DEV_ACCOUNTS",
+		},
+		FlagOptional: true,
+	}
+
+	d.FlagMap["dev-catchup-rate"] = helper.FlagDescriptor{
+		Description: "Sets the number of blocks per second the missed dev interval blocks are back-filled at " +
+			"when the client resumes after a downtime. Default: 0 (no back-fill)",
+		Arguments: []string{
+			"CATCHUP_RATE",
+		},
+		FlagOptional: true,
+	}
+
+	d.FlagMap["fork"] = helper.FlagDescriptor{
+		Description: "Sets the JSON-RPC endpoint of a remote chain to fork from. The accounts and the storage " +
+			"missing from the dev chain are fetched from it, and cached in the data directory",
+		Arguments: []string{
+			"FORK_URL",
+		},
+		FlagOptional: true,
+	}
+
+	d.FlagMap["fork-block"] = helper.FlagDescriptor{
+		Description: "Sets the number of the remote block the state is forked at. It is pinned on the first run. " +
+			"Default: 0 (the latest block)",
+		Arguments: []string{
+			"FORK_BLOCK",
+		},
+		FlagOptional: true,
+	}
+
 	d.FlagMap["locals"] = helper.FlagDescriptor{
 		Description: "Sets comma separated accounts whose transactions are treated as locals",
 		Arguments: []string{
@@ -101,7 +150,7 @@ func (d *DevCommand) DefineFlags() {
 
 func (d *DevCommand) GetHelperText() string {
 	return "\"Bypasses\" consensus and networking and starts a blockchain locally. " +
-		"It starts a local node and mines every transaction in a separate block"
+		"It starts a local node, seals every transaction in a block as it arrives and prefunds the dev accounts"
 }
 
 // Help implements the cli.Command interface
@@ -122,7 +171,7 @@ func (d *DevCommand) GetBaseCommand() string {
 
 // Run implements the cli.Command interface
 func (d *DevCommand) Run(args []string) int {
-	conf, err := helper.BootstrapDevCommand(d.GetBaseCommand(), args)
+	conf, accounts, err := helper.BootstrapDevCommand(d.GetBaseCommand(), args)
 	if err != nil {
 		d.UI.Error(err.Error())
 
@@ -136,10 +185,11 @@ func (d *DevCommand) Run(args []string) int {
 		return 1
 	}
 
-	logger := hclog.New(&hclog.LoggerOptions{
-		Name:  "polygon-dev",
-		Level: hclog.LevelFromString(conf.LogLevel),
-	})
+	logger := logging.New("polygon-dev", conf.LogFormat, config.LogLevels)
+
+	if len(accounts) != 0 {
+		d.UI.Info(formatAccounts(accounts))
+	}
 
 	server, err := server.NewServer(logger, config)
 	if err != nil {
@@ -148,5 +198,24 @@ func (d *DevCommand) Run(args []string) int {
 		return 1
 	}
 
-	return helper.HandleSignals(server.Close, d.UI)
+	return helper.HandleSignalsOrShutdown(server.Close, server.ShutdownCh(), config.ShutdownTimeout+helper.DefaultCloseTimeout, d.UI)
+}
+
+// formatAccounts returns the addresses and the private keys of the prefunded dev accounts
+func formatAccounts(accounts []*dev.Account) string {
+	rows := []string{"#|Address|Private Key"}
+	for i, account := range accounts {
+		key, err := crypto.MarshalPrivateKey(account.Key)
+		if err != nil {
+			continue
+		}
+
+		rows = append(rows, fmt.Sprintf("%d|%s|0x%s", i, account.Address, hex.EncodeToString(key)))
+	}
+
+	output := "\n[DEV ACCOUNTS]\n"
+	output += helper.FormatList(rows)
+	output += "\n\nThe keys of the dev accounts are public, never use them outside of a local chain\n"
+
+	return output
 }
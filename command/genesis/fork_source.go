@@ -0,0 +1,273 @@
+package genesis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/go-web3/jsonrpc"
+)
+
+// forkPageSize is the number of accounts read at once from the forked chain,
+// the largest range of debug_accountRange
+const forkPageSize = 256
+
+// forkSource reads the state of the chain a genesis is forked from
+type forkSource interface {
+	// Header returns the header of the canonical block, the latest one if the number is nil
+	Header(number *uint64) (*types.Header, error)
+
+	// AccountRange returns up to max accounts of the state of the block, with their codes and storages
+	AccountRange(header *types.Header, start types.Hash, max int) (*state.AccountRange, error)
+
+	Close() error
+}
+
+// newForkSource returns the source of the JSON-RPC URL, or of the data directory of a stopped node
+func newForkSource(from string) (forkSource, error) {
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(from, scheme) {
+			return newRPCSource(from)
+		}
+	}
+
+	return newDataDirSource(from)
+}
+
+// forkAlloc returns the allocated accounts of the genesis, the whole state of the block.
+// Every address and storage key must be known, the hashed ones can't be allocated
+func forkAlloc(source forkSource, header *types.Header) (map[types.Address]*chain.GenesisAccount, error) {
+	alloc := map[types.Address]*chain.GenesisAccount{}
+
+	start := types.Hash{}
+	for {
+		rng, err := source.AccountRange(header, start, forkPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, account := range rng.Accounts {
+			if account.Address == nil {
+				return nil, fmt.Errorf("the address of the account %s is unknown", key)
+			}
+			if account.UnknownKeys != 0 {
+				return nil, fmt.Errorf("%d storage keys of the account %s are unknown", account.UnknownKeys, account.Address)
+			}
+
+			entry := &chain.GenesisAccount{
+				Balance: account.Balance,
+				Nonce:   account.Nonce,
+			}
+			if len(account.Code) != 0 {
+				entry.Code = account.Code
+			}
+			if len(account.Storage) != 0 {
+				entry.Storage = account.Storage
+			}
+			alloc[*account.Address] = entry
+		}
+
+		if rng.Next == nil {
+			return alloc, nil
+		}
+		start = *rng.Next
+	}
+}
+
+// dataDirSource reads the stores of the data directory, which can't be opened while the node runs
+type dataDirSource struct {
+	blockchain storage.Storage
+	state      itrie.Storage
+	executor   *state.Executor
+}
+
+func newDataDirSource(dataDir string) (*dataDirSource, error) {
+	logger := hclog.NewNullLogger()
+
+	dirs, err := common.ReadStoreDirs(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	blockchainStorage, err := leveldb.NewLevelDBStorage(dirs.Dir(dataDir, "blockchain"), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStorage, err := itrie.NewLevelDBStorage(dirs.Dir(dataDir, "trie"), logger)
+	if err != nil {
+		blockchainStorage.Close()
+		return nil, err
+	}
+
+	return &dataDirSource{
+		blockchain: blockchainStorage,
+		state:      stateStorage,
+		executor:   state.NewExecutor(&chain.Params{}, itrie.NewState(stateStorage), logger),
+	}, nil
+}
+
+func (d *dataDirSource) Header(number *uint64) (*types.Header, error) {
+	var num uint64
+	if number != nil {
+		num = *number
+	} else {
+		head, ok := d.blockchain.ReadHeadNumber()
+		if !ok {
+			return nil, fmt.Errorf("the head of the chain was not found")
+		}
+		num = head
+	}
+
+	hash, ok := d.blockchain.ReadCanonicalHash(num)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	return d.blockchain.ReadHeader(hash)
+}
+
+func (d *dataDirSource) AccountRange(header *types.Header, start types.Hash, max int) (*state.AccountRange, error) {
+	return d.executor.AccountRange(header.StateRoot, start, max, true, true)
+}
+
+func (d *dataDirSource) Close() error {
+	if err := d.state.Close(); err != nil {
+		d.blockchain.Close()
+		return err
+	}
+
+	return d.blockchain.Close()
+}
+
+// rpcSource pages the state of the block with debug_accountRange
+type rpcSource struct {
+	client *jsonrpc.Client
+}
+
+func newRPCSource(url string) (*rpcSource, error) {
+	client, err := jsonrpc.NewClient(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpcSource{client: client}, nil
+}
+
+type rpcHeader struct {
+	Number    string     `json:"number"`
+	Hash      types.Hash `json:"hash"`
+	StateRoot types.Hash `json:"stateRoot"`
+	GasLimit  string     `json:"gasLimit"`
+	Timestamp string     `json:"timestamp"`
+}
+
+func (r *rpcSource) Header(number *uint64) (*types.Header, error) {
+	block := "latest"
+	if number != nil {
+		block = fmt.Sprintf("0x%x", *number)
+	}
+
+	var res *rpcHeader
+	if err := r.client.Call("eth_getBlockByNumber", &res, block, false); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("block %s not found", block)
+	}
+
+	header := &types.Header{
+		Hash:      res.Hash,
+		StateRoot: res.StateRoot,
+	}
+
+	var err error
+	if header.Number, err = types.ParseUint64orHex(&res.Number); err != nil {
+		return nil, err
+	}
+	if header.GasLimit, err = types.ParseUint64orHex(&res.GasLimit); err != nil {
+		return nil, err
+	}
+	if header.Timestamp, err = types.ParseUint64orHex(&res.Timestamp); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+type rpcDumpAccount struct {
+	Balance     string                    `json:"balance"`
+	Nonce       string                    `json:"nonce"`
+	Code        string                    `json:"code"`
+	Storage     map[types.Hash]types.Hash `json:"storage"`
+	Address     *types.Address            `json:"address"`
+	Key         types.Hash                `json:"key"`
+	UnknownKeys *string                   `json:"unknownKeys"`
+}
+
+type rpcDump struct {
+	Accounts map[string]*rpcDumpAccount `json:"accounts"`
+	Next     *types.Hash                `json:"next"`
+}
+
+func (r *rpcSource) AccountRange(header *types.Header, start types.Hash, max int) (*state.AccountRange, error) {
+	var res rpcDump
+	if err := r.client.Call(
+		"debug_accountRange",
+		&res,
+		fmt.Sprintf("0x%x", header.Number),
+		start,
+		fmt.Sprintf("0x%x", max),
+		false,
+		false,
+	); err != nil {
+		return nil, err
+	}
+
+	rng := &state.AccountRange{
+		Accounts: make(map[types.Hash]*state.DumpAccount, len(res.Accounts)),
+		Next:     res.Next,
+	}
+	for _, account := range res.Accounts {
+		dump := &state.DumpAccount{
+			Address: account.Address,
+			Storage: account.Storage,
+		}
+
+		var err error
+		if dump.Balance, err = types.ParseUint256orHex(&account.Balance); err != nil {
+			return nil, err
+		}
+		if dump.Nonce, err = types.ParseUint64orHex(&account.Nonce); err != nil {
+			return nil, err
+		}
+		if account.Code != "" {
+			if dump.Code, err = hex.DecodeHex(account.Code); err != nil {
+				return nil, err
+			}
+		}
+		if account.UnknownKeys != nil {
+			unknown, err := types.ParseUint64orHex(account.UnknownKeys)
+			if err != nil {
+				return nil, err
+			}
+			dump.UnknownKeys = int(unknown)
+		}
+
+		rng.Accounts[account.Key] = dump
+	}
+
+	return rng, nil
+}
+
+func (r *rpcSource) Close() error {
+	return r.client.Close()
+}
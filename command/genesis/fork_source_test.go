@@ -0,0 +1,125 @@
+package genesis
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeDataDir writes a chain whose head has the state of the accounts
+func writeDataDir(t *testing.T, dataDir string, alloc map[types.Address]*chain.GenesisAccount) *types.Header {
+	logger := hclog.NewNullLogger()
+
+	stateStorage, err := itrie.NewLevelDBStorage(filepath.Join(dataDir, "trie"), logger)
+	assert.NoError(t, err)
+	defer stateStorage.Close()
+
+	executor := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, itrie.NewState(stateStorage), logger)
+	root, err := executor.WriteGenesis(alloc)
+	assert.NoError(t, err)
+
+	blockchainStorage, err := leveldb.NewLevelDBStorage(filepath.Join(dataDir, "blockchain"), logger)
+	assert.NoError(t, err)
+	defer blockchainStorage.Close()
+
+	header := &types.Header{Number: 5, StateRoot: root, GasLimit: 1000000, Timestamp: 100}
+	header.ComputeHash()
+
+	assert.NoError(t, blockchainStorage.WriteHeader(header))
+	assert.NoError(t, blockchainStorage.WriteCanonicalHash(header.Number, header.Hash))
+	assert.NoError(t, blockchainStorage.WriteHeadNumber(header.Number))
+
+	return header
+}
+
+func TestForkAllocDataDir(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "genesis-fork")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+
+	alloc := map[types.Address]*chain.GenesisAccount{
+		types.StringToAddress("1"): {Balance: big.NewInt(10), Nonce: 2},
+		types.StringToAddress("2"): {
+			Balance: big.NewInt(20),
+			Code:    []byte{0x60, 0x00},
+			Storage: map[types.Hash]types.Hash{types.StringToHash("1"): types.StringToHash("2")},
+		},
+	}
+	for i := 0; i < 2*forkPageSize; i++ {
+		alloc[types.BytesToAddress([]byte{byte(i >> 8), byte(i), 3})] = &chain.GenesisAccount{Balance: big.NewInt(int64(i + 1))}
+	}
+	written := writeDataDir(t, dataDir, alloc)
+
+	source, err := newForkSource(dataDir)
+	assert.NoError(t, err)
+	defer source.Close()
+
+	missing := uint64(6)
+	_, err = source.Header(&missing)
+	assert.Error(t, err)
+
+	header, err := source.Header(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, written.Hash, header.Hash)
+
+	// the state is allocated over several pages
+	forked, err := forkAlloc(source, header)
+	assert.NoError(t, err)
+	assert.Len(t, forked, len(alloc))
+
+	for addr, account := range alloc {
+		assert.Zero(t, account.Balance.Cmp(forked[addr].Balance))
+		assert.Equal(t, account.Nonce, forked[addr].Nonce)
+		assert.Equal(t, account.Code, forked[addr].Code)
+		assert.Equal(t, account.Storage, forked[addr].Storage)
+	}
+}
+
+// mockForkSource has a single page of accounts
+type mockForkSource struct {
+	accounts map[types.Hash]*state.DumpAccount
+}
+
+func (m *mockForkSource) Header(number *uint64) (*types.Header, error) {
+	return &types.Header{}, nil
+}
+
+func (m *mockForkSource) AccountRange(header *types.Header, start types.Hash, max int) (*state.AccountRange, error) {
+	return &state.AccountRange{Accounts: m.accounts}, nil
+}
+
+func (m *mockForkSource) Close() error {
+	return nil
+}
+
+func TestForkAllocUnknownKeys(t *testing.T) {
+	addr := types.StringToAddress("1")
+	source := &mockForkSource{
+		accounts: map[types.Hash]*state.DumpAccount{
+			types.StringToHash("1"): {Address: &addr, Balance: big.NewInt(1)},
+		},
+	}
+
+	alloc, err := forkAlloc(source, &types.Header{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[types.Address]*chain.GenesisAccount{addr: {Balance: big.NewInt(1)}}, alloc)
+
+	// the hashed storage keys and addresses can't be allocated
+	source.accounts[types.StringToHash("1")].UnknownKeys = 1
+	_, err = forkAlloc(source, &types.Header{})
+	assert.Error(t, err)
+
+	source.accounts[types.StringToHash("1")] = &state.DumpAccount{Balance: big.NewInt(1)}
+	_, err = forkAlloc(source, &types.Header{})
+	assert.Error(t, err)
+}
@@ -81,7 +81,8 @@ func (c *GenesisCommand) DefineFlags() {
 	}
 
 	c.FlagMap["bootnode"] = helper.FlagDescriptor{
-		Description: "Multiaddr URL for p2p discovery bootstrap. This flag can be used multiple times.",
+		Description: "Multiaddr URL for p2p discovery bootstrap, or the enrtree:// URL of a DNS tree serving bootnodes. " +
+			"This flag can be used multiple times.",
 		Arguments: []string{
 			"BOOTNODE_URL",
 		},
@@ -115,6 +116,24 @@ func (c *GenesisCommand) DefineFlags() {
 		ArgumentsOptional: false,
 		FlagOptional:      true,
 	}
+
+	c.FlagMap["berlin"] = helper.FlagDescriptor{
+		Description: "Sets the block the Berlin fork (access lists, warm and cold state access) is activated at. Default: not activated",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["london"] = helper.FlagDescriptor{
+		Description: "Sets the block the London fork (base fee, reduced refunds) is activated at. Requires the Berlin fork. Default: not activated",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
 }
 
 // GetHelperText returns a simple description of the command
@@ -156,6 +175,8 @@ func (c *GenesisCommand) Run(args []string) int {
 
 	var blockGasLimit uint64
 
+	var berlin, london string
+
 	flags.StringVar(&baseDir, "dir", "", "")
 	flags.StringVar(&name, "name", helper.DefaultChainName, "")
 	flags.Var(&premine, "premine", "")
@@ -165,6 +186,8 @@ func (c *GenesisCommand) Run(args []string) int {
 	flags.Var(&ibftValidators, "ibft-validator", "list of ibft validators")
 	flags.StringVar(&ibftValidatorsPrefixPath, "ibft-validators-prefix-path", "", "")
 	flags.Uint64Var(&blockGasLimit, "block-gas-limit", helper.GenesisGasLimit, "")
+	flags.StringVar(&berlin, "berlin", "", "")
+	flags.StringVar(&london, "london", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		c.UI.Error(fmt.Sprintf("failed to parse args: %v", err))
@@ -178,7 +201,18 @@ func (c *GenesisCommand) Run(args []string) int {
 		return 1
 	}
 
-	var extraData []byte
+	forks, err := buildForks(berlin, london)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	builder := chain.NewGenesisBuilder(name).
+		ChainID(int(chainID)).
+		Consensus(consensus, nil).
+		Forks(forks).
+		GasLimit(blockGasLimit).
+		Bootnodes(bootnodes...)
 
 	if consensus == "ibft" {
 		// we either use validatorsFlags or ibftValidatorsPrefixPath to set the validators
@@ -200,33 +234,15 @@ func (c *GenesisCommand) Run(args []string) int {
 		}
 
 		// create the initial extra data with the validators
-		ibftExtra := &ibft.IstanbulExtra{
-			Validators:    validators,
-			Seal:          []byte{},
-			CommittedSeal: [][]byte{},
-		}
-		extraData = make([]byte, ibft.IstanbulExtraVanity)
-		extraData = ibftExtra.MarshalRLPTo(extraData)
+		builder.ExtraData(ibft.GenesisExtraData(validators))
 	}
 
-	cc := &chain.Chain{
-		Name: name,
-		Genesis: &chain.Genesis{
-			GasLimit:   blockGasLimit,
-			Difficulty: 1,
-			Alloc:      map[types.Address]*chain.GenesisAccount{},
-			ExtraData:  extraData,
-			GasUsed:    helper.GenesisGasUsed,
-		},
-		Params: &chain.Params{
-			ChainID: int(chainID),
-			Forks:   chain.AllForksEnabled,
-			Engine: map[string]interface{}{
-				consensus: map[string]interface{}{},
-			},
-		},
-		Bootnodes: bootnodes,
+	cc, err := builder.Build()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
 	}
+	cc.Genesis.GasUsed = helper.GenesisGasUsed
 
 	if err = helper.FillPremineMap(cc.Genesis.Alloc, premine); err != nil {
 		c.UI.Error(err.Error())
@@ -280,3 +296,34 @@ func readValidatorsByRegexp(prefix string) ([]types.Address, error) {
 
 	return validators, nil
 }
+
+// buildForks returns the forks of the genesis, all the forks before Berlin are active from the genesis
+func buildForks(berlin, london string) (*chain.Forks, error) {
+	forks := *chain.AllForksEnabled
+
+	parseFork := func(name, value string) (*chain.Fork, error) {
+		if value == "" {
+			return nil, nil
+		}
+		num, err := types.ParseUint64orHex(&value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s fork block: %v", name, err)
+		}
+		return chain.NewFork(num), nil
+	}
+
+	var err error
+	if forks.Berlin, err = parseFork("berlin", berlin); err != nil {
+		return nil, err
+	}
+	if forks.London, err = parseFork("london", london); err != nil {
+		return nil, err
+	}
+
+	// the London fork builds on the access lists of the Berlin fork
+	if forks.London != nil && (forks.Berlin == nil || *forks.London < *forks.Berlin) {
+		return nil, fmt.Errorf("the london fork can't be activated before the berlin fork")
+	}
+
+	return &forks, nil
+}
@@ -0,0 +1,190 @@
+package genesis
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+const (
+	// defaultForkGenesisPath is the default file the forked genesis is written to
+	defaultForkGenesisPath = "genesis-fork.json"
+)
+
+// GenesisFork is the command to generate a genesis whose state is the state of an existing chain at a block
+type GenesisFork struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *GenesisFork) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["from"] = helper.FlagDescriptor{
+		Description: "The JSON-RPC URL of a node of the chain, serving debug_accountRange, " +
+			"or the data directory of a stopped node",
+		Arguments: []string{
+			"URL_OR_DATA_DIRECTORY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	c.FlagMap["block"] = helper.FlagDescriptor{
+		Description: "The number of the block whose state is allocated. Default: the latest block",
+		Arguments: []string{
+			"BLOCK_NUMBER",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["chain"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("The genesis file or the name of the preset of the chain, "+
+			"its parameters are kept by the new genesis. Default: %s", helper.GenesisFileName),
+		Arguments: []string{
+			"GENESIS_FILE_OR_PRESET",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["dir"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("The file the new genesis is written to. Default: %s", defaultForkGenesisPath),
+		Arguments: []string{
+			"GENESIS_FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	c.FlagMap["name"] = helper.FlagDescriptor{
+		Description: "Sets the name of the new chain. Default: the name of the forked chain",
+		Arguments: []string{
+			"NAME",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *GenesisFork) GetHelperText() string {
+	return "Generates a genesis file whose allocated accounts are the whole state of an existing chain at a block, " +
+		"to migrate the chain or to restart it with new parameters"
+}
+
+func (c *GenesisFork) GetBaseCommand() string {
+	return "genesis fork"
+}
+
+// Help implements the cli.Command interface
+func (c *GenesisFork) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *GenesisFork) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *GenesisFork) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var (
+		from        string
+		block       string
+		chainName   string
+		genesisPath string
+		name        string
+	)
+
+	flags.StringVar(&from, "from", "", "")
+	flags.StringVar(&block, "block", "", "")
+	flags.StringVar(&chainName, "chain", helper.GenesisFileName, "")
+	flags.StringVar(&genesisPath, "dir", defaultForkGenesisPath, "")
+	flags.StringVar(&name, "name", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if from == "" {
+		c.UI.Error("The node or the data directory the chain is forked from must be set")
+		return 1
+	}
+
+	var number *uint64
+	if block != "" {
+		num, err := types.ParseUint64orHex(&block)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid block number %q: %v", block, err))
+			return 1
+		}
+		number = &num
+	}
+
+	if generateError := helper.VerifyGenesisExistence(genesisPath); generateError != nil {
+		c.UI.Error(generateError.GetMessage())
+		return 1
+	}
+
+	cc, err := chain.Import(chainName)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load the genesis: %v", err))
+		return 1
+	}
+
+	source, err := newForkSource(from)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to open %s: %v", from, err))
+		return 1
+	}
+	defer source.Close()
+
+	header, err := source.Header(number)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to get the forked block: %v", err))
+		return 1
+	}
+
+	alloc, err := forkAlloc(source, header)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to read the state of the block %d: %v", header.Number, err))
+		return 1
+	}
+
+	// the new chain starts from the state, with the gas limit and the time of the block
+	if name != "" {
+		cc.Name = name
+	}
+	cc.Genesis.Alloc = alloc
+	cc.Genesis.GasLimit = header.GasLimit
+	cc.Genesis.Timestamp = header.Timestamp
+
+	if err := helper.WriteGenesisToDisk(cc, genesisPath); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output("\n[GENESIS FORK]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("Forked Block|%d", header.Number),
+		fmt.Sprintf("Block Hash|%s", header.Hash),
+		fmt.Sprintf("State Root|%s", header.StateRoot),
+		fmt.Sprintf("Allocated Accounts|%d", len(alloc)),
+		fmt.Sprintf("Genesis|%s", genesisPath),
+	}))
+
+	return 0
+}
@@ -0,0 +1,94 @@
+package genesis
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/hashicorp/go-multierror"
+)
+
+// GenesisValidate is the command to check a genesis file before launching a network
+type GenesisValidate struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *GenesisValidate) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["chain"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("The genesis file or the name of the preset to check. Default: %s", helper.GenesisFileName),
+		Arguments: []string{
+			"GENESIS_FILE_OR_PRESET",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *GenesisValidate) GetHelperText() string {
+	return "Checks a genesis file for inconsistencies, like the order of the forks, " +
+		"the accounts allocated at the system addresses or the validator set of the consensus"
+}
+
+func (c *GenesisValidate) GetBaseCommand() string {
+	return "genesis validate"
+}
+
+// Help implements the cli.Command interface
+func (c *GenesisValidate) Help() string {
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *GenesisValidate) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *GenesisValidate) Run(args []string) int {
+	flags := flag.NewFlagSet(c.GetBaseCommand(), flag.ContinueOnError)
+
+	var chainName string
+	flags.StringVar(&chainName, "chain", helper.GenesisFileName, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	cc, err := chain.Import(chainName)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load the genesis: %v", err))
+		return 1
+	}
+
+	if err := cc.Validate(); err != nil {
+		c.UI.Output("\n[GENESIS INVALID]\n")
+		if merr, ok := err.(*multierror.Error); ok {
+			for _, issue := range merr.Errors {
+				c.UI.Output(fmt.Sprintf("- %v", issue))
+			}
+		} else {
+			c.UI.Output(fmt.Sprintf("- %v", err))
+		}
+		return 1
+	}
+
+	c.UI.Output("\n[GENESIS VALID]\n")
+	c.UI.Output(helper.FormatKV([]string{
+		fmt.Sprintf("Name|%s", cc.Name),
+		fmt.Sprintf("Chain ID|%d", cc.Params.ChainID),
+		fmt.Sprintf("Allocated Accounts|%d", len(cc.Genesis.Alloc)),
+	}))
+
+	return 0
+}
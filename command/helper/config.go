@@ -6,12 +6,28 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/bridge"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/checkpoint"
 	helperFlags "github.com/0xPolygon/polygon-sdk/helper/flags"
+	"github.com/0xPolygon/polygon-sdk/ethstats"
+	"github.com/0xPolygon/polygon-sdk/exporter"
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/helper/logging"
+	"github.com/0xPolygon/polygon-sdk/helper/tracing"
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
+	"github.com/0xPolygon/polygon-sdk/network"
 	"github.com/0xPolygon/polygon-sdk/secrets"
 	"github.com/0xPolygon/polygon-sdk/server"
+	"github.com/0xPolygon/polygon-sdk/state/fork"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/hcl"
 	"github.com/imdario/mergo"
@@ -24,21 +40,131 @@ type Config struct {
 	BlockGasTarget string                        `json:"block_gas_target"`
 	GRPCAddr       string                        `json:"rpc_addr"`
 	JSONRPCAddr    string                        `json:"jsonrpc_addr"`
+	JSONRPC        *JSONRPC                      `json:"jsonrpc"`
+	GRPCAuth       *GRPCAuth                     `json:"grpc_auth"`
 	Telemetry      *Telemetry                    `json:"telemetry"`
 	Network        *Network                      `json:"network"`
 	SecretsManager *secrets.SecretsManagerConfig `json:"secrets_manager"`
 	Seal           bool                          `json:"seal"`
 	TxPool         *TxPool                       `json:"tx_pool"`
 	LogLevel       string                        `json:"log_level"`
+	LogFormat      string                        `json:"log_format"`
 	Consensus      map[string]interface{}        `json:"consensus"`
 	Dev            bool
 	DevInterval    uint64
+	DevCatchupRate uint64
 	Join           string
+
+	// Fork is the JSON-RPC endpoint of the remote chain the dev chain is forked from, at the block ForkBlock
+	Fork      string `json:"fork"`
+	ForkBlock uint64 `json:"fork_block"`
+
+	StorageEncryption bool   `json:"storage_encryption"`
+	DBBackend         string `json:"db_backend"`
+	FreezerThreshold  uint64 `json:"freezer_threshold"`
+
+	// TrieDir, ChainDir and AncientDir place the state trie, the chain and the freezer stores
+	// outside of the data directory, on other volumes
+	TrieDir    string `json:"trie_dir"`
+	ChainDir   string `json:"chain_dir"`
+	AncientDir string `json:"ancient_dir"`
+
+	MaxReorgDepth     uint64 `json:"max_reorg_depth"`
+	ExecParallelism   uint64 `json:"exec_parallelism"`
+	EVMProfiler       bool   `json:"evm_profiler"`
+	Cache             uint64 `json:"cache"`
+	ValidatorRegistry string `json:"validator_registry"`
+	SyncMode          string `json:"sync_mode"`
+	Pruning           string `json:"pruning"`
+	PruningRetention  uint64 `json:"pruning_retention"`
+	LightServe        bool   `json:"light_serve"`
+	AddressIndex      bool   `json:"address_index"`
+
+	// ShutdownTimeout is the time the node waits for the end of its consensus round when stopped, in seconds
+	ShutdownTimeout uint64 `json:"shutdown_timeout"`
+
+	// EthStats is the netstats server the stats are reported to, as nodename:secret@host:port
+	EthStats string `json:"ethstats"`
+
+	// Checkpoint is the JSON-RPC endpoint of the external chain the chain is anchored to,
+	// the checkpoints are submitted to its contract CheckpointContract every CheckpointInterval blocks
+	Checkpoint         string `json:"checkpoint"`
+	CheckpointContract string `json:"checkpoint_contract"`
+	CheckpointInterval uint64 `json:"checkpoint_interval"`
+	CheckpointKey      string `json:"checkpoint_key"`
+
+	// Bridge is the JSON-RPC endpoint of the root chain the deposits are relayed from, the StateSynced events
+	// of BridgeRootContract are relayed to BridgeStateReceiver, and the withdrawals of BridgeExitContract proven
+	Bridge              string `json:"bridge"`
+	BridgeRootContract  string `json:"bridge_root_contract"`
+	BridgeStateReceiver string `json:"bridge_state_receiver"`
+	BridgeExitContract  string `json:"bridge_exit_contract"`
+	BridgeConfirmations uint64 `json:"bridge_confirmations"`
+	BridgeStartBlock    uint64 `json:"bridge_start_block"`
+
+	// Export is the endpoint of the sink the new blocks, receipts and logs are published to,
+	// ExportSink its kind (webhook, nats or kafka) and ExportTopic the topic or subject
+	Export      string `json:"export"`
+	ExportSink  string `json:"export_sink"`
+	ExportTopic string `json:"export_topic"`
 }
 
 // Telemetry holds the config details for metric services.
 type Telemetry struct {
 	PrometheusAddr string `json:"prometheus_addr"`
+
+	// TracingAddr is the address of the OTLP collector the traces are exported to
+	TracingAddr       string `json:"tracing_addr"`
+	TracingInsecure   bool   `json:"tracing_insecure"`
+	TracingSampleRate uint64 `json:"tracing_sample_rate"`
+
+	// HealthAddr is the address of the health and readiness endpoints, the thresholds of their checks follow.
+	// The maximum age of the head is in seconds
+	HealthAddr       string `json:"health_addr"`
+	HealthMinPeers   uint64 `json:"health_min_peers"`
+	HealthMaxSyncLag uint64 `json:"health_max_sync_lag"`
+	HealthMaxHeadAge uint64 `json:"health_max_head_age"`
+}
+
+// GRPCAuth defines the authentication of the gRPC operator API clients
+type GRPCAuth struct {
+	TokensFile  string `json:"tokens_file"`
+	TLSCert     string `json:"tls_cert"`
+	TLSKey      string `json:"tls_key"`
+	TLSClientCA string `json:"tls_client_ca"`
+}
+
+// JSONRPC defines the JSON-RPC access configuration params.
+// Namespaces are passed in as comma separated lists
+type JSONRPC struct {
+	HTTPNamespaces string `json:"http_namespaces"`
+	WSNamespaces   string `json:"ws_namespaces"`
+	IPCNamespaces  string `json:"ipc_namespaces"`
+	AuthNamespaces string `json:"auth_namespaces"`
+	AuthToken      string `json:"auth_token"`
+	AuthTokenFile  string `json:"auth_token_file"`
+	Personal       bool   `json:"personal"`
+	Admin          bool   `json:"admin"`
+	Debug          bool   `json:"debug"`
+	Trace          bool   `json:"trace"`
+	LogsBlockRange uint64 `json:"logs_block_range"`
+	LogsLimit      uint64 `json:"logs_limit"`
+	CallTimeout    uint64 `json:"call_timeout"`
+	GasCap         uint64 `json:"gas_cap"`
+	GraphQL        bool   `json:"graphql"`
+	ShedCPU        uint64 `json:"shed_cpu"`
+	ShedMemory     uint64 `json:"shed_memory"`
+	ShedQueue      uint64 `json:"shed_queue"`
+	IPCPath        string `json:"ipc_path"`
+
+	RateLimit     uint64            `json:"rate_limit"`
+	RateBurst     uint64            `json:"rate_burst"`
+	MaxConcurrent uint64            `json:"max_concurrent"`
+	RateWeights   map[string]uint64 `json:"rate_weights"`
+	RateAPIKeys   []string          `json:"rate_api_keys"`
+
+	CallCacheTTL  uint64 `json:"call_cache_ttl"`
+	CallCacheSize uint64 `json:"call_cache_size"`
 }
 
 // Network defines the network configuration params
@@ -48,6 +174,30 @@ type Network struct {
 	NatAddr    string `json:"nat_addr"`
 	Dns        string `json:"dns"`
 	MaxPeers   uint64 `json:"max_peers"`
+
+	ConsortiumCA   string `json:"consortium_ca"`
+	ConsortiumCert string `json:"consortium_cert"`
+
+	NATPortMap   bool   `json:"nat_port_map"`
+	NATService   bool   `json:"nat_service"`
+	Reachability string `json:"reachability"`
+	Relays       string `json:"relays"`
+	RelayHop     bool   `json:"relay_hop"`
+
+	StaticPeers  string `json:"static_peers"`
+	TrustedPeers string `json:"trusted_peers"`
+	DNSDiscovery string `json:"dns_discovery"`
+
+	NoCompression  bool   `json:"no_compression"`
+	MaxMessageSize string `json:"max_message_size"`
+
+	Allowlist         string `json:"allowlist"`
+	AllowlistContract string `json:"allowlist_contract"`
+
+	// ConsensusAddr separates the validator traffic on its own listener, with its own peers
+	ConsensusAddr     string `json:"consensus_addr"`
+	ConsensusMaxPeers uint64 `json:"consensus_max_peers"`
+	ConsensusPeers    string `json:"consensus_peers"`
 }
 
 // TxPool defines the TxPool configuration params
@@ -56,6 +206,23 @@ type TxPool struct {
 	NoLocals   bool   `json:"no_locals"`
 	PriceLimit uint64 `json:"price_limit"`
 	MaxSlots   uint64 `json:"max_slots"`
+	PriceBump  uint64 `json:"price_bump"`
+	NoJournal  bool   `json:"no_journal"`
+
+	// QueueLifetime is the lifetime of the queued transactions of an idle account, in seconds
+	QueueLifetime uint64 `json:"queue_lifetime"`
+
+	// TxLifetime is the lifetime of the remote transactions, in seconds
+	TxLifetime uint64 `json:"tx_lifetime"`
+
+	// MaxAccountPending and MaxAccountQueued are the maximum numbers of pending and queued transactions per account
+	MaxAccountPending uint64 `json:"max_account_pending"`
+	MaxAccountQueued  uint64 `json:"max_account_queued"`
+
+	// MinGasPrice is the minimum gas price of every transaction, and ZeroGasAllowlist the comma separated
+	// accounts whose transactions are accepted whatever their gas price
+	MinGasPrice      uint64 `json:"min_gas_price"`
+	ZeroGasAllowlist string `json:"zero_gas_allowlist"`
 }
 
 // DefaultConfig returns the default server configuration
@@ -69,7 +236,12 @@ func DefaultConfig() *Config {
 			MaxPeers:   20,
 		},
 		Telemetry: &Telemetry{},
-		Seal:      false,
+		JSONRPC: &JSONRPC{
+			CallTimeout: uint64(jsonrpc.DefaultCallTimeout / time.Millisecond),
+			GasCap:      jsonrpc.DefaultCallGasCap,
+		},
+		GRPCAuth: &GRPCAuth{},
+		Seal:     false,
 		TxPool: &TxPool{
 			PriceLimit: 0,
 			MaxSlots:   4096,
@@ -94,6 +266,72 @@ func (c *Config) BuildConfig() (*server.Config, error) {
 	conf.Chain = cc
 	conf.Seal = c.Seal
 	conf.DataDir = c.DataDir
+	conf.StorageEncryption = c.StorageEncryption
+
+	switch c.DBBackend {
+	case "", kvdb.BackendLevelDB, kvdb.BackendBadger:
+		conf.DBBackend = c.DBBackend
+	default:
+		return nil, kvdb.ErrUnknownBackend
+	}
+
+	conf.FreezerThreshold = c.FreezerThreshold
+
+	conf.StoreDirs = common.StoreDirs{}
+	for name, dir := range map[string]string{"trie": c.TrieDir, "blockchain": c.ChainDir, "ancient": c.AncientDir} {
+		if dir != "" {
+			conf.StoreDirs[name] = dir
+		}
+	}
+
+	conf.MaxReorgDepth = c.MaxReorgDepth
+	conf.ExecParallelism = c.ExecParallelism
+	conf.EVMProfiler = c.EVMProfiler
+	conf.Cache = c.Cache
+	conf.ValidatorRegistry = c.ValidatorRegistry
+	conf.LightServe = c.LightServe
+	conf.AddressIndex = c.AddressIndex
+	if c.ShutdownTimeout != 0 {
+		conf.ShutdownTimeout = time.Duration(c.ShutdownTimeout) * time.Second
+	}
+
+	levels, err := logging.ParseLevelSpec(c.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	conf.LogLevels = logging.NewLevels(levels)
+
+	switch c.LogFormat {
+	case "", logging.FormatText, logging.FormatJSON:
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected text or json", c.LogFormat)
+	}
+
+	switch c.SyncMode {
+	case "", "full":
+	case "snapshot":
+		conf.SnapshotSync = true
+	default:
+		return nil, fmt.Errorf("unknown sync mode %q, expected full or snapshot", c.SyncMode)
+	}
+
+	if conf.Pruning, err = itrie.NewPruningConfig(c.Pruning); err != nil {
+		return nil, err
+	}
+	if c.PruningRetention != 0 {
+		if conf.Pruning == nil {
+			return nil, errors.New("the pruning retention requires the full or the light pruning mode")
+		}
+		conf.Pruning.Retention = c.PruningRetention
+	}
+
+	// the explicit archive mode guarantees the state of every block is kept
+	if c.Pruning == itrie.PruningArchive {
+		if conf.SnapshotSync {
+			return nil, errors.New("the archive mode requires the full sync mode, the snapshot sync skips the state of the historical blocks")
+		}
+		conf.Archive = true
+	}
 
 	// JSON RPC + GRPC
 	if c.GRPCAddr != "" {
@@ -114,6 +352,184 @@ func (c *Config) BuildConfig() (*server.Config, error) {
 			return nil, err
 		}
 	}
+	if c.Telemetry.TracingAddr != "" {
+		sampleRatio := tracing.DefaultSampleRatio
+		if c.Telemetry.TracingSampleRate != 0 {
+			if c.Telemetry.TracingSampleRate > 100 {
+				return nil, errors.New("the sample rate of the tracing is a percentage")
+			}
+			sampleRatio = float64(c.Telemetry.TracingSampleRate) / 100
+		}
+		conf.Telemetry.Tracing = &tracing.Config{
+			Endpoint:    c.Telemetry.TracingAddr,
+			Insecure:    c.Telemetry.TracingInsecure,
+			SampleRatio: sampleRatio,
+		}
+	}
+	if c.Telemetry.HealthAddr != "" {
+		conf.Health = &server.HealthConfig{
+			MinPeers:   c.Telemetry.HealthMinPeers,
+			MaxSyncLag: server.DefaultHealthMaxSyncLag,
+			MaxHeadAge: time.Duration(c.Telemetry.HealthMaxHeadAge) * time.Second,
+		}
+		if conf.Health.Addr, err = resolveAddr(c.Telemetry.HealthAddr); err != nil {
+			return nil, err
+		}
+		if c.Telemetry.HealthMaxSyncLag != 0 {
+			conf.Health.MaxSyncLag = c.Telemetry.HealthMaxSyncLag
+		}
+	}
+
+	if c.EthStats != "" {
+		if conf.EthStats, err = ethstats.ParseConfig(c.EthStats); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Checkpoint != "" {
+		conf.Checkpoint = &checkpoint.Config{
+			URL:      c.Checkpoint,
+			Interval: checkpoint.DefaultInterval,
+			KeyPath:  filepath.Join(c.DataDir, CheckpointKeyFile),
+		}
+		if c.CheckpointContract == "" {
+			return nil, errors.New("the checkpointing requires the checkpoint contract")
+		}
+		if err := conf.Checkpoint.Contract.UnmarshalText([]byte(c.CheckpointContract)); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint contract: %v", err)
+		}
+		if c.CheckpointInterval != 0 {
+			conf.Checkpoint.Interval = c.CheckpointInterval
+		}
+		if c.CheckpointKey != "" {
+			conf.Checkpoint.KeyPath = c.CheckpointKey
+		}
+	}
+
+	if c.Bridge != "" {
+		conf.Bridge = &bridge.Config{
+			URL:           c.Bridge,
+			Confirmations: bridge.DefaultConfirmations,
+			StartBlock:    c.BridgeStartBlock,
+		}
+		contracts := []struct {
+			name  string
+			value string
+			addr  *types.Address
+		}{
+			{"root contract", c.BridgeRootContract, &conf.Bridge.RootContract},
+			{"state receiver", c.BridgeStateReceiver, &conf.Bridge.StateReceiver},
+			{"exit contract", c.BridgeExitContract, &conf.Bridge.ExitContract},
+		}
+		for _, contract := range contracts {
+			if contract.value == "" {
+				return nil, fmt.Errorf("the bridge requires the %s", contract.name)
+			}
+			if err := contract.addr.UnmarshalText([]byte(contract.value)); err != nil {
+				return nil, fmt.Errorf("invalid bridge %s: %v", contract.name, err)
+			}
+		}
+		if c.BridgeConfirmations != 0 {
+			conf.Bridge.Confirmations = c.BridgeConfirmations
+		}
+	}
+
+	if c.Export != "" {
+		conf.Export = &exporter.Config{
+			Sink:  exporter.SinkWebhook,
+			URL:   c.Export,
+			Topic: c.ExportTopic,
+		}
+		if c.ExportSink != "" {
+			conf.Export.Sink = c.ExportSink
+		}
+		// the sink is checked before the start
+		if _, err := exporter.NewSink(conf.Export); err != nil {
+			return nil, err
+		}
+	}
+
+	// gRPC access
+	if c.GRPCAuth != nil {
+		access := &server.OperatorAccessConfig{
+			TLSCert:     c.GRPCAuth.TLSCert,
+			TLSKey:      c.GRPCAuth.TLSKey,
+			TLSClientCA: c.GRPCAuth.TLSClientCA,
+		}
+		if c.GRPCAuth.TokensFile != "" {
+			if access.Tokens, err = readOperatorTokens(c.GRPCAuth.TokensFile); err != nil {
+				return nil, err
+			}
+		}
+
+		conf.GRPCAccess = access
+	}
+
+	// JSON RPC access
+	{
+		access := &jsonrpc.AccessConfig{
+			Namespaces: map[string][]string{},
+			Protected:  jsonrpc.ParseNamespaces(c.JSONRPC.AuthNamespaces),
+			AuthToken:  c.JSONRPC.AuthToken,
+		}
+		if c.JSONRPC.HTTPNamespaces != "" {
+			access.Namespaces["http"] = jsonrpc.ParseNamespaces(c.JSONRPC.HTTPNamespaces)
+		}
+		if c.JSONRPC.WSNamespaces != "" {
+			access.Namespaces["ws"] = jsonrpc.ParseNamespaces(c.JSONRPC.WSNamespaces)
+		}
+		if c.JSONRPC.IPCNamespaces != "" {
+			access.Namespaces["ipc"] = jsonrpc.ParseNamespaces(c.JSONRPC.IPCNamespaces)
+		}
+		if c.JSONRPC.AuthTokenFile != "" {
+			if c.JSONRPC.AuthToken != "" {
+				return nil, errors.New("only one of the auth token and the auth token file can be set")
+			}
+			if access.AuthToken, err = readAuthToken(c.JSONRPC.AuthTokenFile); err != nil {
+				return nil, err
+			}
+		}
+
+		conf.JSONRPC = access
+		conf.Personal = c.JSONRPC.Personal
+		conf.Admin = c.JSONRPC.Admin
+		conf.Debug = c.JSONRPC.Debug
+		conf.Trace = c.JSONRPC.Trace
+		conf.LogsBlockRange = c.JSONRPC.LogsBlockRange
+		conf.LogsResultLimit = c.JSONRPC.LogsLimit
+		conf.CallTimeout = time.Duration(c.JSONRPC.CallTimeout) * time.Millisecond
+		conf.CallGasCap = c.JSONRPC.GasCap
+		conf.GraphQL = c.JSONRPC.GraphQL
+		conf.IPCPath = c.JSONRPC.IPCPath
+
+		if c.JSONRPC.RateLimit != 0 || c.JSONRPC.MaxConcurrent != 0 {
+			conf.RateLimit = &jsonrpc.RateLimitConfig{
+				RequestsPerSecond: float64(c.JSONRPC.RateLimit),
+				Burst:             c.JSONRPC.RateBurst,
+				MaxConcurrent:     int64(c.JSONRPC.MaxConcurrent),
+				MethodWeights:     c.JSONRPC.RateWeights,
+				APIKeys:           c.JSONRPC.RateAPIKeys,
+			}
+		}
+
+		if c.JSONRPC.CallCacheTTL != 0 {
+			conf.CallCache = &jsonrpc.CallCacheConfig{
+				TTL:  time.Duration(c.JSONRPC.CallCacheTTL) * time.Millisecond,
+				Size: int(c.JSONRPC.CallCacheSize),
+			}
+		}
+
+		if c.JSONRPC.ShedCPU != 0 || c.JSONRPC.ShedMemory != 0 || c.JSONRPC.ShedQueue != 0 {
+			if c.JSONRPC.ShedCPU > 100 {
+				return nil, errors.New("the CPU threshold of the load shedding is a percentage")
+			}
+			conf.LoadShed = &jsonrpc.LoadShedConfig{
+				MaxCPU:    float64(c.JSONRPC.ShedCPU) / 100,
+				MaxMemory: c.JSONRPC.ShedMemory << 20,
+				MaxQueue:  int64(c.JSONRPC.ShedQueue),
+			}
+		}
+	}
 
 	// Network
 	{
@@ -137,6 +553,115 @@ func (c *Config) BuildConfig() (*server.Config, error) {
 		conf.Network.NoDiscover = c.Network.NoDiscover
 		conf.Network.MaxPeers = c.Network.MaxPeers
 
+		if c.Network.NATPortMap || c.Network.NATService || c.Network.Reachability != "" ||
+			c.Network.Relays != "" || c.Network.RelayHop {
+			conf.Network.NAT = &network.NATConfig{
+				PortMap:      c.Network.NATPortMap,
+				Service:      c.Network.NATService,
+				Reachability: c.Network.Reachability,
+				RelayHop:     c.Network.RelayHop,
+			}
+			if c.Network.Relays != "" {
+				for _, raw := range strings.Split(c.Network.Relays, ",") {
+					relay, err := network.StringToAddrInfo(raw)
+					if err != nil {
+						return nil, fmt.Errorf("failed to parse relay %s: %v", raw, err)
+					}
+					conf.Network.NAT.Relays = append(conf.Network.NAT.Relays, relay)
+				}
+			}
+		}
+
+		if c.Network.StaticPeers != "" {
+			for _, raw := range strings.Split(c.Network.StaticPeers, ",") {
+				info, err := network.StringToAddrInfo(raw)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse static peer %s: %v", raw, err)
+				}
+				conf.Network.StaticPeers = append(conf.Network.StaticPeers, info)
+			}
+		}
+		conf.Network.Compression = !c.Network.NoCompression
+		if c.Network.ConsensusAddr != "" {
+			conf.ConsensusNetwork = network.DefaultConfig()
+			conf.ConsensusNetwork.NoDiscover = true
+			if conf.ConsensusNetwork.Addr, err = resolveAddr(c.Network.ConsensusAddr); err != nil {
+				return nil, fmt.Errorf("invalid consensus address: %v", err)
+			}
+			if c.Network.ConsensusMaxPeers != 0 {
+				conf.ConsensusNetwork.MaxPeers = c.Network.ConsensusMaxPeers
+			}
+			if c.Network.ConsensusPeers != "" {
+				for _, raw := range strings.Split(c.Network.ConsensusPeers, ",") {
+					info, err := network.StringToAddrInfo(raw)
+					if err != nil {
+						return nil, fmt.Errorf("failed to parse consensus peer %s: %v", raw, err)
+					}
+					conf.ConsensusNetwork.StaticPeers = append(conf.ConsensusNetwork.StaticPeers, info)
+				}
+			}
+		} else if c.Network.ConsensusMaxPeers != 0 || c.Network.ConsensusPeers != "" {
+			return nil, errors.New("the consensus peers require a consensus address")
+		}
+		if c.Network.Allowlist != "" {
+			allowlist := network.FileAllowlist(c.Network.Allowlist)
+			if _, err := allowlist.Allowlist(); err != nil {
+				return nil, fmt.Errorf("failed to read the allowlist: %v", err)
+			}
+			conf.Network.Allowlist = append(conf.Network.Allowlist, allowlist)
+		}
+		if c.Network.AllowlistContract != "" {
+			addr := types.Address{}
+			if err := addr.UnmarshalText([]byte(c.Network.AllowlistContract)); err != nil {
+				return nil, fmt.Errorf("invalid allowlist contract: %v", err)
+			}
+			conf.AllowlistContract = &addr
+		}
+		if c.Network.MaxMessageSize != "" {
+			conf.Network.MaxMessageSizes = map[string]int{}
+			for _, raw := range strings.Split(c.Network.MaxMessageSize, ",") {
+				kv := strings.SplitN(raw, "=", 2)
+				if len(kv) != 2 {
+					return nil, fmt.Errorf("max message size %s must be <protocol>=<bytes>", raw)
+				}
+				size, err := strconv.Atoi(kv[1])
+				if err != nil || size <= 0 {
+					return nil, fmt.Errorf("invalid max message size of %s: %s", kv[0], kv[1])
+				}
+				conf.Network.MaxMessageSizes[kv[0]] = size
+			}
+		}
+		if c.Network.DNSDiscovery != "" {
+			for _, raw := range strings.Split(c.Network.DNSDiscovery, ",") {
+				tree, err := network.ParseDNSTreeURL(raw)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse dns tree %s: %v", raw, err)
+				}
+				conf.Network.DNSTrees = append(conf.Network.DNSTrees, tree)
+			}
+		}
+		if c.Network.TrustedPeers != "" {
+			for _, raw := range strings.Split(c.Network.TrustedPeers, ",") {
+				id, err := network.StringToPeerID(raw)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse trusted peer %s: %v", raw, err)
+				}
+				conf.Network.TrustedPeers = append(conf.Network.TrustedPeers, id)
+			}
+		}
+
+		if c.Network.ConsortiumCA != "" || c.Network.ConsortiumCert != "" {
+			if c.Network.ConsortiumCA == "" || c.Network.ConsortiumCert == "" {
+				return nil, errors.New("both the consortium CA and the consortium certificate must be set")
+			}
+			if conf.Network.Consortium, err = network.LoadConsortiumConfig(
+				c.Network.ConsortiumCA,
+				c.Network.ConsortiumCert,
+			); err != nil {
+				return nil, err
+			}
+		}
+
 		conf.Chain = cc
 	}
 
@@ -152,6 +677,20 @@ func (c *Config) BuildConfig() (*server.Config, error) {
 		conf.NoLocals = c.TxPool.NoLocals
 		conf.PriceLimit = c.TxPool.PriceLimit
 		conf.MaxSlots = c.TxPool.MaxSlots
+		conf.PriceBump = c.TxPool.PriceBump
+		if !c.TxPool.NoJournal {
+			conf.TxPoolJournal = filepath.Join(c.DataDir, TxPoolJournalFile)
+		}
+		conf.QueueLifetime = time.Duration(c.TxPool.QueueLifetime) * time.Second
+		conf.TxLifetime = time.Duration(c.TxPool.TxLifetime) * time.Second
+		conf.MaxAccountPending = c.TxPool.MaxAccountPending
+		conf.MaxAccountQueued = c.TxPool.MaxAccountQueued
+		conf.MinGasPrice = c.TxPool.MinGasPrice
+		if c.TxPool.ZeroGasAllowlist != "" {
+			for _, sAddr := range strings.Split(c.TxPool.ZeroGasAllowlist, ",") {
+				conf.ZeroGasAllowlist = append(conf.ZeroGasAllowlist, types.StringToAddress(strings.TrimSpace(sAddr)))
+			}
+		}
 	}
 
 	// Target gas limit
@@ -163,6 +702,9 @@ func (c *Config) BuildConfig() (*server.Config, error) {
 		if !value.IsUint64() {
 			return nil, fmt.Errorf("gas target is too large (>64b) %s", c.BlockGasTarget)
 		}
+		if value.Sign() != 0 && value.Uint64() < blockchain.MinGasLimit {
+			return nil, fmt.Errorf("gas target %s is below the minimum gas limit %d", c.BlockGasTarget, blockchain.MinGasLimit)
+		}
 
 		conf.Chain.Params.BlockGasTarget = value.Uint64()
 	}
@@ -178,12 +720,28 @@ func (c *Config) BuildConfig() (*server.Config, error) {
 		if c.DevInterval != 0 {
 			engineConfig["interval"] = c.DevInterval
 		}
+		if c.DevCatchupRate != 0 {
+			engineConfig["catchupRate"] = c.DevCatchupRate
+		}
 		conf.Chain.Params.Forks = chain.AllForksEnabled
 		conf.Chain.Params.Engine = map[string]interface{}{
 			"dev": engineConfig,
 		}
 	}
 
+	// the unknown accounts and slots are read from the remote chain
+	if c.Fork != "" {
+		if !c.Dev {
+			return nil, errors.New("the forking of a remote chain requires the dev mode")
+		}
+		conf.Fork = &fork.Config{
+			URL:   c.Fork,
+			Block: c.ForkBlock,
+		}
+	} else if c.ForkBlock != 0 {
+		return nil, errors.New("the fork block requires the fork endpoint")
+	}
+
 	// Set the secrets manager config if it was passed in
 	if c.SecretsManager != nil {
 		conf.SecretsManager = c.SecretsManager
@@ -216,6 +774,50 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		c.Chain = otherConfig.Chain
 	}
 
+	if otherConfig.StorageEncryption {
+		c.StorageEncryption = true
+	}
+
+	if otherConfig.DBBackend != "" {
+		c.DBBackend = otherConfig.DBBackend
+	}
+
+	if otherConfig.FreezerThreshold != 0 {
+		c.FreezerThreshold = otherConfig.FreezerThreshold
+	}
+
+	if otherConfig.TrieDir != "" {
+		c.TrieDir = otherConfig.TrieDir
+	}
+
+	if otherConfig.ChainDir != "" {
+		c.ChainDir = otherConfig.ChainDir
+	}
+
+	if otherConfig.AncientDir != "" {
+		c.AncientDir = otherConfig.AncientDir
+	}
+
+	if otherConfig.MaxReorgDepth != 0 {
+		c.MaxReorgDepth = otherConfig.MaxReorgDepth
+	}
+
+	if otherConfig.ExecParallelism != 0 {
+		c.ExecParallelism = otherConfig.ExecParallelism
+	}
+
+	if otherConfig.EVMProfiler {
+		c.EVMProfiler = true
+	}
+
+	if otherConfig.Cache != 0 {
+		c.Cache = otherConfig.Cache
+	}
+
+	if otherConfig.AddressIndex {
+		c.AddressIndex = true
+	}
+
 	if otherConfig.Dev {
 		c.Dev = true
 	}
@@ -224,6 +826,18 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		c.DevInterval = otherConfig.DevInterval
 	}
 
+	if otherConfig.DevCatchupRate != 0 {
+		c.DevCatchupRate = otherConfig.DevCatchupRate
+	}
+
+	if otherConfig.Fork != "" {
+		c.Fork = otherConfig.Fork
+	}
+
+	if otherConfig.ForkBlock != 0 {
+		c.ForkBlock = otherConfig.ForkBlock
+	}
+
 	if otherConfig.BlockGasTarget != "" {
 		c.BlockGasTarget = otherConfig.BlockGasTarget
 	}
@@ -236,6 +850,10 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		c.LogLevel = otherConfig.LogLevel
 	}
 
+	if otherConfig.LogFormat != "" {
+		c.LogFormat = otherConfig.LogFormat
+	}
+
 	if otherConfig.GRPCAddr != "" {
 		c.GRPCAddr = otherConfig.GRPCAddr
 	}
@@ -244,6 +862,34 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		c.Telemetry.PrometheusAddr = otherConfig.Telemetry.PrometheusAddr
 	}
 
+	if otherConfig.Telemetry.TracingAddr != "" {
+		c.Telemetry.TracingAddr = otherConfig.Telemetry.TracingAddr
+	}
+
+	if otherConfig.Telemetry.TracingInsecure {
+		c.Telemetry.TracingInsecure = true
+	}
+
+	if otherConfig.Telemetry.TracingSampleRate != 0 {
+		c.Telemetry.TracingSampleRate = otherConfig.Telemetry.TracingSampleRate
+	}
+
+	if otherConfig.Telemetry.HealthAddr != "" {
+		c.Telemetry.HealthAddr = otherConfig.Telemetry.HealthAddr
+	}
+
+	if otherConfig.Telemetry.HealthMinPeers != 0 {
+		c.Telemetry.HealthMinPeers = otherConfig.Telemetry.HealthMinPeers
+	}
+
+	if otherConfig.Telemetry.HealthMaxSyncLag != 0 {
+		c.Telemetry.HealthMaxSyncLag = otherConfig.Telemetry.HealthMaxSyncLag
+	}
+
+	if otherConfig.Telemetry.HealthMaxHeadAge != 0 {
+		c.Telemetry.HealthMaxHeadAge = otherConfig.Telemetry.HealthMaxHeadAge
+	}
+
 	if otherConfig.JSONRPCAddr != "" {
 		c.JSONRPCAddr = otherConfig.JSONRPCAddr
 	}
@@ -252,6 +898,186 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		c.Join = otherConfig.Join
 	}
 
+	if otherConfig.EthStats != "" {
+		c.EthStats = otherConfig.EthStats
+	}
+
+	if otherConfig.Checkpoint != "" {
+		c.Checkpoint = otherConfig.Checkpoint
+	}
+
+	if otherConfig.CheckpointContract != "" {
+		c.CheckpointContract = otherConfig.CheckpointContract
+	}
+
+	if otherConfig.CheckpointInterval != 0 {
+		c.CheckpointInterval = otherConfig.CheckpointInterval
+	}
+
+	if otherConfig.CheckpointKey != "" {
+		c.CheckpointKey = otherConfig.CheckpointKey
+	}
+
+	if otherConfig.Bridge != "" {
+		c.Bridge = otherConfig.Bridge
+	}
+
+	if otherConfig.BridgeRootContract != "" {
+		c.BridgeRootContract = otherConfig.BridgeRootContract
+	}
+
+	if otherConfig.BridgeStateReceiver != "" {
+		c.BridgeStateReceiver = otherConfig.BridgeStateReceiver
+	}
+
+	if otherConfig.BridgeExitContract != "" {
+		c.BridgeExitContract = otherConfig.BridgeExitContract
+	}
+
+	if otherConfig.BridgeConfirmations != 0 {
+		c.BridgeConfirmations = otherConfig.BridgeConfirmations
+	}
+
+	if otherConfig.BridgeStartBlock != 0 {
+		c.BridgeStartBlock = otherConfig.BridgeStartBlock
+	}
+
+	if otherConfig.Export != "" {
+		c.Export = otherConfig.Export
+	}
+
+	if otherConfig.ExportSink != "" {
+		c.ExportSink = otherConfig.ExportSink
+	}
+
+	if otherConfig.ExportTopic != "" {
+		c.ExportTopic = otherConfig.ExportTopic
+	}
+
+	if otherConfig.ValidatorRegistry != "" {
+		c.ValidatorRegistry = otherConfig.ValidatorRegistry
+	}
+
+	if otherConfig.SyncMode != "" {
+		c.SyncMode = otherConfig.SyncMode
+	}
+
+	if otherConfig.Pruning != "" {
+		c.Pruning = otherConfig.Pruning
+	}
+
+	if otherConfig.PruningRetention != 0 {
+		c.PruningRetention = otherConfig.PruningRetention
+	}
+
+	if otherConfig.LightServe {
+		c.LightServe = true
+	}
+
+	if otherConfig.ShutdownTimeout != 0 {
+		c.ShutdownTimeout = otherConfig.ShutdownTimeout
+	}
+
+	if otherConfig.GRPCAuth != nil {
+		if c.GRPCAuth == nil {
+			c.GRPCAuth = &GRPCAuth{}
+		}
+		if otherConfig.GRPCAuth.TokensFile != "" {
+			c.GRPCAuth.TokensFile = otherConfig.GRPCAuth.TokensFile
+		}
+		if otherConfig.GRPCAuth.TLSCert != "" {
+			c.GRPCAuth.TLSCert = otherConfig.GRPCAuth.TLSCert
+		}
+		if otherConfig.GRPCAuth.TLSKey != "" {
+			c.GRPCAuth.TLSKey = otherConfig.GRPCAuth.TLSKey
+		}
+		if otherConfig.GRPCAuth.TLSClientCA != "" {
+			c.GRPCAuth.TLSClientCA = otherConfig.GRPCAuth.TLSClientCA
+		}
+	}
+
+	if otherConfig.JSONRPC != nil {
+		// JSON RPC access
+		if otherConfig.JSONRPC.HTTPNamespaces != "" {
+			c.JSONRPC.HTTPNamespaces = otherConfig.JSONRPC.HTTPNamespaces
+		}
+		if otherConfig.JSONRPC.WSNamespaces != "" {
+			c.JSONRPC.WSNamespaces = otherConfig.JSONRPC.WSNamespaces
+		}
+		if otherConfig.JSONRPC.IPCNamespaces != "" {
+			c.JSONRPC.IPCNamespaces = otherConfig.JSONRPC.IPCNamespaces
+		}
+		if otherConfig.JSONRPC.AuthNamespaces != "" {
+			c.JSONRPC.AuthNamespaces = otherConfig.JSONRPC.AuthNamespaces
+		}
+		if otherConfig.JSONRPC.AuthToken != "" {
+			c.JSONRPC.AuthToken = otherConfig.JSONRPC.AuthToken
+		}
+		if otherConfig.JSONRPC.AuthTokenFile != "" {
+			c.JSONRPC.AuthTokenFile = otherConfig.JSONRPC.AuthTokenFile
+		}
+		if otherConfig.JSONRPC.Personal {
+			c.JSONRPC.Personal = true
+		}
+		if otherConfig.JSONRPC.Admin {
+			c.JSONRPC.Admin = true
+		}
+		if otherConfig.JSONRPC.Debug {
+			c.JSONRPC.Debug = true
+		}
+		if otherConfig.JSONRPC.Trace {
+			c.JSONRPC.Trace = true
+		}
+		if otherConfig.JSONRPC.LogsBlockRange != 0 {
+			c.JSONRPC.LogsBlockRange = otherConfig.JSONRPC.LogsBlockRange
+		}
+		if otherConfig.JSONRPC.LogsLimit != 0 {
+			c.JSONRPC.LogsLimit = otherConfig.JSONRPC.LogsLimit
+		}
+		if otherConfig.JSONRPC.CallTimeout != 0 {
+			c.JSONRPC.CallTimeout = otherConfig.JSONRPC.CallTimeout
+		}
+		if otherConfig.JSONRPC.GasCap != 0 {
+			c.JSONRPC.GasCap = otherConfig.JSONRPC.GasCap
+		}
+		if otherConfig.JSONRPC.GraphQL {
+			c.JSONRPC.GraphQL = true
+		}
+		if otherConfig.JSONRPC.ShedCPU != 0 {
+			c.JSONRPC.ShedCPU = otherConfig.JSONRPC.ShedCPU
+		}
+		if otherConfig.JSONRPC.ShedMemory != 0 {
+			c.JSONRPC.ShedMemory = otherConfig.JSONRPC.ShedMemory
+		}
+		if otherConfig.JSONRPC.ShedQueue != 0 {
+			c.JSONRPC.ShedQueue = otherConfig.JSONRPC.ShedQueue
+		}
+		if otherConfig.JSONRPC.IPCPath != "" {
+			c.JSONRPC.IPCPath = otherConfig.JSONRPC.IPCPath
+		}
+		if otherConfig.JSONRPC.RateLimit != 0 {
+			c.JSONRPC.RateLimit = otherConfig.JSONRPC.RateLimit
+		}
+		if otherConfig.JSONRPC.RateBurst != 0 {
+			c.JSONRPC.RateBurst = otherConfig.JSONRPC.RateBurst
+		}
+		if otherConfig.JSONRPC.MaxConcurrent != 0 {
+			c.JSONRPC.MaxConcurrent = otherConfig.JSONRPC.MaxConcurrent
+		}
+		if otherConfig.JSONRPC.RateWeights != nil {
+			c.JSONRPC.RateWeights = otherConfig.JSONRPC.RateWeights
+		}
+		if otherConfig.JSONRPC.RateAPIKeys != nil {
+			c.JSONRPC.RateAPIKeys = otherConfig.JSONRPC.RateAPIKeys
+		}
+		if otherConfig.JSONRPC.CallCacheTTL != 0 {
+			c.JSONRPC.CallCacheTTL = otherConfig.JSONRPC.CallCacheTTL
+		}
+		if otherConfig.JSONRPC.CallCacheSize != 0 {
+			c.JSONRPC.CallCacheSize = otherConfig.JSONRPC.CallCacheSize
+		}
+	}
+
 	{
 		// Network
 		if otherConfig.Network.Addr != "" {
@@ -266,9 +1092,60 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		if otherConfig.Network.MaxPeers != 0 {
 			c.Network.MaxPeers = otherConfig.Network.MaxPeers
 		}
+		if otherConfig.Network.ConsortiumCA != "" {
+			c.Network.ConsortiumCA = otherConfig.Network.ConsortiumCA
+		}
+		if otherConfig.Network.ConsortiumCert != "" {
+			c.Network.ConsortiumCert = otherConfig.Network.ConsortiumCert
+		}
 		if otherConfig.Network.NoDiscover {
 			c.Network.NoDiscover = true
 		}
+		if otherConfig.Network.NATPortMap {
+			c.Network.NATPortMap = true
+		}
+		if otherConfig.Network.NATService {
+			c.Network.NATService = true
+		}
+		if otherConfig.Network.Reachability != "" {
+			c.Network.Reachability = otherConfig.Network.Reachability
+		}
+		if otherConfig.Network.Relays != "" {
+			c.Network.Relays = otherConfig.Network.Relays
+		}
+		if otherConfig.Network.RelayHop {
+			c.Network.RelayHop = true
+		}
+		if otherConfig.Network.StaticPeers != "" {
+			c.Network.StaticPeers = otherConfig.Network.StaticPeers
+		}
+		if otherConfig.Network.TrustedPeers != "" {
+			c.Network.TrustedPeers = otherConfig.Network.TrustedPeers
+		}
+		if otherConfig.Network.DNSDiscovery != "" {
+			c.Network.DNSDiscovery = otherConfig.Network.DNSDiscovery
+		}
+		if otherConfig.Network.NoCompression {
+			c.Network.NoCompression = true
+		}
+		if otherConfig.Network.ConsensusAddr != "" {
+			c.Network.ConsensusAddr = otherConfig.Network.ConsensusAddr
+		}
+		if otherConfig.Network.ConsensusMaxPeers != 0 {
+			c.Network.ConsensusMaxPeers = otherConfig.Network.ConsensusMaxPeers
+		}
+		if otherConfig.Network.ConsensusPeers != "" {
+			c.Network.ConsensusPeers = otherConfig.Network.ConsensusPeers
+		}
+		if otherConfig.Network.Allowlist != "" {
+			c.Network.Allowlist = otherConfig.Network.Allowlist
+		}
+		if otherConfig.Network.AllowlistContract != "" {
+			c.Network.AllowlistContract = otherConfig.Network.AllowlistContract
+		}
+		if otherConfig.Network.MaxMessageSize != "" {
+			c.Network.MaxMessageSize = otherConfig.Network.MaxMessageSize
+		}
 	}
 
 	{
@@ -285,6 +1162,30 @@ func (c *Config) mergeConfigWith(otherConfig *Config) error {
 		if otherConfig.TxPool.MaxSlots != 0 {
 			c.TxPool.MaxSlots = otherConfig.TxPool.MaxSlots
 		}
+		if otherConfig.TxPool.NoJournal {
+			c.TxPool.NoJournal = true
+		}
+		if otherConfig.TxPool.PriceBump != 0 {
+			c.TxPool.PriceBump = otherConfig.TxPool.PriceBump
+		}
+		if otherConfig.TxPool.QueueLifetime != 0 {
+			c.TxPool.QueueLifetime = otherConfig.TxPool.QueueLifetime
+		}
+		if otherConfig.TxPool.TxLifetime != 0 {
+			c.TxPool.TxLifetime = otherConfig.TxPool.TxLifetime
+		}
+		if otherConfig.TxPool.MaxAccountPending != 0 {
+			c.TxPool.MaxAccountPending = otherConfig.TxPool.MaxAccountPending
+		}
+		if otherConfig.TxPool.MaxAccountQueued != 0 {
+			c.TxPool.MaxAccountQueued = otherConfig.TxPool.MaxAccountQueued
+		}
+		if otherConfig.TxPool.MinGasPrice != 0 {
+			c.TxPool.MinGasPrice = otherConfig.TxPool.MinGasPrice
+		}
+		if otherConfig.TxPool.ZeroGasAllowlist != "" {
+			c.TxPool.ZeroGasAllowlist = otherConfig.TxPool.ZeroGasAllowlist
+		}
 	}
 
 	if err := mergo.Merge(&c.Consensus, otherConfig.Consensus, mergo.WithOverride); err != nil {
@@ -321,3 +1222,36 @@ func readConfigFile(path string) (*Config, error) {
 
 	return &config, nil
 }
+
+// readAuthToken reads the JSON-RPC auth token from the specified file
+func readAuthToken(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the auth token file: %v", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("the auth token file %s is empty", path)
+	}
+
+	return token, nil
+}
+
+// readOperatorTokens reads the gRPC auth tokens from the specified file
+func readOperatorTokens(path string) (map[string]server.OperatorRole, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the gRPC tokens file: %v", err)
+	}
+
+	tokens, err := server.ParseOperatorTokens(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gRPC tokens file %s: %v", path, err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("the gRPC tokens file %s is empty", path)
+	}
+
+	return tokens, nil
+}
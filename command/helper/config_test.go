@@ -0,0 +1,202 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestBuildConfigJSONRPCAccess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenFile := writeTestFile(t, dir, "token", "secret\n")
+	configFile := writeTestFile(t, dir, "config.json", `{
+		"telemetry": {},
+		"network": {},
+		"tx_pool": {},
+		"jsonrpc": {
+			"http_namespaces": "eth, net",
+			"auth_namespaces": "txpool",
+			"auth_token_file": "`+tokenFile+`"
+		}
+	}`)
+
+	diskConfig, err := readConfigFile(configFile)
+	assert.NoError(t, err)
+
+	config := DefaultConfig()
+	assert.NoError(t, config.mergeConfigWith(diskConfig))
+
+	// flags override the config file
+	assert.NoError(t, config.mergeConfigWith(&Config{
+		Network:   &Network{},
+		TxPool:    &TxPool{},
+		Telemetry: &Telemetry{},
+		JSONRPC: &JSONRPC{
+			WSNamespaces: "eth",
+		},
+	}))
+
+	serverConfig, err := config.BuildConfig()
+	assert.NoError(t, err)
+
+	access := serverConfig.JSONRPC
+	assert.Equal(t, []string{"eth", "net"}, access.Namespaces["http"])
+	assert.Equal(t, []string{"eth"}, access.Namespaces["ws"])
+	assert.Equal(t, []string{"txpool"}, access.Protected)
+	assert.Equal(t, "secret", access.AuthToken)
+
+	// the token and the token file are mutually exclusive
+	config.JSONRPC.AuthToken = "other"
+	_, err = config.BuildConfig()
+	assert.Error(t, err)
+
+	// empty token files are rejected
+	config.JSONRPC.AuthToken = ""
+	config.JSONRPC.AuthTokenFile = writeTestFile(t, dir, "empty", "\n")
+	_, err = config.BuildConfig()
+	assert.Error(t, err)
+}
+
+func TestBuildConfigConsensusNetwork(t *testing.T) {
+	peer := "/ip4/10.0.0.2/tcp/1479/p2p/16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW"
+
+	config := DefaultConfig()
+	assert.NoError(t, config.mergeConfigWith(&Config{
+		Network: &Network{
+			ConsensusAddr:     "10.0.0.1:1479",
+			ConsensusMaxPeers: 4,
+			ConsensusPeers:    peer,
+		},
+		TxPool:    &TxPool{},
+		Telemetry: &Telemetry{},
+		JSONRPC:   &JSONRPC{},
+	}))
+
+	serverConfig, err := config.BuildConfig()
+	assert.NoError(t, err)
+
+	consensus := serverConfig.ConsensusNetwork
+	assert.NotNil(t, consensus)
+	assert.Equal(t, "10.0.0.1:1479", consensus.Addr.String())
+	assert.Equal(t, uint64(4), consensus.MaxPeers)
+	assert.True(t, consensus.NoDiscover)
+	assert.Len(t, consensus.StaticPeers, 1)
+
+	// the consensus peers require the consensus listener
+	config.Network.ConsensusAddr = ""
+	_, err = config.BuildConfig()
+	assert.Error(t, err)
+
+	config.Network.ConsensusMaxPeers = 0
+	config.Network.ConsensusPeers = ""
+	serverConfig, err = config.BuildConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, serverConfig.ConsensusNetwork)
+}
+
+func TestBuildConfigLogging(t *testing.T) {
+	config := DefaultConfig()
+	assert.NoError(t, config.mergeConfigWith(&Config{
+		LogLevel:  "warn,ibft=debug",
+		LogFormat: "json",
+		Network:   &Network{},
+		TxPool:    &TxPool{},
+		Telemetry: &Telemetry{},
+		JSONRPC:   &JSONRPC{},
+	}))
+
+	serverConfig, err := config.BuildConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "warn,ibft=debug", serverConfig.LogLevels.Spec().String())
+
+	config.LogFormat = "xml"
+	_, err = config.BuildConfig()
+	assert.Error(t, err)
+
+	config.LogFormat = ""
+	config.LogLevel = "ibft=verbose"
+	_, err = config.BuildConfig()
+	assert.Error(t, err)
+}
+
+func TestBuildConfigHealth(t *testing.T) {
+	config := DefaultConfig()
+	assert.NoError(t, config.mergeConfigWith(&Config{
+		Network: &Network{},
+		TxPool:  &TxPool{},
+		Telemetry: &Telemetry{
+			HealthAddr:       "127.0.0.1:8080",
+			HealthMinPeers:   3,
+			HealthMaxHeadAge: 60,
+		},
+		JSONRPC: &JSONRPC{},
+	}))
+
+	serverConfig, err := config.BuildConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8080", serverConfig.Health.Addr.String())
+	assert.Equal(t, uint64(3), serverConfig.Health.MinPeers)
+	assert.Equal(t, server.DefaultHealthMaxSyncLag, serverConfig.Health.MaxSyncLag)
+	assert.Equal(t, time.Minute, serverConfig.Health.MaxHeadAge)
+}
+
+func TestBuildConfigFork(t *testing.T) {
+	config := DefaultConfig()
+	assert.NoError(t, config.mergeConfigWith(&Config{
+		Fork:      "http://127.0.0.1:8545",
+		ForkBlock: 100,
+		Network:   &Network{},
+		TxPool:    &TxPool{},
+		Telemetry: &Telemetry{},
+		JSONRPC:   &JSONRPC{},
+	}))
+
+	// the forking requires the dev mode
+	_, err := config.BuildConfig()
+	assert.Error(t, err)
+
+	config.Dev = true
+	serverConfig, err := config.BuildConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:8545", serverConfig.Fork.URL)
+	assert.Equal(t, uint64(100), serverConfig.Fork.Block)
+
+	config.Fork = ""
+	_, err = config.BuildConfig()
+	assert.Error(t, err)
+}
+
+func TestBuildConfigStoreDirs(t *testing.T) {
+	config := DefaultConfig()
+	assert.NoError(t, config.mergeConfigWith(&Config{
+		TrieDir:    "/nvme/trie",
+		AncientDir: "/hdd/ancient",
+		Network:    &Network{},
+		TxPool:     &TxPool{},
+		Telemetry:  &Telemetry{},
+		JSONRPC:    &JSONRPC{},
+	}))
+
+	serverConfig, err := config.BuildConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, common.StoreDirs{"trie": "/nvme/trie", "ancient": "/hdd/ancient"}, serverConfig.StoreDirs)
+
+	// the chain store stays in the data directory
+	assert.Equal(t, filepath.Join(config.DataDir, "blockchain"), serverConfig.StoreDirs.Dir(config.DataDir, "blockchain"))
+}
@@ -1,6 +1,9 @@
 package helper
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -15,6 +18,7 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/consensus/dev"
 	helperFlags "github.com/0xPolygon/polygon-sdk/helper/flags"
 	"github.com/0xPolygon/polygon-sdk/secrets"
 	"github.com/0xPolygon/polygon-sdk/server"
@@ -22,6 +26,7 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/ryanuber/columnize"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -31,8 +36,11 @@ const (
 	DefaultPremineBalance = "0x3635C9ADC5DEA00000" // 1000 ETH
 	DefaultConsensus      = "pow"
 	DefaultMaxSlots       = 4096
+	DefaultDevAccounts    = 10
 	GenesisGasUsed        = 458752  // 0x70000
 	GenesisGasLimit       = 5242880 // 0x500000
+	TxPoolJournalFile     = "txpool.journal"
+	CheckpointKeyFile     = "checkpoint.key"
 )
 
 // FlagDescriptor contains the description elements for a command flag
@@ -177,15 +185,28 @@ func GenerateUsage(baseCommand string, flagMap map[string]FlagDescriptor) string
 	return output
 }
 
+// DefaultCloseTimeout is the time the client has to close once it is stopped, before it is killed
+const DefaultCloseTimeout = 5 * time.Second
+
 // HandleSignals is a helper method for handling signals sent to the console
 // Like stop, error, etc.
 func HandleSignals(closeFn func(), ui cli.Ui) int {
+	return HandleSignalsOrShutdown(closeFn, nil, DefaultCloseTimeout, ui)
+}
+
+// HandleSignalsOrShutdown is HandleSignals, which also shuts down the client when the shutdownCh is closed.
+// The client is killed if it isn't closed within the timeout
+func HandleSignalsOrShutdown(closeFn func(), shutdownCh <-chan struct{}, timeout time.Duration, ui cli.Ui) int {
 	signalCh := make(chan os.Signal, 4)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
-	sig := <-signalCh
-
-	output := fmt.Sprintf("\n[SIGNAL] Caught signal: %v\n", sig)
+	var output string
+	select {
+	case sig := <-signalCh:
+		output = fmt.Sprintf("\n[SIGNAL] Caught signal: %v\n", sig)
+	case <-shutdownCh:
+		output = "\n[SHUTDOWN] Shutdown requested\n"
+	}
 	output += "Gracefully shutting down client...\n"
 
 	ui.Output(output)
@@ -202,7 +223,7 @@ func HandleSignals(closeFn func(), ui cli.Ui) int {
 	select {
 	case <-signalCh:
 		return 1
-	case <-time.After(5 * time.Second):
+	case <-time.After(timeout):
 		return 1
 	case <-gracefulCh:
 		return 0
@@ -340,8 +361,9 @@ func generateDevGenesis(params devGenesisParams) error {
 	return WriteGenesisToDisk(cc, genesisPath)
 }
 
-// BootstrapDevCommand creates a config and generates the dev genesis file
-func BootstrapDevCommand(baseCommand string, args []string) (*Config, error) {
+// BootstrapDevCommand creates a config and generates the dev genesis file,
+// with the dev accounts it returns prefunded
+func BootstrapDevCommand(baseCommand string, args []string) (*Config, []*dev.Account, error) {
 	config := DefaultConfig()
 
 	cliConfig := &Config{
@@ -361,8 +383,10 @@ func BootstrapDevCommand(baseCommand string, args []string) (*Config, error) {
 	var premine helperFlags.ArrayFlags
 	var gaslimit uint64
 	var chainID uint64
+	var devAccounts uint64
 
 	flags.StringVar(&cliConfig.LogLevel, "log-level", DefaultConfig().LogLevel, "")
+	flags.StringVar(&cliConfig.LogFormat, "log-format", "", "")
 	flags.Var(&premine, "premine", "")
 	flags.StringVar(&cliConfig.TxPool.Locals, "locals", "", "")
 	flags.BoolVar(&cliConfig.TxPool.NoLocals, "nolocals", false, "")
@@ -370,27 +394,42 @@ func BootstrapDevCommand(baseCommand string, args []string) (*Config, error) {
 	flags.Uint64Var(&cliConfig.TxPool.MaxSlots, "max-slots", DefaultMaxSlots, "")
 	flags.Uint64Var(&gaslimit, "block-gas-limit", GenesisGasLimit, "")
 	flags.Uint64Var(&cliConfig.DevInterval, "dev-interval", 0, "")
+	flags.Uint64Var(&cliConfig.DevCatchupRate, "dev-catchup-rate", 0, "")
+	flags.StringVar(&cliConfig.Fork, "fork", "", "")
+	flags.Uint64Var(&cliConfig.ForkBlock, "fork-block", 0, "")
 	flags.Uint64Var(&chainID, "chainid", DefaultChainID, "")
 	flags.StringVar(&cliConfig.BlockGasTarget, "block-gas-target", strconv.FormatUint(0, 10), "")
+	flags.Uint64Var(&devAccounts, "dev-accounts", DefaultDevAccounts, "")
 
 	if err := flags.Parse(args); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := config.mergeConfigWith(cliConfig); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	accounts, err := dev.Accounts(devAccounts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the dev accounts get the default balance, unless premined explicitly
+	accountsPremine := helperFlags.ArrayFlags{}
+	for _, account := range accounts {
+		accountsPremine = append(accountsPremine, account.Address.String())
 	}
 
 	if err := generateDevGenesis(devGenesisParams{
 		chainName: config.Chain,
-		premine:   premine,
+		premine:   append(accountsPremine, premine...),
 		gasLimit:  gaslimit,
 		chainID:   chainID,
 	}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return config, nil
+	return config, accounts, nil
 }
 
 func ReadConfig(baseCommand string, args []string) (*Config, error) {
@@ -400,6 +439,8 @@ func ReadConfig(baseCommand string, args []string) (*Config, error) {
 		Network:   &Network{},
 		TxPool:    &TxPool{},
 		Telemetry: &Telemetry{},
+		JSONRPC:   &JSONRPC{},
+		GRPCAuth:  &GRPCAuth{},
 	}
 
 	flags := flag.NewFlagSet(baseCommand, flag.ContinueOnError)
@@ -409,25 +450,120 @@ func ReadConfig(baseCommand string, args []string) (*Config, error) {
 	var secretsConfigPath string
 
 	flags.StringVar(&cliConfig.LogLevel, "log-level", "", "")
+	flags.StringVar(&cliConfig.LogFormat, "log-format", "", "")
 	flags.BoolVar(&cliConfig.Seal, "seal", false, "")
+	flags.BoolVar(&cliConfig.StorageEncryption, "storage-encryption", false, "")
+	flags.StringVar(&cliConfig.DBBackend, "db-backend", "", "")
+	flags.Uint64Var(&cliConfig.FreezerThreshold, "freezer-threshold", 0, "")
+	flags.StringVar(&cliConfig.TrieDir, "trie-dir", "", "")
+	flags.StringVar(&cliConfig.ChainDir, "chain-dir", "", "")
+	flags.StringVar(&cliConfig.AncientDir, "ancient-dir", "", "")
+	flags.Uint64Var(&cliConfig.MaxReorgDepth, "max-reorg-depth", 0, "")
+	flags.Uint64Var(&cliConfig.ExecParallelism, "exec-parallelism", 0, "")
+	flags.BoolVar(&cliConfig.EVMProfiler, "evm-profiler", false, "")
+	flags.Uint64Var(&cliConfig.Cache, "cache", 0, "")
 	flags.StringVar(&configFile, "config", "", "")
 	flags.StringVar(&cliConfig.Chain, "chain", "", "")
 	flags.StringVar(&cliConfig.DataDir, "data-dir", "", "")
 	flags.StringVar(&cliConfig.GRPCAddr, "grpc", "", "")
+	flags.StringVar(&cliConfig.GRPCAuth.TokensFile, "grpc-tokens-file", "", "")
+	flags.StringVar(&cliConfig.GRPCAuth.TLSCert, "grpc-tls-cert", "", "")
+	flags.StringVar(&cliConfig.GRPCAuth.TLSKey, "grpc-tls-key", "", "")
+	flags.StringVar(&cliConfig.GRPCAuth.TLSClientCA, "grpc-tls-client-ca", "", "")
 	flags.StringVar(&cliConfig.JSONRPCAddr, "jsonrpc", "", "")
+	flags.StringVar(&cliConfig.JSONRPC.HTTPNamespaces, "jsonrpc-http-namespaces", "", "")
+	flags.StringVar(&cliConfig.JSONRPC.WSNamespaces, "jsonrpc-ws-namespaces", "", "")
+	flags.StringVar(&cliConfig.JSONRPC.IPCNamespaces, "jsonrpc-ipc-namespaces", "", "")
+	flags.StringVar(&cliConfig.JSONRPC.AuthNamespaces, "jsonrpc-auth-namespaces", "", "")
+	flags.StringVar(&cliConfig.JSONRPC.AuthToken, "jsonrpc-auth-token", "", "")
+	flags.StringVar(&cliConfig.JSONRPC.AuthTokenFile, "jsonrpc-auth-token-file", "", "")
+	flags.BoolVar(&cliConfig.JSONRPC.Personal, "jsonrpc-personal", false, "")
+	flags.BoolVar(&cliConfig.JSONRPC.Admin, "jsonrpc-admin", false, "")
+	flags.BoolVar(&cliConfig.JSONRPC.Debug, "jsonrpc-debug", false, "")
+	flags.BoolVar(&cliConfig.JSONRPC.Trace, "jsonrpc-trace", false, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.LogsBlockRange, "jsonrpc-logs-block-range", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.LogsLimit, "jsonrpc-logs-limit", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.CallTimeout, "jsonrpc-call-timeout", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.GasCap, "jsonrpc-gas-cap", 0, "")
+	flags.BoolVar(&cliConfig.JSONRPC.GraphQL, "graphql", false, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.ShedCPU, "jsonrpc-shed-cpu", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.ShedMemory, "jsonrpc-shed-memory", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.ShedQueue, "jsonrpc-shed-queue", 0, "")
+	flags.StringVar(&cliConfig.JSONRPC.IPCPath, "ipc-path", "", "")
+	flags.Uint64Var(&cliConfig.JSONRPC.RateLimit, "jsonrpc-rate-limit", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.RateBurst, "jsonrpc-rate-burst", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.MaxConcurrent, "jsonrpc-max-concurrent", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.CallCacheTTL, "jsonrpc-call-cache-ttl", 0, "")
+	flags.Uint64Var(&cliConfig.JSONRPC.CallCacheSize, "jsonrpc-call-cache-size", 0, "")
 	flags.StringVar(&cliConfig.Join, "join", "", "")
+	flags.StringVar(&cliConfig.ValidatorRegistry, "validator-registry", "", "")
+	flags.StringVar(&cliConfig.SyncMode, "sync-mode", "", "")
+	flags.StringVar(&cliConfig.Pruning, "pruning", "", "")
+	flags.Uint64Var(&cliConfig.PruningRetention, "pruning-retention", 0, "")
+	flags.BoolVar(&cliConfig.LightServe, "light-serve", false, "")
+	flags.BoolVar(&cliConfig.AddressIndex, "address-index", false, "")
+	flags.Uint64Var(&cliConfig.ShutdownTimeout, "shutdown-timeout", 0, "")
 	flags.StringVar(&cliConfig.Network.Addr, "libp2p", "", "")
 	flags.StringVar(&cliConfig.Telemetry.PrometheusAddr, "prometheus", "", "")
+	flags.StringVar(&cliConfig.Telemetry.TracingAddr, "tracing", "", "")
+	flags.BoolVar(&cliConfig.Telemetry.TracingInsecure, "tracing-insecure", false, "")
+	flags.Uint64Var(&cliConfig.Telemetry.TracingSampleRate, "tracing-sample-rate", 0, "")
+	flags.StringVar(&cliConfig.Telemetry.HealthAddr, "health", "", "")
+	flags.StringVar(&cliConfig.EthStats, "ethstats", "", "")
+	flags.StringVar(&cliConfig.Checkpoint, "checkpoint", "", "")
+	flags.StringVar(&cliConfig.CheckpointContract, "checkpoint-contract", "", "")
+	flags.Uint64Var(&cliConfig.CheckpointInterval, "checkpoint-interval", 0, "")
+	flags.StringVar(&cliConfig.CheckpointKey, "checkpoint-key", "", "")
+	flags.StringVar(&cliConfig.Bridge, "bridge", "", "")
+	flags.StringVar(&cliConfig.BridgeRootContract, "bridge-root-contract", "", "")
+	flags.StringVar(&cliConfig.BridgeStateReceiver, "bridge-state-receiver", "", "")
+	flags.StringVar(&cliConfig.BridgeExitContract, "bridge-exit-contract", "", "")
+	flags.Uint64Var(&cliConfig.BridgeConfirmations, "bridge-confirmations", 0, "")
+	flags.Uint64Var(&cliConfig.BridgeStartBlock, "bridge-start-block", 0, "")
+	flags.StringVar(&cliConfig.Export, "export", "", "")
+	flags.StringVar(&cliConfig.ExportSink, "export-sink", "", "")
+	flags.StringVar(&cliConfig.ExportTopic, "export-topic", "", "")
+	flags.Uint64Var(&cliConfig.Telemetry.HealthMinPeers, "health-min-peers", 0, "")
+	flags.Uint64Var(&cliConfig.Telemetry.HealthMaxSyncLag, "health-max-sync-lag", 0, "")
+	flags.Uint64Var(&cliConfig.Telemetry.HealthMaxHeadAge, "health-max-head-age", 0, "")
 	flags.StringVar(&cliConfig.Network.NatAddr, "nat", "", "the external IP address without port, as can be seen by peers")
 	flags.StringVar(&cliConfig.Network.Dns, "dns", "", " the host DNS address which can be used by a remote peer for connection")
 	flags.BoolVar(&cliConfig.Network.NoDiscover, "no-discover", false, "")
 	flags.Uint64Var(&cliConfig.Network.MaxPeers, "max-peers", 0, "")
+	flags.StringVar(&cliConfig.Network.ConsortiumCA, "consortium-ca", "", "")
+	flags.StringVar(&cliConfig.Network.ConsortiumCert, "consortium-cert", "", "")
+	flags.BoolVar(&cliConfig.Network.NATPortMap, "nat-port-map", false, "")
+	flags.BoolVar(&cliConfig.Network.NATService, "nat-service", false, "")
+	flags.StringVar(&cliConfig.Network.Reachability, "reachability", "", "")
+	flags.StringVar(&cliConfig.Network.Relays, "relays", "", "")
+	flags.BoolVar(&cliConfig.Network.RelayHop, "relay-hop", false, "")
+	flags.StringVar(&cliConfig.Network.StaticPeers, "static-peers", "", "")
+	flags.StringVar(&cliConfig.Network.TrustedPeers, "trusted-peers", "", "")
+	flags.StringVar(&cliConfig.Network.DNSDiscovery, "dns-discovery", "", "")
+	flags.BoolVar(&cliConfig.Network.NoCompression, "no-compression", false, "")
+	flags.StringVar(&cliConfig.Network.MaxMessageSize, "max-msg-size", "", "")
+	flags.StringVar(&cliConfig.Network.Allowlist, "allowlist", "", "")
+	flags.StringVar(&cliConfig.Network.ConsensusAddr, "consensus-libp2p", "", "")
+	flags.Uint64Var(&cliConfig.Network.ConsensusMaxPeers, "consensus-max-peers", 0, "")
+	flags.StringVar(&cliConfig.Network.ConsensusPeers, "consensus-peers", "", "")
+	flags.StringVar(&cliConfig.Network.AllowlistContract, "allowlist-contract", "", "")
 	flags.StringVar(&cliConfig.TxPool.Locals, "locals", "", "")
 	flags.BoolVar(&cliConfig.TxPool.NoLocals, "nolocals", false, "")
 	flags.Uint64Var(&cliConfig.TxPool.PriceLimit, "price-limit", 0, "")
 	flags.Uint64Var(&cliConfig.TxPool.MaxSlots, "max-slots", DefaultMaxSlots, "")
+	flags.Uint64Var(&cliConfig.TxPool.PriceBump, "price-bump", 0, "")
+	flags.BoolVar(&cliConfig.TxPool.NoJournal, "nojournal", false, "")
+	flags.Uint64Var(&cliConfig.TxPool.QueueLifetime, "queue-lifetime", 0, "")
+	flags.Uint64Var(&cliConfig.TxPool.TxLifetime, "tx-lifetime", 0, "")
+	flags.Uint64Var(&cliConfig.TxPool.MaxAccountPending, "max-account-pending", 0, "")
+	flags.Uint64Var(&cliConfig.TxPool.MaxAccountQueued, "max-account-queued", 0, "")
+	flags.Uint64Var(&cliConfig.TxPool.MinGasPrice, "min-gas-price", 0, "")
+	flags.StringVar(&cliConfig.TxPool.ZeroGasAllowlist, "zero-gas-allowlist", "", "")
 	flags.BoolVar(&cliConfig.Dev, "dev", false, "")
 	flags.Uint64Var(&cliConfig.DevInterval, "dev-interval", 0, "")
+	flags.Uint64Var(&cliConfig.DevCatchupRate, "dev-catchup-rate", 0, "")
+	flags.StringVar(&cliConfig.Fork, "fork", "", "")
+	flags.Uint64Var(&cliConfig.ForkBlock, "fork-block", 0, "")
 	flags.StringVar(&cliConfig.BlockGasTarget, "block-gas-target", strconv.FormatUint(0, 10), "")
 	flags.StringVar(&secretsConfigPath, "secrets-config", "", "")
 
@@ -472,6 +608,13 @@ type Meta struct {
 	UI   cli.Ui
 	Addr string
 
+	// gRPC credentials
+	Token     string
+	TokenFile string
+	TLSCA     string
+	TLSCert   string
+	TLSKey    string
+
 	FlagMap map[string]FlagDescriptor
 }
 
@@ -487,19 +630,75 @@ func (m *Meta) DefineFlags() {
 		ArgumentsOptional: false,
 		FlagOptional:      true,
 	}
+
+	m.FlagMap["grpc-token"] = FlagDescriptor{
+		Description: "Sets the bearer token used to authenticate with the gRPC API. " +
+			"The token is visible in the process list and the shell history, prefer --grpc-token-file",
+		Arguments: []string{
+			"TOKEN",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	m.FlagMap["grpc-token-file"] = FlagDescriptor{
+		Description: "Sets the path to the file holding the bearer token used to authenticate with the gRPC API",
+		Arguments: []string{
+			"TOKEN_FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	m.FlagMap["grpc-tls-ca"] = FlagDescriptor{
+		Description: "Sets the path to the CA of the gRPC API certificate, and connects over TLS",
+		Arguments: []string{
+			"TLS_CA",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	m.FlagMap["grpc-tls-cert"] = FlagDescriptor{
+		Description: "Sets the path to the client certificate presented to the gRPC API",
+		Arguments: []string{
+			"TLS_CERT",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	m.FlagMap["grpc-tls-key"] = FlagDescriptor{
+		Description: "Sets the path to the private key of the gRPC client certificate",
+		Arguments: []string{
+			"TLS_KEY",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
 }
 
 // FlagSet adds some default commands to handle grpc connections with the server
 func (m *Meta) FlagSet(n string) *flag.FlagSet {
 	f := flag.NewFlagSet(n, flag.ContinueOnError)
 	f.StringVar(&m.Addr, "grpc-address", fmt.Sprintf("%s:%d", "127.0.0.1", server.DefaultGRPCPort), "")
+	f.StringVar(&m.Token, "grpc-token", "", "")
+	f.StringVar(&m.TokenFile, "grpc-token-file", "", "")
+	f.StringVar(&m.TLSCA, "grpc-tls-ca", "", "")
+	f.StringVar(&m.TLSCert, "grpc-tls-cert", "", "")
+	f.StringVar(&m.TLSKey, "grpc-tls-key", "", "")
 
 	return f
 }
 
 // Conn returns a grpc connection
 func (m *Meta) Conn() (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(m.Addr, grpc.WithInsecure())
+	opts, err := m.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(m.Addr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to server: %v", err)
 	}
@@ -507,6 +706,73 @@ func (m *Meta) Conn() (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
+// dialOptions returns the transport and the per-call credentials set by the flags
+func (m *Meta) dialOptions() ([]grpc.DialOption, error) {
+	opts := []grpc.DialOption{}
+
+	secure := m.TLSCA != "" || m.TLSCert != ""
+	if secure {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+		if m.TLSCA != "" {
+			pem, err := ioutil.ReadFile(m.TLSCA)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the gRPC CA: %v", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in the gRPC CA %s", m.TLSCA)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if m.TLSCert != "" {
+			cert, err := tls.LoadX509KeyPair(m.TLSCert, m.TLSKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the gRPC client certificate: %v", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	token := m.Token
+	if m.TokenFile != "" {
+		if token != "" {
+			return nil, errors.New("only one of the gRPC token and the gRPC token file can be set")
+		}
+		data, err := ioutil.ReadFile(m.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the gRPC token file: %v", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&bearerToken{token: token, secure: secure}))
+	}
+
+	return opts, nil
+}
+
+// bearerToken passes the token in the authorization metadata of every call
+type bearerToken struct {
+	token  string
+	secure bool
+}
+
+// GetRequestMetadata implements the credentials.PerRPCCredentials interface
+func (b *bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + b.token}, nil
+}
+
+// RequireTransportSecurity implements the credentials.PerRPCCredentials interface.
+// The token is also sent over plaintext connections, which are meant for the local gRPC API
+func (b *bearerToken) RequireTransportSecurity() bool {
+	return b.secure
+}
+
 // OUTPUT FORMATTING //
 
 // FormatList formats a list, using a specific blank value replacement
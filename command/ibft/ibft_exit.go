@@ -0,0 +1,120 @@
+package ibft
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-sdk/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// IbftExit is the command to remove the validator from the validator set
+type IbftExit struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (p *IbftExit) DefineFlags() {
+	if p.FlagMap == nil {
+		// Flag map not initialized
+		p.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	p.FlagMap["shutdown"] = helper.FlagDescriptor{
+		Description: "Shuts down the client once it is out of the validator set. Default: false",
+		Arguments: []string{
+			"SHUTDOWN",
+		},
+		ArgumentsOptional: true,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (p *IbftExit) GetHelperText() string {
+	return "Proposes the removal of the validator from the validator set, waits until the other validators " +
+		"voted it out, and stops sealing"
+}
+
+func (p *IbftExit) GetBaseCommand() string {
+	return "ibft exit"
+}
+
+// Help implements the cli.IbftExit interface
+func (p *IbftExit) Help() string {
+	p.Meta.DefineFlags()
+	p.DefineFlags()
+
+	return helper.GenerateHelp(p.Synopsis(), helper.GenerateUsage(p.GetBaseCommand(), p.FlagMap), p.FlagMap)
+}
+
+// Synopsis implements the cli.IbftExit interface
+func (p *IbftExit) Synopsis() string {
+	return p.GetHelperText()
+}
+
+// Run implements the cli.IbftExit interface
+func (p *IbftExit) Run(args []string) int {
+	flags := p.FlagSet(p.GetBaseCommand())
+
+	var shutdown bool
+	flags.BoolVar(&shutdown, "shutdown", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	conn, err := p.Conn()
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := ibftOp.NewIbftOperatorClient(conn)
+	stream, err := clt.Exit(context.Background(), &empty.Empty{})
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	p.UI.Output("\n[IBFT EXIT]\nProposed the removal of the validator, waiting for the votes of the other validators\n")
+
+	var last *ibftOp.ExitEvent
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			p.UI.Error(err.Error())
+			return 1
+		}
+
+		last = event
+		if !event.Exited {
+			p.UI.Output(fmt.Sprintf("Block %d: %d of %d validators voted for the removal", event.Number, event.Votes, event.Validators))
+		}
+	}
+
+	if last == nil || !last.Exited {
+		p.UI.Error("The exit was interrupted")
+		return 1
+	}
+
+	p.UI.Info(fmt.Sprintf("\nThe validator is out of the validator set at block %d, sealing stopped\n", last.Number))
+
+	if shutdown {
+		if _, err := proto.NewSystemClient(conn).Shutdown(context.Background(), &empty.Empty{}); err != nil {
+			p.UI.Error(fmt.Sprintf("Failed to shut down the client: %v", err))
+			return 1
+		}
+
+		p.UI.Info("The client is shutting down\n")
+	}
+
+	return 0
+}
@@ -60,6 +60,11 @@ func (p *IbftStatus) Run(args []string) int {
 	var output = "\n[VALIDATOR STATUS]\n"
 	output += helper.FormatKV([]string{
 		fmt.Sprintf("Vaidator key|%s", resp.Key),
+		fmt.Sprintf("Validator|%v", resp.Validator),
+		fmt.Sprintf("State|%s", resp.State),
+		fmt.Sprintf("Sequence|%d", resp.Sequence),
+		fmt.Sprintf("Round|%d", resp.Round),
+		fmt.Sprintf("Proposer|%s", resp.Proposer),
 	})
 
 	output += "\n"
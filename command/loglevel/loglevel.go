@@ -0,0 +1,96 @@
+package loglevel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// LogLevelCommand is the command to show or change the log levels of a running client
+type LogLevelCommand struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *LogLevelCommand) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["set"] = helper.FlagDescriptor{
+		Description: "Replaces the log level and the levels of the modules (e.g. info,ibft=debug,network=warn). " +
+			"The log level is kept if only the modules are set",
+		Arguments: []string{
+			"LOG_LEVEL",
+		},
+		FlagOptional: true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *LogLevelCommand) GetHelperText() string {
+	return "Returns the log levels of the Polygon SDK client, or changes them without restarting it"
+}
+
+func (c *LogLevelCommand) GetBaseCommand() string {
+	return "log-level"
+}
+
+// Help implements the cli.Command interface
+func (c *LogLevelCommand) Help() string {
+	c.Meta.DefineFlags()
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *LogLevelCommand) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *LogLevelCommand) Run(args []string) int {
+	flags := c.FlagSet(c.GetBaseCommand())
+
+	var set string
+	flags.StringVar(&set, "set", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	conn, err := c.Conn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+
+	var resp *proto.LogLevel
+	if set != "" {
+		resp, err = clt.SetLogLevel(context.Background(), &proto.LogLevel{Spec: set})
+	} else {
+		resp, err = clt.GetLogLevel(context.Background(), &empty.Empty{})
+	}
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	output := "\n[LOG LEVEL]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Levels|%s", resp.Spec),
+	})
+	output += "\n"
+
+	c.UI.Output(output)
+
+	return 0
+}
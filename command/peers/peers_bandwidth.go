@@ -0,0 +1,83 @@
+package peers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// PeersBandwidth is the command to show the traffic with the peers
+type PeersBandwidth struct {
+	helper.Meta
+}
+
+// GetHelperText returns a simple description of the command
+func (p *PeersBandwidth) GetHelperText() string {
+	return "Returns the bytes exchanged with the peers by protocol, the largest senders of the node first"
+}
+
+func (p *PeersBandwidth) GetBaseCommand() string {
+	return "peers bandwidth"
+}
+
+// Help implements the cli.PeersBandwidth interface
+func (p *PeersBandwidth) Help() string {
+	p.Meta.DefineFlags()
+
+	return helper.GenerateHelp(p.Synopsis(), helper.GenerateUsage(p.GetBaseCommand(), p.FlagMap), p.FlagMap)
+}
+
+// Synopsis implements the cli.PeersBandwidth interface
+func (p *PeersBandwidth) Synopsis() string {
+	return p.GetHelperText()
+}
+
+// Run implements the cli.PeersBandwidth interface
+func (p *PeersBandwidth) Run(args []string) int {
+	flags := p.FlagSet(p.GetBaseCommand())
+	if err := flags.Parse(args); err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	conn, err := p.Conn()
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+	resp, err := clt.PeersBandwidth(context.Background(), &empty.Empty{})
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	output := "\n[PEERS BANDWIDTH]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Total in|%d bytes", resp.TotalIn),
+		fmt.Sprintf("Total out|%d bytes", resp.TotalOut),
+	})
+	output += "\n"
+
+	for _, peer := range resp.Peers {
+		output += fmt.Sprintf("\n[%s]\n", peer.Id)
+
+		rows := []string{
+			fmt.Sprintf("In|%d bytes (%.0f B/s)", peer.TotalIn, peer.RateIn),
+			fmt.Sprintf("Out|%d bytes (%.0f B/s)", peer.TotalOut, peer.RateOut),
+		}
+		for _, proto := range peer.Protocols {
+			rows = append(rows, fmt.Sprintf("%s|in %d, out %d bytes", proto.Protocol, proto.In, proto.Out))
+		}
+		output += helper.FormatKV(rows)
+		output += "\n"
+	}
+
+	p.UI.Output(output)
+
+	return 0
+}
@@ -0,0 +1,139 @@
+package peers
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	helperFlags "github.com/0xPolygon/polygon-sdk/helper/flags"
+	"github.com/0xPolygon/polygon-sdk/network"
+)
+
+// PeersDNSTree is the command to build the DNS TXT records serving a list of bootnodes
+type PeersDNSTree struct {
+	helper.Meta
+}
+
+func (p *PeersDNSTree) DefineFlags() {
+	if p.FlagMap == nil {
+		// Flag map not initialized
+		p.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	p.FlagMap["key"] = helper.FlagDescriptor{
+		Description: "The file of the secp256k1 key signing the tree. It is generated if it doesn't exist",
+		Arguments: []string{
+			"KEY_FILE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	p.FlagMap["domain"] = helper.FlagDescriptor{
+		Description: "The domain serving the tree",
+		Arguments: []string{
+			"DOMAIN",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	p.FlagMap["seq"] = helper.FlagDescriptor{
+		Description: "The sequence number of the tree, to be increased on every update. Default: 1",
+		Arguments: []string{
+			"SEQUENCE",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      true,
+	}
+
+	p.FlagMap["addr"] = helper.FlagDescriptor{
+		Description: "Bootnode's libp2p address in the multiaddr format",
+		Arguments: []string{
+			"BOOTNODE_ADDRESS",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (p *PeersDNSTree) GetHelperText() string {
+	return "Builds the signed DNS TXT records serving the bootnodes, and the enrtree:// URL of the tree"
+}
+
+func (p *PeersDNSTree) GetBaseCommand() string {
+	return "peers dns-tree"
+}
+
+// Help implements the cli.Command interface
+func (p *PeersDNSTree) Help() string {
+	p.DefineFlags()
+
+	return helper.GenerateHelp(p.Synopsis(), helper.GenerateUsage(p.GetBaseCommand(), p.FlagMap), p.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (p *PeersDNSTree) Synopsis() string {
+	return p.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (p *PeersDNSTree) Run(args []string) int {
+	flags := flag.NewFlagSet(p.GetBaseCommand(), flag.ContinueOnError)
+
+	var keyFile, domain string
+	var seq uint64
+	var addrs = make(helperFlags.ArrayFlags, 0)
+
+	flags.StringVar(&keyFile, "key", "", "")
+	flags.StringVar(&domain, "domain", "", "")
+	flags.Uint64Var(&seq, "seq", 1, "")
+	flags.Var(&addrs, "addr", "")
+
+	if err := flags.Parse(args); err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	if keyFile == "" || domain == "" || len(addrs) == 0 {
+		p.UI.Error("the key file, the domain and at least one address are required")
+		return 1
+	}
+
+	key, err := crypto.GenerateOrReadPrivateKey(keyFile)
+	if err != nil {
+		p.UI.Error(fmt.Sprintf("Failed to read the key: %v", err))
+		return 1
+	}
+
+	records, err := network.MakeDNSTreeRecords(key, domain, seq, addrs)
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	names := make([]string, 0, len(records))
+	for name := range records {
+		if name != domain {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	rows := []string{fmt.Sprintf("%s|%s", domain, records[domain])}
+	for _, name := range names {
+		rows = append(rows, fmt.Sprintf("%s|%s", name, records[name]))
+	}
+
+	tree := &network.DNSTree{PubKey: &key.PublicKey, Domain: domain}
+
+	p.UI.Output("\n[DNS TREE]\n")
+	p.UI.Output(helper.FormatKV([]string{fmt.Sprintf("URL|%s", tree.URL())}))
+	p.UI.Output("\n[TXT RECORDS]\n")
+	p.UI.Output(helper.FormatKV(rows))
+
+	return 0
+}
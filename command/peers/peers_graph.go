@@ -0,0 +1,162 @@
+package peers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// PeersGraph is the command to show the local view of the network topology
+type PeersGraph struct {
+	helper.Meta
+}
+
+func (p *PeersGraph) DefineFlags() {
+	if p.FlagMap == nil {
+		// Flag map not initialized
+		p.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	p.FlagMap["dot"] = helper.FlagDescriptor{
+		Description:  "Outputs the graph in the DOT language, to be rendered with Graphviz",
+		FlagOptional: true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (p *PeersGraph) GetHelperText() string {
+	return "Returns the connected peers, and the peers they reported to the discovery of the node"
+}
+
+func (p *PeersGraph) GetBaseCommand() string {
+	return "peers graph"
+}
+
+// Help implements the cli.PeersGraph interface
+func (p *PeersGraph) Help() string {
+	p.Meta.DefineFlags()
+	p.DefineFlags()
+
+	return helper.GenerateHelp(p.Synopsis(), helper.GenerateUsage(p.GetBaseCommand(), p.FlagMap), p.FlagMap)
+}
+
+// Synopsis implements the cli.PeersGraph interface
+func (p *PeersGraph) Synopsis() string {
+	return p.GetHelperText()
+}
+
+// Run implements the cli.PeersGraph interface
+func (p *PeersGraph) Run(args []string) int {
+	flags := p.FlagSet(p.GetBaseCommand())
+
+	var dot bool
+	flags.BoolVar(&dot, "dot", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	conn, err := p.Conn()
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+	resp, err := clt.PeersGraph(context.Background(), &empty.Empty{})
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	if dot {
+		p.UI.Output(formatPeersGraphDOT(resp))
+
+		return 0
+	}
+
+	output := "\n[PEERS GRAPH]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Node|%s", resp.Id),
+		fmt.Sprintf("Peers|%d", len(resp.Peers)),
+	})
+	output += "\n"
+
+	reported := map[string][]string{}
+	for _, link := range resp.Links {
+		reported[link.From] = append(reported[link.From], link.To)
+	}
+
+	for _, peer := range resp.Peers {
+		output += fmt.Sprintf("\n[%s]\n", peer.Id)
+
+		rows := []string{
+			fmt.Sprintf("Direction|%s", peer.Direction),
+			fmt.Sprintf("Latency|%s", formatLatency(peer.Latency)),
+		}
+		for i, id := range reported[peer.Id] {
+			rows = append(rows, fmt.Sprintf("Reported [%d]|%s", i, id))
+		}
+		output += helper.FormatKV(rows)
+		output += "\n"
+	}
+
+	p.UI.Output(output)
+
+	return 0
+}
+
+// formatPeersGraphDOT formats the graph in the DOT language. The edges of the connections go from the
+// dialing peer, and the peers reported by the connected peers are linked to them with dashed edges
+func formatPeersGraphDOT(graph *proto.PeersGraphResponse) string {
+	var b strings.Builder
+
+	b.WriteString("digraph peers {\n")
+	fmt.Fprintf(&b, "  %q [label=%q, shape=doublecircle];\n", graph.Id, shortPeerID(graph.Id))
+
+	connected := map[string]struct{}{graph.Id: {}}
+	for _, peer := range graph.Peers {
+		connected[peer.Id] = struct{}{}
+
+		label := shortPeerID(peer.Id)
+		if peer.ClientVersion != "" {
+			label += "\n" + peer.ClientVersion
+		}
+		fmt.Fprintf(&b, "  %q [label=%q];\n", peer.Id, label)
+
+		from, to := graph.Id, peer.Id
+		if peer.Direction == "Inbound" {
+			from, to = to, from
+		}
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", from, to, formatLatency(peer.Latency))
+	}
+
+	for _, link := range graph.Links {
+		if link.To == graph.Id {
+			continue
+		}
+		if _, ok := connected[link.To]; !ok {
+			connected[link.To] = struct{}{}
+			fmt.Fprintf(&b, "  %q [label=%q, style=dashed];\n", link.To, shortPeerID(link.To))
+		}
+		fmt.Fprintf(&b, "  %q -> %q [style=dashed];\n", link.From, link.To)
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// shortPeerID returns the end of the peer ID, which tells the peers apart in the graph
+func shortPeerID(id string) string {
+	if len(id) <= 8 {
+		return id
+	}
+
+	return "*" + id[len(id)-8:]
+}
@@ -0,0 +1,40 @@
+package peers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPeersGraphDOT(t *testing.T) {
+	graph := &proto.PeersGraphResponse{
+		Id: "node",
+		Peers: []*proto.Peer{
+			{Id: "in", Direction: "Inbound", Latency: 2000000, ClientVersion: "polygon-sdk/0.1.0"},
+			{Id: "out", Direction: "Outbound"},
+		},
+		Links: []*proto.PeerLink{
+			{From: "in", To: "out"},
+			{From: "in", To: "far"},
+			{From: "out", To: "far"},
+			{From: "out", To: "node"},
+		},
+	}
+
+	dot := formatPeersGraphDOT(graph)
+	assert.True(t, strings.HasPrefix(dot, "digraph peers {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+
+	// the connections go from the dialing peer
+	assert.Contains(t, dot, `"in" -> "node" [label="2ms"];`)
+	assert.Contains(t, dot, `"node" -> "out" [label="unknown"];`)
+	assert.Contains(t, dot, `"in" [label="in\npolygon-sdk/0.1.0"];`)
+
+	// the reported peers are declared once, the node isn't linked to itself
+	assert.Equal(t, 1, strings.Count(dot, `"far" [label="far", style=dashed];`))
+	assert.Contains(t, dot, `"in" -> "out" [style=dashed];`)
+	assert.Contains(t, dot, `"out" -> "far" [style=dashed];`)
+	assert.NotContains(t, dot, `"out" -> "node"`)
+}
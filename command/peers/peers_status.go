@@ -3,9 +3,11 @@ package peers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/command/helper"
 	"github.com/0xPolygon/polygon-sdk/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // PeersStatus is the PeersStatus to start the sever
@@ -25,12 +27,14 @@ func (p *PeersStatus) DefineFlags() {
 			"PEER_ID",
 		},
 		ArgumentsOptional: false,
+		FlagOptional:      true,
 	}
 }
 
 // GetHelperText returns a simple description of the command
 func (p *PeersStatus) GetHelperText() string {
-	return "Returns the status of the specified peer, using the libp2p ID of the peer node"
+	return "Returns the status of the specified peer, using the libp2p ID of the peer node, " +
+		"or of all the connected peers"
 }
 
 func (p *PeersStatus) GetBaseCommand() string {
@@ -62,11 +66,6 @@ func (p *PeersStatus) Run(args []string) int {
 		return 1
 	}
 
-	if nodeId == "" {
-		p.UI.Error("peer-id argument not provided")
-		return 1
-	}
-
 	conn, err := p.Conn()
 	if err != nil {
 		p.UI.Error(err.Error())
@@ -74,14 +73,34 @@ func (p *PeersStatus) Run(args []string) int {
 	}
 
 	clt := proto.NewSystemClient(conn)
-	resp, err := clt.PeersStatus(context.Background(), &proto.PeersStatusRequest{Id: nodeId})
-	if err != nil {
-		p.UI.Error(err.Error())
-		return 1
-	}
 
 	var output = "\n[PEER STATUS]\n"
-	output += formatPeerStatus(resp)
+
+	if nodeId != "" {
+		resp, err := clt.PeersStatus(context.Background(), &proto.PeersStatusRequest{Id: nodeId})
+		if err != nil {
+			p.UI.Error(err.Error())
+			return 1
+		}
+
+		output += formatPeerStatus(resp)
+	} else {
+		resp, err := clt.PeersList(context.Background(), &empty.Empty{})
+		if err != nil {
+			p.UI.Error(err.Error())
+			return 1
+		}
+
+		if len(resp.Peers) == 0 {
+			output += "No peers found"
+		}
+		for i, peer := range resp.Peers {
+			if i != 0 {
+				output += "\n\n"
+			}
+			output += formatPeerStatus(peer)
+		}
+	}
 
 	output += "\n"
 
@@ -94,7 +113,28 @@ func (p *PeersStatus) Run(args []string) int {
 func formatPeerStatus(peer *proto.Peer) string {
 	return helper.FormatKV([]string{
 		fmt.Sprintf("ID|%s", peer.Id),
+		fmt.Sprintf("Direction|%s", peer.Direction),
 		fmt.Sprintf("Protocols|%s", peer.Protocols),
 		fmt.Sprintf("Addresses|%s", peer.Addrs),
+		fmt.Sprintf("Latency|%s", formatLatency(peer.Latency)),
+		fmt.Sprintf("Client version|%s", formatClientVersion(peer.ClientVersion)),
 	})
 }
+
+// formatLatency formats the latency of a peer, unknown until it is measured
+func formatLatency(latency int64) string {
+	if latency == 0 {
+		return "unknown"
+	}
+
+	return time.Duration(latency).Round(time.Microsecond).String()
+}
+
+// formatClientVersion formats the client version of a peer, unknown until it is identified
+func formatClientVersion(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+
+	return version
+}
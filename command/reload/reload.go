@@ -0,0 +1,80 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ReloadCommand is the command to reload the config of a running client
+type ReloadCommand struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (c *ReloadCommand) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (c *ReloadCommand) GetHelperText() string {
+	return "Reloads the config of the Polygon SDK client without restarting it: the gas price floor, " +
+		"the block gas target, the JSON-RPC limits, the log levels and the peer lists"
+}
+
+func (c *ReloadCommand) GetBaseCommand() string {
+	return "reload"
+}
+
+// Help implements the cli.Command interface
+func (c *ReloadCommand) Help() string {
+	c.Meta.DefineFlags()
+	c.DefineFlags()
+
+	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ReloadCommand) Synopsis() string {
+	return c.GetHelperText()
+}
+
+// Run implements the cli.Command interface
+func (c *ReloadCommand) Run(args []string) int {
+	flags := c.FlagSet(c.GetBaseCommand())
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	conn, err := c.Conn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := proto.NewSystemClient(conn)
+	resp, err := clt.Reload(context.Background(), &empty.Empty{})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	output := "\n[RELOADED]\n"
+	rows := make([]string, len(resp.Reloaded))
+	for i, setting := range resp.Reloaded {
+		rows[i] = fmt.Sprintf("[%d]|%s", i, setting)
+	}
+	output += helper.FormatList(rows)
+	output += "\n"
+
+	c.UI.Output(output)
+
+	return 0
+}
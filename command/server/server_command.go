@@ -2,11 +2,19 @@ package server
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/0xPolygon/polygon-sdk/bridge"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/checkpoint"
 	"github.com/0xPolygon/polygon-sdk/command/helper"
+	"github.com/0xPolygon/polygon-sdk/exporter"
+	"github.com/0xPolygon/polygon-sdk/helper/logging"
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
 	"github.com/0xPolygon/polygon-sdk/network"
 	"github.com/0xPolygon/polygon-sdk/server"
-	"github.com/hashicorp/go-hclog"
+	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/mitchellh/cli"
 )
 
@@ -30,13 +38,22 @@ func (c *ServerCommand) DefineFlags() {
 	}
 
 	c.flagMap["log-level"] = helper.FlagDescriptor{
-		Description: fmt.Sprintf("Sets the log level for console output. Default: %s", helper.DefaultConfig().LogLevel),
+		Description: fmt.Sprintf("Sets the log level for console output, optionally followed by the levels "+
+			"of the modules (e.g. info,ibft=debug,network=warn). Default: %s", helper.DefaultConfig().LogLevel),
 		Arguments: []string{
 			"LOG_LEVEL",
 		},
 		FlagOptional: true,
 	}
 
+	c.flagMap["log-format"] = helper.FlagDescriptor{
+		Description: "Sets the format of the log output, text or json. Default: text",
+		Arguments: []string{
+			"LOG_FORMAT",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["seal"] = helper.FlagDescriptor{
 		Description: "Sets the flag indicating that the client should seal blocks. Default: false",
 		Arguments: []string{
@@ -45,8 +62,163 @@ func (c *ServerCommand) DefineFlags() {
 		FlagOptional: true,
 	}
 
+	c.flagMap["storage-encryption"] = helper.FlagDescriptor{
+		Description: "Sets the flag indicating that the data stores are encrypted at rest, with a key " +
+			"held by the secrets manager. It can only be enabled on a new data directory. Default: false",
+		Arguments: []string{
+			"STORAGE_ENCRYPTION",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["db-backend"] = helper.FlagDescriptor{
+		Description: "Sets the key-value database backend of the data stores, leveldb or badger. The badger " +
+			"backend rewrites less data during the compactions, which speeds up the block import. Default: leveldb",
+		Arguments: []string{
+			"DB_BACKEND",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["freezer-threshold"] = helper.FlagDescriptor{
+		Description: "Sets the number of recent blocks kept in the database. The headers, bodies and receipts of the " +
+			"older blocks are moved to compressed append-only files, and can't be reorganized anymore. Default: 0 (disabled)",
+		Arguments: []string{
+			"BLOCKS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["trie-dir"] = helper.FlagDescriptor{
+		Description: "Sets the directory of the state trie store, to place the hot state on a faster volume. " +
+			"The store is not moved, it must be moved while the node is stopped. Default: the trie directory of the data directory",
+		Arguments: []string{
+			"TRIE_DIRECTORY",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["chain-dir"] = helper.FlagDescriptor{
+		Description: "Sets the directory of the blocks store. " +
+			"The store is not moved, it must be moved while the node is stopped. Default: the blockchain directory of the data directory",
+		Arguments: []string{
+			"CHAIN_DIRECTORY",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["ancient-dir"] = helper.FlagDescriptor{
+		Description: "Sets the directory of the freezer files, to place the old blocks on a cheaper volume. " +
+			"The files are not moved, they must be moved while the node is stopped. Default: the ancient directory of the data directory",
+		Arguments: []string{
+			"ANCIENT_DIRECTORY",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["max-reorg-depth"] = helper.FlagDescriptor{
+		Description: "Sets the maximum number of canonical blocks a chain reorganization replaces. The heavier " +
+			"side chains forking deeper are kept as forks, but not made canonical. Default: 0 (no limit)",
+		Arguments: []string{
+			"BLOCKS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["exec-parallelism"] = helper.FlagDescriptor{
+		Description: "Sets the number of transactions of a block executed in parallel. The transactions touching " +
+			"the accounts modified by the previous transactions of the block are executed again, one by one. Default: 0 (sequential)",
+		Arguments: []string{
+			"TRANSACTIONS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["evm-profiler"] = helper.FlagDescriptor{
+		Description: "Sets the flag indicating that the gas and time spent per opcode and per contract by the processed blocks " +
+			"are aggregated, and served by debug_evmProfile and the Prometheus metrics. It slows down the execution. Default: false",
+		Arguments: []string{
+			"EVM_PROFILER",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["cache"] = helper.FlagDescriptor{
+		Description: "Sets the memory budget, in MB, of the cache of the state trie nodes and contract codes between " +
+			"the state and its database. A quarter of it keeps the nodes written by the latest blocks. Default: 0 (disabled)",
+		Arguments: []string{
+			"CACHE_MB",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["validator-registry"] = helper.FlagDescriptor{
+		Description: "Sets the HTTP endpoint the validator set of each IBFT epoch is posted to, along with " +
+			"the peer ID, the addresses and the peer count of the node, for the dashboards of the network. Default: disabled",
+		Arguments: []string{
+			"REGISTRY_URL",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["sync-mode"] = helper.FlagDescriptor{
+		Description: "Sets how an empty chain is synced. In the snapshot mode, the state of a recent block is downloaded " +
+			"along with the headers up to it, instead of executing every block from genesis. Default: full",
+		Arguments: []string{
+			"SYNC_MODE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["pruning"] = helper.FlagDescriptor{
+		Description: "Sets which states are kept. The archive mode keeps every state, the full mode the states of " +
+			"the last 128 blocks and of every 8192th block, and the light mode the states of the last 16 blocks. " +
+			"Setting the archive mode explicitly also refuses to start a node whose state was pruned or snapshot synced. Default: archive",
+		Arguments: []string{
+			"PRUNING_MODE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["pruning-retention"] = helper.FlagDescriptor{
+		Description: "Sets the number of recent blocks whose state is kept by the full or the light pruning mode",
+		Arguments: []string{
+			"BLOCKS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["light-serve"] = helper.FlagDescriptor{
+		Description: "Sets the flag indicating that the node serves the light clients the headers of the chain with " +
+			"their commit seals, and the merkle proofs of the state and of the receipts. Default: false",
+		Arguments: []string{
+			"LIGHT_SERVE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["address-index"] = helper.FlagDescriptor{
+		Description: "Sets the flag indicating that the node indexes the transactions of the new blocks by their " +
+			"sender, recipient and created contract, for eth_getTransactionsByAddress. Default: false",
+		Arguments: []string{
+			"ADDRESS_INDEX",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["shutdown-timeout"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the time the node waits, when stopped, for the end of the consensus round "+
+			"of the block it validates, in seconds. Default: %d", uint64(server.DefaultShutdownTimeout/time.Second)),
+		Arguments: []string{
+			"SHUTDOWN_TIMEOUT",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["block-gas-target"] = helper.FlagDescriptor{
-		Description: "Sets the target block gas limit for the chain. If omitted, the value of the parent block is used",
+		Description: "Sets the target block gas limit for the chain, the proposed blocks move their gas limit " +
+			"towards it by up to 1/1024 of the parent one. The target set in the gas target contract " +
+			"of the chain, if any, takes precedence. If omitted, the value of the parent block is used",
 		Arguments: []string{
 			"BLOCK_GAS_TARGET",
 		},
@@ -63,9 +235,13 @@ func (c *ServerCommand) DefineFlags() {
 	}
 
 	c.flagMap["chain"] = helper.FlagDescriptor{
-		Description: fmt.Sprintf("Specifies the genesis file used for starting the chain. Default: %s", helper.DefaultConfig().Chain),
+		Description: fmt.Sprintf(
+			"Specifies the genesis file used for starting the chain, or the name of a preset network (%s). Default: %s",
+			strings.Join(chain.Presets(), ", "),
+			helper.DefaultConfig().Chain,
+		),
 		Arguments: []string{
-			"GENESIS_FILE",
+			"GENESIS_FILE_OR_PRESET",
 		},
 		FlagOptional: true,
 	}
@@ -86,6 +262,40 @@ func (c *ServerCommand) DefineFlags() {
 		FlagOptional: true,
 	}
 
+	c.flagMap["grpc-tokens-file"] = helper.FlagDescriptor{
+		Description: "Sets the path to the file holding the gRPC auth tokens, one '<role> <token>' pair per line. " +
+			"The roles are read-only, operator and admin. Clients pass the token with the 'authorization: Bearer <token>' metadata",
+		Arguments: []string{
+			"TOKENS_FILE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["grpc-tls-cert"] = helper.FlagDescriptor{
+		Description: "Sets the path to the TLS certificate served by the gRPC service",
+		Arguments: []string{
+			"TLS_CERT",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["grpc-tls-key"] = helper.FlagDescriptor{
+		Description: "Sets the path to the private key of the gRPC TLS certificate",
+		Arguments: []string{
+			"TLS_KEY",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["grpc-tls-client-ca"] = helper.FlagDescriptor{
+		Description: "Sets the path to the CA of the gRPC client certificates, and requires the clients to present one. " +
+			"The role of a client is the organizational unit (OU) of its certificate",
+		Arguments: []string{
+			"CLIENT_CA",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["jsonrpc"] = helper.FlagDescriptor{
 		Description: fmt.Sprintf("Sets the address and port for the JSON-RPC service (address:port). Default: address: 127.0.0.1:%d", server.DefaultJSONRPCPort),
 		Arguments: []string{
@@ -94,6 +304,215 @@ func (c *ServerCommand) DefineFlags() {
 		FlagOptional: true,
 	}
 
+	c.flagMap["jsonrpc-http-namespaces"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated list of JSON-RPC namespaces exposed over HTTP. Default: all namespaces",
+		Arguments: []string{
+			"NAMESPACES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-ws-namespaces"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated list of JSON-RPC namespaces exposed over WebSocket. Default: all namespaces",
+		Arguments: []string{
+			"NAMESPACES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-ipc-namespaces"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated list of JSON-RPC namespaces exposed over IPC. Default: all namespaces",
+		Arguments: []string{
+			"NAMESPACES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-auth-namespaces"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated list of JSON-RPC namespaces that require the auth token",
+		Arguments: []string{
+			"NAMESPACES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-auth-token"] = helper.FlagDescriptor{
+		Description: "Sets the bearer token (Authorization: Bearer <token>) used to access the protected JSON-RPC namespaces. " +
+			"The token is visible in the process list and the shell history, prefer --jsonrpc-auth-token-file",
+		Arguments: []string{
+			"AUTH_TOKEN",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-auth-token-file"] = helper.FlagDescriptor{
+		Description: "Sets the path to the file holding the bearer token used to access the protected JSON-RPC namespaces",
+		Arguments: []string{
+			"AUTH_TOKEN_FILE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-personal"] = helper.FlagDescriptor{
+		Description: "Enables the personal JSON-RPC namespace and the node-side signing of eth_sendTransaction, " +
+			"eth_sign and eth_signTypedData_v4, using the encrypted accounts in the keystore of the data directory. " +
+			"Protect the namespace with --jsonrpc-auth-namespaces when the JSON-RPC service is publicly reachable. Default: false",
+		Arguments: []string{
+			"ENABLE_PERSONAL",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-admin"] = helper.FlagDescriptor{
+		Description: "Enables the admin JSON-RPC namespace, managing the static and trusted peers of the node. " +
+			"Protect the namespace with --jsonrpc-auth-namespaces when the JSON-RPC service is publicly reachable. Default: false",
+		Arguments: []string{
+			"ENABLE_ADMIN",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-debug"] = helper.FlagDescriptor{
+		Description: "Enables the debug JSON-RPC namespace, re-executing the blocks and dumping the state. " +
+			"Protect the namespace with --jsonrpc-auth-namespaces when the JSON-RPC service is publicly reachable. Default: false",
+		Arguments: []string{
+			"ENABLE_DEBUG",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-trace"] = helper.FlagDescriptor{
+		Description: "Enables the trace JSON-RPC namespace, re-executing the blocks for their call traces. " +
+			"Protect the namespace with --jsonrpc-auth-namespaces when the JSON-RPC service is publicly reachable. Default: false",
+		Arguments: []string{
+			"ENABLE_TRACE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-logs-block-range"] = helper.FlagDescriptor{
+		Description: "Sets the maximum number of blocks an eth_getLogs query can span. " +
+			"The queries over the limit fail with the range to request instead. Default: 0 (unlimited)",
+		Arguments: []string{
+			"LOGS_BLOCK_RANGE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-logs-limit"] = helper.FlagDescriptor{
+		Description: "Sets the maximum number of logs an eth_getLogs query spanning more than one block can return. " +
+			"The queries over the limit fail with the range to request instead. Default: 0 (unlimited)",
+		Arguments: []string{
+			"LOGS_LIMIT",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-call-timeout"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the time in milliseconds an eth_call execution, or the whole search of an "+
+			"eth_estimateGas, can run before it is aborted with a timeout error. Default: %d",
+			jsonrpc.DefaultCallTimeout/time.Millisecond),
+		Arguments: []string{
+			"CALL_TIMEOUT",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-gas-cap"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the maximum gas of the eth_call and eth_estimateGas executions, "+
+			"the calls with more gas are capped to it. Default: %d", jsonrpc.DefaultCallGasCap),
+		Arguments: []string{
+			"GAS_CAP",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["ipc-path"] = helper.FlagDescriptor{
+		Description: "Sets the path of the unix socket (named pipe on windows) serving the JSON-RPC API to the local clients. " +
+			"The IPC requests can access the protected namespaces. Default: disabled",
+		Arguments: []string{
+			"IPC_PATH",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-rate-limit"] = helper.FlagDescriptor{
+		Description: "Sets the number of JSON-RPC requests per second of every client, identified by " +
+			"an API key of the rate_api_keys config in the X-API-Key header, or by its IP. The CPU heavy methods count as several requests. Default: 0 (unlimited)",
+		Arguments: []string{
+			"REQUESTS_PER_SECOND",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-rate-burst"] = helper.FlagDescriptor{
+		Description: "Sets the number of JSON-RPC requests a client can send at once. Default: the rate limit",
+		Arguments: []string{
+			"REQUESTS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-max-concurrent"] = helper.FlagDescriptor{
+		Description: "Sets the number of JSON-RPC requests of a client that are executed at the same time. Default: 0 (unlimited)",
+		Arguments: []string{
+			"REQUESTS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-call-cache-ttl"] = helper.FlagDescriptor{
+		Description: "Sets the time in milliseconds the results of the identical eth_call and eth_estimateGas " +
+			"requests on the same block are served from a cache. Default: 0 (disabled)",
+		Arguments: []string{
+			"CALL_CACHE_TTL",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-call-cache-size"] = helper.FlagDescriptor{
+		Description: "Sets the maximum number of cached eth_call and eth_estimateGas results. Default: 1024",
+		Arguments: []string{
+			"CALL_CACHE_SIZE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-shed-cpu"] = helper.FlagDescriptor{
+		Description: "Sets the CPU usage, in percent of all the CPUs, above which the low priority JSON-RPC calls " +
+			"(the debug and trace namespaces, and the eth_getLogs queries over a wide range) are rejected. Default: 0 (disabled)",
+		Arguments: []string{
+			"CPU_PERCENT",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-shed-memory"] = helper.FlagDescriptor{
+		Description: "Sets the heap size, in MiB, above which the low priority JSON-RPC calls are rejected. Default: 0 (disabled)",
+		Arguments: []string{
+			"MEMORY_MIB",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["jsonrpc-shed-queue"] = helper.FlagDescriptor{
+		Description: "Sets the number of JSON-RPC calls being executed above which the low priority calls are rejected. " +
+			"Default: 0 (disabled)",
+		Arguments: []string{
+			"QUEUE_DEPTH",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["graphql"] = helper.FlagDescriptor{
+		Description: "Enables the GraphQL API (EIP-1767) on the /graphql path of the JSON-RPC HTTP service. " +
+			"The queries follow the access rules and the eth_getLogs limits of the eth namespace. Default: false",
+		Arguments: []string{
+			"ENABLE_GRAPHQL",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["libp2p"] = helper.FlagDescriptor{
 		Description: fmt.Sprintf("Sets the address and port for the libp2p service (address:port). Default: address: 127.0.0.1:%d", network.DefaultLibp2pPort),
 		Arguments: []string{
@@ -142,6 +561,143 @@ func (c *ServerCommand) DefineFlags() {
 		FlagOptional: true,
 	}
 
+	c.flagMap["nat-port-map"] = helper.FlagDescriptor{
+		Description: "Maps the libp2p port on the gateway with UPnP or NAT-PMP, and advertises the external address",
+		FlagOptional: true,
+	}
+
+	c.flagMap["nat-service"] = helper.FlagDescriptor{
+		Description: "Answers the dial back requests of the peers detecting if they are behind a NAT. " +
+			"Meant for the nodes with a public address",
+		FlagOptional: true,
+	}
+
+	c.flagMap["reachability"] = helper.FlagDescriptor{
+		Description: "Overrides the reachability detected with the help of the peers, public or private. Default: detected",
+		Arguments: []string{
+			"REACHABILITY",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["relays"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated libp2p addresses of the relays the node reserves an address on " +
+			"when it is behind a NAT",
+		Arguments: []string{
+			"RELAY_ADDRESSES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["relay-hop"] = helper.FlagDescriptor{
+		Description: "Relays the connections to the peers behind a NAT. Meant for the nodes with a public address",
+		FlagOptional: true,
+	}
+
+	c.flagMap["static-peers"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated libp2p addresses of the peers the node keeps connected, " +
+			"redialing them when the connection is lost. The static peers are exempt from the max peer count",
+		Arguments: []string{
+			"STATIC_PEER_ADDRESSES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["trusted-peers"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated ids or libp2p addresses of the peers exempt from the max peer count",
+		Arguments: []string{
+			"TRUSTED_PEERS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["dns-discovery"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated enrtree:// URLs of the DNS trees serving bootnodes, " +
+			"in addition to the ones of the chain. The trees are refreshed every 30 minutes",
+		Arguments: []string{
+			"DNS_TREE_URLS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["no-compression"] = helper.FlagDescriptor{
+		Description: "Disables the snappy compression of the streams and the gossip. Default: false",
+		Arguments: []string{
+			"NO_COMPRESSION",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["max-msg-size"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the comma separated maximum sizes of the messages accepted by protocol, "+
+			"as <protocol>=<bytes>. Default: %d bytes", network.DefaultMaxMessageSize),
+		Arguments: []string{
+			"PROTOCOL=BYTES",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["consensus-libp2p"] = helper.FlagDescriptor{
+		Description: "Sets the address and port of a separate libp2p listener for the validator traffic (address:port), " +
+			"e.g. on a private network, isolating the consensus from the public block and transaction gossip",
+		Arguments: []string{
+			"CONSENSUS_LIBP2P_ADDRESS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["consensus-max-peers"] = helper.FlagDescriptor{
+		Description: "Sets the maximum number of peers of the consensus listener. Default: 10",
+		Arguments: []string{
+			"CONSENSUS_PEER_COUNT",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["consensus-peers"] = helper.FlagDescriptor{
+		Description: "Sets the comma separated libp2p addresses of the validators the consensus listener keeps connected. " +
+			"The consensus listener doesn't discover peers",
+		Arguments: []string{
+			"CONSENSUS_PEERS",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["allowlist"] = helper.FlagDescriptor{
+		Description: "Sets the file of the peers allowed to connect, a peer id or libp2p address per line. " +
+			"When set, along with the allowlist contract, the other peers are refused. The file is reloaded every minute",
+		Arguments: []string{
+			"ALLOWLIST_FILE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["allowlist-contract"] = helper.FlagDescriptor{
+		Description: "Sets the address of the contract listing the peers allowed to connect, " +
+			"read with its getAllowlist() view returning the peer ids or libp2p addresses at the head of the chain",
+		Arguments: []string{
+			"ALLOWLIST_CONTRACT",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["consortium-ca"] = helper.FlagDescriptor{
+		Description: "Sets the PEM file of the consortium CA certificates. When set, only the nodes with a certificate " +
+			"issued by the consortium CA are accepted as peers",
+		Arguments: []string{
+			"CA_FILE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["consortium-cert"] = helper.FlagDescriptor{
+		Description: "Sets the PEM file of the consortium certificate of the node, issued to the node ID with the consortium issue command",
+		Arguments: []string{
+			"CERTIFICATE_FILE",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["locals"] = helper.FlagDescriptor{
 		Description: "Sets comma separated accounts whose transactions are treated as locals",
 		Arguments: []string{
@@ -166,6 +722,23 @@ func (c *ServerCommand) DefineFlags() {
 		FlagOptional: true,
 	}
 
+	c.flagMap["min-gas-price"] = helper.FlagDescriptor{
+		Description: "Sets the minimum gas price of every transaction accepted into the pool, the local ones included. Default: 0",
+		Arguments: []string{
+			"MIN_GAS_PRICE",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["zero-gas-allowlist"] = helper.FlagDescriptor{
+		Description: "Sets comma separated accounts whose transactions, sent by or to them, are accepted " +
+			"into the pool whatever their gas price, zero included",
+		Arguments: []string{
+			"ZERO_GAS_ALLOWLIST",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["max-slots"] = helper.FlagDescriptor{
 		Description: fmt.Sprintf("Sets maximum slots in the pool. Default: %d", helper.DefaultConfig().TxPool.MaxSlots),
 		Arguments: []string{
@@ -174,6 +747,60 @@ func (c *ServerCommand) DefineFlags() {
 		FlagOptional: true,
 	}
 
+	c.flagMap["nojournal"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Disables the journal of the local transactions (%s in the data directory), "+
+			"which are otherwise replayed after a restart. Default: false", helper.TxPoolJournalFile),
+		Arguments: []string{
+			"NOJOURNAL",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["price-bump"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the minimum gas price increase, in percent, for a transaction to replace "+
+			"the pooled transaction of the same sender with the same nonce. Default: %d", txpool.DefaultPriceBump),
+		Arguments: []string{
+			"PRICE_BUMP",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["queue-lifetime"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets how long, in seconds, the transactions with a future nonce of an account are kept "+
+			"while no transaction of the account is added or becomes executable. Default: %d", uint64(txpool.DefaultQueueLifetime/time.Second)),
+		Arguments: []string{
+			"QUEUE_LIFETIME",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["tx-lifetime"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets how long, in seconds, the remote transactions are kept in the pool before being dropped. "+
+			"Default: %d", uint64(txpool.DefaultTxLifetime/time.Second)),
+		Arguments: []string{
+			"TX_LIFETIME",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["max-account-pending"] = helper.FlagDescriptor{
+		Description: "Sets the maximum number of executable transactions of an account in the pending queue, " +
+			"the ones over it wait in the account queue. Default: 0 (unlimited)",
+		Arguments: []string{
+			"MAX_ACCOUNT_PENDING",
+		},
+		FlagOptional: true,
+	}
+
+	c.flagMap["max-account-queued"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the maximum number of transactions with a future nonce of a remote account "+
+			"in the account queue. Default: %d", txpool.DefaultMaxAccountQueued),
+		Arguments: []string{
+			"MAX_ACCOUNT_QUEUED",
+		},
+		FlagOptional: true,
+	}
+
 	c.flagMap["dev"] = helper.FlagDescriptor{
 		Description: "Sets the client to dev mode. Default: false",
 		Arguments: []string{
@@ -189,6 +816,30 @@ func (c *ServerCommand) DefineFlags() {
 		},
 		FlagOptional: true,
 	}
+	c.flagMap["dev-catchup-rate"] = helper.FlagDescriptor{
+		Description: "Sets the number of blocks per second the missed dev interval blocks are back-filled at " +
+			"when the client resumes after a downtime. Default: 0 (no back-fill)",
+		Arguments: []string{
+			"CATCHUP_RATE",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["fork"] = helper.FlagDescriptor{
+		Description: "Sets the JSON-RPC endpoint of a remote chain the dev chain is forked from. The accounts and " +
+			"the storage missing from the dev chain are fetched from it, and cached in the data directory",
+		Arguments: []string{
+			"FORK_URL",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["fork-block"] = helper.FlagDescriptor{
+		Description: "Sets the number of the remote block the dev chain is forked at. It is pinned on the first run. " +
+			"Default: 0 (the latest block)",
+		Arguments: []string{
+			"FORK_BLOCK",
+		},
+		FlagOptional: true,
+	}
 	c.flagMap["prometheus"] = helper.FlagDescriptor{
 		Description: "Sets the address and port for the prometheus instrumentation service (address:port)",
 		Arguments: []string{
@@ -196,6 +847,163 @@ func (c *ServerCommand) DefineFlags() {
 		},
 		FlagOptional: true,
   }
+	c.flagMap["tracing"] = helper.FlagDescriptor{
+		Description: "Sets the address and port of the OTLP collector the traces of the block processing " +
+			"are exported to over gRPC (address:port)",
+		Arguments: []string{
+			"COLLECTOR_ADDRESS",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["tracing-insecure"] = helper.FlagDescriptor{
+		Description:  "Connects to the OTLP collector without TLS",
+		FlagOptional: true,
+	}
+	c.flagMap["tracing-sample-rate"] = helper.FlagDescriptor{
+		Description: "Sets the percentage of the traces exported to the OTLP collector. Default: 100",
+		Arguments: []string{
+			"SAMPLE_RATE",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["ethstats"] = helper.FlagDescriptor{
+		Description: "Reports the block, peer and txpool stats of the node to a netstats server " +
+			"(nodename:secret@host:port)",
+		Arguments: []string{
+			"ETHSTATS_URL",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["checkpoint"] = helper.FlagDescriptor{
+		Description: "Anchors the chain to an external EVM chain, by submitting the finalized blocks and " +
+			"the signatures of their validators to a contract, through the JSON-RPC endpoint of the external chain. " +
+			"The node fails to start if its chain diverged from the latest checkpoint",
+		Arguments: []string{
+			"CHECKPOINT_URL",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["checkpoint-contract"] = helper.FlagDescriptor{
+		Description: "Sets the address of the checkpoint contract on the external chain",
+		Arguments: []string{
+			"CHECKPOINT_CONTRACT",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["checkpoint-interval"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the number of blocks between two checkpoints. Default: %d", checkpoint.DefaultInterval),
+		Arguments: []string{
+			"CHECKPOINT_INTERVAL",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["checkpoint-key"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the key file of the account sending the checkpoints, it pays their fees "+
+			"on the external chain. The key is generated if missing. Default: <data-dir>/%s", helper.CheckpointKeyFile),
+		Arguments: []string{
+			"CHECKPOINT_KEY_FILE",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["bridge"] = helper.FlagDescriptor{
+		Description: "Bridges the chain to a root EVM chain, through its JSON-RPC endpoint. The validators relay the " +
+			"deposits of the root chain with state sync transactions, signed with their validator key and sent without fee, " +
+			"and the bridge namespace of the JSON-RPC API serves the proofs of the withdrawals",
+		Arguments: []string{
+			"BRIDGE_URL",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["bridge-root-contract"] = helper.FlagDescriptor{
+		Description: "Sets the address of the contract of the root chain emitting the StateSynced events of the deposits",
+		Arguments: []string{
+			"BRIDGE_ROOT_CONTRACT",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["bridge-state-receiver"] = helper.FlagDescriptor{
+		Description: "Sets the address of the contract the deposits are relayed to, with onStateReceive",
+		Arguments: []string{
+			"BRIDGE_STATE_RECEIVER",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["bridge-exit-contract"] = helper.FlagDescriptor{
+		Description: "Sets the address of the contract emitting the MessageSent events of the withdrawals",
+		Arguments: []string{
+			"BRIDGE_EXIT_CONTRACT",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["bridge-confirmations"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the number of root chain blocks a deposit waits for before it is relayed. Default: %d",
+			bridge.DefaultConfirmations),
+		Arguments: []string{
+			"BRIDGE_CONFIRMATIONS",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["bridge-start-block"] = helper.FlagDescriptor{
+		Description: "Sets the root chain block the deposits are watched from on the first run",
+		Arguments: []string{
+			"BRIDGE_START_BLOCK",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["export"] = helper.FlagDescriptor{
+		Description: "Publishes the new canonical blocks, with their transactions, receipts and logs, to a sink: " +
+			"the URL of a webhook, the nats://host:port address of a NATS server, or the URL of the REST proxy of " +
+			"a Kafka cluster. The blocks removed by a reorg are published as removed",
+		Arguments: []string{
+			"EXPORT_URL",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["export-sink"] = helper.FlagDescriptor{
+		Description: "Sets the kind of the export sink: webhook, nats or kafka. Default: webhook",
+		Arguments: []string{
+			"EXPORT_SINK",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["export-topic"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the Kafka topic or the NATS subject the blocks are exported to. Default: %s",
+			exporter.DefaultTopic),
+		Arguments: []string{
+			"EXPORT_TOPIC",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["health"] = helper.FlagDescriptor{
+		Description: "Sets the address and port of the /healthz and /readyz HTTP endpoints (address:port)",
+		Arguments: []string{
+			"HEALTH_ADDRESS",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["health-min-peers"] = helper.FlagDescriptor{
+		Description: "Sets the number of peers below which /readyz reports the node as not ready. Default: 0",
+		Arguments: []string{
+			"MIN_PEERS",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["health-max-sync-lag"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the number of blocks behind the highest peer above which /readyz "+
+			"reports the node as not ready. Default: %d", server.DefaultHealthMaxSyncLag),
+		Arguments: []string{
+			"MAX_SYNC_LAG",
+		},
+		FlagOptional: true,
+	}
+	c.flagMap["health-max-head-age"] = helper.FlagDescriptor{
+		Description: "Sets the number of seconds without a new head after which /healthz reports the node " +
+			"as unhealthy. Default: 0 (disabled)",
+		Arguments: []string{
+			"MAX_HEAD_AGE",
+		},
+		FlagOptional: true,
+	}
 	c.flagMap["secrets-config"] = helper.FlagDescriptor{
 		Description: "Sets the path to the SecretsManager config file. Used for Hashicorp Vault. " +
 			"If omitted, the local FS secrets manager is used",
@@ -244,10 +1052,17 @@ func (c *ServerCommand) Run(args []string) int {
 		return 1
 	}
 
-	logger := hclog.New(&hclog.LoggerOptions{
-		Name:  "polygon",
-		Level: hclog.LevelFromString(conf.LogLevel),
-	})
+	// the config file and the flags are read again when a reload is requested over grpc
+	config.Reload = func() (*server.Config, error) {
+		conf, err := helper.ReadConfig(c.GetBaseCommand(), args)
+		if err != nil {
+			return nil, err
+		}
+
+		return conf.BuildConfig()
+	}
+
+	logger := logging.New("polygon", conf.LogFormat, config.LogLevels)
 
 	server, err := server.NewServer(logger, config)
 	if err != nil {
@@ -263,5 +1078,5 @@ func (c *ServerCommand) Run(args []string) int {
 		}
 	}
 
-	return helper.HandleSignals(server.Close, c.UI)
+	return helper.HandleSignalsOrShutdown(server.Close, server.ShutdownCh(), config.ShutdownTimeout+helper.DefaultCloseTimeout, c.UI)
 }
@@ -3,17 +3,54 @@ package status
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-sdk/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-sdk/server/proto"
+	txpoolOp "github.com/0xPolygon/polygon-sdk/txpool/proto"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+const (
+	// defaultWatchInterval is the default refresh interval of the dashboard, in seconds
+	defaultWatchInterval = 2
+
+	// clearScreen moves the cursor home and clears the terminal
+	clearScreen = "\033[H\033[2J"
+)
+
 // StatusCommand is the command to show the version of the agent
 type StatusCommand struct {
 	helper.Meta
 }
 
+// DefineFlags defines the command flags
+func (c *StatusCommand) DefineFlags() {
+	if c.FlagMap == nil {
+		// Flag map not initialized
+		c.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	c.FlagMap["watch"] = helper.FlagDescriptor{
+		Description: "Renders a live dashboard of the height, the consensus round and proposer, " +
+			"the peers, the txpool and the sync lag, until interrupted",
+		FlagOptional: true,
+	}
+
+	c.FlagMap["interval"] = helper.FlagDescriptor{
+		Description: fmt.Sprintf("Sets the refresh interval of the dashboard, in seconds. Default: %d", defaultWatchInterval),
+		Arguments: []string{
+			"INTERVAL",
+		},
+		FlagOptional: true,
+	}
+}
+
 // GetHelperText returns a simple description of the command
 func (c *StatusCommand) GetHelperText() string {
 	return "Returns the status of the Polygon SDK client"
@@ -26,6 +63,7 @@ func (c *StatusCommand) GetBaseCommand() string {
 // Help implements the cli.Command interface
 func (c *StatusCommand) Help() string {
 	c.Meta.DefineFlags()
+	c.DefineFlags()
 
 	return helper.GenerateHelp(c.Synopsis(), helper.GenerateUsage(c.GetBaseCommand(), c.FlagMap), c.FlagMap)
 }
@@ -39,6 +77,12 @@ func (c *StatusCommand) Synopsis() string {
 func (c *StatusCommand) Run(args []string) int {
 
 	flags := c.FlagSet(c.GetBaseCommand())
+
+	var watch bool
+	var interval uint64
+	flags.BoolVar(&watch, "watch", false, "")
+	flags.Uint64Var(&interval, "interval", defaultWatchInterval, "")
+
 	if err := flags.Parse(args); err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -50,6 +94,15 @@ func (c *StatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if watch {
+		if interval == 0 {
+			c.UI.Error("the refresh interval must be at least a second")
+			return 1
+		}
+
+		return c.watch(conn, time.Duration(interval)*time.Second)
+	}
+
 	clt := proto.NewSystemClient(conn)
 	status, err := clt.GetStatus(context.Background(), &emptypb.Empty{})
 	if err != nil {
@@ -71,3 +124,88 @@ func (c *StatusCommand) Run(args []string) int {
 
 	return 0
 }
+
+// watch renders the dashboard every interval, until the user quits with ctrl-c
+func (c *StatusCommand) watch(conn *grpc.ClientConn, interval time.Duration) int {
+	signalCh := make(chan os.Signal, 4)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		output, err := c.dashboard(conn, interval)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.UI.Output(clearScreen + output)
+
+		select {
+		case <-signalCh:
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// dashboard returns the rendered status of the client. The consensus round and the txpool
+// are shown as unavailable if their operator services are not registered or not accessible
+func (c *StatusCommand) dashboard(conn *grpc.ClientConn, timeout time.Duration) (string, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), timeout)
+	defer cancelFn()
+
+	status, err := proto.NewSystemClient(conn).GetStatus(ctx, &emptypb.Empty{})
+	if err != nil {
+		return "", err
+	}
+
+	syncLag := "unknown"
+	if status.HighestBlock != 0 {
+		lag := int64(0)
+		if status.HighestBlock > status.Current.Number {
+			lag = status.HighestBlock - status.Current.Number
+		}
+		syncLag = fmt.Sprintf("%d blocks (highest block %d)", lag, status.HighestBlock)
+	}
+
+	txpoolDepth := "unavailable"
+	if pool, err := txpoolOp.NewTxnPoolOperatorClient(conn).Status(ctx, &emptypb.Empty{}); err == nil {
+		txpoolDepth = fmt.Sprintf("%d", pool.Length)
+	}
+
+	state, round, proposer := "unavailable", "unavailable", "unavailable"
+	if ibft, err := ibftOp.NewIbftOperatorClient(conn).Status(ctx, &emptypb.Empty{}); err == nil {
+		state = ibft.State
+		if !ibft.Validator {
+			state += " (not a validator)"
+		}
+		round = fmt.Sprintf("%d (sequence %d)", ibft.Round, ibft.Sequence)
+		proposer = ibft.Proposer
+		if proposer == "" {
+			proposer = "none"
+		}
+	}
+
+	output := "\n[CLIENT STATUS]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Network (Chain ID)|%d", status.Network),
+		fmt.Sprintf("Height|%d", status.Current.Number),
+		fmt.Sprintf("Hash|%s", status.Current.Hash),
+		fmt.Sprintf("Sync Lag|%s", syncLag),
+		fmt.Sprintf("Peers|%d", status.Peers),
+		fmt.Sprintf("TxPool Depth|%s", txpoolDepth),
+	})
+
+	output += "\n\n[CONSENSUS]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("State|%s", state),
+		fmt.Sprintf("Round|%s", round),
+		fmt.Sprintf("Proposer|%s", proposer),
+	})
+
+	output += fmt.Sprintf("\n\nUpdated at %s, refreshed every %s. Press ctrl-c to quit\n",
+		time.Now().Format("15:04:05"), timeout)
+
+	return output, nil
+}
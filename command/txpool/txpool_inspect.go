@@ -0,0 +1,160 @@
+package txpool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/command/helper"
+	txpoolOp "github.com/0xPolygon/polygon-sdk/txpool/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// TxPoolInspect is the command to query the transactions of a single account in the pool
+type TxPoolInspect struct {
+	helper.Meta
+}
+
+// DefineFlags defines the command flags
+func (p *TxPoolInspect) DefineFlags() {
+	if p.FlagMap == nil {
+		// Flag map not initialized
+		p.FlagMap = make(map[string]helper.FlagDescriptor)
+	}
+
+	p.FlagMap["account"] = helper.FlagDescriptor{
+		Description: "The address of the sender account to inspect",
+		Arguments: []string{
+			"ADDRESS",
+		},
+		ArgumentsOptional: false,
+		FlagOptional:      false,
+	}
+
+	p.FlagMap["evict"] = helper.FlagDescriptor{
+		Description: "Removes all the pending and queued transactions of the account from the pool. Default: false",
+		Arguments: []string{
+			"EVICT",
+		},
+		ArgumentsOptional: true,
+		FlagOptional:      true,
+	}
+}
+
+// GetHelperText returns a simple description of the command
+func (p *TxPoolInspect) GetHelperText() string {
+	return "Returns the pending and queued transactions of a sender account, optionally evicting them"
+}
+
+func (p *TxPoolInspect) GetBaseCommand() string {
+	return "txpool inspect"
+}
+
+// Help implements the cli.TxPoolInspect interface
+func (p *TxPoolInspect) Help() string {
+	p.Meta.DefineFlags()
+	p.DefineFlags()
+
+	return helper.GenerateHelp(p.Synopsis(), helper.GenerateUsage(p.GetBaseCommand(), p.FlagMap), p.FlagMap)
+}
+
+// Synopsis implements the cli.TxPoolInspect interface
+func (p *TxPoolInspect) Synopsis() string {
+	return p.GetHelperText()
+}
+
+// Run implements the cli.TxPoolInspect interface
+func (p *TxPoolInspect) Run(args []string) int {
+	flags := p.FlagSet(p.GetBaseCommand())
+
+	var account string
+	var evict bool
+
+	flags.StringVar(&account, "account", "", "")
+	flags.BoolVar(&evict, "evict", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	if account == "" {
+		p.UI.Error("Account address not specified")
+		return 1
+	}
+
+	var addr types.Address
+	if err := addr.UnmarshalText([]byte(account)); err != nil {
+		p.UI.Error("Failed to decode address")
+		return 1
+	}
+
+	conn, err := p.Conn()
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	clt := txpoolOp.NewTxnPoolOperatorClient(conn)
+
+	resp, err := clt.Inspect(context.Background(), &txpoolOp.InspectReq{Account: addr.String()})
+	if err != nil {
+		p.UI.Error(err.Error())
+		return 1
+	}
+
+	output := printInspect(resp)
+
+	if evict {
+		evictResp, err := clt.Evict(context.Background(), &txpoolOp.EvictReq{Account: addr.String()})
+		if err != nil {
+			p.UI.Error(err.Error())
+			return 1
+		}
+
+		output += "\n[TXPOOL EVICT]\n"
+		output += fmt.Sprintf("Evicted %d transactions of account [%s]\n", evictResp.Count, resp.Account)
+	}
+
+	p.UI.Output(output)
+
+	return 0
+}
+
+func printInspect(resp *txpoolOp.InspectResp) (output string) {
+	output += "\n[TXPOOL INSPECT]\n"
+	output += helper.FormatKV([]string{
+		fmt.Sprintf("Account|%s", resp.Account),
+		fmt.Sprintf("State nonce|%d", resp.StateNonce),
+		fmt.Sprintf("Next pool nonce|%d", resp.NextNonce),
+		fmt.Sprintf("Pending|%d", len(resp.Pending)),
+		fmt.Sprintf("Queued|%d", len(resp.Queued)),
+	})
+
+	output += "\n"
+
+	output += "\n[PENDING]\n"
+	output += formatInspectTxns(resp.Pending, "No pending transactions found")
+
+	output += "\n"
+
+	output += "\n[QUEUED]\n"
+	output += formatInspectTxns(resp.Queued, "No queued transactions found")
+
+	output += "\n"
+
+	return output
+}
+
+func formatInspectTxns(txns []*txpoolOp.InspectResp_Txn, emptyMsg string) string {
+	rows := make([]string, len(txns)+1)
+	if len(txns) == 0 {
+		rows[0] = emptyMsg
+	} else {
+		rows[0] = "NONCE|HASH|GAS PRICE|GAS|AGE"
+		for i, txn := range txns {
+			rows[i+1] = fmt.Sprintf("%d|%s|%s|%d|%ds", txn.Nonce, txn.Hash, txn.GasPrice, txn.Gas, txn.Age)
+		}
+	}
+
+	return helper.FormatList(rows)
+}
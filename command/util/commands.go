@@ -3,13 +3,18 @@ package util
 import (
 	"os"
 
+	"github.com/0xPolygon/polygon-sdk/command/backup"
+	"github.com/0xPolygon/polygon-sdk/command/chain"
+	"github.com/0xPolygon/polygon-sdk/command/consortium"
 	"github.com/0xPolygon/polygon-sdk/command/dev"
 	"github.com/0xPolygon/polygon-sdk/command/genesis"
 	"github.com/0xPolygon/polygon-sdk/command/helper"
 	"github.com/0xPolygon/polygon-sdk/command/ibft"
 	"github.com/0xPolygon/polygon-sdk/command/loadbot"
+	"github.com/0xPolygon/polygon-sdk/command/loglevel"
 	"github.com/0xPolygon/polygon-sdk/command/monitor"
 	"github.com/0xPolygon/polygon-sdk/command/peers"
+	"github.com/0xPolygon/polygon-sdk/command/reload"
 	"github.com/0xPolygon/polygon-sdk/command/secrets"
 	"github.com/0xPolygon/polygon-sdk/command/server"
 	"github.com/0xPolygon/polygon-sdk/command/status"
@@ -34,24 +39,49 @@ func Commands() map[string]cli.CommandFactory {
 	serverCmd := server.ServerCommand{UI: ui}
 	devCmd := dev.DevCommand{UI: ui}
 	genesisCmd := genesis.GenesisCommand{UI: ui}
+	genesisValidateCmd := genesis.GenesisValidate{Meta: meta}
+	genesisForkCmd := genesis.GenesisFork{Meta: meta}
 	monitorCmd := monitor.MonitorCommand{Meta: meta}
 	statusCmd := status.StatusCommand{Meta: meta}
+	logLevelCmd := loglevel.LogLevelCommand{Meta: meta}
+	reloadCmd := reload.ReloadCommand{Meta: meta}
 	versionCmd := version.VersionCommand{UI: ui}
 
+	chainCmd := chain.ChainCommand{}
+	chainReplayCmd := chain.ChainReplay{Meta: meta}
+	chainPruneCmd := chain.ChainPrune{Meta: meta}
+	chainMigrateCmd := chain.ChainMigrate{Meta: meta}
+	chainExportCmd := chain.ChainExport{Meta: meta}
+	chainImportCmd := chain.ChainImport{Meta: meta}
+	chainBadBlocksCmd := chain.ChainBadBlocks{Meta: meta}
+
+	consortiumCmd := consortium.ConsortiumCommand{}
+	consortiumCACmd := consortium.ConsortiumCA{Meta: meta}
+	consortiumIssueCmd := consortium.ConsortiumIssue{Meta: meta}
+
+	backupCmd := backup.BackupCommand{}
+	backupCreateCmd := backup.BackupCreate{Meta: meta}
+	backupRestoreCmd := backup.BackupRestore{Meta: meta}
+
 	ibftCmd := ibft.IbftCommand{}
 	ibftCandidatesCmd := ibft.IbftCandidates{Meta: meta}
 	ibftProposeCmd := ibft.IbftPropose{Meta: meta}
 	ibftSnapshotCmd := ibft.IbftSnapshot{Meta: meta}
 	ibftStatusCmd := ibft.IbftStatus{Meta: meta}
+	ibftExitCmd := ibft.IbftExit{Meta: meta}
 
 	peersCmd := peers.PeersCommand{}
 	peersAddCmd := peers.PeersAdd{Meta: meta}
 	peersListCmd := peers.PeersList{Meta: meta}
 	peersStatusCmd := peers.PeersStatus{Meta: meta}
+	peersDNSTreeCmd := peers.PeersDNSTree{Meta: meta}
+	peersBandwidthCmd := peers.PeersBandwidth{Meta: meta}
+	peersGraphCmd := peers.PeersGraph{Meta: meta}
 
 	txPoolCmd := txpool.TxPoolCommand{}
 	txPoolAddCmd := txpool.TxPoolAdd{Meta: meta}
 	txPoolStatusCmd := txpool.TxPoolStatus{Meta: meta}
+	txPoolInspectCmd := txpool.TxPoolInspect{Meta: meta}
 
 	loadbotCmd := loadbot.LoadbotCommand{Meta: meta}
 
@@ -72,6 +102,12 @@ func Commands() map[string]cli.CommandFactory {
 		genesisCmd.GetBaseCommand(): func() (cli.Command, error) {
 			return &genesisCmd, nil
 		},
+		genesisValidateCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &genesisValidateCmd, nil
+		},
+		genesisForkCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &genesisForkCmd, nil
+		},
 
 		// PEER COMMANDS //
 
@@ -87,6 +123,15 @@ func Commands() map[string]cli.CommandFactory {
 		peersListCmd.GetBaseCommand(): func() (cli.Command, error) {
 			return &peersListCmd, nil
 		},
+		peersDNSTreeCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &peersDNSTreeCmd, nil
+		},
+		peersBandwidthCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &peersBandwidthCmd, nil
+		},
+		peersGraphCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &peersGraphCmd, nil
+		},
 
 		// IBFT COMMANDS //
 
@@ -105,6 +150,9 @@ func Commands() map[string]cli.CommandFactory {
 		ibftStatusCmd.GetBaseCommand(): func() (cli.Command, error) {
 			return &ibftStatusCmd, nil
 		},
+		ibftExitCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &ibftExitCmd, nil
+		},
 
 		// TXPOOL COMMANDS //
 
@@ -117,6 +165,9 @@ func Commands() map[string]cli.CommandFactory {
 		txPoolStatusCmd.GetBaseCommand(): func() (cli.Command, error) {
 			return &txPoolStatusCmd, nil
 		},
+		txPoolInspectCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &txPoolInspectCmd, nil
+		},
 
 		// BLOCKCHAIN COMMANDS //
 
@@ -126,9 +177,56 @@ func Commands() map[string]cli.CommandFactory {
 		monitorCmd.GetBaseCommand(): func() (cli.Command, error) {
 			return &monitorCmd, nil
 		},
+		logLevelCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &logLevelCmd, nil
+		},
+		reloadCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &reloadCmd, nil
+		},
 		versionCmd.GetBaseCommand(): func() (cli.Command, error) {
 			return &versionCmd, nil
 		},
+		chainCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainCmd, nil
+		},
+		chainReplayCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainReplayCmd, nil
+		},
+		chainPruneCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainPruneCmd, nil
+		},
+		chainMigrateCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainMigrateCmd, nil
+		},
+		chainExportCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainExportCmd, nil
+		},
+		chainImportCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainImportCmd, nil
+		},
+		chainBadBlocksCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &chainBadBlocksCmd, nil
+		},
+		consortiumCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &consortiumCmd, nil
+		},
+		consortiumCACmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &consortiumCACmd, nil
+		},
+		consortiumIssueCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &consortiumIssueCmd, nil
+		},
+
+		// BACKUP COMMANDS //
+		backupCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &backupCmd, nil
+		},
+		backupCreateCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &backupCreateCmd, nil
+		},
+		backupRestoreCmd.GetBaseCommand(): func() (cli.Command, error) {
+			return &backupRestoreCmd, nil
+		},
 
 		// SECRETS MANAGER COMMANDS //
 		secretsManagerCmd.GetBaseCommand(): func() (cli.Command, error) {
@@ -7,8 +7,10 @@ import (
 	"github.com/0xPolygon/polygon-sdk/blockchain"
 	"github.com/0xPolygon/polygon-sdk/chain"
 	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/protocol"
 	"github.com/0xPolygon/polygon-sdk/secrets"
 	"github.com/0xPolygon/polygon-sdk/state"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
 	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
@@ -31,6 +33,39 @@ type Consensus interface {
 	Close() error
 }
 
+// Status is the sync and the participation status of the consensus, reported by the health checks
+type Status struct {
+	// HighestBlock is the highest block announced by the peers, 0 if unknown
+	HighestBlock uint64
+
+	// Validator is true if the node seals and is in the validator set of the head
+	Validator bool
+
+	// Participating is true if the node takes part in the rounds of the sequences
+	Participating bool
+}
+
+// StatusReporter is implemented by the consensus mechanisms that report their status
+type StatusReporter interface {
+	// Status returns the current status of the consensus
+	Status() *Status
+}
+
+// Stopper is implemented by the consensus mechanisms which end their participation gracefully,
+// before they are closed
+type Stopper interface {
+	// Stop stops taking part in the consensus, once the work in progress is completed.
+	// It returns once stopped, or when the context is done
+	Stop(ctx context.Context) error
+}
+
+// FinalityProver is implemented by the consensus mechanisms whose blocks carry the signatures
+// of the validators which finalized them
+type FinalityProver interface {
+	// FinalitySignatures returns the signatures of the validators which finalized the block
+	FinalitySignatures(header *types.Header) ([][]byte, error)
+}
+
 // Config is the configuration for the consensus
 type Config struct {
 	// Logger to be used by the backend
@@ -52,12 +87,20 @@ type ConsensusParams struct {
 	Config         *Config
 	Txpool         *txpool.TxPool
 	Network        *network.Server
+	// ConsensusNetwork is the network of the validator traffic, Network if it isn't separated
+	ConsensusNetwork *network.Server
 	Blockchain     *blockchain.Blockchain
 	Executor       *state.Executor
 	Grpc           *grpc.Server
 	Logger         hclog.Logger
   Metrics        *Metrics
 	SecretsManager secrets.SecretsManager
+	ForkMonitor    *protocol.ForkMonitor
+	SyncTracker    *protocol.SyncTracker
+	StateStorage   itrie.Storage
+	SnapshotSync   bool
+	// SystemTxs provides the system transactions at the top of the blocks, if any
+	SystemTxs SystemTxProvider
 }
 
 // Factory is the factory function to create a discovery backend
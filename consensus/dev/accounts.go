@@ -0,0 +1,43 @@
+package dev
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// accountSeed is hashed with the index of the dev account to derive its private key
+var accountSeed = []byte("polygon-sdk dev account")
+
+// Account is a prefunded dev account, its private key is well-known and must never
+// be used outside of a local chain
+type Account struct {
+	Address types.Address
+	Key     *ecdsa.PrivateKey
+}
+
+// Accounts returns the first n dev accounts. They are the same on every run,
+// so that the dapps and their tests can be configured with their keys once
+func Accounts(n uint64) ([]*Account, error) {
+	accounts := make([]*Account, 0, n)
+
+	for i := uint64(0); i < n; i++ {
+		index := make([]byte, 8)
+		binary.BigEndian.PutUint64(index, i)
+
+		key, err := crypto.ToECDSA(crypto.Keccak256(accountSeed, index))
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive the dev account %d: %v", i, err)
+		}
+
+		accounts = append(accounts, &Account{
+			Address: crypto.PubKeyToAddress(&key.PublicKey),
+			Key:     key,
+		})
+	}
+
+	return accounts, nil
+}
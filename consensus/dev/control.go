@@ -0,0 +1,107 @@
+package dev
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// chainSnapshot is a head of the chain saved by the tests, the chain can be reverted to
+type chainSnapshot struct {
+	number     uint64
+	timeOffset int64
+}
+
+// The methods below control the chain of the tests, they back the evm namespace of the JSON-RPC
+
+// Mine seals a block with the transactions of the pool right away. The block has the given timestamp,
+// unless it is zero
+func (d *Dev) Mine(timestamp uint64) error {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	header := d.blockchain.Header()
+	if timestamp != 0 {
+		if err := d.setNextTimestamp(header, timestamp); err != nil {
+			return err
+		}
+	}
+
+	return d.writeNewBlock(header, d.blockTimestamp(header), true)
+}
+
+// IncreaseTime moves the clock of the chain forward by the seconds, and returns
+// the number of seconds it is ahead of the wall clock
+func (d *Dev) IncreaseTime(seconds uint64) int64 {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	d.timeOffset += int64(seconds)
+
+	return d.timeOffset
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next block, the clock of the chain continues from it
+func (d *Dev) SetNextBlockTimestamp(timestamp uint64) error {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	return d.setNextTimestamp(d.blockchain.Header(), timestamp)
+}
+
+func (d *Dev) setNextTimestamp(head *types.Header, timestamp uint64) error {
+	if timestamp <= head.Timestamp {
+		return fmt.Errorf("the timestamp %d is not after the timestamp %d of the head", timestamp, head.Timestamp)
+	}
+
+	d.nextTimestamp = timestamp
+	d.timeOffset = int64(timestamp) - time.Now().Unix()
+
+	return nil
+}
+
+// Snapshot saves the head of the chain and its clock, and returns the id to revert to them
+func (d *Dev) Snapshot() uint64 {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	d.lastSnapshot++
+	d.snapshots[d.lastSnapshot] = &chainSnapshot{
+		number:     d.blockchain.Header().Number,
+		timeOffset: d.timeOffset,
+	}
+
+	return d.lastSnapshot
+}
+
+// Revert rewinds the chain and its clock to the snapshot. The snapshot and the ones taken after it
+// can't be reverted to anymore. It returns false if there is no such snapshot
+func (d *Dev) Revert(id uint64) (bool, error) {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	snapshot, ok := d.snapshots[id]
+	if !ok {
+		return false, nil
+	}
+
+	removed, err := d.blockchain.Rewind(snapshot.number)
+	if err != nil {
+		return false, fmt.Errorf("failed to revert to snapshot %d: %v", id, err)
+	}
+	d.txpool.DiscardBlocks(removed)
+
+	d.timeOffset = snapshot.timeOffset
+	d.nextTimestamp = 0
+
+	for snapshotID := range d.snapshots {
+		if snapshotID >= id {
+			delete(d.snapshots, snapshotID)
+		}
+	}
+
+	d.logger.Info("chain reverted", "snapshot", id, "number", snapshot.number)
+
+	return true, nil
+}
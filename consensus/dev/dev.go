@@ -3,6 +3,7 @@ package dev
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
@@ -17,14 +18,35 @@ import (
 type Dev struct {
 	logger hclog.Logger
 
+	// notifyCh is notified by the txpool when a txn is added. It is buffered, so that
+	// the txns added while a block is sealed go in the next block right away
 	notifyCh chan struct{}
 	closeCh  chan struct{}
 
+	// interval is the number of seconds the chain can be idle before an empty block is sealed.
+	// Zero seals the blocks only when there are new txns
 	interval uint64
 	txpool   *txpool.TxPool
 
+	// catchupRate is the number of missed scheduled blocks back-filled per second when the node
+	// resumes after a downtime, so that the indexers are not flooded. Zero resumes the schedule without back-filling
+	catchupRate uint64
+
 	blockchain *blockchain.Blockchain
 	executor   *state.Executor
+	systemTxs  consensus.SystemTxProvider
+
+	// sealLock serializes the blocks sealed by the run loop and the changes of the chain by the tests
+	sealLock sync.Mutex
+
+	// timeOffset is the number of seconds the clock of the chain is ahead of the wall clock,
+	// nextTimestamp is the timestamp of the next block if set by the tests
+	timeOffset    int64
+	nextTimestamp uint64
+
+	// snapshots are the heads of the chain and the time offsets saved by the tests, by id
+	snapshots    map[uint64]*chainSnapshot
+	lastSnapshot uint64
 }
 
 // Factory implements the base factory method
@@ -35,11 +57,13 @@ func Factory(
 
 	d := &Dev{
 		logger:     logger,
-		notifyCh:   make(chan struct{}),
+		notifyCh:   make(chan struct{}, 1),
 		closeCh:    make(chan struct{}),
 		blockchain: params.Blockchain,
 		executor:   params.Executor,
 		txpool:     params.Txpool,
+		systemTxs:  params.SystemTxs,
+		snapshots:  map[uint64]*chainSnapshot{},
 	}
 
 	rawInterval, ok := params.Config.Config["interval"]
@@ -51,6 +75,15 @@ func Factory(
 		d.interval = interval
 	}
 
+	rawCatchupRate, ok := params.Config.Config["catchupRate"]
+	if ok {
+		catchupRate, ok := rawCatchupRate.(uint64)
+		if !ok {
+			return nil, fmt.Errorf("catchupRate expected int")
+		}
+		d.catchupRate = catchupRate
+	}
+
 	// enable dev mode so that we can accept non-signed txns
 	params.Txpool.EnableDev()
 	params.Txpool.NotifyCh = d.notifyCh
@@ -65,42 +98,109 @@ func (d *Dev) Start() error {
 	return nil
 }
 
-func (d *Dev) nextNotify() chan struct{} {
-	if d.interval != 0 {
-		ch := make(chan struct{})
-		go func() {
-			<-time.After(time.Duration(d.interval) * time.Second)
-			ch <- struct{}{}
-		}()
+// idleCh returns the channel notified when the chain was idle for an interval,
+// so that an empty block is sealed. It is nil, and never notified, without an interval
+func (d *Dev) idleCh() <-chan time.Time {
+	if d.interval == 0 {
+		return nil
+	}
+
+	return time.After(time.Duration(d.interval) * time.Second)
+}
+
+// missedSlot returns the timestamp of the scheduled block after parent if it was missed
+// by more than an interval while the node was down, and has to be back-filled
+func (d *Dev) missedSlot(parent *types.Header, now time.Time) (uint64, bool) {
+	if d.interval == 0 || d.catchupRate == 0 || parent.Number == 0 {
+		// there is no schedule before the first block
+		return 0, false
+	}
 
-		return ch
+	slot := parent.Timestamp + d.interval
+	if slot+d.interval > uint64(now.Unix()) {
+		return 0, false
 	}
 
-	return d.notifyCh
+	return slot, true
 }
 
 func (d *Dev) run() {
 	d.logger.Info("consensus started")
 
 	for {
-		// wait until there is a new txn
+		parent := d.blockchain.Header()
+
+		if slot, missed := d.missedSlot(parent, time.Now()); missed {
+			// the missed blocks are back-filled at the catch-up rate, and are empty.
+			// The pending transactions go in the next block once the schedule is resumed
+			select {
+			case <-time.After(time.Second / time.Duration(d.catchupRate)):
+			case <-d.closeCh:
+				return
+			}
+
+			if err := d.seal(parent, slot, false); err != nil {
+				d.logger.Error("failed to back-fill block", "err", err)
+			}
+			continue
+		}
+
+		// seal as soon as there is a new txn, or an empty block once the chain was idle for an interval
 		select {
-		case <-d.nextNotify():
+		case <-d.notifyCh:
+		case <-d.idleCh():
 		case <-d.closeCh:
 			return
 		}
 
-		// There are new transactions in the pool, try to seal them
-		header := d.blockchain.Header()
-		if err := d.writeNewBlock(header); err != nil {
+		if err := d.sealNext(); err != nil {
 			d.logger.Error("failed to mine block", "err", err)
 		}
 	}
 }
 
+// seal writes a new block after parent, unless the chain changed since
+func (d *Dev) seal(parent *types.Header, timestamp uint64, withTxns bool) error {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	if d.blockchain.Header().Hash != parent.Hash {
+		return nil
+	}
+
+	return d.writeNewBlock(parent, timestamp, withTxns)
+}
+
+// sealNext writes a new block with the transactions of the pool after the head of the chain
+func (d *Dev) sealNext() error {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	header := d.blockchain.Header()
+
+	return d.writeNewBlock(header, d.blockTimestamp(header), true)
+}
+
+// blockTimestamp returns the timestamp of the block after parent, on the clock of the chain
+func (d *Dev) blockTimestamp(parent *types.Header) uint64 {
+	if timestamp := d.nextTimestamp; timestamp != 0 {
+		d.nextTimestamp = 0
+
+		return timestamp
+	}
+
+	now := time.Now().Unix() + d.timeOffset
+	if now < int64(parent.Timestamp) {
+		// the clock of the chain went back with a revert
+		return parent.Timestamp
+	}
+
+	return uint64(now)
+}
+
 // writeNewBLock generates a new block based on transactions from the pool,
 // and writes them to the blockchain
-func (d *Dev) writeNewBlock(parent *types.Header) error {
+func (d *Dev) writeNewBlock(parent *types.Header, timestamp uint64, withTxns bool) error {
 
 	// Generate the base block
 	num := parent.Number
@@ -108,7 +208,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		ParentHash: parent.Hash,
 		Number:     num + 1,
 		GasLimit:   parent.GasLimit, // Inherit from parent for now, will need to adjust dynamically later.
-		Timestamp:  uint64(time.Now().Unix()),
+		Timestamp:  timestamp,
 	}
 
 	// calculate gas limit based on parent header
@@ -117,6 +217,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		return err
 	}
 	header.GasLimit = gasLimit
+	header.BaseFee = d.blockchain.CalculateBaseFee(parent)
 
 	miner, err := d.GetBlockCreator(header)
 	if err != nil {
@@ -127,8 +228,12 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		return err
 	}
 
-	txns := []*types.Transaction{}
-	for {
+	// the system transactions go first, then the ones of the pool
+	txns, err := consensus.WriteSystemTransactions(d.systemTxs, header, transition)
+	if err != nil {
+		return err
+	}
+	for withTxns {
 		// Add transactions to the list until there are none left
 		txn, retFn := d.txpool.Pop()
 
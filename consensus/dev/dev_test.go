@@ -0,0 +1,115 @@
+package dev
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDev_MissedSlot(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name        string
+		interval    uint64
+		catchupRate uint64
+		parent      *types.Header
+		slot        uint64
+		missed      bool
+	}{
+		{
+			name:        "should back-fill the slot missed by more than an interval",
+			interval:    10,
+			catchupRate: 5,
+			parent:      &types.Header{Number: 1, Timestamp: 900},
+			slot:        910,
+			missed:      true,
+		},
+		{
+			name:        "should not back-fill the next slot on schedule",
+			interval:    10,
+			catchupRate: 5,
+			parent:      &types.Header{Number: 1, Timestamp: 985},
+		},
+		{
+			name:     "should not back-fill without a catch-up rate",
+			interval: 10,
+			parent:   &types.Header{Number: 1, Timestamp: 900},
+		},
+		{
+			name:        "should not back-fill without an interval",
+			catchupRate: 5,
+			parent:      &types.Header{Number: 1, Timestamp: 900},
+		},
+		{
+			name:        "should not back-fill after the genesis",
+			interval:    10,
+			catchupRate: 5,
+			parent:      &types.Header{Number: 0, Timestamp: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Dev{interval: tt.interval, catchupRate: tt.catchupRate}
+
+			slot, missed := d.missedSlot(tt.parent, now)
+			assert.Equal(t, tt.missed, missed)
+			assert.Equal(t, tt.slot, slot)
+		})
+	}
+}
+
+func TestDev_IdleCh(t *testing.T) {
+	d := &Dev{}
+	assert.Nil(t, d.idleCh())
+
+	d.interval = 1
+	select {
+	case <-d.idleCh():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the idle interval is not notified")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	accounts, err := Accounts(3)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 3)
+
+	// the accounts are the same on every run
+	again, err := Accounts(2)
+	assert.NoError(t, err)
+
+	seen := map[types.Address]bool{}
+	for i, account := range accounts {
+		assert.Equal(t, crypto.PubKeyToAddress(&account.Key.PublicKey), account.Address)
+		assert.False(t, seen[account.Address])
+		seen[account.Address] = true
+
+		if i < len(again) {
+			assert.Equal(t, again[i].Address, account.Address)
+		}
+	}
+}
+
+func TestDev_BlockTimestamp(t *testing.T) {
+	d := &Dev{}
+	parent := &types.Header{Timestamp: uint64(time.Now().Unix())}
+
+	// the clock of the chain is ahead by the increased time
+	d.timeOffset = 3600
+	assert.GreaterOrEqual(t, d.blockTimestamp(parent), parent.Timestamp+3600)
+
+	// the timestamp set for the next block is used once
+	d.nextTimestamp = parent.Timestamp + 10
+	assert.Equal(t, parent.Timestamp+10, d.blockTimestamp(parent))
+	assert.Zero(t, d.nextTimestamp)
+
+	// the blocks are not before their parent after the clock went back
+	d.timeOffset = -3600
+	assert.Equal(t, parent.Timestamp, d.blockTimestamp(parent))
+}
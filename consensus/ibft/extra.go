@@ -15,7 +15,8 @@ var (
 	// IstanbulExtraVanity represents a fixed number of extra-data bytes reserved for proposer vanity
 	IstanbulExtraVanity = 32
 
-	// IstanbulExtraSeal represents the fixed number of extra-data bytes reserved for proposer seal
+	// IstanbulExtraSeal represents the fixed number of extra-data bytes reserved for proposer seal,
+	// with the default secp256k1 signature scheme
 	IstanbulExtraSeal = 65
 )
 
@@ -41,6 +42,14 @@ func putIbftExtraValidators(h *types.Header, validators []types.Address) {
 	h.ExtraData = extra
 }
 
+// GenesisExtraData returns the extra data of the genesis block of a chain with the initial validator set
+func GenesisExtraData(validators []types.Address) []byte {
+	h := &types.Header{}
+	putIbftExtraValidators(h, validators)
+
+	return h.ExtraData
+}
+
 // PutIbftExtra sets the extra data field in the header to the passed in istanbul extra data
 func PutIbftExtra(h *types.Header, istanbulExtra *IstanbulExtra) error {
 	// Pad zeros to the right up to istanbul vanity
@@ -0,0 +1,157 @@
+package ibft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-multierror"
+)
+
+func init() {
+	chain.RegisterEngineCheck("ibft", validateGenesis)
+}
+
+// epochSizeFromConfig returns the epoch size set in the engine params, or the default one
+func epochSizeFromConfig(config map[string]interface{}) (uint64, error) {
+	epochSize, err := uint64FromConfig(config, "epochSize", "epoch size", DefaultEpochSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if epochSize == 0 {
+		return 0, fmt.Errorf("the epoch size must be positive")
+	}
+
+	return epochSize, nil
+}
+
+// blockTimeFromConfig returns the block time set in seconds in the engine params, or the default one
+func blockTimeFromConfig(config map[string]interface{}) (time.Duration, error) {
+	blockTime, err := uint64FromConfig(config, "blockTime", "block time", uint64(defaultBlockPeriod/time.Second))
+	if err != nil {
+		return 0, err
+	}
+
+	if blockTime == 0 {
+		return 0, fmt.Errorf("the block time must be positive")
+	}
+
+	return time.Duration(blockTime) * time.Second, nil
+}
+
+// boolFromConfig returns the flag set in the engine params, false if not set
+func boolFromConfig(config map[string]interface{}, key string) (bool, error) {
+	value, ok := config[key]
+	if !ok {
+		return false, nil
+	}
+
+	flag, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("the %s flag %v is not a boolean", key, value)
+	}
+
+	return flag, nil
+}
+
+// uint64FromConfig returns the number set in the engine params, or the default value
+func uint64FromConfig(config map[string]interface{}, key, name string, defaultValue uint64) (uint64, error) {
+	value, ok := config[key]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if v != float64(uint64(v)) {
+			return 0, fmt.Errorf("the %s %v is not an integer", name, v)
+		}
+		return uint64(v), nil
+	case int:
+		if v < 0 {
+			return 0, fmt.Errorf("the %s %d is negative", name, v)
+		}
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("the %s %v is not a number", name, value)
+	}
+}
+
+// validateGenesis checks the engine params and the validator set of the genesis of a chain.
+// The validator snapshots are built per epoch, so the fork heights and the upgrades
+// of the chain must be at the start of an epoch
+func validateGenesis(c *chain.Chain) error {
+	var err error
+	fail := func(format string, args ...interface{}) {
+		err = multierror.Append(err, fmt.Errorf(format, args...))
+	}
+
+	config, _ := c.Params.Engine["ibft"].(map[string]interface{})
+
+	// the validator keys are of the signature scheme of the chain
+	if name, ok := config["hasher"].(string); ok && name != "" {
+		if _, hasherErr := crypto.GetHasher(name); hasherErr != nil {
+			fail("%v", hasherErr)
+		}
+	}
+	if name, ok := config["signatureScheme"].(string); ok && name != "" {
+		if _, schemeErr := crypto.GetSignatureScheme(name); schemeErr != nil {
+			fail("%v", schemeErr)
+		}
+	}
+
+	extra, extraErr := getIbftExtra(&types.Header{ExtraData: c.Genesis.ExtraData})
+	if extraErr != nil {
+		fail("the extra data of the genesis has no validator set: %v", extraErr)
+	} else {
+		if len(extra.Validators) == 0 {
+			fail("the validator set of the genesis is empty")
+		}
+
+		seen := map[types.Address]struct{}{}
+		for _, validator := range extra.Validators {
+			if validator == types.ZeroAddress {
+				fail("the validator set of the genesis has the zero address")
+			}
+			if _, ok := seen[validator]; ok {
+				fail("validator %s is in the validator set of the genesis twice", validator)
+			}
+			seen[validator] = struct{}{}
+		}
+	}
+
+	if _, blockTimeErr := blockTimeFromConfig(config); blockTimeErr != nil {
+		fail("%v", blockTimeErr)
+	}
+	for _, key := range []string{"fixedBlockTime", "skipEmptyBlocks"} {
+		if _, flagErr := boolFromConfig(config, key); flagErr != nil {
+			fail("%v", flagErr)
+		}
+	}
+
+	epochSize, epochErr := epochSizeFromConfig(config)
+	if epochErr != nil {
+		fail("%v", epochErr)
+		return err
+	}
+
+	if c.Params.Forks != nil {
+		for _, height := range c.Params.Forks.Heights() {
+			if height%epochSize != 0 {
+				fail("the fork at block %d is not at the start of an epoch of %d blocks", height, epochSize)
+			}
+		}
+	}
+	for _, upgrade := range c.Params.Upgrades {
+		if upgrade.Block%epochSize != 0 {
+			fail("the upgrade of %s at block %d is not at the start of an epoch of %d blocks", upgrade.Address, upgrade.Block, epochSize)
+		}
+	}
+
+	return err
+}
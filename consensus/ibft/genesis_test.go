@@ -0,0 +1,48 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGenesis(t *testing.T) {
+	validators := []types.Address{types.StringToAddress("1"), types.StringToAddress("2")}
+
+	build := func(validators []types.Address, params map[string]interface{}, fork uint64) error {
+		_, err := chain.NewGenesisBuilder("test").
+			ChainID(100).
+			Consensus("ibft", params).
+			ExtraData(GenesisExtraData(validators)).
+			Fork("berlin", fork).
+			Build()
+		return err
+	}
+
+	assert.NoError(t, build(validators, nil, 0))
+	assert.NoError(t, build(validators, map[string]interface{}{"epochSize": float64(10)}, 20))
+
+	// the validator set is required, and its validators are unique
+	assert.Error(t, build(nil, nil, 0))
+	assert.Error(t, build([]types.Address{validators[0], validators[0]}, nil, 0))
+
+	// the signature scheme must exist
+	assert.Error(t, build(validators, map[string]interface{}{"signatureScheme": "unknown"}, 0))
+
+	// the forks are activated at the start of an epoch
+	assert.Error(t, build(validators, map[string]interface{}{"epochSize": float64(10)}, 15))
+	assert.Error(t, build(validators, map[string]interface{}{"epochSize": float64(0)}, 0))
+	assert.Error(t, build(validators, map[string]interface{}{"epochSize": 1.5}, 0))
+
+	// the block time is a positive number of seconds, the flags are booleans
+	assert.NoError(t, build(validators, map[string]interface{}{
+		"blockTime":       float64(5),
+		"fixedBlockTime":  true,
+		"skipEmptyBlocks": true,
+	}, 0))
+	assert.Error(t, build(validators, map[string]interface{}{"blockTime": float64(0)}, 0))
+	assert.Error(t, build(validators, map[string]interface{}{"blockTime": "5s"}, 0))
+	assert.Error(t, build(validators, map[string]interface{}{"skipEmptyBlocks": "yes"}, 0))
+}
@@ -1,7 +1,6 @@
 package ibft
 
 import (
-	"github.com/0xPolygon/polygon-sdk/helper/keccak"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/umbracle/fastrlp"
 )
@@ -38,7 +37,11 @@ func istanbulHeaderHash(h *types.Header) types.Hash {
 	vv.Set(arena.NewUint(h.Timestamp))
 	vv.Set(arena.NewCopyBytes(h.ExtraData))
 
-	buf := keccak.Keccak256Rlp(nil, vv)
+	if h.BaseFee != nil {
+		vv.Set(arena.NewBigInt(h.BaseFee))
+	}
+
+	buf := hasher.Hash(vv.MarshalTo(nil))
 
 	return types.BytesToHash(buf)
 }
@@ -1,10 +1,12 @@
 package ibft
 
 import (
-	"crypto/ecdsa"
+	"context"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-sdk/consensus"
@@ -17,6 +19,9 @@ import (
 	"github.com/0xPolygon/polygon-sdk/state"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	any "google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -29,6 +34,7 @@ type blockchainInterface interface {
 	GetHeaderByNumber(i uint64) (*types.Header, bool)
 	WriteBlocks(blocks []*types.Block) error
 	CalculateGasLimit(number uint64) (uint64, error)
+	CalculateBaseFee(parent *types.Header) *big.Int
 }
 
 type transactionPoolInterface interface {
@@ -36,11 +42,13 @@ type transactionPoolInterface interface {
 	Pop() (*types.Transaction, func())
 	DecreaseAccountNonce(tx *types.Transaction)
 	Length() uint64
+	SpanContext(hash types.Hash) trace.SpanContext
 }
 
 // Ibft represents the IBFT consensus mechanism object
 type Ibft struct {
-	sealing bool // Flag indicating if the node is a sealer
+	sealing     bool // Flag indicating if the node is a sealer
+	sealingLock sync.RWMutex
 
 	logger hclog.Logger      // Output logger
 	config *consensus.Config // Consensus configuration
@@ -50,7 +58,12 @@ type Ibft struct {
 	executor   *state.Executor     // Reference to the state executor
 	closeCh    chan struct{}       // Channel for closing
 
-	validatorKey     *ecdsa.PrivateKey // Private key for the validator
+	// stopCh is closed once the node stops taking part in the consensus, doneCh once the state machine returned
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	doneCh   chan struct{}
+
+	validatorKey     crypto.Signer // Signer of the private key for the validator
 	validatorKeyAddr types.Address
 
 	txpool transactionPoolInterface // Reference to the transaction pool
@@ -58,6 +71,12 @@ type Ibft struct {
 	store     *snapshotStore // Snapshot store that keeps track of all snapshots
 	epochSize uint64
 
+	// blockTime is the time between the blocks. With fixedBlockTime, the blocks are a multiple of it
+	// after their parents. With skipEmptyBlocks, no block is built while the txpool is empty
+	blockTime       time.Duration
+	fixedBlockTime  bool
+	skipEmptyBlocks bool
+
 	msgQueue *msgQueue     // Structure containing different message queues
 	updateCh chan struct{} // Update channel
 
@@ -65,14 +84,31 @@ type Ibft struct {
 	syncNotifyCh chan bool        // Sync protocol notification channel
 
 	network   *network.Server // Reference to the networking layer
+	consensusNetwork *network.Server // Reference to the network of the validator traffic
 	transport transport       // Reference to the transport protocol
 
 	operator *operator
 
+	// round is the view of the current round and its proposer, published for the operator status
+	round     roundInfo
+	roundLock sync.RWMutex
+
+	registry *registryPublisher // Publisher of the validator set of each epoch, if a registry is set
+
+	systemTxs consensus.SystemTxProvider // Provider of the system transactions at the top of the blocks
+
 	// aux test methods
 	forceTimeoutCh bool
   
 	metrics *consensus.Metrics
+
+	// sequenceStart is the time the node started sealing the sequence of sequenceNumber
+	sequenceStart  time.Time
+	sequenceNumber uint64
+
+	// sequenceSpan is the span of the sequence, the parent of the spans of its proposal and commit
+	sequenceCtx  context.Context
+	sequenceSpan trace.Span
   
 	secretsManager secrets.SecretsManager
 }
@@ -87,20 +123,65 @@ func Factory(
 		blockchain:     params.Blockchain,
 		executor:       params.Executor,
 		closeCh:        make(chan struct{}),
+		stopCh:         make(chan struct{}),
+		doneCh:         make(chan struct{}),
 		txpool:         params.Txpool,
 		state:          &currentState{},
 		network:        params.Network,
+		consensusNetwork: params.Network,
 		epochSize:      DefaultEpochSize,
 		syncNotifyCh:   make(chan bool),
 		sealing:        params.Seal,
     metrics:        params.Metrics,
 		secretsManager: params.SecretsManager,
+		systemTxs:      params.SystemTxs,
+	}
+
+	epochSize, err := epochSizeFromConfig(params.Config.Config)
+	if err != nil {
+		return nil, err
+	}
+	p.epochSize = epochSize
+
+	if p.blockTime, err = blockTimeFromConfig(params.Config.Config); err != nil {
+		return nil, err
+	}
+	if p.fixedBlockTime, err = boolFromConfig(params.Config.Config, "fixedBlockTime"); err != nil {
+		return nil, err
+	}
+	if p.skipEmptyBlocks, err = boolFromConfig(params.Config.Config, "skipEmptyBlocks"); err != nil {
+		return nil, err
+	}
+
+	// The validator traffic is isolated from the public gossip, if a consensus network is set
+	if params.ConsensusNetwork != nil {
+		p.consensusNetwork = params.ConsensusNetwork
+	}
+
+	// Publish the validator sets to the registry of the network, if set
+	if endpoint, ok := params.Config.Config["registry"].(string); ok && endpoint != "" {
+		registry, err := newRegistryPublisher(p.logger, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		p.registry = registry
+	}
+
+	// Select the hasher and the signature scheme of the chain
+	if err := setupScheme(params.Config.Config); err != nil {
+		return nil, err
 	}
 
 	// Istanbul requires a different header hash function
 	types.HeaderHash = istanbulHeaderHash
 
 	p.syncer = protocol.NewSyncer(params.Logger, params.Network, params.Blockchain)
+	p.syncer.SetForkMonitor(params.ForkMonitor)
+	p.syncer.SetSyncTracker(params.SyncTracker)
+	p.syncer.SetStateStorage(params.StateStorage)
+	if params.SnapshotSync {
+		p.syncer.EnableSnapshotSync()
+	}
 
 	// register the grpc operator
 	p.operator = &operator{ibft: p}
@@ -131,6 +212,11 @@ func (i *Ibft) Start() error {
 		return err
 	}
 
+	// Start the publishing of the validator sets
+	if i.registry != nil {
+		go i.registry.run(i.closeCh)
+	}
+
 	// Start the actual IBFT protocol
 	go i.start()
 
@@ -156,7 +242,7 @@ func (g *gossipTransport) Gossip(msg *proto.MessageReq) error {
 // setupTransport sets up the gossip transport protocol
 func (i *Ibft) setupTransport() error {
 	// Define a new topic
-	topic, err := i.network.NewTopic(ibftProto, &proto.MessageReq{})
+	topic, err := i.consensusNetwork.NewTopic(ibftProto, &proto.MessageReq{})
 	if err != nil {
 		return err
 	}
@@ -204,33 +290,38 @@ func (i *Ibft) createKey() error {
 
 	if i.validatorKey == nil {
 		// Check if the validator key is initialized
-		var key *ecdsa.PrivateKey
+		var validatorKeyEncoded []byte
 		if i.secretsManager.HasSecret(secrets.ValidatorKey) {
 			// The validator key is present in the secrets manager, load it
-			validatorKey, readErr := crypto.ReadConsensusKey(i.secretsManager)
+			key, readErr := i.secretsManager.GetSecret(secrets.ValidatorKey)
 			if readErr != nil {
 				return fmt.Errorf("unable to read validator key from Secrets Manager, %v", readErr)
 			}
 
-			key = validatorKey
+			validatorKeyEncoded = key
 		} else {
 			// The validator key is not present in the secrets manager, generate it
-			validatorKey, validatorKeyEncoded, genErr := crypto.GenerateAndEncodePrivateKey()
+			key, genErr := signatureScheme.GenerateKey()
 			if genErr != nil {
 				return fmt.Errorf("unable to generate validator key for Secrets Manager, %v", genErr)
 			}
 
 			// Save the key to the secrets manager
-			saveErr := i.secretsManager.SetSecret(secrets.ValidatorKey, validatorKeyEncoded)
+			saveErr := i.secretsManager.SetSecret(secrets.ValidatorKey, key)
 			if saveErr != nil {
 				return fmt.Errorf("unable to save validator key to Secrets Manager, %v", saveErr)
 			}
 
-			key = validatorKey
+			validatorKeyEncoded = key
+		}
+
+		signer, err := signatureScheme.NewSigner(validatorKeyEncoded)
+		if err != nil {
+			return fmt.Errorf("unable to read validator key from Secrets Manager, %v", err)
 		}
 
-		i.validatorKey = key
-		i.validatorKeyAddr = crypto.PubKeyToAddress(&key.PublicKey)
+		i.validatorKey = signer
+		i.validatorKeyAddr = signer.Address()
 	}
 
 	return nil
@@ -240,6 +331,8 @@ const IbftKeyName = "validator.key"
 
 // start starts the IBFT consensus state machine
 func (i *Ibft) start() {
+	defer close(i.doneCh)
+
 	// consensus always starts in SyncState mode in case it needs
 	// to sync with other nodes.
 	i.setState(SyncState)
@@ -255,6 +348,12 @@ func (i *Ibft) start() {
 		default: // Default is here because we would block until we receive something in the closeCh
 		}
 
+		// the round of the block being validated is completed before the node stops
+		if i.isStopping() && !i.isState(ValidateState) {
+			i.logger.Info("stopped taking part in the consensus", "state", i.getState())
+			return
+		}
+
 		// Start the state machine loop
 		i.runCycle()
 	}
@@ -311,7 +410,7 @@ func (i *Ibft) isValidSnapshot() bool {
 //
 // It fetches fresh data from the blockchain. Checks if the current node is a validator and resolves any pending blocks
 func (i *Ibft) runSyncState() {
-	for i.isState(SyncState) {
+	for i.isState(SyncState) && !i.isStopping() {
 		// try to sync with some target peer
 		p := i.syncer.BestPeer()
 		if p == nil {
@@ -335,6 +434,11 @@ func (i *Ibft) runSyncState() {
 			continue
 		}
 
+		if err := i.syncer.SnapshotSyncWithPeer(p); err != nil {
+			i.logger.Error("failed to snapshot sync", "err", err)
+			continue
+		}
+
 		if err := i.syncer.BulkSyncWithPeer(p); err != nil {
 			i.logger.Error("failed to bulk sync", "err", err)
 			continue
@@ -367,8 +471,46 @@ func (i *Ibft) runSyncState() {
 
 var defaultBlockPeriod = 2 * time.Second
 
+// emptyPoolPollInterval is the interval the txpool is checked at by the proposer when the empty blocks are skipped
+var emptyPoolPollInterval = 250 * time.Millisecond
+
+// nextBlockTime returns the timestamp of the block after parent, built at now. The block is a block time
+// after its parent at the earliest. With a fixed block time, it is on the next multiple of the block time
+func (i *Ibft) nextBlockTime(parent *types.Header, now time.Time) time.Time {
+	headerTime := time.Unix(int64(parent.Timestamp), 0).Add(i.blockTime)
+	if !headerTime.Before(now) {
+		return headerTime
+	}
+
+	if !i.fixedBlockTime {
+		return now
+	}
+
+	// the missed slots are skipped
+	missed := now.Sub(headerTime) / i.blockTime
+	headerTime = headerTime.Add(missed * i.blockTime)
+	if headerTime.Before(now) {
+		headerTime = headerTime.Add(i.blockTime)
+	}
+
+	return headerTime
+}
+
+// hasBlockContent checks if the block of the proposer would have any content,
+// the txns of the pool or a vote for a candidate
+func (i *Ibft) hasBlockContent(snap *Snapshot) bool {
+	return i.txpool.Length() != 0 || i.operator.getNextCandidate(snap) != nil
+}
+
 // buildBlock builds the block, based on the passed in snapshot and parent header
 func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, error) {
+	ctx, span := tracer.Start(
+		i.sequenceContext(),
+		"ibft.buildBlock",
+		trace.WithAttributes(attribute.Int64("number", int64(parent.Number+1))),
+	)
+	defer span.End()
+
 	header := &types.Header{
 		ParentHash: parent.Hash,
 		Number:     parent.Number + 1,
@@ -387,6 +529,7 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 		return nil, err
 	}
 	header.GasLimit = gasLimit
+	header.BaseFee = i.blockchain.CalculateBaseFee(parent)
 
 	// try to pick a candidate
 	if candidate := i.operator.getNextCandidate(snap); candidate != nil {
@@ -399,13 +542,7 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 	}
 
 	// set the timestamp
-	parentTime := time.Unix(int64(parent.Timestamp), 0)
-	headerTime := parentTime.Add(defaultBlockPeriod)
-
-	if headerTime.Before(time.Now()) {
-		headerTime = time.Now()
-	}
-	header.Timestamp = uint64(headerTime.Unix())
+	header.Timestamp = uint64(i.nextBlockTime(parent, time.Now()).Unix())
 
 	// we need to include in the extra field the current set of validators
 	putIbftExtraValidators(header, snap.Set)
@@ -414,7 +551,13 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 	if err != nil {
 		return nil, err
 	}
-	txns := i.writeTransactions(gasLimit, transition)
+	// the system transactions go first, then the ones of the pool
+	txns, err := consensus.WriteSystemTransactions(i.systemTxs, header, transition)
+	if err != nil {
+		return nil, err
+	}
+	txns = append(txns, i.writeTransactions(ctx, gasLimit, transition)...)
+	span.SetAttributes(attribute.Int("txns", len(txns)))
 
 	_, root := transition.Commit()
 	header.StateRoot = root
@@ -448,7 +591,11 @@ type transitionInterface interface {
 
 // writeTransactions writes transactions from the txpool to the transition object
 // and returns transactions that were included in the transition (new block)
-func (i *Ibft) writeTransactions(gasLimit uint64, transition transitionInterface) []*types.Transaction {
+func (i *Ibft) writeTransactions(
+	ctx context.Context,
+	gasLimit uint64,
+	transition transitionInterface,
+) []*types.Transaction {
 	txns := []*types.Transaction{}
 	returnTxnFuncs := []func(){}
 	for {
@@ -463,7 +610,7 @@ func (i *Ibft) writeTransactions(gasLimit uint64, transition transitionInterface
 			continue
 		}
 
-		if err := transition.Write(txn); err != nil {
+		if err := i.executeTransaction(ctx, transition, txn); err != nil {
 			if _, ok := err.(*state.GasLimitReachedTransitionApplicationError); ok {
 				returnTxnFuncs = append(returnTxnFuncs, retTxnFn)
 				break
@@ -521,6 +668,12 @@ func (i *Ibft) runAcceptState() { // start new round
 
 	i.logger.Info("current snapshot", "validators", len(snap.Set), "votes", len(snap.Votes))
 
+	if i.sequenceNumber != number {
+		i.sequenceNumber = number
+		i.sequenceStart = time.Now()
+		i.startSequenceSpan(number)
+	}
+
 	i.state.validators = snap.Set
 
 	//Update the No.of validator metric
@@ -535,11 +688,29 @@ func (i *Ibft) runAcceptState() { // start new round
 	}
 
 	i.state.CalcProposer(lastProposer)
+	i.setRound(i.state.view, i.state.proposer)
 
 	if i.state.proposer == i.validatorKeyAddr {
 		logger.Info("we are the proposer", "block", number)
 
+		if i.isStopping() {
+			i.abdicate()
+			return
+		}
+
 		if !i.state.locked {
+			// the block is only built once there is something to seal, if the empty blocks are skipped
+			for i.skipEmptyBlocks && !i.hasBlockContent(snap) {
+				select {
+				case <-time.After(emptyPoolPollInterval):
+				case <-i.stopCh:
+					i.abdicate()
+					return
+				case <-i.closeCh:
+					return
+				}
+			}
+
 			// since the state is not locked, we need to build a new block
 			i.state.block, err = i.buildBlock(snap, parent)
 			if err != nil {
@@ -553,6 +724,9 @@ func (i *Ibft) runAcceptState() { // start new round
 
 			select {
 			case <-time.After(delay):
+			case <-i.stopCh:
+				i.abdicate()
+				return
 			case <-i.closeCh:
 				return
 			}
@@ -581,6 +755,10 @@ func (i *Ibft) runAcceptState() { // start new round
 			return
 		}
 		if msg == nil {
+			if i.skipEmptyBlocks && i.txpool.Length() == 0 {
+				// the proposer is waiting for txns, there is nothing to change the round for
+				continue
+			}
 			i.setState(RoundChangeState)
 			continue
 		}
@@ -611,6 +789,9 @@ func (i *Ibft) runAcceptState() { // start new round
 			if err := i.verifyHeaderImpl(snap, parent, block.Header); err != nil {
 				i.logger.Error("block verification failed", "err", err)
 				i.handleStateErr(errBlockVerificationFailed)
+			} else if err := consensus.VerifySystemTransactions(i.systemTxs, block); err != nil {
+				i.logger.Error("the proposal has unexpected system transactions", "err", err)
+				i.handleStateErr(errBlockVerificationFailed)
 			} else {
 				i.state.block = block
 
@@ -710,6 +891,10 @@ func (i *Ibft) updateMetrics(block *types.Block) {
 	}
 	//Update the Number of transactions in the block metric
 	i.metrics.NumTxs.Set(float64(len(block.Body().Transactions)))
+	//Update the sealing duration metric, over all the rounds of the sequence
+	if i.sequenceNumber == block.Number() {
+		i.metrics.SequenceDuration.Observe(time.Since(i.sequenceStart).Seconds())
+	}
 
 }
 func (i *Ibft) insertBlock(block *types.Block) error {
@@ -728,9 +913,21 @@ func (i *Ibft) insertBlock(block *types.Block) error {
 	block.Header = header
 	block.Header.ComputeHash()
 
+	_, span := tracer.Start(
+		i.sequenceContext(),
+		"ibft.insertBlock",
+		trace.WithAttributes(attribute.Int64("number", int64(header.Number))),
+	)
 	if err := i.blockchain.WriteBlocks([]*types.Block{block}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+
 		return err
 	}
+	span.End()
+
+	// the sequence ends with the commit of its block
+	i.endSequenceSpan()
 
 	i.logger.Info(
 		"block committed",
@@ -774,6 +971,7 @@ func (i *Ibft) runRoundChangeState() {
 		// set the new round and update the round metric
 		i.state.view.Round = round
 		i.metrics.Rounds.Set(float64(round))
+		i.metrics.RoundChanges.Add(1)
 		// clean the round
 		i.state.cleanRound(round)
 		// send the round change message
@@ -945,9 +1143,20 @@ func (i *Ibft) randomTimeout() time.Duration {
 
 // isSealing checks if the current node is sealing blocks
 func (i *Ibft) isSealing() bool {
+	i.sealingLock.RLock()
+	defer i.sealingLock.RUnlock()
+
 	return i.sealing
 }
 
+// stopSealing stops the sealing of blocks, the node keeps following the chain
+func (i *Ibft) stopSealing() {
+	i.sealingLock.Lock()
+	defer i.sealingLock.Unlock()
+
+	i.sealing = false
+}
+
 // verifyHeaderImpl implements the actual header verification logic
 func (i *Ibft) verifyHeaderImpl(snap *Snapshot, parent, header *types.Header) error {
 	// ensure the extra data is correctly formatted
@@ -975,6 +1184,15 @@ func (i *Ibft) verifyHeaderImpl(snap *Snapshot, parent, header *types.Header) er
 		return fmt.Errorf("wrong difficulty")
 	}
 
+	// the blocks are a multiple of the block time after their parents, with a fixed block time
+	if i.fixedBlockTime {
+		blockTime := uint64(i.blockTime / time.Second)
+		if header.Timestamp <= parent.Timestamp || (header.Timestamp-parent.Timestamp)%blockTime != 0 {
+			return fmt.Errorf("the block time %ds is not a multiple of %ds",
+				int64(header.Timestamp)-int64(parent.Timestamp), blockTime)
+		}
+	}
+
 	// verify the sealer
 	if err := verifySigner(snap, header); err != nil {
 		return err
@@ -1005,6 +1223,8 @@ func (i *Ibft) VerifyHeader(parent, header *types.Header) error {
 		return err
 	}
 
+	i.publishEpoch(header)
+
 	return nil
 }
 
@@ -1013,6 +1233,104 @@ func (i *Ibft) GetBlockCreator(header *types.Header) (types.Address, error) {
 	return ecrecoverFromHeader(header)
 }
 
+// FinalitySignatures returns the committed seals of the block, the signatures of the validators which committed it
+func (i *Ibft) FinalitySignatures(header *types.Header) ([][]byte, error) {
+	extra, err := getIbftExtra(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return extra.CommittedSeal, nil
+}
+
+// roundInfo is the view of a round and its proposer
+type roundInfo struct {
+	sequence uint64
+	round    uint64
+	proposer types.Address
+}
+
+// setRound publishes the view and the proposer of the current round
+func (i *Ibft) setRound(view *proto.View, proposer types.Address) {
+	i.roundLock.Lock()
+	defer i.roundLock.Unlock()
+
+	i.round = roundInfo{
+		sequence: view.Sequence,
+		round:    view.Round,
+		proposer: proposer,
+	}
+}
+
+// getRound returns the view and the proposer of the current round
+func (i *Ibft) getRound() roundInfo {
+	i.roundLock.RLock()
+	defer i.roundLock.RUnlock()
+
+	return i.round
+}
+
+// Status returns the highest block of the peers, and whether the node is a validator taking part in the sequences
+func (i *Ibft) Status() *consensus.Status {
+	status := &consensus.Status{
+		HighestBlock: i.syncer.HighestBlock(),
+	}
+
+	if i.isSealing() {
+		header := i.blockchain.Header()
+		if snap, err := i.getSnapshot(header.Number); err == nil {
+			status.Validator = snap.Set.Includes(i.validatorKeyAddr)
+		}
+	}
+
+	// the validator leaves the sync state once it is in sync and joins the rounds
+	status.Participating = status.Validator && !i.isState(SyncState)
+
+	return status
+}
+
+// isStopping returns true once the node stops taking part in the consensus
+func (i *Ibft) isStopping() bool {
+	select {
+	case <-i.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// abdicate gives up the proposal of the round, and sends the change to the next round
+// so the other validators don't only start it once the proposal times out
+func (i *Ibft) abdicate() {
+	i.logger.Info("abdicating the round", "sequence", i.state.view.Sequence, "round", i.state.view.Round)
+
+	i.state.view.Round++
+	i.state.cleanRound(i.state.view.Round)
+	i.sendRoundChange()
+}
+
+// Stop stops taking part in the consensus before the node is closed. The round of the
+// block being validated is completed, the block is committed or the round changes, and
+// the rounds the node would propose a block in are abdicated. It returns once the state
+// machine returned, or the context is done
+func (i *Ibft) Stop(ctx context.Context) error {
+	i.stopOnce.Do(func() {
+		close(i.stopCh)
+	})
+
+	// the sync with a peer isn't interrupted, there is no round to complete
+	if i.isState(SyncState) {
+		return nil
+	}
+
+	select {
+	case <-i.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Close closes the IBFT consensus mechanism, and does write back to disk
 func (i *Ibft) Close() error {
 	close(i.closeCh)
@@ -1030,6 +1348,13 @@ func (i *Ibft) Close() error {
 // getNextMessage reads a new message from the message queue
 func (i *Ibft) getNextMessage(timeout time.Duration) (*proto.MessageReq, bool) {
 	timeoutCh := time.After(timeout)
+
+	// the stop waits for the end of the round of the block being validated
+	stopCh := i.stopCh
+	if i.isState(ValidateState) {
+		stopCh = nil
+	}
+
 	for {
 		msg := i.msgQueue.readMessage(i.getState(), i.state.view)
 		if msg != nil {
@@ -1048,6 +1373,8 @@ func (i *Ibft) getNextMessage(timeout time.Duration) (*proto.MessageReq, bool) {
 			return nil, true
 		case <-i.closeCh:
 			return nil, false
+		case <-stopCh:
+			return nil, false
 		case <-i.updateCh:
 		}
 	}
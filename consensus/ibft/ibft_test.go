@@ -1,8 +1,11 @@
 package ibft
 
 import (
+	"context"
 	"github.com/0xPolygon/polygon-sdk/state"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
 	"github.com/0xPolygon/polygon-sdk/consensus"
@@ -11,6 +14,7 @@ import (
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 	any "google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -152,7 +156,7 @@ func TestTransition_AcceptState_Validator_VerifyCorrect(t *testing.T) {
 	i.setState(AcceptState)
 
 	block := i.DummyBlock()
-	header, err := writeSeal(i.pool.get("A").priv, block.Header)
+	header, err := writeSeal(i.pool.get("A").signer(), block.Header)
 	assert.NoError(t, err)
 	block.Header = header
 
@@ -183,7 +187,7 @@ func TestTransition_AcceptState_Validator_VerifyFails(t *testing.T) {
 	block := i.DummyBlock()
 	block.Header.MixHash = types.Hash{} // invalidates the block
 
-	header, err := writeSeal(i.pool.get("A").priv, block.Header)
+	header, err := writeSeal(i.pool.get("A").signer(), block.Header)
 	assert.NoError(t, err)
 	block.Header = header
 
@@ -455,6 +459,178 @@ func TestTransition_RoundChangeState_MaxRound(t *testing.T) {
 	})
 }
 
+func TestTransition_AcceptState_Proposer_Abdicates(t *testing.T) {
+	// the stopping proposer doesn't build its block, it sends the change to the next round
+	i := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+	i.setState(AcceptState)
+	close(i.stopCh)
+
+	i.runCycle()
+
+	i.expect(expectResult{
+		sequence: 1,
+		round:    1,
+		state:    AcceptState,
+		outgoing: 1, // round change
+	})
+	assert.Equal(t, proto.MessageReq_RoundChange, i.respMsg[0].Type)
+}
+
+func TestTransition_AcceptState_Validator_Stops(t *testing.T) {
+	// the stopping validator doesn't wait for the proposal, nor times out into a round change
+	i := newMockIbft(t, []string{"A", "B", "C"}, "C")
+	i.setState(AcceptState)
+	close(i.stopCh)
+
+	i.runCycle()
+
+	i.expect(expectResult{
+		sequence: 1,
+		state:    AcceptState,
+	})
+}
+
+func TestIbft_Stop(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "C")
+	i.setState(ValidateState)
+
+	// Stop waits for the state machine, until the context is done
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelFn()
+	assert.Equal(t, context.DeadlineExceeded, i.Stop(ctx))
+
+	// the round of the block being validated isn't interrupted by the stop
+	msg, ok := i.getNextMessage(10 * time.Millisecond)
+	assert.Nil(t, msg)
+	assert.True(t, ok)
+
+	close(i.doneCh)
+	assert.NoError(t, i.Stop(context.Background()))
+
+	// there is no round to complete while syncing
+	j := newMockIbft(t, []string{"A", "B", "C"}, "C")
+	j.setState(SyncState)
+	assert.NoError(t, j.Stop(context.Background()))
+	assert.True(t, j.isStopping())
+}
+
+func TestTransition_AcceptState_Proposer_SkipsEmptyBlock(t *testing.T) {
+	// the proposer doesn't build a block while the txpool is empty
+	i := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+	i.setState(AcceptState)
+	i.skipEmptyBlocks = true
+	i.txpool = &mockTxPool{}
+
+	go func() {
+		time.Sleep(2 * emptyPoolPollInterval)
+		close(i.closeCh)
+	}()
+
+	i.runCycle()
+
+	i.expect(expectResult{
+		sequence: 1,
+		state:    AcceptState,
+	})
+}
+
+func TestTransition_AcceptState_Validator_WaitsForTxns(t *testing.T) {
+	// the validator doesn't change the round while the proposer waits for txns
+	i := newMockIbft(t, []string{"A", "B", "C"}, "C")
+	i.setState(AcceptState)
+	i.skipEmptyBlocks = true
+	i.txpool = &mockTxPool{}
+	i.forceTimeoutCh = true
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(i.closeCh)
+	}()
+
+	i.runCycle()
+
+	i.expect(expectResult{
+		sequence: 1,
+		state:    AcceptState,
+	})
+}
+
+func TestIbft_NextBlockTime(t *testing.T) {
+	parent := &types.Header{Timestamp: 100}
+
+	tests := []struct {
+		name      string
+		fixed     bool
+		now       int64
+		timestamp int64
+	}{
+		{
+			name:      "should be a block time after the parent",
+			now:       101,
+			timestamp: 102,
+		},
+		{
+			name:      "should be now once the block time passed",
+			now:       105,
+			timestamp: 105,
+		},
+		{
+			name:      "should be a block time after the parent with a fixed block time",
+			fixed:     true,
+			now:       101,
+			timestamp: 102,
+		},
+		{
+			name:      "should skip the missed slots with a fixed block time",
+			fixed:     true,
+			now:       105,
+			timestamp: 106,
+		},
+		{
+			name:      "should be on the current slot with a fixed block time",
+			fixed:     true,
+			now:       106,
+			timestamp: 106,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Ibft{blockTime: 2 * time.Second, fixedBlockTime: tt.fixed}
+
+			assert.Equal(t, tt.timestamp, i.nextBlockTime(parent, time.Unix(tt.now, 0)).Unix())
+		})
+	}
+}
+
+func TestIbft_VerifyHeader_FixedBlockTime(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "A")
+	i.fixedBlockTime = true
+
+	parent, ok := i.blockchain.GetHeaderByNumber(0)
+	assert.True(t, ok)
+
+	snap, err := i.getSnapshot(0)
+	assert.NoError(t, err)
+
+	for _, timestamp := range []uint64{parent.Timestamp, parent.Timestamp + 3} {
+		header := i.DummyBlock().Header
+		header.Timestamp = timestamp
+
+		err := i.verifyHeaderImpl(snap, parent, header)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a multiple of")
+	}
+
+	// a block on the fixed block time is checked further
+	header := i.DummyBlock().Header
+	header.Timestamp = parent.Timestamp + 4
+
+	err = i.verifyHeaderImpl(snap, parent, header)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "is not a multiple of")
+}
+
 func TestWriteTransactions(t *testing.T) {
 	type testCase struct {
 		description               string
@@ -553,7 +729,7 @@ func TestWriteTransactions(t *testing.T) {
 			m.txpool = mockTxPool
 			mockTransition := setupMockTransition(test, mockTxPool)
 
-			included := m.writeTransactions(1000, mockTransition)
+			included := m.writeTransactions(context.Background(), 1000, mockTransition)
 
 			assert.Equal(t, test.expectedTxPoolLength, len(mockTxPool.transactions))
 			assert.Equal(t, test.expectedIncludedTxnsCount, len(included))
@@ -595,6 +771,10 @@ func (p *mockTxPool) DecreaseAccountNonce(txn *types.Transaction) {
 	p.nonceDecreased[txn] = true
 }
 
+func (p *mockTxPool) SpanContext(hash types.Hash) trace.SpanContext {
+	return trace.SpanContext{}
+}
+
 func (p *mockTxPool) Length() uint64 {
 	return uint64(len(p.transactions))
 }
@@ -695,6 +875,10 @@ func (m *mockIbft) CalculateGasLimit(number uint64) (uint64, error) {
 	return m.blockchain.CalculateGasLimit(number)
 }
 
+func (m *mockIbft) CalculateBaseFee(parent *types.Header) *big.Int {
+	return m.blockchain.CalculateBaseFee(parent)
+}
+
 func newMockIbft(t *testing.T, accounts []string, account string) *mockIbft {
 	pool := newTesterAccountPool()
 	pool.add(accounts...)
@@ -719,13 +903,16 @@ func newMockIbft(t *testing.T, accounts []string, account string) *mockIbft {
 		logger:           hclog.NewNullLogger(),
 		config:           &consensus.Config{},
 		blockchain:       m,
-		validatorKey:     addr.priv,
+		validatorKey:     addr.signer(),
 		validatorKeyAddr: addr.Address(),
 		closeCh:          make(chan struct{}),
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
 		updateCh:         make(chan struct{}),
 		operator:         &operator{},
 		state:            newState(),
 		epochSize:        DefaultEpochSize,
+		blockTime:        defaultBlockPeriod,
 		metrics:          consensus.NilMetrics(),
 	}
 
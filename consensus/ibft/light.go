@@ -0,0 +1,68 @@
+package ibft
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// Validators returns the validator set sealing the header, from its extra data
+func Validators(header *types.Header) (ValidatorSet, error) {
+	extra, err := getIbftExtra(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidatorSet(extra.Validators), nil
+}
+
+// VerifyLightHeader checks the header is the child of the trusted header, and is committed by a quorum
+// of its validator set, which may differ from the trusted one by a single validator, as the votes of the
+// headers add or remove one validator at most. The light clients follow the chain from a trusted header
+// with the headers alone, without executing the blocks. The hash of the header is set, as the headers
+// are exchanged without it
+func VerifyLightHeader(trusted, header *types.Header) error {
+	if header.Number != trusted.Number+1 {
+		return fmt.Errorf("expected header %d, found %d", trusted.Number+1, header.Number)
+	}
+	if header.ParentHash != trusted.Hash {
+		return fmt.Errorf("header %d is not a child of the trusted header", header.Number)
+	}
+
+	trustedSet, err := Validators(trusted)
+	if err != nil {
+		return err
+	}
+	set, err := Validators(header)
+	if err != nil {
+		return err
+	}
+
+	if diff := validatorSetDiff(trustedSet, set); diff > 1 {
+		return fmt.Errorf("validator set of header %d changed by %d validators", header.Number, diff)
+	}
+
+	if err := verifyCommitedFields(&Snapshot{Set: set}, header); err != nil {
+		return err
+	}
+	header.Hash = istanbulHeaderHash(header)
+
+	return nil
+}
+
+// validatorSetDiff returns the number of validators added to or removed from the set
+func validatorSetDiff(from, to ValidatorSet) int {
+	diff := 0
+	for _, addr := range from {
+		if !to.Includes(addr) {
+			diff++
+		}
+	}
+	for _, addr := range to {
+		if !from.Includes(addr) {
+			diff++
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,59 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyLightHeader(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D", "E")
+
+	trusted := &types.Header{Number: 10}
+	putIbftExtraValidators(trusted, pool.ValidatorSet())
+	trusted.Hash = istanbulHeaderHash(trusted)
+
+	buildHeader := func(validators ValidatorSet, signers ...string) *types.Header {
+		h := &types.Header{Number: 11, ParentHash: trusted.Hash}
+		putIbftExtraValidators(h, validators)
+
+		seals := [][]byte{}
+		for _, signer := range signers {
+			seal, err := writeCommittedSeal(pool.get(signer).signer(), h)
+			assert.NoError(t, err)
+			seals = append(seals, seal)
+		}
+		h, err := writeCommittedSeals(h, seals)
+		assert.NoError(t, err)
+
+		return h
+	}
+
+	// Correct
+	h := buildHeader(pool.ValidatorSet(), "A", "B", "C")
+	assert.NoError(t, VerifyLightHeader(trusted, h))
+	assert.Equal(t, istanbulHeaderHash(h), h.Hash)
+
+	// Correct - a validator removed by a vote
+	assert.NoError(t, VerifyLightHeader(trusted, buildHeader(pool.ValidatorSet()[:4], "A", "B", "C")))
+
+	// Failed - Not enough seals
+	assert.Error(t, VerifyLightHeader(trusted, buildHeader(pool.ValidatorSet(), "A", "B")))
+
+	// Failed - The validator set changed by more than a vote
+	pool.add("X", "Y")
+	changed := append(pool.ValidatorSet()[:3], pool.get("X").Address(), pool.get("Y").Address())
+	assert.Error(t, VerifyLightHeader(trusted, buildHeader(changed, "A", "B", "C", "X", "Y")))
+
+	// Failed - Not a child of the trusted header
+	h = buildHeader(pool.ValidatorSet()[:5], "A", "B", "C")
+	h.ParentHash = types.Hash{0x1}
+	assert.Error(t, VerifyLightHeader(trusted, h))
+
+	// Failed - Altered header
+	h = buildHeader(pool.ValidatorSet()[:5], "A", "B", "C")
+	h.StateRoot = types.Hash{0x1}
+	assert.Error(t, VerifyLightHeader(trusted, h))
+}
@@ -2,8 +2,10 @@ package ibft
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-sdk/types"
@@ -21,8 +23,17 @@ type operator struct {
 
 // Status returns the status of the IBFT client
 func (o *operator) Status(ctx context.Context, req *empty.Empty) (*proto.IbftStatusResp, error) {
+	round := o.ibft.getRound()
+
 	resp := &proto.IbftStatusResp{
-		Key: o.ibft.validatorKeyAddr.String(),
+		Key:       o.ibft.validatorKeyAddr.String(),
+		State:     o.ibft.getState().String(),
+		Sequence:  round.sequence,
+		Round:     round.round,
+		Validator: o.ibft.Status().Validator,
+	}
+	if round.proposer != types.ZeroAddress {
+		resp.Proposer = round.proposer.String()
 	}
 
 	return resp, nil
@@ -153,3 +164,96 @@ func (o *operator) Candidates(ctx context.Context, req *empty.Empty) (*proto.Can
 
 	return resp, nil
 }
+
+// exitPollInterval is the interval between the checks of the validator set during an exit
+var exitPollInterval = time.Second
+
+// Exit removes the node from the validator set: it proposes its own removal, reports the progress
+// of the vote until the other validators voted it out, and then stops sealing
+func (o *operator) Exit(req *empty.Empty, stream proto.IbftOperator_ExitServer) error {
+	addr := o.ibft.validatorKeyAddr
+
+	snap, err := o.ibft.getLatestSnapshot()
+	if err != nil {
+		return err
+	}
+	if !snap.Set.Includes(addr) {
+		return errors.New("the node is not a validator")
+	}
+	if snap.Set.Len() == 1 {
+		return errors.New("the node is the last validator")
+	}
+
+	o.proposeExit(snap)
+
+	ticker := time.NewTicker(exitPollInterval)
+	defer ticker.Stop()
+
+	lastNumber := ^uint64(0)
+	for {
+		if snap, err = o.ibft.getLatestSnapshot(); err != nil {
+			return err
+		}
+
+		if !snap.Set.Includes(addr) {
+			o.ibft.stopSealing()
+			o.ibft.logger.Info("the node exited the validator set, sealing stopped", "number", snap.Number)
+
+			return stream.Send(&proto.ExitEvent{
+				Number:     snap.Number,
+				Validators: uint64(snap.Set.Len()),
+				Exited:     true,
+			})
+		}
+
+		if snap.Number != lastNumber {
+			lastNumber = snap.Number
+
+			votes := snap.Count(func(v *Vote) bool {
+				return v.Address == addr && !v.Authorize
+			})
+
+			if err := stream.Send(&proto.ExitEvent{
+				Number:     snap.Number,
+				Votes:      uint64(votes),
+				Validators: uint64(snap.Set.Len()),
+			}); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-o.ibft.closeCh:
+			return errors.New("the consensus is closed")
+		}
+	}
+}
+
+// proposeExit replaces the pending proposal for the node, if any, with its removal
+func (o *operator) proposeExit(snap *Snapshot) {
+	addr := o.ibft.validatorKeyAddr
+
+	o.candidatesLock.Lock()
+	defer o.candidatesLock.Unlock()
+
+	candidates := []*proto.Candidate{}
+	for _, c := range o.candidates {
+		if types.StringToAddress(c.Address) != addr {
+			candidates = append(candidates, c)
+		}
+	}
+	o.candidates = candidates
+
+	voted := snap.Count(func(v *Vote) bool {
+		return v.Address == addr && v.Validator == addr && !v.Authorize
+	})
+	if voted == 0 {
+		o.candidates = append(o.candidates, &proto.Candidate{
+			Address: addr.String(),
+			Auth:    false,
+		})
+	}
+}
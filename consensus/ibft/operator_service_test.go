@@ -124,3 +124,50 @@ func TestOperator_Propose(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestOperator_ProposeExit(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	ibft := &Ibft{
+		validatorKeyAddr: pool.get("A").Address(),
+	}
+
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	o := &operator{
+		ibft: ibft,
+		candidates: []*proto.Candidate{
+			{
+				Address: pool.get("A").Address().String(),
+				Auth:    true,
+			},
+			{
+				Address: pool.get("B").Address().String(),
+				Auth:    false,
+			},
+		},
+	}
+
+	// the pending proposal for the node is replaced with its removal
+	o.proposeExit(snap)
+	assert.Len(t, o.candidates, 2)
+	assert.Equal(t, pool.get("B").Address().String(), o.candidates[0].Address)
+	assert.Equal(t, pool.get("A").Address().String(), o.candidates[1].Address)
+	assert.False(t, o.candidates[1].Auth)
+
+	// the removal is not proposed again once the node voted for it
+	snap.Votes = []*Vote{
+		{
+			Validator: pool.get("A").Address(),
+			Address:   pool.get("A").Address(),
+			Authorize: false,
+		},
+	}
+
+	o.proposeExit(snap)
+	assert.Len(t, o.candidates, 1)
+	assert.Equal(t, pool.get("B").Address().String(), o.candidates[0].Address)
+}
@@ -23,6 +23,7 @@ type IbftOperatorClient interface {
 	Propose(ctx context.Context, in *Candidate, opts ...grpc.CallOption) (*empty.Empty, error)
 	Candidates(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*CandidatesResp, error)
 	Status(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*IbftStatusResp, error)
+	Exit(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (IbftOperator_ExitClient, error)
 }
 
 type ibftOperatorClient struct {
@@ -69,6 +70,38 @@ func (c *ibftOperatorClient) Status(ctx context.Context, in *empty.Empty, opts .
 	return out, nil
 }
 
+func (c *ibftOperatorClient) Exit(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (IbftOperator_ExitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IbftOperator_ServiceDesc.Streams[0], "/v1.IbftOperator/Exit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ibftOperatorExitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IbftOperator_ExitClient interface {
+	Recv() (*ExitEvent, error)
+	grpc.ClientStream
+}
+
+type ibftOperatorExitClient struct {
+	grpc.ClientStream
+}
+
+func (x *ibftOperatorExitClient) Recv() (*ExitEvent, error) {
+	m := new(ExitEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // IbftOperatorServer is the server API for IbftOperator service.
 // All implementations must embed UnimplementedIbftOperatorServer
 // for forward compatibility
@@ -77,6 +110,7 @@ type IbftOperatorServer interface {
 	Propose(context.Context, *Candidate) (*empty.Empty, error)
 	Candidates(context.Context, *empty.Empty) (*CandidatesResp, error)
 	Status(context.Context, *empty.Empty) (*IbftStatusResp, error)
+	Exit(*empty.Empty, IbftOperator_ExitServer) error
 	mustEmbedUnimplementedIbftOperatorServer()
 }
 
@@ -96,6 +130,9 @@ func (UnimplementedIbftOperatorServer) Candidates(context.Context, *empty.Empty)
 func (UnimplementedIbftOperatorServer) Status(context.Context, *empty.Empty) (*IbftStatusResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedIbftOperatorServer) Exit(*empty.Empty, IbftOperator_ExitServer) error {
+	return status.Errorf(codes.Unimplemented, "method Exit not implemented")
+}
 func (UnimplementedIbftOperatorServer) mustEmbedUnimplementedIbftOperatorServer() {}
 
 // UnsafeIbftOperatorServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +218,27 @@ func _IbftOperator_Status_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IbftOperator_Exit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IbftOperatorServer).Exit(m, &ibftOperatorExitServer{stream})
+}
+
+type IbftOperator_ExitServer interface {
+	Send(*ExitEvent) error
+	grpc.ServerStream
+}
+
+type ibftOperatorExitServer struct {
+	grpc.ServerStream
+}
+
+func (x *ibftOperatorExitServer) Send(m *ExitEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // IbftOperator_ServiceDesc is the grpc.ServiceDesc for IbftOperator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -205,6 +263,12 @@ var IbftOperator_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _IbftOperator_Status_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Exit",
+			Handler:       _IbftOperator_Exit_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "consensus/ibft/proto/operator.proto",
 }
@@ -0,0 +1,163 @@
+package ibft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// registryTimeout bounds the time a report takes to reach the registry
+const registryTimeout = 10 * time.Second
+
+// registryReport is the document published to the registry at the start of each epoch
+type registryReport struct {
+	// the first block of the epoch
+	Number uint64     `json:"number"`
+	Hash   types.Hash `json:"hash"`
+	Epoch  uint64     `json:"epoch"`
+
+	// the validator set of the epoch
+	Validators []types.Address `json:"validators"`
+
+	// the reachability of the node sending the report
+	Node *registryNode `json:"node"`
+}
+
+// registryNode is the reachability of the node sending the report
+type registryNode struct {
+	Validator types.Address `json:"validator"`
+	Sealing   bool          `json:"sealing"`
+	PeerID    string        `json:"peerId,omitempty"`
+	Addrs     []string      `json:"addrs,omitempty"`
+	Peers     int           `json:"peers"`
+}
+
+// registryPublisher posts the validator set of each epoch, along with the reachability of this node,
+// to the registry endpoint of the network coordinators. Only the latest report is sent,
+// the reports of the past epochs replayed during a sync are skipped
+type registryPublisher struct {
+	logger   hclog.Logger
+	endpoint string
+	client   *http.Client
+
+	lock     sync.Mutex
+	pending  *registryReport
+	notifyCh chan struct{}
+}
+
+func newRegistryPublisher(logger hclog.Logger, endpoint string) (*registryPublisher, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry endpoint: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid registry endpoint scheme '%s'", u.Scheme)
+	}
+
+	return &registryPublisher{
+		logger:   logger.Named("registry"),
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: registryTimeout},
+		notifyCh: make(chan struct{}, 1),
+	}, nil
+}
+
+// publish queues the report, replacing the one not sent yet
+func (r *registryPublisher) publish(report *registryReport) {
+	r.lock.Lock()
+	r.pending = report
+	r.lock.Unlock()
+
+	select {
+	case r.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// run sends the queued reports until closeCh is closed
+func (r *registryPublisher) run(closeCh chan struct{}) {
+	for {
+		select {
+		case <-r.notifyCh:
+		case <-closeCh:
+			return
+		}
+
+		r.lock.Lock()
+		report := r.pending
+		r.pending = nil
+		r.lock.Unlock()
+
+		if report == nil {
+			continue
+		}
+
+		if err := r.send(report); err != nil {
+			r.logger.Error("failed to publish the validator set", "epoch", report.Epoch, "err", err)
+		} else {
+			r.logger.Debug("published the validator set", "epoch", report.Epoch)
+		}
+	}
+}
+
+// send posts the report to the registry endpoint
+func (r *registryPublisher) send(report *registryReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Post(r.endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// publishEpoch publishes the validator set of the epoch starting at the header, if a registry is set
+func (i *Ibft) publishEpoch(header *types.Header) {
+	if i.registry == nil || header.Number%i.epochSize != 0 {
+		return
+	}
+
+	snap, err := i.getSnapshot(header.Number)
+	if err != nil {
+		i.logger.Error("failed to get the snapshot of the epoch", "number", header.Number, "err", err)
+		return
+	}
+
+	report := &registryReport{
+		Number:     header.Number,
+		Hash:       header.Hash,
+		Epoch:      header.Number / i.epochSize,
+		Validators: append([]types.Address{}, snap.Set...),
+		Node: &registryNode{
+			Validator: i.validatorKeyAddr,
+			Sealing:   i.isSealing(),
+		},
+	}
+
+	if i.network != nil {
+		info := i.network.AddrInfo()
+		report.Node.PeerID = info.ID.String()
+		for _, addr := range info.Addrs {
+			report.Node.Addrs = append(report.Node.Addrs, addr.String())
+		}
+		report.Node.Peers = len(i.network.Peers())
+	}
+
+	i.registry.publish(report)
+}
@@ -0,0 +1,68 @@
+package ibft
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryPublisher_Endpoint(t *testing.T) {
+	for _, endpoint := range []string{"localhost:8080", "ftp://localhost/registry", "://"} {
+		_, err := newRegistryPublisher(hclog.NewNullLogger(), endpoint)
+		assert.Error(t, err, endpoint)
+	}
+
+	_, err := newRegistryPublisher(hclog.NewNullLogger(), "https://registry.example.com/validators")
+	assert.NoError(t, err)
+}
+
+func TestRegistryPublisher_Publish(t *testing.T) {
+	reportCh := make(chan *registryReport, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		report := &registryReport{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(report))
+		reportCh <- report
+	}))
+	defer srv.Close()
+
+	publisher, err := newRegistryPublisher(hclog.NewNullLogger(), srv.URL)
+	assert.NoError(t, err)
+
+	closeCh := make(chan struct{})
+	defer close(closeCh)
+
+	// the reports queued before the publisher runs are replaced by the latest one
+	publisher.publish(&registryReport{Epoch: 1})
+	publisher.publish(&registryReport{
+		Number:     200,
+		Epoch:      2,
+		Validators: []types.Address{{0x1}, {0x2}},
+		Node:       &registryNode{Validator: types.Address{0x1}, Sealing: true, Peers: 3},
+	})
+	go publisher.run(closeCh)
+
+	select {
+	case report := <-reportCh:
+		assert.Equal(t, uint64(2), report.Epoch)
+		assert.Equal(t, uint64(200), report.Number)
+		assert.Equal(t, []types.Address{{0x1}, {0x2}}, report.Validators)
+		assert.Equal(t, &registryNode{Validator: types.Address{0x1}, Sealing: true, Peers: 3}, report.Node)
+	case <-time.After(5 * time.Second):
+		t.Fatal("report not published")
+	}
+
+	select {
+	case report := <-reportCh:
+		t.Fatalf("unexpected report of epoch %d", report.Epoch)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -1,30 +1,54 @@
 package ibft
 
 import (
-	"crypto/ecdsa"
 	"fmt"
 
 	"github.com/0xPolygon/polygon-sdk/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-sdk/crypto"
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
-	"github.com/0xPolygon/polygon-sdk/helper/keccak"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/umbracle/fastrlp"
 )
 
+var (
+	// hasher hashes the headers and the consensus messages, keccak256 by default
+	hasher crypto.Hasher = crypto.Keccak256Hasher{}
+
+	// signatureScheme signs the seals and the consensus messages, secp256k1 by default
+	signatureScheme crypto.SignatureScheme = &crypto.ECDSAScheme{}
+)
+
+// setupScheme selects the hasher and the signature scheme set in the engine config
+func setupScheme(config map[string]interface{}) error {
+	hasherName, signatureSchemeName := crypto.Keccak256Hash, crypto.Secp256k1Scheme
+	if name, ok := config["hasher"].(string); ok && name != "" {
+		hasherName = name
+	}
+	if name, ok := config["signatureScheme"].(string); ok && name != "" {
+		signatureSchemeName = name
+	}
+
+	h, err := crypto.GetHasher(hasherName)
+	if err != nil {
+		return err
+	}
+	scheme, err := crypto.GetSignatureScheme(signatureSchemeName)
+	if err != nil {
+		return err
+	}
+
+	hasher, signatureScheme = h, scheme
+	return nil
+}
+
 func commitMsg(b []byte) []byte {
 	// message that the nodes need to sign to commit to a block
 	// hash with COMMIT_MSG_CODE which is the same value used in quorum
-	return crypto.Keccak256(b, []byte{byte(proto.MessageReq_Commit)})
+	return hasher.Hash(b, []byte{byte(proto.MessageReq_Commit)})
 }
 
 func ecrecoverImpl(sig, msg []byte) (types.Address, error) {
-	pub, err := crypto.RecoverPubkey(sig, crypto.Keccak256(msg))
-	if err != nil {
-		return types.Address{}, err
-	}
-
-	return crypto.PubKeyToAddress(pub), nil
+	return signatureScheme.Recover(sig, hasher.Hash(msg))
 }
 
 func ecrecoverFromHeader(h *types.Header) (types.Address, error) {
@@ -42,7 +66,7 @@ func ecrecoverFromHeader(h *types.Header) (types.Address, error) {
 	return ecrecoverImpl(extra.Seal, msg)
 }
 
-func signSealImpl(prv *ecdsa.PrivateKey, h *types.Header, committed bool) ([]byte, error) {
+func signSealImpl(signer crypto.Signer, h *types.Header, committed bool) ([]byte, error) {
 	hash, err := calculateHeaderHash(h)
 	if err != nil {
 		return nil, err
@@ -53,7 +77,7 @@ func signSealImpl(prv *ecdsa.PrivateKey, h *types.Header, committed bool) ([]byt
 	if committed {
 		msg = commitMsg(hash)
 	}
-	seal, err := crypto.Sign(prv, crypto.Keccak256(msg))
+	seal, err := signer.Sign(hasher.Hash(msg))
 	if err != nil {
 		return nil, err
 	}
@@ -61,9 +85,9 @@ func signSealImpl(prv *ecdsa.PrivateKey, h *types.Header, committed bool) ([]byt
 	return seal, nil
 }
 
-func writeSeal(prv *ecdsa.PrivateKey, h *types.Header) (*types.Header, error) {
+func writeSeal(signer crypto.Signer, h *types.Header) (*types.Header, error) {
 	h = h.Copy()
-	seal, err := signSealImpl(prv, h, false)
+	seal, err := signSealImpl(signer, h, false)
 	if err != nil {
 		return nil, err
 	}
@@ -81,8 +105,8 @@ func writeSeal(prv *ecdsa.PrivateKey, h *types.Header) (*types.Header, error) {
 	return h, nil
 }
 
-func writeCommittedSeal(prv *ecdsa.PrivateKey, h *types.Header) ([]byte, error) {
-	return signSealImpl(prv, h, true)
+func writeCommittedSeal(signer crypto.Signer, h *types.Header) ([]byte, error) {
+	return signSealImpl(signer, h, true)
 }
 
 func writeCommittedSeals(h *types.Header, seals [][]byte) (*types.Header, error) {
@@ -93,7 +117,7 @@ func writeCommittedSeals(h *types.Header, seals [][]byte) (*types.Header, error)
 	}
 
 	for _, seal := range seals {
-		if len(seal) != IstanbulExtraSeal {
+		if len(seal) != signatureScheme.SignatureSize() {
 			return nil, fmt.Errorf("invalid committed seal length")
 		}
 	}
@@ -146,7 +170,7 @@ func calculateHeaderHash(h *types.Header) ([]byte, error) {
 	vv.Set(arena.NewUint(h.Timestamp))
 	vv.Set(arena.NewCopyBytes(h.ExtraData))
 
-	buf := keccak.Keccak256Rlp(nil, vv)
+	buf := hasher.Hash(vv.MarshalTo(nil))
 
 	return buf, nil
 }
@@ -233,13 +257,13 @@ func validateMsg(msg *proto.MessageReq) error {
 	return nil
 }
 
-func signMsg(key *ecdsa.PrivateKey, msg *proto.MessageReq) error {
+func signMsg(signer crypto.Signer, msg *proto.MessageReq) error {
 	signMsg, err := msg.PayloadNoSig()
 	if err != nil {
 		return err
 	}
 
-	sig, err := crypto.Sign(key, crypto.Keccak256(signMsg))
+	sig, err := signer.Sign(hasher.Hash(signMsg))
 	if err != nil {
 		return err
 	}
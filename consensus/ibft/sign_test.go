@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-sdk/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-sdk/crypto"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,11 +23,11 @@ func TestSign_Sealer(t *testing.T) {
 	// non-validator address
 	pool.add("X")
 
-	badSealedBlock, _ := writeSeal(pool.get("X").priv, h)
+	badSealedBlock, _ := writeSeal(pool.get("X").signer(), h)
 	assert.Error(t, verifySigner(snap, badSealedBlock))
 
 	// seal the block with a validator
-	goodSealedBlock, _ := writeSeal(pool.get("A").priv, h)
+	goodSealedBlock, _ := writeSeal(pool.get("A").signer(), h)
 	assert.NoError(t, verifySigner(snap, goodSealedBlock))
 }
 
@@ -47,7 +48,7 @@ func TestSign_CommittedSeals(t *testing.T) {
 	buildCommittedSeal := func(accnt []string) error {
 		seals := [][]byte{}
 		for _, accnt := range accnt {
-			seal, err := writeCommittedSeal(pool.get(accnt).priv, h)
+			seal, err := writeCommittedSeal(pool.get(accnt).signer(), h)
 			assert.NoError(t, err)
 			seals = append(seals, seal)
 		}
@@ -76,8 +77,51 @@ func TestSign_Messages(t *testing.T) {
 	pool.add("A")
 
 	msg := &proto.MessageReq{}
-	assert.NoError(t, signMsg(pool.get("A").priv, msg))
+	assert.NoError(t, signMsg(pool.get("A").signer(), msg))
 	assert.NoError(t, validateMsg(msg))
 
 	assert.Equal(t, msg.From, pool.get("A").Address().String())
 }
+
+type testHasher struct{}
+
+// Hash returns the reversed keccak256 hash, so it differs from the default hasher
+func (testHasher) Hash(data ...[]byte) []byte {
+	hash := crypto.Keccak256(data...)
+	for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+		hash[i], hash[j] = hash[j], hash[i]
+	}
+	return hash
+}
+
+func TestSign_Scheme(t *testing.T) {
+	crypto.RegisterHasher("test", testHasher{})
+
+	assert.Error(t, setupScheme(map[string]interface{}{"hasher": "unknown"}))
+	assert.Error(t, setupScheme(map[string]interface{}{"signatureScheme": "unknown"}))
+
+	pool := newTesterAccountPool()
+	pool.add("A")
+
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	h := &types.Header{}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	sealed, err := writeSeal(pool.get("A").signer(), h)
+	assert.NoError(t, err)
+
+	// the seal is bound to the hasher of the chain
+	assert.NoError(t, setupScheme(map[string]interface{}{"hasher": "test"}))
+	defer func() {
+		assert.NoError(t, setupScheme(map[string]interface{}{}))
+	}()
+
+	assert.Error(t, verifySigner(snap, sealed))
+
+	sealed, err = writeSeal(pool.get("A").signer(), h)
+	assert.NoError(t, err)
+	assert.NoError(t, verifySigner(snap, sealed))
+}
@@ -37,8 +37,12 @@ func (t *testerAccount) Address() types.Address {
 	return crypto.PubKeyToAddress(&t.priv.PublicKey)
 }
 
+func (t *testerAccount) signer() crypto.Signer {
+	return crypto.NewECDSASigner(t.priv)
+}
+
 func (t *testerAccount) sign(h *types.Header) *types.Header {
-	h, _ = writeSeal(t.priv, h)
+	h, _ = writeSeal(t.signer(), h)
 	return h
 }
 
@@ -0,0 +1,69 @@
+package ibft
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-sdk/helper/tracing"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records the sequences, from the proposal of the block to its commit
+var tracer = tracing.Tracer("ibft")
+
+// startSequenceSpan starts the span of the sequence, ending the one of the previous
+// sequence if its block was not committed by this node
+func (i *Ibft) startSequenceSpan(number uint64) {
+	i.endSequenceSpan()
+
+	i.sequenceCtx, i.sequenceSpan = tracer.Start(
+		context.Background(),
+		"ibft.sequence",
+		trace.WithAttributes(attribute.Int64("sequence", int64(number))),
+	)
+}
+
+// endSequenceSpan ends the span of the current sequence, if any
+func (i *Ibft) endSequenceSpan() {
+	if i.sequenceSpan != nil {
+		i.sequenceSpan.End()
+		i.sequenceSpan = nil
+	}
+}
+
+// sequenceContext returns the context holding the span of the current sequence
+func (i *Ibft) sequenceContext() context.Context {
+	if i.sequenceCtx == nil {
+		return context.Background()
+	}
+
+	return i.sequenceCtx
+}
+
+// executeTransaction writes the transaction to the transition in a span of the trace of the transaction,
+// which starts with its admission to the pool, linked to the span of the block being built
+func (i *Ibft) executeTransaction(
+	ctx context.Context,
+	transition transitionInterface,
+	txn *types.Transaction,
+) error {
+	txCtx := trace.ContextWithSpanContext(context.Background(), i.txpool.SpanContext(txn.Hash))
+
+	_, span := tracer.Start(
+		txCtx,
+		"ibft.executeTransaction",
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(attribute.String("tx.hash", txn.Hash.String())),
+	)
+	defer span.End()
+
+	if err := transition.Write(txn); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	return nil
+}
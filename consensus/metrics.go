@@ -18,6 +18,11 @@ type Metrics struct {
 
 	//Time between current block and the previous block in seconds
 	BlockInterval metrics.Histogram
+
+	// Time spent sealing a block, over all its rounds, in seconds
+	SequenceDuration metrics.Histogram
+	// No.of round changes
+	RoundChanges metrics.Counter
 }
 
 // GetPrometheusMetrics return the consensus metrics instance
@@ -54,6 +59,19 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "block_interval",
 			Help:      "Time between current block and the previous block in seconds.",
 		}, labels).With(labelsWithValues...),
+
+		SequenceDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "sequence_duration",
+			Help:      "Time spent sealing a block, over all its rounds, in seconds.",
+		}, labels).With(labelsWithValues...),
+		RoundChanges: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "round_changes",
+			Help:      "Number of round changes.",
+		}, labels).With(labelsWithValues...),
 	}
 }
 
@@ -64,5 +82,8 @@ func NilMetrics() *Metrics {
 		Rounds:        discard.NewGauge(),
 		NumTxs:        discard.NewGauge(),
 		BlockInterval: discard.NewHistogram(),
+
+		SequenceDuration: discard.NewHistogram(),
+		RoundChanges:     discard.NewCounter(),
 	}
 }
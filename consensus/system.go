@@ -0,0 +1,116 @@
+package consensus
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// SystemTxProvider provides the system transactions the validators insert at the top of the blocks,
+// like the state syncs or the updates of the validator set. They are derived from the chain,
+// so all the validators expect the same ones
+type SystemTxProvider interface {
+	// SystemTransactions returns the system transactions of the block of the header, without their nonce
+	SystemTransactions(header *types.Header) ([]*types.Transaction, error)
+}
+
+// SystemCalls provides the system calls of the chain params
+type SystemCalls []*chain.SystemCall
+
+// SystemTransactions returns the transactions of the calls made at the block
+func (s SystemCalls) SystemTransactions(header *types.Header) ([]*types.Transaction, error) {
+	txs := []*types.Transaction{}
+	for _, call := range s {
+		if !call.Active(header.Number) {
+			continue
+		}
+
+		to := call.To
+		txs = append(txs, &types.Transaction{
+			Type:     types.StateTx,
+			From:     types.SystemAddress,
+			To:       &to,
+			Input:    append([]byte{}, call.Input...),
+			Gas:      call.Gas,
+			GasPrice: big.NewInt(0),
+			Value:    big.NewInt(0),
+		})
+	}
+
+	return txs, nil
+}
+
+// WriteSystemTransactions writes the system transactions of the block to the transition,
+// before the transactions of the pool. The block can't be built without them
+func WriteSystemTransactions(
+	provider SystemTxProvider,
+	header *types.Header,
+	transition *state.Transition,
+) ([]*types.Transaction, error) {
+	if provider == nil {
+		return nil, nil
+	}
+
+	txs, err := provider.SystemTransactions(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the system transactions: %v", err)
+	}
+
+	for _, tx := range txs {
+		tx.Nonce = transition.GetNonce(types.SystemAddress)
+		tx.ComputeHash()
+
+		if err := transition.Write(tx); err != nil {
+			return nil, fmt.Errorf("failed to write the system transaction %s: %v", tx.Hash, err)
+		}
+	}
+
+	return txs, nil
+}
+
+// VerifySystemTransactions checks the block starts with exactly the system transactions of the provider,
+// and has no other. Their nonces are checked once the block is executed
+func VerifySystemTransactions(provider SystemTxProvider, block *types.Block) error {
+	expected := []*types.Transaction{}
+	if provider != nil {
+		var err error
+		if expected, err = provider.SystemTransactions(block.Header); err != nil {
+			return fmt.Errorf("failed to get the system transactions: %v", err)
+		}
+	}
+
+	found := 0
+	for i, tx := range block.Transactions {
+		if !tx.IsSystemTx() {
+			continue
+		}
+		if i != found {
+			return fmt.Errorf("system transaction %d after the other transactions", i)
+		}
+		found++
+	}
+	if found != len(expected) {
+		return fmt.Errorf("expected %d system transactions, found %d", len(expected), found)
+	}
+
+	for i, tx := range expected {
+		if !sameSystemTx(tx, block.Transactions[i]) {
+			return fmt.Errorf("unexpected system transaction %d: %s", i, block.Transactions[i].Hash)
+		}
+	}
+
+	return nil
+}
+
+// sameSystemTx checks if the system transactions make the same call
+func sameSystemTx(a, b *types.Transaction) bool {
+	if (a.To == nil) != (b.To == nil) || (a.To != nil && *a.To != *b.To) {
+		return false
+	}
+
+	return a.Gas == b.Gas && bytes.Equal(a.Input, b.Input)
+}
@@ -0,0 +1,52 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySystemTransactions(t *testing.T) {
+	calls := SystemCalls{
+		{Block: 10, Interval: 10, To: types.StringToAddress("100"), Input: []byte{0x1}, Gas: 100000},
+		{Block: 20, To: types.StringToAddress("200"), Input: []byte{0x2}, Gas: 200000},
+	}
+
+	systemTxs, err := calls.SystemTransactions(&types.Header{Number: 20})
+	assert.NoError(t, err)
+	assert.Len(t, systemTxs, 2)
+	for i, tx := range systemTxs {
+		tx.Nonce = uint64(i)
+		tx.ComputeHash()
+	}
+
+	tx := &types.Transaction{Nonce: 1, GasPrice: big.NewInt(1), Value: big.NewInt(0)}
+	tx.ComputeHash()
+
+	block := func(number uint64, txs ...*types.Transaction) *types.Block {
+		return &types.Block{Header: &types.Header{Number: number}, Transactions: txs}
+	}
+
+	// the block starts with the system transactions of the calls
+	assert.NoError(t, VerifySystemTransactions(calls, block(20, systemTxs[0], systemTxs[1], tx)))
+	assert.NoError(t, VerifySystemTransactions(calls, block(21, tx)))
+
+	// the proposer can't skip, change, reorder or add any
+	assert.Error(t, VerifySystemTransactions(calls, block(20, systemTxs[0], tx)))
+	assert.Error(t, VerifySystemTransactions(calls, block(20, systemTxs[1], systemTxs[0])))
+	assert.Error(t, VerifySystemTransactions(calls, block(20, systemTxs[0], tx, systemTxs[1])))
+	assert.Error(t, VerifySystemTransactions(calls, block(30, systemTxs[0], systemTxs[1])))
+	assert.Error(t, VerifySystemTransactions(nil, block(20, systemTxs[0], systemTxs[1])))
+
+	changed := systemTxs[1].Copy()
+	changed.Input = []byte{0x3}
+	assert.Error(t, VerifySystemTransactions(calls, block(20, systemTxs[0], changed)))
+
+	// the calls before their block are not made
+	none, err := SystemCalls([]*chain.SystemCall{calls[1]}).SystemTransactions(&types.Header{Number: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, none)
+}
@@ -30,6 +30,8 @@ func BuildBlock(params BuildBlockParams) *types.Block {
 		header.ReceiptsRoot = buildroot.CalculateReceiptsRoot(receipts)
 	}
 
+	header.LogsBloom = types.CreateBloom(receipts)
+
 	// TODO: Compute uncles
 	header.Sha3Uncles = types.EmptyUncleHash
 	header.ComputeHash()
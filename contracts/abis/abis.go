@@ -5,3 +5,15 @@ import (
 )
 
 var StressTestABI = abi.MustNewABI(StressTestJSONABI)
+
+var PeerAllowlistABI = abi.MustNewABI(PeerAllowlistJSONABI)
+
+var GasTargetABI = abi.MustNewABI(GasTargetJSONABI)
+
+var CheckpointManagerABI = abi.MustNewABI(CheckpointManagerJSONABI)
+
+var StateSenderABI = abi.MustNewABI(StateSenderJSONABI)
+
+var StateReceiverABI = abi.MustNewABI(StateReceiverJSONABI)
+
+var ExitSenderABI = abi.MustNewABI(ExitSenderJSONABI)
@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/helper/keystore"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// Hasher is the hash function of the headers and the consensus messages
+type Hasher interface {
+	// Hash returns the hash of the concatenation of the data
+	Hash(data ...[]byte) []byte
+}
+
+// Keccak256Hasher is the default hasher, the keccak256 hash function
+type Keccak256Hasher struct{}
+
+// Hash implements the Hasher interface
+func (Keccak256Hasher) Hash(data ...[]byte) []byte {
+	return Keccak256(data...)
+}
+
+// Keccak256Hash is the name of the default hasher
+const Keccak256Hash = "keccak256"
+
+// Signer signs the hashes with a private key of a signature scheme
+type Signer interface {
+	// Address returns the address of the private key
+	Address() types.Address
+
+	// Sign signs the hash
+	Sign(hash []byte) ([]byte, error)
+}
+
+// SignatureScheme signs the headers and the consensus messages, and recovers their signer.
+// The schemes that can't recover the public key from a signature, like most of the post-quantum ones,
+// can carry the public key in the signature
+type SignatureScheme interface {
+	// GenerateKey returns a new private key, encoded as it is stored in the secrets manager
+	GenerateKey() ([]byte, error)
+
+	// NewSigner returns the signer of the encoded private key
+	NewSigner(key []byte) (Signer, error)
+
+	// Recover returns the address of the signer of the hash
+	Recover(sig, hash []byte) (types.Address, error)
+
+	// SignatureSize returns the size of the signatures
+	SignatureSize() int
+}
+
+// Secp256k1Scheme is the name of the default signature scheme, ECDSA on the secp256k1 curve
+const Secp256k1Scheme = "secp256k1"
+
+var (
+	registryLock sync.RWMutex
+	schemes      = map[string]SignatureScheme{
+		Secp256k1Scheme: &ECDSAScheme{},
+	}
+	hashers = map[string]Hasher{
+		Keccak256Hash: Keccak256Hasher{},
+	}
+)
+
+// RegisterHasher registers a hasher under the name, so the chains can select it.
+// It is meant to be called by the downstream forks on init
+func RegisterHasher(name string, hasher Hasher) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	hashers[name] = hasher
+}
+
+// GetHasher returns the hasher registered under the name
+func GetHasher(name string) (Hasher, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	hasher, ok := hashers[name]
+	if !ok {
+		return nil, fmt.Errorf("hasher '%s' not found", name)
+	}
+	return hasher, nil
+}
+
+// RegisterSignatureScheme registers a signature scheme under the name, so the chains can select it.
+// It is meant to be called by the downstream forks on init
+func RegisterSignatureScheme(name string, scheme SignatureScheme) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	schemes[name] = scheme
+}
+
+// GetSignatureScheme returns the signature scheme registered under the name
+func GetSignatureScheme(name string) (SignatureScheme, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	scheme, ok := schemes[name]
+	if !ok {
+		return nil, fmt.Errorf("signature scheme '%s' not found", name)
+	}
+	return scheme, nil
+}
+
+// ECDSAScheme is the signature scheme of ECDSA on the secp256k1 curve, with public key recovery
+type ECDSAScheme struct{}
+
+// GenerateKey implements the SignatureScheme interface
+func (e *ECDSAScheme) GenerateKey() ([]byte, error) {
+	return keystore.CreatePrivateKey(generateKeyAndMarshal)
+}
+
+// NewSigner implements the SignatureScheme interface
+func (e *ECDSAScheme) NewSigner(key []byte) (Signer, error) {
+	priv, err := BytesToPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewECDSASigner(priv), nil
+}
+
+// Recover implements the SignatureScheme interface
+func (e *ECDSAScheme) Recover(sig, hash []byte) (types.Address, error) {
+	if len(sig) != e.SignatureSize() {
+		return types.Address{}, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+
+	pub, err := RecoverPubkey(sig, hash)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return PubKeyToAddress(pub), nil
+}
+
+// SignatureSize implements the SignatureScheme interface
+func (e *ECDSAScheme) SignatureSize() int {
+	return 65
+}
+
+// ECDSASigner signs the hashes with a secp256k1 private key
+type ECDSASigner struct {
+	key  *ecdsa.PrivateKey
+	addr types.Address
+}
+
+// NewECDSASigner returns the signer of the private key
+func NewECDSASigner(key *ecdsa.PrivateKey) *ECDSASigner {
+	return &ECDSASigner{
+		key:  key,
+		addr: PubKeyToAddress(&key.PublicKey),
+	}
+}
+
+// Address implements the Signer interface
+func (e *ECDSASigner) Address() types.Address {
+	return e.addr
+}
+
+// Sign implements the Signer interface
+func (e *ECDSASigner) Sign(hash []byte) ([]byte, error) {
+	return Sign(e.key, hash)
+}
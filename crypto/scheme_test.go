@@ -0,0 +1,62 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECDSAScheme(t *testing.T) {
+	scheme, err := GetSignatureScheme(Secp256k1Scheme)
+	assert.NoError(t, err)
+
+	key, err := scheme.GenerateKey()
+	assert.NoError(t, err)
+
+	signer, err := scheme.NewSigner(key)
+	assert.NoError(t, err)
+
+	hash := Keccak256Hasher{}.Hash([]byte("a"), []byte("b"))
+	assert.Equal(t, Keccak256([]byte("ab")), hash)
+
+	sig, err := signer.Sign(hash)
+	assert.NoError(t, err)
+	assert.Len(t, sig, scheme.SignatureSize())
+
+	// the signer is recovered from the signature
+	addr, err := scheme.Recover(sig, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address(), addr)
+
+	// the truncated signatures are rejected
+	_, err = scheme.Recover(sig[:10], hash)
+	assert.Error(t, err)
+
+	_, err = scheme.NewSigner([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Hash(data ...[]byte) []byte {
+	h := sha256.New()
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+func TestRegistry(t *testing.T) {
+	_, err := GetHasher("sha256")
+	assert.Error(t, err)
+
+	RegisterHasher("sha256", sha256Hasher{})
+
+	hasher, err := GetHasher("sha256")
+	assert.NoError(t, err)
+	assert.Equal(t, sha256Hasher{}, hasher)
+
+	_, err = GetSignatureScheme("unknown")
+	assert.Error(t, err)
+}
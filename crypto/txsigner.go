@@ -27,11 +27,13 @@ type TxSigner interface {
 	CalculateV(parity byte) []byte
 }
 
-// NewSigner creates a new signer object (EIP155 or FrontierSigner)
+// NewSigner creates a new signer object (London, EIP155 or FrontierSigner)
 func NewSigner(forks chain.ForksInTime, chainID uint64) TxSigner {
 	var signer TxSigner
 
-	if forks.EIP155 {
+	if forks.London || forks.Berlin {
+		signer = NewLondonSigner(chainID)
+	} else if forks.EIP155 {
 		signer = &EIP155Signer{chainID: chainID}
 	} else {
 		signer = &FrontierSigner{}
@@ -0,0 +1,115 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/helper/keccak"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// NewLondonSigner returns a new LondonSigner object
+func NewLondonSigner(chainID uint64) *LondonSigner {
+	return &LondonSigner{EIP155Signer: EIP155Signer{chainID: chainID}}
+}
+
+// LondonSigner signs the access list transactions of EIP-2930, the dynamic fee transactions of EIP-1559,
+// and the legacy ones like the EIP155Signer
+type LondonSigner struct {
+	EIP155Signer
+}
+
+// calcTypedTxHash calculates the hash signed by the sender of a typed transaction,
+// the keccak256 hash of its type followed by the RLP value of its payload without the signature
+func calcTypedTxHash(tx *types.Transaction, chainID uint64) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewUint(chainID))
+	v.Set(a.NewUint(tx.Nonce))
+	if tx.Type == types.DynamicFeeTx {
+		v.Set(a.NewBigInt(tx.GetGasTipCap()))
+	}
+	v.Set(a.NewBigInt(tx.GasPrice))
+	v.Set(a.NewUint(tx.Gas))
+	if tx.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*tx.To).Bytes()))
+	}
+	v.Set(a.NewBigInt(tx.Value))
+	v.Set(a.NewCopyBytes(tx.Input))
+
+	v.Set(tx.AccessList.MarshalRLPWith(a))
+
+	hash := keccak.Keccak256(nil, append([]byte{byte(tx.Type)}, v.MarshalTo(nil)...))
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
+// Hash returns the hash signed by the sender of the transaction
+func (l *LondonSigner) Hash(tx *types.Transaction) types.Hash {
+	if tx.Type == types.LegacyTx {
+		return l.EIP155Signer.Hash(tx)
+	}
+	return calcTypedTxHash(tx, l.chainID)
+}
+
+// Sender returns the transaction sender
+func (l *LondonSigner) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type == types.LegacyTx {
+		return l.EIP155Signer.Sender(tx)
+	}
+
+	if tx.ChainID == nil || !tx.ChainID.IsUint64() || tx.ChainID.Uint64() != l.chainID {
+		return types.Address{}, fmt.Errorf("invalid chain id")
+	}
+
+	// the V of a typed transaction is the parity of the signature
+	parity := new(big.Int).SetBytes(tx.V)
+	if !parity.IsUint64() || parity.Uint64() > 1 {
+		return types.Address{}, fmt.Errorf("invalid txn signature")
+	}
+
+	sig, err := encodeSignature(tx.R, tx.S, byte(parity.Uint64()))
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	pub, err := Ecrecover(l.Hash(tx).Bytes(), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	buf := Keccak256(pub[1:])[12:]
+
+	return types.BytesToAddress(buf), nil
+}
+
+// SignTx signs the transaction using the passed in private key
+func (l *LondonSigner) SignTx(
+	tx *types.Transaction,
+	privateKey *ecdsa.PrivateKey,
+) (*types.Transaction, error) {
+	if tx.Type == types.LegacyTx {
+		return l.EIP155Signer.SignTx(tx, privateKey)
+	}
+
+	tx = tx.Copy()
+	tx.ChainID = new(big.Int).SetUint64(l.chainID)
+
+	h := l.Hash(tx)
+
+	sig, err := Sign(privateKey, h[:])
+	if err != nil {
+		return nil, err
+	}
+
+	tx.R = sig[:32]
+	tx.S = sig[32:64]
+	tx.V = new(big.Int).SetUint64(uint64(sig[64])).Bytes()
+
+	return tx, nil
+}
@@ -138,3 +138,86 @@ func TestEIP155Signer_ChainIDMismatch(t *testing.T) {
 		}
 	}
 }
+
+func TestLondonSigner_TypedTx(t *testing.T) {
+	toAddress := types.StringToAddress("1")
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	signer := NewLondonSigner(100)
+
+	for _, txn := range []*types.Transaction{
+		{
+			Type:      types.DynamicFeeTx,
+			Nonce:     1,
+			To:        &toAddress,
+			Value:     big.NewInt(10),
+			GasPrice:  big.NewInt(200),
+			GasTipCap: big.NewInt(2),
+			Gas:       21000,
+			Input:     []byte{0x1},
+		},
+		{
+			Type:      types.DynamicFeeTx,
+			Value:     big.NewInt(0),
+			GasPrice:  big.NewInt(0),
+			GasTipCap: big.NewInt(0),
+			Input:     []byte{0x1},
+		},
+		{
+			Type:      types.DynamicFeeTx,
+			Nonce:     2,
+			To:        &toAddress,
+			Value:     big.NewInt(10),
+			GasPrice:  big.NewInt(200),
+			GasTipCap: big.NewInt(2),
+			Gas:       30000,
+			AccessList: types.AccessList{
+				{Address: toAddress, StorageKeys: []types.Hash{types.StringToHash("1"), types.StringToHash("2")}},
+			},
+		},
+		{
+			Type:     types.AccessListTx,
+			Nonce:    3,
+			To:       &toAddress,
+			Value:    big.NewInt(10),
+			GasPrice: big.NewInt(5),
+			Gas:      30000,
+			AccessList: types.AccessList{
+				{Address: toAddress, StorageKeys: []types.Hash{types.StringToHash("1")}},
+				{Address: types.StringToAddress("2")},
+			},
+		},
+		{
+			Type:     types.AccessListTx,
+			Value:    big.NewInt(0),
+			GasPrice: big.NewInt(0),
+		},
+		{
+			To:       &toAddress,
+			Value:    big.NewInt(10),
+			GasPrice: big.NewInt(5),
+		},
+	} {
+		signedTx, err := signer.SignTx(txn, key)
+		assert.NoError(t, err)
+
+		// the decoded transaction has the same sender and the same hash
+		decoded := &types.Transaction{}
+		assert.NoError(t, decoded.UnmarshalRLP(signedTx.MarshalRLP()))
+		assert.Equal(t, txn.Type, decoded.Type)
+		assert.Equal(t, txn.AccessList, decoded.AccessList)
+		assert.Equal(t, signedTx.ComputeHash().Hash, decoded.Hash)
+
+		from, err := signer.Sender(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+		// the signature is bound to the chain
+		_, err = NewLondonSigner(1).Sender(decoded)
+		if err == nil {
+			from, _ = NewLondonSigner(1).Sender(decoded)
+			assert.NotEqual(t, PubKeyToAddress(&key.PublicKey), from)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// eip712Domain is the type of the domain of the typed data
+const eip712Domain = "EIP712Domain"
+
+var (
+	// typedIntRegexp matches the integer types, with their size
+	typedIntRegexp = regexp.MustCompile(`^(u?)int(\d*)$`)
+
+	// typedBytesRegexp matches the fixed size byte array types, with their size
+	typedBytesRegexp = regexp.MustCompile(`^bytes(\d+)$`)
+)
+
+// TypedDataField is a field of a struct type of the typed data
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is the structured data signed as in EIP-712, in the format of eth_signTypedData_v4
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// UnmarshalJSON decodes the typed data, keeping the numbers exact
+func (t *TypedData) UnmarshalJSON(data []byte) error {
+	type typedData TypedData
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode((*typedData)(t))
+}
+
+// HashTypedData returns the hash of the typed data signed as in EIP-712,
+// keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func HashTypedData(t *TypedData) ([]byte, error) {
+	if _, ok := t.Types[eip712Domain]; !ok {
+		return nil, fmt.Errorf("the type %s is missing", eip712Domain)
+	}
+
+	domain, err := t.hashStruct(eip712Domain, t.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash the domain: %v", err)
+	}
+	message, err := t.hashStruct(t.PrimaryType, t.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash the message: %v", err)
+	}
+
+	return Keccak256([]byte{0x19, 0x01}, domain, message), nil
+}
+
+// hashStruct returns keccak256(typeHash ‖ encodeData(data)) of the struct
+func (t *TypedData) hashStruct(typ string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := t.Types[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
+
+	encoded := [][]byte{Keccak256([]byte(t.encodeType(typ)))}
+	for _, field := range fields {
+		val, err := t.encodeValue(field.Type, data[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		encoded = append(encoded, val)
+	}
+
+	return Keccak256(encoded...), nil
+}
+
+// encodeType returns the encoding of the struct type, followed by the types
+// it references sorted by name
+func (t *TypedData) encodeType(typ string) string {
+	deps := map[string]bool{}
+	t.dependencies(typ, deps)
+	delete(deps, typ)
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range append([]string{typ}, names...) {
+		fields := make([]string, 0, len(t.Types[name]))
+		for _, field := range t.Types[name] {
+			fields = append(fields, field.Type+" "+field.Name)
+		}
+		fmt.Fprintf(&b, "%s(%s)", name, strings.Join(fields, ","))
+	}
+
+	return b.String()
+}
+
+// dependencies collects the struct types referenced by the type, and the type itself
+func (t *TypedData) dependencies(typ string, found map[string]bool) {
+	typ = baseType(typ)
+	if _, ok := t.Types[typ]; !ok || found[typ] {
+		return
+	}
+	found[typ] = true
+
+	for _, field := range t.Types[typ] {
+		t.dependencies(field.Type, found)
+	}
+}
+
+// encodeValue returns the 32 bytes encoding of the value of the type
+func (t *TypedData) encodeValue(typ string, val interface{}) ([]byte, error) {
+	// the arrays are the hash of the concatenated encodings of their items
+	if strings.HasSuffix(typ, "]") {
+		items, ok := val.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array for %s", typ)
+		}
+
+		elemType := typ[:strings.LastIndex(typ, "[")]
+		encoded := make([][]byte, 0, len(items))
+		for _, item := range items {
+			enc, err := t.encodeValue(elemType, item)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, enc)
+		}
+
+		return Keccak256(encoded...), nil
+	}
+
+	if _, ok := t.Types[typ]; ok {
+		data, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object for %s", typ)
+		}
+
+		return t.hashStruct(typ, data)
+	}
+
+	switch typ {
+	case "string":
+		str, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string")
+		}
+
+		return Keccak256([]byte(str)), nil
+
+	case "bytes":
+		buf, err := typedBytes(val)
+		if err != nil {
+			return nil, err
+		}
+
+		return Keccak256(buf), nil
+
+	case "bool":
+		b, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a boolean")
+		}
+		res := make([]byte, 32)
+		if b {
+			res[31] = 1
+		}
+
+		return res, nil
+
+	case "address":
+		buf, err := typedBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		if len(buf) != types.AddressLength {
+			return nil, fmt.Errorf("invalid address length %d", len(buf))
+		}
+
+		return leftPad(buf), nil
+	}
+
+	if match := typedBytesRegexp.FindStringSubmatch(typ); match != nil {
+		size, _ := strconv.Atoi(match[1])
+		buf, err := typedBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 || size > 32 || len(buf) > size {
+			return nil, fmt.Errorf("invalid value for %s", typ)
+		}
+		res := make([]byte, 32)
+		copy(res, buf)
+
+		return res, nil
+	}
+
+	if match := typedIntRegexp.FindStringSubmatch(typ); match != nil {
+		size := 256
+		if match[2] != "" {
+			size, _ = strconv.Atoi(match[2])
+		}
+		if size == 0 || size > 256 || size%8 != 0 {
+			return nil, fmt.Errorf("invalid type %s", typ)
+		}
+
+		return encodeTypedInt(val, match[1] == "u", size)
+	}
+
+	return nil, fmt.Errorf("unknown type %s", typ)
+}
+
+// baseType returns the type of the items of the array types
+func baseType(typ string) string {
+	if i := strings.Index(typ, "["); i != -1 {
+		return typ[:i]
+	}
+
+	return typ
+}
+
+// typedBytes decodes the hex string of a bytes value
+func typedBytes(val interface{}) ([]byte, error) {
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a hex string")
+	}
+
+	return hex.DecodeHex(str)
+}
+
+// encodeTypedInt encodes the integer in two's complement. The numbers are
+// JSON numbers, decimal strings or hex strings
+func encodeTypedInt(val interface{}, unsigned bool, size int) ([]byte, error) {
+	var str string
+	switch v := val.(type) {
+	case json.Number:
+		str = v.String()
+	case string:
+		str = v
+	case float64:
+		str = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("expected a number")
+	}
+
+	num, ok := new(big.Int).SetString(str, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", str)
+	}
+
+	if unsigned {
+		if num.Sign() < 0 || num.BitLen() > size {
+			return nil, fmt.Errorf("the number %s overflows uint%d", str, size)
+		}
+
+		return leftPad(num.Bytes()), nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(size-1))
+	if num.Cmp(limit) >= 0 || num.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("the number %s overflows int%d", str, size)
+	}
+	if num.Sign() < 0 {
+		num.Add(num, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+
+	return leftPad(num.Bytes()), nil
+}
+
+func leftPad(buf []byte) []byte {
+	res := make([]byte, 32)
+	copy(res[32-len(buf):], buf)
+
+	return res
+}
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mailTypedData is the example of EIP-712
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestHashTypedData(t *testing.T) {
+	var typed TypedData
+	assert.NoError(t, json.Unmarshal([]byte(mailTypedData), &typed))
+
+	assert.Equal(t,
+		"Mail(Person from,Person to,string contents)Person(string name,address wallet)",
+		typed.encodeType("Mail"),
+	)
+
+	domain, err := typed.hashStruct(eip712Domain, typed.Domain)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", hex.EncodeToHex(domain))
+
+	hash, err := HashTypedData(&typed)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToHex(hash))
+
+	// the signature of the example
+	key, err := ToECDSA(Keccak256([]byte("cow")))
+	assert.NoError(t, err)
+	assert.Equal(t, types.StringToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), PubKeyToAddress(&key.PublicKey))
+
+	sig, err := Sign(key, hash)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"+
+			"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"+"01",
+		hex.EncodeToHex(sig),
+	)
+}
+
+func TestHashTypedDataValues(t *testing.T) {
+	typed := &TypedData{
+		Types: map[string][]TypedDataField{
+			"EIP712Domain": {{Name: "name", Type: "string"}},
+			"Values": {
+				{Name: "flags", Type: "bool[]"},
+				{Name: "small", Type: "int8"},
+				{Name: "tag", Type: "bytes4"},
+				{Name: "data", Type: "bytes"},
+			},
+		},
+		PrimaryType: "Values",
+		Domain:      map[string]interface{}{"name": "test"},
+		Message: map[string]interface{}{
+			"flags": []interface{}{true, false},
+			"small": "-128",
+			"tag":   "0x01020304",
+			"data":  "0x",
+		},
+	}
+
+	_, err := HashTypedData(typed)
+	assert.NoError(t, err)
+
+	// the values are checked against their types
+	invalid := map[string]interface{}{
+		"small": "128",
+		"tag":   "0x0102030405",
+		"flags": true,
+		"data":  "zz",
+	}
+	for field, val := range invalid {
+		prev := typed.Message[field]
+		typed.Message[field] = val
+
+		_, err := HashTypedData(typed)
+		assert.Error(t, err, field)
+
+		typed.Message[field] = prev
+	}
+
+	typed.PrimaryType = "Unknown"
+	_, err = HashTypedData(typed)
+	assert.Error(t, err)
+}
@@ -0,0 +1,521 @@
+package ethstats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/version"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// fullReportInterval is the interval of the report of all the stats
+	fullReportInterval = 15 * time.Second
+
+	// reconnectInterval is the delay before a new connection to the server, after a failure
+	reconnectInterval = 10 * time.Second
+
+	// pongTimeout is the time the server has to answer the latency pings
+	pongTimeout = 5 * time.Second
+
+	// historyLength is the number of blocks of the history reported when the server asks for the latest ones
+	historyLength = 50
+)
+
+var (
+	errInvalidURL   = errors.New("the ethstats URL is not in the nodename:secret@host:port format")
+	errLoginDenied  = errors.New("the ethstats server denied the login")
+	errPongTimeout  = errors.New("no answer to the latency ping")
+	errShuttingDown = errors.New("shutting down")
+)
+
+// Config is the netstats server the stats are reported to
+type Config struct {
+	// Name is the name of the node on the dashboard
+	Name string
+
+	// Secret is the secret of the server
+	Secret string
+
+	// Host is the address of the server. The wss and ws schemes are tried if it has none
+	Host string
+}
+
+// ParseConfig parses a nodename:secret@host:port URL
+func ParseConfig(raw string) (*Config, error) {
+	i := strings.LastIndex(raw, "@")
+	if i <= 0 || i == len(raw)-1 {
+		return nil, errInvalidURL
+	}
+
+	config := &Config{
+		Name: raw[:i],
+		Host: raw[i+1:],
+	}
+	if j := strings.Index(config.Name, ":"); j != -1 {
+		config.Name, config.Secret = config.Name[:j], config.Name[j+1:]
+	}
+	if config.Name == "" {
+		return nil, errInvalidURL
+	}
+
+	return config, nil
+}
+
+// urls returns the websocket URLs of the server, in the order they are tried
+func (c *Config) urls() []string {
+	if strings.Contains(c.Host, "://") {
+		return []string{c.Host}
+	}
+
+	return []string{"wss://" + c.Host + "/api", "ws://" + c.Host + "/api"}
+}
+
+// Backend provides the stats of the node
+type Backend interface {
+	Header() *types.Header
+	GetHeaderByNumber(number uint64) (*types.Header, bool)
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+	GetTD(hash types.Hash) (*big.Int, bool)
+
+	// BlockCreator returns the sealer of the block
+	BlockCreator(header *types.Header) types.Address
+
+	// PendingTxs returns the number of transactions of the pool
+	PendingTxs() uint64
+
+	// PeerCount returns the number of connected peers
+	PeerCount() int
+
+	// Syncing returns true if the node is behind its peers
+	Syncing() bool
+
+	// Sealing returns true if the node takes part in the consensus
+	Sealing() bool
+}
+
+// NodeInfo is the description of the node shown on the dashboard
+type NodeInfo struct {
+	// Network is the chain ID
+	Network string
+
+	// Port is the libp2p port
+	Port int
+}
+
+// Service reports the stats of the node to a netstats server, reconnecting when the connection is lost
+type Service struct {
+	logger  hclog.Logger
+	config  *Config
+	backend Backend
+	bus     *eventbus.Bus
+	info    *NodeInfo
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewService returns the reporting service, it starts with Start
+func NewService(logger hclog.Logger, config *Config, backend Backend, bus *eventbus.Bus, info *NodeInfo) *Service {
+	return &Service{
+		logger:  logger.Named("ethstats"),
+		config:  config,
+		backend: backend,
+		bus:     bus,
+		info:    info,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start connects to the server and starts the reports
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Close stops the reports, and closes the connection to the server
+func (s *Service) Close() {
+	close(s.closeCh)
+	<-s.doneCh
+}
+
+func (s *Service) run() {
+	defer close(s.doneCh)
+
+	headCh, unsubscribe := s.bus.Subscribe(eventbus.TopicNewHead)
+	defer unsubscribe()
+
+	for {
+		conn, err := s.dial()
+		if err == nil {
+			err = s.serve(conn, headCh)
+		}
+		if errors.Is(err, errShuttingDown) {
+			return
+		}
+		s.logger.Warn("ethstats connection failed, reconnecting", "err", err, "in", reconnectInterval)
+
+		select {
+		case <-time.After(reconnectInterval):
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// dial connects to the first reachable URL of the server
+func (s *Service) dial() (*conn, error) {
+	var err error
+	for _, url := range s.config.urls() {
+		var ws *websocket.Conn
+		if ws, _, err = websocket.DefaultDialer.Dial(url, nil); err == nil {
+			return &conn{ws: ws}, nil
+		}
+	}
+
+	return nil, err
+}
+
+// serve logs in and reports the stats over the connection, until it fails or the service is closed
+func (s *Service) serve(c *conn, headCh <-chan eventbus.Event) error {
+	defer c.close()
+
+	if err := s.login(c); err != nil {
+		return err
+	}
+	s.logger.Info("ethstats connected", "host", s.config.Host)
+
+	pongCh := make(chan struct{}, 1)
+	historyCh := make(chan []uint64, 1)
+	readErrCh := make(chan error, 1)
+
+	go func() {
+		readErrCh <- s.readLoop(c, pongCh, historyCh)
+	}()
+
+	if err := s.reportAll(c, pongCh); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(fullReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return errShuttingDown
+
+		case <-ticker.C:
+			if err := s.reportAll(c, pongCh); err != nil {
+				return err
+			}
+
+		case evnt, ok := <-headCh:
+			if !ok {
+				return errShuttingDown
+			}
+
+			// only the latest of the queued heads is reported, the node may be syncing
+			header := evnt.(*eventbus.NewHead).Header
+			for drained := false; !drained; {
+				select {
+				case evnt, ok := <-headCh:
+					if !ok {
+						return errShuttingDown
+					}
+					header = evnt.(*eventbus.NewHead).Header
+				default:
+					drained = true
+				}
+			}
+
+			if err := s.reportBlock(c, header); err != nil {
+				return err
+			}
+			if err := s.reportPending(c); err != nil {
+				return err
+			}
+
+		case numbers := <-historyCh:
+			if err := s.reportHistory(c, numbers); err != nil {
+				return err
+			}
+
+		case err := <-readErrCh:
+			return err
+		}
+	}
+}
+
+// login sends the description of the node and the secret, and waits for the server to accept them
+func (s *Service) login(c *conn) error {
+	auth := map[string]interface{}{
+		"id": s.config.Name,
+		"info": map[string]interface{}{
+			"name":             s.config.Name,
+			"node":             "polygon-sdk/v" + version.Version,
+			"port":             s.info.Port,
+			"net":              s.info.Network,
+			"protocol":         "libp2p",
+			"api":              "No",
+			"os":               runtime.GOOS,
+			"os_v":             runtime.GOARCH,
+			"client":           "0.1.1",
+			"canUpdateHistory": true,
+		},
+		"secret": s.config.Secret,
+	}
+	if err := c.emit("hello", auth); err != nil {
+		return err
+	}
+
+	var ack struct {
+		Emit []string `json:"emit"`
+	}
+	if err := c.ws.ReadJSON(&ack); err != nil || len(ack.Emit) != 1 || ack.Emit[0] != "ready" {
+		return errLoginDenied
+	}
+
+	return nil
+}
+
+// readLoop handles the messages of the server: the answers to the latency pings,
+// the requests of history and the keepalive pings of the primus library
+func (s *Service) readLoop(c *conn, pongCh chan<- struct{}, historyCh chan<- []uint64) error {
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		// the keepalive pings are JSON strings
+		var str string
+		if err := json.Unmarshal(data, &str); err == nil {
+			if strings.HasPrefix(str, "primus::ping::") {
+				if err := c.writeJSON(strings.Replace(str, "ping", "pong", 1)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		var msg struct {
+			Emit []json.RawMessage `json:"emit"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil || len(msg.Emit) == 0 {
+			s.logger.Debug("invalid ethstats message", "msg", string(data))
+			continue
+		}
+
+		var command string
+		if err := json.Unmarshal(msg.Emit[0], &command); err != nil {
+			continue
+		}
+
+		switch command {
+		case "node-pong":
+			select {
+			case pongCh <- struct{}{}:
+			default:
+			}
+
+		case "history":
+			var req struct {
+				List []uint64 `json:"list"`
+			}
+			if len(msg.Emit) > 1 {
+				if err := json.Unmarshal(msg.Emit[1], &req); err != nil {
+					s.logger.Debug("invalid ethstats history request", "err", err)
+				}
+			}
+
+			select {
+			case historyCh <- req.List:
+			default:
+			}
+		}
+	}
+}
+
+// reportAll reports the latency, the head, the pool and the node stats
+func (s *Service) reportAll(c *conn, pongCh <-chan struct{}) error {
+	if err := s.reportLatency(c, pongCh); err != nil {
+		return err
+	}
+	if err := s.reportBlock(c, s.backend.Header()); err != nil {
+		return err
+	}
+	if err := s.reportPending(c); err != nil {
+		return err
+	}
+
+	return s.reportStats(c)
+}
+
+// reportLatency measures the round trip to the server, and reports half of it
+func (s *Service) reportLatency(c *conn, pongCh <-chan struct{}) error {
+	// drop a late pong of the previous ping
+	select {
+	case <-pongCh:
+	default:
+	}
+
+	start := time.Now()
+	ping := map[string]interface{}{
+		"id":         s.config.Name,
+		"clientTime": start.String(),
+	}
+	if err := c.emit("node-ping", ping); err != nil {
+		return err
+	}
+
+	select {
+	case <-pongCh:
+	case <-time.After(pongTimeout):
+		return errPongTimeout
+	case <-s.closeCh:
+		return errShuttingDown
+	}
+
+	latency := time.Since(start) / 2
+
+	return c.emit("latency", map[string]interface{}{
+		"id":      s.config.Name,
+		"latency": fmt.Sprintf("%d", latency.Milliseconds()),
+	})
+}
+
+// blockStats is the description of a block on the dashboard
+type blockStats struct {
+	Number     uint64        `json:"number"`
+	Hash       types.Hash    `json:"hash"`
+	ParentHash types.Hash    `json:"parentHash"`
+	Timestamp  uint64        `json:"timestamp"`
+	Miner      types.Address `json:"miner"`
+	GasUsed    uint64        `json:"gasUsed"`
+	GasLimit   uint64        `json:"gasLimit"`
+	Diff       string        `json:"difficulty"`
+	TotalDiff  string        `json:"totalDifficulty"`
+	Txs        []txStats     `json:"transactions"`
+	TxHash     types.Hash    `json:"transactionsRoot"`
+	Root       types.Hash    `json:"stateRoot"`
+	Uncles     []struct{}    `json:"uncles"`
+}
+
+type txStats struct {
+	Hash types.Hash `json:"hash"`
+}
+
+func (s *Service) blockStats(header *types.Header) *blockStats {
+	stats := &blockStats{
+		Number:     header.Number,
+		Hash:       header.Hash,
+		ParentHash: header.ParentHash,
+		Timestamp:  header.Timestamp,
+		Miner:      s.backend.BlockCreator(header),
+		GasUsed:    header.GasUsed,
+		GasLimit:   header.GasLimit,
+		Diff:       fmt.Sprintf("%d", header.Difficulty),
+		TotalDiff:  "0",
+		Txs:        []txStats{},
+		TxHash:     header.TxRoot,
+		Root:       header.StateRoot,
+		Uncles:     []struct{}{},
+	}
+	if td, ok := s.backend.GetTD(header.Hash); ok {
+		stats.TotalDiff = td.String()
+	}
+	if block, ok := s.backend.GetBlockByHash(header.Hash, true); ok {
+		for _, tx := range block.Transactions {
+			stats.Txs = append(stats.Txs, txStats{Hash: tx.Hash})
+		}
+	}
+
+	return stats
+}
+
+func (s *Service) reportBlock(c *conn, header *types.Header) error {
+	return c.emit("block", map[string]interface{}{
+		"id":    s.config.Name,
+		"block": s.blockStats(header),
+	})
+}
+
+func (s *Service) reportPending(c *conn) error {
+	return c.emit("pending", map[string]interface{}{
+		"id": s.config.Name,
+		"stats": map[string]interface{}{
+			"pending": s.backend.PendingTxs(),
+		},
+	})
+}
+
+func (s *Service) reportStats(c *conn) error {
+	return c.emit("stats", map[string]interface{}{
+		"id": s.config.Name,
+		"stats": map[string]interface{}{
+			"active":   true,
+			"syncing":  s.backend.Syncing(),
+			"mining":   s.backend.Sealing(),
+			"hashrate": 0,
+			"peers":    s.backend.PeerCount(),
+			"gasPrice": 0,
+			"uptime":   100,
+		},
+	})
+}
+
+// reportHistory reports the blocks requested by the server, the latest ones if none are
+func (s *Service) reportHistory(c *conn, numbers []uint64) error {
+	if len(numbers) == 0 {
+		head := s.backend.Header().Number
+		for i := uint64(0); i < historyLength && i <= head; i++ {
+			numbers = append(numbers, head-i)
+		}
+	}
+
+	history := []*blockStats{}
+	for _, number := range numbers {
+		if header, ok := s.backend.GetHeaderByNumber(number); ok {
+			history = append(history, s.blockStats(header))
+		}
+	}
+
+	return c.emit("history", map[string]interface{}{
+		"id":      s.config.Name,
+		"history": history,
+	})
+}
+
+// conn serializes the writes to the websocket, the read loop answers the keepalive pings
+type conn struct {
+	ws   *websocket.Conn
+	lock sync.Mutex
+}
+
+func (c *conn) writeJSON(v interface{}) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.ws.WriteJSON(v)
+}
+
+// emit sends a message of the netstats protocol
+func (c *conn) emit(command string, payload interface{}) error {
+	return c.writeJSON(map[string][]interface{}{
+		"emit": {command, payload},
+	})
+}
+
+func (c *conn) close() {
+	c.ws.Close()
+}
@@ -0,0 +1,235 @@
+package ethstats
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig("node1:s3cr3t@stats.example.com:3000")
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{Name: "node1", Secret: "s3cr3t", Host: "stats.example.com:3000"}, config)
+	assert.Equal(t, []string{"wss://stats.example.com:3000/api", "ws://stats.example.com:3000/api"}, config.urls())
+
+	config, err = ParseConfig("node1@ws://127.0.0.1:3000/api")
+	assert.NoError(t, err)
+	assert.Equal(t, "", config.Secret)
+	assert.Equal(t, []string{"ws://127.0.0.1:3000/api"}, config.urls())
+
+	for _, raw := range []string{"", "stats.example.com", "@stats.example.com", "node1:s3cr3t@", ":s3cr3t@host"} {
+		_, err := ParseConfig(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+type mockBackend struct {
+	headers []*types.Header
+}
+
+func (m *mockBackend) Header() *types.Header {
+	return m.headers[len(m.headers)-1]
+}
+
+func (m *mockBackend) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(m.headers)) {
+		return nil, false
+	}
+
+	return m.headers[number], true
+}
+
+func (m *mockBackend) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	for _, header := range m.headers {
+		if header.Hash == hash {
+			return &types.Block{Header: header}, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *mockBackend) GetTD(hash types.Hash) (*big.Int, bool) {
+	return big.NewInt(1), true
+}
+
+func (m *mockBackend) BlockCreator(header *types.Header) types.Address {
+	return types.StringToAddress("1")
+}
+
+func (m *mockBackend) PendingTxs() uint64 {
+	return 7
+}
+
+func (m *mockBackend) PeerCount() int {
+	return 3
+}
+
+func (m *mockBackend) Syncing() bool {
+	return false
+}
+
+func (m *mockBackend) Sealing() bool {
+	return true
+}
+
+// netstatsMessage is a message received by the test server
+type netstatsMessage struct {
+	command string
+	payload map[string]interface{}
+}
+
+// newNetstatsServer starts a netstats server accepting the secret, it answers the latency pings
+// and forwards the other messages
+func newNetstatsServer(t *testing.T, secret string) (*httptest.Server, <-chan *netstatsMessage, chan<- interface{}) {
+	msgCh := make(chan *netstatsMessage, 100)
+	sendCh := make(chan interface{}, 10)
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		go func() {
+			for msg := range sendCh {
+				if err := ws.WriteJSON(msg); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			var msg struct {
+				Emit []json.RawMessage `json:"emit"`
+			}
+			if err := ws.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			m := &netstatsMessage{}
+			assert.NoError(t, json.Unmarshal(msg.Emit[0], &m.command))
+			assert.NoError(t, json.Unmarshal(msg.Emit[1], &m.payload))
+
+			switch m.command {
+			case "hello":
+				if m.payload["secret"] != secret {
+					return
+				}
+				sendCh <- map[string][]string{"emit": {"ready"}}
+			case "node-ping":
+				sendCh <- map[string][]interface{}{"emit": {"node-pong", m.payload}}
+			}
+
+			msgCh <- m
+		}
+	}))
+
+	return srv, msgCh, sendCh
+}
+
+// waitFor returns the next message of the command
+func waitFor(t *testing.T, msgCh <-chan *netstatsMessage, command string) *netstatsMessage {
+	t.Helper()
+
+	timeoutCh := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-msgCh:
+			if msg.command == command {
+				return msg
+			}
+		case <-timeoutCh:
+			t.Fatalf("no %s message", command)
+		}
+	}
+}
+
+func TestService_Report(t *testing.T) {
+	srv, msgCh, sendCh := newNetstatsServer(t, "s3cr3t")
+	defer srv.Close()
+
+	backend := &mockBackend{}
+	for i := 0; i < 3; i++ {
+		header := &types.Header{Number: uint64(i), GasLimit: 5000}
+		header.Hash = types.BytesToHash([]byte{byte(i + 1)})
+		backend.headers = append(backend.headers, header)
+	}
+
+	bus := eventbus.New(hclog.NewNullLogger())
+	defer bus.Close()
+
+	config := &Config{
+		Name:   "node1",
+		Secret: "s3cr3t",
+		Host:   "ws" + strings.TrimPrefix(srv.URL, "http"),
+	}
+	service := NewService(hclog.NewNullLogger(), config, backend, bus, &NodeInfo{Network: "100", Port: 1478})
+	service.Start()
+	defer service.Close()
+
+	hello := waitFor(t, msgCh, "hello")
+	assert.Equal(t, "node1", hello.payload["id"])
+	assert.Equal(t, "100", hello.payload["info"].(map[string]interface{})["net"])
+
+	// the full report follows the login
+	waitFor(t, msgCh, "latency")
+	block := waitFor(t, msgCh, "block").payload["block"].(map[string]interface{})
+	assert.Equal(t, float64(2), block["number"])
+
+	pending := waitFor(t, msgCh, "pending").payload["stats"].(map[string]interface{})
+	assert.Equal(t, float64(7), pending["pending"])
+
+	stats := waitFor(t, msgCh, "stats").payload["stats"].(map[string]interface{})
+	assert.Equal(t, float64(3), stats["peers"])
+	assert.Equal(t, true, stats["mining"])
+
+	// a new head is reported right away
+	header := &types.Header{Number: 3}
+	header.Hash = types.BytesToHash([]byte{4})
+	backend.headers = append(backend.headers, header)
+	bus.Publish(&eventbus.NewHead{Header: header})
+
+	block = waitFor(t, msgCh, "block").payload["block"].(map[string]interface{})
+	assert.Equal(t, float64(3), block["number"])
+
+	// the history of the requested blocks
+	sendCh <- map[string][]interface{}{"emit": {"history", map[string][]uint64{"list": {0, 1}}}}
+
+	history := waitFor(t, msgCh, "history").payload["history"].([]interface{})
+	assert.Len(t, history, 2)
+}
+
+func TestService_LoginDenied(t *testing.T) {
+	srv, msgCh, _ := newNetstatsServer(t, "s3cr3t")
+	defer srv.Close()
+
+	backend := &mockBackend{headers: []*types.Header{{}}}
+	bus := eventbus.New(hclog.NewNullLogger())
+	defer bus.Close()
+
+	service := NewService(hclog.NewNullLogger(), &Config{
+		Name:   "node1",
+		Secret: "wrong",
+		Host:   "ws" + strings.TrimPrefix(srv.URL, "http"),
+	}, backend, bus, &NodeInfo{})
+
+	c, err := service.dial()
+	assert.NoError(t, err)
+	defer c.close()
+
+	assert.Equal(t, errLoginDenied, service.login(c))
+	assert.Len(t, msgCh, 0)
+}
@@ -0,0 +1,173 @@
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// subBuffer is the number of events a slow subscriber can lag behind.
+// The events published while its buffer is full are not delivered to it
+const subBuffer = 1024
+
+// Topic is the kind of the events published on the bus
+type Topic string
+
+const (
+	// TopicNewHead is the advance of the head of the canonical chain
+	TopicNewHead Topic = "newHead"
+
+	// TopicNewFinalized is the advance of the latest block that can't be reorganized anymore
+	TopicNewFinalized Topic = "newFinalized"
+
+	// TopicReorg is the replacement of canonical blocks by the blocks of another branch
+	TopicReorg Topic = "reorg"
+
+	// TopicNewPendingTx is a transaction of the pool that became executable
+	TopicNewPendingTx Topic = "newPendingTx"
+)
+
+// Event is an event published on the bus
+type Event interface {
+	Topic() Topic
+}
+
+// NewHead is published every time the head of the canonical chain changes
+type NewHead struct {
+	Header *types.Header
+}
+
+func (e *NewHead) Topic() Topic {
+	return TopicNewHead
+}
+
+// NewFinalized is published every time the finalized block of the chain advances
+type NewFinalized struct {
+	Header *types.Header
+}
+
+func (e *NewFinalized) Topic() Topic {
+	return TopicNewFinalized
+}
+
+// Reorg is published when the canonical chain switches to another branch.
+// The removed and added headers are sorted by number, and descend from the common ancestor
+type Reorg struct {
+	Ancestor *types.Header
+	Removed  []*types.Header
+	Added    []*types.Header
+}
+
+func (e *Reorg) Topic() Topic {
+	return TopicReorg
+}
+
+// NewPendingTx is published when a transaction of the pool becomes executable
+type NewPendingTx struct {
+	Tx *types.Transaction
+}
+
+func (e *NewPendingTx) Topic() Topic {
+	return TopicNewPendingTx
+}
+
+// subscriber is a subscription to some of the topics of the bus
+type subscriber struct {
+	topics map[Topic]struct{}
+	ch     chan Event
+}
+
+// Bus delivers the events published by the subsystems of the node to the subscribers of their topic.
+// Publishing never blocks: a subscriber that doesn't keep up misses the events its buffer can't hold
+type Bus struct {
+	logger hclog.Logger
+
+	lock   sync.Mutex
+	subs   map[uint64]*subscriber
+	next   uint64
+	closed bool
+}
+
+// New creates a new event bus
+func New(logger hclog.Logger) *Bus {
+	return &Bus{
+		logger: logger.Named("eventbus"),
+		subs:   map[uint64]*subscriber{},
+	}
+}
+
+// Subscribe returns the channel receiving the events of the topics, and the function that ends
+// the subscription and closes the channel. The channel of a closed bus is closed right away
+func (b *Bus) Subscribe(topics ...Topic) (<-chan Event, func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	sub := &subscriber{
+		topics: make(map[Topic]struct{}, len(topics)),
+		ch:     make(chan Event, subBuffer),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = struct{}{}
+	}
+
+	if b.closed {
+		close(sub.ch)
+
+		return sub.ch, func() {}
+	}
+
+	id := b.next
+	b.next++
+
+	b.subs[id] = sub
+
+	unsubscribe := func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+
+		if _, ok := b.subs[id]; ok {
+			delete(b.subs, id)
+			close(sub.ch)
+		}
+	}
+
+	return sub.ch, unsubscribe
+}
+
+// Publish delivers the event to the subscribers of its topic
+func (b *Bus) Publish(evnt Event) {
+	if b == nil {
+		// the subsystems run without a bus in the tests and the tools
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	topic := evnt.Topic()
+	for _, sub := range b.subs {
+		if _, ok := sub.topics[topic]; !ok {
+			continue
+		}
+
+		select {
+		case sub.ch <- evnt:
+		default:
+			b.logger.Warn("subscriber lagging behind, event dropped", "topic", topic)
+		}
+	}
+}
+
+// Close ends all the subscriptions, the subscribers see their channel closed
+func (b *Bus) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for id, sub := range b.subs {
+		delete(b.subs, id)
+		close(sub.ch)
+	}
+
+	b.closed = true
+}
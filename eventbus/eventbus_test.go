@@ -0,0 +1,103 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveEvents(t *testing.T, ch <-chan Event) []Event {
+	t.Helper()
+
+	events := []Event{}
+	for {
+		select {
+		case evnt, ok := <-ch:
+			if !ok {
+				return events
+			}
+			events = append(events, evnt)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBus_Topics(t *testing.T) {
+	bus := New(hclog.NewNullLogger())
+
+	headCh, unsubscribeHead := bus.Subscribe(TopicNewHead)
+	defer unsubscribeHead()
+
+	allCh, unsubscribeAll := bus.Subscribe(TopicNewHead, TopicReorg, TopicNewPendingTx)
+	defer unsubscribeAll()
+
+	head := &NewHead{Header: &types.Header{Number: 1}}
+	bus.Publish(head)
+	bus.Publish(&Reorg{})
+	bus.Publish(&NewPendingTx{Tx: &types.Transaction{}})
+	bus.Publish(&NewFinalized{Header: &types.Header{}})
+
+	assert.Equal(t, []Event{head}, receiveEvents(t, headCh))
+
+	topics := []Topic{}
+	for _, evnt := range receiveEvents(t, allCh) {
+		topics = append(topics, evnt.Topic())
+	}
+	assert.Equal(t, []Topic{TopicNewHead, TopicReorg, TopicNewPendingTx}, topics)
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := New(hclog.NewNullLogger())
+
+	ch, unsubscribe := bus.Subscribe(TopicNewHead)
+	unsubscribe()
+
+	// the channel is closed once, and receives nothing more
+	unsubscribe()
+	bus.Publish(&NewHead{Header: &types.Header{}})
+
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestBus_SlowSubscriber(t *testing.T) {
+	bus := New(hclog.NewNullLogger())
+
+	ch, unsubscribe := bus.Subscribe(TopicNewHead)
+	defer unsubscribe()
+
+	// publishing doesn't block on a full buffer
+	for i := 0; i < subBuffer+10; i++ {
+		bus.Publish(&NewHead{Header: &types.Header{Number: uint64(i)}})
+	}
+
+	events := receiveEvents(t, ch)
+	assert.Len(t, events, subBuffer)
+	assert.Equal(t, uint64(subBuffer-1), events[len(events)-1].(*NewHead).Header.Number)
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := New(hclog.NewNullLogger())
+
+	ch, unsubscribe := bus.Subscribe(TopicNewHead)
+	bus.Close()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	// unsubscribing after the close is a no-op
+	unsubscribe()
+
+	// and the later subscriptions are closed right away
+	ch, _ = bus.Subscribe(TopicNewHead)
+
+	_, ok = <-ch
+	assert.False(t, ok)
+
+	// the subsystems publish on a nil bus when they have none
+	var noBus *Bus
+	noBus.Publish(&NewHead{})
+}
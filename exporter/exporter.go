@@ -0,0 +1,267 @@
+package exporter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// DefaultTopic is the default topic, or subject, the blocks are published to
+	DefaultTopic = "blocks"
+
+	// retryInterval is the delay before the blocks are exported again, after a failure of the sink
+	retryInterval = 5 * time.Second
+)
+
+// the kinds of sinks
+const (
+	SinkWebhook = "webhook"
+	SinkNATS    = "nats"
+	SinkKafka   = "kafka"
+)
+
+var (
+	ErrUnknownSink = errors.New("unknown export sink, expected webhook, nats or kafka")
+)
+
+// cursorKey is the key of the last exported block in the database
+var cursorKey = []byte("cursor")
+
+// Config is the sink the blocks are exported to
+type Config struct {
+	// Sink is the kind of the sink: webhook, nats or kafka
+	Sink string
+
+	// URL is the endpoint of the sink: the webhook URL, the nats://host:port address of the NATS server,
+	// or the URL of the REST proxy of the Kafka cluster
+	URL string
+
+	// Topic is the Kafka topic or the NATS subject the blocks are published to
+	Topic string
+}
+
+// NewSink returns the sink of the config
+func NewSink(config *Config) (Sink, error) {
+	topic := config.Topic
+	if topic == "" {
+		topic = DefaultTopic
+	}
+
+	switch config.Sink {
+	case SinkWebhook:
+		return newWebhookSink(config.URL), nil
+	case SinkNATS:
+		return newNATSSink(config.URL, topic)
+	case SinkKafka:
+		return newKafkaSink(config.URL, topic), nil
+	}
+
+	return nil, ErrUnknownSink
+}
+
+// Backend provides the canonical blocks of the chain and their receipts
+type Backend interface {
+	Header() *types.Header
+	GetHeaderByNumber(number uint64) (*types.Header, bool)
+	GetHeaderByHash(hash types.Hash) (*types.Header, bool)
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
+}
+
+// Exporter publishes the new canonical blocks, with their transactions, receipts and logs, to a sink,
+// so the data pipelines don't poll the JSON-RPC endpoints. The blocks are published in order, at least once.
+// When the chain reorganizes, the exported blocks which are not canonical anymore are published as removed,
+// the newest first, before the blocks of the new branch
+type Exporter struct {
+	logger  hclog.Logger
+	backend Backend
+	sink    Sink
+	db      kvdb.Database
+	bus     *eventbus.Bus
+
+	// number and hash are the last exported block
+	lock   sync.Mutex
+	number uint64
+	hash   types.Hash
+
+	started bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewExporter returns the exporter, the exports start with Start. The last exported block is kept
+// in the database, the first run starts after the current head
+func NewExporter(
+	logger hclog.Logger,
+	backend Backend,
+	sink Sink,
+	db kvdb.Database,
+	bus *eventbus.Bus,
+) (*Exporter, error) {
+	e := &Exporter{
+		logger:  logger.Named("exporter"),
+		backend: backend,
+		sink:    sink,
+		db:      db,
+		bus:     bus,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	data, ok, err := db.Get(cursorKey)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if len(data) != 8+types.HashLength {
+			return nil, fmt.Errorf("invalid cursor of the exporter: %s", hex.EncodeToHex(data))
+		}
+		e.number = binary.BigEndian.Uint64(data[:8])
+		e.hash = types.BytesToHash(data[8:])
+	} else {
+		head := backend.Header()
+		e.number, e.hash = head.Number, head.Hash
+	}
+
+	return e, nil
+}
+
+// Start starts the exports of the new blocks
+func (e *Exporter) Start() {
+	e.logger.Info("exporting the blocks", "from", e.number+1)
+	e.started = true
+
+	go e.run()
+}
+
+// Close stops the exports, if started, and closes the sink and the database
+func (e *Exporter) Close() error {
+	close(e.closeCh)
+
+	if e.started {
+		<-e.doneCh
+	}
+
+	if err := e.sink.Close(); err != nil {
+		e.logger.Error("failed to close the sink", "err", err)
+	}
+
+	return e.db.Close()
+}
+
+func (e *Exporter) run() {
+	defer close(e.doneCh)
+
+	heads, unsubscribe := e.bus.Subscribe(eventbus.TopicNewHead)
+	defer unsubscribe()
+
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+
+	for {
+		select {
+		case _, ok := <-heads:
+			if !ok {
+				return
+			}
+		case <-retry.C:
+		case <-e.closeCh:
+			return
+		}
+
+		if err := e.export(); err != nil {
+			e.logger.Error("failed to export the blocks", "err", err)
+			retry.Reset(retryInterval)
+		}
+	}
+}
+
+// export publishes the removal of the exported blocks out of the canonical chain,
+// and the canonical blocks after the last exported one
+func (e *Exporter) export() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if err := e.removeReorged(); err != nil {
+		return err
+	}
+
+	for {
+		header, ok := e.backend.GetHeaderByNumber(e.number + 1)
+		if !ok {
+			return nil
+		}
+		if header.ParentHash != e.hash {
+			// the chain reorganized meanwhile
+			number := e.number
+			if err := e.removeReorged(); err != nil {
+				return err
+			}
+			if e.number == number {
+				// the head is being written, the export resumes on the next head
+				return nil
+			}
+			continue
+		}
+
+		block, ok := e.backend.GetBlockByHash(header.Hash, true)
+		if !ok {
+			return fmt.Errorf("block %s not found", header.Hash)
+		}
+		receipts, err := e.backend.GetReceiptsByHash(header.Hash)
+		if err != nil {
+			return err
+		}
+
+		if err := e.sink.Publish(newBlockMessage(block, receipts)); err != nil {
+			return err
+		}
+		if err := e.setCursor(header.Number, header.Hash); err != nil {
+			return err
+		}
+	}
+}
+
+// removeReorged publishes the removal of the exported blocks replaced by a reorg, the newest first
+func (e *Exporter) removeReorged() error {
+	for e.number > 0 {
+		if header, ok := e.backend.GetHeaderByNumber(e.number); ok && header.Hash == e.hash {
+			return nil
+		}
+
+		header, ok := e.backend.GetHeaderByHash(e.hash)
+		if !ok {
+			return fmt.Errorf("exported block %s not found", e.hash)
+		}
+		if err := e.sink.Publish(newRemovedMessage(header)); err != nil {
+			return err
+		}
+		if err := e.setCursor(header.Number-1, header.ParentHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setCursor sets and persists the last exported block
+func (e *Exporter) setCursor(number uint64, hash types.Hash) error {
+	data := make([]byte, 8, 8+types.HashLength)
+	binary.BigEndian.PutUint64(data, number)
+
+	if err := e.db.Put(cursorKey, append(data, hash.Bytes()...)); err != nil {
+		return err
+	}
+	e.number, e.hash = number, hash
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBackend is a chain whose canonical blocks can be replaced
+type mockBackend struct {
+	canonical []*types.Header
+	headers   map[types.Hash]*types.Header
+}
+
+func newMockBackend() *mockBackend {
+	m := &mockBackend{headers: map[types.Hash]*types.Header{}}
+	m.extend(0, 4, 0)
+
+	return m
+}
+
+// extend replaces the canonical blocks after the number with count blocks of the seed
+func (m *mockBackend) extend(number uint64, count int, seed uint64) {
+	m.canonical = m.canonical[:number]
+	for i := 0; i < count; i++ {
+		header := &types.Header{Number: uint64(len(m.canonical)), Timestamp: seed}
+		if header.Number > 0 {
+			header.ParentHash = m.canonical[header.Number-1].Hash
+		}
+		header.ComputeHash()
+
+		m.canonical = append(m.canonical, header)
+		m.headers[header.Hash] = header
+	}
+}
+
+func (m *mockBackend) Header() *types.Header {
+	return m.canonical[len(m.canonical)-1]
+}
+
+func (m *mockBackend) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(m.canonical)) {
+		return nil, false
+	}
+	return m.canonical[number], true
+}
+
+func (m *mockBackend) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
+	header, ok := m.headers[hash]
+	return header, ok
+}
+
+func (m *mockBackend) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	header, ok := m.headers[hash]
+	if !ok {
+		return nil, false
+	}
+
+	to := types.StringToAddress("2")
+	tx := &types.Transaction{Nonce: header.Number, To: &to, Value: big.NewInt(1), GasPrice: big.NewInt(1)}
+	tx.ComputeHash()
+
+	return &types.Block{Header: header, Transactions: []*types.Transaction{tx}}, true
+}
+
+func (m *mockBackend) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	receipt := &types.Receipt{
+		GasUsed: 21000,
+		Logs:    []*types.Log{{Address: types.StringToAddress("3"), Data: []byte{0x1}}},
+	}
+	receipt.SetStatus(types.ReceiptSuccess)
+
+	return []*types.Receipt{receipt}, nil
+}
+
+// mockSink records the published messages, until it fails
+type mockSink struct {
+	msgs []*Message
+	fail bool
+}
+
+func (m *mockSink) Publish(msg *Message) error {
+	if m.fail {
+		return errors.New("unavailable")
+	}
+	m.msgs = append(m.msgs, msg)
+	return nil
+}
+
+func (m *mockSink) Close() error {
+	return nil
+}
+
+// summary returns the type and the number of the published messages
+func (m *mockSink) summary() []string {
+	res := []string{}
+	for _, msg := range m.msgs {
+		res = append(res, fmt.Sprintf("%s %d", msg.Type, msg.Number))
+	}
+	return res
+}
+
+func TestExporter(t *testing.T) {
+	backend := newMockBackend()
+	db := kvdb.NewMemoryDatabase()
+	sink := &mockSink{}
+
+	// the first run starts after the head
+	e, err := NewExporter(hclog.NewNullLogger(), backend, sink, db, nil)
+	assert.NoError(t, err)
+
+	backend.extend(4, 2, 0)
+	assert.NoError(t, e.export())
+	assert.Equal(t, []string{"block 4", "block 5"}, sink.summary())
+
+	msg := sink.msgs[0]
+	assert.Equal(t, backend.canonical[4].Hash, msg.Hash)
+	assert.Len(t, msg.Transactions, 1)
+	assert.Equal(t, uint64(1), *msg.Transactions[0].Status)
+	assert.Nil(t, msg.Transactions[0].ContractAddress)
+	assert.Equal(t, []*Log{{
+		Address: types.StringToAddress("3"),
+		Topics:  nil,
+		Data:    "0x01",
+		TxHash:  msg.Transactions[0].Hash,
+	}}, msg.Logs)
+
+	// the exported blocks replaced by a reorg are removed, the newest first
+	sink.msgs = nil
+	removed := backend.canonical[5]
+	backend.extend(4, 3, 1)
+
+	assert.NoError(t, e.export())
+	assert.Equal(t, []string{"removed 5", "removed 4", "block 4", "block 5", "block 6"}, sink.summary())
+	assert.Equal(t, removed.Hash, sink.msgs[0].Hash)
+	assert.Equal(t, backend.canonical[6].Hash, sink.msgs[4].Hash)
+
+	// the failed blocks are exported again, after a restart too
+	sink.msgs, sink.fail = nil, true
+	backend.extend(7, 1, 1)
+	assert.Error(t, e.export())
+
+	sink.fail = false
+	e, err = NewExporter(hclog.NewNullLogger(), backend, sink, db, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.export())
+	assert.Equal(t, []string{"block 7"}, sink.summary())
+}
+
+func TestHTTPSinks(t *testing.T) {
+	var (
+		path, contentType string
+		body              map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		data, _ := ioutil.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(data, &body)
+
+		if strings.Contains(path, "fail") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	msg := newRemovedMessage(&types.Header{Number: 3, Hash: types.StringToHash("3")})
+
+	sink, err := NewSink(&Config{Sink: SinkWebhook, URL: srv.URL + "/blocks"})
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Publish(msg))
+	assert.Equal(t, "/blocks", path)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "removed", body["type"])
+
+	// the Kafka records are produced through the REST proxy, to the default topic
+	sink, err = NewSink(&Config{Sink: SinkKafka, URL: srv.URL + "/"})
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Publish(msg))
+	assert.Equal(t, "/topics/blocks", path)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", contentType)
+
+	records := body["records"].([]interface{})
+	assert.Len(t, records, 1)
+	assert.Equal(t, msg.Hash.String(), records[0].(map[string]interface{})["key"])
+
+	sink, err = NewSink(&Config{Sink: SinkWebhook, URL: srv.URL + "/fail"})
+	assert.NoError(t, err)
+	assert.Error(t, sink.Publish(msg))
+
+	_, err = NewSink(&Config{Sink: "unknown"})
+	assert.Equal(t, ErrUnknownSink, err)
+}
+
+func TestNATSSink(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer lis.Close()
+
+	// the server acknowledges the PING following every message
+	received := make(chan string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("INFO {}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := reader.ReadString('\n')
+				received <- line + payload
+			case strings.HasPrefix(line, "PING"):
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	_, err = NewSink(&Config{Sink: SinkNATS, URL: "http://" + lis.Addr().String()})
+	assert.Error(t, err)
+
+	sink, err := NewSink(&Config{Sink: SinkNATS, URL: "nats://" + lis.Addr().String(), Topic: "chain.blocks"})
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	msg := newRemovedMessage(&types.Header{Number: 3})
+	assert.NoError(t, sink.Publish(msg))
+
+	data, _ := json.Marshal(msg)
+	assert.Equal(t, fmt.Sprintf("PUB chain.blocks %d\r\n%s\r\n", len(data), data), <-received)
+}
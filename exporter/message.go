@@ -0,0 +1,144 @@
+package exporter
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// the types of the messages
+const (
+	// MessageBlock is a new canonical block, with its transactions, receipts and logs
+	MessageBlock = "block"
+
+	// MessageRemoved is an exported block which is not canonical anymore, only its header is set
+	MessageRemoved = "removed"
+)
+
+// Message is the JSON message of a block published to the sink.
+// The quantities are numbers, the big integers and the byte arrays are hex strings
+type Message struct {
+	Type         string         `json:"type"`
+	Number       uint64         `json:"number"`
+	Hash         types.Hash     `json:"hash"`
+	ParentHash   types.Hash     `json:"parentHash"`
+	Timestamp    uint64         `json:"timestamp"`
+	Miner        types.Address  `json:"miner"`
+	GasLimit     uint64         `json:"gasLimit"`
+	GasUsed      uint64         `json:"gasUsed"`
+	BaseFee      string         `json:"baseFee,omitempty"`
+	Transactions []*Transaction `json:"transactions,omitempty"`
+	Logs         []*Log         `json:"logs,omitempty"`
+}
+
+// Transaction is a transaction of the block, with the fields of its receipt
+type Transaction struct {
+	Hash            types.Hash     `json:"hash"`
+	Index           uint64         `json:"index"`
+	Type            uint64         `json:"type"`
+	From            types.Address  `json:"from"`
+	To              *types.Address `json:"to"`
+	Nonce           uint64         `json:"nonce"`
+	Value           string         `json:"value"`
+	Gas             uint64         `json:"gas"`
+	GasPrice        string         `json:"gasPrice"`
+	Input           string         `json:"input"`
+	Status          *uint64        `json:"status"`
+	GasUsed         uint64         `json:"gasUsed"`
+	ContractAddress *types.Address `json:"contractAddress"`
+}
+
+// Log is a log of the block, with the transaction emitting it
+type Log struct {
+	Address types.Address `json:"address"`
+	Topics  []types.Hash  `json:"topics"`
+	Data    string        `json:"data"`
+	TxHash  types.Hash    `json:"transactionHash"`
+	TxIndex uint64        `json:"transactionIndex"`
+	Index   uint64        `json:"logIndex"`
+}
+
+func newHeaderMessage(typ string, header *types.Header) *Message {
+	msg := &Message{
+		Type:       typ,
+		Number:     header.Number,
+		Hash:       header.Hash,
+		ParentHash: header.ParentHash,
+		Timestamp:  header.Timestamp,
+		Miner:      header.Miner,
+		GasLimit:   header.GasLimit,
+		GasUsed:    header.GasUsed,
+	}
+	if header.BaseFee != nil {
+		msg.BaseFee = hex.EncodeBig(header.BaseFee)
+	}
+
+	return msg
+}
+
+// newRemovedMessage returns the message of an exported block removed from the canonical chain
+func newRemovedMessage(header *types.Header) *Message {
+	return newHeaderMessage(MessageRemoved, header)
+}
+
+// newBlockMessage returns the message of a new canonical block
+func newBlockMessage(block *types.Block, receipts []*types.Receipt) *Message {
+	msg := newHeaderMessage(MessageBlock, block.Header)
+	msg.Transactions = []*Transaction{}
+	msg.Logs = []*Log{}
+
+	logIndex := uint64(0)
+	for i, txn := range block.Transactions {
+		tx := &Transaction{
+			Hash:     txn.Hash,
+			Index:    uint64(i),
+			Type:     uint64(txn.Type),
+			From:     txn.From,
+			To:       txn.To,
+			Nonce:    txn.Nonce,
+			Value:    encodeBig(txn.Value),
+			Gas:      txn.Gas,
+			GasPrice: encodeBig(txn.GasPrice),
+			Input:    hex.EncodeToHex(txn.Input),
+		}
+		msg.Transactions = append(msg.Transactions, tx)
+
+		if i >= len(receipts) {
+			continue
+		}
+		receipt := receipts[i]
+
+		if receipt.Status != nil {
+			status := uint64(*receipt.Status)
+			tx.Status = &status
+		}
+		tx.GasUsed = receipt.GasUsed
+		if txn.To == nil {
+			contract := receipt.ContractAddress
+			tx.ContractAddress = &contract
+		}
+
+		for _, log := range receipt.Logs {
+			msg.Logs = append(msg.Logs, &Log{
+				Address: log.Address,
+				Topics:  log.Topics,
+				Data:    hex.EncodeToHex(log.Data),
+				TxHash:  txn.Hash,
+				TxIndex: uint64(i),
+				Index:   logIndex,
+			})
+			logIndex++
+		}
+	}
+
+	return msg
+}
+
+func encodeBig(v *big.Int) string {
+	if v == nil {
+		return "0x0"
+	}
+
+	return hex.EncodeBig(v)
+}
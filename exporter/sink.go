@@ -0,0 +1,221 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// sinkTimeout is the time a sink has to acknowledge a message
+	sinkTimeout = 10 * time.Second
+)
+
+// Sink publishes the messages of the exporter. A message is published once Publish returns
+// without error, otherwise it is published again
+type Sink interface {
+	Publish(msg *Message) error
+	Close() error
+}
+
+// postJSON posts the JSON body to the URL, and expects a 2xx status
+func postJSON(client *http.Client, url, contentType string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, contentType, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// webhookSink posts every message to an HTTP endpoint
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: sinkTimeout},
+	}
+}
+
+func (w *webhookSink) Publish(msg *Message) error {
+	return postJSON(w.client, w.url, "application/json", msg)
+}
+
+func (w *webhookSink) Close() error {
+	return nil
+}
+
+// kafkaSink produces the messages to a topic through the REST proxy of the Kafka cluster (v2 API).
+// The key of a record is the hash of its block
+type kafkaSink struct {
+	url    string
+	client *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string   `json:"key"`
+	Value *Message `json:"value"`
+}
+
+func newKafkaSink(proxy, topic string) *kafkaSink {
+	return &kafkaSink{
+		url:    strings.TrimSuffix(proxy, "/") + "/topics/" + url.PathEscape(topic),
+		client: &http.Client{Timeout: sinkTimeout},
+	}
+}
+
+func (k *kafkaSink) Publish(msg *Message) error {
+	body := map[string][]*kafkaRecord{
+		"records": {{Key: msg.Hash.String(), Value: msg}},
+	}
+
+	return postJSON(k.client, k.url, "application/vnd.kafka.json.v2+json", body)
+}
+
+func (k *kafkaSink) Close() error {
+	return nil
+}
+
+// natsSink publishes the messages to a subject of a NATS server, with the text protocol of the
+// core NATS. Every message is followed by a PING, the PONG of the server acknowledges it
+type natsSink struct {
+	addr    string
+	subject string
+
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newNATSSink(raw, subject string) (*natsSink, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("the NATS server %q is not a nats://host:port URL", raw)
+	}
+	if strings.ContainsAny(subject, " \t\r\n") {
+		return nil, fmt.Errorf("invalid NATS subject %q", subject)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	return &natsSink{addr: addr, subject: subject}, nil
+}
+
+// connect connects to the server, which sends its INFO first
+func (n *natsSink) connect() error {
+	conn, err := net.DialTimeout("tcp", n.addr, sinkTimeout)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(sinkTimeout))
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected greeting of the NATS server: %q", strings.TrimSpace(line))
+	}
+
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"exporter\"}\r\n")); err != nil {
+		conn.Close()
+		return err
+	}
+
+	n.conn, n.reader = conn, reader
+
+	return nil
+}
+
+func (n *natsSink) Publish(msg *Message) error {
+	if n.conn == nil {
+		if err := n.connect(); err != nil {
+			return err
+		}
+	}
+
+	if err := n.publish(msg); err != nil {
+		// the message is published again on a new connection
+		n.Close()
+		return err
+	}
+
+	return nil
+}
+
+func (n *natsSink) publish(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_ = n.conn.SetDeadline(time.Now().Add(sinkTimeout))
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "PUB %s %d\r\n", n.subject, len(data))
+	buf.Write(data)
+	buf.WriteString("\r\nPING\r\n")
+
+	if _, err := n.conn.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	for {
+		line, err := n.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := n.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("NATS server error: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// the +OK and the INFO updates are ignored
+	}
+}
+
+func (n *natsSink) Close() error {
+	if n.conn == nil {
+		return nil
+	}
+
+	err := n.conn.Close()
+	n.conn, n.reader = nil, nil
+
+	return err
+}
@@ -0,0 +1,431 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+)
+
+// Version is the version of the backup format
+const Version = 1
+
+// magic starts every backup, before the gzipped records
+var magic = []byte("SDKBACKUP")
+
+// Kinds of the records of a backup
+const (
+	recordMetadata byte = iota + 1
+	recordEntry
+	recordFile
+	recordEnd
+)
+
+// restoreBatchSize is the number of entries written at once by Restore
+const restoreBatchSize = 10000
+
+var (
+	ErrNotBackup   = errors.New("the file is not a backup")
+	ErrVersion     = errors.New("unsupported backup version")
+	ErrTruncated   = errors.New("the backup is truncated")
+	ErrStoreExists = errors.New("the data directory already has the data store")
+)
+
+// Metadata describes the content of a backup
+type Metadata struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+
+	// HeadNumber and HeadHash are the head of the chain when the backup was started
+	HeadNumber uint64 `json:"headNumber"`
+	HeadHash   string `json:"headHash"`
+
+	// Stores are the key-value data stores in the backup
+	Stores []string `json:"stores"`
+
+	// Frozen is the number of blocks of the freezer files in the backup
+	Frozen uint64 `json:"frozen"`
+}
+
+// Writer writes a backup: the metadata, the entries of the data stores and the files,
+// in a gzip stream. The backup is only complete once the writer is closed
+type Writer struct {
+	gz      *gzip.Writer
+	entries uint64
+	files   uint64
+	buf     []byte
+}
+
+// NewWriter writes the header of the backup with the metadata
+func NewWriter(w io.Writer, metadata *Metadata) (*Writer, error) {
+	if _, err := w.Write(magic); err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewWriterLevel(w, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	bw := &Writer{gz: gz}
+	if err := bw.writeRecord(recordMetadata, blob); err != nil {
+		return nil, err
+	}
+
+	return bw, nil
+}
+
+// writeRecord writes the kind of the record, and its length prefixed fields
+func (w *Writer) writeRecord(kind byte, fields ...[]byte) error {
+	w.buf = append(w.buf[:0], kind)
+	for _, field := range fields {
+		w.buf = appendUvarint(w.buf, uint64(len(field)))
+		w.buf = append(w.buf, field...)
+	}
+
+	_, err := w.gz.Write(w.buf)
+
+	return err
+}
+
+// WriteEntry writes an entry of the data store
+func (w *Writer) WriteEntry(store string, k, v []byte) error {
+	w.entries++
+
+	return w.writeRecord(recordEntry, []byte(store), k, v)
+}
+
+// WriteFile writes the file of the data directory, with the given size, read from r
+func (w *Writer) WriteFile(name string, size int64, r io.Reader) error {
+	w.buf = append(w.buf[:0], recordFile)
+	w.buf = appendUvarint(w.buf, uint64(len(name)))
+	w.buf = append(w.buf, name...)
+	w.buf = appendUvarint(w.buf, uint64(size))
+	if _, err := w.gz.Write(w.buf); err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(w.gz, r, size); err != nil {
+		return err
+	}
+	w.files++
+
+	return nil
+}
+
+// Close writes the number of entries and files, which are checked by the restore,
+// and flushes the stream. It doesn't close the underlying writer
+func (w *Writer) Close() error {
+	end := appendUvarint(nil, w.entries)
+	end = appendUvarint(end, w.files)
+
+	if err := w.writeRecord(recordEnd, end); err != nil {
+		return err
+	}
+
+	return w.gz.Close()
+}
+
+// Reader reads the records of a backup
+type Reader struct {
+	r        *bufio.Reader
+	Metadata *Metadata
+}
+
+// NewReader reads the header and the metadata of the backup
+func NewReader(r io.Reader) (*Reader, error) {
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != string(magic) {
+		return nil, ErrNotBackup
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	br := &Reader{r: bufio.NewReader(gz)}
+
+	kind, err := br.r.ReadByte()
+	if err != nil {
+		return nil, ErrTruncated
+	}
+	if kind != recordMetadata {
+		return nil, ErrNotBackup
+	}
+	blob, err := br.readField()
+	if err != nil {
+		return nil, err
+	}
+
+	br.Metadata = &Metadata{}
+	if err := json.Unmarshal(blob, br.Metadata); err != nil {
+		return nil, err
+	}
+	if br.Metadata.Version != Version {
+		return nil, fmt.Errorf("%w %d, expected %d", ErrVersion, br.Metadata.Version, Version)
+	}
+
+	return br, nil
+}
+
+func (r *Reader) readLength() (uint64, error) {
+	length, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return 0, ErrTruncated
+	}
+
+	return length, nil
+}
+
+func (r *Reader) readField() ([]byte, error) {
+	length, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+
+	field := make([]byte, length)
+	if _, err := io.ReadFull(r.r, field); err != nil {
+		return nil, ErrTruncated
+	}
+
+	return field, nil
+}
+
+// Result is the content restored from a backup
+type Result struct {
+	Metadata *Metadata
+
+	// Entries are the entries written to every data store
+	Entries map[string]uint64
+
+	// Files are the files written to the data directory
+	Files uint64
+}
+
+// Restore writes the data stores and the files of the backup into the data directory,
+// using the database backend. The stores placed outside of the data directory are restored
+// in their directories. The data stores must not exist yet. The data written
+// so far is removed if the backup can't be restored
+func Restore(r io.Reader, dataDir, backend string) (*Result, error) {
+	dirs, err := common.ReadStoreDirs(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	br, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Metadata: br.Metadata,
+		Entries:  map[string]uint64{},
+	}
+
+	created := []string{}
+	dbs := map[string]kvdb.Database{}
+	batches := map[string]kvdb.Batch{}
+
+	restoreErr := func() error {
+		for _, store := range br.Metadata.Stores {
+			path := dirs.Dir(dataDir, store)
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%w %s", ErrStoreExists, store)
+			}
+
+			created = append(created, path)
+			db, err := kvdb.Open(backend, path)
+			if err != nil {
+				return err
+			}
+			dbs[store] = db
+			batches[store] = db.NewBatch()
+		}
+
+		for {
+			kind, err := br.r.ReadByte()
+			if err != nil {
+				return ErrTruncated
+			}
+
+			switch kind {
+			case recordEntry:
+				fields := make([][]byte, 3)
+				for i := range fields {
+					if fields[i], err = br.readField(); err != nil {
+						return err
+					}
+				}
+
+				store := string(fields[0])
+				batch, ok := batches[store]
+				if !ok {
+					return fmt.Errorf("entry of the store %s, which is not in the metadata", store)
+				}
+				batch.Put(fields[1], fields[2])
+				res.Entries[store]++
+
+				if batch.Len() >= restoreBatchSize {
+					if err := batch.Write(); err != nil {
+						return err
+					}
+					batches[store] = dbs[store].NewBatch()
+				}
+
+			case recordFile:
+				name, err := br.readField()
+				if err != nil {
+					return err
+				}
+				size, err := br.readLength()
+				if err != nil {
+					return err
+				}
+
+				path, err := filePath(dataDir, dirs, string(name))
+				if err != nil {
+					return err
+				}
+				if _, err := os.Stat(path); err == nil {
+					return fmt.Errorf("the data directory already has the file %s", name)
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return err
+				}
+				created = append(created, path)
+
+				if err := restoreFile(path, io.LimitReader(br.r, int64(size)), int64(size)); err != nil {
+					return err
+				}
+				res.Files++
+
+			case recordEnd:
+				end, err := br.readField()
+				if err != nil {
+					return err
+				}
+				if err := checkEnd(end, res); err != nil {
+					return err
+				}
+
+				// the checksum of the gzip stream is only verified at its end
+				if _, err := io.Copy(ioutil.Discard, br.r); err != nil {
+					return fmt.Errorf("%w: %v", ErrTruncated, err)
+				}
+
+				for _, batch := range batches {
+					if err := batch.Write(); err != nil {
+						return err
+					}
+				}
+
+				return nil
+
+			default:
+				return fmt.Errorf("unknown record kind %d", kind)
+			}
+		}
+	}()
+
+	for _, db := range dbs {
+		if err := db.Close(); err != nil && restoreErr == nil {
+			restoreErr = err
+		}
+	}
+
+	if restoreErr != nil {
+		for _, path := range created {
+			os.RemoveAll(path)
+		}
+
+		return nil, restoreErr
+	}
+
+	return res, nil
+}
+
+// filePath returns the path of the file of the backup in the data directory, or in the directory
+// of its store if it is placed outside of it. The files can't be written anywhere else
+func filePath(dataDir string, dirs common.StoreDirs, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("the file %s is outside of the data directory", name)
+	}
+
+	parts := strings.SplitN(clean, string(filepath.Separator), 2)
+	if len(parts) == 2 {
+		return filepath.Join(dirs.Dir(dataDir, parts[0]), parts[1]), nil
+	}
+
+	return filepath.Join(dataDir, clean), nil
+}
+
+func restoreFile(path string, r io.Reader, size int64) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(f, r, size); err != nil {
+		f.Close()
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// checkEnd checks that the restored entries and files are the ones the backup was written with
+func checkEnd(end []byte, res *Result) error {
+	entries, n := binary.Uvarint(end)
+	if n <= 0 {
+		return ErrTruncated
+	}
+	files, m := binary.Uvarint(end[n:])
+	if m <= 0 {
+		return ErrTruncated
+	}
+
+	restored := uint64(0)
+	for _, count := range res.Entries {
+		restored += count
+	}
+	if restored != entries || res.Files != files {
+		return fmt.Errorf("%w: %d entries and %d files restored, %d and %d written", ErrTruncated, restored, res.Files, entries, files)
+	}
+
+	return nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var enc [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(enc[:], v)
+
+	return append(buf, enc[:n]...)
+}
@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDir(t *testing.T) string {
+	path, err := ioutil.TempDir("", "backup")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(path)
+	})
+
+	return path
+}
+
+// writeTestBackup writes a backup with two stores and a file
+func writeTestBackup(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, &Metadata{
+		Version:    Version,
+		HeadNumber: 10,
+		HeadHash:   "0x01",
+		Stores:     []string{"blockchain", "trie"},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.WriteEntry("blockchain", []byte("a"), []byte("1")))
+	assert.NoError(t, w.WriteEntry("blockchain", []byte("b"), []byte("2")))
+	assert.NoError(t, w.WriteEntry("trie", []byte("c"), []byte{}))
+	assert.NoError(t, w.WriteFile("ancient/headers.dat", 4, strings.NewReader("data")))
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func readStore(t *testing.T, path string) []string {
+	db, err := kvdb.OpenLevelDB(path)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	entries := []string{}
+
+	iter := db.NewIterator(nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		entries = append(entries, string(iter.Key())+"="+string(iter.Value()))
+	}
+
+	return entries
+}
+
+func TestRestore(t *testing.T) {
+	dataDir := newTestDir(t)
+
+	res, err := Restore(bytes.NewReader(writeTestBackup(t)), dataDir, kvdb.BackendLevelDB)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(10), res.Metadata.HeadNumber)
+	assert.Equal(t, map[string]uint64{"blockchain": 2, "trie": 1}, res.Entries)
+	assert.Equal(t, uint64(1), res.Files)
+
+	assert.Equal(t, []string{"a=1", "b=2"}, readStore(t, filepath.Join(dataDir, "blockchain")))
+	assert.Equal(t, []string{"c="}, readStore(t, filepath.Join(dataDir, "trie")))
+
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, "ancient", "headers.dat"))
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	// the stores are not overwritten
+	_, err = Restore(bytes.NewReader(writeTestBackup(t)), dataDir, kvdb.BackendLevelDB)
+	assert.ErrorIs(t, err, ErrStoreExists)
+	assert.Equal(t, []string{"a=1", "b=2"}, readStore(t, filepath.Join(dataDir, "blockchain")))
+}
+
+func TestRestore_StoreDirs(t *testing.T) {
+	dataDir, volume := newTestDir(t), newTestDir(t)
+
+	// the blocks and the freezer are placed on another volume
+	dirs := common.StoreDirs{
+		"blockchain": filepath.Join(volume, "chain"),
+		"ancient":    filepath.Join(volume, "ancient"),
+	}
+	assert.NoError(t, common.WriteStoreDirs(dataDir, dirs))
+
+	_, err := Restore(bytes.NewReader(writeTestBackup(t)), dataDir, kvdb.BackendLevelDB)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a=1", "b=2"}, readStore(t, filepath.Join(volume, "chain")))
+	assert.Equal(t, []string{"c="}, readStore(t, filepath.Join(dataDir, "trie")))
+
+	_, err = os.Stat(filepath.Join(volume, "ancient", "headers.dat"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dataDir, "blockchain"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRestore_Truncated(t *testing.T) {
+	blob := writeTestBackup(t)
+
+	for _, size := range []int{0, len(magic) + 10, len(blob) - 10} {
+		dataDir := newTestDir(t)
+
+		_, err := Restore(bytes.NewReader(blob[:size]), dataDir, kvdb.BackendLevelDB)
+		assert.Error(t, err, size)
+
+		// the partially restored stores are removed
+		entries, err := ioutil.ReadDir(dataDir)
+		assert.NoError(t, err)
+		for _, entry := range entries {
+			assert.Equal(t, "ancient", entry.Name())
+		}
+	}
+}
+
+func TestRestore_FileOutsideDataDir(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, &Metadata{Version: Version})
+	assert.NoError(t, err)
+	assert.NoError(t, w.WriteFile("../secrets", 1, strings.NewReader("s")))
+	assert.NoError(t, w.Close())
+
+	dataDir := newTestDir(t)
+
+	_, err = Restore(&buf, filepath.Join(dataDir, "node"), kvdb.BackendLevelDB)
+	assert.Error(t, err)
+
+	_, err = os.Stat(filepath.Join(dataDir, "secrets"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewReader_Version(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, &Metadata{Version: Version + 1})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	_, err = NewReader(&buf)
+	assert.ErrorIs(t, err, ErrVersion)
+
+	_, err = NewReader(strings.NewReader("not a backup"))
+	assert.Equal(t, ErrNotBackup, err)
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// StoreDirsFile is the file of the data directory recording the data stores placed outside of it,
+// so the commands given the data directory find them
+const StoreDirsFile = "stores.json"
+
+// StoreDirs are the directories of the data stores placed outside of the data directory, by store name
+type StoreDirs map[string]string
+
+// Dir returns the directory of the data store, in the data directory unless it is placed elsewhere
+func (s StoreDirs) Dir(dataDir, name string) string {
+	if dir, ok := s[name]; ok && dir != "" {
+		return dir
+	}
+
+	return filepath.Join(dataDir, name)
+}
+
+// ReadStoreDirs returns the directories of the data stores recorded in the data directory,
+// none if they are all in it
+func ReadStoreDirs(dataDir string) (StoreDirs, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, StoreDirsFile))
+	if os.IsNotExist(err) {
+		return StoreDirs{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := StoreDirs{}
+	if err := json.Unmarshal(data, &dirs); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", StoreDirsFile, err)
+	}
+
+	return dirs, nil
+}
+
+// StoreDir returns the directory of the data store of the data directory, following the recorded directories
+func StoreDir(dataDir, name string) (string, error) {
+	dirs, err := ReadStoreDirs(dataDir)
+	if err != nil {
+		return "", err
+	}
+
+	return dirs.Dir(dataDir, name), nil
+}
+
+// WriteStoreDirs records the directories of the data stores placed outside of the data directory,
+// the record is removed once they are all in it
+func WriteStoreDirs(dataDir string, dirs StoreDirs) error {
+	path := filepath.Join(dataDir, StoreDirsFile)
+
+	if len(dirs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	data, err := json.MarshalIndent(dirs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,147 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// KeySize is the size of the AES-256 keys
+const KeySize = 32
+
+var (
+	// canaryKey is the entry written in the encrypted stores, to detect a wrong key
+	// or a store that is opened with a different encryption setting than the one it was created with
+	canaryKey   = []byte("encryption-canary")
+	canaryValue = []byte("polygon-sdk")
+)
+
+var (
+	ErrWrongKey     = errors.New("the store can't be decrypted with the storage key")
+	ErrNotEncrypted = errors.New("the store is not encrypted, the encryption can't be enabled on an existing store")
+	ErrEncrypted    = errors.New("the store is encrypted, the encryption must be enabled to open it")
+)
+
+// Cipher encrypts the values of a key-value store with AES-GCM.
+// The keys of the entries are left in clear, as the lookups need them,
+// but they are authenticated with the values so an encrypted value can't be moved to another key
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher with an AES-256 key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("the storage key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// GenerateAndEncodeKey generates a new storage key, and encodes it into hex
+func GenerateAndEncodeKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	return []byte(hex.EncodeToString(key)), nil
+}
+
+// ParseKey converts the hex encoded storage key into a cipher
+func ParseKey(encoded []byte) (*Cipher, error) {
+	key, err := hex.DecodeString(string(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCipher(key)
+}
+
+// Seal encrypts the value of the entry, the random nonce is prepended to the result
+func (c *Cipher) Seal(key, value []byte) []byte {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(value)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		panic(fmt.Errorf("failed to read a random nonce: %v", err))
+	}
+
+	return c.aead.Seal(nonce, nonce, value, key)
+}
+
+// Open decrypts the value of the entry
+func (c *Cipher) Open(key, data []byte) ([]byte, error) {
+	if len(data) < c.aead.NonceSize() {
+		return nil, ErrWrongKey
+	}
+
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+
+	value, err := c.aead.Open(nil, nonce, ciphertext, key)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+
+	return value, nil
+}
+
+// CheckLevelDB checks the encryption of the leveldb store, see CheckDatabase
+func CheckLevelDB(db *leveldb.DB, c *Cipher) error {
+	return CheckDatabase(kvdb.NewLevelDB(db), c)
+}
+
+// CheckDatabase checks that the store is opened with the same encryption setting,
+// and the same key, it was created with. The cipher is nil if the encryption is disabled
+func CheckDatabase(db kvdb.Database, c *Cipher) error {
+	canary, ok, err := db.Get(canaryKey)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if c == nil {
+			return ErrEncrypted
+		}
+
+		value, err := c.Open(canaryKey, canary)
+		if err != nil || string(value) != string(canaryValue) {
+			return ErrWrongKey
+		}
+
+		return nil
+	}
+
+	if c == nil {
+		return nil
+	}
+
+	// the canary is missing, the store must be a new one
+	iter := db.NewIterator(nil)
+	empty := !iter.Next()
+	iter.Release()
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if !empty {
+		return ErrNotEncrypted
+	}
+
+	return db.Put(canaryKey, c.Seal(canaryKey, canaryValue))
+}
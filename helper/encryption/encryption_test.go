@@ -0,0 +1,93 @@
+package encryption
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+
+	key, err := GenerateAndEncodeKey()
+	assert.NoError(t, err)
+
+	c, err := ParseKey(key)
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestCipher(t *testing.T) {
+	c := newTestCipher(t)
+
+	key, value := []byte("key"), []byte("value")
+
+	data := c.Seal(key, value)
+	assert.NotContains(t, string(data), string(value))
+
+	// the nonces are random
+	assert.NotEqual(t, data, c.Seal(key, value))
+
+	opened, err := c.Open(key, data)
+	assert.NoError(t, err)
+	assert.Equal(t, value, opened)
+
+	// the value is bound to its key
+	_, err = c.Open([]byte("other"), data)
+	assert.Equal(t, ErrWrongKey, err)
+
+	// the value can't be opened with another key
+	_, err = newTestCipher(t).Open(key, data)
+	assert.Equal(t, ErrWrongKey, err)
+
+	// the value is truncated
+	_, err = c.Open(key, data[:4])
+	assert.Equal(t, ErrWrongKey, err)
+
+	_, err = NewCipher([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestCheckLevelDB(t *testing.T) {
+	open := func(t *testing.T) *leveldb.DB {
+		path, err := ioutil.TempDir("", "encryption")
+		assert.NoError(t, err)
+
+		db, err := leveldb.OpenFile(path, nil)
+		assert.NoError(t, err)
+
+		t.Cleanup(func() {
+			db.Close()
+			os.RemoveAll(path)
+		})
+
+		return db
+	}
+
+	c := newTestCipher(t)
+
+	t.Run("encrypted store", func(t *testing.T) {
+		db := open(t)
+
+		// the canary is written in the new store
+		assert.NoError(t, CheckLevelDB(db, c))
+		assert.NoError(t, CheckLevelDB(db, c))
+
+		assert.Equal(t, ErrWrongKey, CheckLevelDB(db, newTestCipher(t)))
+		assert.Equal(t, ErrEncrypted, CheckLevelDB(db, nil))
+	})
+
+	t.Run("plain store", func(t *testing.T) {
+		db := open(t)
+
+		assert.NoError(t, CheckLevelDB(db, nil))
+		assert.NoError(t, db.Put([]byte("key"), []byte("value"), nil))
+
+		assert.NoError(t, CheckLevelDB(db, nil))
+		assert.Equal(t, ErrNotEncrypted, CheckLevelDB(db, c))
+	})
+}
@@ -31,6 +31,11 @@ func (i *BootnodeFlags) String() string {
 }
 
 func (i *BootnodeFlags) Set(value string) error {
+	// the enrtree:// urls of the DNS trees serving bootnodes are checked by the network
+	if strings.HasPrefix(value, "enrtree://") {
+		*i = append(*i, value)
+		return nil
+	}
 	if _, err := multiaddr.NewMultiaddr(value); err != nil {
 		return err
 	}
@@ -0,0 +1,194 @@
+package kvdb
+
+import (
+	"github.com/dgraph-io/badger/v2"
+)
+
+// badgerGCDiscardRatio is the ratio of stale entries a value log file must hold to be rewritten by Compact
+const badgerGCDiscardRatio = 0.5
+
+// badgerDB is the badger backend. Its tree only holds the keys and the small values, the larger values
+// are appended to a value log, so the compactions rewrite much less data than the leveldb ones
+type badgerDB struct {
+	db *badger.DB
+}
+
+// OpenBadger opens the badger database at the path, it is created if it doesn't exist
+func OpenBadger(path string) (Database, error) {
+	// the logger is disabled, the errors are returned to the callers
+	db, err := badger.Open(badger.DefaultOptions(path).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return &badgerDB{db: db}, nil
+}
+
+func (b *badgerDB) Get(k []byte) ([]byte, bool, error) {
+	var data []byte
+
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(k)
+		if err != nil {
+			return err
+		}
+		data, err = item.ValueCopy(nil)
+
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (b *badgerDB) Put(k, v []byte) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(k, v)
+	})
+}
+
+func (b *badgerDB) Delete(k []byte) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(k)
+	})
+}
+
+func (b *badgerDB) NewBatch() Batch {
+	return &badgerBatch{db: b.db}
+}
+
+// NewIterator iterates over the entries of a read transaction, which sees a snapshot of the database
+func (b *badgerDB) NewIterator(prefix []byte) Iterator {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+
+	txn := b.db.NewTransaction(false)
+
+	return &badgerIterator{
+		txn:    txn,
+		iter:   txn.NewIterator(opts),
+		prefix: prefix,
+	}
+}
+
+// Compact merges the levels of the tree, and rewrites the value log files holding the most stale entries
+func (b *badgerDB) Compact() error {
+	if err := b.db.Flatten(1); err != nil {
+		return err
+	}
+
+	for {
+		err := b.db.RunValueLogGC(badgerGCDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (b *badgerDB) Close() error {
+	return b.db.Close()
+}
+
+type badgerBatch struct {
+	db     *badger.DB
+	writes []memoryWrite
+}
+
+func (b *badgerBatch) Put(k, v []byte) {
+	b.writes = append(b.writes, memoryWrite{key: append([]byte{}, k...), value: append([]byte{}, v...)})
+}
+
+func (b *badgerBatch) Delete(k []byte) {
+	b.writes = append(b.writes, memoryWrite{key: append([]byte{}, k...), delete: true})
+}
+
+func (b *badgerBatch) Len() int {
+	return len(b.writes)
+}
+
+// Write applies the writes in a single transaction. A batch too large for a badger transaction
+// is split across several ones, and is then not applied atomically
+func (b *badgerBatch) Write() error {
+	txn := b.db.NewTransaction(true)
+	defer func() {
+		txn.Discard()
+	}()
+
+	for _, w := range b.writes {
+		err := b.apply(txn, w)
+		if err == badger.ErrTxnTooBig {
+			if err := txn.Commit(); err != nil {
+				return err
+			}
+			txn = b.db.NewTransaction(true)
+			err = b.apply(txn, w)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return txn.Commit()
+}
+
+func (b *badgerBatch) apply(txn *badger.Txn, w memoryWrite) error {
+	if w.delete {
+		return txn.Delete(w.key)
+	}
+
+	return txn.Set(w.key, w.value)
+}
+
+type badgerIterator struct {
+	txn     *badger.Txn
+	iter    *badger.Iterator
+	prefix  []byte
+	started bool
+	value   []byte
+	err     error
+}
+
+func (i *badgerIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	if i.started {
+		i.iter.Next()
+	} else {
+		i.iter.Seek(i.prefix)
+		i.started = true
+	}
+	if !i.iter.ValidForPrefix(i.prefix) {
+		return false
+	}
+
+	i.value, i.err = i.iter.Item().ValueCopy(i.value[:0])
+
+	return i.err == nil
+}
+
+func (i *badgerIterator) Key() []byte {
+	return i.iter.Item().Key()
+}
+
+func (i *badgerIterator) Value() []byte {
+	return i.value
+}
+
+func (i *badgerIterator) Release() {
+	i.iter.Close()
+	i.txn.Discard()
+}
+
+func (i *badgerIterator) Error() error {
+	return i.err
+}
@@ -0,0 +1,129 @@
+package kvdb
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	BackendLevelDB = "leveldb"
+	BackendBadger  = "badger"
+)
+
+// copyBatchSize is the number of entries written at once by Copy
+const copyBatchSize = 10000
+
+var (
+	ErrUnknownBackend = errors.New("unknown database backend, expected leveldb or badger")
+	ErrNotEmpty       = errors.New("the destination database is not empty")
+)
+
+// Database is a key-value database, the storages of the chain and of the state are built on top of it
+type Database interface {
+	// Get returns the value of the key, and whether it was found
+	Get(k []byte) ([]byte, bool, error)
+
+	// Put sets the value of the key
+	Put(k, v []byte) error
+
+	// Delete removes the key, it is not an error if it is missing
+	Delete(k []byte) error
+
+	// NewBatch creates a batch of writes applied at once
+	NewBatch() Batch
+
+	// NewIterator iterates over the keys with the prefix, in ascending order. It reads
+	// a snapshot of the database, the writes made once it is created are not seen
+	NewIterator(prefix []byte) Iterator
+
+	// Compact reclaims the space of the deleted and overwritten entries
+	Compact() error
+
+	Close() error
+}
+
+// Stats are the statistics of a database since it was opened, except the size
+type Stats struct {
+	// Size is the size of the tables on disk, in bytes
+	Size uint64
+
+	// ReadBytes and WrittenBytes are the bytes read from and written to disk
+	ReadBytes    uint64
+	WrittenBytes uint64
+
+	// WriteDelay is the time the writes were stalled by the compactions
+	WriteDelay time.Duration
+}
+
+// StatsReporter is implemented by the databases reporting their statistics
+type StatsReporter interface {
+	Stats() (*Stats, error)
+}
+
+// Batch is a set of writes applied at once
+type Batch interface {
+	Put(k, v []byte)
+	Delete(k []byte)
+
+	// Len returns the number of writes in the batch
+	Len() int
+
+	Write() error
+}
+
+// Iterator iterates over the entries of a database. The key and the value
+// are only valid until the next call to Next
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Release()
+	Error() error
+}
+
+// Open opens the database of the backend at the path
+func Open(backend, path string) (Database, error) {
+	switch backend {
+	case "", BackendLevelDB:
+		return OpenLevelDB(path)
+
+	case BackendBadger:
+		return OpenBadger(path)
+	}
+
+	return nil, ErrUnknownBackend
+}
+
+// Copy writes every entry of the source database into the empty destination database,
+// and returns the number of entries copied
+func Copy(dst, src Database) (uint64, error) {
+	dstIter := dst.NewIterator(nil)
+	empty := !dstIter.Next()
+	dstIter.Release()
+	if !empty {
+		return 0, ErrNotEmpty
+	}
+
+	iter := src.NewIterator(nil)
+	defer iter.Release()
+
+	var count uint64
+
+	batch := dst.NewBatch()
+	for iter.Next() {
+		batch.Put(append([]byte{}, iter.Key()...), append([]byte{}, iter.Value()...))
+		count++
+
+		if batch.Len() >= copyBatchSize {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch = dst.NewBatch()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return count, err
+	}
+
+	return count, batch.Write()
+}
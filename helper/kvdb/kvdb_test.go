@@ -0,0 +1,146 @@
+package kvdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testBackendMemory opens the memory database, which is not a backend of Open
+const testBackendMemory = "memory"
+
+func newTestDatabase(t *testing.T, backend string) Database {
+	if backend == testBackendMemory {
+		return NewMemoryDatabase()
+	}
+
+	path, err := ioutil.TempDir("", "kvdb")
+	assert.NoError(t, err)
+
+	db, err := Open(backend, path)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(path)
+	})
+
+	return db
+}
+
+func readAll(t *testing.T, db Database, prefix []byte) []string {
+	entries := []string{}
+
+	iter := db.NewIterator(prefix)
+	defer iter.Release()
+
+	for iter.Next() {
+		entries = append(entries, string(iter.Key())+"="+string(iter.Value()))
+	}
+	assert.NoError(t, iter.Error())
+
+	return entries
+}
+
+func TestDatabase(t *testing.T) {
+	for _, backend := range []string{BackendLevelDB, BackendBadger, testBackendMemory} {
+		t.Run(backend, func(t *testing.T) {
+			db := newTestDatabase(t, backend)
+
+			_, ok, err := db.Get([]byte("a"))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.NoError(t, db.Put([]byte("a"), []byte("1")))
+
+			v, ok, err := db.Get([]byte("a"))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("1"), v)
+
+			batch := db.NewBatch()
+			batch.Put([]byte("b2"), []byte("3"))
+			batch.Put([]byte("b1"), []byte("2"))
+			batch.Put([]byte("c"), []byte("4"))
+			batch.Delete([]byte("a"))
+			assert.Equal(t, 4, batch.Len())
+
+			// the batch is applied once written
+			assert.Equal(t, []string{"a=1"}, readAll(t, db, nil))
+			assert.NoError(t, batch.Write())
+
+			assert.Equal(t, []string{"b1=2", "b2=3", "c=4"}, readAll(t, db, nil))
+			assert.Equal(t, []string{"b1=2", "b2=3"}, readAll(t, db, []byte("b")))
+
+			assert.NoError(t, db.Delete([]byte("c")))
+			assert.NoError(t, db.Delete([]byte("missing")))
+			assert.NoError(t, db.Compact())
+			assert.Equal(t, []string{"b1=2", "b2=3"}, readAll(t, db, nil))
+		})
+	}
+
+	_, err := Open(testBackendMemory, "")
+	assert.Equal(t, ErrUnknownBackend, err)
+}
+
+func TestCopy(t *testing.T) {
+	src := newTestDatabase(t, BackendLevelDB)
+	for _, k := range []string{"a", "b", "c"} {
+		assert.NoError(t, src.Put([]byte(k), []byte(k)))
+	}
+
+	dst := newTestDatabase(t, BackendBadger)
+
+	count, err := Copy(dst, src)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+	assert.Equal(t, readAll(t, src, nil), readAll(t, dst, nil))
+
+	// the entries are never merged into an existing database
+	_, err = Copy(dst, src)
+	assert.Equal(t, ErrNotEmpty, err)
+}
+
+func TestBadger_LargeBatch(t *testing.T) {
+	db := newTestDatabase(t, BackendBadger)
+
+	// the batch is larger than a badger transaction
+	value := make([]byte, 512)
+	batch := db.NewBatch()
+	for i := 0; i < 30000; i++ {
+		batch.Put([]byte(fmt.Sprintf("%05d", i)), value)
+	}
+	assert.NoError(t, batch.Write())
+
+	count := 0
+	iter := db.NewIterator(nil)
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	assert.Equal(t, 30000, count)
+}
+
+func TestStats(t *testing.T) {
+	db := newTestDatabase(t, BackendLevelDB)
+	for _, k := range []string{"a", "b", "c"} {
+		assert.NoError(t, db.Put([]byte(k), []byte(k)))
+	}
+	// the compaction flushes the entries to the tables on disk
+	assert.NoError(t, db.Compact())
+
+	reporter, ok := db.(StatsReporter)
+	assert.True(t, ok)
+
+	stats, err := reporter.Stats()
+	assert.NoError(t, err)
+	assert.NotZero(t, stats.Size)
+	assert.NotZero(t, stats.WrittenBytes)
+
+	// the memory backend doesn't report statistics
+	_, ok = newTestDatabase(t, testBackendMemory).(StatsReporter)
+	assert.False(t, ok)
+}
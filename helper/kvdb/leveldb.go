@@ -0,0 +1,111 @@
+package kvdb
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// levelDBOptions reduces the write amplification of the block import. The larger write buffer
+// and tables lower the number of compactions, and the compactions triggered by the reads of
+// the random trie keys are disabled
+var levelDBOptions = &opt.Options{
+	WriteBuffer:            64 * opt.MiB,
+	CompactionTableSize:    8 * opt.MiB,
+	BlockCacheCapacity:     32 * opt.MiB,
+	Filter:                 filter.NewBloomFilter(10),
+	DisableSeeksCompaction: true,
+}
+
+// levelDB is the leveldb backend
+type levelDB struct {
+	db *leveldb.DB
+}
+
+// OpenLevelDB opens the leveldb database at the path, it is created if it doesn't exist
+func OpenLevelDB(path string) (Database, error) {
+	db, err := leveldb.OpenFile(path, levelDBOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLevelDB(db), nil
+}
+
+// NewLevelDB wraps an opened leveldb database
+func NewLevelDB(db *leveldb.DB) Database {
+	return &levelDB{db: db}
+}
+
+func (l *levelDB) Get(k []byte) ([]byte, bool, error) {
+	data, err := l.db.Get(k, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (l *levelDB) Put(k, v []byte) error {
+	return l.db.Put(k, v, nil)
+}
+
+func (l *levelDB) Delete(k []byte) error {
+	return l.db.Delete(k, nil)
+}
+
+func (l *levelDB) NewBatch() Batch {
+	return &levelDBBatch{db: l.db, batch: &leveldb.Batch{}}
+}
+
+func (l *levelDB) NewIterator(prefix []byte) Iterator {
+	return l.db.NewIterator(util.BytesPrefix(prefix), nil)
+}
+
+func (l *levelDB) Compact() error {
+	return l.db.CompactRange(util.Range{})
+}
+
+// Stats implements the StatsReporter interface
+func (l *levelDB) Stats() (*Stats, error) {
+	stats := &leveldb.DBStats{}
+	if err := l.db.Stats(stats); err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Size:         uint64(stats.LevelSizes.Sum()),
+		ReadBytes:    stats.IORead,
+		WrittenBytes: stats.IOWrite,
+		WriteDelay:   stats.WriteDelayDuration,
+	}, nil
+}
+
+func (l *levelDB) Close() error {
+	return l.db.Close()
+}
+
+type levelDBBatch struct {
+	db    *leveldb.DB
+	batch *leveldb.Batch
+}
+
+func (b *levelDBBatch) Put(k, v []byte) {
+	b.batch.Put(k, v)
+}
+
+func (b *levelDBBatch) Delete(k []byte) {
+	b.batch.Delete(k)
+}
+
+func (b *levelDBBatch) Len() int {
+	return b.batch.Len()
+}
+
+func (b *levelDBBatch) Write() error {
+	return b.db.Write(b.batch, nil)
+}
@@ -0,0 +1,160 @@
+package kvdb
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// memoryDatabase is the memory backend, its entries are lost once it is closed
+type memoryDatabase struct {
+	lock sync.RWMutex
+	db   map[string][]byte
+}
+
+// NewMemoryDatabase creates an empty memory database
+func NewMemoryDatabase() Database {
+	return &memoryDatabase{db: map[string][]byte{}}
+}
+
+func (m *memoryDatabase) Get(k []byte) ([]byte, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	v, ok := m.db[string(k)]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return append([]byte{}, v...), true, nil
+}
+
+func (m *memoryDatabase) Put(k, v []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.db[string(k)] = append([]byte{}, v...)
+
+	return nil
+}
+
+func (m *memoryDatabase) Delete(k []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.db, string(k))
+
+	return nil
+}
+
+func (m *memoryDatabase) NewBatch() Batch {
+	return &memoryBatch{db: m}
+}
+
+// NewIterator iterates over a snapshot of the entries taken when it is created
+func (m *memoryDatabase) NewIterator(prefix []byte) Iterator {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	iter := &memoryIterator{index: -1}
+	for k, v := range m.db {
+		if bytes.HasPrefix([]byte(k), prefix) {
+			iter.keys = append(iter.keys, k)
+			iter.values = append(iter.values, v)
+		}
+	}
+	sort.Sort(iter)
+
+	return iter
+}
+
+func (m *memoryDatabase) Compact() error {
+	return nil
+}
+
+func (m *memoryDatabase) Close() error {
+	return nil
+}
+
+type memoryWrite struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+type memoryBatch struct {
+	db     *memoryDatabase
+	writes []memoryWrite
+}
+
+func (b *memoryBatch) Put(k, v []byte) {
+	b.writes = append(b.writes, memoryWrite{key: append([]byte{}, k...), value: append([]byte{}, v...)})
+}
+
+func (b *memoryBatch) Delete(k []byte) {
+	b.writes = append(b.writes, memoryWrite{key: append([]byte{}, k...), delete: true})
+}
+
+func (b *memoryBatch) Len() int {
+	return len(b.writes)
+}
+
+func (b *memoryBatch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	for _, w := range b.writes {
+		if w.delete {
+			delete(b.db.db, string(w.key))
+		} else {
+			b.db.db[string(w.key)] = w.value
+		}
+	}
+
+	return nil
+}
+
+type memoryIterator struct {
+	keys   []string
+	values [][]byte
+	index  int
+}
+
+func (i *memoryIterator) Len() int {
+	return len(i.keys)
+}
+
+func (i *memoryIterator) Less(a, b int) bool {
+	return i.keys[a] < i.keys[b]
+}
+
+func (i *memoryIterator) Swap(a, b int) {
+	i.keys[a], i.keys[b] = i.keys[b], i.keys[a]
+	i.values[a], i.values[b] = i.values[b], i.values[a]
+}
+
+func (i *memoryIterator) Next() bool {
+	if i.index+1 >= len(i.keys) {
+		i.index = len(i.keys)
+		return false
+	}
+	i.index++
+
+	return true
+}
+
+func (i *memoryIterator) Key() []byte {
+	return []byte(i.keys[i.index])
+}
+
+func (i *memoryIterator) Value() []byte {
+	return i.values[i.index]
+}
+
+func (i *memoryIterator) Release() {
+	i.keys, i.values = nil, nil
+}
+
+func (i *memoryIterator) Error() error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package kvdb
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/hashicorp/go-hclog"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the database metrics
+type Metrics struct {
+	// Size of the tables on disk, by database
+	Size metrics.Gauge
+	// Bytes read from disk since the database was opened, by database
+	ReadBytes metrics.Gauge
+	// Bytes written to disk since the database was opened, by database
+	WrittenBytes metrics.Gauge
+	// Time the writes were stalled by the compactions, by database
+	WriteDelay metrics.Gauge
+}
+
+// GetPrometheusMetrics return the database metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+	labels = append(labels, "db")
+
+	return &Metrics{
+		Size: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "size_bytes",
+			Help:      "Size of the tables on disk in bytes.",
+		}, labels).With(labelsWithValues...),
+		ReadBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "read_bytes",
+			Help:      "Bytes read from disk since the database was opened.",
+		}, labels).With(labelsWithValues...),
+		WrittenBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "written_bytes",
+			Help:      "Bytes written to disk since the database was opened.",
+		}, labels).With(labelsWithValues...),
+		WriteDelay: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "write_delay",
+			Help:      "Time the writes were stalled by the compactions in seconds.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational database metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		Size:         discard.NewGauge(),
+		ReadBytes:    discard.NewGauge(),
+		WrittenBytes: discard.NewGauge(),
+		WriteDelay:   discard.NewGauge(),
+	}
+}
+
+// report sets the metrics of the named database to its statistics
+func (m *Metrics) report(name string, stats *Stats) {
+	m.Size.With("db", name).Set(float64(stats.Size))
+	m.ReadBytes.With("db", name).Set(float64(stats.ReadBytes))
+	m.WrittenBytes.With("db", name).Set(float64(stats.WrittenBytes))
+	m.WriteDelay.With("db", name).Set(stats.WriteDelay.Seconds())
+}
+
+// ReportStats reports the statistics of the databases, by name, every interval until the returned
+// function is called. The databases not implementing StatsReporter are skipped
+func ReportStats(logger hclog.Logger, metrics *Metrics, dbs map[string]Database, interval time.Duration) func() {
+	closeCh := make(chan struct{})
+
+	go func() {
+		for {
+			for name, db := range dbs {
+				reporter, ok := db.(StatsReporter)
+				if !ok {
+					continue
+				}
+
+				stats, err := reporter.Stats()
+				if err != nil {
+					logger.Debug("failed to read the database stats", "db", name, "err", err)
+					continue
+				}
+				metrics.report(name, stats)
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-closeCh:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(closeCh)
+	}
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// LevelSpec is the default level of the loggers and the levels of the modules overriding it
+type LevelSpec struct {
+	// Default is the level of the modules not overridden, NoLevel if not set
+	Default hclog.Level
+
+	// Modules are the levels by module, the name of a logger relative to the root logger.
+	// A module covers its submodules, e.g. ibft covers ibft.acceptState
+	Modules map[string]hclog.Level
+}
+
+// ParseLevelSpec parses a comma separated list of the default level and the module=level overrides,
+// e.g. info,ibft=debug,network=warn
+func ParseLevelSpec(spec string) (*LevelSpec, error) {
+	res := &LevelSpec{
+		Default: hclog.NoLevel,
+		Modules: map[string]hclog.Level{},
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		module, levelStr := "", entry
+		if i := strings.Index(entry, "="); i != -1 {
+			module, levelStr = strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:])
+			if module == "" {
+				return nil, fmt.Errorf("no module in the log level '%s'", entry)
+			}
+		}
+
+		level := hclog.LevelFromString(levelStr)
+		if level == hclog.NoLevel {
+			return nil, fmt.Errorf("invalid log level '%s'", levelStr)
+		}
+
+		if module == "" {
+			res.Default = level
+		} else {
+			res.Modules[module] = level
+		}
+	}
+
+	return res, nil
+}
+
+// String returns the spec in the format of ParseLevelSpec, the modules sorted by name
+func (s *LevelSpec) String() string {
+	entries := []string{}
+	if s.Default != hclog.NoLevel {
+		entries = append(entries, s.Default.String())
+	}
+
+	modules := make([]string, 0, len(s.Modules))
+	for module := range s.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		entries = append(entries, module+"="+s.Modules[module].String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// level returns the level of the module, set by its closest overridden ancestor if any
+func (s *LevelSpec) level(module string) hclog.Level {
+	for {
+		if level, ok := s.Modules[module]; ok {
+			return level
+		}
+
+		i := strings.LastIndex(module, ".")
+		if i == -1 {
+			return s.Default
+		}
+		module = module[:i]
+	}
+}
+
+// Levels holds the level spec of the loggers, replaceable while they are in use
+type Levels struct {
+	spec atomic.Value // *LevelSpec
+}
+
+// NewLevels returns the levels of the spec. The default level is info if the spec doesn't set it
+func NewLevels(spec *LevelSpec) *Levels {
+	l := &Levels{}
+	l.spec.Store(&LevelSpec{Default: hclog.Info, Modules: map[string]hclog.Level{}})
+	l.Set(spec)
+
+	return l
+}
+
+// Spec returns the current level spec
+func (l *Levels) Spec() *LevelSpec {
+	return l.spec.Load().(*LevelSpec)
+}
+
+// Set replaces the level spec. The overrides of the modules are replaced as a whole,
+// the default level is kept if the spec doesn't set it
+func (l *Levels) Set(spec *LevelSpec) {
+	next := &LevelSpec{
+		Default: spec.Default,
+		Modules: make(map[string]hclog.Level, len(spec.Modules)),
+	}
+	if next.Default == hclog.NoLevel {
+		next.Default = l.Spec().Default
+	}
+
+	for module, level := range spec.Modules {
+		next.Modules[module] = level
+	}
+
+	l.spec.Store(next)
+}
+
+// enabled returns true if the module logs at the level
+func (l *Levels) enabled(module string, level hclog.Level) bool {
+	return level >= l.Spec().level(module)
+}
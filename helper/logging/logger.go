@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// FormatText writes the entries as human readable lines
+	FormatText = "text"
+
+	// FormatJSON writes the entries as JSON objects, one per line
+	FormatJSON = "json"
+)
+
+// New returns the root logger of the node, writing to stderr in the format.
+// The level of each logger is the level of its module in the levels, its name relative to the root logger
+func New(name string, format string, levels *Levels) hclog.Logger {
+	return newLogger(name, format, os.Stderr, levels)
+}
+
+func newLogger(name string, format string, output io.Writer, levels *Levels) hclog.Logger {
+	inner := hclog.New(&hclog.LoggerOptions{
+		Name:       name,
+		Level:      hclog.Trace,
+		Output:     output,
+		JSONFormat: format == FormatJSON,
+	})
+
+	return &logger{
+		Logger: inner,
+		levels: levels,
+		root:   name,
+	}
+}
+
+// logger drops the entries below the level of its module, the inner logger writes all the others
+type logger struct {
+	hclog.Logger
+
+	levels *Levels
+
+	// root is the name of the root logger, the module is the name relative to it
+	root   string
+	module string
+}
+
+// wrap returns the logger of the inner logger derived from this one
+func (l *logger) wrap(inner hclog.Logger) hclog.Logger {
+	module := strings.TrimPrefix(strings.TrimPrefix(inner.Name(), l.root), ".")
+
+	return &logger{
+		Logger: inner,
+		levels: l.levels,
+		root:   l.root,
+		module: module,
+	}
+}
+
+func (l *logger) Log(level hclog.Level, msg string, args ...interface{}) {
+	if l.levels.enabled(l.module, level) {
+		l.Logger.Log(level, msg, args...)
+	}
+}
+
+func (l *logger) Trace(msg string, args ...interface{}) {
+	l.Log(hclog.Trace, msg, args...)
+}
+
+func (l *logger) Debug(msg string, args ...interface{}) {
+	l.Log(hclog.Debug, msg, args...)
+}
+
+func (l *logger) Info(msg string, args ...interface{}) {
+	l.Log(hclog.Info, msg, args...)
+}
+
+func (l *logger) Warn(msg string, args ...interface{}) {
+	l.Log(hclog.Warn, msg, args...)
+}
+
+func (l *logger) Error(msg string, args ...interface{}) {
+	l.Log(hclog.Error, msg, args...)
+}
+
+func (l *logger) IsTrace() bool {
+	return l.levels.enabled(l.module, hclog.Trace)
+}
+
+func (l *logger) IsDebug() bool {
+	return l.levels.enabled(l.module, hclog.Debug)
+}
+
+func (l *logger) IsInfo() bool {
+	return l.levels.enabled(l.module, hclog.Info)
+}
+
+func (l *logger) IsWarn() bool {
+	return l.levels.enabled(l.module, hclog.Warn)
+}
+
+func (l *logger) IsError() bool {
+	return l.levels.enabled(l.module, hclog.Error)
+}
+
+func (l *logger) With(args ...interface{}) hclog.Logger {
+	return l.wrap(l.Logger.With(args...))
+}
+
+func (l *logger) Named(name string) hclog.Logger {
+	return l.wrap(l.Logger.Named(name))
+}
+
+func (l *logger) ResetNamed(name string) hclog.Logger {
+	return l.wrap(l.Logger.ResetNamed(name))
+}
+
+// SetLevel sets the level of the module of the logger
+func (l *logger) SetLevel(level hclog.Level) {
+	spec := l.levels.Spec()
+
+	next := &LevelSpec{
+		Default: spec.Default,
+		Modules: map[string]hclog.Level{},
+	}
+	for module, moduleLevel := range spec.Modules {
+		next.Modules[module] = moduleLevel
+	}
+
+	if l.module == "" {
+		next.Default = level
+	} else {
+		next.Modules[l.module] = level
+	}
+	l.levels.Set(next)
+}
+
+func (l *logger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+func (l *logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	level := hclog.Info
+	if opts != nil && opts.ForceLevel != hclog.NoLevel {
+		level = opts.ForceLevel
+	}
+
+	return &stdWriter{logger: l, level: level}
+}
+
+// stdWriter logs the lines written by a standard logger at a fixed level
+type stdWriter struct {
+	logger *logger
+	level  hclog.Level
+}
+
+func (w *stdWriter) Write(data []byte) (int, error) {
+	w.logger.Log(w.level, string(bytes.TrimRight(data, " \t\n")))
+
+	return len(data), nil
+}
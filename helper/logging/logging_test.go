@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	spec, err := ParseLevelSpec("INFO, ibft=debug,network.discovery=warn")
+	assert.NoError(t, err)
+	assert.Equal(t, hclog.Info, spec.Default)
+	assert.Equal(t, map[string]hclog.Level{"ibft": hclog.Debug, "network.discovery": hclog.Warn}, spec.Modules)
+	assert.Equal(t, "info,ibft=debug,network.discovery=warn", spec.String())
+
+	// the default level is optional
+	spec, err = ParseLevelSpec("txpool=trace")
+	assert.NoError(t, err)
+	assert.Equal(t, hclog.NoLevel, spec.Default)
+
+	for _, invalid := range []string{"verbose", "ibft=verbose", "=debug"} {
+		_, err := ParseLevelSpec(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLevelSpec_Level(t *testing.T) {
+	spec, err := ParseLevelSpec("warn,ibft=debug,ibft.acceptState=error")
+	assert.NoError(t, err)
+
+	assert.Equal(t, hclog.Warn, spec.level(""))
+	assert.Equal(t, hclog.Warn, spec.level("network"))
+	assert.Equal(t, hclog.Debug, spec.level("ibft"))
+	assert.Equal(t, hclog.Debug, spec.level("ibft.syncState"))
+	assert.Equal(t, hclog.Error, spec.level("ibft.acceptState"))
+	assert.Equal(t, hclog.Warn, spec.level("ibftx"))
+}
+
+func TestLogger_Levels(t *testing.T) {
+	spec, err := ParseLevelSpec("info,ibft=debug")
+	assert.NoError(t, err)
+	levels := NewLevels(spec)
+
+	buf := &bytes.Buffer{}
+	root := newLogger("polygon", FormatJSON, buf, levels)
+	ibft := root.Named("ibft").With("sequence", 1)
+	network := root.Named("network")
+
+	ibft.Debug("ibft debug")
+	network.Debug("network debug")
+	network.Info("network info")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	entry := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "ibft debug", entry["@message"])
+	assert.Equal(t, "polygon.ibft", entry["@module"])
+	assert.Equal(t, float64(1), entry["sequence"])
+
+	// the levels are replaced while the loggers are in use, the default level is kept
+	spec, err = ParseLevelSpec("network=debug")
+	assert.NoError(t, err)
+	levels.Set(spec)
+
+	assert.False(t, ibft.IsDebug())
+	assert.True(t, ibft.IsInfo())
+	assert.True(t, network.IsDebug())
+	assert.Equal(t, "info,network=debug", levels.Spec().String())
+
+	// setting the level of a logger overrides its module
+	network.Named("discovery").SetLevel(hclog.Error)
+	assert.Equal(t, "info,network=debug,network.discovery=error", levels.Spec().String())
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ServiceName is the name the node reports its spans under
+	ServiceName = "polygon-sdk"
+
+	// DefaultSampleRatio samples all the traces
+	DefaultSampleRatio = 1.0
+
+	// exportTimeout is the time given to the export of the buffered spans on shutdown
+	exportTimeout = 5 * time.Second
+)
+
+var errInvalidSampleRatio = errors.New("the sample ratio must be between 0 and 1")
+
+// Config is the configuration of the export of the traces to an OTLP collector
+type Config struct {
+	// Endpoint is the address of the collector (host:port)
+	Endpoint string
+
+	// Insecure disables the TLS of the connection to the collector
+	Insecure bool
+
+	// SampleRatio is the fraction of the traces exported, from 0 to 1.
+	// The spans follow the decision of their parent
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider, exporting the sampled spans to the collector in batches.
+// The returned function flushes the buffered spans and stops the export
+func Setup(config *Config) (func() error, error) {
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, errInvalidSampleRatio
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(config.Endpoint),
+	}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	// the client connects in the background, the node starts even if the collector is down
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// Tracer returns the named tracer of the global provider. Its spans are discarded
+// unless the provider was installed with Setup
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	_, err := Setup(&Config{Endpoint: "127.0.0.1:4317", SampleRatio: 1.5})
+	assert.Equal(t, errInvalidSampleRatio, err)
+
+	// the collector is dialed in the background, the setup doesn't wait for it
+	stop, err := Setup(&Config{Endpoint: "127.0.0.1:4317", Insecure: true, SampleRatio: 0})
+	assert.NoError(t, err)
+
+	// the span gets a trace but is dropped by the sampler, so nothing is exported
+	_, span := Tracer("test").Start(context.Background(), "test")
+	assert.True(t, span.SpanContext().IsValid())
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+
+	assert.NoError(t, stop())
+}
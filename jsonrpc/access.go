@@ -0,0 +1,164 @@
+package jsonrpc
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// AccessConfig defines which namespaces are exposed on every transport,
+// and which of them can only be accessed with an authorization token
+type AccessConfig struct {
+	// Namespaces maps a transport ("http", "ws", "ipc") to the namespaces enabled on it.
+	// Transports that are not present in the map expose every namespace
+	Namespaces map[string][]string
+
+	// Protected namespaces are only served to requests that carry the AuthToken
+	Protected []string
+
+	// AuthToken is the bearer token used to access the protected namespaces
+	AuthToken string
+}
+
+// validate checks that the access rules only reference registered namespaces,
+// and that the protected namespaces can be accessed with a token
+func (c *AccessConfig) validate(services map[string]*serviceData) error {
+	if c == nil {
+		return nil
+	}
+
+	if len(c.Protected) > 0 && c.AuthToken == "" {
+		return fmt.Errorf("an auth token is required for protecting namespaces %v", c.Protected)
+	}
+
+	for transport, list := range c.Namespaces {
+		if !isTransport(transport) {
+			return fmt.Errorf("unknown transport %q", transport)
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("no namespaces enabled on the %s transport", transport)
+		}
+		for _, namespace := range list {
+			if _, ok := services[namespace]; !ok {
+				return fmt.Errorf("unknown namespace %q enabled on the %s transport", namespace, transport)
+			}
+		}
+	}
+
+	for _, namespace := range c.Protected {
+		if _, ok := services[namespace]; !ok {
+			return fmt.Errorf("unknown namespace %q in the protected namespaces", namespace)
+		}
+	}
+
+	return nil
+}
+
+func isTransport(name string) bool {
+	for _, typ := range []serverType{serverIPC, serverHTTP, serverWS} {
+		if typ.String() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestContext holds the transport level details of a request
+// that are needed for enforcing the access rules
+type requestContext struct {
+	transport  serverType
+	authorized bool
+}
+
+// accessControl is the lookup friendly representation of the AccessConfig
+type accessControl struct {
+	namespaces map[serverType]map[string]bool
+	protected  map[string]bool
+	token      string
+}
+
+func newAccessControl(config *AccessConfig) *accessControl {
+	a := &accessControl{
+		namespaces: map[serverType]map[string]bool{},
+		protected:  map[string]bool{},
+	}
+	if config == nil {
+		return a
+	}
+
+	for _, typ := range []serverType{serverIPC, serverHTTP, serverWS} {
+		list, ok := config.Namespaces[typ.String()]
+		if !ok {
+			continue
+		}
+		a.namespaces[typ] = toSet(list)
+	}
+
+	a.protected = toSet(config.Protected)
+	a.token = config.AuthToken
+
+	return a
+}
+
+// isEnabled checks if the namespace is exposed on the transport
+func (a *accessControl) isEnabled(transport serverType, namespace string) bool {
+	if a == nil {
+		return true
+	}
+
+	enabled, ok := a.namespaces[transport]
+	if !ok {
+		return true
+	}
+
+	return enabled[namespace]
+}
+
+// isProtected checks if the namespace requires an authorization token
+func (a *accessControl) isProtected(namespace string) bool {
+	if a == nil {
+		return false
+	}
+
+	return a.protected[namespace]
+}
+
+// authorize checks the bearer token in the Authorization header of the request
+func (a *accessControl) authorize(req *http.Request) bool {
+	if a == nil || a.token == "" {
+		return false
+	}
+
+	header := req.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return false
+	}
+	token := strings.TrimPrefix(header, bearerPrefix)
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1
+}
+
+// ParseNamespaces parses a comma separated list of namespaces
+func ParseNamespaces(raw string) []string {
+	namespaces := []string{}
+	for _, ns := range strings.Split(raw, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	return namespaces
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, item := range list {
+		set[item] = true
+	}
+
+	return set
+}
@@ -0,0 +1,89 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessControlAuthorize(t *testing.T) {
+	access := newAccessControl(&AccessConfig{
+		Protected: []string{"txpool"},
+		AuthToken: "secret",
+	})
+
+	cases := []struct {
+		header     string
+		authorized bool
+	}{
+		{"Bearer secret", true},
+		{"", false},
+		{"secret", false},
+		{"Basic secret", false},
+		{"Bearer other", false},
+		{"bearer secret", false},
+	}
+
+	for _, c := range cases {
+		req, err := http.NewRequest("POST", "/", nil)
+		assert.NoError(t, err)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+
+		assert.Equal(t, c.authorized, access.authorize(req), c.header)
+	}
+
+	// without a token nothing is authorized
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	assert.False(t, newAccessControl(nil).authorize(req))
+}
+
+func TestNewJSONRPCAccessValidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		access *AccessConfig
+	}{
+		{
+			"protected namespaces without a token",
+			&AccessConfig{
+				Protected: []string{"txpool"},
+			},
+		},
+		{
+			"unknown namespace on a transport",
+			&AccessConfig{
+				Namespaces: map[string][]string{"http": {"eht"}},
+			},
+		},
+		{
+			"unknown protected namespace",
+			&AccessConfig{
+				Protected: []string{"debug"},
+				AuthToken: "secret",
+			},
+		},
+		{
+			"unknown transport",
+			&AccessConfig{
+				Namespaces: map[string][]string{"grpc": {"eth"}},
+			},
+		},
+		{
+			"no namespaces on a transport",
+			&AccessConfig{
+				Namespaces: map[string][]string{"ws": {}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewJSONRPC(hclog.NewNullLogger(), &Config{Access: c.access})
+			assert.Error(t, err)
+		})
+	}
+}
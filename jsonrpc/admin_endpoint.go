@@ -0,0 +1,105 @@
+package jsonrpc
+
+// PeerInfo is a connected peer of the node, as returned by admin_peers
+type PeerInfo struct {
+	ID        string   `json:"id"`
+	Addrs     []string `json:"addrs"`
+	Protocols []string `json:"protocols"`
+	Static    bool     `json:"static"`
+	Trusted   bool     `json:"trusted"`
+}
+
+// PeerScore is the reputation of a peer, as returned by admin_peerScores
+type PeerScore struct {
+	ID     string            `json:"id"`
+	Score  int64             `json:"score"`
+	Faults map[string]uint64 `json:"faults"`
+
+	// BannedUntil is the unix time of the end of the ban, if the peer is banned
+	BannedUntil uint64 `json:"bannedUntil,omitempty"`
+}
+
+// peerManager is the network server backing the admin namespace.
+// The peers are identified by their libp2p addresses, or their ids where no address is needed
+type peerManager interface {
+	// AddStaticPeer connects to the peer and keeps it connected
+	AddStaticPeer(addr string) error
+
+	// RemoveStaticPeer stops keeping the peer connected, and disconnects it
+	RemoveStaticPeer(addr string) error
+
+	// AddTrustedPeer exempts the peer from the max peer count
+	AddTrustedPeer(addr string) error
+
+	// RemoveTrustedPeer subjects the peer to the max peer count again
+	RemoveTrustedPeer(addr string) error
+
+	// Peers returns the connected peers
+	Peers() ([]*PeerInfo, error)
+
+	// PeerScores returns the scores of the peers with faults
+	PeerScores() ([]*PeerScore, error)
+
+	// ClearPeerScore resets the score of the peer, and lifts its ban
+	ClearPeerScore(addr string) error
+}
+
+// Admin is the admin jsonrpc endpoint, managing the peers of the node
+type Admin struct {
+	d *Dispatcher
+}
+
+// AddPeer adds a static peer, which is dialed right away and redialed when the connection is lost
+func (a *Admin) AddPeer(addr string) (interface{}, error) {
+	if err := a.d.peers.AddStaticPeer(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemovePeer removes a static peer, and disconnects it
+func (a *Admin) RemovePeer(addr string) (interface{}, error) {
+	if err := a.d.peers.RemoveStaticPeer(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AddTrustedPeer exempts the peer from the max peer count
+func (a *Admin) AddTrustedPeer(addr string) (interface{}, error) {
+	if err := a.d.peers.AddTrustedPeer(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemoveTrustedPeer subjects the peer to the max peer count again
+func (a *Admin) RemoveTrustedPeer(addr string) (interface{}, error) {
+	if err := a.d.peers.RemoveTrustedPeer(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Peers returns the connected peers of the node
+func (a *Admin) Peers() (interface{}, error) {
+	return a.d.peers.Peers()
+}
+
+// PeerScores returns the scores of the peers with faults, and their bans
+func (a *Admin) PeerScores() (interface{}, error) {
+	return a.d.peers.PeerScores()
+}
+
+// ClearPeerScore resets the score of the peer, and lifts its ban
+func (a *Admin) ClearPeerScore(addr string) (interface{}, error) {
+	if err := a.d.peers.ClearPeerScore(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
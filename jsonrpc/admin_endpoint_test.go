@@ -0,0 +1,153 @@
+package jsonrpc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPeers struct {
+	static  map[string]bool
+	trusted map[string]bool
+	scores  map[string]*PeerScore
+}
+
+func newMockPeers() *mockPeers {
+	return &mockPeers{
+		static:  map[string]bool{},
+		trusted: map[string]bool{},
+		scores:  map[string]*PeerScore{},
+	}
+}
+
+func (m *mockPeers) AddStaticPeer(addr string) error {
+	if !strings.HasPrefix(addr, "/") {
+		return errors.New("not an address")
+	}
+	m.static[addr] = true
+	return nil
+}
+
+func (m *mockPeers) RemoveStaticPeer(addr string) error {
+	delete(m.static, addr)
+	return nil
+}
+
+func (m *mockPeers) AddTrustedPeer(addr string) error {
+	m.trusted[addr] = true
+	return nil
+}
+
+func (m *mockPeers) RemoveTrustedPeer(addr string) error {
+	delete(m.trusted, addr)
+	return nil
+}
+
+func (m *mockPeers) Peers() ([]*PeerInfo, error) {
+	infos := []*PeerInfo{}
+	for addr := range m.static {
+		infos = append(infos, &PeerInfo{ID: addr, Addrs: []string{addr}, Static: true, Trusted: true})
+	}
+	return infos, nil
+}
+
+func (m *mockPeers) PeerScores() ([]*PeerScore, error) {
+	scores := []*PeerScore{}
+	for _, score := range m.scores {
+		scores = append(scores, score)
+	}
+	return scores, nil
+}
+
+func (m *mockPeers) ClearPeerScore(addr string) error {
+	delete(m.scores, addr)
+	return nil
+}
+
+func TestAdminNamespaceDisabled(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "admin_peers"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var peers []*PeerInfo
+	assert.Error(t, expectJSONResult(resp, &peers))
+}
+
+func TestAdminPeers(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+	peers := newMockPeers()
+	dispatcher.enableAdmin(peers)
+
+	call := func(req string) (bool, error) {
+		resp, err := dispatcher.Handle([]byte(req), requestContext{})
+		assert.NoError(t, err)
+
+		var ok bool
+		err = expectJSONResult(resp, &ok)
+		return ok, err
+	}
+
+	addr := "/ip4/127.0.0.1/tcp/1478/p2p/16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW"
+
+	ok, err := call(`{"method": "admin_addPeer", "params": ["` + addr + `"]}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = call(`{"method": "admin_addPeer", "params": ["invalid"]}`)
+	assert.Error(t, err)
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "admin_peers"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var infos []*PeerInfo
+	assert.NoError(t, expectJSONResult(resp, &infos))
+	assert.Equal(t, []*PeerInfo{{ID: addr, Addrs: []string{addr}, Static: true, Trusted: true}}, infos)
+
+	ok, err = call(`{"method": "admin_addTrustedPeer", "params": ["` + addr + `"]}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, peers.trusted[addr])
+
+	ok, err = call(`{"method": "admin_removeTrustedPeer", "params": ["` + addr + `"]}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, peers.trusted)
+
+	ok, err = call(`{"method": "admin_removePeer", "params": ["` + addr + `"]}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, peers.static)
+}
+
+func TestAdminPeerScores(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+	peers := newMockPeers()
+	dispatcher.enableAdmin(peers)
+
+	id := "16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW"
+	peers.scores[id] = &PeerScore{
+		ID:          id,
+		Score:       -100,
+		Faults:      map[string]uint64{"bad_block": 2},
+		BannedUntil: 1000,
+	}
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "admin_peerScores"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var scores []*PeerScore
+	assert.NoError(t, expectJSONResult(resp, &scores))
+	assert.Equal(t, []*PeerScore{peers.scores[id]}, scores)
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "admin_clearPeerScore", "params": ["`+id+`"]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var ok bool
+	assert.NoError(t, expectJSONResult(resp, &ok))
+	assert.True(t, ok)
+	assert.Empty(t, peers.scores)
+}
@@ -1,12 +1,18 @@
 package jsonrpc
 
 import (
+	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/protocol"
 	"github.com/0xPolygon/polygon-sdk/state"
 	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/0xPolygon/polygon-sdk/types"
 )
 
@@ -43,6 +49,12 @@ type blockchainInterface interface {
 	// AddTx adds a new transaction to the tx pool
 	AddTx(tx *types.Transaction) error
 
+	// AddTxWithExpiry adds a new transaction to the tx pool, which is dropped once the expiry is reached
+	AddTxWithExpiry(tx *types.Transaction, expiry *txpool.Expiry) error
+
+	// SubscribeDropped subscribes for the transactions dropped from the tx pool without being mined
+	SubscribeDropped() (<-chan *txpool.DroppedTx, func())
+
 	// Gets tx pool transactions currently pending for inclusion and currently queued for validation
 	GetTxs() (map[types.Address]map[uint64]*types.Transaction, map[types.Address]map[uint64]*types.Transaction)
 
@@ -52,12 +64,63 @@ type blockchainInterface interface {
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
 
-	// ApplyTxn applies a transaction object to the blockchain
-	ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
+	// ApplyTxn applies a transaction object to the blockchain, on top of the state override if any.
+	// The execution is interrupted after the timeout, if not zero
+	ApplyTxn(
+		header *types.Header,
+		txn *types.Transaction,
+		override state.StateOverride,
+		timeout time.Duration,
+	) (*runtime.ExecutionResult, error)
 
 	// GetNonce returns the next nonce for this address
 	GetNonce(addr types.Address) (uint64, bool)
 
+	// GetWitness returns the state access stats of a recently processed block
+	GetWitness(hash types.Hash) (*state.WitnessStats, bool)
+
+	// PendingBlock returns a speculative block built from the pending transactions of the pool
+	PendingBlock() (*types.Block, error)
+
+	// ForkBranches returns the branches announced by the peers that compete with the local chain
+	ForkBranches() []*protocol.ForkBranch
+
+	// SyncProgress returns the progress of the running sync, nil if the node isn't syncing
+	SyncProgress() *protocol.SyncProgress
+
+	// GetArchivedAccount returns the RLP encoding of an account archived by the state rent
+	GetArchivedAccount(addr types.Address) ([]byte, bool)
+
+	// SimulateBundle executes the transactions on top of the state of the parent, in the context of the block following it
+	SimulateBundle(parent *types.Header, timestamp uint64, txs []*types.Transaction) (*state.BundleResult, error)
+
+	// GetBlockTraces re-executes the block and returns the call traces of its transactions
+	GetBlockTraces(block *types.Block) ([]*state.CallTrace, error)
+
+	// FilterLogBlocks returns the blocks in the range whose logs bloom matches the addresses and topics
+	FilterLogBlocks(from, to uint64, addresses []types.Address, topics [][]types.Hash) []uint64
+
+	// CheckState returns the reason why the state of the block can't be queried, if it can't
+	CheckState(header *types.Header) error
+
+	// GetBadBlocks returns the blocks which failed their verification or their execution
+	GetBadBlocks() ([]*storage.BadBlock, error)
+
+	// EVMProfile returns the stats of the evm profiler, optionally clearing them
+	EVMProfile(limit int, reset bool) (*evm.Profile, error)
+
+	// GetStorageRangeAt returns a range of the storage of the account after the first transactions of the block
+	GetStorageRangeAt(block *types.Block, txIndex int, addr types.Address, start types.Hash, max int) (*state.StorageRange, error)
+
+	// GetAccountRange returns a range of the accounts of the state, in the order of their hashed keys
+	GetAccountRange(root types.Hash, start types.Hash, max int, withCode, withStorage bool) (*state.AccountRange, error)
+
+	// GetModifiedAccounts re-executes the block and returns the accounts it modified
+	GetModifiedAccounts(block *types.Block) ([]types.Address, error)
+
+	// GetAddressTxs returns a page of the canonical transactions of the address, the newest first
+	GetAddressTxs(addr types.Address, cursor uint64, limit int) (*blockchain.AddressTxsPage, error)
+
 	stateHelperInterface
 }
 
@@ -96,6 +159,14 @@ func (b *nullBlockchainInterface) AddTx(tx *types.Transaction) error {
 	return nil
 }
 
+func (b *nullBlockchainInterface) AddTxWithExpiry(tx *types.Transaction, expiry *txpool.Expiry) error {
+	return nil
+}
+
+func (b *nullBlockchainInterface) SubscribeDropped() (<-chan *txpool.DroppedTx, func()) {
+	return nil, func() {}
+}
+
 func (b *nullBlockchainInterface) GetTxs() (map[types.Address]map[uint64]*types.Transaction, map[types.Address]map[uint64]*types.Transaction) {
 	return nil, nil
 }
@@ -108,6 +179,18 @@ func (b *nullBlockchainInterface) BeginTxn(parentRoot types.Hash, header *types.
 	return nil, nil
 }
 
+func (b *nullBlockchainInterface) SimulateBundle(
+	parent *types.Header,
+	timestamp uint64,
+	txs []*types.Transaction,
+) (*state.BundleResult, error) {
+	return nil, fmt.Errorf("the bundle simulations are not supported")
+}
+
+func (b *nullBlockchainInterface) GetBlockTraces(block *types.Block) ([]*state.CallTrace, error) {
+	return nil, nil
+}
+
 func (b *nullBlockchainInterface) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
 	return nil, false
 }
@@ -116,7 +199,12 @@ func (b *nullBlockchainInterface) GetBlockByNumber(num uint64, full bool) (*type
 	return nil, false
 }
 
-func (b *nullBlockchainInterface) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+func (b *nullBlockchainInterface) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
 	return nil, nil
 }
 
@@ -131,3 +219,81 @@ func (b *nullBlockchainInterface) GetStorage(root types.Hash, addr types.Address
 func (b *nullBlockchainInterface) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
 	return nil, nil
 }
+
+func (b *nullBlockchainInterface) GetWitness(hash types.Hash) (*state.WitnessStats, bool) {
+	return nil, false
+}
+
+func (b *nullBlockchainInterface) PendingBlock() (*types.Block, error) {
+	return nil, fmt.Errorf("the pending block is not supported")
+}
+
+func (b *nullBlockchainInterface) ForkBranches() []*protocol.ForkBranch {
+	return nil
+}
+
+func (b *nullBlockchainInterface) SyncProgress() *protocol.SyncProgress {
+	return nil
+}
+
+func (b *nullBlockchainInterface) FilterLogBlocks(
+	from, to uint64,
+	addresses []types.Address,
+	topics [][]types.Hash,
+) []uint64 {
+	// without an index, every block of the range is a candidate
+	numbers := []uint64{}
+	for i := from; i <= to; i++ {
+		numbers = append(numbers, i)
+	}
+
+	return numbers
+}
+
+func (b *nullBlockchainInterface) GetArchivedAccount(addr types.Address) ([]byte, bool) {
+	return nil, false
+}
+
+func (b *nullBlockchainInterface) CheckState(header *types.Header) error {
+	return nil
+}
+
+func (b *nullBlockchainInterface) GetBadBlocks() ([]*storage.BadBlock, error) {
+	return nil, nil
+}
+
+func (b *nullBlockchainInterface) EVMProfile(limit int, reset bool) (*evm.Profile, error) {
+	return nil, fmt.Errorf("the evm profiler is disabled")
+}
+
+func (b *nullBlockchainInterface) GetStorageRangeAt(
+	block *types.Block,
+	txIndex int,
+	addr types.Address,
+	start types.Hash,
+	max int,
+) (*state.StorageRange, error) {
+	return nil, fmt.Errorf("the storage ranges are not supported")
+}
+
+func (b *nullBlockchainInterface) GetAccountRange(
+	root types.Hash,
+	start types.Hash,
+	max int,
+	withCode bool,
+	withStorage bool,
+) (*state.AccountRange, error) {
+	return nil, fmt.Errorf("the state dumps are not supported")
+}
+
+func (b *nullBlockchainInterface) GetModifiedAccounts(block *types.Block) ([]types.Address, error) {
+	return nil, fmt.Errorf("the modified accounts are not supported")
+}
+
+func (b *nullBlockchainInterface) GetAddressTxs(
+	addr types.Address,
+	cursor uint64,
+	limit int,
+) (*blockchain.AddressTxsPage, error) {
+	return nil, blockchain.ErrAddressIndexDisabled
+}
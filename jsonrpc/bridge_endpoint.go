@@ -0,0 +1,53 @@
+package jsonrpc
+
+import (
+	"github.com/0xPolygon/polygon-sdk/bridge"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// exitProof is the proof of a withdrawal to the root chain, as returned by bridge_getExitProof.
+// The receipt with the log is proven against the receipts root of the header
+type exitProof struct {
+	BlockNumber  argUint64  `json:"blockNumber"`
+	BlockHash    types.Hash `json:"blockHash"`
+	Header       argBytes   `json:"header"`
+	Receipt      argBytes   `json:"receipt"`
+	ReceiptIndex argUint64  `json:"receiptIndex"`
+	LogIndex     argUint64  `json:"logIndex"`
+	Proof        []argBytes `json:"proof"`
+}
+
+// exitProver is the bridge backing the bridge namespace, proving the withdrawals of the chain
+type exitProver interface {
+	// ExitProof returns the proof of the withdrawal of the log of the transaction,
+	// the log index is the index of the log in the receipt
+	ExitProof(txHash types.Hash, logIndex uint64) (*bridge.ExitProof, error)
+}
+
+// Bridge is the bridge jsonrpc endpoint, with the proofs of the withdrawals to the root chain
+type Bridge struct {
+	d *Dispatcher
+}
+
+// GetExitProof returns the proof of the withdrawal of the log of the transaction
+func (b *Bridge) GetExitProof(hash types.Hash, logIndex argUint64) (interface{}, error) {
+	proof, err := b.d.exits.ExitProof(hash, uint64(logIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &exitProof{
+		BlockNumber:  argUint64(proof.BlockNumber),
+		BlockHash:    proof.BlockHash,
+		Header:       argBytes(proof.Header),
+		Receipt:      argBytes(proof.Receipt),
+		ReceiptIndex: argUint64(proof.ReceiptIndex),
+		LogIndex:     argUint64(proof.LogIndex),
+		Proof:        make([]argBytes, 0, len(proof.Proof)),
+	}
+	for _, node := range proof.Proof {
+		res.Proof = append(res.Proof, argBytes(node))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/bridge"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockExitProver struct {
+	txHash types.Hash
+}
+
+func (m *mockExitProver) ExitProof(txHash types.Hash, logIndex uint64) (*bridge.ExitProof, error) {
+	if txHash != m.txHash {
+		return nil, errors.New("not found")
+	}
+
+	return &bridge.ExitProof{
+		BlockNumber:  10,
+		BlockHash:    types.StringToHash("2"),
+		Header:       []byte{0x1},
+		Receipt:      []byte{0x2},
+		ReceiptIndex: 3,
+		LogIndex:     logIndex,
+		Proof:        [][]byte{{0x3}, {0x4}},
+	}, nil
+}
+
+func TestBridgeGetExitProof(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+
+	// the namespace is disabled without the bridge
+	resp, err := dispatcher.Handle([]byte(`{"method": "bridge_getExitProof", "params": ["0x1", "0x0"]}`), requestContext{})
+	assert.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &map[string]interface{}{}))
+
+	txHash := types.StringToHash("1")
+	dispatcher.enableBridge(&mockExitProver{txHash: txHash})
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "bridge_getExitProof", "params": ["`+txHash.String()+`", "0x1"]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var proof map[string]interface{}
+	assert.NoError(t, expectJSONResult(resp, &proof))
+	assert.Equal(t, "0xa", proof["blockNumber"])
+	assert.Equal(t, "0x1", proof["logIndex"])
+	assert.Equal(t, "0x3", proof["receiptIndex"])
+	assert.Equal(t, "0x01", proof["header"])
+	assert.Equal(t, []interface{}{"0x03", "0x04"}, proof["proof"])
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "bridge_getExitProof", "params": ["0x2", "0x1"]}`), requestContext{})
+	assert.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &proof))
+}
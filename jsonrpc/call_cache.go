@@ -0,0 +1,132 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// defaultCallCacheTTL is the lifetime of the cached results if the config leaves it unset
+	defaultCallCacheTTL = time.Second
+
+	// defaultCallCacheSize is the number of cached results if the config leaves it unset
+	defaultCallCacheSize = 1024
+)
+
+// CallCacheConfig sets the cache of the eth_call and eth_estimateGas results
+type CallCacheConfig struct {
+	// TTL is how long a result is served from the cache
+	TTL time.Duration
+
+	// Size is the maximum number of cached results
+	Size int
+}
+
+type callCacheEntry struct {
+	result  interface{}
+	expires time.Time
+}
+
+// callCache keeps the results of the eth_call and eth_estimateGas requests for a short time,
+// keyed by the block they ran on and their params, so that the identical requests
+// polled by the dashboards do not re-execute the EVM each time
+type callCache struct {
+	ttl     time.Duration
+	entries *lru.Cache
+	metrics *Metrics
+
+	now func() time.Time
+}
+
+func newCallCache(config *CallCacheConfig, metrics *Metrics) (*callCache, error) {
+	ttl := config.TTL
+	if ttl == 0 {
+		ttl = defaultCallCacheTTL
+	}
+
+	size := config.Size
+	if size == 0 {
+		size = defaultCallCacheSize
+	}
+
+	entries, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &callCache{
+		ttl:     ttl,
+		entries: entries,
+		metrics: metrics,
+		now:     time.Now,
+	}, nil
+}
+
+// key returns the cache key of a request, or false if the params can't be encoded
+func (c *callCache) key(method string, header *types.Header, params ...interface{}) (string, bool) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+
+	return method + header.Hash.String() + string(data), true
+}
+
+// get returns the cached result of the request, if it has not expired
+func (c *callCache) get(method string, key string) (interface{}, bool) {
+	if obj, ok := c.entries.Get(key); ok {
+		entry := obj.(*callCacheEntry)
+		if c.now().Before(entry.expires) {
+			c.metrics.CallCacheHits.With("method", method).Add(1)
+			return entry.result, true
+		}
+
+		c.entries.Remove(key)
+	}
+
+	c.metrics.CallCacheMisses.With("method", method).Add(1)
+
+	return nil, false
+}
+
+// set caches the result of the request
+func (c *callCache) set(key string, result interface{}) {
+	c.entries.Add(key, &callCacheEntry{
+		result:  result,
+		expires: c.now().Add(c.ttl),
+	})
+}
+
+// cached returns the result of the request from the cache, or runs it and caches its result
+// if it succeeded. A nil cache runs every request
+func (c *callCache) cached(
+	method string,
+	header *types.Header,
+	run func() (interface{}, error),
+	params ...interface{},
+) (interface{}, error) {
+	if c == nil {
+		return run()
+	}
+
+	key, ok := c.key(method, header, params...)
+	if !ok {
+		return run()
+	}
+
+	if result, ok := c.get(method, key); ok {
+		return result, nil
+	}
+
+	result, err := run()
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, result)
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCallStore struct {
+	nullBlockchainInterface
+
+	header  *types.Header
+	pending *types.Block
+	applied int
+	failed  bool
+}
+
+func (m *mockCallStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	panic("implement me")
+}
+
+func (m *mockCallStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockCallStore) PendingBlock() (*types.Block, error) {
+	return m.pending, nil
+}
+
+func (m *mockCallStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
+	m.applied++
+	if m.failed {
+		return &runtime.ExecutionResult{Err: runtime.ErrExecutionReverted}, nil
+	}
+	return &runtime.ExecutionResult{ReturnValue: []byte{byte(header.Number)}}, nil
+}
+
+// mockCounter counts the additions by label values
+type mockCounter struct {
+	counts map[string]float64
+	label  string
+}
+
+func newMockCounter() *mockCounter {
+	return &mockCounter{counts: map[string]float64{}}
+}
+
+func (c *mockCounter) With(labelValues ...string) metrics.Counter {
+	return &mockCounter{counts: c.counts, label: fmt.Sprint(labelValues)}
+}
+
+func (c *mockCounter) Add(delta float64) {
+	c.counts[c.label] += delta
+}
+
+func TestCallCache_Call(t *testing.T) {
+	store := &mockCallStore{
+		header:  &types.Header{Number: 10, Hash: types.StringToHash("0x10")},
+		pending: &types.Block{Header: &types.Header{Number: 11, Hash: types.StringToHash("0x11")}},
+	}
+	hits, misses := newMockCounter(), newMockCounter()
+
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	assert.NoError(t, dispatcher.enableCallCache(&CallCacheConfig{TTL: time.Second}, &Metrics{
+		CallCacheHits:   hits,
+		CallCacheMisses: misses,
+	}))
+
+	now := time.Now()
+	dispatcher.callCache.now = func() time.Time { return now }
+
+	call := func(to types.Address, number string) (string, *ErrorObject) {
+		body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_call", "params": [{"to": "%s"}, "%s"]}`, to, number)
+
+		res, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var resp SuccessResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		return string(resp.Result), resp.Error
+	}
+
+	result, err := call(addr0, "latest")
+	assert.Nil(t, err)
+	assert.Equal(t, `"0x0a"`, result)
+	assert.Equal(t, 1, store.applied)
+
+	// the identical call on the same block is served from the cache
+	result, err = call(addr0, "latest")
+	assert.Nil(t, err)
+	assert.Equal(t, `"0x0a"`, result)
+	assert.Equal(t, 1, store.applied)
+
+	// the calls with other params or on another block run again
+	call(addr1, "latest")
+	assert.Equal(t, 2, store.applied)
+
+	store.header = &types.Header{Number: 12, Hash: types.StringToHash("0x12")}
+	result, _ = call(addr0, "latest")
+	assert.Equal(t, `"0x0c"`, result)
+	assert.Equal(t, 3, store.applied)
+
+	// the calls on the pending block are not cached
+	call(addr0, "pending")
+	call(addr0, "pending")
+	assert.Equal(t, 5, store.applied)
+
+	// the results expire
+	now = now.Add(2 * time.Second)
+	call(addr0, "latest")
+	assert.Equal(t, 6, store.applied)
+
+	// the failed calls are not cached
+	store.failed = true
+	store.header = &types.Header{Number: 13, Hash: types.StringToHash("0x13")}
+	_, err = call(addr0, "latest")
+	assert.NotNil(t, err)
+	_, err = call(addr0, "latest")
+	assert.NotNil(t, err)
+	assert.Equal(t, 8, store.applied)
+
+	assert.Equal(t, map[string]float64{"[method eth_call]": 1}, hits.counts)
+	assert.Equal(t, map[string]float64{"[method eth_call]": 6}, misses.counts)
+}
+
+func TestCallCache_Disabled(t *testing.T) {
+	store := &mockCallStore{
+		header: &types.Header{Number: 10, Hash: types.StringToHash("0x10")},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_call", "params": [{"to": "%s"}, "latest"]}`, addr0)
+	for i := 0; i < 2; i++ {
+		_, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, store.applied)
+}
@@ -163,6 +163,9 @@ func NewRpcResponse(id interface{}, jsonrpcver string, reply []byte, err Error)
 		response = &SuccessResponse{JSONRPC: jsonrpcver, ID: id, Result: reply}
 	default:
 		response = NewRpcErrorResponse(id, err.ErrorCode(), err.Error(), jsonrpcver)
+		if dataErr, ok := err.(DataError); ok {
+			response.(*ErrorResponse).Error.Data = dataErr.ErrorData()
+		}
 	}
 
 	return response
@@ -0,0 +1,12 @@
+// +build windows
+
+package jsonrpc
+
+import (
+	"time"
+)
+
+// processCPUTime is not supported on windows, the CPU threshold of the load shedder is ignored
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
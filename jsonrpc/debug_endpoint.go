@@ -0,0 +1,440 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+const (
+	// storageRangeMaxResults is the largest number of slots returned by debug_storageRangeAt
+	storageRangeMaxResults = 1024
+
+	// modifiedAccountsMaxBlocks is the largest block range of debug_getModifiedAccountsByNumber,
+	// since every block of the range is re-executed
+	modifiedAccountsMaxBlocks = 1000
+
+	// accountRangeMaxResults is the largest number of accounts returned by debug_accountRange
+	accountRangeMaxResults = 256
+
+	// dumpBlockMaxAccounts is the largest state dumped at once by debug_dumpBlock,
+	// the larger states are paged with debug_accountRange
+	dumpBlockMaxAccounts = 10000
+)
+
+// Debug is the debug jsonrpc endpoint
+type Debug struct {
+	d *Dispatcher
+}
+
+type witnessResponse struct {
+	BlockNumber   argUint64  `json:"blockNumber"`
+	BlockHash     types.Hash `json:"blockHash"`
+	Accounts      argUint64  `json:"accounts"`
+	StorageReads  argUint64  `json:"storageReads"`
+	StorageWrites argUint64  `json:"storageWrites"`
+	TrieNodes     argUint64  `json:"trieNodes"`
+	CodeBytes     argUint64  `json:"codeBytes"`
+}
+
+// GetBlockWitness returns the state access stats of a recently processed block:
+// the touched accounts, the storage slots read and written, and the trie nodes and code bytes loaded.
+// The stats are only kept in memory for the latest blocks processed by the node
+func (d *Debug) GetBlockWitness(number BlockNumber) (interface{}, error) {
+	num, err := GetNumericBlockNumber(number, d.d.endpoints.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	header, ok := d.d.store.GetHeaderByNumber(num)
+	if !ok {
+		return nil, nil
+	}
+
+	witness, ok := d.d.store.GetWitness(header.Hash)
+	if !ok {
+		return nil, nil
+	}
+
+	return &witnessResponse{
+		BlockNumber:   argUint64(header.Number),
+		BlockHash:     header.Hash,
+		Accounts:      argUint64(witness.Accounts),
+		StorageReads:  argUint64(witness.StorageReads),
+		StorageWrites: argUint64(witness.StorageWrites),
+		TrieNodes:     argUint64(witness.TrieNodes),
+		CodeBytes:     argUint64(witness.CodeBytes),
+	}, nil
+}
+
+type forkBranchResponse struct {
+	Head       types.Hash `json:"head"`
+	Number     argUint64  `json:"number"`
+	ForkNumber argUint64  `json:"forkNumber"`
+	LocalHash  types.Hash `json:"localHash"`
+	Peers      []string   `json:"peers"`
+	FirstSeen  argUint64  `json:"firstSeen"`
+	LastSeen   argUint64  `json:"lastSeen"`
+}
+
+// GetForkBranches returns the branches announced by the peers that compete with the local canonical chain,
+// most recently seen first. The first and last seen times are unix timestamps
+func (d *Debug) GetForkBranches() (interface{}, error) {
+	branches := d.d.store.ForkBranches()
+
+	resp := make([]*forkBranchResponse, 0, len(branches))
+	for _, branch := range branches {
+		peers := make([]string, 0, len(branch.Peers))
+		for _, peerID := range branch.Peers {
+			peers = append(peers, peerID.String())
+		}
+
+		resp = append(resp, &forkBranchResponse{
+			Head:       branch.Head,
+			Number:     argUint64(branch.Number),
+			ForkNumber: argUint64(branch.ForkNumber),
+			LocalHash:  branch.LocalHash,
+			Peers:      peers,
+			FirstSeen:  argUint64(branch.FirstSeen.Unix()),
+			LastSeen:   argUint64(branch.LastSeen.Unix()),
+		})
+	}
+
+	return resp, nil
+}
+
+type archivedAccountResponse struct {
+	Address      types.Address `json:"address"`
+	Nonce        argUint64     `json:"nonce"`
+	Balance      argBig        `json:"balance"`
+	StorageRoot  types.Hash    `json:"storageRoot"`
+	CodeHash     types.Hash    `json:"codeHash"`
+	LastTouched  argUint64     `json:"lastTouched"`
+	RestoreTo    types.Address `json:"restoreTo"`
+	RestoreInput argBytes      `json:"restoreInput"`
+}
+
+// GetArchivedAccount returns the latest version of an account archived by the state rent,
+// along with the input of the transaction to the state rent address that restores it.
+// The accounts are only served by the nodes that archived them
+func (d *Debug) GetArchivedAccount(addr types.Address) (interface{}, error) {
+	data, ok := d.d.store.GetArchivedAccount(addr)
+	if !ok {
+		return nil, nil
+	}
+
+	var account state.Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return nil, err
+	}
+
+	return &archivedAccountResponse{
+		Address:      addr,
+		Nonce:        argUint64(account.Nonce),
+		Balance:      argBig(*account.Balance),
+		StorageRoot:  account.Root,
+		CodeHash:     types.BytesToHash(account.CodeHash),
+		LastTouched:  argUint64(account.LastTouched),
+		RestoreTo:    state.StateRentAddress,
+		RestoreInput: argBytes(state.EncodeRestoreInput(addr, data)),
+	}, nil
+}
+
+type badBlockResponse struct {
+	Hash   types.Hash      `json:"hash"`
+	Block  *block          `json:"block"`
+	RLP    argBytes        `json:"rlp"`
+	Reason string          `json:"reason"`
+	Trace  json.RawMessage `json:"trace,omitempty"`
+	Time   argUint64       `json:"time"`
+}
+
+// GetBadBlocks returns the blocks the node rejected because they failed their verification or their execution,
+// most recent first, with the error they failed with. The blocks that were executed come with the call traces
+// of their transactions. The time is a unix timestamp
+func (d *Debug) GetBadBlocks() (interface{}, error) {
+	badBlocks, err := d.d.store.GetBadBlocks()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*badBlockResponse, 0, len(badBlocks))
+	for _, bad := range badBlocks {
+		resp = append(resp, &badBlockResponse{
+			Hash:   bad.Block.Hash(),
+			Block:  toBlock(bad.Block, true),
+			RLP:    argBytes(bad.Block.MarshalRLP()),
+			Reason: bad.Reason,
+			Trace:  json.RawMessage(bad.Trace),
+			Time:   argUint64(bad.Time),
+		})
+	}
+
+	return resp, nil
+}
+
+type opProfileResponse struct {
+	Op    string    `json:"op"`
+	Count argUint64 `json:"count"`
+	Gas   argUint64 `json:"gas"`
+	Time  argUint64 `json:"timeNs"`
+}
+
+type contractProfileResponse struct {
+	Address types.Address `json:"address"`
+	Calls   argUint64     `json:"calls"`
+	Gas     argUint64     `json:"gas"`
+	Time    argUint64     `json:"timeNs"`
+}
+
+type evmProfileResponse struct {
+	Since        argUint64                  `json:"since"`
+	Ops          []*opProfileResponse       `json:"ops"`
+	Contracts    []*contractProfileResponse `json:"contracts"`
+	DroppedCalls argUint64                  `json:"droppedCalls"`
+}
+
+// EvmProfile returns the gas and time spent per opcode and per contract by the blocks processed
+// since the node started or the stats were reset, the most expensive first. The gas and time
+// of a contract exclude its calls to other contracts. The limit caps the opcodes and contracts returned,
+// and reset clears the stats once returned. The profiler is enabled with the evm-profiler flag
+func (d *Debug) EvmProfile(limit *argUint64, reset *bool) (interface{}, error) {
+	n := 0
+	if limit != nil {
+		n = int(*limit)
+	}
+
+	profile, err := d.d.store.EVMProfile(n, reset != nil && *reset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &evmProfileResponse{
+		Since:        argUint64(profile.Since.Unix()),
+		Ops:          make([]*opProfileResponse, 0, len(profile.Ops)),
+		Contracts:    make([]*contractProfileResponse, 0, len(profile.Contracts)),
+		DroppedCalls: argUint64(profile.DroppedCalls),
+	}
+	for _, op := range profile.Ops {
+		resp.Ops = append(resp.Ops, &opProfileResponse{
+			Op:    op.Op,
+			Count: argUint64(op.Count),
+			Gas:   argUint64(op.Gas),
+			Time:  argUint64(op.Time.Nanoseconds()),
+		})
+	}
+	for _, contract := range profile.Contracts {
+		resp.Contracts = append(resp.Contracts, &contractProfileResponse{
+			Address: contract.Address,
+			Calls:   argUint64(contract.Calls),
+			Gas:     argUint64(contract.Gas),
+			Time:    argUint64(contract.Time.Nanoseconds()),
+		})
+	}
+
+	return resp, nil
+}
+
+type storageEntryResponse struct {
+	Key   *types.Hash `json:"key"`
+	Value types.Hash  `json:"value"`
+}
+
+type storageRangeResponse struct {
+	Storage map[types.Hash]*storageEntryResponse `json:"storage"`
+	NextKey *types.Hash                          `json:"nextKey"`
+}
+
+// StorageRangeAt returns up to maxResult storage slots of the account, in the order of their hashed keys
+// from the start key, in the state before the transaction at the index of the block. The slots are indexed
+// by their hashed keys, the keys are only known for the slots written by the previous transactions of the block.
+// The next key is the hashed key to start the following range from, if any
+func (d *Debug) StorageRangeAt(
+	blockHash types.Hash,
+	txIndex argUint64,
+	addr types.Address,
+	startKey types.Hash,
+	maxResult argUint64,
+) (interface{}, error) {
+	if maxResult > storageRangeMaxResults {
+		return nil, fmt.Errorf("the range is limited to %d slots", storageRangeMaxResults)
+	}
+
+	block, ok := d.d.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+
+	storage, err := d.d.store.GetStorageRangeAt(block, int(txIndex), addr, startKey, int(maxResult))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &storageRangeResponse{
+		Storage: make(map[types.Hash]*storageEntryResponse, len(storage.Storage)),
+		NextKey: storage.NextKey,
+	}
+	for hashedKey, entry := range storage.Storage {
+		resp.Storage[hashedKey] = &storageEntryResponse{
+			Key:   entry.Key,
+			Value: entry.Value,
+		}
+	}
+
+	return resp, nil
+}
+
+// GetModifiedAccountsByNumber returns the sorted accounts modified by the start block, or by the blocks
+// after the start block up to the end block if set
+func (d *Debug) GetModifiedAccountsByNumber(start BlockNumber, end *BlockNumber) (interface{}, error) {
+	startNum, err := GetNumericBlockNumber(start, d.d.endpoints.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := startNum, startNum
+	if end != nil {
+		endNum, err := GetNumericBlockNumber(*end, d.d.endpoints.Eth)
+		if err != nil {
+			return nil, err
+		}
+		if startNum >= endNum {
+			return nil, fmt.Errorf("incorrect range")
+		}
+
+		from, to = startNum+1, endNum
+	}
+
+	if to-from >= modifiedAccountsMaxBlocks {
+		return nil, fmt.Errorf("the block range is limited to %d blocks", modifiedAccountsMaxBlocks)
+	}
+
+	modified := map[types.Address]struct{}{}
+	for num := from; num <= to; num++ {
+		block, ok := d.d.store.GetBlockByNumber(num, true)
+		if !ok {
+			return nil, fmt.Errorf("block %d not found", num)
+		}
+
+		addrs, err := d.d.store.GetModifiedAccounts(block)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			modified[addr] = struct{}{}
+		}
+	}
+
+	resp := make([]types.Address, 0, len(modified))
+	for addr := range modified {
+		resp = append(resp, addr)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return bytes.Compare(resp[i].Bytes(), resp[j].Bytes()) < 0
+	})
+
+	return resp, nil
+}
+
+type dumpAccountResponse struct {
+	Balance  *argBig                   `json:"balance"`
+	Nonce    argUint64                 `json:"nonce"`
+	Root     types.Hash                `json:"root"`
+	CodeHash types.Hash                `json:"codeHash"`
+	Code     *argBytes                 `json:"code,omitempty"`
+	Storage  map[types.Hash]types.Hash `json:"storage,omitempty"`
+	Address  *types.Address            `json:"address,omitempty"`
+	Key      types.Hash                `json:"key"`
+
+	// UnknownKeys is the number of storage slots indexed by their hashed keys, since their keys are unknown
+	UnknownKeys argUint64 `json:"unknownKeys,omitempty"`
+}
+
+type dumpResponse struct {
+	Root     types.Hash                      `json:"root"`
+	Accounts map[string]*dumpAccountResponse `json:"accounts"`
+	Next     *types.Hash                     `json:"next,omitempty"`
+}
+
+// dumpState returns up to max accounts of the state of the block, from the hashed key
+func (d *Debug) dumpState(number BlockNumber, start types.Hash, max int, withCode, withStorage bool) (*dumpResponse, error) {
+	header, err := d.d.getStateHeaderImpl(number)
+	if err != nil {
+		return nil, err
+	}
+
+	rng, err := d.d.store.GetAccountRange(header.StateRoot, start, max, withCode, withStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dumpResponse{
+		Root:     header.StateRoot,
+		Accounts: make(map[string]*dumpAccountResponse, len(rng.Accounts)),
+		Next:     rng.Next,
+	}
+	for key, account := range rng.Accounts {
+		entry := &dumpAccountResponse{
+			Balance:  argBigPtr(account.Balance),
+			Nonce:    argUint64(account.Nonce),
+			Root:     account.Root,
+			CodeHash: account.CodeHash,
+			Storage:  account.Storage,
+			Address:  account.Address,
+			Key:      key,
+
+			UnknownKeys: argUint64(account.UnknownKeys),
+		}
+		if account.Code != nil {
+			entry.Code = argBytesPtr(account.Code)
+		}
+
+		// the accounts whose address is unknown are indexed by their hashed key
+		index := key.String()
+		if account.Address != nil {
+			index = account.Address.String()
+		}
+		resp.Accounts[index] = entry
+	}
+
+	return resp, nil
+}
+
+// AccountRange returns up to maxResults accounts of the state of the block, with their balance, nonce,
+// code hash and storage root, in the order of their hashed keys from the start key. The codes and the storages
+// are included unless nocode and nostorage are set. The next key is the hashed key to start the following range from, if any
+func (d *Debug) AccountRange(
+	number BlockNumber,
+	start types.Hash,
+	maxResults argUint64,
+	nocode *bool,
+	nostorage *bool,
+) (interface{}, error) {
+	if maxResults > accountRangeMaxResults {
+		return nil, fmt.Errorf("the range is limited to %d accounts", accountRangeMaxResults)
+	}
+
+	return d.dumpState(number, start, int(maxResults), nocode == nil || !*nocode, nostorage == nil || !*nostorage)
+}
+
+// DumpBlock returns all the accounts of the state of the block, with their codes and storages.
+// The states larger than dumpBlockMaxAccounts are paged with debug_accountRange instead
+func (d *Debug) DumpBlock(number BlockNumber) (interface{}, error) {
+	resp, err := d.dumpState(number, types.Hash{}, dumpBlockMaxAccounts, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Next != nil {
+		return nil, NewLimitExceededError(
+			fmt.Sprintf("the state has more than %d accounts, use debug_accountRange", dumpBlockMaxAccounts),
+			map[string]interface{}{"root": resp.Root, "next": resp.Next},
+		)
+	}
+
+	return resp, nil
+}
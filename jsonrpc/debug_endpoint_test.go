@@ -0,0 +1,530 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/protocol"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+)
+
+type mockWitnessStore struct {
+	nullBlockchainInterface
+
+	header    *types.Header
+	witness   *state.WitnessStats
+	branches  []*protocol.ForkBranch
+	archived  map[types.Address][]byte
+	badBlocks []*storage.BadBlock
+	profiler  *evm.Profiler
+	blocks    []*types.Block
+	storage   *state.StorageRange
+	modified  map[uint64][]types.Address
+	accounts  map[types.Hash]*state.DumpAccount
+}
+
+func (m *mockWitnessStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	for _, block := range m.blocks {
+		if block.Hash() == hash {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+func (m *mockWitnessStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+	return m.blocks[num], true
+}
+
+func (m *mockWitnessStore) GetStorageRangeAt(
+	block *types.Block,
+	txIndex int,
+	addr types.Address,
+	start types.Hash,
+	max int,
+) (*state.StorageRange, error) {
+	return m.storage, nil
+}
+
+// GetAccountRange pages the accounts of the mock, the ones with a storage root have a slot
+func (m *mockWitnessStore) GetAccountRange(
+	root types.Hash,
+	start types.Hash,
+	max int,
+	withCode bool,
+	withStorage bool,
+) (*state.AccountRange, error) {
+	keys := []types.Hash{}
+	for key := range m.accounts {
+		if bytes.Compare(key.Bytes(), start.Bytes()) >= 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].Bytes(), keys[j].Bytes()) < 0
+	})
+
+	res := &state.AccountRange{Accounts: map[types.Hash]*state.DumpAccount{}}
+	for i, key := range keys {
+		if i == max {
+			res.Next = &keys[i]
+			break
+		}
+
+		account := *m.accounts[key]
+		if !withCode {
+			account.Code = nil
+		}
+		if withStorage && account.Root != (types.Hash{}) {
+			account.Storage = map[types.Hash]types.Hash{types.StringToHash("1"): account.Root}
+		}
+		res.Accounts[key] = &account
+	}
+
+	return res, nil
+}
+
+func (m *mockWitnessStore) GetModifiedAccounts(block *types.Block) ([]types.Address, error) {
+	return m.modified[block.Number()], nil
+}
+
+func (m *mockWitnessStore) EVMProfile(limit int, reset bool) (*evm.Profile, error) {
+	if m.profiler == nil {
+		return m.nullBlockchainInterface.EVMProfile(limit, reset)
+	}
+	return m.profiler.Profile(limit, reset), nil
+}
+
+func (m *mockWitnessStore) GetBadBlocks() ([]*storage.BadBlock, error) {
+	return m.badBlocks, nil
+}
+
+func (m *mockWitnessStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	panic("implement me")
+}
+
+func (m *mockWitnessStore) GetArchivedAccount(addr types.Address) ([]byte, bool) {
+	data, ok := m.archived[addr]
+	return data, ok
+}
+
+func (m *mockWitnessStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockWitnessStore) GetHeaderByNumber(num uint64) (*types.Header, bool) {
+	if num != m.header.Number {
+		return nil, false
+	}
+	return m.header, true
+}
+
+func (m *mockWitnessStore) GetWitness(hash types.Hash) (*state.WitnessStats, bool) {
+	if hash != m.header.Hash {
+		return nil, false
+	}
+	return m.witness, true
+}
+
+func (m *mockWitnessStore) ForkBranches() []*protocol.ForkBranch {
+	return m.branches
+}
+
+func TestDebugNamespaceDisabled(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockWitnessStore{})
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "debug_evmProfile", "params": ["0xa", true]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res interface{}
+	assert.Error(t, expectJSONResult(resp, &res))
+}
+
+func TestDebugEndpointGetBlockWitness(t *testing.T) {
+	store := &mockWitnessStore{
+		header: &types.Header{
+			Number: 10,
+			Hash:   types.StringToHash("10"),
+		},
+		witness: &state.WitnessStats{
+			Accounts:      3,
+			StorageReads:  4,
+			StorageWrites: 2,
+			TrieNodes:     25,
+			CodeBytes:     1024,
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "debug_getBlockWitness", "params": ["latest"]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res witnessResponse
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Equal(t, argUint64(10), res.BlockNumber)
+	assert.Equal(t, store.header.Hash, res.BlockHash)
+	assert.Equal(t, argUint64(3), res.Accounts)
+	assert.Equal(t, argUint64(4), res.StorageReads)
+	assert.Equal(t, argUint64(2), res.StorageWrites)
+	assert.Equal(t, argUint64(25), res.TrieNodes)
+	assert.Equal(t, argUint64(1024), res.CodeBytes)
+
+	// the stats of the older blocks are not kept
+	resp, err = dispatcher.Handle([]byte(`{"method": "debug_getBlockWitness", "params": ["0x9"]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var empty *witnessResponse
+	assert.NoError(t, expectJSONResult(resp, &empty))
+	assert.Nil(t, empty)
+}
+
+func TestDebugEndpointGetForkBranches(t *testing.T) {
+	seen := time.Unix(1000, 0)
+	store := &mockWitnessStore{
+		branches: []*protocol.ForkBranch{
+			{
+				Head:       types.StringToHash("2"),
+				Number:     12,
+				ForkNumber: 10,
+				LocalHash:  types.StringToHash("1"),
+				Peers:      []peer.ID{peer.ID("A")},
+				FirstSeen:  seen,
+				LastSeen:   seen.Add(time.Minute),
+			},
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "debug_getForkBranches"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res []*forkBranchResponse
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Len(t, res, 1)
+	assert.Equal(t, types.StringToHash("2"), res[0].Head)
+	assert.Equal(t, argUint64(12), res[0].Number)
+	assert.Equal(t, argUint64(10), res[0].ForkNumber)
+	assert.Equal(t, types.StringToHash("1"), res[0].LocalHash)
+	assert.Equal(t, []string{peer.ID("A").String()}, res[0].Peers)
+	assert.Equal(t, argUint64(1000), res[0].FirstSeen)
+	assert.Equal(t, argUint64(1060), res[0].LastSeen)
+
+	// no branches is an empty list
+	store.branches = nil
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "debug_getForkBranches"}`), requestContext{})
+	assert.NoError(t, err)
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Empty(t, res)
+}
+
+func TestDebugEndpointGetArchivedAccount(t *testing.T) {
+	addr := types.StringToAddress("1")
+	account := &state.Account{
+		Nonce:       2,
+		Balance:     big.NewInt(100),
+		Root:        types.StringToHash("2"),
+		CodeHash:    types.StringToHash("3").Bytes(),
+		LastTouched: 10,
+	}
+	data := account.MarshalWith(&fastrlp.Arena{}).MarshalTo(nil)
+
+	store := &mockWitnessStore{
+		archived: map[types.Address][]byte{addr: data},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	resp, err := dispatcher.Handle(
+		[]byte(`{"method": "debug_getArchivedAccount", "params": ["`+addr.String()+`"]}`),
+		requestContext{},
+	)
+	assert.NoError(t, err)
+
+	var res archivedAccountResponse
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Equal(t, addr, res.Address)
+	assert.Equal(t, argUint64(2), res.Nonce)
+	assert.Equal(t, argBig(*big.NewInt(100)), res.Balance)
+	assert.Equal(t, types.StringToHash("2"), res.StorageRoot)
+	assert.Equal(t, types.StringToHash("3"), res.CodeHash)
+	assert.Equal(t, argUint64(10), res.LastTouched)
+	assert.Equal(t, state.StateRentAddress, res.RestoreTo)
+	assert.Equal(t, argBytes(state.EncodeRestoreInput(addr, data)), res.RestoreInput)
+
+	// the accounts that were not archived are not found
+	resp, err = dispatcher.Handle(
+		[]byte(`{"method": "debug_getArchivedAccount", "params": ["`+types.StringToAddress("2").String()+`"]}`),
+		requestContext{},
+	)
+	assert.NoError(t, err)
+
+	var empty *archivedAccountResponse
+	assert.NoError(t, expectJSONResult(resp, &empty))
+	assert.Nil(t, empty)
+}
+
+func TestDebugEndpointGetBadBlocks(t *testing.T) {
+	header := &types.Header{Number: 5, Sha3Uncles: types.EmptyUncleHash}
+	header.ComputeHash()
+	badBlock := &types.Block{Header: header}
+
+	store := &mockWitnessStore{
+		badBlocks: []*storage.BadBlock{
+			{
+				Block:  badBlock,
+				Reason: "invalid merkle root",
+				Trace:  []byte(`[{"type":"call"}]`),
+				Time:   1000,
+			},
+			{
+				Block:  badBlock,
+				Reason: "failed to verify the header",
+			},
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "debug_getBadBlocks"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res []struct {
+		Hash  types.Hash
+		Block struct {
+			Hash   types.Hash
+			Number argUint64
+		}
+		RLP    argBytes
+		Reason string
+		Trace  []map[string]interface{}
+		Time   argUint64
+	}
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Len(t, res, 2)
+
+	assert.Equal(t, header.Hash, res[0].Hash)
+	assert.Equal(t, header.Hash, res[0].Block.Hash)
+	assert.Equal(t, argUint64(5), res[0].Block.Number)
+	assert.Equal(t, argBytes(badBlock.MarshalRLP()), res[0].RLP)
+	assert.Equal(t, "invalid merkle root", res[0].Reason)
+	assert.Equal(t, []map[string]interface{}{{"type": "call"}}, res[0].Trace)
+	assert.Equal(t, argUint64(1000), res[0].Time)
+
+	// the blocks rejected before their execution have no trace
+	assert.Nil(t, res[1].Trace)
+}
+
+func TestDebugEndpointEvmProfile(t *testing.T) {
+	// the profiler is disabled
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockWitnessStore{})
+	dispatcher.enableDebug()
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "debug_evmProfile"}`), requestContext{})
+	assert.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &evmProfileResponse{}))
+
+	dispatcher = newTestDispatcher(hclog.NewNullLogger(), &mockWitnessStore{
+		profiler: evm.NewProfiler(evm.NilMetrics()),
+	})
+	dispatcher.enableDebug()
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "debug_evmProfile", "params": ["0xa", true]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res evmProfileResponse
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Empty(t, res.Ops)
+	assert.Empty(t, res.Contracts)
+	assert.NotZero(t, res.Since)
+}
+
+func TestDebugEndpointStorageRangeAt(t *testing.T) {
+	header := &types.Header{Number: 0}
+	header.ComputeHash()
+
+	key := types.StringToHash("1")
+	store := &mockWitnessStore{
+		blocks: []*types.Block{{Header: header}},
+		storage: &state.StorageRange{
+			Storage: map[types.Hash]state.StorageEntry{
+				types.StringToHash("2"): {Key: &key, Value: types.StringToHash("3")},
+				types.StringToHash("4"): {Value: types.StringToHash("5")},
+			},
+			NextKey: &key,
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	request := func(blockHash types.Hash, maxResult string) []byte {
+		return []byte(`{"method": "debug_storageRangeAt", "params": ["` + blockHash.String() + `", "0x0", "` +
+			types.StringToAddress("1").String() + `", "` + types.Hash{}.String() + `", "` + maxResult + `"]}`)
+	}
+
+	resp, err := dispatcher.Handle(request(header.Hash, "0xa"), requestContext{})
+	assert.NoError(t, err)
+
+	var res storageRangeResponse
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Equal(t, &key, res.NextKey)
+	assert.Equal(t, map[types.Hash]*storageEntryResponse{
+		types.StringToHash("2"): {Key: &key, Value: types.StringToHash("3")},
+		types.StringToHash("4"): {Value: types.StringToHash("5")},
+	}, res.Storage)
+
+	// the block must exist, and the range is capped
+	resp, err = dispatcher.Handle(request(types.StringToHash("1"), "0xa"), requestContext{})
+	assert.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &res))
+
+	resp, err = dispatcher.Handle(request(header.Hash, "0x100000"), requestContext{})
+	assert.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &res))
+}
+
+func TestDebugEndpointGetModifiedAccountsByNumber(t *testing.T) {
+	var (
+		addr1 = types.StringToAddress("1")
+		addr2 = types.StringToAddress("2")
+		addr3 = types.StringToAddress("3")
+	)
+
+	store := &mockWitnessStore{
+		modified: map[uint64][]types.Address{
+			0: {addr1},
+			1: {addr2, addr3},
+			2: {addr2},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		store.blocks = append(store.blocks, &types.Block{Header: &types.Header{Number: uint64(i)}})
+	}
+	store.header = store.blocks[2].Header
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	cases := []struct {
+		params   string
+		expected []types.Address
+	}{
+		// the accounts of the start block
+		{`["0x0"]`, []types.Address{addr1}},
+		// the accounts of the blocks after the start block, merged
+		{`["0x0", "0x2"]`, []types.Address{addr2, addr3}},
+		{`["0x1", "latest"]`, []types.Address{addr2}},
+	}
+	for _, c := range cases {
+		resp, err := dispatcher.Handle(
+			[]byte(`{"method": "debug_getModifiedAccountsByNumber", "params": `+c.params+`}`),
+			requestContext{},
+		)
+		assert.NoError(t, err)
+
+		var res []types.Address
+		assert.NoError(t, expectJSONResult(resp, &res))
+		assert.Equal(t, c.expected, res)
+	}
+
+	// the end must be after the start
+	resp, err := dispatcher.Handle(
+		[]byte(`{"method": "debug_getModifiedAccountsByNumber", "params": ["0x2", "0x1"]}`),
+		requestContext{},
+	)
+	assert.NoError(t, err)
+	assert.Error(t, expectJSONResult(resp, &[]types.Address{}))
+}
+
+func TestDebugEndpointAccountRange(t *testing.T) {
+	header := &types.Header{Number: 0, StateRoot: types.StringToHash("10")}
+	header.ComputeHash()
+
+	addr := types.StringToAddress("1")
+	store := &mockWitnessStore{
+		header: header,
+		accounts: map[types.Hash]*state.DumpAccount{
+			types.StringToHash("1"): {
+				Address:  &addr,
+				Balance:  big.NewInt(10),
+				Nonce:    1,
+				Root:     types.StringToHash("2"),
+				CodeHash: types.StringToHash("3"),
+				Code:     []byte{0x1},
+			},
+			types.StringToHash("2"): {Balance: big.NewInt(1)},
+			types.StringToHash("3"): {Balance: big.NewInt(1)},
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableDebug()
+
+	request := func(method string, params string) (*dumpResponse, error) {
+		resp, err := dispatcher.Handle([]byte(`{"method": "`+method+`", "params": [`+params+`]}`), requestContext{})
+		assert.NoError(t, err)
+
+		var res *dumpResponse
+		return res, expectJSONResult(resp, &res)
+	}
+
+	// the accounts are indexed by their address if known, by their hashed key otherwise
+	res, err := request("debug_accountRange", `"0x0", "`+types.Hash{}.String()+`", "0x2"`)
+	assert.NoError(t, err)
+	assert.Equal(t, header.StateRoot, res.Root)
+	assert.Equal(t, types.StringToHash("3"), *res.Next)
+	assert.Equal(t, map[string]*dumpAccountResponse{
+		addr.String(): {
+			Balance:  argBigPtr(big.NewInt(10)),
+			Nonce:    1,
+			Root:     types.StringToHash("2"),
+			CodeHash: types.StringToHash("3"),
+			Code:     argBytesPtr([]byte{0x1}),
+			Storage:  map[types.Hash]types.Hash{types.StringToHash("1"): types.StringToHash("2")},
+			Address:  &addr,
+			Key:      types.StringToHash("1"),
+		},
+		types.StringToHash("2").String(): {
+			Balance: argBigPtr(big.NewInt(1)),
+			Key:     types.StringToHash("2"),
+		},
+	}, res.Accounts)
+
+	// the next page, without the codes and the storages
+	res, err = request("debug_accountRange", `"latest", "`+types.StringToHash("2").String()+`", "0x2", true, true`)
+	assert.NoError(t, err)
+	assert.Nil(t, res.Next)
+	assert.Len(t, res.Accounts, 2)
+	assert.Nil(t, res.Accounts[addr.String()])
+
+	res, err = request("debug_accountRange", `"0x0", "`+types.Hash{}.String()+`", "0x0", true, true`)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Accounts)
+
+	_, err = request("debug_accountRange", `"0x0", "`+types.Hash{}.String()+`", "0x1000"`)
+	assert.Error(t, err)
+
+	// the block dump has all the accounts
+	res, err = request("debug_dumpBlock", `"0x0"`)
+	assert.NoError(t, err)
+	assert.Len(t, res.Accounts, 3)
+	assert.Nil(t, res.Next)
+	assert.Equal(t, argBytesPtr([]byte{0x1}), res.Accounts[addr.String()].Code)
+
+	_, err = request("debug_dumpBlock", `"0x1"`)
+	assert.Error(t, err)
+}
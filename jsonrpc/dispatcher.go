@@ -2,18 +2,27 @@ package jsonrpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
 	"reflect"
 	"strings"
+	"sync/atomic"
+	"time"
 	"unicode"
 
+	"github.com/0xPolygon/polygon-sdk/helper/tracing"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer records the requests, the receipt of the transactions sent to the node
+var tracer = tracing.Tracer("jsonrpc")
+
 type serviceData struct {
 	sv      reflect.Value
 	funcMap map[string]*funcData
@@ -31,10 +40,16 @@ func (f *funcData) numParams() int {
 }
 
 type endpoints struct {
-	Eth    *Eth
-	Web3   *Web3
-	Net    *Net
-	Txpool *Txpool
+	Eth      *Eth
+	Web3     *Web3
+	Net      *Net
+	Txpool   *Txpool
+	Debug    *Debug
+	Trace    *Trace
+	Personal *Personal
+	Admin    *Admin
+	Evm      *Evm
+	Bridge   *Bridge
 }
 
 // Dispatcher handles jsonrpc requests
@@ -45,6 +60,30 @@ type Dispatcher struct {
 	endpoints     endpoints
 	filterManager *FilterManager
 	chainID       uint64
+	access        *accessControl
+	accounts      accountManager
+	peers         peerManager
+	dev           devChain
+	exits         exitProver
+
+	// logsBlockRange and logsResultLimit cap the block range and the number of logs
+	// of the eth_getLogs queries. Zero means unlimited. They are accessed atomically
+	logsBlockRange  uint64
+	logsResultLimit uint64
+
+	// callTimeout (in nanoseconds) and callGasCap cap the time and the gas of the executions
+	// of eth_call and eth_estimateGas. Zero means unlimited. They are accessed atomically
+	callTimeout int64
+	callGasCap  uint64
+
+	// shedder rejects the low priority calls under load, if enabled
+	shedder *loadShedder
+
+	// callCache serves the identical eth_call and eth_estimateGas requests, if enabled
+	callCache *callCache
+
+	// metrics records the latency of the requests, if set
+	metrics *Metrics
 }
 
 // newTestDispatcher returns a dispatcher without the filter manager, used for testing
@@ -60,10 +99,15 @@ func newTestDispatcher(logger hclog.Logger, store blockchainInterface) *Dispatch
 }
 
 func newDispatcher(logger hclog.Logger, store blockchainInterface, chainID uint64) *Dispatcher {
+	return newDispatcherWithAccess(logger, store, chainID, nil)
+}
+
+func newDispatcherWithAccess(logger hclog.Logger, store blockchainInterface, chainID uint64, access *accessControl) *Dispatcher {
 	d := &Dispatcher{
 		logger:  logger.Named("dispatcher"),
 		store:   store,
 		chainID: chainID,
+		access:  access,
 	}
 	d.registerEndpoints()
 	if store != nil {
@@ -85,7 +129,89 @@ func (d *Dispatcher) registerEndpoints() {
 	d.registerService("txpool", d.endpoints.Txpool)
 }
 
-func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, Error) {
+// enableDebug registers the debug namespace, inspecting and re-executing the chain
+func (d *Dispatcher) enableDebug() {
+	d.endpoints.Debug = &Debug{d}
+
+	d.registerService("debug", d.endpoints.Debug)
+}
+
+// enableTrace registers the trace namespace, re-executing the blocks for their call traces
+func (d *Dispatcher) enableTrace() {
+	d.endpoints.Trace = &Trace{d}
+
+	d.registerService("trace", d.endpoints.Trace)
+}
+
+// enablePersonal registers the personal namespace, backed by the given keystore
+func (d *Dispatcher) enablePersonal(accounts accountManager) {
+	d.accounts = accounts
+	d.endpoints.Personal = &Personal{d}
+
+	d.registerService("personal", d.endpoints.Personal)
+}
+
+// enableAdmin registers the admin namespace, backed by the given network server
+func (d *Dispatcher) enableAdmin(peers peerManager) {
+	d.peers = peers
+	d.endpoints.Admin = &Admin{d}
+
+	d.registerService("admin", d.endpoints.Admin)
+}
+
+// enableEvm registers the evm namespace of the testing methods, backed by the dev consensus
+func (d *Dispatcher) enableEvm(dev devChain) {
+	d.dev = dev
+	d.endpoints.Evm = &Evm{d}
+
+	d.registerService("evm", d.endpoints.Evm)
+}
+
+// enableBridge registers the bridge namespace, backed by the bridge of the root chain
+func (d *Dispatcher) enableBridge(exits exitProver) {
+	d.exits = exits
+	d.endpoints.Bridge = &Bridge{d}
+
+	d.registerService("bridge", d.endpoints.Bridge)
+}
+
+// setLogsLimits replaces the limits of the eth_getLogs queries
+func (d *Dispatcher) setLogsLimits(blockRange, resultLimit uint64) {
+	atomic.StoreUint64(&d.logsBlockRange, blockRange)
+	atomic.StoreUint64(&d.logsResultLimit, resultLimit)
+}
+
+// setCallLimits replaces the limits of the eth_call and eth_estimateGas executions
+func (d *Dispatcher) setCallLimits(timeout time.Duration, gasCap uint64) {
+	atomic.StoreInt64(&d.callTimeout, int64(timeout))
+	atomic.StoreUint64(&d.callGasCap, gasCap)
+}
+
+// callLimits returns the limits of the eth_call and eth_estimateGas executions
+func (d *Dispatcher) callLimits() (time.Duration, uint64) {
+	return time.Duration(atomic.LoadInt64(&d.callTimeout)), atomic.LoadUint64(&d.callGasCap)
+}
+
+// enableLoadShedding starts rejecting the low priority calls when the thresholds are crossed
+func (d *Dispatcher) enableLoadShedding(config *LoadShedConfig) {
+	d.shedder = newLoadShedder(d.logger, config)
+
+	go d.shedder.run()
+}
+
+// enableCallCache caches the results of the eth_call and eth_estimateGas requests
+func (d *Dispatcher) enableCallCache(config *CallCacheConfig, metrics *Metrics) error {
+	cache, err := newCallCache(config, metrics)
+	if err != nil {
+		return err
+	}
+
+	d.callCache = cache
+
+	return nil
+}
+
+func (d *Dispatcher) getFnHandler(req Request, ctx requestContext) (*serviceData, *funcData, Error) {
 	callName := strings.SplitN(req.Method, "_", 2)
 	if len(callName) != 2 {
 		return nil, nil, NewMethodNotFoundError(req.Method)
@@ -93,6 +219,10 @@ func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, Error)
 
 	serviceName, funcName := callName[0], callName[1]
 
+	if err := d.checkAccess(serviceName, req.Method, ctx); err != nil {
+		return nil, nil, err
+	}
+
 	service, ok := d.serviceMap[serviceName]
 	if !ok {
 		return nil, nil, NewMethodNotFoundError(req.Method)
@@ -104,6 +234,19 @@ func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, Error)
 	return service, fd, nil
 }
 
+// checkAccess verifies the namespace can be accessed with the given request context
+func (d *Dispatcher) checkAccess(namespace, method string, ctx requestContext) Error {
+	if !d.access.isEnabled(ctx.transport, namespace) {
+		// disabled namespaces are indistinguishable from the non existing ones
+		return NewMethodNotFoundError(method)
+	}
+	if d.access.isProtected(namespace) && !ctx.authorized {
+		return NewUnauthorizedError(method)
+	}
+
+	return nil
+}
+
 type wsConn interface {
 	WriteMessage(messageType int, data []byte) error
 }
@@ -151,6 +294,12 @@ func (d *Dispatcher) handleSubscribe(req Request, conn wsConn) (string, Error) {
 		}
 		filterID = d.filterManager.NewLogFilter(logFilter, conn)
 
+	} else if subscribeMethod == "droppedTransactions" {
+		filterID = d.filterManager.NewDroppedTxFilter(conn)
+
+	} else if subscribeMethod == "reorgs" {
+		filterID = d.filterManager.NewReorgFilter(conn)
+
 	} else {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
 	}
@@ -175,13 +324,19 @@ func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 	return d.filterManager.Uninstall(filterID), nil
 }
 
-func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
+func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn, ctx requestContext) ([]byte, error) {
 	var req Request
 	if err := json.Unmarshal(reqBody, &req); err != nil {
 
 		return NewRpcResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 	}
 
+	if req.Method == "eth_subscribe" || req.Method == "eth_unsubscribe" {
+		if err := d.checkAccess("eth", req.Method, ctx); err != nil {
+			return NewRpcResponse(req.ID, "2.0", nil, err).Bytes()
+		}
+	}
+
 	// if the request method is eth_subscribe we need to create a
 	// new filter with ws connection
 	if req.Method == "eth_subscribe" {
@@ -217,14 +372,14 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	}
 
 	// its a normal query that we handle with the dispatcher
-	resp, err := d.handleReq(req)
+	resp, err := d.handleReq(req, ctx)
 	if err != nil {
 		return nil, err
 	}
 	return NewRpcResponse(req.ID, "2.0", resp, err).Bytes()
 }
 
-func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
+func (d *Dispatcher) Handle(reqBody []byte, ctx requestContext) ([]byte, error) {
 
 	x := bytes.TrimLeft(reqBody, " \t\r\n")
 	if len(x) == 0 {
@@ -240,7 +395,7 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 			return NewRpcResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 		}
 
-		resp, err := d.handleReq(req)
+		resp, err := d.handleReq(req, ctx)
 
 		return NewRpcResponse(req.ID, "2.0", resp, err).Bytes()
 	}
@@ -252,7 +407,7 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	}
 	var responses []Response
 	for _, req := range requests {
-		var response, err = d.handleReq(req)
+		var response, err = d.handleReq(req, ctx)
 		if err != nil {
 			errorResponse := NewRpcResponse(req.ID, "2.0", nil, err)
 			responses = append(responses, errorResponse)
@@ -270,14 +425,46 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	return respBytes, nil
 }
 
-func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
+// observeRequest records the latency of the request. Only the registered methods are observed,
+// so the unknown methods sent by the clients don't grow the label set
+func (d *Dispatcher) observeRequest(method string, start time.Time) {
+	d.metrics.RequestDuration.With("method", method).Observe(time.Since(start).Seconds())
+}
+
+func (d *Dispatcher) handleReq(req Request, ctx requestContext) ([]byte, Error) {
 	d.logger.Debug("request", "method", req.Method, "id", req.ID)
 
-	service, fd, ferr := d.getFnHandler(req)
+	service, fd, ferr := d.getFnHandler(req, ctx)
 	if ferr != nil {
 		return nil, ferr
 	}
 
+	if d.metrics != nil {
+		defer d.observeRequest(req.Method, time.Now())
+	}
+
+	if d.shedder != nil {
+		defer d.shedder.track()()
+
+		if d.isLowPriority(req) {
+			if err := d.shedder.admit(req.Method); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, span := tracer.Start(context.Background(), "jsonrpc."+req.Method, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	data, err := d.invoke(service, fd, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return data, err
+}
+
+// invoke calls the handler of the request with its decoded params and encodes the result
+func (d *Dispatcher) invoke(service *serviceData, fd *funcData, req Request) ([]byte, Error) {
 	inArgs := make([]reflect.Value, fd.inNum)
 	inArgs[0] = service.sv
 
@@ -296,6 +483,10 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 	output := fd.fv.Call(inArgs)
 	if err := getError(output[1]); err != nil {
 		d.logInternalError(req.Method, err)
+		if dataErr, ok := err.(DataError); ok {
+			// the errors with data are returned as they are, so the data reaches the caller
+			return nil, dataErr
+		}
 		return nil, NewInvalidRequestError(err.Error())
 	}
 
@@ -310,7 +501,6 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 		}
 	}
 	return data, nil
-
 }
 
 func (d *Dispatcher) logInternalError(method string, err error) {
@@ -423,7 +613,11 @@ func (d *Dispatcher) getBlockHeaderImpl(number BlockNumber) (*types.Header, erro
 		return nil, fmt.Errorf("fetching the earliest header is not supported")
 
 	case PendingBlockNumber:
-		return nil, fmt.Errorf("fetching the pending header is not supported")
+		block, err := d.store.PendingBlock()
+		if err != nil {
+			return nil, err
+		}
+		return block.Header, nil
 
 	default:
 		// Convert the block number from hex to uint64
@@ -435,6 +629,25 @@ func (d *Dispatcher) getBlockHeaderImpl(number BlockNumber) (*types.Header, erro
 	}
 }
 
+// getStateHeaderImpl returns the header of the block whose state is queried,
+// or an error if the node doesn't have the state of the block
+func (d *Dispatcher) getStateHeaderImpl(number BlockNumber) (*types.Header, error) {
+	header, err := d.getBlockHeaderImpl(number)
+	if err != nil {
+		return nil, err
+	}
+
+	// the pending state is built on top of the head state
+	if number == PendingBlockNumber {
+		return header, nil
+	}
+	if err := d.store.CheckState(header); err != nil {
+		return nil, NewStateUnavailableError(err.Error(), header.Number)
+	}
+
+	return header, nil
+}
+
 func (d *Dispatcher) getNextNonce(address types.Address, number BlockNumber) (uint64, error) {
 	if number == PendingBlockNumber {
 		res, ok := d.store.GetNonce(address)
@@ -443,7 +656,7 @@ func (d *Dispatcher) getNextNonce(address types.Address, number BlockNumber) (ui
 		}
 		number = LatestBlockNumber
 	}
-	header, err := d.getBlockHeaderImpl(number)
+	header, err := d.getStateHeaderImpl(number)
 	if err != nil {
 		return 0, err
 	}
@@ -474,7 +687,18 @@ func (d *Dispatcher) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 	if arg.Value == nil {
 		arg.Value = argBytesPtr([]byte{})
 	}
-	if arg.GasPrice == nil {
+	dynamicFee := arg.MaxFeePerGas != nil || arg.MaxPriorityFeePerGas != nil
+	if dynamicFee {
+		if arg.GasPrice != nil {
+			return nil, fmt.Errorf("both gasPrice and maxFeePerGas or maxPriorityFeePerGas specified")
+		}
+		if arg.MaxFeePerGas == nil {
+			arg.MaxFeePerGas = argBytesPtr([]byte{})
+		}
+		if arg.MaxPriorityFeePerGas == nil {
+			arg.MaxPriorityFeePerGas = argBytesPtr([]byte{})
+		}
+	} else if arg.GasPrice == nil {
 		arg.GasPrice = argBytesPtr([]byte{})
 	}
 
@@ -498,12 +722,28 @@ func (d *Dispatcher) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 	}
 
 	txn := &types.Transaction{
-		From:     *arg.From,
-		Gas:      uint64(*arg.Gas),
-		GasPrice: new(big.Int).SetBytes(*arg.GasPrice),
-		Value:    new(big.Int).SetBytes(*arg.Value),
-		Input:    input,
-		Nonce:    uint64(*arg.Nonce),
+		From:  *arg.From,
+		Gas:   uint64(*arg.Gas),
+		Value: new(big.Int).SetBytes(*arg.Value),
+		Input: input,
+		Nonce: uint64(*arg.Nonce),
+	}
+	if dynamicFee {
+		// the gas price of a dynamic fee transaction is its fee cap
+		txn.Type = types.DynamicFeeTx
+		txn.ChainID = new(big.Int).SetUint64(d.chainID)
+		txn.GasPrice = new(big.Int).SetBytes(*arg.MaxFeePerGas)
+		txn.GasTipCap = new(big.Int).SetBytes(*arg.MaxPriorityFeePerGas)
+	} else {
+		txn.GasPrice = new(big.Int).SetBytes(*arg.GasPrice)
+	}
+	if arg.AccessList != nil {
+		// a gas price with an access list is an access list transaction
+		if !dynamicFee {
+			txn.Type = types.AccessListTx
+			txn.ChainID = new(big.Int).SetUint64(d.chainID)
+		}
+		txn.AccessList = arg.AccessList.Copy()
 	}
 	if arg.To != nil {
 		txn.To = arg.To
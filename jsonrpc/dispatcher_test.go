@@ -65,7 +65,7 @@ func TestDispatcherWebsocket(t *testing.T) {
 		"method": "eth_subscribe",
 		"params": ["newHeads"]
 	}`)
-	if _, err := s.HandleWs(req, mock); err != nil {
+	if _, err := s.HandleWs(req, mock, requestContext{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -141,7 +141,7 @@ func TestDispatcherWebsocketRequestFormats(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		data, err := s.HandleWs(c.msg, mock)
+		data, err := s.HandleWs(c.msg, mock, requestContext{})
 		resp := new(SuccessResponse)
 		merr := json.Unmarshal(data, resp)
 		if merr != nil {
@@ -194,7 +194,7 @@ func TestDispatcherFuncDecode(t *testing.T) {
 		_, err := s.handleReq(Request{
 			Method: "mock_" + typ,
 			Params: []byte(msg),
-		})
+		}, requestContext{})
 		assert.NoError(t, err)
 		return <-srv.msgCh
 	}
@@ -261,7 +261,7 @@ func TestDispatcherBatchRequest(t *testing.T) {
     {"id":2,"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["0x2", true]},
     {"id":3,"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["0x3", true]},
 	{"id":4,"jsonrpc":"2.0","method": "web3_sha3","params": ["0x68656c6c6f20776f726c64"]}
-]`)...))
+]`)...), requestContext{})
 	assert.NoError(t, err)
 
 	var res []SuccessResponse
@@ -272,6 +272,95 @@ func TestDispatcherBatchRequest(t *testing.T) {
 	assert.Nil(t, res[3].Error)
 }
 
+func TestDispatcherAccessControl(t *testing.T) {
+	access := newAccessControl(&AccessConfig{
+		Namespaces: map[string][]string{
+			"http": {"eth", "txpool"},
+		},
+		Protected: []string{"txpool"},
+		AuthToken: "secret",
+	})
+
+	s := newDispatcherWithAccess(hclog.NewNullLogger(), newMockStore(), 0, access)
+
+	cases := []struct {
+		method string
+		ctx    requestContext
+		code   int
+	}{
+		{
+			// namespace not enabled on the transport
+			"web3_clientVersion",
+			requestContext{transport: serverHTTP},
+			-32601,
+		},
+		{
+			// no namespace restrictions on the transport
+			"web3_clientVersion",
+			requestContext{transport: serverWS},
+			0,
+		},
+		{
+			// protected namespace without authorization
+			"txpool_status",
+			requestContext{transport: serverHTTP},
+			-32001,
+		},
+		{
+			"txpool_status",
+			requestContext{transport: serverHTTP, authorized: true},
+			0,
+		},
+	}
+
+	for _, c := range cases {
+		resp, err := s.Handle([]byte(`{"method": "`+c.method+`", "params": []}`), c.ctx)
+		assert.NoError(t, err)
+
+		var res SuccessResponse
+		assert.NoError(t, json.Unmarshal(resp, &res))
+
+		if c.code == 0 {
+			assert.Nil(t, res.Error, c.method)
+		} else {
+			assert.NotNil(t, res.Error, c.method)
+			assert.Equal(t, c.code, res.Error.Code, c.method)
+		}
+	}
+}
+
+func TestDispatcherWebsocketAccessControl(t *testing.T) {
+	access := newAccessControl(&AccessConfig{
+		Namespaces: map[string][]string{
+			"ws": {"net"},
+		},
+	})
+
+	s := newDispatcherWithAccess(hclog.NewNullLogger(), newMockStore(), 0, access)
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+
+	for _, method := range []string{"eth_subscribe", "eth_unsubscribe"} {
+		resp, err := s.HandleWs([]byte(`{"method": "`+method+`", "params": ["newHeads"]}`), mock, requestContext{transport: serverWS})
+		assert.NoError(t, err)
+
+		var res SuccessResponse
+		assert.NoError(t, json.Unmarshal(resp, &res))
+		assert.NotNil(t, res.Error, method)
+		assert.Equal(t, -32601, res.Error.Code, method)
+	}
+
+	// the subscriptions are still available over the other transports
+	resp, err := s.HandleWs([]byte(`{"method": "eth_subscribe", "params": ["newHeads"]}`), mock, requestContext{transport: serverHTTP})
+	assert.NoError(t, err)
+
+	var res SuccessResponse
+	assert.NoError(t, json.Unmarshal(resp, &res))
+	assert.Nil(t, res.Error)
+}
+
 func TestDecodeTxn(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -421,6 +510,66 @@ func TestDecodeTxn(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "should be failed when both GasPrice and MaxFeePerGas are given",
+			arg: &txnArgs{
+				From:         &addr1,
+				To:           &addr2,
+				GasPrice:     toArgBytesPtr(big.NewInt(10000).Bytes()),
+				MaxFeePerGas: toArgBytesPtr(big.NewInt(10000).Bytes()),
+				Nonce:        toArgUint64Ptr(1),
+			},
+			res: nil,
+			err: errors.New("both gasPrice and maxFeePerGas or maxPriorityFeePerGas specified"),
+		},
+		{
+			name: "should build a dynamic fee transaction",
+			arg: &txnArgs{
+				From:                 &addr1,
+				To:                   &addr2,
+				Gas:                  toArgUint64Ptr(21000),
+				MaxFeePerGas:         toArgBytesPtr(big.NewInt(10000).Bytes()),
+				MaxPriorityFeePerGas: toArgBytesPtr(big.NewInt(100).Bytes()),
+				Nonce:                toArgUint64Ptr(1),
+			},
+			res: &types.Transaction{
+				Type:      types.DynamicFeeTx,
+				ChainID:   big.NewInt(0),
+				From:      addr1,
+				To:        &addr2,
+				Gas:       21000,
+				GasPrice:  big.NewInt(10000),
+				GasTipCap: big.NewInt(100),
+				Value:     new(big.Int).SetBytes([]byte{}),
+				Input:     []byte{},
+				Nonce:     1,
+			},
+			err: nil,
+		},
+		{
+			name: "should build an access list transaction",
+			arg: &txnArgs{
+				From:       &addr1,
+				To:         &addr2,
+				Gas:        toArgUint64Ptr(30000),
+				GasPrice:   toArgBytesPtr(big.NewInt(10000).Bytes()),
+				Nonce:      toArgUint64Ptr(1),
+				AccessList: &types.AccessList{{Address: addr2, StorageKeys: []types.Hash{{0x1}}}},
+			},
+			res: &types.Transaction{
+				Type:       types.AccessListTx,
+				ChainID:    big.NewInt(0),
+				From:       addr1,
+				To:         &addr2,
+				Gas:        30000,
+				GasPrice:   big.NewInt(10000),
+				Value:      new(big.Int).SetBytes([]byte{}),
+				Input:      []byte{},
+				Nonce:      1,
+				AccessList: types.AccessList{{Address: addr2, StorageKeys: []types.Hash{{0x1}}}},
+			},
+			err: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -440,3 +589,25 @@ func TestDecodeTxn(t *testing.T) {
 		})
 	}
 }
+
+type mockLimitService struct{}
+
+func (m *mockLimitService) Query() (interface{}, error) {
+	return nil, NewLimitExceededError("limit exceeded", map[string]string{"cursor": "0x2"})
+}
+
+func TestDispatcherErrorData(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+	dispatcher.registerService("mock", &mockLimitService{})
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "mock_query"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res ErrorResponse
+	assert.NoError(t, json.Unmarshal(resp, &res))
+
+	// the data errors keep their code and data
+	assert.Equal(t, -32005, res.Error.Code)
+	assert.Equal(t, "limit exceeded", res.Error.Message)
+	assert.Equal(t, map[string]interface{}{"cursor": "0x2"}, res.Error.Data)
+}
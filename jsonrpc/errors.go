@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -13,6 +14,13 @@ type Error interface {
 	Error() string
 	ErrorCode() int
 }
+
+// DataError is an error that carries additional information in the data field of the response
+type DataError interface {
+	Error
+	ErrorData() interface{}
+}
+
 type invalidParamsError struct {
 	err string
 }
@@ -61,6 +69,72 @@ func (e *subscriptionNotFoundError) ErrorCode() int {
 	return -32601
 }
 
+type unauthorizedError struct {
+	err string
+}
+
+func (e *unauthorizedError) Error() string {
+	return e.err
+}
+
+func (e *unauthorizedError) ErrorCode() int {
+	return -32001
+}
+
+type limitExceededError struct {
+	err  string
+	data interface{}
+}
+
+func (e *limitExceededError) Error() string {
+	return e.err
+}
+
+func (e *limitExceededError) ErrorCode() int {
+	return -32005
+}
+
+func (e *limitExceededError) ErrorData() interface{} {
+	return e.data
+}
+
+// stateUnavailableError is returned when the state of the queried block is not stored,
+// its data is the number of the block
+type stateUnavailableError struct {
+	err    string
+	number uint64
+}
+
+func (e *stateUnavailableError) Error() string {
+	return e.err
+}
+
+func (e *stateUnavailableError) ErrorCode() int {
+	return -32000
+}
+
+func (e *stateUnavailableError) ErrorData() interface{} {
+	return argUint64(e.number)
+}
+
+// executionTimeoutError is returned when a simulated execution runs longer than the call timeout,
+// its data is the timeout in milliseconds
+type executionTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *executionTimeoutError) Error() string {
+	return fmt.Sprintf("execution aborted (timeout = %s)", e.timeout)
+}
+
+func (e *executionTimeoutError) ErrorCode() int {
+	return -32000
+}
+
+func (e *executionTimeoutError) ErrorData() interface{} {
+	return argUint64(e.timeout.Milliseconds())
+}
+
 type methodNotFoundError struct {
 	err string
 }
@@ -95,3 +169,22 @@ func NewSubscriptionNotFoundError(method string) *subscriptionNotFoundError {
 	e := &subscriptionNotFoundError{fmt.Sprintf("subscribe method %s not found", method)}
 	return e
 }
+
+func NewUnauthorizedError(method string) *unauthorizedError {
+	e := &unauthorizedError{fmt.Sprintf("the method %s requires authorization", method)}
+	return e
+}
+
+func NewLimitExceededError(msg string, data interface{}) *limitExceededError {
+	e := &limitExceededError{msg, data}
+	return e
+}
+
+func NewStateUnavailableError(msg string, number uint64) *stateUnavailableError {
+	e := &stateUnavailableError{msg, number}
+	return e
+}
+
+func NewExecutionTimeoutError(timeout time.Duration) *executionTimeoutError {
+	return &executionTimeoutError{timeout}
+}
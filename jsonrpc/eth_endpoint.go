@@ -1,11 +1,17 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"sync/atomic"
+	"time"
 
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/crypto"
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
 	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/umbracle/fastrlp"
 )
@@ -40,7 +46,16 @@ func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
 }
 
 // GetBlockByNumber returns information about a block by block number
+// The pending block is a speculative block built from the pending transactions of the pool
 func (e *Eth) GetBlockByNumber(number BlockNumber, fullTx bool) (interface{}, error) {
+	if number == PendingBlockNumber {
+		block, err := e.d.store.PendingBlock()
+		if err != nil {
+			return nil, err
+		}
+		return toBlock(block, fullTx), nil
+	}
+
 	num, err := GetNumericBlockNumber(number, e)
 	if err != nil {
 		return nil, err
@@ -82,8 +97,20 @@ func (e *Eth) BlockNumber() (interface{}, error) {
 	return argUintPtr(h.Number), nil
 }
 
-// SendRawTransaction sends a raw transaction
-func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
+// Syncing returns false if the node isn't syncing, else the progress of the sync
+// with the counters of its stages and the estimated time left
+func (e *Eth) Syncing() (interface{}, error) {
+	progress := e.d.store.SyncProgress()
+	if progress == nil {
+		return false, nil
+	}
+
+	return toSyncProgress(progress), nil
+}
+
+// SendRawTransaction sends a raw transaction. The optional inclusion bounds make the pool
+// drop the transaction once they are reached, so it is never included afterwards
+func (e *Eth) SendRawTransaction(input string, bounds *inclusionBounds) (interface{}, error) {
 	buf := hex.MustDecodeHex(input)
 
 	tx := &types.Transaction{}
@@ -92,6 +119,13 @@ func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
 	}
 	tx.ComputeHash()
 
+	if bounds != nil {
+		if err := e.d.store.AddTxWithExpiry(tx, bounds.toExpiry()); err != nil {
+			return nil, err
+		}
+		return tx.Hash.String(), nil
+	}
+
 	if err := e.d.store.AddTx(tx); err != nil {
 		return nil, err
 	}
@@ -99,8 +133,17 @@ func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
 }
 
 // SendTransaction creates new message call transaction or a contract creation, if the data field contains code.
+// If the sender is an account of the node keystore, the transaction is signed with its unlocked key
 func (e *Eth) SendTransaction(arg *txnArgs) (interface{}, error) {
-	transaction, err := e.d.decodeTxn(arg)
+	var (
+		transaction *types.Transaction
+		err         error
+	)
+	if arg.From != nil && e.d.accounts != nil && e.d.accounts.HasAccount(*arg.From) {
+		transaction, err = e.d.signTransaction(arg)
+	} else {
+		transaction, err = e.d.decodeTxn(arg)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +153,51 @@ func (e *Eth) SendTransaction(arg *txnArgs) (interface{}, error) {
 	return transaction.Hash.String(), nil
 }
 
+// Sign returns the signature of the data by the unlocked account of the node keystore.
+// The data is prefixed as in EIP-191, so that the signature can't be used for a transaction
+func (e *Eth) Sign(addr types.Address, data argBytes) (interface{}, error) {
+	if e.d.accounts == nil {
+		return nil, errNoKeystore
+	}
+
+	sig, err := e.d.accounts.SignHash(addr, signHash(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return walletSignature(sig), nil
+}
+
+// SignTypedData_v4 returns the signature of the structured data by the unlocked account of the node keystore,
+// as in EIP-712. The typed data is a JSON object, or a string of it as sent by the wallets
+func (e *Eth) SignTypedData_v4(addr types.Address, data json.RawMessage) (interface{}, error) {
+	if e.d.accounts == nil {
+		return nil, errNoKeystore
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		data = json.RawMessage(str)
+	}
+
+	var typed crypto.TypedData
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, fmt.Errorf("invalid typed data: %v", err)
+	}
+
+	hash, err := crypto.HashTypedData(&typed)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := e.d.accounts.SignHash(addr, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return walletSignature(sig), nil
+}
+
 // GetTransactionByHash returns a transaction by his hash
 func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 	blockHash, ok := e.d.store.ReadTxLookup(hash)
@@ -131,6 +219,94 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 	return nil, nil
 }
 
+const (
+	// defaultAddressTxsLimit is the size of the pages of eth_getTransactionsByAddress without a limit
+	defaultAddressTxsLimit = 100
+
+	// maxAddressTxsLimit is the largest page of eth_getTransactionsByAddress
+	maxAddressTxsLimit = 1000
+)
+
+// addressTxs is a page of the transactions of an address. Next is the cursor of the next page,
+// and IndexedFrom the first block of the index, the older transactions are not returned
+type addressTxs struct {
+	Transactions []*transaction `json:"transactions"`
+	Next         *argUint64     `json:"next"`
+	IndexedFrom  argUint64      `json:"indexedFrom"`
+}
+
+// GetTransactionsByAddress returns a page of the transactions sent, received or creating a contract
+// by the address, the newest first. The cursor is the next one of the previous page.
+// It requires the node to run with the index of the transactions by address
+func (e *Eth) GetTransactionsByAddress(address types.Address, cursor *argUint64, limit *argUint64) (interface{}, error) {
+	from, size := uint64(0), uint64(defaultAddressTxsLimit)
+	if cursor != nil {
+		from = uint64(*cursor)
+	}
+	if limit != nil {
+		size = uint64(*limit)
+	}
+
+	page, txs, err := e.d.addressTxs(address, from, size)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &addressTxs{
+		Transactions: []*transaction{},
+		IndexedFrom:  argUint64(page.IndexedFrom),
+	}
+	if page.Next != 0 {
+		res.Next = argUintPtr(page.Next)
+	}
+	for _, txn := range txs {
+		res.Transactions = append(res.Transactions, toTransaction(txn.block.Transactions[txn.index], txn.block, txn.index))
+	}
+
+	return res, nil
+}
+
+// blockTx is a transaction of a block
+type blockTx struct {
+	block *types.Block
+	index int
+}
+
+// addressTxs returns a page of the index of the transactions by address, with the transactions
+func (d *Dispatcher) addressTxs(addr types.Address, cursor, limit uint64) (*blockchain.AddressTxsPage, []*blockTx, error) {
+	if limit == 0 || limit > maxAddressTxsLimit {
+		return nil, nil, NewInvalidParamsError(fmt.Sprintf("the limit must be between 1 and %d", maxAddressTxsLimit))
+	}
+
+	page, err := d.store.GetAddressTxs(addr, cursor, int(limit))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the transactions of a page are often in the same blocks
+	blocks := map[types.Hash]*types.Block{}
+	txs := []*blockTx{}
+	for _, entry := range page.Txs {
+		block, ok := blocks[entry.BlockHash]
+		if !ok {
+			if block, ok = d.store.GetBlockByHash(entry.BlockHash, true); !ok {
+				return nil, nil, fmt.Errorf("block %s not found", entry.BlockHash)
+			}
+			blocks[entry.BlockHash] = block
+		}
+
+		for index, txn := range block.Transactions {
+			if txn.Hash == entry.TxHash {
+				txs = append(txs, &blockTx{block: block, index: index})
+
+				break
+			}
+		}
+	}
+
+	return page, txs, nil
+}
+
 // GetTransactionReceipt returns a transaction receipt by his hash
 func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 	blockHash, ok := e.d.store.ReadTxLookup(hash)
@@ -202,6 +378,12 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 		ToAddr:            txn.To,
 		Logs:              logs,
 	}
+	if txn.GasPrice != nil {
+		res.EffectiveGasPrice = argBig(*txn.EffectiveGasPrice(block.Header.BaseFee))
+	}
+	if reason, ok := decodeRevertReason(raw.RevertData); ok {
+		res.RevertReason = reason
+	}
 	return res, nil
 }
 
@@ -213,7 +395,7 @@ func (e *Eth) GetStorageAt(address types.Address, index types.Hash, number *Bloc
 		number, _ = createBlockNumberPointer("latest")
 	}
 	// Fetch the requested header
-	header, err := e.d.getBlockHeaderImpl(*number)
+	header, err := e.d.getStateHeaderImpl(*number)
 	if err != nil {
 		return nil, err
 	}
@@ -247,8 +429,9 @@ func (e *Eth) GasPrice() (interface{}, error) {
 	return avgGasPrice, nil
 }
 
-// Call executes a smart contract call using the transaction object data
-func (e *Eth) Call(arg *txnArgs, number *BlockNumber) (interface{}, error) {
+// Call executes a smart contract call using the transaction object data,
+// on top of the accounts replaced by the optional state override
+func (e *Eth) Call(arg *txnArgs, number *BlockNumber, override *stateOverride) (interface{}, error) {
 
 	if number == nil {
 		number, _ = createBlockNumberPointer("latest")
@@ -258,7 +441,7 @@ func (e *Eth) Call(arg *txnArgs, number *BlockNumber) (interface{}, error) {
 		return nil, err
 	}
 	// Fetch the requested header
-	header, err := e.d.getBlockHeaderImpl(*number)
+	header, err := e.d.getStateHeaderImpl(*number)
 	if err != nil {
 		return nil, err
 	}
@@ -268,16 +451,37 @@ func (e *Eth) Call(arg *txnArgs, number *BlockNumber) (interface{}, error) {
 		transaction.Gas = header.GasLimit
 	}
 
-	// The return value of the execution is saved in the transition (returnValue field)
-	result, err := e.d.store.ApplyTxn(header, transaction)
-	if err != nil {
-		return nil, err
+	// The gas of the call is capped by the gas cap of the node
+	timeout, gasCap := e.d.callLimits()
+	if gasCap != 0 && transaction.Gas > gasCap {
+		transaction.Gas = gasCap
 	}
 
-	if result.Failed() {
-		return nil, fmt.Errorf("unable to execute call")
+	run := func() (interface{}, error) {
+		// The return value of the execution is saved in the transition (returnValue field)
+		result, err := e.d.store.ApplyTxn(header, transaction, override.toState(), timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Err == runtime.ErrExecutionInterrupted {
+			return nil, NewExecutionTimeoutError(timeout)
+		}
+
+		if result.Reverted() {
+			return nil, newRevertError(result.ReturnValue)
+		}
+		if result.Failed() {
+			return nil, fmt.Errorf("unable to execute call")
+		}
+		return argBytesPtr(result.ReturnValue), nil
 	}
-	return argBytesPtr(result.ReturnValue), nil
+
+	// The pending block changes with the pool, so its calls are not cached
+	if *number == PendingBlockNumber {
+		return run()
+	}
+	return e.d.callCache.cached("eth_call", header, run, arg, override)
 }
 
 // EstimateGas estimates the gas needed to execute a transaction
@@ -296,28 +500,43 @@ func (e *Eth) EstimateGas(
 	}
 
 	// Fetch the requested header
-	header, err := e.d.getBlockHeaderImpl(number)
+	header, err := e.d.getStateHeaderImpl(number)
 	if err != nil {
 		return nil, err
 	}
 
-	forksInTime := e.d.store.GetForksInTime(uint64(number))
+	run := func() (interface{}, error) {
+		return e.estimateGas(transaction, header)
+	}
 
-	var standardGas uint64
-	if transaction.IsContractCreation() && forksInTime.Homestead {
-		standardGas = state.TxGasContractCreation
-	} else {
-		standardGas = state.TxGas
+	// The pending block changes with the pool, so its estimations are not cached
+	if number == PendingBlockNumber {
+		return run()
+	}
+	return e.d.callCache.cached("eth_estimateGas", header, run, arg)
+}
+
+// estimateGas runs the binary search of the lowest gas limit the transaction succeeds with.
+// The transaction is run with the highest gas first, so that the failures are returned with their revert reason,
+// and the gas it consumed before its refunds bounds the search from below
+func (e *Eth) estimateGas(
+	transaction *types.Transaction,
+	header *types.Header,
+) (interface{}, error) {
+	forksInTime := e.d.store.GetForksInTime(header.Number)
+
+	intrinsicGas, err := state.TransactionGasCost(transaction, forksInTime.Homestead, forksInTime.Istanbul)
+	if err != nil {
+		return nil, err
 	}
 
 	var (
-		lowEnd  = standardGas
 		highEnd uint64
 		gasCap  uint64
 	)
 
 	// If the gas limit was passed in, use it as a ceiling
-	if transaction.Gas != 0 && transaction.Gas >= standardGas {
+	if transaction.Gas != 0 && transaction.Gas >= intrinsicGas {
 		highEnd = transaction.Gas
 	} else {
 		// If not, use the referenced block number
@@ -358,59 +577,280 @@ func (e *Eth) EstimateGas(
 		highEnd = types.GasCap.Uint64()
 	}
 
+	timeout, callGasCap := e.d.callLimits()
+	if callGasCap != 0 && highEnd > callGasCap {
+		// The high end is greater than the gas cap of the node
+		highEnd = callGasCap
+	}
+
 	gasCap = highEnd
 
+	// The whole search runs within the timeout
+	var deadline time.Time
+	if timeout != 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
 	// Run the transaction with the estimated gas
-	testTransaction := func(gas uint64) (bool, error) {
+	testTransaction := func(gas uint64) (*runtime.ExecutionResult, error) {
+		remaining := time.Duration(0)
+		if timeout != 0 {
+			if remaining = time.Until(deadline); remaining <= 0 {
+				return nil, NewExecutionTimeoutError(timeout)
+			}
+		}
+
 		// Create a dummy transaction with the new gas
 		txn := transaction.Copy()
 		txn.Gas = gas
 
-		result, err := e.d.store.ApplyTxn(header, txn)
+		result, err := e.d.store.ApplyTxn(header, txn, nil, remaining)
 
 		if err != nil {
-			return true, err
+			return nil, err
+		}
+
+		if result.Err == runtime.ErrExecutionInterrupted {
+			return nil, NewExecutionTimeoutError(timeout)
+		}
+
+		return result, nil
+	}
+
+	if gasCap < intrinsicGas {
+		return 0, fmt.Errorf("gas required exceeds allowance (%d)", gasCap)
+	}
+
+	// Check the edge case if even the highest cap is not enough to complete the transaction
+	result, err := testTransaction(gasCap)
+	if err != nil {
+		return 0, err
+	}
+	if result.Failed() {
+		switch result.Err {
+		case runtime.ErrExecutionReverted:
+			return 0, newRevertError(result.ReturnValue)
+		case runtime.ErrOutOfGas, runtime.ErrCodeStoreOutOfGas:
+			return 0, fmt.Errorf("gas required exceeds allowance (%d)", gasCap)
+		default:
+			return 0, fmt.Errorf("execution failed: %v", result.Err)
 		}
+	}
 
-		return result.Failed(), nil
+	// The transaction fails below the gas it consumed before its refunds, and below its intrinsic gas.
+	// The search keeps the highest failing gas in lowEnd, and the lowest succeeding one in highEnd
+	lowEnd := intrinsicGas - 1
+	if consumed := result.GasUsed + result.GasRefunded; consumed > 0 && consumed-1 > lowEnd {
+		lowEnd = consumed - 1
+	}
+	highEnd = gasCap
+
+	// Most transactions succeed with the gas they consumed, plus the stipend of the calls with value
+	// and the 1/64 of the gas kept by every call after EIP-150, which is tried first
+	optimistic := (result.GasUsed + result.GasRefunded + runtime.CallStipend) * 64 / 63
+	if optimistic > lowEnd && optimistic < highEnd {
+		result, err := testTransaction(optimistic)
+		if err != nil {
+			return 0, err
+		}
+		if result.Failed() {
+			lowEnd = optimistic
+		} else {
+			highEnd = optimistic
+		}
 	}
 
-	// Start the binary search for the lowest possible gas price
-	for lowEnd <= highEnd {
-		mid := (lowEnd + highEnd) / 2
+	// Start the binary search for the lowest possible gas
+	for lowEnd+1 < highEnd {
+		mid := lowEnd + (highEnd-lowEnd)/2
 
-		failed, err := testTransaction(mid)
+		result, err := testTransaction(mid)
 		if err != nil {
 			return 0, err
 		}
 
-		if failed {
+		if result.Failed() {
 			// If the transaction failed => increase the gas
-			lowEnd = mid + 1
+			lowEnd = mid
 		} else {
 			// If the transaction didn't fail => lower the gas
-			highEnd = mid - 1
+			highEnd = mid
 		}
 	}
 
-	// we stopped the binary search at the last gas limit
-	// at which the txn could not be executed
-	highEnd += 1
+	return hex.EncodeUint64(highEnd), nil
+}
 
-	// Check the edge case if even the highest cap is not enough to complete the transaction
-	if highEnd == gasCap {
-		failed, err := testTransaction(gasCap)
+// callBundleMaxTxs is the largest number of transactions of a bundle simulated by eth_callBundle
+const callBundleMaxTxs = 256
 
-		if err != nil {
-			return 0, err
+// callBundleArgs is the bundle of eth_callBundle: the signed transactions, executed in order on top of
+// the state of the block, in the context of the block following it
+type callBundleArgs struct {
+	Txs              []argBytes   `json:"txs"`
+	StateBlockNumber *BlockNumber `json:"stateBlockNumber"`
+	Timestamp        *argUint64   `json:"timestamp"`
+}
+
+type bundleTxResponse struct {
+	TxHash          types.Hash     `json:"txHash"`
+	From            types.Address  `json:"fromAddress"`
+	To              *types.Address `json:"toAddress"`
+	Value           *argBig        `json:"value"`
+	GasUsed         argUint64      `json:"gasUsed"`
+	GasPrice        *argBig        `json:"gasPrice"`
+	GasFees         *argBig        `json:"gasFees"`
+	Status          argUint64      `json:"status"`
+	ContractAddress *types.Address `json:"contractAddress,omitempty"`
+	Output          argBytes       `json:"output"`
+	Error           string         `json:"error,omitempty"`
+	RevertReason    string         `json:"revertReason,omitempty"`
+	Logs            []*Log         `json:"logs"`
+}
+
+type bundleAccountState struct {
+	Balance *argBig                   `json:"balance,omitempty"`
+	Nonce   *argUint64                `json:"nonce,omitempty"`
+	Code    *argBytes                 `json:"code,omitempty"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+type bundleStateDiff struct {
+	Pre  map[types.Address]*bundleAccountState `json:"pre"`
+	Post map[types.Address]*bundleAccountState `json:"post"`
+}
+
+type callBundleResponse struct {
+	BundleHash       types.Hash          `json:"bundleHash"`
+	StateBlockNumber argUint64           `json:"stateBlockNumber"`
+	BlockNumber      argUint64           `json:"blockNumber"`
+	Results          []*bundleTxResponse `json:"results"`
+	TotalGasUsed     argUint64           `json:"totalGasUsed"`
+	CoinbaseDiff     *argBig             `json:"coinbaseDiff"`
+	StateDiff        *bundleStateDiff    `json:"stateDiff"`
+}
+
+func newBundleAccountStates(states map[types.Address]*state.AccountState) map[types.Address]*bundleAccountState {
+	res := make(map[types.Address]*bundleAccountState, len(states))
+	for addr, account := range states {
+		entry := &bundleAccountState{Storage: account.Storage}
+		if account.Balance != nil {
+			entry.Balance = argBigPtr(account.Balance)
 		}
+		if account.Nonce != nil {
+			nonce := argUint64(*account.Nonce)
+			entry.Nonce = &nonce
+		}
+		if account.Code != nil {
+			entry.Code = argBytesPtr(account.Code)
+		}
+		res[addr] = entry
+	}
 
-		if failed {
-			return 0, fmt.Errorf("gas required exceeds allowance (%d)", gasCap)
+	return res
+}
+
+// CallBundle simulates an ordered bundle of signed transactions on top of the state of a block, in the context
+// of the block following it, and returns the result, the gas, the fees and the logs of every transaction,
+// with the state changed by the bundle. The reverted transactions are included, the bundle fails if a transaction
+// can't be included in a block, like with an invalid nonce. The simulated state is discarded
+func (e *Eth) CallBundle(args *callBundleArgs) (interface{}, error) {
+	if len(args.Txs) == 0 {
+		return nil, NewInvalidParamsError("the bundle has no transactions")
+	}
+	if len(args.Txs) > callBundleMaxTxs {
+		return nil, NewInvalidParamsError(fmt.Sprintf("the bundle is limited to %d transactions", callBundleMaxTxs))
+	}
+
+	number := LatestBlockNumber
+	if args.StateBlockNumber != nil {
+		number = *args.StateBlockNumber
+	}
+	parent, err := e.d.getStateHeaderImpl(number)
+	if err != nil {
+		return nil, err
+	}
+
+	// the block follows its parent by a second, unless set
+	timestamp := parent.Timestamp + 1
+	if args.Timestamp != nil {
+		timestamp = uint64(*args.Timestamp)
+	}
+
+	txs := make([]*types.Transaction, 0, len(args.Txs))
+	hashes := make([]byte, 0, len(args.Txs)*types.HashLength)
+	for i, raw := range args.Txs {
+		tx := &types.Transaction{}
+		if err := tx.UnmarshalRLP(raw); err != nil {
+			return nil, NewInvalidParamsError(fmt.Sprintf("transaction %d: %v", i, err))
 		}
+		tx.ComputeHash()
+
+		txs = append(txs, tx)
+		hashes = append(hashes, tx.Hash.Bytes()...)
 	}
 
-	return hex.EncodeUint64(highEnd), nil
+	res, err := e.d.store.SimulateBundle(parent, timestamp, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &callBundleResponse{
+		BundleHash:       types.BytesToHash(crypto.Keccak256(hashes)),
+		StateBlockNumber: argUint64(parent.Number),
+		BlockNumber:      argUint64(parent.Number + 1),
+		Results:          make([]*bundleTxResponse, 0, len(res.Txs)),
+		TotalGasUsed:     argUint64(res.GasUsed),
+		CoinbaseDiff:     argBigPtr(res.CoinbaseDiff),
+		StateDiff: &bundleStateDiff{
+			Pre:  newBundleAccountStates(res.StateDiff.Pre),
+			Post: newBundleAccountStates(res.StateDiff.Post),
+		},
+	}
+
+	logIndex := uint64(0)
+	for i, tx := range res.Txs {
+		receipt := tx.Receipt
+		txResp := &bundleTxResponse{
+			TxHash:   tx.Txn.Hash,
+			From:     tx.Txn.From,
+			To:       tx.Txn.To,
+			Value:    argBigPtr(tx.Txn.Value),
+			GasUsed:  argUint64(receipt.GasUsed),
+			GasPrice: argBigPtr(tx.GasPrice),
+			GasFees:  argBigPtr(new(big.Int).Mul(tx.GasPrice, new(big.Int).SetUint64(receipt.GasUsed))),
+			Status:   argUint64(*receipt.Status),
+			Output:   argBytes(tx.ReturnValue),
+			Logs:     make([]*Log, 0, len(receipt.Logs)),
+		}
+		if tx.Txn.To == nil {
+			txResp.ContractAddress = &receipt.ContractAddress
+		}
+		if tx.Err != nil {
+			txResp.Error = tx.Err.Error()
+		}
+		if reason, ok := decodeRevertReason(receipt.RevertData); ok {
+			txResp.RevertReason = reason
+		}
+
+		// the logs have no block, the simulated block is not sealed
+		for _, log := range receipt.Logs {
+			txResp.Logs = append(txResp.Logs, &Log{
+				Address:     log.Address,
+				Topics:      log.Topics,
+				Data:        argBytes(log.Data),
+				BlockNumber: argUint64(parent.Number + 1),
+				TxHash:      tx.Txn.Hash,
+				TxIndex:     argUint64(i),
+				LogIndex:    argUint64(logIndex),
+			})
+			logIndex++
+		}
+
+		resp.Results = append(resp.Results, txResp)
+	}
+
+	return resp, nil
 }
 
 // GetLogs returns an array of logs matching the filter options
@@ -452,25 +892,21 @@ func (e *Eth) GetLogs(filterOptions *LogFilter) (interface{}, error) {
 		return result, nil
 	}
 
-	head := e.d.store.Header().Number
-
-	resolveNum := func(num BlockNumber) uint64 {
-		if num == PendingBlockNumber || num == EarliestBlockNumber {
-			num = LatestBlockNumber
-		}
-		if num == LatestBlockNumber {
-			return head
-		}
-		return uint64(num)
-	}
-
-	from := resolveNum(filterOptions.fromBlock)
-	to := resolveNum(filterOptions.toBlock)
+	from, to := e.d.logsRange(filterOptions)
 
 	if to < from {
 		return nil, fmt.Errorf("incorrect range")
 	}
-	for i := from; i <= to; i++ {
+
+	if limit := atomic.LoadUint64(&e.d.logsBlockRange); limit != 0 && to-from+1 > limit {
+		return nil, NewLimitExceededError(
+			fmt.Sprintf("query exceeds the limit of %d blocks", limit),
+			newLogsCursor(from, from+limit-1),
+		)
+	}
+
+	// only the blocks whose logs bloom matches the filter are checked
+	for _, i := range e.d.store.FilterLogBlocks(from, to, filterOptions.Addresses, filterOptions.Topics) {
 		header, ok := e.d.store.GetHeaderByNumber(i)
 		if !ok {
 			break
@@ -482,17 +918,65 @@ func (e *Eth) GetLogs(filterOptions *LogFilter) (interface{}, error) {
 		if err := parseReceipts(header); err != nil {
 			return nil, err
 		}
+
+		// the queries of a single block are not limited, so the pages always make progress
+		if limit := atomic.LoadUint64(&e.d.logsResultLimit); limit != 0 && from != to && uint64(len(result)) > limit {
+			// the logs of the blocks before the current one fit in the limit
+			last := from
+			if header.Number > from {
+				last = header.Number - 1
+			}
+
+			return nil, NewLimitExceededError(
+				fmt.Sprintf("query returns more than %d logs", limit),
+				newLogsCursor(from, last),
+			)
+		}
 	}
 	return result, nil
 }
 
+// logsRange returns the block range of a log filter, with the block tags resolved to the head
+func (d *Dispatcher) logsRange(filter *LogFilter) (uint64, uint64) {
+	head := d.store.Header().Number
+
+	resolveNum := func(num BlockNumber) uint64 {
+		if num == PendingBlockNumber || num == EarliestBlockNumber {
+			num = LatestBlockNumber
+		}
+		if num == LatestBlockNumber {
+			return head
+		}
+		return uint64(num)
+	}
+
+	return resolveNum(filter.fromBlock), resolveNum(filter.toBlock)
+}
+
+// logsCursor is the data of the error returned when an eth_getLogs query exceeds the limits.
+// FromBlock and ToBlock are the range that fits in the limits, and Cursor is the
+// first block of the range of the next query
+type logsCursor struct {
+	FromBlock argUint64 `json:"fromBlock"`
+	ToBlock   argUint64 `json:"toBlock"`
+	Cursor    argUint64 `json:"cursor"`
+}
+
+func newLogsCursor(from, to uint64) *logsCursor {
+	return &logsCursor{
+		FromBlock: argUint64(from),
+		ToBlock:   argUint64(to),
+		Cursor:    argUint64(to + 1),
+	}
+}
+
 // GetBalance returns the account's balance at the referenced block
 func (e *Eth) GetBalance(address types.Address, number *BlockNumber) (interface{}, error) {
 
 	if number == nil {
 		number, _ = createBlockNumberPointer("latest")
 	}
-	header, err := e.d.getBlockHeaderImpl(*number)
+	header, err := e.d.getStateHeaderImpl(*number)
 	if err != nil {
 		return nil, err
 	}
@@ -529,7 +1013,7 @@ func (e *Eth) GetCode(address types.Address, number *BlockNumber) (interface{},
 	if number == nil {
 		number, _ = createBlockNumberPointer("latest")
 	}
-	header, err := e.d.getBlockHeaderImpl(*number)
+	header, err := e.d.getStateHeaderImpl(*number)
 	if err != nil {
 		return nil, err
 	}
@@ -2,18 +2,26 @@ package jsonrpc
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/crypto"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/umbracle/fastrlp"
 
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/protocol"
 	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/0xPolygon/polygon-sdk/types"
 )
 
@@ -402,6 +410,299 @@ func TestEth_Block_GetLogs(t *testing.T) {
 	}
 }
 
+// mockBloomStore only reports the candidate blocks as matching the logs bloom
+type mockBloomStore struct {
+	mockBlockStore2
+	candidates []uint64
+}
+
+func (m *mockBloomStore) FilterLogBlocks(
+	from, to uint64,
+	addresses []types.Address,
+	topics [][]types.Hash,
+) []uint64 {
+	numbers := []uint64{}
+	for _, n := range m.candidates {
+		if n >= from && n <= to {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
+
+func TestEth_Block_GetLogs_BloomCandidates(t *testing.T) {
+	topic := types.StringToHash("4")
+
+	store := &mockBloomStore{candidates: []uint64{2, 3}}
+	store.topics = []types.Hash{topic}
+	for i := 0; i < 4; i++ {
+		store.add(&types.Block{
+			Header: &types.Header{
+				Number: uint64(i),
+				Hash:   types.StringToHash(strconv.Itoa(i)),
+			},
+		})
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	// the receipts of the blocks filtered out by the bloom are not checked
+	foundLogs, err := dispatcher.endpoints.Eth.GetLogs(&LogFilter{
+		fromBlock: 1,
+		toBlock:   2,
+		Topics:    [][]types.Hash{{topic}},
+	})
+	assert.NoError(t, err)
+
+	logs := foundLogs.([]*Log)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, argUint64(2), logs[0].BlockNumber)
+}
+
+func TestEth_Block_GetLogs_Limits(t *testing.T) {
+	store := &mockBlockStore2{}
+	for i := 0; i < 4; i++ {
+		store.add(&types.Block{
+			Header: &types.Header{
+				Number: uint64(i),
+				Hash:   types.StringToHash(strconv.Itoa(i)),
+			},
+		})
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	expectLimitError := func(err error, cursor *logsCursor) {
+		t.Helper()
+
+		limitErr, ok := err.(*limitExceededError)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, cursor, limitErr.ErrorData())
+	}
+
+	// the block range limit
+	dispatcher.logsBlockRange = 2
+
+	_, err := dispatcher.endpoints.Eth.GetLogs(&LogFilter{fromBlock: 1, toBlock: 3})
+	expectLimitError(err, newLogsCursor(1, 2))
+
+	foundLogs, err := dispatcher.endpoints.Eth.GetLogs(&LogFilter{fromBlock: 1, toBlock: 2})
+	assert.NoError(t, err)
+	assert.Len(t, foundLogs, 4)
+
+	// the result limit, the blocks 1 and 2 have two logs each
+	dispatcher.logsBlockRange = 0
+	dispatcher.logsResultLimit = 3
+
+	_, err = dispatcher.endpoints.Eth.GetLogs(&LogFilter{fromBlock: 1, toBlock: 3})
+	expectLimitError(err, newLogsCursor(1, 1))
+
+	dispatcher.logsResultLimit = 1
+
+	_, err = dispatcher.endpoints.Eth.GetLogs(&LogFilter{fromBlock: 1, toBlock: 3})
+	expectLimitError(err, newLogsCursor(1, 1))
+
+	// a single block is not limited
+	foundLogs, err = dispatcher.endpoints.Eth.GetLogs(&LogFilter{fromBlock: 2, toBlock: 2})
+	assert.NoError(t, err)
+	assert.Len(t, foundLogs, 2)
+}
+
+// mockAddressStore returns the same page of the index of the transactions by address
+type mockAddressStore struct {
+	mockBlockStore2
+	page   *blockchain.AddressTxsPage
+	limits []int
+}
+
+func (m *mockAddressStore) GetAddressTxs(
+	addr types.Address,
+	cursor uint64,
+	limit int,
+) (*blockchain.AddressTxsPage, error) {
+	m.limits = append(m.limits, limit)
+	return m.page, nil
+}
+
+func TestEth_GetTransactionsByAddress(t *testing.T) {
+	store := &mockAddressStore{}
+
+	txs := []*types.Transaction{}
+	for i := 0; i < 3; i++ {
+		txn := &types.Transaction{Nonce: uint64(i), GasPrice: big.NewInt(1), Value: big.NewInt(0)}
+		txn.ComputeHash()
+		txs = append(txs, txn)
+	}
+	store.add(
+		&types.Block{Header: &types.Header{Number: 1, Hash: types.StringToHash("1")}, Transactions: txs[:2]},
+		&types.Block{Header: &types.Header{Number: 2, Hash: types.StringToHash("2")}, Transactions: txs[2:]},
+	)
+	store.page = &blockchain.AddressTxsPage{
+		Txs: []*storage.AddressTx{
+			{BlockHash: types.StringToHash("2"), TxHash: txs[2].Hash},
+			{BlockHash: types.StringToHash("1"), TxHash: txs[1].Hash},
+		},
+		Next:        1,
+		IndexedFrom: 1,
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	res, err := dispatcher.endpoints.Eth.GetTransactionsByAddress(types.StringToAddress("1"), nil, nil)
+	assert.NoError(t, err)
+
+	page := res.(*addressTxs)
+	assert.Len(t, page.Transactions, 2)
+	assert.Equal(t, txs[2].Hash, page.Transactions[0].Hash)
+	assert.Equal(t, argUint64(2), page.Transactions[0].BlockNumber)
+	assert.Equal(t, txs[1].Hash, page.Transactions[1].Hash)
+	assert.Equal(t, argUint64(1), page.Transactions[1].TxIndex)
+	assert.Equal(t, argUintPtr(1), page.Next)
+	assert.Equal(t, argUint64(1), page.IndexedFrom)
+
+	// the limit defaults to a page of 100 transactions, and can't exceed 1000
+	assert.Equal(t, []int{defaultAddressTxsLimit}, store.limits)
+
+	_, err = dispatcher.endpoints.Eth.GetTransactionsByAddress(types.StringToAddress("1"), nil, argUintPtr(1001))
+	assert.Error(t, err)
+
+	// a node without the index returns an error
+	dispatcher = newTestDispatcher(hclog.NewNullLogger(), &mockBlockStore2{})
+
+	_, err = dispatcher.endpoints.Eth.GetTransactionsByAddress(types.StringToAddress("1"), nil, nil)
+	assert.Equal(t, blockchain.ErrAddressIndexDisabled, err)
+}
+
+// mockBundleStore returns the same result for every bundle, and records the simulated ones
+type mockBundleStore struct {
+	mockBlockStore2
+	result    *state.BundleResult
+	parent    *types.Header
+	timestamp uint64
+	simulated []*types.Transaction
+}
+
+func (m *mockBundleStore) SimulateBundle(
+	parent *types.Header,
+	timestamp uint64,
+	txs []*types.Transaction,
+) (*state.BundleResult, error) {
+	m.parent, m.timestamp, m.simulated = parent, timestamp, txs
+	return m.result, nil
+}
+
+func TestEth_CallBundle(t *testing.T) {
+	store := &mockBundleStore{}
+	store.add(
+		&types.Block{Header: &types.Header{Number: 0, Hash: types.StringToHash("0")}},
+		&types.Block{Header: &types.Header{Number: 1, Hash: types.StringToHash("1"), Timestamp: 10}},
+	)
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	to := types.StringToAddress("2")
+	txs := []*types.Transaction{
+		{Nonce: 0, To: &to, Value: big.NewInt(1), GasPrice: big.NewInt(2), Gas: 21000},
+		{Nonce: 1, Value: big.NewInt(0), GasPrice: big.NewInt(2), Gas: 100000, Input: []byte{0x1}},
+	}
+	raw := []argBytes{}
+	hashes := []byte{}
+	for _, txn := range txs {
+		txn.ComputeHash()
+		raw = append(raw, txn.MarshalRLP())
+		hashes = append(hashes, txn.Hash.Bytes()...)
+	}
+
+	sender := types.StringToAddress("1")
+	nonce, prevNonce := uint64(2), uint64(0)
+	success, failed := types.ReceiptSuccess, types.ReceiptFailed
+
+	store.result = &state.BundleResult{
+		Txs: []*state.BundleTxResult{
+			{
+				Txn: &types.Transaction{Hash: txs[0].Hash, From: sender, To: &to, Value: big.NewInt(1)},
+				Receipt: &types.Receipt{
+					GasUsed: 21000,
+					Status:  &success,
+					Logs:    []*types.Log{{Address: to, Data: []byte{0x2}}},
+				},
+				GasPrice: big.NewInt(2),
+			},
+			{
+				Txn: &types.Transaction{Hash: txs[1].Hash, From: sender, Value: big.NewInt(0)},
+				Receipt: &types.Receipt{
+					GasUsed:         30000,
+					Status:          &failed,
+					ContractAddress: types.StringToAddress("3"),
+					RevertData:      encodeRevertReason("not allowed"),
+				},
+				ReturnValue: encodeRevertReason("not allowed"),
+				Err:         runtime.ErrExecutionReverted,
+				GasPrice:    big.NewInt(2),
+			},
+		},
+		GasUsed:      51000,
+		CoinbaseDiff: big.NewInt(102000),
+		StateDiff: &state.StateDiff{
+			Pre: map[types.Address]*state.AccountState{
+				sender: {Balance: big.NewInt(200000), Nonce: &prevNonce},
+			},
+			Post: map[types.Address]*state.AccountState{
+				sender: {Balance: big.NewInt(97999), Nonce: &nonce},
+				to:     {Balance: big.NewInt(1), Code: []byte{}},
+			},
+		},
+	}
+
+	res, err := dispatcher.endpoints.Eth.CallBundle(&callBundleArgs{Txs: raw})
+	assert.NoError(t, err)
+
+	// the bundle runs on top of the latest block, a second after it
+	assert.Equal(t, uint64(1), store.parent.Number)
+	assert.Equal(t, uint64(11), store.timestamp)
+	assert.Len(t, store.simulated, 2)
+	assert.Equal(t, txs[1].Hash, store.simulated[1].Hash)
+
+	resp := res.(*callBundleResponse)
+	assert.Equal(t, types.BytesToHash(crypto.Keccak256(hashes)), resp.BundleHash)
+	assert.Equal(t, argUint64(1), resp.StateBlockNumber)
+	assert.Equal(t, argUint64(2), resp.BlockNumber)
+	assert.Equal(t, argUint64(51000), resp.TotalGasUsed)
+	assert.Equal(t, argBigPtr(big.NewInt(102000)), resp.CoinbaseDiff)
+	assert.Len(t, resp.Results, 2)
+
+	first := resp.Results[0]
+	assert.Equal(t, argUint64(1), first.Status)
+	assert.Equal(t, argBigPtr(big.NewInt(42000)), first.GasFees)
+	assert.Nil(t, first.ContractAddress)
+	assert.Equal(t, []*Log{{Address: to, Data: argBytes{0x2}, BlockNumber: 2, TxHash: txs[0].Hash}}, first.Logs)
+
+	second := resp.Results[1]
+	assert.Equal(t, argUint64(0), second.Status)
+	assert.Equal(t, types.StringToAddress("3"), *second.ContractAddress)
+	assert.Equal(t, runtime.ErrExecutionReverted.Error(), second.Error)
+	assert.Equal(t, "not allowed", second.RevertReason)
+	assert.Empty(t, second.Logs)
+
+	assert.Equal(t, argUintPtr(0), resp.StateDiff.Pre[sender].Nonce)
+	assert.Equal(t, argUintPtr(2), resp.StateDiff.Post[sender].Nonce)
+	assert.Nil(t, resp.StateDiff.Post[sender].Code)
+	assert.Equal(t, argBytesPtr([]byte{}), resp.StateDiff.Post[to].Code)
+
+	// the state block and the timestamp are set
+	number := BlockNumber(0)
+	timestamp := argUint64(100)
+	_, err = dispatcher.endpoints.Eth.CallBundle(&callBundleArgs{Txs: raw, StateBlockNumber: &number, Timestamp: &timestamp})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), store.parent.Number)
+	assert.Equal(t, uint64(100), store.timestamp)
+
+	// the bundles are not empty, and their transactions are valid
+	_, err = dispatcher.endpoints.Eth.CallBundle(&callBundleArgs{})
+	assert.Error(t, err)
+
+	_, err = dispatcher.endpoints.Eth.CallBundle(&callBundleArgs{Txs: []argBytes{{0x1, 0x2}}})
+	assert.Error(t, err)
+}
+
 var (
 	addr0                = types.Address{0x1}
 	uninitializedAddress = types.Address{0x99}
@@ -648,6 +949,7 @@ type mockStoreTxn struct {
 	nullBlockchainInterface
 	accounts map[types.Address]*mockAccount2
 	txn      *types.Transaction
+	expiry   *txpool.Expiry
 }
 
 func (m *mockStoreTxn) GetForksInTime(blockNumber uint64) chain.ForksInTime {
@@ -659,6 +961,12 @@ func (m *mockStoreTxn) AddTx(tx *types.Transaction) error {
 	return nil
 }
 
+func (m *mockStoreTxn) AddTxWithExpiry(tx *types.Transaction, expiry *txpool.Expiry) error {
+	m.txn = tx
+	m.expiry = expiry
+	return nil
+}
+
 func (m *mockStoreTxn) GetNonce(addr types.Address) (uint64, bool) {
 	return 1, false
 }
@@ -697,7 +1005,7 @@ func TestEth_TxnPool_SendRawTransaction(t *testing.T) {
 	txn.ComputeHash()
 
 	data := txn.MarshalRLP()
-	_, err := dispatcher.endpoints.Eth.SendRawTransaction(hex.EncodeToHex(data))
+	_, err := dispatcher.endpoints.Eth.SendRawTransaction(hex.EncodeToHex(data), nil)
 	assert.NoError(t, err)
 	assert.NotEqual(t, store.txn.Hash, types.ZeroHash)
 
@@ -705,6 +1013,14 @@ func TestEth_TxnPool_SendRawTransaction(t *testing.T) {
 	if txn.Hash != store.txn.Hash {
 		t.Fatal("bad")
 	}
+	assert.Nil(t, store.expiry)
+
+	// the optional inclusion bounds are passed to the pool
+	body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_sendRawTransaction", "params": ["%s", {"maxBlockNumber": "0x10"}]}`,
+		hex.EncodeToHex(data))
+	_, err = dispatcher.Handle([]byte(body), requestContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, &txpool.Expiry{MaxBlockNumber: 16}, store.expiry)
 }
 
 func TestEth_TxnPool_SendTransaction(t *testing.T) {
@@ -722,3 +1038,393 @@ func TestEth_TxnPool_SendTransaction(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEqual(t, store.txn.Hash, types.ZeroHash)
 }
+
+type mockPendingStore struct {
+	nullBlockchainInterface
+
+	header   *types.Header
+	pending  *types.Block
+	balances   map[types.Hash]*big.Int
+	applied    *types.Header
+	overridden state.StateOverride
+}
+
+func (m *mockPendingStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	panic("implement me")
+}
+
+func (m *mockPendingStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockPendingStore) PendingBlock() (*types.Block, error) {
+	return m.pending, nil
+}
+
+func (m *mockPendingStore) GetNonce(addr types.Address) (uint64, bool) {
+	if addr != addr0 {
+		return 0, false
+	}
+	return 7, true
+}
+
+func (m *mockPendingStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	balance, ok := m.balances[root]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return &state.Account{Balance: balance, Nonce: 3}, nil
+}
+
+func (m *mockPendingStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
+	m.applied = header
+	m.overridden = override
+	return &runtime.ExecutionResult{ReturnValue: []byte{0x1}}, nil
+}
+
+func TestEth_PendingBlockTag(t *testing.T) {
+	latestRoot, pendingRoot := types.StringToHash("1"), types.StringToHash("2")
+
+	store := &mockPendingStore{
+		header: &types.Header{Number: 10, StateRoot: latestRoot},
+		pending: &types.Block{
+			Header: &types.Header{Number: 11, StateRoot: pendingRoot, Hash: types.StringToHash("11")},
+			Transactions: []*types.Transaction{
+				{Nonce: 3, Value: big.NewInt(1), GasPrice: big.NewInt(1), Hash: types.StringToHash("tx")},
+			},
+		},
+		balances: map[types.Hash]*big.Int{
+			latestRoot:  big.NewInt(100),
+			pendingRoot: big.NewInt(99),
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	pending := PendingBlockNumber
+
+	// the pending balance is read from the state of the pending block
+	balance, err := dispatcher.endpoints.Eth.GetBalance(addr1, &pending)
+	assert.NoError(t, err)
+	assert.Equal(t, argBigPtr(big.NewInt(99)), balance)
+
+	balance, err = dispatcher.endpoints.Eth.GetBalance(addr1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, argBigPtr(big.NewInt(100)), balance)
+
+	// the pending nonce is taken from the pool, or from the state if the pool has no transactions of the account
+	nonce, err := dispatcher.endpoints.Eth.GetTransactionCount(addr0, &pending)
+	assert.NoError(t, err)
+	assert.Equal(t, argUintPtr(7), nonce)
+
+	nonce, err = dispatcher.endpoints.Eth.GetTransactionCount(addr1, &pending)
+	assert.NoError(t, err)
+	assert.Equal(t, argUintPtr(3), nonce)
+
+	// the call is executed on top of the pending block
+	_, err = dispatcher.endpoints.Eth.Call(&txnArgs{From: argAddrPtr(addr0), To: argAddrPtr(addr1)}, &pending, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, store.pending.Header, store.applied)
+
+	res, err := dispatcher.endpoints.Eth.GetBlockByNumber(PendingBlockNumber, true)
+	assert.NoError(t, err)
+
+	block := res.(*block)
+	assert.Equal(t, argUint64(11), block.Number)
+	assert.Len(t, block.Transactions, 1)
+}
+
+func TestEth_CallStateOverride(t *testing.T) {
+	store := &mockPendingStore{
+		header: &types.Header{Number: 10},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	call := func(override string) *ErrorObject {
+		body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_call", "params": [{"from": "%s", "to": "%s", "nonce": "0x1"}, "latest"%s]}`,
+			addr0, addr1, override)
+
+		res, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var resp ErrorResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		return resp.Error
+	}
+
+	// the override is optional
+	assert.Nil(t, call(""))
+	assert.Nil(t, store.overridden)
+
+	slot := types.StringToHash("1")
+	assert.Nil(t, call(fmt.Sprintf(`, {"%s": {"nonce": "0x5", "balance": "0x64", "code": "0x01", "stateDiff": {"%s": "%s"}}}`,
+		addr1, slot, slot)))
+
+	nonce := uint64(5)
+	assert.Equal(t, state.StateOverride{
+		addr1: {
+			Nonce:     &nonce,
+			Balance:   big.NewInt(100),
+			Code:      []byte{0x1},
+			StateDiff: map[types.Hash]types.Hash{slot: slot},
+		},
+	}, store.overridden)
+
+	// the override is validated
+	assert.NotNil(t, call(`, {"0x1": {"nonce": "foo"}}`))
+}
+
+// mockLimitStore records the gas and the timeout of the executions, which need 30000 gas to succeed
+type mockLimitStore struct {
+	mockPendingStore
+
+	gas         []uint64
+	timeouts    []time.Duration
+	interrupted bool
+}
+
+func (m *mockLimitStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(blockNumber)
+}
+
+func (m *mockLimitStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
+	m.gas = append(m.gas, txn.Gas)
+	m.timeouts = append(m.timeouts, timeout)
+
+	if m.interrupted {
+		return &runtime.ExecutionResult{Err: runtime.ErrExecutionInterrupted}, nil
+	}
+	if txn.Gas < 30000 {
+		return &runtime.ExecutionResult{Err: runtime.ErrOutOfGas}, nil
+	}
+	return &runtime.ExecutionResult{ReturnValue: []byte{0x1}}, nil
+}
+
+func TestEth_CallLimits(t *testing.T) {
+	store := &mockLimitStore{
+		mockPendingStore: mockPendingStore{
+			header: &types.Header{Number: 10, GasLimit: 1000000},
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.setCallLimits(time.Second, 40000)
+
+	call := func(method string) *ErrorObject {
+		body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "%s", "params": [{"to": "%s"}, "latest"]}`,
+			method, addr1)
+
+		res, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var resp ErrorResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		return resp.Error
+	}
+
+	// the gas of the call is capped, and the execution has the timeout
+	assert.Nil(t, call("eth_call"))
+	assert.Equal(t, []uint64{40000}, store.gas)
+	assert.Equal(t, []time.Duration{time.Second}, store.timeouts)
+
+	// the estimation searches below the cap, within the timeout
+	store.gas, store.timeouts = nil, nil
+	assert.Nil(t, call("eth_estimateGas"))
+	for i := range store.gas {
+		assert.LessOrEqual(t, store.gas[i], uint64(40000))
+		assert.True(t, store.timeouts[i] > 0 && store.timeouts[i] <= time.Second)
+	}
+
+	dispatcher.setCallLimits(time.Second, 25000)
+	assert.NotNil(t, call("eth_estimateGas"))
+
+	// the interrupted executions fail with the timeout
+	store.interrupted = true
+	for _, method := range []string{"eth_call", "eth_estimateGas"} {
+		resp := call(method)
+		assert.Equal(t, -32000, resp.Code)
+		assert.Equal(t, "execution aborted (timeout = 1s)", resp.Message)
+		assert.Equal(t, "0x3e8", resp.Data)
+	}
+
+	// without limits, the calls have the gas of the block
+	store.gas, store.timeouts = nil, nil
+	store.interrupted = false
+	dispatcher.setCallLimits(0, 0)
+	assert.Nil(t, call("eth_call"))
+	assert.Equal(t, []uint64{1000000}, store.gas)
+	assert.Equal(t, []time.Duration{0}, store.timeouts)
+}
+
+// mockRefundStore executes a transaction which needs the gas it consumed before its refund to succeed,
+// and records the gas it is run with
+type mockRefundStore struct {
+	mockPendingStore
+
+	needed   uint64
+	refunded uint64
+	gas      []uint64
+}
+
+func (m *mockRefundStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(blockNumber)
+}
+
+func (m *mockRefundStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
+	m.gas = append(m.gas, txn.Gas)
+
+	if txn.Gas < m.needed {
+		return &runtime.ExecutionResult{Err: runtime.ErrOutOfGas}, nil
+	}
+	return &runtime.ExecutionResult{
+		GasUsed:     m.needed - m.refunded,
+		GasRefunded: m.refunded,
+		GasLeft:     txn.Gas - m.needed + m.refunded,
+	}, nil
+}
+
+func TestEth_EstimateGasRefund(t *testing.T) {
+	store := &mockRefundStore{
+		mockPendingStore: mockPendingStore{
+			header: &types.Header{Number: 10, GasLimit: 1000000},
+		},
+		needed:   60000,
+		refunded: 15000,
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	estimate := func() string {
+		body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_estimateGas", "params": [{"to": "%s"}, "latest"]}`, addr1)
+
+		res, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var resp SuccessResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		assert.Nil(t, resp.Error)
+
+		var gas string
+		assert.NoError(t, json.Unmarshal(resp.Result, &gas))
+		return gas
+	}
+
+	// the estimate is the gas consumed before the refund, and not the gas used
+	assert.Equal(t, "0xea60", estimate())
+
+	// the search starts from the consumed gas, instead of the whole block gas limit
+	assert.Equal(t, uint64(1000000), store.gas[0])
+	assert.Less(t, len(store.gas), 20)
+
+	// the search ends at the intrinsic gas
+	store.needed, store.refunded, store.gas = state.TxGas, 0, nil
+	assert.Equal(t, "0x5208", estimate())
+}
+
+type mockPrunedStore struct {
+	mockPendingStore
+
+	pruned uint64
+}
+
+func (m *mockPrunedStore) GetHeaderByNumber(num uint64) (*types.Header, bool) {
+	return &types.Header{Number: num, StateRoot: types.StringToHash("1")}, true
+}
+
+func (m *mockPrunedStore) CheckState(header *types.Header) error {
+	if header.Number <= m.pruned {
+		return fmt.Errorf("the state of block %d is not available", header.Number)
+	}
+	return nil
+}
+
+func TestEth_StateUnavailable(t *testing.T) {
+	store := &mockPrunedStore{
+		mockPendingStore: mockPendingStore{
+			header:   &types.Header{Number: 10, StateRoot: types.StringToHash("1")},
+			balances: map[types.Hash]*big.Int{types.StringToHash("1"): big.NewInt(100)},
+		},
+		pruned: 5,
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	getBalance := func(number string) *ErrorObject {
+		body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_getBalance", "params": ["%s", "%s"]}`, addr1, number)
+
+		res, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var resp ErrorResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		return resp.Error
+	}
+
+	assert.Nil(t, getBalance("0x6"))
+
+	// the queries of a missing state fail, instead of returning an empty account
+	obj := getBalance("0x5")
+	assert.NotNil(t, obj)
+	assert.Equal(t, -32000, obj.Code)
+	assert.Equal(t, "the state of block 5 is not available", obj.Message)
+	assert.Equal(t, "0x5", obj.Data)
+}
+
+type mockSyncStore struct {
+	mockBlockStore2
+	progress *protocol.SyncProgress
+}
+
+func (m *mockSyncStore) SyncProgress() *protocol.SyncProgress {
+	return m.progress
+}
+
+func TestEth_Syncing(t *testing.T) {
+	store := &mockSyncStore{}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	syncing := func() string {
+		res, err := dispatcher.Handle([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "eth_syncing"}`), requestContext{})
+		assert.NoError(t, err)
+
+		var resp SuccessResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		return string(resp.Result)
+	}
+
+	assert.Equal(t, "false", syncing())
+
+	store.progress = &protocol.SyncProgress{
+		Stage:             protocol.SyncStageBlocks,
+		StartingBlock:     10,
+		CurrentBlock:      20,
+		HighestBlock:      100,
+		HeadersDownloaded: 2,
+		BlocksDownloaded:  15,
+		BlocksExecuted:    10,
+		ETA:               90 * time.Second,
+	}
+	assert.JSONEq(t, `{
+		"startingBlock": "0xa",
+		"currentBlock": "0x14",
+		"highestBlock": "0x64",
+		"stage": "blocks",
+		"headersDownloaded": "0x2",
+		"blocksDownloaded": "0xf",
+		"blocksExecuted": "0xa",
+		"stateNodes": "0x0",
+		"stateCodes": "0x0",
+		"statePending": "0x0",
+		"eta": "0x5a"
+	}`, syncing())
+}
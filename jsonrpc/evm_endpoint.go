@@ -0,0 +1,94 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// devChain is the dev consensus backing the evm namespace, controlling the chain of the tests
+type devChain interface {
+	// Mine seals a block right away, with the given timestamp unless it is zero
+	Mine(timestamp uint64) error
+
+	// IncreaseTime moves the clock of the chain forward, and returns its offset in seconds
+	IncreaseTime(seconds uint64) int64
+
+	// SetNextBlockTimestamp sets the timestamp of the next block
+	SetNextBlockTimestamp(timestamp uint64) error
+
+	// Snapshot saves the head of the chain, and returns the id to revert to it
+	Snapshot() uint64
+
+	// Revert rewinds the chain to the snapshot, false if there is no such snapshot
+	Revert(id uint64) (bool, error)
+}
+
+// evmQuantity is a quantity param of the evm namespace. The testing frameworks send it
+// as a number or as a hex string
+type evmQuantity uint64
+
+func (q *evmQuantity) UnmarshalJSON(data []byte) error {
+	var num uint64
+	if err := json.Unmarshal(data, &num); err == nil {
+		*q = evmQuantity(num)
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("the quantity %s is neither a number nor a string", data)
+	}
+
+	num, err := types.ParseUint64orHex(&str)
+	if err != nil {
+		return err
+	}
+	*q = evmQuantity(num)
+
+	return nil
+}
+
+// Evm is the evm jsonrpc endpoint of the dev chains, with the testing methods of Hardhat and Ganache
+type Evm struct {
+	d *Dispatcher
+}
+
+// Mine seals a block with the pending transactions right away, at the timestamp if set
+func (e *Evm) Mine(timestamp *evmQuantity) (interface{}, error) {
+	var ts uint64
+	if timestamp != nil {
+		ts = uint64(*timestamp)
+	}
+
+	if err := e.d.dev.Mine(ts); err != nil {
+		return nil, err
+	}
+
+	return "0x0", nil
+}
+
+// IncreaseTime moves the clock of the chain forward by the seconds, and returns the total adjustment
+func (e *Evm) IncreaseTime(seconds evmQuantity) (interface{}, error) {
+	return e.d.dev.IncreaseTime(uint64(seconds)), nil
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next block, the clock of the chain continues from it
+func (e *Evm) SetNextBlockTimestamp(timestamp evmQuantity) (interface{}, error) {
+	if err := e.d.dev.SetNextBlockTimestamp(uint64(timestamp)); err != nil {
+		return nil, err
+	}
+
+	return argUint64(timestamp), nil
+}
+
+// Snapshot saves the state of the chain, and returns the id to revert to it
+func (e *Evm) Snapshot() (interface{}, error) {
+	return argUint64(e.d.dev.Snapshot()), nil
+}
+
+// Revert reverts the chain to the snapshot. The snapshot can't be reverted to again
+func (e *Evm) Revert(id evmQuantity) (interface{}, error) {
+	return e.d.dev.Revert(uint64(id))
+}
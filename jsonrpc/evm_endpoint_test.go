@@ -0,0 +1,100 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDevChain struct {
+	mined      []uint64
+	timeOffset int64
+	next       uint64
+	snapshots  uint64
+	reverted   []uint64
+}
+
+func (m *mockDevChain) Mine(timestamp uint64) error {
+	m.mined = append(m.mined, timestamp)
+	return nil
+}
+
+func (m *mockDevChain) IncreaseTime(seconds uint64) int64 {
+	m.timeOffset += int64(seconds)
+	return m.timeOffset
+}
+
+func (m *mockDevChain) SetNextBlockTimestamp(timestamp uint64) error {
+	if timestamp == 0 {
+		return errors.New("timestamp too low")
+	}
+	m.next = timestamp
+	return nil
+}
+
+func (m *mockDevChain) Snapshot() uint64 {
+	m.snapshots++
+	return m.snapshots
+}
+
+func (m *mockDevChain) Revert(id uint64) (bool, error) {
+	if id == 0 || id > m.snapshots {
+		return false, nil
+	}
+	m.reverted = append(m.reverted, id)
+	return true, nil
+}
+
+func TestEvmNamespaceDisabled(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "evm_mine"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res string
+	assert.Error(t, expectJSONResult(resp, &res))
+}
+
+func TestEvm(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), nil)
+	dev := &mockDevChain{}
+	dispatcher.enableEvm(dev)
+
+	call := func(req string, res interface{}) error {
+		resp, err := dispatcher.Handle([]byte(req), requestContext{})
+		assert.NoError(t, err)
+
+		return expectJSONResult(resp, res)
+	}
+
+	var str string
+
+	// the timestamp is optional
+	assert.NoError(t, call(`{"method": "evm_mine", "params": []}`, &str))
+	assert.Equal(t, "0x0", str)
+	assert.NoError(t, call(`{"method": "evm_mine", "params": [1000]}`, &str))
+	assert.Equal(t, []uint64{0, 1000}, dev.mined)
+
+	// the quantities are numbers or hex strings
+	var offset int64
+	assert.NoError(t, call(`{"method": "evm_increaseTime", "params": [3600]}`, &offset))
+	assert.NoError(t, call(`{"method": "evm_increaseTime", "params": ["0x10"]}`, &offset))
+	assert.Equal(t, int64(3616), offset)
+
+	assert.NoError(t, call(`{"method": "evm_setNextBlockTimestamp", "params": [2000]}`, &str))
+	assert.Equal(t, "0x7d0", str)
+	assert.Equal(t, uint64(2000), dev.next)
+	assert.Error(t, call(`{"method": "evm_setNextBlockTimestamp", "params": [0]}`, &str))
+
+	assert.NoError(t, call(`{"method": "evm_snapshot"}`, &str))
+	assert.Equal(t, "0x1", str)
+
+	var ok bool
+	assert.NoError(t, call(`{"method": "evm_revert", "params": ["0x1"]}`, &ok))
+	assert.True(t, ok)
+	assert.NoError(t, call(`{"method": "evm_revert", "params": [5]}`, &ok))
+	assert.False(t, ok)
+	assert.Equal(t, []uint64{1}, dev.reverted)
+}
@@ -4,11 +4,13 @@ import (
 	"container/heap"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -27,6 +29,14 @@ type Filter struct {
 	// log filter
 	logFilter *LogFilter
 
+	// dropped transactions filter, and its cache
+	droppedTxs bool
+	dropped    []*droppedTx
+
+	// chain reorgs filter, and its cache
+	reorgs      bool
+	reorgEvents []*reorgEvent
+
 	// index of the filter in the timer array
 	index int
 
@@ -38,6 +48,22 @@ type Filter struct {
 }
 
 func (f *Filter) getFilterUpdates() (string, error) {
+	if f.isReorgFilter() {
+		res, err := json.Marshal(f.reorgEvents)
+		if err != nil {
+			return "", err
+		}
+		f.reorgEvents = []*reorgEvent{}
+		return string(res), nil
+	}
+	if f.isDroppedTxFilter() {
+		res, err := json.Marshal(f.dropped)
+		if err != nil {
+			return "", err
+		}
+		f.dropped = []*droppedTx{}
+		return string(res), nil
+	}
 	if f.isBlockFilter() {
 		// block filter
 		headers, newHead := f.block.getUpdates()
@@ -80,7 +106,29 @@ func (f *Filter) sendMessage(msg string) error {
 }
 
 func (f *Filter) flush() error {
-	if f.isBlockFilter() {
+	if f.isReorgFilter() {
+		for _, reorg := range f.reorgEvents {
+			res, err := json.Marshal(reorg)
+			if err != nil {
+				return err
+			}
+			if err := f.sendMessage(string(res)); err != nil {
+				return err
+			}
+		}
+		f.reorgEvents = []*reorgEvent{}
+	} else if f.isDroppedTxFilter() {
+		for _, dropped := range f.dropped {
+			res, err := json.Marshal(dropped)
+			if err != nil {
+				return err
+			}
+			if err := f.sendMessage(string(res)); err != nil {
+				return err
+			}
+		}
+		f.dropped = []*droppedTx{}
+	} else if f.isBlockFilter() {
 		// send each block independently
 		updates, newHead := f.block.getUpdates()
 		f.block = newHead
@@ -118,6 +166,68 @@ func (f *Filter) isBlockFilter() bool {
 	return f.block != nil
 }
 
+func (f *Filter) isDroppedTxFilter() bool {
+	return f.droppedTxs
+}
+
+func (f *Filter) isReorgFilter() bool {
+	return f.reorgs
+}
+
+// droppedTx is a transaction dropped from the pool without being mined
+type droppedTx struct {
+	Hash   types.Hash    `json:"hash"`
+	From   types.Address `json:"from"`
+	Nonce  argUint64     `json:"nonce"`
+	Reason string        `json:"reason"`
+}
+
+// reorgEvent is a reorganization of the canonical chain, the blocks are sorted by number
+type reorgEvent struct {
+	Depth          argUint64    `json:"depth"`
+	AncestorNumber argUint64    `json:"ancestorNumber"`
+	AncestorHash   types.Hash   `json:"ancestorHash"`
+	OldHead        types.Hash   `json:"oldHead"`
+	NewHead        types.Hash   `json:"newHead"`
+	Removed        []types.Hash `json:"removed"`
+	Added          []types.Hash `json:"added"`
+}
+
+// newReorgEvent returns the reorg of the blockchain event, nil if it replaced no block
+func newReorgEvent(evnt *blockchain.Event) *reorgEvent {
+	if evnt.Type != blockchain.EventReorg || len(evnt.OldChain) == 0 || len(evnt.NewChain) == 0 {
+		return nil
+	}
+
+	sorted := func(headers []*types.Header) []*types.Header {
+		res := append([]*types.Header{}, headers...)
+		sort.Slice(res, func(i, j int) bool {
+			return res[i].Number < res[j].Number
+		})
+		return res
+	}
+	hashes := func(headers []*types.Header) []types.Hash {
+		res := make([]types.Hash, 0, len(headers))
+		for _, header := range headers {
+			res = append(res, header.Hash)
+		}
+		return res
+	}
+
+	oldChain := sorted(evnt.OldChain)
+	newChain := sorted(evnt.NewChain)
+
+	return &reorgEvent{
+		Depth:          argUint64(len(oldChain)),
+		AncestorNumber: argUint64(newChain[0].Number - 1),
+		AncestorHash:   newChain[0].ParentHash,
+		OldHead:        oldChain[len(oldChain)-1].Hash,
+		NewHead:        newChain[len(newChain)-1].Hash,
+		Removed:        hashes(oldChain),
+		Added:          hashes(newChain),
+	}
+}
+
 var defaultTimeout = 1 * time.Minute
 
 type FilterManager struct {
@@ -128,6 +238,9 @@ type FilterManager struct {
 
 	subscription blockchain.Subscription
 
+	droppedCh          <-chan *txpool.DroppedTx
+	unsubscribeDropped func()
+
 	filters map[string]*Filter
 	lock    sync.Mutex
 
@@ -157,6 +270,9 @@ func NewFilterManager(logger hclog.Logger, store blockchainInterface) *FilterMan
 	// start the head watcher
 	m.subscription = store.SubscribeEvents()
 
+	// and the watcher of the transactions dropped from the pool
+	m.droppedCh, m.unsubscribeDropped = store.SubscribeDropped()
+
 	return m
 }
 
@@ -191,6 +307,13 @@ func (f *FilterManager) Run() {
 				f.logger.Error("failed to dispatch event", "err", err)
 			}
 
+		case dropped, ok := <-f.droppedCh:
+			if !ok {
+				f.droppedCh = nil
+				continue
+			}
+			f.dispatchDropped(dropped)
+
 		case <-timeoutCh:
 			// timeout for filter
 			if !f.Uninstall(filter.id) {
@@ -270,6 +393,15 @@ func (f *FilterManager) dispatchEvent(evnt *blockchain.Event) error {
 		processBlock(i, false)
 	}
 
+	// announce the reorg to the reorg filters
+	if reorg := newReorgEvent(evnt); reorg != nil {
+		for _, f := range f.filters {
+			if f.isReorgFilter() {
+				f.reorgEvents = append(f.reorgEvents, reorg)
+			}
+		}
+	}
+
 	// flush all the websocket values
 	for _, f := range f.filters {
 		if f.isWS() {
@@ -279,6 +411,30 @@ func (f *FilterManager) dispatchEvent(evnt *blockchain.Event) error {
 	return nil
 }
 
+// dispatchDropped hands the dropped transaction to the dropped transactions filters
+func (f *FilterManager) dispatchDropped(dropped *txpool.DroppedTx) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, filter := range f.filters {
+		if !filter.isDroppedTxFilter() {
+			continue
+		}
+
+		filter.dropped = append(filter.dropped, &droppedTx{
+			Hash:   dropped.Tx.Hash,
+			From:   dropped.Tx.From,
+			Nonce:  argUint64(dropped.Tx.Nonce),
+			Reason: string(dropped.Reason),
+		})
+		if filter.isWS() {
+			if err := filter.flush(); err != nil {
+				f.logger.Error("failed to send dropped txn", "id", filter.id, "err", err)
+			}
+		}
+	}
+}
+
 func (f *FilterManager) Exists(id string) bool {
 	f.lock.Lock()
 	_, ok := f.filters[id]
@@ -331,9 +487,25 @@ func (f *FilterManager) NewLogFilter(logFilter *LogFilter, ws wsConn) string {
 	return f.addFilter(logFilter, ws)
 }
 
-func (f *FilterManager) addFilter(logFilter *LogFilter, ws wsConn) string {
-	f.lock.Lock()
+// NewDroppedTxFilter adds a filter of the transactions dropped from the pool without being mined
+func (f *FilterManager) NewDroppedTxFilter(ws wsConn) string {
+	return f.installFilter(&Filter{
+		id:         uuid.New().String(),
+		ws:         ws,
+		droppedTxs: true,
+	})
+}
+
+// NewReorgFilter adds a filter of the reorganizations of the canonical chain
+func (f *FilterManager) NewReorgFilter(ws wsConn) string {
+	return f.installFilter(&Filter{
+		id:     uuid.New().String(),
+		ws:     ws,
+		reorgs: true,
+	})
+}
 
+func (f *FilterManager) addFilter(logFilter *LogFilter, ws wsConn) string {
 	filter := &Filter{
 		id: uuid.New().String(),
 		ws: ws,
@@ -348,6 +520,12 @@ func (f *FilterManager) addFilter(logFilter *LogFilter, ws wsConn) string {
 		filter.logFilter = logFilter
 	}
 
+	return f.installFilter(filter)
+}
+
+func (f *FilterManager) installFilter(filter *Filter) string {
+	f.lock.Lock()
+
 	f.filters[filter.id] = filter
 	filter.timestamp = time.Now().Add(f.timeout)
 	heap.Push(&f.timer, filter)
@@ -364,6 +542,7 @@ func (f *FilterManager) addFilter(logFilter *LogFilter, ws wsConn) string {
 
 func (f *FilterManager) Close() {
 	close(f.closeCh)
+	f.unsubscribeDropped()
 }
 
 type timeHeapImpl []*Filter
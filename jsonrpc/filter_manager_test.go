@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"errors"
 	"sync"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
 	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -224,7 +226,12 @@ func (m *mockStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
 	panic("implement me")
 }
 
-func (m *mockStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+func (m *mockStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
 	panic("implement me")
 }
 
@@ -261,6 +268,7 @@ type mockHeader struct {
 }
 
 type mockEvent struct {
+	Type     blockchain.EventType
 	OldChain []*mockHeader
 	NewChain []*mockHeader
 }
@@ -271,6 +279,7 @@ func (m *mockStore) emitEvent(evnt *mockEvent) {
 	}
 
 	bEvnt := &blockchain.Event{
+		Type:     evnt.Type,
 		NewChain: []*types.Header{},
 		OldChain: []*types.Header{},
 	}
@@ -301,3 +310,119 @@ func (m *mockStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
 func (m *mockStore) SubscribeEvents() blockchain.Subscription {
 	return m.subscription
 }
+
+type mockDroppedStore struct {
+	*mockStore
+	droppedCh chan *txpool.DroppedTx
+}
+
+func (m *mockDroppedStore) SubscribeDropped() (<-chan *txpool.DroppedTx, func()) {
+	return m.droppedCh, func() {}
+}
+
+func TestFilterDroppedTxs(t *testing.T) {
+	store := &mockDroppedStore{
+		mockStore: newMockStore(),
+		droppedCh: make(chan *txpool.DroppedTx),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store)
+	go m.Run()
+	defer m.Close()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+	id := m.NewDroppedTxFilter(mock)
+
+	tx := &types.Transaction{Nonce: 3, From: types.StringToAddress("1")}
+	tx.ComputeHash()
+	store.droppedCh <- &txpool.DroppedTx{Tx: tx, Reason: txpool.DropLifetime}
+
+	select {
+	case msg := <-mock.msgCh:
+		var resp struct {
+			Params struct {
+				Subscription string
+				Result       droppedTx
+			}
+		}
+		assert.NoError(t, json.Unmarshal(msg, &resp))
+		assert.Equal(t, id, resp.Params.Subscription)
+		assert.Equal(t, droppedTx{
+			Hash:   tx.Hash,
+			From:   tx.From,
+			Nonce:  3,
+			Reason: "lifetime",
+		}, resp.Params.Result)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("dropped txn not sent")
+	}
+}
+
+func TestFilterReorgs(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store)
+	go m.Run()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+	id := m.NewReorgFilter(mock)
+
+	header := func(number uint64, parent types.Hash, extra byte) *mockHeader {
+		h := &types.Header{Number: number, ParentHash: parent, ExtraData: []byte{extra}}
+		h.ComputeHash()
+		return &mockHeader{header: h}
+	}
+
+	ancestor := header(1, types.ZeroHash, 0)
+	old2 := header(2, ancestor.header.Hash, 0)
+	old3 := header(3, old2.header.Hash, 0)
+	new2 := header(2, ancestor.header.Hash, 1)
+
+	// the new blocks are not a reorg
+	store.emitEvent(&mockEvent{
+		Type:     blockchain.EventHead,
+		NewChain: []*mockHeader{ancestor},
+	})
+
+	// the head is replaced by a shorter chain, the event lists the headers from the heads
+	store.emitEvent(&mockEvent{
+		Type:     blockchain.EventReorg,
+		OldChain: []*mockHeader{old2, old3},
+		NewChain: []*mockHeader{new2},
+	})
+
+	select {
+	case msg := <-mock.msgCh:
+		var resp struct {
+			Params struct {
+				Subscription string
+				Result       reorgEvent
+			}
+		}
+		assert.NoError(t, json.Unmarshal(msg, &resp))
+		assert.Equal(t, id, resp.Params.Subscription)
+		assert.Equal(t, reorgEvent{
+			Depth:          2,
+			AncestorNumber: 1,
+			AncestorHash:   ancestor.header.Hash,
+			OldHead:        old3.header.Hash,
+			NewHead:        new2.header.Hash,
+			Removed:        []types.Hash{old2.header.Hash, old3.header.Hash},
+			Added:          []types.Hash{new2.header.Hash},
+		}, resp.Params.Result)
+
+	case <-time.After(2 * time.Second):
+		t.Fatal("reorg not sent")
+	}
+
+	select {
+	case <-mock.msgCh:
+		t.Fatal("unexpected message")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,351 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// graphQL executes the queries of the Ethereum GraphQL API (EIP-1767),
+// using the same backends as the JSON-RPC endpoints
+type graphQL struct {
+	d *Dispatcher
+}
+
+type gqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type gqlError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+type gqlResponse struct {
+	Data   *gqlResult  `json:"data,omitempty"`
+	Errors []*gqlError `json:"errors,omitempty"`
+}
+
+// gqlResult is a result object, its fields are encoded in the order of the selections
+type gqlResult struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (r *gqlResult) set(key string, value interface{}) {
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (r *gqlResult) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// gqlObject is an object type of the schema
+type gqlObject interface {
+	typeName() string
+
+	// resolve returns the value of the field, which is either a scalar,
+	// an object, or a list of objects
+	resolve(field string, args map[string]interface{}) (interface{}, error)
+}
+
+func unknownField(obj gqlObject, field string) error {
+	return fmt.Errorf("unknown field %s on type %s", field, obj.typeName())
+}
+
+type gqlExecution struct {
+	doc    *gqlDocument
+	vars   map[string]interface{}
+	errors []*gqlError
+}
+
+func (g *graphQL) execute(req *gqlRequest) *gqlResponse {
+	fail := func(err error) *gqlResponse {
+		return &gqlResponse{Errors: []*gqlError{{Message: err.Error()}}}
+	}
+
+	doc, err := parseGraphQL(req.Query)
+	if err != nil {
+		return fail(err)
+	}
+
+	var op *gqlOperation
+	if req.OperationName == "" {
+		if len(doc.operations) != 1 {
+			return fail(fmt.Errorf("the operation name is required with multiple operations"))
+		}
+		op = doc.operations[0]
+	} else {
+		for _, candidate := range doc.operations {
+			if candidate.name == req.OperationName {
+				op = candidate
+			}
+		}
+		if op == nil {
+			return fail(fmt.Errorf("operation %s not found", req.OperationName))
+		}
+	}
+
+	vars := map[string]interface{}{}
+	for name, value := range op.defaults {
+		vars[name] = value.resolve(nil)
+	}
+	for name, value := range req.Variables {
+		vars[name] = value
+	}
+
+	ex := &gqlExecution{doc: doc, vars: vars}
+	data := ex.executeObject(&gqlQuery{g: g}, op.selections, nil)
+
+	return &gqlResponse{Data: data, Errors: ex.errors}
+}
+
+func (ex *gqlExecution) addError(path []interface{}, err error) {
+	ex.errors = append(ex.errors, &gqlError{Message: err.Error(), Path: path})
+}
+
+// skipped evaluates the skip and include directives of the selection
+func (ex *gqlExecution) skipped(selection *gqlSelection) bool {
+	for _, directive := range selection.directives {
+		cond, ok := directive.args["if"]
+		if !ok {
+			continue
+		}
+		value, _ := cond.resolve(ex.vars).(bool)
+
+		if (directive.name == "skip" && value) || (directive.name == "include" && !value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// collectFields groups the fields of the selections by response key, expanding the fragments
+func (ex *gqlExecution) collectFields(
+	obj gqlObject,
+	selections []*gqlSelection,
+	keys *[]string,
+	fields map[string][]*gqlSelection,
+	path []interface{},
+) {
+	for _, selection := range selections {
+		if ex.skipped(selection) {
+			continue
+		}
+
+		switch {
+		case selection.fragment != "":
+			fragment, ok := ex.doc.fragments[selection.fragment]
+			if !ok {
+				ex.addError(path, fmt.Errorf("unknown fragment %s", selection.fragment))
+				continue
+			}
+			if fragment.typeCondition == obj.typeName() {
+				ex.collectFields(obj, fragment.selections, keys, fields, path)
+			}
+
+		case selection.inline:
+			if selection.typeCondition == "" || selection.typeCondition == obj.typeName() {
+				ex.collectFields(obj, selection.selections, keys, fields, path)
+			}
+
+		default:
+			key := selection.responseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], selection)
+		}
+	}
+}
+
+func (ex *gqlExecution) executeObject(obj gqlObject, selections []*gqlSelection, path []interface{}) *gqlResult {
+	keys := []string{}
+	fields := map[string][]*gqlSelection{}
+	ex.collectFields(obj, selections, &keys, fields, path)
+
+	result := &gqlResult{values: map[string]interface{}{}}
+	for _, key := range keys {
+		field := fields[key][0]
+		fieldPath := append(append([]interface{}{}, path...), key)
+
+		// the selections of the fields with the same response key are merged
+		subSelections := []*gqlSelection{}
+		for _, f := range fields[key] {
+			subSelections = append(subSelections, f.selections...)
+		}
+
+		if field.name == "__typename" {
+			result.set(key, obj.typeName())
+			continue
+		}
+
+		args := map[string]interface{}{}
+		for name, value := range field.args {
+			args[name] = value.resolve(ex.vars)
+		}
+
+		value, err := obj.resolve(field.name, args)
+		if err != nil {
+			ex.addError(fieldPath, err)
+			result.set(key, nil)
+			continue
+		}
+
+		result.set(key, ex.complete(value, field.name, subSelections, fieldPath))
+	}
+
+	return result
+}
+
+func (ex *gqlExecution) complete(value interface{}, name string, selections []*gqlSelection, path []interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+
+	case gqlObject:
+		if len(selections) == 0 {
+			ex.addError(path, fmt.Errorf("field %s of type %s must have a selection of subfields", name, v.typeName()))
+			return nil
+		}
+		return ex.executeObject(v, selections, path)
+
+	case []gqlObject:
+		if len(selections) == 0 {
+			ex.addError(path, fmt.Errorf("field %s must have a selection of subfields", name))
+			return nil
+		}
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			list[i] = ex.executeObject(elem, selections, append(append([]interface{}{}, path...), i))
+		}
+		return list
+	}
+
+	if len(selections) != 0 {
+		ex.addError(path, fmt.Errorf("field %s has no subfields", name))
+		return nil
+	}
+
+	return value
+}
+
+// readGraphQLRequest decodes the query of the HTTP request, from the parameters of the GET requests
+// and from the body of the POST requests
+func readGraphQLRequest(req *http.Request) (*gqlRequest, error) {
+	gqlReq := &gqlRequest{}
+
+	if req.Method == http.MethodGet {
+		params := req.URL.Query()
+
+		gqlReq.Query = params.Get("query")
+		gqlReq.OperationName = params.Get("operationName")
+		if vars := params.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &gqlReq.Variables); err != nil {
+				return nil, fmt.Errorf("invalid variables: %v", err)
+			}
+		}
+
+		return gqlReq, nil
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/graphql") {
+		gqlReq.Query = string(data)
+		return gqlReq, nil
+	}
+
+	if err := json.Unmarshal(data, gqlReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %v", err)
+	}
+
+	return gqlReq, nil
+}
+
+func (j *JSONRPC) handleGraphQL(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+
+	if req.Method == http.MethodOptions {
+		return
+	}
+
+	writeResponse := func(status int, resp *gqlResponse) {
+		data, err := json.Marshal(resp)
+		if err != nil {
+			j.logger.Error("failed to encode the graphql response", "err", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(status)
+		w.Write(data)
+	}
+	writeError := func(status int, err error) {
+		writeResponse(status, &gqlResponse{Errors: []*gqlError{{Message: err.Error()}}})
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		writeError(http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+
+	// the queries have the same access rules as the eth namespace
+	if !j.access.isEnabled(serverHTTP, "eth") {
+		writeError(http.StatusForbidden, fmt.Errorf("the eth namespace is not enabled"))
+		return
+	}
+	if j.access.isProtected("eth") && !j.access.authorize(req) {
+		writeError(http.StatusUnauthorized, fmt.Errorf("the eth namespace requires authorization"))
+		return
+	}
+
+	gqlReq, err := readGraphQLRequest(req)
+	if err != nil {
+		writeError(http.StatusBadRequest, err)
+		return
+	}
+
+	j.logger.Debug("handle graphql", "query", gqlReq.Query)
+
+	writeResponse(http.StatusOK, j.graphql.execute(gqlReq))
+}
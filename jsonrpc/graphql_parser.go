@@ -0,0 +1,669 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The GraphQL documents are parsed into operations, fragments and selection sets.
+// Only the query operations are supported, the types of the variables are not checked,
+// the arguments are coerced by the resolvers instead
+
+type gqlTokenKind int
+
+const (
+	gqlEOF gqlTokenKind = iota
+	gqlPunct
+	gqlName
+	gqlInt
+	gqlFloat
+	gqlString
+)
+
+type gqlToken struct {
+	kind  gqlTokenKind
+	value string
+	pos   int
+}
+
+type gqlLexer struct {
+	src string
+	pos int
+}
+
+func (l *gqlLexer) errorf(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("syntax error at %d: %s", pos, fmt.Sprintf(format, args...))
+}
+
+func (l *gqlLexer) next() (gqlToken, error) {
+	// skip the ignored tokens
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.pos++
+		} else if c == '#' {
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		} else {
+			break
+		}
+	}
+
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return gqlToken{kind: gqlEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+		l.pos++
+		return gqlToken{kind: gqlPunct, value: string(c), pos: start}, nil
+
+	case c == '.':
+		if !strings.HasPrefix(l.src[l.pos:], "...") {
+			return gqlToken{}, l.errorf(start, "unexpected '.'")
+		}
+		l.pos += 3
+		return gqlToken{kind: gqlPunct, value: "...", pos: start}, nil
+
+	case c == '_' || isGqlLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isGqlLetter(l.src[l.pos]) || isGqlDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return gqlToken{kind: gqlName, value: l.src[start:l.pos], pos: start}, nil
+
+	case c == '-' || isGqlDigit(c):
+		kind := gqlInt
+		l.pos++
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if c == '.' || c == 'e' || c == 'E' || ((c == '+' || c == '-') && kind == gqlFloat) {
+				kind = gqlFloat
+			} else if !isGqlDigit(c) {
+				break
+			}
+			l.pos++
+		}
+		return gqlToken{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+
+	case c == '"':
+		return l.readString()
+	}
+
+	return gqlToken{}, l.errorf(start, "unexpected character %q", c)
+}
+
+func (l *gqlLexer) readString() (gqlToken, error) {
+	start := l.pos
+	l.pos++
+
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return gqlToken{kind: gqlString, value: sb.String(), pos: start}, nil
+		case '\n':
+			return gqlToken{}, l.errorf(start, "unterminated string")
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return gqlToken{}, l.errorf(start, "unterminated string")
+			}
+			l.pos++
+			switch esc := l.src[l.pos]; esc {
+			case '"', '\\', '/':
+				sb.WriteByte(esc)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+4 >= len(l.src) {
+					return gqlToken{}, l.errorf(l.pos, "invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(l.src[l.pos+1:l.pos+5], 16, 32)
+				if err != nil {
+					return gqlToken{}, l.errorf(l.pos, "invalid unicode escape")
+				}
+				sb.WriteRune(rune(r))
+				l.pos += 4
+			default:
+				return gqlToken{}, l.errorf(l.pos, "invalid escape %q", esc)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+		l.pos++
+	}
+
+	return gqlToken{}, l.errorf(start, "unterminated string")
+}
+
+func isGqlLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isGqlDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// gqlValue is an input value of a document, which may reference the variables of the operation
+type gqlValue struct {
+	variable string
+	literal  interface{}
+	list     []*gqlValue
+	object   map[string]*gqlValue
+	isList   bool
+}
+
+// resolve returns the value in the form of a decoded JSON value
+func (v *gqlValue) resolve(vars map[string]interface{}) interface{} {
+	switch {
+	case v.variable != "":
+		return vars[v.variable]
+	case v.isList:
+		list := make([]interface{}, len(v.list))
+		for i, elem := range v.list {
+			list[i] = elem.resolve(vars)
+		}
+		return list
+	case v.object != nil:
+		obj := map[string]interface{}{}
+		for k, elem := range v.object {
+			obj[k] = elem.resolve(vars)
+		}
+		return obj
+	}
+
+	return v.literal
+}
+
+type gqlDirective struct {
+	name string
+	args map[string]*gqlValue
+}
+
+// gqlSelection is either a field, a fragment spread or an inline fragment
+type gqlSelection struct {
+	// field
+	alias      string
+	name       string
+	args       map[string]*gqlValue
+	selections []*gqlSelection
+
+	// fragments
+	fragment      string
+	typeCondition string
+	inline        bool
+
+	directives []*gqlDirective
+}
+
+func (s *gqlSelection) responseKey() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type gqlOperation struct {
+	name       string
+	defaults   map[string]*gqlValue
+	selections []*gqlSelection
+}
+
+type gqlFragment struct {
+	typeCondition string
+	selections    []*gqlSelection
+}
+
+type gqlDocument struct {
+	operations []*gqlOperation
+	fragments  map[string]*gqlFragment
+}
+
+type gqlParser struct {
+	lexer *gqlLexer
+	tok   gqlToken
+}
+
+func parseGraphQL(src string) (*gqlDocument, error) {
+	p := &gqlParser{lexer: &gqlLexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &gqlDocument{fragments: map[string]*gqlFragment{}}
+	for p.tok.kind != gqlEOF {
+		switch {
+		case p.peek(gqlPunct, "{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &gqlOperation{selections: selections})
+
+		case p.peek(gqlName, "query"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+
+		case p.peek(gqlName, "fragment"):
+			name, fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[name] = fragment
+
+		case p.peek(gqlName, "mutation"), p.peek(gqlName, "subscription"):
+			return nil, fmt.Errorf("%s operations are not supported", p.tok.value)
+
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("no operation found")
+	}
+
+	return doc, nil
+}
+
+func (p *gqlParser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+
+	return nil
+}
+
+func (p *gqlParser) peek(kind gqlTokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *gqlParser) unexpected() error {
+	if p.tok.kind == gqlEOF {
+		return p.lexer.errorf(p.tok.pos, "unexpected end of document")
+	}
+	return p.lexer.errorf(p.tok.pos, "unexpected %q", p.tok.value)
+}
+
+func (p *gqlParser) expect(kind gqlTokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// skip advances if the current token matches
+func (p *gqlParser) skip(kind gqlTokenKind, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *gqlParser) parseName() (string, error) {
+	if p.tok.kind != gqlName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+
+	return name, p.advance()
+}
+
+func (p *gqlParser) parseOperation() (*gqlOperation, error) {
+	if err := p.expect(gqlName, "query"); err != nil {
+		return nil, err
+	}
+
+	op := &gqlOperation{defaults: map[string]*gqlValue{}}
+	if p.tok.kind == gqlName {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	// the variable definitions, only the default values are kept
+	if ok, err := p.skip(gqlPunct, "("); err != nil {
+		return nil, err
+	} else if ok {
+		for {
+			if ok, err := p.skip(gqlPunct, ")"); err != nil {
+				return nil, err
+			} else if ok {
+				break
+			}
+
+			if err := p.expect(gqlPunct, "$"); err != nil {
+				return nil, err
+			}
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(gqlPunct, ":"); err != nil {
+				return nil, err
+			}
+			if err := p.parseType(); err != nil {
+				return nil, err
+			}
+			if ok, err := p.skip(gqlPunct, "="); err != nil {
+				return nil, err
+			} else if ok {
+				if op.defaults[name], err = p.parseValue(true); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+
+	return op, nil
+}
+
+func (p *gqlParser) parseType() error {
+	if ok, err := p.skip(gqlPunct, "["); err != nil {
+		return err
+	} else if ok {
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if err := p.expect(gqlPunct, "]"); err != nil {
+			return err
+		}
+	} else if _, err := p.parseName(); err != nil {
+		return err
+	}
+
+	_, err := p.skip(gqlPunct, "!")
+	return err
+}
+
+func (p *gqlParser) parseFragment() (string, *gqlFragment, error) {
+	if err := p.expect(gqlName, "fragment"); err != nil {
+		return "", nil, err
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.expect(gqlName, "on"); err != nil {
+		return "", nil, err
+	}
+	typeCondition, err := p.parseName()
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return "", nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, &gqlFragment{typeCondition: typeCondition, selections: selections}, nil
+}
+
+func (p *gqlParser) parseSelectionSet() ([]*gqlSelection, error) {
+	if err := p.expect(gqlPunct, "{"); err != nil {
+		return nil, err
+	}
+
+	selections := []*gqlSelection{}
+	for {
+		if ok, err := p.skip(gqlPunct, "}"); err != nil {
+			return nil, err
+		} else if ok {
+			break
+		}
+
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+
+	if len(selections) == 0 {
+		return nil, p.lexer.errorf(p.tok.pos, "empty selection set")
+	}
+
+	return selections, nil
+}
+
+func (p *gqlParser) parseSelection() (*gqlSelection, error) {
+	var err error
+
+	if ok, err := p.skip(gqlPunct, "..."); err != nil {
+		return nil, err
+	} else if ok {
+		selection := &gqlSelection{}
+
+		if p.tok.kind == gqlName && p.tok.value != "on" {
+			// fragment spread
+			if selection.fragment, err = p.parseName(); err != nil {
+				return nil, err
+			}
+			if selection.directives, err = p.parseDirectives(); err != nil {
+				return nil, err
+			}
+			return selection, nil
+		}
+
+		selection.inline = true
+		if ok, err := p.skip(gqlName, "on"); err != nil {
+			return nil, err
+		} else if ok {
+			if selection.typeCondition, err = p.parseName(); err != nil {
+				return nil, err
+			}
+		}
+		if selection.directives, err = p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		if selection.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+		return selection, nil
+	}
+
+	selection := &gqlSelection{}
+	if selection.name, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(gqlPunct, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		selection.alias = selection.name
+		if selection.name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	if selection.args, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	if selection.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(gqlPunct, "{") {
+		if selection.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return selection, nil
+}
+
+func (p *gqlParser) parseArguments() (map[string]*gqlValue, error) {
+	args := map[string]*gqlValue{}
+
+	if ok, err := p.skip(gqlPunct, "("); err != nil || !ok {
+		return args, err
+	}
+
+	for {
+		if ok, err := p.skip(gqlPunct, ")"); err != nil {
+			return nil, err
+		} else if ok {
+			break
+		}
+
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(gqlPunct, ":"); err != nil {
+			return nil, err
+		}
+		if args[name], err = p.parseValue(false); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+func (p *gqlParser) parseDirectives() ([]*gqlDirective, error) {
+	directives := []*gqlDirective{}
+
+	for p.peek(gqlPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &gqlDirective{name: name, args: args})
+	}
+
+	return directives, nil
+}
+
+func (p *gqlParser) parseValue(constant bool) (*gqlValue, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case gqlPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			return &gqlValue{variable: name}, nil
+
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v := &gqlValue{isList: true, list: []*gqlValue{}}
+			for {
+				if ok, err := p.skip(gqlPunct, "]"); err != nil {
+					return nil, err
+				} else if ok {
+					return v, nil
+				}
+				elem, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				v.list = append(v.list, elem)
+			}
+
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v := &gqlValue{object: map[string]*gqlValue{}}
+			for {
+				if ok, err := p.skip(gqlPunct, "}"); err != nil {
+					return nil, err
+				} else if ok {
+					return v, nil
+				}
+				name, err := p.parseName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(gqlPunct, ":"); err != nil {
+					return nil, err
+				}
+				if v.object[name], err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+	case gqlInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, p.lexer.errorf(tok.pos, "invalid integer %s", tok.value)
+		}
+		return &gqlValue{literal: n}, p.advance()
+
+	case gqlFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.lexer.errorf(tok.pos, "invalid float %s", tok.value)
+		}
+		return &gqlValue{literal: f}, p.advance()
+
+	case gqlString:
+		return &gqlValue{literal: tok.value}, p.advance()
+
+	case gqlName:
+		v := &gqlValue{}
+		switch tok.value {
+		case "true":
+			v.literal = true
+		case "false":
+			v.literal = false
+		case "null":
+		default:
+			// enum values
+			v.literal = tok.value
+		}
+		return v, p.advance()
+	}
+
+	return nil, p.unexpected()
+}
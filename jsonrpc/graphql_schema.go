@@ -0,0 +1,676 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// The scalars of the schema are encoded as in EIP-1767: Long as a JSON number,
+// BigInt, Bytes, Bytes32 and Address as hex strings
+
+// gqlQuery is the root type of the queries
+type gqlQuery struct {
+	g *graphQL
+}
+
+func (q *gqlQuery) typeName() string {
+	return "Query"
+}
+
+func (q *gqlQuery) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	d := q.g.d
+
+	switch field {
+	case "block":
+		var (
+			block *types.Block
+			ok    bool
+		)
+
+		if hash, isSet, err := gqlArgHash(args, "hash"); err != nil {
+			return nil, err
+		} else if isSet {
+			block, ok = d.store.GetBlockByHash(hash, true)
+		} else {
+			number, isSet, err := gqlArgLong(args, "number")
+			if err != nil {
+				return nil, err
+			}
+			if !isSet {
+				number = d.store.Header().Number
+			}
+			block, ok = d.store.GetBlockByNumber(number, true)
+		}
+
+		if !ok {
+			return nil, nil
+		}
+		return &gqlBlock{g: q.g, block: block}, nil
+
+	case "blocks":
+		from, isSet, err := gqlArgLong(args, "from")
+		if err != nil {
+			return nil, err
+		}
+		if !isSet {
+			return nil, fmt.Errorf("the from argument is required")
+		}
+
+		head := d.store.Header().Number
+		to, isSet, err := gqlArgLong(args, "to")
+		if err != nil {
+			return nil, err
+		}
+		if !isSet || to > head {
+			to = head
+		}
+		if to < from {
+			return []gqlObject{}, nil
+		}
+		if limit := atomic.LoadUint64(&d.logsBlockRange); limit != 0 && to-from+1 > limit {
+			return nil, fmt.Errorf("query exceeds the limit of %d blocks", limit)
+		}
+
+		blocks := []gqlObject{}
+		for number := from; number <= to; number++ {
+			block, ok := d.store.GetBlockByNumber(number, true)
+			if !ok {
+				break
+			}
+			blocks = append(blocks, &gqlBlock{g: q.g, block: block})
+		}
+		return blocks, nil
+
+	case "pending":
+		block, err := d.store.PendingBlock()
+		if err != nil {
+			return nil, err
+		}
+		return &gqlPending{&gqlBlock{g: q.g, block: block}}, nil
+
+	case "transaction":
+		hash, isSet, err := gqlArgHash(args, "hash")
+		if err != nil {
+			return nil, err
+		}
+		if !isSet {
+			return nil, fmt.Errorf("the hash argument is required")
+		}
+		return q.g.transaction(hash), nil
+
+	case "logs":
+		filter, err := gqlArgLogFilter(args)
+		if err != nil {
+			return nil, err
+		}
+		return q.g.logs(filter)
+
+	case "gasPrice":
+		price := d.store.GetAvgGasPrice()
+		if price == nil {
+			price = big.NewInt(0)
+		}
+		return argBigPtr(price), nil
+
+	case "chainID":
+		return argBigPtr(new(big.Int).SetUint64(d.chainID)), nil
+	}
+
+	return nil, unknownField(q, field)
+}
+
+// transaction returns the transaction object of a mined transaction, or nil if it is not found
+func (g *graphQL) transaction(hash types.Hash) interface{} {
+	blockHash, ok := g.d.store.ReadTxLookup(hash)
+	if !ok {
+		return nil
+	}
+	block, ok := g.d.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil
+	}
+
+	for index, tx := range block.Transactions {
+		if tx.Hash == hash {
+			return &gqlTransaction{g: g, tx: tx, block: block, index: index}
+		}
+	}
+
+	return nil
+}
+
+// logs returns the logs matching the filter, with the same limits as eth_getLogs
+func (g *graphQL) logs(filter *LogFilter) ([]gqlObject, error) {
+	res, err := g.d.endpoints.Eth.GetLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []gqlObject{}
+	for _, log := range res.([]*Log) {
+		logs = append(logs, &gqlLog{g: g, log: log})
+	}
+
+	return logs, nil
+}
+
+// account returns the account object at the state of the block, or of the latest block if not set
+func (g *graphQL) account(addr types.Address, args map[string]interface{}) (interface{}, error) {
+	number, isSet, err := gqlArgLong(args, "block")
+	if err != nil {
+		return nil, err
+	}
+
+	header := g.d.store.Header()
+	if isSet {
+		var ok bool
+		if header, ok = g.d.store.GetHeaderByNumber(number); !ok {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+	}
+
+	return &gqlAccount{g: g, addr: addr, root: header.StateRoot}, nil
+}
+
+// addressTxs returns a page of the transactions of the address, with the limits of eth_getTransactionsByAddress
+func (g *graphQL) addressTxs(addr types.Address, args map[string]interface{}) (interface{}, error) {
+	cursor, _, err := gqlArgLong(args, "cursor")
+	if err != nil {
+		return nil, err
+	}
+	limit, isSet, err := gqlArgLong(args, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if !isSet {
+		limit = defaultAddressTxsLimit
+	}
+
+	page, txs, err := g.d.addressTxs(addr, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &gqlTransactionPage{page: page, transactions: []gqlObject{}}
+	for _, txn := range txs {
+		res.transactions = append(res.transactions, &gqlTransaction{
+			g:     g,
+			tx:    txn.block.Transactions[txn.index],
+			block: txn.block,
+			index: txn.index,
+		})
+	}
+
+	return res, nil
+}
+
+// gqlTransactionPage is a page of the transactions of an account, the newest first
+type gqlTransactionPage struct {
+	page         *blockchain.AddressTxsPage
+	transactions []gqlObject
+}
+
+func (p *gqlTransactionPage) typeName() string {
+	return "TransactionPage"
+}
+
+func (p *gqlTransactionPage) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "transactions":
+		return p.transactions, nil
+	case "next":
+		if p.page.Next == 0 {
+			return nil, nil
+		}
+		return p.page.Next, nil
+	case "indexedFrom":
+		return p.page.IndexedFrom, nil
+	}
+
+	return nil, unknownField(p, field)
+}
+
+type gqlBlock struct {
+	g     *graphQL
+	block *types.Block
+}
+
+func (b *gqlBlock) typeName() string {
+	return "Block"
+}
+
+func (b *gqlBlock) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	header := b.block.Header
+
+	switch field {
+	case "number":
+		return header.Number, nil
+	case "hash":
+		return header.Hash, nil
+	case "parent":
+		if header.Number == 0 {
+			return nil, nil
+		}
+		parent, ok := b.g.d.store.GetBlockByHash(header.ParentHash, true)
+		if !ok {
+			return nil, nil
+		}
+		return &gqlBlock{g: b.g, block: parent}, nil
+	case "nonce":
+		return argBytes(header.Nonce[:]), nil
+	case "transactionsRoot":
+		return header.TxRoot, nil
+	case "transactionCount":
+		return len(b.block.Transactions), nil
+	case "stateRoot":
+		return header.StateRoot, nil
+	case "receiptsRoot":
+		return header.ReceiptsRoot, nil
+	case "miner":
+		return b.g.account(header.Miner, args)
+	case "extraData":
+		return argBytes(header.ExtraData), nil
+	case "gasLimit":
+		return header.GasLimit, nil
+	case "gasUsed":
+		return header.GasUsed, nil
+	case "timestamp":
+		return header.Timestamp, nil
+	case "logsBloom":
+		return header.LogsBloom, nil
+	case "mixHash":
+		return header.MixHash, nil
+	case "difficulty":
+		return argBigPtr(new(big.Int).SetUint64(header.Difficulty)), nil
+	case "ommerHash":
+		return header.Sha3Uncles, nil
+	case "ommerCount":
+		return len(b.block.Uncles), nil
+	case "ommers":
+		ommers := []gqlObject{}
+		for _, uncle := range b.block.Uncles {
+			ommers = append(ommers, &gqlBlock{g: b.g, block: &types.Block{Header: uncle}})
+		}
+		return ommers, nil
+	case "transactions":
+		txs := []gqlObject{}
+		for index, tx := range b.block.Transactions {
+			txs = append(txs, &gqlTransaction{g: b.g, tx: tx, block: b.block, index: index})
+		}
+		return txs, nil
+	case "transactionAt":
+		index, isSet, err := gqlArgLong(args, "index")
+		if err != nil {
+			return nil, err
+		}
+		if !isSet || index >= uint64(len(b.block.Transactions)) {
+			return nil, nil
+		}
+		return &gqlTransaction{g: b.g, tx: b.block.Transactions[index], block: b.block, index: int(index)}, nil
+	case "logs":
+		filter, err := gqlArgLogFilter(args)
+		if err != nil {
+			return nil, err
+		}
+		hash := header.Hash
+		filter.BlockHash = &hash
+
+		return b.g.logs(filter)
+	case "account":
+		addr, isSet, err := gqlArgAddress(args, "address")
+		if err != nil {
+			return nil, err
+		}
+		if !isSet {
+			return nil, fmt.Errorf("the address argument is required")
+		}
+		return &gqlAccount{g: b.g, addr: addr, root: header.StateRoot}, nil
+	}
+
+	return nil, unknownField(b, field)
+}
+
+// gqlPending is the block built from the pending transactions of the pool
+type gqlPending struct {
+	*gqlBlock
+}
+
+func (p *gqlPending) typeName() string {
+	return "Pending"
+}
+
+func (p *gqlPending) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "transactionCount", "transactions", "account":
+		return p.gqlBlock.resolve(field, args)
+	}
+
+	return nil, unknownField(p, field)
+}
+
+type gqlTransaction struct {
+	g     *graphQL
+	tx    *types.Transaction
+	block *types.Block
+	index int
+}
+
+func (t *gqlTransaction) typeName() string {
+	return "Transaction"
+}
+
+func (t *gqlTransaction) receipt() (*types.Receipt, error) {
+	receipts, err := t.g.d.store.GetReceiptsByHash(t.block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if t.index >= len(receipts) {
+		return nil, fmt.Errorf("receipt not found")
+	}
+
+	return receipts[t.index], nil
+}
+
+func (t *gqlTransaction) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "hash":
+		return t.tx.Hash, nil
+	case "nonce":
+		return t.tx.Nonce, nil
+	case "index":
+		return t.index, nil
+	case "from":
+		return t.g.account(t.tx.From, args)
+	case "to":
+		if t.tx.To == nil {
+			return nil, nil
+		}
+		return t.g.account(*t.tx.To, args)
+	case "value":
+		return argBigPtr(t.tx.Value), nil
+	case "gasPrice":
+		return argBigPtr(t.tx.GasPrice), nil
+	case "gas":
+		return t.tx.Gas, nil
+	case "inputData":
+		return argBytes(t.tx.Input), nil
+	case "block":
+		return &gqlBlock{g: t.g, block: t.block}, nil
+	case "r":
+		return argBigPtr(new(big.Int).SetBytes(t.tx.R)), nil
+	case "s":
+		return argBigPtr(new(big.Int).SetBytes(t.tx.S)), nil
+	case "v":
+		return argBigPtr(new(big.Int).SetBytes(t.tx.V)), nil
+	}
+
+	receipt, err := t.receipt()
+	if err != nil {
+		return nil, err
+	}
+
+	switch field {
+	case "status":
+		if receipt.Status == nil {
+			return nil, nil
+		}
+		return uint64(*receipt.Status), nil
+	case "gasUsed":
+		return receipt.GasUsed, nil
+	case "cumulativeGasUsed":
+		return receipt.CumulativeGasUsed, nil
+	case "createdContract":
+		if t.tx.To != nil {
+			return nil, nil
+		}
+		return t.g.account(receipt.ContractAddress, args)
+	case "logs":
+		logs := []gqlObject{}
+		for index, log := range receipt.Logs {
+			logs = append(logs, &gqlLog{g: t.g, log: &Log{
+				Address:     log.Address,
+				Topics:      log.Topics,
+				Data:        argBytes(log.Data),
+				BlockNumber: argUint64(t.block.Number()),
+				BlockHash:   t.block.Hash(),
+				TxHash:      t.tx.Hash,
+				TxIndex:     argUint64(t.index),
+				LogIndex:    argUint64(index),
+			}})
+		}
+		return logs, nil
+	}
+
+	return nil, unknownField(t, field)
+}
+
+type gqlLog struct {
+	g   *graphQL
+	log *Log
+}
+
+func (l *gqlLog) typeName() string {
+	return "Log"
+}
+
+func (l *gqlLog) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "index":
+		return uint64(l.log.LogIndex), nil
+	case "account":
+		return l.g.account(l.log.Address, args)
+	case "topics":
+		return l.log.Topics, nil
+	case "data":
+		return l.log.Data, nil
+	case "transaction":
+		block, ok := l.g.d.store.GetBlockByHash(l.log.BlockHash, true)
+		if !ok || int(l.log.TxIndex) >= len(block.Transactions) {
+			return nil, nil
+		}
+		index := int(l.log.TxIndex)
+
+		return &gqlTransaction{g: l.g, tx: block.Transactions[index], block: block, index: index}, nil
+	}
+
+	return nil, unknownField(l, field)
+}
+
+type gqlAccount struct {
+	g    *graphQL
+	addr types.Address
+	root types.Hash
+}
+
+func (a *gqlAccount) typeName() string {
+	return "Account"
+}
+
+func (a *gqlAccount) resolve(field string, args map[string]interface{}) (interface{}, error) {
+	if field == "address" {
+		return a.addr, nil
+	}
+
+	store := a.g.d.store
+
+	if field == "transactions" {
+		return a.g.addressTxs(a.addr, args)
+	}
+
+	if field == "storage" {
+		slot, isSet, err := gqlArgHash(args, "slot")
+		if err != nil {
+			return nil, err
+		}
+		if !isSet {
+			return nil, fmt.Errorf("the slot argument is required")
+		}
+
+		value, err := store.GetStorage(a.root, a.addr, slot)
+		if err != nil && err != ErrStateNotFound {
+			return nil, err
+		}
+		return types.BytesToHash(value), nil
+	}
+
+	acc, err := store.GetAccount(a.root, a.addr)
+	if err != nil && err != ErrStateNotFound {
+		return nil, err
+	}
+
+	switch field {
+	case "balance":
+		if acc == nil {
+			return argBigPtr(big.NewInt(0)), nil
+		}
+		return argBigPtr(acc.Balance), nil
+	case "transactionCount":
+		if acc == nil {
+			return uint64(0), nil
+		}
+		return acc.Nonce, nil
+	case "code":
+		if acc == nil {
+			return argBytes{}, nil
+		}
+		code, err := store.GetCode(types.BytesToHash(acc.CodeHash))
+		if err != nil {
+			return nil, err
+		}
+		return argBytes(code), nil
+	}
+
+	return nil, unknownField(a, field)
+}
+
+// gqlArgLong returns the Long argument, which may be a number or a decimal or hex string
+func gqlArgLong(args map[string]interface{}, name string) (uint64, bool, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		if v >= 0 {
+			return uint64(v), true, nil
+		}
+	case float64:
+		if v >= 0 && v == float64(uint64(v)) {
+			return uint64(v), true, nil
+		}
+	case string:
+		n, err := types.ParseUint64orHex(&v)
+		if err == nil {
+			return n, true, nil
+		}
+	}
+
+	return 0, false, fmt.Errorf("invalid %s argument", name)
+}
+
+func gqlArgBytes(value interface{}, size int) ([]byte, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	b, err := hex.DecodeHex(str)
+	if err != nil || len(b) != size {
+		return nil, false
+	}
+
+	return b, true
+}
+
+func gqlArgHash(args map[string]interface{}, name string) (types.Hash, bool, error) {
+	if args[name] == nil {
+		return types.Hash{}, false, nil
+	}
+	b, ok := gqlArgBytes(args[name], types.HashLength)
+	if !ok {
+		return types.Hash{}, false, fmt.Errorf("invalid %s argument", name)
+	}
+
+	return types.BytesToHash(b), true, nil
+}
+
+func gqlArgAddress(args map[string]interface{}, name string) (types.Address, bool, error) {
+	if args[name] == nil {
+		return types.Address{}, false, nil
+	}
+	b, ok := gqlArgBytes(args[name], types.AddressLength)
+	if !ok {
+		return types.Address{}, false, fmt.Errorf("invalid %s argument", name)
+	}
+
+	return types.BytesToAddress(b), true, nil
+}
+
+// gqlArgLogFilter returns the log filter of the filter argument,
+// with the fromBlock, toBlock, addresses and topics fields
+func gqlArgLogFilter(args map[string]interface{}) (*LogFilter, error) {
+	filter := &LogFilter{
+		fromBlock: LatestBlockNumber,
+		toBlock:   LatestBlockNumber,
+	}
+
+	if args["filter"] == nil {
+		return filter, nil
+	}
+	obj, ok := args["filter"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid filter argument")
+	}
+
+	if from, isSet, err := gqlArgLong(obj, "fromBlock"); err != nil {
+		return nil, err
+	} else if isSet {
+		filter.fromBlock = BlockNumber(from)
+	}
+	if to, isSet, err := gqlArgLong(obj, "toBlock"); err != nil {
+		return nil, err
+	} else if isSet {
+		filter.toBlock = BlockNumber(to)
+	}
+
+	if obj["addresses"] != nil {
+		addresses, ok := obj["addresses"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid addresses")
+		}
+		for _, raw := range addresses {
+			b, ok := gqlArgBytes(raw, types.AddressLength)
+			if !ok {
+				return nil, fmt.Errorf("invalid address %v", raw)
+			}
+			filter.Addresses = append(filter.Addresses, types.BytesToAddress(b))
+		}
+	}
+
+	if obj["topics"] != nil {
+		sets, ok := obj["topics"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid topics")
+		}
+		for _, rawSet := range sets {
+			topics := []types.Hash{}
+
+			set, _ := rawSet.([]interface{})
+			if rawSet != nil && set == nil {
+				return nil, fmt.Errorf("invalid topics")
+			}
+			for _, raw := range set {
+				b, ok := gqlArgBytes(raw, types.HashLength)
+				if !ok {
+					return nil, fmt.Errorf("invalid topic %v", raw)
+				}
+				topics = append(topics, types.BytesToHash(b))
+			}
+			filter.Topics = append(filter.Topics, topics)
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,314 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	gqlSender = types.StringToAddress("1")
+	gqlTopic  = types.StringToHash("2")
+)
+
+// mockGraphQLStore is a chain of three blocks, with one transaction in the last one
+type mockGraphQLStore struct {
+	nullBlockchainInterface
+	blocks []*types.Block
+}
+
+func newMockGraphQLStore() *mockGraphQLStore {
+	store := &mockGraphQLStore{}
+
+	for i := 0; i < 3; i++ {
+		header := &types.Header{
+			Number:    uint64(i),
+			StateRoot: types.StringToHash("root"),
+			GasLimit:  100,
+		}
+		if i > 0 {
+			header.ParentHash = store.blocks[i-1].Hash()
+		}
+		header.ComputeHash()
+
+		store.blocks = append(store.blocks, &types.Block{Header: header})
+	}
+
+	to := types.StringToAddress("3")
+	tx := &types.Transaction{
+		Nonce:    1,
+		To:       &to,
+		From:     gqlSender,
+		Value:    big.NewInt(10),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+	}
+	tx.ComputeHash()
+	store.blocks[2].Transactions = []*types.Transaction{tx}
+
+	return store
+}
+
+func (m *mockGraphQLStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.ForksInTime{}
+}
+
+func (m *mockGraphQLStore) Header() *types.Header {
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockGraphQLStore) GetHeaderByNumber(num uint64) (*types.Header, bool) {
+	block, ok := m.GetBlockByNumber(num, false)
+	if !ok {
+		return nil, false
+	}
+	return block.Header, true
+}
+
+func (m *mockGraphQLStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+	return m.blocks[num], true
+}
+
+func (m *mockGraphQLStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	for _, block := range m.blocks {
+		if block.Hash() == hash {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+func (m *mockGraphQLStore) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	for _, block := range m.blocks {
+		for _, tx := range block.Transactions {
+			if tx.Hash == hash {
+				return block.Hash(), true
+			}
+		}
+	}
+	return types.Hash{}, false
+}
+
+func (m *mockGraphQLStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	if hash != m.blocks[2].Hash() {
+		return []*types.Receipt{}, nil
+	}
+
+	receipt := &types.Receipt{
+		GasUsed: 21000,
+		Logs: []*types.Log{
+			{Address: gqlSender, Topics: []types.Hash{gqlTopic}},
+		},
+	}
+	receipt.SetStatus(types.ReceiptSuccess)
+
+	return []*types.Receipt{receipt}, nil
+}
+
+func (m *mockGraphQLStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	if addr != gqlSender {
+		return nil, ErrStateNotFound
+	}
+	return &state.Account{Balance: big.NewInt(100), Nonce: 2}, nil
+}
+
+func (m *mockGraphQLStore) GetAddressTxs(
+	addr types.Address,
+	cursor uint64,
+	limit int,
+) (*blockchain.AddressTxsPage, error) {
+	page := &blockchain.AddressTxsPage{Txs: []*storage.AddressTx{}, IndexedFrom: 1}
+	if addr == gqlSender {
+		tx := m.blocks[2].Transactions[0]
+		page.Txs = append(page.Txs, &storage.AddressTx{BlockHash: m.blocks[2].Hash(), TxHash: tx.Hash})
+	}
+	return page, nil
+}
+
+func newTestGraphQL() *graphQL {
+	return &graphQL{d: newTestDispatcher(hclog.NewNullLogger(), newMockGraphQLStore())}
+}
+
+// executeGraphQL executes the query and returns the JSON encoding of the response
+func executeGraphQL(t *testing.T, g *graphQL, req *gqlRequest) string {
+	t.Helper()
+
+	data, err := json.Marshal(g.execute(req))
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestGraphQLParse(t *testing.T) {
+	doc, err := parseGraphQL(`
+		# a comment
+		query Blocks($n: Long = 1, $h: [Bytes32!]!) {
+			latest: block { ...fields }
+			block(number: $n, filter: {addresses: ["0x1"], flag: true}) @skip(if: false) {
+				... on Block { hash }
+			}
+		}
+		fragment fields on Block { number }
+	`)
+	assert.NoError(t, err)
+
+	assert.Len(t, doc.operations, 1)
+	op := doc.operations[0]
+	assert.Equal(t, "Blocks", op.name)
+	assert.Equal(t, int64(1), op.defaults["n"].resolve(nil))
+
+	latest := op.selections[0]
+	assert.Equal(t, "latest", latest.responseKey())
+	assert.Equal(t, "fields", latest.selections[0].fragment)
+
+	block := op.selections[1]
+	assert.Equal(t, int64(5), block.args["number"].resolve(map[string]interface{}{"n": int64(5)}))
+	assert.Equal(
+		t,
+		map[string]interface{}{"addresses": []interface{}{"0x1"}, "flag": true},
+		block.args["filter"].resolve(nil),
+	)
+	assert.Equal(t, "skip", block.directives[0].name)
+	assert.True(t, block.selections[0].inline)
+
+	assert.Equal(t, "Block", doc.fragments["fields"].typeCondition)
+
+	for _, query := range []string{
+		"",
+		"{",
+		"{ block { } }",
+		"{ block(number: ) { number } }",
+		`{ block(hash: "0x1) { number } }`,
+		"mutation { sendRawTransaction }",
+	} {
+		_, err := parseGraphQL(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestGraphQLQueries(t *testing.T) {
+	g := newTestGraphQL()
+	store := g.d.store.(*mockGraphQLStore)
+	tx := store.blocks[2].Transactions[0]
+
+	cases := []struct {
+		name     string
+		req      *gqlRequest
+		expected string
+	}{
+		{
+			"latest block",
+			&gqlRequest{Query: `{ block { number parent { number } transactionCount __typename } }`},
+			`{"data":{"block":{"number":2,"parent":{"number":1},"transactionCount":1,"__typename":"Block"}}}`,
+		},
+		{
+			"variables and aliases",
+			&gqlRequest{
+				Query:     `query($n: Long) { first: block(number: $n) { number } none: block(number: 10) { number } }`,
+				Variables: map[string]interface{}{"n": float64(1)},
+			},
+			`{"data":{"first":{"number":1},"none":null}}`,
+		},
+		{
+			"transaction",
+			&gqlRequest{
+				Query:     `query($hash: Bytes32!) { transaction(hash: $hash) { ...tx } } fragment tx on Transaction { index value status gasUsed from { balance transactionCount } block { number } }`,
+				Variables: map[string]interface{}{"hash": tx.Hash.String()},
+			},
+			`{"data":{"transaction":{"index":0,"value":"0xa","status":1,"gasUsed":21000,"from":{"balance":"0x64","transactionCount":2},"block":{"number":2}}}}`,
+		},
+		{
+			"logs",
+			&gqlRequest{
+				Query: `{ logs(filter: {fromBlock: 0, topics: [["` + gqlTopic.String() + `"]]}) { index account { address } transaction { nonce } } }`,
+			},
+			`{"data":{"logs":[{"index":0,"account":{"address":"` + gqlSender.String() + `"},"transaction":{"nonce":1}}]}}`,
+		},
+		{
+			"blocks",
+			&gqlRequest{Query: `{ blocks(from: 1) { number } }`},
+			`{"data":{"blocks":[{"number":1},{"number":2}]}}`,
+		},
+		{
+			"account transactions",
+			&gqlRequest{
+				Query: `{ block { account(address: "` + gqlSender.String() + `") { transactions(limit: 10) { transactions { nonce } next indexedFrom } } } }`,
+			},
+			`{"data":{"block":{"account":{"transactions":{"transactions":[{"nonce":1}],"next":null,"indexedFrom":1}}}}}`,
+		},
+		{
+			"field errors",
+			&gqlRequest{Query: `{ block { number unknown } chainID }`},
+			`{"data":{"block":{"number":2,"unknown":null},"chainID":"0x0"},"errors":[{"message":"unknown field unknown on type Block","path":["block","unknown"]}]}`,
+		},
+		{
+			"missing subfields",
+			&gqlRequest{Query: `{ block }`},
+			`{"data":{"block":null},"errors":[{"message":"field block of type Block must have a selection of subfields","path":["block"]}]}`,
+		},
+		{
+			"unknown operation",
+			&gqlRequest{Query: `query A { block { number } }`, OperationName: "B"},
+			`{"errors":[{"message":"operation B not found"}]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, executeGraphQL(t, g, c.req))
+		})
+	}
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	j := &JSONRPC{
+		logger:  hclog.NewNullLogger(),
+		graphql: newTestGraphQL(),
+	}
+
+	expected := `{"data":{"block":{"number":2}}}`
+
+	// POST
+	body, _ := json.Marshal(&gqlRequest{Query: "{ block { number } }"})
+	rec := httptest.NewRecorder()
+	j.handleGraphQL(rec, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, expected, rec.Body.String())
+
+	// GET
+	rec = httptest.NewRecorder()
+	j.handleGraphQL(rec, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ block { number } }"), nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, expected, rec.Body.String())
+
+	// the eth namespace is protected
+	j.access = newAccessControl(&AccessConfig{Protected: []string{"eth"}, AuthToken: "token"})
+
+	rec = httptest.NewRecorder()
+	j.handleGraphQL(rec, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec = httptest.NewRecorder()
+	j.handleGraphQL(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,109 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/helper/ipc"
+)
+
+// ipcConn is a connection of the IPC transport. The messages are JSON values
+// written one after the other, the responses and the notifications end with a new line
+type ipcConn struct {
+	conn      net.Conn
+	writeLock sync.Mutex
+}
+
+// WriteMessage writes out the message to the IPC peer
+func (c *ipcConn) WriteMessage(_ int, data []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_, err := c.conn.Write(append(data, '\n'))
+
+	return err
+}
+
+// setupIPC listens on the IPC path. The socket is only accessible to the user running the node,
+// so the IPC requests are authorized to use the protected namespaces
+func (j *JSONRPC) setupIPC() error {
+	lis, err := ipc.Listen(j.config.IPCPath)
+	if err != nil {
+		return err
+	}
+	j.ipcListener = lis
+
+	j.logger.Info("ipc server started", "path", j.config.IPCPath)
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				select {
+				case <-j.closeCh:
+				default:
+					j.logger.Error("closed ipc listener", "err", err)
+				}
+				return
+			}
+
+			go j.handleIPC(conn)
+		}
+	}()
+
+	return nil
+}
+
+func (j *JSONRPC) handleIPC(conn net.Conn) {
+	defer conn.Close()
+
+	wrapConn := &ipcConn{conn: conn}
+	ctx := requestContext{
+		transport:  serverIPC,
+		authorized: true,
+	}
+
+	j.logger.Debug("ipc connection established")
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var message json.RawMessage
+		if err := decoder.Decode(&message); err != nil {
+			if err != io.EOF {
+				// the stream can't be read past an invalid message
+				resp, _ := NewRpcResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+				_ = wrapConn.WriteMessage(0, resp)
+
+				j.logger.Debug("closing ipc connection", "err", err)
+			}
+			return
+		}
+
+		go func() {
+			var (
+				resp []byte
+				err  error
+			)
+
+			if bytes.HasPrefix(bytes.TrimLeft(message, " \t\r\n"), []byte("[")) {
+				resp, err = j.dispatcher.Handle(message, ctx)
+			} else {
+				// the single requests can subscribe to the events, like on the WS transport
+				resp, err = j.dispatcher.HandleWs(message, wrapConn, ctx)
+			}
+
+			if err != nil {
+				rpcErr, ok := err.(Error)
+				if !ok {
+					rpcErr = NewInternalError(err.Error())
+				}
+				resp, _ = NewRpcResponse(nil, "2.0", nil, rpcErr).Bytes()
+			}
+
+			_ = wrapConn.WriteMessage(0, resp)
+		}()
+	}
+}
@@ -0,0 +1,77 @@
+// +build !windows
+
+package jsonrpc
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/ipc"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonrpc-ipc")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.ipc")
+
+	access := newAccessControl(&AccessConfig{Protected: []string{"eth"}, AuthToken: "token"})
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), newMockGraphQLStore())
+	dispatcher.access = access
+
+	j := &JSONRPC{
+		logger:     hclog.NewNullLogger(),
+		config:     &Config{IPCPath: path},
+		access:     access,
+		dispatcher: dispatcher,
+		closeCh:    make(chan struct{}),
+	}
+	assert.NoError(t, j.setupIPC())
+
+	conn, err := ipc.Dial(path)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	call := func(req string) string {
+		_, err := conn.Write([]byte(req))
+		assert.NoError(t, err)
+
+		resp, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+
+		return resp
+	}
+
+	// the IPC requests can access the protected namespaces
+	assert.Equal(
+		t,
+		`{"jsonrpc":"2.0","id":1,"result":"0x2"}`+"\n",
+		call(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`),
+	)
+
+	// batch requests
+	assert.Equal(
+		t,
+		`[{"jsonrpc":"2.0","id":1,"result":"0x2"},{"jsonrpc":"2.0","id":2,"result":"0x2"}]`+"\n",
+		call(`[{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"}]`),
+	)
+
+	// the stream is closed after an invalid message
+	assert.Contains(t, call(`{"jsonrpc":`+"}\n"), "Invalid json request")
+
+	_, err = reader.ReadString('\n')
+	assert.Error(t, err)
+
+	// the socket is removed on close
+	assert.NoError(t, j.Close())
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,11 +1,14 @@
 package jsonrpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-hclog"
@@ -36,35 +39,216 @@ func (s serverType) String() string {
 type JSONRPC struct {
 	logger     hclog.Logger
 	config     *Config
+	access     *accessControl
 	dispatcher dispatcherImpl
+	graphql    *graphQL
+	limiter    *rateLimiter
+
+	httpServer  *http.Server
+	ipcListener net.Listener
+	closeCh     chan struct{}
 }
 
+// closeTimeout is the time the in-flight HTTP requests have to complete once the server is closed
+const closeTimeout = 5 * time.Second
+
+const (
+	// DefaultCallTimeout is the default time an eth_call or eth_estimateGas can run
+	DefaultCallTimeout = 5 * time.Second
+
+	// DefaultCallGasCap is the default maximum gas of an eth_call or eth_estimateGas
+	DefaultCallGasCap = 50000000
+)
+
 type dispatcherImpl interface {
-	HandleWs(reqBody []byte, conn wsConn) ([]byte, error)
-	Handle(reqBody []byte) ([]byte, error)
+	HandleWs(reqBody []byte, conn wsConn, ctx requestContext) ([]byte, error)
+	Handle(reqBody []byte, ctx requestContext) ([]byte, error)
 }
 
 type Config struct {
 	Store   blockchainInterface
 	Addr    *net.TCPAddr
 	ChainID uint64
+	Access  *AccessConfig
+
+	// Accounts enables the personal namespace and the node-side signing of eth_sendTransaction,
+	// eth_sign and eth_signTypedData_v4
+	Accounts accountManager
+
+	// Peers enables the admin namespace, managing the peers of the node
+	Peers peerManager
+
+	// Dev enables the evm namespace, controlling the chain of the tests
+	Dev devChain
+
+	// Bridge enables the bridge namespace, proving the withdrawals to the root chain
+	Bridge exitProver
+
+	// Debug enables the debug namespace, meant for the operators as it re-executes the blocks
+	// and dumps the state
+	Debug bool
+
+	// Trace enables the trace namespace, re-executing the blocks for their call traces
+	Trace bool
+
+	// LogsBlockRange is the maximum number of blocks an eth_getLogs query can span. Zero means unlimited
+	LogsBlockRange uint64
+
+	// LogsResultLimit is the maximum number of logs an eth_getLogs query spanning
+	// more than one block can return. Zero means unlimited
+	LogsResultLimit uint64
+
+	// CallTimeout and CallGasCap are the maximum time and gas of an eth_call execution, and of the whole
+	// search of an eth_estimateGas. Zero means unlimited
+	CallTimeout time.Duration
+	CallGasCap  uint64
+
+	// LoadShed rejects the low priority calls when the resources of the node cross the thresholds
+	LoadShed *LoadShedConfig
+
+	// GraphQL enables the GraphQL API on the /graphql path of the HTTP server
+	GraphQL bool
+
+	// RateLimit sets the quotas of the clients of the HTTP and WS transports, if not nil
+	RateLimit *RateLimitConfig
+
+	// CallCache caches the results of the eth_call and eth_estimateGas requests for a short time, if not nil
+	CallCache *CallCacheConfig
+
+	// Metrics are the metrics of the server, optional
+	Metrics *Metrics
+
+	// IPCPath is the path of the unix socket (named pipe on windows) of the IPC transport.
+	// The IPC transport is disabled if it is empty
+	IPCPath string
 }
 
 // NewJSONRPC returns the JsonRPC http server
 func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
+	access := newAccessControl(config.Access)
+	dispatcher := newDispatcherWithAccess(logger, config.Store, config.ChainID, access)
+	if config.Accounts != nil {
+		dispatcher.enablePersonal(config.Accounts)
+	}
+	if config.Peers != nil {
+		dispatcher.enableAdmin(config.Peers)
+	}
+	if config.Dev != nil {
+		dispatcher.enableEvm(config.Dev)
+	}
+	if config.Bridge != nil {
+		dispatcher.enableBridge(config.Bridge)
+	}
+	if config.Debug {
+		dispatcher.enableDebug()
+	}
+	if config.Trace {
+		dispatcher.enableTrace()
+	}
+	dispatcher.logsBlockRange = config.LogsBlockRange
+	dispatcher.logsResultLimit = config.LogsResultLimit
+	dispatcher.setCallLimits(config.CallTimeout, config.CallGasCap)
+
+	if err := config.Access.validate(dispatcher.serviceMap); err != nil {
+		if dispatcher.filterManager != nil {
+			dispatcher.filterManager.Close()
+		}
+		return nil, err
+	}
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = NilMetrics()
+	}
+	dispatcher.metrics = metrics
+
+	if config.CallCache != nil {
+		if err := dispatcher.enableCallCache(config.CallCache, metrics); err != nil {
+			if dispatcher.filterManager != nil {
+				dispatcher.filterManager.Close()
+			}
+			return nil, err
+		}
+	}
+
+	if config.LoadShed != nil {
+		dispatcher.enableLoadShedding(config.LoadShed)
+	}
+
 	srv := &JSONRPC{
 		logger:     logger.Named("jsonrpc"),
 		config:     config,
-		dispatcher: newDispatcher(logger, config.Store, config.ChainID),
+		access:     access,
+		dispatcher: dispatcher,
+		closeCh:    make(chan struct{}),
+	}
+	if config.GraphQL {
+		srv.graphql = &graphQL{d: dispatcher}
+	}
+	if config.RateLimit != nil {
+		srv.limiter = newRateLimiter(srv.logger, config.RateLimit, metrics)
+
+		go srv.limiter.run(srv.closeCh)
 	}
 
 	// start http server
 	if err := srv.setupHTTP(); err != nil {
 		return nil, err
 	}
+
+	// start ipc server
+	if config.IPCPath != "" {
+		if err := srv.setupIPC(); err != nil {
+			return nil, err
+		}
+	}
 	return srv, nil
 }
 
+// SetRateLimit replaces the quotas of the clients. The rate limiter must have been enabled by the config
+func (j *JSONRPC) SetRateLimit(config *RateLimitConfig) error {
+	if j.limiter == nil {
+		return errors.New("the rate limiter is not enabled")
+	}
+	j.limiter.setConfig(config)
+
+	return nil
+}
+
+// SetLogsLimits replaces the maximum block range and number of logs of the eth_getLogs queries
+func (j *JSONRPC) SetLogsLimits(blockRange, resultLimit uint64) {
+	if d, ok := j.dispatcher.(*Dispatcher); ok {
+		d.setLogsLimits(blockRange, resultLimit)
+	}
+}
+
+// SetCallLimits replaces the maximum time and gas of the eth_call and eth_estimateGas executions
+func (j *JSONRPC) SetCallLimits(timeout time.Duration, gasCap uint64) {
+	if d, ok := j.dispatcher.(*Dispatcher); ok {
+		d.setCallLimits(timeout, gasCap)
+	}
+}
+
+// Close stops the HTTP and the IPC transports, removing the IPC socket
+func (j *JSONRPC) Close() error {
+	close(j.closeCh)
+
+	// the new requests are refused, the in-flight ones complete
+	if j.httpServer != nil {
+		ctx, cancelFn := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancelFn()
+
+		if err := j.httpServer.Shutdown(ctx); err != nil {
+			j.logger.Error("failed to shut down the http server", "err", err)
+		}
+	}
+
+	if j.ipcListener != nil {
+		return j.ipcListener.Close()
+	}
+	return nil
+}
+
 func (j *JSONRPC) setupHTTP() error {
 	j.logger.Info("http server started", "addr", j.config.Addr.String())
 
@@ -76,12 +260,22 @@ func (j *JSONRPC) setupHTTP() error {
 	mux := http.DefaultServeMux
 	mux.HandleFunc("/", j.handle)
 	mux.HandleFunc("/ws", j.handleWs)
+	if j.graphql != nil {
+		mux.HandleFunc("/graphql", j.handleGraphQL)
+	}
 
-	srv := http.Server{
-		Handler: mux,
+	var handler http.Handler = mux
+	if j.limiter != nil {
+		handler = j.limiter.middleware(mux)
 	}
+
+	srv := &http.Server{
+		Handler: handler,
+	}
+	j.httpServer = srv
+
 	go func() {
-		if err := srv.Serve(lis); err != nil {
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
 			j.logger.Error("closed http connection", "err", err)
 		}
 	}()
@@ -150,6 +344,15 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 	}(ws)
 
 	wrapConn := &wsWrapper{ws: ws, logger: j.logger}
+	key := ""
+	if j.limiter != nil {
+		key = j.limiter.clientKey(req)
+	}
+	ctx := requestContext{
+		transport:  serverWS,
+		authorized: j.access.authorize(req),
+	}
+
 	j.logger.Info("Websocket connection established")
 	// Run the listen loop
 	for {
@@ -172,8 +375,20 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if isSupportedWSType(msgType) {
+			release := func() {}
+			if j.limiter != nil {
+				if release, err = j.limiter.acquire(key, j.limiter.requestWeight(message), true); err != nil {
+					resp, _ := NewRpcResponse(nil, "2.0", nil, NewLimitExceededError(err.Error(), nil)).Bytes()
+					_ = wrapConn.WriteMessage(msgType, resp)
+
+					continue
+				}
+			}
+
 			go func() {
-				resp, handleErr := j.dispatcher.HandleWs(message, wrapConn)
+				defer release()
+
+				resp, handleErr := j.dispatcher.HandleWs(message, wrapConn, ctx)
 				if handleErr != nil {
 					j.logger.Error(fmt.Sprintf("Unable to handle WS request, %s", handleErr.Error()))
 
@@ -215,7 +430,10 @@ func (j *JSONRPC) handle(w http.ResponseWriter, req *http.Request) {
 	// log request
 	j.logger.Debug("handle", "request", string(data))
 
-	resp, err := j.dispatcher.Handle(data)
+	resp, err := j.dispatcher.Handle(data, requestContext{
+		transport:  serverHTTP,
+		authorized: j.access.authorize(req),
+	})
 
 	if err != nil {
 		w.Write([]byte(err.Error()))
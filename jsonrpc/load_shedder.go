@@ -0,0 +1,149 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// loadSampleInterval is the time between the samples of the CPU and memory usage
+	loadSampleInterval = time.Second
+
+	// wideLogsBlockRange is the block range above which an eth_getLogs query is a low priority call
+	wideLogsBlockRange = 1000
+)
+
+// LoadShedConfig sets the thresholds above which the low priority calls are rejected,
+// so the resources are left to the block production. Zero thresholds are disabled
+type LoadShedConfig struct {
+	// MaxCPU is the fraction of the CPUs of the host used by the process, between 0 and 1
+	MaxCPU float64
+
+	// MaxMemory is the size in bytes of the heap in use
+	MaxMemory uint64
+
+	// MaxQueue is the number of calls being executed
+	MaxQueue int64
+}
+
+// loadShedder tracks the resources used by the process and the calls being executed
+type loadShedder struct {
+	logger hclog.Logger
+	config *LoadShedConfig
+
+	inflight int64
+	cpu      uint64 // float64 bits
+	memory   uint64
+
+	shed uint64
+}
+
+func newLoadShedder(logger hclog.Logger, config *LoadShedConfig) *loadShedder {
+	return &loadShedder{
+		logger: logger.Named("load-shedder"),
+		config: config,
+	}
+}
+
+// run samples the CPU and memory usage of the process
+func (l *loadShedder) run() {
+	lastCPU, cpuOk := processCPUTime()
+	lastTime := time.Now()
+
+	ticker := time.NewTicker(loadSampleInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if l.config.MaxCPU != 0 && cpuOk {
+			cpu, ok := processCPUTime()
+			if ok {
+				elapsed := now.Sub(lastTime) * time.Duration(runtime.NumCPU())
+				if elapsed > 0 {
+					atomic.StoreUint64(&l.cpu, math.Float64bits(float64(cpu-lastCPU)/float64(elapsed)))
+				}
+				lastCPU = cpu
+			}
+		}
+		lastTime = now
+
+		if l.config.MaxMemory != 0 {
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+
+			atomic.StoreUint64(&l.memory, stats.HeapInuse)
+		}
+	}
+}
+
+// track records a call being executed, until the returned function is called
+func (l *loadShedder) track() func() {
+	atomic.AddInt64(&l.inflight, 1)
+
+	return func() {
+		atomic.AddInt64(&l.inflight, -1)
+	}
+}
+
+// loadShedData is the data of the error returned to the rejected calls
+type loadShedData struct {
+	Reason    string  `json:"reason"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+}
+
+// admit returns an error if any of the thresholds is crossed
+func (l *loadShedder) admit(method string) Error {
+	var data *loadShedData
+
+	if cpu := math.Float64frombits(atomic.LoadUint64(&l.cpu)); l.config.MaxCPU != 0 && cpu > l.config.MaxCPU {
+		data = &loadShedData{Reason: "cpu", Value: cpu, Threshold: l.config.MaxCPU}
+	} else if memory := atomic.LoadUint64(&l.memory); l.config.MaxMemory != 0 && memory > l.config.MaxMemory {
+		data = &loadShedData{Reason: "memory", Value: float64(memory), Threshold: float64(l.config.MaxMemory)}
+	} else if queue := atomic.LoadInt64(&l.inflight); l.config.MaxQueue != 0 && queue > l.config.MaxQueue {
+		data = &loadShedData{Reason: "queue", Value: float64(queue), Threshold: float64(l.config.MaxQueue)}
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	if shed := atomic.AddUint64(&l.shed, 1); shed%100 == 1 {
+		l.logger.Warn("rejecting low priority calls", "method", method, "reason", data.Reason, "shed", shed)
+	}
+
+	return NewLimitExceededError(
+		fmt.Sprintf("the node is overloaded (%s), %s is rejected, retry later", data.Reason, method),
+		data,
+	)
+}
+
+// isLowPriority checks if the call can be rejected under load: the debug and trace namespaces,
+// and the eth_getLogs queries over a wide range of blocks
+func (d *Dispatcher) isLowPriority(req Request) bool {
+	if strings.HasPrefix(req.Method, "debug_") || strings.HasPrefix(req.Method, "trace_") {
+		return true
+	}
+	if req.Method != "eth_getLogs" {
+		return false
+	}
+
+	var params []*LogFilter
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) != 1 || params[0] == nil {
+		// the invalid calls fail anyway
+		return false
+	}
+	if params[0].BlockHash != nil {
+		return false
+	}
+
+	from, to := d.logsRange(params[0])
+
+	return to >= from && to-from+1 > wideLogsBlockRange
+}
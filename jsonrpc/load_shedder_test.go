@@ -0,0 +1,95 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadShedder(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), newMockGraphQLStore())
+	dispatcher.enableDebug()
+	dispatcher.shedder = newLoadShedder(hclog.NewNullLogger(), &LoadShedConfig{
+		MaxCPU:    0.8,
+		MaxMemory: 1 << 30,
+		MaxQueue:  10,
+	})
+
+	calls := []struct {
+		body     string
+		rejected bool
+	}{
+		{`{"method": "debug_getForkBranches"}`, true},
+		{`{"method": "eth_getLogs", "params": [{"fromBlock": "0x0", "toBlock": "0x2000"}]}`, true},
+		{`{"method": "eth_getLogs", "params": [{"fromBlock": "0x0", "toBlock": "latest"}]}`, false},
+		{`{"method": "eth_blockNumber"}`, false},
+	}
+
+	// handle returns the error of the call, if any
+	handle := func(body string) *ErrorObject {
+		resp, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var res ErrorResponse
+		assert.NoError(t, json.Unmarshal(resp, &res))
+
+		return res.Error
+	}
+
+	// no threshold is crossed
+	for _, c := range calls {
+		if err := handle(c.body); err != nil {
+			assert.NotEqual(t, -32005, err.Code, c.body)
+		}
+	}
+
+	overloads := []struct {
+		reason string
+		set    func()
+		reset  func()
+	}{
+		{
+			"cpu",
+			func() { dispatcher.shedder.cpu = math.Float64bits(0.9) },
+			func() { dispatcher.shedder.cpu = 0 },
+		},
+		{
+			"memory",
+			func() { dispatcher.shedder.memory = 2 << 30 },
+			func() { dispatcher.shedder.memory = 0 },
+		},
+		{
+			"queue",
+			func() { dispatcher.shedder.inflight = 10 },
+			func() { dispatcher.shedder.inflight = 0 },
+		},
+	}
+
+	for _, o := range overloads {
+		t.Run(o.reason, func(t *testing.T) {
+			o.set()
+			defer o.reset()
+
+			for _, c := range calls {
+				err := handle(c.body)
+				if !c.rejected {
+					if err != nil {
+						assert.NotEqual(t, -32005, err.Code, c.body)
+					}
+					continue
+				}
+
+				if assert.NotNil(t, err, c.body) {
+					assert.Equal(t, -32005, err.Code)
+					assert.Equal(t, o.reason, err.Data.(map[string]interface{})["reason"])
+				}
+			}
+		})
+	}
+
+	// the calls being executed are released
+	assert.Equal(t, int64(0), dispatcher.shedder.inflight)
+}
@@ -0,0 +1,75 @@
+package jsonrpc
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the JSON-RPC server metrics
+type Metrics struct {
+	// No.of requests rejected by the rate limiter, by reason
+	RateLimited metrics.Counter
+	// No.of clients tracked by the rate limiter
+	RateLimitedClients metrics.Gauge
+	// No.of eth_call and eth_estimateGas requests served from the result cache, by method
+	CallCacheHits metrics.Counter
+	// No.of eth_call and eth_estimateGas requests executed on a cache miss, by method
+	CallCacheMisses metrics.Counter
+	// Latency of the requests, by method
+	RequestDuration metrics.Histogram
+}
+
+// GetPrometheusMetrics return the JSON-RPC server metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		RateLimited: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "rate_limited_requests",
+			Help:      "Number of requests rejected by the rate limiter.",
+		}, append(labels, "reason")).With(labelsWithValues...),
+		RateLimitedClients: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "rate_limited_clients",
+			Help:      "Number of clients tracked by the rate limiter.",
+		}, labels).With(labelsWithValues...),
+		CallCacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "call_cache_hits",
+			Help:      "Number of eth_call and eth_estimateGas requests served from the result cache.",
+		}, append(labels, "method")).With(labelsWithValues...),
+		CallCacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "call_cache_misses",
+			Help:      "Number of eth_call and eth_estimateGas requests executed on a cache miss.",
+		}, append(labels, "method")).With(labelsWithValues...),
+		RequestDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "request_duration",
+			Help:      "Latency of the requests in seconds.",
+		}, append(labels, "method")).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational JSON-RPC server metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		RateLimited:        discard.NewCounter(),
+		RateLimitedClients: discard.NewGauge(),
+		CallCacheHits:      discard.NewCounter(),
+		CallCacheMisses:    discard.NewCounter(),
+		RequestDuration:    discard.NewHistogram(),
+	}
+}
@@ -0,0 +1,203 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// defaultUnlockDuration is the unlock duration used when the request does not set one
+const defaultUnlockDuration = 300 * time.Second
+
+// errNoKeystore is returned by the signing methods if the node holds no accounts
+var errNoKeystore = errors.New("the personal namespace is disabled, the node holds no accounts")
+
+// signatureLength is the length of the signatures of personal_sign, the recovery id is the last byte
+const signatureLength = 65
+
+// accountManager is the keystore backing the personal namespace
+type accountManager interface {
+	// NewAccount creates a new passphrase protected account
+	NewAccount(passphrase string) (types.Address, error)
+
+	// Accounts returns the addresses of the stored accounts
+	Accounts() ([]types.Address, error)
+
+	// HasAccount checks if the account is stored in the keystore
+	HasAccount(addr types.Address) bool
+
+	// Unlock decrypts the key of the account for the given duration (zero means until locked)
+	Unlock(addr types.Address, passphrase string, timeout time.Duration) error
+
+	// Lock removes the decrypted key of the account from memory
+	Lock(addr types.Address) error
+
+	// SignTx signs the transaction with the key of the unlocked sender
+	SignTx(tx *types.Transaction, signer crypto.TxSigner) (*types.Transaction, error)
+
+	// SignHash signs the hash with the key of the unlocked account
+	SignHash(addr types.Address, hash []byte) ([]byte, error)
+
+	// SignTxWithPassphrase signs the transaction with the key of the sender, decrypted with the passphrase
+	SignTxWithPassphrase(tx *types.Transaction, signer crypto.TxSigner, passphrase string) (*types.Transaction, error)
+
+	// SignHashWithPassphrase signs the hash with the key of the account, decrypted with the passphrase
+	SignHashWithPassphrase(addr types.Address, passphrase string, hash []byte) ([]byte, error)
+}
+
+// Personal is the personal jsonrpc endpoint, managing the node-side signing accounts
+type Personal struct {
+	d *Dispatcher
+}
+
+// NewAccount creates a new account protected by the passphrase
+func (p *Personal) NewAccount(passphrase string) (interface{}, error) {
+	return p.d.accounts.NewAccount(passphrase)
+}
+
+// ListAccounts returns the addresses of the accounts held by the node
+func (p *Personal) ListAccounts() (interface{}, error) {
+	return p.d.accounts.Accounts()
+}
+
+// UnlockAccount unlocks the account for the given duration in seconds.
+// If the duration is not set the account is unlocked for 300 seconds,
+// while a zero duration keeps it unlocked until it is locked explicitly
+func (p *Personal) UnlockAccount(addr types.Address, passphrase string, duration *argUint64) (interface{}, error) {
+	timeout := defaultUnlockDuration
+	if duration != nil {
+		timeout = time.Duration(*duration) * time.Second
+	}
+
+	if err := p.d.accounts.Unlock(addr, passphrase, timeout); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LockAccount removes the decrypted key of the account from memory
+func (p *Personal) LockAccount(addr types.Address) (interface{}, error) {
+	if err := p.d.accounts.Lock(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SendTransaction signs the transaction with the key of the sender, decrypted with the passphrase,
+// and sends it. The account is not unlocked
+func (p *Personal) SendTransaction(arg *txnArgs, passphrase string) (interface{}, error) {
+	if arg.From == nil {
+		return nil, fmt.Errorf("the sender of the transaction is not set")
+	}
+
+	transaction, err := p.d.fillTransaction(arg)
+	if err != nil {
+		return nil, err
+	}
+	if transaction, err = p.d.accounts.SignTxWithPassphrase(transaction, p.d.txSigner(), passphrase); err != nil {
+		return nil, err
+	}
+
+	if err := p.d.store.AddTx(transaction); err != nil {
+		return nil, err
+	}
+
+	return transaction.Hash.String(), nil
+}
+
+// Sign returns the signature of the data by the account, its key decrypted with the passphrase.
+// The data is prefixed as in EIP-191, so that the signature can't be used for a transaction
+func (p *Personal) Sign(data argBytes, addr types.Address, passphrase string) (interface{}, error) {
+	sig, err := p.d.accounts.SignHashWithPassphrase(addr, passphrase, signHash(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return walletSignature(sig), nil
+}
+
+// EcRecover returns the address of the account which signed the data with personal_sign
+func (p *Personal) EcRecover(data argBytes, sig argBytes) (interface{}, error) {
+	if len(sig) != signatureLength {
+		return nil, fmt.Errorf("the signature must be %d bytes long", signatureLength)
+	}
+	if sig[signatureLength-1] != 27 && sig[signatureLength-1] != 28 {
+		return nil, fmt.Errorf("invalid recovery id, expected 27 or 28")
+	}
+
+	rsv := append([]byte{}, sig...)
+	rsv[signatureLength-1] -= 27
+
+	pub, err := crypto.SigToPub(signHash(data), rsv)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.PubKeyToAddress(pub), nil
+}
+
+// walletSignature sets the recovery id of the signature to 27 or 28, as expected by the wallets and by ecrecover
+func walletSignature(sig []byte) argBytes {
+	sig[signatureLength-1] += 27
+
+	return argBytes(sig)
+}
+
+// signHash is the hash of the data signed by personal_sign and eth_sign, prefixed as in EIP-191
+func signHash(data []byte) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(data))
+
+	return crypto.Keccak256([]byte(prefix), data)
+}
+
+// txSigner returns the signer of the transactions at the head of the chain
+func (d *Dispatcher) txSigner() crypto.TxSigner {
+	header := d.store.Header()
+
+	return crypto.NewSigner(d.store.GetForksInTime(header.Number), d.chainID)
+}
+
+// signTransaction fills in the missing fields of the transaction
+// and signs it with the unlocked key of the sender
+func (d *Dispatcher) signTransaction(arg *txnArgs) (*types.Transaction, error) {
+	transaction, err := d.fillTransaction(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.accounts.SignTx(transaction, d.txSigner())
+}
+
+// fillTransaction fills in the missing nonce, gas price and gas of the transaction
+func (d *Dispatcher) fillTransaction(arg *txnArgs) (*types.Transaction, error) {
+	if arg.Nonce == nil {
+		// take the pending transactions of the account into account
+		nonce, err := d.getNextNonce(*arg.From, PendingBlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		arg.Nonce = argUintPtr(nonce)
+	}
+	if arg.GasPrice == nil {
+		arg.GasPrice = argBytesPtr(d.store.GetAvgGasPrice().Bytes())
+	}
+	if arg.Gas == nil {
+		estimate, err := d.endpoints.Eth.EstimateGas(arg, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate the gas: %v", err)
+		}
+		str := estimate.(string)
+		gas, err := types.ParseUint64orHex(&str)
+		if err != nil {
+			return nil, err
+		}
+		arg.Gas = argUintPtr(gas)
+	}
+
+	return d.decodeTxn(arg)
+}
@@ -0,0 +1,322 @@
+package jsonrpc
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAccounts struct {
+	passphrase string
+	keys       map[types.Address]*ecdsa.PrivateKey
+	unlocked   map[types.Address]time.Duration
+}
+
+func newMockAccounts() *mockAccounts {
+	return &mockAccounts{
+		keys:     map[types.Address]*ecdsa.PrivateKey{},
+		unlocked: map[types.Address]time.Duration{},
+	}
+}
+
+func (m *mockAccounts) NewAccount(passphrase string) (types.Address, error) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubKeyToAddress(&key.PublicKey)
+
+	m.passphrase = passphrase
+	m.keys[addr] = key
+
+	return addr, nil
+}
+
+func (m *mockAccounts) Accounts() ([]types.Address, error) {
+	addrs := []types.Address{}
+	for addr := range m.keys {
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func (m *mockAccounts) HasAccount(addr types.Address) bool {
+	_, ok := m.keys[addr]
+	return ok
+}
+
+func (m *mockAccounts) Unlock(addr types.Address, passphrase string, timeout time.Duration) error {
+	if passphrase != m.passphrase {
+		return errors.New("wrong passphrase")
+	}
+	m.unlocked[addr] = timeout
+	return nil
+}
+
+func (m *mockAccounts) Lock(addr types.Address) error {
+	delete(m.unlocked, addr)
+	return nil
+}
+
+func (m *mockAccounts) SignTx(tx *types.Transaction, signer crypto.TxSigner) (*types.Transaction, error) {
+	if _, ok := m.unlocked[tx.From]; !ok {
+		return nil, errors.New("account is locked")
+	}
+	return signer.SignTx(tx, m.keys[tx.From])
+}
+
+func (m *mockAccounts) SignHash(addr types.Address, hash []byte) ([]byte, error) {
+	if _, ok := m.unlocked[addr]; !ok {
+		return nil, errors.New("account is locked")
+	}
+	return crypto.Sign(m.keys[addr], hash)
+}
+
+func (m *mockAccounts) SignTxWithPassphrase(tx *types.Transaction, signer crypto.TxSigner, passphrase string) (*types.Transaction, error) {
+	if passphrase != m.passphrase {
+		return nil, errors.New("wrong passphrase")
+	}
+	return signer.SignTx(tx, m.keys[tx.From])
+}
+
+func (m *mockAccounts) SignHashWithPassphrase(addr types.Address, passphrase string, hash []byte) ([]byte, error) {
+	if passphrase != m.passphrase {
+		return nil, errors.New("wrong passphrase")
+	}
+	return crypto.Sign(m.keys[addr], hash)
+}
+
+type mockPersonalStore struct {
+	mockStoreTxn
+}
+
+func (m *mockPersonalStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(blockNumber)
+}
+
+func (m *mockPersonalStore) GetNonce(addr types.Address) (uint64, bool) {
+	return 5, true
+}
+
+func (m *mockPersonalStore) GetAvgGasPrice() *big.Int {
+	return big.NewInt(10)
+}
+
+func TestPersonalNamespaceDisabled(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockPersonalStore{})
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "personal_listAccounts"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var accounts []types.Address
+	assert.Error(t, expectJSONResult(resp, &accounts))
+}
+
+func TestPersonalAccounts(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockPersonalStore{})
+	accounts := newMockAccounts()
+	dispatcher.enablePersonal(accounts)
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "personal_newAccount", "params": ["pass"]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var addr types.Address
+	assert.NoError(t, expectJSONResult(resp, &addr))
+	assert.True(t, accounts.HasAccount(addr))
+
+	resp, err = dispatcher.Handle([]byte(`{"method": "personal_listAccounts"}`), requestContext{})
+	assert.NoError(t, err)
+
+	var list []types.Address
+	assert.NoError(t, expectJSONResult(resp, &list))
+	assert.Equal(t, []types.Address{addr}, list)
+
+	// the default unlock duration is used when none is set
+	_, err = dispatcher.endpoints.Personal.UnlockAccount(addr, "pass", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultUnlockDuration, accounts.unlocked[addr])
+
+	_, err = dispatcher.endpoints.Personal.UnlockAccount(addr, "pass", argUintPtr(0))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), accounts.unlocked[addr])
+
+	_, err = dispatcher.endpoints.Personal.UnlockAccount(addr, "wrong", argUintPtr(10))
+	assert.Error(t, err)
+
+	_, err = dispatcher.endpoints.Personal.LockAccount(addr)
+	assert.NoError(t, err)
+	assert.NotContains(t, accounts.unlocked, addr)
+}
+
+func TestPersonalSendTransaction(t *testing.T) {
+	store := &mockPersonalStore{}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	accounts := newMockAccounts()
+	dispatcher.enablePersonal(accounts)
+
+	addr, _ := accounts.NewAccount("pass")
+	arg := func() *txnArgs {
+		return &txnArgs{
+			From: argAddrPtr(addr),
+			To:   argAddrPtr(addr0),
+			Gas:  argUintPtr(21000),
+		}
+	}
+
+	// the account has to be unlocked for signing
+	_, err := dispatcher.endpoints.Eth.SendTransaction(arg())
+	assert.Error(t, err)
+
+	assert.NoError(t, accounts.Unlock(addr, "pass", 0))
+
+	_, err = dispatcher.endpoints.Eth.SendTransaction(arg())
+	assert.NoError(t, err)
+
+	txn := store.txn
+	assert.Equal(t, uint64(5), txn.Nonce)
+	assert.Equal(t, big.NewInt(10), txn.GasPrice)
+
+	sender, err := crypto.NewEIP155Signer(dispatcher.chainID).Sender(txn)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, sender)
+}
+
+func TestPersonalSendTransactionWithPassphrase(t *testing.T) {
+	store := &mockPersonalStore{}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	accounts := newMockAccounts()
+	dispatcher.enablePersonal(accounts)
+
+	addr, _ := accounts.NewAccount("pass")
+	arg := func() *txnArgs {
+		return &txnArgs{
+			From: argAddrPtr(addr),
+			To:   argAddrPtr(addr0),
+			Gas:  argUintPtr(21000),
+		}
+	}
+
+	_, err := dispatcher.endpoints.Personal.SendTransaction(arg(), "wrong")
+	assert.Error(t, err)
+
+	// the account is not unlocked to sign with the passphrase
+	_, err = dispatcher.endpoints.Personal.SendTransaction(arg(), "pass")
+	assert.NoError(t, err)
+	assert.NotContains(t, accounts.unlocked, addr)
+
+	txn := store.txn
+	assert.Equal(t, uint64(5), txn.Nonce)
+
+	sender, err := crypto.NewEIP155Signer(dispatcher.chainID).Sender(txn)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, sender)
+}
+
+func TestPersonalSign(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockPersonalStore{})
+	accounts := newMockAccounts()
+	dispatcher.enablePersonal(accounts)
+
+	addr, _ := accounts.NewAccount("pass")
+	data := argBytes("hello")
+
+	_, err := dispatcher.endpoints.Personal.Sign(data, addr, "wrong")
+	assert.Error(t, err)
+
+	res, err := dispatcher.endpoints.Personal.Sign(data, addr, "pass")
+	assert.NoError(t, err)
+
+	sig := res.(argBytes)
+	assert.Len(t, sig, signatureLength)
+	assert.Contains(t, []byte{27, 28}, sig[signatureLength-1])
+
+	signer, err := dispatcher.endpoints.Personal.EcRecover(data, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, signer)
+
+	// the signature of other data recovers another account
+	signer, err = dispatcher.endpoints.Personal.EcRecover(argBytes("world"), sig)
+	assert.NoError(t, err)
+	assert.NotEqual(t, addr, signer)
+
+	_, err = dispatcher.endpoints.Personal.EcRecover(data, sig[:64])
+	assert.Error(t, err)
+}
+
+func TestEthSign(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockPersonalStore{})
+
+	// the signing requires the keystore
+	_, err := dispatcher.endpoints.Eth.Sign(addr0, argBytes("hello"))
+	assert.Error(t, err)
+
+	accounts := newMockAccounts()
+	dispatcher.enablePersonal(accounts)
+	addr, _ := accounts.NewAccount("pass")
+
+	// the account has to be unlocked for signing
+	_, err = dispatcher.endpoints.Eth.Sign(addr, argBytes("hello"))
+	assert.Error(t, err)
+
+	assert.NoError(t, accounts.Unlock(addr, "pass", 0))
+
+	res, err := dispatcher.endpoints.Eth.Sign(addr, argBytes("hello"))
+	assert.NoError(t, err)
+
+	// the same signature as personal_sign
+	signer, err := dispatcher.endpoints.Personal.EcRecover(argBytes("hello"), res.(argBytes))
+	assert.NoError(t, err)
+	assert.Equal(t, addr, signer)
+}
+
+func TestEthSignTypedData(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), &mockPersonalStore{})
+	accounts := newMockAccounts()
+	dispatcher.enablePersonal(accounts)
+
+	// the account of the example of EIP-712
+	key, _ := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	addr := crypto.PubKeyToAddress(&key.PublicKey)
+	accounts.keys[addr] = key
+	accounts.unlocked[addr] = 0
+
+	typedData := `{
+		"types": {
+			"EIP712Domain": [
+				{"name": "name", "type": "string"},
+				{"name": "version", "type": "string"},
+				{"name": "chainId", "type": "uint256"},
+				{"name": "verifyingContract", "type": "address"}
+			],
+			"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+			"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+		},
+		"primaryType": "Mail",
+		"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+		"message": {
+			"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!"
+		}
+	}`
+	expected := "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+
+	// the typed data is sent as an object, or as a string by the wallets
+	quoted, _ := json.Marshal(typedData)
+	for _, param := range []string{typedData, string(quoted)} {
+		req := `{"method": "eth_signTypedData_v4", "params": ["` + addr.String() + `", ` + param + `]}`
+		resp, err := dispatcher.Handle([]byte(req), requestContext{})
+		assert.NoError(t, err)
+
+		var sig string
+		assert.NoError(t, expectJSONResult(resp, &sig))
+		assert.Equal(t, expected, sig)
+	}
+}
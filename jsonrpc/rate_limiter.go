@@ -0,0 +1,315 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// APIKeyHeader is the header of the API key that identifies the clients of the rate limiter,
+	// the clients without a configured key are identified by their IP
+	APIKeyHeader = "X-API-Key"
+
+	// rateLimitIdleTimeout is the time after which the quota of an idle client is released
+	rateLimitIdleTimeout = 5 * time.Minute
+)
+
+// DefaultMethodWeights are the costs of the CPU heavy methods, the other methods cost 1
+var DefaultMethodWeights = map[string]uint64{
+	"eth_call":        5,
+	"eth_estimateGas": 5,
+	"eth_getLogs":     10,
+	"debug_*":         20,
+	"trace_*":         20,
+}
+
+// RateLimitConfig defines the quotas of every client of the JSON-RPC server
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate of the quota, in weighted requests per second. Zero means unlimited
+	RequestsPerSecond float64
+
+	// Burst is the number of weighted requests that can be sent at once. Default: RequestsPerSecond
+	Burst uint64
+
+	// MaxConcurrent is the number of requests of a client that are executed at the same time. Zero means unlimited
+	MaxConcurrent int64
+
+	// MethodWeights are the costs of the methods, "namespace_*" sets the cost of a namespace.
+	// Default: DefaultMethodWeights
+	MethodWeights map[string]uint64
+
+	// APIKeys are the keys of the clients identified by their API key. The clients sending
+	// another key are identified by their IP, so they can't get a new quota with a new key
+	APIKeys []string
+}
+
+// weight returns the cost of the method
+func (c *RateLimitConfig) weight(method string) float64 {
+	weights := c.MethodWeights
+	if weights == nil {
+		weights = DefaultMethodWeights
+	}
+
+	if w, ok := weights[method]; ok {
+		return float64(w)
+	}
+	if i := strings.Index(method, "_"); i > 0 {
+		if w, ok := weights[method[:i]+"_*"]; ok {
+			return float64(w)
+		}
+	}
+
+	return 1
+}
+
+// rateLimitClient is the quota of a client, a token bucket
+type rateLimitClient struct {
+	tokens   float64
+	last     time.Time
+	inflight int64
+}
+
+// rateLimiter enforces the quotas of the clients, identified by their API key or their IP
+type rateLimiter struct {
+	logger  hclog.Logger
+	metrics *Metrics
+
+	// config, burst and apiKeys are replaced when the config is reloaded, they are guarded by the lock
+	config  *RateLimitConfig
+	burst   float64
+	apiKeys map[string]struct{}
+
+	clients map[string]*rateLimitClient
+	lock    sync.Mutex
+
+	// now is replaced in the tests
+	now func() time.Time
+}
+
+func newRateLimiter(logger hclog.Logger, config *RateLimitConfig, metrics *Metrics) *rateLimiter {
+	r := &rateLimiter{
+		logger:  logger.Named("rate-limiter"),
+		metrics: metrics,
+		clients: map[string]*rateLimitClient{},
+		now:     time.Now,
+	}
+	r.setConfig(config)
+
+	return r
+}
+
+// setConfig replaces the quotas. The buckets of the clients are kept, capped to the new burst
+func (r *rateLimiter) setConfig(config *RateLimitConfig) {
+	burst := float64(config.Burst)
+	if burst == 0 {
+		burst = math.Max(config.RequestsPerSecond, 1)
+	}
+
+	apiKeys := map[string]struct{}{}
+	for _, key := range config.APIKeys {
+		apiKeys[key] = struct{}{}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.config = config
+	r.burst = burst
+	r.apiKeys = apiKeys
+	for _, client := range r.clients {
+		client.tokens = math.Min(client.tokens, burst)
+	}
+}
+
+// clientKey identifies the client of the request, by its API key if it is a configured one
+func (r *rateLimiter) clientKey(req *http.Request) string {
+	if key := req.Header.Get(APIKeyHeader); key != "" {
+		r.lock.Lock()
+		_, ok := r.apiKeys[key]
+		r.lock.Unlock()
+
+		if ok {
+			return "key:" + key
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// requestWeight returns the cost of the JSON-RPC request, or batch of requests
+func (r *rateLimiter) requestWeight(body []byte) float64 {
+	body = bytes.TrimLeft(body, " \t\r\n")
+
+	var requests []Request
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &requests); err != nil {
+			return 1
+		}
+	} else {
+		var req Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			return 1
+		}
+		requests = append(requests, req)
+	}
+
+	r.lock.Lock()
+	config := r.config
+	r.lock.Unlock()
+
+	weight := float64(0)
+	for _, req := range requests {
+		weight += config.weight(req.Method)
+	}
+
+	return math.Max(weight, 1)
+}
+
+// acquire takes the weight of the request from the quota of the client, and marks it as being executed
+// if it is tracked. The returned function is called when the request is done
+func (r *rateLimiter) acquire(key string, weight float64, track bool) (func(), error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+
+	client, ok := r.clients[key]
+	if !ok {
+		client = &rateLimitClient{tokens: r.burst, last: now}
+		r.clients[key] = client
+		r.metrics.RateLimitedClients.Set(float64(len(r.clients)))
+	}
+
+	if track && r.config.MaxConcurrent != 0 && client.inflight >= r.config.MaxConcurrent {
+		r.metrics.RateLimited.With("reason", "concurrency").Add(1)
+		return nil, fmt.Errorf("too many concurrent requests, the limit is %d", r.config.MaxConcurrent)
+	}
+
+	if r.config.RequestsPerSecond != 0 {
+		client.tokens = math.Min(r.burst, client.tokens+now.Sub(client.last).Seconds()*r.config.RequestsPerSecond)
+		client.last = now
+
+		// the batches heavier than the burst are allowed with a full bucket
+		cost := math.Min(weight, r.burst)
+		if client.tokens < cost {
+			r.metrics.RateLimited.With("reason", "rate").Add(1)
+			return nil, fmt.Errorf("rate limit of %g requests per second exceeded", r.config.RequestsPerSecond)
+		}
+		client.tokens -= cost
+	}
+
+	if !track {
+		return func() {}, nil
+	}
+
+	client.inflight++
+
+	return func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		client.inflight--
+		client.last = r.now()
+	}, nil
+}
+
+// evictIdle releases the quotas of the clients that have been idle for a while,
+// their buckets are full again anyway
+func (r *rateLimiter) evictIdle() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	for key, client := range r.clients {
+		if client.inflight == 0 && now.Sub(client.last) > rateLimitIdleTimeout {
+			delete(r.clients, key)
+		}
+	}
+
+	r.metrics.RateLimitedClients.Set(float64(len(r.clients)))
+}
+
+func (r *rateLimiter) run(closeCh <-chan struct{}) {
+	ticker := time.NewTicker(rateLimitIdleTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.evictIdle()
+		case <-closeCh:
+			return
+		}
+	}
+}
+
+// middleware rejects the HTTP requests of the clients that exceed their quota with a 429 status.
+// The WS connections are charged when they are established, and then for every message
+func (r *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodOptions {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		key := r.clientKey(req)
+
+		if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			if _, err := r.acquire(key, 1, false); err != nil {
+				r.reject(w, key, err)
+				return
+			}
+
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		weight := float64(1)
+		if req.Method == http.MethodPost && req.Body != nil {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			weight = r.requestWeight(body)
+		}
+
+		release, err := r.acquire(key, weight, true)
+		if err != nil {
+			r.reject(w, key, err)
+			return
+		}
+		defer release()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// reject writes the JSON-RPC error of a rejected request, with the 429 status
+func (r *rateLimiter) reject(w http.ResponseWriter, key string, err error) {
+	r.logger.Debug("rejected request", "client", key, "err", err)
+
+	resp, _ := NewRpcResponse(nil, "2.0", nil, NewLimitExceededError(err.Error(), nil)).Bytes()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "1")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write(resp)
+}
@@ -0,0 +1,193 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Weight(t *testing.T) {
+	r := newRateLimiter(hclog.NewNullLogger(), &RateLimitConfig{RequestsPerSecond: 1}, NilMetrics())
+
+	cases := []struct {
+		body   string
+		weight float64
+	}{
+		{`{"method": "eth_blockNumber"}`, 1},
+		{`{"method": "eth_getLogs"}`, 10},
+		{`{"method": "debug_traceTransaction"}`, 20},
+		{`[{"method": "eth_call"}, {"method": "eth_chainId"}]`, 6},
+		{`[]`, 1},
+		{`not json`, 1},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.weight, r.requestWeight([]byte(c.body)), c.body)
+	}
+
+	// the configured weights replace the default ones
+	r.config.MethodWeights = map[string]uint64{"eth_*": 3}
+	assert.Equal(t, float64(3), r.requestWeight([]byte(`{"method": "eth_getLogs"}`)))
+	assert.Equal(t, float64(1), r.requestWeight([]byte(`{"method": "debug_traceTransaction"}`)))
+}
+
+func TestRateLimiter_Rate(t *testing.T) {
+	r := newRateLimiter(
+		hclog.NewNullLogger(),
+		&RateLimitConfig{RequestsPerSecond: 2, Burst: 10, APIKeys: []string{"key"}},
+		NilMetrics(),
+	)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	handler := r.middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(body string, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if apiKey != "" {
+			req.Header.Set(APIKeyHeader, apiKey)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// the burst is used by a batch heavier than it
+	assert.Equal(t, http.StatusOK, send(`[{"method": "eth_getLogs"}, {"method": "eth_getLogs"}]`, "").Code)
+
+	rec := send(`{"method": "eth_blockNumber"}`, "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	var resp ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, -32005, resp.Error.Code)
+
+	// the API keys have their own quota
+	assert.Equal(t, http.StatusOK, send(`{"method": "eth_blockNumber"}`, "key").Code)
+
+	// the quota is refilled over time
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, send(`{"method": "eth_blockNumber"}`, "").Code)
+	assert.Equal(t, http.StatusOK, send(`{"method": "eth_blockNumber"}`, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(`{"method": "eth_blockNumber"}`, "").Code)
+
+	// the idle clients are released
+	assert.Len(t, r.clients, 2)
+
+	now = now.Add(rateLimitIdleTimeout + time.Second)
+	r.evictIdle()
+	assert.Len(t, r.clients, 0)
+}
+
+func TestRateLimiter_UnknownAPIKeys(t *testing.T) {
+	r := newRateLimiter(
+		hclog.NewNullLogger(),
+		&RateLimitConfig{RequestsPerSecond: 1, Burst: 2, APIKeys: []string{"key"}},
+		NilMetrics(),
+	)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	handler := r.middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"method": "eth_blockNumber"}`))
+		req.Header.Set(APIKeyHeader, apiKey)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	// the rotated keys share the quota of the IP
+	assert.Equal(t, http.StatusOK, send("random-1"))
+	assert.Equal(t, http.StatusOK, send("random-2"))
+	assert.Equal(t, http.StatusTooManyRequests, send("random-3"))
+
+	// the configured key has its own quota
+	assert.Equal(t, http.StatusOK, send("key"))
+
+	assert.Len(t, r.clients, 2)
+	assert.Contains(t, r.clients, "ip:192.0.2.1")
+	assert.Contains(t, r.clients, "key:key")
+}
+
+func TestRateLimiter_Concurrency(t *testing.T) {
+	r := newRateLimiter(hclog.NewNullLogger(), &RateLimitConfig{MaxConcurrent: 1}, NilMetrics())
+
+	blockCh := make(chan struct{})
+	handler := r.middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/block" {
+			<-blockCh
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`)))
+
+		return rec.Code
+	}
+
+	doneCh := make(chan int)
+	go func() {
+		doneCh <- send("/block")
+	}()
+
+	// wait for the first request to be executed
+	assert.Eventually(t, func() bool {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		client, ok := r.clients["ip:192.0.2.1"]
+		return ok && client.inflight == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, http.StatusTooManyRequests, send("/"))
+
+	close(blockCh)
+	assert.Equal(t, http.StatusOK, <-doneCh)
+	assert.Equal(t, http.StatusOK, send("/"))
+}
+
+func TestRateLimiter_SetConfig(t *testing.T) {
+	r := newRateLimiter(hclog.NewNullLogger(), &RateLimitConfig{RequestsPerSecond: 1, Burst: 10}, NilMetrics())
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	done, err := r.acquire("a", 1, false)
+	assert.NoError(t, err)
+	done()
+
+	// the bucket of the client is capped to the new burst
+	r.setConfig(&RateLimitConfig{RequestsPerSecond: 1, Burst: 2})
+	for i := 0; i < 2; i++ {
+		_, err = r.acquire("a", 1, false)
+		assert.NoError(t, err)
+	}
+	_, err = r.acquire("a", 1, false)
+	assert.Error(t, err)
+
+	// no quota
+	r.setConfig(&RateLimitConfig{})
+	_, err = r.acquire("a", 1, false)
+	assert.NoError(t, err)
+}
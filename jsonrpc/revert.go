@@ -0,0 +1,60 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+)
+
+// revertSelector is the selector of Error(string), the ABI encoding of the revert reasons of solidity
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// decodeRevertReason returns the reason string of the data returned by a reverted execution,
+// or false if the data is not an encoded reason
+func decodeRevertReason(data []byte) (string, bool) {
+	if len(data) < 4+64 || !bytes.Equal(data[:4], revertSelector) {
+		return "", false
+	}
+	data = data[4:]
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", false
+	}
+	start := offset.Uint64()
+
+	length := new(big.Int).SetBytes(data[start : start+32])
+	if !length.IsUint64() || start+32+length.Uint64() > uint64(len(data)) {
+		return "", false
+	}
+
+	return string(data[start+32 : start+32+length.Uint64()]), true
+}
+
+// revertError is the error of a reverted call, it carries the data returned by the execution
+type revertError struct {
+	err  string
+	data []byte
+}
+
+func newRevertError(data []byte) *revertError {
+	msg := "execution reverted"
+	if reason, ok := decodeRevertReason(data); ok {
+		msg += ": " + reason
+	}
+
+	return &revertError{msg, data}
+}
+
+func (e *revertError) Error() string {
+	return e.err
+}
+
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+func (e *revertError) ErrorData() interface{} {
+	return hex.EncodeToHex(e.data)
+}
@@ -0,0 +1,158 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// encodeRevertReason returns the ABI encoding of Error(reason)
+func encodeRevertReason(reason string) []byte {
+	word := func(n int) []byte {
+		return types.BytesToHash(big.NewInt(int64(n)).Bytes()).Bytes()
+	}
+
+	data := append([]byte{}, revertSelector...)
+	data = append(data, word(32)...)
+	data = append(data, word(len(reason))...)
+	data = append(data, []byte(reason)...)
+	if pad := len(reason) % 32; pad != 0 {
+		data = append(data, make([]byte, 32-pad)...)
+	}
+
+	return data
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	reason, ok := decodeRevertReason(encodeRevertReason("not enough balance"))
+	assert.True(t, ok)
+	assert.Equal(t, "not enough balance", reason)
+
+	reason, ok = decodeRevertReason(encodeRevertReason(""))
+	assert.True(t, ok)
+	assert.Equal(t, "", reason)
+
+	// the data that is not an encoded reason
+	for _, data := range [][]byte{
+		nil,
+		{0x1, 0x2},
+		append([]byte{0x1, 0x2, 0x3, 0x4}, encodeRevertReason("foo")[4:]...),
+		encodeRevertReason("foo")[:4+64],
+	} {
+		_, ok := decodeRevertReason(data)
+		assert.False(t, ok)
+	}
+}
+
+type mockRevertStore struct {
+	nullBlockchainInterface
+
+	block    *types.Block
+	receipts []*types.Receipt
+	result   *runtime.ExecutionResult
+}
+
+func (m *mockRevertStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(blockNumber)
+}
+
+func (m *mockRevertStore) Header() *types.Header {
+	return m.block.Header
+}
+
+func (m *mockRevertStore) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	return m.block.Hash(), true
+}
+
+func (m *mockRevertStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	return m.block, true
+}
+
+func (m *mockRevertStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return m.receipts, nil
+}
+
+func (m *mockRevertStore) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (*runtime.ExecutionResult, error) {
+	return m.result, nil
+}
+
+func TestEth_RevertReason(t *testing.T) {
+	revertData := encodeRevertReason("not enough balance")
+
+	txn := &types.Transaction{
+		Hash:     types.StringToHash("0x1"),
+		To:       &addr1,
+		GasPrice: big.NewInt(10),
+		Value:    big.NewInt(0),
+	}
+	status := types.ReceiptFailed
+
+	store := &mockRevertStore{
+		block: &types.Block{
+			Header:       &types.Header{Number: 1, Hash: types.StringToHash("0x10"), GasLimit: 100000},
+			Transactions: []*types.Transaction{txn},
+		},
+		receipts: []*types.Receipt{
+			{Status: &status, GasUsed: 100, RevertData: revertData},
+		},
+		result: &runtime.ExecutionResult{
+			ReturnValue: revertData,
+			Err:         runtime.ErrExecutionReverted,
+		},
+	}
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+
+	handle := func(body string) *SuccessResponse {
+		res, err := dispatcher.Handle([]byte(body), requestContext{})
+		assert.NoError(t, err)
+
+		var resp SuccessResponse
+		assert.NoError(t, json.Unmarshal(res, &resp))
+		return &resp
+	}
+
+	// the receipt has the effective gas price and the revert reason
+	resp := handle(fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_getTransactionReceipt", "params": ["%s"]}`, txn.Hash))
+	assert.Nil(t, resp.Error)
+
+	var receipt map[string]interface{}
+	assert.NoError(t, json.Unmarshal(resp.Result, &receipt))
+	assert.Equal(t, "0xa", receipt["effectiveGasPrice"])
+	assert.Equal(t, "not enough balance", receipt["revertReason"])
+
+	// the reverted call returns the reason and the data
+	resp = handle(fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_call", "params": [{"to": "%s", "nonce": "0x1"}, "latest"]}`, addr1))
+	assert.NotNil(t, resp.Error)
+	assert.Equal(t, 3, resp.Error.Code)
+	assert.Equal(t, "execution reverted: not enough balance", resp.Error.Message)
+	assert.Equal(t, "0x"+fmt.Sprintf("%x", revertData), resp.Error.Data)
+
+	// and so does the estimation of a reverted transaction
+	resp = handle(fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_estimateGas", "params": [{"to": "%s"}, "latest"]}`, addr1))
+	assert.NotNil(t, resp.Error)
+	assert.Equal(t, 3, resp.Error.Code)
+	assert.Equal(t, "execution reverted: not enough balance", resp.Error.Message)
+
+	// the receipts of the successful transactions have no revert reason
+	status = types.ReceiptSuccess
+	store.receipts[0].RevertData = nil
+
+	resp = handle(fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_getTransactionReceipt", "params": ["%s"]}`, txn.Hash))
+	receipt = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(resp.Result, &receipt))
+	assert.NotContains(t, receipt, "revertReason")
+}
@@ -0,0 +1,375 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// traceFilterMaxBlocks is the largest block range of a trace_filter query,
+// since every block of the range is re-executed
+const traceFilterMaxBlocks = 1000
+
+// Trace is the trace jsonrpc endpoint. It returns the call traces of the transactions
+// in the OpenEthereum (parity) format, built by re-executing their blocks
+type Trace struct {
+	d *Dispatcher
+}
+
+type traceAction struct {
+	CallType      string         `json:"callType,omitempty"`
+	From          *types.Address `json:"from,omitempty"`
+	To            *types.Address `json:"to,omitempty"`
+	Gas           *argUint64     `json:"gas,omitempty"`
+	Input         *argBytes      `json:"input,omitempty"`
+	Init          *argBytes      `json:"init,omitempty"`
+	Value         *argBig        `json:"value,omitempty"`
+	Address       *types.Address `json:"address,omitempty"`
+	RefundAddress *types.Address `json:"refundAddress,omitempty"`
+	Balance       *argBig        `json:"balance,omitempty"`
+}
+
+type traceResult struct {
+	GasUsed argUint64      `json:"gasUsed"`
+	Output  *argBytes      `json:"output,omitempty"`
+	Address *types.Address `json:"address,omitempty"`
+	Code    *argBytes      `json:"code,omitempty"`
+}
+
+type parityTrace struct {
+	Action              *traceAction `json:"action"`
+	Result              *traceResult `json:"result"`
+	Error               string       `json:"error,omitempty"`
+	Subtraces           int          `json:"subtraces"`
+	TraceAddress        []int        `json:"traceAddress"`
+	Type                string       `json:"type"`
+	BlockHash           *types.Hash  `json:"blockHash,omitempty"`
+	BlockNumber         *uint64      `json:"blockNumber,omitempty"`
+	TransactionHash     *types.Hash  `json:"transactionHash,omitempty"`
+	TransactionPosition *uint64      `json:"transactionPosition,omitempty"`
+}
+
+type replayResponse struct {
+	Output    argBytes       `json:"output"`
+	StateDiff interface{}    `json:"stateDiff"`
+	Trace     []*parityTrace `json:"trace"`
+	VMTrace   interface{}    `json:"vmTrace"`
+}
+
+// traceFilter is the query of trace_filter, the traces match any of the
+// from addresses and any of the to addresses, if set
+type traceFilter struct {
+	FromBlock   *BlockNumber    `json:"fromBlock"`
+	ToBlock     *BlockNumber    `json:"toBlock"`
+	FromAddress []types.Address `json:"fromAddress"`
+	ToAddress   []types.Address `json:"toAddress"`
+	After       *uint64         `json:"after"`
+	Count       *uint64         `json:"count"`
+}
+
+func (f *traceFilter) match(frame *state.CallTrace) bool {
+	return matchAddress(f.FromAddress, frame.From) && matchAddress(f.ToAddress, frame.To)
+}
+
+func matchAddress(list []types.Address, addr types.Address) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Block returns the traces of the transactions of a block
+func (t *Trace) Block(number BlockNumber) (interface{}, error) {
+	num, err := GetNumericBlockNumber(number, t.d.endpoints.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := t.d.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, nil
+	}
+
+	return t.blockTraces(block, nil)
+}
+
+// Transaction returns the traces of a transaction
+func (t *Trace) Transaction(hash types.Hash) (interface{}, error) {
+	block, index, ok := t.findTransaction(hash)
+	if !ok {
+		return nil, nil
+	}
+
+	frames, err := t.d.store.GetBlockTraces(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.transactionTraces(block, index, frames[index], true), nil
+}
+
+// ReplayTransaction re-executes a transaction and returns its output and traces.
+// Only the "trace" type is supported
+func (t *Trace) ReplayTransaction(hash types.Hash, traceTypes []string) (interface{}, error) {
+	for _, typ := range traceTypes {
+		if typ != "trace" {
+			return nil, fmt.Errorf("trace type %q is not supported", typ)
+		}
+	}
+
+	block, index, ok := t.findTransaction(hash)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", hash)
+	}
+
+	frames, err := t.d.store.GetBlockTraces(block)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &replayResponse{
+		Output: argBytes{},
+		Trace:  []*parityTrace{},
+	}
+	if frame := frames[index]; frame != nil {
+		resp.Output = append(resp.Output, frame.Output...)
+		resp.Trace = t.transactionTraces(block, index, frame, false)
+	}
+
+	return resp, nil
+}
+
+// Filter returns the traces of a block range that match the filter
+func (t *Trace) Filter(filter *traceFilter) (interface{}, error) {
+	if filter == nil {
+		filter = &traceFilter{}
+	}
+
+	from, to := LatestBlockNumber, LatestBlockNumber
+	if filter.FromBlock != nil {
+		from = *filter.FromBlock
+	}
+	if filter.ToBlock != nil {
+		to = *filter.ToBlock
+	}
+
+	fromNum, err := GetNumericBlockNumber(from, t.d.endpoints.Eth)
+	if err != nil {
+		return nil, err
+	}
+	toNum, err := GetNumericBlockNumber(to, t.d.endpoints.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromNum > toNum {
+		return nil, fmt.Errorf("incorrect range")
+	}
+	if toNum-fromNum >= traceFilterMaxBlocks {
+		return nil, fmt.Errorf("the block range is limited to %d blocks", traceFilterMaxBlocks)
+	}
+
+	var after uint64
+	if filter.After != nil {
+		after = *filter.After
+	}
+
+	traces := []*parityTrace{}
+	for num := fromNum; num <= toNum; num++ {
+		block, ok := t.d.store.GetBlockByNumber(num, true)
+		if !ok {
+			break
+		}
+
+		matches, err := t.blockTraces(block, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, trace := range matches {
+			if after > 0 {
+				after--
+				continue
+			}
+
+			traces = append(traces, trace)
+			if filter.Count != nil && uint64(len(traces)) == *filter.Count {
+				return traces, nil
+			}
+		}
+	}
+
+	return traces, nil
+}
+
+// findTransaction returns the block of a transaction, and its position in the block
+func (t *Trace) findTransaction(hash types.Hash) (*types.Block, int, bool) {
+	blockHash, ok := t.d.store.ReadTxLookup(hash)
+	if !ok {
+		return nil, 0, false
+	}
+
+	block, ok := t.d.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, 0, false
+	}
+
+	for index, txn := range block.Transactions {
+		if txn.Hash == hash {
+			return block, index, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// blockTraces returns the traces of the transactions of the block that match the filter, if any
+func (t *Trace) blockTraces(block *types.Block, filter *traceFilter) ([]*parityTrace, error) {
+	traces := []*parityTrace{}
+	if len(block.Transactions) == 0 {
+		return traces, nil
+	}
+
+	frames, err := t.d.store.GetBlockTraces(block)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, frame := range frames {
+		txTraces := t.transactionTraces(block, index, frame, true)
+
+		if filter == nil {
+			traces = append(traces, txTraces...)
+			continue
+		}
+
+		// the flattened traces and the frames are both in depth first order
+		i := 0
+		walkFrames(frame, func(frame *state.CallTrace) {
+			if filter.match(frame) {
+				traces = append(traces, txTraces[i])
+			}
+			i++
+		})
+	}
+
+	return traces, nil
+}
+
+// transactionTraces flattens the frames of a transaction in depth first order
+func (t *Trace) transactionTraces(block *types.Block, index int, frame *state.CallTrace, withBlock bool) []*parityTrace {
+	traces := []*parityTrace{}
+	if frame == nil {
+		// the transaction did not run any code, i.e. an account restoration
+		return traces
+	}
+
+	traces = flattenFrame(frame, []int{}, traces)
+
+	if withBlock {
+		blockHash := block.Hash()
+		blockNumber := block.Number()
+		txHash := block.Transactions[index].Hash
+		position := uint64(index)
+
+		for _, trace := range traces {
+			trace.BlockHash = &blockHash
+			trace.BlockNumber = &blockNumber
+			trace.TransactionHash = &txHash
+			trace.TransactionPosition = &position
+		}
+	}
+
+	return traces
+}
+
+func walkFrames(frame *state.CallTrace, fn func(frame *state.CallTrace)) {
+	if frame == nil {
+		return
+	}
+
+	fn(frame)
+	for _, call := range frame.Calls {
+		walkFrames(call, fn)
+	}
+}
+
+func flattenFrame(frame *state.CallTrace, address []int, traces []*parityTrace) []*parityTrace {
+	trace := &parityTrace{
+		Action:       &traceAction{},
+		Subtraces:    len(frame.Calls),
+		TraceAddress: address,
+		Type:         string(frame.Type),
+	}
+
+	from, to := frame.From, frame.To
+	gas, gasUsed := argUint64(frame.Gas), argUint64(frame.GasUsed)
+	value := argBig(*frame.Value)
+
+	switch frame.Type {
+	case state.CallTraceCall:
+		trace.Action.CallType = frame.CallType
+		trace.Action.From = &from
+		trace.Action.To = &to
+		trace.Action.Gas = &gas
+		trace.Action.Input = argBytesPtr(frame.Input)
+		trace.Action.Value = &value
+		trace.Result = &traceResult{
+			GasUsed: gasUsed,
+			Output:  argBytesPtr(frame.Output),
+		}
+
+	case state.CallTraceCreate:
+		trace.Action.From = &from
+		trace.Action.Gas = &gas
+		trace.Action.Init = argBytesPtr(frame.Input)
+		trace.Action.Value = &value
+		trace.Result = &traceResult{
+			GasUsed: gasUsed,
+			Address: &to,
+			Code:    argBytesPtr(frame.Output),
+		}
+
+	case state.CallTraceSuicide:
+		trace.Action.Address = &from
+		trace.Action.RefundAddress = &to
+		trace.Action.Balance = &value
+	}
+
+	if frame.Err != nil {
+		trace.Result = nil
+		trace.Error = traceError(frame.Err)
+	}
+
+	traces = append(traces, trace)
+
+	for i, call := range frame.Calls {
+		callAddress := make([]int, len(address)+1)
+		copy(callAddress, address)
+		callAddress[len(address)] = i
+
+		traces = flattenFrame(call, callAddress, traces)
+	}
+
+	return traces
+}
+
+// traceError returns the parity name of the common execution errors
+func traceError(err error) string {
+	switch err {
+	case runtime.ErrExecutionReverted:
+		return "Reverted"
+	case runtime.ErrOutOfGas, runtime.ErrCodeStoreOutOfGas:
+		return "Out of gas"
+	case runtime.ErrDepth:
+		return "Out of stack"
+	}
+	return err.Error()
+}
@@ -0,0 +1,240 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTraceStore struct {
+	nullBlockchainInterface
+
+	block  *types.Block
+	traces []*state.CallTrace
+}
+
+func (m *mockTraceStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	panic("implement me")
+}
+
+func (m *mockTraceStore) Header() *types.Header {
+	return m.block.Header
+}
+
+func (m *mockTraceStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	if num != m.block.Number() {
+		return nil, false
+	}
+	return m.block, true
+}
+
+func (m *mockTraceStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	if hash != m.block.Hash() {
+		return nil, false
+	}
+	return m.block, true
+}
+
+func (m *mockTraceStore) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	for _, txn := range m.block.Transactions {
+		if txn.Hash == hash {
+			return m.block.Hash(), true
+		}
+	}
+	return types.Hash{}, false
+}
+
+func (m *mockTraceStore) GetBlockTraces(block *types.Block) ([]*state.CallTrace, error) {
+	return m.traces, nil
+}
+
+func newMockTraceStore() *mockTraceStore {
+	addr2 := types.StringToAddress("2")
+
+	return &mockTraceStore{
+		block: &types.Block{
+			Header: &types.Header{Number: 1, Hash: types.StringToHash("0xb")},
+			Transactions: []*types.Transaction{
+				{Hash: types.StringToHash("0x10"), To: &addr2},
+				{Hash: types.StringToHash("0x11")},
+			},
+		},
+		traces: []*state.CallTrace{
+			{
+				Type:     state.CallTraceCall,
+				CallType: "call",
+				From:     addr0,
+				To:       addr2,
+				Value:    big.NewInt(1),
+				Gas:      100,
+				GasUsed:  50,
+				Output:   []byte{0x1},
+				Calls: []*state.CallTrace{
+					{
+						Type:     state.CallTraceCall,
+						CallType: "delegatecall",
+						From:     addr2,
+						To:       addr1,
+						Value:    big.NewInt(0),
+						Gas:      40,
+						GasUsed:  40,
+						Err:      runtime.ErrExecutionReverted,
+					},
+					{
+						Type:  state.CallTraceSuicide,
+						From:  addr2,
+						To:    addr0,
+						Value: big.NewInt(5),
+					},
+				},
+			},
+			{
+				Type:    state.CallTraceCreate,
+				From:    addr1,
+				To:      addr2,
+				Value:   big.NewInt(0),
+				Gas:     200,
+				GasUsed: 150,
+				Input:   []byte{0x60},
+				Output:  []byte{0x61},
+			},
+		},
+	}
+}
+
+func TestTraceNamespaceDisabled(t *testing.T) {
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), newMockTraceStore())
+
+	resp, err := dispatcher.Handle([]byte(`{"method": "trace_block", "params": ["0x1"]}`), requestContext{})
+	assert.NoError(t, err)
+
+	var res interface{}
+	assert.Error(t, expectJSONResult(resp, &res))
+}
+
+func TestTrace_Block(t *testing.T) {
+	store := newMockTraceStore()
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableTrace()
+
+	res, err := dispatcher.endpoints.Trace.Block(BlockNumber(1))
+	assert.NoError(t, err)
+
+	traces := res.([]*parityTrace)
+	assert.Len(t, traces, 4)
+
+	// the frames are flattened in depth first order
+	assert.Equal(t, []int{}, traces[0].TraceAddress)
+	assert.Equal(t, 2, traces[0].Subtraces)
+	assert.Equal(t, []int{0}, traces[1].TraceAddress)
+	assert.Equal(t, []int{1}, traces[2].TraceAddress)
+	assert.Equal(t, []int{}, traces[3].TraceAddress)
+
+	assert.Equal(t, "Reverted", traces[1].Error)
+	assert.Nil(t, traces[1].Result)
+	assert.Equal(t, uint64(1), *traces[3].TransactionPosition)
+	assert.Equal(t, types.StringToHash("0x11"), *traces[3].TransactionHash)
+
+	data, err := json.Marshal(traces[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"action": {
+			"callType": "call",
+			"from": "`+addr0.String()+`",
+			"to": "`+types.StringToAddress("2").String()+`",
+			"gas": "0x64",
+			"input": "0x",
+			"value": "0x1"
+		},
+		"result": {"gasUsed": "0x32", "output": "0x01"},
+		"subtraces": 2,
+		"traceAddress": [],
+		"type": "call",
+		"blockHash": "`+types.StringToHash("0xb").String()+`",
+		"blockNumber": 1,
+		"transactionHash": "`+types.StringToHash("0x10").String()+`",
+		"transactionPosition": 0
+	}`, string(data))
+
+	data, err = json.Marshal(traces[2].Action)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"address": "`+types.StringToAddress("2").String()+`",
+		"refundAddress": "`+addr0.String()+`",
+		"balance": "0x5"
+	}`, string(data))
+
+	data, err = json.Marshal(traces[3].Result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"gasUsed": "0x96",
+		"address": "`+types.StringToAddress("2").String()+`",
+		"code": "0x61"
+	}`, string(data))
+}
+
+func TestTrace_TransactionAndReplay(t *testing.T) {
+	store := newMockTraceStore()
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableTrace()
+
+	res, err := dispatcher.endpoints.Trace.Transaction(types.StringToHash("0x10"))
+	assert.NoError(t, err)
+	assert.Len(t, res.([]*parityTrace), 3)
+
+	res, err = dispatcher.endpoints.Trace.Transaction(types.StringToHash("0x99"))
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	res, err = dispatcher.endpoints.Trace.ReplayTransaction(types.StringToHash("0x10"), []string{"trace"})
+	assert.NoError(t, err)
+
+	replay := res.(*replayResponse)
+	assert.Equal(t, argBytes{0x1}, replay.Output)
+	assert.Len(t, replay.Trace, 3)
+	assert.Nil(t, replay.Trace[0].BlockHash)
+
+	_, err = dispatcher.endpoints.Trace.ReplayTransaction(types.StringToHash("0x10"), []string{"vmTrace"})
+	assert.Error(t, err)
+}
+
+func TestTrace_Filter(t *testing.T) {
+	store := newMockTraceStore()
+	dispatcher := newTestDispatcher(hclog.NewNullLogger(), store)
+	dispatcher.enableTrace()
+
+	filter := func(f *traceFilter) []*parityTrace {
+		res, err := dispatcher.endpoints.Trace.Filter(f)
+		assert.NoError(t, err)
+		return res.([]*parityTrace)
+	}
+
+	assert.Len(t, filter(nil), 4)
+
+	// the frames started by the account, including its self destruct
+	traces := filter(&traceFilter{FromAddress: []types.Address{types.StringToAddress("2")}})
+	assert.Len(t, traces, 2)
+	assert.Equal(t, "call", traces[0].Type)
+	assert.Equal(t, "suicide", traces[1].Type)
+
+	// the frames that reached the account, including its creation
+	traces = filter(&traceFilter{ToAddress: []types.Address{types.StringToAddress("2")}})
+	assert.Len(t, traces, 2)
+	assert.Equal(t, "create", traces[1].Type)
+
+	after, count := uint64(1), uint64(2)
+	traces = filter(&traceFilter{After: &after, Count: &count})
+	assert.Len(t, traces, 2)
+	assert.Equal(t, []int{0}, traces[0].TraceAddress)
+
+	from, to := BlockNumber(0), BlockNumber(traceFilterMaxBlocks)
+	_, err := dispatcher.endpoints.Trace.Filter(&traceFilter{FromBlock: &from, ToBlock: &to})
+	assert.Error(t, err)
+}
@@ -14,7 +14,7 @@ func TestContentEndpoint(t *testing.T) {
 	resp, err := s.Handle([]byte(`{
 		"method": "txpool_content",
 		"params": []
-	}`))
+	}`), requestContext{})
 	assert.NoError(t, err)
 
 	var res ContentResponse
@@ -30,7 +30,7 @@ func TestInspectEndpoint(t *testing.T) {
 	resp, err := s.Handle([]byte(`{
 		"method": "txpool_inspect",
 		"params": []
-	}`))
+	}`), requestContext{})
 	assert.NoError(t, err)
 
 	var res InspectResponse
@@ -46,7 +46,7 @@ func TestStatusEndpoint(t *testing.T) {
 	resp, err := s.Handle([]byte(`{
 		"method": "txpool_status",
 		"params": []
-	}`))
+	}`), requestContext{})
 	assert.NoError(t, err)
 
 	var res StatusResponse
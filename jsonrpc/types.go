@@ -4,8 +4,12 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/protocol"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/txpool"
 	"github.com/0xPolygon/polygon-sdk/types"
 )
 
@@ -29,6 +33,15 @@ type transaction struct {
 	BlockHash   types.Hash     `json:"blockHash"`
 	BlockNumber argUint64      `json:"blockNumber"`
 	TxIndex     argUint64      `json:"transactionIndex"`
+
+	// the fields of the dynamic fee transactions (EIP-1559)
+	Type                 *argUint64 `json:"type,omitempty"`
+	MaxFeePerGas         *argBig    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *argBig    `json:"maxPriorityFeePerGas,omitempty"`
+	ChainID              *argBig    `json:"chainId,omitempty"`
+
+	// the access list of the typed transactions (EIP-2930)
+	AccessList *types.AccessList `json:"accessList,omitempty"`
 }
 
 func (t transaction) getHash() types.Hash { return t.Hash }
@@ -43,7 +56,7 @@ func (h transactionHash) MarshalText() ([]byte, error) {
 }
 
 func toTransaction(t *types.Transaction, b *types.Block, txIndex int) *transaction {
-	return &transaction{
+	res := &transaction{
 		Nonce:       argUint64(t.Nonce),
 		GasPrice:    argBig(*t.GasPrice),
 		Gas:         argUint64(t.Gas),
@@ -59,6 +72,28 @@ func toTransaction(t *types.Transaction, b *types.Block, txIndex int) *transacti
 		BlockNumber: argUint64(b.Number()),
 		TxIndex:     argUint64(txIndex),
 	}
+
+	if t.Type != types.LegacyTx {
+		accessList := t.AccessList.Copy()
+		if accessList == nil {
+			accessList = types.AccessList{}
+		}
+
+		res.Type = argUintPtr(uint64(t.Type))
+		res.AccessList = &accessList
+		if t.ChainID != nil {
+			res.ChainID = argBigPtr(t.ChainID)
+		}
+	}
+
+	if t.Type == types.DynamicFeeTx {
+		// the gas price of a dynamic fee transaction is the one it paid in its block
+		res.GasPrice = argBig(*t.EffectiveGasPrice(b.Header.BaseFee))
+		res.MaxFeePerGas = argBigPtr(t.GasPrice)
+		res.MaxPriorityFeePerGas = argBigPtr(t.GetGasTipCap())
+	}
+
+	return res
 }
 
 type block struct {
@@ -82,6 +117,7 @@ type block struct {
 	Hash            types.Hash          `json:"hash"`
 	Transactions    []transactionOrHash `json:"transactions"`
 	Uncles          []types.Hash        `json:"uncles"`
+	BaseFeePerGas   *argBig             `json:"baseFeePerGas,omitempty"`
 }
 
 func toBlock(b *types.Block, fullTx bool) *block {
@@ -108,6 +144,9 @@ func toBlock(b *types.Block, fullTx bool) *block {
 		Transactions:    []transactionOrHash{},
 		Uncles:          []types.Hash{},
 	}
+	if h.BaseFee != nil {
+		res.BaseFeePerGas = argBigPtr(h.BaseFee)
+	}
 	for idx, txn := range b.Transactions {
 		if fullTx {
 			res.Transactions = append(res.Transactions, toTransaction(txn, b, idx))
@@ -121,6 +160,38 @@ func toBlock(b *types.Block, fullTx bool) *block {
 	return res
 }
 
+type syncProgress struct {
+	StartingBlock     argUint64 `json:"startingBlock"`
+	CurrentBlock      argUint64 `json:"currentBlock"`
+	HighestBlock      argUint64 `json:"highestBlock"`
+	Stage             string    `json:"stage"`
+	HeadersDownloaded argUint64 `json:"headersDownloaded"`
+	BlocksDownloaded  argUint64 `json:"blocksDownloaded"`
+	BlocksExecuted    argUint64 `json:"blocksExecuted"`
+	StateNodes        argUint64 `json:"stateNodes"`
+	StateCodes        argUint64 `json:"stateCodes"`
+	StatePending      argUint64 `json:"statePending"`
+
+	// ETA is the estimated number of seconds left, 0 if unknown
+	ETA argUint64 `json:"eta"`
+}
+
+func toSyncProgress(p *protocol.SyncProgress) *syncProgress {
+	return &syncProgress{
+		StartingBlock:     argUint64(p.StartingBlock),
+		CurrentBlock:      argUint64(p.CurrentBlock),
+		HighestBlock:      argUint64(p.HighestBlock),
+		Stage:             string(p.Stage),
+		HeadersDownloaded: argUint64(p.HeadersDownloaded),
+		BlocksDownloaded:  argUint64(p.BlocksDownloaded),
+		BlocksExecuted:    argUint64(p.BlocksExecuted),
+		StateNodes:        argUint64(p.StateNodes),
+		StateCodes:        argUint64(p.StateCodes),
+		StatePending:      argUint64(p.StatePending),
+		ETA:               argUint64(p.ETA / time.Second),
+	}
+}
+
 type receipt struct {
 	Root              types.Hash     `json:"root"`
 	CumulativeGasUsed argUint64      `json:"cumulativeGasUsed"`
@@ -135,6 +206,8 @@ type receipt struct {
 	ContractAddress   types.Address  `json:"contractAddress"`
 	FromAddr          types.Address  `json:"from"`
 	ToAddr            *types.Address `json:"to"`
+	EffectiveGasPrice argBig         `json:"effectiveGasPrice"`
+	RevertReason      string         `json:"revertReason,omitempty"`
 }
 
 type Log struct {
@@ -249,4 +322,73 @@ type txnArgs struct {
 	Input    *argBytes
 	Data     *argBytes
 	Nonce    *argUint64
+
+	// the fees of a dynamic fee transaction (EIP-1559), instead of the gas price
+	MaxFeePerGas         *argBytes
+	MaxPriorityFeePerGas *argBytes
+
+	// the access list of a typed transaction (EIP-2930)
+	AccessList *types.AccessList
+}
+
+// inclusionBounds are the optional bounds of eth_sendRawTransaction, after which the transaction is dropped
+type inclusionBounds struct {
+	MaxBlockNumber *argUint64 `json:"maxBlockNumber"`
+	MaxTimestamp   *argUint64 `json:"maxTimestamp"`
+}
+
+func (b *inclusionBounds) toExpiry() *txpool.Expiry {
+	expiry := &txpool.Expiry{}
+	if b.MaxBlockNumber != nil {
+		expiry.MaxBlockNumber = uint64(*b.MaxBlockNumber)
+	}
+	if b.MaxTimestamp != nil {
+		expiry.MaxTimestamp = uint64(*b.MaxTimestamp)
+	}
+
+	return expiry
+}
+
+// overrideAccount is the state of an account replaced for the duration of an eth_call
+type overrideAccount struct {
+	Nonce     *argUint64                 `json:"nonce"`
+	Code      *argBytes                  `json:"code"`
+	Balance   *argBig                    `json:"balance"`
+	State     *map[types.Hash]types.Hash `json:"state"`
+	StateDiff *map[types.Hash]types.Hash `json:"stateDiff"`
+}
+
+// stateOverride is the optional set of accounts replaced for the duration of an eth_call
+type stateOverride map[types.Address]overrideAccount
+
+func (s *stateOverride) toState() state.StateOverride {
+	if s == nil {
+		return nil
+	}
+
+	override := state.StateOverride{}
+	for addr, acc := range *s {
+		account := state.OverrideAccount{}
+
+		if acc.Nonce != nil {
+			nonce := uint64(*acc.Nonce)
+			account.Nonce = &nonce
+		}
+		if acc.Code != nil {
+			account.Code = append([]byte{}, *acc.Code...)
+		}
+		if acc.Balance != nil {
+			account.Balance = new(big.Int).Set((*big.Int)(acc.Balance))
+		}
+		if acc.State != nil {
+			account.State = *acc.State
+		}
+		if acc.StateDiff != nil {
+			account.StateDiff = *acc.StateDiff
+		}
+
+		override[addr] = account
+	}
+
+	return override
 }
@@ -92,3 +92,82 @@ func TestDecode_TxnArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestToBlock_DynamicFee(t *testing.T) {
+	txn := &types.Transaction{
+		Type:      types.DynamicFeeTx,
+		ChainID:   big.NewInt(100),
+		GasPrice:  big.NewInt(50),
+		GasTipCap: big.NewInt(5),
+		Value:     big.NewInt(0),
+	}
+	b := &types.Block{
+		Header:       &types.Header{Number: 1, BaseFee: big.NewInt(10)},
+		Transactions: []*types.Transaction{txn},
+	}
+
+	encode := func(obj interface{}) map[string]interface{} {
+		data, err := json.Marshal(obj)
+		assert.NoError(t, err)
+
+		res := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &res))
+		return res
+	}
+
+	res := encode(toBlock(b, true))
+	assert.Equal(t, "0xa", res["baseFeePerGas"])
+
+	// the gas price of the transaction is the one it paid in the block
+	tx := res["transactions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "0x2", tx["type"])
+	assert.Equal(t, "0xf", tx["gasPrice"])
+	assert.Equal(t, "0x32", tx["maxFeePerGas"])
+	assert.Equal(t, "0x5", tx["maxPriorityFeePerGas"])
+	assert.Equal(t, "0x64", tx["chainId"])
+
+	// the blocks and the transactions before the London fork have no fee fields
+	b.Header.BaseFee = nil
+	b.Transactions = []*types.Transaction{{GasPrice: big.NewInt(50), Value: big.NewInt(0)}}
+
+	res = encode(toBlock(b, true))
+	assert.NotContains(t, res, "baseFeePerGas")
+
+	tx = res["transactions"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, tx, "type")
+	assert.NotContains(t, tx, "maxFeePerGas")
+}
+
+func TestToTransaction_AccessList(t *testing.T) {
+	addr := types.StringToAddress("1")
+	txn := &types.Transaction{
+		Type:     types.AccessListTx,
+		ChainID:  big.NewInt(100),
+		GasPrice: big.NewInt(50),
+		Value:    big.NewInt(0),
+		AccessList: types.AccessList{
+			{Address: addr, StorageKeys: []types.Hash{types.StringToHash("2")}},
+		},
+	}
+	b := &types.Block{
+		Header:       &types.Header{Number: 1},
+		Transactions: []*types.Transaction{txn},
+	}
+
+	data, err := json.Marshal(toTransaction(txn, b, 0))
+	assert.NoError(t, err)
+
+	res := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &res))
+
+	assert.Equal(t, "0x1", res["type"])
+	assert.Equal(t, "0x32", res["gasPrice"])
+	assert.Equal(t, "0x64", res["chainId"])
+	assert.NotContains(t, res, "maxFeePerGas")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"address":     addr.String(),
+			"storageKeys": []interface{}{types.StringToHash("2").String()},
+		},
+	}, res["accessList"])
+}
@@ -14,7 +14,7 @@ func TestWeb3EndpointSha3(t *testing.T) {
 	resp, err := s.Handle([]byte(`{
 		"method": "web3_sha3",
 		"params": ["0x68656c6c6f20776f726c64"]
-	}`))
+	}`), requestContext{})
 	assert.NoError(t, err)
 
 	var res string
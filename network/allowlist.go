@@ -0,0 +1,198 @@
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// AllowlistRefreshInterval is the interval of the refresh of the allowlist sources
+const AllowlistRefreshInterval = time.Minute
+
+var (
+	// ErrNotAllowlisted is returned to the peers not in the allowlist of a permissioned network
+	ErrNotAllowlisted = errors.New("peer not in the allowlist")
+
+	// ErrAllowlistUnavailable is returned by the allowlist sources not ready yet,
+	// like an on-chain allowlist before the chain is loaded
+	ErrAllowlistUnavailable = errors.New("allowlist unavailable")
+)
+
+// AllowlistSource provides the libp2p IDs of the peers allowed in a permissioned network
+type AllowlistSource interface {
+	Allowlist() ([]peer.ID, error)
+}
+
+// FileAllowlist is a file with a peer ID or a libp2p address per line.
+// The empty lines and the lines starting with # are ignored
+type FileAllowlist string
+
+// Allowlist reads the peer IDs of the file
+func (f FileAllowlist) Allowlist() ([]peer.ID, error) {
+	file, err := os.Open(string(f))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ids := []peer.ID{}
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, err := StringToPeerID(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer at line %d: %v", n, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// allowlist restricts the network to the union of the peers of its sources.
+// The list of a source failing to refresh is the one of its last refresh
+type allowlist struct {
+	srv     *Server
+	sources []AllowlistSource
+
+	lists [][]peer.ID
+	ids   map[peer.ID]struct{}
+	lock  sync.RWMutex
+}
+
+func newAllowlist(srv *Server, sources []AllowlistSource) *allowlist {
+	return &allowlist{
+		srv:     srv,
+		sources: sources,
+		lists:   make([][]peer.ID, len(sources)),
+		ids:     map[peer.ID]struct{}{},
+	}
+}
+
+func (a *allowlist) contains(id peer.ID) bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	_, ok := a.ids[id]
+
+	return ok
+}
+
+// refresh reads the sources again, and disconnects the peers no longer allowed
+func (a *allowlist) refresh() {
+	for i, source := range a.sources {
+		ids, err := source.Allowlist()
+		if errors.Is(err, ErrAllowlistUnavailable) {
+			a.srv.logger.Debug("allowlist unavailable", "source", i)
+			continue
+		}
+		if err != nil {
+			a.srv.logger.Error("failed to refresh the allowlist", "source", i, "err", err)
+			continue
+		}
+
+		a.lock.Lock()
+		a.lists[i] = ids
+		a.lock.Unlock()
+	}
+
+	a.lock.Lock()
+	a.ids = map[peer.ID]struct{}{}
+	for _, list := range a.lists {
+		for _, id := range list {
+			a.ids[id] = struct{}{}
+		}
+	}
+	a.lock.Unlock()
+
+	// the connections still in the handshake are closed too
+	for _, id := range a.srv.host.Network().Peers() {
+		if !a.srv.IsAllowed(id) {
+			a.srv.Disconnect(id, ErrNotAllowlisted.Error())
+		}
+	}
+}
+
+// allowlistGater refuses the connections with the peers outside of the allowlist, in both directions,
+// before any protocol is served to them
+type allowlistGater struct {
+	// srv is set once the allowlist of the server is loaded, the connections are refused until then
+	srv atomic.Value
+}
+
+func (g *allowlistGater) allowed(id peer.ID) bool {
+	srv, ok := g.srv.Load().(*Server)
+
+	return ok && srv.IsAllowed(id)
+}
+
+func (g *allowlistGater) InterceptPeerDial(id peer.ID) bool {
+	return g.allowed(id)
+}
+
+func (g *allowlistGater) InterceptAddrDial(peer.ID, ma.Multiaddr) bool {
+	return true
+}
+
+func (g *allowlistGater) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *allowlistGater) InterceptSecured(_ network.Direction, id peer.ID, _ network.ConnMultiaddrs) bool {
+	return g.allowed(id)
+}
+
+func (g *allowlistGater) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+func (a *allowlist) run() {
+	for {
+		select {
+		case <-time.After(AllowlistRefreshInterval):
+		case <-a.srv.closeCh:
+			return
+		}
+
+		a.refresh()
+	}
+}
+
+// permissioned checks if the network is restricted to the consortium members or to the allowlist
+func (s *Server) permissioned() bool {
+	return s.config.Consortium != nil || s.allowlist != nil
+}
+
+// IsAllowed checks if the peer may connect, always true if the network has no allowlist
+func (s *Server) IsAllowed(id peer.ID) bool {
+	if s.allowlist == nil || id == s.host.ID() {
+		return true
+	}
+
+	return s.allowlist.contains(id)
+}
+
+// RefreshAllowlist reads the allowlist sources again, and disconnects the peers no longer allowed.
+// The allowlist is otherwise refreshed every AllowlistRefreshInterval
+func (s *Server) RefreshAllowlist() {
+	if s.allowlist != nil {
+		s.allowlist.refresh()
+	}
+}
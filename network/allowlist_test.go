@@ -0,0 +1,109 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAllowlist is an allowlist source the tests can update
+type mockAllowlist struct {
+	ids  []peer.ID
+	err  error
+	lock sync.Mutex
+}
+
+func (m *mockAllowlist) set(ids []peer.ID, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ids, m.err = ids, err
+}
+
+func (m *mockAllowlist) Allowlist() ([]peer.ID, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.ids, m.err
+}
+
+func TestFileAllowlist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "allowlist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	id0 := "16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW"
+	id1 := "16Uiu2HAkwMANiPL9bFTVRkxvEPqPRjygQsH4JoDNuYWjpzSBCzwu"
+
+	path := filepath.Join(dir, "allowlist")
+	content := "# validators\n" + id0 + "\n\n  /ip4/127.0.0.1/tcp/1478/p2p/" + id1 + "  \n"
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	ids, err := FileAllowlist(path).Allowlist()
+	assert.NoError(t, err)
+	assert.Len(t, ids, 2)
+	assert.Equal(t, id0, ids[0].String())
+	assert.Equal(t, id1, ids[1].String())
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(id0+"\ninvalid\n"), 0600))
+	_, err = FileAllowlist(path).Allowlist()
+	assert.Error(t, err)
+
+	_, err = FileAllowlist(filepath.Join(dir, "missing")).Allowlist()
+	assert.Error(t, err)
+}
+
+func TestAllowlist_Connections(t *testing.T) {
+	source := &mockAllowlist{}
+
+	srv0 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+		c.Allowlist = []AllowlistSource{source}
+	})
+	srv1 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1} {
+			srv.Close()
+		}
+	}()
+
+	id := srv1.AddrInfo().ID
+	assert.True(t, srv0.permissioned())
+	assert.True(t, srv0.IsAllowed(srv0.AddrInfo().ID))
+	assert.False(t, srv0.IsAllowed(id))
+
+	// the connections with the peers not in the allowlist are refused, in both directions
+	assert.Error(t, srv1.Join(srv0.AddrInfo(), 2*time.Second))
+	assert.False(t, srv0.hasPeer(id))
+	assert.Error(t, srv0.host.Connect(context.Background(), *srv1.AddrInfo()))
+	assert.NotEqual(t, network.Connected, srv0.host.Network().Connectedness(id))
+
+	source.set([]peer.ID{id}, nil)
+	srv0.RefreshAllowlist()
+	// srv1 backs off from dialing srv0 after the refused connection, srv0 dials it instead
+	assert.NoError(t, srv0.Join(srv1.AddrInfo(), 10*time.Second))
+
+	// the list of a source failing to refresh is kept
+	source.set(nil, errors.New("unavailable"))
+	srv0.RefreshAllowlist()
+	assert.True(t, srv0.IsAllowed(id))
+
+	// and the peers removed from the allowlist are disconnected
+	disconnectedCh := asyncWaitForEvent(srv0, 10*time.Second, disconnectedPeerHandler(id))
+	source.set([]peer.ID{}, nil)
+	srv0.RefreshAllowlist()
+	assert.True(t, <-disconnectedCh)
+	assert.False(t, srv0.IsAllowed(id))
+}
@@ -0,0 +1,196 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// appTopicPrefix is the prefix of the names of the application topics on the network,
+	// so they can't collide with the topics of the node
+	appTopicPrefix = "/app"
+
+	// DefaultAppMessageSize is the maximum size of the application messages, if not set
+	DefaultAppMessageSize = 64 * 1024
+)
+
+var appTopicNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// AppTopicConfig defines a gossip topic of an application embedding the node,
+// which piggybacks on the P2P layer of the chain
+type AppTopicConfig struct {
+	// Name is the name of the topic, it is namespaced by the chain ID on the network.
+	// It is made of up to 64 lowercase letters, digits, dots, dashes and underscores
+	Name string
+
+	// Message is an instance of the protobuf message type of the topic
+	Message proto.Message
+
+	// Validator checks the messages, with the peer that authored them, before they are
+	// delivered and relayed to the other peers. The rejected messages are dropped. Optional
+	Validator func(from peer.ID, msg proto.Message) bool
+
+	// Handler receives the messages of the other peers. Optional, for the topics that are only published to
+	Handler func(from peer.ID, msg proto.Message)
+
+	// MaxMessageSize is the maximum size of the encoded messages. Default: DefaultAppMessageSize
+	MaxMessageSize int
+}
+
+func (c *AppTopicConfig) validate() error {
+	if !appTopicNameRegex.MatchString(c.Name) {
+		return fmt.Errorf("invalid application topic name %q", c.Name)
+	}
+	if c.Message == nil {
+		return errors.New("the message type of the application topic is not set")
+	}
+	if c.MaxMessageSize < 0 {
+		return errors.New("the maximum message size of the application topic is negative")
+	}
+
+	return nil
+}
+
+// appTopicName returns the name of the application topic on the network
+func (s *Server) appTopicName(name string) string {
+	var chainID int
+	if s.config.Chain != nil {
+		chainID = s.config.Chain.Params.ChainID
+	}
+
+	return fmt.Sprintf("%s/%d/%s", appTopicPrefix, chainID, name)
+}
+
+// RegisterAppTopic joins a gossip topic of the application. The messages are decoded and validated
+// before they reach the handler, and the panics of the validator and of the handler are recovered,
+// so a faulty application topic can't take the node down
+func (s *Server) RegisterAppTopic(config *AppTopicConfig) (*Topic, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	name := s.appTopicName(config.Name)
+	logger := s.logger.Named("app-topic").With("topic", config.Name)
+
+	maxSize := config.MaxMessageSize
+	if maxSize == 0 {
+		maxSize = DefaultAppMessageSize
+	}
+
+	validate := func(from peer.ID, obj proto.Message) (ok bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("the validator of the application topic panicked", "from", from, "err", r)
+				ok = false
+			}
+		}()
+
+		return config.Validator(from, obj)
+	}
+
+	validator := func(ctx context.Context, id peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		if s.permissioned() && id != s.host.ID() && !s.hasPeer(id) {
+			return pubsub.ValidationReject
+		}
+		// the peer relaying an oversized or malformed message violates the protocol
+		data, err := decodeGossip(msg.Data, maxSize)
+		if err != nil {
+			s.ReportFault(id, FaultProtocolViolation)
+			return pubsub.ValidationReject
+		}
+
+		obj := config.Message.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal(data, obj); err != nil {
+			s.ReportFault(id, FaultProtocolViolation)
+			return pubsub.ValidationReject
+		}
+
+		if config.Validator != nil && !validate(msg.GetFrom(), obj) {
+			return pubsub.ValidationReject
+		}
+
+		// the decoded message is handed to the subscription
+		msg.ValidatorData = obj
+
+		return pubsub.ValidationAccept
+	}
+
+	if err := s.ps.RegisterTopicValidator(name, validator); err != nil {
+		return nil, err
+	}
+
+	topic, err := s.ps.Join(name)
+	if err != nil {
+		_ = s.ps.UnregisterTopicValidator(name)
+		return nil, err
+	}
+
+	if config.Handler != nil {
+		sub, err := topic.Subscribe()
+		if err != nil {
+			_ = topic.Close()
+			_ = s.ps.UnregisterTopicValidator(name)
+			return nil, err
+		}
+
+		go s.appTopicReadLoop(sub, config.Handler, logger)
+	}
+
+	return &Topic{
+		logger:  logger,
+		srv:     s,
+		topic:   topic,
+		typ:     reflect.TypeOf(config.Message).Elem(),
+		maxSize: maxSize,
+	}, nil
+}
+
+// appTopicReadLoop delivers the messages of the other peers to the handler, until the server is closed
+func (s *Server) appTopicReadLoop(
+	sub *pubsub.Subscription,
+	handler func(from peer.ID, msg proto.Message),
+	logger hclog.Logger,
+) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	go func() {
+		<-s.closeCh
+		cancelFn()
+	}()
+
+	handle := func(from peer.ID, obj proto.Message) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("the handler of the application topic panicked", "from", from, "err", r)
+			}
+		}()
+
+		handler(from, obj)
+	}
+
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			// the subscription is canceled when the server is closed
+			return
+		}
+
+		if msg.ReceivedFrom == s.host.ID() {
+			continue
+		}
+
+		obj, ok := msg.ValidatorData.(proto.Message)
+		if !ok {
+			continue
+		}
+
+		handle(msg.GetFrom(), obj)
+	}
+}
@@ -0,0 +1,109 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	testproto "github.com/0xPolygon/polygon-sdk/network/proto/test"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAppTopic_Config(t *testing.T) {
+	srv := CreateServer(t, nil)
+	defer srv.Close()
+
+	assert.Equal(t, "/app/1/chat", srv.appTopicName("chat"))
+
+	for _, config := range []*AppTopicConfig{
+		{Name: "", Message: &testproto.AReq{}},
+		{Name: "Chat", Message: &testproto.AReq{}},
+		{Name: "chat/0.1", Message: &testproto.AReq{}},
+		{Name: "chat"},
+		{Name: "chat", Message: &testproto.AReq{}, MaxMessageSize: -1},
+	} {
+		_, err := srv.RegisterAppTopic(config)
+		assert.Error(t, err, config.Name)
+	}
+
+	_, err := srv.RegisterAppTopic(&AppTopicConfig{Name: "chat", Message: &testproto.AReq{}})
+	assert.NoError(t, err)
+
+	// the topics are registered once
+	_, err = srv.RegisterAppTopic(&AppTopicConfig{Name: "chat", Message: &testproto.AReq{}})
+	assert.Error(t, err)
+}
+
+func TestAppTopic_Gossip(t *testing.T) {
+	srv0 := CreateServer(t, nil)
+	srv1 := CreateServer(t, nil)
+
+	MultiJoin(t, srv0, srv1)
+
+	validator := func(from peer.ID, msg proto.Message) bool {
+		switch msg.(*testproto.AReq).Msg {
+		case "bad":
+			return false
+		case "panic":
+			panic("validator")
+		}
+		return true
+	}
+
+	type received struct {
+		from peer.ID
+		msg  string
+	}
+
+	msgCh := make(chan received, 10)
+	handler := func(from peer.ID, msg proto.Message) {
+		req := msg.(*testproto.AReq)
+		if req.Msg == "crash" {
+			panic("handler")
+		}
+		msgCh <- received{from, req.Msg}
+	}
+
+	topic0, err := srv0.RegisterAppTopic(&AppTopicConfig{
+		Name:           "chat",
+		Message:        &testproto.AReq{},
+		Validator:      validator,
+		MaxMessageSize: 100,
+	})
+	assert.NoError(t, err)
+
+	_, err = srv1.RegisterAppTopic(&AppTopicConfig{
+		Name:           "chat",
+		Message:        &testproto.AReq{},
+		Validator:      validator,
+		Handler:        handler,
+		MaxMessageSize: 100,
+	})
+	assert.NoError(t, err)
+
+	// wait until build mesh
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, WaitForSubscribers(ctx, srv0, srv0.appTopicName("chat"), 1))
+
+	// the invalid messages are dropped, and the panic of the handler is recovered
+	for _, msg := range []string{"bad", "panic", string(make([]byte, 200)), "crash", "a"} {
+		assert.NoError(t, topic0.Publish(&testproto.AReq{Msg: msg}))
+	}
+
+	select {
+	case msg := <-msgCh:
+		assert.Equal(t, "a", msg.msg)
+		assert.Equal(t, srv0.host.ID(), msg.from)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	select {
+	case msg := <-msgCh:
+		t.Fatalf("unexpected message %q", msg.msg)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
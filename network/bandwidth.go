@@ -0,0 +1,159 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	libp2pMetrics "github.com/libp2p/go-libp2p-core/metrics"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+)
+
+// bandwidthTrimInterval is the interval at which the traffic of the peers disconnected
+// for longer than the interval is forgotten
+const bandwidthTrimInterval = time.Hour
+
+// Traffic is the number of bytes exchanged with a peer
+type Traffic struct {
+	In  int64
+	Out int64
+}
+
+// PeerBandwidth is the traffic with a peer, in total and by protocol
+type PeerBandwidth struct {
+	ID peer.ID
+
+	// Total is the traffic of the peer on all the protocols
+	Total Traffic
+
+	// RateIn and RateOut are the current bytes per second received from and sent to the peer
+	RateIn  float64
+	RateOut float64
+
+	Protocols map[string]Traffic
+}
+
+// bandwidthReporter counts the traffic of the streams by peer and protocol, on top of the
+// libp2p counter, and reports the traffic of the protocols to the metrics
+type bandwidthReporter struct {
+	*libp2pMetrics.BandwidthCounter
+
+	metrics *Metrics
+
+	traffic map[peer.ID]map[protocol.ID]*Traffic
+	lock    sync.Mutex
+}
+
+func newBandwidthReporter(metrics *Metrics) *bandwidthReporter {
+	return &bandwidthReporter{
+		BandwidthCounter: libp2pMetrics.NewBandwidthCounter(),
+		metrics:          metrics,
+		traffic:          map[peer.ID]map[protocol.ID]*Traffic{},
+	}
+}
+
+// get returns the traffic of the peer on the protocol. It is called with the lock held
+func (b *bandwidthReporter) get(p peer.ID, proto protocol.ID) *Traffic {
+	protocols, ok := b.traffic[p]
+	if !ok {
+		protocols = map[protocol.ID]*Traffic{}
+		b.traffic[p] = protocols
+	}
+	traffic, ok := protocols[proto]
+	if !ok {
+		traffic = &Traffic{}
+		protocols[proto] = traffic
+	}
+
+	return traffic
+}
+
+// LogSentMessageStream implements the libp2p metrics reporter
+func (b *bandwidthReporter) LogSentMessageStream(size int64, proto protocol.ID, p peer.ID) {
+	b.BandwidthCounter.LogSentMessageStream(size, proto, p)
+	b.metrics.SentBytes.With("protocol", string(proto)).Add(float64(size))
+
+	b.lock.Lock()
+	b.get(p, proto).Out += size
+	b.lock.Unlock()
+}
+
+// LogRecvMessageStream implements the libp2p metrics reporter
+func (b *bandwidthReporter) LogRecvMessageStream(size int64, proto protocol.ID, p peer.ID) {
+	b.BandwidthCounter.LogRecvMessageStream(size, proto, p)
+	b.metrics.ReceivedBytes.With("protocol", string(proto)).Add(float64(size))
+
+	b.lock.Lock()
+	b.get(p, proto).In += size
+	b.lock.Unlock()
+}
+
+// list returns the traffic of the peers, the largest senders of the node first
+func (b *bandwidthReporter) list() []*PeerBandwidth {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	peers := make([]*PeerBandwidth, 0, len(b.traffic))
+	for id, protocols := range b.traffic {
+		stats := b.GetBandwidthForPeer(id)
+		bw := &PeerBandwidth{
+			ID:        id,
+			RateIn:    stats.RateIn,
+			RateOut:   stats.RateOut,
+			Protocols: map[string]Traffic{},
+		}
+		for proto, traffic := range protocols {
+			bw.Protocols[string(proto)] = *traffic
+			bw.Total.In += traffic.In
+			bw.Total.Out += traffic.Out
+		}
+		peers = append(peers, bw)
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Total.Out != peers[j].Total.Out {
+			return peers[i].Total.Out > peers[j].Total.Out
+		}
+		return peers[i].ID < peers[j].ID
+	})
+
+	return peers
+}
+
+// trim forgets the traffic of the peers that are not connected
+func (b *bandwidthReporter) trim(isConnected func(peer.ID) bool, since time.Time) {
+	b.TrimIdle(since)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for id := range b.traffic {
+		if !isConnected(id) {
+			delete(b.traffic, id)
+		}
+	}
+}
+
+func (s *Server) runBandwidthTrim() {
+	for {
+		select {
+		case <-time.After(bandwidthTrimInterval):
+		case <-s.closeCh:
+			return
+		}
+
+		s.bandwidth.trim(s.isConnected, time.Now().Add(-bandwidthTrimInterval))
+	}
+}
+
+// Bandwidth returns the traffic with the peers, by protocol, the largest senders of the node first.
+// The traffic of the peers disconnected for a while is forgotten
+func (s *Server) Bandwidth() []*PeerBandwidth {
+	return s.bandwidth.list()
+}
+
+// BandwidthTotals returns the traffic of the node on all the streams
+func (s *Server) BandwidthTotals() libp2pMetrics.Stats {
+	return s.bandwidth.GetBandwidthTotals()
+}
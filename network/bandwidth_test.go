@@ -0,0 +1,69 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandwidth_Peers(t *testing.T) {
+	srv0 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+	srv1 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+	defer srv0.Close()
+	defer srv1.Close()
+
+	assert.NoError(t, srv0.Join(srv1.AddrInfo(), 10*time.Second))
+
+	// the handshake is counted on the identity protocol, on both sides
+	for _, srv := range []*Server{srv0, srv1} {
+		assert.Eventually(t, func() bool {
+			peers := srv.Bandwidth()
+			if len(peers) != 1 {
+				return false
+			}
+			traffic := peers[0].Protocols[identityProtoV1]
+
+			return traffic.In > 0 && traffic.Out > 0
+		}, 10*time.Second, 100*time.Millisecond)
+	}
+
+	peers := srv0.Bandwidth()
+	assert.Equal(t, srv1.AddrInfo().ID, peers[0].ID)
+	assert.GreaterOrEqual(t, peers[0].Total.Out, peers[0].Protocols[identityProtoV1].Out)
+
+	// the totals are updated by the libp2p meters every second
+	assert.Eventually(t, func() bool {
+		return srv0.BandwidthTotals().TotalOut > 0
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestBandwidth_Trim(t *testing.T) {
+	b := newBandwidthReporter(NilMetrics())
+
+	b.LogSentMessageStream(10, "/a", "peer1")
+	b.LogSentMessageStream(30, "/b", "peer2")
+	b.LogSentMessageStream(5, "/a", "peer2")
+	b.LogRecvMessageStream(20, "/a", "peer1")
+
+	// the largest senders first
+	peers := b.list()
+	assert.Len(t, peers, 2)
+	assert.Equal(t, peer.ID("peer2"), peers[0].ID)
+	assert.Equal(t, Traffic{In: 0, Out: 35}, peers[0].Total)
+	assert.Equal(t, Traffic{In: 20, Out: 10}, peers[1].Protocols["/a"])
+
+	// the disconnected peers are forgotten
+	b.trim(func(id peer.ID) bool {
+		return id == "peer1"
+	}, time.Now())
+
+	peers = b.list()
+	assert.Len(t, peers, 1)
+	assert.Equal(t, peer.ID("peer1"), peers[0].ID)
+}
@@ -0,0 +1,137 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygon/polygon-sdk/network/grpc"
+	"github.com/0xPolygon/polygon-sdk/network/proto"
+	"github.com/golang/snappy"
+	"github.com/libp2p/go-libp2p-core/peer"
+	rawGrpc "google.golang.org/grpc"
+)
+
+// DefaultMaxMessageSize is the maximum size of the messages of a protocol, if not set in the config
+const DefaultMaxMessageSize = 4 * 1024 * 1024
+
+// gossipSnappyPrefix prefixes the snappy compressed gossip messages. Field number 0 and
+// wire type 7 are both invalid in protobuf, so a raw message never starts with it
+const gossipSnappyPrefix = 0x07
+
+var errMessageTooLarge = errors.New("message too large")
+
+// MaxMessageSize returns the maximum size of the messages the node accepts on the protocol
+func (s *Server) MaxMessageSize(proto string) int {
+	if size, ok := s.config.MaxMessageSizes[proto]; ok && size > 0 {
+		return size
+	}
+
+	return DefaultMaxMessageSize
+}
+
+// protocolIDs returns the registered protocols, sorted
+func (s *Server) protocolIDs() []string {
+	s.protocolsLock.Lock()
+	defer s.protocolsLock.Unlock()
+
+	ids := make([]string, 0, len(s.protocols))
+	for id := range s.protocols {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// GrpcServerOptions returns the options of the grpc server of the protocol,
+// limiting the size of the received messages
+func (s *Server) GrpcServerOptions(proto string) []rawGrpc.ServerOption {
+	return []rawGrpc.ServerOption{
+		rawGrpc.MaxRecvMsgSize(s.MaxMessageSize(proto)),
+	}
+}
+
+// GrpcCallOptions returns the options of the grpc client of the protocol to the peer, as negotiated
+// at the handshake. The messages are compressed if both nodes support it, and the ones larger than
+// the peer accepts fail before they are sent
+func (s *Server) GrpcCallOptions(proto string, id peer.ID) []rawGrpc.CallOption {
+	opts := []rawGrpc.CallOption{
+		rawGrpc.MaxCallRecvMsgSize(s.MaxMessageSize(proto)),
+	}
+
+	s.peersLock.Lock()
+	p, ok := s.peers[id]
+	s.peersLock.Unlock()
+
+	if !ok {
+		return opts
+	}
+	if size, ok := p.maxMessageSizes[proto]; ok && size > 0 {
+		opts = append(opts, rawGrpc.MaxCallSendMsgSize(int(size)))
+	}
+	if s.config.Compression && p.compression {
+		opts = append(opts, rawGrpc.UseCompressor(grpc.Snappy))
+	}
+
+	return opts
+}
+
+// setHandshake sets the compression and the message sizes the peer accepts, from its status
+func (p *Peer) setHandshake(status *proto.Status) {
+	for _, algo := range status.Compression {
+		if algo == grpc.Snappy {
+			p.compression = true
+		}
+	}
+	p.maxMessageSizes = status.MaxMessageSizes
+}
+
+// compressGossip checks if the published gossip messages are compressed, which
+// requires the compression to be enabled and supported by all the peers
+func (s *Server) compressGossip() bool {
+	if !s.config.Compression {
+		return false
+	}
+
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	for _, p := range s.peers {
+		if !p.compression {
+			return false
+		}
+	}
+
+	return true
+}
+
+// encodeGossip compresses the encoded gossip message, if the peers support it
+func (s *Server) encodeGossip(data []byte) []byte {
+	if !s.compressGossip() {
+		return data
+	}
+
+	return append([]byte{gossipSnappyPrefix}, snappy.Encode(nil, data)...)
+}
+
+// decodeGossip decompresses the gossip message if it is compressed, checking its size
+// before it is decompressed
+func decodeGossip(data []byte, maxSize int) ([]byte, error) {
+	if len(data) == 0 || data[0] != gossipSnappyPrefix {
+		if len(data) > maxSize {
+			return nil, errMessageTooLarge
+		}
+		return data, nil
+	}
+
+	size, err := snappy.DecodedLen(data[1:])
+	if err != nil {
+		return nil, err
+	}
+	if size > maxSize {
+		return nil, fmt.Errorf("%v: %d bytes decompressed", errMessageTooLarge, size)
+	}
+
+	return snappy.Decode(nil, data[1:])
+}
@@ -0,0 +1,119 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	testproto "github.com/0xPolygon/polygon-sdk/network/proto/test"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeGossip(t *testing.T) {
+	raw := bytes.Repeat([]byte{0x0a, 0x01, 0x61}, 100)
+	compressed := append([]byte{gossipSnappyPrefix}, snappy.Encode(nil, raw)...)
+	assert.Less(t, len(compressed), len(raw))
+
+	// the raw messages are passed through
+	data, err := decodeGossip(raw, len(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	data, err = decodeGossip(compressed, len(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	// the size is checked before the message is decompressed
+	_, err = decodeGossip(raw, len(raw)-1)
+	assert.Error(t, err)
+
+	_, err = decodeGossip(compressed, len(raw)-1)
+	assert.Error(t, err)
+
+	_, err = decodeGossip([]byte{gossipSnappyPrefix, 0xff}, len(raw))
+	assert.Error(t, err)
+}
+
+func TestCompression_Handshake(t *testing.T) {
+	srv0 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+		c.MaxMessageSizes = map[string]int{identityProtoV1: 1024}
+	})
+	srv1 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+	srv2 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+		c.Compression = false
+	})
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1, srv2} {
+			srv.Close()
+		}
+	}()
+
+	assert.NoError(t, srv0.Join(srv1.AddrInfo(), 10*time.Second))
+
+	// the compression and the message sizes are negotiated at the handshake
+	assert.Eventually(t, func() bool {
+		return srv1.hasPeer(srv0.AddrInfo().ID)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	p := srv1.peers[srv0.AddrInfo().ID]
+	assert.True(t, p.compression)
+	assert.Equal(t, uint64(1024), p.maxMessageSizes[identityProtoV1])
+	assert.Len(t, srv1.GrpcCallOptions(identityProtoV1, srv0.AddrInfo().ID), 3)
+	assert.True(t, srv0.compressGossip())
+
+	// the gossip is not compressed as long as a peer doesn't support it
+	assert.NoError(t, srv0.Join(srv2.AddrInfo(), 10*time.Second))
+	assert.False(t, srv0.compressGossip())
+	assert.Len(t, srv0.GrpcCallOptions(identityProtoV1, srv2.AddrInfo().ID), 2)
+}
+
+func TestCompression_Gossip(t *testing.T) {
+	srv0 := CreateServer(t, nil)
+	srv1 := CreateServer(t, func(c *Config) {
+		c.MaxMessageSizes = map[string]int{"topic/0.1": 64}
+	})
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1} {
+			srv.Close()
+		}
+	}()
+
+	MultiJoin(t, srv0, srv1)
+
+	topicName := "topic/0.1"
+
+	topic0, err := srv0.NewTopic(topicName, &testproto.AReq{})
+	assert.NoError(t, err)
+
+	topic1, err := srv1.NewTopic(topicName, &testproto.AReq{})
+	assert.NoError(t, err)
+
+	msgCh := make(chan *testproto.AReq, 2)
+	assert.NoError(t, topic1.Subscribe(func(obj interface{}) {
+		msgCh <- obj.(*testproto.AReq)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, WaitForSubscribers(ctx, srv0, topicName, 1))
+
+	// the messages larger than the peer accepts once decompressed are dropped
+	assert.True(t, srv0.compressGossip())
+	assert.NoError(t, topic0.Publish(&testproto.AReq{Msg: string(bytes.Repeat([]byte("a"), 100))}))
+	assert.NoError(t, topic0.Publish(&testproto.AReq{Msg: "b"}))
+
+	select {
+	case msg := <-msgCh:
+		assert.Equal(t, "b", msg.Msg)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout")
+	}
+}
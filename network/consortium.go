@@ -0,0 +1,150 @@
+package network
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var (
+	ErrNoConsortiumCertificate = errors.New("no consortium certificate")
+)
+
+// ConsortiumConfig restricts the network to the nodes holding a certificate issued by the consortium CA.
+// A certificate binds the libp2p ID of a node in its subject common name, and the ID itself
+// is authenticated by the secure channel of the connection
+type ConsortiumConfig struct {
+	// CA is the pool of the certificates of the consortium CA
+	CA *x509.CertPool
+
+	// Certificate is the DER encoded certificate of the node
+	Certificate []byte
+}
+
+// LoadConsortiumConfig reads the PEM encoded certificates of the consortium CA and of the node
+func LoadConsortiumConfig(caFile, certFile string) (*ConsortiumConfig, error) {
+	caData, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the consortium CA: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+
+	certData, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the consortium certificate: %v", err)
+	}
+
+	block, _ := pem.Decode(certData)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found in %s", certFile)
+	}
+
+	return &ConsortiumConfig{
+		CA:          pool,
+		Certificate: block.Bytes,
+	}, nil
+}
+
+// verify checks that the certificate is issued by the consortium CA to the peer
+func (c *ConsortiumConfig) verify(id peer.ID, raw []byte, now time.Time) error {
+	if len(raw) == 0 {
+		return ErrNoConsortiumCertificate
+	}
+
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return fmt.Errorf("invalid consortium certificate: %v", err)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:       c.CA,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		return fmt.Errorf("invalid consortium certificate: %v", err)
+	}
+
+	if cert.Subject.CommonName != id.String() {
+		return fmt.Errorf("the consortium certificate is issued to %s", cert.Subject.CommonName)
+	}
+
+	return nil
+}
+
+// GenerateConsortiumCA generates the key and the self-signed certificate of a consortium CA
+func GenerateConsortiumCA(name string, validity time.Duration) ([]byte, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := newCertificateTemplate(name, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+// IssueConsortiumCertificate issues the certificate of the node with the ID, signed by the consortium CA.
+// The node does not hold a key for the certificate, it proves its ID with the libp2p key instead
+func IssueConsortiumCertificate(
+	ca *x509.Certificate,
+	caKey crypto.Signer,
+	id peer.ID,
+	validity time.Duration,
+) ([]byte, error) {
+	if err := id.Validate(); err != nil {
+		return nil, err
+	}
+
+	template, err := newCertificateTemplate(id.String(), validity)
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+
+	// the certificate carries the CA public key, since x509 does not support the libp2p keys
+	return x509.CreateCertificate(rand.Reader, template, ca, caKey.Public(), caKey)
+}
+
+func newCertificateTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
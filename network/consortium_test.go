@@ -0,0 +1,182 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/secrets"
+	"github.com/0xPolygon/polygon-sdk/secrets/local"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConsortium struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestConsortium(t *testing.T) *testConsortium {
+	t.Helper()
+
+	raw, key, err := GenerateConsortiumCA("consortium", time.Hour)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(raw)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &testConsortium{cert: cert, key: key, pool: pool}
+}
+
+func (c *testConsortium) issue(t *testing.T, id peer.ID) []byte {
+	t.Helper()
+
+	cert, err := IssueConsortiumCertificate(c.cert, c.key, id, time.Hour)
+	assert.NoError(t, err)
+
+	return cert
+}
+
+// newTestNodeKey initializes the networking key in the data directory, and returns the ID of the node
+func newTestNodeKey(t *testing.T, dataDir string) peer.ID {
+	t.Helper()
+
+	secretsManager, err := local.SecretsManagerFactory(
+		nil,
+		&secrets.SecretsManagerParams{
+			Logger: hclog.NewNullLogger(),
+			Extra: map[string]interface{}{
+				secrets.Path: dataDir,
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	key, err := setupLibp2pKey(secretsManager)
+	assert.NoError(t, err)
+
+	id, err := peer.IDFromPrivateKey(key)
+	assert.NoError(t, err)
+
+	return id
+}
+
+func TestConsortiumVerify(t *testing.T) {
+	consortium := newTestConsortium(t)
+	config := &ConsortiumConfig{CA: consortium.pool}
+
+	id0, id1 := peer.ID("a"), peer.ID("b")
+	cert := consortium.issue(t, id0)
+
+	assert.NoError(t, config.verify(id0, cert, time.Now()))
+
+	// issued to another node
+	assert.Error(t, config.verify(id1, cert, time.Now()))
+
+	// expired
+	assert.Error(t, config.verify(id0, cert, time.Now().Add(2*time.Hour)))
+
+	// issued by another CA
+	other := newTestConsortium(t)
+	assert.Error(t, config.verify(id0, other.issue(t, id0), time.Now()))
+
+	// without certificate
+	assert.Equal(t, ErrNoConsortiumCertificate, config.verify(id0, nil, time.Now()))
+}
+
+// newConsortiumServer creates a server with a certificate of the consortium
+func newConsortiumServer(t *testing.T, c *testConsortium) *Server {
+	t.Helper()
+
+	dataDir, err := ioutil.TempDir("", "consortium")
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dataDir)
+	})
+
+	id := newTestNodeKey(t, dataDir)
+
+	return CreateServer(t, func(cfg *Config) {
+		cfg.DataDir = dataDir
+		cfg.Consortium = &ConsortiumConfig{
+			CA:          c.pool,
+			Certificate: c.issue(t, id),
+		}
+	})
+}
+
+func TestConsortiumHandshake(t *testing.T) {
+	consortium := newTestConsortium(t)
+	other := newTestConsortium(t)
+
+	srv0, srv1 := newConsortiumServer(t, consortium), newConsortiumServer(t, consortium)
+	outsider := newConsortiumServer(t, other)
+
+	defer func() {
+		srv0.Close()
+		srv1.Close()
+		outsider.Close()
+	}()
+
+	// the members join each other
+	MultiJoin(t, srv0, srv1)
+	assert.True(t, srv0.hasPeer(srv1.host.ID()))
+	assert.True(t, srv1.hasPeer(srv0.host.ID()))
+
+	// the node of another consortium is rejected
+	assert.Error(t, outsider.Join(srv0.AddrInfo(), 5*time.Second))
+	assert.False(t, srv0.hasPeer(outsider.host.ID()))
+	assert.False(t, outsider.hasPeer(srv0.host.ID()))
+}
+
+func TestConsortiumStreams(t *testing.T) {
+	consortium := newTestConsortium(t)
+
+	srv0, srv1 := newConsortiumServer(t, consortium), newConsortiumServer(t, consortium)
+	outsider := newConsortiumServer(t, newTestConsortium(t))
+
+	defer func() {
+		srv0.Close()
+		srv1.Close()
+		outsider.Close()
+	}()
+
+	const testProto = "/test/0.1"
+
+	var served int32
+	srv0.wrapStream(testProto, func(stream network.Stream) {
+		atomic.AddInt32(&served, 1)
+		stream.Close()
+	})
+
+	// openStream opens a stream of the protocol and waits for the end of it
+	openStream := func(srv *Server) {
+		stream, err := srv.host.NewStream(context.Background(), srv0.host.ID(), protocol.ID(testProto))
+		if err != nil {
+			return
+		}
+		stream.Read(make([]byte, 1))
+		stream.Close()
+	}
+
+	// the node without a certificate is connected, but its streams are refused before the handshake
+	assert.NoError(t, outsider.host.Connect(context.Background(), *srv0.AddrInfo()))
+	openStream(outsider)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&served))
+
+	MultiJoin(t, srv0, srv1)
+	openStream(srv1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&served))
+}
@@ -28,6 +28,9 @@ const defaultBucketSize = 20
 type referencePeer struct {
 	id     peer.ID
 	stream interface{}
+
+	// reported are the peers returned by the last find peers request
+	reported []peer.ID
 }
 
 type referencePeers []*referencePeer
@@ -68,13 +71,37 @@ type discovery struct {
 	notifyCh chan struct{}
 	closeCh  chan struct{}
 
-	bootnodes []*peer.AddrInfo
+	bootnodes     []*peer.AddrInfo
+	dnsBootnodes  []*peer.AddrInfo
+	bootnodesLock sync.RWMutex
 }
 
 func (d *discovery) setBootnodes(bootnodes []*peer.AddrInfo) {
+	d.bootnodesLock.Lock()
+	defer d.bootnodesLock.Unlock()
+
 	d.bootnodes = bootnodes
 }
 
+// setDNSBootnodes sets the bootnodes resolved from the dns trees
+func (d *discovery) setDNSBootnodes(bootnodes []*peer.AddrInfo) {
+	d.bootnodesLock.Lock()
+	defer d.bootnodesLock.Unlock()
+
+	d.dnsBootnodes = bootnodes
+}
+
+// getBootnodes returns the bootnodes of the chain, followed by the ones of the dns trees
+func (d *discovery) getBootnodes() []*peer.AddrInfo {
+	d.bootnodesLock.RLock()
+	defer d.bootnodesLock.RUnlock()
+
+	bootnodes := make([]*peer.AddrInfo, 0, len(d.bootnodes)+len(d.dnsBootnodes))
+	bootnodes = append(bootnodes, d.bootnodes...)
+
+	return append(bootnodes, d.dnsBootnodes...)
+}
+
 func (d *discovery) setup() error {
 	d.notifyCh = make(chan struct{}, 5)
 	d.peers = referencePeers{}
@@ -140,6 +167,16 @@ func (d *discovery) call(peerID peer.ID) error {
 		return err
 	}
 
+	reported := make([]peer.ID, 0, len(nodes))
+	for _, node := range nodes {
+		reported = append(reported, node.ID)
+	}
+	d.peersLock.Lock()
+	if p := d.peers.find(peerID); p != nil {
+		p.reported = reported
+	}
+	d.peersLock.Unlock()
+
 	// before we include peers on the routing table -> dial queue
 	// we have to add them to the peerstore so that they are
 	// available to all the libp2p services
@@ -153,6 +190,19 @@ func (d *discovery) call(peerID peer.ID) error {
 	return nil
 }
 
+// reportedPeers returns the peers reported by the connected peers
+func (d *discovery) reportedPeers() map[peer.ID][]peer.ID {
+	d.peersLock.Lock()
+	defer d.peersLock.Unlock()
+
+	res := map[peer.ID][]peer.ID{}
+	for _, p := range d.peers {
+		res[p.id] = append([]peer.ID{}, p.reported...)
+	}
+
+	return res
+}
+
 func (d *discovery) getStream(peerID peer.ID) (interface{}, error) {
 	d.peersLock.Lock()
 	defer d.peersLock.Unlock()
@@ -215,7 +265,7 @@ func (d *discovery) run() {
 func (d *discovery) handleDiscovery() {
 	if d.routingTable.Size() == 0 {
 		// if there are no peers on the table try to include the bootnodes
-		for _, node := range d.bootnodes {
+		for _, node := range d.getBootnodes() {
 			if _, err := d.routingTable.TryAddPeer(node.ID, false, false); err != nil {
 				d.srv.logger.Error("failed to add bootnode", "err", err)
 			}
@@ -0,0 +1,360 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+)
+
+// The bootnodes can be served with DNS TXT records, as in EIP-1459 where the leaves
+// hold libp2p addresses instead of ENRs. The root record at the domain of the tree
+// points to the hash of the top branch, and is signed with the key in the URL of the tree:
+//
+//	enrtree://<base32 compressed public key>@<domain>
+//
+//	<domain>               enrtree-root:v1 e=<hash> seq=<seq> sig=<signature>
+//	<hash>.<domain>        enrtree-branch:<hash>,<hash>,...
+//	<hash>.<domain>        libp2p:<multiaddr with the peer id>
+//
+// Every record is at the subdomain of its hash, the base32 encoding of the first
+// 16 bytes of its keccak256 hash, so the records can't be altered without the key
+const (
+	DNSTreeScheme = "enrtree://"
+
+	dnsRootPrefix   = "enrtree-root:v1"
+	dnsBranchPrefix = "enrtree-branch:"
+	dnsLeafPrefix   = "libp2p:"
+
+	// dnsMaxBranchChildren is the number of children of a branch, so the record fits in a TXT string
+	dnsMaxBranchChildren = 13
+
+	// dnsMaxEntries caps the number of records resolved in a tree
+	dnsMaxEntries = 2000
+
+	// DefaultDNSDiscoveryInterval is the interval of the refresh of the DNS trees
+	DefaultDNSDiscoveryInterval = 30 * time.Minute
+)
+
+var dnsEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var errDNSTreeTooLarge = errors.New("the dns tree has too many entries")
+
+// DNSTree is a tree of bootnodes served with DNS TXT records
+type DNSTree struct {
+	PubKey *ecdsa.PublicKey
+	Domain string
+}
+
+// ParseDNSTreeURL parses an enrtree://<public key>@<domain> URL
+func ParseDNSTreeURL(url string) (*DNSTree, error) {
+	if !strings.HasPrefix(url, DNSTreeScheme) {
+		return nil, fmt.Errorf("dns tree url must start with %s", DNSTreeScheme)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(url, DNSTreeScheme), "@", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errors.New("dns tree url must be enrtree://<public key>@<domain>")
+	}
+
+	raw, err := dnsEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding: %v", err)
+	}
+	pub, err := btcec.ParsePubKey(raw, crypto.S256)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+
+	return &DNSTree{PubKey: pub.ToECDSA(), Domain: parts[1]}, nil
+}
+
+// URL returns the enrtree:// URL of the tree
+func (t *DNSTree) URL() string {
+	key := (*btcec.PublicKey)(t.PubKey).SerializeCompressed()
+
+	return DNSTreeScheme + dnsEncoding.EncodeToString(key) + "@" + t.Domain
+}
+
+func dnsRecordHash(record string) string {
+	return dnsEncoding.EncodeToString(crypto.Keccak256([]byte(record))[:16])
+}
+
+func dnsRootContent(hash string, seq uint64) string {
+	return fmt.Sprintf("%s e=%s seq=%d", dnsRootPrefix, hash, seq)
+}
+
+// MakeDNSTreeRecords builds the TXT records serving the libp2p addresses, signed with the key of the tree.
+// The records are keyed by their name, the root record being at the domain itself
+func MakeDNSTreeRecords(key *ecdsa.PrivateKey, domain string, seq uint64, addrs []string) (map[string]string, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("the dns tree has no addresses")
+	}
+
+	records := map[string]string{}
+
+	hashes := []string{}
+	for _, addr := range addrs {
+		if _, err := StringToAddrInfo(addr); err != nil {
+			return nil, fmt.Errorf("invalid address %s: %v", addr, err)
+		}
+		leaf := dnsLeafPrefix + addr
+		hash := dnsRecordHash(leaf)
+
+		records[hash+"."+domain] = leaf
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	// build the branches bottom up, until a single one is left
+	for {
+		branches := []string{}
+		for i := 0; i < len(hashes); i += dnsMaxBranchChildren {
+			end := i + dnsMaxBranchChildren
+			if end > len(hashes) {
+				end = len(hashes)
+			}
+			branch := dnsBranchPrefix + strings.Join(hashes[i:end], ",")
+			hash := dnsRecordHash(branch)
+
+			records[hash+"."+domain] = branch
+			branches = append(branches, hash)
+		}
+		hashes = branches
+
+		if len(hashes) <= 1 {
+			break
+		}
+	}
+
+	content := dnsRootContent(hashes[0], seq)
+	sig, err := crypto.Sign(key, crypto.Keccak256([]byte(content)))
+	if err != nil {
+		return nil, err
+	}
+	records[domain] = content + " sig=" + base64.RawURLEncoding.EncodeToString(sig)
+
+	return records, nil
+}
+
+// txtResolver resolves the TXT records of a name, as net.Resolver does
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// dnsTreeState is the last resolution of a tree
+type dnsTreeState struct {
+	seq   uint64
+	nodes []*peer.AddrInfo
+}
+
+// dnsDiscovery periodically resolves the DNS trees, and adds their nodes to the bootnodes
+type dnsDiscovery struct {
+	srv      *Server
+	resolver txtResolver
+	trees    []*DNSTree
+	interval time.Duration
+
+	states     map[string]*dnsTreeState
+	statesLock sync.Mutex
+}
+
+func newDNSDiscovery(srv *Server, trees []*DNSTree) *dnsDiscovery {
+	return &dnsDiscovery{
+		srv:      srv,
+		resolver: net.DefaultResolver,
+		trees:    trees,
+		interval: DefaultDNSDiscoveryInterval,
+		states:   map[string]*dnsTreeState{},
+	}
+}
+
+// lookup returns the single TXT record of the name
+func (d *dnsDiscovery) lookup(ctx context.Context, name string) (string, error) {
+	txts, err := d.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(txts) != 1 {
+		return "", fmt.Errorf("expected one TXT record at %s, found %d", name, len(txts))
+	}
+
+	return txts[0], nil
+}
+
+// resolveRoot returns the top branch hash and the sequence number of the tree,
+// checking the signature of the root record
+func (d *dnsDiscovery) resolveRoot(ctx context.Context, tree *DNSTree) (string, uint64, error) {
+	record, err := d.lookup(ctx, tree.Domain)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var hash, sig string
+	var seq uint64
+	fields := strings.Fields(record)
+	if len(fields) != 4 || fields[0] != dnsRootPrefix {
+		return "", 0, fmt.Errorf("invalid root record %q", record)
+	}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return "", 0, fmt.Errorf("invalid root record %q", record)
+		}
+		switch kv[0] {
+		case "e":
+			hash = kv[1]
+		case "seq":
+			if seq, err = strconv.ParseUint(kv[1], 10, 64); err != nil {
+				return "", 0, fmt.Errorf("invalid root sequence: %v", err)
+			}
+		case "sig":
+			sig = kv[1]
+		}
+	}
+
+	rawSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || len(rawSig) != 65 {
+		return "", 0, errors.New("invalid root signature")
+	}
+	pub, err := crypto.RecoverPubkey(rawSig, crypto.Keccak256([]byte(dnsRootContent(hash, seq))))
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid root signature: %v", err)
+	}
+	if pub.X.Cmp(tree.PubKey.X) != 0 || pub.Y.Cmp(tree.PubKey.Y) != 0 {
+		return "", 0, errors.New("root record not signed by the key of the tree")
+	}
+
+	return hash, seq, nil
+}
+
+// resolveTree returns the nodes of the tree and its sequence number.
+// The tree is not walked again if its sequence number didn't change
+func (d *dnsDiscovery) resolveTree(ctx context.Context, tree *DNSTree) ([]*peer.AddrInfo, uint64, error) {
+	top, seq, err := d.resolveRoot(ctx, tree)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d.statesLock.Lock()
+	state, ok := d.states[tree.Domain]
+	d.statesLock.Unlock()
+
+	if ok && state.seq == seq {
+		return state.nodes, seq, nil
+	}
+
+	nodes := []*peer.AddrInfo{}
+	queue := []string{top}
+	visited := map[string]struct{}{}
+
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+
+		if _, ok := visited[hash]; ok {
+			continue
+		}
+		visited[hash] = struct{}{}
+		if len(visited) > dnsMaxEntries {
+			return nil, 0, errDNSTreeTooLarge
+		}
+
+		record, err := d.lookup(ctx, hash+"."+tree.Domain)
+		if err != nil {
+			return nil, 0, err
+		}
+		if dnsRecordHash(record) != hash {
+			return nil, 0, fmt.Errorf("record at %s.%s doesn't match its hash", hash, tree.Domain)
+		}
+
+		switch {
+		case strings.HasPrefix(record, dnsBranchPrefix):
+			children := strings.TrimPrefix(record, dnsBranchPrefix)
+			if children != "" {
+				queue = append(queue, strings.Split(children, ",")...)
+			}
+
+		case strings.HasPrefix(record, dnsLeafPrefix):
+			node, err := StringToAddrInfo(strings.TrimPrefix(record, dnsLeafPrefix))
+			if err != nil {
+				d.srv.logger.Warn("Omitting invalid dns tree node", "domain", tree.Domain, "err", err)
+				continue
+			}
+			nodes = append(nodes, node)
+
+		default:
+			return nil, 0, fmt.Errorf("unknown record %q", record)
+		}
+	}
+
+	d.statesLock.Lock()
+	d.states[tree.Domain] = &dnsTreeState{seq: seq, nodes: nodes}
+	d.statesLock.Unlock()
+
+	return nodes, seq, nil
+}
+
+// refresh resolves the trees, and returns their nodes. The nodes of a tree
+// failing to resolve are the ones of its last resolution
+func (d *dnsDiscovery) refresh() []*peer.AddrInfo {
+	nodes := []*peer.AddrInfo{}
+	for _, tree := range d.trees {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		treeNodes, seq, err := d.resolveTree(ctx, tree)
+		cancel()
+
+		if err != nil {
+			d.srv.logger.Error("failed to resolve dns tree", "domain", tree.Domain, "err", err)
+
+			d.statesLock.Lock()
+			if state, ok := d.states[tree.Domain]; ok {
+				treeNodes = state.nodes
+			}
+			d.statesLock.Unlock()
+		} else {
+			d.srv.logger.Debug("resolved dns tree", "domain", tree.Domain, "seq", seq, "nodes", len(treeNodes))
+		}
+
+		nodes = append(nodes, treeNodes...)
+	}
+
+	return nodes
+}
+
+// run refreshes the trees periodically, and dials their new nodes
+func (d *dnsDiscovery) run() {
+	for {
+		bootnodes := []*peer.AddrInfo{}
+		for _, node := range d.refresh() {
+			if node.ID == d.srv.host.ID() {
+				continue
+			}
+			d.srv.host.Peerstore().AddAddrs(node.ID, node.Addrs, peerstore.AddressTTL)
+			if !d.srv.hasPeer(node.ID) {
+				d.srv.dialQueue.add(node, 10)
+			}
+			bootnodes = append(bootnodes, node)
+		}
+		d.srv.discovery.setDNSBootnodes(bootnodes)
+
+		select {
+		case <-time.After(d.interval):
+		case <-d.srv.closeCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/hashicorp/go-hclog"
+	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// mapResolver serves the TXT records of a map
+type mapResolver map[string]string
+
+func (m mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	record, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("no such host %s", name)
+	}
+	return []string{record}, nil
+}
+
+func newTestDNSTree(t *testing.T, num int) ([]string, []string) {
+	t.Helper()
+
+	addrs, ids := []string{}, []string{}
+	for i := 0; i < num; i++ {
+		key, _, err := libp2pCrypto.GenerateKeyPair(libp2pCrypto.Secp256k1, 256)
+		assert.NoError(t, err)
+		id, err := peer.IDFromPrivateKey(key)
+		assert.NoError(t, err)
+
+		addrs = append(addrs, fmt.Sprintf("/ip4/10.0.0.%d/tcp/1478/p2p/%s", i, id))
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+
+	return addrs, ids
+}
+
+func nodeIDs(nodes []*peer.AddrInfo) []string {
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.ID.String())
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func TestDNSTree_URL(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	tree := &DNSTree{PubKey: &key.PublicKey, Domain: "nodes.example.org"}
+	parsed, err := ParseDNSTreeURL(tree.URL())
+	assert.NoError(t, err)
+	assert.Equal(t, tree.Domain, parsed.Domain)
+	assert.Equal(t, crypto.PubKeyToAddress(&key.PublicKey), crypto.PubKeyToAddress(parsed.PubKey))
+
+	for _, url := range []string{
+		"nodes.example.org",
+		"enrtree://nodes.example.org",
+		"enrtree://AAAA@nodes.example.org",
+	} {
+		_, err := ParseDNSTreeURL(url)
+		assert.Error(t, err, url)
+	}
+}
+
+func TestDNSDiscovery_ResolveTree(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tree := &DNSTree{PubKey: &key.PublicKey, Domain: "nodes.example.org"}
+
+	// enough nodes for two levels of branches
+	addrs, ids := newTestDNSTree(t, 30)
+	records, err := MakeDNSTreeRecords(key, tree.Domain, 1, addrs)
+	assert.NoError(t, err)
+
+	d := newDNSDiscovery(&Server{logger: hclog.NewNullLogger()}, []*DNSTree{tree})
+	d.resolver = mapResolver(records)
+
+	nodes, seq, err := d.resolveTree(context.Background(), tree)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), seq)
+	assert.Equal(t, ids, nodeIDs(nodes))
+
+	// the tree isn't walked again while the sequence number is the same
+	d.resolver = mapResolver{tree.Domain: records[tree.Domain]}
+	nodes, _, err = d.resolveTree(context.Background(), tree)
+	assert.NoError(t, err)
+	assert.Equal(t, ids, nodeIDs(nodes))
+
+	// and is replaced by the new sequence
+	newAddrs, newIDs := newTestDNSTree(t, 3)
+	records, err = MakeDNSTreeRecords(key, tree.Domain, 2, newAddrs)
+	assert.NoError(t, err)
+	d.resolver = mapResolver(records)
+	assert.Equal(t, newIDs, nodeIDs(d.refresh()))
+
+	// the nodes of the last resolution are kept when the tree fails to resolve
+	d.resolver = mapResolver{}
+	assert.Equal(t, newIDs, nodeIDs(d.refresh()))
+}
+
+func TestDNSDiscovery_InvalidTree(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+
+	addrs, _ := newTestDNSTree(t, 3)
+
+	resolve := func(tree *DNSTree, records map[string]string) error {
+		d := newDNSDiscovery(&Server{logger: hclog.NewNullLogger()}, []*DNSTree{tree})
+		d.resolver = mapResolver(records)
+
+		_, _, err := d.resolveTree(context.Background(), tree)
+		return err
+	}
+
+	records, err := MakeDNSTreeRecords(key, "nodes.example.org", 1, addrs)
+	assert.NoError(t, err)
+
+	// the root is signed by another key
+	assert.Error(t, resolve(&DNSTree{PubKey: &otherKey.PublicKey, Domain: "nodes.example.org"}, records))
+
+	// a record doesn't match its hash
+	tree := &DNSTree{PubKey: &key.PublicKey, Domain: "nodes.example.org"}
+	assert.NoError(t, resolve(tree, records))
+
+	for name, record := range records {
+		if name != tree.Domain && strings.HasPrefix(record, dnsLeafPrefix) {
+			records[name] = dnsLeafPrefix + addrs[0]
+			if record == records[name] {
+				records[name] = dnsLeafPrefix + addrs[1]
+			}
+			break
+		}
+	}
+	assert.Error(t, resolve(tree, records))
+
+	// a tree needs nodes
+	_, err = MakeDNSTreeRecords(key, "nodes.example.org", 1, nil)
+	assert.Error(t, err)
+}
@@ -5,16 +5,21 @@ import (
 	"reflect"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"google.golang.org/protobuf/proto"
 )
 
 type Topic struct {
 	logger hclog.Logger
+	srv    *Server
 
 	topic   *pubsub.Topic
 	typ     reflect.Type
 	closeCh chan struct{}
+
+	// maxSize is the maximum size of the received messages, once decompressed
+	maxSize int
 }
 
 func (t *Topic) createObj() proto.Message {
@@ -27,7 +32,7 @@ func (t *Topic) Publish(obj proto.Message) error {
 		return err
 	}
 
-	return t.topic.Publish(context.Background(), data)
+	return t.topic.Publish(context.Background(), t.srv.encodeGossip(data))
 }
 
 func (t *Topic) Subscribe(handler func(obj interface{})) error {
@@ -55,9 +60,17 @@ func (t *Topic) readLoop(sub *pubsub.Subscription, handler func(obj interface{})
 			continue
 		}
 
+		data, err := decodeGossip(msg.Data, t.maxSize)
+		if err != nil {
+			t.logger.Error("failed to decode topic", "err", err)
+			t.srv.ReportFault(msg.ReceivedFrom, FaultProtocolViolation)
+			continue
+		}
+
 		obj := t.createObj()
-		if err := proto.Unmarshal(msg.Data, obj); err != nil {
+		if err := proto.Unmarshal(data, obj); err != nil {
 			t.logger.Error("failed to unmarshal topic", "err", err)
+			t.srv.ReportFault(msg.ReceivedFrom, FaultProtocolViolation)
 			continue
 		}
 		handler(obj)
@@ -65,15 +78,28 @@ func (t *Topic) readLoop(sub *pubsub.Subscription, handler func(obj interface{})
 }
 
 func (s *Server) NewTopic(protoID string, obj proto.Message) (*Topic, error) {
+	if s.permissioned() {
+		// in a permissioned network the messages are only accepted from the peers
+		// that completed the handshake, which checks their allowlisting and certificate
+		validator := func(ctx context.Context, id peer.ID, msg *pubsub.Message) bool {
+			return id == s.host.ID() || s.hasPeer(id)
+		}
+		if err := s.ps.RegisterTopicValidator(protoID, validator); err != nil {
+			return nil, err
+		}
+	}
+
 	topic, err := s.ps.Join(protoID)
 	if err != nil {
 		return nil, err
 	}
 
 	tt := &Topic{
-		logger: s.logger.Named(protoID),
-		topic:  topic,
-		typ:    reflect.TypeOf(obj).Elem(),
+		logger:  s.logger.Named(protoID),
+		srv:     s,
+		topic:   topic,
+		typ:     reflect.TypeOf(obj).Elem(),
+		maxSize: s.MaxMessageSize(protoID),
 	}
 
 	return tt, nil
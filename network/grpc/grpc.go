@@ -20,11 +20,12 @@ type GrpcStream struct {
 	grpcServer *grpc.Server
 }
 
-func NewGrpcStream() *GrpcStream {
+// NewGrpcStream creates a grpc server served on libp2p streams, with the options on top of the defaults
+func NewGrpcStream(opts ...grpc.ServerOption) *GrpcStream {
 	g := &GrpcStream{
 		ctx:        context.Background(),
 		streamCh:   make(chan network.Stream),
-		grpcServer: grpc.NewServer(grpc.UnaryInterceptor(interceptor)),
+		grpcServer: grpc.NewServer(append([]grpc.ServerOption{grpc.UnaryInterceptor(interceptor)}, opts...)...),
 	}
 
 	return g
@@ -103,11 +104,12 @@ func (g *GrpcStream) Close() error {
 
 // --- conn ---
 
-func WrapClient(s network.Stream) *grpc.ClientConn {
+// WrapClient creates a grpc client on the libp2p stream, with the default call options
+func WrapClient(s network.Stream, callOpts ...grpc.CallOption) *grpc.ClientConn {
 	opts := grpc.WithContextDialer(func(ctx context.Context, peerIdStr string) (net.Conn, error) {
 		return &streamConn{s}, nil
 	})
-	conn, err := grpc.Dial("", grpc.WithInsecure(), opts)
+	conn, err := grpc.Dial("", grpc.WithInsecure(), opts, grpc.WithDefaultCallOptions(callOpts...))
 	if err != nil {
 		// TODO: this should not fail at all
 		panic(err)
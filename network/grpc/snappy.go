@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+)
+
+// Snappy is the name of the snappy compressor of the grpc streams
+const Snappy = "snappy"
+
+func init() {
+	encoding.RegisterCompressor(&snappyCompressor{})
+}
+
+// snappyCompressor compresses the grpc messages with the snappy framing format
+type snappyCompressor struct{}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+func (c *snappyCompressor) Name() string {
+	return Snappy
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	rawGrpc "google.golang.org/grpc"
 
@@ -23,6 +24,9 @@ type identity struct {
 	pending     sync.Map
 	pendingSize int64
 
+	// members are the peers whose membership was verified by a handshake, in either direction
+	members sync.Map
+
 	srv *Server
 }
 
@@ -50,6 +54,21 @@ func (i *identity) setPending(id peer.ID) {
 	}
 }
 
+// isMember checks if the membership of the peer was verified, always true if the network is not permissioned
+func (i *identity) isMember(id peer.ID) bool {
+	if !i.srv.permissioned() {
+		return true
+	}
+
+	_, ok := i.members.Load(id)
+
+	return ok
+}
+
+func (i *identity) delMember(id peer.ID) {
+	i.members.Delete(id)
+}
+
 func (i *identity) setup() {
 	// register the protobuf protocol
 	grpc := grpc.NewGrpcStream()
@@ -64,6 +83,10 @@ func (i *identity) setup() {
 			peerID := conn.RemotePeer()
 			i.srv.logger.Trace("Conn", "peer", peerID, "direction", conn.Stat().Direction)
 
+			if i.srv.IsBanned(peerID) {
+				i.srv.Disconnect(peerID, "banned")
+				return
+			}
 			// limit by MaxPeers on incomming requests since we already limit
 			// the outgoing requests
 			if conn.Stat().Direction == network.DirInbound {
@@ -71,7 +94,7 @@ func (i *identity) setup() {
 					// handshake has already started
 					return
 				}
-				if i.srv.numOpenSlots() == 0 {
+				if i.srv.numOpenSlots() == 0 && !i.srv.IsTrusted(peerID) {
 					i.srv.Disconnect(peerID, "no available slots")
 					return
 				}
@@ -100,9 +123,39 @@ func (i *identity) setup() {
 }
 
 func (i *identity) getStatus() *proto.Status {
-	return &proto.Status{
-		Chain: int64(i.srv.config.Chain.Params.ChainID),
+	status := &proto.Status{
+		Chain:           int64(i.srv.config.Chain.Params.ChainID),
+		MaxMessageSizes: map[string]uint64{},
+	}
+	if i.srv.config.Compression {
+		status.Compression = []string{grpc.Snappy}
 	}
+	for _, id := range i.srv.protocolIDs() {
+		status.MaxMessageSizes[id] = uint64(i.srv.MaxMessageSize(id))
+	}
+	if consortium := i.srv.config.Consortium; consortium != nil {
+		status.Certificate = consortium.Certificate
+	}
+
+	return status
+}
+
+// verifyMembership checks that the peer is allowlisted and holds a consortium certificate,
+// if the network is permissioned. The protocols other than the handshake are only served to the members
+func (i *identity) verifyMembership(peerID peer.ID, status *proto.Status) error {
+	if !i.srv.IsAllowed(peerID) {
+		return ErrNotAllowlisted
+	}
+
+	if consortium := i.srv.config.Consortium; consortium != nil {
+		if err := consortium.verify(peerID, status.Certificate, time.Now()); err != nil {
+			return fmt.Errorf("peer is not a member of the consortium: %v", err)
+		}
+	}
+
+	i.members.Store(peerID, struct{}{})
+
+	return nil
 }
 
 func (i *identity) handleConnected(peerID peer.ID) error {
@@ -121,14 +174,24 @@ func (i *identity) handleConnected(peerID peer.ID) error {
 
 	// validation
 	if status.Chain != resp.Chain {
+		i.srv.ReportFault(peerID, FaultProtocolViolation)
 		return fmt.Errorf("incorrect chain id")
 	}
+	if err := i.verifyMembership(peerID, resp); err != nil {
+		i.srv.ReportFault(peerID, FaultProtocolViolation)
+		return err
+	}
 
-	i.srv.addPeer(peerID)
+	i.srv.addPeer(peerID, resp)
 	return nil
 }
 
 func (i *identity) Hello(ctx context.Context, req *proto.Status) (*proto.Status, error) {
+	// the status of a permissioned network is only shared with the members
+	if err := i.verifyMembership(ctx.(*grpc.Context).PeerID, req); err != nil {
+		return nil, err
+	}
+
 	return i.getStatus(), nil
 }
 
@@ -0,0 +1,57 @@
+package network
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the network metrics
+type Metrics struct {
+	// Bytes sent on the streams, by protocol
+	SentBytes metrics.Counter
+	// Bytes received on the streams, by protocol
+	ReceivedBytes metrics.Counter
+	// No.of connected peers, over the public and the validator networks
+	Peers metrics.Gauge
+}
+
+// GetPrometheusMetrics return the network metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		SentBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "sent_bytes",
+			Help:      "Bytes sent on the streams, by protocol.",
+		}, append(labels, "protocol")).With(labelsWithValues...),
+		ReceivedBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "received_bytes",
+			Help:      "Bytes received on the streams, by protocol.",
+		}, append(labels, "protocol")).With(labelsWithValues...),
+		Peers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "peers",
+			Help:      "Number of connected peers.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational network metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		SentBytes:     discard.NewCounter(),
+		ReceivedBytes: discard.NewCounter(),
+		Peers:         discard.NewGauge(),
+	}
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/event"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// ReachabilityPublic forces the node to advertise its addresses as dialable
+	ReachabilityPublic = "public"
+
+	// ReachabilityPrivate forces the node to reserve addresses on its relays
+	ReachabilityPrivate = "private"
+
+	// relayOptHop is the circuit.OptHop option of the relay transport, relaying the connections of the other peers
+	relayOptHop = 1
+)
+
+// NATConfig configures how a node behind a NAT makes itself dialable by its peers.
+// The reachability of the node is detected by AutoNAT, with the help of the peers running the AutoNAT service
+type NATConfig struct {
+	// PortMap maps the listening port on the gateway with UPnP or NAT-PMP,
+	// and advertises the external address of the mapping
+	PortMap bool
+
+	// Reachability overrides the detected reachability, public or private. Empty to detect it
+	Reachability string
+
+	// Service answers the dial back requests of the peers detecting their reachability.
+	// It is meant for the nodes with a public address
+	Service bool
+
+	// Relays are the circuit relays the node reserves a relayed address on, once it is detected as private
+	Relays []*peer.AddrInfo
+
+	// RelayHop relays the connections to the private peers. It is meant for the nodes with a public address
+	RelayHop bool
+}
+
+// options returns the libp2p options of the NAT traversal
+func (c *NATConfig) options() ([]libp2p.Option, error) {
+	opts := []libp2p.Option{}
+	if c == nil {
+		return opts, nil
+	}
+
+	if c.PortMap {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	if c.Service {
+		opts = append(opts, libp2p.EnableNATService())
+	}
+
+	switch c.Reachability {
+	case "":
+	case ReachabilityPublic:
+		opts = append(opts, libp2p.ForceReachabilityPublic())
+	case ReachabilityPrivate:
+		opts = append(opts, libp2p.ForceReachabilityPrivate())
+	default:
+		return nil, fmt.Errorf("reachability %s is not public or private", c.Reachability)
+	}
+
+	if c.RelayHop {
+		opts = append(opts, libp2p.EnableRelay(relayOptHop))
+	}
+	if len(c.Relays) != 0 {
+		if c.RelayHop {
+			return nil, fmt.Errorf("a relay can't use other relays")
+		}
+
+		relays := make([]peer.AddrInfo, 0, len(c.Relays))
+		for _, relay := range c.Relays {
+			relays = append(relays, *relay)
+		}
+		opts = append(opts, libp2p.EnableAutoRelay(), libp2p.StaticRelays(relays))
+	}
+
+	return opts, nil
+}
+
+// watchReachability tracks the reachability of the node detected by AutoNAT
+func (s *Server) watchReachability() error {
+	sub, err := s.host.EventBus().Subscribe(new(event.EvtLocalReachabilityChanged))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer sub.Close()
+
+		for {
+			select {
+			case evnt, ok := <-sub.Out():
+				if !ok {
+					return
+				}
+
+				reachability := evnt.(event.EvtLocalReachabilityChanged).Reachability
+				atomic.StoreInt32(&s.reachability, int32(reachability))
+
+				s.logger.Info("reachability changed", "reachability", reachability, "addrs", s.host.Addrs())
+			case <-s.closeCh:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Reachability returns the reachability of the node detected by AutoNAT, or forced by the config
+func (s *Server) Reachability() network.Reachability {
+	return network.Reachability(atomic.LoadInt32(&s.reachability))
+}
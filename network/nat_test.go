@@ -0,0 +1,50 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNATConfig_Options(t *testing.T) {
+	relay := &peer.AddrInfo{ID: peer.ID("relay")}
+
+	cases := []struct {
+		name   string
+		config *NATConfig
+		opts   int
+		err    bool
+	}{
+		{"disabled", nil, 0, false},
+		{"port map", &NATConfig{PortMap: true}, 1, false},
+		{"public service", &NATConfig{Service: true, Reachability: ReachabilityPublic, RelayHop: true}, 3, false},
+		{"private with relays", &NATConfig{Reachability: ReachabilityPrivate, Relays: []*peer.AddrInfo{relay}}, 3, false},
+		{"unknown reachability", &NATConfig{Reachability: "unknown"}, 0, true},
+		{"relay with relays", &NATConfig{RelayHop: true, Relays: []*peer.AddrInfo{relay}}, 0, true},
+	}
+
+	for _, c := range cases {
+		opts, err := c.config.options()
+		if c.err {
+			assert.Error(t, err, c.name)
+			continue
+		}
+		assert.NoError(t, err, c.name)
+		assert.Len(t, opts, c.opts, c.name)
+	}
+}
+
+func TestServer_Reachability(t *testing.T) {
+	srv := CreateServer(t, func(c *Config) {
+		c.NAT = &NATConfig{Reachability: ReachabilityPrivate}
+	})
+	defer srv.Close()
+
+	// the forced reachability is reported once the node starts
+	assert.Eventually(t, func() bool {
+		return srv.Reachability() == network.ReachabilityPrivate
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,103 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/version"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+)
+
+const (
+	// peerPingInterval is how often the latency of the peers is measured
+	peerPingInterval = 30 * time.Second
+
+	// peerPingTimeout is how long a ping is waited for
+	peerPingTimeout = 10 * time.Second
+)
+
+// UserAgent is the client version the node announces to its peers
+var UserAgent = "polygon-sdk/" + version.Version
+
+// PeerStatus is the status of the connection with a peer
+type PeerStatus struct {
+	Info      peer.AddrInfo
+	Protocols []string
+
+	// Direction is whether the peer dialed the node, unknown if it isn't connected
+	Direction network.Direction
+
+	// Latency is the moving average of the round trips of the pings, 0 until measured
+	Latency time.Duration
+
+	// ClientVersion is the user agent announced by the peer, empty until identified
+	ClientVersion string
+}
+
+// PeerStatus returns the status of the connection with the peer
+func (s *Server) PeerStatus(id peer.ID) (*PeerStatus, error) {
+	protocols, err := s.GetProtocols(id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &PeerStatus{
+		Info:      s.GetPeerInfo(id),
+		Protocols: protocols,
+		Direction: network.DirUnknown,
+		Latency:   s.host.Peerstore().LatencyEWMA(id),
+	}
+	if conns := s.host.Network().ConnsToPeer(id); len(conns) != 0 {
+		status.Direction = conns[0].Stat().Direction
+	}
+	if agent, err := s.host.Peerstore().Get(id, "AgentVersion"); err == nil {
+		status.ClientVersion, _ = agent.(string)
+	}
+
+	return status, nil
+}
+
+// Topology returns the peers each connected peer reported to the discovery, the local view
+// of the network beyond the peers of the node. Empty if the discovery is disabled
+func (s *Server) Topology() map[peer.ID][]peer.ID {
+	if s.discovery == nil {
+		return map[peer.ID][]peer.ID{}
+	}
+
+	return s.discovery.reportedPeers()
+}
+
+// runPeerPing measures the latency of the connected peers
+func (s *Server) runPeerPing() {
+	for {
+		select {
+		case <-time.After(peerPingInterval):
+		case <-s.closeCh:
+			return
+		}
+
+		for _, p := range s.Peers() {
+			go func(id peer.ID) {
+				if err := s.pingPeer(id); err != nil {
+					s.logger.Debug("failed to ping peer", "id", id, "err", err)
+				}
+			}(p.Info.ID)
+		}
+	}
+}
+
+// pingPeer pings the peer once, the round trip is recorded in the peerstore
+func (s *Server) pingPeer(id peer.ID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), peerPingTimeout)
+	defer cancel()
+
+	res, ok := <-ping.Ping(ctx, s.host, id)
+	if !ok {
+		return fmt.Errorf("ping timed out")
+	}
+
+	return res.Error
+}
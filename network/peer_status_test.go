@@ -0,0 +1,54 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerStatus(t *testing.T) {
+	conf := func(c *Config) {
+		c.NoDiscover = true
+	}
+
+	srv0 := CreateServer(t, conf)
+	srv1 := CreateServer(t, conf)
+
+	defer func() {
+		srv0.Close()
+		srv1.Close()
+	}()
+
+	assert.NoError(t, srv1.Join(srv0.AddrInfo(), 10*time.Second))
+
+	id0, id1 := srv0.AddrInfo().ID, srv1.AddrInfo().ID
+
+	// the direction is the one of the dial
+	status, err := srv0.PeerStatus(id1)
+	assert.NoError(t, err)
+	assert.Equal(t, network.DirInbound, status.Direction)
+	assert.NotEmpty(t, status.Protocols)
+
+	status, err = srv1.PeerStatus(id0)
+	assert.NoError(t, err)
+	assert.Equal(t, network.DirOutbound, status.Direction)
+
+	// the client version is announced by the identify protocol
+	assert.Eventually(t, func() bool {
+		status, err := srv1.PeerStatus(id0)
+
+		return err == nil && status.ClientVersion == UserAgent
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// the latency is known once pinged
+	assert.NoError(t, srv1.pingPeer(id0))
+
+	status, err = srv1.PeerStatus(id0)
+	assert.NoError(t, err)
+	assert.NotZero(t, status.Latency)
+
+	// the topology is empty without discovery
+	assert.Empty(t, srv0.Topology())
+}
@@ -0,0 +1,326 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+)
+
+const (
+	// peersFile is the file of the data dir persisting the known peers across restarts
+	peersFile = "peers.json"
+
+	// maxKnownPeers is the maximum number of persisted peers
+	maxKnownPeers = 100
+
+	// peersSaveInterval is the interval of the persistence of the known peers
+	peersSaveInterval = 5 * time.Minute
+
+	// staticDialInterval is the interval of the redial of the disconnected static peers
+	staticDialInterval = 30 * time.Second
+)
+
+// staticRedialDelay is the minimum delay between two redials of the static peers,
+// so a static peer dropping the connection right away is not redialed in a loop
+var staticRedialDelay = time.Second
+
+var errSelfPeer = errors.New("the node can't be its own peer")
+
+// StringToPeerID parses a peer id, or the id of a libp2p address
+func StringToPeerID(raw string) (peer.ID, error) {
+	if strings.HasPrefix(raw, "/") {
+		info, err := StringToAddrInfo(raw)
+		if err != nil {
+			return "", err
+		}
+		return info.ID, nil
+	}
+
+	return peer.Decode(raw)
+}
+
+// setupPeerSets sets the static and trusted peers of the config
+func (s *Server) setupPeerSets() {
+	s.staticPeers = map[peer.ID]*peer.AddrInfo{}
+	s.trustedPeers = map[peer.ID]struct{}{}
+	s.staticDialCh = make(chan struct{}, 1)
+
+	for _, info := range s.config.StaticPeers {
+		if err := s.AddStaticPeer(info); err != nil {
+			s.logger.Warn("Omitting static peer", "id", info.ID, "err", err)
+		}
+	}
+	for _, id := range s.config.TrustedPeers {
+		s.AddTrustedPeer(id)
+	}
+}
+
+// AddStaticPeer connects to the peer and keeps it connected, redialing it when the connection is lost.
+// The static peers are exempt from the MaxPeers limit
+func (s *Server) AddStaticPeer(info *peer.AddrInfo) error {
+	if info.ID == s.host.ID() {
+		return errSelfPeer
+	}
+
+	s.peerSetsLock.Lock()
+	s.staticPeers[info.ID] = info
+	s.peerSetsLock.Unlock()
+
+	s.host.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
+	s.notifyStaticDial()
+
+	return nil
+}
+
+// RemoveStaticPeer stops redialing the peer, and disconnects it
+func (s *Server) RemoveStaticPeer(id peer.ID) {
+	s.peerSetsLock.Lock()
+	_, ok := s.staticPeers[id]
+	delete(s.staticPeers, id)
+	s.peerSetsLock.Unlock()
+
+	if ok {
+		s.host.Peerstore().UpdateAddrs(id, peerstore.PermanentAddrTTL, peerstore.AddressTTL)
+	}
+	s.Disconnect(id, "peer removed")
+}
+
+// AddTrustedPeer exempts the peer from the MaxPeers limit
+func (s *Server) AddTrustedPeer(id peer.ID) {
+	s.peerSetsLock.Lock()
+	defer s.peerSetsLock.Unlock()
+
+	s.trustedPeers[id] = struct{}{}
+}
+
+// RemoveTrustedPeer subjects the peer to the MaxPeers limit again. It is not disconnected
+func (s *Server) RemoveTrustedPeer(id peer.ID) {
+	s.peerSetsLock.Lock()
+	defer s.peerSetsLock.Unlock()
+
+	delete(s.trustedPeers, id)
+}
+
+// IsStatic checks if the peer is kept connected
+func (s *Server) IsStatic(id peer.ID) bool {
+	s.peerSetsLock.RLock()
+	defer s.peerSetsLock.RUnlock()
+
+	_, ok := s.staticPeers[id]
+
+	return ok
+}
+
+// IsTrusted checks if the peer is exempt from the MaxPeers limit, as the trusted and static peers are
+func (s *Server) IsTrusted(id peer.ID) bool {
+	s.peerSetsLock.RLock()
+	defer s.peerSetsLock.RUnlock()
+
+	if _, ok := s.trustedPeers[id]; ok {
+		return true
+	}
+	_, ok := s.staticPeers[id]
+
+	return ok
+}
+
+// numTrustedPeers returns the number of connected peers exempt from the MaxPeers limit
+func (s *Server) numTrustedPeers() int64 {
+	n := int64(0)
+	for _, p := range s.Peers() {
+		if s.IsTrusted(p.Info.ID) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (s *Server) notifyStaticDial() {
+	select {
+	case s.staticDialCh <- struct{}{}:
+	default:
+	}
+}
+
+// disconnectedStaticPeers returns the static peers to redial
+func (s *Server) disconnectedStaticPeers() []*peer.AddrInfo {
+	s.peerSetsLock.RLock()
+	defer s.peerSetsLock.RUnlock()
+
+	infos := []*peer.AddrInfo{}
+	for id, info := range s.staticPeers {
+		if !s.isConnected(id) {
+			infos = append(infos, info)
+		}
+	}
+
+	return infos
+}
+
+// runStaticDial dials the disconnected static peers, bypassing the dial queue
+// and its MaxPeers limit
+func (s *Server) runStaticDial() {
+	for {
+		for _, info := range s.disconnectedStaticPeers() {
+			s.logger.Debug("dial static peer", "id", info.ID)
+
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultJoinTimeout)
+			if err := s.host.Connect(ctx, *info); err != nil {
+				s.logger.Trace("failed to dial static peer", "id", info.ID, "err", err)
+			}
+			cancel()
+		}
+
+		select {
+		case <-time.After(staticRedialDelay):
+		case <-s.closeCh:
+			return
+		}
+
+		select {
+		case <-s.staticDialCh:
+		case <-time.After(staticDialInterval):
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// loadKnownPeers reads the peers persisted by the previous runs of the node
+func (s *Server) loadKnownPeers() ([]*peer.AddrInfo, error) {
+	if s.config.DataDir == "" {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(s.config.DataDir, peersFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []*peer.AddrInfo{}
+	if err := json.Unmarshal(data, &infos); err != nil {
+		return nil, err
+	}
+
+	known := []*peer.AddrInfo{}
+	for _, info := range infos {
+		if info.ID == s.host.ID() || len(info.Addrs) == 0 {
+			continue
+		}
+		known = append(known, info)
+	}
+
+	return known, nil
+}
+
+// setupKnownPeers adds the persisted peers to the peerstore, and dials them if the discovery is enabled
+func (s *Server) setupKnownPeers() {
+	known, err := s.loadKnownPeers()
+	if err != nil {
+		s.logger.Warn("failed to load the known peers", "err", err)
+	}
+
+	s.knownPeersLock.Lock()
+	s.knownPeers = known
+	s.knownPeersLock.Unlock()
+
+	for _, info := range known {
+		s.host.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.AddressTTL)
+		if !s.config.NoDiscover {
+			s.dialQueue.add(info, 10)
+		}
+	}
+}
+
+// randomKnownPeer returns one of the known peers, if any
+func (s *Server) randomKnownPeer() *peer.AddrInfo {
+	s.knownPeersLock.Lock()
+	defer s.knownPeersLock.Unlock()
+
+	if len(s.knownPeers) == 0 {
+		return nil
+	}
+
+	return s.knownPeers[rand.Intn(len(s.knownPeers))]
+}
+
+// saveKnownPeers persists the connected peers, followed by the ones known from before
+// up to maxKnownPeers
+func (s *Server) saveKnownPeers() error {
+	if s.config.DataDir == "" {
+		return nil
+	}
+
+	known := []*peer.AddrInfo{}
+	connected := map[peer.ID]struct{}{}
+	for _, p := range s.Peers() {
+		info := s.host.Peerstore().PeerInfo(p.Info.ID)
+		if len(info.Addrs) == 0 {
+			continue
+		}
+		known = append(known, &info)
+		connected[info.ID] = struct{}{}
+	}
+
+	s.knownPeersLock.Lock()
+	for _, info := range s.knownPeers {
+		if _, ok := connected[info.ID]; !ok {
+			known = append(known, info)
+		}
+	}
+	if len(known) > maxKnownPeers {
+		known = known[:maxKnownPeers]
+	}
+	s.knownPeers = known
+	s.knownPeersLock.Unlock()
+
+	data, err := json.Marshal(known)
+	if err != nil {
+		return err
+	}
+
+	return writeDataFile(s.config.DataDir, peersFile, data)
+}
+
+// writeDataFile writes the file of the data dir, through a temporary file
+// so a crash doesn't leave a truncated file
+func writeDataFile(dataDir, name string, data []byte) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dataDir, name)
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// runKnownPeersSave persists the known peers periodically
+func (s *Server) runKnownPeersSave() {
+	for {
+		select {
+		case <-time.After(peersSaveInterval):
+		case <-s.closeCh:
+			return
+		}
+
+		if err := s.saveKnownPeers(); err != nil {
+			s.logger.Error("failed to save the known peers", "err", err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringToPeerID(t *testing.T) {
+	srv := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+	defer srv.Close()
+
+	id := srv.AddrInfo().ID
+
+	fromAddr, err := StringToPeerID(AddrInfoToString(srv.AddrInfo()))
+	assert.NoError(t, err)
+	assert.Equal(t, id, fromAddr)
+
+	fromID, err := StringToPeerID(id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id, fromID)
+
+	_, err = StringToPeerID("/ip4/127.0.0.1/tcp/1478")
+	assert.Error(t, err)
+}
+
+func TestTrustedPeers_ExemptFromLimit(t *testing.T) {
+	conf := func(c *Config) {
+		c.MaxPeers = 1
+		c.NoDiscover = true
+	}
+
+	srv0 := CreateServer(t, conf)
+	srv1 := CreateServer(t, conf)
+	srv2 := CreateServer(t, conf)
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1, srv2} {
+			srv.Close()
+		}
+	}()
+
+	// srv1 takes the only slot of srv0
+	assert.NoError(t, srv1.Join(srv0.AddrInfo(), 10*time.Second))
+
+	// srv2 is trusted by srv0, and accepted over the limit
+	srv0.AddTrustedPeer(srv2.AddrInfo().ID)
+	assert.NoError(t, srv2.Join(srv0.AddrInfo(), 10*time.Second))
+	assert.Equal(t, int64(2), srv0.numPeers())
+
+	// the trusted peer doesn't take a slot
+	assert.Equal(t, int64(0), srv0.numOpenSlots())
+	srv0.Disconnect(srv1.AddrInfo().ID, "bye")
+	assert.Eventually(t, func() bool {
+		return srv0.numOpenSlots() == 1
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestStaticPeers_Redial(t *testing.T) {
+	conf := func(c *Config) {
+		c.MaxPeers = 1
+		c.NoDiscover = true
+	}
+
+	srv0 := CreateServer(t, conf)
+	srv1 := CreateServer(t, conf)
+	srv2 := CreateServer(t, conf)
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1, srv2} {
+			srv.Close()
+		}
+	}()
+
+	// the slot of srv0 is taken
+	assert.NoError(t, srv0.Join(srv2.AddrInfo(), 10*time.Second))
+
+	// the static peer is dialed over the limit
+	connectedCh := asyncWaitForEvent(srv0, 10*time.Second, connectedPeerHandler(srv1.AddrInfo().ID))
+	assert.NoError(t, srv0.AddStaticPeer(srv1.AddrInfo()))
+	assert.True(t, <-connectedCh)
+	assert.True(t, srv0.IsStatic(srv1.AddrInfo().ID))
+	assert.True(t, srv0.IsTrusted(srv1.AddrInfo().ID))
+
+	// and redialed when the connection is lost
+	disconnectedCh := asyncWaitForEvent(srv0, 10*time.Second, disconnectedPeerHandler(srv1.AddrInfo().ID))
+	srv1.Disconnect(srv0.AddrInfo().ID, "bye")
+	assert.True(t, <-disconnectedCh)
+
+	connectedCh = asyncWaitForEvent(srv0, 10*time.Second, connectedPeerHandler(srv1.AddrInfo().ID))
+	assert.True(t, <-connectedCh)
+
+	// until it is removed
+	disconnectedCh = asyncWaitForEvent(srv0, 10*time.Second, disconnectedPeerHandler(srv1.AddrInfo().ID))
+	srv0.RemoveStaticPeer(srv1.AddrInfo().ID)
+	assert.True(t, <-disconnectedCh)
+	assert.False(t, srv0.IsStatic(srv1.AddrInfo().ID))
+
+	// the node can't be its own static peer
+	assert.Equal(t, errSelfPeer, srv0.AddStaticPeer(srv0.AddrInfo()))
+}
+
+func TestKnownPeers_Persistence(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "peers")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+
+	conf := func(c *Config) {
+		c.NoDiscover = true
+	}
+
+	srv0 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+		c.DataDir = dataDir
+	})
+	srv1 := CreateServer(t, conf)
+	srv2 := CreateServer(t, conf)
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1, srv2} {
+			srv.Close()
+		}
+	}()
+
+	assert.NoError(t, srv0.Join(srv1.AddrInfo(), 10*time.Second))
+	assert.NoError(t, srv0.saveKnownPeers())
+
+	// the peers known from before are kept after the connected ones
+	assert.NoError(t, srv0.Join(srv2.AddrInfo(), 10*time.Second))
+	srv0.Disconnect(srv1.AddrInfo().ID, "bye")
+	assert.Eventually(t, func() bool {
+		return !srv0.hasPeer(srv1.AddrInfo().ID)
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.NoError(t, srv0.saveKnownPeers())
+
+	known, err := srv0.loadKnownPeers()
+	assert.NoError(t, err)
+
+	ids := []peer.ID{}
+	for _, info := range known {
+		ids = append(ids, info.ID)
+		assert.NotEmpty(t, info.Addrs)
+	}
+	assert.Equal(t, []peer.ID{srv2.AddrInfo().ID, srv1.AddrInfo().ID}, ids)
+}
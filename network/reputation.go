@@ -0,0 +1,290 @@
+package network
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// PeerFault is a misbehavior of a peer, lowering its score
+type PeerFault string
+
+const (
+	// FaultProtocolViolation is a malformed message or a failed handshake validation
+	FaultProtocolViolation PeerFault = "protocol_violation"
+
+	// FaultBadBlock is an invalid block, or blocks not matching the requested range
+	FaultBadBlock PeerFault = "bad_block"
+
+	// FaultBadReceipts is a block whose receipts root or logs bloom doesn't match its execution
+	FaultBadReceipts PeerFault = "bad_receipts"
+
+	// FaultTimeout is a request left unanswered
+	FaultTimeout PeerFault = "timeout"
+)
+
+// faultPenalties are the scores deducted for the faults
+var faultPenalties = map[PeerFault]int64{
+	FaultProtocolViolation: 25,
+	FaultBadBlock:          50,
+	FaultBadReceipts:       75,
+	FaultTimeout:           5,
+}
+
+const (
+	// banScore is the score at which a peer is banned
+	banScore = -100
+
+	// banDuration is how long a banned peer is refused
+	banDuration = time.Hour
+
+	// scoreRecoveryInterval is the interval at which the negative scores recover by one point
+	scoreRecoveryInterval = time.Minute
+
+	// denylistFile is the file of the data dir persisting the banned peers across restarts
+	denylistFile = "denylist.json"
+)
+
+// PeerScore is the reputation of a peer
+type PeerScore struct {
+	ID     peer.ID
+	Score  int64
+	Faults map[PeerFault]uint64
+
+	// BannedUntil is the end of the ban of the peer, zero if it isn't banned
+	BannedUntil time.Time
+}
+
+func (p *PeerScore) copy() *PeerScore {
+	faults := make(map[PeerFault]uint64, len(p.Faults))
+	for fault, count := range p.Faults {
+		faults[fault] = count
+	}
+
+	return &PeerScore{ID: p.ID, Score: p.Score, Faults: faults, BannedUntil: p.BannedUntil}
+}
+
+func (p *PeerScore) isBanned(now time.Time) bool {
+	return !p.BannedUntil.IsZero() && now.Before(p.BannedUntil)
+}
+
+// deniedPeer is an entry of the persisted denylist
+type deniedPeer struct {
+	ID    peer.ID   `json:"id"`
+	Until time.Time `json:"until"`
+}
+
+// reputation scores the peers on their faults, and bans the ones falling to the ban score
+type reputation struct {
+	srv *Server
+
+	scores map[peer.ID]*PeerScore
+	lock   sync.Mutex
+}
+
+func newReputation(srv *Server) *reputation {
+	r := &reputation{
+		srv:    srv,
+		scores: map[peer.ID]*PeerScore{},
+	}
+
+	denied, err := r.loadDenylist()
+	if err != nil {
+		srv.logger.Warn("failed to load the denylist", "err", err)
+	}
+	now := time.Now()
+	for _, entry := range denied {
+		if now.Before(entry.Until) {
+			r.scores[entry.ID] = &PeerScore{
+				ID:          entry.ID,
+				Score:       banScore,
+				Faults:      map[PeerFault]uint64{},
+				BannedUntil: entry.Until,
+			}
+		}
+	}
+
+	return r
+}
+
+// ReportFault lowers the score of the peer for the fault, and bans the peer if its score
+// falls to the ban score. The trusted and static peers are scored but not banned.
+// It is a no-op on a nil server, for the subsystems running without a network
+func (s *Server) ReportFault(id peer.ID, fault PeerFault) {
+	if s == nil || id == s.host.ID() {
+		return
+	}
+
+	banned := s.reputation.report(id, fault, !s.IsTrusted(id))
+	if banned {
+		s.logger.Warn("Peer banned", "id", id, "fault", fault, "duration", banDuration)
+		s.Disconnect(id, "banned")
+	} else {
+		s.logger.Debug("Peer fault", "id", id, "fault", fault)
+	}
+}
+
+// IsBanned checks if the peer is banned
+func (s *Server) IsBanned(id peer.ID) bool {
+	return s.reputation.isBanned(id)
+}
+
+// PeerScores returns the scores of the peers with faults, sorted by id
+func (s *Server) PeerScores() []*PeerScore {
+	return s.reputation.list()
+}
+
+// ClearPeerScore resets the score of the peer, and lifts its ban
+func (s *Server) ClearPeerScore(id peer.ID) {
+	s.reputation.clear(id)
+}
+
+func (r *reputation) report(id peer.ID, fault PeerFault, bannable bool) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	score, ok := r.scores[id]
+	if !ok {
+		score = &PeerScore{ID: id, Faults: map[PeerFault]uint64{}}
+		r.scores[id] = score
+	}
+	score.Score -= faultPenalties[fault]
+	score.Faults[fault]++
+
+	now := time.Now()
+	if !bannable || score.Score > banScore || score.isBanned(now) {
+		return false
+	}
+
+	score.BannedUntil = now.Add(banDuration)
+	r.saveDenylist()
+
+	return true
+}
+
+func (r *reputation) isBanned(id peer.ID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	score, ok := r.scores[id]
+	if !ok {
+		return false
+	}
+
+	return score.isBanned(time.Now())
+}
+
+func (r *reputation) list() []*PeerScore {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	scores := make([]*PeerScore, 0, len(r.scores))
+	for _, score := range r.scores {
+		scores = append(scores, score.copy())
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].ID < scores[j].ID
+	})
+
+	return scores
+}
+
+func (r *reputation) clear(id peer.ID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	score, ok := r.scores[id]
+	if !ok {
+		return
+	}
+	delete(r.scores, id)
+
+	if !score.BannedUntil.IsZero() {
+		r.saveDenylist()
+	}
+}
+
+// recover raises the negative scores by one point, and forgets the peers back to zero
+// once their ban is over
+func (r *reputation) recover() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	for id, score := range r.scores {
+		if score.isBanned(now) {
+			continue
+		}
+		if score.Score < 0 {
+			score.Score++
+		}
+		if score.Score >= 0 {
+			delete(r.scores, id)
+		}
+	}
+}
+
+func (r *reputation) run() {
+	for {
+		select {
+		case <-time.After(scoreRecoveryInterval):
+		case <-r.srv.closeCh:
+			return
+		}
+
+		r.recover()
+	}
+}
+
+func (r *reputation) loadDenylist() ([]*deniedPeer, error) {
+	if r.srv.config.DataDir == "" {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(r.srv.config.DataDir, denylistFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	denied := []*deniedPeer{}
+	if err := json.Unmarshal(data, &denied); err != nil {
+		return nil, err
+	}
+
+	return denied, nil
+}
+
+// saveDenylist persists the banned peers. It is called with the lock held
+func (r *reputation) saveDenylist() {
+	if r.srv.config.DataDir == "" {
+		return
+	}
+
+	now := time.Now()
+	denied := []*deniedPeer{}
+	for _, score := range r.scores {
+		if score.isBanned(now) {
+			denied = append(denied, &deniedPeer{ID: score.ID, Until: score.BannedUntil})
+		}
+	}
+	sort.Slice(denied, func(i, j int) bool {
+		return denied[i].ID < denied[j].ID
+	})
+
+	data, err := json.Marshal(denied)
+	if err == nil {
+		err = writeDataFile(r.srv.config.DataDir, denylistFile, data)
+	}
+	if err != nil {
+		r.srv.logger.Error("failed to save the denylist", "err", err)
+	}
+}
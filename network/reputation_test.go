@@ -0,0 +1,125 @@
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReputation_Ban(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "reputation")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+
+	srv0 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+		c.DataDir = dataDir
+	})
+	srv1 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1} {
+			srv.Close()
+		}
+	}()
+
+	id := srv1.AddrInfo().ID
+	assert.NoError(t, srv1.Join(srv0.AddrInfo(), 10*time.Second))
+
+	// the peer is scored on its faults
+	srv0.ReportFault(id, FaultBadBlock)
+	srv0.ReportFault(id, FaultTimeout)
+
+	scores := srv0.PeerScores()
+	assert.Len(t, scores, 1)
+	assert.Equal(t, int64(-55), scores[0].Score)
+	assert.Equal(t, uint64(1), scores[0].Faults[FaultBadBlock])
+	assert.Equal(t, uint64(1), scores[0].Faults[FaultTimeout])
+	assert.False(t, srv0.IsBanned(id))
+	assert.True(t, srv0.hasPeer(id))
+
+	// and banned and disconnected once its score falls to the ban score
+	disconnectedCh := asyncWaitForEvent(srv0, 10*time.Second, disconnectedPeerHandler(id))
+	srv0.ReportFault(id, FaultBadBlock)
+	assert.True(t, <-disconnectedCh)
+	assert.True(t, srv0.IsBanned(id))
+
+	// the ban is persisted across restarts
+	restored := newReputation(srv0)
+	assert.True(t, restored.isBanned(id))
+
+	// and lifted with the score
+	srv0.ClearPeerScore(id)
+	assert.False(t, srv0.IsBanned(id))
+	assert.Empty(t, srv0.PeerScores())
+	assert.False(t, newReputation(srv0).isBanned(id))
+}
+
+func TestReputation_TrustedNotBanned(t *testing.T) {
+	srv0 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+	srv1 := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+
+	defer func() {
+		for _, srv := range []*Server{srv0, srv1} {
+			srv.Close()
+		}
+	}()
+
+	id := srv1.AddrInfo().ID
+	srv0.AddTrustedPeer(id)
+
+	for i := 0; i < 3; i++ {
+		srv0.ReportFault(id, FaultBadBlock)
+	}
+	assert.Equal(t, int64(-150), srv0.PeerScores()[0].Score)
+	assert.False(t, srv0.IsBanned(id))
+
+	// reporting the node itself is a no-op
+	srv0.ReportFault(srv0.AddrInfo().ID, FaultBadBlock)
+	assert.Len(t, srv0.PeerScores(), 1)
+}
+
+func TestReputation_Recover(t *testing.T) {
+	srv := CreateServer(t, func(c *Config) {
+		c.NoDiscover = true
+	})
+	defer srv.Close()
+
+	r := newReputation(srv)
+	id := peer.ID("a")
+
+	r.report(id, FaultTimeout, true)
+	assert.Equal(t, int64(-5), r.list()[0].Score)
+
+	r.recover()
+	assert.Equal(t, int64(-4), r.list()[0].Score)
+
+	// the peers back to zero are forgotten
+	for i := 0; i < 4; i++ {
+		r.recover()
+	}
+	assert.Empty(t, r.list())
+
+	// the banned peers don't recover until the end of their ban
+	for i := 0; i < 2; i++ {
+		r.report(id, FaultBadBlock, true)
+	}
+	assert.True(t, r.isBanned(id))
+	r.recover()
+	assert.Equal(t, int64(banScore), r.list()[0].Score)
+
+	r.scores[id].BannedUntil = time.Now().Add(-time.Second)
+	assert.False(t, r.isBanned(id))
+	r.recover()
+	assert.Equal(t, int64(banScore+1), r.list()[0].Score)
+}
@@ -6,10 +6,12 @@ import (
 	"math/rand"
 	"net"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/network/proto"
 	"github.com/0xPolygon/polygon-sdk/secrets"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p"
@@ -38,13 +40,40 @@ type Config struct {
 	MaxPeers       uint64
 	Chain          *chain.Chain
 	SecretsManager secrets.SecretsManager
+
+	// Consortium restricts the network to the nodes with a certificate of the consortium CA
+	Consortium *ConsortiumConfig
+
+	// Allowlist restricts the network to the peers listed by the sources
+	Allowlist []AllowlistSource
+
+	// NAT configures the traversal of the NAT the node is behind, if any
+	NAT *NATConfig
+
+	// StaticPeers are kept connected, and along with the TrustedPeers are exempt from the MaxPeers limit
+	StaticPeers  []*peer.AddrInfo
+	TrustedPeers []peer.ID
+
+	// DNSTrees serve bootnodes with DNS TXT records, in addition to the enrtree:// bootnodes of the chain
+	DNSTrees []*DNSTree
+
+	// Compression compresses the streams and the gossip with snappy, with the peers supporting it
+	Compression bool
+
+	// MaxMessageSizes are the maximum sizes of the messages accepted by protocol,
+	// DefaultMaxMessageSize for the protocols not set
+	MaxMessageSizes map[string]int
+
+	// Metrics reports the peers and the traffic by protocol, not reported if nil
+	Metrics *Metrics
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		NoDiscover: false,
-		Addr:       &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: DefaultLibp2pPort},
-		MaxPeers:   10,
+		NoDiscover:  false,
+		Addr:        &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: DefaultLibp2pPort},
+		MaxPeers:    10,
+		Compression: true,
 	}
 }
 
@@ -54,16 +83,38 @@ type Server struct {
 
 	closeCh chan struct{}
 
-	host  host.Host
-	addrs []multiaddr.Multiaddr
+	host host.Host
+
+	// reachability is the network.Reachability detected by AutoNAT
+	reachability int32
 
 	peers     map[peer.ID]*Peer
 	peersLock sync.Mutex
 
 	dialQueue *dialQueue
 
-	identity  *identity
-	discovery *discovery
+	reputation *reputation
+
+	// bandwidth counts the traffic by peer and protocol
+	bandwidth *bandwidthReporter
+
+	metrics *Metrics
+
+	// allowlist is nil unless the network is restricted to the allowlisted peers
+	allowlist *allowlist
+
+	staticPeers  map[peer.ID]*peer.AddrInfo
+	trustedPeers map[peer.ID]struct{}
+	peerSetsLock sync.RWMutex
+	staticDialCh chan struct{}
+
+	// knownPeers are the peers persisted in the data dir across restarts
+	knownPeers     []*peer.AddrInfo
+	knownPeersLock sync.Mutex
+
+	identity     *identity
+	discovery    *discovery
+	dnsDiscovery *dnsDiscovery
 
 	protocols     map[string]Protocol
 	protocolsLock sync.Mutex
@@ -84,6 +135,10 @@ type Peer struct {
 	srv *Server
 
 	Info peer.AddrInfo
+
+	// compression and maxMessageSizes are negotiated at the handshake
+	compression     bool
+	maxMessageSizes map[string]uint64
 }
 
 // setupLibp2pKey is a helper method for setting up the networking private key
@@ -142,18 +197,45 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		return addrs
 	}
 
-	host, err := libp2p.New(
-		context.Background(),
+	natOpts, err := config.NAT.options()
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = NilMetrics()
+	}
+	bandwidth := newBandwidthReporter(metrics)
+
+	opts := []libp2p.Option{
 		// Use noise as the encryption protocol
 		libp2p.Security(noise.ID, noise.New),
 		libp2p.ListenAddrs(listenAddr),
 		libp2p.AddrsFactory(addrsFactory),
 		libp2p.Identity(key),
-	)
+		libp2p.BandwidthReporter(bandwidth),
+		libp2p.UserAgent(UserAgent),
+	}
+
+	var gater *allowlistGater
+	if len(config.Allowlist) > 0 {
+		gater = &allowlistGater{}
+		opts = append(opts, libp2p.ConnectionGater(gater))
+	}
+
+	host, err := libp2p.New(context.Background(), append(opts, natOpts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create libp2p stack: %v", err)
 	}
 
+	if config.Consortium != nil {
+		if err := config.Consortium.verify(host.ID(), config.Consortium.Certificate, time.Now()); err != nil {
+			host.Close()
+			return nil, fmt.Errorf("the node certificate is not valid: %v", err)
+		}
+	}
+
 	emitter, err := host.EventBus().Emitter(new(PeerEvent))
 	if err != nil {
 		return nil, err
@@ -163,21 +245,43 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		logger:           logger,
 		config:           config,
 		host:             host,
-		addrs:            host.Addrs(),
 		peers:            map[peer.ID]*Peer{},
 		dialQueue:        newDialQueue(),
 		closeCh:          make(chan struct{}),
 		emitterPeerEvent: emitter,
 		protocols:        map[string]Protocol{},
 		secretsManager:   config.SecretsManager,
+		bandwidth:        bandwidth,
+		metrics:          metrics,
+	}
+
+	if err := srv.watchReachability(); err != nil {
+		return nil, err
+	}
+
+	srv.reputation = newReputation(srv)
+	go srv.reputation.run()
+
+	if len(config.Allowlist) > 0 {
+		srv.allowlist = newAllowlist(srv, config.Allowlist)
+		srv.allowlist.refresh()
+		gater.srv.Store(srv)
+		go srv.allowlist.run()
 	}
 
 	// start identity
 	srv.identity = &identity{srv: srv}
 	srv.identity.setup()
 
+	srv.setupPeerSets()
+	srv.setupKnownPeers()
+
 	go srv.runDial()
+	go srv.runStaticDial()
+	go srv.runKnownPeersSave()
+	go srv.runBandwidthTrim()
 	go srv.checkPeerConnections()
+	go srv.runPeerPing()
 	logger.Info("LibP2P server running", "addr", AddrInfoToString(srv.AddrInfo()))
 
 	if !config.NoDiscover {
@@ -187,7 +291,16 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 
 		// try to decode the bootnodes
 		bootnodes := []*peer.AddrInfo{}
+		trees := append([]*DNSTree{}, config.DNSTrees...)
 		for _, raw := range config.Chain.Bootnodes {
+			if strings.HasPrefix(raw, DNSTreeScheme) {
+				tree, err := ParseDNSTreeURL(raw)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse bootnode tree %s: %v", raw, err)
+				}
+				trees = append(trees, tree)
+				continue
+			}
 			node, err := StringToAddrInfo(raw)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse bootnode %s: %v", raw, err)
@@ -202,6 +315,11 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		}
 
 		srv.discovery.setBootnodes(bootnodes)
+
+		if len(trees) > 0 {
+			srv.dnsDiscovery = newDNSDiscovery(srv, trees)
+			go srv.dnsDiscovery.run()
+		}
 	}
 
 	// start gossip protocol
@@ -234,8 +352,11 @@ func (s *Server) checkPeerConnections() {
 			return
 		}
 		if s.numPeers() < MinimumPeerConnections {
-			if s.config.NoDiscover || len(s.discovery.bootnodes) == 0 {
-				//TODO: dial peers from the peerstore
+			if s.config.NoDiscover || len(s.discovery.getBootnodes()) == 0 {
+				// dial one of the peers known from the previous runs, unless the discovery is disabled
+				if knownNode := s.randomKnownPeer(); knownNode != nil && !s.config.NoDiscover {
+					s.dialQueue.add(knownNode, 10)
+				}
 			} else {
 				randomNode := s.getRandomBootNode()
 				s.dialQueue.add(randomNode, 10)
@@ -279,6 +400,15 @@ func (s *Server) runDial() {
 			}
 			s.logger.Debug("dial", "local", s.host.ID(), "addr", tt.addr.String())
 
+			if s.IsBanned(tt.addr.ID) {
+				s.logger.Debug("omitting banned peer", "id", tt.addr.ID)
+				continue
+			}
+			if !s.IsAllowed(tt.addr.ID) {
+				s.logger.Debug("omitting peer not in the allowlist", "id", tt.addr.ID)
+				continue
+			}
+
 			if s.isConnected(tt.addr.ID) {
 				// the node is already connected, send an event to wake up
 				// any join watchers
@@ -312,7 +442,9 @@ func (s *Server) numPeers() int64 {
 }
 func (s *Server) getRandomBootNode() *peer.AddrInfo {
 
-	return s.discovery.bootnodes[rand.Intn(len(s.discovery.bootnodes))]
+	bootnodes := s.discovery.getBootnodes()
+
+	return bootnodes[rand.Intn(len(bootnodes))]
 
 }
 func (s *Server) Peers() []*Peer {
@@ -327,13 +459,22 @@ func (s *Server) Peers() []*Peer {
 }
 
 func (s *Server) numOpenSlots() int64 {
-	n := int64(s.config.MaxPeers) - (s.numPeers() + s.identity.numPending())
+	// the trusted peers don't take slots
+	n := int64(s.config.MaxPeers) - (s.numPeers() - s.numTrustedPeers() + s.identity.numPending())
 	if n < 0 {
 		n = 0
 	}
 	return n
 }
 
+func (s *Server) hasPeer(peerID peer.ID) bool {
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	_, ok := s.peers[peerID]
+	return ok
+}
+
 func (s *Server) isConnected(peerID peer.ID) bool {
 	return s.host.Network().Connectedness(peerID) == network.Connected
 }
@@ -346,7 +487,7 @@ func (s *Server) GetPeerInfo(peerID peer.ID) peer.AddrInfo {
 	return s.host.Peerstore().PeerInfo(peerID)
 }
 
-func (s *Server) addPeer(id peer.ID) {
+func (s *Server) addPeer(id peer.ID, status *proto.Status) {
 	s.logger.Info("Peer connected", "id", id.String())
 
 	s.peersLock.Lock()
@@ -356,6 +497,10 @@ func (s *Server) addPeer(id peer.ID) {
 		srv:  s,
 		Info: s.host.Peerstore().PeerInfo(id),
 	}
+	p.setHandshake(status)
+	if _, ok := s.peers[id]; !ok {
+		s.metrics.Peers.Add(1)
+	}
 	s.peers[id] = p
 
 	s.emitEvent(&PeerEvent{
@@ -370,13 +515,21 @@ func (s *Server) delPeer(id peer.ID) {
 	s.peersLock.Lock()
 	defer s.peersLock.Unlock()
 
+	if _, ok := s.peers[id]; ok {
+		s.metrics.Peers.Add(-1)
+	}
 	delete(s.peers, id)
+	s.identity.delMember(id)
 	s.host.Network().ClosePeer(id)
 
 	s.emitEvent(&PeerEvent{
 		PeerID: id,
 		Type:   PeerEventDisconnected,
 	})
+
+	if s.IsStatic(id) {
+		s.notifyStaticDial()
+	}
 }
 
 func (s *Server) Disconnect(peer peer.ID, reason string) {
@@ -487,6 +640,10 @@ func (s *Server) runJoinWatcher() error {
 }
 
 func (s *Server) Close() error {
+	if err := s.saveKnownPeers(); err != nil {
+		s.logger.Error("failed to save the known peers", "err", err)
+	}
+
 	err := s.host.Close()
 	s.dialQueue.Close()
 	close(s.closeCh)
@@ -530,14 +687,24 @@ func (s *Server) wrapStream(id string, handle func(network.Stream)) {
 		peerID := stream.Conn().RemotePeer()
 		s.logger.Trace("open stream", "protocol", id, "peer", peerID)
 
+		// a permissioned network only serves the handshake until it verifies the membership of the peer
+		if id != identityProtoV1 && !s.identity.isMember(peerID) {
+			s.logger.Debug("rejecting stream of a peer not verified", "protocol", id, "peer", peerID)
+			stream.Reset()
+
+			return
+		}
+
 		handle(stream)
 	})
 }
 
+// AddrInfo returns the addresses the node advertises, including the ones
+// mapped on the gateway or reserved on the relays once they are set up
 func (s *Server) AddrInfo() *peer.AddrInfo {
 	return &peer.AddrInfo{
 		ID:    s.host.ID(),
-		Addrs: s.addrs,
+		Addrs: s.host.Addrs(),
 	}
 }
 
@@ -22,4 +22,8 @@ type blockchainShim interface {
 	// advance chain methods
 	WriteBlocks(blocks []*types.Block) error
 	CalculateGasLimit(number uint64) (uint64, error)
+
+	// snapshot sync methods
+	WriteSyncedHeaders(headers []*types.Header) error
+	WriteSyncedBlock(block *types.Block, receipts []*types.Receipt) error
 }
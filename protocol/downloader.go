@@ -0,0 +1,395 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/types/buildroot"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// downloadRangeSize is the number of blocks requested at once from a peer
+	downloadRangeSize = 64
+
+	// maxPendingRanges bounds the ranges downloaded ahead of the next block to write
+	maxPendingRanges = 32
+
+	// downloadTimeout is how long the headers and the bodies of a range are waited for
+	downloadTimeout = 10 * time.Second
+
+	// blacklistDuration is how long a peer that served bad data is not synced from
+	blacklistDuration = 10 * time.Minute
+)
+
+var (
+	ErrNoDownloadPeers = errors.New("no peer left to download the blocks from")
+)
+
+// blockRange is a range of consecutive blocks downloaded from a single peer. The hashes of its
+// parent and of its last block come from the skeleton of the sync peer, they anchor the range to its chain
+type blockRange struct {
+	from   uint64
+	to     uint64
+	parent types.Hash
+	hash   types.Hash
+}
+
+// rangeResult is the outcome of the download of a range
+type rangeResult struct {
+	rng    blockRange
+	peer   *syncPeer
+	blocks []*types.Block
+	err    error
+}
+
+// blockFetcher downloads the chain data from the peers
+type blockFetcher interface {
+	// fetchHeaders returns the headers from the given number, every skip+1 blocks
+	fetchHeaders(p *syncPeer, from, skip, amount uint64) ([]*types.Header, error)
+
+	// fetchBlocks returns the blocks of the range, with their bodies
+	fetchBlocks(p *syncPeer, rng blockRange) ([]*types.Block, error)
+}
+
+// blacklistPeer stops syncing from the peer for a while, it served bad data.
+// The fault is reported to the network, lowering the score of the peer
+func (s *Syncer) blacklistPeer(p *syncPeer, fault network.PeerFault, err error) {
+	s.logger.Warn("blacklisting sync peer", "id", p.peer, "err", err)
+	s.server.ReportFault(p.peer, fault)
+
+	s.blacklistLock.Lock()
+	defer s.blacklistLock.Unlock()
+
+	s.blacklist[p.peer] = time.Now().Add(blacklistDuration)
+}
+
+// fetchFault returns the fault of a failed request, a timeout or bad data
+func fetchFault(err error) network.PeerFault {
+	if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return network.FaultTimeout
+	}
+
+	return network.FaultBadBlock
+}
+
+// writeFault returns the fault of a block failing to be written, its receipts not matching its header are
+// corrupted data and penalized the most
+func writeFault(err error) network.PeerFault {
+	if errors.Is(err, blockchain.ErrInvalidReceiptsRoot) || errors.Is(err, blockchain.ErrInvalidLogsBloom) {
+		return network.FaultBadReceipts
+	}
+
+	return network.FaultBadBlock
+}
+
+// isBlacklisted returns whether the peer is blacklisted
+func (s *Syncer) isBlacklisted(id peer.ID) bool {
+	s.blacklistLock.Lock()
+	defer s.blacklistLock.Unlock()
+
+	until, ok := s.blacklist[id]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(s.blacklist, id)
+		return false
+	}
+
+	return true
+}
+
+// downloadPeers returns the peers the blocks can be downloaded from, the given peer first
+func (s *Syncer) downloadPeers(best *syncPeer) []*syncPeer {
+	peers := []*syncPeer{best}
+
+	s.peers.Range(func(_, value interface{}) bool {
+		p := value.(*syncPeer)
+		if p != best && !p.IsClosed() && !s.isBlacklisted(p.peer) {
+			peers = append(peers, p)
+		}
+
+		return true
+	})
+
+	return peers
+}
+
+// downloadBlocks downloads the blocks after the parent up to the given number, and writes them in order.
+// The ranges of blocks are anchored to the skeleton of the first peer, and scheduled across all the peers.
+// The peers serving bad data are blacklisted, the ones on another chain are left out,
+// and their ranges are downloaded from the other peers. It returns the last written header
+func (s *Syncer) downloadBlocks(peers []*syncPeer, parent *types.Header, to uint64) (*types.Header, error) {
+	queue := []blockRange{}
+	for from := parent.Number + 1; from <= to; from += downloadRangeSize {
+		rng := blockRange{from: from, to: from + downloadRangeSize - 1}
+		if rng.to > to {
+			rng.to = to
+		}
+		queue = append(queue, rng)
+	}
+
+	if err := s.downloadSkeleton(peers[0], parent, queue); err != nil {
+		return nil, fmt.Errorf("failed to download the skeleton: %v", err)
+	}
+
+	var (
+		// every peer downloads one range at a time
+		resultCh = make(chan *rangeResult, len(peers))
+		busy     = map[peer.ID]bool{}
+		excluded = map[peer.ID]bool{}
+		inflight = 0
+
+		downloaded = map[uint64]*rangeResult{}
+		next       = parent.Number + 1
+
+		lastErr error
+	)
+
+	// retry puts the range back in front of the queue
+	retry := func(res *rangeResult, err error) {
+		lastErr = err
+		queue = append([]blockRange{res.rng}, queue...)
+	}
+
+	for next <= to {
+		for len(queue) > 0 && inflight+len(downloaded) < maxPendingRanges {
+			p := s.pickDownloadPeer(peers, busy, excluded, queue[0])
+			if p == nil {
+				break
+			}
+
+			rng := queue[0]
+			queue = queue[1:]
+
+			busy[p.peer] = true
+			inflight++
+
+			go func() {
+				blocks, err := s.fetcher.fetchBlocks(p, rng)
+				resultCh <- &rangeResult{rng: rng, peer: p, blocks: blocks, err: err}
+			}()
+		}
+
+		if inflight == 0 {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w, last error: %v", ErrNoDownloadPeers, lastErr)
+			}
+			return nil, ErrNoDownloadPeers
+		}
+
+		res := <-resultCh
+		inflight--
+		delete(busy, res.peer.peer)
+
+		if res.err != nil {
+			err := fmt.Errorf("failed to download blocks %d to %d: %v", res.rng.from, res.rng.to, res.err)
+
+			s.blacklistPeer(res.peer, fetchFault(res.err), err)
+			retry(res, err)
+
+			continue
+		}
+
+		// the peer is on another chain than the sync peer
+		if res.blocks[0].ParentHash() != res.rng.parent || res.blocks[len(res.blocks)-1].Hash() != res.rng.hash {
+			s.logger.Debug("sync peer is on another chain", "id", res.peer.peer, "from", res.rng.from)
+
+			excluded[res.peer.peer] = true
+			retry(res, fmt.Errorf("blocks %d to %d are not on the synced chain", res.rng.from, res.rng.to))
+
+			continue
+		}
+
+		downloaded[res.rng.from] = res
+		s.tracker.update(func(p *SyncProgress) {
+			p.BlocksDownloaded += uint64(len(res.blocks))
+		})
+
+		// write the downloaded ranges following the written blocks
+		for {
+			res, ok := downloaded[next]
+			if !ok {
+				break
+			}
+			delete(downloaded, next)
+
+			if err := s.blockchain.WriteBlocks(res.blocks); err != nil {
+				err = fmt.Errorf("failed to write blocks %d to %d: %v", res.rng.from, res.rng.to, err)
+
+				s.blacklistPeer(res.peer, writeFault(err), err)
+				retry(res, err)
+
+				break
+			}
+
+			parent = res.blocks[len(res.blocks)-1].Header
+			next = res.rng.to + 1
+
+			s.tracker.update(func(p *SyncProgress) {
+				p.BlocksExecuted += uint64(len(res.blocks))
+				p.CurrentBlock = parent.Number
+			})
+		}
+	}
+
+	return parent, nil
+}
+
+// downloadSkeleton sets the hashes anchoring the ranges, from the last headers of the ranges of the peer
+func (s *Syncer) downloadSkeleton(p *syncPeer, parent *types.Header, ranges []blockRange) error {
+	for i := 0; i < len(ranges); {
+		// the last headers of the full ranges are requested at once
+		amount := 1
+		for i+amount < len(ranges) && amount < maxHeadersAmount &&
+			ranges[i+amount].to-ranges[i+amount-1].to == downloadRangeSize {
+			amount++
+		}
+
+		headers, err := s.fetcher.fetchHeaders(p, ranges[i].to, downloadRangeSize-1, uint64(amount))
+		if err != nil {
+			return err
+		}
+		if len(headers) != amount {
+			return fmt.Errorf("expected %d headers, got %d", amount, len(headers))
+		}
+
+		for j, header := range headers {
+			if header.Number != ranges[i+j].to {
+				return fmt.Errorf("expected header %d, got %d", ranges[i+j].to, header.Number)
+			}
+			ranges[i+j].hash = header.Hash
+		}
+		s.tracker.update(func(p *SyncProgress) {
+			p.HeadersDownloaded += uint64(len(headers))
+		})
+
+		i += amount
+	}
+
+	for i := range ranges {
+		if i == 0 {
+			ranges[i].parent = parent.Hash
+		} else {
+			ranges[i].parent = ranges[i-1].hash
+		}
+	}
+
+	return nil
+}
+
+// pickDownloadPeer returns an idle peer which has the range, if any
+func (s *Syncer) pickDownloadPeer(
+	peers []*syncPeer,
+	busy, excluded map[peer.ID]bool,
+	rng blockRange,
+) *syncPeer {
+	for _, p := range peers {
+		if busy[p.peer] || excluded[p.peer] || p.Number() < rng.to || s.isBlacklisted(p.peer) {
+			continue
+		}
+
+		return p
+	}
+
+	return nil
+}
+
+// peerFetcher downloads the chain data with the sync protocol
+type peerFetcher struct{}
+
+func (peerFetcher) fetchHeaders(p *syncPeer, from, skip, amount uint64) ([]*types.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	return getHeaders(ctx, p.client, &proto.GetHeadersRequest{
+		Number: int64(from),
+		Skip:   int64(skip),
+		Amount: int64(amount),
+	})
+}
+
+// fetchBlocks downloads the headers and the bodies of the range, and verifies they make a chain of complete blocks
+func (peerFetcher) fetchBlocks(p *syncPeer, rng blockRange) ([]*types.Block, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	amount := rng.to - rng.from + 1
+
+	headers, err := getHeaders(ctx, p.client, &proto.GetHeadersRequest{
+		Number: int64(rng.from),
+		Amount: int64(amount),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(headers)) != amount {
+		return nil, fmt.Errorf("expected %d headers, got %d", amount, len(headers))
+	}
+
+	blocks := make([]*types.Block, 0, len(headers))
+
+	// for each header with body we request it
+	bodyHashes := []types.Hash{}
+	bodyIndex := []int{}
+
+	for indx, header := range headers {
+		if header.Number != rng.from+uint64(indx) {
+			return nil, fmt.Errorf("expected header %d, got %d", rng.from+uint64(indx), header.Number)
+		}
+		if indx > 0 && header.ParentHash != headers[indx-1].Hash {
+			return nil, fmt.Errorf("header %d does not link to its parent", header.Number)
+		}
+
+		blocks = append(blocks, &types.Block{Header: header})
+
+		if header.TxRoot != types.EmptyRootHash {
+			bodyHashes = append(bodyHashes, header.Hash)
+			bodyIndex = append(bodyIndex, indx)
+		}
+	}
+	if len(bodyHashes) == 0 {
+		return blocks, nil
+	}
+
+	bodies, err := getBodies(ctx, p.client, bodyHashes)
+	if err != nil {
+		return nil, err
+	}
+	for indx, body := range bodies {
+		block := blocks[bodyIndex[indx]]
+		if root := buildroot.CalculateTransactionsRoot(body.Transactions); root != block.Header.TxRoot {
+			return nil, fmt.Errorf("body of block %d does not match its header", block.Number())
+		}
+
+		block.Transactions = body.Transactions
+		block.Uncles = body.Uncles
+	}
+
+	return blocks, nil
+}
+
+func getHeaders(ctx context.Context, clt proto.V1Client, req *proto.GetHeadersRequest) ([]*types.Header, error) {
+	resp, err := clt.GetHeaders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	headers := []*types.Header{}
+	for _, obj := range resp.Objs {
+		header := &types.Header{}
+		if err := header.UnmarshalRLP(obj.Spec.Value); err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
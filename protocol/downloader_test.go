@@ -0,0 +1,155 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBlockFetcher serves the headers and the ranges from the chains of the peers, and records who served the ranges
+type mockBlockFetcher struct {
+	lock   sync.Mutex
+	chains map[peer.ID][]*types.Header
+	failed map[peer.ID]bool
+	served map[peer.ID]int
+}
+
+func (m *mockBlockFetcher) fetchHeaders(p *syncPeer, from, skip, amount uint64) ([]*types.Header, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.failed[p.peer] {
+		return nil, errors.New("timeout")
+	}
+
+	headers := []*types.Header{}
+	chain := m.chains[p.peer]
+	for i := from; i < uint64(len(chain)) && uint64(len(headers)) < amount; i += skip + 1 {
+		headers = append(headers, chain[i])
+	}
+
+	return headers, nil
+}
+
+func (m *mockBlockFetcher) fetchBlocks(p *syncPeer, rng blockRange) ([]*types.Block, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.served[p.peer]++
+	if m.failed[p.peer] {
+		return nil, errors.New("timeout")
+	}
+
+	return blockchain.HeadersToBlocks(m.chains[p.peer][rng.from : rng.to+1]), nil
+}
+
+func newDownloadPeer(id string, number uint64) *syncPeer {
+	return &syncPeer{
+		peer:   peer.ID(id),
+		status: &Status{Number: number},
+	}
+}
+
+func TestDownloadBlocks(t *testing.T) {
+	chain := blockchain.TestBlockchain(t, nil)
+
+	// the seed is the gas limit of the headers
+	gasLimit := int(chain.Header().GasLimit)
+
+	headers := blockchain.NewTestHeaderChainWithSeed(chain.Header(), 300, gasLimit)
+	forkHeaders := blockchain.NewTestHeaderFromChainWithSeed(headers[:50], 250, gasLimit+1)
+
+	syncer := NewSyncer(hclog.NewNullLogger(), nil, chain)
+
+	fetcher := &mockBlockFetcher{
+		chains: map[peer.ID][]*types.Header{
+			"a":    headers,
+			"b":    headers,
+			"fork": forkHeaders,
+			"down": headers,
+			"low":  headers,
+		},
+		failed: map[peer.ID]bool{"down": true},
+		served: map[peer.ID]int{},
+	}
+	syncer.fetcher = fetcher
+
+	tracker := NewSyncTracker()
+	syncer.SetSyncTracker(tracker)
+	tracker.start(SyncStageBlocks, 0, 299)
+
+	peers := []*syncPeer{
+		newDownloadPeer("a", 299),
+		newDownloadPeer("fork", 299),
+		newDownloadPeer("down", 299),
+		newDownloadPeer("b", 299),
+		newDownloadPeer("low", 10),
+	}
+
+	head, err := syncer.downloadBlocks(peers, headers[0], 299)
+	assert.NoError(t, err)
+	assert.Equal(t, headers[299].Hash, head.Hash)
+	assert.Equal(t, headers[299].Hash, chain.Header().Hash)
+
+	// the ranges were spread across the peers having them
+	assert.NotZero(t, fetcher.served["a"])
+	assert.NotZero(t, fetcher.served["b"])
+	assert.Zero(t, fetcher.served["low"])
+
+	// the peers failing are not synced from anymore, the ones on another chain are only left out
+	assert.True(t, syncer.isBlacklisted("down"))
+	assert.False(t, syncer.isBlacklisted("fork"))
+	assert.LessOrEqual(t, fetcher.served["fork"], 1)
+	assert.False(t, syncer.isBlacklisted("a"))
+	assert.False(t, syncer.isBlacklisted("b"))
+
+	// the progress counts the skeleton headers, and the blocks downloaded again after the failures
+	progress := tracker.SyncProgress()
+	assert.Equal(t, uint64(299), progress.CurrentBlock)
+	assert.Equal(t, uint64(299), progress.BlocksExecuted)
+	assert.GreaterOrEqual(t, progress.BlocksDownloaded, uint64(299))
+	assert.Equal(t, uint64(5), progress.HeadersDownloaded)
+	assert.Zero(t, progress.ETA)
+}
+
+func TestDownloadBlocks_NoPeers(t *testing.T) {
+	chain := blockchain.TestBlockchain(t, nil)
+
+	headers := blockchain.NewTestHeaderChainWithSeed(chain.Header(), 100, int(chain.Header().GasLimit))
+	syncer := NewSyncer(hclog.NewNullLogger(), nil, chain)
+
+	fetcher := &mockBlockFetcher{
+		chains: map[peer.ID][]*types.Header{"a": headers, "b": headers, "c": headers},
+		failed: map[peer.ID]bool{"a": true, "c": true},
+		served: map[peer.ID]int{},
+	}
+	syncer.fetcher = fetcher
+
+	// the skeleton of the sync peer is required
+	_, err := syncer.downloadBlocks([]*syncPeer{newDownloadPeer("a", 99)}, headers[0], 99)
+	assert.Error(t, err)
+	assert.Zero(t, fetcher.served["a"])
+
+	// the sync peer does not have the blocks yet and the other peer fails
+	_, err = syncer.downloadBlocks([]*syncPeer{newDownloadPeer("b", 10), newDownloadPeer("c", 99)}, headers[0], 99)
+	assert.True(t, errors.Is(err, ErrNoDownloadPeers))
+	assert.Equal(t, 1, fetcher.served["c"])
+
+	// no peer has the blocks
+	_, err = syncer.downloadBlocks([]*syncPeer{newDownloadPeer("b", 10)}, headers[0], 99)
+	assert.Equal(t, ErrNoDownloadPeers, err)
+}
+
+func TestWriteFault(t *testing.T) {
+	assert.Equal(t, network.FaultBadBlock, writeFault(errors.New("invalid merkle root")))
+	assert.Equal(t, network.FaultBadReceipts, writeFault(fmt.Errorf("%w: have a, want b", blockchain.ErrInvalidReceiptsRoot)))
+	assert.Equal(t, network.FaultBadReceipts, writeFault(blockchain.ErrInvalidLogsBloom))
+}
@@ -0,0 +1,197 @@
+package protocol
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// maxForkBranches is the number of competing branches kept by the fork monitor.
+// When the limit is reached, the branch that was seen the longest time ago is dropped
+const maxForkBranches = 64
+
+// ForkBranch is a chain branch announced by the peers that conflicts with the local canonical chain
+type ForkBranch struct {
+	// Head is the hash of the latest known header of the branch
+	Head types.Hash
+
+	// Number is the height of the latest known header of the branch
+	Number uint64
+
+	// ForkNumber is the first height at which the branch was seen conflicting with the local chain
+	ForkNumber uint64
+
+	// LocalHash is the hash of the local canonical header at ForkNumber, when the branch was detected.
+	// It is the zero hash if the local chain did not reach ForkNumber
+	LocalHash types.Hash
+
+	// Peers are the peers that announced the branch
+	Peers []peer.ID
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+
+	// seq orders the branches by the time they were last seen
+	seq uint64
+}
+
+func (b *ForkBranch) copy() *ForkBranch {
+	bb := new(ForkBranch)
+	*bb = *b
+	bb.Peers = append([]peer.ID{}, b.Peers...)
+
+	return bb
+}
+
+func (b *ForkBranch) addPeer(peerID peer.ID) {
+	for _, p := range b.Peers {
+		if p == peerID {
+			return
+		}
+	}
+	b.Peers = append(b.Peers, peerID)
+}
+
+// canonicalReader is the part of the blockchain needed by the fork monitor
+type canonicalReader interface {
+	GetHeaderByNumber(n uint64) (*types.Header, bool)
+}
+
+// ForkMonitor tracks the headers received from the peers that do not extend
+// the local canonical chain, and groups them into competing branches
+type ForkMonitor struct {
+	logger     hclog.Logger
+	blockchain canonicalReader
+	metrics    *Metrics
+
+	lock     sync.Mutex
+	branches map[types.Hash]*ForkBranch // branch head -> branch
+	seq      uint64
+}
+
+// NewForkMonitor creates a new fork monitor for the given chain
+func NewForkMonitor(logger hclog.Logger, blockchain canonicalReader, metrics *Metrics) *ForkMonitor {
+	return &ForkMonitor{
+		logger:     logger.Named("fork-monitor"),
+		blockchain: blockchain,
+		metrics:    metrics,
+		branches:   map[types.Hash]*ForkBranch{},
+	}
+}
+
+// Observe checks the header announced by the peer against the local canonical chain.
+// Headers that replace a canonical header, or that are not built on top of the canonical
+// parent, are recorded as part of a competing branch
+func (f *ForkMonitor) Observe(peerID peer.ID, header *types.Header) {
+	var localHash types.Hash
+
+	if local, ok := f.blockchain.GetHeaderByNumber(header.Number); ok {
+		if local.Hash == header.Hash {
+			return
+		}
+		localHash = local.Hash
+	} else {
+		// the header is ahead of the local chain, it conflicts only if it does not extend it
+		if header.Number == 0 {
+			return
+		}
+		parent, ok := f.blockchain.GetHeaderByNumber(header.Number - 1)
+		if !ok || parent.Hash == header.ParentHash {
+			return
+		}
+	}
+
+	f.metrics.ConflictingHeaders.Add(1)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	now := time.Now()
+	f.seq++
+
+	if branch, ok := f.branches[header.Hash]; ok {
+		// the header is already known
+		branch.addPeer(peerID)
+		branch.LastSeen = now
+		branch.seq = f.seq
+
+		return
+	}
+
+	if branch, ok := f.branches[header.ParentHash]; ok {
+		// the header extends a known branch
+		delete(f.branches, branch.Head)
+
+		branch.Head = header.Hash
+		branch.Number = header.Number
+		branch.addPeer(peerID)
+		branch.LastSeen = now
+		branch.seq = f.seq
+		f.branches[branch.Head] = branch
+
+		return
+	}
+
+	f.logger.Warn(
+		"detected competing branch",
+		"peer", peerID,
+		"number", header.Number,
+		"hash", header.Hash,
+		"local", localHash,
+	)
+
+	f.branches[header.Hash] = &ForkBranch{
+		Head:       header.Hash,
+		Number:     header.Number,
+		ForkNumber: header.Number,
+		LocalHash:  localHash,
+		Peers:      []peer.ID{peerID},
+		FirstSeen:  now,
+		LastSeen:   now,
+		seq:        f.seq,
+	}
+	f.evictLocked()
+
+	f.metrics.ForkBranches.Set(float64(len(f.branches)))
+}
+
+// evictLocked drops the least recently seen branches over the limit
+func (f *ForkMonitor) evictLocked() {
+	for len(f.branches) > maxForkBranches {
+		var oldest *ForkBranch
+		for _, branch := range f.branches {
+			if oldest == nil || branch.seq < oldest.seq {
+				oldest = branch
+			}
+		}
+		delete(f.branches, oldest.Head)
+	}
+}
+
+// ForkBranches returns the competing branches, most recently seen first.
+// Branches that became part of the local canonical chain are dropped
+func (f *ForkMonitor) ForkBranches() []*ForkBranch {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	branches := make([]*ForkBranch, 0, len(f.branches))
+	for hash, branch := range f.branches {
+		if local, ok := f.blockchain.GetHeaderByNumber(branch.Number); ok && local.Hash == hash {
+			// the local chain switched to the branch
+			delete(f.branches, hash)
+			continue
+		}
+		branches = append(branches, branch.copy())
+	}
+	f.metrics.ForkBranches.Set(float64(len(f.branches)))
+
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].seq > branches[j].seq
+	})
+
+	return branches
+}
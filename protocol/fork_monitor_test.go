@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkMonitorBranches(t *testing.T) {
+	var (
+		peerA = peer.ID("A")
+		peerB = peer.ID("B")
+	)
+
+	common := blockchain.NewTestHeaderChain(5)
+	local := blockchain.NewTestHeaderFromChainWithSeed(common, 3, 0)
+	fork := blockchain.NewTestHeaderFromChainWithSeed(common, 4, 1)
+
+	store := newMockBlockStore()
+	store.blocks = blockchain.HeadersToBlocks(local)
+
+	monitor := NewForkMonitor(hclog.NewNullLogger(), store, NilMetrics())
+
+	// the canonical headers and their children are not conflicting
+	monitor.Observe(peerA, local[6])
+	monitor.Observe(peerA, blockchain.NewTestHeaderFromChain(local, 1)[8])
+	assert.Empty(t, monitor.ForkBranches())
+
+	// a header replacing a canonical one starts a branch
+	monitor.Observe(peerA, fork[5])
+
+	branches := monitor.ForkBranches()
+	assert.Len(t, branches, 1)
+	assert.Equal(t, fork[5].Hash, branches[0].Head)
+	assert.Equal(t, uint64(5), branches[0].ForkNumber)
+	assert.Equal(t, local[5].Hash, branches[0].LocalHash)
+
+	// the children extend the branch, including the ones ahead of the local chain
+	for _, header := range fork[6:] {
+		monitor.Observe(peerB, header)
+	}
+
+	branches = monitor.ForkBranches()
+	assert.Len(t, branches, 1)
+	assert.Equal(t, fork[8].Hash, branches[0].Head)
+	assert.Equal(t, uint64(8), branches[0].Number)
+	assert.Equal(t, uint64(5), branches[0].ForkNumber)
+	assert.Equal(t, []peer.ID{peerA, peerB}, branches[0].Peers)
+
+	// a header ahead of the local chain with an unknown parent starts another branch
+	orphan := &types.Header{Number: 8, ParentHash: types.StringToHash("1")}
+	orphan.ComputeHash()
+	monitor.Observe(peerA, orphan)
+
+	branches = monitor.ForkBranches()
+	assert.Len(t, branches, 2)
+	assert.Equal(t, orphan.Hash, branches[0].Head)
+	assert.Equal(t, types.ZeroHash, branches[0].LocalHash)
+
+	// the branch is dropped once the local chain switches to it
+	store.blocks = blockchain.HeadersToBlocks(fork)
+
+	branches = monitor.ForkBranches()
+	assert.Len(t, branches, 1)
+	assert.Equal(t, orphan.Hash, branches[0].Head)
+}
+
+func TestForkMonitorEviction(t *testing.T) {
+	store := newMockBlockStore()
+	store.blocks = blockchain.HeadersToBlocks(blockchain.NewTestHeaderChain(2))
+
+	monitor := NewForkMonitor(hclog.NewNullLogger(), store, NilMetrics())
+
+	var first types.Hash
+	for i := 0; i < maxForkBranches+1; i++ {
+		header := &types.Header{Number: 1, GasLimit: uint64(i + 1)}
+		header.ComputeHash()
+		if i == 0 {
+			first = header.Hash
+		}
+
+		monitor.Observe(peer.ID("A"), header)
+	}
+
+	branches := monitor.ForkBranches()
+	assert.Len(t, branches, maxForkBranches)
+	for _, branch := range branches {
+		assert.NotEqual(t, first, branch.Head)
+	}
+}
@@ -0,0 +1,309 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/network"
+	libp2pGrpc "github.com/0xPolygon/polygon-sdk/network/grpc"
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	"github.com/0xPolygon/polygon-sdk/state"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/umbracle/fastrlp"
+)
+
+const lightV1 = "/light/0.1"
+
+// maxLightStorageKeys is the maximum number of storage slots proven in a request
+const maxLightStorageKeys = 64
+
+var (
+	errLightBlockNotFound = errors.New("block not found")
+	errLightStateNotFound = errors.New("state of the block not available")
+)
+
+// lightService serves the headers of the chain and the merkle proofs of its state and receipts
+type lightService struct {
+	proto.UnimplementedLightServer
+
+	store blockchainShim
+	state itrie.Storage
+}
+
+// ServeLightClients registers the light protocol on the network. The light clients follow the chain
+// with the headers and their commit seals, and read the state and the receipts with merkle proofs
+func ServeLightClients(server *network.Server, blockchain blockchainShim, storage itrie.Storage) {
+	grpcStream := libp2pGrpc.NewGrpcStream(server.GrpcServerOptions(lightV1)...)
+	proto.RegisterLightServer(grpcStream.GrpcServer(), &lightService{store: blockchain, state: storage})
+	grpcStream.Serve()
+
+	server.Register(lightV1, grpcStream)
+}
+
+// GetHeaders implements the LightServer interface
+func (l *lightService) GetHeaders(_ context.Context, req *proto.LightHeadersRequest) (*proto.LightHeaders, error) {
+	amount := req.Amount
+	if amount > maxHeadersAmount {
+		amount = maxHeadersAmount
+	}
+
+	resp := &proto.LightHeaders{
+		Headers: [][]byte{},
+	}
+	for i := uint64(0); i < amount; i++ {
+		header, ok := l.store.GetHeaderByNumber(req.From + i)
+		if !ok {
+			break
+		}
+		resp.Headers = append(resp.Headers, header.MarshalRLP())
+	}
+
+	return resp, nil
+}
+
+// GetProof implements the LightServer interface
+func (l *lightService) GetProof(_ context.Context, req *proto.LightProofRequest) (*proto.LightProof, error) {
+	if len(req.StorageKeys) > maxLightStorageKeys {
+		return nil, fmt.Errorf("too many storage keys, the maximum is %d", maxLightStorageKeys)
+	}
+
+	header, ok := l.store.GetHeaderByNumber(req.Number)
+	if !ok {
+		return nil, errLightBlockNotFound
+	}
+
+	var addr types.Address
+	if err := addr.UnmarshalText([]byte(req.Address)); err != nil {
+		return nil, err
+	}
+
+	accountKey := crypto.Keccak256(addr.Bytes())
+	accountProof, err := itrie.Prove(l.state, header.StateRoot, accountKey)
+	if err != nil {
+		return nil, errLightStateNotFound
+	}
+
+	resp := &proto.LightProof{
+		AccountProof:  accountProof,
+		StorageProofs: []*proto.LightStorageProof{},
+	}
+	if len(req.StorageKeys) == 0 {
+		return resp, nil
+	}
+
+	data, err := itrie.VerifyProof(header.StateRoot, accountKey, accountProof)
+	if err != nil {
+		return nil, err
+	}
+	account := &state.Account{Root: types.EmptyRootHash}
+	if data != nil {
+		if err := account.UnmarshalRlp(data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, raw := range req.StorageKeys {
+		var key types.Hash
+		if err := key.UnmarshalText([]byte(raw)); err != nil {
+			return nil, err
+		}
+
+		proof, err := itrie.Prove(l.state, account.Root, crypto.Keccak256(key.Bytes()))
+		if err != nil {
+			return nil, errLightStateNotFound
+		}
+		resp.StorageProofs = append(resp.StorageProofs, &proto.LightStorageProof{
+			Key:   raw,
+			Proof: proof,
+		})
+	}
+
+	return resp, nil
+}
+
+// GetReceiptProof implements the LightServer interface
+func (l *lightService) GetReceiptProof(_ context.Context, req *proto.LightReceiptProofRequest) (*proto.LightReceiptProof, error) {
+	var hash types.Hash
+	if err := hash.UnmarshalText([]byte(req.BlockHash)); err != nil {
+		return nil, err
+	}
+
+	receipts, err := l.store.GetReceiptsByHash(hash)
+	if err != nil {
+		return nil, errLightBlockNotFound
+	}
+
+	proof, err := itrie.ProveIndex(len(receipts), func(i int) []byte {
+		return receipts[i].MarshalRLPTo(nil)
+	}, int(req.Index))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.LightReceiptProof{Proof: proof}, nil
+}
+
+// HeaderVerifier checks the header is the child of the trusted header, and is sealed by the consensus,
+// as ibft.VerifyLightHeader does
+type HeaderVerifier func(trusted, header *types.Header) error
+
+// LightClient follows the chain of a peer from a trusted header, verifying every header it is served,
+// and reads the state and the receipts of the chain with merkle proofs against the verified headers
+type LightClient struct {
+	client proto.LightClient
+	verify HeaderVerifier
+
+	head     *types.Header
+	headLock sync.Mutex
+}
+
+// NewLightClient creates a light client following the chain from the trusted header
+func NewLightClient(client proto.LightClient, trusted *types.Header, verify HeaderVerifier) *LightClient {
+	return &LightClient{
+		client: client,
+		verify: verify,
+		head:   trusted,
+	}
+}
+
+// DialLightClient opens the light protocol with the peer, and creates a light client following
+// its chain from the trusted header
+func DialLightClient(server *network.Server, id peer.ID, trusted *types.Header, verify HeaderVerifier) (*LightClient, error) {
+	stream, err := server.NewStream(lightV1, id)
+	if err != nil {
+		return nil, err
+	}
+	conn := libp2pGrpc.WrapClient(stream, server.GrpcCallOptions(lightV1, id)...)
+
+	return NewLightClient(proto.NewLightClient(conn), trusted, verify), nil
+}
+
+// Head returns the last verified header
+func (l *LightClient) Head() *types.Header {
+	l.headLock.Lock()
+	defer l.headLock.Unlock()
+
+	return l.head
+}
+
+// Sync downloads and verifies the headers after the head, up to the head of the peer
+func (l *LightClient) Sync(ctx context.Context) error {
+	l.headLock.Lock()
+	defer l.headLock.Unlock()
+
+	for {
+		resp, err := l.client.GetHeaders(ctx, &proto.LightHeadersRequest{
+			From:   l.head.Number + 1,
+			Amount: maxHeadersAmount,
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.Headers) == 0 {
+			return nil
+		}
+
+		for _, raw := range resp.Headers {
+			header := &types.Header{}
+			if err := header.UnmarshalRLP(raw); err != nil {
+				return err
+			}
+			if err := l.verify(l.head, header); err != nil {
+				return err
+			}
+			l.head = header
+		}
+	}
+}
+
+// GetAccount returns the account at the state of the header, nil if it doesn't exist
+func (l *LightClient) GetAccount(ctx context.Context, header *types.Header, addr types.Address) (*state.Account, error) {
+	resp, err := l.client.GetProof(ctx, &proto.LightProofRequest{
+		Number:  header.Number,
+		Address: addr.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyAccountProof(header, addr, resp.AccountProof)
+}
+
+// GetStorage returns the value of the storage slot of the account at the state of the header
+func (l *LightClient) GetStorage(ctx context.Context, header *types.Header, addr types.Address, key types.Hash) (types.Hash, error) {
+	resp, err := l.client.GetProof(ctx, &proto.LightProofRequest{
+		Number:      header.Number,
+		Address:     addr.String(),
+		StorageKeys: []string{key.String()},
+	})
+	if err != nil {
+		return types.Hash{}, err
+	}
+	if len(resp.StorageProofs) != 1 {
+		return types.Hash{}, itrie.ErrInvalidProof
+	}
+
+	account, err := verifyAccountProof(header, addr, resp.AccountProof)
+	if err != nil || account == nil {
+		return types.Hash{}, err
+	}
+
+	data, err := itrie.VerifyProof(account.Root, crypto.Keccak256(key.Bytes()), resp.StorageProofs[0].Proof)
+	if err != nil || data == nil {
+		return types.Hash{}, err
+	}
+
+	p := &fastrlp.Parser{}
+	v, err := p.Parse(data)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	value, err := v.Bytes()
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return types.BytesToHash(value), nil
+}
+
+// GetReceipt returns the receipt at the index of the block of the header
+func (l *LightClient) GetReceipt(ctx context.Context, header *types.Header, index uint64) (*types.Receipt, error) {
+	resp, err := l.client.GetReceiptProof(ctx, &proto.LightReceiptProofRequest{
+		BlockHash: header.Hash.String(),
+		Index:     index,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := itrie.VerifyIndexProof(header.ReceiptsRoot, int(index), resp.Proof)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &types.Receipt{}
+	if err := receipt.UnmarshalRLP(data); err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+func verifyAccountProof(header *types.Header, addr types.Address, proof [][]byte) (*state.Account, error) {
+	data, err := itrie.VerifyProof(header.StateRoot, crypto.Keccak256(addr.Bytes()), proof)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	account := &state.Account{}
+	if err := account.UnmarshalRlp(data); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
@@ -0,0 +1,140 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/state"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/0xPolygon/polygon-sdk/types/buildroot"
+	"github.com/stretchr/testify/assert"
+)
+
+// lightChain is a mock of the blockchain with the receipts of its blocks
+type lightChain struct {
+	*mockBlockchain
+
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func (b *lightChain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	receipts, ok := b.receipts[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return receipts, nil
+}
+
+func TestLightClient(t *testing.T) {
+	addr := types.StringToAddress("1")
+	slot := types.StringToHash("2")
+
+	storage := itrie.NewMemoryStorage()
+	st := itrie.NewState(storage)
+	txn := state.NewTxn(st, st.NewSnapshot())
+	txn.SetBalance(addr, big.NewInt(100))
+	txn.SetState(addr, slot, types.StringToHash("3"))
+	for i := 0; i < 50; i++ {
+		txn.SetBalance(types.BytesToAddress([]byte{byte(i), 2}), big.NewInt(1))
+	}
+	_, root := txn.Commit(false)
+
+	receipts := []*types.Receipt{}
+	for i := 0; i < 3; i++ {
+		receipt := &types.Receipt{
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs: []*types.Log{
+				{Address: addr, Topics: []types.Hash{slot}, Data: []byte{byte(i)}},
+			},
+		}
+		receipt.SetStatus(types.ReceiptSuccess)
+		receipts = append(receipts, receipt)
+	}
+
+	headers := []*types.Header{}
+	for i := uint64(0); i <= 5; i++ {
+		h := &types.Header{
+			Number:       i,
+			StateRoot:    types.BytesToHash(root),
+			ReceiptsRoot: types.EmptyRootHash,
+		}
+		if i > 0 {
+			h.ParentHash = headers[i-1].Hash
+		}
+		if i == 3 {
+			h.ReceiptsRoot = buildroot.CalculateReceiptsRoot(receipts)
+		}
+		headers = append(headers, h.ComputeHash())
+	}
+
+	chain := &lightChain{
+		mockBlockchain: NewMockBlockchain(headers),
+		receipts:       map[types.Hash][]*types.Receipt{headers[3].Hash: receipts},
+	}
+
+	srv0 := network.CreateServer(t, defaultNetworkConfig)
+	srv1 := network.CreateServer(t, defaultNetworkConfig)
+	defer srv0.Close()
+	defer srv1.Close()
+
+	ServeLightClients(srv0, chain, storage)
+	assert.NoError(t, srv1.Join(srv0.AddrInfo(), 10*time.Second))
+
+	verify := func(trusted, header *types.Header) error {
+		if header.ParentHash != trusted.Hash || header.Number != trusted.Number+1 {
+			return errors.New("not a child of the trusted header")
+		}
+		header.ComputeHash()
+
+		return nil
+	}
+
+	trusted := &types.Header{}
+	*trusted = *headers[0]
+
+	client, err := DialLightClient(srv1, srv0.AddrInfo().ID, trusted, verify)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	assert.NoError(t, client.Sync(ctx))
+	head := client.Head()
+	assert.Equal(t, headers[5].Hash, head.Hash)
+
+	// the state is proven against the state root of the header
+	account, err := client.GetAccount(ctx, head, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), account.Balance)
+
+	value, err := client.GetStorage(ctx, head, addr, slot)
+	assert.NoError(t, err)
+	assert.Equal(t, types.StringToHash("3"), value)
+
+	account, err = client.GetAccount(ctx, head, types.StringToAddress("4"))
+	assert.NoError(t, err)
+	assert.Nil(t, account)
+
+	// the receipts are proven against the receipts root of their block
+	receipt, err := client.GetReceipt(ctx, headers[3], 1)
+	assert.NoError(t, err)
+	assert.Equal(t, receipts[1].CumulativeGasUsed, receipt.CumulativeGasUsed)
+	assert.Equal(t, receipts[1].Logs[0].Data, receipt.Logs[0].Data)
+
+	// a header not matching the receipts is rejected
+	_, err = client.GetReceipt(ctx, headers[4], 1)
+	assert.Error(t, err)
+
+	// the headers not verified stop the sync
+	client = NewLightClient(client.client, trusted, func(trusted, header *types.Header) error {
+		return errors.New("invalid seals")
+	})
+	assert.Error(t, client.Sync(ctx))
+	assert.Equal(t, trusted, client.Head())
+}
@@ -0,0 +1,48 @@
+package protocol
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the sync protocol metrics
+type Metrics struct {
+	// No.of peer headers conflicting with the local canonical chain
+	ConflictingHeaders metrics.Counter
+	// No.of tracked competing branches
+	ForkBranches metrics.Gauge
+}
+
+// GetPrometheusMetrics return the sync protocol metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		ConflictingHeaders: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "protocol",
+			Name:      "conflicting_headers",
+			Help:      "Number of peer headers conflicting with the local canonical chain.",
+		}, labels).With(labelsWithValues...),
+		ForkBranches: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "protocol",
+			Name:      "fork_branches",
+			Help:      "Number of tracked branches competing with the local canonical chain.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational sync protocol metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		ConflictingHeaders: discard.NewCounter(),
+		ForkBranches:       discard.NewGauge(),
+	}
+}
@@ -0,0 +1,605 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.12.0
+// source: protocol/proto/light.proto
+
+package proto
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type LightHeadersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From   uint64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	Amount uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *LightHeadersRequest) Reset() {
+	*x = LightHeadersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightHeadersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightHeadersRequest) ProtoMessage() {}
+
+func (x *LightHeadersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightHeadersRequest.ProtoReflect.Descriptor instead.
+func (*LightHeadersRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LightHeadersRequest) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *LightHeadersRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type LightHeaders struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// headers are RLP encoded
+	Headers [][]byte `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+}
+
+func (x *LightHeaders) Reset() {
+	*x = LightHeaders{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightHeaders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightHeaders) ProtoMessage() {}
+
+func (x *LightHeaders) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightHeaders.ProtoReflect.Descriptor instead.
+func (*LightHeaders) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LightHeaders) GetHeaders() [][]byte {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+type LightProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number      uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Address     string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	StorageKeys []string `protobuf:"bytes,3,rep,name=storageKeys,proto3" json:"storageKeys,omitempty"`
+}
+
+func (x *LightProofRequest) Reset() {
+	*x = LightProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightProofRequest) ProtoMessage() {}
+
+func (x *LightProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightProofRequest.ProtoReflect.Descriptor instead.
+func (*LightProofRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LightProofRequest) GetNumber() uint64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *LightProofRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *LightProofRequest) GetStorageKeys() []string {
+	if x != nil {
+		return x.StorageKeys
+	}
+	return nil
+}
+
+type LightProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountProof  [][]byte             `protobuf:"bytes,1,rep,name=accountProof,proto3" json:"accountProof,omitempty"`
+	StorageProofs []*LightStorageProof `protobuf:"bytes,2,rep,name=storageProofs,proto3" json:"storageProofs,omitempty"`
+}
+
+func (x *LightProof) Reset() {
+	*x = LightProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightProof) ProtoMessage() {}
+
+func (x *LightProof) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightProof.ProtoReflect.Descriptor instead.
+func (*LightProof) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LightProof) GetAccountProof() [][]byte {
+	if x != nil {
+		return x.AccountProof
+	}
+	return nil
+}
+
+func (x *LightProof) GetStorageProofs() []*LightStorageProof {
+	if x != nil {
+		return x.StorageProofs
+	}
+	return nil
+}
+
+type LightStorageProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Proof [][]byte `protobuf:"bytes,2,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *LightStorageProof) Reset() {
+	*x = LightStorageProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightStorageProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightStorageProof) ProtoMessage() {}
+
+func (x *LightStorageProof) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightStorageProof.ProtoReflect.Descriptor instead.
+func (*LightStorageProof) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LightStorageProof) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LightStorageProof) GetProof() [][]byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type LightReceiptProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockHash string `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Index     uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (x *LightReceiptProofRequest) Reset() {
+	*x = LightReceiptProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightReceiptProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightReceiptProofRequest) ProtoMessage() {}
+
+func (x *LightReceiptProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightReceiptProofRequest.ProtoReflect.Descriptor instead.
+func (*LightReceiptProofRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LightReceiptProofRequest) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *LightReceiptProofRequest) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type LightReceiptProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Proof [][]byte `protobuf:"bytes,1,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *LightReceiptProof) Reset() {
+	*x = LightReceiptProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protocol_proto_light_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LightReceiptProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LightReceiptProof) ProtoMessage() {}
+
+func (x *LightReceiptProof) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_light_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LightReceiptProof.ProtoReflect.Descriptor instead.
+func (*LightReceiptProof) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_light_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LightReceiptProof) GetProof() [][]byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+var File_protocol_proto_light_proto protoreflect.FileDescriptor
+
+var file_protocol_proto_light_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31,
+	0x22, 0x41, 0x0a, 0x13, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x28, 0x0a, 0x0c, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x22, 0x67, 0x0a,
+	0x11, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x4b,
+	0x65, 0x79, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x6d, 0x0a, 0x0a, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x3b, 0x0a, 0x0d, 0x73, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0d, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x73, 0x22, 0x3b, 0x0a, 0x11, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x22, 0x4e, 0x0a, 0x18, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x22, 0x29, 0x0a, 0x11, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x32, 0xbb, 0x01,
+	0x0a, 0x05, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x37, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x17, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x12, 0x31, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x15, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x12, 0x46, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70,
+	0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1c, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68,
+	0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x11, 0x5a, 0x0f, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protocol_proto_light_proto_rawDescOnce sync.Once
+	file_protocol_proto_light_proto_rawDescData = file_protocol_proto_light_proto_rawDesc
+)
+
+func file_protocol_proto_light_proto_rawDescGZIP() []byte {
+	file_protocol_proto_light_proto_rawDescOnce.Do(func() {
+		file_protocol_proto_light_proto_rawDescData = protoimpl.X.CompressGZIP(file_protocol_proto_light_proto_rawDescData)
+	})
+	return file_protocol_proto_light_proto_rawDescData
+}
+
+var file_protocol_proto_light_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_protocol_proto_light_proto_goTypes = []interface{}{
+	(*LightHeadersRequest)(nil),      // 0: v1.LightHeadersRequest
+	(*LightHeaders)(nil),             // 1: v1.LightHeaders
+	(*LightProofRequest)(nil),        // 2: v1.LightProofRequest
+	(*LightProof)(nil),               // 3: v1.LightProof
+	(*LightStorageProof)(nil),        // 4: v1.LightStorageProof
+	(*LightReceiptProofRequest)(nil), // 5: v1.LightReceiptProofRequest
+	(*LightReceiptProof)(nil),        // 6: v1.LightReceiptProof
+}
+var file_protocol_proto_light_proto_depIdxs = []int32{
+	4, // 0: v1.LightProof.storageProofs:type_name -> v1.LightStorageProof
+	0, // 1: v1.Light.GetHeaders:input_type -> v1.LightHeadersRequest
+	2, // 2: v1.Light.GetProof:input_type -> v1.LightProofRequest
+	5, // 3: v1.Light.GetReceiptProof:input_type -> v1.LightReceiptProofRequest
+	1, // 4: v1.Light.GetHeaders:output_type -> v1.LightHeaders
+	3, // 5: v1.Light.GetProof:output_type -> v1.LightProof
+	6, // 6: v1.Light.GetReceiptProof:output_type -> v1.LightReceiptProof
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_protocol_proto_light_proto_init() }
+func file_protocol_proto_light_proto_init() {
+	if File_protocol_proto_light_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_protocol_proto_light_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightHeadersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protocol_proto_light_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightHeaders); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protocol_proto_light_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protocol_proto_light_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protocol_proto_light_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightStorageProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protocol_proto_light_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightReceiptProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protocol_proto_light_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LightReceiptProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_protocol_proto_light_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_protocol_proto_light_proto_goTypes,
+		DependencyIndexes: file_protocol_proto_light_proto_depIdxs,
+		MessageInfos:      file_protocol_proto_light_proto_msgTypes,
+	}.Build()
+	File_protocol_proto_light_proto = out.File
+	file_protocol_proto_light_proto_rawDesc = nil
+	file_protocol_proto_light_proto_goTypes = nil
+	file_protocol_proto_light_proto_depIdxs = nil
+}
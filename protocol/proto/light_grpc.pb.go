@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// LightClient is the client API for Light service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LightClient interface {
+	// GetHeaders returns a chain of headers, with the commit seals in their extra data
+	GetHeaders(ctx context.Context, in *LightHeadersRequest, opts ...grpc.CallOption) (*LightHeaders, error)
+	// GetProof returns the merkle proofs of an account and its storage slots against the state root of a block
+	GetProof(ctx context.Context, in *LightProofRequest, opts ...grpc.CallOption) (*LightProof, error)
+	// GetReceiptProof returns the merkle proof of a receipt against the receipts root of its block
+	GetReceiptProof(ctx context.Context, in *LightReceiptProofRequest, opts ...grpc.CallOption) (*LightReceiptProof, error)
+}
+
+type lightClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLightClient(cc grpc.ClientConnInterface) LightClient {
+	return &lightClient{cc}
+}
+
+func (c *lightClient) GetHeaders(ctx context.Context, in *LightHeadersRequest, opts ...grpc.CallOption) (*LightHeaders, error) {
+	out := new(LightHeaders)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetHeaders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightClient) GetProof(ctx context.Context, in *LightProofRequest, opts ...grpc.CallOption) (*LightProof, error) {
+	out := new(LightProof)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightClient) GetReceiptProof(ctx context.Context, in *LightReceiptProofRequest, opts ...grpc.CallOption) (*LightReceiptProof, error) {
+	out := new(LightReceiptProof)
+	err := c.cc.Invoke(ctx, "/v1.Light/GetReceiptProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LightServer is the server API for Light service.
+// All implementations must embed UnimplementedLightServer
+// for forward compatibility
+type LightServer interface {
+	// GetHeaders returns a chain of headers, with the commit seals in their extra data
+	GetHeaders(context.Context, *LightHeadersRequest) (*LightHeaders, error)
+	// GetProof returns the merkle proofs of an account and its storage slots against the state root of a block
+	GetProof(context.Context, *LightProofRequest) (*LightProof, error)
+	// GetReceiptProof returns the merkle proof of a receipt against the receipts root of its block
+	GetReceiptProof(context.Context, *LightReceiptProofRequest) (*LightReceiptProof, error)
+	mustEmbedUnimplementedLightServer()
+}
+
+// UnimplementedLightServer must be embedded to have forward compatible implementations.
+type UnimplementedLightServer struct {
+}
+
+func (UnimplementedLightServer) GetHeaders(context.Context, *LightHeadersRequest) (*LightHeaders, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeaders not implemented")
+}
+func (UnimplementedLightServer) GetProof(context.Context, *LightProofRequest) (*LightProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProof not implemented")
+}
+func (UnimplementedLightServer) GetReceiptProof(context.Context, *LightReceiptProofRequest) (*LightReceiptProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceiptProof not implemented")
+}
+func (UnimplementedLightServer) mustEmbedUnimplementedLightServer() {}
+
+// UnsafeLightServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LightServer will
+// result in compilation errors.
+type UnsafeLightServer interface {
+	mustEmbedUnimplementedLightServer()
+}
+
+func RegisterLightServer(s grpc.ServiceRegistrar, srv LightServer) {
+	s.RegisterService(&Light_ServiceDesc, srv)
+}
+
+func _Light_GetHeaders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LightHeadersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetHeaders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetHeaders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetHeaders(ctx, req.(*LightHeadersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Light_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LightProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetProof(ctx, req.(*LightProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Light_GetReceiptProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LightReceiptProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightServer).GetReceiptProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.Light/GetReceiptProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightServer).GetReceiptProof(ctx, req.(*LightReceiptProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Light_ServiceDesc is the grpc.ServiceDesc for Light service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Light_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.Light",
+	HandlerType: (*LightServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHeaders",
+			Handler:    _Light_GetHeaders_Handler,
+		},
+		{
+			MethodName: "GetProof",
+			Handler:    _Light_GetProof_Handler,
+		},
+		{
+			MethodName: "GetReceiptProof",
+			Handler:    _Light_GetReceiptProof_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "protocol/proto/light.proto",
+}
@@ -22,6 +22,7 @@ type serviceV1 struct {
 	logger hclog.Logger
 
 	store blockchainShim
+	state stateStore
 }
 
 type rlpObject interface {
@@ -47,6 +48,7 @@ func (s *serviceV1) Notify(ctx context.Context, req *proto.NotifyReq) (*empty.Em
 		return nil, err
 	}
 
+	s.syncer.observeHeader(id, b.Header)
 	s.syncer.enqueueBlock(id, b)
 	s.syncer.updatePeerStatus(id, status)
 	return &empty.Empty{}, nil
@@ -63,6 +65,10 @@ func (s *serviceV1) GetObjectsByHash(_ context.Context, req *proto.HashRequest)
 	if err != nil {
 		return nil, err
 	}
+	if req.Type == proto.HashRequest_STATE_NODES || req.Type == proto.HashRequest_CODES {
+		return s.getStateObjects(req.Type, hashes), nil
+	}
+
 	resp := &proto.Response{
 		Objs: []*proto.Response_Component{},
 	}
@@ -0,0 +1,305 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+	any "google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// pivotDistance is how far behind the head of the peer the state is downloaded,
+	// so the pivot is final and its state is still kept by the peer
+	pivotDistance = 64
+
+	// maxStateObjects is the maximum number of trie nodes or codes in a request
+	maxStateObjects = 384
+
+	// stateRequestTimeout is how long a batch of trie nodes or codes is waited for
+	stateRequestTimeout = 10 * time.Second
+
+	// maxStateFailures is the number of failed requests after which the peer is given up
+	maxStateFailures = 5
+)
+
+// stateStore is the storage of the state tries the trie nodes and codes are served from
+type stateStore interface {
+	Get(k []byte) ([]byte, bool)
+	GetCode(hash types.Hash) ([]byte, bool)
+}
+
+// EnableSnapshotSync makes the first sync of an empty chain download the state of a recent
+// pivot block, along with the headers up to it, instead of executing every block from genesis
+func (s *Syncer) EnableSnapshotSync() {
+	s.snapshotSync = true
+}
+
+// SetStateStorage sets the storage the state is served from, and downloaded to
+func (s *Syncer) SetStateStorage(storage itrie.Storage) {
+	s.stateStorage = storage
+}
+
+// getStateObjects returns the trie nodes or the codes with the given hashes, and an empty object
+// for the ones it doesn't have
+func (s *serviceV1) getStateObjects(typ proto.HashRequest_Type, hashes []types.Hash) *proto.Response {
+	if len(hashes) > maxStateObjects {
+		hashes = hashes[:maxStateObjects]
+	}
+
+	resp := &proto.Response{
+		Objs: []*proto.Response_Component{},
+	}
+	for _, hash := range hashes {
+		var data []byte
+		if s.state != nil {
+			if typ == proto.HashRequest_CODES {
+				data, _ = s.state.GetCode(hash)
+			} else {
+				data, _ = s.state.Get(hash.Bytes())
+			}
+		}
+		if data == nil {
+			data = []byte{}
+		}
+
+		resp.Objs = append(resp.Objs, &proto.Response_Component{
+			Spec: &any.Any{
+				Value: data,
+			},
+		})
+	}
+
+	return resp
+}
+
+// getStateItems requests the trie nodes or the codes from the peer
+func getStateItems(clt proto.V1Client, typ proto.HashRequest_Type, hashes []types.Hash) ([][]byte, error) {
+	input := make([]string, 0, len(hashes))
+	for _, h := range hashes {
+		input = append(input, h.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stateRequestTimeout)
+	defer cancel()
+
+	resp, err := clt.GetObjectsByHash(ctx, &proto.HashRequest{Hash: input, Type: typ})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Objs) > len(input) {
+		return nil, fmt.Errorf("not correct size")
+	}
+
+	res := make([][]byte, 0, len(resp.Objs))
+	for _, obj := range resp.Objs {
+		res = append(res, obj.Spec.GetValue())
+	}
+
+	return res, nil
+}
+
+func getReceipts(clt proto.V1Client, hash types.Hash) ([]*types.Receipt, error) {
+	resp, err := clt.GetObjectsByHash(
+		context.Background(),
+		&proto.HashRequest{Hash: []string{hash.String()}, Type: proto.HashRequest_RECEIPTS},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Objs) != 1 {
+		return nil, fmt.Errorf("not correct size")
+	}
+
+	var receipts types.Receipts
+	if err := receipts.UnmarshalRLP(resp.Objs[0].Spec.Value); err != nil {
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// SnapshotSyncWithPeer syncs an empty chain from a recent pivot block of the peer, if the snapshot sync
+// is enabled. The state of the pivot is downloaded first, every trie node being checked against the state
+// root of the pivot header. Then the headers up to the pivot are downloaded and verified, and the pivot
+// block is written along with its receipts. The blocks after the pivot are synced as usual
+func (s *Syncer) SnapshotSyncWithPeer(p *syncPeer) (err error) {
+	if !s.snapshotSync || s.stateStorage == nil {
+		return nil
+	}
+
+	if head := s.blockchain.Header(); head.Number != 0 || p.status.Number <= pivotDistance {
+		// the chain is synced block by block from now on
+		s.snapshotSync = false
+
+		return nil
+	}
+
+	pivotNumber := p.status.Number - pivotDistance
+	pivot, err := getHeader(p.client, &pivotNumber, nil)
+	if err != nil {
+		return err
+	}
+	if pivot == nil {
+		return fmt.Errorf("pivot header %d not found", pivotNumber)
+	}
+
+	s.logger.Info("snapshot sync started", "peer", p.peer, "pivot", pivot.Number, "root", pivot.StateRoot)
+
+	// the sync goes on with the blocks after the pivot, unless it fails
+	defer func() {
+		if err != nil {
+			s.tracker.done()
+		}
+	}()
+
+	s.tracker.start(SyncStageState, 0, pivot.Number)
+	if err := s.syncState(p, pivot.StateRoot); err != nil {
+		return err
+	}
+
+	s.tracker.start(SyncStageHeaders, 0, pivot.Number)
+	if err := s.syncHeaders(p, pivot); err != nil {
+		return err
+	}
+
+	bodies, err := getBodies(context.Background(), p.client, []types.Hash{pivot.Hash})
+	if err != nil {
+		return err
+	}
+	receipts, err := getReceipts(p.client, pivot.Hash)
+	if err != nil {
+		return err
+	}
+
+	block := &types.Block{
+		Header:       pivot,
+		Transactions: bodies[0].Transactions,
+		Uncles:       bodies[0].Uncles,
+	}
+	if err := s.blockchain.WriteSyncedBlock(block, receipts); err != nil {
+		return fmt.Errorf("failed to write the pivot block: %v", err)
+	}
+
+	s.snapshotSync = false
+	s.logger.Info("snapshot sync done", "pivot", pivot.Number)
+
+	return nil
+}
+
+// syncState downloads the missing trie nodes and codes of the state with the given root
+func (s *Syncer) syncState(p *syncPeer, root types.Hash) error {
+	sync := itrie.NewSync(root, s.stateStorage)
+
+	failures := 0
+	for !sync.Done() {
+		items := sync.Next(maxStateObjects)
+		if len(items) == 0 {
+			return fmt.Errorf("no state items left to download")
+		}
+
+		if err := s.syncStateItems(p, sync, items); err != nil {
+			failures++
+			if failures >= maxStateFailures {
+				return fmt.Errorf("failed to download the state: %v", err)
+			}
+			s.logger.Debug("failed to download state items", "peer", p.peer, "err", err)
+		} else {
+			failures = 0
+		}
+		sync.Flush()
+
+		nodes, codes := sync.Stats()
+		s.logger.Debug("snapshot sync state", "nodes", nodes, "codes", codes, "pending", sync.Pending())
+
+		s.tracker.update(func(p *SyncProgress) {
+			p.StateNodes, p.StateCodes, p.StatePending = nodes, codes, uint64(sync.Pending())
+		})
+	}
+
+	nodes, codes := sync.Stats()
+	s.logger.Info("snapshot sync state downloaded", "root", root, "nodes", nodes, "codes", codes)
+
+	return nil
+}
+
+// syncStateItems downloads a batch of items, and retries the ones the peer didn't return
+func (s *Syncer) syncStateItems(p *syncPeer, sync *itrie.Sync, items []itrie.SyncItem) error {
+	nodes, codes := []types.Hash{}, []types.Hash{}
+	for _, item := range items {
+		if item.Code {
+			codes = append(codes, item.Hash)
+		} else {
+			nodes = append(nodes, item.Hash)
+		}
+	}
+
+	var firstErr error
+	process := func(typ proto.HashRequest_Type, hashes []types.Hash, code bool) {
+		if len(hashes) == 0 {
+			return
+		}
+
+		data, err := getStateItems(p.client, typ, hashes)
+		for i, hash := range hashes {
+			item := itrie.SyncItem{Hash: hash, Code: code}
+
+			if err != nil || i >= len(data) || len(data[i]) == 0 {
+				sync.Retry(item)
+				continue
+			}
+			if perr := sync.Process(item, data[i]); perr != nil {
+				sync.Retry(item)
+				err = perr
+			}
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	process(proto.HashRequest_STATE_NODES, nodes, false)
+	process(proto.HashRequest_CODES, codes, true)
+
+	return firstErr
+}
+
+// syncHeaders downloads and writes the headers after the head, up to the parent of the pivot
+func (s *Syncer) syncHeaders(p *syncPeer, pivot *types.Header) error {
+	for {
+		head := s.blockchain.Header()
+		if head.Number+1 >= pivot.Number {
+			return nil
+		}
+
+		amount := int64(pivot.Number - head.Number - 1)
+		if amount > maxHeadersAmount {
+			amount = maxHeadersAmount
+		}
+
+		headers, err := getHeaders(context.Background(), p.client, &proto.GetHeadersRequest{
+			Number: int64(head.Number + 1),
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+		if len(headers) == 0 {
+			return fmt.Errorf("headers after %d not found", head.Number)
+		}
+
+		if err := s.blockchain.WriteSyncedHeaders(headers); err != nil {
+			return fmt.Errorf("failed to write synced headers: %v", err)
+		}
+
+		s.logger.Debug("snapshot sync headers", "number", headers[len(headers)-1].Number, "pivot", pivot.Number)
+
+		s.tracker.update(func(p *SyncProgress) {
+			p.HeadersDownloaded += uint64(len(headers))
+			p.CurrentBlock = headers[len(headers)-1].Number
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package protocol
+
+import (
+	"sync"
+	"time"
+)
+
+// syncProgressSubBuffer is the number of progress events buffered for a subscriber,
+// the events are dropped for the subscribers falling behind
+const syncProgressSubBuffer = 64
+
+// SyncStage is a stage of the sync of the chain
+type SyncStage string
+
+const (
+	// SyncStageState downloads the state of the pivot block of a snapshot sync
+	SyncStageState SyncStage = "state"
+
+	// SyncStageHeaders writes the headers up to the pivot block of a snapshot sync
+	SyncStageHeaders SyncStage = "headers"
+
+	// SyncStageBlocks downloads and executes the blocks up to the head of the peers
+	SyncStageBlocks SyncStage = "blocks"
+)
+
+// SyncProgress is the progress of a running sync
+type SyncProgress struct {
+	Stage SyncStage
+
+	// StartingBlock is the head when the sync started
+	StartingBlock uint64
+	CurrentBlock  uint64
+	HighestBlock  uint64
+
+	HeadersDownloaded uint64
+	BlocksDownloaded  uint64
+	BlocksExecuted    uint64
+
+	// StateNodes and StateCodes are the state entries downloaded by a snapshot sync,
+	// and StatePending the ones known to be missing
+	StateNodes   uint64
+	StateCodes   uint64
+	StatePending uint64
+
+	StartedAt time.Time
+
+	// ETA is the estimated time left of the blocks stage, from the rate of the executed blocks. 0 if unknown
+	ETA time.Duration
+
+	// Done is set on the last event of a sync, the node isn't syncing anymore
+	Done bool
+}
+
+func (p *SyncProgress) copy() *SyncProgress {
+	pp := new(SyncProgress)
+	*pp = *p
+
+	return pp
+}
+
+// SyncTracker tracks the progress of the syncs of the syncer, and delivers it to the subscribers
+type SyncTracker struct {
+	lock     sync.Mutex
+	progress *SyncProgress
+
+	// blocksStart is when the blocks stage started, and blocksExecuted the blocks executed before it
+	blocksStart    time.Time
+	blocksExecuted uint64
+
+	subs map[uint64]chan *SyncProgress
+	next uint64
+}
+
+// NewSyncTracker creates a new tracker, not syncing
+func NewSyncTracker() *SyncTracker {
+	return &SyncTracker{
+		subs: map[uint64]chan *SyncProgress{},
+	}
+}
+
+// SyncProgress returns the progress of the running sync, nil if the node isn't syncing
+func (t *SyncTracker) SyncProgress() *SyncProgress {
+	if t == nil {
+		return nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.progress == nil {
+		return nil
+	}
+
+	return t.progress.copy()
+}
+
+// Subscribe returns the progress events of the syncs, until unsubscribed
+func (t *SyncTracker) Subscribe() (<-chan *SyncProgress, func()) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	id := t.next
+	t.next++
+
+	ch := make(chan *SyncProgress, syncProgressSubBuffer)
+	t.subs[id] = ch
+
+	unsubscribe := func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
+		if _, ok := t.subs[id]; ok {
+			delete(t.subs, id)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// start enters the stage of the sync, the counters of the running sync are kept
+func (t *SyncTracker) start(stage SyncStage, current, highest uint64) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.progress == nil {
+		t.progress = &SyncProgress{
+			StartingBlock: current,
+			StartedAt:     time.Now(),
+		}
+	}
+	t.progress.Stage = stage
+	t.progress.CurrentBlock = current
+	t.progress.HighestBlock = highest
+
+	if stage == SyncStageBlocks {
+		t.blocksStart = time.Now()
+		t.blocksExecuted = t.progress.BlocksExecuted
+	}
+
+	t.notify()
+}
+
+// update applies the change to the progress of the running sync
+func (t *SyncTracker) update(fn func(p *SyncProgress)) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.progress == nil {
+		return
+	}
+	fn(t.progress)
+
+	t.progress.ETA = 0
+	if executed := t.progress.BlocksExecuted - t.blocksExecuted; t.progress.Stage == SyncStageBlocks && executed != 0 &&
+		t.progress.HighestBlock > t.progress.CurrentBlock {
+		left := t.progress.HighestBlock - t.progress.CurrentBlock
+		t.progress.ETA = time.Duration(float64(time.Since(t.blocksStart)) / float64(executed) * float64(left))
+	}
+
+	t.notify()
+}
+
+// done ends the running sync, if any
+func (t *SyncTracker) done() {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.progress == nil {
+		return
+	}
+	t.progress.Done = true
+	t.progress.ETA = 0
+	t.notify()
+
+	t.progress = nil
+}
+
+// notify sends the progress to the subscribers, the lock is held
+func (t *SyncTracker) notify() {
+	for _, ch := range t.subs {
+		select {
+		case ch <- t.progress.copy():
+		default:
+		}
+	}
+}
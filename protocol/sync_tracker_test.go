@@ -0,0 +1,62 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncTracker(t *testing.T) {
+	tracker := NewSyncTracker()
+	assert.Nil(t, tracker.SyncProgress())
+
+	ch, unsubscribe := tracker.Subscribe()
+
+	// the updates before a sync are ignored
+	tracker.update(func(p *SyncProgress) {
+		p.BlocksExecuted++
+	})
+	tracker.done()
+	assert.Len(t, ch, 0)
+
+	// the counters are kept across the stages of a sync
+	tracker.start(SyncStageHeaders, 0, 100)
+	tracker.update(func(p *SyncProgress) {
+		p.HeadersDownloaded = 50
+		p.CurrentBlock = 50
+	})
+	tracker.start(SyncStageBlocks, 50, 150)
+	tracker.update(func(p *SyncProgress) {
+		p.BlocksExecuted = 10
+		p.CurrentBlock = 60
+	})
+
+	progress := tracker.SyncProgress()
+	assert.Equal(t, SyncStageBlocks, progress.Stage)
+	assert.Equal(t, uint64(0), progress.StartingBlock)
+	assert.Equal(t, uint64(60), progress.CurrentBlock)
+	assert.Equal(t, uint64(150), progress.HighestBlock)
+	assert.Equal(t, uint64(50), progress.HeadersDownloaded)
+	assert.NotZero(t, progress.ETA)
+
+	tracker.done()
+	assert.Nil(t, tracker.SyncProgress())
+
+	// every change is delivered, the last one ending the sync
+	assert.Len(t, ch, 5)
+	for i := 0; i < 4; i++ {
+		assert.False(t, (<-ch).Done)
+	}
+	last := <-ch
+	assert.True(t, last.Done)
+	assert.Equal(t, uint64(60), last.CurrentBlock)
+
+	unsubscribe()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	// a syncer without tracker doesn't report its progress
+	var nilTracker *SyncTracker
+	nilTracker.start(SyncStageBlocks, 0, 1)
+	assert.Nil(t, nilTracker.SyncProgress())
+}
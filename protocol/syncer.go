@@ -13,6 +13,7 @@ import (
 	"github.com/0xPolygon/polygon-sdk/network"
 	libp2pGrpc "github.com/0xPolygon/polygon-sdk/network/grpc"
 	"github.com/0xPolygon/polygon-sdk/protocol/proto"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -204,6 +205,22 @@ type Syncer struct {
 	statusLock sync.Mutex
 
 	server *network.Server
+
+	forkMonitor *ForkMonitor
+
+	// tracker reports the progress of the syncs, if set
+	tracker *SyncTracker
+
+	// snapshotSync is set until the first sync, if it syncs from a pivot block
+	snapshotSync bool
+	stateStorage itrie.Storage
+
+	// blacklist holds the peers that served bad data, until they are synced from again
+	blacklist     map[peer.ID]time.Time
+	blacklistLock sync.Mutex
+
+	// fetcher downloads the headers and the blocks from the peers
+	fetcher blockFetcher
 }
 
 // NewSyncer creates a new Syncer instance
@@ -213,6 +230,8 @@ func NewSyncer(logger hclog.Logger, server *network.Server, blockchain blockchai
 		stopCh:     make(chan struct{}),
 		blockchain: blockchain,
 		server:     server,
+		blacklist:  map[peer.ID]time.Time{},
+		fetcher:    peerFetcher{},
 	}
 
 	return s
@@ -266,6 +285,23 @@ func (s *Syncer) syncCurrentStatus() {
 
 const syncerV1 = "/syncer/0.1"
 
+// SetForkMonitor sets the fork monitor that checks the blocks announced by the peers
+func (s *Syncer) SetForkMonitor(monitor *ForkMonitor) {
+	s.forkMonitor = monitor
+}
+
+// SetSyncTracker sets the tracker the progress of the syncs is reported to
+func (s *Syncer) SetSyncTracker(tracker *SyncTracker) {
+	s.tracker = tracker
+}
+
+// observeHeader reports the header announced by the peer to the fork monitor
+func (s *Syncer) observeHeader(peerID peer.ID, header *types.Header) {
+	if s.forkMonitor != nil {
+		s.forkMonitor.Observe(peerID, header)
+	}
+}
+
 // enqueueBlock adds the specific block to the peerID queue
 func (s *Syncer) enqueueBlock(peerID peer.ID, b *types.Block) {
 	s.logger.Debug("enqueue block", "peer", peerID, "number", b.Number(), "hash", b.Hash())
@@ -327,12 +363,15 @@ func (s *Syncer) Broadcast(b *types.Block) {
 // Start starts the syncer protocol
 func (s *Syncer) Start() {
 	s.serviceV1 = &serviceV1{syncer: s, logger: hclog.NewNullLogger(), store: s.blockchain}
+	if s.stateStorage != nil {
+		s.serviceV1.state = s.stateStorage
+	}
 
 	// Run the blockchain event listener loop
 	go s.syncCurrentStatus()
 
 	// Register the grpc protocol for syncer
-	grpcStream := libp2pGrpc.NewGrpcStream()
+	grpcStream := libp2pGrpc.NewGrpcStream(s.server.GrpcServerOptions(syncerV1)...)
 	proto.RegisterV1Server(grpcStream.GrpcServer(), s.serviceV1)
 	grpcStream.Serve()
 
@@ -358,7 +397,8 @@ func (s *Syncer) Start() {
 					s.logger.Error("failed to open a stream", "err", err)
 					continue
 				}
-				if err := s.HandleNewPeer(evnt.PeerID, libp2pGrpc.WrapClient(stream)); err != nil {
+				conn := libp2pGrpc.WrapClient(stream, s.server.GrpcCallOptions(syncerV1, evnt.PeerID)...)
+				if err := s.HandleNewPeer(evnt.PeerID, conn); err != nil {
 					s.logger.Error("failed to handle user", "err", err)
 				}
 
@@ -377,6 +417,10 @@ func (s *Syncer) BestPeer() *syncPeer {
 	var bestTd *big.Int
 
 	s.peers.Range(func(peerID, peer interface{}) bool {
+		if s.isBlacklisted(peer.(*syncPeer).peer) {
+			return true
+		}
+
 		status := peer.(*syncPeer).status
 		if bestPeer == nil || status.Difficulty.Cmp(bestTd) > 0 {
 			bestPeer, bestTd = peer.(*syncPeer), status.Difficulty
@@ -397,6 +441,21 @@ func (s *Syncer) BestPeer() *syncPeer {
 	return bestPeer
 }
 
+// HighestBlock returns the highest block announced by the peers, 0 if there are none
+func (s *Syncer) HighestBlock() uint64 {
+	var highest uint64
+
+	s.peers.Range(func(peerID, peer interface{}) bool {
+		if number := peer.(*syncPeer).Number(); number > highest {
+			highest = number
+		}
+
+		return true
+	})
+
+	return highest
+}
+
 // HandleNewPeer is a helper method that is used to handle new user connections within the Syncer
 func (s *Syncer) HandleNewPeer(peerID peer.ID, conn *grpc.ClientConn) error {
 	// watch for changes of the other node first
@@ -521,6 +580,9 @@ func (s *Syncer) WatchSyncWithPeer(p *syncPeer, handler func(b *types.Block) boo
 		}
 		if err := s.blockchain.WriteBlocks([]*types.Block{b}); err != nil {
 			s.logger.Error("failed to write block", "err", err)
+			if fault := writeFault(err); fault == network.FaultBadReceipts {
+				s.blacklistPeer(p, fault, err)
+			}
 			break
 		}
 		if handler(b) {
@@ -538,59 +600,38 @@ func (s *Syncer) logSyncPeerPopBlockError(err error, peer *syncPeer) {
 	}
 }
 
+// BulkSyncWithPeer syncs the blocks up to the head of the peer. The blocks are downloaded in parallel
+// from all the peers having them, and verified and written in order
 func (s *Syncer) BulkSyncWithPeer(p *syncPeer) error {
+	defer s.tracker.done()
+
 	// find the common ancestor
-	ancestor, fork, err := s.findCommonAncestor(p.client, p.status)
+	ancestor, _, err := s.findCommonAncestor(p.client, p.status)
 	if err != nil {
 		return err
 	}
 
-	// find in batches
 	s.logger.Debug("fork found", "ancestor", ancestor.Number)
 
-	startBlock := fork
+	parent := ancestor
 
 	var lastTarget uint64
 
 	// sync up to the current known header
 	for {
 		// update target
-		target := p.status.Number
+		target := p.Number()
 		if target == lastTarget {
 			// there are no more changes to pull for now
 			break
 		}
 
-		for {
-			s.logger.Debug("sync up to block", "from", startBlock.Number, "to", target)
-
-			// start to synchronize with it
-			sk := &skeleton{
-				span: 10,
-				num:  5,
-			}
-
-			if err := sk.build(p.client, startBlock.Hash); err != nil {
-				return fmt.Errorf("failed to build skeleton: %v", err)
-			}
-
-			// fill skeleton
-			for indx := range sk.slots {
-				sk.fillSlot(uint64(indx), p.client) //nolint
-			}
-
-			// sync the data
-			for _, slot := range sk.slots {
-				if err := s.blockchain.WriteBlocks(slot.blocks); err != nil {
-					return fmt.Errorf("failed to write bulk sync blocks: %v", err)
-				}
-			}
-
-			// try to get the next block
-			startBlock = sk.LastHeader()
+		if target > parent.Number {
+			s.logger.Debug("sync up to block", "from", parent.Number+1, "to", target)
+			s.tracker.start(SyncStageBlocks, parent.Number, target)
 
-			if startBlock.Number >= uint64(target) {
-				break
+			if parent, err = s.downloadBlocks(s.downloadPeers(p), parent, target); err != nil {
+				return fmt.Errorf("failed to write bulk sync blocks: %v", err)
 			}
 		}
 
@@ -429,6 +429,14 @@ func (m *mockBlockStore) WriteBlocks(blocks []*types.Block) error {
 	return nil
 }
 
+func (m *mockBlockStore) WriteSyncedHeaders(headers []*types.Header) error {
+	return m.WriteBlocks(blockchain.HeadersToBlocks(headers))
+}
+
+func (m *mockBlockStore) WriteSyncedBlock(block *types.Block, receipts []*types.Receipt) error {
+	return m.WriteBlocks([]*types.Block{block})
+}
+
 func (m *mockBlockStore) CurrentTD() *big.Int {
 	return m.td
 }
@@ -260,6 +260,14 @@ func (b *mockBlockchain) WriteBlocks(blocks []*types.Block) error {
 	return nil
 }
 
+func (b *mockBlockchain) WriteSyncedHeaders(headers []*types.Header) error {
+	return b.WriteBlocks(blockchain.HeadersToBlocks(headers))
+}
+
+func (b *mockBlockchain) WriteSyncedBlock(block *types.Block, receipts []*types.Receipt) error {
+	return b.WriteBlocks([]*types.Block{block})
+}
+
 // mockSubscription is a mock of subscription for blockchain events
 type mockSubscription struct {
 	eventCh chan *blockchain.Event
@@ -56,7 +56,7 @@ func SecretsManagerFactory(
 // Setup sets up the local SecretsManager
 func (l *LocalSecretsManager) Setup() error {
 	// The local SecretsManager initially handles only the
-	// validator and networking private keys
+	// validator and networking private keys, and the storage key
 	l.secretPathMapLock.Lock()
 	defer l.secretPathMapLock.Unlock()
 
@@ -81,6 +81,12 @@ func (l *LocalSecretsManager) Setup() error {
 		secrets.NetworkKeyLocal,
 	)
 
+	// baseDir/storage.key
+	l.secretPathMap[secrets.StorageKey] = filepath.Join(
+		l.path,
+		secrets.StorageKeyLocal,
+	)
+
 	return nil
 }
 
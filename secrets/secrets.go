@@ -28,12 +28,16 @@ const (
 
 	// NetworkKey is the libp2p private key secret used for networking
 	NetworkKey = "network-key"
+
+	// StorageKey is the key used to encrypt the data stores at rest
+	StorageKey = "storage-key"
 )
 
 // Define constant file names for the local StorageManager
 const (
 	ValidatorKeyLocal = "validator.key"
 	NetworkKeyLocal   = "libp2p.key"
+	StorageKeyLocal   = "storage.key"
 )
 
 // Define constant folder names for the local StorageManager
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/umbracle/go-web3/abi"
+)
+
+// contractAllowlist reads the peer allowlist from the getAllowlist() view of a contract,
+// at the head of the chain. The entries are peer IDs or libp2p addresses
+type contractAllowlist struct {
+	srv     *Server
+	address types.Address
+}
+
+// Allowlist implements the network allowlist source
+func (c *contractAllowlist) Allowlist() ([]peer.ID, error) {
+	// the network is started before the chain is loaded
+	if c.srv.blockchain == nil {
+		return nil, network.ErrAllowlistUnavailable
+	}
+
+	method := abis.PeerAllowlistABI.Methods["getAllowlist"]
+
+	header := c.srv.blockchain.Header()
+	transition, err := c.srv.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+	transition.SetNoBaseFee(true)
+
+	result, err := transition.Apply(&types.Transaction{
+		From:     types.ZeroAddress,
+		To:       &c.address,
+		Input:    method.ID(),
+		Gas:      header.GasLimit,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Failed() {
+		return nil, fmt.Errorf("allowlist contract call failed: %v", result.Err)
+	}
+
+	return decodeAllowlist(result.ReturnValue)
+}
+
+// decodeAllowlist decodes the peers returned by the getAllowlist() view
+func decodeAllowlist(data []byte) ([]peer.ID, error) {
+	method := abis.PeerAllowlistABI.Methods["getAllowlist"]
+
+	decoded, err := abi.Decode(method.Outputs, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowlist: %v", err)
+	}
+	entries, ok := decoded.(map[string]interface{})["0"].([]string)
+	if !ok {
+		return nil, fmt.Errorf("invalid allowlist")
+	}
+
+	ids := []peer.ID{}
+	for _, entry := range entries {
+		id, err := network.StringToPeerID(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist entry %s: %v", entry, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
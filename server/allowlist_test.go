@@ -0,0 +1,37 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/go-web3/abi"
+)
+
+func TestDecodeAllowlist(t *testing.T) {
+	id := "16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW"
+	addr := "/ip4/127.0.0.1/tcp/1478/p2p/16Uiu2HAkwMANiPL9bFTVRkxvEPqPRjygQsH4JoDNuYWjpzSBCzwu"
+
+	outputs := abis.PeerAllowlistABI.Methods["getAllowlist"].Outputs
+
+	data, err := abi.Encode([]interface{}{[]string{id, addr}}, outputs)
+	assert.NoError(t, err)
+
+	ids, err := decodeAllowlist(data)
+	assert.NoError(t, err)
+
+	expected := []peer.ID{}
+	for _, raw := range []string{id, "16Uiu2HAkwMANiPL9bFTVRkxvEPqPRjygQsH4JoDNuYWjpzSBCzwu"} {
+		decoded, err := peer.Decode(raw)
+		assert.NoError(t, err)
+		expected = append(expected, decoded)
+	}
+	assert.Equal(t, expected, ids)
+
+	data, err = abi.Encode([]interface{}{[]string{"invalid"}}, outputs)
+	assert.NoError(t, err)
+
+	_, err = decodeAllowlist(data)
+	assert.Error(t, err)
+}
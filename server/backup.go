@@ -0,0 +1,81 @@
+package server
+
+import (
+	"io"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/helper/backup"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+)
+
+// backupStores are the data stores in a backup, in the order of their snapshots. The blocks
+// are written after their state, so the state of the head of the blockchain snapshot is in
+// the snapshot of the trie taken after it
+var backupStores = []string{"blockchain", "trie", "archive", "fork", "bridge"}
+
+// writeBackup writes a consistent backup of the data stores and of the freezer, while the
+// node runs. The stores are read from snapshots, taken one after the other
+func (s *Server) writeBackup(w io.Writer) (*backup.Metadata, error) {
+	header := s.blockchain.Header()
+
+	metadata := &backup.Metadata{
+		Version:    backup.Version,
+		Created:    time.Now().UTC(),
+		HeadNumber: header.Number,
+		HeadHash:   header.Hash.String(),
+	}
+
+	iterators := map[string]kvdb.Iterator{}
+	defer func() {
+		for _, iter := range iterators {
+			iter.Release()
+		}
+	}()
+
+	for _, store := range backupStores {
+		db, ok := s.databases[store]
+		if !ok {
+			continue
+		}
+		iterators[store] = db.NewIterator(nil)
+		metadata.Stores = append(metadata.Stores, store)
+	}
+
+	// the blocks are only removed from the blockchain store once they are frozen,
+	// so the freezer has every block missing from the snapshot
+	if s.freezer != nil {
+		metadata.Frozen = s.freezer.Items()
+	}
+
+	bw, err := backup.NewWriter(w, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, store := range metadata.Stores {
+		iter := iterators[store]
+		for iter.Next() {
+			if err := bw.WriteEntry(store, iter.Key(), iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.freezer != nil {
+		err := s.freezer.ExportFiles(metadata.Frozen, func(name string, size int64, r io.Reader) error {
+			return bw.WriteFile("ancient/"+name, size, r)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := bw.Close(); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/bridge"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/secrets"
+	"github.com/0xPolygon/polygon-sdk/txpool"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// bridgeHub provides the chain and the pool to the bridge
+type bridgeHub struct {
+	*blockchain.Blockchain
+
+	txpool *txpool.TxPool
+	state  *txpoolHub
+}
+
+// GetNonce returns the next nonce of the account, after its transactions in the pool
+func (b *bridgeHub) GetNonce(addr types.Address) uint64 {
+	if nonce, ok := b.txpool.GetNonce(addr); ok {
+		return nonce
+	}
+
+	return b.state.GetNonce(b.Header().StateRoot, addr)
+}
+
+// AddTx adds the transaction to the pool
+func (b *bridgeHub) AddTx(tx *types.Transaction) error {
+	return b.txpool.AddTx(tx)
+}
+
+// setupBridge creates the bridge to the root chain. The sealing nodes relay the deposits
+// with the validator key once started
+func (s *Server) setupBridge() error {
+	hub := &bridgeHub{
+		Blockchain: s.blockchain,
+		txpool:     s.txpool,
+		state: &txpoolHub{
+			state:      s.state,
+			Blockchain: s.blockchain,
+		},
+	}
+
+	key, err := crypto.ReadConsensusKey(s.secretsManager)
+	if err != nil && s.config.Seal {
+		return fmt.Errorf("the deposits are relayed with the %s, failed to read it: %v", secrets.ValidatorKey, err)
+	}
+
+	db, err := s.openDatabase("bridge")
+	if err != nil {
+		return err
+	}
+
+	signer := crypto.NewLondonSigner(uint64(s.config.Chain.Params.ChainID))
+	b, err := bridge.NewBridge(s.logger, s.config.Bridge, hub, db, key, signer)
+	if err != nil {
+		db.Close()
+		return fmt.Errorf("failed to setup the bridge: %v", err)
+	}
+	s.bridge = b
+
+	return nil
+}
+
+// zeroGasAllowlist returns the accounts of the zero gas allowlist of the pool, the state receiver
+// of the bridge included, the state sync transactions have no fee
+func (s *Server) zeroGasAllowlist(allowlist []types.Address) []types.Address {
+	if s.config.Bridge == nil {
+		return allowlist
+	}
+
+	return append(append([]types.Address{}, allowlist...), s.config.Bridge.StateReceiver)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/checkpoint"
+	"github.com/0xPolygon/polygon-sdk/consensus"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// checkpointHub provides the finalized blocks to the checkpointing
+type checkpointHub struct {
+	*blockchain.Blockchain
+
+	consensus consensus.Consensus
+}
+
+// FinalitySignatures returns the signatures of the block, none if the consensus doesn't sign its blocks
+func (c *checkpointHub) FinalitySignatures(header *types.Header) ([][]byte, error) {
+	if prover, ok := c.consensus.(consensus.FinalityProver); ok {
+		return prover.FinalitySignatures(header)
+	}
+
+	return nil, nil
+}
+
+// setupCheckpoint verifies the chain against the latest checkpoint, and starts the submission of the next ones
+func (s *Server) setupCheckpoint() error {
+	hub := &checkpointHub{
+		Blockchain: s.blockchain,
+		consensus:  s.consensus,
+	}
+
+	if _, ok := s.consensus.(consensus.FinalityProver); !ok {
+		s.logger.Warn("the consensus doesn't sign its blocks, the checkpoints have no signatures")
+	}
+
+	service, err := checkpoint.NewService(s.logger, s.config.Checkpoint, hub)
+	if err != nil {
+		return err
+	}
+	if err := service.Start(); err != nil {
+		return fmt.Errorf("failed to start the checkpointing: %v", err)
+	}
+	s.checkpoint = service
+
+	return nil
+}
@@ -2,32 +2,97 @@ package server
 
 import (
 	"net"
+	"time"
 
+	"github.com/0xPolygon/polygon-sdk/bridge"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/checkpoint"
+	"github.com/0xPolygon/polygon-sdk/ethstats"
+	"github.com/0xPolygon/polygon-sdk/exporter"
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/helper/logging"
+	"github.com/0xPolygon/polygon-sdk/helper/tracing"
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
 	"github.com/0xPolygon/polygon-sdk/network"
 	"github.com/0xPolygon/polygon-sdk/secrets"
+	"github.com/0xPolygon/polygon-sdk/state/fork"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
 	"github.com/0xPolygon/polygon-sdk/types"
 )
 
 const DefaultGRPCPort int = 9632
 const DefaultJSONRPCPort int = 8545
 
+// DefaultShutdownTimeout is the default time the consensus has to complete its round when the node is stopped
+const DefaultShutdownTimeout = 30 * time.Second
+
 // Config is used to parametrize the minimal client
 type Config struct {
 	Chain *chain.Chain
 
 	JSONRPCAddr *net.TCPAddr
+	JSONRPC     *jsonrpc.AccessConfig
+	Personal    bool
+	Admin       bool
+	Debug       bool
+	Trace       bool
+	LogsBlockRange  uint64
+	LogsResultLimit uint64
+	CallTimeout     time.Duration
+	CallGasCap      uint64
+	GraphQL         bool
+	LoadShed        *jsonrpc.LoadShedConfig
+	IPCPath         string
+	RateLimit       *jsonrpc.RateLimitConfig
+	CallCache       *jsonrpc.CallCacheConfig
 	GRPCAddr    *net.TCPAddr
+	GRPCAccess  *OperatorAccessConfig
 	LibP2PAddr  *net.TCPAddr
 	Telemetry   *Telemetry
+	Health      *HealthConfig
+	EthStats    *ethstats.Config
+	Checkpoint  *checkpoint.Config
+	Bridge      *bridge.Config
+	Export      *exporter.Config
+	LogLevels   *logging.Levels
 	Network     *network.Config
+	AllowlistContract *types.Address
+	ConsensusNetwork  *network.Config
 	DataDir     string
+	// StoreDirs are the data stores placed outside of the data directory, like the trie on a faster volume
+	StoreDirs         common.StoreDirs
+	StorageEncryption bool
+	DBBackend         string
+	FreezerThreshold  uint64
+	MaxReorgDepth     uint64
+	ExecParallelism   uint64
+	EVMProfiler       bool
+	Cache             uint64
+	ValidatorRegistry string
+	SnapshotSync      bool
+	LightServe        bool
+	AddressIndex      bool
+	Pruning           *itrie.PruningConfig
+	Archive           bool
+	Fork              *fork.Config
 	Seal        bool
+	ShutdownTimeout time.Duration
 	Locals      []types.Address
 	NoLocals    bool
 	PriceLimit  uint64
 	MaxSlots    uint64
+	PriceBump   uint64
+	QueueLifetime time.Duration
+	TxLifetime    time.Duration
+	MaxAccountPending uint64
+	MaxAccountQueued  uint64
+	MinGasPrice       uint64
+	ZeroGasAllowlist  []types.Address
+	TxPoolJournal string
 	SecretsManager *secrets.SecretsManagerConfig
+
+	// Reload reads the config again, for the settings that can change at runtime. Nil if it can't be reloaded
+	Reload func() (*Config, error)
 }
 
 // DefaultConfig returns the default config for JSON-RPC, GRPC (ports) and Networking
@@ -37,6 +102,7 @@ func DefaultConfig() *Config {
 		GRPCAddr:    &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: DefaultGRPCPort},
 		Network:     network.DefaultConfig(),
 		Telemetry:   &Telemetry{PrometheusAddr: nil},
+		ShutdownTimeout: DefaultShutdownTimeout,
 		SecretsManager: nil,
 	}
 }
@@ -44,4 +110,7 @@ func DefaultConfig() *Config {
 // Telemetry holds the config details for metric services
 type Telemetry struct {
 	PrometheusAddr *net.TCPAddr
+
+	// Tracing exports the traces of the block processing to an OTLP collector, if set
+	Tracing *tracing.Config
 }
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/consensus"
+	"github.com/0xPolygon/polygon-sdk/ethstats"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/txpool"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// ethstatsHub provides the stats of the node to the ethstats reporting
+type ethstatsHub struct {
+	*blockchain.Blockchain
+
+	txpool    *txpool.TxPool
+	network   *network.Server
+	consensus consensus.Consensus
+}
+
+func (e *ethstatsHub) BlockCreator(header *types.Header) types.Address {
+	creator, err := e.consensus.GetBlockCreator(header)
+	if err != nil {
+		return header.Miner
+	}
+
+	return creator
+}
+
+func (e *ethstatsHub) PendingTxs() uint64 {
+	return e.txpool.Length()
+}
+
+func (e *ethstatsHub) PeerCount() int {
+	return len(e.network.Peers())
+}
+
+func (e *ethstatsHub) status() *consensus.Status {
+	if reporter, ok := e.consensus.(consensus.StatusReporter); ok {
+		return reporter.Status()
+	}
+
+	return &consensus.Status{}
+}
+
+func (e *ethstatsHub) Syncing() bool {
+	return e.status().HighestBlock > e.Header().Number
+}
+
+func (e *ethstatsHub) Sealing() bool {
+	return e.status().Participating
+}
+
+// setupEthstats starts the reports of the stats to the netstats server
+func (s *Server) setupEthstats() {
+	hub := &ethstatsHub{
+		Blockchain: s.blockchain,
+		txpool:     s.txpool,
+		network:    s.network,
+		consensus:  s.consensus,
+	}
+	info := &ethstats.NodeInfo{
+		Network: fmt.Sprintf("%d", s.config.Chain.Params.ChainID),
+		Port:    s.config.Network.Addr.Port,
+	}
+
+	s.ethstats = ethstats.NewService(s.logger, s.config.EthStats, hub, s.eventBus, info)
+	s.ethstats.Start()
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/exporter"
+)
+
+// setupExporter starts the exports of the new blocks to the sink, from the last exported one
+func (s *Server) setupExporter() error {
+	sink, err := exporter.NewSink(s.config.Export)
+	if err != nil {
+		return err
+	}
+
+	db, err := s.openDatabase("exporter")
+	if err != nil {
+		return err
+	}
+
+	e, err := exporter.NewExporter(s.logger, s.blockchain, sink, db, s.eventBus)
+	if err != nil {
+		db.Close()
+		return fmt.Errorf("failed to setup the exporter: %v", err)
+	}
+	e.Start()
+	s.exporter = e
+
+	return nil
+}
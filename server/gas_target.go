@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/umbracle/go-web3/abi"
+)
+
+// contractGasTargetSource reads the block gas target from the gasTarget() view of the gas target contract
+type contractGasTargetSource struct {
+	executor *state.Executor
+}
+
+// GasTarget implements the blockchain gas target source
+func (c *contractGasTargetSource) GasTarget(header *types.Header, contract types.Address) (uint64, error) {
+	method := abis.GasTargetABI.Methods["gasTarget"]
+
+	transition, err := c.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return 0, err
+	}
+	transition.SetNoBaseFee(true)
+
+	result, err := transition.Apply(&types.Transaction{
+		From:     types.ZeroAddress,
+		To:       &contract,
+		Input:    method.ID(),
+		Gas:      header.GasLimit,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result.Failed() {
+		return 0, fmt.Errorf("gas target contract call failed: %v", result.Err)
+	}
+
+	return decodeGasTarget(result.ReturnValue)
+}
+
+// decodeGasTarget decodes the gas target returned by the gasTarget() view
+func decodeGasTarget(data []byte) (uint64, error) {
+	method := abis.GasTargetABI.Methods["gasTarget"]
+
+	// the accounts without code return nothing
+	if len(data) != 32 {
+		return 0, fmt.Errorf("invalid gas target of %d bytes", len(data))
+	}
+
+	decoded, err := abi.Decode(method.Outputs, data)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gas target: %v", err)
+	}
+	target, ok := decoded.(map[string]interface{})["0"].(*big.Int)
+	if !ok || !target.IsUint64() {
+		return 0, fmt.Errorf("invalid gas target")
+	}
+
+	return target.Uint64(), nil
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/contracts/abis"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/go-web3/abi"
+)
+
+func TestDecodeGasTarget(t *testing.T) {
+	outputs := abis.GasTargetABI.Methods["gasTarget"].Outputs
+
+	data, err := abi.Encode([]interface{}{big.NewInt(30000000)}, outputs)
+	assert.NoError(t, err)
+
+	target, err := decodeGasTarget(data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(30000000), target)
+
+	// the target fits in the gas limit
+	data, err = abi.Encode([]interface{}{new(big.Int).Lsh(big.NewInt(1), 64)}, outputs)
+	assert.NoError(t, err)
+
+	_, err = decodeGasTarget(data)
+	assert.Error(t, err)
+
+	_, err = decodeGasTarget(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/consensus"
+)
+
+// DefaultHealthMaxSyncLag is the default number of blocks the node can be behind its peers while ready
+const DefaultHealthMaxSyncLag uint64 = 5
+
+// HealthConfig is the config of the health and readiness HTTP endpoints
+type HealthConfig struct {
+	// Addr is the listen address of the endpoints
+	Addr *net.TCPAddr
+
+	// MinPeers is the number of peers below which the node isn't ready
+	MinPeers uint64
+
+	// MaxSyncLag is the number of blocks behind the highest peer above which the node isn't ready
+	MaxSyncLag uint64
+
+	// MaxHeadAge is the time without a new head after which the node isn't healthy, 0 disables the check
+	MaxHeadAge time.Duration
+}
+
+// healthCheck is the result of one of the checks of an endpoint
+type healthCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Detail  string `json:"detail"`
+}
+
+// healthReport is the response of an endpoint, the node is healthy if all the checks are
+type healthReport struct {
+	Healthy bool           `json:"healthy"`
+	Checks  []*healthCheck `json:"checks"`
+}
+
+// healthChecker serves /healthz, the liveness of the node, and /readyz, whether it can serve traffic
+type healthChecker struct {
+	config *HealthConfig
+
+	// head returns the number of the head, peers the number of peers,
+	// and status the status of the consensus, nil if it doesn't report one
+	head   func() uint64
+	peers  func() int
+	status func() *consensus.Status
+
+	now func() time.Time
+
+	// lastHead is the last seen head, lastHeadTime the time it was first seen at
+	lastHead     uint64
+	lastHeadTime time.Time
+	lastHeadLock sync.Mutex
+}
+
+func newHealthChecker(
+	config *HealthConfig,
+	head func() uint64,
+	peers func() int,
+	status func() *consensus.Status,
+) *healthChecker {
+	return &healthChecker{
+		config:       config,
+		head:         head,
+		peers:        peers,
+		status:       status,
+		now:          time.Now,
+		lastHead:     head(),
+		lastHeadTime: time.Now(),
+	}
+}
+
+// liveness checks that the head of the node advances
+func (h *healthChecker) liveness() *healthReport {
+	return newHealthReport(h.checkHead())
+}
+
+// readiness checks that the node is in sync, has enough peers, and takes part in the consensus if it is a validator
+func (h *healthChecker) readiness() *healthReport {
+	status := h.status()
+
+	return newHealthReport(
+		h.checkSync(status),
+		h.checkPeers(),
+		h.checkConsensus(status),
+	)
+}
+
+func (h *healthChecker) checkHead() *healthCheck {
+	head, now := h.head(), h.now()
+
+	h.lastHeadLock.Lock()
+	if head != h.lastHead {
+		h.lastHead, h.lastHeadTime = head, now
+	}
+	age := now.Sub(h.lastHeadTime)
+	h.lastHeadLock.Unlock()
+
+	check := &healthCheck{
+		Name:    "head",
+		Healthy: true,
+		Detail:  fmt.Sprintf("head %d, unchanged for %s", head, age.Truncate(time.Second)),
+	}
+	if h.config.MaxHeadAge != 0 && age > h.config.MaxHeadAge {
+		check.Healthy = false
+	}
+
+	return check
+}
+
+func (h *healthChecker) checkSync(status *consensus.Status) *healthCheck {
+	head := h.head()
+
+	check := &healthCheck{
+		Name:    "sync",
+		Healthy: true,
+		Detail:  fmt.Sprintf("head %d", head),
+	}
+	if status == nil || status.HighestBlock == 0 {
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("head %d, highest block of the peers %d", head, status.HighestBlock)
+	if status.HighestBlock > head && status.HighestBlock-head > h.config.MaxSyncLag {
+		check.Healthy = false
+	}
+
+	return check
+}
+
+func (h *healthChecker) checkPeers() *healthCheck {
+	peers := h.peers()
+
+	return &healthCheck{
+		Name:    "peers",
+		Healthy: uint64(peers) >= h.config.MinPeers,
+		Detail:  fmt.Sprintf("%d peers, minimum %d", peers, h.config.MinPeers),
+	}
+}
+
+func (h *healthChecker) checkConsensus(status *consensus.Status) *healthCheck {
+	check := &healthCheck{
+		Name:    "consensus",
+		Healthy: true,
+		Detail:  "not a validator",
+	}
+
+	if status != nil && status.Validator {
+		check.Healthy = status.Participating
+		if status.Participating {
+			check.Detail = "validator, participating"
+		} else {
+			check.Detail = "validator, not participating"
+		}
+	}
+
+	return check
+}
+
+func newHealthReport(checks ...*healthCheck) *healthReport {
+	report := &healthReport{
+		Healthy: true,
+		Checks:  checks,
+	}
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Healthy = false
+		}
+	}
+
+	return report
+}
+
+// handler returns the handler of the endpoints, they answer 503 if the node isn't healthy
+func (h *healthChecker) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, h.liveness())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, h.readiness())
+	})
+
+	return mux
+}
+
+func writeHealthReport(w http.ResponseWriter, report *healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func (s *Server) startHealthServer(config *HealthConfig) *http.Server {
+	status := func() *consensus.Status {
+		return nil
+	}
+	if reporter, ok := s.consensus.(consensus.StatusReporter); ok {
+		status = reporter.Status
+	}
+
+	checker := newHealthChecker(
+		config,
+		func() uint64 {
+			return s.blockchain.Header().Number
+		},
+		func() int {
+			return len(s.network.Peers())
+		},
+		status,
+	)
+
+	srv := &http.Server{
+		Addr:    config.Addr.String(),
+		Handler: checker.handler(),
+	}
+
+	go func() {
+		s.logger.Info(
+			"Health server started",
+			"addr", config.Addr.String(),
+			"min-peers", config.MinPeers,
+			"max-sync-lag", config.MaxSyncLag,
+			"max-head-age", config.MaxHeadAge,
+		)
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			s.logger.Error("Health HTTP server ListenAndServe", "err", err)
+		}
+	}()
+
+	return srv
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/consensus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecker_Readiness(t *testing.T) {
+	var (
+		head   uint64 = 100
+		peers         = 2
+		status        = &consensus.Status{HighestBlock: 103}
+	)
+
+	h := newHealthChecker(
+		&HealthConfig{MinPeers: 2, MaxSyncLag: 5},
+		func() uint64 { return head },
+		func() int { return peers },
+		func() *consensus.Status { return status },
+	)
+	assert.True(t, h.readiness().Healthy)
+
+	// too far behind the peers
+	status.HighestBlock = 106
+	assert.False(t, h.readiness().Healthy)
+	status.HighestBlock = 100
+
+	// not enough peers
+	peers = 1
+	assert.False(t, h.readiness().Healthy)
+	peers = 2
+
+	// a validator out of the rounds
+	status.Validator = true
+	assert.False(t, h.readiness().Healthy)
+
+	status.Participating = true
+	assert.True(t, h.readiness().Healthy)
+
+	// a consensus without a status is always in sync
+	status = nil
+	assert.True(t, h.readiness().Healthy)
+}
+
+func TestHealthChecker_Liveness(t *testing.T) {
+	var head uint64 = 100
+
+	h := newHealthChecker(
+		&HealthConfig{MaxHeadAge: time.Minute},
+		func() uint64 { return head },
+		func() int { return 0 },
+		func() *consensus.Status { return nil },
+	)
+
+	now := h.lastHeadTime
+	h.now = func() time.Time { return now }
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, h.liveness().Healthy)
+
+	now = now.Add(time.Minute)
+	assert.False(t, h.liveness().Healthy)
+
+	// a new head resets the age
+	head++
+	assert.True(t, h.liveness().Healthy)
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, h.liveness().Healthy)
+
+	// the check is disabled without a maximum age
+	h.config.MaxHeadAge = 0
+	assert.True(t, h.liveness().Healthy)
+}
+
+func TestHealthChecker_Handler(t *testing.T) {
+	peers := 0
+
+	h := newHealthChecker(
+		&HealthConfig{MinPeers: 1},
+		func() uint64 { return 0 },
+		func() int { return peers },
+		func() *consensus.Status { return nil },
+	)
+	srv := httptest.NewServer(h.handler())
+	defer srv.Close()
+
+	get := func(path string) int {
+		resp, err := http.Get(srv.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, get("/healthz"))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+
+	peers = 1
+	assert.Equal(t, http.StatusOK, get("/readyz"))
+}
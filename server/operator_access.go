@@ -0,0 +1,312 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// OperatorRole is the access level of a client of the gRPC operator API.
+// Every role can call the methods of the roles below it
+type OperatorRole int
+
+const (
+	// RoleNone can't call any method
+	RoleNone OperatorRole = iota
+
+	// RoleReadOnly can query the status of the node, like monitoring systems do
+	RoleReadOnly
+
+	// RoleOperator can change the runtime state of the node, like adding peers or transactions
+	RoleOperator
+
+	// RoleAdmin can stop the node and change the validator set
+	RoleAdmin
+)
+
+var roleNames = map[OperatorRole]string{
+	RoleNone:     "none",
+	RoleReadOnly: "read-only",
+	RoleOperator: "operator",
+	RoleAdmin:    "admin",
+}
+
+func (r OperatorRole) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("role(%d)", int(r))
+}
+
+// ParseOperatorRole parses the name of a role ("read-only", "operator" or "admin")
+func ParseOperatorRole(name string) (OperatorRole, error) {
+	for role, roleName := range roleNames {
+		if role != RoleNone && roleName == name {
+			return role, nil
+		}
+	}
+
+	return RoleNone, fmt.Errorf("unknown operator role %q", name)
+}
+
+// operatorMethodRoles is the minimum role required by the methods of the operator API.
+// The methods that are not listed, like the ones registered by a custom consensus, require the admin role
+var operatorMethodRoles = map[string]OperatorRole{
+	// System
+	"/v1.System/GetStatus":      RoleReadOnly,
+	"/v1.System/PeersList":      RoleReadOnly,
+	"/v1.System/PeersStatus":    RoleReadOnly,
+	"/v1.System/PeersGraph":     RoleReadOnly,
+	"/v1.System/PeersBandwidth": RoleReadOnly,
+	"/v1.System/Subscribe":      RoleReadOnly,
+	"/v1.System/GetLogLevel":    RoleReadOnly,
+	"/v1.System/PeersAdd":       RoleOperator,
+	"/v1.System/ReplayBlocks":   RoleOperator,
+	"/v1.System/SetLogLevel":    RoleOperator,
+	"/v1.System/Reload":         RoleOperator,
+	"/v1.System/Shutdown":       RoleAdmin,
+
+	// TxPool
+	"/v1.TxnPoolOperator/Status":    RoleReadOnly,
+	"/v1.TxnPoolOperator/Subscribe": RoleReadOnly,
+	"/v1.TxnPoolOperator/Inspect":   RoleReadOnly,
+	"/v1.TxnPoolOperator/AddTxn":    RoleOperator,
+	"/v1.TxnPoolOperator/Evict":     RoleOperator,
+
+	// IBFT
+	"/v1.IbftOperator/GetSnapshot": RoleReadOnly,
+	"/v1.IbftOperator/Candidates":  RoleReadOnly,
+	"/v1.IbftOperator/Status":      RoleReadOnly,
+	"/v1.IbftOperator/Propose":     RoleAdmin,
+	"/v1.IbftOperator/Exit":        RoleAdmin,
+}
+
+// OperatorAccessConfig defines how the clients of the gRPC operator API are authenticated.
+// The clients authenticate either with a bearer token in the "authorization" metadata,
+// or with a client certificate whose organizational unit is the name of their role.
+// The API is open to everyone when neither of them is configured
+type OperatorAccessConfig struct {
+	// Tokens maps the bearer tokens to the role they grant
+	Tokens map[string]OperatorRole
+
+	// TLSCert and TLSKey are the paths of the certificate served by the API
+	TLSCert string
+	TLSKey  string
+
+	// TLSClientCA is the path of the CA that signs the client certificates.
+	// When set, every client must present a certificate
+	TLSClientCA string
+}
+
+// enabled checks if the clients have to authenticate
+func (c *OperatorAccessConfig) enabled() bool {
+	return c != nil && (len(c.Tokens) > 0 || c.TLSClientCA != "")
+}
+
+func (c *OperatorAccessConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return fmt.Errorf("both the TLS certificate and the TLS key of the gRPC API are required")
+	}
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		return fmt.Errorf("a TLS certificate is required for authenticating the gRPC clients with certificates")
+	}
+	for token, role := range c.Tokens {
+		if token == "" {
+			return fmt.Errorf("empty gRPC auth token")
+		}
+		if role <= RoleNone || role > RoleAdmin {
+			return fmt.Errorf("invalid role %s for a gRPC auth token", role)
+		}
+	}
+
+	return nil
+}
+
+// serverOptions returns the transport credentials and the interceptors that enforce the access rules
+func (c *OperatorAccessConfig) serverOptions() ([]grpc.ServerOption, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.ServerOption{}
+	if c != nil && c.TLSCert != "" {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	if c.enabled() {
+		a := &operatorAccess{tokens: c.Tokens}
+		opts = append(opts,
+			grpc.UnaryInterceptor(a.unaryInterceptor),
+			grpc.StreamInterceptor(a.streamInterceptor),
+		)
+	}
+
+	return opts, nil
+}
+
+func (c *OperatorAccessConfig) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the gRPC TLS certificate: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.TLSClientCA != "" {
+		pem, err := ioutil.ReadFile(c.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the gRPC client CA: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in the gRPC client CA %s", c.TLSClientCA)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// operatorAccess authorizes the calls to the operator API
+type operatorAccess struct {
+	tokens map[string]OperatorRole
+}
+
+// role returns the highest role granted by the credentials of the call
+func (a *operatorAccess) role(ctx context.Context) OperatorRole {
+	role := RoleNone
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			// the chains are verified by the TLS handshake, the leaf is the client certificate
+			for _, chain := range info.State.VerifiedChains {
+				if len(chain) == 0 {
+					continue
+				}
+				for _, unit := range chain[0].Subject.OrganizationalUnit {
+					if r, err := ParseOperatorRole(unit); err == nil && r > role {
+						role = r
+					}
+				}
+			}
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, header := range md.Get("authorization") {
+			if !strings.HasPrefix(header, bearerPrefix) {
+				continue
+			}
+			token := []byte(strings.TrimPrefix(header, bearerPrefix))
+
+			for expected, r := range a.tokens {
+				if subtle.ConstantTimeCompare(token, []byte(expected)) == 1 && r > role {
+					role = r
+				}
+			}
+		}
+	}
+
+	return role
+}
+
+// authorize checks that the credentials of the call grant the role required by the method
+func (a *operatorAccess) authorize(ctx context.Context, method string) error {
+	required, ok := operatorMethodRoles[method]
+	if !ok {
+		required = RoleAdmin
+	}
+
+	role := a.role(ctx)
+	if role == RoleNone {
+		return status.Error(codes.Unauthenticated, "missing or invalid credentials")
+	}
+	if role < required {
+		return status.Errorf(codes.PermissionDenied, "method %s requires the %s role", method, required)
+	}
+
+	return nil
+}
+
+func (a *operatorAccess) unaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *operatorAccess) streamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
+
+const bearerPrefix = "Bearer "
+
+// ParseOperatorTokens parses the gRPC auth tokens file, where every line holds a role and a token
+// separated by a space. Empty lines and lines starting with '#' are skipped
+func ParseOperatorTokens(data []byte) (map[string]OperatorRole, error) {
+	tokens := map[string]OperatorRole{}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a role and a token", i+1)
+		}
+
+		role, err := ParseOperatorRole(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		if _, ok := tokens[fields[1]]; ok {
+			return nil, fmt.Errorf("line %d: duplicate token", i+1)
+		}
+		tokens[fields[1]] = role
+	}
+
+	return tokens, nil
+}
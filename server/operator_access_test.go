@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestOperatorAccess_Authorize(t *testing.T) {
+	a := &operatorAccess{
+		tokens: map[string]OperatorRole{
+			"monitor": RoleReadOnly,
+			"ops":     RoleOperator,
+			"root":    RoleAdmin,
+		},
+	}
+
+	withToken := func(token string) context.Context {
+		if token == "" {
+			return context.Background()
+		}
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	cases := []struct {
+		token  string
+		method string
+		code   codes.Code
+	}{
+		{"", "/v1.System/GetStatus", codes.Unauthenticated},
+		{"unknown", "/v1.System/GetStatus", codes.Unauthenticated},
+		{"monitor", "/v1.System/GetStatus", codes.OK},
+		{"monitor", "/v1.TxnPoolOperator/Subscribe", codes.OK},
+		{"monitor", "/v1.TxnPoolOperator/AddTxn", codes.PermissionDenied},
+		{"monitor", "/v1.System/Shutdown", codes.PermissionDenied},
+		{"ops", "/v1.TxnPoolOperator/AddTxn", codes.OK},
+		{"ops", "/v1.IbftOperator/Propose", codes.PermissionDenied},
+		{"ops", "/v1.System/Shutdown", codes.PermissionDenied},
+		{"root", "/v1.System/Shutdown", codes.OK},
+		{"root", "/v1.IbftOperator/Exit", codes.OK},
+		// unknown methods require the admin role
+		{"ops", "/v1.Custom/Method", codes.PermissionDenied},
+		{"root", "/v1.Custom/Method", codes.OK},
+	}
+
+	for _, c := range cases {
+		err := a.authorize(withToken(c.token), c.method)
+		assert.Equal(t, c.code, status.Code(err), "token %q method %s", c.token, c.method)
+	}
+}
+
+func TestOperatorAccess_UnaryInterceptor(t *testing.T) {
+	a := &operatorAccess{
+		tokens: map[string]OperatorRole{"monitor": RoleReadOnly},
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer monitor"))
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err := a.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.System/Shutdown"}, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+
+	_, err = a.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/v1.System/GetStatus"}, handler)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestParseOperatorTokens(t *testing.T) {
+	tokens, err := ParseOperatorTokens([]byte("# monitoring\nread-only abc\n\nadmin def\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]OperatorRole{"abc": RoleReadOnly, "def": RoleAdmin}, tokens)
+
+	_, err = ParseOperatorTokens([]byte("superuser abc"))
+	assert.Error(t, err)
+
+	_, err = ParseOperatorTokens([]byte("admin abc\noperator abc"))
+	assert.Error(t, err)
+
+	_, err = ParseOperatorTokens([]byte("admin"))
+	assert.Error(t, err)
+}
+
+func TestOperatorAccessConfig_Validate(t *testing.T) {
+	assert.NoError(t, (*OperatorAccessConfig)(nil).validate())
+	assert.Error(t, (&OperatorAccessConfig{TLSCert: "cert.pem"}).validate())
+	assert.Error(t, (&OperatorAccessConfig{TLSClientCA: "ca.pem"}).validate())
+	assert.Error(t, (&OperatorAccessConfig{Tokens: map[string]OperatorRole{"abc": RoleNone}}).validate())
+	assert.NoError(t, (&OperatorAccessConfig{Tokens: map[string]OperatorRole{"abc": RoleOperator}}).validate())
+}
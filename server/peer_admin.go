@@ -0,0 +1,122 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
+	"github.com/0xPolygon/polygon-sdk/network"
+)
+
+// peerAdmin manages the peers of the network server for the admin JSON-RPC namespace
+type peerAdmin struct {
+	network *network.Server
+}
+
+// AddStaticPeer implements the jsonrpc peer manager
+func (p *peerAdmin) AddStaticPeer(addr string) error {
+	info, err := network.StringToAddrInfo(addr)
+	if err != nil {
+		return err
+	}
+
+	return p.network.AddStaticPeer(info)
+}
+
+// RemoveStaticPeer implements the jsonrpc peer manager
+func (p *peerAdmin) RemoveStaticPeer(addr string) error {
+	id, err := network.StringToPeerID(addr)
+	if err != nil {
+		return err
+	}
+	p.network.RemoveStaticPeer(id)
+
+	return nil
+}
+
+// AddTrustedPeer implements the jsonrpc peer manager
+func (p *peerAdmin) AddTrustedPeer(addr string) error {
+	id, err := network.StringToPeerID(addr)
+	if err != nil {
+		return err
+	}
+	p.network.AddTrustedPeer(id)
+
+	return nil
+}
+
+// RemoveTrustedPeer implements the jsonrpc peer manager
+func (p *peerAdmin) RemoveTrustedPeer(addr string) error {
+	id, err := network.StringToPeerID(addr)
+	if err != nil {
+		return err
+	}
+	p.network.RemoveTrustedPeer(id)
+
+	return nil
+}
+
+// Peers implements the jsonrpc peer manager
+func (p *peerAdmin) Peers() ([]*jsonrpc.PeerInfo, error) {
+	infos := []*jsonrpc.PeerInfo{}
+	for _, peer := range p.network.Peers() {
+		id := peer.Info.ID
+
+		protocols, err := p.network.GetProtocols(id)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs := []string{}
+		for _, addr := range p.network.GetPeerInfo(id).Addrs {
+			addrs = append(addrs, addr.String())
+		}
+
+		infos = append(infos, &jsonrpc.PeerInfo{
+			ID:        id.String(),
+			Addrs:     addrs,
+			Protocols: protocols,
+			Static:    p.network.IsStatic(id),
+			Trusted:   p.network.IsTrusted(id),
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+
+	return infos, nil
+}
+
+// PeerScores implements the jsonrpc peer manager
+func (p *peerAdmin) PeerScores() ([]*jsonrpc.PeerScore, error) {
+	scores := []*jsonrpc.PeerScore{}
+	for _, score := range p.network.PeerScores() {
+		faults := map[string]uint64{}
+		for fault, count := range score.Faults {
+			faults[string(fault)] = count
+		}
+
+		res := &jsonrpc.PeerScore{
+			ID:     score.ID.String(),
+			Score:  score.Score,
+			Faults: faults,
+		}
+		if !score.BannedUntil.IsZero() {
+			res.BannedUntil = uint64(score.BannedUntil.Unix())
+		}
+		scores = append(scores, res)
+	}
+
+	return scores, nil
+}
+
+// ClearPeerScore implements the jsonrpc peer manager
+func (p *peerAdmin) ClearPeerScore(addr string) error {
+	id, err := network.StringToPeerID(addr)
+	if err != nil {
+		return err
+	}
+	p.network.ClearPeerScore(id)
+
+	return nil
+}
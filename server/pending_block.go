@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/consensus"
+	"github.com/0xPolygon/polygon-sdk/state"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// pendingBlockTTL is the time a speculative pending block is reused
+// by the JSON-RPC queries before it is built again from the pool
+const pendingBlockTTL = time.Second
+
+// pendingBlock caches the speculative block built on top of the chain head
+type pendingBlock struct {
+	// storage is the state storage of the chain, the pending states are read from
+	storage itrie.Storage
+
+	lock  sync.Mutex
+	block *types.Block
+	built time.Time
+
+	// state holds the state of the block in memory, on top of the one of the chain,
+	// and executor runs on top of it
+	state    state.State
+	executor *state.Executor
+}
+
+// PendingBlock returns a speculative block with the executable transactions of the pool
+// applied on top of the current head. The resulting state is kept in memory, never written
+// to the state storage, and the pending state is queried with the state root of the block
+func (j *jsonRPCHub) PendingBlock() (*types.Block, error) {
+	parent := j.Header()
+
+	j.pending.lock.Lock()
+	defer j.pending.lock.Unlock()
+
+	if block := j.pending.block; block != nil && block.ParentHash() == parent.Hash &&
+		time.Since(j.pending.built) < pendingBlockTTL {
+		return block, nil
+	}
+
+	st := itrie.NewState(itrie.NewOverlayStorage(j.pending.storage))
+	executor := j.Executor.WithState(st)
+
+	block, err := j.buildPendingBlock(parent, executor)
+	if err != nil {
+		return nil, err
+	}
+	j.pending.block = block
+	j.pending.built = time.Now()
+	j.pending.state = st
+	j.pending.executor = executor
+
+	return block, nil
+}
+
+// isPendingHeader checks if the header is the one of the cached pending block
+func (j *jsonRPCHub) isPendingHeader(header *types.Header) bool {
+	j.pending.lock.Lock()
+	defer j.pending.lock.Unlock()
+
+	return j.pending.block != nil && j.pending.block.Hash() == header.Hash
+}
+
+// stateAt returns the state the root is read from, along with the executor running on top of it:
+// the state of the chain, or the one of the pending block if the root is only in the latter
+func (j *jsonRPCHub) stateAt(root types.Hash) (state.State, *state.Executor) {
+	j.pending.lock.Lock()
+	st, executor := j.pending.state, j.pending.executor
+	j.pending.lock.Unlock()
+
+	// the pending state reads the one of the chain too, but the tries recently committed are cached by the latter
+	if st == nil {
+		return j.state, j.Executor
+	}
+	if _, err := j.state.NewSnapshotAt(root); err == nil {
+		return j.state, j.Executor
+	}
+
+	return st, executor
+}
+
+func (j *jsonRPCHub) buildPendingBlock(parent *types.Header, executor *state.Executor) (*types.Block, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Difficulty: parent.Difficulty,
+		Timestamp:  uint64(time.Now().Unix()),
+		ExtraData:  []byte{},
+	}
+
+	gasLimit, err := j.CalculateGasLimit(header.Number)
+	if err != nil {
+		return nil, err
+	}
+	header.GasLimit = gasLimit
+	header.BaseFee = j.CalculateBaseFee(parent)
+
+	// the block creator of the pending block is not known, the latest one is used instead
+	coinbase, err := j.GetConsensus().GetBlockCreator(parent)
+	if err != nil {
+		return nil, err
+	}
+	header.Miner = coinbase
+
+	transition, err := executor.BeginTxn(parent.StateRoot, header, coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, _ := j.GetTxs()
+
+	txns := []*types.Transaction{}
+	for _, accountTxs := range sortPendingTxs(pending) {
+		for _, txn := range accountTxs {
+			if txn.Gas > gasLimit-transition.TotalGas() {
+				// the next transactions of the account cannot be executed without this one
+				break
+			}
+			if err := transition.Write(txn); err != nil {
+				break
+			}
+			txns = append(txns, txn)
+		}
+	}
+
+	_, root := transition.Commit()
+
+	header.StateRoot = root
+	header.GasUsed = transition.TotalGas()
+
+	return consensus.BuildBlock(consensus.BuildBlockParams{
+		Header:   header,
+		Txns:     txns,
+		Receipts: transition.Receipts(),
+	}), nil
+}
+
+// sortPendingTxs returns the pending transactions of every account ordered by nonce,
+// with the accounts ordered by the gas price of their first transaction
+func sortPendingTxs(pending map[types.Address]map[uint64]*types.Transaction) [][]*types.Transaction {
+	sorted := make([][]*types.Transaction, 0, len(pending))
+	for _, txs := range pending {
+		accountTxs := make([]*types.Transaction, 0, len(txs))
+		for _, txn := range txs {
+			accountTxs = append(accountTxs, txn)
+		}
+		sort.Slice(accountTxs, func(i, j int) bool {
+			return accountTxs[i].Nonce < accountTxs[j].Nonce
+		})
+		sorted = append(sorted, accountTxs)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if c := sorted[i][0].GasPrice.Cmp(sorted[j][0].GasPrice); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(sorted[i][0].From.Bytes(), sorted[j][0].From.Bytes()) < 0
+	})
+
+	return sorted
+}
@@ -90,6 +90,10 @@ type ServerStatus struct {
 	Genesis string              `protobuf:"bytes,2,opt,name=genesis,proto3" json:"genesis,omitempty"`
 	Current *ServerStatus_Block `protobuf:"bytes,3,opt,name=current,proto3" json:"current,omitempty"`
 	P2PAddr string              `protobuf:"bytes,4,opt,name=p2pAddr,proto3" json:"p2pAddr,omitempty"`
+	// peers is the number of connected peers
+	Peers int64 `protobuf:"varint,5,opt,name=peers,proto3" json:"peers,omitempty"`
+	// highestBlock is the highest block announced by the peers, 0 if unknown
+	HighestBlock int64 `protobuf:"varint,6,opt,name=highestBlock,proto3" json:"highestBlock,omitempty"`
 }
 
 func (x *ServerStatus) Reset() {
@@ -152,33 +156,1036 @@ func (x *ServerStatus) GetP2PAddr() string {
 	return ""
 }
 
+func (x *ServerStatus) GetPeers() int64 {
+	if x != nil {
+		return x.Peers
+	}
+	return 0
+}
+
+func (x *ServerStatus) GetHighestBlock() int64 {
+	if x != nil {
+		return x.HighestBlock
+	}
+	return 0
+}
+
 type Peer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id        string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Protocols []string `protobuf:"bytes,2,rep,name=protocols,proto3" json:"protocols,omitempty"`
-	Addrs     []string `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	Id        string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Protocols []string `protobuf:"bytes,2,rep,name=protocols,proto3" json:"protocols,omitempty"`
+	Addrs     []string `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	// direction is whether the peer dialed the node, inbound, or the node dialed it, outbound
+	Direction string `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`
+	// latency is the average round trip of the pings in nanoseconds, 0 until measured
+	Latency       int64  `protobuf:"varint,5,opt,name=latency,proto3" json:"latency,omitempty"`
+	ClientVersion string `protobuf:"bytes,6,opt,name=clientVersion,proto3" json:"clientVersion,omitempty"`
+}
+
+func (x *Peer) Reset() {
+	*x = Peer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Peer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Peer) ProtoMessage() {}
+
+func (x *Peer) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
+func (*Peer) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Peer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Peer) GetProtocols() []string {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+func (x *Peer) GetAddrs() []string {
+	if x != nil {
+		return x.Addrs
+	}
+	return nil
+}
+
+func (x *Peer) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Peer) GetLatency() int64 {
+	if x != nil {
+		return x.Latency
+	}
+	return 0
+}
+
+func (x *Peer) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+type PeersGraphResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the ID of the node
+	Id    string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Peers []*Peer `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	// links are the peers reported by the connected peers to the discovery
+	Links []*PeerLink `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+}
+
+func (x *PeersGraphResponse) Reset() {
+	*x = PeersGraphResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeersGraphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeersGraphResponse) ProtoMessage() {}
+
+func (x *PeersGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeersGraphResponse.ProtoReflect.Descriptor instead.
+func (*PeersGraphResponse) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PeersGraphResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PeersGraphResponse) GetPeers() []*Peer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *PeersGraphResponse) GetLinks() []*PeerLink {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type PeerLink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *PeerLink) Reset() {
+	*x = PeerLink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerLink) ProtoMessage() {}
+
+func (x *PeerLink) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerLink.ProtoReflect.Descriptor instead.
+func (*PeerLink) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PeerLink) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *PeerLink) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type PeersAddRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Blocked bool   `protobuf:"varint,2,opt,name=blocked,proto3" json:"blocked,omitempty"`
+}
+
+func (x *PeersAddRequest) Reset() {
+	*x = PeersAddRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeersAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeersAddRequest) ProtoMessage() {}
+
+func (x *PeersAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeersAddRequest.ProtoReflect.Descriptor instead.
+func (*PeersAddRequest) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PeersAddRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PeersAddRequest) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+type PeersStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *PeersStatusRequest) Reset() {
+	*x = PeersStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeersStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeersStatusRequest) ProtoMessage() {}
+
+func (x *PeersStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeersStatusRequest.ProtoReflect.Descriptor instead.
+func (*PeersStatusRequest) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PeersStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PeersListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Peers []*Peer `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+}
+
+func (x *PeersListResponse) Reset() {
+	*x = PeersListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeersListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeersListResponse) ProtoMessage() {}
+
+func (x *PeersListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeersListResponse.ProtoReflect.Descriptor instead.
+func (*PeersListResponse) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PeersListResponse) GetPeers() []*Peer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type PeersBandwidthResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// totalIn and totalOut are the bytes received and sent on all the streams
+	TotalIn  uint64           `protobuf:"varint,1,opt,name=totalIn,proto3" json:"totalIn,omitempty"`
+	TotalOut uint64           `protobuf:"varint,2,opt,name=totalOut,proto3" json:"totalOut,omitempty"`
+	Peers    []*PeerBandwidth `protobuf:"bytes,3,rep,name=peers,proto3" json:"peers,omitempty"`
+}
+
+func (x *PeersBandwidthResponse) Reset() {
+	*x = PeersBandwidthResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeersBandwidthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeersBandwidthResponse) ProtoMessage() {}
+
+func (x *PeersBandwidthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeersBandwidthResponse.ProtoReflect.Descriptor instead.
+func (*PeersBandwidthResponse) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PeersBandwidthResponse) GetTotalIn() uint64 {
+	if x != nil {
+		return x.TotalIn
+	}
+	return 0
+}
+
+func (x *PeersBandwidthResponse) GetTotalOut() uint64 {
+	if x != nil {
+		return x.TotalOut
+	}
+	return 0
+}
+
+func (x *PeersBandwidthResponse) GetPeers() []*PeerBandwidth {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type PeerBandwidth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TotalIn  uint64 `protobuf:"varint,2,opt,name=totalIn,proto3" json:"totalIn,omitempty"`
+	TotalOut uint64 `protobuf:"varint,3,opt,name=totalOut,proto3" json:"totalOut,omitempty"`
+	// rateIn and rateOut are the current bytes per second
+	RateIn    float64              `protobuf:"fixed64,4,opt,name=rateIn,proto3" json:"rateIn,omitempty"`
+	RateOut   float64              `protobuf:"fixed64,5,opt,name=rateOut,proto3" json:"rateOut,omitempty"`
+	Protocols []*ProtocolBandwidth `protobuf:"bytes,6,rep,name=protocols,proto3" json:"protocols,omitempty"`
+}
+
+func (x *PeerBandwidth) Reset() {
+	*x = PeerBandwidth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerBandwidth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerBandwidth) ProtoMessage() {}
+
+func (x *PeerBandwidth) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerBandwidth.ProtoReflect.Descriptor instead.
+func (*PeerBandwidth) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PeerBandwidth) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PeerBandwidth) GetTotalIn() uint64 {
+	if x != nil {
+		return x.TotalIn
+	}
+	return 0
+}
+
+func (x *PeerBandwidth) GetTotalOut() uint64 {
+	if x != nil {
+		return x.TotalOut
+	}
+	return 0
+}
+
+func (x *PeerBandwidth) GetRateIn() float64 {
+	if x != nil {
+		return x.RateIn
+	}
+	return 0
+}
+
+func (x *PeerBandwidth) GetRateOut() float64 {
+	if x != nil {
+		return x.RateOut
+	}
+	return 0
+}
+
+func (x *PeerBandwidth) GetProtocols() []*ProtocolBandwidth {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+type ProtocolBandwidth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Protocol string `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	In       uint64 `protobuf:"varint,2,opt,name=in,proto3" json:"in,omitempty"`
+	Out      uint64 `protobuf:"varint,3,opt,name=out,proto3" json:"out,omitempty"`
+}
+
+func (x *ProtocolBandwidth) Reset() {
+	*x = ProtocolBandwidth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProtocolBandwidth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtocolBandwidth) ProtoMessage() {}
+
+func (x *ProtocolBandwidth) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtocolBandwidth.ProtoReflect.Descriptor instead.
+func (*ProtocolBandwidth) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProtocolBandwidth) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ProtocolBandwidth) GetIn() uint64 {
+	if x != nil {
+		return x.In
+	}
+	return 0
+}
+
+func (x *ProtocolBandwidth) GetOut() uint64 {
+	if x != nil {
+		return x.Out
+	}
+	return 0
+}
+
+type ReplayBlocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From        uint64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To          uint64 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Parallelism uint64 `protobuf:"varint,3,opt,name=parallelism,proto3" json:"parallelism,omitempty"`
+	// trace selects the traced blocks: mismatches, all, or none if empty
+	Trace string `protobuf:"bytes,4,opt,name=trace,proto3" json:"trace,omitempty"`
+}
+
+func (x *ReplayBlocksRequest) Reset() {
+	*x = ReplayBlocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayBlocksRequest) ProtoMessage() {}
+
+func (x *ReplayBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayBlocksRequest.ProtoReflect.Descriptor instead.
+func (*ReplayBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReplayBlocksRequest) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ReplayBlocksRequest) GetTo() uint64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *ReplayBlocksRequest) GetParallelism() uint64 {
+	if x != nil {
+		return x.Parallelism
+	}
+	return 0
+}
+
+func (x *ReplayBlocksRequest) GetTrace() string {
+	if x != nil {
+		return x.Trace
+	}
+	return ""
+}
+
+type SyncProgressEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// syncing is false if the node isn't syncing, the last event of a sync
+	Syncing           bool   `protobuf:"varint,1,opt,name=syncing,proto3" json:"syncing,omitempty"`
+	Stage             string `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`
+	StartingBlock     uint64 `protobuf:"varint,3,opt,name=startingBlock,proto3" json:"startingBlock,omitempty"`
+	CurrentBlock      uint64 `protobuf:"varint,4,opt,name=currentBlock,proto3" json:"currentBlock,omitempty"`
+	HighestBlock      uint64 `protobuf:"varint,5,opt,name=highestBlock,proto3" json:"highestBlock,omitempty"`
+	HeadersDownloaded uint64 `protobuf:"varint,6,opt,name=headersDownloaded,proto3" json:"headersDownloaded,omitempty"`
+	BlocksDownloaded  uint64 `protobuf:"varint,7,opt,name=blocksDownloaded,proto3" json:"blocksDownloaded,omitempty"`
+	BlocksExecuted    uint64 `protobuf:"varint,8,opt,name=blocksExecuted,proto3" json:"blocksExecuted,omitempty"`
+	StateNodes        uint64 `protobuf:"varint,9,opt,name=stateNodes,proto3" json:"stateNodes,omitempty"`
+	StateCodes        uint64 `protobuf:"varint,10,opt,name=stateCodes,proto3" json:"stateCodes,omitempty"`
+	StatePending      uint64 `protobuf:"varint,11,opt,name=statePending,proto3" json:"statePending,omitempty"`
+	// etaMs is the estimated time left of the blocks stage, 0 if unknown
+	EtaMs int64 `protobuf:"varint,12,opt,name=etaMs,proto3" json:"etaMs,omitempty"`
+}
+
+func (x *SyncProgressEvent) Reset() {
+	*x = SyncProgressEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncProgressEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncProgressEvent) ProtoMessage() {}
+
+func (x *SyncProgressEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncProgressEvent.ProtoReflect.Descriptor instead.
+func (*SyncProgressEvent) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SyncProgressEvent) GetSyncing() bool {
+	if x != nil {
+		return x.Syncing
+	}
+	return false
+}
+
+func (x *SyncProgressEvent) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *SyncProgressEvent) GetStartingBlock() uint64 {
+	if x != nil {
+		return x.StartingBlock
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetCurrentBlock() uint64 {
+	if x != nil {
+		return x.CurrentBlock
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetHighestBlock() uint64 {
+	if x != nil {
+		return x.HighestBlock
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetHeadersDownloaded() uint64 {
+	if x != nil {
+		return x.HeadersDownloaded
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetBlocksDownloaded() uint64 {
+	if x != nil {
+		return x.BlocksDownloaded
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetBlocksExecuted() uint64 {
+	if x != nil {
+		return x.BlocksExecuted
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetStateNodes() uint64 {
+	if x != nil {
+		return x.StateNodes
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetStateCodes() uint64 {
+	if x != nil {
+		return x.StateCodes
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetStatePending() uint64 {
+	if x != nil {
+		return x.StatePending
+	}
+	return 0
+}
+
+func (x *SyncProgressEvent) GetEtaMs() int64 {
+	if x != nil {
+		return x.EtaMs
+	}
+	return 0
+}
+
+type ReplayBlockResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number     uint64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Hash       string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Txns       uint64 `protobuf:"varint,3,opt,name=txns,proto3" json:"txns,omitempty"`
+	GasUsed    uint64 `protobuf:"varint,4,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
+	DurationMs int64  `protobuf:"varint,5,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	// error is set if the block could not be executed or the results do not match
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// traces is the JSON encoding of the traces of the transactions, if traced
+	Traces     []byte `protobuf:"bytes,7,opt,name=traces,proto3" json:"traces,omitempty"`
+	TraceError string `protobuf:"bytes,8,opt,name=traceError,proto3" json:"traceError,omitempty"`
+}
+
+func (x *ReplayBlockResult) Reset() {
+	*x = ReplayBlockResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayBlockResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayBlockResult) ProtoMessage() {}
+
+func (x *ReplayBlockResult) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayBlockResult.ProtoReflect.Descriptor instead.
+func (*ReplayBlockResult) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReplayBlockResult) GetNumber() uint64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *ReplayBlockResult) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *ReplayBlockResult) GetTxns() uint64 {
+	if x != nil {
+		return x.Txns
+	}
+	return 0
+}
+
+func (x *ReplayBlockResult) GetGasUsed() uint64 {
+	if x != nil {
+		return x.GasUsed
+	}
+	return 0
+}
+
+func (x *ReplayBlockResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ReplayBlockResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReplayBlockResult) GetTraces() []byte {
+	if x != nil {
+		return x.Traces
+	}
+	return nil
+}
+
+func (x *ReplayBlockResult) GetTraceError() string {
+	if x != nil {
+		return x.TraceError
+	}
+	return ""
+}
+
+type ExportBlocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From uint64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	// to is the last block of the range, the head of the chain if not set
+	To uint64 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *ExportBlocksRequest) Reset() {
+	*x = ExportBlocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportBlocksRequest) ProtoMessage() {}
+
+func (x *ExportBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportBlocksRequest.ProtoReflect.Descriptor instead.
+func (*ExportBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportBlocksRequest) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ExportBlocksRequest) GetTo() uint64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type RLPBlocks struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blocks [][]byte `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *RLPBlocks) Reset() {
+	*x = RLPBlocks{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minimal_proto_system_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RLPBlocks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RLPBlocks) ProtoMessage() {}
+
+func (x *RLPBlocks) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RLPBlocks.ProtoReflect.Descriptor instead.
+func (*RLPBlocks) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RLPBlocks) GetBlocks() [][]byte {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type BackupChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// data is the next part of the backup file
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 }
 
-func (x *Peer) Reset() {
-	*x = Peer{}
+func (x *BackupChunk) Reset() {
+	*x = BackupChunk{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_minimal_proto_system_proto_msgTypes[2]
+		mi := &file_minimal_proto_system_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Peer) String() string {
+func (x *BackupChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Peer) ProtoMessage() {}
+func (*BackupChunk) ProtoMessage() {}
 
-func (x *Peer) ProtoReflect() protoreflect.Message {
-	mi := &file_minimal_proto_system_proto_msgTypes[2]
+func (x *BackupChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -189,58 +1196,47 @@ func (x *Peer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
-func (*Peer) Descriptor() ([]byte, []int) {
-	return file_minimal_proto_system_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *Peer) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *Peer) GetProtocols() []string {
-	if x != nil {
-		return x.Protocols
-	}
-	return nil
+// Deprecated: Use BackupChunk.ProtoReflect.Descriptor instead.
+func (*BackupChunk) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *Peer) GetAddrs() []string {
+func (x *BackupChunk) GetData() []byte {
 	if x != nil {
-		return x.Addrs
+		return x.Data
 	}
 	return nil
 }
 
-type PeersAddRequest struct {
+type ImportBlocksResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Blocked bool   `protobuf:"varint,2,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	Imported uint64 `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"`
+	// skipped is the number of blocks already in the canonical chain
+	Skipped    uint64 `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	HeadNumber uint64 `protobuf:"varint,3,opt,name=headNumber,proto3" json:"headNumber,omitempty"`
+	HeadHash   string `protobuf:"bytes,4,opt,name=headHash,proto3" json:"headHash,omitempty"`
 }
 
-func (x *PeersAddRequest) Reset() {
-	*x = PeersAddRequest{}
+func (x *ImportBlocksResponse) Reset() {
+	*x = ImportBlocksResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_minimal_proto_system_proto_msgTypes[3]
+		mi := &file_minimal_proto_system_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PeersAddRequest) String() string {
+func (x *ImportBlocksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PeersAddRequest) ProtoMessage() {}
+func (*ImportBlocksResponse) ProtoMessage() {}
 
-func (x *PeersAddRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_minimal_proto_system_proto_msgTypes[3]
+func (x *ImportBlocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -251,50 +1247,65 @@ func (x *PeersAddRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PeersAddRequest.ProtoReflect.Descriptor instead.
-func (*PeersAddRequest) Descriptor() ([]byte, []int) {
-	return file_minimal_proto_system_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ImportBlocksResponse.ProtoReflect.Descriptor instead.
+func (*ImportBlocksResponse) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *PeersAddRequest) GetId() string {
+func (x *ImportBlocksResponse) GetImported() uint64 {
 	if x != nil {
-		return x.Id
+		return x.Imported
 	}
-	return ""
+	return 0
 }
 
-func (x *PeersAddRequest) GetBlocked() bool {
+func (x *ImportBlocksResponse) GetSkipped() uint64 {
 	if x != nil {
-		return x.Blocked
+		return x.Skipped
 	}
-	return false
+	return 0
 }
 
-type PeersStatusRequest struct {
+func (x *ImportBlocksResponse) GetHeadNumber() uint64 {
+	if x != nil {
+		return x.HeadNumber
+	}
+	return 0
+}
+
+func (x *ImportBlocksResponse) GetHeadHash() string {
+	if x != nil {
+		return x.HeadHash
+	}
+	return ""
+}
+
+type LogLevel struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// spec is the default level and the module=level overrides, e.g. info,ibft=debug,network=warn
+	Spec string `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
 }
 
-func (x *PeersStatusRequest) Reset() {
-	*x = PeersStatusRequest{}
+func (x *LogLevel) Reset() {
+	*x = LogLevel{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_minimal_proto_system_proto_msgTypes[4]
+		mi := &file_minimal_proto_system_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PeersStatusRequest) String() string {
+func (x *LogLevel) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PeersStatusRequest) ProtoMessage() {}
+func (*LogLevel) ProtoMessage() {}
 
-func (x *PeersStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_minimal_proto_system_proto_msgTypes[4]
+func (x *LogLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -305,43 +1316,44 @@ func (x *PeersStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PeersStatusRequest.ProtoReflect.Descriptor instead.
-func (*PeersStatusRequest) Descriptor() ([]byte, []int) {
-	return file_minimal_proto_system_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use LogLevel.ProtoReflect.Descriptor instead.
+func (*LogLevel) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *PeersStatusRequest) GetId() string {
+func (x *LogLevel) GetSpec() string {
 	if x != nil {
-		return x.Id
+		return x.Spec
 	}
 	return ""
 }
 
-type PeersListResponse struct {
+type ReloadResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Peers []*Peer `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	// reloaded are the flags of the reloaded settings
+	Reloaded []string `protobuf:"bytes,1,rep,name=reloaded,proto3" json:"reloaded,omitempty"`
 }
 
-func (x *PeersListResponse) Reset() {
-	*x = PeersListResponse{}
+func (x *ReloadResponse) Reset() {
+	*x = ReloadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_minimal_proto_system_proto_msgTypes[5]
+		mi := &file_minimal_proto_system_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PeersListResponse) String() string {
+func (x *ReloadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PeersListResponse) ProtoMessage() {}
+func (*ReloadResponse) ProtoMessage() {}
 
-func (x *PeersListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_minimal_proto_system_proto_msgTypes[5]
+func (x *ReloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_minimal_proto_system_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -352,14 +1364,14 @@ func (x *PeersListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PeersListResponse.ProtoReflect.Descriptor instead.
-func (*PeersListResponse) Descriptor() ([]byte, []int) {
-	return file_minimal_proto_system_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ReloadResponse.ProtoReflect.Descriptor instead.
+func (*ReloadResponse) Descriptor() ([]byte, []int) {
+	return file_minimal_proto_system_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *PeersListResponse) GetPeers() []*Peer {
+func (x *ReloadResponse) GetReloaded() []string {
 	if x != nil {
-		return x.Peers
+		return x.Reloaded
 	}
 	return nil
 }
@@ -376,7 +1388,7 @@ type BlockchainEvent_Header struct {
 func (x *BlockchainEvent_Header) Reset() {
 	*x = BlockchainEvent_Header{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_minimal_proto_system_proto_msgTypes[6]
+		mi := &file_minimal_proto_system_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -389,7 +1401,7 @@ func (x *BlockchainEvent_Header) String() string {
 func (*BlockchainEvent_Header) ProtoMessage() {}
 
 func (x *BlockchainEvent_Header) ProtoReflect() protoreflect.Message {
-	mi := &file_minimal_proto_system_proto_msgTypes[6]
+	mi := &file_minimal_proto_system_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,7 +1443,7 @@ type ServerStatus_Block struct {
 func (x *ServerStatus_Block) Reset() {
 	*x = ServerStatus_Block{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_minimal_proto_system_proto_msgTypes[7]
+		mi := &file_minimal_proto_system_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -444,7 +1456,7 @@ func (x *ServerStatus_Block) String() string {
 func (*ServerStatus_Block) ProtoMessage() {}
 
 func (x *ServerStatus_Block) ProtoReflect() protoreflect.Message {
-	mi := &file_minimal_proto_system_proto_msgTypes[7]
+	mi := &file_minimal_proto_system_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -492,7 +1504,7 @@ var file_minimal_proto_system_proto_rawDesc = []byte{
 	0x64, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x03, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x68,
 	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22,
-	0xc3, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0xfd, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
 	0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x03, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x65,
 	0x6e, 0x65, 0x73, 0x69, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x65, 0x6e,
@@ -501,44 +1513,198 @@ var file_minimal_proto_system_proto_rawDesc = []byte{
 	0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x07, 0x63,
 	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x32, 0x70, 0x41, 0x64, 0x64,
 	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x32, 0x70, 0x41, 0x64, 0x64, 0x72,
-	0x1a, 0x33, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x68, 0x61, 0x73, 0x68, 0x22, 0x4a, 0x0a, 0x04, 0x50, 0x65, 0x65, 0x72, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a,
-	0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61,
-	0x64, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x64, 0x64, 0x72,
-	0x73, 0x22, 0x3b, 0x0a, 0x0f, 0x50, 0x65, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x22, 0x24,
-	0x0a, 0x12, 0x50, 0x65, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x33, 0x0a, 0x11, 0x50, 0x65, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x05, 0x70, 0x65, 0x65,
-	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65,
-	0x65, 0x72, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x32, 0xa1, 0x02, 0x0a, 0x06, 0x53, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x12, 0x35, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x53,
-	0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x37, 0x0a, 0x08, 0x50,
-	0x65, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x12, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65,
-	0x72, 0x73, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x69, 0x67, 0x68, 0x65, 0x73,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x68, 0x69,
+	0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x1a, 0x33, 0x0a, 0x05, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22,
+	0xa8, 0x01, 0x0a, 0x04, 0x50, 0x65, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x68, 0x0a, 0x12, 0x50, 0x65,
+	0x65, 0x72, 0x73, 0x47, 0x72, 0x61, 0x70, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1e, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x08, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x12, 0x22, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x6c,
+	0x69, 0x6e, 0x6b, 0x73, 0x22, 0x2e, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6e, 0x6b,
+	0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x74, 0x6f, 0x22, 0x3b, 0x0a, 0x0f, 0x50, 0x65, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x65,
+	0x64, 0x22, 0x24, 0x0a, 0x12, 0x50, 0x65, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x33, 0x0a, 0x11, 0x50, 0x65, 0x65, 0x72, 0x73,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x05,
+	0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x22, 0x77, 0x0a, 0x16,
+	0x50, 0x65, 0x65, 0x72, 0x73, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x6e,
+	0x12, 0x1a, 0x0a, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f, 0x75, 0x74, 0x12, 0x27, 0x0a, 0x05,
+	0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x65, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x52, 0x05,
+	0x70, 0x65, 0x65, 0x72, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x0d, 0x50, 0x65, 0x65, 0x72, 0x42, 0x61,
+	0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x49, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49,
+	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f, 0x75, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f, 0x75, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72,
+	0x61, 0x74, 0x65, 0x49, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x61, 0x74, 0x65, 0x4f, 0x75, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x72, 0x61, 0x74, 0x65, 0x4f, 0x75, 0x74, 0x12,
+	0x33, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x73, 0x22, 0x51, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x03, 0x6f, 0x75, 0x74, 0x22, 0x71, 0x0a, 0x13, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02,
+	0x74, 0x6f, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x69, 0x73,
+	0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65,
+	0x6c, 0x69, 0x73, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x22, 0xad, 0x03, 0x0a, 0x11, 0x53,
+	0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6e, 0x63, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x73, 0x79, 0x6e, 0x63, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e,
+	0x67, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x69,
+	0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0c, 0x68, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2c,
+	0x0a, 0x11, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x10,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x44, 0x6f,
+	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x73,
+	0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x73,
+	0x12, 0x22, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x65, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x65, 0x50, 0x65, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x74, 0x61, 0x4d, 0x73, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x65, 0x74, 0x61, 0x4d, 0x73, 0x22, 0xdb, 0x01, 0x0a, 0x11, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x78, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x78, 0x6e, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x67, 0x61, 0x73, 0x55, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x67, 0x61, 0x73, 0x55, 0x73, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x74, 0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x63,
+	0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x72,
+	0x61, 0x63, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x39, 0x0a, 0x13, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x02, 0x74, 0x6f, 0x22, 0x23, 0x0a, 0x09, 0x52, 0x4c, 0x50, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c,
+	0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x21, 0x0a, 0x0b, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x88, 0x01, 0x0a, 0x14,
+	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x68, 0x65,
+	0x61, 0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x68, 0x65, 0x61, 0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x65,
+	0x61, 0x64, 0x48, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x65,
+	0x61, 0x64, 0x48, 0x61, 0x73, 0x68, 0x22, 0x1e, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0x2c, 0x0a, 0x0e, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x6f,
+	0x61, 0x64, 0x65, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x6f,
+	0x61, 0x64, 0x65, 0x64, 0x32, 0xa4, 0x07, 0x0a, 0x06, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12,
+	0x35, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x37, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x73, 0x41,
+	0x64, 0x64, 0x12, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x3a, 0x0a, 0x09, 0x50, 0x65, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x73, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x0b, 0x50,
+	0x65, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x65, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x08, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x12, 0x3c, 0x0a, 0x0a,
+	0x50, 0x65, 0x65, 0x72, 0x73, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x16, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x73, 0x47, 0x72, 0x61,
+	0x70, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x50, 0x65,
+	0x65, 0x72, 0x73, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x73, 0x42,
+	0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3a, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x3f, 0x0a, 0x0c,
+	0x53, 0x79, 0x6e, 0x63, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x2e, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x12, 0x3a, 0x0a, 0x09, 0x50, 0x65, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73,
-	0x74, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x50,
-	0x65, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x2f, 0x0a, 0x0b, 0x50, 0x65, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x16, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x08, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65,
-	0x72, 0x12, 0x3a, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x16,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x40, 0x0a,
+	0x0c, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x17, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01, 0x12,
+	0x38, 0x0a, 0x0c, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12,
+	0x17, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x4c,
+	0x50, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x0c, 0x49, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x4c, 0x50, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x1a, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x28, 0x01, 0x12, 0x33, 0x0a, 0x06, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x16,
 	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x10, 0x5a,
-	0x0e, 0x2f, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x33, 0x0a, 0x0b, 0x47, 0x65, 0x74,
+	0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x29,
+	0x0a, 0x0b, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0c, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x1a, 0x0c, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x34, 0x0a, 0x06, 0x52, 0x65, 0x6c,
+	0x6f, 0x61, 0x64, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x12, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3a, 0x0a, 0x08, 0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x10, 0x5a, 0x0e, 0x2f,
+	0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -553,38 +1719,78 @@ func file_minimal_proto_system_proto_rawDescGZIP() []byte {
 	return file_minimal_proto_system_proto_rawDescData
 }
 
-var file_minimal_proto_system_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_minimal_proto_system_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_minimal_proto_system_proto_goTypes = []interface{}{
 	(*BlockchainEvent)(nil),        // 0: v1.BlockchainEvent
 	(*ServerStatus)(nil),           // 1: v1.ServerStatus
 	(*Peer)(nil),                   // 2: v1.Peer
-	(*PeersAddRequest)(nil),        // 3: v1.PeersAddRequest
-	(*PeersStatusRequest)(nil),     // 4: v1.PeersStatusRequest
-	(*PeersListResponse)(nil),      // 5: v1.PeersListResponse
-	(*BlockchainEvent_Header)(nil), // 6: v1.BlockchainEvent.Header
-	(*ServerStatus_Block)(nil),     // 7: v1.ServerStatus.Block
-	(*empty.Empty)(nil),            // 8: google.protobuf.Empty
+	(*PeersGraphResponse)(nil),     // 3: v1.PeersGraphResponse
+	(*PeerLink)(nil),               // 4: v1.PeerLink
+	(*PeersAddRequest)(nil),        // 5: v1.PeersAddRequest
+	(*PeersStatusRequest)(nil),     // 6: v1.PeersStatusRequest
+	(*PeersListResponse)(nil),      // 7: v1.PeersListResponse
+	(*PeersBandwidthResponse)(nil), // 8: v1.PeersBandwidthResponse
+	(*PeerBandwidth)(nil),          // 9: v1.PeerBandwidth
+	(*ProtocolBandwidth)(nil),      // 10: v1.ProtocolBandwidth
+	(*ReplayBlocksRequest)(nil),    // 11: v1.ReplayBlocksRequest
+	(*SyncProgressEvent)(nil),      // 12: v1.SyncProgressEvent
+	(*ReplayBlockResult)(nil),      // 13: v1.ReplayBlockResult
+	(*ExportBlocksRequest)(nil),    // 14: v1.ExportBlocksRequest
+	(*RLPBlocks)(nil),              // 15: v1.RLPBlocks
+	(*BackupChunk)(nil),            // 16: v1.BackupChunk
+	(*ImportBlocksResponse)(nil),   // 17: v1.ImportBlocksResponse
+	(*LogLevel)(nil),               // 18: v1.LogLevel
+	(*ReloadResponse)(nil),         // 19: v1.ReloadResponse
+	(*BlockchainEvent_Header)(nil), // 20: v1.BlockchainEvent.Header
+	(*ServerStatus_Block)(nil),     // 21: v1.ServerStatus.Block
+	(*empty.Empty)(nil),            // 22: google.protobuf.Empty
 }
 var file_minimal_proto_system_proto_depIdxs = []int32{
-	6, // 0: v1.BlockchainEvent.added:type_name -> v1.BlockchainEvent.Header
-	6, // 1: v1.BlockchainEvent.removed:type_name -> v1.BlockchainEvent.Header
-	7, // 2: v1.ServerStatus.current:type_name -> v1.ServerStatus.Block
-	2, // 3: v1.PeersListResponse.peers:type_name -> v1.Peer
-	8, // 4: v1.System.GetStatus:input_type -> google.protobuf.Empty
-	3, // 5: v1.System.PeersAdd:input_type -> v1.PeersAddRequest
-	8, // 6: v1.System.PeersList:input_type -> google.protobuf.Empty
-	4, // 7: v1.System.PeersStatus:input_type -> v1.PeersStatusRequest
-	8, // 8: v1.System.Subscribe:input_type -> google.protobuf.Empty
-	1, // 9: v1.System.GetStatus:output_type -> v1.ServerStatus
-	8, // 10: v1.System.PeersAdd:output_type -> google.protobuf.Empty
-	5, // 11: v1.System.PeersList:output_type -> v1.PeersListResponse
-	2, // 12: v1.System.PeersStatus:output_type -> v1.Peer
-	0, // 13: v1.System.Subscribe:output_type -> v1.BlockchainEvent
-	9, // [9:14] is the sub-list for method output_type
-	4, // [4:9] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	20, // 0: v1.BlockchainEvent.added:type_name -> v1.BlockchainEvent.Header
+	20, // 1: v1.BlockchainEvent.removed:type_name -> v1.BlockchainEvent.Header
+	21, // 2: v1.ServerStatus.current:type_name -> v1.ServerStatus.Block
+	2,  // 3: v1.PeersGraphResponse.peers:type_name -> v1.Peer
+	4,  // 4: v1.PeersGraphResponse.links:type_name -> v1.PeerLink
+	2,  // 5: v1.PeersListResponse.peers:type_name -> v1.Peer
+	9,  // 6: v1.PeersBandwidthResponse.peers:type_name -> v1.PeerBandwidth
+	10, // 7: v1.PeerBandwidth.protocols:type_name -> v1.ProtocolBandwidth
+	22, // 8: v1.System.GetStatus:input_type -> google.protobuf.Empty
+	5,  // 9: v1.System.PeersAdd:input_type -> v1.PeersAddRequest
+	22, // 10: v1.System.PeersList:input_type -> google.protobuf.Empty
+	6,  // 11: v1.System.PeersStatus:input_type -> v1.PeersStatusRequest
+	22, // 12: v1.System.PeersGraph:input_type -> google.protobuf.Empty
+	22, // 13: v1.System.PeersBandwidth:input_type -> google.protobuf.Empty
+	22, // 14: v1.System.Subscribe:input_type -> google.protobuf.Empty
+	22, // 15: v1.System.SyncProgress:input_type -> google.protobuf.Empty
+	11, // 16: v1.System.ReplayBlocks:input_type -> v1.ReplayBlocksRequest
+	14, // 17: v1.System.ExportBlocks:input_type -> v1.ExportBlocksRequest
+	15, // 18: v1.System.ImportBlocks:input_type -> v1.RLPBlocks
+	22, // 19: v1.System.Backup:input_type -> google.protobuf.Empty
+	22, // 20: v1.System.GetLogLevel:input_type -> google.protobuf.Empty
+	18, // 21: v1.System.SetLogLevel:input_type -> v1.LogLevel
+	22, // 22: v1.System.Reload:input_type -> google.protobuf.Empty
+	22, // 23: v1.System.Shutdown:input_type -> google.protobuf.Empty
+	1,  // 24: v1.System.GetStatus:output_type -> v1.ServerStatus
+	22, // 25: v1.System.PeersAdd:output_type -> google.protobuf.Empty
+	7,  // 26: v1.System.PeersList:output_type -> v1.PeersListResponse
+	2,  // 27: v1.System.PeersStatus:output_type -> v1.Peer
+	3,  // 28: v1.System.PeersGraph:output_type -> v1.PeersGraphResponse
+	8,  // 29: v1.System.PeersBandwidth:output_type -> v1.PeersBandwidthResponse
+	0,  // 30: v1.System.Subscribe:output_type -> v1.BlockchainEvent
+	12, // 31: v1.System.SyncProgress:output_type -> v1.SyncProgressEvent
+	13, // 32: v1.System.ReplayBlocks:output_type -> v1.ReplayBlockResult
+	15, // 33: v1.System.ExportBlocks:output_type -> v1.RLPBlocks
+	17, // 34: v1.System.ImportBlocks:output_type -> v1.ImportBlocksResponse
+	16, // 35: v1.System.Backup:output_type -> v1.BackupChunk
+	18, // 36: v1.System.GetLogLevel:output_type -> v1.LogLevel
+	18, // 37: v1.System.SetLogLevel:output_type -> v1.LogLevel
+	19, // 38: v1.System.Reload:output_type -> v1.ReloadResponse
+	22, // 39: v1.System.Shutdown:output_type -> google.protobuf.Empty
+	24, // [24:40] is the sub-list for method output_type
+	8,  // [8:24] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_minimal_proto_system_proto_init() }
@@ -630,7 +1836,7 @@ func file_minimal_proto_system_proto_init() {
 			}
 		}
 		file_minimal_proto_system_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeersAddRequest); i {
+			switch v := v.(*PeersGraphResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -642,7 +1848,7 @@ func file_minimal_proto_system_proto_init() {
 			}
 		}
 		file_minimal_proto_system_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeersStatusRequest); i {
+			switch v := v.(*PeerLink); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -654,7 +1860,7 @@ func file_minimal_proto_system_proto_init() {
 			}
 		}
 		file_minimal_proto_system_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeersListResponse); i {
+			switch v := v.(*PeersAddRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -666,7 +1872,7 @@ func file_minimal_proto_system_proto_init() {
 			}
 		}
 		file_minimal_proto_system_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlockchainEvent_Header); i {
+			switch v := v.(*PeersStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -678,6 +1884,174 @@ func file_minimal_proto_system_proto_init() {
 			}
 		}
 		file_minimal_proto_system_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeersListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeersBandwidthResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerBandwidth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProtocolBandwidth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayBlocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncProgressEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayBlockResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportBlocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RLPBlocks); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackupChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportBlocksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogLevel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockchainEvent_Header); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minimal_proto_system_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ServerStatus_Block); i {
 			case 0:
 				return &v.state
@@ -696,7 +2070,7 @@ func file_minimal_proto_system_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_minimal_proto_system_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
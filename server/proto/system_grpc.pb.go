@@ -27,8 +27,30 @@ type SystemClient interface {
 	PeersList(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PeersListResponse, error)
 	// PeersInfo returns the info of a peer
 	PeersStatus(ctx context.Context, in *PeersStatusRequest, opts ...grpc.CallOption) (*Peer, error)
+	// PeersGraph returns the connected peers and the peers they reported, the local view of the network
+	PeersGraph(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PeersGraphResponse, error)
+	// PeersBandwidth returns the traffic with the peers, by protocol
+	PeersBandwidth(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PeersBandwidthResponse, error)
 	// Subscribe subscribes to blockchain events
 	Subscribe(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (System_SubscribeClient, error)
+	// SyncProgress streams the progress of the syncs, starting with the current one
+	SyncProgress(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (System_SyncProgressClient, error)
+	// ReplayBlocks re-executes a range of blocks and verifies the results against the stored ones
+	ReplayBlocks(ctx context.Context, in *ReplayBlocksRequest, opts ...grpc.CallOption) (System_ReplayBlocksClient, error)
+	// ExportBlocks streams the canonical blocks of a range, encoded in RLP
+	ExportBlocks(ctx context.Context, in *ExportBlocksRequest, opts ...grpc.CallOption) (System_ExportBlocksClient, error)
+	// ImportBlocks verifies and writes a stream of RLP encoded blocks
+	ImportBlocks(ctx context.Context, opts ...grpc.CallOption) (System_ImportBlocksClient, error)
+	// Backup streams a consistent backup of the data stores, taken while the client runs
+	Backup(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (System_BackupClient, error)
+	// GetLogLevel returns the log levels of the client
+	GetLogLevel(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LogLevel, error)
+	// SetLogLevel replaces the log levels of the client
+	SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error)
+	// Reload reads the config again, and applies the settings that can change without a restart
+	Reload(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReloadResponse, error)
+	// Shutdown gracefully stops the client
+	Shutdown(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type systemClient struct {
@@ -75,6 +97,24 @@ func (c *systemClient) PeersStatus(ctx context.Context, in *PeersStatusRequest,
 	return out, nil
 }
 
+func (c *systemClient) PeersGraph(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PeersGraphResponse, error) {
+	out := new(PeersGraphResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) PeersBandwidth(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PeersBandwidthResponse, error) {
+	out := new(PeersBandwidthResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersBandwidth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *systemClient) Subscribe(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (System_SubscribeClient, error) {
 	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[0], "/v1.System/Subscribe", opts...)
 	if err != nil {
@@ -107,6 +147,204 @@ func (x *systemSubscribeClient) Recv() (*BlockchainEvent, error) {
 	return m, nil
 }
 
+func (c *systemClient) SyncProgress(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (System_SyncProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[1], "/v1.System/SyncProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemSyncProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type System_SyncProgressClient interface {
+	Recv() (*SyncProgressEvent, error)
+	grpc.ClientStream
+}
+
+type systemSyncProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemSyncProgressClient) Recv() (*SyncProgressEvent, error) {
+	m := new(SyncProgressEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *systemClient) ReplayBlocks(ctx context.Context, in *ReplayBlocksRequest, opts ...grpc.CallOption) (System_ReplayBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[2], "/v1.System/ReplayBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemReplayBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type System_ReplayBlocksClient interface {
+	Recv() (*ReplayBlockResult, error)
+	grpc.ClientStream
+}
+
+type systemReplayBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemReplayBlocksClient) Recv() (*ReplayBlockResult, error) {
+	m := new(ReplayBlockResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *systemClient) ExportBlocks(ctx context.Context, in *ExportBlocksRequest, opts ...grpc.CallOption) (System_ExportBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[3], "/v1.System/ExportBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemExportBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type System_ExportBlocksClient interface {
+	Recv() (*RLPBlocks, error)
+	grpc.ClientStream
+}
+
+type systemExportBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemExportBlocksClient) Recv() (*RLPBlocks, error) {
+	m := new(RLPBlocks)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *systemClient) ImportBlocks(ctx context.Context, opts ...grpc.CallOption) (System_ImportBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[4], "/v1.System/ImportBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemImportBlocksClient{stream}
+	return x, nil
+}
+
+type System_ImportBlocksClient interface {
+	Send(*RLPBlocks) error
+	CloseAndRecv() (*ImportBlocksResponse, error)
+	grpc.ClientStream
+}
+
+type systemImportBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemImportBlocksClient) Send(m *RLPBlocks) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *systemImportBlocksClient) CloseAndRecv() (*ImportBlocksResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportBlocksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *systemClient) Backup(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (System_BackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[5], "/v1.System/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type System_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type systemBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *systemClient) GetLogLevel(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LogLevel, error) {
+	out := new(LogLevel)
+	err := c.cc.Invoke(ctx, "/v1.System/GetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error) {
+	out := new(LogLevel)
+	err := c.cc.Invoke(ctx, "/v1.System/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) Reload(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReloadResponse, error) {
+	out := new(ReloadResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/Reload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) Shutdown(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/v1.System/Shutdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SystemServer is the server API for System service.
 // All implementations must embed UnimplementedSystemServer
 // for forward compatibility
@@ -119,8 +357,30 @@ type SystemServer interface {
 	PeersList(context.Context, *empty.Empty) (*PeersListResponse, error)
 	// PeersInfo returns the info of a peer
 	PeersStatus(context.Context, *PeersStatusRequest) (*Peer, error)
+	// PeersGraph returns the connected peers and the peers they reported, the local view of the network
+	PeersGraph(context.Context, *empty.Empty) (*PeersGraphResponse, error)
+	// PeersBandwidth returns the traffic with the peers, by protocol
+	PeersBandwidth(context.Context, *empty.Empty) (*PeersBandwidthResponse, error)
 	// Subscribe subscribes to blockchain events
 	Subscribe(*empty.Empty, System_SubscribeServer) error
+	// SyncProgress streams the progress of the syncs, starting with the current one
+	SyncProgress(*empty.Empty, System_SyncProgressServer) error
+	// ReplayBlocks re-executes a range of blocks and verifies the results against the stored ones
+	ReplayBlocks(*ReplayBlocksRequest, System_ReplayBlocksServer) error
+	// ExportBlocks streams the canonical blocks of a range, encoded in RLP
+	ExportBlocks(*ExportBlocksRequest, System_ExportBlocksServer) error
+	// ImportBlocks verifies and writes a stream of RLP encoded blocks
+	ImportBlocks(System_ImportBlocksServer) error
+	// Backup streams a consistent backup of the data stores, taken while the client runs
+	Backup(*empty.Empty, System_BackupServer) error
+	// GetLogLevel returns the log levels of the client
+	GetLogLevel(context.Context, *empty.Empty) (*LogLevel, error)
+	// SetLogLevel replaces the log levels of the client
+	SetLogLevel(context.Context, *LogLevel) (*LogLevel, error)
+	// Reload reads the config again, and applies the settings that can change without a restart
+	Reload(context.Context, *empty.Empty) (*ReloadResponse, error)
+	// Shutdown gracefully stops the client
+	Shutdown(context.Context, *empty.Empty) (*empty.Empty, error)
 	mustEmbedUnimplementedSystemServer()
 }
 
@@ -140,9 +400,42 @@ func (UnimplementedSystemServer) PeersList(context.Context, *empty.Empty) (*Peer
 func (UnimplementedSystemServer) PeersStatus(context.Context, *PeersStatusRequest) (*Peer, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PeersStatus not implemented")
 }
+func (UnimplementedSystemServer) PeersGraph(context.Context, *empty.Empty) (*PeersGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersGraph not implemented")
+}
+func (UnimplementedSystemServer) PeersBandwidth(context.Context, *empty.Empty) (*PeersBandwidthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersBandwidth not implemented")
+}
 func (UnimplementedSystemServer) Subscribe(*empty.Empty, System_SubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
+func (UnimplementedSystemServer) SyncProgress(*empty.Empty, System_SyncProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncProgress not implemented")
+}
+func (UnimplementedSystemServer) ReplayBlocks(*ReplayBlocksRequest, System_ReplayBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReplayBlocks not implemented")
+}
+func (UnimplementedSystemServer) ExportBlocks(*ExportBlocksRequest, System_ExportBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportBlocks not implemented")
+}
+func (UnimplementedSystemServer) ImportBlocks(System_ImportBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBlocks not implemented")
+}
+func (UnimplementedSystemServer) Backup(*empty.Empty, System_BackupServer) error {
+	return status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (UnimplementedSystemServer) GetLogLevel(context.Context, *empty.Empty) (*LogLevel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevel not implemented")
+}
+func (UnimplementedSystemServer) SetLogLevel(context.Context, *LogLevel) (*LogLevel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedSystemServer) Reload(context.Context, *empty.Empty) (*ReloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
+func (UnimplementedSystemServer) Shutdown(context.Context, *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
+}
 func (UnimplementedSystemServer) mustEmbedUnimplementedSystemServer() {}
 
 // UnsafeSystemServer may be embedded to opt out of forward compatibility for this service.
@@ -228,6 +521,42 @@ func _System_PeersStatus_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _System_PeersGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersGraph(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_PeersBandwidth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersBandwidth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersBandwidth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersBandwidth(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _System_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(empty.Empty)
 	if err := stream.RecvMsg(m); err != nil {
@@ -249,6 +578,188 @@ func (x *systemSubscribeServer) Send(m *BlockchainEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _System_SyncProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServer).SyncProgress(m, &systemSyncProgressServer{stream})
+}
+
+type System_SyncProgressServer interface {
+	Send(*SyncProgressEvent) error
+	grpc.ServerStream
+}
+
+type systemSyncProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemSyncProgressServer) Send(m *SyncProgressEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _System_ReplayBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReplayBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServer).ReplayBlocks(m, &systemReplayBlocksServer{stream})
+}
+
+type System_ReplayBlocksServer interface {
+	Send(*ReplayBlockResult) error
+	grpc.ServerStream
+}
+
+type systemReplayBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemReplayBlocksServer) Send(m *ReplayBlockResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _System_ExportBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServer).ExportBlocks(m, &systemExportBlocksServer{stream})
+}
+
+type System_ExportBlocksServer interface {
+	Send(*RLPBlocks) error
+	grpc.ServerStream
+}
+
+type systemExportBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemExportBlocksServer) Send(m *RLPBlocks) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _System_ImportBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SystemServer).ImportBlocks(&systemImportBlocksServer{stream})
+}
+
+type System_ImportBlocksServer interface {
+	SendAndClose(*ImportBlocksResponse) error
+	Recv() (*RLPBlocks, error)
+	grpc.ServerStream
+}
+
+type systemImportBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemImportBlocksServer) SendAndClose(m *ImportBlocksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *systemImportBlocksServer) Recv() (*RLPBlocks, error) {
+	m := new(RLPBlocks)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _System_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServer).Backup(m, &systemBackupServer{stream})
+}
+
+type System_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type systemBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _System_GetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).GetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/GetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).GetLogLevel(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLevel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).SetLogLevel(ctx, req.(*LogLevel))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/Reload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).Reload(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/Shutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).Shutdown(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // System_ServiceDesc is the grpc.ServiceDesc for System service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -272,6 +783,30 @@ var System_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PeersStatus",
 			Handler:    _System_PeersStatus_Handler,
 		},
+		{
+			MethodName: "PeersGraph",
+			Handler:    _System_PeersGraph_Handler,
+		},
+		{
+			MethodName: "PeersBandwidth",
+			Handler:    _System_PeersBandwidth_Handler,
+		},
+		{
+			MethodName: "GetLogLevel",
+			Handler:    _System_GetLogLevel_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _System_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "Reload",
+			Handler:    _System_Reload_Handler,
+		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _System_Shutdown_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -279,6 +814,31 @@ var System_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _System_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SyncProgress",
+			Handler:       _System_SyncProgress_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReplayBlocks",
+			Handler:       _System_ReplayBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportBlocks",
+			Handler:       _System_ExportBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportBlocks",
+			Handler:       _System_ImportBlocks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Backup",
+			Handler:       _System_Backup_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "minimal/proto/system.proto",
 }
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// headerReader is the access to the canonical headers required to find the retained state roots
+type headerReader interface {
+	GetHeaderByNumber(n uint64) (*types.Header, bool)
+}
+
+// RetainedRoots returns the state roots kept by the pruning, given the head of the chain.
+// The blocks without a header, like the ones below the pivot of a snapshot sync, are skipped
+func RetainedRoots(config *itrie.PruningConfig, chain headerReader, head uint64) []types.Hash {
+	roots := []types.Hash{}
+	for _, num := range config.RetainedBlocks(head) {
+		if header, ok := chain.GetHeaderByNumber(num); ok {
+			roots = append(roots, header.StateRoot)
+		}
+	}
+
+	return roots
+}
+
+// startPruning prunes the state in the background, every time the chain
+// advanced by the pruning interval since the last pruning
+func (s *Server) startPruning() {
+	var headCh <-chan eventbus.Event
+	headCh, s.stopPruning = s.eventBus.Subscribe(eventbus.TopicNewHead)
+
+	go func() {
+		var last uint64
+
+		for evnt := range headCh {
+			head := evnt.(*eventbus.NewHead).Header.Number
+			if head < last+s.config.Pruning.Interval {
+				continue
+			}
+			last = head
+
+			go s.pruneState(head)
+		}
+	}()
+}
+
+func (s *Server) pruneState(head uint64) {
+	roots := RetainedRoots(s.config.Pruning, s.blockchain, head)
+
+	res, err := s.pruner.Prune(roots)
+	if err == itrie.ErrPruneRunning || err == itrie.ErrPruneAborted {
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to prune the state", "head", head, "err", err)
+		return
+	}
+
+	s.logger.Info(
+		"pruned the state",
+		"head", head,
+		"roots", res.Roots,
+		"kept", res.Kept,
+		"deleted", res.Deleted,
+		"duration", res.Duration,
+	)
+}
+
+// checkArchive makes sure the node has the state of every block, as required by the archive mode
+func (s *Server) checkArchive() error {
+	if itrie.WasPruned(s.stateStorage) {
+		return fmt.Errorf("the state was pruned, the archive mode requires to sync the chain again from genesis")
+	}
+
+	// the first block is executed, unless its header was snapshot synced
+	if header, ok := s.blockchain.GetHeaderByNumber(1); ok {
+		if _, ok := s.blockchain.GetStateRoot(1); !ok {
+			if _, err := s.state.NewSnapshotAt(header.StateRoot); err != nil {
+				return fmt.Errorf("the chain was snapshot synced, the archive mode requires to sync the chain again from genesis")
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasState returns whether the state of the root is in the storage
+func (s *Server) hasState(root types.Hash) bool {
+	_, err := s.state.NewSnapshotAt(root)
+
+	return err == nil
+}
+
+// CheckState returns the reason why the state of the block can't be queried, if it can't
+func (j *jsonRPCHub) CheckState(header *types.Header) error {
+	st, _ := j.stateAt(header.StateRoot)
+	if _, err := st.NewSnapshotAt(header.StateRoot); err == nil {
+		return nil
+	}
+
+	if _, ok := j.GetStateRoot(header.Number); !ok {
+		return fmt.Errorf("the state of block %d is not available, its header was synced without executing it", header.Number)
+	}
+	if j.pruning != nil {
+		return fmt.Errorf(
+			"the state of block %d is not available, the node only keeps the state of the last %d blocks, "+
+				"query an archive node instead",
+			header.Number,
+			j.pruning.Retention,
+		)
+	}
+
+	return fmt.Errorf("the state of block %d is missing from the storage", header.Number)
+}
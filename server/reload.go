@@ -0,0 +1,131 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var errReloadNotSupported = errors.New("the client can't reload its config")
+
+// reload reads the config again, and applies the settings that can change without a restart:
+// the gas price floor, the JSON-RPC limits, the log levels and the peer lists.
+// It returns the names of the flags of the reloaded settings
+func (s *Server) reload() ([]string, error) {
+	if s.config.Reload == nil {
+		return nil, errReloadNotSupported
+	}
+
+	next, err := s.config.Reload()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config: %v", err)
+	}
+
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	reloaded := []string{}
+
+	// gas price floor
+	s.txpool.SetMinGasPrice(next.MinGasPrice)
+	s.txpool.SetZeroGasAllowlist(s.zeroGasAllowlist(next.ZeroGasAllowlist))
+	s.config.MinGasPrice = next.MinGasPrice
+	s.config.ZeroGasAllowlist = next.ZeroGasAllowlist
+	reloaded = append(reloaded, "min-gas-price", "zero-gas-allowlist")
+
+	// JSON-RPC limits
+	if s.jsonrpcServer != nil {
+		s.jsonrpcServer.SetLogsLimits(next.LogsBlockRange, next.LogsResultLimit)
+		s.config.LogsBlockRange = next.LogsBlockRange
+		s.config.LogsResultLimit = next.LogsResultLimit
+		reloaded = append(reloaded, "jsonrpc-logs-block-range", "jsonrpc-logs-limit")
+
+		s.jsonrpcServer.SetCallLimits(next.CallTimeout, next.CallGasCap)
+		s.config.CallTimeout = next.CallTimeout
+		s.config.CallGasCap = next.CallGasCap
+		reloaded = append(reloaded, "jsonrpc-call-timeout", "jsonrpc-gas-cap")
+
+		switch {
+		case s.config.RateLimit == nil && next.RateLimit != nil:
+			s.logger.Warn("The JSON-RPC rate limiter is enabled on the next restart")
+		case s.config.RateLimit != nil:
+			rateLimit := next.RateLimit
+			if rateLimit == nil {
+				// the limiter stays in place, without quotas
+				rateLimit = &jsonrpc.RateLimitConfig{}
+			}
+			if err := s.jsonrpcServer.SetRateLimit(rateLimit); err != nil {
+				return nil, err
+			}
+			s.config.RateLimit = rateLimit
+			reloaded = append(reloaded, "jsonrpc-rate-limit", "jsonrpc-rate-burst", "jsonrpc-max-concurrent")
+		}
+	}
+
+	// gas limit target of the proposed blocks
+	if next.Chain != nil {
+		s.blockchain.SetBlockGasTarget(next.Chain.Params.BlockGasTarget)
+		reloaded = append(reloaded, "block-gas-target")
+	}
+
+	// log levels
+	if s.config.LogLevels != nil && next.LogLevels != nil {
+		s.config.LogLevels.Set(next.LogLevels.Spec())
+		reloaded = append(reloaded, "log-level")
+	}
+
+	// peer lists
+	s.reloadPeerSets(s.network, s.config.Network, next.Network)
+	if s.consensusNetwork != nil && next.ConsensusNetwork != nil {
+		s.reloadPeerSets(s.consensusNetwork, s.config.ConsensusNetwork, next.ConsensusNetwork)
+	}
+	reloaded = append(reloaded, "static-peers", "trusted-peers")
+
+	// the allowlist file is read again, the peers no longer in it are disconnected
+	s.network.RefreshAllowlist()
+	if s.consensusNetwork != nil {
+		s.consensusNetwork.RefreshAllowlist()
+	}
+	reloaded = append(reloaded, "allowlist")
+
+	s.logger.Info("Config reloaded", "settings", reloaded)
+
+	return reloaded, nil
+}
+
+// reloadPeerSets applies the changes of the static and trusted peers of the config.
+// The peers added at runtime, over the admin API, are kept
+func (s *Server) reloadPeerSets(srv *network.Server, current, next *network.Config) {
+	nextStatic := map[peer.ID]struct{}{}
+	for _, info := range next.StaticPeers {
+		nextStatic[info.ID] = struct{}{}
+
+		if !srv.IsStatic(info.ID) {
+			if err := srv.AddStaticPeer(info); err != nil {
+				s.logger.Warn("Omitting static peer", "id", info.ID, "err", err)
+			}
+		}
+	}
+	for _, info := range current.StaticPeers {
+		if _, ok := nextStatic[info.ID]; !ok {
+			srv.RemoveStaticPeer(info.ID)
+		}
+	}
+
+	nextTrusted := map[peer.ID]struct{}{}
+	for _, id := range next.TrustedPeers {
+		nextTrusted[id] = struct{}{}
+		srv.AddTrustedPeer(id)
+	}
+	for _, id := range current.TrustedPeers {
+		if _, ok := nextTrusted[id]; !ok {
+			srv.RemoveTrustedPeer(id)
+		}
+	}
+
+	current.StaticPeers = next.StaticPeers
+	current.TrustedPeers = next.TrustedPeers
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -8,14 +9,24 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/0xPolygon/polygon-sdk/accounts"
+	"github.com/0xPolygon/polygon-sdk/bridge"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/checkpoint"
 	"github.com/0xPolygon/polygon-sdk/crypto"
-	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/0xPolygon/polygon-sdk/ethstats"
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/exporter"
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
 	"github.com/0xPolygon/polygon-sdk/helper/keccak"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/helper/tracing"
 	"github.com/0xPolygon/polygon-sdk/jsonrpc"
 	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/protocol"
 	"github.com/0xPolygon/polygon-sdk/secrets"
 	"github.com/0xPolygon/polygon-sdk/server/proto"
 	"github.com/0xPolygon/polygon-sdk/state"
@@ -26,14 +37,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/fastrlp"
 	"google.golang.org/grpc"
 
+	"github.com/0xPolygon/polygon-sdk/state/fork"
 	itrie "github.com/0xPolygon/polygon-sdk/state/immutable-trie"
 	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
 	"github.com/0xPolygon/polygon-sdk/state/runtime/precompiled"
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
 	"github.com/0xPolygon/polygon-sdk/consensus"
+	consensusDev "github.com/0xPolygon/polygon-sdk/consensus/dev"
 )
 
 // Minimal is the central manager of the blockchain client
@@ -43,8 +58,30 @@ type Server struct {
 	state        state.State
 	stateStorage itrie.Storage
 
+	// secondary store of the accounts archived by the state rent
+	archiveStorage itrie.Storage
+
+	// remote is the state of the chain the dev chain is forked from, if set
+	remote *fork.Client
+
+	// pruner of the state storage, if the pruning is enabled
+	pruner      *itrie.Pruner
+	stopPruning func()
+
+	// databases are the opened key-value databases, by name
+	databases map[string]kvdb.Database
+
+	// freezer holds the old blocks of the blockchain storage, if enabled
+	freezer *storage.Freezer
+
+	// stopDatabaseStats stops the report of the database statistics, if started
+	stopDatabaseStats func()
+
 	consensus consensus.Consensus
 
+	// bus of the chain and pool events, for the subsystems and the plugins
+	eventBus *eventbus.Bus
+
 	// blockchain stack
 	blockchain *blockchain.Blockchain
 	chain      *chain.Chain
@@ -52,6 +89,12 @@ type Server struct {
 	// state executor
 	executor *state.Executor
 
+	// competing branches announced by the peers
+	forkMonitor *protocol.ForkMonitor
+
+	// progress of the syncs from the peers
+	syncTracker *protocol.SyncTracker
+
 	// jsonrpc stack
 	jsonrpcServer *jsonrpc.JSONRPC
 
@@ -61,16 +104,51 @@ type Server struct {
 	// libp2p network
 	network *network.Server
 
+	// consensusNetwork is the separate network of the validator traffic, if set
+	consensusNetwork *network.Server
+
 	// transaction pool
 	txpool *txpool.TxPool
 
 	serverMetrics *serverMetrics
 
+	// evmProfiler aggregates the stats of the opcodes and contracts of the processed blocks, if enabled
+	evmProfiler *evm.Profiler
+
 	prometheusServer *http.Server
+
+	// healthServer serves the health and readiness endpoints, if enabled
+	healthServer *http.Server
+
+	// ethstats reports the stats of the node to a netstats server, if enabled
+	ethstats *ethstats.Service
+
+	// checkpoint anchors the chain to an external chain, if enabled
+	checkpoint *checkpoint.Service
+
+	// bridge relays the deposits of the root chain, and proves the withdrawals to it, if enabled
+	bridge *bridge.Bridge
+
+	// exporter publishes the new blocks to a data pipeline, if enabled
+	exporter *exporter.Exporter
+
+	// stopTracing flushes the buffered spans and stops their export, if the tracing is enabled
+	stopTracing func() error
+
 	// secrets manager
 	secretsManager secrets.SecretsManager
+
+	// reloadLock serializes the reloads of the config
+	reloadLock sync.Mutex
+
+	// closed when a shutdown is requested over grpc
+	shutdownCh   chan struct{}
+	shutdownOnce sync.Once
 }
 
+// databaseStatsInterval is the interval of the report of the database statistics to the metrics
+const databaseStatsInterval = 15 * time.Second
+
 var dirPaths = []string{
 	"blockchain",
 	"keystore",
@@ -79,17 +157,25 @@ var dirPaths = []string{
 
 // NewServer creates a new Minimal server, using the passed in configuration
 func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
+	grpcOpts, err := config.GRPCAccess.serverOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the gRPC access control: %v", err)
+	}
+
 	m := &Server{
 		logger:     logger,
 		config:     config,
 		chain:      config.Chain,
-		grpcServer: grpc.NewServer(),
+		grpcServer: grpc.NewServer(grpcOpts...),
+		eventBus:   eventbus.New(logger),
+		shutdownCh: make(chan struct{}),
+		databases:  map[string]kvdb.Database{},
 	}
 
 	m.logger.Info("Data dir", "path", config.DataDir)
 
-	// Generate all the paths in the dataDir
-	if err := common.SetupDataDir(config.DataDir, dirPaths); err != nil {
+	// Generate all the paths in the dataDir, and the directories of the stores placed elsewhere
+	if err := setupStoreDirs(config.DataDir, config.StoreDirs); err != nil {
 		return nil, fmt.Errorf("failed to create data directories: %v", err)
 	}
 
@@ -99,6 +185,17 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 	} else {
 		m.serverMetrics = metricProvider("PSDK", config.Chain.Name, false)
 	}
+
+	if config.Telemetry.Tracing != nil {
+		stopTracing, err := tracing.Setup(config.Telemetry.Tracing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up the tracing: %v", err)
+		}
+		m.stopTracing = stopTracing
+
+		m.logger.Info("Tracing enabled", "endpoint", config.Telemetry.Tracing.Endpoint)
+	}
+
 	// Set up the secrets manager
 	if err := m.setupSecretsManager(); err != nil {
 		return nil, fmt.Errorf("failed to set up the secrets manager: %v", err)
@@ -110,6 +207,11 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		netConfig.Chain = m.config.Chain
 		netConfig.DataDir = filepath.Join(m.config.DataDir, "libp2p")
 		netConfig.SecretsManager = m.secretsManager
+		netConfig.Metrics = m.serverMetrics.network
+
+		if config.AllowlistContract != nil {
+			netConfig.Allowlist = append(netConfig.Allowlist, &contractAllowlist{srv: m, address: *config.AllowlistContract})
+		}
 
 		network, err := network.NewServer(logger, netConfig)
 		if err != nil {
@@ -118,31 +220,161 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		m.network = network
 	}
 
+	// the validator traffic on a separate listener, with the access control of the public network
+	if config.ConsensusNetwork != nil {
+		netConfig := config.ConsensusNetwork
+		netConfig.Chain = m.config.Chain
+		netConfig.DataDir = filepath.Join(m.config.DataDir, "libp2p-consensus")
+		netConfig.SecretsManager = m.secretsManager
+		netConfig.Consortium = config.Network.Consortium
+		netConfig.Allowlist = config.Network.Allowlist
+		netConfig.Compression = config.Network.Compression
+		netConfig.MaxMessageSizes = config.Network.MaxMessageSizes
+		netConfig.Metrics = m.serverMetrics.network
+
+		consensusNetwork, err := network.NewServer(logger.Named("consensus-network"), netConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start the consensus network: %v", err)
+		}
+		m.consensusNetwork = consensusNetwork
+	}
+
+	// the key of the encryption at rest, if enabled
+	cipher, err := m.setupStorageCipher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the storage encryption: %v", err)
+	}
+
 	// start blockchain object
-	stateStorage, err := itrie.NewLevelDBStorage(filepath.Join(m.config.DataDir, "trie"), logger)
+	stateDB, err := m.openDatabase("trie")
 	if err != nil {
 		return nil, err
 	}
+	stateStorage, err := itrie.NewDatabaseStorage(stateDB, cipher)
+	if err != nil {
+		stateDB.Close()
+		return nil, fmt.Errorf("failed to open the trie store: %v", err)
+	}
 	m.stateStorage = stateStorage
 
-	st := itrie.NewState(stateStorage)
+	if config.Cache != 0 {
+		m.stateStorage = itrie.NewCachingStorage(m.stateStorage, config.Cache*1024*1024)
+	}
+
+	if config.Pruning != nil {
+		pruner, err := itrie.NewPruner(m.stateStorage, logger)
+		if err != nil {
+			return nil, err
+		}
+		m.pruner = pruner
+		m.stateStorage = pruner
+	}
+
+	st := itrie.NewState(m.stateStorage)
 	m.state = st
 
 	m.executor = state.NewExecutor(config.Chain.Params, st, logger)
-	m.executor.SetRuntime(precompiled.NewPrecompiled())
-	m.executor.SetRuntime(evm.NewEVM())
+	precompiles := precompiled.NewPrecompiled()
+	if err := precompiles.AddCustom(config.Chain.Params.Precompiles); err != nil {
+		return nil, fmt.Errorf("failed to set up the precompiled contracts: %v", err)
+	}
+	m.executor.SetRuntime(precompiles)
+
+	evmRuntime := evm.NewEVM()
+	if config.EVMProfiler {
+		m.evmProfiler = evm.NewProfiler(m.serverMetrics.evm)
+		evmRuntime.SetProfiler(m.evmProfiler)
+	}
+	m.executor.SetRuntime(evmRuntime)
+	m.executor.SetMetrics(m.serverMetrics.state)
+	m.executor.SetParallelism(int(config.ExecParallelism))
+
+	if config.Chain.Params.StateRent != nil {
+		archiveDB, err := m.openDatabase("archive")
+		if err != nil {
+			return nil, err
+		}
+		archiveStorage, err := itrie.NewDatabaseStorage(archiveDB, cipher)
+		if err != nil {
+			archiveDB.Close()
+			return nil, fmt.Errorf("failed to open the archive store: %v", err)
+		}
+		m.archiveStorage = archiveStorage
+		m.executor.SetArchiveStore(archiveStorage)
+	}
+
+	if config.Fork != nil {
+		forkDB, err := m.openDatabase("fork")
+		if err != nil {
+			return nil, err
+		}
+		remote, err := fork.NewClient(logger, config.Fork, forkDB)
+		if err != nil {
+			forkDB.Close()
+			return nil, fmt.Errorf("failed to fork the remote chain: %v", err)
+		}
+		m.remote = remote
+		m.executor.SetRemoteState(remote)
+	}
 
 	// compute the genesis root state
-	genesisRoot := m.executor.WriteGenesis(config.Chain.Genesis.Alloc)
+	genesisRoot, err := m.executor.WriteGenesis(config.Chain.Genesis.Alloc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write the genesis state: %v", err)
+	}
 	config.Chain.Genesis.StateRoot = genesisRoot
 
 	// blockchain object
-	m.blockchain, err = blockchain.NewBlockchain(logger, m.config.DataDir, config.Chain, nil, m.executor)
+	blockchainDB, err := m.openDatabase("blockchain")
+	if err != nil {
+		return nil, err
+	}
+	var freezer *storage.Freezer
+	if config.FreezerThreshold != 0 {
+		if freezer, err = storage.OpenFreezer(m.config.StoreDirs.Dir(m.config.DataDir, "ancient"), cipher); err != nil {
+			blockchainDB.Close()
+			return nil, fmt.Errorf("failed to open the freezer: %v", err)
+		}
+	}
+	blockchainStorage, err := storage.NewDatabaseStorage(blockchainDB, freezer, cipher, logger.Named("storage"))
+	if err != nil {
+		blockchainDB.Close()
+		if freezer != nil {
+			freezer.Close()
+		}
+		return nil, fmt.Errorf("failed to open the blockchain store: %v", err)
+	}
+	m.freezer = freezer
+
+	m.blockchain, err = blockchain.NewBlockchainWithStorage(logger, blockchainStorage, config.Chain, nil, m.executor)
 	if err != nil {
 		return nil, err
 	}
 
+	m.blockchain.SetMetrics(m.serverMetrics.blockchain)
+
+	if config.Telemetry.PrometheusAddr != nil {
+		m.stopDatabaseStats = kvdb.ReportStats(logger, m.serverMetrics.kvdb, m.databases, databaseStatsInterval)
+	}
+
 	m.executor.GetHash = m.blockchain.GetHashHelper
+	m.blockchain.SetMaxReorgDepth(config.MaxReorgDepth)
+	m.blockchain.SetEventBus(m.eventBus)
+	m.blockchain.SetGasTargetSource(&contractGasTargetSource{executor: m.executor})
+
+	// the on-chain allowlist is available once the chain is loaded
+	m.network.RefreshAllowlist()
+	if m.consensusNetwork != nil {
+		m.consensusNetwork.RefreshAllowlist()
+	}
+
+	// fork monitor, fed by the blocks announced in the sync protocol
+	m.forkMonitor = protocol.NewForkMonitor(logger, m.blockchain, m.serverMetrics.protocol)
+	m.syncTracker = protocol.NewSyncTracker()
+
+	if m.config.LightServe {
+		protocol.ServeLightClients(m.network, m.blockchain, m.stateStorage)
+	}
 
 	{
 		hub := &txpoolHub{
@@ -167,9 +399,32 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 			return nil, err
 		}
 
-		// use the eip155 signer
-		signer := crypto.NewEIP155Signer(uint64(m.config.Chain.Params.ChainID))
+		// use the london signer, it accepts the eip155 transactions too
+		signer := crypto.NewLondonSigner(uint64(m.config.Chain.Params.ChainID))
 		m.txpool.AddSigner(signer)
+		m.txpool.SetEventBus(m.eventBus)
+
+		if m.config.PriceBump != 0 {
+			m.txpool.SetPriceBump(m.config.PriceBump)
+		}
+		if m.config.QueueLifetime != 0 {
+			m.txpool.SetQueueLifetime(m.config.QueueLifetime)
+		}
+		if m.config.TxLifetime != 0 {
+			m.txpool.SetTxLifetime(m.config.TxLifetime)
+		}
+		if m.config.MaxAccountPending != 0 || m.config.MaxAccountQueued != 0 {
+			maxQueued := m.config.MaxAccountQueued
+			if maxQueued == 0 {
+				maxQueued = txpool.DefaultMaxAccountQueued
+			}
+			m.txpool.SetAccountLimits(m.config.MaxAccountPending, maxQueued)
+		}
+		m.txpool.SetMinGasPrice(m.config.MinGasPrice)
+		m.txpool.SetZeroGasAllowlist(m.zeroGasAllowlist(m.config.ZeroGasAllowlist))
+		if m.config.TxPoolJournal != "" {
+			m.txpool.SetJournal(m.config.TxPoolJournal)
+		}
 	}
 
 	{
@@ -187,11 +442,52 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// roll back the blocks left incomplete by an unclean shutdown, instead of failing on them later
+	if err := m.blockchain.CheckIntegrity(m.hasState); err != nil {
+		return nil, fmt.Errorf("the chain is corrupted: %v", err)
+	}
+
+	if m.config.Archive {
+		if err := m.checkArchive(); err != nil {
+			return nil, err
+		}
+	}
+
+	// index the logs of the chain in the background for the log queries
+	m.blockchain.StartBloomIndexer()
+
+	// index the transactions of the new blocks by address for eth_getTransactionsByAddress
+	if config.AddressIndex {
+		if err := m.blockchain.EnableAddressIndex(); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.FreezerThreshold != 0 {
+		m.blockchain.StartFreezer(config.FreezerThreshold)
+	}
+
+	if m.pruner != nil {
+		m.startPruning()
+	}
+
+	// the journaled transactions are validated against the head state
+	m.txpool.Start()
+
+	// the transactions of the blocks replaced by a reorg go back to the pool
+	m.txpool.WatchReorgs(m.eventBus)
+
 	// setup grpc server
 	if err := m.setupGRPC(); err != nil {
 		return nil, err
 	}
 
+	if config.Bridge != nil {
+		if err := m.setupBridge(); err != nil {
+			return nil, err
+		}
+	}
+
 	// setup jsonrpc
 	if err := m.setupJSONRPC(); err != nil {
 		return nil, err
@@ -201,6 +497,31 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// the validators relay the deposits of the root chain
+	if m.bridge != nil && m.config.Seal {
+		m.bridge.Start()
+	}
+
+	if config.Health != nil {
+		m.healthServer = m.startHealthServer(config.Health)
+	}
+
+	if config.EthStats != nil {
+		m.setupEthstats()
+	}
+
+	if config.Checkpoint != nil {
+		if err := m.setupCheckpoint(); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Export != nil {
+		if err := m.setupExporter(); err != nil {
+			return nil, err
+		}
+	}
+
 	return m, nil
 }
 
@@ -209,6 +530,11 @@ type txpoolHub struct {
 	*blockchain.Blockchain
 }
 
+// GetForksInTime returns the active forks at the given block height
+func (t *txpoolHub) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return t.Blockchain.Config().Forks.At(blockNumber)
+}
+
 func (t *txpoolHub) GetNonce(root types.Hash, addr types.Address) uint64 {
 	snap, err := t.state.NewSnapshotAt(root)
 	if err != nil {
@@ -288,6 +614,45 @@ func (s *Server) setupSecretsManager() error {
 	return nil
 }
 
+// openDatabase opens the key-value database of a data store with the configured backend
+func (s *Server) openDatabase(name string) (kvdb.Database, error) {
+	db, err := kvdb.Open(s.config.DBBackend, s.config.StoreDirs.Dir(s.config.DataDir, name))
+	if err != nil {
+		return nil, err
+	}
+	s.databases[name] = db
+
+	return db, nil
+}
+
+// setupStorageCipher returns the cipher of the encryption at rest of the data stores,
+// or nil if it is disabled. The key is generated on the first run
+func (s *Server) setupStorageCipher() (*encryption.Cipher, error) {
+	if !s.config.StorageEncryption {
+		return nil, nil
+	}
+
+	if !s.secretsManager.HasSecret(secrets.StorageKey) {
+		key, err := encryption.GenerateAndEncodeKey()
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate the storage key, %v", err)
+		}
+
+		if err := s.secretsManager.SetSecret(secrets.StorageKey, key); err != nil {
+			return nil, fmt.Errorf("unable to store the storage key to Secrets Manager, %v", err)
+		}
+
+		s.logger.Info("Generated the storage encryption key")
+	}
+
+	key, err := s.secretsManager.GetSecret(secrets.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the storage key from Secrets Manager, %v", err)
+	}
+
+	return encryption.ParseKey(key)
+}
+
 // setupConsensus sets up the consensus mechanism
 func (s *Server) setupConsensus() error {
 	engineName := s.config.Chain.Params.GetEngine()
@@ -300,6 +665,16 @@ func (s *Server) setupConsensus() error {
 	if !ok {
 		engineConfig = map[string]interface{}{}
 	}
+
+	// the registry of the node overrides the one of the chain, if any
+	if s.config.ValidatorRegistry != "" {
+		nodeConfig := map[string]interface{}{}
+		for k, v := range engineConfig {
+			nodeConfig[k] = v
+		}
+		nodeConfig["registry"] = s.config.ValidatorRegistry
+		engineConfig = nodeConfig
+	}
 	config := &consensus.Config{
 		Params: s.config.Chain.Params,
 		Config: engineConfig,
@@ -307,17 +682,23 @@ func (s *Server) setupConsensus() error {
 	}
 	consensus, err := engine(
 		&consensus.ConsensusParams{
-			Context:        context.Background(),
-			Seal:           s.config.Seal,
-			Config:         config,
-			Txpool:         s.txpool,
-			Network:        s.network,
-			Blockchain:     s.blockchain,
-			Executor:       s.executor,
-			Grpc:           s.grpcServer,
-			Logger:         s.logger.Named("consensus"),
-			Metrics:        s.serverMetrics.consensus,
-			SecretsManager: s.secretsManager,
+			Context:          context.Background(),
+			Seal:             s.config.Seal,
+			Config:           config,
+			Txpool:           s.txpool,
+			Network:          s.network,
+			ConsensusNetwork: s.consensusNetwork,
+			Blockchain:       s.blockchain,
+			Executor:         s.executor,
+			Grpc:             s.grpcServer,
+			Logger:           s.logger.Named("consensus"),
+			Metrics:          s.serverMetrics.consensus,
+			SecretsManager:   s.secretsManager,
+			ForkMonitor:      s.forkMonitor,
+			SyncTracker:      s.syncTracker,
+			StateStorage:     s.stateStorage,
+			SnapshotSync:     s.config.SnapshotSync,
+			SystemTxs:        consensus.SystemCalls(s.config.Chain.Params.SystemCalls),
 		},
 	)
 	if err != nil {
@@ -329,11 +710,20 @@ func (s *Server) setupConsensus() error {
 }
 
 type jsonRPCHub struct {
-	state state.State
+	state   state.State
+	pending *pendingBlock
+	pruning *itrie.PruningConfig
+
+	evmProfiler *evm.Profiler
+
+	// remote is the state of the chain forked from, read for the accounts missing from the local state
+	remote *fork.Client
 
 	*blockchain.Blockchain
 	*txpool.TxPool
 	*state.Executor
+	*protocol.ForkMonitor
+	*protocol.SyncTracker
 }
 
 // HELPER + WRAPPER METHODS //
@@ -342,7 +732,9 @@ func (j *jsonRPCHub) getState(root types.Hash, slot []byte) ([]byte, error) {
 	// the values in the trie are the hashed objects of the keys
 	key := keccak.Keccak256(nil, slot)
 
-	snap, err := j.state.NewSnapshotAt(root)
+	st, _ := j.stateAt(root)
+
+	snap, err := st.NewSnapshotAt(root)
 	if err != nil {
 		return nil, err
 	}
@@ -355,6 +747,9 @@ func (j *jsonRPCHub) getState(root types.Hash, slot []byte) ([]byte, error) {
 
 func (j *jsonRPCHub) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
 	obj, err := j.getState(root, addr.Bytes())
+	if err == jsonrpc.ErrStateNotFound && j.remote != nil {
+		return j.getRemoteAccount(addr)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -365,6 +760,23 @@ func (j *jsonRPCHub) GetAccount(root types.Hash, addr types.Address) (*state.Acc
 	return &account, nil
 }
 
+// getRemoteAccount returns the account of the chain forked from, if it is missing from the local state
+func (j *jsonRPCHub) getRemoteAccount(addr types.Address) (*state.Account, error) {
+	remote, ok := j.remote.GetAccount(addr)
+	if !ok {
+		return nil, jsonrpc.ErrStateNotFound
+	}
+
+	account := &state.Account{
+		Nonce:    remote.Nonce,
+		Balance:  remote.Balance,
+		Root:     types.EmptyRootHash,
+		CodeHash: crypto.Keccak256(remote.Code),
+	}
+
+	return account, nil
+}
+
 // GetForksInTime returns the active forks at the given block height
 func (j *jsonRPCHub) GetForksInTime(blockNumber uint64) chain.ForksInTime {
 	return j.Executor.GetForksInTime(blockNumber)
@@ -378,6 +790,12 @@ func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.
 	}
 
 	obj, err := j.getState(account.Root, slot.Bytes())
+	if err == jsonrpc.ErrStateNotFound && j.remote != nil {
+		// the slots are stored rlp encoded, without the leading zeros
+		val := j.remote.GetStorage(addr, slot)
+		ar := &fastrlp.Arena{}
+		return ar.NewBytes(bytes.TrimLeft(val.Bytes(), "\x00")).MarshalTo(nil), nil
+	}
 
 	if err != nil {
 		return nil, err
@@ -388,6 +806,19 @@ func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.
 
 func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 	res, ok := j.state.GetCode(hash)
+	if !ok {
+		// the code deployed by the pending block is only in its state
+		j.pending.lock.Lock()
+		st := j.pending.state
+		j.pending.lock.Unlock()
+
+		if st != nil {
+			res, ok = st.GetCode(hash)
+		}
+	}
+	if !ok && j.remote != nil {
+		res, ok = j.remote.GetCode(hash)
+	}
 
 	if !ok {
 		return nil, fmt.Errorf("unable to fetch code")
@@ -396,38 +827,208 @@ func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 	return res, nil
 }
 
-func (j *jsonRPCHub) ApplyTxn(header *types.Header, txn *types.Transaction) (result *runtime.ExecutionResult, err error) {
-	blockCreator, err := j.GetConsensus().GetBlockCreator(header)
-	if err != nil {
+func (j *jsonRPCHub) ApplyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override state.StateOverride,
+	timeout time.Duration,
+) (result *runtime.ExecutionResult, err error) {
+	var blockCreator types.Address
+	if j.isPendingHeader(header) {
+		// the pending header is not sealed, the creator is set in the miner field
+		blockCreator = header.Miner
+	} else if blockCreator, err = j.GetConsensus().GetBlockCreator(header); err != nil {
 		return nil, err
 	}
 
-	transition, err := j.BeginTxn(header.StateRoot, header, blockCreator)
+	_, executor := j.stateAt(header.StateRoot)
+
+	transition, err := executor.BeginTxn(header.StateRoot, header, blockCreator)
 
 	if err != nil {
 		return
 	}
 
+	if err = transition.ApplyOverride(override); err != nil {
+		return
+	}
+
+	// the calls without fees are not charged the base fee
+	transition.SetNoBaseFee(true)
+
+	if timeout != 0 {
+		timer := time.AfterFunc(timeout, transition.Interrupt)
+		defer timer.Stop()
+	}
+
 	result, err = transition.Apply(txn)
 
 	return
 }
 
+// SimulateBundle executes the transactions on top of the state of the parent, in the context of the block
+// following it, built like the pending block
+func (j *jsonRPCHub) SimulateBundle(
+	parent *types.Header,
+	timestamp uint64,
+	txs []*types.Transaction,
+) (*state.BundleResult, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Difficulty: parent.Difficulty,
+		Timestamp:  timestamp,
+		BaseFee:    j.CalculateBaseFee(parent),
+	}
+
+	gasLimit, err := j.CalculateGasLimit(header.Number)
+	if err != nil {
+		return nil, err
+	}
+	header.GasLimit = gasLimit
+
+	// the creator of the next block is not known, the one of the parent is used instead
+	coinbase := parent.Miner
+	if !j.isPendingHeader(parent) {
+		if coinbase, err = j.GetConsensus().GetBlockCreator(parent); err != nil {
+			return nil, err
+		}
+	}
+	header.Miner = coinbase
+
+	_, executor := j.stateAt(parent.StateRoot)
+
+	return executor.SimulateBundle(parent.StateRoot, header, coinbase, txs)
+}
+
+// GetBlockTraces re-executes the block on top of the state of its parent,
+// and returns the call traces of its transactions
+func (j *jsonRPCHub) GetBlockTraces(block *types.Block) ([]*state.CallTrace, error) {
+	parent, ok := j.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return nil, fmt.Errorf("parent of block %d not found", block.Number())
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Executor.TraceBlock(parent.StateRoot, block, blockCreator)
+}
+
+// GetStorageRangeAt re-executes the first transactions of the block on top of the state of its parent,
+// and returns a range of the storage of the account
+func (j *jsonRPCHub) GetStorageRangeAt(
+	block *types.Block,
+	txIndex int,
+	addr types.Address,
+	start types.Hash,
+	max int,
+) (*state.StorageRange, error) {
+	parent, ok := j.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return nil, fmt.Errorf("parent of block %d not found", block.Number())
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Executor.StorageRangeAt(parent.StateRoot, block, blockCreator, txIndex, addr, start, max)
+}
+
+// GetAccountRange returns a range of the accounts of the state
+func (j *jsonRPCHub) GetAccountRange(
+	root types.Hash,
+	start types.Hash,
+	max int,
+	withCode bool,
+	withStorage bool,
+) (*state.AccountRange, error) {
+	_, executor := j.stateAt(root)
+
+	return executor.AccountRange(root, start, max, withCode, withStorage)
+}
+
+// GetModifiedAccounts re-executes the block on top of the state of its parent,
+// and returns the accounts it modified
+func (j *jsonRPCHub) GetModifiedAccounts(block *types.Block) ([]types.Address, error) {
+	parent, ok := j.GetHeaderByHash(block.ParentHash())
+	if !ok {
+		return nil, fmt.Errorf("parent of block %d not found", block.Number())
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Executor.ModifiedAccounts(parent.StateRoot, block, blockCreator)
+}
+
+// EVMProfile returns the stats of the evm profiler, optionally clearing them
+func (j *jsonRPCHub) EVMProfile(limit int, reset bool) (*evm.Profile, error) {
+	if j.evmProfiler == nil {
+		return nil, fmt.Errorf("the evm profiler is disabled")
+	}
+
+	return j.evmProfiler.Profile(limit, reset), nil
+}
+
 // SETUP //
 
 // setupJSONRCP sets up the JSONRPC server, using the set configuration
 func (s *Server) setupJSONRPC() error {
 	hub := &jsonRPCHub{
-		state:      s.state,
-		Blockchain: s.blockchain,
-		TxPool:     s.txpool,
-		Executor:   s.executor,
+		state:       s.state,
+		pending:     &pendingBlock{storage: s.stateStorage},
+		pruning:     s.config.Pruning,
+		Blockchain:  s.blockchain,
+		TxPool:      s.txpool,
+		Executor:    s.executor,
+		ForkMonitor: s.forkMonitor,
+		SyncTracker: s.syncTracker,
+		evmProfiler: s.evmProfiler,
+		remote:      s.remote,
 	}
 
 	conf := &jsonrpc.Config{
 		Store:   hub,
 		Addr:    s.config.JSONRPCAddr,
 		ChainID: uint64(s.config.Chain.Params.ChainID),
+		Access:  s.config.JSONRPC,
+
+		LogsBlockRange:  s.config.LogsBlockRange,
+		LogsResultLimit: s.config.LogsResultLimit,
+		CallTimeout:     s.config.CallTimeout,
+		CallGasCap:      s.config.CallGasCap,
+		GraphQL:         s.config.GraphQL,
+		LoadShed:        s.config.LoadShed,
+		IPCPath:         s.config.IPCPath,
+		RateLimit:       s.config.RateLimit,
+		CallCache:       s.config.CallCache,
+		Metrics:         s.serverMetrics.jsonrpc,
+	}
+
+	if s.config.Personal {
+		keystore, err := accounts.NewKeystore(filepath.Join(s.config.DataDir, "keystore"))
+		if err != nil {
+			return err
+		}
+		conf.Accounts = keystore
+	}
+	if s.config.Admin {
+		conf.Peers = &peerAdmin{network: s.network}
+	}
+	conf.Debug = s.config.Debug
+	conf.Trace = s.config.Trace
+	if dev, ok := s.consensus.(*consensusDev.Dev); ok {
+		conf.Dev = dev
+	}
+	if s.bridge != nil {
+		conf.Bridge = s.bridge
 	}
 
 	srv, err := jsonrpc.NewJSONRPC(s.logger, conf)
@@ -464,20 +1065,101 @@ func (s *Server) Chain() *chain.Chain {
 	return s.chain
 }
 
+// RegisterAppTopic registers a gossip topic of the application embedding the node,
+// see network.AppTopicConfig
+func (s *Server) RegisterAppTopic(config *network.AppTopicConfig) (*network.Topic, error) {
+	return s.network.RegisterAppTopic(config)
+}
+
+// EventBus returns the bus of the new heads, finalized blocks, reorgs and pending transactions,
+// for the plugins to subscribe to
+func (s *Server) EventBus() *eventbus.Bus {
+	return s.eventBus
+}
+
 func (s *Server) Join(addr0 string, dur time.Duration) error {
 	return s.network.JoinAddr(addr0, dur)
 }
 
-// Close closes the Minimal server (blockchain, networking, consensus)
+// ShutdownCh returns the channel closed when a shutdown of the client is requested
+func (s *Server) ShutdownCh() <-chan struct{} {
+	return s.shutdownCh
+}
+
+// stopGRPC stops the gRPC server, the calls still running after grpcStopTimeout are canceled
+func (s *Server) stopGRPC() {
+	stoppedCh := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stoppedCh)
+	}()
+
+	select {
+	case <-stoppedCh:
+	case <-time.After(grpcStopTimeout):
+		s.grpcServer.Stop()
+	}
+}
+
+// requestShutdown notifies the owner of the server that it should be closed
+func (s *Server) requestShutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+}
+
+// grpcStopTimeout is the time the in-flight gRPC calls have to complete once the server is closed
+const grpcStopTimeout = 2 * time.Second
+
+// Close gracefully stops the Minimal server. It stops accepting RPC requests, lets the consensus
+// complete its round, flushes the txpool journal, and closes the networking, the blockchain and
+// the data stores last, so a restart resumes from a consistent state
 func (s *Server) Close() {
-	// Close the blockchain layer
-	if err := s.blockchain.Close(); err != nil {
-		s.logger.Error("failed to close blockchain", "err", err.Error())
+	// Stop the health endpoints first, the node stops serving
+	if s.healthServer != nil {
+		if err := s.healthServer.Shutdown(context.Background()); err != nil {
+			s.logger.Error("Health server shutdown error", "err", err)
+		}
 	}
 
-	// Close the networking layer
-	if err := s.network.Close(); err != nil {
-		s.logger.Error("failed to close networking", "err", err.Error())
+	// Stop the reports of the stats
+	if s.checkpoint != nil {
+		s.checkpoint.Close()
+	}
+
+	if s.ethstats != nil {
+		s.ethstats.Close()
+	}
+
+	// Stop the exports of the blocks
+	if s.exporter != nil {
+		if err := s.exporter.Close(); err != nil {
+			s.logger.Error("failed to close the exporter", "err", err.Error())
+		}
+	}
+
+	// Stop relaying the deposits of the root chain
+	if s.bridge != nil {
+		if err := s.bridge.Close(); err != nil {
+			s.logger.Error("failed to close the bridge", "err", err.Error())
+		}
+	}
+
+	// Stop accepting RPC requests
+	if s.jsonrpcServer != nil {
+		if err := s.jsonrpcServer.Close(); err != nil {
+			s.logger.Error("failed to close the JSON-RPC server", "err", err.Error())
+		}
+	}
+	s.stopGRPC()
+
+	// Let the consensus complete its round, its messages are still gossiped
+	if stopper, ok := s.consensus.(consensus.Stopper); ok {
+		ctx, cancelFn := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+		if err := stopper.Stop(ctx); err != nil {
+			s.logger.Error("the consensus round didn't complete before the shutdown timeout", "err", err.Error())
+		}
+		cancelFn()
 	}
 
 	// Close the consensus layer
@@ -485,16 +1167,68 @@ func (s *Server) Close() {
 		s.logger.Error("failed to close consensus", "err", err.Error())
 	}
 
+	// Stop the txpool maintenance, and flush its journal
+	s.txpool.Close()
+
+	// Close the networking layer
+	if err := s.network.Close(); err != nil {
+		s.logger.Error("failed to close networking", "err", err.Error())
+	}
+	if s.consensusNetwork != nil {
+		if err := s.consensusNetwork.Close(); err != nil {
+			s.logger.Error("failed to close the consensus network", "err", err.Error())
+		}
+	}
+
+	// Close the blockchain layer, once nothing writes blocks anymore
+	if err := s.blockchain.Close(); err != nil {
+		s.logger.Error("failed to close blockchain", "err", err.Error())
+	}
+
+	// End the subscriptions to the events, the chain and the pool are stopped
+	s.eventBus.Close()
+
+	// Stop the report of the database statistics before the databases are closed
+	if s.stopDatabaseStats != nil {
+		s.stopDatabaseStats()
+	}
+
+	// Stop the pruning, the state storage closes the running one
+	if s.stopPruning != nil {
+		s.stopPruning()
+	}
+
 	// Close the state storage
 	if err := s.stateStorage.Close(); err != nil {
 		s.logger.Error("failed to close storage for trie", "err", err.Error())
 	}
 
+	// Close the archive storage
+	if s.archiveStorage != nil {
+		if err := s.archiveStorage.Close(); err != nil {
+			s.logger.Error("failed to close storage for archived accounts", "err", err.Error())
+		}
+	}
+
+	// Close the cache of the forked state
+	if s.remote != nil {
+		if err := s.remote.Close(); err != nil {
+			s.logger.Error("failed to close the cache of the forked state", "err", err.Error())
+		}
+	}
+
 	if s.prometheusServer != nil {
 		if err := s.prometheusServer.Shutdown(context.Background()); err != nil {
 			s.logger.Error("Prometheus server shutdown error", err)
 		}
 	}
+
+	// Export the spans of the closed subsystems
+	if s.stopTracing != nil {
+		if err := s.stopTracing(); err != nil {
+			s.logger.Error("failed to flush the traces", "err", err.Error())
+		}
+	}
 }
 
 // Entry is a backend configuration entry
@@ -1,27 +1,55 @@
 package server
 
 import (
+	"github.com/0xPolygon/polygon-sdk/blockchain"
 	"github.com/0xPolygon/polygon-sdk/consensus"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/protocol"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
 	"github.com/0xPolygon/polygon-sdk/txpool"
 )
 
 // serverMetrics holds the metric instances of all sub systems
 type serverMetrics struct {
-	consensus *consensus.Metrics
-	txpool    *txpool.Metrics
+	consensus  *consensus.Metrics
+	txpool     *txpool.Metrics
+	state      *state.Metrics
+	protocol   *protocol.Metrics
+	jsonrpc    *jsonrpc.Metrics
+	evm        *evm.Metrics
+	network    *network.Metrics
+	blockchain *blockchain.Metrics
+	kvdb       *kvdb.Metrics
 }
 
 // metricProvider serverMetric instance for the given ChainID and nameSpace
 func metricProvider(nameSpace string, chainID string, metricsRequired bool) *serverMetrics {
 	if metricsRequired {
 		return &serverMetrics{
-			consensus: consensus.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
-			txpool:    txpool.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			consensus:  consensus.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			txpool:     txpool.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			state:      state.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			protocol:   protocol.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			jsonrpc:    jsonrpc.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			evm:        evm.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			network:    network.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			blockchain: blockchain.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			kvdb:       kvdb.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 		}
 	}
 	return &serverMetrics{
-		consensus: consensus.NilMetrics(),
-		txpool:    txpool.NilMetrics(),
+		consensus:  consensus.NilMetrics(),
+		txpool:     txpool.NilMetrics(),
+		state:      state.NilMetrics(),
+		protocol:   protocol.NilMetrics(),
+		jsonrpc:    jsonrpc.NilMetrics(),
+		evm:        evm.NilMetrics(),
+		network:    network.NilMetrics(),
+		blockchain: blockchain.NilMetrics(),
+		kvdb:       kvdb.NilMetrics(),
 	}
 
 }
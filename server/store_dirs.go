@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+)
+
+// setupStoreDirs creates the data directory and the directories of the stores placed outside of it,
+// and records them for the commands reading the data directory. The config alone doesn't move a store,
+// the node refuses to start from an empty directory while the store is still in its previous one
+func setupStoreDirs(dataDir string, dirs common.StoreDirs) error {
+	prevDirs, err := common.ReadStoreDirs(dataDir)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]struct{}{}
+	for name := range prevDirs {
+		names[name] = struct{}{}
+	}
+	for name := range dirs {
+		names[name] = struct{}{}
+	}
+
+	for name := range names {
+		prev, dir := prevDirs.Dir(dataDir, name), dirs.Dir(dataDir, name)
+		if err := checkStoreMoved(prev, dir); err != nil {
+			return fmt.Errorf("the %s store was not moved: %v", name, err)
+		}
+	}
+
+	paths := []string{}
+	for _, path := range dirPaths {
+		if _, ok := dirs[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	if err := common.SetupDataDir(dataDir, paths); err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := common.SetupDataDir(dir, nil); err != nil {
+			return err
+		}
+	}
+
+	return common.WriteStoreDirs(dataDir, dirs)
+}
+
+// checkStoreMoved checks the store isn't left in its previous directory, while the new one is empty
+func checkStoreMoved(prev, dir string) error {
+	if filepath.Clean(prev) == filepath.Clean(dir) {
+		return nil
+	}
+
+	prevEmpty, err := isEmptyDir(prev)
+	if err != nil {
+		return err
+	}
+	empty, err := isEmptyDir(dir)
+	if err != nil {
+		return err
+	}
+
+	if !prevEmpty && empty {
+		return fmt.Errorf("%s is empty while the store is still in %s, move it first", dir, prev)
+	}
+
+	return nil
+}
+
+// isEmptyDir returns whether the directory is empty or missing
+func isEmptyDir(dir string) (bool, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return len(files) == 0, nil
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupStoreDirs(t *testing.T) {
+	root, err := ioutil.TempDir("", "store-dirs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	dataDir := filepath.Join(root, "data")
+	trieDir := filepath.Join(root, "nvme", "trie")
+
+	// a new data directory has its stores where they are configured
+	assert.NoError(t, setupStoreDirs(dataDir, common.StoreDirs{"trie": trieDir}))
+	assert.DirExists(t, trieDir)
+	assert.DirExists(t, filepath.Join(dataDir, "blockchain"))
+	assert.NoDirExists(t, filepath.Join(dataDir, "trie"))
+
+	dir, err := common.StoreDir(dataDir, "trie")
+	assert.NoError(t, err)
+	assert.Equal(t, trieDir, dir)
+
+	// the store is not moved back by the config alone
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(trieDir, "CURRENT"), []byte{}, 0600))
+	assert.Error(t, setupStoreDirs(dataDir, common.StoreDirs{}))
+
+	assert.NoError(t, os.Rename(trieDir, filepath.Join(dataDir, "trie")))
+	assert.NoError(t, setupStoreDirs(dataDir, common.StoreDirs{}))
+
+	// the record is removed once the stores are all in the data directory
+	_, err = os.Stat(filepath.Join(dataDir, common.StoreDirsFile))
+	assert.True(t, os.IsNotExist(err))
+
+	dir, err = common.StoreDir(dataDir, "trie")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "trie"), dir)
+}
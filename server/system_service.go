@@ -1,15 +1,29 @@
 package server
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
 	"time"
 
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/consensus"
+	"github.com/0xPolygon/polygon-sdk/helper/logging"
 	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/protocol"
 	"github.com/0xPolygon/polygon-sdk/server/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
+var errLogLevelsNotSet = errors.New("the client was not started with adjustable log levels")
+
 type systemService struct {
 	proto.UnimplementedSystemServer
 
@@ -33,7 +47,12 @@ func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto
 			Hash:   header.Hash.String(),
 		},
 		P2PAddr: network.AddrInfoToString(s.s.network.AddrInfo()),
+		Peers:   int64(len(s.s.network.Peers())),
+	}
+	if reporter, ok := s.s.consensus.(consensus.StatusReporter); ok {
+		status.HighestBlock = int64(reporter.Status().HighestBlock)
 	}
+
 	return status, nil
 }
 
@@ -74,6 +93,53 @@ func (s *systemService) Subscribe(req *empty.Empty, stream proto.System_Subscrib
 	return nil
 }
 
+// SyncProgress streams the progress of the syncs, the current one first, until the client closes the stream
+func (s *systemService) SyncProgress(req *empty.Empty, stream proto.System_SyncProgressServer) error {
+	ch, unsubscribe := s.s.syncTracker.Subscribe()
+	defer unsubscribe()
+
+	if err := stream.Send(toSyncProgressEvent(s.s.syncTracker.SyncProgress())); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case progress, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toSyncProgressEvent(progress)); err != nil {
+				return err
+			}
+
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// toSyncProgressEvent returns the event of the progress, not syncing if it is nil or done
+func toSyncProgressEvent(progress *protocol.SyncProgress) *proto.SyncProgressEvent {
+	if progress == nil {
+		return &proto.SyncProgressEvent{}
+	}
+
+	return &proto.SyncProgressEvent{
+		Syncing:           !progress.Done,
+		Stage:             string(progress.Stage),
+		StartingBlock:     progress.StartingBlock,
+		CurrentBlock:      progress.CurrentBlock,
+		HighestBlock:      progress.HighestBlock,
+		HeadersDownloaded: progress.HeadersDownloaded,
+		BlocksDownloaded:  progress.BlocksDownloaded,
+		BlocksExecuted:    progress.BlocksExecuted,
+		StateNodes:        progress.StateNodes,
+		StateCodes:        progress.StateCodes,
+		StatePending:      progress.StatePending,
+		EtaMs:             progress.ETA.Milliseconds(),
+	}
+}
+
 // PeersAdd implements the 'peers add' operator service
 func (s *systemService) PeersAdd(ctx context.Context, req *proto.PeersAddRequest) (*empty.Empty, error) {
 	dur := time.Duration(0)
@@ -103,21 +169,23 @@ func (s *systemService) PeersStatus(ctx context.Context, req *proto.PeersStatusR
 
 // getPeer returns a specific proto.Peer using the peer ID
 func (s *systemService) getPeer(id peer.ID) (*proto.Peer, error) {
-	protocols, err := s.s.network.GetProtocols(id)
+	status, err := s.s.network.PeerStatus(id)
 	if err != nil {
 		return nil, err
 	}
-	info := s.s.network.GetPeerInfo(id)
 
 	addrs := []string{}
-	for _, addr := range info.Addrs {
+	for _, addr := range status.Info.Addrs {
 		addrs = append(addrs, addr.String())
 	}
 
 	peer := &proto.Peer{
-		Id:        id.String(),
-		Protocols: protocols,
-		Addrs:     addrs,
+		Id:            id.String(),
+		Protocols:     status.Protocols,
+		Addrs:         addrs,
+		Direction:     status.Direction.String(),
+		Latency:       int64(status.Latency),
+		ClientVersion: status.ClientVersion,
 	}
 
 	return peer, nil
@@ -144,3 +212,297 @@ func (s *systemService) PeersList(
 
 	return resp, nil
 }
+
+// PeersGraph implements the 'peers graph' operator service
+func (s *systemService) PeersGraph(ctx context.Context, req *empty.Empty) (*proto.PeersGraphResponse, error) {
+	peers, err := s.PeersList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.PeersGraphResponse{
+		Id:    s.s.network.AddrInfo().ID.String(),
+		Peers: peers.Peers,
+		Links: []*proto.PeerLink{},
+	}
+
+	for from, reported := range s.s.network.Topology() {
+		for _, to := range reported {
+			resp.Links = append(resp.Links, &proto.PeerLink{
+				From: from.String(),
+				To:   to.String(),
+			})
+		}
+	}
+	sort.Slice(resp.Links, func(i, j int) bool {
+		if resp.Links[i].From != resp.Links[j].From {
+			return resp.Links[i].From < resp.Links[j].From
+		}
+
+		return resp.Links[i].To < resp.Links[j].To
+	})
+
+	return resp, nil
+}
+
+// PeersBandwidth implements the 'peers bandwidth' operator service
+func (s *systemService) PeersBandwidth(
+	ctx context.Context,
+	req *empty.Empty,
+) (*proto.PeersBandwidthResponse, error) {
+	totals := s.s.network.BandwidthTotals()
+	resp := &proto.PeersBandwidthResponse{
+		TotalIn:  uint64(totals.TotalIn),
+		TotalOut: uint64(totals.TotalOut),
+		Peers:    []*proto.PeerBandwidth{},
+	}
+
+	for _, p := range s.s.network.Bandwidth() {
+		peer := &proto.PeerBandwidth{
+			Id:        p.ID.String(),
+			TotalIn:   uint64(p.Total.In),
+			TotalOut:  uint64(p.Total.Out),
+			RateIn:    p.RateIn,
+			RateOut:   p.RateOut,
+			Protocols: []*proto.ProtocolBandwidth{},
+		}
+		for name, traffic := range p.Protocols {
+			peer.Protocols = append(peer.Protocols, &proto.ProtocolBandwidth{
+				Protocol: name,
+				In:       uint64(traffic.In),
+				Out:      uint64(traffic.Out),
+			})
+		}
+		sort.Slice(peer.Protocols, func(i, j int) bool {
+			return peer.Protocols[i].Out > peer.Protocols[j].Out
+		})
+
+		resp.Peers = append(resp.Peers, peer)
+	}
+
+	return resp, nil
+}
+
+// ReplayBlocks implements the 'chain replay' operator service
+func (s *systemService) ReplayBlocks(req *proto.ReplayBlocksRequest, stream proto.System_ReplayBlocksServer) error {
+	workers := int(req.Parallelism)
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var trace blockchain.ReplayTraceMode
+	switch req.Trace {
+	case "":
+		trace = blockchain.ReplayTraceNone
+	case "mismatches":
+		trace = blockchain.ReplayTraceMismatches
+	case "all":
+		trace = blockchain.ReplayTraceAll
+	default:
+		return fmt.Errorf("unknown trace mode %q, expected mismatches or all", req.Trace)
+	}
+
+	return s.s.blockchain.ReplayBlocks(
+		stream.Context(),
+		req.From,
+		req.To,
+		workers,
+		trace,
+		func(res *blockchain.ReplayResult) error {
+			result := &proto.ReplayBlockResult{
+				Number:     res.Number,
+				Hash:       res.Hash.String(),
+				Txns:       uint64(res.Txns),
+				GasUsed:    res.GasUsed,
+				DurationMs: res.Duration.Milliseconds(),
+			}
+			if res.Err != nil {
+				result.Error = res.Err.Error()
+			}
+			if res.TraceErr != nil {
+				result.TraceError = res.TraceErr.Error()
+			}
+			if res.Traces != nil {
+				traces, err := json.Marshal(res.Traces)
+				if err != nil {
+					return err
+				}
+				result.Traces = traces
+			}
+
+			return stream.Send(result)
+		},
+	)
+}
+
+// exportBatchSize is the size of the RLP encoded blocks sent at once by ExportBlocks
+const exportBatchSize = 1024 * 1024
+
+// ExportBlocks streams the canonical blocks of the range, encoded in RLP
+func (s *systemService) ExportBlocks(req *proto.ExportBlocksRequest, stream proto.System_ExportBlocksServer) error {
+	to := req.To
+	if to == 0 {
+		to = s.s.blockchain.Header().Number
+	}
+	if to < req.From {
+		return fmt.Errorf("the last block of the range must not be smaller than the first one")
+	}
+
+	batch := &proto.RLPBlocks{}
+	size := 0
+
+	for n := req.From; n <= to; n++ {
+		if err := stream.Context().Err(); err != nil {
+			return err
+		}
+
+		block, ok := s.s.blockchain.GetBlockByNumber(n, true)
+		if !ok {
+			return fmt.Errorf("block %d not found, the blocks synced without their bodies can't be exported", n)
+		}
+
+		blob := block.MarshalRLP()
+		batch.Blocks = append(batch.Blocks, blob)
+		size += len(blob)
+
+		if size >= exportBatchSize {
+			if err := stream.Send(batch); err != nil {
+				return err
+			}
+			batch = &proto.RLPBlocks{}
+			size = 0
+		}
+	}
+
+	if len(batch.Blocks) != 0 {
+		return stream.Send(batch)
+	}
+
+	return nil
+}
+
+// ImportBlocks verifies and writes the streamed blocks, the blocks already in the chain are skipped
+func (s *systemService) ImportBlocks(stream proto.System_ImportBlocksServer) error {
+	res := &proto.ImportBlocksResponse{}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		blocks := make([]*types.Block, 0, len(req.Blocks))
+		for _, blob := range req.Blocks {
+			block := &types.Block{}
+			if err := block.UnmarshalRLP(blob); err != nil {
+				return fmt.Errorf("failed to decode the block %d of the stream: %v", res.Imported+res.Skipped+uint64(len(blocks)), err)
+			}
+			blocks = append(blocks, block)
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		imported, err := s.s.blockchain.ImportBlocks(blocks)
+		if err != nil {
+			return fmt.Errorf("failed to import the blocks %d to %d: %v", blocks[0].Number(), blocks[len(blocks)-1].Number(), err)
+		}
+
+		res.Imported += uint64(imported)
+		res.Skipped += uint64(len(blocks) - imported)
+	}
+
+	header := s.s.blockchain.Header()
+	res.HeadNumber = header.Number
+	res.HeadHash = header.Hash.String()
+
+	return stream.SendAndClose(res)
+}
+
+// backupStreamWriter sends the written data as the chunks of a Backup stream
+type backupStreamWriter struct {
+	stream proto.System_BackupServer
+}
+
+func (w *backupStreamWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&proto.BackupChunk{Data: p}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Backup streams a consistent backup of the data stores and of the freezer
+func (s *systemService) Backup(req *empty.Empty, stream proto.System_BackupServer) error {
+	start := time.Now()
+
+	buf := bufio.NewWriterSize(&backupStreamWriter{stream: stream}, exportBatchSize)
+
+	metadata, err := s.s.writeBackup(buf)
+	if err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+
+	s.s.logger.Info(
+		"Backup streamed",
+		"head", metadata.HeadNumber,
+		"stores", metadata.Stores,
+		"frozen", metadata.Frozen,
+		"duration", time.Since(start),
+	)
+
+	return nil
+}
+
+// GetLogLevel returns the log levels of the client
+func (s *systemService) GetLogLevel(ctx context.Context, req *empty.Empty) (*proto.LogLevel, error) {
+	if s.s.config.LogLevels == nil {
+		return nil, errLogLevelsNotSet
+	}
+
+	return &proto.LogLevel{Spec: s.s.config.LogLevels.Spec().String()}, nil
+}
+
+// SetLogLevel replaces the log levels of the client, and returns the resulting ones
+func (s *systemService) SetLogLevel(ctx context.Context, req *proto.LogLevel) (*proto.LogLevel, error) {
+	if s.s.config.LogLevels == nil {
+		return nil, errLogLevelsNotSet
+	}
+
+	spec, err := logging.ParseLevelSpec(req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	s.s.config.LogLevels.Set(spec)
+
+	levels := s.s.config.LogLevels.Spec().String()
+	s.s.logger.Info("log levels changed over grpc", "levels", levels)
+
+	return &proto.LogLevel{Spec: levels}, nil
+}
+
+// Reload reads the config again, and applies the settings that can change without a restart
+func (s *systemService) Reload(ctx context.Context, req *empty.Empty) (*proto.ReloadResponse, error) {
+	s.s.logger.Info("config reload requested over grpc")
+
+	reloaded, err := s.s.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ReloadResponse{Reloaded: reloaded}, nil
+}
+
+// Shutdown requests a graceful shutdown of the client
+func (s *systemService) Shutdown(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	s.s.logger.Info("shutdown requested over grpc")
+	s.s.requestShutdown()
+
+	return &empty.Empty{}, nil
+}
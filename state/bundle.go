@@ -0,0 +1,165 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// BundleTxResult is the result of a transaction of a simulated bundle
+type BundleTxResult struct {
+	Txn     *types.Transaction
+	Receipt *types.Receipt
+
+	// ReturnValue is the output of the call, or the revert reason
+	ReturnValue []byte
+
+	// Err is the error of the failed execution, like a revert
+	Err error
+
+	// GasPrice is the price per gas paid by the sender
+	GasPrice *big.Int
+}
+
+// AccountState is the state of an account in a state diff, only the changed fields are set
+type AccountState struct {
+	Balance *big.Int
+	Nonce   *uint64
+	Code    []byte
+	Storage map[types.Hash]types.Hash
+}
+
+// StateDiff is the state changed by a bundle. Pre has the previous values of the changed fields and slots
+// of the accounts, and Post their new values. The created accounts are only in Post, and the deleted ones only in Pre
+type StateDiff struct {
+	Pre  map[types.Address]*AccountState
+	Post map[types.Address]*AccountState
+}
+
+// BundleResult is the result of a simulated bundle
+type BundleResult struct {
+	Txs       []*BundleTxResult
+	GasUsed   uint64
+	StateDiff *StateDiff
+
+	// CoinbaseDiff is the balance change of the coinbase, the fees and the direct payments of the bundle
+	CoinbaseDiff *big.Int
+}
+
+// SimulateBundle executes the transactions in order on top of the state of the root, in the context of the header,
+// and returns their results and the state they changed. The resulting state is discarded.
+// The bundle fails if a transaction can't be included in the block, the reverted transactions don't fail it
+func (e *Executor) SimulateBundle(
+	parentRoot types.Hash,
+	header *types.Header,
+	coinbase types.Address,
+	txs []*types.Transaction,
+) (*BundleResult, error) {
+	transition, err := e.beginTxn(parentRoot, header, coinbase, true)
+	if err != nil {
+		return nil, err
+	}
+	signer := crypto.NewSigner(transition.config, uint64(e.config.ChainID))
+
+	res := &BundleResult{Txs: []*BundleTxResult{}}
+	for i, txn := range txs {
+		if txn.From == emptyFrom {
+			if txn.From, err = signer.Sender(txn); err != nil {
+				return nil, fmt.Errorf("transaction %d (%s): %v", i, txn.Hash, err)
+			}
+		}
+
+		msg := txn.Copy()
+		result, err := transition.Apply(msg)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d (%s): %v", i, txn.Hash, err)
+		}
+		transition.totalGas += result.GasUsed
+		transition.addReceipt(txn, msg, result, transition.state.Logs())
+
+		res.Txs = append(res.Txs, &BundleTxResult{
+			Txn:         txn,
+			Receipt:     transition.receipts[i],
+			ReturnValue: result.ReturnValue,
+			Err:         result.Err,
+			GasPrice:    transition.gasPrice(msg),
+		})
+	}
+	res.GasUsed = transition.totalGas
+
+	pre, err := e.state.NewSnapshotAt(parentRoot)
+	if err != nil {
+		return nil, err
+	}
+	preTxn := NewTxn(e.state, pre)
+	preTxn.remote = e.remote
+
+	res.StateDiff = diffState(preTxn, transition.state)
+	res.CoinbaseDiff = new(big.Int).Sub(transition.state.GetBalance(coinbase), preTxn.GetBalance(coinbase))
+
+	return res, nil
+}
+
+// diffState returns the changes of the accounts and the slots written in the txn
+func diffState(pre, post *Txn) *StateDiff {
+	diff := &StateDiff{
+		Pre:  map[types.Address]*AccountState{},
+		Post: map[types.Address]*AccountState{},
+	}
+
+	// the witness is copied first, since the reads below are tracked too
+	accounts := map[types.Address]struct{}{}
+	for addr := range post.witness.modified {
+		accounts[addr] = struct{}{}
+	}
+	slots := map[types.Address][]types.Hash{}
+	for slot := range post.witness.writes {
+		accounts[slot.addr] = struct{}{}
+		slots[slot.addr] = append(slots[slot.addr], slot.key)
+	}
+
+	for addr := range accounts {
+		existed, exists := pre.Exist(addr), post.Exist(addr)
+		if !existed && !exists {
+			continue
+		}
+
+		before, after := &AccountState{}, &AccountState{}
+		changed := existed != exists
+
+		if balance := post.GetBalance(addr); changed || pre.GetBalance(addr).Cmp(balance) != 0 {
+			before.Balance, after.Balance = pre.GetBalance(addr), balance
+		}
+		if nonce, prevNonce := post.GetNonce(addr), pre.GetNonce(addr); changed || nonce != prevNonce {
+			before.Nonce, after.Nonce = &prevNonce, &nonce
+		}
+		if code, prevCode := post.GetCode(addr), pre.GetCode(addr); !bytes.Equal(code, prevCode) {
+			before.Code, after.Code = append([]byte{}, prevCode...), append([]byte{}, code...)
+		}
+		for _, key := range slots[addr] {
+			value, prevValue := post.GetState(addr, key), pre.GetState(addr, key)
+			if value == prevValue {
+				continue
+			}
+			if before.Storage == nil {
+				before.Storage, after.Storage = map[types.Hash]types.Hash{}, map[types.Hash]types.Hash{}
+			}
+			before.Storage[key], after.Storage[key] = prevValue, value
+		}
+
+		if !changed && before.Balance == nil && before.Nonce == nil && before.Code == nil && before.Storage == nil {
+			continue
+		}
+		if existed {
+			diff.Pre[addr] = before
+		}
+		if exists {
+			diff.Post[addr] = after
+		}
+	}
+
+	return diff
+}
@@ -4,20 +4,29 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/0xPolygon/polygon-sdk/chain"
 	"github.com/0xPolygon/polygon-sdk/crypto"
 	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/precompiled"
 	"github.com/0xPolygon/polygon-sdk/types"
 )
 
 const (
 	spuriousDragonMaxCodeSize = 24576
 
+	// witnessCacheSize is the number of recent blocks the witness stats are kept for
+	witnessCacheSize = 256
+
 	TxGas                 uint64 = 21000 // Per transaction not creating a contract
 	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract
+
+	TxAccessListAddressGas    uint64 = 2400 // Per address in the access list of a transaction
+	TxAccessListStorageKeyGas uint64 = 1900 // Per storage slot in the access list of a transaction
 )
 
 var emptyCodeHashTwo = types.BytesToHash(crypto.Keccak256(nil))
@@ -36,19 +45,47 @@ type Executor struct {
 	GetHash  GetHashByNumberHelper
 
 	PostHook func(txn *Transition)
+
+	metrics   *Metrics
+	witnesses *lru.Cache
+
+	// parallelism is the number of transactions of a block executed at once
+	parallelism int
+
+	// archive is the secondary store of the accounts archived by the state rent
+	archive ArchiveStore
+
+	// remote is the state of the chain forked from, set by the dev chains
+	remote RemoteState
 }
 
 // NewExecutor creates a new executor
 func NewExecutor(config *chain.Params, s State, logger hclog.Logger) *Executor {
+	witnesses, _ := lru.New(witnessCacheSize)
+
 	return &Executor{
-		logger:   logger,
-		config:   config,
-		runtimes: []runtime.Runtime{},
-		state:    s,
+		logger:    logger,
+		config:    config,
+		runtimes:  []runtime.Runtime{},
+		state:     s,
+		metrics:   NilMetrics(),
+		witnesses: witnesses,
 	}
 }
 
-func (e *Executor) WriteGenesis(alloc map[types.Address]*chain.GenesisAccount) types.Hash {
+// SetMetrics sets the metrics the executor reports to
+func (e *Executor) SetMetrics(metrics *Metrics) {
+	e.metrics = metrics
+}
+
+// SetParallelism sets the number of transactions of a block executed at once, 1 or less executes them one by one
+func (e *Executor) SetParallelism(parallelism int) {
+	e.parallelism = parallelism
+}
+
+// WriteGenesis writes the state of the genesis block, and returns its root.
+// The constructors of the accounts are run once all the accounts are set
+func (e *Executor) WriteGenesis(alloc map[types.Address]*chain.GenesisAccount) (types.Hash, error) {
 	snap := e.state.NewSnapshot()
 	txn := NewTxn(e.state, snap)
 
@@ -67,8 +104,12 @@ func (e *Executor) WriteGenesis(alloc map[types.Address]*chain.GenesisAccount) t
 		}
 	}
 
+	if err := e.runGenesisConstructors(txn, alloc); err != nil {
+		return types.Hash{}, err
+	}
+
 	_, root := txn.Commit(false)
-	return types.BytesToHash(root)
+	return types.BytesToHash(root), nil
 }
 
 // SetRuntime adds a runtime to the runtime set
@@ -80,36 +121,82 @@ type BlockResult struct {
 	Root     types.Hash
 	Receipts []*types.Receipt
 	TotalGas uint64
+	Witness  *WitnessStats
 }
 
 // ProcessBlock already does all the handling of the whole process, TODO
 func (e *Executor) ProcessBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) (*BlockResult, error) {
+	// The node count is shared with the other state readers (i.e. json-rpc calls),
+	// so the per block figure is an upper bound
+	nodesBefore := nodesLoaded(e.state)
+
 	txn, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
 	if err != nil {
 		return nil, err
 	}
 
 	txn.block = block
-	for _, t := range block.Transactions {
-		if err := txn.Write(t); err != nil {
+	txn.state.TrackWitness()
+	if txn.canWriteParallel(block.Transactions) {
+		if err := txn.writeParallel(block.Transactions, e.parallelism); err != nil {
 			return nil, err
 		}
+	} else {
+		for _, t := range block.Transactions {
+			if err := txn.Write(t); err != nil {
+				return nil, err
+			}
+		}
 	}
 	_, root := txn.Commit()
 
+	witness := txn.state.WitnessStats()
+	witness.TrieNodes = nodesLoaded(e.state) - nodesBefore
+	e.recordWitness(block.Hash(), witness)
+
 	res := &BlockResult{
 		Root:     root,
 		Receipts: txn.Receipts(),
 		TotalGas: txn.TotalGas(),
+		Witness:  witness,
 	}
 	return res, nil
 }
 
+// recordWitness reports the witness stats of a processed block,
+// and keeps them for the recent blocks
+func (e *Executor) recordWitness(hash types.Hash, witness *WitnessStats) {
+	e.witnesses.Add(hash, witness)
+
+	e.metrics.WitnessAccounts.Set(float64(witness.Accounts))
+	e.metrics.WitnessStorageReads.Set(float64(witness.StorageReads))
+	e.metrics.WitnessStorageWrites.Set(float64(witness.StorageWrites))
+	e.metrics.WitnessTrieNodes.Set(float64(witness.TrieNodes))
+	e.metrics.WitnessCodeBytes.Set(float64(witness.CodeBytes))
+}
+
+// GetWitness returns the witness stats of a recently processed block
+func (e *Executor) GetWitness(hash types.Hash) (*WitnessStats, bool) {
+	witness, ok := e.witnesses.Get(hash)
+	if !ok {
+		return nil, false
+	}
+	return witness.(*WitnessStats), true
+}
+
 // StateAt returns snapshot at given root
 func (e *Executor) State() State {
 	return e.state
 }
 
+// WithState returns a copy of the executor running on top of the state, sharing its runtimes and hooks
+func (e *Executor) WithState(s State) *Executor {
+	copied := *e
+	copied.state = s
+
+	return &copied
+}
+
 // StateAt returns snapshot at given root
 func (e *Executor) StateAt(root types.Hash) (Snapshot, error) {
 	return e.state.NewSnapshotAt(root)
@@ -121,6 +208,17 @@ func (e *Executor) GetForksInTime(blockNumber uint64) chain.ForksInTime {
 }
 
 func (e *Executor) BeginTxn(parentRoot types.Hash, header *types.Header, coinbaseReceiver types.Address) (*Transition, error) {
+	return e.beginTxn(parentRoot, header, coinbaseReceiver, false)
+}
+
+// beginTxn starts the transition of the block, tracking the state it accesses from
+// the system operations before the transactions, if set
+func (e *Executor) beginTxn(
+	parentRoot types.Hash,
+	header *types.Header,
+	coinbaseReceiver types.Address,
+	trackWitness bool,
+) (*Transition, error) {
 	config := e.config.Forks.At(header.Number)
 
 	auxSnap2, err := e.state.NewSnapshotAt(parentRoot)
@@ -129,6 +227,10 @@ func (e *Executor) BeginTxn(parentRoot types.Hash, header *types.Header, coinbas
 	}
 
 	newTxn := NewTxn(e.state, auxSnap2)
+	newTxn.remote = e.remote
+	if trackWitness {
+		newTxn.TrackWitness()
+	}
 
 	env2 := runtime.TxContext{
 		Coinbase:   coinbaseReceiver,
@@ -138,6 +240,9 @@ func (e *Executor) BeginTxn(parentRoot types.Hash, header *types.Header, coinbas
 		GasLimit:   int64(header.GasLimit),
 		ChainID:    int64(e.config.ChainID),
 	}
+	if config.London && header.BaseFee != nil {
+		env2.BaseFee = types.BytesToHash(header.BaseFee.Bytes())
+	}
 
 	txn := &Transition{
 		logger:   e.logger,
@@ -148,10 +253,18 @@ func (e *Executor) BeginTxn(parentRoot types.Hash, header *types.Header, coinbas
 		auxState: e.state,
 		config:   config,
 		gasPool:  uint64(env2.GasLimit),
+		baseFee:  header.BaseFee,
 
 		receipts: []*types.Receipt{},
 		totalGas: 0,
 	}
+
+	// the inactive accounts are archived before the transactions of the block
+	txn.archiveInactiveAccounts()
+
+	// and the system contracts are upgraded
+	txn.upgradeContracts()
+
 	return txn, nil
 }
 
@@ -171,9 +284,24 @@ type Transition struct {
 	ctx     runtime.TxContext
 	gasPool uint64
 
+	// baseFee is the base fee per gas of the block after the London fork, burnt by every transaction
+	baseFee *big.Int
+
+	// noBaseFee lets the messages without fees run below the base fee, for the calls outside of a block
+	noBaseFee bool
+
 	// result
 	receipts []*types.Receipt
 	totalGas uint64
+
+	// tracer records the frames of the transactions, if set
+	tracer *CallTracer
+
+	// deferredFee receives the fee of the coinbase instead of its balance, if set
+	deferredFee *big.Int
+
+	// interrupted stops the running execution once set (atomic)
+	interrupted uint32
 }
 
 func (t *Transition) TotalGas() uint64 {
@@ -191,7 +319,10 @@ func (t *Transition) Write(txn *types.Transaction) error {
 	signer := crypto.NewSigner(t.config, uint64(t.r.config.ChainID))
 
 	var err error
-	if txn.From == emptyFrom {
+	if txn.IsSystemTx() {
+		// the system transactions have no signature
+		txn.From = types.SystemAddress
+	} else if txn.From == emptyFrom {
 		// Decrypt the from address
 		txn.From, err = signer.Sender(txn)
 		if err != nil {
@@ -209,8 +340,13 @@ func (t *Transition) Write(txn *types.Transaction) error {
 	}
 	t.totalGas += result.GasUsed
 
-	logs := t.state.Logs()
+	t.addReceipt(txn, msg, result, t.state.Logs())
+
+	return nil
+}
 
+// addReceipt finalizes the state changes of the applied transaction, and adds its receipt
+func (t *Transition) addReceipt(txn, msg *types.Transaction, result *runtime.ExecutionResult, logs []*types.Log) {
 	var root []byte
 
 	receipt := &types.Receipt{
@@ -230,12 +366,20 @@ func (t *Transition) Write(txn *types.Transaction) error {
 		}
 
 	} else {
+		t.trackActivity()
 		ss, aux := t.state.Commit(t.config.EIP155)
+		remote, witness := t.state.remote, t.state.witness
 		t.state = NewTxn(t.auxState, ss)
+		t.state.remote, t.state.witness = remote, witness
 		root = aux
 		receipt.Root = types.BytesToHash(root)
 	}
 
+	// keep the revert reason, so that it is not needed to replay the transaction to read it
+	if result.Reverted() && len(result.ReturnValue) != 0 {
+		receipt.RevertData = append([]byte{}, result.ReturnValue...)
+	}
+
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To == nil {
 		receipt.ContractAddress = crypto.CreateAddress(msg.From, txn.Nonce)
@@ -245,17 +389,22 @@ func (t *Transition) Write(txn *types.Transaction) error {
 	receipt.Logs = logs
 	receipt.LogsBloom = types.CreateBloom([]*types.Receipt{receipt})
 	t.receipts = append(t.receipts, receipt)
-
-	return nil
 }
 
 // Commit commits the final result
 func (t *Transition) Commit() (Snapshot, types.Hash) {
+	t.finalize()
 	s2, root := t.state.Commit(t.config.EIP155)
 
 	return s2, types.BytesToHash(root)
 }
 
+// finalize applies the system operations after the transactions of the block
+func (t *Transition) finalize() {
+	t.mintBlockReward()
+	t.trackActivity()
+}
+
 func (t *Transition) subGasPool(amount uint64) error {
 	if t.gasPool < amount {
 		return ErrBlockLimitReached
@@ -295,15 +444,67 @@ func (t *Transition) Apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	return result, err
 }
 
+// SetNoBaseFee lets the messages with a zero fee cap and tip run below the base fee of the block,
+// used by the calls that are not included in a block, like eth_call
+func (t *Transition) SetNoBaseFee(noBaseFee bool) {
+	t.noBaseFee = noBaseFee
+}
+
 // ContextPtr returns reference of context
 // This method is called only by test
 func (t *Transition) ContextPtr() *runtime.TxContext {
 	return &t.ctx
 }
 
+// gasPrice returns the price per gas paid by the sender of the message in the block
+func (t *Transition) gasPrice(msg *types.Transaction) *big.Int {
+	if t.skipBaseFee(msg) {
+		return new(big.Int)
+	}
+	return msg.EffectiveGasPrice(t.baseFee)
+}
+
+// skipBaseFee checks if the message is a call without fees that does not pay the base fee,
+// or a system transaction
+func (t *Transition) skipBaseFee(msg *types.Transaction) bool {
+	if msg.IsSystemTx() {
+		return true
+	}
+	return t.noBaseFee && msg.GasPrice.Sign() == 0 && msg.GetGasTipCap().Sign() == 0
+}
+
+// checkDynamicFees checks that the fees of the message cover the base fee of the block (EIP-1559)
+func (t *Transition) checkDynamicFees(msg *types.Transaction) error {
+	if msg.Type == types.DynamicFeeTx && !t.config.London {
+		return ErrTxTypeNotSupported
+	}
+	if msg.Type == types.AccessListTx && !t.config.Berlin {
+		return ErrTxTypeNotSupported
+	}
+
+	if t.baseFee == nil || t.skipBaseFee(msg) {
+		return nil
+	}
+
+	if msg.GetGasTipCap().Cmp(msg.GasPrice) > 0 {
+		return ErrTipAboveFeeCap
+	}
+	if msg.GasPrice.Cmp(t.baseFee) < 0 {
+		return ErrFeeCapTooLow
+	}
+
+	// the sender must afford the fee cap, even if it pays less
+	maxGasCost := new(big.Int).Mul(msg.GasPrice, new(big.Int).SetUint64(msg.Gas))
+	if t.state.GetBalance(msg.From).Cmp(maxGasCost) < 0 {
+		return ErrNotEnoughFundsForGas
+	}
+
+	return nil
+}
+
 func (t *Transition) subGasLimitPrice(msg *types.Transaction) error {
 	// deduct the upfront max gas cost
-	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
+	upfrontGasCost := t.gasPrice(msg)
 	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
 
 	if err := t.state.SubBalance(msg.From, upfrontGasCost); err != nil {
@@ -337,6 +538,9 @@ var (
 	ErrIntrinsicGasOverflow  = fmt.Errorf("overflow in intrinsic gas calculation")
 	ErrNotEnoughIntrinsicGas = fmt.Errorf("not enough gas supplied for intrinsic gas costs")
 	ErrNotEnoughFunds        = fmt.Errorf("not enough funds for transfer with given value")
+	ErrTxTypeNotSupported    = fmt.Errorf("transaction type not supported")
+	ErrTipAboveFeeCap        = fmt.Errorf("max priority fee per gas higher than max fee per gas")
+	ErrFeeCapTooLow          = fmt.Errorf("max fee per gas less than block base fee")
 )
 
 type TransitionApplicationError struct {
@@ -370,11 +574,12 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	// applying the message. The rules include these clauses
 	//
 	// 1. the nonce of the message caller is correct
-	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
-	// 3. the amount of gas required is available in the block
-	// 4. there is no overflow when calculating intrinsic gas
-	// 5. the purchased gas is enough to cover intrinsic usage
-	// 6. caller has enough balance to cover asset transfer for **topmost** call
+	// 2. the fees of the message cover the base fee of the block (EIP-1559)
+	// 3. caller has enough balance to cover transaction fee(gaslimit * gasprice)
+	// 4. the amount of gas required is available in the block
+	// 5. there is no overflow when calculating intrinsic gas
+	// 6. the purchased gas is enough to cover intrinsic usage
+	// 7. caller has enough balance to cover asset transfer for **topmost** call
 
 	txn := t.state
 
@@ -383,59 +588,86 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
-	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
+	// 2. the fees of the message cover the base fee of the block, the fee cap of a
+	// message below the base fee may be enough for a later block
+	if err := t.checkDynamicFees(msg); err != nil {
+		return nil, NewTransitionApplicationError(err, err == ErrFeeCapTooLow)
+	}
+
+	// 3. caller has enough balance to cover transaction fee(gaslimit * gasprice)
 	if err := t.subGasLimitPrice(msg); err != nil {
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
-	// 3. the amount of gas required is available in the block
+	// 4. the amount of gas required is available in the block
 	if err := t.subGasPool(msg.Gas); err != nil {
 		return nil, NewGasLimitReachedTransitionApplicationError(err)
 	}
 
-	// 4. there is no overflow when calculating intrinsic gas
+	// 5. there is no overflow when calculating intrinsic gas
 	intrinsicGasCost, err := TransactionGasCost(msg, t.config.Homestead, t.config.Istanbul)
 	if err != nil {
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
-	// 5. the purchased gas is enough to cover intrinsic usage
+	// 6. the purchased gas is enough to cover intrinsic usage
 	gasLeft := msg.Gas - intrinsicGasCost
 	// Because we are working with unsigned integers for gas, the `>` operator is used instead of the more intuitive `<`
 	if gasLeft > msg.Gas {
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
-	// 6. caller has enough balance to cover asset transfer for **topmost** call
+	// 7. caller has enough balance to cover asset transfer for **topmost** call
 	if balance := txn.GetBalance(msg.From); balance.Cmp(msg.Value) < 0 {
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
-	gasPrice := new(big.Int).Set(msg.GasPrice)
+	gasPrice := t.gasPrice(msg)
 	value := new(big.Int).Set(msg.Value)
 
 	// Set the specific transaction fields in the context
 	t.ctx.GasPrice = types.BytesToHash(gasPrice.Bytes())
 	t.ctx.Origin = msg.From
 
+	if t.config.Berlin {
+		t.prepareAccessList(msg)
+	}
+
 	var result *runtime.ExecutionResult = nil
 	if msg.IsContractCreation() {
 		result = t.Create2(msg.From, msg.Input, value, gasLeft)
 	} else {
 		txn.IncrNonce(msg.From)
-		result = t.Call2(msg.From, *msg.To, msg.Input, value, gasLeft)
+		if _, ok := t.inactivityPeriod(); ok && *msg.To == StateRentAddress {
+			result = t.restoreAccount(msg.Input, value, gasLeft)
+		} else {
+			result = t.Call2(msg.From, *msg.To, msg.Input, value, gasLeft)
+		}
 	}
 
 	refund := txn.GetRefund()
-	result.UpdateGasUsed(msg.Gas, refund)
+	refundQuotient := runtime.RefundQuotient
+	if t.config.London {
+		// eip-3529
+		refundQuotient = runtime.RefundQuotientEIP3529
+	}
+	result.UpdateGasUsed(msg.Gas, refund, refundQuotient)
 
 	// refund the sender
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
 	txn.AddBalance(msg.From, remaining)
 
-	// pay the coinbase
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
-	txn.AddBalance(t.ctx.Coinbase, coinbaseFee)
+	// pay the coinbase, the base fee is burnt
+	tip := new(big.Int).Set(gasPrice)
+	if t.baseFee != nil && !t.skipBaseFee(msg) {
+		tip.Sub(tip, t.baseFee)
+	}
+	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), tip)
+	if t.deferredFee != nil {
+		t.deferredFee.Set(coinbaseFee)
+	} else {
+		t.payFee(coinbaseFee)
+	}
 
 	// return gas to the pool
 	t.addGasPool(result.GasLeft)
@@ -443,15 +675,68 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	return result, nil
 }
 
+// warmRuntime is implemented by the runtimes with contracts that are warm from the start of the transactions
+type warmRuntime interface {
+	WarmAddresses(number uint64) []types.Address
+}
+
+// warmAddresses returns the addresses of the contracts of the runtimes that are warm at the block
+func (e *Executor) warmAddresses(number uint64) []types.Address {
+	if e == nil {
+		return nil
+	}
+
+	addrs := []types.Address{}
+	for _, r := range e.runtimes {
+		if warm, ok := r.(warmRuntime); ok {
+			addrs = append(addrs, warm.WarmAddresses(number)...)
+		}
+	}
+	return addrs
+}
+
+// prepareAccessList warms the sender, the recipient, the precompiled contracts
+// and the access list of the message (EIP-2929, EIP-2930)
+func (t *Transition) prepareAccessList(msg *types.Transaction) {
+	t.state.ResetAccessList()
+
+	t.state.AccessAddress(msg.From)
+	if msg.To != nil {
+		t.state.AccessAddress(*msg.To)
+	}
+	for _, addr := range precompiled.ActiveAddresses(&t.config) {
+		t.state.AccessAddress(addr)
+	}
+	for _, addr := range t.r.warmAddresses(uint64(t.ctx.Number)) {
+		t.state.AccessAddress(addr)
+	}
+	for _, tuple := range msg.AccessList {
+		t.state.AccessAddress(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			t.state.AccessSlot(tuple.Address, key)
+		}
+	}
+}
+
 func (t *Transition) Create2(caller types.Address, code []byte, value *big.Int, gas uint64) *runtime.ExecutionResult {
 	address := crypto.CreateAddress(caller, t.state.GetNonce(caller))
 	contract := runtime.NewContractCreation(1, caller, caller, address, value, gas, code)
-	return t.applyCreate(contract, t)
+
+	t.traceContract(contract, true)
+	result := t.applyCreate(contract, t)
+	t.traceResult(result)
+
+	return result
 }
 
 func (t *Transition) Call2(caller types.Address, to types.Address, input []byte, value *big.Int, gas uint64) *runtime.ExecutionResult {
 	c := runtime.NewContractCall(1, caller, caller, to, value, gas, t.state.GetCode(to), input)
-	return t.applyCall(c, runtime.Call, t)
+
+	t.traceContract(c, false)
+	result := t.applyCall(c, runtime.Call, t)
+	t.traceResult(result)
+
+	return result
 }
 
 func (t *Transition) run(contract *runtime.Contract, host runtime.Host) *runtime.ExecutionResult {
@@ -546,6 +831,11 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		}
 	}
 
+	// The created address is warm, even if the creation fails
+	if t.config.Berlin {
+		t.state.AccessAddress(c.Address)
+	}
+
 	// Take snapshot of the current state
 	snapshot := t.state.Snapshot()
 
@@ -570,6 +860,15 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		return result
 	}
 
+	// The code starting with the 0xEF byte is rejected after the London fork (eip-3541)
+	if t.config.London && len(result.ReturnValue) > 0 && result.ReturnValue[0] == 0xEF {
+		t.state.RevertToSnapshot(snapshot)
+		return &runtime.ExecutionResult{
+			GasLeft: 0,
+			Err:     runtime.ErrInvalidCode,
+		}
+	}
+
 	if t.config.EIP158 && len(result.ReturnValue) > spuriousDragonMaxCodeSize {
 		// Contract size exceeds 'SpuriousDragon' size limit
 		t.state.RevertToSnapshot(snapshot)
@@ -604,10 +903,35 @@ func (t *Transition) SetStorage(addr types.Address, key types.Hash, value types.
 	return t.state.SetStorage(addr, key, value, config)
 }
 
+func (t *Transition) AccessAddress(addr types.Address) bool {
+	return t.state.AccessAddress(addr)
+}
+
+func (t *Transition) AccessSlot(addr types.Address, key types.Hash) bool {
+	return t.state.AccessSlot(addr, key)
+}
+
 func (t *Transition) GetTxContext() runtime.TxContext {
 	return t.ctx
 }
 
+// Interrupt stops the running execution of the transition, which fails with an interrupted error.
+// It is safe to call from another goroutine, like the timer of a call with a time limit
+func (t *Transition) Interrupt() {
+	atomic.StoreUint32(&t.interrupted, 1)
+}
+
+// InterruptFlag returns the flag the runtimes stop their executions at
+func (t *Transition) InterruptFlag() *uint32 {
+	return &t.interrupted
+}
+
+// Profiled returns whether the runtimes profile the executions of the transition,
+// only the blocks processed by the executor are, and not their traces
+func (t *Transition) Profiled() bool {
+	return t.block != nil && t.tracer == nil
+}
+
 func (t *Transition) GetBlockHash(number int64) (res types.Hash) {
 	return t.getHash(uint64(number))
 }
@@ -649,18 +973,27 @@ func (t *Transition) GetNonce(addr types.Address) uint64 {
 }
 
 func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address) {
-	if !t.state.HasSuicided(addr) {
+	// the refund is removed after the London fork (eip-3529)
+	if !t.config.London && !t.state.HasSuicided(addr) {
 		t.state.AddRefund(24000)
 	}
+	t.traceSelfdestruct(addr, beneficiary)
 	t.state.AddBalance(beneficiary, t.state.GetBalance(addr))
 	t.state.Suicide(addr)
 }
 
 func (t *Transition) Callx(c *runtime.Contract, h runtime.Host) *runtime.ExecutionResult {
+	t.traceContract(c, c.Type == runtime.Create)
+
+	var result *runtime.ExecutionResult
 	if c.Type == runtime.Create {
-		return t.applyCreate(c, h)
+		result = t.applyCreate(c, h)
+	} else {
+		result = t.applyCall(c, c.Type, h)
 	}
-	return t.applyCall(c, c.Type, h)
+
+	t.traceResult(result)
+	return result
 }
 
 func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (uint64, error) {
@@ -701,5 +1034,10 @@ func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (u
 		cost += zeros * 4
 	}
 
+	if len(msg.AccessList) > 0 {
+		cost += uint64(len(msg.AccessList)) * TxAccessListAddressGas
+		cost += uint64(msg.AccessList.StorageKeys()) * TxAccessListStorageKeyGas
+	}
+
 	return cost, nil
 }
\ No newline at end of file
@@ -0,0 +1,275 @@
+package fork
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/go-web3/jsonrpc"
+)
+
+const (
+	// remoteRetries is the number of times a failed request to the remote endpoint is sent
+	remoteRetries = 3
+
+	// remoteRetryDelay is the delay before a failed request is sent again
+	remoteRetryDelay = 500 * time.Millisecond
+)
+
+var (
+	// pinKey is the key of the number of the remote block the chain is forked from
+	pinKey = []byte("pin")
+
+	accountPrefix = []byte("a")
+	storagePrefix = []byte("s")
+	codePrefix    = []byte("c")
+)
+
+// Config is the remote chain a dev chain is forked from
+type Config struct {
+	// URL is the JSON-RPC endpoint of the remote chain
+	URL string
+
+	// Block is the number of the remote block the state is read at. The latest block if zero
+	Block uint64
+}
+
+// cachedAccount is a remote account in the cache, the missing accounts are cached too
+type cachedAccount struct {
+	Exists   bool
+	Nonce    uint64
+	Balance  *big.Int
+	CodeHash types.Hash
+}
+
+// Client reads the state of the remote chain at the pinned block from its JSON-RPC endpoint.
+// The accounts, the storage slots and the code are fetched once, and cached in the database
+type Client struct {
+	logger hclog.Logger
+	client *jsonrpc.Client
+	db     kvdb.Database
+	block  string
+}
+
+// NewClient creates the client of the remote chain, it takes ownership of the database of the cache.
+// The block is pinned in the database on the first run, so that the chain is forked from the same block once restarted
+func NewClient(logger hclog.Logger, config *Config, db kvdb.Database) (*Client, error) {
+	client, err := jsonrpc.NewClient(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the fork endpoint: %v", err)
+	}
+
+	c := &Client{
+		logger: logger.Named("fork"),
+		client: client,
+		db:     db,
+	}
+
+	block, err := c.pinBlock(config.Block)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	c.block = fmt.Sprintf("0x%x", block)
+
+	c.logger.Info("forked from the remote chain", "url", config.URL, "block", block)
+
+	return c, nil
+}
+
+// Close closes the connection to the remote endpoint and the cache
+func (c *Client) Close() error {
+	if err := c.client.Close(); err != nil {
+		c.logger.Error("failed to close the connection to the fork endpoint", "err", err)
+	}
+
+	return c.db.Close()
+}
+
+// pinBlock returns the number of the remote block the chain is forked from
+func (c *Client) pinBlock(block uint64) (uint64, error) {
+	data, ok, err := c.db.Get(pinKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if ok {
+		pinned := new(big.Int).SetBytes(data).Uint64()
+		if block != 0 && block != pinned {
+			return 0, fmt.Errorf("the chain is forked from the block %d, not %d", pinned, block)
+		}
+
+		return pinned, nil
+	}
+
+	if block == 0 {
+		var latest string
+		if err := c.call("eth_blockNumber", &latest); err != nil {
+			return 0, fmt.Errorf("failed to get the latest block of the fork endpoint: %v", err)
+		}
+		if block, err = types.ParseUint64orHex(&latest); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := c.db.Put(pinKey, new(big.Int).SetUint64(block).Bytes()); err != nil {
+		return 0, err
+	}
+
+	return block, nil
+}
+
+// call sends the request to the remote endpoint, again if it fails
+func (c *Client) call(method string, out interface{}, params ...interface{}) (err error) {
+	for i := 0; i < remoteRetries; i++ {
+		if i != 0 {
+			time.Sleep(remoteRetryDelay)
+		}
+		if err = c.client.Call(method, out, params...); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// callQuantity sends the request of a hex value to the remote endpoint at the pinned block
+func (c *Client) callQuantity(method string, params ...interface{}) ([]byte, error) {
+	var res string
+	if err := c.call(method, &res, append(params, c.block)...); err != nil {
+		return nil, err
+	}
+
+	// the quantities have no leading zeros
+	res = strings.TrimPrefix(res, "0x")
+	if len(res)%2 == 1 {
+		res = "0" + res
+	}
+
+	return hex.DecodeString(res)
+}
+
+// GetAccount returns the account at the pinned block, false if it doesn't exist.
+// The account is read as missing if the remote endpoint can't be reached
+func (c *Client) GetAccount(addr types.Address) (*state.RemoteAccount, bool) {
+	key := append(append([]byte{}, accountPrefix...), addr.Bytes()...)
+
+	var account cachedAccount
+	data, ok, err := c.db.Get(key)
+	if err == nil && ok {
+		err = json.Unmarshal(data, &account)
+	}
+	if err != nil {
+		c.logger.Error("failed to read the cached account", "addr", addr, "err", err)
+		return nil, false
+	}
+
+	if !ok {
+		if account, err = c.fetchAccount(addr); err != nil {
+			c.logger.Error("failed to fetch the account", "addr", addr, "err", err)
+			return nil, false
+		}
+		if data, err = json.Marshal(account); err == nil {
+			err = c.db.Put(key, data)
+		}
+		if err != nil {
+			c.logger.Error("failed to cache the account", "addr", addr, "err", err)
+		}
+	}
+
+	if !account.Exists {
+		return nil, false
+	}
+
+	remote := &state.RemoteAccount{
+		Nonce:   account.Nonce,
+		Balance: account.Balance,
+	}
+	if account.CodeHash != types.ZeroHash {
+		if remote.Code, ok = c.GetCode(account.CodeHash); !ok {
+			return nil, false
+		}
+	}
+
+	return remote, true
+}
+
+func (c *Client) fetchAccount(addr types.Address) (cachedAccount, error) {
+	account := cachedAccount{}
+
+	balance, err := c.callQuantity("eth_getBalance", addr.String())
+	if err != nil {
+		return account, err
+	}
+	nonce, err := c.callQuantity("eth_getTransactionCount", addr.String())
+	if err != nil {
+		return account, err
+	}
+	code, err := c.callQuantity("eth_getCode", addr.String())
+	if err != nil {
+		return account, err
+	}
+
+	account.Balance = new(big.Int).SetBytes(balance)
+	account.Nonce = new(big.Int).SetBytes(nonce).Uint64()
+	account.Exists = account.Nonce != 0 || account.Balance.Sign() != 0 || len(code) != 0
+
+	if len(code) != 0 {
+		account.CodeHash = types.BytesToHash(crypto.Keccak256(code))
+		if err := c.db.Put(append(append([]byte{}, codePrefix...), account.CodeHash.Bytes()...), code); err != nil {
+			return account, err
+		}
+	}
+
+	return account, nil
+}
+
+// GetCode returns the code of a remote account, by its hash
+func (c *Client) GetCode(hash types.Hash) ([]byte, bool) {
+	code, ok, err := c.db.Get(append(append([]byte{}, codePrefix...), hash.Bytes()...))
+	if err != nil {
+		c.logger.Error("failed to read the cached code", "hash", hash, "err", err)
+		return nil, false
+	}
+
+	return code, ok
+}
+
+// GetStorage returns the slot of the storage of the account at the pinned block.
+// The slot is read as zero if the remote endpoint can't be reached
+func (c *Client) GetStorage(addr types.Address, slot types.Hash) types.Hash {
+	key := append(append(append([]byte{}, storagePrefix...), addr.Bytes()...), slot.Bytes()...)
+
+	data, ok, err := c.db.Get(key)
+	if err != nil {
+		c.logger.Error("failed to read the cached slot", "addr", addr, "slot", slot, "err", err)
+		return types.Hash{}
+	}
+	if ok {
+		return types.BytesToHash(data)
+	}
+
+	if data, err = c.callQuantity("eth_getStorageAt", addr.String(), slot.String()); err != nil {
+		c.logger.Error("failed to fetch the slot", "addr", addr, "slot", slot, "err", err)
+		return types.Hash{}
+	}
+	if len(data) > types.HashLength {
+		c.logger.Error("the fetched slot is too long", "addr", addr, "slot", slot, "len", len(data))
+		return types.Hash{}
+	}
+
+	val := types.BytesToHash(data)
+	if err := c.db.Put(key, val.Bytes()); err != nil {
+		c.logger.Error("failed to cache the slot", "addr", addr, "slot", slot, "err", err)
+	}
+
+	return val
+}
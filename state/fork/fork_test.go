@@ -0,0 +1,125 @@
+package fork
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	remoteAddr = types.StringToAddress("1")
+	remoteSlot = types.StringToHash("1")
+)
+
+// mockRemote is a JSON-RPC endpoint with one account, it counts the requests
+type mockRemote struct {
+	lock     sync.Mutex
+	requests map[string]int
+	blocks   []string
+}
+
+func (m *mockRemote) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     interface{}   `json:"id"`
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m.lock.Lock()
+	m.requests[req.Method]++
+	if len(req.Params) != 0 {
+		m.blocks = append(m.blocks, req.Params[len(req.Params)-1].(string))
+	}
+	m.lock.Unlock()
+
+	existing := len(req.Params) != 0 && req.Params[0] == remoteAddr.String()
+
+	var result string
+	switch req.Method {
+	case "eth_blockNumber":
+		result = "0x64"
+	case "eth_getBalance":
+		result = "0x0"
+		if existing {
+			result = "0x3e8"
+		}
+	case "eth_getTransactionCount":
+		result = "0x0"
+		if existing {
+			result = "0x7"
+		}
+	case "eth_getCode":
+		result = "0x"
+		if existing {
+			result = "0x6000"
+		}
+	case "eth_getStorageAt":
+		result = "0x0"
+		if existing && req.Params[1] == remoteSlot.String() {
+			result = "0x2a"
+		}
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+		"result":  result,
+	})
+}
+
+func TestClient(t *testing.T) {
+	remote := &mockRemote{requests: map[string]int{}}
+	srv := httptest.NewServer(remote)
+	defer srv.Close()
+
+	db := kvdb.NewMemoryDatabase()
+	client, err := NewClient(hclog.NewNullLogger(), &Config{URL: srv.URL}, db)
+	assert.NoError(t, err)
+
+	// the latest block is pinned
+	assert.Equal(t, "0x64", client.block)
+
+	for i := 0; i < 2; i++ {
+		account, ok := client.GetAccount(remoteAddr)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(7), account.Nonce)
+		assert.Equal(t, big.NewInt(1000), account.Balance)
+		assert.Equal(t, []byte{0x60, 0x00}, account.Code)
+
+		_, ok = client.GetAccount(types.StringToAddress("2"))
+		assert.False(t, ok)
+
+		assert.Equal(t, types.BytesToHash([]byte{0x2a}), client.GetStorage(remoteAddr, remoteSlot))
+		assert.Equal(t, types.Hash{}, client.GetStorage(remoteAddr, types.StringToHash("2")))
+	}
+
+	// the accounts and the slots are fetched once, the missing ones too
+	assert.Equal(t, 2, remote.requests["eth_getBalance"])
+	assert.Equal(t, 2, remote.requests["eth_getStorageAt"])
+
+	// the state is read at the pinned block
+	for _, block := range remote.blocks {
+		assert.Equal(t, "0x64", block)
+	}
+
+	// the pinned block is kept once restarted
+	client, err = NewClient(hclog.NewNullLogger(), &Config{URL: srv.URL}, db)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x64", client.block)
+	assert.Equal(t, 1, remote.requests["eth_blockNumber"])
+
+	_, err = NewClient(hclog.NewNullLogger(), &Config{URL: srv.URL, Block: 50}, db)
+	assert.Error(t, err)
+}
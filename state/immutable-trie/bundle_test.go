@@ -0,0 +1,103 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateBundle(t *testing.T) {
+	var (
+		sender   = types.StringToAddress("1")
+		counter  = types.StringToAddress("2")
+		reverter = types.StringToAddress("3")
+		receiver = types.StringToAddress("4")
+		coinbase = types.StringToAddress("5")
+	)
+
+	st := NewState(NewMemoryStorage())
+
+	executor := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, st, hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.Hash{}
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1000000000)},
+		// the counter increments its first slot and emits a log
+		counter: {Code: hex.MustDecodeHex("0x60005460010160005560006000a000")},
+		// the reverter reverts every call
+		reverter: {Code: hex.MustDecodeHex("0x60006000fd")},
+	})
+	assert.NoError(t, err)
+
+	tx := func(nonce uint64, to types.Address, value int64) *types.Transaction {
+		return &types.Transaction{
+			From:     sender,
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(value),
+			Gas:      100000,
+			GasPrice: big.NewInt(1),
+		}
+	}
+	header := &types.Header{Number: 1, GasLimit: 1000000}
+
+	res, err := executor.SimulateBundle(root, header, coinbase, []*types.Transaction{
+		tx(0, counter, 0),
+		tx(1, reverter, 0),
+		tx(2, receiver, 100),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, res.Txs, 3)
+
+	// the reverted transaction is included
+	assert.Equal(t, types.ReceiptSuccess, *res.Txs[0].Receipt.Status)
+	assert.Len(t, res.Txs[0].Receipt.Logs, 1)
+	assert.Equal(t, types.ReceiptFailed, *res.Txs[1].Receipt.Status)
+	assert.Equal(t, types.ReceiptSuccess, *res.Txs[2].Receipt.Status)
+	assert.Equal(t, big.NewInt(1), res.Txs[0].GasPrice)
+
+	gasUsed := uint64(0)
+	for _, tx := range res.Txs {
+		gasUsed += tx.Receipt.GasUsed
+	}
+	assert.Equal(t, gasUsed, res.GasUsed)
+	assert.Equal(t, new(big.Int).SetUint64(gasUsed), res.CoinbaseDiff)
+
+	// the diff has the changed fields and slots, the receiver and the coinbase are created
+	diff := res.StateDiff
+	assert.Equal(t, uint64(0), *diff.Pre[sender].Nonce)
+	assert.Equal(t, uint64(3), *diff.Post[sender].Nonce)
+	assert.Equal(t, big.NewInt(1000000000-int64(gasUsed)-100), diff.Post[sender].Balance)
+
+	slot := types.Hash{}
+	assert.Equal(t, &state.AccountState{Storage: map[types.Hash]types.Hash{slot: {}}}, diff.Pre[counter])
+	assert.Equal(t, &state.AccountState{Storage: map[types.Hash]types.Hash{slot: types.BytesToHash([]byte{1})}}, diff.Post[counter])
+
+	assert.NotContains(t, diff.Pre, reverter)
+	assert.NotContains(t, diff.Post, reverter)
+
+	assert.NotContains(t, diff.Pre, receiver)
+	assert.Equal(t, big.NewInt(100), diff.Post[receiver].Balance)
+	assert.NotContains(t, diff.Pre, coinbase)
+
+	// the state is discarded
+	res, err = executor.SimulateBundle(root, header, coinbase, []*types.Transaction{tx(0, receiver, 1)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), *res.StateDiff.Post[sender].Nonce)
+
+	// a transaction which can't be included fails the bundle
+	_, err = executor.SimulateBundle(root, header, coinbase, []*types.Transaction{tx(0, counter, 0), tx(0, counter, 0)})
+	assert.Error(t, err)
+}
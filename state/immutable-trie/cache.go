@@ -0,0 +1,184 @@
+package itrie
+
+import (
+	"math"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// writtenCacheShare is the share of the cache budget kept for the recently written nodes
+const writtenCacheShare = 4
+
+// CachingStorage keeps the recently used trie nodes and codes in memory, within a budget in bytes.
+// The nodes read from the storage and the ones written by the latest blocks are kept apart,
+// so the commit of a block doesn't evict the nodes read by the next ones.
+// The writes go through to the storage, which always holds every node
+type CachingStorage struct {
+	Storage
+
+	clean   *bytesCache
+	written *bytesCache
+}
+
+// NewCachingStorage creates a cache of the storage within the budget, in bytes
+func NewCachingStorage(storage Storage, budget uint64) *CachingStorage {
+	return &CachingStorage{
+		Storage: storage,
+		clean:   newBytesCache(budget - budget/writtenCacheShare),
+		written: newBytesCache(budget / writtenCacheShare),
+	}
+}
+
+func (c *CachingStorage) get(k []byte, load func() ([]byte, bool)) ([]byte, bool) {
+	key := string(k)
+	if v, ok := c.written.get(key); ok {
+		return v, true
+	}
+	if v, ok := c.clean.get(key); ok {
+		return v, true
+	}
+
+	v, ok := load()
+	if ok {
+		c.clean.add(key, v)
+	}
+
+	return v, ok
+}
+
+func (c *CachingStorage) Get(k []byte) ([]byte, bool) {
+	return c.get(k, func() ([]byte, bool) {
+		return c.Storage.Get(k)
+	})
+}
+
+func (c *CachingStorage) Put(k, v []byte) {
+	c.Storage.Put(k, v)
+	c.written.add(string(k), v)
+}
+
+func (c *CachingStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	return c.get(append(codePrefix, hash.Bytes()...), func() ([]byte, bool) {
+		return c.Storage.GetCode(hash)
+	})
+}
+
+func (c *CachingStorage) SetCode(hash types.Hash, code []byte) {
+	c.Storage.SetCode(hash, code)
+	c.written.add(string(append(codePrefix, hash.Bytes()...)), code)
+}
+
+func (c *CachingStorage) Batch() Batch {
+	return &cachingBatch{Batch: c.Storage.Batch(), cache: c}
+}
+
+// ForEachNode lists the nodes of the storage, if it is prunable
+func (c *CachingStorage) ForEachNode(handler func(key []byte) bool) error {
+	prunable, ok := c.Storage.(PrunableStorage)
+	if !ok {
+		return ErrNotPrunable
+	}
+
+	return prunable.ForEachNode(handler)
+}
+
+// Delete removes the nodes from the storage, if it is prunable, and from the cache
+func (c *CachingStorage) Delete(keys [][]byte) error {
+	prunable, ok := c.Storage.(PrunableStorage)
+	if !ok {
+		return ErrNotPrunable
+	}
+
+	for _, k := range keys {
+		c.clean.remove(string(k))
+		c.written.remove(string(k))
+	}
+
+	return prunable.Delete(keys)
+}
+
+// Compact compacts the storage, if it is prunable
+func (c *CachingStorage) Compact() error {
+	prunable, ok := c.Storage.(PrunableStorage)
+	if !ok {
+		return ErrNotPrunable
+	}
+
+	return prunable.Compact()
+}
+
+type cachingBatch struct {
+	Batch
+	cache *CachingStorage
+}
+
+func (b *cachingBatch) Put(k, v []byte) {
+	b.Batch.Put(k, v)
+	b.cache.written.add(string(k), v)
+}
+
+// bytesCache is a LRU cache of values within a budget in bytes
+type bytesCache struct {
+	lock   sync.Mutex
+	lru    *simplelru.LRU
+	budget uint64
+	bytes  uint64
+}
+
+func newBytesCache(budget uint64) *bytesCache {
+	c := &bytesCache{budget: budget}
+
+	// the entries are bounded by the budget, not by their number
+	c.lru, _ = simplelru.NewLRU(math.MaxInt32, func(key, value interface{}) {
+		c.bytes -= entrySize(key.(string), value.([]byte))
+	})
+
+	return c
+}
+
+func entrySize(key string, value []byte) uint64 {
+	return uint64(len(key) + len(value))
+}
+
+func (c *bytesCache) get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return v.([]byte), true
+}
+
+func (c *bytesCache) add(key string, value []byte) {
+	size := entrySize(key, value)
+	if size > c.budget {
+		return
+	}
+
+	// the callers may reuse their buffers
+	value = append([]byte{}, value...)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lru.Remove(key)
+	c.lru.Add(key, value)
+	c.bytes += size
+
+	for c.bytes > c.budget {
+		c.lru.RemoveOldest()
+	}
+}
+
+func (c *bytesCache) remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lru.Remove(key)
+}
@@ -0,0 +1,79 @@
+package itrie
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPrunableStorage counts the reads of a prunable storage
+type countingPrunableStorage struct {
+	PrunableStorage
+	reads uint64
+}
+
+func (c *countingPrunableStorage) Get(k []byte) ([]byte, bool) {
+	c.reads++
+	return c.PrunableStorage.Get(k)
+}
+
+func TestCachingStorage(t *testing.T) {
+	counter := &countingPrunableStorage{PrunableStorage: NewMemoryStorage().(PrunableStorage)}
+	key := func(i byte) []byte {
+		return types.BytesToHash([]byte{i}).Bytes()
+	}
+
+	// room for two clean nodes of 32 + 32 bytes, and one written node
+	cache := NewCachingStorage(counter, 170)
+
+	for i := byte(1); i <= 3; i++ {
+		counter.PrunableStorage.Put(key(i), make([]byte, 32))
+	}
+
+	// the nodes are read once
+	for i := 0; i < 2; i++ {
+		_, ok := cache.Get(key(1))
+		assert.True(t, ok)
+		_, ok = cache.Get(key(2))
+		assert.True(t, ok)
+	}
+	assert.Equal(t, uint64(2), counter.reads)
+
+	// until they are evicted by the newer ones
+	cache.Get(key(3))
+	cache.Get(key(1))
+	assert.Equal(t, uint64(4), counter.reads)
+
+	// the written nodes are kept apart, and go through to the storage
+	batch := cache.Batch()
+	batch.Put(key(4), []byte{1})
+	batch.Write()
+
+	v, ok := cache.Get(key(4))
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1}, v)
+	assert.Equal(t, uint64(4), counter.reads)
+
+	v, ok = counter.PrunableStorage.Get(key(4))
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1}, v)
+
+	// the missing nodes are not cached
+	_, ok = cache.Get(key(5))
+	assert.False(t, ok)
+	cache.Put(key(5), []byte{2})
+	v, _ = cache.Get(key(5))
+	assert.Equal(t, []byte{2}, v)
+
+	// the deleted nodes are dropped
+	assert.NoError(t, cache.Delete([][]byte{key(5)}))
+	_, ok = cache.Get(key(5))
+	assert.False(t, ok)
+
+	// and so are the codes
+	cache.SetCode(types.StringToHash("1"), []byte{3})
+	code, ok := cache.GetCode(types.StringToHash("1"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte{3}, code)
+}
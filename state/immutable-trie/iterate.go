@@ -0,0 +1,91 @@
+package itrie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Iterate calls the handler with the keys and values of the trie in the order of the keys,
+// from the first key not lower than the start, until it returns false
+func (t *Trie) Iterate(start []byte, handler func(k, v []byte) bool) error {
+	startHex := keybytesToHex(start)
+	it := &trieIterator{
+		storage:  t.storage,
+		start:    start,
+		startHex: startHex[:len(startHex)-1],
+		handler:  handler,
+	}
+
+	_, err := it.iterate(t.root, nil)
+
+	return err
+}
+
+type trieIterator struct {
+	storage  Storage
+	start    []byte
+	startHex []byte
+	handler  func(k, v []byte) bool
+}
+
+// skip checks if every key under the path is lower than the start
+func (it *trieIterator) skip(path []byte) bool {
+	l := len(path)
+	if l > len(it.startHex) {
+		l = len(it.startHex)
+	}
+
+	return bytes.Compare(path[:l], it.startHex[:l]) < 0
+}
+
+// iterate walks the node under the path, and returns false once the handler stops the iteration
+func (it *trieIterator) iterate(node Node, path []byte) (bool, error) {
+	if it.skip(path) {
+		return true, nil
+	}
+
+	switch n := node.(type) {
+	case nil:
+		return true, nil
+
+	case *ValueNode:
+		if n.hash {
+			nc, ok, err := GetNode(n.buf, it.storage)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				return false, fmt.Errorf("trie node %x not found", n.buf)
+			}
+			return it.iterate(nc, path)
+		}
+
+		key := make([]byte, len(path)/2)
+		decodeNibbles(path, key)
+		if bytes.Compare(key, it.start) < 0 {
+			return true, nil
+		}
+		return it.handler(key, n.buf), nil
+
+	case *ShortNode:
+		key := n.key
+		if hasTerm(key) {
+			key = key[:len(key)-1]
+		}
+		return it.iterate(n.child, concat(path, key))
+
+	case *FullNode:
+		if ok, err := it.iterate(n.value, path); !ok || err != nil {
+			return ok, err
+		}
+		for i, child := range n.children {
+			if ok, err := it.iterate(child, concat(path, []byte{byte(i)})); !ok || err != nil {
+				return ok, err
+			}
+		}
+		return true, nil
+
+	default:
+		return false, fmt.Errorf("unknown node type %T", n)
+	}
+}
@@ -0,0 +1,93 @@
+package itrie
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// overlayStorage keeps its writes in memory, on top of a storage it reads the missing entries from.
+// It holds the speculative states that must not be persisted, which are discarded along with it
+type overlayStorage struct {
+	Storage
+
+	lock sync.RWMutex
+	kv   map[string][]byte
+	code map[types.Hash][]byte
+}
+
+// overlayBatch writes the entries to the overlay once written
+type overlayBatch struct {
+	storage *overlayStorage
+	kv      map[string][]byte
+}
+
+// NewOverlayStorage creates a storage reading the entries of the underlying storage,
+// whose writes are kept in memory and never reach the underlying storage
+func NewOverlayStorage(storage Storage) Storage {
+	return &overlayStorage{
+		Storage: storage,
+		kv:      map[string][]byte{},
+		code:    map[types.Hash][]byte{},
+	}
+}
+
+func (o *overlayStorage) Put(k, v []byte) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.kv[string(k)] = append([]byte{}, v...)
+}
+
+func (o *overlayStorage) Get(k []byte) ([]byte, bool) {
+	o.lock.RLock()
+	v, ok := o.kv[string(k)]
+	o.lock.RUnlock()
+
+	if ok {
+		return v, true
+	}
+
+	return o.Storage.Get(k)
+}
+
+func (o *overlayStorage) Batch() Batch {
+	return &overlayBatch{storage: o, kv: map[string][]byte{}}
+}
+
+func (o *overlayStorage) SetCode(hash types.Hash, code []byte) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.code[hash] = append([]byte{}, code...)
+}
+
+func (o *overlayStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	o.lock.RLock()
+	code, ok := o.code[hash]
+	o.lock.RUnlock()
+
+	if ok {
+		return code, true
+	}
+
+	return o.Storage.GetCode(hash)
+}
+
+// Close discards the overlay, the underlying storage is left open
+func (o *overlayStorage) Close() error {
+	return nil
+}
+
+func (b *overlayBatch) Put(k, v []byte) {
+	b.kv[string(k)] = append([]byte{}, v...)
+}
+
+func (b *overlayBatch) Write() {
+	b.storage.lock.Lock()
+	defer b.storage.lock.Unlock()
+
+	for k, v := range b.kv {
+		b.storage.kv[k] = v
+	}
+}
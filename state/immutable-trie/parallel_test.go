@@ -0,0 +1,119 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCounter is a counter the tests can read
+type testCounter struct {
+	value float64
+}
+
+func (c *testCounter) With(labelValues ...string) metrics.Counter {
+	return c
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.value += delta
+}
+
+func TestParallelExecution(t *testing.T) {
+	var (
+		sender1  = types.StringToAddress("1")
+		sender2  = types.StringToAddress("2")
+		sender3  = types.StringToAddress("3")
+		sender4  = types.StringToAddress("4")
+		counter  = types.StringToAddress("5")
+		coinbase = types.StringToAddress("6")
+	)
+
+	// the counter increments its first slot, and emits a log
+	counterCode := hex.MustDecodeHex("0x60005460010160005560006000a000")
+
+	st := NewState(NewMemoryStorage())
+
+	newExecutor := func(parallelism int) (*state.Executor, *state.Metrics) {
+		executor := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, st, hclog.NewNullLogger())
+		executor.SetRuntime(evm.NewEVM())
+		executor.GetHash = func(*types.Header) state.GetHashByNumber {
+			return func(uint64) types.Hash {
+				return types.Hash{}
+			}
+		}
+
+		metrics := state.NilMetrics()
+		metrics.ParallelTxs = &testCounter{}
+		metrics.ReexecutedTxs = &testCounter{}
+		executor.SetMetrics(metrics)
+		executor.SetParallelism(parallelism)
+
+		return executor, metrics
+	}
+
+	sequential, _ := newExecutor(1)
+	parallel, metrics := newExecutor(4)
+
+	root, err := sequential.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender1: {Balance: big.NewInt(1000000000)},
+		sender2: {Balance: big.NewInt(1000000000)},
+		sender3: {Balance: big.NewInt(1000000000)},
+		sender4: {Balance: big.NewInt(1000000000)},
+		counter: {Code: counterCode},
+	})
+	assert.NoError(t, err)
+
+	transfer := func(from types.Address, nonce uint64, to types.Address) *types.Transaction {
+		return &types.Transaction{
+			From:     from,
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(10),
+			Gas:      100000,
+			GasPrice: big.NewInt(1),
+		}
+	}
+
+	block := &types.Block{
+		Header: &types.Header{Number: 1, GasLimit: 1000000},
+		Transactions: []*types.Transaction{
+			transfer(sender1, 0, types.StringToAddress("10")),
+			transfer(sender2, 0, types.StringToAddress("11")),
+			// the same sender as the first transaction
+			transfer(sender1, 1, types.StringToAddress("12")),
+			transfer(sender3, 0, counter),
+			// the same contract as the previous transaction
+			transfer(sender4, 0, counter),
+			// pays the coinbase
+			transfer(sender2, 1, coinbase),
+		},
+	}
+
+	expected, err := sequential.ProcessBlock(root, block, coinbase)
+	assert.NoError(t, err)
+
+	res, err := parallel.ProcessBlock(root, block, coinbase)
+	assert.NoError(t, err)
+
+	assert.Equal(t, expected.Root, res.Root)
+	assert.Equal(t, expected.TotalGas, res.TotalGas)
+	assert.Equal(t, expected.Receipts, res.Receipts)
+	assert.Equal(t, expected.Witness.Accounts, res.Witness.Accounts)
+
+	assert.Equal(t, float64(3), metrics.ParallelTxs.(*testCounter).value)
+	assert.Equal(t, float64(3), metrics.ReexecutedTxs.(*testCounter).value)
+
+	// the counter was incremented twice
+	snap, err := st.NewSnapshotAt(res.Root)
+	assert.NoError(t, err)
+	assert.Equal(t, types.BytesToHash([]byte{2}), state.NewTxn(st, snap).GetState(counter, types.Hash{}))
+}
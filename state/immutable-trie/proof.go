@@ -0,0 +1,178 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	ErrMissingTrieNode = errors.New("trie node not found")
+	ErrInvalidProof    = errors.New("invalid merkle proof")
+)
+
+// Prove returns the merkle proof of the key in the trie with the given root: the encoded nodes
+// on the path from the root to the key, ending with the leaf of the key, or with the last node
+// of the path if the key is not in the trie. The key is the path in the trie, the hash of the
+// address or of the storage slot for the state tries
+func Prove(storage Storage, root types.Hash, key []byte) ([][]byte, error) {
+	proof := [][]byte{}
+	if root == types.EmptyRootHash {
+		return proof, nil
+	}
+
+	p := parserPool.Get()
+	defer parserPool.Put(p)
+
+	_, err := walkProof(root, key, func(hash []byte) (*fastrlp.Value, error) {
+		data, ok := storage.Get(hash)
+		if !ok {
+			return nil, ErrMissingTrieNode
+		}
+		proof = append(proof, data)
+
+		return p.Parse(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// VerifyProof checks the merkle proof of the key against the root, and returns the value of the key,
+// or nil if the proof shows the key is not in the trie
+func VerifyProof(root types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	if root == types.EmptyRootHash {
+		return nil, nil
+	}
+
+	nodes := map[types.Hash][]byte{}
+	for _, node := range proof {
+		nodes[types.BytesToHash(crypto.Keccak256(node))] = node
+	}
+
+	p := parserPool.Get()
+	defer parserPool.Put(p)
+
+	return walkProof(root, key, func(hash []byte) (*fastrlp.Value, error) {
+		data, ok := nodes[types.BytesToHash(hash)]
+		if !ok {
+			return nil, ErrInvalidProof
+		}
+
+		return p.Parse(data)
+	})
+}
+
+// walkProof walks the path of the key from the root, resolving the nodes referenced by their hash
+// with the resolver, and returns the value at the end of the path, if any
+func walkProof(root types.Hash, key []byte, resolve func(hash []byte) (*fastrlp.Value, error)) ([]byte, error) {
+	v, err := resolve(root.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	path := keybytesToHex(key)
+	for {
+		var child *fastrlp.Value
+
+		switch v.Elems() {
+		case 2:
+			k := v.Get(0)
+			if k.Type() != fastrlp.TypeBytes {
+				return nil, fmt.Errorf("short key expected to be bytes")
+			}
+			nibbles := compactToHex(k.Raw())
+			if len(nibbles) > len(path) || !bytes.Equal(path[:len(nibbles)], nibbles) {
+				// the path diverges, the key is not in the trie
+				return nil, nil
+			}
+			path = path[len(nibbles):]
+
+			if hasTerm(nibbles) {
+				return append([]byte{}, v.Get(1).Raw()...), nil
+			}
+			child = v.Get(1)
+
+		case 17:
+			if path[0] == 16 {
+				return append([]byte{}, v.Get(16).Raw()...), nil
+			}
+			child = v.Get(int(path[0]))
+			path = path[1:]
+
+		default:
+			return nil, fmt.Errorf("node has incorrect number of leafs")
+		}
+
+		if child.Type() == fastrlp.TypeArray {
+			// the node is embedded in its parent
+			v = child
+			continue
+		}
+		if len(child.Raw()) == 0 {
+			return nil, nil
+		}
+		if len(child.Raw()) != 32 {
+			return nil, fmt.Errorf("child reference expected to be a hash")
+		}
+
+		// the parser is reused for the child, copy the reference before it is overwritten
+		hash := append([]byte{}, child.Raw()...)
+		if v, err = resolve(hash); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// indexKey is the key of the item at the index of a list, in the trie of the list
+func indexKey(index int) []byte {
+	ar := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(ar)
+
+	return ar.NewUint(uint64(index)).MarshalTo(nil)
+}
+
+// ProveIndex returns the merkle proof of the item at the index of a list, in the trie keyed by the
+// RLP encoded indexes of the items, as the transactions and the receipts of a block are
+func ProveIndex(num int, item func(i int) []byte, index int) ([][]byte, error) {
+	if index < 0 || index >= num {
+		return nil, fmt.Errorf("index %d out of range", index)
+	}
+
+	storage := NewMemoryStorage()
+
+	batch := storage.Batch()
+
+	txn := NewTrie().Txn()
+	txn.batch = batch
+	for i := 0; i < num; i++ {
+		txn.Insert(indexKey(i), item(i))
+	}
+	root, err := txn.Hash()
+	if err != nil {
+		return nil, err
+	}
+	batch.Write()
+
+	return Prove(storage, types.BytesToHash(root), indexKey(index))
+}
+
+// VerifyIndexProof checks the merkle proof of the item at the index of a list against the root
+// of the list, and returns the item
+func VerifyIndexProof(root types.Hash, index int, proof [][]byte) ([]byte, error) {
+	value, err := VerifyProof(root, indexKey(index), proof)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, ErrInvalidProof
+	}
+
+	return value, nil
+}
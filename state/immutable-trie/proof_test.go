@@ -0,0 +1,105 @@
+package itrie
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProof(t *testing.T) {
+	storage := NewMemoryStorage()
+	root := buildSyncState(t, storage)
+
+	snap, err := NewState(storage).NewSnapshotAt(root)
+	assert.NoError(t, err)
+	trie := snap.(*Trie)
+
+	for i := 0; i < 100; i++ {
+		key := crypto.Keccak256(types.BytesToAddress([]byte{byte(i), 1}).Bytes())
+
+		proof, err := Prove(storage, root, key)
+		assert.NoError(t, err)
+
+		value, err := VerifyProof(root, key, proof)
+		assert.NoError(t, err)
+
+		expected, ok := trie.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, value)
+
+		if i%10 != 0 {
+			continue
+		}
+
+		// the storage slots are proven against the root of the account
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(value))
+
+		slot := crypto.Keccak256(types.BytesToHash([]byte{5}).Bytes())
+		proof, err = Prove(storage, account.Root, slot)
+		assert.NoError(t, err)
+
+		value, err = VerifyProof(account.Root, slot, proof)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, value)
+	}
+
+	// the proof of a missing key shows it is not in the trie
+	missing := crypto.Keccak256([]byte{0xff})
+	proof, err := Prove(storage, root, missing)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, proof)
+
+	value, err := VerifyProof(root, missing, proof)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	// a proof with a missing node is rejected
+	key := crypto.Keccak256(types.BytesToAddress([]byte{1, 1}).Bytes())
+	proof, err = Prove(storage, root, key)
+	assert.NoError(t, err)
+
+	_, err = VerifyProof(root, key, proof[:len(proof)-1])
+	assert.Equal(t, ErrInvalidProof, err)
+
+	_, err = VerifyProof(types.BytesToHash(missing), key, proof)
+	assert.Equal(t, ErrInvalidProof, err)
+}
+
+func TestProofIndex(t *testing.T) {
+	items := [][]byte{}
+	for i := 0; i < 300; i++ {
+		items = append(items, []byte{byte(i), byte(i >> 8), 0x1, 0x2})
+	}
+	item := func(i int) []byte {
+		return items[i]
+	}
+
+	txn := NewTrie().Txn()
+	for i := range items {
+		txn.Insert(indexKey(i), items[i])
+	}
+	root, err := txn.Hash()
+	assert.NoError(t, err)
+
+	for _, index := range []int{0, 1, 127, 128, 299} {
+		proof, err := ProveIndex(len(items), item, index)
+		assert.NoError(t, err)
+
+		value, err := VerifyIndexProof(types.BytesToHash(root), index, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, items[index], value)
+
+		// an altered node doesn't match its hash
+		last := append([]byte{}, proof[len(proof)-1]...)
+		last[len(last)-1] ^= 0xff
+		_, err = VerifyIndexProof(types.BytesToHash(root), index, append(proof[:len(proof)-1], last))
+		assert.Error(t, err)
+	}
+
+	_, err = ProveIndex(len(items), item, len(items))
+	assert.Error(t, err)
+}
@@ -0,0 +1,347 @@
+package itrie
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	PruningArchive = "archive"
+	PruningFull    = "full"
+	PruningLight   = "light"
+)
+
+// pruneDeleteBatch is the number of trie nodes deleted at once
+const pruneDeleteBatch = 10000
+
+// prunedKey marks a storage that was pruned at least once. It is shorter than the
+// keys of the nodes, so the pruning never iterates over it
+var prunedKey = []byte("pruned")
+
+var (
+	ErrNotPrunable    = errors.New("the state storage can't be pruned")
+	ErrPruneRunning   = errors.New("the state is already being pruned")
+	ErrPruneAborted   = errors.New("the pruning of the state was aborted")
+	ErrUnknownPruning = errors.New("unknown pruning mode, expected archive, full or light")
+	ErrNoRetainedRoot = errors.New("none of the retained state roots is stored")
+)
+
+// PruningConfig defines which states are kept by the pruning
+type PruningConfig struct {
+	// Retention is the number of recent blocks whose state is kept
+	Retention uint64
+
+	// CheckpointInterval keeps the state of every block whose number is a multiple of it, 0 disables the checkpoints
+	CheckpointInterval uint64
+
+	// Interval is the number of blocks between two online prunings
+	Interval uint64
+}
+
+// NewPruningConfig returns the pruning of the mode, or nil for the archive mode, which keeps every state
+func NewPruningConfig(mode string) (*PruningConfig, error) {
+	switch mode {
+	case "", PruningArchive:
+		return nil, nil
+
+	case PruningFull:
+		return &PruningConfig{
+			Retention:          128,
+			CheckpointInterval: 8192,
+			Interval:           1024,
+		}, nil
+
+	case PruningLight:
+		return &PruningConfig{
+			Retention: 16,
+			Interval:  256,
+		}, nil
+	}
+
+	return nil, ErrUnknownPruning
+}
+
+// RetainedBlocks returns the numbers of the blocks whose state is kept, given the head of the chain
+func (c *PruningConfig) RetainedBlocks(head uint64) []uint64 {
+	numbers := []uint64{}
+
+	if c.CheckpointInterval != 0 {
+		for num := uint64(0); num <= head; num += c.CheckpointInterval {
+			numbers = append(numbers, num)
+		}
+	}
+
+	first := uint64(0)
+	if head+1 > c.Retention {
+		first = head + 1 - c.Retention
+	}
+	for num := first; num <= head; num++ {
+		if c.CheckpointInterval == 0 || num%c.CheckpointInterval != 0 {
+			numbers = append(numbers, num)
+		}
+	}
+
+	return numbers
+}
+
+// WasPruned reports whether the states of the storage were ever pruned
+func WasPruned(storage Storage) bool {
+	_, ok := storage.Get(prunedKey)
+
+	return ok
+}
+
+// PruneResult is the outcome of a pruning
+type PruneResult struct {
+	Roots    int
+	Kept     uint64
+	Deleted  uint64
+	Duration time.Duration
+}
+
+// Pruner deletes the trie nodes that are not reachable from the retained state roots.
+// It wraps the storage of the state, so the nodes written while a pruning runs are never deleted by it
+type Pruner struct {
+	PrunableStorage
+
+	logger hclog.Logger
+
+	lock    sync.Mutex
+	running bool
+	written map[types.Hash]struct{}
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewPruner creates a pruner of the storage
+func NewPruner(storage Storage, logger hclog.Logger) (*Pruner, error) {
+	prunable, ok := storage.(PrunableStorage)
+	if !ok {
+		return nil, ErrNotPrunable
+	}
+
+	return &Pruner{
+		PrunableStorage: prunable,
+		logger:          logger.Named("pruner"),
+		closeCh:         make(chan struct{}),
+	}, nil
+}
+
+// record remembers the node written while a pruning runs
+func (p *Pruner) record(k []byte) {
+	if len(k) != types.HashLength {
+		return
+	}
+
+	p.lock.Lock()
+	if p.running {
+		p.written[types.BytesToHash(k)] = struct{}{}
+	}
+	p.lock.Unlock()
+}
+
+func (p *Pruner) Put(k, v []byte) {
+	p.record(k)
+	p.PrunableStorage.Put(k, v)
+}
+
+func (p *Pruner) Batch() Batch {
+	return &prunerBatch{Batch: p.PrunableStorage.Batch(), pruner: p}
+}
+
+// Close aborts the running pruning, and closes the storage
+func (p *Pruner) Close() error {
+	close(p.closeCh)
+	p.wg.Wait()
+
+	return p.PrunableStorage.Close()
+}
+
+type prunerBatch struct {
+	Batch
+	pruner *Pruner
+}
+
+func (b *prunerBatch) Put(k, v []byte) {
+	b.pruner.record(k)
+	b.Batch.Put(k, v)
+}
+
+func (p *Pruner) aborted() bool {
+	select {
+	case <-p.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Prune deletes the trie nodes not reachable from the roots. The missing roots, already pruned, are skipped
+func (p *Pruner) Prune(roots []types.Hash) (*PruneResult, error) {
+	p.lock.Lock()
+	if p.running {
+		p.lock.Unlock()
+		return nil, ErrPruneRunning
+	}
+	p.running = true
+	p.written = map[types.Hash]struct{}{}
+	p.wg.Add(1)
+	p.lock.Unlock()
+
+	defer func() {
+		p.lock.Lock()
+		p.running = false
+		p.written = nil
+		p.lock.Unlock()
+		p.wg.Done()
+	}()
+
+	start := time.Now()
+
+	marked, err := p.mark(roots)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := p.sweep(marked)
+	if err != nil {
+		return nil, err
+	}
+	p.PrunableStorage.Put(prunedKey, []byte{0x1})
+
+	return &PruneResult{
+		Roots:    len(roots),
+		Kept:     uint64(len(marked)),
+		Deleted:  deleted,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// mark collects the nodes reachable from the roots, including the nodes of the storage tries
+func (p *Pruner) mark(roots []types.Hash) (map[types.Hash]struct{}, error) {
+	marked := map[types.Hash]struct{}{}
+
+	parser := parserPool.Get()
+	defer parserPool.Put(parser)
+
+	type item struct {
+		hash        types.Hash
+		storageTrie bool
+	}
+
+	queue := []item{}
+	found := false
+	for _, root := range roots {
+		if root == types.EmptyRootHash {
+			found = true
+			continue
+		}
+		if _, ok := p.Get(root.Bytes()); !ok {
+			p.logger.Debug("state root not found", "root", root)
+			continue
+		}
+		queue = append(queue, item{hash: root})
+		found = true
+	}
+	if !found {
+		// never wipe the whole state, the roots are likely of another chain
+		return nil, ErrNoRetainedRoot
+	}
+
+	for len(queue) != 0 {
+		if p.aborted() {
+			return nil, ErrPruneAborted
+		}
+
+		next := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if next.hash == types.EmptyRootHash {
+			continue
+		}
+		if _, ok := marked[next.hash]; ok {
+			continue
+		}
+
+		data, ok := p.Get(next.hash.Bytes())
+		if !ok {
+			return nil, fmt.Errorf("trie node %s not found", next.hash)
+		}
+		marked[next.hash] = struct{}{}
+
+		v, err := parser.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		err = walkNode(v, next.storageTrie, func(child SyncItem, storageTrie bool) {
+			if !child.Code {
+				queue = append(queue, item{hash: child.Hash, storageTrie: storageTrie})
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return marked, nil
+}
+
+// sweep deletes the nodes that are not marked, nor written since the pruning started
+func (p *Pruner) sweep(marked map[types.Hash]struct{}) (uint64, error) {
+	var deleted uint64
+
+	flush := func(keys [][]byte) error {
+		// the lock is held while deleting, so a node written again meanwhile is either skipped or written after
+		p.lock.Lock()
+		defer p.lock.Unlock()
+
+		unwritten := keys[:0]
+		for _, key := range keys {
+			if _, ok := p.written[types.BytesToHash(key)]; !ok {
+				unwritten = append(unwritten, key)
+			}
+		}
+		deleted += uint64(len(unwritten))
+
+		return p.PrunableStorage.Delete(unwritten)
+	}
+
+	var flushErr error
+
+	keys := [][]byte{}
+	err := p.ForEachNode(func(key []byte) bool {
+		if _, ok := marked[types.BytesToHash(key)]; ok {
+			return true
+		}
+		keys = append(keys, key)
+		if len(keys) < pruneDeleteBatch {
+			return true
+		}
+
+		if p.aborted() {
+			flushErr = ErrPruneAborted
+			return false
+		}
+		flushErr = flush(keys)
+		keys = [][]byte{}
+
+		return flushErr == nil
+	})
+	if err != nil {
+		return deleted, err
+	}
+	if flushErr != nil {
+		return deleted, flushErr
+	}
+	if len(keys) != 0 {
+		err = flush(keys)
+	}
+
+	return deleted, err
+}
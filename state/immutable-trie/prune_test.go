@@ -0,0 +1,111 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruningConfig_RetainedBlocks(t *testing.T) {
+	config := &PruningConfig{Retention: 3, CheckpointInterval: 4}
+
+	assert.Equal(t, []uint64{0, 1}, config.RetainedBlocks(1))
+	assert.Equal(t, []uint64{0, 4, 8, 9, 10}, config.RetainedBlocks(10))
+
+	config.CheckpointInterval = 0
+	assert.Equal(t, []uint64{8, 9, 10}, config.RetainedBlocks(10))
+
+	_, err := NewPruningConfig("fast")
+	assert.ErrorIs(t, err, ErrUnknownPruning)
+
+	archive, err := NewPruningConfig(PruningArchive)
+	assert.NoError(t, err)
+	assert.Nil(t, archive)
+}
+
+func TestPruner(t *testing.T) {
+	pruner, err := NewPruner(NewMemoryStorage(), hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	st := NewState(pruner)
+	addr := types.StringToAddress("1")
+
+	// every block updates the balance and the storage of the account
+	roots := []types.Hash{}
+	snap := st.NewSnapshot()
+	for i := 0; i < 5; i++ {
+		txn := state.NewTxn(st, snap)
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+		txn.SetState(addr, types.BytesToHash([]byte{byte(i)}), types.BytesToHash([]byte{1}))
+
+		var root []byte
+		snap, root = txn.Commit(false)
+		roots = append(roots, types.BytesToHash(root))
+	}
+
+	assert.False(t, WasPruned(pruner))
+
+	res, err := pruner.Prune(roots[3:])
+	assert.NoError(t, err)
+	assert.NotZero(t, res.Deleted)
+	assert.True(t, WasPruned(pruner))
+
+	// the retained states are complete, the other ones are gone
+	fresh := NewState(pruner)
+	for i, root := range roots {
+		_, ok := pruner.Get(root.Bytes())
+		assert.Equal(t, i >= 3, ok)
+	}
+
+	snapshot, err := fresh.NewSnapshotAt(roots[3])
+	assert.NoError(t, err)
+	txn := state.NewTxn(fresh, snapshot)
+	assert.Equal(t, big.NewInt(4), txn.GetBalance(addr))
+	assert.Equal(t, types.BytesToHash([]byte{1}), txn.GetState(addr, types.BytesToHash([]byte{0})))
+
+	// a second pruning with the same roots has nothing left to delete
+	res, err = pruner.Prune(roots[3:])
+	assert.NoError(t, err)
+	assert.Zero(t, res.Deleted)
+}
+
+func TestPruner_NoRetainedRoot(t *testing.T) {
+	storage := NewMemoryStorage()
+	pruner, err := NewPruner(storage, hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	st := NewState(pruner)
+	txn := state.NewTxn(st, st.NewSnapshot())
+	txn.SetBalance(types.StringToAddress("1"), big.NewInt(1))
+	_, root := txn.Commit(false)
+
+	// the unknown roots never wipe the state
+	_, err = pruner.Prune([]types.Hash{types.StringToHash("1")})
+	assert.ErrorIs(t, err, ErrNoRetainedRoot)
+
+	_, ok := storage.Get(root)
+	assert.True(t, ok)
+}
+
+func TestPruner_KeepWrittenNodes(t *testing.T) {
+	storage := NewMemoryStorage()
+	pruner, err := NewPruner(storage, hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	// a node written while the pruning runs is not reachable from the roots it was given
+	pruner.running = true
+	pruner.written = map[types.Hash]struct{}{}
+	key := types.StringToHash("2").Bytes()
+	pruner.Batch().Put(key, []byte{0x1})
+
+	deleted, err := pruner.sweep(map[types.Hash]struct{}{})
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	_, ok := storage.Get(key)
+	assert.True(t, ok)
+}
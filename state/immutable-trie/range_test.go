@@ -0,0 +1,251 @@
+package itrie
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedHashes(hashes []types.Hash) []types.Hash {
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i].Bytes(), hashes[j].Bytes()) < 0
+	})
+	return hashes
+}
+
+func TestTrieIterate(t *testing.T) {
+	storage := NewMemoryStorage()
+	root := buildSyncState(t, storage)
+
+	// the nodes are loaded from the storage
+	snap, err := NewState(storage).NewSnapshotAt(root)
+	assert.NoError(t, err)
+	trie := snap.(*Trie)
+
+	expected := []types.Hash{}
+	for i := 0; i < 100; i++ {
+		expected = append(expected, types.BytesToHash(crypto.Keccak256(types.BytesToAddress([]byte{byte(i), 1}).Bytes())))
+	}
+	expected = sortedHashes(expected)
+
+	iterate := func(start types.Hash, limit int) []types.Hash {
+		keys := []types.Hash{}
+		assert.NoError(t, trie.Iterate(start.Bytes(), func(k, v []byte) bool {
+			value, ok := trie.Get(k)
+			assert.True(t, ok)
+			assert.Equal(t, value, v)
+
+			keys = append(keys, types.BytesToHash(k))
+			return len(keys) < limit
+		}))
+		return keys
+	}
+
+	assert.Equal(t, expected, iterate(types.Hash{}, 1000))
+
+	// from an existing key, and from a key between two keys
+	assert.Equal(t, expected[40:], iterate(expected[40], 1000))
+
+	between := expected[40]
+	between[31]++
+	assert.Equal(t, expected[41:], iterate(between, 1000))
+
+	// the handler stops the iteration
+	assert.Equal(t, expected[10:15], iterate(expected[10], 5))
+}
+
+func TestStorageRangeAt(t *testing.T) {
+	var (
+		sender   = types.StringToAddress("1")
+		counter  = types.StringToAddress("2")
+		coinbase = types.StringToAddress("3")
+	)
+
+	st := NewState(NewMemoryStorage())
+
+	executor := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, st, hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.Hash{}
+		}
+	}
+
+	// the counter increments its first slot
+	genesisStorage := map[types.Hash]types.Hash{}
+	for i := 1; i <= 5; i++ {
+		genesisStorage[types.BytesToHash([]byte{byte(i)})] = types.BytesToHash([]byte{byte(i * 10)})
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1000000000)},
+		counter: {
+			Code:    hex.MustDecodeHex("0x60005460010160005560006000a000"),
+			Storage: genesisStorage,
+		},
+	})
+	assert.NoError(t, err)
+
+	call := func(nonce uint64) *types.Transaction {
+		return &types.Transaction{
+			From:     sender,
+			Nonce:    nonce,
+			To:       &counter,
+			Value:    big.NewInt(0),
+			Gas:      100000,
+			GasPrice: big.NewInt(1),
+		}
+	}
+
+	block := &types.Block{
+		Header:       &types.Header{Number: 1, GasLimit: 1000000},
+		Transactions: []*types.Transaction{call(0), call(1)},
+	}
+
+	res, err := executor.ProcessBlock(root, block, coinbase)
+	assert.NoError(t, err)
+	block.Header.StateRoot = res.Root
+
+	hashedKey := func(key types.Hash) types.Hash {
+		return types.BytesToHash(crypto.Keccak256(key.Bytes()))
+	}
+
+	// before the transactions, the storage of the genesis
+	rng, err := executor.StorageRangeAt(root, block, coinbase, 0, counter, types.Hash{}, 100)
+	assert.NoError(t, err)
+	assert.Nil(t, rng.NextKey)
+	assert.Len(t, rng.Storage, 5)
+
+	for key, value := range genesisStorage {
+		assert.Equal(t, state.StorageEntry{Value: value}, rng.Storage[hashedKey(key)])
+	}
+
+	// the slot written by the first transaction is known
+	slot := types.Hash{}
+	for txIndex, value := range []byte{1, 2} {
+		rng, err = executor.StorageRangeAt(root, block, coinbase, txIndex+1, counter, types.Hash{}, 100)
+		assert.NoError(t, err)
+		assert.Len(t, rng.Storage, 6)
+		assert.Equal(t, state.StorageEntry{Key: &slot, Value: types.BytesToHash([]byte{value})}, rng.Storage[hashedKey(slot)])
+	}
+
+	// the ranges are paged in the order of the hashed keys
+	keys := []types.Hash{hashedKey(slot)}
+	for key := range genesisStorage {
+		keys = append(keys, hashedKey(key))
+	}
+	keys = sortedHashes(keys)
+
+	start := types.Hash{}
+	for i := 0; i < len(keys); i += 4 {
+		rng, err = executor.StorageRangeAt(root, block, coinbase, 2, counter, start, 4)
+		assert.NoError(t, err)
+
+		end := i + 4
+		if end >= len(keys) {
+			end = len(keys)
+			assert.Nil(t, rng.NextKey)
+		} else {
+			assert.Equal(t, keys[end], *rng.NextKey)
+			start = *rng.NextKey
+		}
+
+		paged := []types.Hash{}
+		for key := range rng.Storage {
+			paged = append(paged, key)
+		}
+		assert.Equal(t, keys[i:end], sortedHashes(paged))
+	}
+
+	// the accounts without storage have an empty range
+	rng, err = executor.StorageRangeAt(root, block, coinbase, 2, sender, types.Hash{}, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, rng.Storage)
+
+	_, err = executor.StorageRangeAt(root, block, coinbase, 3, counter, types.Hash{}, 100)
+	assert.Error(t, err)
+
+	// the block modified the sender, the counter and the coinbase
+	modified, err := executor.ModifiedAccounts(root, block, coinbase)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{sender, counter, coinbase}, modified)
+}
+
+func TestAccountRange(t *testing.T) {
+	st := NewState(NewMemoryStorage())
+	executor := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, st, hclog.NewNullLogger())
+
+	code := hex.MustDecodeHex("0x6000")
+	slot := types.BytesToHash([]byte{1})
+
+	genesis := map[types.Address]*chain.GenesisAccount{}
+	for i := 0; i < 10; i++ {
+		genesis[types.BytesToAddress([]byte{byte(i), 1})] = &chain.GenesisAccount{Balance: big.NewInt(int64(i))}
+	}
+	contract := types.StringToAddress("2")
+	genesis[contract] = &chain.GenesisAccount{
+		Balance: big.NewInt(100),
+		Code:    code,
+		Storage: map[types.Hash]types.Hash{slot: types.BytesToHash([]byte{10})},
+	}
+
+	root, err := executor.WriteGenesis(genesis)
+	assert.NoError(t, err)
+
+	keys := []types.Hash{}
+	for addr := range genesis {
+		keys = append(keys, types.BytesToHash(crypto.Keccak256(addr.Bytes())))
+	}
+	keys = sortedHashes(keys)
+
+	// the accounts are paged in the order of the hashed keys, with their addresses
+	start := types.Hash{}
+	paged := []types.Hash{}
+	for {
+		rng, err := executor.AccountRange(root, start, 4, false, false)
+		assert.NoError(t, err)
+
+		for key, account := range rng.Accounts {
+			paged = append(paged, key)
+
+			assert.NotNil(t, account.Address)
+			assert.Zero(t, genesis[*account.Address].Balance.Cmp(account.Balance))
+			assert.Nil(t, account.Code)
+			assert.Nil(t, account.Storage)
+		}
+		if rng.Next == nil {
+			break
+		}
+		start = *rng.Next
+	}
+	assert.Equal(t, keys, sortedHashes(paged))
+
+	// the codes and the storages are dumped on demand
+	rng, err := executor.AccountRange(root, types.Hash{}, 100, true, true)
+	assert.NoError(t, err)
+	assert.Len(t, rng.Accounts, 11)
+	assert.Nil(t, rng.Next)
+
+	account := rng.Accounts[types.BytesToHash(crypto.Keccak256(contract.Bytes()))]
+	assert.Equal(t, contract, *account.Address)
+	assert.Equal(t, code, account.Code)
+	assert.Equal(t, types.BytesToHash(crypto.Keccak256(code)), account.CodeHash)
+	assert.Equal(t, map[types.Hash]types.Hash{slot: types.BytesToHash([]byte{10})}, account.Storage)
+
+	for _, account := range rng.Accounts {
+		if *account.Address != contract {
+			assert.Nil(t, account.Code)
+			assert.Empty(t, account.Storage)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+)
+
+func TestStateRentArchiveAndRestore(t *testing.T) {
+	var (
+		sender  = types.StringToAddress("1")
+		account = types.StringToAddress("2")
+	)
+
+	st := NewState(NewMemoryStorage())
+	archive := NewMemoryStorage()
+
+	executor := state.NewExecutor(&chain.Params{
+		Forks:     &chain.Forks{StateRent: chain.NewFork(0)},
+		StateRent: &chain.StateRent{InactivityPeriod: 10},
+	}, st, hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.SetArchiveStore(archive)
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.Hash{}
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1000)},
+	})
+	assert.NoError(t, err)
+
+	nonce := uint64(0)
+	newTx := func(to types.Address, value int64, input []byte) *types.Transaction {
+		tx := &types.Transaction{
+			From:     sender,
+			To:       &to,
+			Nonce:    nonce,
+			Value:    big.NewInt(value),
+			Gas:      100000,
+			GasPrice: big.NewInt(0),
+			Input:    input,
+		}
+		nonce++
+
+		return tx
+	}
+
+	// block applies the transactions in a new block, and returns the result of the last one
+	block := func(number uint64, txs ...*types.Transaction) error {
+		transition, err := executor.BeginTxn(root, &types.Header{Number: number, GasLimit: 1000000}, sender)
+		assert.NoError(t, err)
+
+		var resErr error
+		for _, tx := range txs {
+			res, err := transition.Apply(tx)
+			assert.NoError(t, err)
+			resErr = res.Err
+		}
+
+		_, root = transition.Commit()
+		return resErr
+	}
+
+	getAccount := func(addr types.Address) (*state.Account, bool) {
+		snap, err := st.NewSnapshotAt(root)
+		assert.NoError(t, err)
+
+		return state.NewTxn(st, snap).GetAccount(addr)
+	}
+
+	// both accounts are modified in the first period,
+	// and only the sender is modified in the second one
+	assert.NoError(t, block(1, newTx(account, 10, nil)))
+	assert.NoError(t, block(11, newTx(sender, 0, nil)))
+
+	acc, ok := getAccount(account)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), acc.LastTouched)
+
+	// the account is archived at the start of the third period
+	assert.NoError(t, block(20))
+
+	_, ok = getAccount(account)
+	assert.False(t, ok)
+
+	acc, ok = getAccount(sender)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(11), acc.LastTouched)
+
+	archived, ok := executor.GetArchivedAccount(account)
+	assert.True(t, ok)
+
+	// the restoration fails with a proof that does not match the archived account
+	forged := &state.Account{Balance: big.NewInt(1000000), CodeHash: acc.CodeHash, Root: acc.Root, LastTouched: 1}
+	forgedData := forged.MarshalWith(&fastrlp.Arena{}).MarshalTo(nil)
+
+	err = block(21, newTx(state.StateRentAddress, 0, state.EncodeRestoreInput(account, forgedData)))
+	assert.Equal(t, state.ErrInvalidRestoreProof, err)
+
+	// the address is used again before the account is restored
+	assert.NoError(t, block(22, newTx(account, 5, nil)))
+
+	err = block(23, newTx(state.StateRentAddress, 0, state.EncodeRestoreInput(account, archived)))
+	assert.NoError(t, err)
+
+	acc, ok = getAccount(account)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(15), acc.Balance)
+	assert.Equal(t, uint64(23), acc.LastTouched)
+
+	// the account can only be restored once
+	err = block(24, newTx(state.StateRentAddress, 0, state.EncodeRestoreInput(account, archived)))
+	assert.Equal(t, state.ErrAccountNotArchived, err)
+}
+
+func TestStateRentDisabled(t *testing.T) {
+	st := NewState(NewMemoryStorage())
+
+	executor := state.NewExecutor(&chain.Params{
+		Forks:     &chain.Forks{StateRent: chain.NewFork(100)},
+		StateRent: &chain.StateRent{InactivityPeriod: 10},
+	}, st, hclog.NewNullLogger())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return nil
+	}
+
+	addr := types.StringToAddress("1")
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		addr: {Balance: big.NewInt(1000)},
+	})
+	assert.NoError(t, err)
+
+	// before the fork the accounts are not tracked, and the state root is the same as without state rent
+	transition, err := executor.BeginTxn(root, &types.Header{Number: 20}, addr)
+	assert.NoError(t, err)
+	transition.Txn().AddBalance(addr, big.NewInt(1))
+	_, root = transition.Commit()
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+	txn.AddBalance(addr, big.NewInt(1001))
+	_, expected := txn.Commit(false)
+
+	assert.Equal(t, types.BytesToHash(expected), root)
+}
@@ -0,0 +1,106 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockRewards(t *testing.T) {
+	var (
+		sender1  = types.StringToAddress("1")
+		sender2  = types.StringToAddress("2")
+		receiver = types.StringToAddress("3")
+		treasury = types.StringToAddress("4")
+		proposer = types.StringToAddress("5")
+	)
+
+	st := NewState(NewMemoryStorage())
+
+	newExecutor := func(parallelism int) *state.Executor {
+		executor := state.NewExecutor(&chain.Params{
+			Forks: chain.AllForksEnabled,
+			Economics: &chain.Economics{
+				Block:         2,
+				BlockReward:   big.NewInt(100),
+				Treasury:      treasury,
+				TreasuryShare: 2000,
+				BurnShare:     3000,
+			},
+		}, st, hclog.NewNullLogger())
+		executor.SetRuntime(evm.NewEVM())
+		executor.GetHash = func(*types.Header) state.GetHashByNumber {
+			return func(uint64) types.Hash {
+				return types.Hash{}
+			}
+		}
+		executor.SetParallelism(parallelism)
+
+		return executor
+	}
+
+	sequential := newExecutor(1)
+	parallel := newExecutor(4)
+
+	root, err := sequential.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender1: {Balance: big.NewInt(1000000000)},
+		sender2: {Balance: big.NewInt(1000000000)},
+	})
+	assert.NoError(t, err)
+
+	// every block has two transfers paying a fee of 21000 * 10
+	block := func(number uint64) *types.Block {
+		transfer := func(from types.Address) *types.Transaction {
+			return &types.Transaction{
+				From:     from,
+				Nonce:    number - 1,
+				To:       &receiver,
+				Value:    big.NewInt(1),
+				Gas:      21000,
+				GasPrice: big.NewInt(10),
+			}
+		}
+
+		return &types.Block{
+			Header:       &types.Header{Number: number, GasLimit: 1000000},
+			Transactions: []*types.Transaction{transfer(sender1), transfer(sender2)},
+		}
+	}
+
+	balances := func(root types.Hash) (*big.Int, *big.Int) {
+		snap, err := st.NewSnapshotAt(root)
+		assert.NoError(t, err)
+
+		txn := state.NewTxn(st, snap)
+
+		return txn.GetBalance(proposer), txn.GetBalance(treasury)
+	}
+
+	// the proposer receives the whole fees before the economics are active
+	res, err := sequential.ProcessBlock(root, block(1), proposer)
+	assert.NoError(t, err)
+	root = res.Root
+
+	proposerBalance, treasuryBalance := balances(root)
+	assert.Equal(t, big.NewInt(420000), proposerBalance)
+	assert.Equal(t, big.NewInt(0), treasuryBalance)
+
+	// then half of the fees and the reward, the treasury a fifth of the fees and the rest is burnt
+	res, err = sequential.ProcessBlock(root, block(2), proposer)
+	assert.NoError(t, err)
+
+	proposerBalance, treasuryBalance = balances(res.Root)
+	assert.Equal(t, big.NewInt(420000+210000+100), proposerBalance)
+	assert.Equal(t, big.NewInt(84000), treasuryBalance)
+
+	// the same with the transactions executed in parallel
+	parallelRes, err := parallel.ProcessBlock(root, block(2), proposer)
+	assert.NoError(t, err)
+	assert.Equal(t, res.Root, parallelRes.Root)
+}
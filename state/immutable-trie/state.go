@@ -2,6 +2,7 @@ package itrie
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	lru "github.com/hashicorp/golang-lru"
 
@@ -12,18 +13,27 @@ import (
 type State struct {
 	storage Storage
 	cache   *lru.Cache
+	counter *countingStorage
 }
 
 func NewState(storage Storage) *State {
 	cache, _ := lru.New(128)
 
+	counter := &countingStorage{Storage: storage}
+
 	s := &State{
-		storage: storage,
+		storage: counter,
 		cache:   cache,
+		counter: counter,
 	}
 	return s
 }
 
+// NodesLoaded returns the number of trie nodes loaded from the storage so far
+func (s *State) NodesLoaded() uint64 {
+	return atomic.LoadUint64(&s.counter.reads)
+}
+
 func (s *State) NewSnapshot() state.Snapshot {
 	t := NewTrie()
 	t.state = s
@@ -39,6 +49,11 @@ func (s *State) GetCode(hash types.Hash) ([]byte, bool) {
 	return s.storage.GetCode(hash)
 }
 
+// GetPreimage returns the address or the storage key hashed into the trie key, if committed by this node
+func (s *State) GetPreimage(hash types.Hash) ([]byte, bool) {
+	return s.storage.Get(preimageKey(hash.Bytes()))
+}
+
 func (s *State) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
 	if root == types.EmptyRootHash {
 		// empty state
@@ -69,3 +84,14 @@ func (s *State) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
 func (s *State) AddState(root types.Hash, t *Trie) {
 	s.cache.Add(root, t)
 }
+
+// countingStorage counts the trie nodes read from the underlying storage
+type countingStorage struct {
+	Storage
+	reads uint64
+}
+
+func (c *countingStorage) Get(k []byte) ([]byte, bool) {
+	atomic.AddUint64(&c.reads, 1)
+	return c.Storage.Get(k)
+}
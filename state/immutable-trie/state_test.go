@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
 )
 
 func TestState(t *testing.T) {
@@ -17,3 +18,56 @@ func buildPreState(pre state.PreStates) (state.State, state.Snapshot) {
 
 	return st, snap
 }
+
+func TestStateNodesLoaded(t *testing.T) {
+	st := NewState(NewMemoryStorage())
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+	txn.SetState(types.StringToAddress("1"), types.StringToHash("1"), types.StringToHash("1"))
+	_, root := txn.Commit(false)
+
+	before := st.NodesLoaded()
+
+	// bypass the cache of the committed tries, so the nodes are loaded from the storage
+	st.cache.Purge()
+
+	snap, err := st.NewSnapshotAt(types.BytesToHash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.NewTxn(st, snap).GetState(types.StringToAddress("1"), types.StringToHash("1"))
+
+	if st.NodesLoaded() <= before {
+		t.Fatal("expected trie nodes to be loaded from the storage")
+	}
+}
+
+func TestStateOverlay(t *testing.T) {
+	storage := NewMemoryStorage()
+	st := NewState(storage)
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+	txn.SetState(types.StringToAddress("1"), types.StringToHash("1"), types.StringToHash("1"))
+	_, root := txn.Commit(false)
+
+	// the overlay reads the committed state, and keeps its own commits in memory
+	overlay := NewState(NewOverlayStorage(storage))
+	snap, err := overlay.NewSnapshotAt(types.BytesToHash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn = state.NewTxn(overlay, snap)
+	txn.SetState(types.StringToAddress("2"), types.StringToHash("1"), types.StringToHash("2"))
+	_, overlayRoot := txn.Commit(false)
+
+	overlay.cache.Purge()
+	if _, err := overlay.NewSnapshotAt(types.BytesToHash(overlayRoot)); err != nil {
+		t.Fatal(err)
+	}
+
+	st.cache.Purge()
+	if _, err := st.NewSnapshotAt(types.BytesToHash(overlayRoot)); err == nil {
+		t.Fatal("expected the overlay state not to be written to the storage")
+	}
+}
@@ -3,10 +3,11 @@ package itrie
 import (
 	"fmt"
 
+	"github.com/0xPolygon/polygon-sdk/helper/encryption"
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -15,8 +16,15 @@ var parserPool fastrlp.ParserPool
 var (
 	// codePrefix is the code prefix for leveldb
 	codePrefix = []byte("code")
+
+	// preimagePrefix is the prefix of the addresses and the storage keys, by their hashed trie keys
+	preimagePrefix = []byte("preimage")
 )
 
+func preimageKey(hash []byte) []byte {
+	return append(append([]byte{}, preimagePrefix...), hash...)
+}
+
 type Batch interface {
 	Put(k, v []byte)
 	Write()
@@ -33,23 +41,41 @@ type Storage interface {
 	Close() error
 }
 
-// KVStorage is a k/v storage on memory using leveldb
+// PrunableStorage is a storage whose trie nodes can be listed and deleted
+type PrunableStorage interface {
+	Storage
+
+	// ForEachNode calls the handler with the key of every trie node, until it returns false
+	ForEachNode(handler func(key []byte) bool) error
+
+	// Delete removes the trie nodes with the given keys
+	Delete(keys [][]byte) error
+
+	// Compact reclaims the disk space of the deleted nodes
+	Compact() error
+}
+
+// KVStorage is a k/v storage on top of a key-value database
 type KVStorage struct {
-	db *leveldb.DB
+	db     kvdb.Database
+	cipher *encryption.Cipher
 }
 
-// KVBatch is a batch write for leveldb
+// KVBatch is a batch write for the key-value database
 type KVBatch struct {
-	db    *leveldb.DB
-	batch *leveldb.Batch
+	batch  kvdb.Batch
+	cipher *encryption.Cipher
 }
 
 func (b *KVBatch) Put(k, v []byte) {
+	if b.cipher != nil {
+		v = b.cipher.Seal(k, v)
+	}
 	b.batch.Put(k, v)
 }
 
 func (b *KVBatch) Write() {
-	b.db.Write(b.batch, nil)
+	b.batch.Write()
 }
 
 func (kv *KVStorage) SetCode(hash types.Hash, code []byte) {
@@ -61,35 +87,91 @@ func (kv *KVStorage) GetCode(hash types.Hash) ([]byte, bool) {
 }
 
 func (kv *KVStorage) Batch() Batch {
-	return &KVBatch{db: kv.db, batch: &leveldb.Batch{}}
+	return &KVBatch{batch: kv.db.NewBatch(), cipher: kv.cipher}
 }
 
 func (kv *KVStorage) Put(k, v []byte) {
-	kv.db.Put(k, v, nil)
+	if kv.cipher != nil {
+		v = kv.cipher.Seal(k, v)
+	}
+	kv.db.Put(k, v)
 }
 
 func (kv *KVStorage) Get(k []byte) ([]byte, bool) {
-	data, err := kv.db.Get(k, nil)
+	data, ok, err := kv.db.Get(k)
 	if err != nil {
-		if err.Error() == "leveldb: not found" {
-			return nil, false
-		} else {
+		panic(err)
+	}
+	if !ok {
+		return nil, false
+	}
+	if kv.cipher != nil {
+		if data, err = kv.cipher.Open(k, data); err != nil {
 			panic(err)
 		}
 	}
 	return data, true
 }
 
+func (kv *KVStorage) ForEachNode(handler func(key []byte) bool) error {
+	iter := kv.db.NewIterator(nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		// the codes and the other entries have longer keys than the node hashes
+		if key := iter.Key(); len(key) == types.HashLength {
+			if !handler(append([]byte{}, key...)) {
+				break
+			}
+		}
+	}
+
+	return iter.Error()
+}
+
+func (kv *KVStorage) Delete(keys [][]byte) error {
+	batch := kv.db.NewBatch()
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+
+	return batch.Write()
+}
+
+func (kv *KVStorage) Compact() error {
+	return kv.db.Compact()
+}
+
 func (kv *KVStorage) Close() error {
 	return kv.db.Close()
 }
 
 func NewLevelDBStorage(path string, logger hclog.Logger) (Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	return NewEncryptedLevelDBStorage(path, nil, logger)
+}
+
+// NewEncryptedLevelDBStorage creates a leveldb storage whose values are encrypted
+// with the cipher, if it is not nil
+func NewEncryptedLevelDBStorage(path string, cipher *encryption.Cipher, logger hclog.Logger) (Storage, error) {
+	db, err := kvdb.OpenLevelDB(path)
 	if err != nil {
 		return nil, err
 	}
-	return &KVStorage{db}, nil
+	storage, err := NewDatabaseStorage(db, cipher)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	return storage, nil
+}
+
+// NewDatabaseStorage creates a storage on top of the database, whose values are encrypted
+// with the cipher, if it is not nil
+func NewDatabaseStorage(db kvdb.Database, cipher *encryption.Cipher) (Storage, error) {
+	if err := encryption.CheckDatabase(db, cipher); err != nil {
+		return nil, err
+	}
+	return &KVStorage{db: db, cipher: cipher}, nil
 }
 
 type memStorage struct {
@@ -133,6 +215,35 @@ func (m *memStorage) Batch() Batch {
 	return &memBatch{db: &m.db}
 }
 
+func (m *memStorage) ForEachNode(handler func(key []byte) bool) error {
+	for k := range m.db {
+		key, err := hex.DecodeHex(k)
+		if err != nil {
+			return err
+		}
+		if len(key) != types.HashLength {
+			continue
+		}
+		if !handler(key) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (m *memStorage) Delete(keys [][]byte) error {
+	for _, key := range keys {
+		delete(m.db, hex.EncodeToHex(key))
+	}
+
+	return nil
+}
+
+func (m *memStorage) Compact() error {
+	return nil
+}
+
 func (m *memStorage) Close() error {
 	return nil
 }
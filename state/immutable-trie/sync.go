@@ -0,0 +1,257 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	ErrUnrequestedSyncItem = errors.New("sync item was not requested")
+	ErrSyncHashMismatch    = errors.New("sync item does not match its hash")
+)
+
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// SyncItem is a trie node or a contract code missing from the storage
+type SyncItem struct {
+	Hash types.Hash
+	Code bool
+}
+
+// syncRequest is a scheduled item, kept until its whole subtree is stored
+type syncRequest struct {
+	item SyncItem
+
+	// storageTrie is set for the nodes of the storage tries, whose leaves are not accounts
+	storageTrie bool
+
+	data    []byte
+	deps    int
+	parents []*syncRequest
+}
+
+// Sync downloads a state trie, along with its storage tries and contract codes, given its root.
+// Every item is checked against the hash it was requested with, and a node is only written
+// once its whole subtree is, so an interrupted sync resumes without leaving holes in the storage
+type Sync struct {
+	storage Storage
+	batch   Batch
+
+	// requests holds the scheduled items not stored yet
+	requests map[SyncItem]*syncRequest
+
+	// queue holds the scheduled items not handed out by Next yet
+	queue []SyncItem
+
+	nodes uint64
+	codes uint64
+}
+
+// NewSync creates a sync of the state trie with the given root into the storage
+func NewSync(root types.Hash, storage Storage) *Sync {
+	s := &Sync{
+		storage:  storage,
+		batch:    storage.Batch(),
+		requests: map[SyncItem]*syncRequest{},
+	}
+	s.schedule(&syncRequest{item: SyncItem{Hash: root}}, nil)
+
+	return s
+}
+
+// schedule queues the request, unless its item is stored or already scheduled.
+// It reports whether the parent has to wait for the item
+func (s *Sync) schedule(req *syncRequest, parent *syncRequest) bool {
+	if req.item.Hash == types.EmptyRootHash || bytes.Equal(req.item.Hash.Bytes(), emptyCodeHash) {
+		return false
+	}
+
+	if old, ok := s.requests[req.item]; ok {
+		if parent != nil {
+			old.parents = append(old.parents, parent)
+		}
+
+		return true
+	}
+
+	var stored bool
+	if req.item.Code {
+		_, stored = s.storage.GetCode(req.item.Hash)
+	} else {
+		_, stored = s.storage.Get(req.item.Hash.Bytes())
+	}
+	if stored {
+		return false
+	}
+
+	if parent != nil {
+		req.parents = append(req.parents, parent)
+	}
+	s.requests[req.item] = req
+	s.queue = append(s.queue, req.item)
+
+	return true
+}
+
+// Next returns at most max of the missing items to download
+func (s *Sync) Next(max int) []SyncItem {
+	if max > len(s.queue) {
+		max = len(s.queue)
+	}
+	items := s.queue[:max]
+	s.queue = s.queue[max:]
+
+	return items
+}
+
+// Retry queues again an item returned by Next that could not be downloaded
+func (s *Sync) Retry(item SyncItem) {
+	if _, ok := s.requests[item]; ok {
+		s.queue = append(s.queue, item)
+	}
+}
+
+// Process checks a downloaded item against its hash, and schedules the children
+// it references that are missing from the storage
+func (s *Sync) Process(item SyncItem, data []byte) error {
+	req, ok := s.requests[item]
+	if !ok || req.data != nil {
+		return ErrUnrequestedSyncItem
+	}
+	if !bytes.Equal(crypto.Keccak256(data), item.Hash.Bytes()) {
+		return ErrSyncHashMismatch
+	}
+	req.data = data
+
+	if !item.Code {
+		p := parserPool.Get()
+		defer parserPool.Put(p)
+
+		v, err := p.Parse(data)
+		if err != nil {
+			return err
+		}
+		if err := s.scheduleChildren(req, v); err != nil {
+			return err
+		}
+	}
+
+	if req.deps == 0 {
+		s.commit(req)
+	}
+
+	return nil
+}
+
+// scheduleChildren schedules the items referenced by the node that are missing from the storage
+func (s *Sync) scheduleChildren(req *syncRequest, v *fastrlp.Value) error {
+	return walkNode(v, req.storageTrie, func(item SyncItem, storageTrie bool) {
+		child := &syncRequest{
+			item:        item,
+			storageTrie: storageTrie,
+		}
+		if s.schedule(child, req) {
+			req.deps++
+		}
+	})
+}
+
+// walkNode calls the handler with the nodes referenced by their hash from the node, and for the leaves
+// of the account trie, with the root of the storage trie and the code of the account
+func walkNode(v *fastrlp.Value, storageTrie bool, handler func(item SyncItem, storageTrie bool)) error {
+	switch v.Elems() {
+	case 2:
+		key := v.Get(0)
+		if key.Type() != fastrlp.TypeBytes {
+			return fmt.Errorf("short key expected to be bytes")
+		}
+		if !hasTerm(compactToHex(key.Raw())) {
+			return walkChild(v.Get(1), storageTrie, handler)
+		}
+		if storageTrie {
+			return nil
+		}
+
+		var account state.Account
+		if err := account.UnmarshalRlp(v.Get(1).Raw()); err != nil {
+			return err
+		}
+		handler(SyncItem{Hash: account.Root}, true)
+		handler(SyncItem{Hash: types.BytesToHash(account.CodeHash), Code: true}, false)
+
+		return nil
+
+	case 17:
+		for i := 0; i < 16; i++ {
+			if err := walkChild(v.Get(i), storageTrie, handler); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("node has incorrect number of leafs")
+}
+
+// walkChild calls the handler with the child if it is referenced by its hash, or walks it if it is embedded
+func walkChild(child *fastrlp.Value, storageTrie bool, handler func(item SyncItem, storageTrie bool)) error {
+	if child.Type() == fastrlp.TypeArray {
+		return walkNode(child, storageTrie, handler)
+	}
+	if len(child.Raw()) == 0 {
+		return nil
+	}
+	if len(child.Raw()) != 32 {
+		return fmt.Errorf("child reference expected to be a hash")
+	}
+	handler(SyncItem{Hash: types.BytesToHash(child.Raw())}, storageTrie)
+
+	return nil
+}
+
+// commit writes the item, and the parents that were only waiting for it
+func (s *Sync) commit(req *syncRequest) {
+	if req.item.Code {
+		s.storage.SetCode(req.item.Hash, req.data)
+		s.codes++
+	} else {
+		s.batch.Put(req.item.Hash.Bytes(), req.data)
+		s.nodes++
+	}
+	delete(s.requests, req.item)
+
+	for _, parent := range req.parents {
+		parent.deps--
+		if parent.deps == 0 && parent.data != nil {
+			s.commit(parent)
+		}
+	}
+}
+
+// Flush writes the committed nodes to the storage
+func (s *Sync) Flush() {
+	s.batch.Write()
+	s.batch = s.storage.Batch()
+}
+
+// Pending returns the number of items not stored yet
+func (s *Sync) Pending() int {
+	return len(s.requests)
+}
+
+// Done reports whether the whole state is stored
+func (s *Sync) Done() bool {
+	return len(s.requests) == 0
+}
+
+// Stats returns the number of nodes and codes stored so far
+func (s *Sync) Stats() (nodes uint64, codes uint64) {
+	return s.nodes, s.codes
+}
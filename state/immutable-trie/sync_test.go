@@ -0,0 +1,131 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildSyncState(t *testing.T, storage Storage) types.Hash {
+	st := NewState(storage)
+	txn := state.NewTxn(st, st.NewSnapshot())
+
+	for i := 0; i < 100; i++ {
+		addr := types.BytesToAddress([]byte{byte(i), 1})
+		txn.SetBalance(addr, big.NewInt(int64(i+1)))
+
+		if i%10 == 0 {
+			txn.SetCode(addr, []byte{0x60, byte(i)})
+			for j := 0; j < 20; j++ {
+				txn.SetState(addr, types.BytesToHash([]byte{byte(j)}), types.BytesToHash([]byte{byte(i), byte(j)}))
+			}
+		}
+	}
+	_, root := txn.Commit(false)
+
+	return types.BytesToHash(root)
+}
+
+// syncFrom serves the missing items from the source storage, at most batch items at a time
+func syncFrom(t *testing.T, sync *Sync, source Storage, batch int) {
+	for !sync.Done() {
+		items := sync.Next(batch)
+		assert.NotEmpty(t, items)
+
+		for _, item := range items {
+			var data []byte
+			if item.Code {
+				data, _ = source.GetCode(item.Hash)
+			} else {
+				data, _ = source.Get(item.Hash.Bytes())
+			}
+			assert.NoError(t, sync.Process(item, data))
+		}
+		sync.Flush()
+	}
+}
+
+func TestSync(t *testing.T) {
+	source := NewMemoryStorage()
+	root := buildSyncState(t, source)
+
+	target := NewMemoryStorage()
+	sync := NewSync(root, target)
+	syncFrom(t, sync, source, 16)
+
+	nodes, codes := sync.Stats()
+	assert.NotZero(t, nodes)
+	assert.Equal(t, uint64(10), codes)
+
+	st := NewState(target)
+	snap, err := st.NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	txn := state.NewTxn(st, snap)
+	addr := types.BytesToAddress([]byte{20, 1})
+	assert.Equal(t, big.NewInt(21), txn.GetBalance(addr))
+	assert.Equal(t, []byte{0x60, 20}, txn.GetCode(addr))
+	assert.Equal(t, types.BytesToHash([]byte{20, 7}), txn.GetState(addr, types.BytesToHash([]byte{7})))
+
+	// a synced state has nothing left to download
+	assert.True(t, NewSync(root, target).Done())
+}
+
+func TestSync_RejectInvalidItems(t *testing.T) {
+	source := NewMemoryStorage()
+	root := buildSyncState(t, source)
+
+	sync := NewSync(root, NewMemoryStorage())
+	items := sync.Next(1)
+	assert.Len(t, items, 1)
+
+	assert.ErrorIs(t, sync.Process(items[0], []byte{0xc0}), ErrSyncHashMismatch)
+	assert.ErrorIs(t, sync.Process(SyncItem{Hash: types.StringToHash("1")}, nil), ErrUnrequestedSyncItem)
+
+	// the item is downloaded again once retried
+	sync.Retry(items[0])
+	assert.Equal(t, items, sync.Next(1))
+}
+
+func TestSync_ResumeInterrupted(t *testing.T) {
+	source := NewMemoryStorage()
+	root := buildSyncState(t, source)
+
+	target := NewMemoryStorage()
+	sync := NewSync(root, target)
+
+	// download part of the state, the root is only written once its subtree is
+	for i := 0; i < 5; i++ {
+		for _, item := range sync.Next(8) {
+			var data []byte
+			if item.Code {
+				data, _ = source.GetCode(item.Hash)
+			} else {
+				data, _ = source.Get(item.Hash.Bytes())
+			}
+			assert.NoError(t, sync.Process(item, data))
+		}
+	}
+	sync.Flush()
+	_, ok := target.Get(root.Bytes())
+	assert.False(t, ok)
+
+	// the stored subtrees are not downloaded again
+	resumed := NewSync(root, target)
+	syncFrom(t, resumed, source, 32)
+
+	full := NewSync(root, NewMemoryStorage())
+	syncFrom(t, full, source, 32)
+
+	before, _ := sync.Stats()
+	after, _ := resumed.Stats()
+	total, _ := full.Stats()
+	assert.NotZero(t, before)
+	assert.Equal(t, total, before+after)
+
+	_, err := NewState(target).NewSnapshotAt(root)
+	assert.NoError(t, err)
+}
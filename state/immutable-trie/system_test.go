@@ -0,0 +1,105 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemContracts(t *testing.T) {
+	var (
+		system = types.StringToAddress("1001")
+		sender = types.StringToAddress("1")
+	)
+
+	// the counter increments its first slot, and emits a log
+	counterCode := hex.MustDecodeHex("0x60005460010160005560006000a000")
+	// the constructor sets the first slot to 42, and returns the counter code
+	constructor := append(hex.MustDecodeHex("0x602a600055600f6011600039600f6000f3"), counterCode...)
+	// the upgraded code does nothing
+	upgradedCode := hex.MustDecodeHex("0x00")
+
+	st := NewState(NewMemoryStorage())
+
+	executor := state.NewExecutor(&chain.Params{
+		Forks: chain.AllForksEnabled,
+		Upgrades: []*chain.ContractUpgrade{
+			{
+				Block:   2,
+				Address: system,
+				Code:    upgradedCode,
+				Storage: map[types.Hash]types.Hash{
+					types.StringToHash("1"): types.StringToHash("2"),
+				},
+			},
+		},
+	}, st, hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.Hash{}
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(1000000)},
+		system: {Balance: big.NewInt(10), Constructor: constructor},
+	})
+	assert.NoError(t, err)
+
+	txnAt := func(root types.Hash) *state.Txn {
+		snap, err := st.NewSnapshotAt(root)
+		assert.NoError(t, err)
+
+		return state.NewTxn(st, snap)
+	}
+
+	// the constructor ran at genesis
+	txn := txnAt(root)
+	assert.Equal(t, counterCode, txn.GetCode(system))
+	assert.Equal(t, types.BytesToHash([]byte{42}), txn.GetState(system, types.Hash{}))
+	assert.Equal(t, big.NewInt(10), txn.GetBalance(system))
+
+	block := func(number uint64) {
+		to := system
+		res, err := executor.ProcessBlock(root, &types.Block{
+			Header: &types.Header{Number: number, GasLimit: 1000000},
+			Transactions: []*types.Transaction{
+				{From: sender, Nonce: number - 1, To: &to, Value: big.NewInt(0), Gas: 100000, GasPrice: big.NewInt(0)},
+			},
+		}, sender)
+		assert.NoError(t, err)
+
+		root = res.Root
+	}
+
+	// the counter runs until the upgrade
+	block(1)
+	assert.Equal(t, types.BytesToHash([]byte{43}), txnAt(root).GetState(system, types.Hash{}))
+
+	// the upgraded code keeps the storage
+	block(2)
+	txn = txnAt(root)
+	assert.Equal(t, upgradedCode, txn.GetCode(system))
+	assert.Equal(t, types.BytesToHash([]byte{43}), txn.GetState(system, types.Hash{}))
+	assert.Equal(t, types.StringToHash("2"), txn.GetState(system, types.StringToHash("1")))
+
+	// the code and the constructor are exclusive
+	_, err = executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		system: {Code: counterCode, Constructor: constructor},
+	})
+	assert.Error(t, err)
+
+	// and the failing constructors fail the genesis
+	_, err = executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		system: {Constructor: hex.MustDecodeHex("0xfe")},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceBlock(t *testing.T) {
+	var (
+		sender      = types.StringToAddress("1")
+		caller      = types.StringToAddress("2")
+		reverter    = types.StringToAddress("3")
+		beneficiary = types.StringToAddress("4")
+	)
+
+	// the caller calls the reverter, and then destroys itself
+	callerCode := hex.MustDecodeHex("0x6000600060006000600073" + hex.EncodeToHex(reverter.Bytes())[2:] +
+		"61fffff15073" + hex.EncodeToHex(beneficiary.Bytes())[2:] + "ff")
+	reverterCode := hex.MustDecodeHex("0x60006000fd")
+
+	st := NewState(NewMemoryStorage())
+
+	executor := state.NewExecutor(&chain.Params{Forks: chain.AllForksEnabled}, st, hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.Hash{}
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		sender:   {Balance: big.NewInt(1000)},
+		caller:   {Balance: big.NewInt(7), Code: callerCode},
+		reverter: {Code: reverterCode},
+	})
+	assert.NoError(t, err)
+
+	block := &types.Block{
+		Header: &types.Header{Number: 1, GasLimit: 1000000},
+		Transactions: []*types.Transaction{
+			{
+				From:     sender,
+				To:       &caller,
+				Value:    big.NewInt(1),
+				Gas:      100000,
+				GasPrice: big.NewInt(0),
+			},
+		},
+	}
+
+	traces, err := executor.TraceBlock(root, block, sender)
+	assert.NoError(t, err)
+	assert.Len(t, traces, 1)
+
+	call := traces[0]
+	assert.Equal(t, state.CallTraceCall, call.Type)
+	assert.Equal(t, "call", call.CallType)
+	assert.Equal(t, sender, call.From)
+	assert.Equal(t, caller, call.To)
+	assert.Equal(t, big.NewInt(1), call.Value)
+	assert.NoError(t, call.Err)
+	assert.NotZero(t, call.GasUsed)
+	assert.Len(t, call.Calls, 2)
+
+	reverted := call.Calls[0]
+	assert.Equal(t, state.CallTraceCall, reverted.Type)
+	assert.Equal(t, caller, reverted.From)
+	assert.Equal(t, reverter, reverted.To)
+	assert.Equal(t, runtime.ErrExecutionReverted, reverted.Err)
+
+	suicide := call.Calls[1]
+	assert.Equal(t, state.CallTraceSuicide, suicide.Type)
+	assert.Equal(t, caller, suicide.From)
+	assert.Equal(t, beneficiary, suicide.To)
+	assert.Equal(t, big.NewInt(8), suicide.Value)
+
+	// the state is not modified
+	snap, err := st.NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	_, ok := state.NewTxn(st, snap).GetAccount(beneficiary)
+	assert.False(t, ok)
+}
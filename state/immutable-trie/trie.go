@@ -146,6 +146,8 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 				Nonce:    obj.Nonce,
 				CodeHash: obj.CodeHash.Bytes(),
 				Root:     obj.Root, // old root
+
+				LastTouched: obj.LastTouched,
 			}
 
 			if len(obj.Storage) != 0 {
@@ -164,6 +166,7 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 					} else {
 						vv := ar1.NewBytes(bytes.TrimLeft(entry.Val, "\x00"))
 						localTxn.Insert(k, vv.MarshalTo(nil))
+						batch.Put(preimageKey(k), entry.Key)
 					}
 				}
 
@@ -183,7 +186,9 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 			vv := account.MarshalWith(arena)
 			data := vv.MarshalTo(nil)
 
-			tt.Insert(hashit(obj.Address.Bytes()), data)
+			key := hashit(obj.Address.Bytes())
+			tt.Insert(key, data)
+			batch.Put(preimageKey(key), obj.Address.Bytes())
 			arena.Reset()
 		}
 	}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the state metrics
+type Metrics struct {
+	// No.of accounts touched by the last block
+	WitnessAccounts metrics.Gauge
+	// No.of storage slots read by the last block
+	WitnessStorageReads metrics.Gauge
+	// No.of storage slots written by the last block
+	WitnessStorageWrites metrics.Gauge
+	// No.of trie nodes loaded by the last block
+	WitnessTrieNodes metrics.Gauge
+	// No.of code bytes loaded by the last block
+	WitnessCodeBytes metrics.Gauge
+
+	// No.of transactions applied from their parallel execution
+	ParallelTxs metrics.Counter
+	// No.of transactions executed again after conflicting with the previous transactions of the block
+	ReexecutedTxs metrics.Counter
+}
+
+// GetPrometheusMetrics return the state metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		WitnessAccounts: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "witness_accounts",
+			Help:      "Number of accounts touched by the last block.",
+		}, labels).With(labelsWithValues...),
+		WitnessStorageReads: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "witness_storage_reads",
+			Help:      "Number of storage slots read by the last block.",
+		}, labels).With(labelsWithValues...),
+		WitnessStorageWrites: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "witness_storage_writes",
+			Help:      "Number of storage slots written by the last block.",
+		}, labels).With(labelsWithValues...),
+		WitnessTrieNodes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "witness_trie_nodes",
+			Help:      "Number of trie nodes loaded by the last block.",
+		}, labels).With(labelsWithValues...),
+		WitnessCodeBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "witness_code_bytes",
+			Help:      "Number of contract code bytes loaded by the last block.",
+		}, labels).With(labelsWithValues...),
+		ParallelTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "parallel_txs",
+			Help:      "Number of transactions applied from their parallel execution.",
+		}, labels).With(labelsWithValues...),
+		ReexecutedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "reexecuted_txs",
+			Help:      "Number of transactions executed again after conflicting with the previous transactions of the block.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational state metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		WitnessAccounts:      discard.NewGauge(),
+		WitnessStorageReads:  discard.NewGauge(),
+		WitnessStorageWrites: discard.NewGauge(),
+		WitnessTrieNodes:     discard.NewGauge(),
+		WitnessCodeBytes:     discard.NewGauge(),
+		ParallelTxs:          discard.NewCounter(),
+		ReexecutedTxs:        discard.NewCounter(),
+	}
+}
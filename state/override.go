@@ -0,0 +1,58 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// OverrideAccount is the state of an account replaced for the duration of a call,
+// the nil fields keep the state of the account
+type OverrideAccount struct {
+	Nonce   *uint64
+	Code    []byte
+	Balance *big.Int
+
+	// State replaces the whole storage of the account
+	State map[types.Hash]types.Hash
+
+	// StateDiff replaces the given slots of the storage of the account
+	StateDiff map[types.Hash]types.Hash
+}
+
+// StateOverride is the set of the accounts replaced for the duration of a call
+type StateOverride map[types.Address]OverrideAccount
+
+// ApplyOverride replaces the state of the accounts before the transactions of the transition are applied.
+// The changes are never committed, it is meant for the calls simulated on top of a block
+func (t *Transition) ApplyOverride(override StateOverride) error {
+	for addr, account := range override {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("the override of %s has both state and stateDiff", addr)
+		}
+
+		if account.Nonce != nil {
+			t.state.SetNonce(addr, *account.Nonce)
+		}
+		if account.Balance != nil {
+			t.state.SetBalance(addr, account.Balance)
+		}
+		if account.Code != nil {
+			t.state.SetCode(addr, account.Code)
+		}
+
+		if account.State != nil {
+			t.state.ResetStorage(addr)
+
+			for key, value := range account.State {
+				t.state.SetState(addr, key, value)
+			}
+		}
+		for key, value := range account.StateDiff {
+			t.state.SetState(addr, key, value)
+		}
+	}
+
+	return nil
+}
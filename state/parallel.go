@@ -0,0 +1,180 @@
+package state
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// speculativeTx is the execution of a transaction on top of the state before the transactions of the block.
+// It is valid as long as none of the previous transactions of the block modified an account it touched
+type speculativeTx struct {
+	transition *Transition
+
+	msg    *types.Transaction
+	result *runtime.ExecutionResult
+	logs   []*types.Log
+	err    error
+}
+
+// canWriteParallel checks if the transactions can be executed in parallel.
+// The pre-Byzantium blocks have an intermediate state root per receipt, and the tracers,
+// the post hooks and the state rent expect the transactions to be executed one by one
+func (t *Transition) canWriteParallel(txs []*types.Transaction) bool {
+	if t.r.parallelism <= 1 || len(txs) <= 1 {
+		return false
+	}
+	if !t.config.Byzantium || t.tracer != nil || t.r.PostHook != nil {
+		return false
+	}
+	if _, ok := t.inactivityPeriod(); ok {
+		return false
+	}
+
+	return true
+}
+
+// writeParallel executes the transactions in parallel on top of the current state, then applies their changes
+// in order. The transactions touching an account modified by a previous transaction are executed again,
+// so the result is the same as writing them one by one
+func (t *Transition) writeParallel(txs []*types.Transaction, parallelism int) error {
+	if t.state.witness == nil {
+		// the modified accounts are tracked by the witness
+		t.state.TrackWitness()
+	}
+
+	specs := make([]*speculativeTx, len(txs))
+	for i := range txs {
+		specs[i] = &speculativeTx{transition: t.fork()}
+	}
+
+	jobs := make(chan int, len(txs))
+	for i := range txs {
+		jobs <- i
+	}
+	close(jobs)
+
+	if parallelism > len(txs) {
+		parallelism = len(txs)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				specs[i].run(txs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, tx := range txs {
+		spec := specs[i]
+		if spec.err != nil || spec.msg.Gas > t.gasPool || t.conflicts(spec) {
+			t.r.metrics.ReexecutedTxs.Add(1)
+
+			if err := t.Write(tx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		t.r.metrics.ParallelTxs.Add(1)
+		t.applySpeculative(tx, spec)
+	}
+
+	return nil
+}
+
+// fork returns a transition starting from the current state, for the speculative execution of a transaction.
+// The fee of the coinbase is kept aside, or every transaction would touch the coinbase and the treasury
+func (t *Transition) fork() *Transition {
+	forked := &Transition{
+		logger:      t.logger,
+		auxState:    t.auxState,
+		block:       t.block,
+		r:           t.r,
+		config:      t.config,
+		state:       t.state.fork(),
+		getHash:     t.getHash,
+		ctx:         t.ctx,
+		gasPool:     t.gasPool,
+		baseFee:     t.baseFee,
+		noBaseFee:   t.noBaseFee,
+		receipts:    []*types.Receipt{},
+		deferredFee: new(big.Int),
+	}
+	forked.state.TrackWitness()
+
+	return forked
+}
+
+// conflicts checks if the speculative execution touched an account modified since the start of the block.
+// The coinbase is credited by every transaction
+func (t *Transition) conflicts(spec *speculativeTx) bool {
+	for addr := range spec.transition.state.witness.accounts {
+		if addr == t.ctx.Coinbase {
+			return true
+		}
+		if _, ok := t.state.witness.modified[addr]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applySpeculative applies the changes of the speculative execution of the transaction, as Write would
+func (t *Transition) applySpeculative(tx *types.Transaction, spec *speculativeTx) {
+	forked := spec.transition.state
+	for addr := range forked.witness.modified {
+		if object, ok := forked.txn.Get(addr.Bytes()); ok {
+			t.state.txn.Insert(addr.Bytes(), object)
+		}
+	}
+	t.state.witness.merge(forked.witness)
+
+	// the same accounting as apply, the gas pool was checked by the caller
+	t.gasPool -= spec.msg.Gas
+	t.addGasPool(spec.result.GasLeft)
+	t.payFee(spec.transition.deferredFee)
+
+	t.totalGas += spec.result.GasUsed
+	t.addReceipt(tx, spec.msg, spec.result, spec.logs)
+}
+
+// run executes the transaction on the forked transition
+func (s *speculativeTx) run(tx *types.Transaction) {
+	t := s.transition
+
+	if tx.IsSystemTx() {
+		tx.From = types.SystemAddress
+	} else if tx.From == emptyFrom {
+		signer := crypto.NewSigner(t.config, uint64(t.r.config.ChainID))
+
+		from, err := signer.Sender(tx)
+		if err != nil {
+			s.err = err
+			return
+		}
+		tx.From = from
+	}
+
+	s.msg = tx.Copy()
+
+	s.result, s.err = t.Apply(s.msg)
+	if s.err != nil {
+		return
+	}
+
+	s.logs = t.state.Logs()
+
+	// the suicided accounts are set as deleted, as Write does
+	t.state.CleanDeleteObjects(true)
+}
@@ -0,0 +1,347 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// iterableTrie is implemented by the tries listing their keys in order
+type iterableTrie interface {
+	Iterate(start []byte, handler func(k, v []byte) bool) error
+}
+
+// StorageEntry is a storage slot of an account.
+// The key is only known for the slots written by the replayed transactions
+type StorageEntry struct {
+	Key   *types.Hash
+	Value types.Hash
+}
+
+// StorageRange is a range of the storage of an account, indexed by the hashed keys
+type StorageRange struct {
+	Storage map[types.Hash]StorageEntry
+
+	// NextKey is the hashed key of the slot following the range, if any
+	NextKey *types.Hash
+}
+
+type storageRangeEntry struct {
+	hashedKey types.Hash
+	entry     StorageEntry
+}
+
+// StorageRangeAt returns up to max storage slots of the account, in the order of their hashed keys from the start,
+// in the state after the first txIndex transactions of the block
+func (e *Executor) StorageRangeAt(
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+	txIndex int,
+	addr types.Address,
+	start types.Hash,
+	max int,
+) (*StorageRange, error) {
+	if txIndex < 0 || txIndex > len(block.Transactions) {
+		return nil, fmt.Errorf("transaction index %d out of the %d transactions of the block", txIndex, len(block.Transactions))
+	}
+
+	txn, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range block.Transactions[:txIndex] {
+		if err := txn.Write(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &StorageRange{Storage: map[types.Hash]StorageEntry{}}
+
+	object, ok := txn.state.getStateObject(addr)
+	if !ok || object.Deleted || max <= 0 {
+		return res, nil
+	}
+
+	// the slots written by the transactions override the committed ones, the zero values delete them
+	written := map[types.Hash]*storageRangeEntry{}
+	if object.Txn != nil {
+		object.Txn.Root().Walk(func(k []byte, v interface{}) bool {
+			key := types.BytesToHash(k)
+			entry := &storageRangeEntry{
+				hashedKey: types.BytesToHash(crypto.Keccak256(k)),
+				entry:     StorageEntry{Key: &key},
+			}
+			if v != nil {
+				entry.entry.Value = types.BytesToHash(v.([]byte))
+			}
+			written[entry.hashedKey] = entry
+
+			return false
+		})
+	}
+
+	entries := []*storageRangeEntry{}
+	for _, entry := range written {
+		if entry.entry.Value != zeroHash && bytes.Compare(entry.hashedKey.Bytes(), start.Bytes()) >= 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	// the first max+1 committed slots are enough, the others follow them
+	trie, ok := object.Account.Trie.(iterableTrie)
+	if !ok {
+		return nil, fmt.Errorf("the state can't list the storage of the accounts")
+	}
+
+	committed := 0
+	var decodeErr error
+	err = trie.Iterate(start.Bytes(), func(k, v []byte) bool {
+		hashedKey := types.BytesToHash(k)
+		if _, ok := written[hashedKey]; ok {
+			return true
+		}
+
+		value, err := decodeStorageValue(v)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+		entries = append(entries, &storageRangeEntry{
+			hashedKey: hashedKey,
+			entry:     StorageEntry{Value: value},
+		})
+
+		committed++
+		return committed <= max
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].hashedKey.Bytes(), entries[j].hashedKey.Bytes()) < 0
+	})
+
+	for i, entry := range entries {
+		if i == max {
+			nextKey := entry.hashedKey
+			res.NextKey = &nextKey
+			break
+		}
+		res.Storage[entry.hashedKey] = entry.entry
+	}
+
+	return res, nil
+}
+
+// PreimageReader is implemented by the states keeping the addresses and the storage keys of their hashed trie keys
+type PreimageReader interface {
+	GetPreimage(hash types.Hash) ([]byte, bool)
+}
+
+// DumpAccount is an account of a state dump
+type DumpAccount struct {
+	// Address is the preimage of the hashed key, if known
+	Address  *types.Address
+	Balance  *big.Int
+	Nonce    uint64
+	Root     types.Hash
+	CodeHash types.Hash
+
+	// Code and Storage are only set when requested. The storage is indexed by the slot keys,
+	// or by the hashed keys when their preimages are unknown
+	Code    []byte
+	Storage map[types.Hash]types.Hash
+
+	// UnknownKeys is the number of slots of the storage indexed by their hashed keys
+	UnknownKeys int
+}
+
+// AccountRange is a range of the accounts of a state, indexed by their hashed keys
+type AccountRange struct {
+	Accounts map[types.Hash]*DumpAccount
+
+	// Next is the hashed key of the account following the range, if any
+	Next *types.Hash
+}
+
+// AccountRange returns up to max accounts of the state, in the order of their hashed keys from the start.
+// The codes and the storages of the accounts are only dumped on demand
+func (e *Executor) AccountRange(root, start types.Hash, max int, withCode, withStorage bool) (*AccountRange, error) {
+	snap, err := e.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+	trie, ok := snap.(iterableTrie)
+	if !ok {
+		return nil, fmt.Errorf("the state can't list its accounts")
+	}
+	preimages, _ := e.state.(PreimageReader)
+
+	res := &AccountRange{Accounts: map[types.Hash]*DumpAccount{}}
+	if max <= 0 {
+		return res, nil
+	}
+
+	var iterErr error
+	err = trie.Iterate(start.Bytes(), func(k, v []byte) bool {
+		hashedKey := types.BytesToHash(k)
+		if len(res.Accounts) == max {
+			res.Next = &hashedKey
+			return false
+		}
+
+		var account Account
+		if iterErr = account.UnmarshalRlp(v); iterErr != nil {
+			return false
+		}
+
+		dump := &DumpAccount{
+			Balance:  account.Balance,
+			Nonce:    account.Nonce,
+			Root:     account.Root,
+			CodeHash: types.BytesToHash(account.CodeHash),
+		}
+		if addr, ok := readPreimage(preimages, hashedKey); ok {
+			address := types.BytesToAddress(addr)
+			dump.Address = &address
+		}
+		if withCode && !bytes.Equal(account.CodeHash, emptyCodeHash) {
+			dump.Code, _ = e.state.GetCode(dump.CodeHash)
+		}
+		if withStorage {
+			if dump.Storage, dump.UnknownKeys, iterErr = e.dumpStorage(account.Root, preimages); iterErr != nil {
+				return false
+			}
+		}
+		res.Accounts[hashedKey] = dump
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if iterErr != nil {
+		return nil, iterErr
+	}
+
+	return res, nil
+}
+
+// dumpStorage returns all the slots of the storage trie, and the number of slots whose keys are unknown
+func (e *Executor) dumpStorage(root types.Hash, preimages PreimageReader) (map[types.Hash]types.Hash, int, error) {
+	storage := map[types.Hash]types.Hash{}
+	if root == emptyStateHash {
+		return storage, 0, nil
+	}
+
+	snap, err := e.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, 0, err
+	}
+	trie, ok := snap.(iterableTrie)
+	if !ok {
+		return nil, 0, fmt.Errorf("the state can't list the storage of the accounts")
+	}
+
+	unknown := 0
+	var decodeErr error
+	err = trie.Iterate(nil, func(k, v []byte) bool {
+		value, err := decodeStorageValue(v)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+
+		key := types.BytesToHash(k)
+		if slot, ok := readPreimage(preimages, key); ok {
+			key = types.BytesToHash(slot)
+		} else {
+			unknown++
+		}
+		storage[key] = value
+
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return storage, unknown, decodeErr
+}
+
+func readPreimage(preimages PreimageReader, hash types.Hash) ([]byte, bool) {
+	if preimages == nil {
+		return nil, false
+	}
+
+	return preimages.GetPreimage(hash)
+}
+
+// decodeStorageValue decodes the value of a slot of a storage trie
+func decodeStorageValue(v []byte) (types.Hash, error) {
+	p := stateStateParserPool.Get()
+	defer stateStateParserPool.Put(p)
+
+	value, err := p.Parse(v)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	res, err := value.GetBytes(nil)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return types.BytesToHash(res), nil
+}
+
+// ModifiedAccounts re-executes the block on top of the state of its parent, and returns the accounts
+// whose state differs between the parent and the block, sorted. The resulting state is discarded
+func (e *Executor) ModifiedAccounts(parentRoot types.Hash, block *types.Block, blockCreator types.Address) ([]types.Address, error) {
+	txn, err := e.beginTxn(parentRoot, block.Header, blockCreator, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range block.Transactions {
+		if err := txn.Write(tx); err != nil {
+			return nil, err
+		}
+	}
+	txn.finalize()
+
+	parent, err := e.state.NewSnapshotAt(parentRoot)
+	if err != nil {
+		return nil, err
+	}
+	current, err := e.state.NewSnapshotAt(block.Header.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// the accounts written with the same values are not modified
+	addrs := []types.Address{}
+	for addr := range txn.state.witness.modified {
+		key := crypto.Keccak256(addr.Bytes())
+
+		before, _ := parent.Get(key)
+		after, _ := current.Get(key)
+		if !bytes.Equal(before, after) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+
+	return addrs, nil
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// RemoteAccount is an account of the remote state
+type RemoteAccount struct {
+	Nonce   uint64
+	Balance *big.Int
+	Code    []byte
+}
+
+// RemoteState is the state of a remote chain at a pinned block, a dev chain is forked from.
+// The accounts missing from the local state are read from it, and so are the storage slots
+// missing from the local storage of the accounts
+type RemoteState interface {
+	// GetAccount returns the account, false if it doesn't exist
+	GetAccount(addr types.Address) (*RemoteAccount, bool)
+
+	// GetStorage returns the slot of the storage of the account
+	GetStorage(addr types.Address, key types.Hash) types.Hash
+}
+
+// SetRemoteState sets the remote state the chain is forked from
+func (e *Executor) SetRemoteState(remote RemoteState) {
+	e.remote = remote
+}
+
+// remoteObject returns the state object of the account of the remote state
+func (txn *Txn) remoteObject(addr types.Address) (*StateObject, bool) {
+	account, ok := txn.remote.GetAccount(addr)
+	if !ok {
+		return nil, false
+	}
+
+	obj := &StateObject{
+		Account: &Account{
+			Nonce:    account.Nonce,
+			Balance:  new(big.Int).Set(account.Balance),
+			Trie:     txn.state.NewSnapshot(),
+			CodeHash: emptyCodeHash,
+			Root:     emptyStateHash,
+		},
+	}
+
+	if len(account.Code) != 0 {
+		// the code is written to the local state along with the account, once it changes
+		obj.Account.CodeHash = crypto.Keccak256(account.Code)
+		obj.Code = account.Code
+		obj.DirtyCode = true
+	}
+
+	return obj, true
+}
+
+// committedState returns the slot of the storage of the account before the txn,
+// read from the remote state if it is missing from the local storage
+func (txn *Txn) committedState(addr types.Address, object *StateObject, key types.Hash) types.Hash {
+	k := types.BytesToHash(txn.hashit(key.Bytes()))
+
+	if txn.remote == nil {
+		return object.GetCommitedState(k)
+	}
+
+	if val, ok := object.getCommitedState(k); ok {
+		return val
+	}
+
+	return txn.remote.GetStorage(addr, key)
+}
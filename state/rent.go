@@ -0,0 +1,325 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// StateRentAddress is the system account that keeps the state rent bookkeeping: the accounts scheduled
+// for archival at every period, and the commitments of the archived accounts.
+// The transactions sent to it restore an archived account
+var StateRentAddress = types.StringToAddress("0x0000000000000000000000000000000000001001")
+
+// StateRentRestoreGas is the gas charged, on top of the intrinsic gas, for the restoration of an account
+const StateRentRestoreGas uint64 = 25000
+
+var (
+	ErrAccountNotArchived    = errors.New("the account is not archived")
+	ErrInvalidRestoreProof   = errors.New("the restoration proof does not match the archived account")
+	ErrArchivedAccountInUse  = errors.New("the address of the archived account holds code or storage")
+	ErrRestoreValue          = errors.New("the restoration does not accept value")
+	ErrMalformedRestoreInput = errors.New("malformed restoration input")
+)
+
+// ArchiveStore is the secondary store the archived accounts are copied to,
+// so they can be served to the users restoring them
+type ArchiveStore interface {
+	Put(k, v []byte)
+	Get(k []byte) ([]byte, bool)
+}
+
+// SetArchiveStore sets the store the accounts archived by the state rent are copied to
+func (e *Executor) SetArchiveStore(archive ArchiveStore) {
+	e.archive = archive
+}
+
+// GetArchivedAccount returns the RLP encoding of the latest archived version of the account,
+// which is the restoration proof of the account
+func (e *Executor) GetArchivedAccount(addr types.Address) ([]byte, bool) {
+	if e.archive == nil {
+		return nil, false
+	}
+	return e.archive.Get(addr.Bytes())
+}
+
+// EncodeRestoreInput returns the input of the transaction to the state rent address
+// that restores the archived account
+func EncodeRestoreInput(addr types.Address, account []byte) []byte {
+	ar := &fastrlp.Arena{}
+
+	v := ar.NewArray()
+	v.Set(ar.NewBytes(addr.Bytes()))
+	v.Set(ar.NewBytes(account))
+
+	return v.MarshalTo(nil)
+}
+
+// archivalPeriod returns the period the accounts are scheduled to be archived at
+// if they are modified at the block. It leaves at least one whole period to modify them again
+func archivalPeriod(number, inactivityPeriod uint64) uint64 {
+	return number/inactivityPeriod + 2
+}
+
+func uint64ToHash(n uint64) types.Hash {
+	var h types.Hash
+	binary.BigEndian.PutUint64(h[types.HashLength-8:], n)
+
+	return h
+}
+
+func hashToUint64(h types.Hash) uint64 {
+	return binary.BigEndian.Uint64(h[types.HashLength-8:])
+}
+
+// rentScheduleSlot is the slot of the number of accounts scheduled for archival in the period
+func rentScheduleSlot(period uint64) types.Hash {
+	return types.BytesToHash(crypto.Keccak256([]byte("schedule"), uint64ToHash(period).Bytes()))
+}
+
+// rentScheduleEntrySlot is the slot of an account scheduled for archival in the period
+func rentScheduleEntrySlot(period, index uint64) types.Hash {
+	return types.BytesToHash(
+		crypto.Keccak256([]byte("schedule"), uint64ToHash(period).Bytes(), uint64ToHash(index).Bytes()),
+	)
+}
+
+// rentArchiveSlot is the slot of the commitment of an archived account
+func rentArchiveSlot(addr types.Address) types.Hash {
+	return types.BytesToHash(crypto.Keccak256([]byte("archive"), addr.Bytes()))
+}
+
+// inactivityPeriod returns the state rent inactivity period, if the state rent is active
+func (t *Transition) inactivityPeriod() (uint64, bool) {
+	rent := t.r.config.StateRent
+	if !t.config.StateRent || rent == nil || rent.InactivityPeriod == 0 {
+		return 0, false
+	}
+
+	return rent.InactivityPeriod, true
+}
+
+// setRentState writes a slot of the state rent account.
+// The account gets a nonce so it is never removed as an empty account
+func (t *Transition) setRentState(key, value types.Hash) {
+	if t.state.GetNonce(StateRentAddress) == 0 {
+		t.state.SetNonce(StateRentAddress, 1)
+	}
+	t.state.SetState(StateRentAddress, key, value)
+}
+
+// trackActivity records the current block as the last modification of the accounts modified by the transition,
+// and schedules their archival
+func (t *Transition) trackActivity() {
+	inactivityPeriod, ok := t.inactivityPeriod()
+	if !ok {
+		return
+	}
+
+	number := uint64(t.ctx.Number)
+	period := archivalPeriod(number, inactivityPeriod)
+
+	for _, addr := range t.state.modifiedAccounts() {
+		if addr == StateRentAddress {
+			continue
+		}
+
+		var lastTouched uint64
+		t.state.upsertAccount(addr, false, func(object *StateObject) {
+			lastTouched = object.Account.LastTouched
+			object.Account.LastTouched = number
+		})
+
+		// the accounts are scheduled once per period
+		if lastTouched != 0 && archivalPeriod(lastTouched, inactivityPeriod) == period {
+			continue
+		}
+
+		count := hashToUint64(t.state.GetState(StateRentAddress, rentScheduleSlot(period)))
+		t.setRentState(rentScheduleEntrySlot(period, count), types.BytesToHash(addr.Bytes()))
+		t.setRentState(rentScheduleSlot(period), uint64ToHash(count+1))
+	}
+}
+
+// archiveInactiveAccounts archives the accounts scheduled for the period starting at the current block
+// that were not modified since they were scheduled
+func (t *Transition) archiveInactiveAccounts() {
+	inactivityPeriod, ok := t.inactivityPeriod()
+	if !ok {
+		return
+	}
+
+	number := uint64(t.ctx.Number)
+	if number == 0 || number%inactivityPeriod != 0 {
+		return
+	}
+
+	period := number / inactivityPeriod
+
+	count := hashToUint64(t.state.GetState(StateRentAddress, rentScheduleSlot(period)))
+	if count == 0 {
+		return
+	}
+
+	archived := 0
+	ar := &fastrlp.Arena{}
+
+	for i := uint64(0); i < count; i++ {
+		slot := rentScheduleEntrySlot(period, i)
+		addr := types.BytesToAddress(t.state.GetState(StateRentAddress, slot).Bytes())
+		t.setRentState(slot, zeroHash)
+
+		object, ok := t.state.getStateObject(addr)
+		if !ok || object.Account.LastTouched == 0 {
+			continue
+		}
+		if archivalPeriod(object.Account.LastTouched, inactivityPeriod) != period {
+			// modified since it was scheduled
+			continue
+		}
+
+		data := object.Account.MarshalWith(ar).MarshalTo(nil)
+		ar.Reset()
+
+		t.setRentState(rentArchiveSlot(addr), types.BytesToHash(crypto.Keccak256(data)))
+		t.state.removeAccount(addr)
+
+		if t.r.archive != nil {
+			t.r.archive.Put(addr.Bytes(), data)
+		}
+		archived++
+	}
+
+	t.setRentState(rentScheduleSlot(period), zeroHash)
+
+	t.logger.Debug("archived inactive accounts", "number", number, "scheduled", count, "archived", archived)
+}
+
+// restoreAccount executes a transaction to the state rent address, which restores the archived account
+// included in the input
+func (t *Transition) restoreAccount(input []byte, value *big.Int, gas uint64) *runtime.ExecutionResult {
+	if gas < StateRentRestoreGas {
+		return &runtime.ExecutionResult{
+			GasLeft: 0,
+			Err:     runtime.ErrOutOfGas,
+		}
+	}
+	gasLeft := gas - StateRentRestoreGas
+
+	var err error
+	if value.Sign() != 0 {
+		err = ErrRestoreValue
+	} else {
+		err = t.restoreAccountImpl(input)
+	}
+
+	return &runtime.ExecutionResult{
+		GasLeft: gasLeft,
+		Err:     err,
+	}
+}
+
+func (t *Transition) restoreAccountImpl(input []byte) error {
+	p := &fastrlp.Parser{}
+
+	v, err := p.Parse(input)
+	if err != nil {
+		return ErrMalformedRestoreInput
+	}
+	elems, err := v.GetElems()
+	if err != nil || len(elems) != 2 {
+		return ErrMalformedRestoreInput
+	}
+
+	var addr types.Address
+	if err := elems[0].GetAddr(addr[:]); err != nil {
+		return ErrMalformedRestoreInput
+	}
+	data, err := elems[1].GetBytes(nil)
+	if err != nil {
+		return ErrMalformedRestoreInput
+	}
+
+	// the account must match the commitment written when it was archived
+	commitment := t.state.GetState(StateRentAddress, rentArchiveSlot(addr))
+	if commitment == zeroHash {
+		return ErrAccountNotArchived
+	}
+	if types.BytesToHash(crypto.Keccak256(data)) != commitment {
+		return ErrInvalidRestoreProof
+	}
+
+	var account Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return ErrMalformedRestoreInput
+	}
+
+	if account.Root == emptyStateHash {
+		account.Trie = t.state.state.NewSnapshot()
+	} else {
+		if account.Trie, err = t.state.state.NewSnapshotAt(account.Root); err != nil {
+			return fmt.Errorf("the storage of the archived account is not available: %v", err)
+		}
+	}
+
+	// the address may have been used again since the account was archived,
+	// the balance and the nonce of the new account are merged into the restored one
+	current, exists := t.state.getStateObject(addr)
+	if exists {
+		if !bytes.Equal(current.Account.CodeHash, emptyCodeHash) ||
+			current.Account.Root != emptyStateHash || current.Txn != nil {
+			return ErrArchivedAccountInUse
+		}
+
+		account.Balance.Add(account.Balance, current.Account.Balance)
+		if current.Account.Nonce > account.Nonce {
+			account.Nonce = current.Account.Nonce
+		}
+	}
+
+	t.state.upsertAccount(addr, true, func(object *StateObject) {
+		object.Account = account.Copy()
+		object.Txn = nil
+		object.Code = nil
+		object.DirtyCode = false
+		object.Suicide = false
+	})
+	t.setRentState(rentArchiveSlot(addr), zeroHash)
+
+	return nil
+}
+
+// modifiedAccounts returns the existing accounts modified in the txn
+func (txn *Txn) modifiedAccounts() []types.Address {
+	addrs := []types.Address{}
+
+	txn.txn.Root().Walk(func(k []byte, v interface{}) bool {
+		object, ok := v.(*StateObject)
+		if ok && !object.Deleted {
+			addrs = append(addrs, types.BytesToAddress(k))
+		}
+		return false
+	})
+
+	return addrs
+}
+
+// removeAccount removes the account from the state, without the checks of a self destruct
+func (txn *Txn) removeAccount(addr types.Address) {
+	object, ok := txn.getStateObject(addr)
+	if !ok {
+		return
+	}
+
+	object.Deleted = true
+	txn.witness.modifyAccount(addr)
+	txn.txn.Insert(addr.Bytes(), object)
+}
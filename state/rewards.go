@@ -0,0 +1,53 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+)
+
+// economics returns the block rewards and the distribution of the fees, if active at the current block
+func (t *Transition) economics() (*chain.Economics, bool) {
+	economics := t.r.config.Economics
+	if economics == nil || uint64(t.ctx.Number) < economics.Block {
+		return nil, false
+	}
+
+	return economics, true
+}
+
+// payFee distributes the fee of a transaction between the proposer, the treasury and the burn
+func (t *Transition) payFee(fee *big.Int) {
+	economics, ok := t.economics()
+	if !ok {
+		t.state.AddBalance(t.ctx.Coinbase, fee)
+		return
+	}
+
+	treasuryFee := feeShare(fee, economics.TreasuryShare)
+	burntFee := feeShare(fee, economics.BurnShare)
+
+	if treasuryFee.Sign() > 0 {
+		t.state.AddBalance(economics.Treasury, treasuryFee)
+	}
+
+	proposerFee := new(big.Int).Sub(fee, treasuryFee)
+	proposerFee.Sub(proposerFee, burntFee)
+	t.state.AddBalance(t.ctx.Coinbase, proposerFee)
+}
+
+// mintBlockReward mints the block reward to the proposer of the block
+func (t *Transition) mintBlockReward() {
+	economics, ok := t.economics()
+	if !ok || economics.BlockReward == nil || economics.BlockReward.Sign() == 0 {
+		return
+	}
+
+	t.state.AddBalance(t.ctx.Coinbase, economics.BlockReward)
+}
+
+// feeShare returns the share of the fee, in basis points
+func feeShare(fee *big.Int, share uint64) *big.Int {
+	res := new(big.Int).Mul(fee, new(big.Int).SetUint64(share))
+	return res.Div(res, big.NewInt(chain.MaxShare))
+}
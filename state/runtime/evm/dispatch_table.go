@@ -105,6 +105,7 @@ func init() {
 	register(GASPRICE, handler{opGasPrice, 0, 2})
 	register(RETURNDATASIZE, handler{opReturnDataSize, 0, 2})
 	register(CHAINID, handler{opChainID, 0, 2})
+	register(BASEFEE, handler{opBaseFee, 0, 2})
 	register(PC, handler{opPC, 0, 2})
 	register(MSIZE, handler{opMSize, 0, 2})
 	register(GAS, handler{opGas, 0, 2})
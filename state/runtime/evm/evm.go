@@ -9,6 +9,8 @@ var _ runtime.Runtime = &EVM{}
 
 // EVM is the ethereum virtual machine
 type EVM struct {
+	// profiler aggregates the stats of the profiled executions, if set
+	profiler *Profiler
 }
 
 // NewEVM creates a new EVM
@@ -40,9 +42,17 @@ func (e *EVM) Run(c *runtime.Contract, host runtime.Host, config *chain.ForksInT
 	contract.config = config
 
 	contract.bitmap.setCode(c.Code)
+	contract.profile = e.profiler.startFrame(host)
+	if h, ok := host.(interruptibleHost); ok {
+		contract.interrupt = h.InterruptFlag()
+	}
 
 	ret, err := contract.Run()
 
+	if contract.profile != nil {
+		e.profiler.endFrame(c.CodeAddress, contract.profile)
+	}
+
 	// We are probably doing this append magic to make sure that the slice doesn't have more capacity than it needs
 	var returnValue []byte
 	returnValue = append(returnValue[:0], ret...)
@@ -1,8 +1,11 @@
 package evm
 
 import (
+	"math"
 	"math/big"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/chain"
 	"github.com/0xPolygon/polygon-sdk/state/runtime"
@@ -82,6 +85,14 @@ func (m *mockHost) GetNonce(addr types.Address) uint64 {
 	panic("Not implemented in tests")
 }
 
+func (m *mockHost) AccessAddress(addr types.Address) bool {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) AccessSlot(addr types.Address, key types.Hash) bool {
+	panic("Not implemented in tests")
+}
+
 func TestRun(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -159,3 +170,33 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// mockInterruptibleHost stops the executions once its flag is set
+type mockInterruptibleHost struct {
+	mockHost
+
+	interrupt uint32
+}
+
+func (m *mockInterruptibleHost) InterruptFlag() *uint32 {
+	return &m.interrupt
+}
+
+func TestRunInterrupt(t *testing.T) {
+	// the code loops until it runs out of gas
+	code := []byte{JUMPDEST, PUSH1, 0x00, JUMP}
+	config := &chain.ForksInTime{}
+
+	host := &mockInterruptibleHost{}
+	time.AfterFunc(10*time.Millisecond, func() {
+		atomic.StoreUint32(&host.interrupt, 1)
+	})
+
+	res := NewEVM().Run(newMockContract(big.NewInt(0), math.MaxUint64, code), host, config)
+	assert.Equal(t, runtime.ErrExecutionInterrupted, res.Err)
+	assert.Equal(t, uint64(0), res.GasLeft)
+
+	// the interrupted host stops the next executions too
+	res = NewEVM().Run(newMockContract(big.NewInt(0), 5000, []byte{PUSH1, 0x01}), host, config)
+	assert.Equal(t, runtime.ErrExecutionInterrupted, res.Err)
+}
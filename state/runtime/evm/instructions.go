@@ -444,11 +444,33 @@ func opMStore8(c *state) {
 
 // --- storage ---
 
+// access gas of the state after the Berlin fork (eip-2929)
+const (
+	warmStorageReadGas   uint64 = 100
+	coldSloadGas         uint64 = 2100
+	coldAccountAccessGas uint64 = 2600
+)
+
+// accessAddressGas returns the gas of accessing the address, which is cold
+// the first time it is accessed in the transaction
+func (c *state) accessAddressGas(addr types.Address) uint64 {
+	if c.host.AccessAddress(addr) {
+		return warmStorageReadGas
+	}
+	return coldAccountAccessGas
+}
+
 func opSload(c *state) {
 	loc := c.top()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		gas = coldSloadGas
+		if c.host.AccessSlot(c.msg.Address, bigToHash(loc)) {
+			gas = warmStorageReadGas
+		}
+	} else if c.config.Istanbul {
 		// eip-1884
 		gas = 800
 	} else if c.config.EIP150 {
@@ -480,8 +502,27 @@ func opSStore(c *state) {
 
 	legacyGasMetering := !c.config.Istanbul && (c.config.Petersburg || !c.config.Constantinople)
 
-	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
 	cost := uint64(0)
+	if c.config.Berlin && !c.host.AccessSlot(c.msg.Address, key) {
+		// eip-2929
+		cost = coldSloadGas
+	}
+
+	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
+
+	if c.config.Berlin {
+		// eip-2929, the cost of eip-2200 with the warm read of the slot
+		switch status {
+		case runtime.StorageUnchanged, runtime.StorageModifiedAgain:
+			cost += warmStorageReadGas
+		case runtime.StorageModified, runtime.StorageDeleted:
+			cost += 5000 - coldSloadGas
+		case runtime.StorageAdded:
+			cost += 20000
+		}
+		c.consumeGas(cost)
+		return
+	}
 
 	switch status {
 	case runtime.StorageUnchanged:
@@ -554,7 +595,10 @@ func opBalance(c *state) {
 	addr, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accessAddressGas(addr)
+	} else if c.config.Istanbul {
 		// eip-1884
 		gas = 700
 	} else if c.config.EIP150 {
@@ -588,6 +632,15 @@ func opChainID(c *state) {
 	c.push1().SetUint64(uint64(c.host.GetTxContext().ChainID))
 }
 
+func opBaseFee(c *state) {
+	if !c.config.London {
+		c.exit(errOpCodeNotFound)
+		return
+	}
+
+	c.push1().SetBytes(c.host.GetTxContext().BaseFee.Bytes())
+}
+
 func opOrigin(c *state) {
 	c.push1().SetBytes(c.host.GetTxContext().Origin.Bytes())
 }
@@ -634,7 +687,10 @@ func opExtCodeSize(c *state) {
 	addr, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accessAddressGas(addr)
+	} else if c.config.EIP150 {
 		gas = 700
 	} else {
 		gas = 20
@@ -667,7 +723,10 @@ func opExtCodeHash(c *state) {
 	address, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accessAddressGas(address)
+	} else if c.config.Istanbul {
 		gas = 700
 	} else {
 		gas = 400
@@ -738,7 +797,10 @@ func opExtCodeCopy(c *state) {
 	}
 
 	var gas uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accessAddressGas(address)
+	} else if c.config.EIP150 {
 		gas = 700
 	} else {
 		gas = 20
@@ -888,6 +950,11 @@ func opSelfDestruct(c *state) {
 		}
 	}
 
+	// eip-2929, the beneficiary is charged the cold access
+	if c.config.Berlin && !c.host.AccessAddress(address) {
+		gas += coldAccountAccessGas
+	}
+
 	if !c.consumeGas(gas) {
 		return
 	}
@@ -1033,7 +1100,7 @@ func opCreate(op OpCode) instruction {
 		contract.Type = runtime.Create
 
 		// Correct call
-		result := c.host.Callx(contract, c.host)
+		result := c.callx(contract)
 
 		v := c.push1()
 		if op == CREATE && c.config.Homestead && result.Err == runtime.ErrCodeStoreOutOfGas {
@@ -1104,7 +1171,7 @@ func opCall(op OpCode) instruction {
 
 		contract.Type = callType
 
-		result := c.host.Callx(contract, c.host)
+		result := c.callx(contract)
 
 		v := c.push1()
 		if result.Succeeded() {
@@ -1153,7 +1220,10 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 	}
 
 	var gasCost uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		// eip-2929
+		gasCost = c.accessAddressGas(addr)
+	} else if c.config.EIP150 {
 		gasCost = 700
 	} else {
 		gasCost = 40
@@ -1202,7 +1272,7 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 		return nil, 0, 0, nil
 	}
 	if transfersValue {
-		gas += 2300
+		gas += runtime.CallStipend
 	}
 
 	parent := c
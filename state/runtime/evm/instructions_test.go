@@ -374,3 +374,80 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+type mockHostForAccess struct {
+	mockHost
+	warm map[string]bool
+}
+
+func (m *mockHostForAccess) access(k string) bool {
+	if m.warm[k] {
+		return true
+	}
+	m.warm[k] = true
+	return false
+}
+
+func (m *mockHostForAccess) AccessAddress(addr types.Address) bool {
+	return m.access(addr.String())
+}
+
+func (m *mockHostForAccess) AccessSlot(addr types.Address, key types.Hash) bool {
+	return m.access(addr.String() + key.String())
+}
+
+func (m *mockHostForAccess) GetStorage(types.Address, types.Hash) types.Hash {
+	return types.Hash{}
+}
+
+func (m *mockHostForAccess) GetBalance(types.Address) *big.Int {
+	return big.NewInt(10)
+}
+
+func TestAccessGas(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *chain.ForksInTime
+		op     instruction
+		gas    []uint64
+	}{
+		{
+			name:   "should charge the cold and the warm SLOAD",
+			config: &chain.ForksInTime{Berlin: true, Istanbul: true},
+			op:     opSload,
+			gas:    []uint64{coldSloadGas, warmStorageReadGas},
+		},
+		{
+			name:   "should charge the cold and the warm BALANCE",
+			config: &chain.ForksInTime{Berlin: true, Istanbul: true},
+			op:     opBalance,
+			gas:    []uint64{coldAccountAccessGas, warmStorageReadGas},
+		},
+		{
+			name:   "should charge the SLOAD of eip-1884 before the Berlin fork",
+			config: &chain.ForksInTime{Istanbul: true},
+			op:     opSload,
+			gas:    []uint64{800, 800},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, close := getState()
+			defer close()
+
+			s.msg = &runtime.Contract{Address: addr1}
+			s.config = tt.config
+			s.host = &mockHostForAccess{warm: map[string]bool{}}
+
+			for _, gas := range tt.gas {
+				s.gas = 10000
+				s.push(big.NewInt(2))
+				tt.op(s)
+				s.pop()
+
+				assert.Equal(t, 10000-gas, s.gas)
+			}
+		})
+	}
+}
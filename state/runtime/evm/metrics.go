@@ -0,0 +1,66 @@
+package evm
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the evm profiler metrics
+type Metrics struct {
+	// Gas spent per opcode
+	OpcodeGas metrics.Counter
+	// Seconds spent per opcode
+	OpcodeTime metrics.Counter
+	// Gas spent per contract, excluding its calls to other contracts
+	ContractGas metrics.Counter
+	// Seconds spent per contract, excluding its calls to other contracts
+	ContractTime metrics.Counter
+}
+
+// GetPrometheusMetrics return the evm profiler metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		OpcodeGas: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "evm",
+			Name:      "opcode_gas",
+			Help:      "Gas spent per opcode by the profiled executions.",
+		}, append(labels, "opcode")).With(labelsWithValues...),
+		OpcodeTime: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "evm",
+			Name:      "opcode_seconds",
+			Help:      "Seconds spent per opcode by the profiled executions.",
+		}, append(labels, "opcode")).With(labelsWithValues...),
+		ContractGas: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "evm",
+			Name:      "contract_gas",
+			Help:      "Gas spent per contract by the profiled executions, excluding its calls to other contracts.",
+		}, append(labels, "contract")).With(labelsWithValues...),
+		ContractTime: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "evm",
+			Name:      "contract_seconds",
+			Help:      "Seconds spent per contract by the profiled executions, excluding its calls to other contracts.",
+		}, append(labels, "contract")).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational evm profiler metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		OpcodeGas:    discard.NewCounter(),
+		OpcodeTime:   discard.NewCounter(),
+		ContractGas:  discard.NewCounter(),
+		ContractTime: discard.NewCounter(),
+	}
+}
@@ -0,0 +1,205 @@
+package evm
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// maxProfiledContracts is the number of contracts the profiler keeps the stats of,
+// the calls to the other contracts are only counted as dropped
+const maxProfiledContracts = 10000
+
+// profiledHost is implemented by the hosts that tell whether their executions are profiled,
+// like the transitions of the blocks. The executions of the other hosts are never profiled
+type profiledHost interface {
+	Profiled() bool
+}
+
+// OpProfile are the stats of an opcode
+type OpProfile struct {
+	Op    string
+	Count uint64
+	Gas   uint64
+	Time  time.Duration
+}
+
+// ContractProfile are the stats of the code of a contract.
+// The gas and time of its calls to the other contracts are not included
+type ContractProfile struct {
+	Address types.Address
+	Calls   uint64
+	Gas     uint64
+	Time    time.Duration
+}
+
+// Profile are the stats of the profiler, sorted by gas
+type Profile struct {
+	Since     time.Time
+	Ops       []*OpProfile
+	Contracts []*ContractProfile
+
+	// DroppedCalls is the number of calls to the contracts beyond the ones the profiler keeps
+	DroppedCalls uint64
+}
+
+type opStats struct {
+	count uint64
+	gas   uint64
+	time  time.Duration
+}
+
+// frameProfile are the stats of the opcodes of a single call
+type frameProfile struct {
+	ops [256]opStats
+}
+
+var framePool = sync.Pool{
+	New: func() interface{} {
+		return new(frameProfile)
+	},
+}
+
+// Profiler aggregates the gas and time spent per opcode and per contract by the profiled executions
+type Profiler struct {
+	lock sync.Mutex
+
+	metrics *Metrics
+
+	since        time.Time
+	ops          [256]opStats
+	contracts    map[types.Address]*ContractProfile
+	droppedCalls uint64
+}
+
+// NewProfiler creates a profiler reporting to the metrics
+func NewProfiler(metrics *Metrics) *Profiler {
+	return &Profiler{
+		metrics:   metrics,
+		since:     time.Now(),
+		contracts: map[types.Address]*ContractProfile{},
+	}
+}
+
+// SetProfiler sets the profiler of the executions of the hosts which are profiled
+func (e *EVM) SetProfiler(profiler *Profiler) {
+	e.profiler = profiler
+}
+
+// startFrame returns the profile of the call, if it is profiled
+func (p *Profiler) startFrame(host runtime.Host) *frameProfile {
+	if p == nil {
+		return nil
+	}
+	if h, ok := host.(profiledHost); !ok || !h.Profiled() {
+		return nil
+	}
+
+	return framePool.Get().(*frameProfile)
+}
+
+// endFrame adds the profile of the call of the contract to the stats
+func (p *Profiler) endFrame(addr types.Address, frame *frameProfile) {
+	gas, elapsed := uint64(0), time.Duration(0)
+
+	p.lock.Lock()
+	for op, stats := range frame.ops {
+		if stats.count == 0 {
+			continue
+		}
+
+		p.ops[op].count += stats.count
+		p.ops[op].gas += stats.gas
+		p.ops[op].time += stats.time
+
+		name := OpCode(op).String()
+		p.metrics.OpcodeGas.With("opcode", name).Add(float64(stats.gas))
+		p.metrics.OpcodeTime.With("opcode", name).Add(stats.time.Seconds())
+
+		gas += stats.gas
+		elapsed += stats.time
+	}
+
+	contract, ok := p.contracts[addr]
+	if !ok && len(p.contracts) < maxProfiledContracts {
+		contract = &ContractProfile{Address: addr}
+		p.contracts[addr] = contract
+	}
+	if contract != nil {
+		contract.Calls++
+		contract.Gas += gas
+		contract.Time += elapsed
+
+		p.metrics.ContractGas.With("contract", addr.String()).Add(float64(gas))
+		p.metrics.ContractTime.With("contract", addr.String()).Add(elapsed.Seconds())
+	} else {
+		p.droppedCalls++
+	}
+	p.lock.Unlock()
+
+	frame.ops = [256]opStats{}
+	framePool.Put(frame)
+}
+
+// Profile returns the stats of the opcodes and of the contracts with the most gas, up to the limit if positive.
+// With reset, the stats are cleared once returned, the metrics keep counting
+func (p *Profiler) Profile(limit int, reset bool) *Profile {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	profile := &Profile{
+		Since:        p.since,
+		Ops:          []*OpProfile{},
+		Contracts:    []*ContractProfile{},
+		DroppedCalls: p.droppedCalls,
+	}
+
+	for op, stats := range p.ops {
+		if stats.count == 0 {
+			continue
+		}
+		profile.Ops = append(profile.Ops, &OpProfile{
+			Op:    OpCode(op).String(),
+			Count: stats.count,
+			Gas:   stats.gas,
+			Time:  stats.time,
+		})
+	}
+	sort.SliceStable(profile.Ops, func(i, j int) bool {
+		return profile.Ops[i].Gas > profile.Ops[j].Gas
+	})
+
+	for _, contract := range p.contracts {
+		c := *contract
+		profile.Contracts = append(profile.Contracts, &c)
+	}
+	sort.Slice(profile.Contracts, func(i, j int) bool {
+		a, b := profile.Contracts[i], profile.Contracts[j]
+		if a.Gas != b.Gas {
+			return a.Gas > b.Gas
+		}
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes()) < 0
+	})
+
+	if limit > 0 {
+		if len(profile.Ops) > limit {
+			profile.Ops = profile.Ops[:limit]
+		}
+		if len(profile.Contracts) > limit {
+			profile.Contracts = profile.Contracts[:limit]
+		}
+	}
+
+	if reset {
+		p.since = time.Now()
+		p.ops = [256]opStats{}
+		p.contracts = map[types.Address]*ContractProfile{}
+		p.droppedCalls = 0
+	}
+
+	return profile
+}
@@ -0,0 +1,65 @@
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// profiledMockHost is a mock host whose executions are profiled
+type profiledMockHost struct {
+	mockHost
+	profiled bool
+}
+
+func (m *profiledMockHost) Profiled() bool {
+	return m.profiled
+}
+
+func TestProfiler(t *testing.T) {
+	// PUSH1 1, PUSH1 2, ADD, POP, STOP
+	code := hex.MustDecodeHex("0x600160020150" + "00")
+	addr := types.StringToAddress("1")
+
+	profiler := NewProfiler(NilMetrics())
+
+	evm := NewEVM()
+	evm.SetProfiler(profiler)
+
+	run := func(host *profiledMockHost) {
+		contract := newMockContract(big.NewInt(0), 100000, code)
+		contract.CodeAddress = addr
+
+		res := evm.Run(contract, host, &chain.ForksInTime{})
+		assert.NoError(t, res.Err)
+	}
+
+	// the executions of the other hosts are not profiled
+	run(&profiledMockHost{})
+	assert.Empty(t, profiler.Profile(0, false).Ops)
+
+	run(&profiledMockHost{profiled: true})
+	run(&profiledMockHost{profiled: true})
+
+	profile := profiler.Profile(2, true)
+
+	// sorted by gas, up to the limit
+	assert.Len(t, profile.Ops, 2)
+	assert.Equal(t, "PUSH1", profile.Ops[0].Op)
+	assert.Equal(t, uint64(4), profile.Ops[0].Count)
+	assert.Equal(t, uint64(12), profile.Ops[0].Gas)
+	assert.Equal(t, "ADD", profile.Ops[1].Op)
+	assert.Equal(t, uint64(6), profile.Ops[1].Gas)
+
+	assert.Len(t, profile.Contracts, 1)
+	assert.Equal(t, addr, profile.Contracts[0].Address)
+	assert.Equal(t, uint64(2), profile.Contracts[0].Calls)
+	assert.Equal(t, uint64(2*(3+3+3+2)), profile.Contracts[0].Gas)
+
+	// the stats were reset
+	assert.Empty(t, profiler.Profile(0, false).Contracts)
+}
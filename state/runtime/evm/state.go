@@ -4,8 +4,9 @@ import (
 	"errors"
 	"math/big"
 	"strings"
-
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/chain"
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
@@ -35,6 +36,7 @@ var (
 	errStackUnderflow        = runtime.ErrStackUnderflow
 	errStackOverflow         = runtime.ErrStackOverflow
 	errRevert                = runtime.ErrExecutionReverted
+	errInterrupted           = runtime.ErrExecutionInterrupted
 	errGasUintOverflow       = errors.New("gas uint64 overflow")
 	errWriteProtection       = errors.New("write protection")
 	errInvalidJump           = errors.New("invalid jump destination")
@@ -74,6 +76,21 @@ type state struct {
 
 	returnData []byte
 	ret        []byte
+
+	// profile are the stats of the opcodes of the call, if profiled.
+	// The gas and time of the nested calls are kept aside, they are not part of the opcodes of the call
+	profile   *frameProfile
+	childGas  uint64
+	childTime time.Duration
+
+	// interrupt stops the execution once set, nil if the host can't interrupt it
+	interrupt *uint32
+}
+
+// interruptibleHost is implemented by the hosts whose executions can be interrupted,
+// like the calls with a time limit. The executions stop at the next instruction once the flag is set
+type interruptibleHost interface {
+	InterruptFlag() *uint32
 }
 
 func (c *state) reset() {
@@ -83,6 +100,8 @@ func (c *state) reset() {
 	c.lastGasCost = 0
 	c.stop = false
 	c.err = nil
+	c.profile = nil
+	c.interrupt = nil
 
 	// reset bitmap
 	c.bitmap.reset()
@@ -204,6 +223,11 @@ func (c *state) Run() ([]byte, error) {
 			break
 		}
 
+		if c.interrupt != nil && atomic.LoadUint32(c.interrupt) != 0 {
+			c.exit(errInterrupted)
+			break
+		}
+
 		op := OpCode(c.code[c.ip])
 
 		inst := dispatchTable[op]
@@ -216,6 +240,12 @@ func (c *state) Run() ([]byte, error) {
 			c.exit(errStackUnderflow)
 			break
 		}
+		gasBefore, start := c.gas, time.Time{}
+		if c.profile != nil {
+			start = time.Now()
+			c.childGas, c.childTime = 0, 0
+		}
+
 		// consume the gas of the instruction
 		if !c.consumeGas(inst.gas) {
 			c.exit(errOutOfGas)
@@ -225,6 +255,10 @@ func (c *state) Run() ([]byte, error) {
 		// execute the instruction
 		inst.inst(c)
 
+		if c.profile != nil {
+			c.recordOp(op, gasBefore, start)
+		}
+
 		// check if stack size exceeds the max size
 		if c.sp > stackSize {
 			c.exit(errStackOverflow)
@@ -239,6 +273,42 @@ func (c *state) Run() ([]byte, error) {
 	return c.ret, vmerr
 }
 
+// recordOp adds the gas and time spent by the instruction to the profile of the call
+func (c *state) recordOp(op OpCode, gasBefore uint64, start time.Time) {
+	// the nested calls may return more gas than the instruction was charged, with the call stipend
+	gas := uint64(0)
+	if gasBefore > c.gas && gasBefore-c.gas > c.childGas {
+		gas = gasBefore - c.gas - c.childGas
+	}
+
+	elapsed := time.Since(start) - c.childTime
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	stats := &c.profile.ops[op]
+	stats.count++
+	stats.gas += gas
+	stats.time += elapsed
+}
+
+// callx runs the nested call, and keeps aside the gas and time it spent for the profile of the call
+func (c *state) callx(contract *runtime.Contract) *runtime.ExecutionResult {
+	if c.profile == nil {
+		return c.host.Callx(contract, c.host)
+	}
+
+	start := time.Now()
+	result := c.host.Callx(contract, c.host)
+
+	c.childTime += time.Since(start)
+	if contract.Gas > result.GasLeft {
+		c.childGas += contract.Gas - result.GasLeft
+	}
+
+	return result
+}
+
 func (c *state) inStaticCall() bool {
 	return c.msg.Static
 }
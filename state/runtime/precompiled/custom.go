@@ -0,0 +1,127 @@
+package precompiled
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// Contract is a custom precompiled contract, like the BLS12-381 operations or a zk proof verifier.
+// Run returns the output of the call, or the error failing it. It is called concurrently
+type Contract interface {
+	Run(input []byte) ([]byte, error)
+}
+
+// ContractFunc adapts a function to the Contract interface
+type ContractFunc func(input []byte) ([]byte, error)
+
+// Run implements the Contract interface
+func (f ContractFunc) Run(input []byte) ([]byte, error) {
+	return f(input)
+}
+
+var (
+	registryLock sync.Mutex
+	registry     = map[string]Contract{}
+)
+
+// Register makes the custom precompiled contract available to the chains placing it in their params.
+// It is meant to be called from the init function of the package of the contract, and panics
+// if the name is already taken
+func Register(name string, contract Contract) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if contract == nil {
+		panic("precompiled: registered contract is nil")
+	}
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("precompiled: contract %s registered twice", name))
+	}
+
+	registry[name] = contract
+}
+
+// Registered returns the sorted names of the registered custom precompiled contracts
+func Registered() []string {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupContract(name string) (Contract, bool) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	contract, ok := registry[name]
+
+	return contract, ok
+}
+
+// custom is a custom precompiled contract placed by the chain params
+type custom struct {
+	contract Contract
+	config   *chain.Precompile
+}
+
+func (c *custom) gas(input []byte, config *chain.ForksInTime) uint64 {
+	return baseGasCalc(input, c.config.BaseGas, c.config.WordGas)
+}
+
+func (c *custom) run(input []byte) ([]byte, error) {
+	return c.contract.Run(input)
+}
+
+// AddCustom places the custom precompiled contracts of the chain params. The contracts must be registered,
+// and their addresses can't be the ones of the built-in contracts
+func (p *Precompiled) AddCustom(precompiles []*chain.Precompile) error {
+	for _, config := range precompiles {
+		contract, ok := lookupContract(config.Name)
+		if !ok {
+			return fmt.Errorf("precompiled contract %s is not registered in the node, registered: %v", config.Name, Registered())
+		}
+
+		if _, ok := p.contracts[config.Address]; ok {
+			return fmt.Errorf("address %s of precompiled contract %s is already taken", config.Address, config.Name)
+		}
+
+		p.contracts[config.Address] = &custom{contract: contract, config: config}
+		p.custom = append(p.custom, config)
+	}
+
+	return nil
+}
+
+// customConfig returns the params of the custom precompiled contract at the address, if any
+func (p *Precompiled) customConfig(addr types.Address) (*chain.Precompile, bool) {
+	for _, config := range p.custom {
+		if config.Address == addr {
+			return config, true
+		}
+	}
+
+	return nil, false
+}
+
+// WarmAddresses returns the addresses of the custom precompiled contracts active at the block,
+// which are warm from the start of the transactions like the built-in ones (EIP-2929)
+func (p *Precompiled) WarmAddresses(number uint64) []types.Address {
+	addrs := []types.Address{}
+	for _, config := range p.custom {
+		if number >= config.Block {
+			addrs = append(addrs, config.Address)
+		}
+	}
+
+	return addrs
+}
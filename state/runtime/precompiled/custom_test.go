@@ -0,0 +1,86 @@
+package precompiled
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockHost is a host only serving the number of the block
+type blockHost struct {
+	runtime.Host
+	number int64
+}
+
+func (h *blockHost) GetTxContext() runtime.TxContext {
+	return runtime.TxContext{Number: h.number}
+}
+
+var errOddInput = errors.New("odd input")
+
+func init() {
+	// doubles the bytes of the even inputs
+	Register("test-double", ContractFunc(func(input []byte) ([]byte, error) {
+		if len(input)%2 != 0 {
+			return nil, errOddInput
+		}
+		return append(append([]byte{}, input...), input...), nil
+	}))
+}
+
+func TestCustomPrecompile(t *testing.T) {
+	addr := types.StringToAddress("100")
+
+	p := NewPrecompiled()
+	assert.NoError(t, p.AddCustom([]*chain.Precompile{
+		{Name: "test-double", Address: addr, Block: 10, BaseGas: 100, WordGas: 10},
+	}))
+
+	config := chain.AllForksEnabled.At(0)
+	contract := &runtime.Contract{CodeAddress: addr, Input: []byte{1, 2}, Gas: 1000}
+
+	// active from the block of the params
+	assert.False(t, p.CanRun(contract, &blockHost{number: 9}, &config))
+	assert.True(t, p.CanRun(contract, &blockHost{number: 10}, &config))
+	assert.Empty(t, p.WarmAddresses(9))
+	assert.Equal(t, []types.Address{addr}, p.WarmAddresses(10))
+
+	// the gas of the params is charged, for one word of input
+	res := p.Run(contract, &blockHost{number: 10}, &config)
+	assert.NoError(t, res.Err)
+	assert.Equal(t, []byte{1, 2, 1, 2}, res.ReturnValue)
+	assert.Equal(t, uint64(1000-110), res.GasLeft)
+
+	// the failed calls consume all the gas
+	contract = &runtime.Contract{CodeAddress: addr, Input: []byte{1}, Gas: 1000}
+	res = p.Run(contract, &blockHost{number: 10}, &config)
+	assert.Equal(t, errOddInput, res.Err)
+	assert.Zero(t, res.GasLeft)
+}
+
+func TestCustomPrecompile_Invalid(t *testing.T) {
+	assert.Contains(t, Registered(), "test-double")
+
+	// the contract must be registered
+	p := NewPrecompiled()
+	assert.Error(t, p.AddCustom([]*chain.Precompile{
+		{Name: "unknown", Address: types.StringToAddress("100")},
+	}))
+
+	// and can't replace a built-in contract
+	p = NewPrecompiled()
+	assert.Error(t, p.AddCustom([]*chain.Precompile{
+		{Name: "test-double", Address: types.StringToAddress("1")},
+	}))
+
+	// the names are unique
+	assert.Panics(t, func() {
+		Register("test-double", ContractFunc(func(input []byte) ([]byte, error) {
+			return nil, nil
+		}))
+	})
+}
@@ -17,8 +17,10 @@ type contract interface {
 
 // Precompiled is the runtime for the precompiled contracts
 type Precompiled struct {
-	buf       []byte
 	contracts map[types.Address]contract
+
+	// custom are the custom contracts placed by the chain params
+	custom []*chain.Precompile
 }
 
 // NewPrecompiled creates a new runtime for the precompiled contracts
@@ -60,13 +62,20 @@ var (
 )
 
 // CanRun implements the runtime interface
-func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.ForksInTime) bool {
+func (p *Precompiled) CanRun(c *runtime.Contract, host runtime.Host, config *chain.ForksInTime) bool {
 	if _, ok := p.contracts[c.CodeAddress]; !ok {
 		return false
 	}
+	if config, ok := p.customConfig(c.CodeAddress); ok {
+		return uint64(host.GetTxContext().Number) >= config.Block
+	}
+	return isActive(c.CodeAddress, config)
+}
 
+// isActive checks if the fork of the precompiled contract at the address is active
+func isActive(addr types.Address, config *chain.ForksInTime) bool {
 	// byzantium precompiles
-	switch c.CodeAddress {
+	switch addr {
 	case five:
 		fallthrough
 	case six:
@@ -78,7 +87,7 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 	}
 
 	// istanbul precompiles
-	switch c.CodeAddress {
+	switch addr {
 	case nine:
 		return config.Istanbul
 	}
@@ -86,6 +95,18 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 	return true
 }
 
+// ActiveAddresses returns the addresses of the precompiled contracts active in the fork
+func ActiveAddresses(config *chain.ForksInTime) []types.Address {
+	addrs := []types.Address{}
+	for i := 1; i <= 9; i++ {
+		addr := types.BytesToAddress([]byte{byte(i)})
+		if isActive(addr, config) {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 // Name implements the runtime interface
 func (p *Precompiled) Name() string {
 	return "precompiled"
@@ -121,8 +142,6 @@ func (p *Precompiled) Run(c *runtime.Contract, _ runtime.Host, config *chain.For
 	return result
 }
 
-var zeroPadding = make([]byte, 64)
-
 func (p *Precompiled) leftPad(buf []byte, n int) []byte {
 	// TODO, avoid buffer allocation
 	l := len(buf)
@@ -135,38 +154,22 @@ func (p *Precompiled) leftPad(buf []byte, n int) []byte {
 	return tmp
 }
 
+// get returns the first size bytes of the input, right padded with zeros, and the rest of the input.
+// The bytes are copied to a new buffer, as the runtime is shared by the transactions executed in parallel
 func (p *Precompiled) get(input []byte, size int) ([]byte, []byte) {
-	p.buf = extendByteSlice(p.buf, size)
+	buf := make([]byte, size)
+
 	n := size
 	if len(input) < n {
 		n = len(input)
 	}
+	copy(buf, input[:n])
 
-	// copy the part from the input
-	copy(p.buf[0:], input[:n])
-
-	// copy empty values
-	if n < size {
-		rest := size - n
-		if rest < 64 {
-			copy(p.buf[n:], zeroPadding[0:size-n])
-		} else {
-			copy(p.buf[n:], make([]byte, rest))
-		}
-	}
-	return p.buf, input[n:]
+	return buf, input[n:]
 }
 
 func (p *Precompiled) getUint64(input []byte) (uint64, []byte) {
-	p.buf, input = p.get(input, 32)
-	num := binary.BigEndian.Uint64(p.buf[24:32])
+	buf, input := p.get(input, 32)
+	num := binary.BigEndian.Uint64(buf[24:32])
 	return num, input
 }
-
-func extendByteSlice(b []byte, needLen int) []byte {
-	b = b[:cap(b)]
-	if n := needLen - cap(b); n > 0 {
-		b = append(b, make([]byte, n)...)
-	}
-	return b[:needLen]
-}
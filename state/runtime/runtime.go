@@ -18,6 +18,7 @@ type TxContext struct {
 	GasLimit   int64
 	ChainID    int64
 	Difficulty types.Hash
+	BaseFee    types.Hash
 }
 
 // StorageStatus is the status of the storage access
@@ -69,6 +70,8 @@ type Host interface {
 	Callx(*Contract, Host) *ExecutionResult
 	Empty(addr types.Address) bool
 	GetNonce(addr types.Address) uint64
+	AccessAddress(addr types.Address) bool
+	AccessSlot(addr types.Address, key types.Hash) bool
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -77,6 +80,7 @@ type ExecutionResult struct {
 	ReturnValue []byte // Returned data from the runtime (function result or data supplied with revert opcode)
 	GasLeft     uint64 // Total gas left as result of execution
 	GasUsed     uint64 // Total gas used as result of execution
+	GasRefunded uint64 // Gas refunded at the end of the execution, part of the gas left
 	Err         error  // Any error encountered during the execution, listed below
 }
 
@@ -84,17 +88,29 @@ func (r *ExecutionResult) Succeeded() bool { return r.Err == nil }
 func (r *ExecutionResult) Failed() bool    { return r.Err != nil }
 func (r *ExecutionResult) Reverted() bool  { return r.Err == ErrExecutionReverted }
 
-func (r *ExecutionResult) UpdateGasUsed(gasLimit uint64, refund uint64) {
+const (
+	// RefundQuotient bounds the refund to half the gas used
+	RefundQuotient uint64 = 2
+
+	// RefundQuotientEIP3529 bounds the refund to a fifth of the gas used, after the London fork
+	RefundQuotientEIP3529 uint64 = 5
+
+	// CallStipend is the gas the callee of a call with value receives on top of the gas of the call
+	CallStipend uint64 = 2300
+)
+
+func (r *ExecutionResult) UpdateGasUsed(gasLimit uint64, refund uint64, refundQuotient uint64) {
 	r.GasUsed = gasLimit - r.GasLeft
 
-	// Refund can go up to half the gas used
-	maxRefund := r.GasUsed / 2
+	// Refund can go up to a fraction of the gas used
+	maxRefund := r.GasUsed / refundQuotient
 	if refund > maxRefund {
 		refund = maxRefund
 	}
 
 	r.GasLeft += refund
 	r.GasUsed -= refund
+	r.GasRefunded = refund
 }
 
 var (
@@ -108,6 +124,8 @@ var (
 	ErrDepth                    = errors.New("max call depth exceeded")
 	ErrExecutionReverted        = errors.New("execution was reverted")
 	ErrCodeStoreOutOfGas        = errors.New("contract creation code storage out of gas")
+	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
+	ErrExecutionInterrupted     = errors.New("execution interrupted")
 )
 
 type CallType int
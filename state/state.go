@@ -35,6 +35,10 @@ type Account struct {
 	Root     types.Hash
 	CodeHash []byte
 	Trie     accountTrie
+
+	// LastTouched is the last block the account was modified in, once the state rent is active.
+	// It is only encoded when set, so the accounts of the chains without state rent are not affected
+	LastTouched uint64
 }
 
 func (a *Account) MarshalWith(ar *fastrlp.Arena) *fastrlp.Value {
@@ -43,6 +47,9 @@ func (a *Account) MarshalWith(ar *fastrlp.Arena) *fastrlp.Value {
 	v.Set(ar.NewBigInt(a.Balance))
 	v.Set(ar.NewBytes(a.Root.Bytes()))
 	v.Set(ar.NewBytes(a.CodeHash))
+	if a.LastTouched != 0 {
+		v.Set(ar.NewUint(a.LastTouched))
+	}
 	return v
 }
 
@@ -60,7 +67,7 @@ func (a *Account) UnmarshalRlp(b []byte) error {
 	if err != nil {
 		return err
 	}
-	if len(elems) != 4 {
+	if len(elems) != 4 && len(elems) != 5 {
 		return fmt.Errorf("bad")
 	}
 
@@ -83,6 +90,12 @@ func (a *Account) UnmarshalRlp(b []byte) error {
 	if a.CodeHash, err = elems[3].GetBytes(a.CodeHash[:0]); err != nil {
 		return err
 	}
+	// last touched
+	if len(elems) == 5 {
+		if a.LastTouched, err = elems[4].GetUint64(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -98,6 +111,7 @@ func (a *Account) Copy() *Account {
 	aa.CodeHash = a.CodeHash
 	aa.Root = a.Root
 	aa.Trie = a.Trie
+	aa.LastTouched = a.LastTouched
 
 	return aa
 }
@@ -121,9 +135,16 @@ func (s *StateObject) Empty() bool {
 var stateStateParserPool fastrlp.ParserPool
 
 func (s *StateObject) GetCommitedState(key types.Hash) types.Hash {
+	val, _ := s.getCommitedState(key)
+
+	return val
+}
+
+// getCommitedState returns the slot of the storage trie of the account, false if it is not in the trie
+func (s *StateObject) getCommitedState(key types.Hash) (types.Hash, bool) {
 	val, ok := s.Account.Trie.Get(key.Bytes())
 	if !ok {
-		return types.Hash{}
+		return types.Hash{}, false
 	}
 
 	p := stateStateParserPool.Get()
@@ -131,15 +152,15 @@ func (s *StateObject) GetCommitedState(key types.Hash) types.Hash {
 
 	v, err := p.Parse(val)
 	if err != nil {
-		return types.Hash{}
+		return types.Hash{}, false
 	}
 
 	res := []byte{}
 	if res, err = v.GetBytes(res[:0]); err != nil {
-		return types.Hash{}
+		return types.Hash{}, false
 	}
 
-	return types.BytesToHash(res)
+	return types.BytesToHash(res), true
 }
 
 // Copy makes a copy of the state object
@@ -170,6 +191,8 @@ type Object struct {
 	Nonce    uint64
 	Deleted  bool
 
+	LastTouched uint64
+
 	// TODO: Move this to executor
 	DirtyCode bool
 	Code      []byte
@@ -0,0 +1,86 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// genesisConstructorGas is the gas of the constructors run at genesis, they are not metered
+const genesisConstructorGas = 1 << 62
+
+// runGenesisConstructors runs the constructors of the genesis accounts in the order of their addresses,
+// so the constructors reading the other accounts get the same state on every node
+func (e *Executor) runGenesisConstructors(txn *Txn, alloc map[types.Address]*chain.GenesisAccount) error {
+	addrs := []types.Address{}
+	for addr, account := range alloc {
+		if len(account.Constructor) == 0 {
+			continue
+		}
+		if len(account.Code) != 0 {
+			return fmt.Errorf("genesis account %s has both code and a constructor", addr)
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+
+	t := &Transition{
+		logger:   e.logger,
+		r:        e,
+		state:    txn,
+		auxState: e.state,
+		config:   e.config.Forks.At(0),
+		ctx: runtime.TxContext{
+			GasLimit: genesisConstructorGas,
+			ChainID:  int64(e.config.ChainID),
+		},
+		getHash: func(uint64) types.Hash {
+			return types.Hash{}
+		},
+		receipts: []*types.Receipt{},
+	}
+
+	for _, addr := range addrs {
+		contract := runtime.NewContractCreation(1, types.ZeroAddress, types.ZeroAddress, addr, big.NewInt(0), genesisConstructorGas, alloc[addr].Constructor)
+
+		result := t.run(contract, t)
+		if result.Failed() {
+			return fmt.Errorf("constructor of genesis account %s failed: %v", addr, result.Err)
+		}
+		txn.SetCode(addr, result.ReturnValue)
+	}
+
+	// the logs of the constructors are not part of any receipt
+	txn.Logs()
+	txn.CleanDeleteObjects(false)
+
+	return nil
+}
+
+// upgradeContracts replaces the code of the system contracts upgraded at the current block
+func (t *Transition) upgradeContracts() {
+	number := uint64(t.ctx.Number)
+
+	for _, upgrade := range t.r.config.Upgrades {
+		if upgrade.Block != number {
+			continue
+		}
+
+		t.state.SetCode(upgrade.Address, upgrade.Code)
+		for key, value := range upgrade.Storage {
+			t.state.SetState(upgrade.Address, key, value)
+		}
+		t.logger.Info("system contract upgraded", "address", upgrade.Address, "block", number)
+	}
+}
@@ -0,0 +1,223 @@
+package state
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// CallTraceType is the kind of a frame of a transaction trace
+type CallTraceType string
+
+const (
+	CallTraceCall    CallTraceType = "call"
+	CallTraceCreate  CallTraceType = "create"
+	CallTraceSuicide CallTraceType = "suicide"
+)
+
+// CallTrace is a frame of the execution of a transaction:
+// a message call, a contract creation or a self destruct, with the frames it started
+type CallTrace struct {
+	Type CallTraceType
+
+	// CallType is the opcode of a message call: call, callcode, delegatecall or staticcall
+	CallType string
+
+	// From is the caller, or the destroyed contract of a self destruct
+	From types.Address
+
+	// To is the callee, the created contract, or the beneficiary of a self destruct
+	To types.Address
+
+	// Value is the transferred value, or the balance of the destroyed contract
+	Value *big.Int
+
+	Gas     uint64
+	GasUsed uint64
+
+	// Input is the call data, or the init code of a creation
+	Input []byte
+
+	// Output is the return data, or the code of the created contract
+	Output []byte
+
+	Err error
+
+	Calls []*CallTrace
+}
+
+// callTraceJSON is the JSON encoding of a frame, with hex encoded numbers and data
+type callTraceJSON struct {
+	Type     CallTraceType `json:"type"`
+	CallType string        `json:"callType,omitempty"`
+	From     types.Address `json:"from"`
+	To       types.Address `json:"to"`
+	Value    string        `json:"value"`
+	Gas      string        `json:"gas"`
+	GasUsed  string        `json:"gasUsed"`
+	Input    string        `json:"input"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Calls    []*CallTrace  `json:"calls,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (c *CallTrace) MarshalJSON() ([]byte, error) {
+	frame := &callTraceJSON{
+		Type:     c.Type,
+		CallType: c.CallType,
+		From:     c.From,
+		To:       c.To,
+		Value:    "0x0",
+		Gas:      hex.EncodeUint64(c.Gas),
+		GasUsed:  hex.EncodeUint64(c.GasUsed),
+		Input:    hex.EncodeToHex(c.Input),
+		Calls:    c.Calls,
+	}
+	if c.Value != nil {
+		frame.Value = hex.EncodeBig(c.Value)
+	}
+	if len(c.Output) != 0 {
+		frame.Output = hex.EncodeToHex(c.Output)
+	}
+	if c.Err != nil {
+		frame.Error = c.Err.Error()
+	}
+
+	return json.Marshal(frame)
+}
+
+var callTypeNames = map[runtime.CallType]string{
+	runtime.Call:         "call",
+	runtime.CallCode:     "callcode",
+	runtime.DelegateCall: "delegatecall",
+	runtime.StaticCall:   "staticcall",
+}
+
+// CallTracer records the frames of the transaction applied by a transition
+type CallTracer struct {
+	root  *CallTrace
+	stack []*CallTrace
+}
+
+// Trace returns the top level frame of the traced transaction, or nil if it did not execute
+func (c *CallTracer) Trace() *CallTrace {
+	return c.root
+}
+
+func (c *CallTracer) enter(frame *CallTrace) {
+	if len(c.stack) == 0 {
+		c.root = frame
+	} else {
+		parent := c.stack[len(c.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+
+	c.stack = append(c.stack, frame)
+}
+
+func (c *CallTracer) exit(result *runtime.ExecutionResult) {
+	frame := c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+
+	if result == nil {
+		return
+	}
+
+	frame.GasUsed = frame.Gas - result.GasLeft
+	frame.Err = result.Err
+	if frame.Type == CallTraceCall || result.Succeeded() {
+		frame.Output = append([]byte{}, result.ReturnValue...)
+	}
+}
+
+// SetTracer sets the tracer that records the frames of the next transactions, nil disables the tracing
+func (t *Transition) SetTracer(tracer *CallTracer) {
+	t.tracer = tracer
+}
+
+// traceContract starts the frame of a message call or a contract creation
+func (t *Transition) traceContract(c *runtime.Contract, create bool) {
+	if t.tracer == nil {
+		return
+	}
+
+	frame := &CallTrace{
+		Type:  CallTraceCall,
+		From:  c.Caller,
+		To:    c.Address,
+		Value: new(big.Int),
+		Gas:   c.Gas,
+		Input: append([]byte{}, c.Input...),
+	}
+	if c.Value != nil {
+		frame.Value.Set(c.Value)
+	}
+
+	if create {
+		frame.Type = CallTraceCreate
+		frame.Input = append([]byte{}, c.Code...)
+	} else {
+		frame.CallType = callTypeNames[c.Type]
+
+		if c.Type == runtime.CallCode || c.Type == runtime.DelegateCall {
+			// the code of the callee runs in the context of the caller
+			frame.From = c.Address
+			frame.To = c.CodeAddress
+		}
+		if c.Type == runtime.DelegateCall {
+			// the value of the parent frame is not transferred again
+			frame.Value = new(big.Int)
+		}
+	}
+
+	t.tracer.enter(frame)
+}
+
+// traceResult ends the current frame
+func (t *Transition) traceResult(result *runtime.ExecutionResult) {
+	if t.tracer != nil {
+		t.tracer.exit(result)
+	}
+}
+
+// traceSelfdestruct records the self destruct of a contract, before its balance is transferred
+func (t *Transition) traceSelfdestruct(addr, beneficiary types.Address) {
+	if t.tracer == nil {
+		return
+	}
+
+	t.tracer.enter(&CallTrace{
+		Type:  CallTraceSuicide,
+		From:  addr,
+		To:    beneficiary,
+		Value: new(big.Int).Set(t.state.GetBalance(addr)),
+	})
+	t.tracer.exit(nil)
+}
+
+// TraceBlock re-executes the transactions of the block on top of the state of its parent, and returns
+// the top level frame of each of them. The resulting state is discarded
+func (e *Executor) TraceBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) ([]*CallTrace, error) {
+	txn, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+	txn.block = block
+
+	traces := make([]*CallTrace, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		tracer := &CallTracer{}
+		txn.SetTracer(tracer)
+
+		if err := txn.Write(tx); err != nil {
+			return nil, err
+		}
+		traces = append(traces, tracer.Trace())
+	}
+
+	return traces, nil
+}
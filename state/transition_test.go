@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/0xPolygon/polygon-sdk/chain"
 	"github.com/0xPolygon/polygon-sdk/state/runtime"
+	"github.com/0xPolygon/polygon-sdk/state/runtime/evm"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -148,3 +150,230 @@ func TestTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestTransition_ApplyOverride(t *testing.T) {
+	transition := newTestTransition(nil)
+	txn := transition.Txn()
+
+	txn.SetState(addr1, hash1, hash1)
+	txn.SetState(addr2, hash1, hash1)
+
+	nonce := uint64(5)
+	assert.NoError(t, transition.ApplyOverride(StateOverride{
+		addr1: {
+			Nonce:   &nonce,
+			Balance: big.NewInt(100),
+			Code:    []byte{0x1},
+			State:   map[types.Hash]types.Hash{hash2: hash2},
+		},
+		addr2: {
+			StateDiff: map[types.Hash]types.Hash{hash2: hash2},
+		},
+	}))
+
+	assert.Equal(t, nonce, txn.GetNonce(addr1))
+	assert.Equal(t, big.NewInt(100), txn.GetBalance(addr1))
+	assert.Equal(t, []byte{0x1}, txn.GetCode(addr1))
+
+	// the state replaces the whole storage
+	assert.Equal(t, types.Hash{}, txn.GetState(addr1, hash1))
+	assert.Equal(t, hash2, txn.GetState(addr1, hash2))
+
+	// the state diff replaces the given slots
+	assert.Equal(t, hash1, txn.GetState(addr2, hash1))
+	assert.Equal(t, hash2, txn.GetState(addr2, hash2))
+
+	// the state and the state diff are exclusive
+	assert.Error(t, transition.ApplyOverride(StateOverride{
+		addr1: {
+			State:     map[types.Hash]types.Hash{},
+			StateDiff: map[types.Hash]types.Hash{},
+		},
+	}))
+}
+
+func TestTransition_DynamicFees(t *testing.T) {
+	dynamicFeeTx := func(feeCap, tipCap int64) *types.Transaction {
+		return &types.Transaction{
+			Type:      types.DynamicFeeTx,
+			From:      addr1,
+			Gas:       10,
+			GasPrice:  big.NewInt(feeCap),
+			GasTipCap: big.NewInt(tipCap),
+			Value:     big.NewInt(0),
+		}
+	}
+
+	newTransition := func(baseFee int64) *Transition {
+		transition := newTestTransition(map[types.Address]*PreState{
+			addr1: {Balance: 1000},
+		})
+		transition.config = chain.ForksInTime{London: true}
+		transition.baseFee = big.NewInt(baseFee)
+		return transition
+	}
+
+	tests := []struct {
+		name        string
+		msg         *types.Transaction
+		expectedErr error
+		gasPrice    int64
+	}{
+		{
+			name:     "should pay the base fee and the tip",
+			msg:      dynamicFeeTx(50, 5),
+			gasPrice: 15,
+		},
+		{
+			name:     "should pay at most the fee cap",
+			msg:      dynamicFeeTx(12, 5),
+			gasPrice: 12,
+		},
+		{
+			name:     "should pay the gas price of the legacy transactions",
+			msg:      &types.Transaction{From: addr1, Gas: 10, GasPrice: big.NewInt(20), Value: big.NewInt(0)},
+			gasPrice: 20,
+		},
+		{
+			name:        "should fail with the fee cap below the base fee",
+			msg:         dynamicFeeTx(5, 1),
+			expectedErr: ErrFeeCapTooLow,
+		},
+		{
+			name:        "should fail with the tip above the fee cap",
+			msg:         dynamicFeeTx(20, 30),
+			expectedErr: ErrTipAboveFeeCap,
+		},
+		{
+			name:        "should fail if the balance does not cover the fee cap",
+			msg:         dynamicFeeTx(200, 1),
+			expectedErr: ErrNotEnoughFundsForGas,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition := newTransition(10)
+
+			err := transition.checkDynamicFees(tt.msg)
+			assert.Equal(t, tt.expectedErr, err)
+			if err == nil {
+				assert.Equal(t, big.NewInt(tt.gasPrice), transition.gasPrice(tt.msg))
+			}
+		})
+	}
+
+	// the calls without fees don't pay the base fee
+	transition := newTransition(10)
+	transition.SetNoBaseFee(true)
+	assert.NoError(t, transition.checkDynamicFees(dynamicFeeTx(0, 0)))
+	assert.Equal(t, 0, transition.gasPrice(dynamicFeeTx(0, 0)).Sign())
+
+	// the dynamic fee transactions are not valid before the London fork
+	transition = newTransition(10)
+	transition.config = chain.ForksInTime{}
+	assert.Equal(t, ErrTxTypeNotSupported, transition.checkDynamicFees(dynamicFeeTx(50, 5)))
+
+	// the system transactions have no fee
+	transition = newTransition(10)
+	systemTx := &types.Transaction{Type: types.StateTx, From: types.SystemAddress, Gas: 10, GasPrice: big.NewInt(0), Value: big.NewInt(0)}
+	assert.NoError(t, transition.checkDynamicFees(systemTx))
+	assert.Equal(t, 0, transition.gasPrice(systemTx).Sign())
+}
+
+func TestTransition_AccessList(t *testing.T) {
+	from, to, listed := types.StringToAddress("a1"), types.StringToAddress("a2"), types.StringToAddress("a3")
+
+	accessListTx := &types.Transaction{
+		Type:     types.AccessListTx,
+		From:     from,
+		To:       &to,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		AccessList: types.AccessList{
+			{Address: listed, StorageKeys: []types.Hash{hash1, hash2}},
+		},
+	}
+
+	// the access list is paid upfront
+	cost, err := TransactionGasCost(accessListTx, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+TxAccessListAddressGas+2*TxAccessListStorageKeyGas, cost)
+
+	// the sender, the recipient, the precompiles and the access list are warm
+	transition := newTestTransition(nil)
+	transition.config = chain.ForksInTime{Berlin: true, Istanbul: true, Byzantium: true}
+	transition.prepareAccessList(accessListTx)
+
+	for _, addr := range []types.Address{from, to, listed, types.StringToAddress("1"), types.StringToAddress("9")} {
+		assert.True(t, transition.AccessAddress(addr), addr.String())
+	}
+	assert.True(t, transition.AccessSlot(listed, hash1))
+	assert.True(t, transition.AccessSlot(listed, hash2))
+	assert.False(t, transition.AccessSlot(to, hash1))
+	assert.False(t, transition.AccessAddress(types.StringToAddress("a4")))
+
+	// the access list transactions are not valid before the Berlin fork
+	transition.config = chain.ForksInTime{London: true}
+	assert.Equal(t, ErrTxTypeNotSupported, transition.checkDynamicFees(accessListTx))
+}
+
+func TestTransition_LondonRefunds(t *testing.T) {
+	// the refund is bounded to a fifth of the gas used after the London fork
+	result := &runtime.ExecutionResult{GasLeft: 0}
+	result.UpdateGasUsed(10000, 5000, runtime.RefundQuotient)
+	assert.Equal(t, uint64(5000), result.GasUsed)
+
+	result = &runtime.ExecutionResult{GasLeft: 0}
+	result.UpdateGasUsed(10000, 5000, runtime.RefundQuotientEIP3529)
+	assert.Equal(t, uint64(8000), result.GasUsed)
+
+	// the refund of clearing a slot is reduced
+	for config, refund := range map[chain.ForksInTime]uint64{
+		{Istanbul: true, Berlin: true}:               15000,
+		{Istanbul: true, Berlin: true, London: true}: 4800,
+	} {
+		// the slots of the mock state are indexed by their hash
+		txn := newTestTxn(map[types.Address]*PreState{
+			addr1: {State: map[types.Hash]types.Hash{types.BytesToHash(hashit(hash1.Bytes())): hash1}},
+		})
+		config := config
+		assert.Equal(t, runtime.StorageDeleted, txn.SetStorage(addr1, hash1, types.Hash{}, &config))
+		assert.Equal(t, refund, txn.GetRefund())
+	}
+
+	// the selfdestruct refund is removed
+	transition := newTestTransition(nil)
+	transition.config = chain.ForksInTime{London: true}
+	transition.Selfdestruct(addr1, addr2)
+	assert.Equal(t, uint64(0), transition.state.GetRefund())
+
+	transition = newTestTransition(nil)
+	transition.Selfdestruct(addr1, addr2)
+	assert.Equal(t, uint64(24000), transition.state.GetRefund())
+}
+
+func TestTransition_RejectCodeWithEF(t *testing.T) {
+	// the init code returns the code 0xEF
+	initCode := []byte{0x60, 0xEF, 0x60, 0x00, 0x53, 0x60, 0x01, 0x60, 0x00, 0xF3}
+
+	for _, london := range []bool{false, true} {
+		transition := newTestTransition(nil)
+		transition.r = &Executor{runtimes: []runtime.Runtime{evm.NewEVM()}}
+		transition.config = chain.ForksInTime{Homestead: true, EIP158: true, London: london}
+
+		addr := types.StringToAddress("c1")
+		contract := runtime.NewContractCreation(1, addr1, addr1, addr, big.NewInt(0), 100000, initCode)
+		result := transition.applyCreate(contract, transition)
+
+		if london {
+			assert.Equal(t, runtime.ErrInvalidCode, result.Err)
+			assert.Equal(t, uint64(0), result.GasLeft)
+			assert.Empty(t, transition.state.GetCode(addr))
+		} else {
+			assert.NoError(t, result.Err)
+			assert.Equal(t, []byte{0xEF}, transition.state.GetCode(addr))
+		}
+	}
+}
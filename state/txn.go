@@ -21,6 +21,9 @@ var (
 
 	// refundIndex is the index of the refund
 	refundIndex = types.BytesToHash([]byte{3}).Bytes()
+
+	// accessListPrefix is the prefix of the addresses and the slots accessed by the transaction (EIP-2929)
+	accessListPrefix = types.BytesToHash([]byte{4}).Bytes()
 )
 
 // Txn is a reference of the state
@@ -31,6 +34,12 @@ type Txn struct {
 	txn       *iradix.Txn
 	codeCache *lru.Cache
 	hash      *keccak.Keccak
+
+	// witness records the accessed state, if tracking is enabled
+	witness *witness
+
+	// remote is the state of the chain forked from, if set
+	remote RemoteState
 }
 
 func NewTxn(state State, snapshot Snapshot) *Txn {
@@ -93,7 +102,41 @@ func (txn *Txn) GetAccount(addr types.Address) (*Account, bool) {
 	return object.Account, true
 }
 
+// fork returns a txn starting from the state of the txn, the changes of either are not seen by the other.
+// The account objects are copied, so the txns can be used from different goroutines
+func (txn *Txn) fork() *Txn {
+	forked := newTxn(txn.state, txn.snapshot)
+	forked.remote = txn.remote
+
+	txn.txn.Root().Walk(func(k []byte, v interface{}) bool {
+		if object, ok := v.(*StateObject); ok {
+			forked.txn.Insert(k, object.Copy())
+		} else {
+			forked.txn.Insert(k, v)
+		}
+		return false
+	})
+
+	return forked
+}
+
+// TrackWitness enables recording the state accessed by the txn
+func (txn *Txn) TrackWitness() {
+	txn.witness = newWitness()
+}
+
+// WitnessStats returns the counters of the state accessed by the txn,
+// or nil if tracking is not enabled
+func (txn *Txn) WitnessStats() *WitnessStats {
+	if txn.witness == nil {
+		return nil
+	}
+	return txn.witness.stats()
+}
+
 func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
+	txn.witness.touchAccount(addr)
+
 	// Try to get state from radix tree which holds transient states during block processing first
 	val, exists := txn.txn.Get(addr.Bytes())
 	if exists {
@@ -106,6 +149,9 @@ func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
 
 	data, ok := txn.snapshot.Get(txn.hashit(addr.Bytes()))
 	if !ok {
+		if txn.remote != nil {
+			return txn.remoteObject(addr)
+		}
 		return nil, false
 	}
 
@@ -148,6 +194,7 @@ func (txn *Txn) upsertAccount(addr types.Address, create bool, f func(object *St
 	f(object)
 
 	if object != nil {
+		txn.witness.modifyAccount(addr)
 		txn.txn.Insert(addr.Bytes(), object)
 	}
 }
@@ -268,33 +315,45 @@ func (txn *Txn) SetStorage(addr types.Address, key types.Hash, value types.Hash,
 		return runtime.StorageModified
 	}
 
+	// the refund of clearing a slot is reduced after the London fork (eip-3529)
+	clearRefund := uint64(15000)
+	if config.London {
+		clearRefund = 4800
+	}
+
 	if original == current {
 		if original == zeroHash { // create slot (2.1.1)
 			return runtime.StorageAdded
 		}
 		if value == zeroHash { // delete slot (2.1.2b)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 			return runtime.StorageDeleted
 		}
 		return runtime.StorageModified
 	}
 	if original != zeroHash { // Storage slot was populated before this transaction started
 		if current == zeroHash { // recreate slot (2.2.1.1)
-			txn.SubRefund(15000)
+			txn.SubRefund(clearRefund)
 		} else if value == zeroHash { // delete slot (2.2.1.2)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 		}
 	}
 	if original == value {
 		if original == zeroHash { // reset to original nonexistent slot (2.2.2.1)
 			// Storage was used as memory (allocation and deallocation occurred within the same contract)
-			if config.Istanbul {
+			if config.Berlin {
+				// eip-2929
+				txn.AddRefund(19900)
+			} else if config.Istanbul {
 				txn.AddRefund(19200)
 			} else {
 				txn.AddRefund(19800)
 			}
 		} else { // reset to original existing slot (2.2.2.2)
-			if config.Istanbul {
+			if config.Berlin {
+				// eip-2929
+				txn.AddRefund(2800)
+			} else if config.Istanbul {
 				txn.AddRefund(4200)
 			} else {
 				txn.AddRefund(4800)
@@ -306,6 +365,8 @@ func (txn *Txn) SetStorage(addr types.Address, key types.Hash, value types.Hash,
 
 // SetState change the state of an address
 func (txn *Txn) SetState(addr types.Address, key, value types.Hash) {
+	txn.witness.writeSlot(addr, key)
+
 	txn.upsertAccount(addr, true, func(object *StateObject) {
 		if object.Txn == nil {
 			object.Txn = iradix.New().Txn()
@@ -319,8 +380,19 @@ func (txn *Txn) SetState(addr types.Address, key, value types.Hash) {
 	})
 }
 
+// ResetStorage clears the storage of the address
+func (txn *Txn) ResetStorage(addr types.Address) {
+	txn.upsertAccount(addr, true, func(object *StateObject) {
+		object.Account.Trie = txn.state.NewSnapshot()
+		object.Account.Root = emptyStateHash
+		object.Txn = nil
+	})
+}
+
 // GetState returns the state of the address at a given key
 func (txn *Txn) GetState(addr types.Address, key types.Hash) types.Hash {
+	txn.witness.readSlot(addr, key)
+
 	object, exists := txn.getStateObject(addr)
 	if !exists {
 		return types.Hash{}
@@ -339,8 +411,7 @@ func (txn *Txn) GetState(addr types.Address, key types.Hash) types.Hash {
 	}
 
 	// If the object was not found in the radix trie due to no state update, we fetch it from the trie tre
-	k := txn.hashit(key.Bytes())
-	return object.GetCommitedState(types.BytesToHash(k))
+	return txn.committedState(addr, object, key)
 }
 
 // Nonce
@@ -394,6 +465,7 @@ func (txn *Txn) GetCode(addr types.Address) []byte {
 	}
 	code, _ := txn.state.GetCode(types.BytesToHash(object.Account.CodeHash))
 	txn.codeCache.Add(addr, code)
+	txn.witness.loadCode(code)
 	return code
 }
 
@@ -462,13 +534,41 @@ func (txn *Txn) GetRefund() uint64 {
 	return data.(uint64)
 }
 
+// AccessAddress adds the address to the access list of the transaction,
+// and returns whether it was already accessed (warm)
+func (txn *Txn) AccessAddress(addr types.Address) bool {
+	return txn.access(append(append([]byte{}, accessListPrefix...), addr.Bytes()...))
+}
+
+// AccessSlot adds the storage slot of the address to the access list of the transaction,
+// and returns whether it was already accessed (warm)
+func (txn *Txn) AccessSlot(addr types.Address, key types.Hash) bool {
+	k := append(append([]byte{}, accessListPrefix...), addr.Bytes()...)
+	return txn.access(append(k, key.Bytes()...))
+}
+
+func (txn *Txn) access(k []byte) bool {
+	if _, exists := txn.txn.Get(k); exists {
+		return true
+	}
+	txn.txn.Insert(k, true)
+	return false
+}
+
+// ResetAccessList clears the access list, at the start of each transaction
+func (txn *Txn) ResetAccessList() {
+	txn.txn.DeletePrefix(accessListPrefix)
+}
+
 // GetCommittedState returns the state of the address in the trie
 func (txn *Txn) GetCommittedState(addr types.Address, key types.Hash) types.Hash {
+	txn.witness.readSlot(addr, key)
+
 	obj, ok := txn.getStateObject(addr)
 	if !ok {
 		return types.Hash{}
 	}
-	return obj.GetCommitedState(types.BytesToHash(txn.hashit(key.Bytes())))
+	return txn.committedState(addr, obj, key)
 }
 
 func (txn *Txn) TouchAccount(addr types.Address) {
@@ -518,6 +618,7 @@ func (txn *Txn) CreateAccount(addr types.Address) {
 		obj.Account.Balance.SetBytes(prev.Account.Balance.Bytes())
 	}
 
+	txn.witness.modifyAccount(addr)
 	txn.txn.Insert(addr.Bytes(), obj)
 }
 
@@ -546,11 +647,15 @@ func (txn *Txn) CleanDeleteObjects(deleteEmptyObjects bool) {
 
 		obj2 := obj.Copy()
 		obj2.Deleted = true
+		txn.witness.modifyAccount(types.BytesToAddress(k))
 		txn.txn.Insert(k, obj2)
 	}
 
 	// delete refunds
 	txn.txn.Delete(refundIndex)
+
+	// delete the access list
+	txn.ResetAccessList()
 }
 
 func (txn *Txn) Commit(deleteEmptyObjects bool) (Snapshot, []byte) {
@@ -575,14 +680,24 @@ func (txn *Txn) Commit(deleteEmptyObjects bool) (Snapshot, []byte) {
 			CodeHash:  types.BytesToHash(a.Account.CodeHash),
 			DirtyCode: a.DirtyCode,
 			Code:      a.Code,
+
+			LastTouched: a.Account.LastTouched,
 		}
-		if a.Deleted {
+		if a.Deleted && txn.remote != nil {
+			// the account is kept empty, so that it isn't read from the remote state again
+			obj.Nonce, obj.Balance = 0, big.NewInt(0)
+			obj.Root, obj.CodeHash = emptyStateHash, types.BytesToHash(emptyCodeHash)
+			obj.DirtyCode = false
+		} else if a.Deleted {
 			obj.Deleted = true
 		} else {
 			if a.Txn != nil {
 				a.Txn.Root().Walk(func(k []byte, v interface{}) bool {
 					store := &StorageObject{Key: k}
-					if v == nil {
+					if v == nil && txn.remote != nil {
+						// the cleared slot is kept zero, so that it isn't read from the remote state again
+						store.Val = []byte{}
+					} else if v == nil {
 						store.Deleted = true
 					} else {
 						store.Val = v.([]byte)
@@ -134,3 +134,114 @@ func hashit(k []byte) []byte {
 	h.Write(k)
 	return h.Sum(nil)
 }
+
+func TestTxnWitnessStats(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+	assert.Nil(t, txn.WitnessStats())
+
+	txn.TrackWitness()
+
+	// reading a slot twice counts once
+	txn.GetState(addr1, hash1)
+	txn.GetState(addr1, hash1)
+	txn.SetState(addr1, hash2, hash2)
+	txn.GetBalance(addr2)
+
+	stats := txn.WitnessStats()
+	assert.Equal(t, uint64(2), stats.Accounts)
+	assert.Equal(t, uint64(1), stats.StorageReads)
+	assert.Equal(t, uint64(1), stats.StorageWrites)
+	assert.Equal(t, uint64(0), stats.CodeBytes)
+}
+
+func TestTxnAccessList(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+
+	// the first access is cold, the next ones are warm
+	assert.False(t, txn.AccessAddress(addr1))
+	assert.True(t, txn.AccessAddress(addr1))
+	assert.False(t, txn.AccessSlot(addr1, hash1))
+	assert.True(t, txn.AccessSlot(addr1, hash1))
+
+	// the slots are tracked apart from the address
+	assert.False(t, txn.AccessSlot(addr2, hash1))
+	assert.False(t, txn.AccessAddress(addr2))
+
+	// the accesses of a reverted call are cold again
+	ss := txn.Snapshot()
+	assert.False(t, txn.AccessSlot(addr1, hash2))
+	txn.RevertToSnapshot(ss)
+	assert.False(t, txn.AccessSlot(addr1, hash2))
+
+	// the access list is not part of the state
+	txn.SetState(addr1, hash1, hash2)
+	objs := 0
+	txn.txn.Root().Walk(func(k []byte, v interface{}) bool {
+		if _, ok := v.(*StateObject); ok {
+			objs++
+		}
+		return false
+	})
+	assert.Equal(t, 1, objs)
+
+	txn.ResetAccessList()
+	assert.False(t, txn.AccessAddress(addr1))
+	assert.False(t, txn.AccessSlot(addr1, hash1))
+	assert.Equal(t, hash2, txn.GetState(addr1, hash1))
+}
+
+type mockRemoteState struct {
+	accounts map[types.Address]*RemoteAccount
+	storage  map[types.Address]map[types.Hash]types.Hash
+}
+
+func (m *mockRemoteState) GetAccount(addr types.Address) (*RemoteAccount, bool) {
+	account, ok := m.accounts[addr]
+	return account, ok
+}
+
+func (m *mockRemoteState) GetStorage(addr types.Address, key types.Hash) types.Hash {
+	return m.storage[addr][key]
+}
+
+func TestTxnRemoteState(t *testing.T) {
+	addr3 := types.StringToAddress("3")
+	code := []byte{0x60, 0x00}
+
+	txn := newTestTxn(map[types.Address]*PreState{
+		addr1: {Nonce: 1, Balance: 10},
+	})
+	txn.remote = &mockRemoteState{
+		accounts: map[types.Address]*RemoteAccount{
+			addr1: {Nonce: 5, Balance: big.NewInt(50)},
+			addr2: {Nonce: 2, Balance: big.NewInt(20), Code: code},
+		},
+		storage: map[types.Address]map[types.Hash]types.Hash{
+			addr1: {hash1: hash2},
+			addr2: {hash1: hash1},
+		},
+	}
+
+	// the local accounts take precedence over the remote ones
+	assert.Equal(t, uint64(1), txn.GetNonce(addr1))
+	assert.Equal(t, big.NewInt(10), txn.GetBalance(addr1))
+
+	// the missing accounts are read from the remote state
+	assert.True(t, txn.Exist(addr2))
+	assert.Equal(t, uint64(2), txn.GetNonce(addr2))
+	assert.Equal(t, big.NewInt(20), txn.GetBalance(addr2))
+	assert.Equal(t, code, txn.GetCode(addr2))
+	assert.False(t, txn.Exist(addr3))
+
+	// and so are the missing slots, of the local accounts too
+	assert.Equal(t, hash2, txn.GetState(addr1, hash1))
+	assert.Equal(t, hash1, txn.GetState(addr2, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetState(addr2, hash2))
+
+	txn.SetState(addr2, hash1, hash2)
+	assert.Equal(t, hash2, txn.GetState(addr2, hash1))
+	assert.Equal(t, hash1, txn.GetCommittedState(addr2, hash1))
+
+	// the forks of the txn read the remote state too
+	assert.Equal(t, uint64(2), txn.fork().GetNonce(addr2))
+}
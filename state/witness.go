@@ -0,0 +1,120 @@
+package state
+
+import (
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// WitnessStats are the state access counters of a block execution.
+// They are used for forecasting the state growth and sizing the caches
+type WitnessStats struct {
+	// Number of distinct accounts touched
+	Accounts uint64
+
+	// Number of distinct storage slots read
+	StorageReads uint64
+
+	// Number of distinct storage slots written
+	StorageWrites uint64
+
+	// Number of trie nodes loaded from the storage
+	TrieNodes uint64
+
+	// Number of contract code bytes loaded from the storage
+	CodeBytes uint64
+}
+
+// nodeCounter is implemented by the State backends that count
+// the trie nodes they load from the storage
+type nodeCounter interface {
+	NodesLoaded() uint64
+}
+
+// nodesLoaded returns the number of trie nodes loaded by the state so far,
+// or 0 if the state doesn't count them
+func nodesLoaded(s State) uint64 {
+	if counter, ok := s.(nodeCounter); ok {
+		return counter.NodesLoaded()
+	}
+	return 0
+}
+
+type storageSlot struct {
+	addr types.Address
+	key  types.Hash
+}
+
+// witness records the state accessed by a Txn
+type witness struct {
+	accounts  map[types.Address]struct{}
+	reads     map[storageSlot]struct{}
+	writes    map[storageSlot]struct{}
+	codeBytes uint64
+
+	// modified are the accounts written in the txn, they are a subset of the touched accounts
+	modified map[types.Address]struct{}
+}
+
+func newWitness() *witness {
+	return &witness{
+		accounts: map[types.Address]struct{}{},
+		reads:    map[storageSlot]struct{}{},
+		writes:   map[storageSlot]struct{}{},
+		modified: map[types.Address]struct{}{},
+	}
+}
+
+func (w *witness) touchAccount(addr types.Address) {
+	if w != nil {
+		w.accounts[addr] = struct{}{}
+	}
+}
+
+func (w *witness) readSlot(addr types.Address, key types.Hash) {
+	if w != nil {
+		w.reads[storageSlot{addr, key}] = struct{}{}
+	}
+}
+
+func (w *witness) writeSlot(addr types.Address, key types.Hash) {
+	if w != nil {
+		w.writes[storageSlot{addr, key}] = struct{}{}
+	}
+}
+
+func (w *witness) modifyAccount(addr types.Address) {
+	if w != nil {
+		w.modified[addr] = struct{}{}
+	}
+}
+
+// merge adds the state accessed in another txn
+func (w *witness) merge(other *witness) {
+	for addr := range other.accounts {
+		w.accounts[addr] = struct{}{}
+	}
+	for slot := range other.reads {
+		w.reads[slot] = struct{}{}
+	}
+	for slot := range other.writes {
+		w.writes[slot] = struct{}{}
+	}
+	for addr := range other.modified {
+		w.modified[addr] = struct{}{}
+	}
+	w.codeBytes += other.codeBytes
+}
+
+func (w *witness) loadCode(code []byte) {
+	if w != nil {
+		w.codeBytes += uint64(len(code))
+	}
+}
+
+func (w *witness) stats() *WitnessStats {
+	return &WitnessStats{
+		Accounts:      uint64(len(w.accounts)),
+		StorageReads:  uint64(len(w.reads)),
+		StorageWrites: uint64(len(w.writes)),
+		CodeBytes:     w.codeBytes,
+	}
+}
@@ -0,0 +1,540 @@
+package txpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/network/grpc"
+	"github.com/0xPolygon/polygon-sdk/txpool/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const gossipProtoV1 = "/txpool/0.2"
+
+const (
+	// maxKnownTxs is the number of transaction hashes remembered as known by each peer
+	maxKnownTxs = 32768
+
+	// maxAnnounceHashes is the maximum number of hashes in an announcement or in a request
+	maxAnnounceHashes = 256
+
+	// announceInterval is how often the hashes queued for a peer are announced to it
+	announceInterval = 100 * time.Millisecond
+
+	// requestTimeout is how long a requested transaction is waited for,
+	// before it can be requested from another peer announcing it
+	requestTimeout = 5 * time.Second
+
+	// maxInflightRequests is the maximum number of pending transaction requests to a peer
+	maxInflightRequests = 4
+
+	// maxAlternates is the maximum number of other peers remembered as announcing a requested transaction
+	maxAlternates = 4
+)
+
+// announcePeer is a peer the transactions are announced to
+type announcePeer struct {
+	id     peer.ID
+	client proto.TxnPoolGossipClient
+
+	// known holds the hashes of the transactions the peer has, or was told about
+	known *lru.Cache
+
+	lock   sync.Mutex
+	queued []types.Hash
+
+	// inflight is the number of pending requests to the peer
+	inflight int
+
+	closeCh chan struct{}
+}
+
+// markKnown records that the peer has the transaction, and reports whether it was known already
+func (p *announcePeer) markKnown(hash types.Hash) bool {
+	known, _ := p.known.ContainsOrAdd(hash, nil)
+
+	return known
+}
+
+// enqueue queues the hash for the next announcement to the peer
+func (p *announcePeer) enqueue(hash types.Hash) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.queued) >= maxKnownTxs {
+		return false
+	}
+	p.queued = append(p.queued, hash)
+
+	return true
+}
+
+// dequeue returns the next batch of queued hashes
+func (p *announcePeer) dequeue() []types.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	n := len(p.queued)
+	if n > maxAnnounceHashes {
+		n = maxAnnounceHashes
+	}
+	batch := p.queued[:n]
+	p.queued = p.queued[n:]
+
+	return batch
+}
+
+// acquire reserves a request to the peer, and reports whether it has room for it
+func (p *announcePeer) acquire() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.inflight >= maxInflightRequests {
+		return false
+	}
+	p.inflight++
+
+	return true
+}
+
+// release frees a request reserved by acquire
+func (p *announcePeer) release() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.inflight--
+}
+
+// txRequest is a pending request of a missing transaction
+type txRequest struct {
+	at   time.Time
+	from peer.ID
+
+	// alternates are the other peers that announced the transaction,
+	// it's requested from them in turn if the peer doesn't return it
+	alternates []peer.ID
+}
+
+// addAlternate records the peer as announcing the transaction
+func (r *txRequest) addAlternate(id peer.ID) {
+	if id == r.from || len(r.alternates) >= maxAlternates {
+		return
+	}
+	for _, alternate := range r.alternates {
+		if alternate == id {
+			return
+		}
+	}
+	r.alternates = append(r.alternates, id)
+}
+
+// txAnnouncer gossips the transactions of the pool by announcing their hashes to the peers,
+// which pull the ones they miss. Every peer is told about a transaction at most once
+type txAnnouncer struct {
+	proto.UnimplementedTxnPoolGossipServer
+
+	logger hclog.Logger
+	pool   *TxPool
+	server *network.Server
+
+	peersLock sync.Mutex
+	peers     map[peer.ID]*announcePeer
+
+	// requested holds the pending requests of the missing transactions,
+	// so a transaction announced by several peers is pulled once
+	requestedLock sync.Mutex
+	requested     map[types.Hash]*txRequest
+}
+
+func newTxAnnouncer(logger hclog.Logger, pool *TxPool, server *network.Server) *txAnnouncer {
+	return &txAnnouncer{
+		logger:    logger.Named("announcer"),
+		pool:      pool,
+		server:    server,
+		peers:     map[peer.ID]*announcePeer{},
+		requested: map[types.Hash]*txRequest{},
+	}
+}
+
+// setup registers the gossip protocol and tracks the connected peers
+func (a *txAnnouncer) setup() error {
+	stream := grpc.NewGrpcStream(a.server.GrpcServerOptions(gossipProtoV1)...)
+	proto.RegisterTxnPoolGossipServer(stream.GrpcServer(), a)
+	stream.Serve()
+
+	a.server.Register(gossipProtoV1, stream)
+
+	return a.server.SubscribeFn(func(evnt *network.PeerEvent) {
+		switch evnt.Type {
+		case network.PeerEventConnected:
+			a.addPeer(evnt.PeerID)
+
+		case network.PeerEventDisconnected:
+			a.removePeer(evnt.PeerID)
+		}
+	})
+}
+
+func (a *txAnnouncer) addPeer(id peer.ID) {
+	stream, err := a.server.NewStream(gossipProtoV1, id)
+	if err != nil {
+		a.logger.Debug("peer does not support the txpool gossip protocol", "id", id, "err", err)
+		return
+	}
+
+	known, _ := lru.New(maxKnownTxs)
+	p := &announcePeer{
+		id:      id,
+		client:  proto.NewTxnPoolGossipClient(grpc.WrapClient(stream, a.server.GrpcCallOptions(gossipProtoV1, id)...)),
+		known:   known,
+		closeCh: make(chan struct{}),
+	}
+
+	a.peersLock.Lock()
+	if old, ok := a.peers[id]; ok {
+		close(old.closeCh)
+	}
+	a.peers[id] = p
+	a.peersLock.Unlock()
+
+	go a.announceLoop(p)
+}
+
+func (a *txAnnouncer) removePeer(id peer.ID) {
+	a.peersLock.Lock()
+	defer a.peersLock.Unlock()
+
+	if p, ok := a.peers[id]; ok {
+		close(p.closeCh)
+		delete(a.peers, id)
+	}
+}
+
+func (a *txAnnouncer) getPeer(id peer.ID) *announcePeer {
+	a.peersLock.Lock()
+	defer a.peersLock.Unlock()
+
+	return a.peers[id]
+}
+
+// close stops announcing to the peers
+func (a *txAnnouncer) close() {
+	a.peersLock.Lock()
+	defer a.peersLock.Unlock()
+
+	for id, p := range a.peers {
+		close(p.closeCh)
+		delete(a.peers, id)
+	}
+}
+
+// announce queues the transaction hash for the peers that don't know the transaction
+func (a *txAnnouncer) announce(hash types.Hash) {
+	a.peersLock.Lock()
+	defer a.peersLock.Unlock()
+
+	for _, p := range a.peers {
+		if p.known.Contains(hash) {
+			continue
+		}
+		// the hash is only known once queued, so it's announced again if the queue is full
+		if !p.enqueue(hash) {
+			a.logger.Debug("announcement queue full", "id", p.id)
+			continue
+		}
+		p.markKnown(hash)
+	}
+}
+
+func (a *txAnnouncer) announceLoop(p *announcePeer) {
+	ticker := time.NewTicker(announceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for batch := p.dequeue(); len(batch) != 0; batch = p.dequeue() {
+				ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+				_, err := p.client.Announce(ctx, &proto.TxnHashes{Hashes: hashesToBytes(batch)})
+				cancel()
+
+				if err != nil {
+					a.logger.Debug("failed to announce txns", "id", p.id, "err", err)
+					break
+				}
+			}
+
+		case <-p.closeCh:
+			return
+		}
+	}
+}
+
+// request marks the transaction as requested from the peer, and reports whether it has to be requested,
+// which is the case if no request for it is pending. Otherwise the peer is an alternate to request it from
+func (a *txAnnouncer) request(hash types.Hash, id peer.ID, now time.Time) bool {
+	a.requestedLock.Lock()
+	defer a.requestedLock.Unlock()
+
+	if req, ok := a.requested[hash]; ok && now.Sub(req.at) < requestTimeout {
+		req.addAlternate(id)
+
+		return false
+	}
+	a.requested[hash] = &txRequest{at: now, from: id}
+
+	if len(a.requested) > maxKnownTxs {
+		for hash, req := range a.requested {
+			if now.Sub(req.at) >= requestTimeout {
+				delete(a.requested, hash)
+			}
+		}
+	}
+
+	return true
+}
+
+// addAlternate records the peer as announcing the transaction, if it's being requested from another one
+func (a *txAnnouncer) addAlternate(hash types.Hash, id peer.ID) {
+	a.requestedLock.Lock()
+	defer a.requestedLock.Unlock()
+
+	if req, ok := a.requested[hash]; ok {
+		req.addAlternate(id)
+	}
+}
+
+func (a *txAnnouncer) received(hash types.Hash) {
+	a.requestedLock.Lock()
+	defer a.requestedLock.Unlock()
+
+	delete(a.requested, hash)
+}
+
+// retry clears the requests of the transactions a peer didn't return,
+// and requests them from the next peers that announced them
+func (a *txAnnouncer) retry(hashes []types.Hash) {
+	now := time.Now()
+	batches := map[peer.ID][]types.Hash{}
+
+	a.requestedLock.Lock()
+	for _, hash := range hashes {
+		req, ok := a.requested[hash]
+		if !ok {
+			continue
+		}
+		if len(req.alternates) == 0 {
+			delete(a.requested, hash)
+			continue
+		}
+
+		id := req.alternates[0]
+		a.requested[hash] = &txRequest{at: now, from: id, alternates: req.alternates[1:]}
+		batches[id] = append(batches[id], hash)
+	}
+	a.requestedLock.Unlock()
+
+	for id, batch := range batches {
+		p := a.getPeer(id)
+		if p == nil || !p.acquire() {
+			// the peer is gone or busy, the next ones are tried
+			a.retry(batch)
+			continue
+		}
+
+		go a.fetch(p, batch)
+	}
+}
+
+// fetch pulls the missing transactions from the peer that announced them, and adds them to the pool.
+// The request must be reserved with acquire. The transactions the peer doesn't return are requested
+// from the other peers that announced them
+func (a *txAnnouncer) fetch(p *announcePeer, hashes []types.Hash) {
+	defer p.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	resp, err := p.client.GetTxns(ctx, &proto.TxnHashes{Hashes: hashesToBytes(hashes)})
+	if err != nil {
+		a.logger.Debug("failed to request txns", "id", p.id, "err", err)
+		a.retry(hashes)
+
+		return
+	}
+
+	pending := make(map[types.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		pending[hash] = struct{}{}
+	}
+
+	for _, txn := range resp.Txns {
+		tx, expiry, err := txFromProto(txn)
+		if err != nil {
+			a.logger.Error("failed to decode requested txn", "id", p.id, "err", err)
+			continue
+		}
+		tx.ComputeHash()
+
+		if _, ok := pending[tx.Hash]; !ok {
+			a.logger.Debug("dropping unrequested txn", "id", p.id, "hash", tx.Hash)
+			continue
+		}
+		delete(pending, tx.Hash)
+
+		p.markKnown(tx.Hash)
+		a.received(tx.Hash)
+
+		a.pool.addGossipTxn(tx, expiry)
+	}
+
+	if len(pending) != 0 {
+		missing := make([]types.Hash, 0, len(pending))
+		for hash := range pending {
+			missing = append(missing, hash)
+		}
+		a.retry(missing)
+	}
+}
+
+// Announce implements the gossip protocol, it requests the announced transactions missing from the pool.
+// The transactions announced by a peer with too many pending requests are only requested from it
+// if the peers they are requested from don't return them
+func (a *txAnnouncer) Announce(ctx context.Context, req *proto.TxnHashes) (*empty.Empty, error) {
+	p := a.getPeer(ctx.(*grpc.Context).PeerID)
+	if p == nil || !a.pool.sealing {
+		return &empty.Empty{}, nil
+	}
+
+	hashes := req.Hashes
+	if len(hashes) > maxAnnounceHashes {
+		hashes = hashes[:maxAnnounceHashes]
+	}
+
+	busy := !p.acquire()
+
+	now := time.Now()
+	missing := []types.Hash{}
+	for _, raw := range hashes {
+		hash := types.BytesToHash(raw)
+		p.markKnown(hash)
+
+		if a.pool.knownTx(hash) {
+			continue
+		}
+		if busy {
+			a.addAlternate(hash, p.id)
+			continue
+		}
+		if a.request(hash, p.id, now) {
+			missing = append(missing, hash)
+		}
+	}
+
+	if busy {
+		return &empty.Empty{}, nil
+	}
+	if len(missing) == 0 {
+		p.release()
+		return &empty.Empty{}, nil
+	}
+
+	go a.fetch(p, missing)
+
+	return &empty.Empty{}, nil
+}
+
+// GetTxns implements the gossip protocol, it returns the requested transactions still in the pool
+func (a *txAnnouncer) GetTxns(ctx context.Context, req *proto.TxnHashes) (*proto.Txns, error) {
+	hashes := req.Hashes
+	if len(hashes) > maxAnnounceHashes {
+		hashes = hashes[:maxAnnounceHashes]
+	}
+
+	resp := &proto.Txns{}
+	for _, raw := range hashes {
+		hash := types.BytesToHash(raw)
+		if tx := a.pool.getTx(hash); tx != nil {
+			resp.Txns = append(resp.Txns, txToProto(tx, a.pool.expiries.get(hash)))
+		}
+	}
+
+	return resp, nil
+}
+
+func hashesToBytes(hashes []types.Hash) [][]byte {
+	raw := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		raw[i] = hash.Bytes()
+	}
+
+	return raw
+}
+
+// txToProto encodes the transaction, along with its expiry if any
+func txToProto(tx *types.Transaction, expiry *Expiry) *proto.Txn {
+	txn := &proto.Txn{
+		Raw: &any.Any{
+			Value: tx.MarshalRLP(),
+		},
+	}
+	if expiry != nil {
+		txn.MaxBlockNumber = expiry.MaxBlockNumber
+		txn.MaxTimestamp = expiry.MaxTimestamp
+	}
+
+	return txn
+}
+
+func txFromProto(txn *proto.Txn) (*types.Transaction, *Expiry, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalRLP(txn.Raw.GetValue()); err != nil {
+		return nil, nil, err
+	}
+
+	expiry := &Expiry{
+		MaxBlockNumber: txn.MaxBlockNumber,
+		MaxTimestamp:   txn.MaxTimestamp,
+	}
+
+	return tx, expiry, nil
+}
+
+// knownTx checks if the transaction is in the pool
+func (t *TxPool) knownTx(hash types.Hash) bool {
+	return !t.arrivals.get(hash).IsZero()
+}
+
+// getTx returns the pending or queued transaction with the given hash, or nil if it's not in the pool
+func (t *TxPool) getTx(hash types.Hash) *types.Transaction {
+	if tx := t.pendingQueue.get(hash); tx != nil {
+		return tx
+	}
+	if tx := t.remoteTxns.get(hash); tx != nil {
+		return tx
+	}
+
+	// the queued local transactions are only in the account queues
+	for _, addr := range t.locals.addrs() {
+		mux := t.lockExistingAccountQueue(addr, false)
+		if mux == nil {
+			continue
+		}
+		for _, tx := range mux.accountQueue.txs {
+			if tx.Hash == hash {
+				mux.unlock()
+				return tx
+			}
+		}
+		mux.unlock()
+	}
+
+	return nil
+}
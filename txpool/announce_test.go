@@ -0,0 +1,188 @@
+package txpool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/tests"
+	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/txpool/proto"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestAnnounce_PullTxns(t *testing.T) {
+	key, addr := tests.GenerateKeyAndAddr(t)
+	signer := crypto.NewEIP155Signer(100)
+
+	createPool := func() (*TxPool, *network.Server) {
+		server := network.CreateServer(t, nil)
+		pool, err := NewTxPool(hclog.NewNullLogger(), true, nil, true, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, server, nilMetrics)
+		assert.NoError(t, err)
+		pool.AddSigner(signer)
+
+		return pool, server
+	}
+
+	pool1, network1 := createPool()
+	pool2, network2 := createPool()
+	defer pool1.Close()
+	defer pool2.Close()
+
+	network.MultiJoin(t, network1, network2)
+
+	assert.Eventually(t, func() bool {
+		return pool1.announcer.getPeer(network2.AddrInfo().ID) != nil &&
+			pool2.announcer.getPeer(network1.AddrInfo().ID) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	tx, err := signer.SignTx(&types.Transaction{
+		To:       &addr,
+		Gas:      validGasLimit,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(1),
+	}, key)
+	assert.NoError(t, err)
+
+	expiry := &Expiry{MaxBlockNumber: 100}
+	assert.NoError(t, pool1.AddTxWithExpiry(tx, expiry))
+
+	// the second pool pulls the announced transaction, along with its expiry
+	assert.Eventually(t, func() bool {
+		return pool2.knownTx(tx.Hash)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, expiry, pool2.expiries.get(tx.Hash))
+
+	// and doesn't announce it back to the first one, which already knows it
+	peer1 := pool2.announcer.getPeer(network1.AddrInfo().ID)
+	assert.Empty(t, peer1.dequeue())
+}
+
+func TestAnnounce_RequestOnce(t *testing.T) {
+	announcer := newTxAnnouncer(hclog.NewNullLogger(), nil, nil)
+	hash := types.StringToHash("1")
+	now := time.Now()
+
+	assert.True(t, announcer.request(hash, "a", now))
+	assert.False(t, announcer.request(hash, "b", now.Add(time.Second)))
+
+	// the other announcers are the alternates to request it from
+	assert.Equal(t, []peer.ID{"b"}, announcer.requested[hash].alternates)
+
+	// requested again from another peer once the request times out
+	assert.True(t, announcer.request(hash, "b", now.Add(requestTimeout)))
+
+	announcer.received(hash)
+	assert.True(t, announcer.request(hash, "a", now))
+}
+
+func TestAnnounce_InflightRequests(t *testing.T) {
+	p := &announcePeer{}
+
+	for i := 0; i < maxInflightRequests; i++ {
+		assert.True(t, p.acquire())
+	}
+	assert.False(t, p.acquire())
+
+	p.release()
+	assert.True(t, p.acquire())
+}
+
+func TestAnnounce_QueueFull(t *testing.T) {
+	announcer := newTxAnnouncer(hclog.NewNullLogger(), nil, nil)
+
+	known, _ := lru.New(maxKnownTxs)
+	p := &announcePeer{id: "a", known: known, queued: make([]types.Hash, maxKnownTxs)}
+	announcer.peers[p.id] = p
+
+	// the hash isn't known by the peer while it can't be queued
+	hash := types.StringToHash("1")
+	announcer.announce(hash)
+	assert.False(t, p.known.Contains(hash))
+
+	p.queued = nil
+	announcer.announce(hash)
+	assert.True(t, p.known.Contains(hash))
+	assert.Equal(t, []types.Hash{hash}, p.dequeue())
+}
+
+type mockGossipClient struct {
+	txns []*proto.Txn
+}
+
+func (m *mockGossipClient) Announce(context.Context, *proto.TxnHashes, ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func (m *mockGossipClient) GetTxns(context.Context, *proto.TxnHashes, ...grpc.CallOption) (*proto.Txns, error) {
+	return &proto.Txns{Txns: m.txns}, nil
+}
+
+func TestAnnounce_FetchFromAlternate(t *testing.T) {
+	key, addr := tests.GenerateKeyAndAddr(t)
+	signer := crypto.NewEIP155Signer(100)
+
+	pool, err := NewTxPool(hclog.NewNullLogger(), true, nil, true, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.AddSigner(signer)
+
+	signTx := func(nonce uint64) *types.Transaction {
+		tx, err := signer.SignTx(&types.Transaction{
+			Nonce:    nonce,
+			To:       &addr,
+			Gas:      validGasLimit,
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(1),
+		}, key)
+		assert.NoError(t, err)
+		tx.ComputeHash()
+
+		return tx
+	}
+	requestedTx, unrequestedTx := signTx(0), signTx(1)
+
+	// the first peer returns a transaction that wasn't requested instead of the announced one
+	announcer := newTxAnnouncer(hclog.NewNullLogger(), pool, nil)
+	newPeer := func(id peer.ID, txns ...*types.Transaction) *announcePeer {
+		client := &mockGossipClient{}
+		for _, tx := range txns {
+			client.txns = append(client.txns, txToProto(tx, nil))
+		}
+		known, _ := lru.New(maxKnownTxs)
+		p := &announcePeer{id: id, client: client, known: known}
+		announcer.peers[id] = p
+
+		return p
+	}
+	peer1 := newPeer("a", unrequestedTx)
+	peer2 := newPeer("b", requestedTx)
+
+	now := time.Now()
+	assert.True(t, announcer.request(requestedTx.Hash, peer1.id, now))
+	assert.False(t, announcer.request(requestedTx.Hash, peer2.id, now))
+
+	assert.True(t, peer1.acquire())
+	announcer.fetch(peer1, []types.Hash{requestedTx.Hash})
+
+	// the unrequested transaction is dropped, and the announced one is pulled from the other announcer
+	assert.Eventually(t, func() bool {
+		return pool.knownTx(requestedTx.Hash)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, pool.knownTx(unrequestedTx.Hash))
+
+	assert.Eventually(t, func() bool {
+		announcer.requestedLock.Lock()
+		defer announcer.requestedLock.Unlock()
+
+		return len(announcer.requested) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, peer1.inflight)
+}
@@ -0,0 +1,143 @@
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultTxLifetime is how long the remote transactions are kept in the pool by default
+const DefaultTxLifetime = 24 * time.Hour
+
+// droppedSubBuffer is the number of dropped transactions a slow subscriber can lag behind.
+// The transactions dropped while its buffer is full are not delivered to it
+const droppedSubBuffer = 256
+
+// DropReason tells why a transaction left the pool without being mined
+type DropReason string
+
+const (
+	// DropStale is a transaction whose nonce is below the nonce of its sender in the state
+	DropStale DropReason = "stale"
+
+	// DropLifetime is a remote transaction older than the lifetime, or queued by an idle account
+	DropLifetime DropReason = "lifetime"
+
+	// DropEvicted is a transaction evicted to make room in the pool, or evicted with its account
+	DropEvicted DropReason = "evicted"
+
+	// DropReplaced is a transaction replaced by one paying more for the same nonce
+	DropReplaced DropReason = "replaced"
+
+	// DropExpired is a transaction whose expiry is reached
+	DropExpired DropReason = "expired"
+)
+
+// DroppedTx is a transaction that left the pool without being mined
+type DroppedTx struct {
+	Tx     *types.Transaction
+	Reason DropReason
+}
+
+// droppedFeed delivers the dropped transactions to the subscribers
+type droppedFeed struct {
+	lock sync.Mutex
+	subs map[uint64]chan *DroppedTx
+	next uint64
+}
+
+func (f *droppedFeed) subscribe() (<-chan *DroppedTx, func()) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.subs == nil {
+		f.subs = map[uint64]chan *DroppedTx{}
+	}
+
+	id := f.next
+	f.next++
+
+	ch := make(chan *DroppedTx, droppedSubBuffer)
+	f.subs[id] = ch
+
+	unsubscribe := func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		if _, ok := f.subs[id]; ok {
+			delete(f.subs, id)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (f *droppedFeed) send(dropped *DroppedTx) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, ch := range f.subs {
+		select {
+		case ch <- dropped:
+		default:
+		}
+	}
+}
+
+// SubscribeDropped returns the channel receiving the transactions that leave the pool without being mined,
+// and the function that ends the subscription and closes the channel
+func (t *TxPool) SubscribeDropped() (<-chan *DroppedTx, func()) {
+	return t.dropped.subscribe()
+}
+
+// notifyDropped delivers the dropped transactions to the subscribers
+func (t *TxPool) notifyDropped(txs []*types.Transaction, reason DropReason) {
+	for _, tx := range txs {
+		t.dropped.send(&DroppedTx{Tx: tx, Reason: reason})
+	}
+}
+
+// SetTxLifetime sets how long the remote transactions are kept in the pool, 0 keeps them until mined
+func (t *TxPool) SetTxLifetime(lifetime time.Duration) {
+	t.txLifetime = lifetime
+}
+
+// evictOld drops the remote transactions that have been in the pool for longer than the lifetime
+func (t *TxPool) evictOld(now time.Time) {
+	if t.txLifetime == 0 {
+		return
+	}
+
+	for _, tx := range t.remoteTxns.txs() {
+		if receivedAt := t.arrivals.get(tx.Hash); !receivedAt.IsZero() && now.Sub(receivedAt) > t.txLifetime {
+			if t.remoteTxns.Delete(tx) {
+				t.evictTx(tx, DropLifetime)
+			}
+		}
+	}
+}
+
+// pruneAccount drops the pending transactions of the account whose nonce is below the state nonce,
+// executed or replaced without going through the pool, and promotes the queued transactions
+// held back by the pending limit
+func (t *TxPool) pruneAccount(addr types.Address, stateNonce uint64) {
+	mux := t.lockExistingAccountQueue(addr, true)
+	if mux == nil {
+		return
+	}
+	defer mux.unlock()
+
+	stale := []*types.Transaction{}
+	for _, tx := range t.pendingQueue.txsFrom(addr) {
+		if tx.Nonce < stateNonce && t.pendingQueue.Delete(tx) {
+			stale = append(stale, tx)
+		}
+	}
+	t.dropTxs(stale, DropStale)
+
+	if t.maxAccountPending != 0 {
+		t.promote(addr, mux.accountQueue)
+	}
+}
@@ -0,0 +1,109 @@
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveDropped(t *testing.T, ch <-chan *DroppedTx) []*DroppedTx {
+	t.Helper()
+
+	dropped := []*DroppedTx{}
+	for {
+		select {
+		case d := <-ch:
+			dropped = append(dropped, d)
+		default:
+			return dropped
+		}
+	}
+}
+
+func TestDropped_Lifetime(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+	pool.SetTxLifetime(time.Hour)
+	pool.locals.addAddr(addr2)
+
+	ch, unsubscribe := pool.SubscribeDropped()
+	defer unsubscribe()
+
+	txs := []*types.Transaction{
+		queueTestTx(addr1, 0, 1),
+		queueTestTx(addr1, 1, 1),
+		queueTestTx(addr1, 3, 1),
+		queueTestTx(addr2, 0, 2),
+	}
+	for _, tx := range txs {
+		assert.NoError(t, pool.addImpl(OriginGossip, tx))
+	}
+
+	// not old yet
+	pool.evictOld(time.Now())
+	assert.Empty(t, receiveDropped(t, ch))
+
+	pool.evictOld(time.Now().Add(2 * time.Hour))
+
+	// the remote transactions are dropped, the local ones kept
+	dropped := receiveDropped(t, ch)
+	assert.Len(t, dropped, 3)
+	for _, d := range dropped {
+		assert.Equal(t, addr1, d.Tx.From)
+		assert.Equal(t, DropLifetime, d.Reason)
+	}
+
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, 0, pool.NumAccountTxs(addr1))
+	assert.Equal(t, uint64(1), pool.slots)
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(0), nonce)
+}
+
+func TestDropped_StaleNonce(t *testing.T) {
+	pool, store := newQueueTestPool(t, true)
+
+	ch, unsubscribe := pool.SubscribeDropped()
+	defer unsubscribe()
+
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, nonce, 1)))
+	}
+
+	// the nonce 0 is executed without going through the pool,
+	// while the next one is out of the pool with the sealer
+	tx, _ := pool.Pop()
+	assert.Equal(t, uint64(0), tx.Nonce)
+	popped, _ := pool.Pop()
+	assert.Equal(t, uint64(1), popped.Nonce)
+
+	assert.NoError(t, pool.pendingQueue.Push(tx))
+	store.nonces[addr1] = 1
+	pool.promoteExecuted()
+
+	dropped := receiveDropped(t, ch)
+	assert.Len(t, dropped, 1)
+	assert.Equal(t, tx.Hash, dropped[0].Tx.Hash)
+	assert.Equal(t, DropStale, dropped[0].Reason)
+
+	// the transaction after the popped one is still pending
+	assert.Equal(t, uint64(1), pool.Length())
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(3), nonce)
+}
+
+func TestDropped_Unsubscribe(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+
+	ch, unsubscribe := pool.SubscribeDropped()
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	pool.notifyDropped([]*types.Transaction{queueTestTx(addr1, 0, 1)}, DropEvicted)
+}
@@ -0,0 +1,185 @@
+package txpool
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// ErrTxExpired is returned if the transaction can't be included anymore
+var ErrTxExpired = errors.New("transaction expired")
+
+// Expiry bounds the inclusion of a transaction, the zero fields are unbounded.
+// The expiry is not part of the signed transaction: the pools that know it drop the
+// transaction once it expires, and it is gossiped along with the transaction
+type Expiry struct {
+	// MaxBlockNumber is the last block number the transaction can be included in
+	MaxBlockNumber uint64
+
+	// MaxTimestamp is the last block timestamp, in unix seconds, the transaction can be included at
+	MaxTimestamp uint64
+}
+
+func (e *Expiry) isZero() bool {
+	return e == nil || (e.MaxBlockNumber == 0 && e.MaxTimestamp == 0)
+}
+
+// expired checks if the transaction can't be included in a block with the given number and timestamp
+func (e *Expiry) expired(number, timestamp uint64) bool {
+	if e.isZero() {
+		return false
+	}
+
+	return (e.MaxBlockNumber != 0 && number > e.MaxBlockNumber) ||
+		(e.MaxTimestamp != 0 && timestamp > e.MaxTimestamp)
+}
+
+// expiredNow checks if the transaction can't be included in the next block
+func (t *TxPool) expiredNow(expiry *Expiry) bool {
+	return expiry.expired(t.store.Header().Number+1, uint64(time.Now().Unix()))
+}
+
+// AddTxWithExpiry adds a new transaction to the pool, which is dropped once the expiry is reached,
+// and broadcasts it along with the expiry if networking is enabled
+func (t *TxPool) AddTxWithExpiry(tx *types.Transaction, expiry *Expiry) error {
+	if err := t.addWithExpiry(OriginAddTxn, tx, expiry); err != nil {
+		return err
+	}
+
+	t.broadcast(tx, expiry)
+
+	return nil
+}
+
+// addWithExpiry adds the transaction to the pool, and records its expiry if any
+func (t *TxPool) addWithExpiry(origin TxOrigin, tx *types.Transaction, expiry *Expiry) error {
+	if expiry.isZero() {
+		return t.addImpl(origin, tx)
+	}
+
+	if t.expiredNow(expiry) {
+		return ErrTxExpired
+	}
+
+	tx.ComputeHash()
+	t.expiries.set(tx, expiry)
+
+	if err := t.addImpl(origin, tx); err != nil {
+		t.expiries.forget(tx.Hash)
+		return err
+	}
+
+	return nil
+}
+
+// popLive pops the max priced pending transaction that has not expired,
+// the expired transactions popped on the way are dropped
+func (t *TxPool) popLive() *pricedTx {
+	for {
+		txn := t.pendingQueue.Pop()
+		if txn == nil || !t.expiredNow(t.expiries.get(txn.tx.Hash)) {
+			return txn
+		}
+
+		t.dropExpired(txn.tx)
+	}
+}
+
+// pruneExpired drops the transactions that can't be included in the next block anymore
+func (t *TxPool) pruneExpired() {
+	for _, tx := range t.expiries.txs() {
+		if t.expiredNow(t.expiries.get(tx.Hash)) {
+			t.dropExpired(tx)
+		}
+	}
+}
+
+// dropExpired removes an expired transaction from the pool. If it was promoted, the pending
+// transactions of the account with a higher nonce are removed too, since they can't be executed
+// without it, and the next nonce of the account goes back to its nonce
+func (t *TxPool) dropExpired(tx *types.Transaction) {
+	t.logger.Debug("drop expired txn", "hash", tx.Hash, "from", tx.From)
+
+	mux := t.lockAccountQueue(tx.From, true)
+	defer mux.unlock()
+
+	dropped := []*types.Transaction{}
+	if mux.accountQueue.Remove(tx.Hash) {
+		dropped = append(dropped, tx)
+	} else if tx.Nonce < mux.accountQueue.nextNonce {
+		dropped = append(dropped, tx)
+		for _, pending := range t.pendingQueue.txsFrom(tx.From) {
+			if pending.Nonce > tx.Nonce {
+				dropped = append(dropped, pending)
+			}
+		}
+
+		mux.accountQueue.nextNonce = tx.Nonce
+	}
+
+	for _, tx := range dropped {
+		t.pendingQueue.Delete(tx)
+		t.remoteTxns.Delete(tx)
+		t.decreaseSlots(numSlots(tx))
+		t.forgetTx(tx.Hash)
+	}
+	t.expiries.forget(tx.Hash)
+	t.notifyDropped(dropped, DropExpired)
+
+	t.metrics.PendingTxs.Set(float64(t.pendingQueue.Length()))
+}
+
+// txExpiries keeps the expiry of the transactions of the pool that have one
+type txExpiries struct {
+	lock     sync.Mutex
+	expiries map[types.Hash]*Expiry
+	txns     map[types.Hash]*types.Transaction
+}
+
+func newTxExpiries() *txExpiries {
+	return &txExpiries{
+		expiries: make(map[types.Hash]*Expiry),
+		txns:     make(map[types.Hash]*types.Transaction),
+	}
+}
+
+// set records the expiry of the transaction
+func (e *txExpiries) set(tx *types.Transaction, expiry *Expiry) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.expiries[tx.Hash] = expiry
+	e.txns[tx.Hash] = tx
+}
+
+// get returns the expiry of the transaction, or nil if it is unbounded
+func (e *txExpiries) get(hash types.Hash) *Expiry {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.expiries[hash]
+}
+
+// txs returns the transactions that have an expiry
+func (e *txExpiries) txs() []*types.Transaction {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	txs := make([]*types.Transaction, 0, len(e.txns))
+	for _, tx := range e.txns {
+		txs = append(txs, tx)
+	}
+
+	return txs
+}
+
+// forget drops the expiry of a transaction that left the pool
+func (e *txExpiries) forget(hash types.Hash) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.expiries, hash)
+	delete(e.txns, hash)
+}
@@ -0,0 +1,110 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHeadStore struct {
+	mockStore
+	number uint64
+}
+
+func (m *mockHeadStore) Header() *types.Header {
+	return &types.Header{Number: m.number}
+}
+
+func newExpiryTestPool(t *testing.T) (*TxPool, *mockHeadStore) {
+	store := &mockHeadStore{}
+
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	return pool, store
+}
+
+func TestExpiry_Expired(t *testing.T) {
+	var unbounded *Expiry
+	assert.False(t, unbounded.expired(100, 100))
+	assert.False(t, (&Expiry{}).expired(100, 100))
+
+	expiry := &Expiry{MaxBlockNumber: 10, MaxTimestamp: 1000}
+	assert.False(t, expiry.expired(10, 1000))
+	assert.True(t, expiry.expired(11, 1000))
+	assert.True(t, expiry.expired(10, 1001))
+}
+
+func TestExpiry_Add(t *testing.T) {
+	pool, store := newExpiryTestPool(t)
+	store.number = 5
+
+	// the transactions that expired already are rejected
+	tx := generateTx(addr1, big.NewInt(1), big.NewInt(1), nil)
+	assert.ErrorIs(t, pool.AddTxWithExpiry(tx, &Expiry{MaxBlockNumber: 5}), ErrTxExpired)
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Len(t, pool.expiries.expiries, 0)
+
+	assert.NoError(t, pool.AddTxWithExpiry(tx, &Expiry{MaxBlockNumber: 6}))
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, &Expiry{MaxBlockNumber: 6}, pool.expiries.get(tx.Hash))
+}
+
+func TestExpiry_Pop(t *testing.T) {
+	pool, store := newExpiryTestPool(t)
+
+	expiring := generateTx(addr2, big.NewInt(1), big.NewInt(2), nil)
+	assert.NoError(t, pool.AddTxWithExpiry(expiring, &Expiry{MaxBlockNumber: 1}))
+	assert.NoError(t, pool.addImpl(OriginAddTxn, generateTx(addr1, big.NewInt(1), big.NewInt(1), nil)))
+
+	// the expired transaction is dropped instead of being popped, even though it is better priced
+	store.number = 1
+
+	txn, _ := pool.Pop()
+	assert.Equal(t, addr1, txn.From)
+
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, uint64(0), pool.slots)
+	assert.Len(t, pool.expiries.expiries, 0)
+	assert.Equal(t, uint64(0), pool.accountQueues[addr2].accountQueue.nextNonce)
+}
+
+func TestExpiry_Prune(t *testing.T) {
+	pool, store := newExpiryTestPool(t)
+
+	txs := []*types.Transaction{}
+	for _, nonce := range []uint64{0, 1, 3} {
+		tx := generateTx(addr1, big.NewInt(1), big.NewInt(1), nil)
+		tx.Nonce = nonce
+		txs = append(txs, tx)
+	}
+
+	assert.NoError(t, pool.AddTxWithExpiry(txs[0], &Expiry{MaxBlockNumber: 1}))
+	assert.NoError(t, pool.addImpl(OriginAddTxn, txs[1]))
+	assert.NoError(t, pool.addImpl(OriginAddTxn, txs[2]))
+	assert.Equal(t, uint64(2), pool.Length())
+
+	// nothing expires while the transaction can be included in the next block
+	pool.ProcessEvent(&blockchain.Event{})
+	assert.Equal(t, uint64(2), pool.Length())
+
+	// the later pending transactions of the account are dropped with the expired one,
+	// the queued ones are kept
+	store.number = 1
+	pool.ProcessEvent(&blockchain.Event{})
+
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, uint64(1), pool.slots)
+	assert.Len(t, pool.expiries.expiries, 0)
+
+	wrapper := pool.accountQueues[addr1]
+	assert.Equal(t, uint64(0), wrapper.accountQueue.nextNonce)
+	assert.Len(t, wrapper.accountQueue.txs, 1)
+	assert.Equal(t, uint64(3), wrapper.accountQueue.txs[0].Nonce)
+}
@@ -0,0 +1,62 @@
+package txpool
+
+import (
+	"math/big"
+	"sync/atomic"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// SetMinGasPrice sets the minimum gas price, or tip for the dynamic fee transactions,
+// of every transaction accepted by the pool, the local ones included
+func (t *TxPool) SetMinGasPrice(minGasPrice uint64) {
+	atomic.StoreUint64(&t.minGasPrice, minGasPrice)
+}
+
+// SetZeroGasAllowlist sets the accounts whose transactions, sent by or to them,
+// are accepted whatever their gas price, zero included
+func (t *TxPool) SetZeroGasAllowlist(addrs []types.Address) {
+	allowlist := make(map[types.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		allowlist[addr] = struct{}{}
+	}
+
+	t.zeroGasAllowlistLock.Lock()
+	t.zeroGasAllowlist = allowlist
+	t.zeroGasAllowlistLock.Unlock()
+}
+
+// zeroGasAllowed checks if the transaction is sent by or to an account of the zero gas allowlist
+func (t *TxPool) zeroGasAllowed(tx *types.Transaction) bool {
+	t.zeroGasAllowlistLock.RLock()
+	defer t.zeroGasAllowlistLock.RUnlock()
+
+	if _, ok := t.zeroGasAllowlist[tx.From]; ok {
+		return true
+	}
+	if tx.To != nil {
+		if _, ok := t.zeroGasAllowlist[*tx.To]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkGasPrice checks the gas price of the transaction against the minimum gas price of the node,
+// and of the remote transactions against the price limit
+func (t *TxPool) checkGasPrice(tx *types.Transaction, isLocal bool) error {
+	if t.zeroGasAllowed(tx) {
+		return nil
+	}
+
+	tip := tx.GetGasTipCap()
+	if tip.Cmp(new(big.Int).SetUint64(atomic.LoadUint64(&t.minGasPrice))) < 0 {
+		return ErrUnderpriced
+	}
+	if !isLocal && tip.Cmp(new(big.Int).SetUint64(t.priceLimit)) < 0 {
+		return ErrUnderpriced
+	}
+
+	return nil
+}
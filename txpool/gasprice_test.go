@@ -0,0 +1,37 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasPrice_MinGasPrice(t *testing.T) {
+	pool, _ := newQueueTestPool(t, false)
+	pool.SetMinGasPrice(10)
+
+	// the minimum gas price applies to the local transactions too
+	assert.ErrorIs(t, pool.addImpl(OriginAddTxn, queueTestTx(addr1, 0, 9)), ErrUnderpriced)
+	assert.NoError(t, pool.addImpl(OriginAddTxn, queueTestTx(addr1, 0, 10)))
+}
+
+func TestGasPrice_ZeroGasAllowlist(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+	pool.SetMinGasPrice(10)
+	pool.SetZeroGasAllowlist([]types.Address{addr1, addr3})
+
+	// sent by an allowed account
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 0)))
+
+	// sent to an allowed account
+	toAllowed := queueTestTx(addr2, 0, 0)
+	toAllowed.To = &addr3
+	assert.NoError(t, pool.addImpl(OriginGossip, toAllowed))
+
+	notAllowed := queueTestTx(addr2, 1, 0)
+	notAllowed.To = &addr2
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, notAllowed), ErrUnderpriced)
+
+	assert.Equal(t, uint64(2), pool.Length())
+}
@@ -0,0 +1,212 @@
+package txpool
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultJournalRotation is how often the journal is rewritten with the local transactions still in the pool
+const DefaultJournalRotation = time.Hour
+
+// txJournal is an append only file of the local transactions of the pool,
+// replayed at startup so the transactions that were not mined survive a restart.
+// Every line holds the hex encoded RLP of a transaction
+type txJournal struct {
+	lock   sync.Mutex
+	path   string
+	writer *os.File
+}
+
+func newTxJournal(path string) *txJournal {
+	return &txJournal{
+		path: path,
+	}
+}
+
+// load reads the transactions of the journal. A missing journal holds no transactions,
+// and the lines that can't be decoded, like a line cut by a crash, are skipped
+func (j *txJournal) load() ([]*types.Transaction, error) {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	txs := []*types.Transaction{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 2*txMaxSize+16)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		raw, err := hex.DecodeHex(line)
+		if err != nil {
+			continue
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalRLP(raw); err != nil {
+			continue
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, scanner.Err()
+}
+
+// insert appends the transaction to the journal
+func (j *txJournal) insert(tx *types.Transaction) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.writer == nil {
+		writer, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+		j.writer = writer
+	}
+
+	return writeJournalTx(j.writer, tx)
+}
+
+// rotate replaces the journal with one holding only the given transactions
+func (j *txJournal) rotate(txs []*types.Transaction) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return err
+		}
+		j.writer = nil
+	}
+
+	// write the new journal aside, and move it over the old one once complete
+	tmpPath := filepath.Join(filepath.Dir(j.path), "."+filepath.Base(j.path)+".new")
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err := writeJournalTx(tmp, tx); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+
+	writer, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	j.writer = writer
+
+	return nil
+}
+
+// close flushes the journal file to the disk, and closes it
+func (j *txJournal) close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Sync()
+	if closeErr := j.writer.Close(); err == nil {
+		err = closeErr
+	}
+	j.writer = nil
+
+	return err
+}
+
+func writeJournalTx(w io.Writer, tx *types.Transaction) error {
+	_, err := fmt.Fprintln(w, hex.EncodeToHex(tx.MarshalRLP()))
+
+	return err
+}
+
+// SetJournal enables the journal of the local transactions at the given path.
+// It has to be called before Start, which replays the journal
+func (t *TxPool) SetJournal(path string) {
+	t.journal = newTxJournal(path)
+}
+
+// loadJournal adds the transactions of the journal back to the pool as local transactions,
+// and rewrites the journal with the ones that are still valid
+func (t *TxPool) loadJournal() {
+	txs, err := t.journal.load()
+	if err != nil {
+		t.logger.Error("failed to load the txpool journal", "path", t.journal.path, "err", err)
+	}
+
+	added := 0
+	for _, tx := range txs {
+		if err := t.addImpl(OriginJournal, tx); err != nil {
+			t.logger.Debug("dropped journaled txn", "hash", tx.Hash, "err", err)
+			continue
+		}
+		t.broadcast(tx, nil)
+		added++
+	}
+	if len(txs) != 0 {
+		t.logger.Info("loaded the txpool journal", "txns", len(txs), "added", added)
+	}
+
+	t.rotateJournal()
+}
+
+// journalTx appends a local transaction to the journal, if enabled
+func (t *TxPool) journalTx(tx *types.Transaction) {
+	if t.journal == nil {
+		return
+	}
+
+	if err := t.journal.insert(tx); err != nil {
+		t.logger.Error("failed to journal txn", "hash", tx.Hash, "err", err)
+	}
+}
+
+// rotateJournal rewrites the journal with the transactions of the local accounts still in the pool
+func (t *TxPool) rotateJournal() {
+	if t.journal == nil {
+		return
+	}
+
+	txs := []*types.Transaction{}
+	for _, addr := range t.locals.addrs() {
+		if accountTxs, ok := t.getAccountTxs(addr); ok {
+			txs = append(txs, accountTxs.pending...)
+			txs = append(txs, accountTxs.queued...)
+		}
+	}
+
+	if err := t.journal.rotate(txs); err != nil {
+		t.logger.Error("failed to rotate the txpool journal", "path", t.journal.path, "err", err)
+	}
+}
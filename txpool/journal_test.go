@@ -0,0 +1,104 @@
+package txpool
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/helper/tests"
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal_ReplayLocalTxs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool-journal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "txpool.journal")
+	signer := crypto.NewEIP155Signer(100)
+	key, addr := tests.GenerateKeyAndAddr(t)
+
+	newPool := func(store *mockStore) *TxPool {
+		pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+		assert.NoError(t, err)
+		pool.AddSigner(signer)
+		pool.SetJournal(path)
+		pool.Start()
+
+		return pool
+	}
+
+	signedTx := func(nonce uint64) *types.Transaction {
+		tx, err := signer.SignTx(&types.Transaction{
+			To:       &addr,
+			Nonce:    nonce,
+			Gas:      validGasLimit,
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(1),
+		}, key)
+		assert.NoError(t, err)
+
+		return tx
+	}
+
+	store := &mockStore{nonces: map[types.Address]uint64{}}
+
+	pool := newPool(store)
+	assert.NoError(t, pool.AddTx(signedTx(0)))
+	assert.NoError(t, pool.AddTx(signedTx(1)))
+	assert.NoError(t, pool.AddTx(signedTx(3)))
+	pool.Close()
+
+	// the local transactions survive the restart
+	pool = newPool(store)
+	assert.Equal(t, uint64(2), pool.Length())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr))
+	pool.Close()
+
+	// the mined transactions are dropped from the journal on the next replay
+	store.nonces[addr] = 2
+	pool = newPool(store)
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr))
+	pool.Close()
+
+	txs, err := newTxJournal(path).load()
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, uint64(3), txs[0].Nonce)
+}
+
+func TestJournal_SkipCorruptedLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool-journal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "txpool.journal")
+	journal := newTxJournal(path)
+
+	tx := &types.Transaction{
+		Nonce:    1,
+		Gas:      validGasLimit,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	}
+	assert.NoError(t, journal.insert(tx))
+	assert.NoError(t, journal.close())
+
+	// a line cut by a crash
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	assert.NoError(t, err)
+	_, err = file.WriteString("0xf86b01")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	txs, err := journal.load()
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, uint64(1), txs[0].Nonce)
+}
@@ -0,0 +1,80 @@
+package txpool
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultMaxAccountQueued is the default maximum number of queued transactions per account
+const DefaultMaxAccountQueued = 64
+
+var ErrAccountQueueFull = errors.New("too many queued transactions for the account")
+
+// SetAccountLimits sets the maximum number of pending and queued transactions per account, 0 is unlimited.
+// The executable transactions over the pending limit wait in the account queue.
+// The local accounts are not bound by the queued limit
+func (t *TxPool) SetAccountLimits(maxPending, maxQueued uint64) {
+	t.maxAccountPending = maxPending
+	t.maxAccountQueued = maxQueued
+}
+
+// accountQueueFull checks if the account queue has no room for the transaction.
+// The transaction filling the next nonce is always accepted, since it unblocks the queue.
+// The account queue lock must be held
+func (t *TxPool) accountQueueFull(queue *txHeapWrapper, tx *types.Transaction, isLocal bool) bool {
+	if t.maxAccountQueued == 0 || isLocal || tx.Nonce == queue.nextNonce {
+		return false
+	}
+
+	return uint64(len(queue.txs)) >= t.maxAccountQueued
+}
+
+// evictTx drops a transaction discarded from the pool, which is already out of the remote transactions.
+// The highest nonce remote transactions are evicted first, so only the local transactions of the account
+// can be after it. A pending transaction would leave them a nonce gap, so they are moved back to the account
+// queue, which isn't limited for the local accounts, until the gap is filled again
+func (t *TxPool) evictTx(tx *types.Transaction, reason DropReason) {
+	mux := t.lockAccountQueue(tx.From, true)
+	defer mux.unlock()
+
+	queue := mux.accountQueue
+
+	switch {
+	case queue.Remove(tx.Hash):
+	case t.pendingQueue.Delete(tx):
+		for _, pending := range t.pendingQueue.txsFrom(tx.From) {
+			if pending.Nonce > tx.Nonce {
+				t.pendingQueue.Delete(pending)
+				queue.Push(pending)
+			}
+		}
+		if queue.nextNonce > tx.Nonce {
+			queue.nextNonce = tx.Nonce
+		}
+
+	default:
+		// popped by the sealer, its slots are already released
+		t.forgetTx(tx.Hash)
+
+		return
+	}
+
+	t.dropTxs([]*types.Transaction{tx}, reason)
+	t.metrics.EvictedTxs.Add(1)
+
+	t.logger.Debug("evicted txn", "hash", tx.Hash, "from", tx.From, "nonce", tx.Nonce)
+}
+
+// numQueued returns the number of transactions waiting in the account queues
+func (t *TxPool) numQueued() int {
+	queued := 0
+	for _, addr := range t.queueAddrs() {
+		if mux := t.lockExistingAccountQueue(addr, false); mux != nil {
+			queued += len(mux.accountQueue.txs)
+			mux.unlock()
+		}
+	}
+
+	return queued
+}
@@ -0,0 +1,78 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_AccountQueued(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+	pool.SetAccountLimits(0, 2)
+
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 1, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 1)))
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 3, 1)), ErrAccountQueueFull)
+
+	// a replacement takes no more room
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 2)))
+
+	// the local accounts are not limited
+	pool.locals.addAddr(addr2)
+	for nonce := uint64(1); nonce <= 3; nonce++ {
+		assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr2, nonce, 1)))
+	}
+
+	// the next nonce is accepted and unblocks the queue
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 1)))
+	assert.Equal(t, uint64(3), pool.Length())
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 3, 1)))
+}
+
+func TestLimits_AccountPending(t *testing.T) {
+	pool, store := newQueueTestPool(t, true)
+	pool.SetAccountLimits(2, 0)
+
+	for nonce := uint64(0); nonce < 4; nonce++ {
+		assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, nonce, 1)))
+	}
+
+	// the executable transactions over the limit wait in the account queue
+	assert.Equal(t, uint64(2), pool.Length())
+	assert.Equal(t, 2, pool.NumAccountTxs(addr1))
+
+	// and are promoted as the pending ones are executed
+	tx, _ := pool.Pop()
+	assert.Equal(t, uint64(0), tx.Nonce)
+	store.nonces[addr1] = 1
+	pool.promoteExecuted()
+
+	assert.Equal(t, uint64(2), pool.Length())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr1))
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(3), nonce)
+}
+
+func TestLimits_EvictCheapestPending(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+	pool.maxSlots = 3
+
+	tx0, tx1 := queueTestTx(addr1, 0, 1), queueTestTx(addr1, 1, 5)
+	assert.NoError(t, pool.addImpl(OriginGossip, tx0))
+	assert.NoError(t, pool.addImpl(OriginGossip, tx1))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr2, 0, 3)))
+
+	// the full pool evicts the highest nonce of the sender of the cheapest transaction, for a better paying one
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr3, 0, 4)))
+	assert.Equal(t, uint64(3), pool.slots)
+	assert.Nil(t, pool.remoteTxns.get(tx1.Hash))
+
+	// the transactions of the sender before the evicted nonce stay pending
+	assert.Equal(t, uint64(3), pool.Length())
+	assert.Equal(t, 0, pool.NumAccountTxs(addr1))
+	assert.NotNil(t, pool.remoteTxns.get(tx0.Hash))
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(1), nonce)
+}
@@ -11,6 +11,15 @@ import (
 type Metrics struct {
 	// Pending transactions
 	PendingTxs metrics.Gauge
+
+	// Queued transactions, waiting for a nonce gap to be filled
+	QueuedTxs metrics.Gauge
+
+	// Slots taken by the transactions in the pool
+	Slots metrics.Gauge
+
+	// Transactions evicted to make room in the pool or for being idle
+	EvictedTxs metrics.Counter
 }
 
 // GetPrometheusMetrics return the txpool metrics instance
@@ -28,6 +37,24 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "pendingTxs",
 			Help:      "Pending transactions in the pool",
 		}, labels).With(labelsWithValues...),
+		QueuedTxs: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "queuedTxs",
+			Help:      "Queued transactions in the pool",
+		}, labels).With(labelsWithValues...),
+		Slots: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "slots",
+			Help:      "Slots taken by the transactions in the pool",
+		}, labels).With(labelsWithValues...),
+		EvictedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "evictedTxs",
+			Help:      "Transactions evicted from the pool",
+		}, labels).With(labelsWithValues...),
 	}
 }
 
@@ -35,5 +62,8 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 func NilMetrics() *Metrics {
 	return &Metrics{
 		PendingTxs: discard.NewGauge(),
+		QueuedTxs:  discard.NewGauge(),
+		Slots:      discard.NewGauge(),
+		EvictedTxs: discard.NewCounter(),
 	}
 }
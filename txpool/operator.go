@@ -3,6 +3,7 @@ package txpool
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/txpool/proto"
 	"github.com/0xPolygon/polygon-sdk/types"
@@ -49,3 +50,61 @@ func (t *TxPool) Subscribe(req *empty.Empty, stream proto.TxnPoolOperator_Subscr
 	// TODO
 	return nil
 }
+
+// Inspect implements the operator endpoint. Returns the pending and queued transactions of an account
+func (t *TxPool) Inspect(ctx context.Context, req *proto.InspectReq) (*proto.InspectResp, error) {
+	addr := types.Address{}
+	if err := addr.UnmarshalText([]byte(req.Account)); err != nil {
+		return nil, err
+	}
+
+	stateNonce := t.store.GetNonce(t.store.Header().StateRoot, addr)
+
+	resp := &proto.InspectResp{
+		Account:    addr.String(),
+		StateNonce: stateNonce,
+		NextNonce:  stateNonce,
+	}
+
+	if txs, ok := t.getAccountTxs(addr); ok {
+		resp.NextNonce = txs.nextNonce
+		resp.Pending = t.toInspectTxns(txs.pending)
+		resp.Queued = t.toInspectTxns(txs.queued)
+	}
+
+	return resp, nil
+}
+
+// Evict implements the operator endpoint. Drops all the transactions of an account from the pool
+func (t *TxPool) Evict(ctx context.Context, req *proto.EvictReq) (*proto.EvictResp, error) {
+	addr := types.Address{}
+	if err := addr.UnmarshalText([]byte(req.Account)); err != nil {
+		return nil, err
+	}
+
+	evicted := t.EvictAccount(addr)
+	t.logger.Info("evicted account transactions", "account", addr, "count", len(evicted))
+
+	return &proto.EvictResp{Count: uint64(len(evicted))}, nil
+}
+
+// toInspectTxns converts the transactions to their inspect representation
+func (t *TxPool) toInspectTxns(txs []*types.Transaction) []*proto.InspectResp_Txn {
+	res := make([]*proto.InspectResp_Txn, 0, len(txs))
+	for _, tx := range txs {
+		var age uint64
+		if receivedAt := t.arrivals.get(tx.Hash); !receivedAt.IsZero() {
+			age = uint64(time.Since(receivedAt) / time.Second)
+		}
+
+		res = append(res, &proto.InspectResp_Txn{
+			Hash:     tx.Hash.String(),
+			Nonce:    tx.Nonce,
+			GasPrice: tx.GasPrice.String(),
+			Gas:      tx.Gas,
+			Age:      age,
+		})
+	}
+
+	return res
+}
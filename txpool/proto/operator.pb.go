@@ -167,6 +167,306 @@ func (*TxPoolEvent) Descriptor() ([]byte, []int) {
 	return file_txpool_proto_operator_proto_rawDescGZIP(), []int{2}
 }
 
+type InspectReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account string `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *InspectReq) Reset() {
+	*x = InspectReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_operator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InspectReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectReq) ProtoMessage() {}
+
+func (x *InspectReq) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_operator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectReq.ProtoReflect.Descriptor instead.
+func (*InspectReq) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_operator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InspectReq) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+type InspectResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account    string             `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	StateNonce uint64             `protobuf:"varint,2,opt,name=stateNonce,proto3" json:"stateNonce,omitempty"`
+	NextNonce  uint64             `protobuf:"varint,3,opt,name=nextNonce,proto3" json:"nextNonce,omitempty"`
+	Pending    []*InspectResp_Txn `protobuf:"bytes,4,rep,name=pending,proto3" json:"pending,omitempty"`
+	Queued     []*InspectResp_Txn `protobuf:"bytes,5,rep,name=queued,proto3" json:"queued,omitempty"`
+}
+
+func (x *InspectResp) Reset() {
+	*x = InspectResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_operator_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InspectResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectResp) ProtoMessage() {}
+
+func (x *InspectResp) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_operator_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectResp.ProtoReflect.Descriptor instead.
+func (*InspectResp) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_operator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InspectResp) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *InspectResp) GetStateNonce() uint64 {
+	if x != nil {
+		return x.StateNonce
+	}
+	return 0
+}
+
+func (x *InspectResp) GetNextNonce() uint64 {
+	if x != nil {
+		return x.NextNonce
+	}
+	return 0
+}
+
+func (x *InspectResp) GetPending() []*InspectResp_Txn {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+func (x *InspectResp) GetQueued() []*InspectResp_Txn {
+	if x != nil {
+		return x.Queued
+	}
+	return nil
+}
+
+type EvictReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account string `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *EvictReq) Reset() {
+	*x = EvictReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_operator_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvictReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvictReq) ProtoMessage() {}
+
+func (x *EvictReq) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_operator_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvictReq.ProtoReflect.Descriptor instead.
+func (*EvictReq) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_operator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EvictReq) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+type EvictResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count uint64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *EvictResp) Reset() {
+	*x = EvictResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_operator_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvictResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvictResp) ProtoMessage() {}
+
+func (x *EvictResp) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_operator_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvictResp.ProtoReflect.Descriptor instead.
+func (*EvictResp) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_operator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EvictResp) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type InspectResp_Txn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash     string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Nonce    uint64 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	GasPrice string `protobuf:"bytes,3,opt,name=gasPrice,proto3" json:"gasPrice,omitempty"`
+	Gas      uint64 `protobuf:"varint,4,opt,name=gas,proto3" json:"gas,omitempty"`
+	// age of the transaction in the pool, in seconds
+	Age uint64 `protobuf:"varint,5,opt,name=age,proto3" json:"age,omitempty"`
+}
+
+func (x *InspectResp_Txn) Reset() {
+	*x = InspectResp_Txn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_operator_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InspectResp_Txn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectResp_Txn) ProtoMessage() {}
+
+func (x *InspectResp_Txn) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_operator_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectResp_Txn.ProtoReflect.Descriptor instead.
+func (*InspectResp_Txn) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_operator_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *InspectResp_Txn) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *InspectResp_Txn) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *InspectResp_Txn) GetGasPrice() string {
+	if x != nil {
+		return x.GasPrice
+	}
+	return ""
+}
+
+func (x *InspectResp_Txn) GetGas() uint64 {
+	if x != nil {
+		return x.Gas
+	}
+	return 0
+}
+
+func (x *InspectResp_Txn) GetAge() uint64 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
 var File_txpool_proto_operator_proto protoreflect.FileDescriptor
 
 var file_txpool_proto_operator_proto_rawDesc = []byte{
@@ -183,20 +483,52 @@ var file_txpool_proto_operator_proto_rawDesc = []byte{
 	0x6f, 0x6d, 0x22, 0x2b, 0x0a, 0x11, 0x54, 0x78, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61,
 	0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74,
 	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22,
-	0x0d, 0x0a, 0x0b, 0x54, 0x78, 0x50, 0x6f, 0x6f, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x32, 0xb3,
-	0x01, 0x0a, 0x0f, 0x54, 0x78, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
-	0x6f, 0x72, 0x12, 0x37, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x6e, 0x50, 0x6f, 0x6f,
-	0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x41,
-	0x64, 0x64, 0x54, 0x78, 0x6e, 0x12, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x54, 0x78,
-	0x6e, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x36, 0x0a, 0x09,
-	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x50, 0x6f, 0x6f, 0x6c, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x30, 0x01, 0x42, 0x0f, 0x5a, 0x0d, 0x2f, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0d, 0x0a, 0x0b, 0x54, 0x78, 0x50, 0x6f, 0x6f, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x26,
+	0x0a, 0x0a, 0x49, 0x6e, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb2, 0x02, 0x0a, 0x0b, 0x49, 0x6e, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x2d,
+	0x0a, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x2e, 0x54, 0x78, 0x6e, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x2b, 0x0a,
+	0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x2e, 0x54,
+	0x78, 0x6e, 0x52, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x1a, 0x6f, 0x0a, 0x03, 0x54, 0x78,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x67,
+	0x61, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x67,
+	0x61, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x61, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x67, 0x61, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x67, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x61, 0x67, 0x65, 0x22, 0x24, 0x0a, 0x08, 0x45,
+	0x76, 0x69, 0x63, 0x74, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x21, 0x0a, 0x09, 0x45, 0x76, 0x69, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x32, 0x85, 0x02, 0x0a, 0x0f, 0x54, 0x78, 0x6e, 0x50, 0x6f, 0x6f, 0x6c,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x37, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x78, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x12, 0x2f, 0x0a, 0x06, 0x41, 0x64, 0x64, 0x54, 0x78, 0x6e, 0x12, 0x0d, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x64, 0x64, 0x54, 0x78, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x36, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x50,
+	0x6f, 0x6f, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2a, 0x0a, 0x07, 0x49, 0x6e,
+	0x73, 0x70, 0x65, 0x63, 0x74, 0x12, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x24, 0x0a, 0x05, 0x45, 0x76, 0x69, 0x63, 0x74, 0x12,
+	0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x69, 0x63, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x0d, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x76, 0x69, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x42, 0x0f, 0x5a, 0x0d,
+	0x2f, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -211,27 +543,38 @@ func file_txpool_proto_operator_proto_rawDescGZIP() []byte {
 	return file_txpool_proto_operator_proto_rawDescData
 }
 
-var file_txpool_proto_operator_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_txpool_proto_operator_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_txpool_proto_operator_proto_goTypes = []interface{}{
 	(*AddTxnReq)(nil),         // 0: v1.AddTxnReq
 	(*TxnPoolStatusResp)(nil), // 1: v1.TxnPoolStatusResp
 	(*TxPoolEvent)(nil),       // 2: v1.TxPoolEvent
-	(*any.Any)(nil),           // 3: google.protobuf.Any
-	(*empty.Empty)(nil),       // 4: google.protobuf.Empty
+	(*InspectReq)(nil),        // 3: v1.InspectReq
+	(*InspectResp)(nil),       // 4: v1.InspectResp
+	(*EvictReq)(nil),          // 5: v1.EvictReq
+	(*EvictResp)(nil),         // 6: v1.EvictResp
+	(*InspectResp_Txn)(nil),   // 7: v1.InspectResp.Txn
+	(*any.Any)(nil),           // 8: google.protobuf.Any
+	(*empty.Empty)(nil),       // 9: google.protobuf.Empty
 }
 var file_txpool_proto_operator_proto_depIdxs = []int32{
-	3, // 0: v1.AddTxnReq.raw:type_name -> google.protobuf.Any
-	4, // 1: v1.TxnPoolOperator.Status:input_type -> google.protobuf.Empty
-	0, // 2: v1.TxnPoolOperator.AddTxn:input_type -> v1.AddTxnReq
-	4, // 3: v1.TxnPoolOperator.Subscribe:input_type -> google.protobuf.Empty
-	1, // 4: v1.TxnPoolOperator.Status:output_type -> v1.TxnPoolStatusResp
-	4, // 5: v1.TxnPoolOperator.AddTxn:output_type -> google.protobuf.Empty
-	2, // 6: v1.TxnPoolOperator.Subscribe:output_type -> v1.TxPoolEvent
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	8, // 0: v1.AddTxnReq.raw:type_name -> google.protobuf.Any
+	7, // 1: v1.InspectResp.pending:type_name -> v1.InspectResp.Txn
+	7, // 2: v1.InspectResp.queued:type_name -> v1.InspectResp.Txn
+	9, // 3: v1.TxnPoolOperator.Status:input_type -> google.protobuf.Empty
+	0, // 4: v1.TxnPoolOperator.AddTxn:input_type -> v1.AddTxnReq
+	9, // 5: v1.TxnPoolOperator.Subscribe:input_type -> google.protobuf.Empty
+	3, // 6: v1.TxnPoolOperator.Inspect:input_type -> v1.InspectReq
+	5, // 7: v1.TxnPoolOperator.Evict:input_type -> v1.EvictReq
+	1, // 8: v1.TxnPoolOperator.Status:output_type -> v1.TxnPoolStatusResp
+	9, // 9: v1.TxnPoolOperator.AddTxn:output_type -> google.protobuf.Empty
+	2, // 10: v1.TxnPoolOperator.Subscribe:output_type -> v1.TxPoolEvent
+	4, // 11: v1.TxnPoolOperator.Inspect:output_type -> v1.InspectResp
+	6, // 12: v1.TxnPoolOperator.Evict:output_type -> v1.EvictResp
+	8, // [8:13] is the sub-list for method output_type
+	3, // [3:8] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_txpool_proto_operator_proto_init() }
@@ -276,6 +619,66 @@ func file_txpool_proto_operator_proto_init() {
 				return nil
 			}
 		}
+		file_txpool_proto_operator_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InspectReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_operator_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InspectResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_operator_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvictReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_operator_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvictResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_operator_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InspectResp_Txn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -283,7 +686,7 @@ func file_txpool_proto_operator_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_txpool_proto_operator_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
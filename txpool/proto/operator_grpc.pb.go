@@ -25,6 +25,10 @@ type TxnPoolOperatorClient interface {
 	AddTxn(ctx context.Context, in *AddTxnReq, opts ...grpc.CallOption) (*empty.Empty, error)
 	// Subscribe subscribes for new events in the txpool
 	Subscribe(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (TxnPoolOperator_SubscribeClient, error)
+	// Inspect returns the pending and queued transactions of a single account
+	Inspect(ctx context.Context, in *InspectReq, opts ...grpc.CallOption) (*InspectResp, error)
+	// Evict removes all the transactions of a single account from the pool
+	Evict(ctx context.Context, in *EvictReq, opts ...grpc.CallOption) (*EvictResp, error)
 }
 
 type txnPoolOperatorClient struct {
@@ -85,6 +89,24 @@ func (x *txnPoolOperatorSubscribeClient) Recv() (*TxPoolEvent, error) {
 	return m, nil
 }
 
+func (c *txnPoolOperatorClient) Inspect(ctx context.Context, in *InspectReq, opts ...grpc.CallOption) (*InspectResp, error) {
+	out := new(InspectResp)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolOperator/Inspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txnPoolOperatorClient) Evict(ctx context.Context, in *EvictReq, opts ...grpc.CallOption) (*EvictResp, error) {
+	out := new(EvictResp)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolOperator/Evict", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TxnPoolOperatorServer is the server API for TxnPoolOperator service.
 // All implementations must embed UnimplementedTxnPoolOperatorServer
 // for forward compatibility
@@ -95,6 +117,10 @@ type TxnPoolOperatorServer interface {
 	AddTxn(context.Context, *AddTxnReq) (*empty.Empty, error)
 	// Subscribe subscribes for new events in the txpool
 	Subscribe(*empty.Empty, TxnPoolOperator_SubscribeServer) error
+	// Inspect returns the pending and queued transactions of a single account
+	Inspect(context.Context, *InspectReq) (*InspectResp, error)
+	// Evict removes all the transactions of a single account from the pool
+	Evict(context.Context, *EvictReq) (*EvictResp, error)
 	mustEmbedUnimplementedTxnPoolOperatorServer()
 }
 
@@ -111,6 +137,12 @@ func (UnimplementedTxnPoolOperatorServer) AddTxn(context.Context, *AddTxnReq) (*
 func (UnimplementedTxnPoolOperatorServer) Subscribe(*empty.Empty, TxnPoolOperator_SubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
+func (UnimplementedTxnPoolOperatorServer) Inspect(context.Context, *InspectReq) (*InspectResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inspect not implemented")
+}
+func (UnimplementedTxnPoolOperatorServer) Evict(context.Context, *EvictReq) (*EvictResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evict not implemented")
+}
 func (UnimplementedTxnPoolOperatorServer) mustEmbedUnimplementedTxnPoolOperatorServer() {}
 
 // UnsafeTxnPoolOperatorServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +213,42 @@ func (x *txnPoolOperatorSubscribeServer) Send(m *TxPoolEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _TxnPoolOperator_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolOperatorServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolOperator/Inspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolOperatorServer).Inspect(ctx, req.(*InspectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TxnPoolOperator_Evict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvictReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolOperatorServer).Evict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolOperator/Evict",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolOperatorServer).Evict(ctx, req.(*EvictReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TxnPoolOperator_ServiceDesc is the grpc.ServiceDesc for TxnPoolOperator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -196,6 +264,14 @@ var TxnPoolOperator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddTxn",
 			Handler:    _TxnPoolOperator_AddTxn_Handler,
 		},
+		{
+			MethodName: "Inspect",
+			Handler:    _TxnPoolOperator_Inspect_Handler,
+		},
+		{
+			MethodName: "Evict",
+			Handler:    _TxnPoolOperator_Evict_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -9,6 +9,7 @@ package proto
 import (
 	proto "github.com/golang/protobuf/proto"
 	any "github.com/golang/protobuf/ptypes/any"
+	empty "github.com/golang/protobuf/ptypes/empty"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -32,6 +33,10 @@ type Txn struct {
 	unknownFields protoimpl.UnknownFields
 
 	Raw *any.Any `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	// last block number the transaction can be included in, zero if unbounded
+	MaxBlockNumber uint64 `protobuf:"varint,2,opt,name=maxBlockNumber,proto3" json:"maxBlockNumber,omitempty"`
+	// last block timestamp the transaction can be included at, zero if unbounded
+	MaxTimestamp uint64 `protobuf:"varint,3,opt,name=maxTimestamp,proto3" json:"maxTimestamp,omitempty"`
 }
 
 func (x *Txn) Reset() {
@@ -73,17 +78,143 @@ func (x *Txn) GetRaw() *any.Any {
 	return nil
 }
 
+func (x *Txn) GetMaxBlockNumber() uint64 {
+	if x != nil {
+		return x.MaxBlockNumber
+	}
+	return 0
+}
+
+func (x *Txn) GetMaxTimestamp() uint64 {
+	if x != nil {
+		return x.MaxTimestamp
+	}
+	return 0
+}
+
+type TxnHashes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hashes [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (x *TxnHashes) Reset() {
+	*x = TxnHashes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_v1_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxnHashes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnHashes) ProtoMessage() {}
+
+func (x *TxnHashes) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_v1_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnHashes.ProtoReflect.Descriptor instead.
+func (*TxnHashes) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_v1_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TxnHashes) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type Txns struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txns []*Txn `protobuf:"bytes,1,rep,name=txns,proto3" json:"txns,omitempty"`
+}
+
+func (x *Txns) Reset() {
+	*x = Txns{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txpool_proto_v1_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Txns) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Txns) ProtoMessage() {}
+
+func (x *Txns) ProtoReflect() protoreflect.Message {
+	mi := &file_txpool_proto_v1_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Txns.ProtoReflect.Descriptor instead.
+func (*Txns) Descriptor() ([]byte, []int) {
+	return file_txpool_proto_v1_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Txns) GetTxns() []*Txn {
+	if x != nil {
+		return x.Txns
+	}
+	return nil
+}
+
 var File_txpool_proto_v1_proto protoreflect.FileDescriptor
 
 var file_txpool_proto_v1_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76,
 	0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x1a, 0x19, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2d, 0x0a, 0x03, 0x54, 0x78, 0x6e, 0x12, 0x26, 0x0a,
-	0x03, 0x72, 0x61, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79,
-	0x52, 0x03, 0x72, 0x61, 0x77, 0x42, 0x0f, 0x5a, 0x0d, 0x2f, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x79, 0x0a, 0x03, 0x54, 0x78, 0x6e, 0x12, 0x26, 0x0a, 0x03, 0x72, 0x61,
+	0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x03, 0x72,
+	0x61, 0x77, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61,
+	0x78, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0c, 0x6d, 0x61, 0x78, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x23,
+	0x0a, 0x09, 0x54, 0x78, 0x6e, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73,
+	0x68, 0x65, 0x73, 0x22, 0x23, 0x0a, 0x04, 0x54, 0x78, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x04, 0x74,
+	0x78, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x78, 0x6e, 0x52, 0x04, 0x74, 0x78, 0x6e, 0x73, 0x32, 0x66, 0x0a, 0x0d, 0x54, 0x78, 0x6e, 0x50,
+	0x6f, 0x6f, 0x6c, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x12, 0x31, 0x0a, 0x08, 0x41, 0x6e, 0x6e,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x12, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x6e, 0x48, 0x61,
+	0x73, 0x68, 0x65, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x22, 0x0a, 0x07,
+	0x47, 0x65, 0x74, 0x54, 0x78, 0x6e, 0x73, 0x12, 0x0d, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x6e,
+	0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x1a, 0x08, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x78, 0x6e, 0x73,
+	0x42, 0x0f, 0x5a, 0x0d, 0x2f, 0x74, 0x78, 0x70, 0x6f, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -98,18 +229,26 @@ func file_txpool_proto_v1_proto_rawDescGZIP() []byte {
 	return file_txpool_proto_v1_proto_rawDescData
 }
 
-var file_txpool_proto_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_txpool_proto_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_txpool_proto_v1_proto_goTypes = []interface{}{
-	(*Txn)(nil),     // 0: v1.Txn
-	(*any.Any)(nil), // 1: google.protobuf.Any
+	(*Txn)(nil),         // 0: v1.Txn
+	(*TxnHashes)(nil),   // 1: v1.TxnHashes
+	(*Txns)(nil),        // 2: v1.Txns
+	(*any.Any)(nil),     // 3: google.protobuf.Any
+	(*empty.Empty)(nil), // 4: google.protobuf.Empty
 }
 var file_txpool_proto_v1_proto_depIdxs = []int32{
-	1, // 0: v1.Txn.raw:type_name -> google.protobuf.Any
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: v1.Txn.raw:type_name -> google.protobuf.Any
+	0, // 1: v1.Txns.txns:type_name -> v1.Txn
+	1, // 2: v1.TxnPoolGossip.Announce:input_type -> v1.TxnHashes
+	1, // 3: v1.TxnPoolGossip.GetTxns:input_type -> v1.TxnHashes
+	4, // 4: v1.TxnPoolGossip.Announce:output_type -> google.protobuf.Empty
+	2, // 5: v1.TxnPoolGossip.GetTxns:output_type -> v1.Txns
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_txpool_proto_v1_proto_init() }
@@ -130,6 +269,30 @@ func file_txpool_proto_v1_proto_init() {
 				return nil
 			}
 		}
+		file_txpool_proto_v1_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxnHashes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txpool_proto_v1_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Txns); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -137,9 +300,9 @@ func file_txpool_proto_v1_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_txpool_proto_v1_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   3,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_txpool_proto_v1_proto_goTypes,
 		DependencyIndexes: file_txpool_proto_v1_proto_depIdxs,
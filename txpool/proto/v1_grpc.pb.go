@@ -0,0 +1,144 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TxnPoolGossipClient is the client API for TxnPoolGossip service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TxnPoolGossipClient interface {
+	// Announce notifies the peer of the transactions added to the pool
+	Announce(ctx context.Context, in *TxnHashes, opts ...grpc.CallOption) (*empty.Empty, error)
+	// GetTxns returns the transactions of the pool with the given hashes,
+	// the ones no longer in the pool are left out
+	GetTxns(ctx context.Context, in *TxnHashes, opts ...grpc.CallOption) (*Txns, error)
+}
+
+type txnPoolGossipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTxnPoolGossipClient(cc grpc.ClientConnInterface) TxnPoolGossipClient {
+	return &txnPoolGossipClient{cc}
+}
+
+func (c *txnPoolGossipClient) Announce(ctx context.Context, in *TxnHashes, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolGossip/Announce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txnPoolGossipClient) GetTxns(ctx context.Context, in *TxnHashes, opts ...grpc.CallOption) (*Txns, error) {
+	out := new(Txns)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolGossip/GetTxns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TxnPoolGossipServer is the server API for TxnPoolGossip service.
+// All implementations must embed UnimplementedTxnPoolGossipServer
+// for forward compatibility
+type TxnPoolGossipServer interface {
+	// Announce notifies the peer of the transactions added to the pool
+	Announce(context.Context, *TxnHashes) (*empty.Empty, error)
+	// GetTxns returns the transactions of the pool with the given hashes,
+	// the ones no longer in the pool are left out
+	GetTxns(context.Context, *TxnHashes) (*Txns, error)
+	mustEmbedUnimplementedTxnPoolGossipServer()
+}
+
+// UnimplementedTxnPoolGossipServer must be embedded to have forward compatible implementations.
+type UnimplementedTxnPoolGossipServer struct {
+}
+
+func (UnimplementedTxnPoolGossipServer) Announce(context.Context, *TxnHashes) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Announce not implemented")
+}
+func (UnimplementedTxnPoolGossipServer) GetTxns(context.Context, *TxnHashes) (*Txns, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxns not implemented")
+}
+func (UnimplementedTxnPoolGossipServer) mustEmbedUnimplementedTxnPoolGossipServer() {}
+
+// UnsafeTxnPoolGossipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TxnPoolGossipServer will
+// result in compilation errors.
+type UnsafeTxnPoolGossipServer interface {
+	mustEmbedUnimplementedTxnPoolGossipServer()
+}
+
+func RegisterTxnPoolGossipServer(s grpc.ServiceRegistrar, srv TxnPoolGossipServer) {
+	s.RegisterService(&TxnPoolGossip_ServiceDesc, srv)
+}
+
+func _TxnPoolGossip_Announce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnHashes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolGossipServer).Announce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolGossip/Announce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolGossipServer).Announce(ctx, req.(*TxnHashes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TxnPoolGossip_GetTxns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnHashes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolGossipServer).GetTxns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolGossip/GetTxns",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolGossipServer).GetTxns(ctx, req.(*TxnHashes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TxnPoolGossip_ServiceDesc is the grpc.ServiceDesc for TxnPoolGossip service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TxnPoolGossip_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.TxnPoolGossip",
+	HandlerType: (*TxnPoolGossipServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Announce",
+			Handler:    _TxnPoolGossip_Announce_Handler,
+		},
+		{
+			MethodName: "GetTxns",
+			Handler:    _TxnPoolGossip_GetTxns_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "txpool/proto/v1.proto",
+}
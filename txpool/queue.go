@@ -0,0 +1,211 @@
+package txpool
+
+import (
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultQueueLifetime is how long the queued transactions of an idle account are kept by default
+const DefaultQueueLifetime = 3 * time.Hour
+
+// SetQueueLifetime sets how long the queued transactions of an account are kept
+// without any transaction of the account being added or promoted
+func (t *TxPool) SetQueueLifetime(lifetime time.Duration) {
+	t.queueLifetime = lifetime
+}
+
+// Start replays the journal, and starts the maintenance loop of the pool, which periodically promotes
+// the queued transactions whose nonce gap was filled by transactions the pool never saw, evicts
+// the queued transactions of the idle accounts and the transactions older than the lifetime,
+// and rotates the journal
+func (t *TxPool) Start() {
+	if t.journal != nil {
+		t.loadJournal()
+	}
+
+	go t.maintenanceLoop()
+}
+
+// Close stops the maintenance loop, the announcements and the reorgs watcher, and closes the journal
+func (t *TxPool) Close() {
+	close(t.closeCh)
+
+	if t.unsubscribeReorgs != nil {
+		t.unsubscribeReorgs()
+	}
+
+	if t.announcer != nil {
+		t.announcer.close()
+	}
+
+	// the journal is left with the local transactions still in the pool
+	if t.journal != nil {
+		t.rotateJournal()
+
+		if err := t.journal.close(); err != nil {
+			t.logger.Error("failed to close the txpool journal", "err", err)
+		}
+	}
+}
+
+func (t *TxPool) maintenanceLoop() {
+	ticker := time.NewTicker(t.idlePeriod)
+	defer ticker.Stop()
+
+	rotation := time.NewTicker(DefaultJournalRotation)
+	defer rotation.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.promoteExecuted()
+			t.evictIdleQueued(time.Now())
+			t.evictOld(time.Now())
+			t.metrics.QueuedTxs.Set(float64(t.numQueued()))
+
+		case <-rotation.C:
+			t.rotateJournal()
+
+		case <-t.closeCh:
+			return
+		}
+	}
+}
+
+// queueAddrs returns the accounts that have a queue in the pool
+func (t *TxPool) queueAddrs() []types.Address {
+	t.accountQueuesLock.Lock()
+	defer t.accountQueuesLock.Unlock()
+
+	addrs := make([]types.Address, 0, len(t.accountQueues))
+	for addr := range t.accountQueues {
+		addrs = append(addrs, addr)
+	}
+
+	return addrs
+}
+
+// promoteExecuted resets the accounts whose state nonce moved past their next nonce in the pool,
+// so the queued transactions don't wait for the nonces executed without going through the pool,
+// and prunes the stale pending transactions of the other accounts. The queues left empty are removed
+func (t *TxPool) promoteExecuted() {
+	stateRoot := t.store.Header().StateRoot
+
+	for _, addr := range t.queueAddrs() {
+		mux := t.lockExistingAccountQueue(addr, false)
+		if mux == nil {
+			continue
+		}
+		nextNonce := mux.accountQueue.nextNonce
+		mux.unlock()
+
+		// The accounts behind the state are only pruned, their transactions may be popped by the sealer
+		stateNonce := t.store.GetNonce(stateRoot, addr)
+		if stateNonce > nextNonce {
+			t.resetAccount(addr, stateNonce)
+		} else {
+			t.pruneAccount(addr, stateNonce)
+		}
+
+		t.removeAccountQueue(addr, stateNonce)
+	}
+}
+
+// resetAccount aligns the transactions of the account with its state nonce. The pending transactions
+// below the state nonce are dropped, and the ones after a nonce gap are moved back to the account queue.
+// Then the queued transactions below the new next nonce are dropped, and the executable ones promoted
+func (t *TxPool) resetAccount(addr types.Address, stateNonce uint64) {
+	mux := t.lockExistingAccountQueue(addr, true)
+	if mux == nil {
+		return
+	}
+	defer mux.unlock()
+
+	queue := mux.accountQueue
+
+	dropped := []*types.Transaction{}
+	nextNonce := stateNonce
+	for _, tx := range t.pendingQueue.txsFrom(addr) {
+		switch {
+		case tx.Nonce < stateNonce:
+			// executed, or replaced by an executed transaction with the same nonce
+			t.pendingQueue.Delete(tx)
+			dropped = append(dropped, tx)
+
+		case tx.Nonce == nextNonce:
+			nextNonce++
+
+		default:
+			// the nonce gap below the transaction, left by a reorg or a discarded
+			// transaction, has to be filled again before it can be executed
+			t.pendingQueue.Delete(tx)
+			if !queue.Push(tx) {
+				dropped = append(dropped, tx)
+			}
+		}
+	}
+
+	queue.nextNonce = nextNonce
+	dropped = append(dropped, queue.pruneLowNonceTx()...)
+
+	t.dropTxs(dropped, DropStale)
+	t.promote(addr, queue)
+
+	t.metrics.PendingTxs.Set(float64(t.pendingQueue.Length()))
+}
+
+// evictIdleQueued drops the queued transactions of the remote accounts that had no transaction
+// added or promoted for longer than the queue lifetime, since their nonce gap is unlikely to be filled
+func (t *TxPool) evictIdleQueued(now time.Time) {
+	for _, addr := range t.queueAddrs() {
+		if t.locals.containsAddr(addr) {
+			continue
+		}
+
+		mux := t.lockExistingAccountQueue(addr, true)
+		if mux == nil {
+			continue
+		}
+		queue := mux.accountQueue
+
+		if len(queue.txs) != 0 && now.Sub(queue.lastActive) > t.queueLifetime {
+			evicted := queue.txs
+			queue.txs = txHeap{}
+			t.dropTxs(evicted, DropLifetime)
+			t.metrics.EvictedTxs.Add(float64(len(evicted)))
+
+			t.logger.Debug("evicted idle queued txns", "from", addr, "count", len(evicted))
+		}
+
+		mux.unlock()
+	}
+}
+
+// dropTxs releases the slots and the bookkeeping of the transactions that left the pool,
+// once they are out of the pending and the account queues, and notifies the subscribers
+func (t *TxPool) dropTxs(txs []*types.Transaction, reason DropReason) {
+	for _, tx := range txs {
+		t.remoteTxns.Delete(tx)
+		t.decreaseSlots(numSlots(tx))
+		t.forgetTx(tx.Hash)
+	}
+	t.notifyDropped(txs, reason)
+}
+
+// senderOf returns the sender of a transaction read from the chain, which doesn't carry it
+func (t *TxPool) senderOf(tx *types.Transaction) (types.Address, bool) {
+	if tx.From != types.ZeroAddress {
+		return tx.From, true
+	}
+	if t.signer == nil {
+		return types.ZeroAddress, false
+	}
+
+	from, err := t.signer.Sender(tx)
+	if err != nil {
+		return types.ZeroAddress, false
+	}
+
+	return from, true
+}
@@ -0,0 +1,187 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueueTestPool(t *testing.T, noLocals bool) (*TxPool, *mockStore) {
+	store := &mockStore{nonces: map[types.Address]uint64{}}
+
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, noLocals, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	return pool, store
+}
+
+func queueTestTx(from types.Address, nonce uint64, price int64) *types.Transaction {
+	return &types.Transaction{
+		From:     from,
+		Nonce:    nonce,
+		Gas:      validGasLimit,
+		GasPrice: big.NewInt(price),
+		Value:    big.NewInt(0),
+	}
+}
+
+func TestQueue_PromoteOnNonceFill(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+
+	// the future nonces are queued
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 3, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 1, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 1)))
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, 3, pool.NumAccountTxs(addr1))
+
+	// filling the gap promotes the whole sequence
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 1)))
+	assert.Equal(t, uint64(4), pool.Length())
+	assert.Equal(t, 0, pool.NumAccountTxs(addr1))
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(4), nonce)
+
+	for i := uint64(0); i < 4; i++ {
+		tx, _ := pool.Pop()
+		assert.Equal(t, i, tx.Nonce)
+	}
+}
+
+func TestQueue_NonceExists(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 1, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 3, 1)))
+	slots := pool.slots
+
+	// a different transaction with the same nonce and price
+	sameNonce := func(nonce uint64) *types.Transaction {
+		tx := queueTestTx(addr1, nonce, 1)
+		tx.Value = big.NewInt(1)
+		return tx
+	}
+
+	// pending nonce
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, sameNonce(1)), ErrReplacementUnderpriced)
+	// queued nonce
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, sameNonce(3)), ErrReplacementUnderpriced)
+
+	// nonce popped by the sealer
+	popped, _ := pool.Pop()
+	assert.Equal(t, uint64(0), popped.Nonce)
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 10)), ErrNonceExists)
+
+	assert.Equal(t, slots-1, pool.slots)
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr1))
+}
+
+func TestQueue_PromoteExecuted(t *testing.T) {
+	pool, store := newQueueTestPool(t, true)
+
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 4, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 5, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 7, 1)))
+	assert.Equal(t, uint64(0), pool.Length())
+
+	// the nonces 0 to 4 are executed without going through the pool
+	store.nonces[addr1] = 5
+	pool.promoteExecuted()
+
+	// the executed nonce is dropped, the next one promoted, the one after the gap is still queued
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr1))
+	assert.Equal(t, uint64(2), pool.slots)
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(6), nonce)
+}
+
+func TestQueue_ResetAccountDemotes(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+
+	txs := []*types.Transaction{
+		queueTestTx(addr1, 0, 1),
+		queueTestTx(addr1, 1, 1),
+		queueTestTx(addr1, 2, 1),
+	}
+	for _, tx := range txs {
+		assert.NoError(t, pool.addImpl(OriginGossip, tx))
+	}
+	assert.Equal(t, uint64(3), pool.Length())
+
+	// the first transaction is discarded, its nonce becomes a gap
+	pool.pendingQueue.Delete(txs[0])
+	pool.dropTxs(txs[:1], DropEvicted)
+	pool.resetAccount(addr1, 0)
+
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, 2, pool.NumAccountTxs(addr1))
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(0), nonce)
+
+	// a new transaction for the nonce promotes them back
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 2)))
+	assert.Equal(t, uint64(3), pool.Length())
+}
+
+func TestQueue_EvictIdle(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+	pool.SetQueueLifetime(time.Minute)
+	pool.locals.addAddr(addr2)
+
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr2, 2, 1)))
+
+	// not idle yet
+	pool.evictIdleQueued(time.Now())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr1))
+
+	pool.evictIdleQueued(time.Now().Add(2 * time.Minute))
+
+	// the pending transaction is kept, and so are the queued transactions of the local accounts
+	assert.Equal(t, 0, pool.NumAccountTxs(addr1))
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, 1, pool.NumAccountTxs(addr2))
+	assert.Equal(t, uint64(2), pool.slots)
+}
+
+func TestQueue_RemoveEmpty(t *testing.T) {
+	pool, store := newQueueTestPool(t, true)
+
+	// the lookups don't create queues
+	store.nonces[addr2] = 3
+	nonce, _ := pool.GetNonce(addr2)
+	assert.Equal(t, uint64(3), nonce)
+	assert.Equal(t, 0, pool.NumAccountTxs(addr2))
+	assert.Empty(t, pool.queueAddrs())
+
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 1)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 1)))
+
+	// the queue is kept while it has transactions
+	tx, _ := pool.Pop()
+	assert.Equal(t, uint64(0), tx.Nonce)
+	store.nonces[addr1] = 1
+	pool.promoteExecuted()
+	assert.Len(t, pool.queueAddrs(), 1)
+
+	// the queue is removed once its transactions are executed
+	store.nonces[addr1] = 3
+	pool.promoteExecuted()
+	assert.Empty(t, pool.queueAddrs())
+
+	nonce, _ = pool.GetNonce(addr1)
+	assert.Equal(t, uint64(3), nonce)
+}
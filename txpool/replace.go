@@ -0,0 +1,67 @@
+package txpool
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultPriceBump is the default minimum price increase, in percent, for replacing a transaction
+const DefaultPriceBump = 10
+
+// ErrReplacementUnderpriced is returned if a transaction with the same nonce is in the pool,
+// and the transaction doesn't pay enough more to replace it
+var ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+
+// ErrReplacedTxSealing is returned if the transaction to replace was taken by the sealer
+var ErrReplacedTxSealing = errors.New("the replaced transaction is being sealed")
+
+// SetPriceBump sets the minimum price increase, in percent, for a transaction to replace
+// the pooled transaction of the same account with the same nonce
+func (t *TxPool) SetPriceBump(percent uint64) {
+	t.priceBump = percent
+}
+
+// replaces checks if the transaction pays enough more than the pooled one with the same nonce to replace it.
+// Both the fee cap and the tip cap have to be raised by the price bump, so a replacement can be used for
+// speeding up a stuck transaction, or for cancelling it with a transfer to self
+func (t *TxPool) replaces(old, tx *types.Transaction) bool {
+	return bumped(old.GasPrice, tx.GasPrice, t.priceBump) &&
+		bumped(old.GetGasTipCap(), tx.GetGasTipCap(), t.priceBump)
+}
+
+// bumped checks if the new price is higher than the old one by at least the given percentage
+func bumped(oldPrice, newPrice *big.Int, percent uint64) bool {
+	if newPrice.Cmp(oldPrice) <= 0 {
+		return false
+	}
+
+	threshold := new(big.Int).Mul(oldPrice, new(big.Int).SetUint64(100+percent))
+	threshold.Div(threshold, big.NewInt(100))
+
+	return newPrice.Cmp(threshold) >= 0
+}
+
+// replace swaps the pooled transaction with the one replacing it, the replacement takes its place
+// in the account queue or in the pending queue. The account queue lock must be held.
+// It fails if the pooled transaction was taken by the sealer in the meantime
+func (t *TxPool) replace(queue *txHeapWrapper, old, tx *types.Transaction) error {
+	t.logger.Debug("replace txn", "old", old.Hash, "new", tx.Hash, "from", tx.From, "nonce", tx.Nonce)
+
+	if queue.Remove(old.Hash) {
+		queue.Push(tx)
+	} else if t.pendingQueue.Delete(old) {
+		if err := t.pendingQueue.Push(tx); err != nil {
+			t.dropTxs([]*types.Transaction{old}, DropReplaced)
+
+			return err
+		}
+	} else {
+		return ErrReplacedTxSealing
+	}
+
+	t.dropTxs([]*types.Transaction{old}, DropReplaced)
+
+	return nil
+}
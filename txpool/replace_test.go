@@ -0,0 +1,122 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumped(t *testing.T) {
+	cases := []struct {
+		old, new int64
+		bumped   bool
+	}{
+		{100, 109, false},
+		{100, 110, true},
+		{100, 200, true},
+		{0, 0, false},
+		{0, 1, true},
+		{5, 5, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.bumped, bumped(big.NewInt(c.old), big.NewInt(c.new), DefaultPriceBump), "%d -> %d", c.old, c.new)
+	}
+}
+
+func TestReplace_Pending(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+
+	old := queueTestTx(addr1, 0, 100)
+	assert.NoError(t, pool.addImpl(OriginGossip, old))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 1, 100)))
+
+	// not enough of a bump
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 105)), ErrReplacementUnderpriced)
+
+	speedUp := queueTestTx(addr1, 0, 110)
+	assert.NoError(t, pool.addImpl(OriginGossip, speedUp))
+
+	assert.Equal(t, uint64(2), pool.Length())
+	assert.Equal(t, uint64(2), pool.slots)
+	assert.False(t, pool.pendingQueue.Contains(old))
+	assert.False(t, pool.remoteTxns.Contains(old))
+	assert.True(t, pool.pendingQueue.Contains(speedUp))
+
+	nonce, _ := pool.GetNonce(addr1)
+	assert.Equal(t, uint64(2), nonce)
+
+	tx, _ := pool.Pop()
+	assert.Equal(t, speedUp.Hash, tx.Hash)
+}
+
+func TestReplace_Sealing(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+
+	old := queueTestTx(addr1, 0, 100)
+	assert.NoError(t, pool.addImpl(OriginGossip, old))
+
+	// the sealer takes the transaction before it is replaced
+	tx, _ := pool.Pop()
+	assert.Equal(t, old.Hash, tx.Hash)
+
+	slots := pool.slots
+	replacement := queueTestTx(addr1, 0, 110)
+	replacement.ComputeHash()
+
+	mux := pool.lockAccountQueue(addr1, true)
+	err := pool.replace(mux.accountQueue, old, replacement)
+	mux.unlock()
+
+	assert.ErrorIs(t, err, ErrReplacedTxSealing)
+	assert.Equal(t, slots, pool.slots)
+	assert.False(t, pool.pendingQueue.Contains(replacement))
+}
+
+func TestReplace_Queued(t *testing.T) {
+	pool, _ := newQueueTestPool(t, true)
+	pool.SetPriceBump(50)
+
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 10)))
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 2, 14)), ErrReplacementUnderpriced)
+
+	// cancel the transaction with a transfer to self
+	cancel := queueTestTx(addr1, 2, 15)
+	cancel.To = &addr1
+	assert.NoError(t, pool.addImpl(OriginGossip, cancel))
+
+	assert.Equal(t, 1, pool.NumAccountTxs(addr1))
+	assert.Equal(t, uint64(1), pool.slots)
+
+	// the replacement is promoted with the rest of the sequence
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 0, 10)))
+	assert.NoError(t, pool.addImpl(OriginGossip, queueTestTx(addr1, 1, 10)))
+	assert.True(t, pool.pendingQueue.Contains(cancel))
+}
+
+func TestReplace_DynamicFeeTx(t *testing.T) {
+	pool, store := newQueueTestPool(t, true)
+	store.baseFee = big.NewInt(1)
+
+	dynamicTx := func(feeCap, tipCap int64) *types.Transaction {
+		return &types.Transaction{
+			Type:      types.DynamicFeeTx,
+			From:      addr1,
+			Gas:       validGasLimit,
+			GasPrice:  big.NewInt(feeCap),
+			GasTipCap: big.NewInt(tipCap),
+			Value:     big.NewInt(0),
+		}
+	}
+
+	assert.NoError(t, pool.addImpl(OriginGossip, dynamicTx(100, 10)))
+
+	// both the fee cap and the tip cap must be bumped
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, dynamicTx(200, 10)), ErrReplacementUnderpriced)
+	assert.ErrorIs(t, pool.addImpl(OriginGossip, dynamicTx(100, 20)), ErrReplacementUnderpriced)
+	assert.NoError(t, pool.addImpl(OriginGossip, dynamicTx(110, 11)))
+
+	assert.Equal(t, uint64(1), pool.Length())
+}
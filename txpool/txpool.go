@@ -2,8 +2,10 @@ package txpool
 
 import (
 	"container/heap"
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sort"
 	"sync"
@@ -12,12 +14,16 @@ import (
 
 	"github.com/0xPolygon/polygon-sdk/blockchain"
 	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/eventbus"
+	"github.com/0xPolygon/polygon-sdk/helper/tracing"
 	"github.com/0xPolygon/polygon-sdk/network"
 	"github.com/0xPolygon/polygon-sdk/state"
 	"github.com/0xPolygon/polygon-sdk/txpool/proto"
 	"github.com/0xPolygon/polygon-sdk/types"
-	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -38,6 +44,9 @@ var (
 	ErrInsufficientFunds   = errors.New("insufficient funds for gas * price + value")
 	ErrInvalidAccountState = errors.New("invalid account state")
 	ErrAlreadyKnown        = errors.New("already known")
+	ErrNonceExists         = errors.New("another transaction with the same nonce is in the pool")
+	ErrTxTypeNotSupported  = errors.New("transaction type not supported")
+	ErrTipAboveFeeCap      = errors.New("max priority fee per gas higher than max fee per gas")
 	// ErrOversizedData is returned if size of a transction is greater than the specified limit
 	ErrOversizedData = errors.New("oversized data")
 )
@@ -48,16 +57,24 @@ const (
 	OriginAddTxn TxOrigin = "addTxn"
 	OriginReorg  TxOrigin = "reorg"
 	OriginGossip TxOrigin = "gossip"
+
+	// OriginJournal is a local transaction replayed from the journal
+	OriginJournal TxOrigin = "journal"
 )
 
 var topicNameV1 = "txpool/0.1"
 
+// tracer records the admission of the transactions, the first span of their trace
+var tracer = tracing.Tracer("txpool")
+
 // store interface defines State helper methods the Txpool should have access to
 type store interface {
 	Header() *types.Header
 	GetNonce(root types.Hash, addr types.Address) uint64
 	GetBalance(root types.Hash, addr types.Address) (*big.Int, error)
 	GetBlockByHash(types.Hash, bool) (*types.Block, bool)
+	CalculateBaseFee(parent *types.Header) *big.Int
+	GetForksInTime(blockNumber uint64) chain.ForksInTime
 }
 
 type signer interface {
@@ -67,8 +84,10 @@ type signer interface {
 // TxPool is module that handles pending transactions.
 //
 // There are fundamentally 2 queues in the txpool module:
-// - Account based transactions (accountQueues)
-// - Global valid transactions, from any account (pendingQueue)
+// - Account based transactions with a future nonce, waiting for the nonce gap to be filled (accountQueues)
+// - Global valid transactions, from any account, that are executable in nonce order (pendingQueue)
+//
+// A queued transaction is promoted to the pending queue once every lower nonce of the account is pending or executed
 type TxPool struct {
 	logger     hclog.Logger
 	signer     signer
@@ -76,8 +95,11 @@ type TxPool struct {
 	store      store
 	idlePeriod time.Duration
 
-	// Unsorted min heap of transactions per account.
-	// The heap is min nonce based
+	// queueLifetime is how long the queued transactions of an account are kept
+	// without any transaction of the account being added or promoted
+	queueLifetime time.Duration
+
+	// Min nonce heap of the queued transactions per account
 	accountQueuesLock sync.Mutex
 	accountQueues     map[types.Address]*accountQueueWrapper
 
@@ -90,11 +112,33 @@ type TxPool struct {
 	// Number of used slots
 	slots uint64
 
+	// Local time the pool first saw each of its transactions
+	arrivals *txArrivals
+
+	// Inclusion bounds of the transactions submitted with an expiry
+	expiries *txExpiries
+
+	// Number of account evictions so far. Used for telling if a transaction
+	// popped by the sealer belongs to an account evicted in the meantime
+	evictions uint64
+
 	// Maximum number of transaction slots for all accounts
 	maxSlots uint64
 
-	// Networking stack
-	topic *network.Topic
+	// Maximum number of pending and queued transactions per account, 0 is unlimited
+	maxAccountPending uint64
+	maxAccountQueued  uint64
+
+	// How long the remote transactions are kept in the pool, 0 is until mined
+	txLifetime time.Duration
+
+	// Subscribers to the transactions dropped without being mined
+	dropped droppedFeed
+
+	// Networking stack. The transactions are announced by hash with the announcer,
+	// the topic only receives the full transactions gossiped by the older nodes
+	topic     *network.Topic
+	announcer *txAnnouncer
 
 	// Flag indicating if the current node is a sealer,
 	// and should therefore gossip transactions
@@ -112,9 +156,32 @@ type TxPool struct {
 	// priceLimit is a lower threshold for gas price
 	priceLimit uint64
 
+	// minGasPrice is the lower threshold for the gas price of the local transactions too
+	minGasPrice uint64
+
+	// zeroGasAllowlist holds the accounts whose transactions are exempt from the gas price thresholds.
+	// Both thresholds are replaced at runtime when the config is reloaded
+	zeroGasAllowlist     map[types.Address]struct{}
+	zeroGasAllowlistLock sync.RWMutex
+
+	// priceBump is the minimum price increase, in percent, for replacing a transaction
+	priceBump uint64
+
 	// Notification channel used so signal added transactions to the pool
 	NotifyCh chan struct{}
 
+	// closeCh stops the maintenance loop
+	closeCh chan struct{}
+
+	// Event bus the new pending transactions are published on, nil if not set
+	bus *eventbus.Bus
+
+	// unsubscribeReorgs ends the subscription to the chain reorgs, nil if not watched
+	unsubscribeReorgs func()
+
+	// Journal of the local transactions, nil if disabled
+	journal *txJournal
+
 	// Indicates which txpool operator commands should be implemented
 	proto.UnimplementedTxnPoolOperatorServer
 
@@ -136,20 +203,27 @@ func NewTxPool(
 	metrics *Metrics,
 ) (*TxPool, error) {
 	txPool := &TxPool{
-		logger:        logger.Named("txpool"),
-		store:         store,
-		idlePeriod:    defaultIdlePeriod,
-		accountQueues: make(map[types.Address]*accountQueueWrapper),
-		pendingQueue:  newMaxTxPriceHeap(),
-		remoteTxns:    newMinTxPriceHeap(),
-		slots:         0,
-		maxSlots:      maxSlots,
-		sealing:       sealing,
-		locals:        newLocalAccounts(locals),
-		noLocals:      noLocals,
-		priceLimit:    priceLimit,
-		forks:         forks,
-		metrics:       metrics,
+		logger:           logger.Named("txpool"),
+		store:            store,
+		idlePeriod:       defaultIdlePeriod,
+		queueLifetime:    DefaultQueueLifetime,
+		txLifetime:       DefaultTxLifetime,
+		accountQueues:    make(map[types.Address]*accountQueueWrapper),
+		pendingQueue:     newMaxTxPriceHeap(),
+		remoteTxns:       newMinTxPriceHeap(),
+		slots:            0,
+		arrivals:         newTxArrivals(),
+		expiries:         newTxExpiries(),
+		maxSlots:         maxSlots,
+		maxAccountQueued: DefaultMaxAccountQueued,
+		sealing:          sealing,
+		locals:           newLocalAccounts(locals),
+		noLocals:         noLocals,
+		priceLimit:       priceLimit,
+		priceBump:        DefaultPriceBump,
+		forks:            forks,
+		metrics:          metrics,
+		closeCh:          make(chan struct{}),
 	}
 
 	if network != nil {
@@ -160,6 +234,11 @@ func NewTxPool(
 		}
 		topic.Subscribe(txPool.handleGossipTxn)
 		txPool.topic = topic
+
+		txPool.announcer = newTxAnnouncer(txPool.logger, txPool, network)
+		if err := txPool.announcer.setup(); err != nil {
+			return nil, err
+		}
 	}
 
 	if grpcServer != nil {
@@ -173,39 +252,98 @@ type accountQueueWrapper struct {
 	lock         sync.RWMutex // lock for accessing the accountQueue
 	writeLock    int32        // flag indicating whether a write lock is held
 	accountQueue *txHeapWrapper
+
+	// removed is set once the queue is deleted from the account queue map, under the write lock.
+	// The callers which got the queue from the map before look it up again
+	removed bool
 }
 
 // lockAccountQueue returns the corresponding account queue wrapper object, or creates it
 // if it doesn't exist in the account queue map
 func (t *TxPool) lockAccountQueue(address types.Address, writer bool) *accountQueueWrapper {
-	// Lock the global map
-	t.accountQueuesLock.Lock()
+	for {
+		// Lock the global map
+		t.accountQueuesLock.Lock()
 
-	accountQueue, ok := t.accountQueues[address]
-	if !ok {
-		// Account queue is not initialized yet, initialize it
-		stateRoot := t.store.Header().StateRoot
+		accountQueue, ok := t.accountQueues[address]
+		if !ok {
+			// Account queue is not initialized yet, initialize it
+			stateRoot := t.store.Header().StateRoot
+
+			// Initialize the account based transaction heap
+			txnsQueue := newTxHeapWrapper()
+			txnsQueue.nextNonce = t.store.GetNonce(stateRoot, address)
+
+			// a removed queue may be created again while a transaction of the account evicted
+			// before is out of the pool, it must not be returned to the pool either
+			txnsQueue.evicted = atomic.LoadUint64(&t.evictions)
+
+			accountQueue = &accountQueueWrapper{accountQueue: txnsQueue}
+			t.accountQueues[address] = accountQueue
+		}
+		// Unlock the global map, since work is finished
+		t.accountQueuesLock.Unlock()
+
+		// Grab the lock for the specific account queue
+		accountQueue.acquire(writer)
+		if !accountQueue.removed {
+			return accountQueue
+		}
+		accountQueue.unlock()
+	}
+}
+
+// lockExistingAccountQueue returns the corresponding account queue wrapper object,
+// or nil if the account has no queue in the pool. Unlike lockAccountQueue,
+// it never creates a queue for the account
+func (t *TxPool) lockExistingAccountQueue(address types.Address, writer bool) *accountQueueWrapper {
+	for {
+		t.accountQueuesLock.Lock()
+		accountQueue, ok := t.accountQueues[address]
+		t.accountQueuesLock.Unlock()
+
+		if !ok {
+			return nil
+		}
 
-		// Initialize the account based transaction heap
-		txnsQueue := newTxHeapWrapper()
-		txnsQueue.nextNonce = t.store.GetNonce(stateRoot, address)
+		accountQueue.acquire(writer)
+		if !accountQueue.removed {
+			return accountQueue
+		}
+		accountQueue.unlock()
+	}
+}
+
+// removeAccountQueue deletes the queue of the account once it has no transaction in the pool, and no
+// transaction out of it either, so the queues of the accounts that were only looked up don't accumulate
+func (t *TxPool) removeAccountQueue(addr types.Address, stateNonce uint64) {
+	mux := t.lockExistingAccountQueue(addr, true)
+	if mux == nil {
+		return
+	}
+	defer mux.unlock()
 
-		accountQueue = &accountQueueWrapper{accountQueue: txnsQueue}
-		t.accountQueues[address] = accountQueue
+	queue := mux.accountQueue
+	if len(queue.txs) != 0 || queue.nextNonce > stateNonce || len(t.pendingQueue.txsFrom(addr)) != 0 {
+		return
 	}
-	// Unlock the global map, since work is finished
+
+	t.accountQueuesLock.Lock()
+	delete(t.accountQueues, addr)
 	t.accountQueuesLock.Unlock()
 
-	// Grab the lock for the specific account queue
+	mux.removed = true
+}
+
+// acquire grabs the account specific transaction queue lock
+func (a *accountQueueWrapper) acquire(writer bool) {
 	if writer {
-		accountQueue.lock.Lock()
-		atomic.StoreInt32(&accountQueue.writeLock, 1)
+		a.lock.Lock()
+		atomic.StoreInt32(&a.writeLock, 1)
 	} else {
-		accountQueue.lock.RLock()
-		atomic.StoreInt32(&accountQueue.writeLock, 0)
+		a.lock.RLock()
+		atomic.StoreInt32(&a.writeLock, 0)
 	}
-
-	return accountQueue
 }
 
 // unlock releases the account specific transaction queue lock.
@@ -220,24 +358,27 @@ func (a *accountQueueWrapper) unlock() {
 	}
 }
 
-// GetNonce returns the next nonce for the account, based on the txpool
+// GetNonce returns the next nonce for the account, based on the txpool.
+// The accounts without a queue in the pool are at their state nonce
 func (t *TxPool) GetNonce(addr types.Address) (uint64, bool) {
-	mux := t.lockAccountQueue(addr, false)
+	mux := t.lockExistingAccountQueue(addr, false)
+	if mux == nil {
+		return t.store.GetNonce(t.store.Header().StateRoot, addr), true
+	}
 	defer mux.unlock()
 
-	wrapper, ok := t.accountQueues[addr]
-	if !ok {
-		return 0, false
-	}
-	return wrapper.accountQueue.nextNonce, true
+	return mux.accountQueue.nextNonce, true
 }
 
 // NumAccountTxs Returns the number of transactions in the account specific queue
 func (t *TxPool) NumAccountTxs(address types.Address) int {
-	mux := t.lockAccountQueue(address, false)
+	mux := t.lockExistingAccountQueue(address, false)
+	if mux == nil {
+		return 0
+	}
 	defer mux.unlock()
 
-	return len(t.accountQueues[address].accountQueue.txs)
+	return len(mux.accountQueue.txs)
 }
 
 func (t *TxPool) AddSigner(s signer) {
@@ -249,17 +390,24 @@ func (t *TxPool) handleGossipTxn(obj interface{}) {
 		return
 	}
 
-	raw := obj.(*proto.Txn)
-	txn := new(types.Transaction)
-	if err := txn.UnmarshalRLP(raw.Raw.Value); err != nil {
+	txn, expiry, err := txFromProto(obj.(*proto.Txn))
+	if err != nil {
 		t.logger.Error("failed to decode broadcasted txn", "err", err)
 	} else {
-		if err := t.addImpl(OriginGossip, txn); err != nil {
-			t.logger.Error("failed to add broadcasted txn", "err", err)
-		}
+		t.addGossipTxn(txn, expiry)
 	}
 }
 
+// addGossipTxn adds a transaction received from a peer, and announces it to the peers that don't know it
+func (t *TxPool) addGossipTxn(txn *types.Transaction, expiry *Expiry) {
+	if err := t.addWithExpiry(OriginGossip, txn, expiry); err != nil {
+		t.logger.Error("failed to add broadcasted txn", "err", err)
+		return
+	}
+
+	t.broadcast(txn, expiry)
+}
+
 // EnableDev enables dev mode for the txpool
 func (t *TxPool) EnableDev() {
 	t.dev = true
@@ -271,17 +419,18 @@ func (t *TxPool) AddTx(tx *types.Transaction) error {
 		return err
 	}
 
+	t.broadcast(tx, nil)
+
+	return nil
+}
+
+// broadcast announces the transaction to the peers, which pull it along with its expiry if any,
+// and notifies the sealer
+func (t *TxPool) broadcast(tx *types.Transaction, expiry *Expiry) {
 	// broadcast the transaction only if network is enabled
 	// and we are not in dev mode
-	if t.topic != nil && !t.dev {
-		txn := &proto.Txn{
-			Raw: &any.Any{
-				Value: tx.MarshalRLP(),
-			},
-		}
-		if err := t.topic.Publish(txn); err != nil {
-			t.logger.Error("failed to topic txn", "err", err)
-		}
+	if t.announcer != nil && !t.dev {
+		t.announcer.announce(tx.Hash)
 	}
 
 	if t.NotifyCh != nil {
@@ -290,26 +439,44 @@ func (t *TxPool) AddTx(tx *types.Transaction) error {
 		default:
 		}
 	}
-	return nil
 }
 
 // addImpl validates the tx and adds it to the appropriate account transaction queue.
 // Additionally, it updates the global valid transactions queue
-func (t *TxPool) addImpl(origin TxOrigin, tx *types.Transaction) error {
+func (t *TxPool) addImpl(origin TxOrigin, tx *types.Transaction) (err error) {
 	// Since this is a single point of inclusion for new transactions both
 	// to the promoted queue and pending queue we use this point to calculate the hash
 	tx.ComputeHash()
 
+	_, span := tracer.Start(context.Background(), "txpool.add", trace.WithAttributes(
+		attribute.String("tx.hash", tx.Hash.String()),
+		attribute.String("origin", origin),
+	))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	// should treat as local in the following cases
 	// (1) noLocals is false and Tx is local transaction
 	// (2) from in tx is in locals addresses
-	isLocal := (!t.noLocals && origin == OriginAddTxn) || t.locals.containsTxSender(t.signer, tx)
-	err := t.validateTx(tx, isLocal)
+	isLocal := (!t.noLocals && origin == OriginAddTxn) || origin == OriginJournal || t.locals.containsTxSender(t.signer, tx)
+	err = t.validateTx(tx, isLocal)
 	if err != nil {
 		t.logger.Error("Discarding invalid transaction", "hash", tx.Hash, "err", err)
 		return err
 	}
 
+	// Check the nonce is free, or the transaction can replace the one with the same nonce, and the account
+	// has room for it, before making room in the pool. Adding a transaction that is already in the pool is a no-op
+	if err := t.checkAccount(tx, isLocal); errors.Is(err, ErrAlreadyKnown) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
 	if t.slots+numSlots(tx) > t.maxSlots {
 		if !isLocal && t.Underpriced(tx) {
 			return ErrUnderpriced
@@ -320,15 +487,7 @@ func (t *TxPool) addImpl(origin TxOrigin, tx *types.Transaction) error {
 			return ErrTxPoolOverflow
 		}
 		for _, tx := range dropped {
-			mux := t.lockAccountQueue(tx.From, true)
-			if wrapper, ok := t.accountQueues[tx.From]; ok {
-				wrapper.accountQueue.Remove(tx.Hash)
-			}
-			mux.unlock()
-
-			t.pendingQueue.Delete(tx)
-
-			t.decreaseSlots(numSlots(tx))
+			t.evictTx(tx, DropEvicted)
 		}
 		t.metrics.PendingTxs.Set(float64(t.pendingQueue.Length()))
 	}
@@ -338,8 +497,24 @@ func (t *TxPool) addImpl(origin TxOrigin, tx *types.Transaction) error {
 	mux := t.lockAccountQueue(tx.From, true)
 	defer mux.unlock()
 
-	wrapper := t.accountQueues[tx.From]
-	wrapper.accountQueue.Add(tx)
+	replaced, err := t.sameNonceTx(mux.accountQueue, tx)
+	if errors.Is(err, ErrAlreadyKnown) {
+		return nil
+	} else if err != nil {
+		// a transaction with the same nonce was added in the meantime
+		return err
+	}
+	if replaced != nil {
+		if err := t.replace(mux.accountQueue, replaced, tx); err != nil {
+			return err
+		}
+	} else if t.accountQueueFull(mux.accountQueue, tx, isLocal) {
+		return ErrAccountQueueFull
+	} else {
+		mux.accountQueue.Add(tx)
+	}
+	mux.accountQueue.lastActive = time.Now()
+	t.arrivals.mark(tx.Hash, span.SpanContext())
 
 	t.increaseSlots(numSlots(tx))
 	if !isLocal {
@@ -351,19 +526,85 @@ func (t *TxPool) addImpl(origin TxOrigin, tx *types.Transaction) error {
 		t.locals.addAddr(tx.From)
 	}
 
-	// Skip check of GasPrice in the future transactions created by same address when TxPool receives transaction by Gossip or Reorg
-	if isLocal && !t.locals.containsAddr(tx.From) {
-		t.locals.addAddr(tx.From)
+	// The journal is rewritten with the replayed transactions once loaded
+	if isLocal && origin != OriginJournal {
+		t.journalTx(tx)
+	}
+
+	t.promote(tx.From, mux.accountQueue)
+
+	return nil
+}
+
+// checkAccount checks that no other transaction of the account with the same nonce is in the pool,
+// or that the transaction can replace it, and that the account queue has room for the transaction
+func (t *TxPool) checkAccount(tx *types.Transaction, isLocal bool) error {
+	mux := t.lockExistingAccountQueue(tx.From, false)
+	if mux == nil {
+		return nil
+	}
+	defer mux.unlock()
+
+	replaced, err := t.sameNonceTx(mux.accountQueue, tx)
+	if err != nil {
+		return err
+	}
+	if replaced == nil && t.accountQueueFull(mux.accountQueue, tx, isLocal) {
+		return ErrAccountQueueFull
+	}
+
+	return nil
+}
+
+// sameNonceTx returns the transaction of the account queue or of the pending queue with the same nonce,
+// which the transaction replaces, or nil if the nonce is free. The account queue lock must be held
+func (t *TxPool) sameNonceTx(queue *txHeapWrapper, tx *types.Transaction) (*types.Transaction, error) {
+	if t.pendingQueue.Contains(tx) {
+		return nil, ErrAlreadyKnown
+	}
+
+	var existing *types.Transaction
+	if tx.Nonce < queue.nextNonce {
+		if existing = t.pendingQueue.txFrom(tx.From, tx.Nonce); existing == nil {
+			// the nonce is popped by the sealer
+			return nil, ErrNonceExists
+		}
+	} else if existing = queue.get(tx.Nonce); existing == nil {
+		return nil, nil
+	}
+
+	if existing.Hash == tx.Hash {
+		return nil, ErrAlreadyKnown
+	}
+	if !t.replaces(existing, tx) {
+		return nil, ErrReplacementUnderpriced
 	}
 
-	for _, promoted := range wrapper.accountQueue.Promote() {
-		if pushErr := t.pendingQueue.Push(promoted); pushErr != nil {
-			t.logger.Error(fmt.Sprintf("Unable to promote transaction %s, %v", promoted.Hash.String(), pushErr))
+	return existing, nil
+}
+
+// promote moves the queued transactions of the account that became executable to the pending queue,
+// as long as the account has less pending transactions than the limit. The account queue lock must be held
+func (t *TxPool) promote(addr types.Address, queue *txHeapWrapper) {
+	maxNonce := uint64(math.MaxUint64)
+	if t.maxAccountPending != 0 {
+		maxNonce = t.store.GetNonce(t.store.Header().StateRoot, addr) + t.maxAccountPending
+	}
+
+	promoted := queue.Promote(maxNonce)
+	if len(promoted) == 0 {
+		return
+	}
+
+	for _, tx := range promoted {
+		if pushErr := t.pendingQueue.Push(tx); pushErr != nil {
+			t.logger.Error(fmt.Sprintf("Unable to promote transaction %s, %v", tx.Hash.String(), pushErr))
 		} else {
 			t.metrics.PendingTxs.Add(1)
+			t.bus.Publish(&eventbus.NewPendingTx{Tx: tx})
 		}
 	}
-	return nil
+	queue.lastActive = time.Now()
 }
 
 // DecreaseAccountNonce resets the nonce attached to an account whenever a transaction produce an error which is not
@@ -375,29 +616,27 @@ func (t *TxPool) DecreaseAccountNonce(tx *types.Transaction) {
 	mux := t.lockAccountQueue(tx.From, true)
 	defer mux.unlock()
 
-	wrapper, ok := t.accountQueues[tx.From]
-	if ok {
-		wrapper.accountQueue.nextNonce -= 1
-	}
+	mux.accountQueue.nextNonce -= 1
+	t.forgetTx(tx.Hash)
 }
 
 // GetTxs gets both pending and queued transactions
 func (t *TxPool) GetTxs() (map[types.Address]map[uint64]*types.Transaction, map[types.Address]map[uint64]*types.Transaction) {
-
 	pendingTxs := make(map[types.Address]map[uint64]*types.Transaction)
-	sortedPricedTxs := t.pendingQueue.index
-	for _, sortedPricedTx := range sortedPricedTxs {
-		if _, ok := pendingTxs[sortedPricedTx.from]; !ok {
-			pendingTxs[sortedPricedTx.from] = make(map[uint64]*types.Transaction)
+	for _, tx := range t.pendingQueue.txs() {
+		if _, ok := pendingTxs[tx.From]; !ok {
+			pendingTxs[tx.From] = make(map[uint64]*types.Transaction)
 		}
-		pendingTxs[sortedPricedTx.from][sortedPricedTx.tx.Nonce] = sortedPricedTx.tx
+		pendingTxs[tx.From][tx.Nonce] = tx
 	}
 
 	queuedTxs := make(map[types.Address]map[uint64]*types.Transaction)
-	queue := t.accountQueues
-	for addr, queuedTxn := range queue {
-		mux := t.lockAccountQueue(addr, false)
-		for _, tx := range queuedTxn.accountQueue.txs {
+	for _, addr := range t.queueAddrs() {
+		mux := t.lockExistingAccountQueue(addr, false)
+		if mux == nil {
+			continue
+		}
+		for _, tx := range mux.accountQueue.txs {
 			if _, ok := queuedTxs[addr]; !ok {
 				queuedTxs[addr] = make(map[uint64]*types.Transaction)
 			}
@@ -409,6 +648,71 @@ func (t *TxPool) GetTxs() (map[types.Address]map[uint64]*types.Transaction, map[
 	return pendingTxs, queuedTxs
 }
 
+// accountTxs is a consistent view of the transactions of a single account in the pool
+type accountTxs struct {
+	pending   []*types.Transaction
+	queued    []*types.Transaction
+	nextNonce uint64
+}
+
+// getAccountTxs returns the promoted (pending) and the not yet promoted (queued)
+// transactions of a single account, both sorted by nonce, along with the account's next nonce.
+// It returns false if the account has no queue in the pool
+func (t *TxPool) getAccountTxs(addr types.Address) (*accountTxs, bool) {
+	mux := t.lockExistingAccountQueue(addr, false)
+	if mux == nil {
+		return nil, false
+	}
+	defer mux.unlock()
+
+	// Promotions happen under the account write lock, so a transaction
+	// can't move from the queued to the pending list while reading them
+	queued := make([]*types.Transaction, len(mux.accountQueue.txs))
+	copy(queued, mux.accountQueue.txs)
+
+	sort.Slice(queued, func(i, j int) bool {
+		return queued[i].Nonce < queued[j].Nonce
+	})
+
+	return &accountTxs{
+		pending:   t.pendingQueue.txsFrom(addr),
+		queued:    queued,
+		nextNonce: mux.accountQueue.nextNonce,
+	}, true
+}
+
+// EvictAccount drops every pending and queued transaction of the given account from the pool,
+// and resets the account's next nonce to the one in the latest state.
+// The account transactions currently popped by the sealer are not returned to the pool.
+// It returns the evicted transactions
+func (t *TxPool) EvictAccount(addr types.Address) []*types.Transaction {
+	mux := t.lockExistingAccountQueue(addr, true)
+	if mux == nil {
+		return nil
+	}
+	defer mux.unlock()
+
+	mux.accountQueue.evicted = atomic.AddUint64(&t.evictions, 1)
+
+	evicted := t.pendingQueue.txsFrom(addr)
+	evicted = append(evicted, mux.accountQueue.txs...)
+
+	for _, tx := range evicted {
+		t.pendingQueue.Delete(tx)
+		t.remoteTxns.Delete(tx)
+		t.decreaseSlots(numSlots(tx))
+		t.forgetTx(tx.Hash)
+	}
+	t.notifyDropped(evicted, DropEvicted)
+
+	mux.accountQueue.txs = txHeap{}
+	mux.accountQueue.nextNonce = t.store.GetNonce(t.store.Header().StateRoot, addr)
+
+	t.metrics.PendingTxs.Set(float64(t.pendingQueue.Length()))
+
+	return evicted
+}
+
 // Length returns the size of the valid transactions in the txpool
 func (t *TxPool) Length() uint64 {
 	return t.pendingQueue.Length()
@@ -417,7 +721,11 @@ func (t *TxPool) Length() uint64 {
 // Pop returns the max priced transaction from the
 // valid transactions heap in txpool
 func (t *TxPool) Pop() (*types.Transaction, func()) {
-	txn := t.pendingQueue.Pop()
+	// Grab the eviction count before popping, so an eviction of the account
+	// while the transaction is out of the pool can be detected
+	evictions := atomic.LoadUint64(&t.evictions)
+
+	txn := t.popLive()
 	if txn == nil {
 		return nil, nil
 	}
@@ -429,6 +737,17 @@ func (t *TxPool) Pop() (*types.Transaction, func()) {
 	// Subtracts tx slots
 	t.decreaseSlots(slots)
 	ret := func() {
+		// Hold the account lock, so the account can't be evicted
+		// between the check and the push
+		mux := t.lockAccountQueue(txn.from, false)
+		defer mux.unlock()
+
+		if mux.accountQueue.evicted > evictions {
+			// The account was evicted while the transaction was out of the pool
+			t.forgetTx(txn.tx.Hash)
+			return
+		}
+
 		if pushErr := t.pendingQueue.Push(txn.tx); pushErr != nil {
 			t.logger.Error(fmt.Sprintf("Unable to promote transaction %s, %v", txn.tx.Hash.String(), pushErr))
 			return
@@ -440,6 +759,12 @@ func (t *TxPool) Pop() (*types.Transaction, func()) {
 	return txn.tx, ret
 }
 
+// SpanContext returns the span of the admission of the transaction to the pool, so the spans
+// of its inclusion join its trace. The span is invalid if the transaction is not in the pool
+func (t *TxPool) SpanContext(hash types.Hash) trace.SpanContext {
+	return t.arrivals.span(hash)
+}
+
 // ResetWithHeader does basic txpool housekeeping after a block write
 func (t *TxPool) ResetWithHeader(h *types.Header) {
 	evnt := &blockchain.Event{
@@ -448,6 +773,60 @@ func (t *TxPool) ResetWithHeader(h *types.Header) {
 	t.ProcessEvent(evnt)
 }
 
+// DiscardBlocks aligns the accounts of the transactions of the blocks, rewound out of the chain,
+// with the state of the new head. Unlike a reorg, the transactions are not added back to the pool
+func (t *TxPool) DiscardBlocks(headers []*types.Header) {
+	touched := map[types.Address]struct{}{}
+	for _, header := range headers {
+		block, ok := t.store.GetBlockByHash(header.Hash, true)
+		if !ok {
+			t.logger.Error("block not found on txn discard", "hash", header.Hash)
+			continue
+		}
+		for _, txn := range block.Transactions {
+			if from, ok := t.senderOf(txn); ok {
+				touched[from] = struct{}{}
+			}
+		}
+	}
+
+	stateRoot := t.store.Header().StateRoot
+	for addr := range touched {
+		t.resetAccount(addr, t.store.GetNonce(stateRoot, addr))
+	}
+
+	baseFee := t.store.CalculateBaseFee(t.store.Header())
+	t.pendingQueue.setBaseFee(baseFee)
+	t.remoteTxns.setBaseFee(baseFee)
+}
+
+// SetEventBus sets the bus the transactions becoming executable are published on
+func (t *TxPool) SetEventBus(bus *eventbus.Bus) {
+	t.bus = bus
+}
+
+// WatchReorgs processes the reorgs published on the bus until the pool is closed, so the transactions
+// of the replaced blocks are added back to the pool. The blocks sealed by the node reset the pool themselves
+func (t *TxPool) WatchReorgs(bus *eventbus.Bus) {
+	var reorgCh <-chan eventbus.Event
+	reorgCh, t.unsubscribeReorgs = bus.Subscribe(eventbus.TopicReorg)
+
+	go func() {
+		for evnt := range reorgCh {
+			reorg, ok := evnt.(*eventbus.Reorg)
+			if !ok {
+				continue
+			}
+
+			t.ProcessEvent(&blockchain.Event{
+				Type:     blockchain.EventReorg,
+				OldChain: reorg.Removed,
+				NewChain: reorg.Added,
+			})
+		}
+	}()
+}
+
 // ProcessEvent processes the blockchain event and resets the txpool accordingly
 func (t *TxPool) ProcessEvent(evnt *blockchain.Event) {
 	addTxns := map[types.Hash]*types.Transaction{}
@@ -455,7 +834,7 @@ func (t *TxPool) ProcessEvent(evnt *blockchain.Event) {
 		// reinject these transactions on the pool
 		block, ok := t.store.GetBlockByHash(evnt.Hash, true)
 		if !ok {
-			t.logger.Error("block not found on txn add", "hash", block.Hash())
+			t.logger.Error("block not found on txn add", "hash", evnt.Hash)
 		} else {
 			for _, txn := range block.Transactions {
 				addTxns[txn.Hash] = txn
@@ -468,7 +847,7 @@ func (t *TxPool) ProcessEvent(evnt *blockchain.Event) {
 		// remove these transactions from the pool
 		block, ok := t.store.GetBlockByHash(evnt.Hash, true)
 		if !ok {
-			t.logger.Error("block not found on txn del", "hash", block.Hash())
+			t.logger.Error("block not found on txn del", "hash", evnt.Hash)
 		} else {
 			for _, txn := range block.Transactions {
 				delete(addTxns, txn.Hash)
@@ -477,6 +856,31 @@ func (t *TxPool) ProcessEvent(evnt *blockchain.Event) {
 		}
 	}
 
+	// remove the mined transactions from the pendingQueue list
+	for _, txn := range delTxns {
+		// the transactions popped by the sealer already freed their slots
+		if t.pendingQueue.Delete(txn) {
+			t.decreaseSlots(numSlots(txn))
+		}
+		t.remoteTxns.Delete(txn)
+		t.forgetTx(txn.Hash)
+	}
+
+	// align the accounts of the mined and the reverted transactions with their new state nonce,
+	// before the reverted transactions are added back
+	touched := map[types.Address]struct{}{}
+	for _, txns := range []map[types.Hash]*types.Transaction{delTxns, addTxns} {
+		for _, txn := range txns {
+			if from, ok := t.senderOf(txn); ok {
+				touched[from] = struct{}{}
+			}
+		}
+	}
+	stateRoot := t.store.Header().StateRoot
+	for addr := range touched {
+		t.resetAccount(addr, t.store.GetNonce(stateRoot, addr))
+	}
+
 	// try to include again the transactions in the pendingQueue list
 	for _, txn := range addTxns {
 		if err := t.addImpl(OriginReorg, txn); err != nil {
@@ -484,12 +888,14 @@ func (t *TxPool) ProcessEvent(evnt *blockchain.Event) {
 		}
 	}
 
-	// remove the mined transactions from the pendingQueue list
-	for _, txn := range delTxns {
-		t.decreaseSlots(numSlots(txn))
-		t.pendingQueue.Delete(txn)
-		t.remoteTxns.Delete(txn)
-	}
+	// drop the transactions that can't be included in the next block anymore
+	t.pruneExpired()
+
+	// the transactions are priced by the tip they pay on top of the base fee of the next block
+	baseFee := t.store.CalculateBaseFee(t.store.Header())
+	t.pendingQueue.setBaseFee(baseFee)
+	t.remoteTxns.setBaseFee(baseFee)
+
 	//update the metric
 	t.metrics.PendingTxs.Set(float64(t.pendingQueue.Length()))
 }
@@ -502,6 +908,11 @@ func (t *TxPool) validateTx(tx *types.Transaction, isLocal bool) error {
 		return ErrOversizedData
 	}
 
+	// The system transactions are inserted by the validators, they don't go through the pool
+	if tx.IsSystemTx() {
+		return ErrTxTypeNotSupported
+	}
+
 	// Check if the transaction has a strictly positive value
 	if tx.Value.Sign() < 0 {
 		return ErrNegativeValue
@@ -522,9 +933,27 @@ func (t *TxPool) validateTx(tx *types.Transaction, isLocal bool) error {
 		}
 	}
 
-	// Reject non-local transactions whose Gas Price is under priceLimit
-	if !isLocal && tx.GasPrice.Cmp(big.NewInt(int64(t.priceLimit))) < 0 {
-		return ErrUnderpriced
+	if tx.Type == types.AccessListTx {
+		// the access list transactions are only valid after the Berlin fork
+		if !t.store.GetForksInTime(t.store.Header().Number + 1).Berlin {
+			return ErrTxTypeNotSupported
+		}
+	}
+
+	if tx.Type == types.DynamicFeeTx {
+		// the dynamic fee transactions are only valid after the London fork
+		if t.store.CalculateBaseFee(t.store.Header()) == nil {
+			return ErrTxTypeNotSupported
+		}
+
+		if tx.GetGasTipCap().Cmp(tx.GasPrice) > 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
+
+	// Reject transactions whose Gas Price (or tip) is under minGasPrice, or under priceLimit for the non-local ones
+	if err := t.checkGasPrice(tx, isLocal); err != nil {
+		return err
 	}
 
 	// Grab the state root for the latest block
@@ -564,8 +993,8 @@ func (t *TxPool) Underpriced(tx *types.Transaction) bool {
 	if lowestTx == nil {
 		return false
 	}
-	// tx.GasPrice < lowestTx.Price
-	underpriced := tx.GasPrice.Cmp(lowestTx.price) < 0
+	// tx.EffectiveTip < lowestTx.Price
+	underpriced := t.remoteTxns.priceOf(tx).Cmp(lowestTx.price) < 0
 	t.remoteTxns.Push(lowestTx.tx)
 	return underpriced
 }
@@ -573,7 +1002,7 @@ func (t *TxPool) Underpriced(tx *types.Transaction) bool {
 func (t *TxPool) Discard(slots uint64, force bool) ([]*types.Transaction, bool) {
 	dropped := make([]*types.Transaction, 0)
 	for t.remoteTxns.Length() > 0 && slots > 0 {
-		tx := t.remoteTxns.Pop()
+		tx := t.remoteTxns.PopEvictable()
 		dropped = append(dropped, tx.tx)
 
 		txSlots := numSlots(tx.tx)
@@ -596,12 +1025,12 @@ func (t *TxPool) Discard(slots uint64, force bool) ([]*types.Transaction, bool)
 
 // increaseSlots increases number of taken slots
 func (t *TxPool) increaseSlots(slots uint64) {
-	atomic.AddUint64(&t.slots, slots)
+	t.metrics.Slots.Set(float64(atomic.AddUint64(&t.slots, slots)))
 }
 
 // increaseSlots decreases number of taken slots
 func (t *TxPool) decreaseSlots(slots uint64) {
-	atomic.AddUint64(&t.slots, ^(slots - 1))
+	t.metrics.Slots.Set(float64(atomic.AddUint64(&t.slots, ^(slots - 1))))
 }
 
 // txHeapWrapper is a wrapper object for account based transactions
@@ -612,6 +1041,12 @@ type txHeapWrapper struct {
 	// nextNonce is a field indicating what should be the next
 	// valid nonce for the account transaction
 	nextNonce uint64
+
+	// evicted is the pool eviction count at the last eviction of the account
+	evicted uint64
+
+	// lastActive is the last time a transaction of the account was added or promoted
+	lastActive time.Time
 }
 
 // newTxHeapWrapper creates a new account based tx heap
@@ -622,13 +1057,14 @@ func newTxHeapWrapper() *txHeapWrapper {
 }
 
 // Add adds a new tx onto the account based tx heap
-func (t *txHeapWrapper) Add(tx *types.Transaction) {
-	t.Push(tx)
+func (t *txHeapWrapper) Add(tx *types.Transaction) bool {
+	return t.Push(tx)
 }
 
-// pruneLowNonceTx removes any transactions from the account tx queue
-// that have a lower nonce than the current account nonce in state
-func (t *txHeapWrapper) pruneLowNonceTx() {
+// pruneLowNonceTx removes the transactions from the account tx queue
+// that have a lower nonce than the next nonce of the account, and returns them
+func (t *txHeapWrapper) pruneLowNonceTx() []*types.Transaction {
+	pruned := []*types.Transaction{}
 	for {
 		// Grab the min-nonce transaction from the heap
 		tx := t.Peek()
@@ -637,59 +1073,39 @@ func (t *txHeapWrapper) pruneLowNonceTx() {
 		}
 
 		// Drop it from the heap
-		t.Pop()
+		pruned = append(pruned, t.Pop())
 	}
-}
-
-// Promote promotes all the new valid transactions
-func (t *txHeapWrapper) Promote() []*types.Transaction {
-	// Remove elements lower than nonce
-	t.pruneLowNonceTx()
 
-	// Promote elements
-	tx := t.Peek()
-	if tx == nil || tx.Nonce != t.nextNonce {
-		// Nothing to promote
-		return nil
-	}
+	return pruned
+}
 
+// Promote removes the queued transactions that continue the nonce sequence
+// of the account from the heap, up to the max nonce excluded, and returns them
+func (t *txHeapWrapper) Promote(maxNonce uint64) []*types.Transaction {
 	promote := []*types.Transaction{}
-	higherNonceTxs := []*types.Transaction{}
-
-	reinsertFunc := func() {
-		// Reinsert the tx back to the account specific transaction queue
-		for _, highNonceTx := range higherNonceTxs {
-			t.Push(highNonceTx)
-		}
-	}
-
-	for {
-		promote = append(promote, tx)
-		t.Pop()
-
-		var nextTx *types.Transaction
-		if nextTx = t.Peek(); nextTx == nil {
-			break
-		}
-
-		if tx.Nonce+1 != nextTx.Nonce {
-			// Tx that have a higher nonce are shelved for later
-			// when they can actually be parsed
-			higherNonceTxs = append(higherNonceTxs, nextTx)
+	for t.nextNonce < maxNonce {
+		tx := t.Peek()
+		if tx == nil || tx.Nonce != t.nextNonce {
+			// The next nonce is missing, the remaining transactions wait for it
 			break
 		}
 
-		tx = nextTx
+		promote = append(promote, t.Pop())
+		t.nextNonce++
 	}
 
-	// Find the last transaction to be promoted
-	lastTxn := promote[len(promote)-1]
-	// Grab its nonce value and set it as the reference next nonce
-	t.nextNonce = lastTxn.Nonce + 1
+	return promote
+}
 
-	reinsertFunc()
+// get returns the queued transaction with the nonce, or nil if there is none
+func (t *txHeapWrapper) get(nonce uint64) *types.Transaction {
+	for _, tx := range t.txs {
+		if tx.Nonce == nonce {
+			return tx
+		}
+	}
 
-	return promote
+	return nil
 }
 
 // Peek returns the lowest nonce transaction in the account based heap
@@ -697,24 +1113,16 @@ func (t *txHeapWrapper) Peek() *types.Transaction {
 	return t.txs.Peek()
 }
 
-// Push adds a transaction to the account based heap
-func (t *txHeapWrapper) Push(tx *types.Transaction) {
-	// Check if the current transaction has a higher or equal nonce
-	// than all the current transactions in the account based heap
-	i := sort.Search(len(t.txs), func(i int) bool {
-		return t.txs[0].Nonce >= tx.Nonce
-	})
-
-	// If sort.Search found something, it will return the index
-	// of the first found element for which func(i int) was true
-	if i < len(t.txs) && t.txs[i].Nonce == tx.Nonce {
-		// i is an index corresponding to an element in the
-		// account based heap, and the nonces match up, so this tx is discarded
-		return
+// Push adds a transaction to the account based heap, unless a transaction
+// with the same nonce is queued. It reports whether the transaction was added
+func (t *txHeapWrapper) Push(tx *types.Transaction) bool {
+	if t.get(tx.Nonce) != nil {
+		return false
 	}
 
-	// All checks have passed, add the tx to the account based heap
 	heap.Push(&t.txs, tx)
+
+	return true
 }
 
 // Pop removes the min-nonce transaction from the account based heap
@@ -731,7 +1139,7 @@ func (t *txHeapWrapper) Pop() *types.Transaction {
 func (t *txHeapWrapper) Remove(hash types.Hash) bool {
 	for i, tx := range t.txs {
 		if tx.Hash == hash {
-			t.txs = append(t.txs[:i], t.txs[i+1:]...)
+			heap.Remove(&t.txs, i)
 			return true
 		}
 	}
@@ -789,6 +1197,102 @@ type txPriceHeap struct {
 	lock  sync.Mutex
 	index map[types.Hash]*pricedTx
 	heap  heap.Interface
+
+	// baseFee is the base fee of the next block, nil before the London fork
+	baseFee *big.Int
+
+	// nonceOrdered makes the heap pop the transactions of a sender in nonce order. Only the lowest nonce
+	// transaction of each sender, its head, is in the heap, the others wait in the sender's list, sorted by nonce
+	nonceOrdered bool
+	heads        map[types.Address]*pricedTx
+	waiting      map[types.Address][]*pricedTx
+}
+
+// priceOf returns the price of the transaction in the heap, the tip it pays to the block creator
+func (t *txPriceHeap) priceOf(tx *types.Transaction) *big.Int {
+	return tx.EffectiveTip(t.baseFee)
+}
+
+// setBaseFee reprices the transactions in the heap with the base fee of the next block
+func (t *txPriceHeap) setBaseFee(baseFee *big.Int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if baseFee == t.baseFee || (baseFee != nil && t.baseFee != nil && baseFee.Cmp(t.baseFee) == 0) {
+		return
+	}
+
+	t.baseFee = baseFee
+	for _, pTx := range t.index {
+		pTx.price = t.priceOf(pTx.tx)
+	}
+	heap.Init(t.heap)
+}
+
+// pushHead adds the transaction to the heap, or to the sender's waiting list if a transaction
+// of the sender with a lower nonce is in the heap. The lock must be held
+func (t *txPriceHeap) pushHead(pTx *pricedTx) {
+	head, ok := t.heads[pTx.from]
+	if !ok {
+		t.heads[pTx.from] = pTx
+		heap.Push(t.heap, pTx)
+
+		return
+	}
+
+	if pTx.tx.Nonce < head.tx.Nonce {
+		// the transaction takes the place of the head, like a transaction returned by the sealer
+		heap.Remove(t.heap, head.index)
+		t.heads[pTx.from] = pTx
+		heap.Push(t.heap, pTx)
+		pTx = head
+	}
+
+	waiting := t.waiting[pTx.from]
+	i := sort.Search(len(waiting), func(i int) bool {
+		return waiting[i].tx.Nonce > pTx.tx.Nonce
+	})
+	waiting = append(waiting, nil)
+	copy(waiting[i+1:], waiting[i:])
+	waiting[i] = pTx
+	t.waiting[pTx.from] = waiting
+}
+
+// removeHead removes the transaction from the heap or from the sender's waiting list.
+// The next transaction of the sender takes the place of a removed head. The lock must be held
+func (t *txPriceHeap) removeHead(pTx *pricedTx) {
+	if t.heads[pTx.from] != pTx {
+		waiting := t.waiting[pTx.from]
+		for i, wTx := range waiting {
+			if wTx == pTx {
+				t.setWaiting(pTx.from, append(waiting[:i], waiting[i+1:]...))
+
+				break
+			}
+		}
+
+		return
+	}
+
+	if pTx.index >= 0 {
+		heap.Remove(t.heap, pTx.index)
+	}
+	delete(t.heads, pTx.from)
+
+	if waiting := t.waiting[pTx.from]; len(waiting) != 0 {
+		next := waiting[0]
+		t.setWaiting(pTx.from, waiting[1:])
+		t.heads[pTx.from] = next
+		heap.Push(t.heap, next)
+	}
+}
+
+func (t *txPriceHeap) setWaiting(from types.Address, waiting []*pricedTx) {
+	if len(waiting) == 0 {
+		delete(t.waiting, from)
+	} else {
+		t.waiting[from] = waiting
+	}
 }
 
 func (t *txPriceHeap) Length() uint64 {
@@ -798,21 +1302,29 @@ func (t *txPriceHeap) Length() uint64 {
 	return uint64(len(t.index))
 }
 
-func (t *txPriceHeap) Delete(tx *types.Transaction) {
+// Delete removes the transaction from the heap, and reports whether it was in it
+func (t *txPriceHeap) Delete(tx *types.Transaction) bool {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	if item, ok := t.index[tx.Hash]; ok {
-		heap.Remove(t.heap, item.index)
+	item, ok := t.index[tx.Hash]
+	if ok {
+		if t.nonceOrdered {
+			t.removeHead(item)
+		} else {
+			heap.Remove(t.heap, item.index)
+		}
 		delete(t.index, tx.Hash)
 	}
+
+	return ok
 }
 
 func (t *txPriceHeap) Push(tx *types.Transaction) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	price := new(big.Int).Set(tx.GasPrice)
+	price := t.priceOf(tx)
 
 	if _, ok := t.index[tx.Hash]; ok {
 		return ErrAlreadyKnown
@@ -824,7 +1336,11 @@ func (t *txPriceHeap) Push(tx *types.Transaction) error {
 		price: price,
 	}
 	t.index[tx.Hash] = pTx
-	heap.Push(t.heap, pTx)
+	if t.nonceOrdered {
+		t.pushHead(pTx)
+	} else {
+		heap.Push(t.heap, pTx)
+	}
 	return nil
 }
 
@@ -837,19 +1353,115 @@ func (t *txPriceHeap) Pop() *pricedTx {
 	}
 	tx := heap.Pop(t.heap).(*pricedTx)
 	delete(t.index, tx.tx.Hash)
+	if t.nonceOrdered {
+		t.removeHead(tx)
+	}
 	return tx
 }
 
+// PopEvictable removes the transaction to evict first, the highest nonce transaction of the sender of
+// the cheapest one. The eviction of a lower nonce would leave the later transactions of the sender unexecutable
+func (t *txPriceHeap) PopEvictable() *pricedTx {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.index) == 0 {
+		return nil
+	}
+	cheapest := heap.Pop(t.heap).(*pricedTx)
+	heap.Push(t.heap, cheapest)
+
+	last := cheapest
+	for _, pTx := range t.index {
+		if pTx.from == cheapest.from && pTx.tx.Nonce > last.tx.Nonce {
+			last = pTx
+		}
+	}
+
+	if t.nonceOrdered {
+		t.removeHead(last)
+	} else {
+		heap.Remove(t.heap, last.index)
+	}
+	delete(t.index, last.tx.Hash)
+
+	return last
+}
+
+// get returns the transaction in the heap with the given hash, or nil if there is none
+func (t *txPriceHeap) get(hash types.Hash) *types.Transaction {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if pTx, ok := t.index[hash]; ok {
+		return pTx.tx
+	}
+
+	return nil
+}
+
+// txFrom returns the transaction in the heap sent by the given account with the nonce, or nil if there is none
+func (t *txPriceHeap) txFrom(addr types.Address, nonce uint64) *types.Transaction {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, pTx := range t.index {
+		if pTx.from == addr && pTx.tx.Nonce == nonce {
+			return pTx.tx
+		}
+	}
+
+	return nil
+}
+
+// txs returns the transactions in the heap, in no particular order
+func (t *txPriceHeap) txs() []*types.Transaction {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	txs := make([]*types.Transaction, 0, len(t.index))
+	for _, pTx := range t.index {
+		txs = append(txs, pTx.tx)
+	}
+
+	return txs
+}
+
+// txsFrom returns the transactions in the heap sent by the given account, sorted by nonce
+func (t *txPriceHeap) txsFrom(addr types.Address) []*types.Transaction {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	txs := []*types.Transaction{}
+	for _, pTx := range t.index {
+		if pTx.from == addr {
+			txs = append(txs, pTx.tx)
+		}
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Nonce < txs[j].Nonce
+	})
+
+	return txs
+}
+
 func (t *txPriceHeap) Contains(tx *types.Transaction) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
 	_, ok := t.index[tx.Hash]
 	return ok
 }
 
-// return new max-price ordered tx heap
+// return new max-price ordered tx heap, which pops the transactions of a sender in nonce order
 func newMaxTxPriceHeap() *txPriceHeap {
 	return &txPriceHeap{
-		index: make(map[types.Hash]*pricedTx),
-		heap:  newMaxTxPriceHeapImpl(),
+		index:        make(map[types.Hash]*pricedTx),
+		heap:         newMaxTxPriceHeapImpl(),
+		nonceOrdered: true,
+		heads:        make(map[types.Address]*pricedTx),
+		waiting:      make(map[types.Address][]*pricedTx),
 	}
 }
 
@@ -907,12 +1519,9 @@ func newMaxTxPriceHeapImpl() heap.Interface {
 	}
 }
 
+// Less orders the transactions by price only, the heap holds a single transaction per sender
 func (t maxTxPriceHeapImpl) Less(i, j int) bool {
-	if t.txs[i].from == t.txs[j].from {
-		return t.txs[i].tx.Nonce < t.txs[j].tx.Nonce
-	}
-
-	return t.txs[i].price.Cmp(t.txs[j].price) >= 0
+	return t.txs[i].price.Cmp(t.txs[j].price) > 0
 }
 
 type minTxPriceHeapImpl struct {
@@ -971,6 +1580,74 @@ func (a *localAccounts) addAddr(addr types.Address) {
 	a.accounts[addr] = true
 }
 
+func (a *localAccounts) addrs() []types.Address {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	addrs := make([]types.Address, 0, len(a.accounts))
+	for addr := range a.accounts {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// txArrivals keeps the local time the pool first saw each of its transactions,
+// and the span of their admission the later spans of the transactions descend from
+type txArrivals struct {
+	lock  sync.Mutex
+	times map[types.Hash]time.Time
+	spans map[types.Hash]trace.SpanContext
+}
+
+func newTxArrivals() *txArrivals {
+	return &txArrivals{
+		times: make(map[types.Hash]time.Time),
+		spans: make(map[types.Hash]trace.SpanContext),
+	}
+}
+
+// mark records the arrival of the transaction, unless it is already known
+func (a *txArrivals) mark(hash types.Hash, span trace.SpanContext) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if _, ok := a.times[hash]; !ok {
+		a.times[hash] = time.Now()
+		a.spans[hash] = span
+	}
+}
+
+// get returns the arrival time of the transaction, or the zero time if it is not known
+func (a *txArrivals) get(hash types.Hash) time.Time {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.times[hash]
+}
+
+// span returns the span of the admission of the transaction, or an invalid span if it is not known
+func (a *txArrivals) span(hash types.Hash) trace.SpanContext {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.spans[hash]
+}
+
+// forget drops the arrival time of a transaction that left the pool
+func (a *txArrivals) forget(hash types.Hash) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	delete(a.times, hash)
+	delete(a.spans, hash)
+}
+
+// forgetTx drops the bookkeeping of a transaction that left the pool
+func (t *TxPool) forgetTx(hash types.Hash) {
+	t.arrivals.forget(hash)
+	t.expiries.forget(hash)
+}
+
 // numSlots calculates the number of slots for given transaction
 func numSlots(tx *types.Transaction) uint64 {
 	return (tx.Size() + txSlotSize - 1) / txSlotSize
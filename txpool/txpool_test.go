@@ -7,16 +7,22 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/0xPolygon/polygon-sdk/chain"
 	"github.com/0xPolygon/polygon-sdk/crypto"
+	"github.com/0xPolygon/polygon-sdk/eventbus"
 	"github.com/0xPolygon/polygon-sdk/helper/tests"
 	"github.com/0xPolygon/polygon-sdk/network"
+	"github.com/0xPolygon/polygon-sdk/state"
 	"github.com/0xPolygon/polygon-sdk/txpool/proto"
 	"github.com/0xPolygon/polygon-sdk/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 var forks = &chain.Forks{
@@ -32,20 +38,26 @@ const (
 var (
 	addr1 = types.Address{0x1}
 	addr2 = types.Address{0x2}
+	addr3 = types.Address{0x3}
 )
 var (
 	nilMetrics = NilMetrics()
 )
 
 type mockStore struct {
+	nonces  map[types.Address]uint64
+	blocks  map[types.Hash]*types.Block
+	baseFee *big.Int
+	berlin  bool
 }
 
-func (m *mockStore) GetNonce(types.Hash, types.Address) uint64 {
-	return 0
+func (m *mockStore) GetNonce(_ types.Hash, addr types.Address) uint64 {
+	return m.nonces[addr]
 }
 
-func (m *mockStore) GetBlockByHash(types.Hash, bool) (*types.Block, bool) {
-	return nil, false
+func (m *mockStore) GetBlockByHash(hash types.Hash, _ bool) (*types.Block, bool) {
+	block, ok := m.blocks[hash]
+	return block, ok
 }
 
 func (m *mockStore) GetBalance(types.Hash, types.Address) (*big.Int, error) {
@@ -57,6 +69,14 @@ func (m *mockStore) Header() *types.Header {
 	return &types.Header{}
 }
 
+func (m *mockStore) CalculateBaseFee(parent *types.Header) *big.Int {
+	return m.baseFee
+}
+
+func (m *mockStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.ForksInTime{Berlin: m.berlin}
+}
+
 type mockSigner struct{}
 
 func (s *mockSigner) Sender(tx *types.Transaction) (types.Address, error) {
@@ -162,6 +182,52 @@ func TestMultipleTransactions(t *testing.T) {
 	assert.Equal(t, pool.Length(), uint64(1))
 }
 
+func TestWatchReorgs(t *testing.T) {
+	txn := &types.Transaction{
+		From:     addr1,
+		Gas:      validGasLimit,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	}
+	txn.ComputeHash()
+
+	// the transaction was mined in a block replaced by the reorg
+	replaced := &types.Header{Number: 1}
+	replaced.ComputeHash()
+
+	store := &mockStore{
+		blocks: map[types.Hash]*types.Block{
+			replaced.Hash: {Header: replaced, Transactions: []*types.Transaction{txn}},
+		},
+	}
+
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, true, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	bus := eventbus.New(hclog.NewNullLogger())
+	pool.SetEventBus(bus)
+	pool.WatchReorgs(bus)
+	defer pool.Close()
+
+	pendingCh, unsubscribe := bus.Subscribe(eventbus.TopicNewPendingTx)
+	defer unsubscribe()
+
+	bus.Publish(&eventbus.Reorg{Removed: []*types.Header{replaced}})
+
+	// the reinjected transaction is executable again
+	select {
+	case evnt := <-pendingCh:
+		assert.Equal(t, txn.Hash, evnt.(*eventbus.NewPendingTx).Tx.Hash)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the transaction of the replaced block is not back in the pool")
+	}
+
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.True(t, pool.pendingQueue.Contains(txn))
+}
+
 func TestGetPendingAndQueuedTransactions(t *testing.T) {
 	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
 	assert.NoError(t, err)
@@ -215,6 +281,176 @@ func TestGetPendingAndQueuedTransactions(t *testing.T) {
 	assert.Equal(t, pendingTxs[from1][txn0.Nonce].Value, big.NewInt(106))
 }
 
+func TestGetTxsConcurrently(t *testing.T) {
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	const numTxs = 100
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numTxs; i++ {
+			txn := generateTx(addr1, big.NewInt(int64(i)), big.NewInt(1), nil)
+			txn.Nonce = uint64(i)
+			_ = pool.addImpl(OriginAddTxn, txn)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numTxs; i++ {
+			pool.GetTxs()
+		}
+	}()
+	wg.Wait()
+
+	pending, _ := pool.GetTxs()
+	assert.Len(t, pending[addr1], numTxs)
+}
+
+func TestInspectAndEvictAccount(t *testing.T) {
+	store := &mockStore{
+		nonces: map[types.Address]uint64{addr1: 1},
+	}
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	// nonces 1 and 2 are promoted, nonce 4 is stuck behind a gap
+	for _, nonce := range []uint64{2, 1, 4} {
+		txn := generateTx(addr1, big.NewInt(1), big.NewInt(1), nil)
+		txn.Nonce = nonce
+		assert.NoError(t, pool.addImpl(OriginAddTxn, txn))
+	}
+	assert.NoError(t, pool.addImpl(OriginAddTxn, generateTx(addr2, big.NewInt(2), big.NewInt(1), nil)))
+
+	// backdate the arrival of the queued transaction
+	txs, ok := pool.getAccountTxs(addr1)
+	assert.True(t, ok)
+	pool.arrivals.times[txs.queued[0].Hash] = time.Now().Add(-time.Minute)
+
+	resp, err := pool.Inspect(context.Background(), &proto.InspectReq{Account: addr1.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.StateNonce)
+	assert.Equal(t, uint64(3), resp.NextNonce)
+	assert.Len(t, resp.Pending, 2)
+	assert.Equal(t, uint64(1), resp.Pending[0].Nonce)
+	assert.Equal(t, uint64(2), resp.Pending[1].Nonce)
+	assert.Len(t, resp.Queued, 1)
+	assert.Equal(t, uint64(4), resp.Queued[0].Nonce)
+	assert.GreaterOrEqual(t, resp.Queued[0].Age, uint64(60))
+
+	evictResp, err := pool.Evict(context.Background(), &proto.EvictReq{Account: addr1.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), evictResp.Count)
+
+	txs, ok = pool.getAccountTxs(addr1)
+	assert.True(t, ok)
+	assert.Len(t, txs.pending, 0)
+	assert.Len(t, txs.queued, 0)
+	assert.Equal(t, uint64(1), txs.nextNonce)
+
+	// the other account is untouched
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, uint64(1), pool.slots)
+	assert.Len(t, pool.arrivals.times, 1)
+}
+
+func TestInspectUnknownAccount(t *testing.T) {
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+
+	resp, err := pool.Inspect(context.Background(), &proto.InspectReq{Account: addr1.String()})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Pending, 0)
+	assert.Len(t, resp.Queued, 0)
+
+	evictResp, err := pool.Evict(context.Background(), &proto.EvictReq{Account: addr1.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), evictResp.Count)
+
+	// neither of the calls creates a queue for the account
+	assert.Len(t, pool.accountQueues, 0)
+}
+
+func TestEvictAccountWithPoppedTx(t *testing.T) {
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	assert.NoError(t, pool.addImpl(OriginAddTxn, generateTx(addr1, big.NewInt(1), big.NewInt(1), nil)))
+
+	// the sealer takes the transaction out of the pool
+	txn, ret := pool.Pop()
+	assert.NotNil(t, txn)
+
+	pool.EvictAccount(addr1)
+
+	// returning the transaction of the evicted account is a no-op
+	ret()
+
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, uint64(0), pool.slots)
+	assert.Len(t, pool.arrivals.times, 0)
+
+	// transactions popped after the eviction are returned as usual
+	assert.NoError(t, pool.addImpl(OriginAddTxn, generateTx(addr1, big.NewInt(2), big.NewInt(1), nil)))
+
+	_, ret = pool.Pop()
+	ret()
+
+	assert.Equal(t, uint64(1), pool.Length())
+	assert.Equal(t, uint64(1), pool.slots)
+}
+
+func TestEvictAccountConcurrently(t *testing.T) {
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	const numTxs = 100
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numTxs; i++ {
+			txn := generateTx(addr1, big.NewInt(int64(i)), big.NewInt(1), nil)
+			txn.Nonce = uint64(i)
+			_ = pool.addImpl(OriginAddTxn, txn)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numTxs; i++ {
+			if _, ret := pool.Pop(); ret != nil {
+				ret()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numTxs/10; i++ {
+			pool.EvictAccount(addr1)
+		}
+	}()
+	wg.Wait()
+
+	pool.EvictAccount(addr1)
+
+	assert.Equal(t, uint64(0), pool.Length())
+	assert.Equal(t, uint64(0), pool.slots)
+	assert.Len(t, pool.arrivals.times, 0)
+}
+
 func TestBroadcast(t *testing.T) {
 	// we need a fully encrypted txn with (r, s, v) values so that we can
 	// safely encrypt in RLP and broadcast it
@@ -425,6 +661,14 @@ func (fms faultyMockStore) GetBalance(root types.Hash, addr types.Address) (*big
 	return nil, fmt.Errorf("unable to fetch account state")
 }
 
+func (fms faultyMockStore) CalculateBaseFee(parent *types.Header) *big.Int {
+	return nil
+}
+
+func (fms faultyMockStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.ForksInTime{}
+}
+
 func TestTxPool_ErrorCodes(t *testing.T) {
 	testTable := []struct {
 		name          string
@@ -862,3 +1106,162 @@ func TestSizeLimit(t *testing.T) {
 		})
 	}
 }
+
+func generateDynamicFeeTx(from types.Address, nonce uint64, feeCap, tipCap *big.Int) *types.Transaction {
+	tx := generateTx(from, big.NewInt(0), feeCap, nil)
+	tx.Type = types.DynamicFeeTx
+	tx.Nonce = nonce
+	tx.GasTipCap = tipCap
+	tx.ChainID = big.NewInt(100)
+	tx.ComputeHash()
+
+	return tx
+}
+
+func TestTxPool_DynamicFeeTx(t *testing.T) {
+	store := &mockStore{}
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, true, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	// the dynamic fee transactions are rejected before the London fork
+	assert.ErrorIs(t, pool.addImpl("", generateDynamicFeeTx(addr1, 0, big.NewInt(10), big.NewInt(2))), ErrTxTypeNotSupported)
+
+	store.baseFee = big.NewInt(5)
+
+	// the tip can't be higher than the fee cap
+	assert.ErrorIs(t, pool.addImpl("", generateDynamicFeeTx(addr1, 0, big.NewInt(10), big.NewInt(20))), ErrTipAboveFeeCap)
+
+	// the tip is checked against the price limit
+	assert.ErrorIs(t, pool.addImpl("", generateDynamicFeeTx(addr1, 0, big.NewInt(10), big.NewInt(0))), ErrUnderpriced)
+
+	assert.NoError(t, pool.addImpl("", generateDynamicFeeTx(addr1, 0, big.NewInt(10), big.NewInt(2))))
+	assert.Equal(t, uint64(1), pool.Length())
+}
+
+func TestTxPool_AccessListTx(t *testing.T) {
+	store := &mockStore{}
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, true, defaultPriceLimit, defaultMaxSlots, forks.At(0), store, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	accessListTx := func(gas uint64) *types.Transaction {
+		tx := generateTx(addr1, big.NewInt(0), big.NewInt(1), nil)
+		tx.Type = types.AccessListTx
+		tx.Gas = gas
+		tx.ChainID = big.NewInt(100)
+		tx.AccessList = types.AccessList{
+			{Address: addr2, StorageKeys: []types.Hash{{0x1}}},
+		}
+		tx.ComputeHash()
+
+		return tx
+	}
+
+	// the access list transactions are rejected before the Berlin fork
+	assert.ErrorIs(t, pool.addImpl("", accessListTx(validGasLimit)), ErrTxTypeNotSupported)
+
+	store.berlin = true
+
+	// the access list is part of the intrinsic gas
+	assert.ErrorIs(t, pool.addImpl("", accessListTx(state.TxGas+state.TxAccessListAddressGas)), ErrIntrinsicGas)
+
+	assert.NoError(t, pool.addImpl("", accessListTx(validGasLimit)))
+	assert.Equal(t, uint64(1), pool.Length())
+}
+
+func TestTxPriceHeap_EffectiveTip(t *testing.T) {
+	legacy := generateTx(types.Address{0x1}, big.NewInt(0), big.NewInt(15), nil)
+	legacy.ComputeHash()
+
+	highTip := generateDynamicFeeTx(types.Address{0x2}, 0, big.NewInt(30), big.NewInt(10))
+	lowCap := generateDynamicFeeTx(types.Address{0x3}, 0, big.NewInt(12), big.NewInt(11))
+
+	popAll := func(h *txPriceHeap) []*types.Transaction {
+		txs := []*types.Transaction{}
+		for h.Length() > 0 {
+			txs = append(txs, h.Pop().tx)
+		}
+		return txs
+	}
+
+	pushAll := func(h *txPriceHeap) {
+		for _, tx := range []*types.Transaction{legacy, highTip, lowCap} {
+			assert.NoError(t, h.Push(tx))
+		}
+	}
+
+	// before the London fork the price is the gas price, or the tip of the dynamic fee transactions
+	h := newMaxTxPriceHeap()
+	pushAll(h)
+	assert.Equal(t, []*types.Transaction{legacy, lowCap, highTip}, popAll(h))
+
+	// after the fork the price is the tip paid on top of the base fee
+	h = newMaxTxPriceHeap()
+	pushAll(h)
+	h.setBaseFee(big.NewInt(10))
+	assert.Equal(t, []*types.Transaction{highTip, legacy, lowCap}, popAll(h))
+
+	pushAll(h)
+	assert.Equal(t, []*types.Transaction{highTip, legacy, lowCap}, popAll(h))
+}
+
+func TestTxPriceHeap_NonceOrder(t *testing.T) {
+	priced := func(from types.Address, nonce uint64, price int64) *types.Transaction {
+		tx := queueTestTx(from, nonce, price)
+		tx.ComputeHash()
+
+		return tx
+	}
+
+	a0, a1, a2 := priced(addr1, 0, 1), priced(addr1, 1, 10), priced(addr1, 2, 2)
+	b0 := priced(addr2, 0, 5)
+	c0 := priced(addr3, 0, 3)
+
+	h := newMaxTxPriceHeap()
+	for _, tx := range []*types.Transaction{a2, b0, a1, c0, a0} {
+		assert.NoError(t, h.Push(tx))
+	}
+
+	// the well paying transaction waits for the lower nonces of its sender
+	assert.Equal(t, b0, h.Pop().tx)
+	assert.Equal(t, c0, h.Pop().tx)
+	assert.Equal(t, a0, h.Pop().tx)
+	assert.Equal(t, a1, h.Pop().tx)
+
+	// a transaction returned by the sealer goes back before the higher nonces
+	assert.NoError(t, h.Push(a1))
+	assert.True(t, h.Delete(a2))
+	assert.NoError(t, h.Push(a2))
+	assert.Equal(t, a1, h.Pop().tx)
+	assert.Equal(t, a2, h.Pop().tx)
+	assert.Nil(t, h.Pop())
+	assert.Empty(t, h.heads)
+	assert.Empty(t, h.waiting)
+}
+
+func TestSpanContext(t *testing.T) {
+	// the spans are recorded once a provider is installed
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	pool, err := NewTxPool(hclog.NewNullLogger(), false, nil, false, defaultPriceLimit, defaultMaxSlots, forks.At(0), &mockStore{}, nil, nil, nilMetrics)
+	assert.NoError(t, err)
+	pool.EnableDev()
+	pool.AddSigner(&mockSigner{})
+
+	txn := generateTx(addr1, big.NewInt(1), big.NewInt(1), nil)
+	assert.NoError(t, pool.addImpl(OriginAddTxn, txn))
+
+	// the admission span is kept while the transaction is in the pool
+	span := pool.SpanContext(txn.Hash)
+	assert.True(t, span.IsValid())
+
+	// adding the transaction again doesn't replace the span
+	assert.NoError(t, pool.addImpl(OriginGossip, txn))
+	assert.Equal(t, span, pool.SpanContext(txn.Hash))
+
+	pool.EvictAccount(addr1)
+	assert.False(t, pool.SpanContext(txn.Hash).IsValid())
+}
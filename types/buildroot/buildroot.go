@@ -22,17 +22,12 @@ func CalculateReceiptsRoot(receipts []*types.Receipt) types.Hash {
 	return res
 }
 
-// CalculateTransactionsRoot calculates the root of a list of transactions
+// CalculateTransactionsRoot calculates the root of a list of transactions.
+// The typed transactions are in the trie with their type and payload, not as a RLP string
 func CalculateTransactionsRoot(transactions []*types.Transaction) types.Hash {
-	ar := arenaPool.Get()
-
-	res := calculateRootWithRlp(len(transactions), func(i int) *fastrlp.Value {
-		ar.Reset()
-		return transactions[i].MarshalRLPWith(ar)
+	return CalculateRoot(len(transactions), func(i int) []byte {
+		return transactions[i].MarshalRLPTo(nil)
 	})
-
-	arenaPool.Put(ar)
-	return res
 }
 
 // CalculateUncleRoot calculates the root of a list of uncles
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/binary"
 	"fmt"
+	"math/big"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-sdk/helper/hex"
@@ -27,6 +28,9 @@ type Header struct {
 	MixHash      Hash
 	Nonce        Nonce
 	Hash         Hash
+
+	// BaseFee is the base fee per gas of the block, as defined by EIP-1559. It is nil before the London fork
+	BaseFee *big.Int
 }
 
 func (h *Header) Equal(hh *Header) bool {
@@ -72,6 +76,10 @@ func (h *Header) Copy() *Header {
 
 	hh.ExtraData = make([]byte, len(h.ExtraData))
 	copy(hh.ExtraData[:], h.ExtraData[:])
+
+	if h.BaseFee != nil {
+		hh.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	return hh
 }
 
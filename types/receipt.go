@@ -30,6 +30,9 @@ type Receipt struct {
 	GasUsed         uint64
 	ContractAddress Address
 	TxHash          Hash
+
+	// RevertData is the data returned by the transaction if it reverted
+	RevertData []byte
 }
 
 func (r *Receipt) SetStatus(s ReceiptStatus) {
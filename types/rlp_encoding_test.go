@@ -1,6 +1,7 @@
 package types
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
@@ -42,3 +43,186 @@ func TestRLPUnmarshal_Header_ComputeHash(t *testing.T) {
 	assert.NoError(t, h2.UnmarshalRLP(data))
 	assert.Equal(t, h.Hash, h2.Hash)
 }
+
+func TestRLPStorage_Receipt_RevertData(t *testing.T) {
+	status := ReceiptFailed
+
+	for _, revertData := range [][]byte{nil, {0x1, 0x2}} {
+		r := &Receipt{
+			Status:          &status,
+			GasUsed:         10,
+			ContractAddress: StringToAddress("1"),
+			RevertData:      revertData,
+		}
+
+		r2 := &Receipt{}
+		assert.NoError(t, r2.UnmarshalStoreRLP(r.MarshalStoreRLPTo(nil)))
+		assert.Equal(t, r.GasUsed, r2.GasUsed)
+		assert.Equal(t, r.ContractAddress, r2.ContractAddress)
+		assert.Equal(t, len(revertData), len(r2.RevertData))
+		assert.Equal(t, string(revertData), string(r2.RevertData))
+	}
+}
+
+func TestRLPEncoding_BaseFee(t *testing.T) {
+	h := &Header{Number: 1, BaseFee: big.NewInt(1000)}
+	h.ComputeHash()
+
+	h2 := &Header{}
+	assert.NoError(t, h2.UnmarshalRLP(h.MarshalRLP()))
+	assert.Equal(t, h.BaseFee, h2.BaseFee)
+	assert.Equal(t, h.Hash, h2.Hash)
+
+	// the base fee is part of the hash
+	h3 := h.Copy()
+	h3.BaseFee = nil
+	h3.ComputeHash()
+	assert.NotEqual(t, h.Hash, h3.Hash)
+}
+
+func TestRLPEncoding_DynamicFeeTx(t *testing.T) {
+	to := StringToAddress("1")
+	txs := []*Transaction{
+		{
+			Type:      DynamicFeeTx,
+			ChainID:   big.NewInt(100),
+			Nonce:     1,
+			GasPrice:  big.NewInt(20),
+			GasTipCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(5),
+			Input:     []byte{0x1},
+			V:         []byte{0x1},
+			R:         []byte{0x2},
+			S:         []byte{0x3},
+		},
+		{
+			Nonce:    2,
+			GasPrice: big.NewInt(20),
+			Value:    big.NewInt(5),
+			Input:    []byte{},
+			V:        []byte{0x1b},
+			R:        []byte{0x2},
+			S:        []byte{0x3},
+		},
+	}
+
+	// the typed transactions are encoded with their type before the payload
+	raw := txs[0].MarshalRLP()
+	assert.Equal(t, byte(DynamicFeeTx), raw[0])
+
+	tx := &Transaction{}
+	assert.NoError(t, tx.UnmarshalRLP(raw))
+	assert.Equal(t, txs[0].ComputeHash().Hash, tx.Hash)
+	assert.Equal(t, txs[0].GasTipCap, tx.GasTipCap)
+	assert.Equal(t, txs[0].ChainID, tx.ChainID)
+
+	// the typed and legacy transactions are decoded from the blocks and the bodies
+	block := &Block{Header: &Header{}, Transactions: txs}
+
+	block2 := &Block{}
+	assert.NoError(t, block2.UnmarshalRLP(block.MarshalRLP()))
+
+	body := &Body{}
+	assert.NoError(t, body.UnmarshalRLP(block.Body().MarshalRLPTo(nil)))
+
+	for _, decoded := range [][]*Transaction{block2.Transactions, body.Transactions} {
+		assert.Len(t, decoded, 2)
+		assert.Equal(t, DynamicFeeTx, decoded[0].Type)
+		assert.Equal(t, LegacyTx, decoded[1].Type)
+		assert.Equal(t, txs[0].Hash, decoded[0].Hash)
+		assert.Equal(t, txs[1].ComputeHash().Hash, decoded[1].Hash)
+	}
+
+	// the unknown types are rejected
+	assert.Error(t, tx.UnmarshalRLP(append([]byte{0x5}, raw[1:]...)))
+}
+
+func TestRLPEncoding_AccessListTx(t *testing.T) {
+	to := StringToAddress("1")
+	accessList := AccessList{
+		{Address: to, StorageKeys: []Hash{StringToHash("1"), StringToHash("2")}},
+		{Address: StringToAddress("2")},
+	}
+
+	for _, txn := range []*Transaction{
+		{
+			Type:       AccessListTx,
+			ChainID:    big.NewInt(100),
+			Nonce:      1,
+			GasPrice:   big.NewInt(20),
+			Gas:        21000,
+			To:         &to,
+			Value:      big.NewInt(5),
+			Input:      []byte{0x1},
+			AccessList: accessList,
+			V:          []byte{0x1},
+			R:          []byte{0x2},
+			S:          []byte{0x3},
+		},
+		{
+			Type:       DynamicFeeTx,
+			ChainID:    big.NewInt(100),
+			Nonce:      2,
+			GasPrice:   big.NewInt(20),
+			GasTipCap:  big.NewInt(2),
+			Gas:        21000,
+			Value:      big.NewInt(5),
+			Input:      []byte{},
+			AccessList: accessList,
+			V:          []byte{0x1},
+			R:          []byte{0x2},
+			S:          []byte{0x3},
+		},
+	} {
+		raw := txn.MarshalRLP()
+		assert.Equal(t, byte(txn.Type), raw[0])
+
+		tx := &Transaction{}
+		assert.NoError(t, tx.UnmarshalRLP(raw))
+		assert.Equal(t, txn.Type, tx.Type)
+		assert.Equal(t, txn.ComputeHash().Hash, tx.Hash)
+		assert.Equal(t, txn.To, tx.To)
+		assert.Equal(t, txn.GasTipCap, tx.GasTipCap)
+		assert.Equal(t, accessList, tx.AccessList)
+	}
+}
+
+func TestRLPEncoding_StateTx(t *testing.T) {
+	to := StringToAddress("1")
+	txn := &Transaction{
+		Type:     StateTx,
+		Nonce:    3,
+		Gas:      100000,
+		To:       &to,
+		Input:    []byte{0x1, 0x2},
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+		From:     SystemAddress,
+	}
+
+	raw := txn.MarshalRLP()
+	assert.Equal(t, byte(StateTx), raw[0])
+
+	// the system transactions are sent by the system address, without a signature
+	tx := &Transaction{}
+	assert.NoError(t, tx.UnmarshalRLP(raw))
+	assert.True(t, tx.IsSystemTx())
+	assert.Equal(t, txn.ComputeHash().Hash, tx.Hash)
+	assert.Equal(t, SystemAddress, tx.From)
+	assert.Equal(t, txn.Nonce, tx.Nonce)
+	assert.Equal(t, txn.Gas, tx.Gas)
+	assert.Equal(t, txn.To, tx.To)
+	assert.Equal(t, txn.Input, tx.Input)
+	assert.Equal(t, 0, tx.GasPrice.Sign())
+	assert.Equal(t, 0, tx.Value.Sign())
+
+	// and decoded from the blocks
+	block := &Block{Header: &Header{}, Transactions: []*Transaction{txn}}
+
+	block2 := &Block{}
+	assert.NoError(t, block2.UnmarshalRLP(block.MarshalRLP()))
+	assert.Len(t, block2.Transactions, 1)
+	assert.Equal(t, txn.Hash, block2.Transactions[0].Hash)
+}
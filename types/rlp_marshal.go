@@ -1,6 +1,8 @@
 package types
 
 import (
+	"math/big"
+
 	"github.com/umbracle/fastrlp"
 )
 
@@ -82,6 +84,11 @@ func (h *Header) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv.Set(arena.NewBytes(h.MixHash.Bytes()))
 	vv.Set(arena.NewCopyBytes(h.Nonce[:]))
 
+	// the base fee is only part of the headers after the London fork
+	if h.BaseFee != nil {
+		vv.Set(arena.NewBigInt(h.BaseFee))
+	}
+
 	return vv
 }
 
@@ -149,12 +156,30 @@ func (t *Transaction) MarshalRLP() []byte {
 	return t.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo appends the encoding of the transaction to dst: the RLP list of a legacy transaction,
+// or the type followed by the RLP list of the payload of a typed transaction (EIP-2718)
 func (t *Transaction) MarshalRLPTo(dst []byte) []byte {
+	switch t.Type {
+	case AccessListTx:
+		dst = append(dst, byte(t.Type))
+		return MarshalRLPTo(t.marshalAccessListRLPWith, dst)
+	case DynamicFeeTx:
+		dst = append(dst, byte(t.Type))
+		return MarshalRLPTo(t.marshalDynamicFeeRLPWith, dst)
+	case StateTx:
+		dst = append(dst, byte(t.Type))
+		return MarshalRLPTo(t.marshalStateRLPWith, dst)
+	}
 	return MarshalRLPTo(t.MarshalRLPWith, dst)
 }
 
-// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena
+// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena.
+// The typed transactions are marshaled as a byte string of their encoding
 func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if t.Type != LegacyTx {
+		return arena.NewCopyBytes(t.MarshalRLPTo(nil))
+	}
+
 	vv := arena.NewArray()
 
 	vv.Set(arena.NewUint(t.Nonce))
@@ -178,3 +203,114 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 
 	return vv
 }
+
+// marshalAccessListRLPWith marshals the payload of an access list transaction (EIP-2930)
+func (t *Transaction) marshalAccessListRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBigInt(bigOrZero(t.ChainID)))
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewBigInt(t.GasPrice))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewBigInt(t.Value))
+	vv.Set(arena.NewCopyBytes(t.Input))
+	vv.Set(t.AccessList.MarshalRLPWith(arena))
+
+	// signature values, the V of a typed transaction is the parity of the signature
+	vv.Set(arena.NewBigInt(new(big.Int).SetBytes(t.V)))
+	vv.Set(arena.NewCopyBytes(t.R))
+	vv.Set(arena.NewCopyBytes(t.S))
+
+	return vv
+}
+
+// marshalStateRLPWith marshals the payload of a system transaction, it has no fee and no signature
+func (t *Transaction) marshalStateRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewCopyBytes(t.Input))
+
+	return vv
+}
+
+// marshalDynamicFeeRLPWith marshals the payload of a dynamic fee transaction (EIP-1559)
+func (t *Transaction) marshalDynamicFeeRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBigInt(bigOrZero(t.ChainID)))
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewBigInt(bigOrZero(t.GasTipCap)))
+	vv.Set(arena.NewBigInt(t.GasPrice))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewBigInt(t.Value))
+	vv.Set(arena.NewCopyBytes(t.Input))
+
+	vv.Set(t.AccessList.MarshalRLPWith(arena))
+
+	// signature values, the V of a typed transaction is the parity of the signature
+	vv.Set(arena.NewBigInt(new(big.Int).SetBytes(t.V)))
+	vv.Set(arena.NewCopyBytes(t.R))
+	vv.Set(arena.NewCopyBytes(t.S))
+
+	return vv
+}
+
+// MarshalRLPWith marshals the access list to RLP with a specific fastrlp.Arena
+func (a AccessList) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if len(a) == 0 {
+		return arena.NewNullArray()
+	}
+
+	vv := arena.NewArray()
+	for _, tuple := range a {
+		v := arena.NewArray()
+		v.Set(arena.NewCopyBytes(tuple.Address.Bytes()))
+
+		if len(tuple.StorageKeys) == 0 {
+			v.Set(arena.NewNullArray())
+		} else {
+			keys := arena.NewArray()
+			for _, key := range tuple.StorageKeys {
+				keys.Set(arena.NewCopyBytes(key.Bytes()))
+			}
+			v.Set(keys)
+		}
+
+		vv.Set(v)
+	}
+
+	return vv
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b
+}
@@ -77,5 +77,10 @@ func (r *Receipt) MarshalStoreRLPWith(a *fastrlp.Arena) *fastrlp.Value {
 
 	// gas used
 	vv.Set(a.NewUint(r.GasUsed))
+
+	// revert data, only for the reverted transactions
+	if len(r.RevertData) != 0 {
+		vv.Set(a.NewBytes(r.RevertData))
+	}
 	return vv
 }
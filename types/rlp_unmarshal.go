@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/0xPolygon/polygon-sdk/helper/keccak"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -87,8 +88,8 @@ func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	if err != nil {
 		return err
 	}
-	if num := len(elems); num != 15 {
-		return fmt.Errorf("not enough elements to decode header, expected 15 but found %d", num)
+	if num := len(elems); num != 15 && num != 16 {
+		return fmt.Errorf("not enough elements to decode header, expected 15 or 16 but found %d", num)
 	}
 
 	// parentHash
@@ -154,6 +155,16 @@ func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	}
 	h.SetNonce(nonce)
 
+	// baseFee
+	if len(elems) == 16 {
+		h.BaseFee = new(big.Int)
+		if err = elems[15].GetBigInt(h.BaseFee); err != nil {
+			return err
+		}
+	} else {
+		h.BaseFee = nil
+	}
+
 	// compute the hash after the decoding
 	h.ComputeHash()
 	return err
@@ -263,12 +274,26 @@ func (l *Log) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a transaction encoded by MarshalRLPTo
 func (t *Transaction) UnmarshalRLP(input []byte) error {
+	if len(input) > 0 && input[0] <= 0x7f {
+		// a list starts at 0xc0, the lower first bytes are the types of the typed transactions
+		return t.unmarshalTyped(input)
+	}
 	return UnmarshalRlp(t.UnmarshalRLPFrom, input)
 }
 
 // UnmarshalRLP unmarshals a Transaction in RLP format
 func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	if v.Type() == fastrlp.TypeBytes {
+		// a typed transaction in a list of transactions
+		raw, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+		return t.unmarshalTyped(raw)
+	}
+
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
@@ -278,6 +303,10 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 	}
 
 	p.Hash(t.Hash[:0], v)
+	t.Type = LegacyTx
+	t.GasTipCap = nil
+	t.ChainID = nil
+	t.AccessList = nil
 
 	// nonce
 	if t.Nonce, err = elems[0].GetUint64(); err != nil {
@@ -327,3 +356,259 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 	}
 	return nil
 }
+
+// unmarshalTyped unmarshals the type and the payload of a typed transaction (EIP-2718)
+func (t *Transaction) unmarshalTyped(input []byte) error {
+	if len(input) == 0 {
+		return fmt.Errorf("empty typed transaction")
+	}
+
+	var err error
+	switch typ := TxType(input[0]); typ {
+	case AccessListTx:
+		err = UnmarshalRlp(t.unmarshalAccessListRLPFrom, input[1:])
+		t.GasTipCap = nil
+	case DynamicFeeTx:
+		err = UnmarshalRlp(t.unmarshalDynamicFeeRLPFrom, input[1:])
+	case StateTx:
+		err = UnmarshalRlp(t.unmarshalStateRLPFrom, input[1:])
+	default:
+		return fmt.Errorf("transaction type %d not supported", typ)
+	}
+	if err != nil {
+		return err
+	}
+
+	t.Type = TxType(input[0])
+	keccak.Keccak256(t.Hash[:0], input)
+	return nil
+}
+
+// unmarshalStateRLPFrom unmarshals the payload of a system transaction, sent by the SystemAddress
+func (t *Transaction) unmarshalStateRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if num := len(elems); num != 4 {
+		return fmt.Errorf("not enough elements to decode state transaction, expected 4 but found %d", num)
+	}
+
+	t.GasPrice = new(big.Int)
+	t.GasTipCap = nil
+	t.ChainID = nil
+	t.AccessList = nil
+	t.Value = new(big.Int)
+	t.V, t.R, t.S = nil, nil, nil
+	t.From = SystemAddress
+
+	// nonce
+	if t.Nonce, err = elems[0].GetUint64(); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	vv, _ := elems[2].Bytes()
+	if len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// input
+	if t.Input, err = elems[3].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// unmarshalDynamicFeeRLPFrom unmarshals the payload of a dynamic fee transaction (EIP-1559)
+func (t *Transaction) unmarshalDynamicFeeRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if num := len(elems); num != 12 {
+		return fmt.Errorf("not enough elements to decode dynamic fee transaction, expected 12 but found %d", num)
+	}
+
+	// chainID
+	t.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(t.ChainID); err != nil {
+		return err
+	}
+	// nonce
+	if t.Nonce, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// maxPriorityFeePerGas
+	t.GasTipCap = new(big.Int)
+	if err := elems[2].GetBigInt(t.GasTipCap); err != nil {
+		return err
+	}
+	// maxFeePerGas
+	t.GasPrice = new(big.Int)
+	if err := elems[3].GetBigInt(t.GasPrice); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[4].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	vv, _ := elems[5].Bytes()
+	if len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// value
+	t.Value = new(big.Int)
+	if err := elems[6].GetBigInt(t.Value); err != nil {
+		return err
+	}
+	// input
+	if t.Input, err = elems[7].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	// access list
+	if err := t.AccessList.UnmarshalRLPFrom(p, elems[8]); err != nil {
+		return err
+	}
+
+	// V
+	if t.V, err = elems[9].GetBytes(t.V[:0]); err != nil {
+		return err
+	}
+	// R
+	if t.R, err = elems[10].GetBytes(t.R[:0]); err != nil {
+		return err
+	}
+	// S
+	if t.S, err = elems[11].GetBytes(t.S[:0]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// unmarshalAccessListRLPFrom unmarshals the payload of an access list transaction (EIP-2930)
+func (t *Transaction) unmarshalAccessListRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if num := len(elems); num != 11 {
+		return fmt.Errorf("not enough elements to decode access list transaction, expected 11 but found %d", num)
+	}
+
+	// chainID
+	t.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(t.ChainID); err != nil {
+		return err
+	}
+	// nonce
+	if t.Nonce, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// gasPrice
+	t.GasPrice = new(big.Int)
+	if err := elems[2].GetBigInt(t.GasPrice); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	vv, _ := elems[4].Bytes()
+	if len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// value
+	t.Value = new(big.Int)
+	if err := elems[5].GetBigInt(t.Value); err != nil {
+		return err
+	}
+	// input
+	if t.Input, err = elems[6].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	// access list
+	if err := t.AccessList.UnmarshalRLPFrom(p, elems[7]); err != nil {
+		return err
+	}
+
+	// V
+	if t.V, err = elems[8].GetBytes(t.V[:0]); err != nil {
+		return err
+	}
+	// R
+	if t.R, err = elems[9].GetBytes(t.R[:0]); err != nil {
+		return err
+	}
+	// S
+	if t.S, err = elems[10].GetBytes(t.S[:0]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalRLPFrom unmarshals an access list in RLP format
+func (a *AccessList) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	*a = nil
+	if len(elems) == 0 {
+		return nil
+	}
+
+	list := make(AccessList, len(elems))
+	for i, elem := range elems {
+		tuple, err := elem.GetElems()
+		if err != nil {
+			return err
+		}
+		if num := len(tuple); num != 2 {
+			return fmt.Errorf("not enough elements to decode access tuple, expected 2 but found %d", num)
+		}
+
+		// address
+		if err := tuple[0].GetAddr(list[i].Address[:]); err != nil {
+			return err
+		}
+
+		// storage keys
+		keys, err := tuple[1].GetElems()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		list[i].StorageKeys = make([]Hash, len(keys))
+		for j, key := range keys {
+			if err := key.GetHash(list[i].StorageKeys[j][:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	*a = list
+	return nil
+}
@@ -104,8 +104,8 @@ func (r *Receipt) UnmarshalStoreRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) err
 	if err != nil {
 		return err
 	}
-	if len(elems) != 3 {
-		return fmt.Errorf("expected 3 elements")
+	if len(elems) != 3 && len(elems) != 4 {
+		return fmt.Errorf("expected 3 or 4 elements")
 	}
 
 	if err := r.UnmarshalRLPFrom(p, elems[0]); err != nil {
@@ -128,5 +128,12 @@ func (r *Receipt) UnmarshalStoreRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) err
 	if r.GasUsed, err = elems[2].GetUint64(); err != nil {
 		return err
 	}
+
+	// revert data
+	if len(elems) == 4 {
+		if r.RevertData, err = elems[3].GetBytes(r.RevertData[:0]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -7,18 +7,88 @@ import (
 	"github.com/0xPolygon/polygon-sdk/helper/keccak"
 )
 
+// TxType is the EIP-2718 type of a transaction
+type TxType byte
+
+const (
+	// LegacyTx is the transaction with a gas price, encoded as a plain RLP list
+	LegacyTx TxType = 0x0
+
+	// AccessListTx is the EIP-2930 transaction with a gas price and an access list
+	AccessListTx TxType = 0x1
+
+	// DynamicFeeTx is the EIP-1559 transaction with a fee cap and a tip cap
+	DynamicFeeTx TxType = 0x2
+
+	// StateTx is the system transaction the validators insert at the top of the blocks,
+	// sent by the SystemAddress without a signature nor a fee
+	StateTx TxType = 0x7f
+)
+
+// SystemAddress is the sender of the system transactions
+var SystemAddress = StringToAddress("0xfffffffffffffffffffffffffffffffffffffffe")
+
+// AccessTuple is an address and the storage slots of the address accessed by a transaction
+type AccessTuple struct {
+	Address     Address `json:"address"`
+	StorageKeys []Hash  `json:"storageKeys"`
+}
+
+// AccessList is the list of the addresses and the storage slots a transaction plans to access (EIP-2930).
+// They are warm from the start of the transaction, for a fee paid upfront
+type AccessList []AccessTuple
+
+// Copy returns a deep copy of the access list
+func (a AccessList) Copy() AccessList {
+	if a == nil {
+		return nil
+	}
+
+	cc := make(AccessList, len(a))
+	for i, tuple := range a {
+		cc[i] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]Hash{}, tuple.StorageKeys...),
+		}
+	}
+	return cc
+}
+
+// StorageKeys returns the number of storage slots in the access list
+func (a AccessList) StorageKeys() int {
+	num := 0
+	for _, tuple := range a {
+		num += len(tuple.StorageKeys)
+	}
+	return num
+}
+
 type Transaction struct {
-	Nonce    uint64
+	Type  TxType
+	Nonce uint64
+
+	// GasPrice is the gas price of a legacy transaction,
+	// or the fee cap (max fee per gas) of a dynamic fee transaction
 	GasPrice *big.Int
-	Gas      uint64
-	To       *Address
-	Value    *big.Int
-	Input    []byte
-	V        []byte
-	R        []byte
-	S        []byte
-	Hash     Hash
-	From     Address
+
+	// GasTipCap is the max priority fee per gas of a dynamic fee transaction
+	GasTipCap *big.Int
+
+	// ChainID is the chain of a typed transaction, the legacy ones carry it in V
+	ChainID *big.Int
+
+	// AccessList is the list of the addresses and the storage slots a typed transaction plans to access
+	AccessList AccessList
+
+	Gas   uint64
+	To    *Address
+	Value *big.Int
+	Input []byte
+	V     []byte
+	R     []byte
+	S     []byte
+	Hash  Hash
+	From  Address
 
 	// Cache
 	size atomic.Value
@@ -28,8 +98,19 @@ func (t *Transaction) IsContractCreation() bool {
 	return t.To == nil
 }
 
+// IsSystemTx checks if the transaction is a system transaction, inserted by the validators
+func (t *Transaction) IsSystemTx() bool {
+	return t.Type == StateTx
+}
+
 // ComputeHash computes the hash of the transaction
 func (t *Transaction) ComputeHash() *Transaction {
+	if t.Type != LegacyTx {
+		// the hash of a typed transaction covers its type and its payload
+		keccak.Keccak256(t.Hash[:0], t.MarshalRLP())
+		return t
+	}
+
 	ar := marshalArenaPool.Get()
 	hash := keccak.DefaultKeccakPool.Get()
 
@@ -48,6 +129,14 @@ func (t *Transaction) Copy() *Transaction {
 	tt.GasPrice = new(big.Int)
 	tt.GasPrice.Set(t.GasPrice)
 
+	if t.GasTipCap != nil {
+		tt.GasTipCap = new(big.Int).Set(t.GasTipCap)
+	}
+	if t.ChainID != nil {
+		tt.ChainID = new(big.Int).Set(t.ChainID)
+	}
+	tt.AccessList = t.AccessList.Copy()
+
 	tt.Value = new(big.Int)
 	tt.Value.Set(t.Value)
 
@@ -68,6 +157,42 @@ func (t *Transaction) Cost() *big.Int {
 	return total
 }
 
+// GetGasTipCap returns the max priority fee per gas, the gas price of a legacy transaction
+func (t *Transaction) GetGasTipCap() *big.Int {
+	if t.Type == DynamicFeeTx && t.GasTipCap != nil {
+		return t.GasTipCap
+	}
+	return t.GasPrice
+}
+
+// EffectiveGasPrice returns the price per gas paid by the sender in a block with the given base fee,
+// a nil base fee is the block before the London fork
+func (t *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(t.GasPrice)
+	}
+
+	price := new(big.Int).Add(baseFee, t.GetGasTipCap())
+	if price.Cmp(t.GasPrice) > 0 {
+		price.Set(t.GasPrice)
+	}
+	return price
+}
+
+// EffectiveTip returns the price per gas paid to the block creator in a block with the given base fee.
+// It is negative if the fee cap is below the base fee
+func (t *Transaction) EffectiveTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(t.GetGasTipCap())
+	}
+
+	tip := new(big.Int).Sub(t.GasPrice, baseFee)
+	if tipCap := t.GetGasTipCap(); tip.Cmp(tipCap) > 0 {
+		tip.Set(tipCap)
+	}
+	return tip
+}
+
 func (t *Transaction) Size() uint64 {
 	if size := t.size.Load(); size != nil {
 		return size.(uint64)